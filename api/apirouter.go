@@ -22,12 +22,38 @@ type fileMux struct {
 	*chi.Mux
 }
 
+// CachedRoutePrefixes are the path prefixes of the expensive routes whose
+// responses are cached when a response cache is configured.
+var CachedRoutePrefixes = []string{"/chart/", "/ticketpool"}
+
+// UncachedRoutePrefixes are the path prefixes of the CachedRoutePrefixes
+// routes that are not cached. The market charts change with exchange updates
+// rather than new blocks.
+var UncachedRoutePrefixes = []string{"/chart/market/"}
+
+// CachedRouteParams are the URL query parameters used by the cached routes.
+// Other parameters are not part of the response cache key.
+var CachedRouteParams = []string{"axis", "bin", "from", "indent", "to", "until", "zoom"}
+
 // NewAPIRouter creates a new HTTP request path router/mux for the given API,
 // appContext.
 func NewAPIRouter(app *appContext, JSONIndent string, useRealIP, compressLarge bool) apiMux {
 	// chi router
 	mux := stackedMux(useRealIP)
 
+	// Count every API request by route and client, including the requests
+	// refused by the rate limiter.
+	mux.Use(app.usage.Track)
+
+	// Rate limit every API request, and additionally charge the expensive
+	// address history and chart routes against their own budget.
+	mux.Use(app.rateLimiter.Limit)
+	expensive := app.rateLimiter.LimitExpensive
+
+	// Serve the cached responses of the CachedRoutePrefixes routes. The cache
+	// must run before the middleware that sets request context values.
+	mux.Use(app.respCache.Cache)
+
 	// Check for and validate the "indent" URL query. Each API request handler
 	// may now access the configured indentation string if indent was specified
 	// and parsed as a boolean, otherwise the empty string, from
@@ -38,8 +64,28 @@ func NewAPIRouter(app *appContext, JSONIndent string, useRealIP, compressLarge b
 
 	mux.Get("/status", app.status)
 	mux.Get("/status/happy", app.statusHappy)
+	mux.Get("/status/maintenance", app.maintenanceStatus)
+	mux.Get("/status/websocket", app.websocketStatus)
+	mux.Get("/status/ratelimit", app.rateLimitStatus)
+	mux.Get("/status/heightntfn", app.heightNtfnStatus)
+	mux.Get("/db/stats", app.dbStats)
 	mux.Get("/supply", app.coinSupply)
 	mux.Get("/supply/circulating", app.coinSupplyCirculating)
+	mux.Get("/supply/reductions", app.getSubsidySchedule)
+	mux.Get("/supply/distribution", app.getUTXODistribution)
+	mux.Get("/supply/distribution/history", app.getUTXODistributionHistory)
+	mux.Get("/home", app.getHomeSummary)
+	mux.Get("/nulldata", app.searchNullData)
+
+	// The admin endpoints are only enabled when a token is configured.
+	if app.adminToken != "" {
+		mux.Route("/admin", func(r chi.Router) {
+			r.Use(m.BearerToken(app.adminToken))
+			r.Get("/caches", app.cacheStats)
+			r.Post("/caches/flush", app.flushCaches)
+			r.Get("/usage", app.usageStats)
+		})
+	}
 
 	compMiddleware := m.Next
 	if compressLarge {
@@ -56,12 +102,17 @@ func NewAPIRouter(app *appContext, JSONIndent string, useRealIP, compressLarge b
 			rd.Route("/header", func(rt chi.Router) {
 				rt.Get("/", app.getBlockHeader)
 				rt.Get("/raw", app.getBlockHeaderRaw)
+				rt.Get("/stake", app.getBlockStakeHeader)
 			})
 			rd.Get("/raw", app.getBlockRaw)
 			rd.Get("/size", app.getBlockSize)
 			rd.Get("/subsidy", app.blockSubsidies)
 			rd.With(compMiddleware).Get("/verbose", app.getBlockVerbose)
+			rd.With(compMiddleware).Get("/full", app.getBlockFull)
 			rd.Get("/pos", app.getBlockStakeInfoExtendedByHeight)
+			rd.Get("/winners", app.getBlockWinners)
+			rd.Get("/chainwork", app.getBlockChainWork)
+			rd.Get("/status", app.getBlockStatus)
 			rd.Route("/tx", func(rt chi.Router) {
 				rt.Get("/", app.getBlockTransactions)
 				rt.Get("/count", app.getBlockTransactionsCount)
@@ -75,42 +126,70 @@ func NewAPIRouter(app *appContext, JSONIndent string, useRealIP, compressLarge b
 			rd.Route("/header", func(rt chi.Router) {
 				rt.Get("/", app.getBlockHeader)
 				rt.Get("/raw", app.getBlockHeaderRaw)
+				rt.Get("/stake", app.getBlockStakeHeader)
 			})
 			rd.Get("/raw", app.getBlockRaw)
 			rd.Get("/size", app.getBlockSize)
 			rd.Get("/subsidy", app.blockSubsidies)
 			rd.With(compMiddleware).Get("/verbose", app.getBlockVerbose)
+			rd.With(compMiddleware).Get("/full", app.getBlockFull)
 			rd.Get("/pos", app.getBlockStakeInfoExtendedByHash)
+			rd.Get("/winners", app.getBlockWinners)
+			rd.Get("/chainwork", app.getBlockChainWork)
+			rd.Get("/status", app.getBlockStatus)
 			rd.Route("/tx", func(rt chi.Router) {
 				rt.Get("/", app.getBlockTransactions)
 				rt.Get("/count", app.getBlockTransactionsCount)
 			})
 		})
 
+		r.Route("/at/{timestamp}", func(rd chi.Router) {
+			rd.Use(m.BlockTimePathCtx, app.BlockTimeIndexCtx)
+			rd.Get("/", app.getBlockSummary)
+			rd.Get("/height", app.getBlockHeight)
+			rd.Get("/hash", app.getBlockHash)
+			rd.Route("/header", func(rt chi.Router) {
+				rt.Get("/", app.getBlockHeader)
+				rt.Get("/raw", app.getBlockHeaderRaw)
+			})
+		})
+
 		r.Route("/{idx}", func(rd chi.Router) {
 			rd.Use(m.BlockIndexPathCtx)
 			rd.Get("/", app.getBlockSummary)
 			rd.Route("/header", func(rt chi.Router) {
 				rt.Get("/", app.getBlockHeader)
 				rt.Get("/raw", app.getBlockHeaderRaw)
+				rt.Get("/stake", app.getBlockStakeHeader)
 			})
 			rd.Get("/hash", app.getBlockHash)
 			rd.Get("/raw", app.getBlockRaw)
 			rd.Get("/size", app.getBlockSize)
 			rd.Get("/subsidy", app.blockSubsidies)
 			rd.With(compMiddleware).Get("/verbose", app.getBlockVerbose)
+			rd.With(compMiddleware).Get("/full", app.getBlockFull)
 			rd.Get("/pos", app.getBlockStakeInfoExtendedByHeight)
+			rd.Get("/winners", app.getBlockWinners)
+			rd.Get("/chainwork", app.getBlockChainWork)
+			rd.Get("/status", app.getBlockStatus)
 			rd.Route("/tx", func(rt chi.Router) {
 				rt.Get("/", app.getBlockTransactions)
 				rt.Get("/count", app.getBlockTransactionsCount)
 			})
 		})
 
+		r.Get("/tips", app.getChainTipsChainWork)
+		r.Get("/list", app.getBlockList)
+		r.Get("/headers", app.getBlockHeaders)
+
 		r.Route("/range/{idx0}/{idx}", func(rd chi.Router) {
 			rd.Use(m.BlockIndex0PathCtx, m.BlockIndexPathCtx)
 			rd.Use(compMiddleware)
 			rd.Get("/", app.getBlockRangeSummary)
 			rd.Get("/size", app.getBlockRangeSize)
+			rd.Get("/script-anomalies", app.getScriptAnomalies)
+			rd.Get("/anomalies", app.getBlockAnomalies)
+			rd.Get("/vins", app.getBlockRangeVins)
 			rd.Route("/{step}", func(rs chi.Router) {
 				rs.Use(m.BlockStepPathCtx)
 				rs.Get("/", app.getBlockRangeSteppedSummary)
@@ -124,21 +203,40 @@ func NewAPIRouter(app *appContext, JSONIndent string, useRealIP, compressLarge b
 			rd.Use(app.StakeVersionLatestCtx)
 			rd.Get("/info", app.getVoteInfo)
 		})
+		r.Get("/upgrade", app.getUpgradeProgress)
 		r.Route("/pool", func(rd chi.Router) {
 			rd.With(app.BlockIndexLatestCtx).Get("/", app.getTicketPoolInfo)
 			rd.With(app.BlockIndexLatestCtx).Get("/full", app.getTicketPool)
-			rd.With(m.BlockIndexPathCtx).Get("/b/{idx}", app.getTicketPoolInfo)
+			rd.With(m.BlockIndexOrHashPathCtx).Get("/b/{idxorhash}", app.getTicketPoolInfo)
 			rd.With(m.BlockIndexOrHashPathCtx).Get("/b/{idxorhash}/full", app.getTicketPool)
 			rd.With(m.BlockIndex0PathCtx, m.BlockIndexPathCtx).Get("/r/{idx0}/{idx}", app.getTicketPoolInfoRange)
+			rd.With(middleware.AllowContentType("application/json"),
+				m.ValidateTxnsPostCtx, m.PostTxnsCtx).Post("/odds", app.getTicketVoteOdds)
 		})
 		r.Route("/diff", func(rd chi.Router) {
 			rd.Get("/", app.getStakeDiffSummary)
 			rd.Get("/current", app.getStakeDiffCurrent)
 			rd.Get("/estimates", app.getStakeDiffEstimates)
+			rd.Route("/estimates/accuracy", func(ra chi.Router) {
+				ra.Get("/", app.getStakeDiffEstimateAccuracy)
+				ra.With(m.NPathCtx).Get("/count/{N}", app.getStakeDiffEstimateAccuracy)
+				ra.With(m.NPathCtx, m.MPathCtx).Get("/count/{N}/skip/{M}", app.getStakeDiffEstimateAccuracy)
+			})
+			rd.Route("/windows", func(rw chi.Router) {
+				rw.Get("/", app.getStakeDiffWindows)
+				rw.With(m.NPathCtx).Get("/count/{N}", app.getStakeDiffWindows)
+				rw.With(m.NPathCtx, m.MPathCtx).Get("/count/{N}/skip/{M}", app.getStakeDiffWindows)
+			})
 			rd.With(m.BlockIndexPathCtx).Get("/b/{idx}", app.getStakeDiff)
 			rd.With(m.BlockIndex0PathCtx, m.BlockIndexPathCtx).Get("/r/{idx0}/{idx}", app.getStakeDiffRange)
 		})
+		r.With(expensive).Get("/simulate", app.getTicketPoolSimulation)
 		r.Get("/powerless", app.getPowerlessTickets)
+		r.Route("/revocable", func(rd chi.Router) {
+			rd.Get("/", app.getRevocableTickets)
+			rd.With(m.NPathCtx).Get("/count/{N}", app.getRevocableTickets)
+			rd.With(m.NPathCtx, m.MPathCtx).Get("/count/{N}/skip/{M}", app.getRevocableTickets)
+		})
 	})
 
 	mux.Route("/tx", func(r chi.Router) {
@@ -147,6 +245,7 @@ func NewAPIRouter(app *appContext, JSONIndent string, useRealIP, compressLarge b
 				rd.Use(m.TransactionHashCtx)
 				rd.Get("/", app.getTransaction)
 				rd.Get("/trimmed", app.getDecodedTx)
+				rd.Get("/blocks", app.getTransactionBlocks)
 				rd.Route("/out", func(ro chi.Router) {
 					ro.Get("/", app.getTransactionOutputs)
 					ro.With(m.TransactionIOIndexCtx).Get("/{txinoutindex}", app.getTransactionOutput)
@@ -157,12 +256,23 @@ func NewAPIRouter(app *appContext, JSONIndent string, useRealIP, compressLarge b
 				})
 				rd.Get("/vinfo", app.getTxVoteInfo)
 				rd.Get("/tinfo", app.getTxTicketInfo)
+				rd.Get("/luck", app.getTxTicketLuck)
+				rd.Get("/wins", app.getTicketWinningBlocks)
 			})
 		})
+		r.Route("/duplicates", func(rd chi.Router) {
+			rd.Get("/", app.getDuplicateTransactions)
+			rd.With(m.NPathCtx).Get("/count/{N}", app.getDuplicateTransactions)
+			rd.With(m.NPathCtx, m.MPathCtx).Get("/count/{N}/skip/{M}", app.getDuplicateTransactions)
+		})
 		r.With(m.TransactionHashCtx).Get("/hex/{txid}", app.getTransactionHex)
 		r.With(m.TransactionHashCtx).Get("/decoded/{txid}", app.getDecodedTx)
+		r.With(middleware.AllowContentType("application/json"),
+			m.ValidateTxnsPostCtx).Post("/validate", app.validateTx)
 	})
 
+	mux.With(m.TransactionHashCtx).Get("/vote/{txid}", app.getVote)
+
 	mux.Route("/txs", func(r chi.Router) {
 		r.Use(middleware.AllowContentType("application/json"),
 			m.ValidateTxnsPostCtx, m.PostTxnsCtx)
@@ -180,12 +290,35 @@ func NewAPIRouter(app *appContext, JSONIndent string, useRealIP, compressLarge b
 			rd.Group(func(re chi.Router) {
 				re.Use(m.AddressPathCtxN(1))
 				re.Get("/totals", app.addressTotals)
-				re.Get("/", app.getAddressTransactions)
-				re.With(m.ChartGroupingCtx).Get("/types/{chartgrouping}", app.getAddressTxTypesData)
-				re.With(m.ChartGroupingCtx).Get("/amountflow/{chartgrouping}", app.getAddressTxAmountFlowData)
-				re.With(compMiddleware).Get("/raw", app.getAddressTransactionsRaw)
+				re.Get("/txcount", app.addressTxCounts)
+				re.Get("/balance", app.addressBalanceAt)
+				re.Get("/summary", app.addressSummary)
+				re.Get("/uri", app.addressPaymentURI)
+				re.Get("/qr", app.addressQRCode)
+				re.With(middleware.AllowContentType("application/json")).Post("/verify", app.verifyMessage)
+				re.Get("/staking", app.getAddressStakingPosition)
+				re.Route("/mined", func(ri chi.Router) {
+					ri.Get("/", app.getAddressCoinbaseBlocks)
+					ri.With(m.NPathCtx).Get("/count/{N}", app.getAddressCoinbaseBlocks)
+					ri.With(m.NPathCtx, m.MPathCtx).Get("/count/{N}/skip/{M}", app.getAddressCoinbaseBlocks)
+				})
+				re.Route("/utxos", func(ri chi.Router) {
+					ri.Get("/", app.getAddressUTXOs)
+					ri.With(m.NPathCtx).Get("/count/{N}", app.getAddressUTXOs)
+					ri.With(m.NPathCtx, m.MPathCtx).Get("/count/{N}/skip/{M}", app.getAddressUTXOs)
+				})
+				re.Route("/tickets", func(ri chi.Router) {
+					ri.Get("/", app.getAddressRewardTickets)
+					ri.Get("/luck", app.getAddressTicketLuck)
+					ri.With(m.NPathCtx).Get("/count/{N}", app.getAddressRewardTickets)
+					ri.With(m.NPathCtx, m.MPathCtx).Get("/count/{N}/skip/{M}", app.getAddressRewardTickets)
+				})
+				re.With(expensive).Get("/", app.getAddressTransactions)
+				re.With(expensive, m.ChartGroupingCtx).Get("/types/{chartgrouping}", app.getAddressTxTypesData)
+				re.With(expensive, m.ChartGroupingCtx).Get("/amountflow/{chartgrouping}", app.getAddressTxAmountFlowData)
+				re.With(expensive, compMiddleware).Get("/raw", app.getAddressTransactionsRaw)
 				re.Route("/count/{N}", func(ri chi.Router) {
-					ri.Use(m.NPathCtx)
+					ri.Use(expensive, m.NPathCtx)
 					ri.Get("/", app.getAddressTransactions)
 					ri.With(compMiddleware).Get("/raw", app.getAddressTransactionsRaw)
 					ri.Route("/skip/{M}", func(rj chi.Router) {
@@ -203,6 +336,9 @@ func NewAPIRouter(app *appContext, JSONIndent string, useRealIP, compressLarge b
 	// high level agenda details for all agendas.
 	mux.Route("/agendas", func(r chi.Router) {
 		r.Get("/", app.getAgendasData)
+		r.With(m.AgendaIdCtx).Get("/{agendaId}/status", app.getAgendaStatus)
+		r.With(m.AgendaIdCtx).Get("/{agendaId}/ticket-types", app.getAgendaTicketTypes)
+		r.With(m.AgendaIdCtx).Get("/{agendaId}/timeline", app.getAgendaTimeline)
 	})
 
 	// Returns the charts data for the respective individual agendas.
@@ -212,6 +348,10 @@ func NewAPIRouter(app *appContext, JSONIndent string, useRealIP, compressLarge b
 
 	mux.Route("/mempool", func(r chi.Router) {
 		r.Get("/", http.NotFound /*app.getMempoolOverview*/)
+		r.Get("/nextblock", app.getNextBlockPreview)
+		r.Get("/expiring", app.getMempoolExpiring)
+		r.Get("/graph", app.getMempoolGraph)
+		r.With(m.TransactionHashCtx).Get("/graph/{txid}", app.getMempoolTxPackages)
 		// ticket purchases
 		r.Route("/sstx", func(rd chi.Router) {
 			rd.Get("/", app.getSSTxSummary)
@@ -222,13 +362,20 @@ func NewAPIRouter(app *appContext, JSONIndent string, useRealIP, compressLarge b
 		})
 	})
 
+	mux.Route("/export/daily", func(r chi.Router) {
+		r.Get("/", app.getDailyExports)
+		r.With(m.DailyExportFileCtx).Get("/{date}/{file}", app.getDailyExportFile)
+	})
+
 	mux.Route("/chart", func(r chi.Router) {
+		r.Use(expensive)
 		// Return default chart data (ticket price)
 		r.Route("/market/{token}", func(rd chi.Router) {
 			rd.Use(m.ExchangeTokenContext)
 			rd.With(m.StickWidthContext).Get("/candlestick/{bin}", app.getCandlestickChart)
 			rd.Get("/depth", app.getDepthChart)
 		})
+		r.Get("/coin-supply/projection", app.coinSupplyProjection)
 		r.With(m.ChartTypeCtx).Get("/{charttype}", app.ChartTypeData)
 	})
 
@@ -242,9 +389,17 @@ func NewAPIRouter(app *appContext, JSONIndent string, useRealIP, compressLarge b
 		r.With(m.ProposalTokenCtx).Get("/{token}", app.getProposalChartData)
 	})
 
+	mux.Route("/mining", func(r chi.Router) {
+		r.Get("/shares", app.getMinerShares)
+		r.With(m.NPathCtx).Get("/shares/blocks/{N}", app.getMinerShares)
+	})
+
+	mux.Get("/exchange", app.getExchangeRate)
+
 	mux.Route("/exchanges", func(r chi.Router) {
 		r.Get("/", app.getExchanges)
 		r.Get("/codes", app.getCurrencyCodes)
+		r.Get("/daily", app.getDailyPrices)
 	})
 
 	mux.NotFound(func(w http.ResponseWriter, r *http.Request) {
@@ -316,7 +471,10 @@ func stackedMux(useRealIP bool) *chi.Mux {
 	}
 	mux.Use(middleware.Logger)
 	mux.Use(middleware.Recoverer)
-	corsMW := cors.Default()
+	// Expose the next page cursor and rate limit headers to cross-origin
+	// clients.
+	corsMW := cors.New(cors.Options{ExposedHeaders: []string{nextCursorHeader,
+		"RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "Retry-After"}})
 	corsMW.Log = loggerFunc(apiLog.Tracef)
 	mux.Use(corsMW.Handler)
 	return mux
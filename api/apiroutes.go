@@ -14,7 +14,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
+	"os"
 	"reflect"
 	"sort"
 	"strconv"
@@ -22,101 +24,71 @@ import (
 	"sync"
 	"time"
 
-	"github.com/decred/dcrd/chaincfg/chainhash"
 	"github.com/decred/dcrd/chaincfg/v2"
 	"github.com/decred/dcrd/dcrutil/v2"
 	chainjson "github.com/decred/dcrd/rpc/jsonrpc/types/v2"
 	"github.com/decred/dcrd/rpcclient/v5"
+	"github.com/decred/dcrd/txscript/v2"
 	"github.com/decred/dcrd/wire"
 	apitypes "github.com/decred/dcrdata/api/types/v5"
 	"github.com/decred/dcrdata/db/cache/v3"
 	"github.com/decred/dcrdata/db/dbtypes/v2"
 	"github.com/decred/dcrdata/exchanges/v2"
 	"github.com/decred/dcrdata/gov/v3/agendas"
+	"github.com/decred/dcrdata/mempool/v5"
 	m "github.com/decred/dcrdata/middleware/v3"
 	"github.com/decred/dcrdata/txhelpers/v4"
+	"github.com/decred/dcrdata/v5/blockarchive"
+	"github.com/decred/dcrdata/v5/chainstore"
+	"github.com/decred/dcrdata/v5/dailyexport"
+	"github.com/decred/dcrdata/v5/maintenance"
+	"github.com/decred/dcrdata/v5/stakesim"
 	appver "github.com/decred/dcrdata/v5/version"
+	"github.com/skip2/go-qrcode"
 )
 
 // maxBlockRangeCount is the maximum number of blocks that can be requested at
 // once.
 const maxBlockRangeCount = 1000
 
-// DataSource specifies an interface for advanced data collection using the
-// auxiliary DB (e.g. PostgreSQL).
-type DataSource interface {
-	GetHeight() (int64, error)
-	GetBestBlockHash() (string, error)
-	GetBlockHash(idx int64) (string, error)
-	GetBlockHeight(hash string) (int64, error)
-	GetBlockByHash(string) (*wire.MsgBlock, error)
-	SpendingTransaction(fundingTx string, vout uint32) (string, uint32, int8, error)
-	SpendingTransactions(fundingTxID string) ([]string, []uint32, []uint32, error)
-	AddressHistory(address string, N, offset int64, txnType dbtypes.AddrTxnViewType) ([]*dbtypes.AddressRow, *dbtypes.AddressBalance, error)
-	FillAddressTransactions(addrInfo *dbtypes.AddressInfo) error
-	AddressTransactionDetails(addr string, count, skip int64,
-		txnType dbtypes.AddrTxnViewType) (*apitypes.Address, error)
-	AddressTotals(address string) (*apitypes.AddressTotals, error)
-	VotesInBlock(hash string) (int16, error)
-	TxHistoryData(address string, addrChart dbtypes.HistoryChart,
-		chartGroupings dbtypes.TimeBasedGrouping) (*dbtypes.ChartsData, error)
-	TicketPoolVisualization(interval dbtypes.TimeBasedGrouping) (
-		*dbtypes.PoolTicketsData, *dbtypes.PoolTicketsData, *dbtypes.PoolTicketsData, int64, error)
-	AgendaVotes(agendaID string, chartType int) (*dbtypes.AgendaVoteChoices, error)
-	AddressTxIoCsv(address string) ([][]string, error)
-	Height() int64
-	AllAgendas() (map[string]dbtypes.MileStone, error)
-	GetTicketInfo(txid string) (*apitypes.TicketInfo, error)
-	ProposalVotes(proposalToken string) (*dbtypes.ProposalChartsData, error)
-	PowerlessTickets() (*apitypes.PowerlessTickets, error)
-	GetStakeInfoExtendedByHash(hash string) *apitypes.StakeInfoExtended
-	GetStakeInfoExtendedByHeight(idx int) *apitypes.StakeInfoExtended
-	GetPoolInfo(idx int) *apitypes.TicketPoolInfo
-	GetPoolInfoByHash(hash string) *apitypes.TicketPoolInfo
-	GetPoolInfoRange(idx0, idx1 int) []apitypes.TicketPoolInfo
-	GetPoolValAndSizeRange(idx0, idx1 int) ([]float64, []uint32)
-	GetPool(idx int64) ([]string, error)
-	CurrentCoinSupply() *apitypes.CoinSupply
-	GetHeader(idx int) *chainjson.GetBlockHeaderVerboseResult
-	GetBlockHeaderByHash(hash string) (*wire.BlockHeader, error)
-	GetBlockVerboseByHash(hash string, verboseTx bool) *chainjson.GetBlockVerboseResult
-	GetRawAPITransaction(txid *chainhash.Hash) *apitypes.Tx
-	GetTransactionHex(txid *chainhash.Hash) string
-	GetTrimmedTransaction(txid *chainhash.Hash) *apitypes.TrimmedTx
-	GetVoteInfo(txid *chainhash.Hash) (*apitypes.VoteInfo, error)
-	GetVoteVersionInfo(ver uint32) (*chainjson.GetVoteInfoResult, error)
-	GetStakeVersionsLatest() (*chainjson.StakeVersions, error)
-	GetAllTxIn(txid *chainhash.Hash) []*apitypes.TxIn
-	GetAllTxOut(txid *chainhash.Hash) []*apitypes.TxOut
-	GetTransactionsForBlockByHash(hash string) *apitypes.BlockTransactions
-	GetStakeDiffEstimates() *apitypes.StakeDiff
-	GetSummary(idx int) *apitypes.BlockDataBasic
-	GetSummaryRange(idx0, idx1 int) []*apitypes.BlockDataBasic
-	GetSummaryRangeStepped(idx0, idx1, step int) []*apitypes.BlockDataBasic
-	GetSummaryByHash(hash string, withTxTotals bool) *apitypes.BlockDataBasic
-	GetBestBlockSummary() *apitypes.BlockDataBasic
-	GetBlockSize(idx int) (int32, error)
-	GetBlockSizeRange(idx0, idx1 int) ([]int32, error)
-	GetSDiff(idx int) float64
-	GetSDiffRange(idx0, idx1 int) []float64
-	GetMempoolSSTxSummary() *apitypes.MempoolTicketFeeInfo
-	GetMempoolSSTxFeeRates(N int) *apitypes.MempoolTicketFees
-	GetMempoolSSTxDetails(N int) *apitypes.MempoolTicketDetails
-	GetAddressTransactionsRawWithSkip(addr string, count, skip int) []*apitypes.AddressTxRaw
-	GetMempoolPriceCountTime() *apitypes.PriceCountTime
-}
+// maxScriptAnomalyRange is the maximum number of blocks that can be scanned
+// for script or block anomalies at once. Only the anomalies are returned, so
+// this is much larger than maxBlockRangeCount.
+const maxScriptAnomalyRange = 100000
+
+// maxSigTypeVinRange is the maximum number of blocks that can be scanned for
+// the inputs with a signature script type at once.
+const maxSigTypeVinRange = 10000
+
+// maxSimWindowSize is the largest stake difficulty window size of a ticket
+// pool simulation.
+const maxSimWindowSize = 20160
+
+// defaultUsageClients is the number of clients with the most requests listed
+// by the /admin/usage endpoint if not specified.
+const defaultUsageClients = 50
 
 // dcrdata application context used by all route handlers
 type appContext struct {
 	nodeClient   *rpcclient.Client
 	Params       *chaincfg.Params
-	DataSource   DataSource
+	DataSource   chainstore.ChainStore
 	Status       *apitypes.Status
 	xcBot        *exchanges.ExchangeBot
+	rates        exchanges.RateProvider
 	AgendaDB     *agendas.AgendaDB
 	maxCSVAddrs  int
 	charts       *cache.ChartData
 	isPiDisabled bool // is piparser disabled
+	maintenance  *maintenance.Scheduler
+	wsMetrics    func() *apitypes.WebsocketMetrics
+	blockArchive *blockarchive.Archive
+	adminToken   string
+	rateLimiter  *m.RateLimiter
+	respCache    *m.ResponseCache
+	usage        *m.UsageTracker
+	mempoolGraph *mempool.TxGraph
+	dailyExports *dailyexport.Exporter
 }
 
 // AppContextConfig is the configuration for the appContext and the only
@@ -124,12 +96,33 @@ type appContext struct {
 type AppContextConfig struct {
 	Client             *rpcclient.Client
 	Params             *chaincfg.Params
-	DataSource         DataSource
+	DataSource         chainstore.ChainStore
 	XcBot              *exchanges.ExchangeBot
 	AgendasDBInstance  *agendas.AgendaDB
 	MaxAddrs           int
 	Charts             *cache.ChartData
 	IsPiparserDisabled bool
+	Maintenance        *maintenance.Scheduler
+	WebsocketMetrics   func() *apitypes.WebsocketMetrics
+	BlockArchive       *blockarchive.Archive
+	// AdminToken is the bearer token required by the /admin endpoints. The
+	// endpoints are disabled if it is empty.
+	AdminToken string
+	// RateLimiter limits the API request rate per client. It may be nil to
+	// disable rate limiting.
+	RateLimiter *m.RateLimiter
+	// ResponseCache caches the responses of the CachedRoutePrefixes routes. It
+	// may be nil to disable response caching.
+	ResponseCache *m.ResponseCache
+	// UsageTracker counts the API requests by route and client for the
+	// /admin/usage endpoint. It may be nil to disable usage analytics.
+	UsageTracker *m.UsageTracker
+	// MempoolGraph is the mempool transaction dependency graph served by the
+	// /mempool/graph endpoints, which are unavailable if it is nil.
+	MempoolGraph *mempool.TxGraph
+	// DailyExports are the bulk export files served by the /export/daily
+	// endpoints, which are unavailable if it is nil.
+	DailyExports *dailyexport.Exporter
 }
 
 // NewContext constructs a new appContext from the RPC client, primary and
@@ -144,16 +137,34 @@ func NewContext(cfg *AppContextConfig) *appContext {
 		return nil
 	}
 
+	// Exchange rates are taken from the ExchangeBot, or from the recorded
+	// daily prices if the monitored exchanges are unavailable.
+	var rates exchanges.RateProvider
+	if cfg.XcBot != nil {
+		rates = exchanges.NewFailoverRates(rateCacheTime, rateMaxAge,
+			cfg.XcBot, &dailyPriceRates{cfg.DataSource})
+	}
+
 	return &appContext{
 		nodeClient:   cfg.Client,
 		Params:       cfg.Params,
 		DataSource:   cfg.DataSource,
 		xcBot:        cfg.XcBot,
+		rates:        rates,
 		AgendaDB:     cfg.AgendasDBInstance,
 		Status:       apitypes.NewStatus(uint32(nodeHeight), conns, APIVersion, appver.Version(), cfg.Params.Name),
 		maxCSVAddrs:  cfg.MaxAddrs,
 		charts:       cfg.Charts,
 		isPiDisabled: cfg.IsPiparserDisabled,
+		maintenance:  cfg.Maintenance,
+		wsMetrics:    cfg.WebsocketMetrics,
+		blockArchive: cfg.BlockArchive,
+		adminToken:   cfg.AdminToken,
+		rateLimiter:  cfg.RateLimiter,
+		respCache:    cfg.ResponseCache,
+		usage:        cfg.UsageTracker,
+		mempoolGraph: cfg.MempoolGraph,
+		dailyExports: cfg.DailyExports,
 	}
 }
 
@@ -212,7 +223,11 @@ out:
 	keepon:
 		select {
 		case <-rpcCheckTicker.C:
-			if err := c.updateNodeConnections(); err != nil {
+			err := c.updateNodeConnections()
+			// Report a degraded node connection if either this check or the
+			// DataSource's recent RPCs have failed.
+			c.Status.SetNodeDegraded(err != nil || c.DataSource.NodeDegraded())
+			if err != nil {
 				log.Warn("updateNodeConnections: ", err)
 				break keepon
 			}
@@ -361,7 +376,139 @@ func (c *appContext) statusHappy(w http.ResponseWriter, r *http.Request) {
 	writeJSONWithStatus(w, happy, statusCode, m.GetIndentCtx(r))
 }
 
+// maintenanceStatus reports the schedule, last run time and duration, and last
+// error of each periodic maintenance task.
+func (c *appContext) maintenanceStatus(w http.ResponseWriter, r *http.Request) {
+	if c.maintenance == nil {
+		http.Error(w, "Maintenance scheduler not available.", http.StatusServiceUnavailable)
+		return
+	}
+	writeJSON(w, c.maintenance.Status(), m.GetIndentCtx(r))
+}
+
+// websocketStatus reports the explorer's websocket connection metrics.
+func (c *appContext) websocketStatus(w http.ResponseWriter, r *http.Request) {
+	if c.wsMetrics == nil {
+		http.Error(w, "Websocket metrics not available.", http.StatusServiceUnavailable)
+		return
+	}
+	writeJSON(w, c.wsMetrics(), m.GetIndentCtx(r))
+}
+
+// heightNtfnStatus reports the counts of the DB's new block height
+// notifications, including those coalesced for slow clients.
+func (c *appContext) heightNtfnStatus(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, c.DataSource.HeightNtfnStats(), m.GetIndentCtx(r))
+}
+
+// rateLimitStatus reports the rate limiter's budgets and request counters.
+func (c *appContext) rateLimitStatus(w http.ResponseWriter, r *http.Request) {
+	metrics := c.rateLimiter.Metrics()
+	if metrics == nil {
+		http.Error(w, "Rate limiting is not enabled.", http.StatusServiceUnavailable)
+		return
+	}
+	writeJSON(w, metrics, m.GetIndentCtx(r))
+}
+
+// cacheStats reports the size, age, and hit statistics of the internal caches.
+func (c *appContext) cacheStats(w http.ResponseWriter, r *http.Request) {
+	stats := c.DataSource.CacheStats()
+	if rs := c.respCache.Stats(); rs != nil {
+		cs := &apitypes.CacheStats{
+			Name:    m.ResponseCacheName,
+			Entries: rs.Entries,
+			Hits:    rs.Hits,
+			Misses:  rs.Misses,
+		}
+		cs.SetUpdated(rs.Updated)
+		stats = append(stats, cs)
+	}
+	writeJSON(w, stats, m.GetIndentCtx(r))
+}
+
+// usageStats reports the API request counters by route and client in the
+// usage window. The "clients" URL query limits the number of clients listed,
+// which defaults to defaultUsageClients.
+func (c *appContext) usageStats(w http.ResponseWriter, r *http.Request) {
+	maxClients := defaultUsageClients
+	if clientsStr := r.URL.Query().Get("clients"); clientsStr != "" {
+		n, err := strconv.Atoi(clientsStr)
+		if err != nil || n < 1 {
+			http.Error(w, "invalid clients", http.StatusBadRequest)
+			return
+		}
+		maxClients = n
+	}
+	stats := c.usage.Stats(maxClients)
+	if stats == nil {
+		http.Error(w, "Usage analytics are not enabled.", http.StatusServiceUnavailable)
+		return
+	}
+	writeJSON(w, stats, m.GetIndentCtx(r))
+}
+
+// dbStats reports the estimated row counts, sizes, bloat, and last VACUUM and
+// ANALYZE times of the DB tables.
+func (c *appContext) dbStats(w http.ResponseWriter, r *http.Request) {
+	stats, err := c.DataSource.DBStats(r.Context())
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("DBStats: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		apiLog.Errorf("DBStats: %v", err)
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+	writeJSON(w, stats, m.GetIndentCtx(r))
+}
+
+// flushCaches removes all data from the caches named in the comma-separated
+// "cache" URL query parameter, or from all caches if it is not set.
+func (c *appContext) flushCaches(w http.ResponseWriter, r *http.Request) {
+	// The API response cache is flushed here since it is not one of the
+	// DataSource's caches.
+	var names []string
+	flushAll := true
+	flushResponses := c.respCache != nil
+	if cacheParam := r.URL.Query().Get("cache"); cacheParam != "" {
+		flushAll, flushResponses = false, false
+		for _, name := range strings.Split(cacheParam, ",") {
+			if name == m.ResponseCacheName && c.respCache != nil {
+				flushResponses = true
+				continue
+			}
+			names = append(names, name)
+		}
+	}
+
+	var flushed []*apitypes.CacheFlush
+	if flushAll || len(names) > 0 {
+		var err error
+		flushed, err = c.DataSource.FlushCaches(names)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if flushResponses {
+		flushed = append(flushed, &apitypes.CacheFlush{
+			Name:    m.ResponseCacheName,
+			Flushed: c.respCache.Flush(),
+		})
+	}
+	writeJSON(w, flushed, m.GetIndentCtx(r))
+}
+
 func (c *appContext) coinSupply(w http.ResponseWriter, r *http.Request) {
+	rate, status, err := c.fiatRate(r)
+	if err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+
 	supply := c.DataSource.CurrentCoinSupply()
 	if supply == nil {
 		apiLog.Error("Unable to get coin supply.")
@@ -369,9 +516,116 @@ func (c *appContext) coinSupply(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if rate != nil {
+		// Decorate a copy, since the supply may be shared.
+		fiatSupply := *supply
+		fiatSupply.Fiat = fiatValues(rate, map[string]float64{
+			"supply_mined":    dcrutil.Amount(supply.Mined).ToCoin(),
+			"supply_ultimate": dcrutil.Amount(supply.Ultimate).ToCoin(),
+		})
+		supply = &fiatSupply
+	}
+
 	writeJSON(w, supply, m.GetIndentCtx(r))
 }
 
+// getSubsidySchedule serves the block subsidy reduction schedule, with a
+// countdown to the next reduction and the times of the past reductions.
+func (c *appContext) getSubsidySchedule(w http.ResponseWriter, r *http.Request) {
+	height, err := c.DataSource.GetHeight()
+	if err != nil {
+		apiLog.Errorf("GetHeight: %v", err)
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+
+	// The times of the best block, the block a reduction interval earlier for
+	// the average block time, and the blocks of the past reductions.
+	interval := c.Params.SubsidyReductionInterval
+	heights := []int64{height, height - interval}
+	if heights[1] < 0 {
+		heights[1] = 0
+	}
+	for h := interval; h <= height; h += interval {
+		heights = append(heights, h)
+	}
+	times, err := c.DataSource.BlockTimesByHeights(r.Context(), heights)
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("BlockTimesByHeights: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		apiLog.Errorf("BlockTimesByHeights: %v", err)
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+
+	schedule, err := makeSubsidySchedule(c.Params, height, times)
+	if err != nil {
+		apiLog.Errorf("makeSubsidySchedule: %v", err)
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+	writeJSON(w, schedule, m.GetIndentCtx(r))
+}
+
+// makeSubsidySchedule makes the subsidy reduction schedule as of the best
+// block at height, given the times of the best block, the block a reduction
+// interval before it, and the past reductions' blocks.
+func makeSubsidySchedule(params *chaincfg.Params, height int64, times map[int64]int64) (*apitypes.SubsidySchedule, error) {
+	bestTime, found := times[height]
+	if !found {
+		return nil, fmt.Errorf("no time for best block %d", height)
+	}
+	interval := params.SubsidyReductionInterval
+	schedule := &apitypes.SubsidySchedule{
+		Height:            height,
+		Time:              bestTime,
+		ReductionInterval: interval,
+		MulSubsidy:        params.MulSubsidy,
+		DivSubsidy:        params.DivSubsidy,
+		AvgBlockTime:      params.TargetTimePerBlock.Seconds(),
+		Reductions:        []*apitypes.SubsidyReduction{},
+	}
+	window := interval
+	if window > height {
+		window = height
+	}
+	if startTime, found := times[height-window]; found && window > 0 && bestTime > startTime {
+		schedule.AvgBlockTime = float64(bestTime-startTime) / float64(window)
+	}
+
+	votes := params.TicketsPerBlock
+	reduction := func(number int64) *apitypes.SubsidyReduction {
+		h := number * interval
+		work, stake, tax := txhelpers.RewardsAtBlock(h, votes, params)
+		return &apitypes.SubsidyReduction{
+			Number:     number,
+			Height:     h,
+			Work:       work,
+			Stake:      stake,
+			TotalStake: stake * int64(votes),
+			Tax:        tax,
+			Total:      work + stake*int64(votes) + tax,
+		}
+	}
+	for number := int64(1); number*interval <= height; number++ {
+		sr := reduction(number)
+		if sr.Time, found = times[sr.Height]; !found {
+			return nil, fmt.Errorf("no time for block %d", sr.Height)
+		}
+		schedule.Reductions = append(schedule.Reductions, sr)
+	}
+
+	schedule.Next = reduction(height/interval + 1)
+	schedule.BlocksRemaining = schedule.Next.Height - height
+	schedule.SecondsRemaining = int64(float64(schedule.BlocksRemaining) * schedule.AvgBlockTime)
+	schedule.Next.Time = bestTime + schedule.SecondsRemaining
+	schedule.Next.Estimated = true
+	return schedule, nil
+}
+
 func (c *appContext) coinSupplyCirculating(w http.ResponseWriter, r *http.Request) {
 	var dcr bool
 	if dcrParam := r.URL.Query().Get("dcr"); dcrParam != "" {
@@ -399,6 +653,51 @@ func (c *appContext) coinSupplyCirculating(w http.ResponseWriter, r *http.Reques
 	writeJSONBytes(w, []byte(strconv.FormatInt(supply.Mined, 10)))
 }
 
+// getHomeSummary returns the data for all of the explorer's home page widgets
+// in one response.
+func (c *appContext) getHomeSummary(w http.ResponseWriter, r *http.Request) {
+	block := c.DataSource.GetBestBlockSummary()
+	if block == nil {
+		apiLog.Error("Unable to get best block summary.")
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+
+	home := &apitypes.HomeSummary{
+		Block:       block,
+		Mempool:     c.DataSource.GetMempoolShortSummary(),
+		TicketPrice: block.StakeDiff,
+	}
+	if block.PoolInfo != nil {
+		home.PoolSize = block.PoolInfo.Size
+		home.PoolValue = block.PoolInfo.Value
+	}
+	if stakeDiff := c.DataSource.GetStakeDiffEstimates(); stakeDiff != nil {
+		home.NextTicketPrice = stakeDiff.Estimates.Expected
+	}
+
+	devBalance, err := c.DataSource.DevBalance()
+	if err != nil {
+		apiLog.Errorf("DevBalance: %v", err)
+	} else if devBalance != nil {
+		home.DevFundBalance = dcrutil.Amount(devBalance.TotalUnspent).ToCoin()
+	}
+
+	if c.charts != nil {
+		since := block.Time.S.T.Add(-24 * time.Hour).Unix()
+		stats := c.charts.BlockStatsSince(uint64(since))
+		home.Last24Hours = &apitypes.BlockStats{
+			Blocks:       stats.Blocks,
+			Transactions: stats.Transactions,
+			Bytes:        stats.Bytes,
+			Fees:         dcrutil.Amount(stats.Fees).ToCoin(),
+			NewCoins:     dcrutil.Amount(stats.NewAtoms).ToCoin(),
+		}
+	}
+
+	writeJSON(w, home, m.GetIndentCtx(r))
+}
+
 func (c *appContext) currentHeight(w http.ResponseWriter, _ *http.Request) {
 	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
 	if _, err := io.WriteString(w, strconv.Itoa(int(c.Status.Height()))); err != nil {
@@ -522,6 +821,22 @@ func (c *appContext) getBlockRaw(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Serve the block from the archive if it is there, or get it from dcrd.
+	if c.blockArchive != nil {
+		b, height, err := c.blockArchive.RawBlock(hash)
+		if err == nil {
+			writeJSON(w, &apitypes.BlockRaw{
+				Height: height,
+				Hash:   hash,
+				Hex:    hex.EncodeToString(b),
+			}, m.GetIndentCtx(r))
+			return
+		}
+		if err != blockarchive.ErrNotFound {
+			apiLog.Warnf("Unable to get block %s from the archive: %v", hash, err)
+		}
+	}
+
 	msgBlock, err := c.DataSource.GetBlockByHash(hash)
 	if err != nil {
 		apiLog.Errorf("Unable to get block %s: %v", hash, err)
@@ -554,11 +869,20 @@ func (c *appContext) getBlockHeaderRaw(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	blockHeader, err := c.DataSource.GetBlockHeaderByHash(hash)
-	if err != nil {
-		apiLog.Errorf("Unable to get block %s: %v", hash, err)
-		http.Error(w, http.StatusText(422), 422)
-		return
+	var blockHeader *wire.BlockHeader
+	if c.blockArchive != nil {
+		blockHeader, err = c.blockArchive.Header(hash)
+		if err != nil && err != blockarchive.ErrNotFound {
+			apiLog.Warnf("Unable to get block %s header from the archive: %v", hash, err)
+		}
+	}
+	if blockHeader == nil {
+		blockHeader, err = c.DataSource.GetBlockHeaderByHash(hash)
+		if err != nil {
+			apiLog.Errorf("Unable to get block %s: %v", hash, err)
+			http.Error(w, http.StatusText(422), 422)
+			return
+		}
 	}
 
 	var hexString strings.Builder
@@ -578,6 +902,190 @@ func (c *appContext) getBlockHeaderRaw(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, blockRaw, m.GetIndentCtx(r))
 }
 
+// maxStreamedHeaders is the most block headers streamed by getBlockHeaders in
+// one response. Clients resume from the height in the Next-Height trailer.
+const maxStreamedHeaders = 100000
+
+// maxNullDataResults is the most null data outputs returned by one request to
+// the /nulldata endpoint.
+const maxNullDataResults = 1000
+
+// searchNullData serves the mainchain null data (OP_RETURN) outputs with a
+// payload beginning with the prefix given by either the "hex" or "text" URL
+// query, newest first. Up to "count" outputs (default 100) are returned,
+// skipping "skip".
+func (c *appContext) searchNullData(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	hexPrefix, textPrefix := query.Get("hex"), query.Get("text")
+	var prefix []byte
+	switch {
+	case hexPrefix != "" && textPrefix != "":
+		http.Error(w, "only one of hex and text may be given", http.StatusBadRequest)
+		return
+	case hexPrefix != "":
+		var err error
+		prefix, err = hex.DecodeString(hexPrefix)
+		if err != nil {
+			http.Error(w, "invalid hex prefix", http.StatusBadRequest)
+			return
+		}
+	case textPrefix != "":
+		prefix = []byte(textPrefix)
+	default:
+		http.Error(w, "a hex or text prefix is required", http.StatusBadRequest)
+		return
+	}
+	if len(prefix) > txscript.MaxDataCarrierSize {
+		http.Error(w, "prefix is longer than the largest null data payload", http.StatusBadRequest)
+		return
+	}
+
+	count, skip := int64(100), int64(0)
+	if countStr := query.Get("count"); countStr != "" {
+		var err error
+		count, err = strconv.ParseInt(countStr, 10, 64)
+		if err != nil || count < 1 {
+			http.Error(w, "invalid count", http.StatusBadRequest)
+			return
+		}
+		if count > maxNullDataResults {
+			count = maxNullDataResults
+		}
+	}
+	if skipStr := query.Get("skip"); skipStr != "" {
+		var err error
+		skip, err = strconv.ParseInt(skipStr, 10, 64)
+		if err != nil || skip < 0 {
+			http.Error(w, "invalid skip", http.StatusBadRequest)
+			return
+		}
+	}
+
+	outputs, err := c.DataSource.NullDataByPrefix(r.Context(), prefix, count, skip)
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("NullDataByPrefix: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		apiLog.Errorf("NullDataByPrefix: %v", err)
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+	if outputs == nil {
+		outputs = []*dbtypes.NullDataOutput{}
+	}
+	writeJSON(w, outputs, m.GetIndentCtx(r))
+}
+
+// getBlockHeaders streams mainchain block headers starting at the height given
+// by the "from" URL query, up to "count" headers or the best block. The headers
+// are written as newline-delimited JSON (apitypes.BlockHeaderLine), or as
+// concatenated serialized headers (180 bytes each) if the "format" URL query is
+// "raw". The height following the last header written is set in the
+// Next-Height trailer so that an interrupted or truncated stream may be
+// resumed.
+func (c *appContext) getBlockHeaders(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	var from int64
+	if fromStr := query.Get("from"); fromStr != "" {
+		var err error
+		from, err = strconv.ParseInt(fromStr, 10, 64)
+		if err != nil || from < 0 {
+			http.Error(w, "invalid from height", http.StatusBadRequest)
+			return
+		}
+	}
+	count := int64(maxStreamedHeaders)
+	if countStr := query.Get("count"); countStr != "" {
+		var err error
+		count, err = strconv.ParseInt(countStr, 10, 64)
+		if err != nil || count < 1 {
+			http.Error(w, "invalid count", http.StatusBadRequest)
+			return
+		}
+		if count > maxStreamedHeaders {
+			count = maxStreamedHeaders
+		}
+	}
+	var raw bool
+	switch format := query.Get("format"); format {
+	case "", "ndjson":
+	case "raw":
+		raw = true
+	default:
+		http.Error(w, "invalid format", http.StatusBadRequest)
+		return
+	}
+
+	if raw {
+		w.Header().Set("Content-Type", "application/octet-stream")
+	} else {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+	}
+	w.Header().Set("Trailer", "Next-Height")
+
+	flusher, _ := w.(http.Flusher)
+	enc := json.NewEncoder(w)
+	next, end := from, from+count
+	for next < end {
+		if r.Context().Err() != nil {
+			return // client is gone
+		}
+		batch := end - next
+		if batch > wire.MaxBlockHeadersPerMsg {
+			batch = wire.MaxBlockHeadersPerMsg
+		}
+		headers, err := c.DataSource.BlockHeaders(r.Context(), next, int(batch))
+		if err != nil {
+			apiLog.Errorf("BlockHeaders(%d, %d): %v", next, batch, err)
+			if next == from {
+				if dbtypes.IsTimeoutErr(err) {
+					http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+					return
+				}
+				http.Error(w, http.StatusText(http.StatusUnprocessableEntity),
+					http.StatusUnprocessableEntity)
+				return
+			}
+			break // resume from the Next-Height trailer
+		}
+		if len(headers) == 0 {
+			break // best block reached
+		}
+
+		for _, header := range headers {
+			if raw {
+				err = header.Serialize(w)
+			} else {
+				err = enc.Encode(&apitypes.BlockHeaderLine{
+					Height:       header.Height,
+					Hash:         header.BlockHash().String(),
+					PreviousHash: header.PrevBlock.String(),
+					Time:         header.Timestamp.Unix(),
+					Bits:         fmt.Sprintf("%08x", header.Bits),
+					SBits:        header.SBits,
+					VoteBits:     header.VoteBits,
+					Voters:       header.Voters,
+					FreshStake:   header.FreshStake,
+					Revocations:  header.Revocations,
+					PoolSize:     header.PoolSize,
+				})
+			}
+			if err != nil {
+				apiLog.Debugf("Failed to write block header: %v", err)
+				return
+			}
+			next++
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+
+	w.Header().Set("Next-Height", strconv.FormatInt(next, 10))
+}
+
 func (c *appContext) getBlockVerbose(w http.ResponseWriter, r *http.Request) {
 	hash, err := c.getBlockHashCtx(r)
 	if err != nil {
@@ -595,6 +1103,35 @@ func (c *appContext) getBlockVerbose(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, blockVerbose, m.GetIndentCtx(r))
 }
 
+// getBlockFull retrieves the block summary, trimmed transactions, vote
+// validation details, ticket info, and previous and next block hashes for the
+// block specified by hash, or by height if no hash is in the request context.
+func (c *appContext) getBlockFull(w http.ResponseWriter, r *http.Request) {
+	hash, err := c.getBlockHashCtx(r)
+	if err != nil {
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+
+	block, err := c.DataSource.BlockFull(r.Context(), hash)
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("BlockFull: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return
+	}
+	if err == sql.ErrNoRows {
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		apiLog.Errorf("Unable to get block %s: %v", hash, err)
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+
+	writeJSON(w, block, m.GetIndentCtx(r))
+}
+
 func (c *appContext) getVoteInfo(w http.ResponseWriter, r *http.Request) {
 	ver, verStr, err := getVoteVersionQuery(r)
 	if err != nil || ver < 0 {
@@ -615,10 +1152,10 @@ func (c *appContext) getVoteInfo(w http.ResponseWriter, r *http.Request) {
 // the specified transaction. This sets the vouts[i].Spend fields for each
 // output that is spent. For unspent outputs, the Spend field remains a nil
 // pointer.
-func (c *appContext) setOutputSpends(txid string, vouts []apitypes.Vout) error {
+func (c *appContext) setOutputSpends(ctx context.Context, txid string, vouts []apitypes.Vout) error {
 	// For each output of this transaction, look up any spending transactions,
 	// and the index of the spending transaction input.
-	spendHashes, spendVinInds, voutInds, err := c.DataSource.SpendingTransactions(txid)
+	spendHashes, spendVinInds, voutInds, err := c.DataSource.SpendingTransactions(ctx, txid)
 	if dbtypes.IsTimeoutErr(err) {
 		return fmt.Errorf("SpendingTransactions: %v", err)
 	}
@@ -643,14 +1180,69 @@ func (c *appContext) setOutputSpends(txid string, vouts []apitypes.Vout) error {
 // setTxSpends retrieves spending transaction information for each output of the
 // given transaction. This sets the tx.Vout[i].Spend fields for each output that
 // is spent. For unspent outputs, the Spend field remains a nil pointer.
-func (c *appContext) setTxSpends(tx *apitypes.Tx) error {
-	return c.setOutputSpends(tx.TxID, tx.Vout)
+func (c *appContext) setTxSpends(ctx context.Context, tx *apitypes.Tx) error {
+	return c.setOutputSpends(ctx, tx.TxID, tx.Vout)
 }
 
 // setTrimmedTxSpends is like setTxSpends except that it operates on a TrimmedTx
 // instead of a Tx.
-func (c *appContext) setTrimmedTxSpends(tx *apitypes.TrimmedTx) error {
-	return c.setOutputSpends(tx.TxID, tx.Vout)
+func (c *appContext) setTrimmedTxSpends(ctx context.Context, tx *apitypes.TrimmedTx) error {
+	return c.setOutputSpends(ctx, tx.TxID, tx.Vout)
+}
+
+// mainchainOnly parses the optional ?mainchain=[true|false] URL query. When
+// true, transactions in side chain blocks, or invalidated by stakeholder
+// disapproval of their block, are excluded.
+func mainchainOnly(r *http.Request) (bool, error) {
+	param := r.URL.Query().Get("mainchain")
+	if param == "" {
+		return false, nil
+	}
+	return strconv.ParseBool(param)
+}
+
+// setTxBlockStatus sets the chain status of the block of a mined transaction.
+// The status is not set if the block is not yet in the database.
+func (c *appContext) setTxBlockStatus(ctx context.Context, tx *apitypes.Tx) error {
+	if tx.Block == nil || tx.Block.BlockHash == "" {
+		return nil
+	}
+	blocks, _, err := c.DataSource.TransactionBlocks(ctx, tx.TxID)
+	if err != nil {
+		return err
+	}
+	for _, block := range blocks {
+		if block.Hash == tx.Block.BlockHash {
+			tx.Block.Status = apitypes.TxBlockStatus(block.IsMainchain, block.IsValid)
+			break
+		}
+	}
+	return nil
+}
+
+// setTxTimeLocks interprets the lock time and expiry of the transaction relative
+// to the block that includes it, or to the next block if it is unconfirmed.
+func (c *appContext) setTxTimeLocks(tx *apitypes.Tx) {
+	var sequenceLocked bool
+	for i := range tx.Vin {
+		if tx.Vin[i].Sequence != wire.MaxTxInSequenceNum {
+			sequenceLocked = true
+			break
+		}
+	}
+	height, blockTime := int64(c.Status.Height())+1, time.Now().Unix()
+	if tx.Block != nil && tx.Block.BlockHash != "" {
+		height, blockTime = tx.Block.BlockHeight, tx.Block.BlockTime
+	}
+	tx.TimeLocks = txhelpers.TimeLocks(tx.Locktime, tx.Expiry, sequenceLocked,
+		height, blockTime)
+}
+
+// isNonMainchainTx checks if the transaction's block status is known, and is
+// not a valid main chain block.
+func isNonMainchainTx(tx *apitypes.Tx) bool {
+	return tx.Block != nil && tx.Block.Status != "" &&
+		tx.Block.Status != apitypes.TxBlockMainchain
 }
 
 func (c *appContext) getTransaction(w http.ResponseWriter, r *http.Request) {
@@ -667,6 +1259,12 @@ func (c *appContext) getTransaction(w http.ResponseWriter, r *http.Request) {
 		withSpends = b
 	}
 
+	mainchain, err := mainchainOnly(r)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
 	txid, err := m.GetTxIDCtx(r)
 	if err != nil {
 		http.Error(w, http.StatusText(422), 422)
@@ -680,8 +1278,26 @@ func (c *appContext) getTransaction(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	err = c.setTxBlockStatus(r.Context(), tx)
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("TransactionBlocks: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		apiLog.Errorf("Unable to get the block status of transaction %s: %v", txid, err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError),
+			http.StatusInternalServerError)
+		return
+	}
+	if mainchain && isNonMainchainTx(tx) {
+		http.Error(w, "Transaction is not in a valid mainchain block.", http.StatusNotFound)
+		return
+	}
+	c.setTxTimeLocks(tx)
+
 	if withSpends {
-		if err := c.setTxSpends(tx); err != nil {
+		if err := c.setTxSpends(r.Context(), tx); err != nil {
 			apiLog.Errorf("Unable to get spending transaction info for outputs of %s: %v", txid, err)
 			http.Error(w, http.StatusText(http.StatusInternalServerError),
 				http.StatusInternalServerError)
@@ -692,16 +1308,88 @@ func (c *appContext) getTransaction(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, tx, m.GetIndentCtx(r))
 }
 
-func (c *appContext) getTransactionHex(w http.ResponseWriter, r *http.Request) {
+// getTransactionBlocks lists the blocks in which the transaction appears, with
+// the chain status of each. Only the valid main chain blocks are listed with
+// ?mainchain=true.
+func (c *appContext) getTransactionBlocks(w http.ResponseWriter, r *http.Request) {
+	mainchain, err := mainchainOnly(r)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
 	txid, err := m.GetTxIDCtx(r)
 	if err != nil {
 		http.Error(w, http.StatusText(422), 422)
 		return
 	}
 
-	hex := c.DataSource.GetTransactionHex(txid)
+	blocks, inds, err := c.DataSource.TransactionBlocks(r.Context(), txid.String())
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("TransactionBlocks: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		apiLog.Errorf("Unable to get the blocks of transaction %s: %v", txid, err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError),
+			http.StatusInternalServerError)
+		return
+	}
 
-	fmt.Fprint(w, hex)
+	txBlocks := make([]*apitypes.TxBlock, 0, len(blocks))
+	for i, block := range blocks {
+		status := apitypes.TxBlockStatus(block.IsMainchain, block.IsValid)
+		if mainchain && status != apitypes.TxBlockMainchain {
+			continue
+		}
+		txBlocks = append(txBlocks, &apitypes.TxBlock{
+			BlockHash:   block.Hash,
+			BlockHeight: int64(block.Height),
+			BlockIndex:  inds[i],
+			Status:      status,
+		})
+	}
+
+	writeJSON(w, txBlocks, m.GetIndentCtx(r))
+}
+
+// validateTx checks whether the raw transaction in the POSTed JSON object,
+// {"rawtx": "<hex>"}, would likely be accepted to the mempool, without
+// broadcasting it.
+func (c *appContext) validateTx(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		RawTx string `json:"rawtx"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.RawTx == "" {
+		http.Error(w, `expected a JSON object {"rawtx": "<hex>"}`, http.StatusBadRequest)
+		return
+	}
+
+	validation, err := c.DataSource.ValidateRawTransaction(r.Context(), req.RawTx)
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("ValidateRawTransaction: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		apiLog.Errorf("ValidateRawTransaction: %v", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, validation, m.GetIndentCtx(r))
+}
+
+func (c *appContext) getTransactionHex(w http.ResponseWriter, r *http.Request) {
+	txid, err := m.GetTxIDCtx(r)
+	if err != nil {
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+
+	hex := c.DataSource.GetTransactionHex(txid)
+
+	fmt.Fprint(w, hex)
 }
 
 func (c *appContext) getDecodedTx(w http.ResponseWriter, r *http.Request) {
@@ -732,7 +1420,7 @@ func (c *appContext) getDecodedTx(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if withSpends {
-		if err := c.setTrimmedTxSpends(tx); err != nil {
+		if err := c.setTrimmedTxSpends(r.Context(), tx); err != nil {
 			apiLog.Errorf("Unable to get spending transaction info for outputs of %s: %v", txid, err)
 			http.Error(w, http.StatusText(http.StatusInternalServerError),
 				http.StatusInternalServerError)
@@ -757,6 +1445,14 @@ func (c *appContext) getTransactions(w http.ResponseWriter, r *http.Request) {
 		withSpends = b
 	}
 
+	// Transactions that are not in a valid main chain block are omitted with
+	// ?mainchain=true.
+	mainchain, err := mainchainOnly(r)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
 	txids, err := m.GetTxnsCtx(r)
 	if err != nil {
 		http.Error(w, http.StatusText(422), 422)
@@ -772,8 +1468,25 @@ func (c *appContext) getTransactions(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		err = c.setTxBlockStatus(r.Context(), tx)
+		if dbtypes.IsTimeoutErr(err) {
+			apiLog.Errorf("TransactionBlocks: %v", err)
+			http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+			return
+		}
+		if err != nil {
+			apiLog.Errorf("Unable to get the block status of transaction %s: %v", txids[i], err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError),
+				http.StatusInternalServerError)
+			return
+		}
+		if mainchain && isNonMainchainTx(tx) {
+			continue
+		}
+		c.setTxTimeLocks(tx)
+
 		if withSpends {
-			if err := c.setTxSpends(tx); err != nil {
+			if err := c.setTxSpends(r.Context(), tx); err != nil {
 				apiLog.Errorf("Unable to get spending transaction info for outputs of %s: %v",
 					txids[i], err)
 				http.Error(w, http.StatusText(http.StatusInternalServerError),
@@ -826,6 +1539,35 @@ func (c *appContext) getTxVoteInfo(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, vinfo, m.GetIndentCtx(r))
 }
 
+// getVote serves a vote with its decoded vote bits from the votes table, for
+// /vote/{txid}.
+func (c *appContext) getVote(w http.ResponseWriter, r *http.Request) {
+	txid, err := m.GetTxIDCtx(r)
+	if err != nil {
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+
+	vote, err := c.DataSource.Vote(r.Context(), txid.String())
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("Vote: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return
+	}
+	if err == sql.ErrNoRows {
+		http.Error(w, "Vote not found.", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		apiLog.Errorf("Unable to get vote %v: %v", txid, err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError),
+			http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, vote, m.GetIndentCtx(r))
+}
+
 // For /tx/{txid}/tinfo
 func (c *appContext) getTxTicketInfo(w http.ResponseWriter, r *http.Request) {
 	txid, err := m.GetTxIDCtx(r)
@@ -833,7 +1575,7 @@ func (c *appContext) getTxTicketInfo(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, http.StatusText(422), 422)
 		return
 	}
-	tinfo, err := c.DataSource.GetTicketInfo(txid.String())
+	tinfo, err := c.DataSource.GetTicketInfo(r.Context(), txid.String())
 	if err != nil {
 		err = fmt.Errorf("unable to get ticket info for tx %v: %v",
 			txid, err)
@@ -841,9 +1583,58 @@ func (c *appContext) getTxTicketInfo(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), 422)
 		return
 	}
+	tinfo.Commitments, err = c.DataSource.TicketCommitments(r.Context(), txid.String())
+	if err != nil {
+		// The ticket info is still useful without the commitments.
+		apiLog.Errorf("TicketCommitments: %v", err)
+	}
 	writeJSON(w, tinfo, m.GetIndentCtx(r))
 }
 
+// For /tx/{txid}/luck
+func (c *appContext) getTxTicketLuck(w http.ResponseWriter, r *http.Request) {
+	txid, err := m.GetTxIDCtx(r)
+	if err != nil {
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+	luck, err := c.DataSource.TicketLuck(r.Context(), txid.String())
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("TicketLuck: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		err = fmt.Errorf("unable to get ticket luck for tx %v: %v",
+			txid, err)
+		apiLog.Error(err)
+		http.Error(w, err.Error(), 422)
+		return
+	}
+	writeJSON(w, luck, m.GetIndentCtx(r))
+}
+
+// For /tx/{txid}/wins
+func (c *appContext) getTicketWinningBlocks(w http.ResponseWriter, r *http.Request) {
+	txid, err := m.GetTxIDCtx(r)
+	if err != nil {
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+	blocks, err := c.DataSource.TicketWinningBlocks(r.Context(), txid.String())
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("TicketWinningBlocks: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		apiLog.Errorf("TicketWinningBlocks: %v", err)
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+	writeJSON(w, blocks, m.GetIndentCtx(r))
+}
+
 // getTransactionInputs serves []TxIn
 func (c *appContext) getTransactionInputs(w http.ResponseWriter, r *http.Request) {
 	txid, err := m.GetTxIDCtx(r)
@@ -964,6 +1755,128 @@ func (c *appContext) getBlockStakeInfoExtendedByHash(w http.ResponseWriter, r *h
 	writeJSON(w, stakeinfo, m.GetIndentCtx(r))
 }
 
+// getBlockWinners retrieves the tickets called to vote on the block specified
+// by hash, or by height if no hash is in the request context.
+func (c *appContext) getBlockWinners(w http.ResponseWriter, r *http.Request) {
+	var winners []string
+	hash, err := m.GetBlockHashCtx(r)
+	if err == nil {
+		winners, err = c.DataSource.GetWinnersByHash(r.Context(), hash)
+	} else {
+		var idx int64
+		idx, err = c.getBlockHeightCtx(r)
+		if err != nil {
+			http.Error(w, http.StatusText(422), 422)
+			return
+		}
+		winners, err = c.DataSource.GetWinners(r.Context(), idx)
+	}
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("GetWinners: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		apiLog.Errorf("Unable to get block winners: %v", err)
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+
+	writeJSON(w, winners, m.GetIndentCtx(r))
+}
+
+// getBlockChainWork retrieves the proof-of-work and cumulative chain work of a
+// block.
+func (c *appContext) getBlockChainWork(w http.ResponseWriter, r *http.Request) {
+	hash, err := c.getBlockHashCtx(r)
+	if err != nil {
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+
+	bcw, err := c.DataSource.BlockChainWork(r.Context(), hash)
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("BlockChainWork: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		apiLog.Errorf("Unable to get chain work for block %s: %v", hash, err)
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+
+	writeJSON(w, bcw, m.GetIndentCtx(r))
+}
+
+// getBlockStatus retrieves the chain status of a block, including whether its
+// regular transactions are approved, disapproved, or pending approval by the
+// votes in the next block.
+func (c *appContext) getBlockStatus(w http.ResponseWriter, r *http.Request) {
+	hash, err := c.getBlockHashCtx(r)
+	if err != nil {
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+
+	status, err := c.DataSource.BlockStatus(r.Context(), hash)
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("BlockStatus: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		apiLog.Errorf("Unable to get status of block %s: %v", hash, err)
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+
+	writeJSON(w, status, m.GetIndentCtx(r))
+}
+
+// getBlockStakeHeader retrieves the stake fields of the block header, such as
+// the vote bits and final state, from the DB rather than dcrd.
+func (c *appContext) getBlockStakeHeader(w http.ResponseWriter, r *http.Request) {
+	hash, err := c.getBlockHashCtx(r)
+	if err != nil {
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+
+	bsh, err := c.DataSource.BlockStakeHeader(r.Context(), hash)
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("BlockStakeHeader: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		apiLog.Errorf("Unable to get stake header for block %s: %v", hash, err)
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+
+	writeJSON(w, bsh, m.GetIndentCtx(r))
+}
+
+// getChainTipsChainWork retrieves the proof-of-work and cumulative chain work
+// of the main chain tip and all known side chain tips, most work first.
+func (c *appContext) getChainTipsChainWork(w http.ResponseWriter, r *http.Request) {
+	tips, err := c.DataSource.ChainTipsChainWork(r.Context())
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("ChainTipsChainWork: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		apiLog.Errorf("Unable to get chain tips: %v", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError),
+			http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, tips, m.GetIndentCtx(r))
+}
+
 // getBlockStakeInfoExtendedByHeight retrieves the apitype.StakeInfoExtended
 // for the given blockheight on mainchain
 func (c *appContext) getBlockStakeInfoExtendedByHeight(w http.ResponseWriter, r *http.Request) {
@@ -996,7 +1909,7 @@ func (c *appContext) getStakeDiffSummary(w http.ResponseWriter, r *http.Request)
 // Encodes apitypes.PowerlessTickets, which is missed or expired tickets sorted
 // by revocation status.
 func (c *appContext) getPowerlessTickets(w http.ResponseWriter, r *http.Request) {
-	tickets, err := c.DataSource.PowerlessTickets()
+	tickets, err := c.DataSource.PowerlessTickets(r.Context())
 	if err != nil {
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 		return
@@ -1005,6 +1918,80 @@ func (c *appContext) getPowerlessTickets(w http.ResponseWriter, r *http.Request)
 	writeJSON(w, tickets, m.GetIndentCtx(r))
 }
 
+// getRevocableTickets serves the missed and expired tickets that have not been
+// revoked, optionally only those with a commitment to the address given by the
+// "address" URL query parameter, and the amount recoverable by revoking them.
+func (c *appContext) getRevocableTickets(w http.ResponseWriter, r *http.Request) {
+	address := r.URL.Query().Get("address")
+	if address != "" {
+		if _, err := dcrutil.DecodeAddress(address, c.Params); err != nil {
+			http.Error(w, "invalid address", http.StatusBadRequest)
+			return
+		}
+	}
+
+	count := int64(m.GetNCtx(r))
+	skip := int64(m.GetMCtx(r))
+	if count <= 0 {
+		count = 100
+	} else if count > 8000 {
+		count = 8000
+	}
+	if skip <= 0 {
+		skip = 0
+	}
+
+	after, err := pageCursorFromQuery(r, dbtypes.CursorRevocable)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	tickets, next, err := c.DataSource.RevocableTickets(r.Context(), address, count, skip, after)
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("RevocableTickets: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		apiLog.Errorf("RevocableTickets: %v", err)
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+	setNextCursor(w, next)
+	writeJSON(w, tickets, m.GetIndentCtx(r))
+}
+
+// getDuplicateTransactions serves the transactions that appear in more than
+// one block, such as in both a side chain block and a main chain block, with
+// the chain status of each block.
+func (c *appContext) getDuplicateTransactions(w http.ResponseWriter, r *http.Request) {
+	count := int64(m.GetNCtx(r))
+	skip := int64(m.GetMCtx(r))
+	if count <= 0 {
+		count = 100
+	} else if count > 1000 {
+		count = 1000
+	}
+	if skip <= 0 {
+		skip = 0
+	}
+
+	txns, err := c.DataSource.DuplicateTransactions(r.Context(), count, skip)
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("DuplicateTransactions: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		apiLog.Errorf("DuplicateTransactions: %v", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError),
+			http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, txns, m.GetIndentCtx(r))
+}
+
 func (c *appContext) getStakeDiffCurrent(w http.ResponseWriter, r *http.Request) {
 	stakeDiff := c.DataSource.GetStakeDiffEstimates()
 	if stakeDiff == nil {
@@ -1032,34 +2019,139 @@ func (c *appContext) getStakeDiffEstimates(w http.ResponseWriter, r *http.Reques
 	writeJSON(w, stakeDiff.Estimates, m.GetIndentCtx(r))
 }
 
-func (c *appContext) getSSTxSummary(w http.ResponseWriter, r *http.Request) {
-	sstxSummary := c.DataSource.GetMempoolSSTxSummary()
-	if sstxSummary == nil {
-		apiLog.Errorf("Unable to get SSTx info from mempool")
-		http.Error(w, http.StatusText(422), 422)
-		return
-	}
+// defaultExpiringBlocks is the default number of blocks after the best block
+// within which the transactions listed by getMempoolExpiring expire.
+const defaultExpiringBlocks = 16
 
-	writeJSON(w, sstxSummary, m.GetIndentCtx(r))
+// getMempoolExpiring serves the mempool transactions that expire within the
+// number of blocks given by the "blocks" URL query parameter (default 16) after
+// the best block.
+func (c *appContext) getMempoolExpiring(w http.ResponseWriter, r *http.Request) {
+	blocks := int64(defaultExpiringBlocks)
+	if blocksParam := r.URL.Query().Get("blocks"); blocksParam != "" {
+		var err error
+		blocks, err = strconv.ParseInt(blocksParam, 10, 64)
+		if err != nil || blocks < 0 {
+			http.Error(w, "invalid blocks", http.StatusBadRequest)
+			return
+		}
+	}
+	writeJSON(w, c.DataSource.GetMempoolExpiring(blocks), m.GetIndentCtx(r))
 }
 
-func (c *appContext) getSSTxFees(w http.ResponseWriter, r *http.Request) {
-	N := m.GetNCtx(r)
-	sstxFees := c.DataSource.GetMempoolSSTxFeeRates(N)
-	if sstxFees == nil {
-		apiLog.Errorf("Unable to get SSTx fees from mempool")
-		http.Error(w, http.StatusText(422), 422)
+// getMempoolGraph serves the mempool transactions with mempool parents or
+// children, and their ancestor and descendant packages.
+func (c *appContext) getMempoolGraph(w http.ResponseWriter, r *http.Request) {
+	if c.mempoolGraph == nil {
+		http.Error(w, "Mempool graph unavailable.", http.StatusServiceUnavailable)
 		return
 	}
-
-	writeJSON(w, sstxFees, m.GetIndentCtx(r))
+	writeJSON(w, c.mempoolGraph.Graph(), m.GetIndentCtx(r))
 }
 
-func (c *appContext) getSSTxDetails(w http.ResponseWriter, r *http.Request) {
-	N := m.GetNCtx(r)
-	sstxDetails := c.DataSource.GetMempoolSSTxDetails(N)
-	if sstxDetails == nil {
-		apiLog.Errorf("Unable to get SSTx details from mempool")
+// getMempoolTxPackages serves the dependencies of a mempool transaction,
+// including all of its mempool ancestors and descendants.
+func (c *appContext) getMempoolTxPackages(w http.ResponseWriter, r *http.Request) {
+	if c.mempoolGraph == nil {
+		http.Error(w, "Mempool graph unavailable.", http.StatusServiceUnavailable)
+		return
+	}
+	txid, err := m.GetTxIDCtx(r)
+	if err != nil {
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+	pkgs := c.mempoolGraph.Packages(txid.String())
+	if pkgs == nil {
+		http.Error(w, "Transaction not in mempool.", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, pkgs, m.GetIndentCtx(r))
+}
+
+// getDailyExports serves the index of the daily bulk exports, oldest first,
+// starting with the date given by the "since" URL query (YYYY-MM-DD).
+func (c *appContext) getDailyExports(w http.ResponseWriter, r *http.Request) {
+	if c.dailyExports == nil {
+		http.Error(w, "Daily exports are disabled.", http.StatusServiceUnavailable)
+		return
+	}
+	since := r.URL.Query().Get("since")
+	if since != "" {
+		if _, err := time.Parse(dailyexport.DateFormat, since); err != nil {
+			http.Error(w, "invalid since date (YYYY-MM-DD)", http.StatusBadRequest)
+			return
+		}
+	}
+	writeJSON(w, c.dailyExports.Days(since), m.GetIndentCtx(r))
+}
+
+// getDailyExportFile serves a gzipped CSV file of a daily bulk export.
+func (c *appContext) getDailyExportFile(w http.ResponseWriter, r *http.Request) {
+	if c.dailyExports == nil {
+		http.Error(w, "Daily exports are disabled.", http.StatusServiceUnavailable)
+		return
+	}
+	date, name := m.GetDailyExportFileCtx(r)
+	path, err := c.dailyExports.File(date, name)
+	if err != nil {
+		http.Error(w, "Export file not found.", http.StatusNotFound)
+		return
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		apiLog.Errorf("Failed to open daily export file %s: %v", path, err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError),
+			http.StatusInternalServerError)
+		return
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		apiLog.Errorf("Failed to stat daily export file %s: %v", path, err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError),
+			http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Disposition",
+		fmt.Sprintf("attachment; filename=%s-%s", date, name))
+	http.ServeContent(w, r, name, info.ModTime(), f)
+}
+
+// getNextBlockPreview serves the likely next block assembled from the mempool.
+func (c *appContext) getNextBlockPreview(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, c.DataSource.NextBlockPreview(), m.GetIndentCtx(r))
+}
+
+func (c *appContext) getSSTxSummary(w http.ResponseWriter, r *http.Request) {
+	sstxSummary := c.DataSource.GetMempoolSSTxSummary()
+	if sstxSummary == nil {
+		apiLog.Errorf("Unable to get SSTx info from mempool")
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+
+	writeJSON(w, sstxSummary, m.GetIndentCtx(r))
+}
+
+func (c *appContext) getSSTxFees(w http.ResponseWriter, r *http.Request) {
+	N := m.GetNCtx(r)
+	sstxFees := c.DataSource.GetMempoolSSTxFeeRates(N)
+	if sstxFees == nil {
+		apiLog.Errorf("Unable to get SSTx fees from mempool")
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+
+	writeJSON(w, sstxFees, m.GetIndentCtx(r))
+}
+
+func (c *appContext) getSSTxDetails(w http.ResponseWriter, r *http.Request) {
+	N := m.GetNCtx(r)
+	sstxDetails := c.DataSource.GetMempoolSSTxDetails(N)
+	if sstxDetails == nil {
+		apiLog.Errorf("Unable to get SSTx details from mempool")
 		http.Error(w, http.StatusText(422), 422)
 		return
 	}
@@ -1070,7 +2162,7 @@ func (c *appContext) getSSTxDetails(w http.ResponseWriter, r *http.Request) {
 // getTicketPoolCharts pulls the initial data to populate the /ticketpool page
 // charts.
 func (c *appContext) getTicketPoolCharts(w http.ResponseWriter, r *http.Request) {
-	timeChart, priceChart, outputsChart, height, err := c.DataSource.TicketPoolVisualization(dbtypes.AllGrouping)
+	timeChart, priceChart, outputsChart, ageChart, height, err := c.DataSource.TicketPoolVisualization(r.Context(), dbtypes.AllGrouping)
 	if dbtypes.IsTimeoutErr(err) {
 		apiLog.Errorf("TicketPoolVisualization: %v", err)
 		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
@@ -1089,6 +2181,7 @@ func (c *appContext) getTicketPoolCharts(w http.ResponseWriter, r *http.Request)
 		TimeChart:    timeChart,
 		PriceChart:   priceChart,
 		OutputsChart: outputsChart,
+		AgeChart:     ageChart,
 		Mempool:      mp,
 	}
 
@@ -1106,7 +2199,7 @@ func (c *appContext) getTicketPoolByDate(w http.ResponseWriter, r *http.Request)
 	// TicketPoolVisualization here even though it returns a lot of data not
 	// needed by this request.
 	interval := dbtypes.TimeGroupingFromStr(tp)
-	timeChart, _, _, height, err := c.DataSource.TicketPoolVisualization(interval)
+	timeChart, _, _, _, height, err := c.DataSource.TicketPoolVisualization(r.Context(), interval)
 	if dbtypes.IsTimeoutErr(err) {
 		apiLog.Errorf("TicketPoolVisualization: %v", err)
 		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
@@ -1138,7 +2231,7 @@ func (c *appContext) getProposalChartData(w http.ResponseWriter, r *http.Request
 	}
 
 	token := m.GetProposalTokenCtx(r)
-	votesData, err := c.DataSource.ProposalVotes(token)
+	votesData, err := c.DataSource.ProposalVotes(r.Context(), token)
 	if dbtypes.IsTimeoutErr(err) {
 		apiLog.Errorf("ProposalVotes: %v", err)
 		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
@@ -1186,7 +2279,7 @@ func (c *appContext) blockSubsidies(w http.ResponseWriter, r *http.Request) {
 	numVotes := int16(c.Params.TicketsPerBlock)
 	if hash != "" {
 		var err error
-		numVotes, err = c.DataSource.VotesInBlock(hash)
+		numVotes, err = c.DataSource.VotesInBlock(r.Context(), hash)
 		if dbtypes.IsTimeoutErr(err) {
 			apiLog.Errorf("VotesInBlock: %v", err)
 			http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
@@ -1302,6 +2395,181 @@ func (c *appContext) getBlockRangeSummary(w http.ResponseWriter, r *http.Request
 	writeJSON(w, blocks, m.GetIndentCtx(r))
 }
 
+// getBlockList serves the summaries of up to "limit" (default 20) mainchain
+// blocks below the height or block hash in the "before" URL query parameter,
+// or up to the best block if it is not set, newest first. The next page starts
+// before the height of the last block.
+func (c *appContext) getBlockList(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	limit := int64(20)
+	if limitStr := q.Get("limit"); limitStr != "" {
+		var err error
+		limit, err = strconv.ParseInt(limitStr, 10, 64)
+		if err != nil || limit <= 0 {
+			http.Error(w, "invalid limit", http.StatusBadRequest)
+			return
+		}
+		if limit > maxBlockRangeCount {
+			limit = maxBlockRangeCount
+		}
+	}
+
+	before := int64(c.Status.Height()) + 1
+	if beforeStr := q.Get("before"); beforeStr != "" {
+		height, err := strconv.ParseInt(beforeStr, 10, 64)
+		switch {
+		case err == nil && height >= 0:
+			before = height
+		case err != nil && len(beforeStr) == 64:
+			// A block hash.
+			height, err = c.DataSource.BlockHeight(r.Context(), beforeStr)
+			if dbtypes.IsTimeoutErr(err) {
+				apiLog.Errorf("BlockHeight: %v", err)
+				http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+				return
+			}
+			if err == sql.ErrNoRows {
+				http.Error(w, "block not found", http.StatusNotFound)
+				return
+			}
+			if err != nil {
+				apiLog.Errorf("BlockHeight: %v", err)
+				http.Error(w, http.StatusText(422), 422)
+				return
+			}
+			before = height
+		default:
+			http.Error(w, "invalid before height or hash", http.StatusBadRequest)
+			return
+		}
+	}
+
+	blocks, err := c.DataSource.BlockSummariesBefore(r.Context(), before, limit)
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("BlockSummariesBefore: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		apiLog.Errorf("BlockSummariesBefore: %v", err)
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+	if blocks == nil {
+		blocks = []*apitypes.BlockDataBasic{}
+	}
+	writeJSON(w, blocks, m.GetIndentCtx(r))
+}
+
+// getScriptAnomalies reports the outputs of the mainchain blocks in the range
+// with nonstandard scripts or script versions other than 0.
+func (c *appContext) getScriptAnomalies(w http.ResponseWriter, r *http.Request) {
+	low, high := m.GetBlockIndex0Ctx(r), m.GetBlockIndexCtx(r)
+	if low > high {
+		low, high = high, low
+	}
+	if low < 0 || uint32(high) > c.Status.Height() {
+		http.Error(w, "invalid block range", http.StatusBadRequest)
+		return
+	}
+	if high-low+1 > maxScriptAnomalyRange {
+		http.Error(w, fmt.Sprintf("requested more than %d-block maximum", maxScriptAnomalyRange), http.StatusBadRequest)
+		return
+	}
+
+	report, err := c.DataSource.ScriptAnomalies(r.Context(), int64(low), int64(high))
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("ScriptAnomalies: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		apiLog.Errorf("ScriptAnomalies: %v", err)
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+	writeJSON(w, report, m.GetIndentCtx(r))
+}
+
+// getBlockAnomalies reports the anomalies of the mainchain blocks in the range,
+// optionally only of the kind given by the "kind" URL query parameter.
+func (c *appContext) getBlockAnomalies(w http.ResponseWriter, r *http.Request) {
+	low, high := m.GetBlockIndex0Ctx(r), m.GetBlockIndexCtx(r)
+	if low > high {
+		low, high = high, low
+	}
+	if low < 0 || uint32(high) > c.Status.Height() {
+		http.Error(w, "invalid block range", http.StatusBadRequest)
+		return
+	}
+	if high-low+1 > maxScriptAnomalyRange {
+		http.Error(w, fmt.Sprintf("requested more than %d-block maximum", maxScriptAnomalyRange), http.StatusBadRequest)
+		return
+	}
+
+	kind := r.URL.Query().Get("kind")
+	switch kind {
+	case "", dbtypes.BlockAnomalyEmpty, dbtypes.BlockAnomalyFewVotes,
+		dbtypes.BlockAnomalyLarge, dbtypes.BlockAnomalyDeepReorg:
+	default:
+		http.Error(w, fmt.Sprintf("unknown anomaly kind %q", kind), http.StatusBadRequest)
+		return
+	}
+
+	anomalies, err := c.DataSource.BlockAnomalies(r.Context(), int64(low), int64(high), kind)
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("BlockAnomalies: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		apiLog.Errorf("BlockAnomalies: %v", err)
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+	writeJSON(w, anomalies, m.GetIndentCtx(r))
+}
+
+// getBlockRangeVins lists the inputs of the mainchain transactions in the
+// range with signature scripts of the type given by the "sigtype" URL query
+// parameter, such as "multisig" redemptions.
+func (c *appContext) getBlockRangeVins(w http.ResponseWriter, r *http.Request) {
+	low, high := m.GetBlockIndex0Ctx(r), m.GetBlockIndexCtx(r)
+	if low > high {
+		low, high = high, low
+	}
+	if low < 0 || uint32(high) > c.Status.Height() {
+		http.Error(w, "invalid block range", http.StatusBadRequest)
+		return
+	}
+	if high-low+1 > maxSigTypeVinRange {
+		http.Error(w, fmt.Sprintf("requested more than %d-block maximum", maxSigTypeVinRange), http.StatusBadRequest)
+		return
+	}
+
+	sigType, err := dbtypes.VinSigTypeFromStr(r.URL.Query().Get("sigtype"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	vins, err := c.DataSource.VinsBySigType(r.Context(), int64(low), int64(high), sigType)
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("VinsBySigType: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		apiLog.Errorf("VinsBySigType: %v", err)
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+	if vins == nil {
+		vins = []*dbtypes.SigTypeVin{}
+	}
+	writeJSON(w, vins, m.GetIndentCtx(r))
+}
+
 func (c *appContext) getBlockRangeSteppedSummary(w http.ResponseWriter, r *http.Request) {
 	idx0 := m.GetBlockIndex0Ctx(r)
 	idx1 := m.GetBlockIndexCtx(r)
@@ -1366,6 +2634,17 @@ func (c *appContext) getTicketPool(w http.ResponseWriter, r *http.Request) {
 }
 
 func (c *appContext) getTicketPoolInfo(w http.ResponseWriter, r *http.Request) {
+	// Use the block hash directly if provided, avoiding a height lookup.
+	if hash, err := m.GetBlockHashCtx(r); err == nil {
+		tpi := c.DataSource.GetPoolInfoByHash(hash)
+		if tpi == nil {
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+		writeJSON(w, tpi, m.GetIndentCtx(r))
+		return
+	}
+
 	idx, err := c.getBlockHeightCtx(r)
 	if err != nil {
 		http.Error(w, http.StatusText(422), 422)
@@ -1407,6 +2686,59 @@ func (c *appContext) getTicketPoolInfoRange(w http.ResponseWriter, r *http.Reque
 	writeJSON(w, tpis, m.GetIndentCtx(r))
 }
 
+// maxVoteOddsTickets is the maximum number of tickets in a vote odds request.
+// The no-vote probabilities are computed per block for every ticket, so the
+// cost of a request grows with the number of tickets.
+const maxVoteOddsTickets = 50
+
+// getTicketVoteOdds serves the probability that at least one of the POSTed
+// tickets votes within the number of blocks given by the "blocks" URL query
+// parameter, and the distribution of the wait for the first vote. If blocks is
+// not given, it covers the full maturity and expiry period.
+func (c *appContext) getTicketVoteOdds(w http.ResponseWriter, r *http.Request) {
+	var numBlocks int64
+	if blocks := r.URL.Query().Get("blocks"); blocks != "" {
+		var err error
+		numBlocks, err = strconv.ParseInt(blocks, 10, 64)
+		if err != nil || numBlocks <= 0 {
+			http.Error(w, "invalid blocks", http.StatusBadRequest)
+			return
+		}
+	}
+
+	hashes, err := m.GetTxnsCtx(r)
+	if err != nil {
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+	if len(hashes) > maxVoteOddsTickets {
+		http.Error(w, fmt.Sprintf("Maximum of %d tickets allowed", maxVoteOddsTickets),
+			http.StatusBadRequest)
+		return
+	}
+	txids := make([]string, 0, len(hashes))
+	for _, hash := range hashes {
+		txids = append(txids, hash.String())
+	}
+
+	odds, err := c.DataSource.TicketVoteOdds(r.Context(), txids, numBlocks)
+	if err == dbtypes.ErrEmptyTicketPool {
+		http.Error(w, "Ticket pool not available.", http.StatusServiceUnavailable)
+		return
+	}
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("TicketVoteOdds: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		apiLog.Errorf("TicketVoteOdds: %v", err)
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+	writeJSON(w, odds, m.GetIndentCtx(r))
+}
+
 func (c *appContext) getTicketPoolValAndSizeRange(w http.ResponseWriter, r *http.Request) {
 	idx0 := m.GetBlockIndex0Ctx(r)
 	if idx0 < 0 {
@@ -1435,58 +2767,486 @@ func (c *appContext) getTicketPoolValAndSizeRange(w http.ResponseWriter, r *http
 	writeJSON(w, tPVS, m.GetIndentCtx(r))
 }
 
-func (c *appContext) getStakeDiff(w http.ResponseWriter, r *http.Request) {
-	idx, err := c.getBlockHeightCtx(r)
+// getMinerShares serves the distribution of the last N mainchain blocks among
+// their coinbase payout addresses, with each address's estimated share of the
+// network hashrate.
+func (c *appContext) getMinerShares(w http.ResponseWriter, r *http.Request) {
+	N := int64(m.GetNCtx(r))
+	if N <= 0 {
+		N = 1000
+	} else if N > 8064 {
+		N = 8064
+	}
+
+	shares, err := c.DataSource.MinerShares(r.Context(), N)
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("MinerShares: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return
+	}
 	if err != nil {
+		apiLog.Errorf("MinerShares: %v", err)
 		http.Error(w, http.StatusText(422), 422)
 		return
 	}
 
-	sdiff := c.DataSource.GetSDiff(int(idx))
-	writeJSON(w, []float64{sdiff}, m.GetIndentCtx(r))
+	if block := c.DataSource.GetBestBlockSummary(); block != nil {
+		shares.NetworkHashRate = dbtypes.CalculateHashRate(block.Difficulty,
+			c.Params.TargetTimePerBlock.Seconds())
+		for _, miner := range shares.Miners {
+			miner.HashRate = miner.Share * shares.NetworkHashRate
+		}
+	}
+	writeJSON(w, shares, m.GetIndentCtx(r))
 }
 
-func (c *appContext) getStakeDiffRange(w http.ResponseWriter, r *http.Request) {
-	idx0 := m.GetBlockIndex0Ctx(r)
-	if idx0 < 0 {
+// getStakeDiffEstimateAccuracy compares the stake difficulty estimates made in
+// past windows with the realized stake difficulty of the following windows.
+func (c *appContext) getStakeDiffEstimateAccuracy(w http.ResponseWriter, r *http.Request) {
+	count := int64(m.GetNCtx(r))
+	skip := int64(m.GetMCtx(r))
+	if count <= 0 {
+		count = 20
+	} else if count > 2000 {
+		count = 2000
+	}
+	if skip <= 0 {
+		skip = 0
+	}
+
+	windows, err := c.DataSource.StakeDiffEstimateAccuracy(r.Context(), count, skip)
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("StakeDiffEstimateAccuracy: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		apiLog.Errorf("StakeDiffEstimateAccuracy: %v", err)
 		http.Error(w, http.StatusText(422), 422)
 		return
 	}
+	if windows == nil {
+		windows = []*dbtypes.StakeDiffEstimateAccuracy{}
+	}
+	writeJSON(w, windows, m.GetIndentCtx(r))
+}
 
-	idx := m.GetBlockIndexCtx(r)
-	if idx < 0 {
+// getStakeDiffWindows serves the ticket price of the latest stake difficulty
+// windows as an open-high-low-close series, with the windows' ticket pool
+// statistics.
+func (c *appContext) getStakeDiffWindows(w http.ResponseWriter, r *http.Request) {
+	count := int64(m.GetNCtx(r))
+	skip := int64(m.GetMCtx(r))
+	if count <= 0 {
+		count = 20
+	} else if count > 2000 {
+		count = 2000
+	}
+	if skip <= 0 {
+		skip = 0
+	}
+
+	windows, err := c.DataSource.StakeDiffWindowsOHLC(r.Context(), count, skip)
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("StakeDiffWindowsOHLC: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		apiLog.Errorf("StakeDiffWindowsOHLC: %v", err)
 		http.Error(w, http.StatusText(422), 422)
 		return
 	}
-
-	sdiffs := c.DataSource.GetSDiffRange(idx0, idx)
-	writeJSON(w, sdiffs, m.GetIndentCtx(r))
+	if windows == nil {
+		windows = []*dbtypes.StakeDiffWindowOHLC{}
+	}
+	writeJSON(w, windows, m.GetIndentCtx(r))
 }
 
-func (c *appContext) addressTotals(w http.ResponseWriter, r *http.Request) {
-	addresses, err := m.GetAddressCtx(r, c.Params)
-	if err != nil || len(addresses) > 1 {
-		http.Error(w, http.StatusText(422), 422)
+// getTicketPoolSimulation processes a request for a replay of the historical
+// ticket demand with alternative stake difficulty parameters from
+// /stake/simulate. The "window" and "poolsize" URL queries set the stake
+// difficulty window size and the target ticket pool size in units of the
+// tickets per block, which default to the network's parameters. The "demand"
+// URL query is either "tickets" (the default) or "value".
+func (c *appContext) getTicketPoolSimulation(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	cfg := stakesim.Config{Demand: stakesim.Demand(query.Get("demand"))}
+	parseParam := func(name string, max int64) (int64, bool) {
+		str := query.Get(name)
+		if str == "" {
+			return 0, true
+		}
+		n, err := strconv.ParseInt(str, 10, 64)
+		if err != nil || n < 1 || n > max {
+			http.Error(w, fmt.Sprintf("%s must be between 1 and %d", name, max),
+				http.StatusBadRequest)
+			return 0, false
+		}
+		return n, true
+	}
+	var ok bool
+	if cfg.WindowSize, ok = parseParam("window", maxSimWindowSize); !ok {
+		return
+	}
+	if cfg.TicketPoolSize, ok = parseParam("poolsize", math.MaxUint16); !ok {
+		return
+	}
+	switch cfg.Demand {
+	case "", stakesim.DemandTickets, stakesim.DemandValue:
+	default:
+		http.Error(w, "invalid demand", http.StatusBadRequest)
 		return
 	}
 
-	address := addresses[0]
-	totals, err := c.DataSource.AddressTotals(address)
+	history, err := c.DataSource.TicketDemandHistory(r.Context())
 	if dbtypes.IsTimeoutErr(err) {
-		apiLog.Errorf("AddressTotals: %v", err)
+		apiLog.Errorf("TicketDemandHistory: %v", err)
 		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
 		return
 	}
 	if err != nil {
-		log.Warnf("failed to get address totals (%s): %v", address, err)
+		apiLog.Errorf("TicketDemandHistory: %v", err)
 		http.Error(w, http.StatusText(422), 422)
 		return
 	}
 
-	writeJSON(w, totals, m.GetIndentCtx(r))
+	result, err := stakesim.Simulate(c.Params, cfg, history)
+	if err != nil {
+		apiLog.Errorf("Simulate: %v", err)
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+	writeJSON(w, result, m.GetIndentCtx(r))
 }
 
-// addressExists provides access to the existsaddresses RPC call and parses the
+func (c *appContext) getStakeDiff(w http.ResponseWriter, r *http.Request) {
+	idx, err := c.getBlockHeightCtx(r)
+	if err != nil {
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+
+	sdiff := c.DataSource.GetSDiff(int(idx))
+	writeJSON(w, []float64{sdiff}, m.GetIndentCtx(r))
+}
+
+func (c *appContext) getStakeDiffRange(w http.ResponseWriter, r *http.Request) {
+	idx0 := m.GetBlockIndex0Ctx(r)
+	if idx0 < 0 {
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+
+	idx := m.GetBlockIndexCtx(r)
+	if idx < 0 {
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+
+	sdiffs := c.DataSource.GetSDiffRange(idx0, idx)
+	writeJSON(w, sdiffs, m.GetIndentCtx(r))
+}
+
+func (c *appContext) addressTotals(w http.ResponseWriter, r *http.Request) {
+	addresses, err := m.GetAddressCtx(r, c.Params)
+	if err != nil || len(addresses) > 1 {
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+
+	rate, status, err := c.fiatRate(r)
+	if err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	address := addresses[0]
+	totals, err := c.DataSource.AddressTotals(r.Context(), address)
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("AddressTotals: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		log.Warnf("failed to get address totals (%s): %v", address, err)
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+
+	if rate != nil {
+		totals.Fiat = fiatValues(rate, map[string]float64{
+			"dcr_spent":   totals.CoinsSpent,
+			"dcr_unspent": totals.CoinsUnspent,
+		})
+	}
+
+	writeJSON(w, totals, m.GetIndentCtx(r))
+}
+
+// addressTxCounts writes the numbers of funding and spending transaction rows
+// of an address, which are maintained as blocks are stored so that they are
+// fast even for addresses with very many transactions.
+func (c *appContext) addressTxCounts(w http.ResponseWriter, r *http.Request) {
+	addresses, err := m.GetAddressCtx(r, c.Params)
+	if err != nil || len(addresses) > 1 {
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+
+	address := addresses[0]
+	counts, err := c.DataSource.AddressTxCounts(r.Context(), address)
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("AddressTxCounts: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		log.Warnf("failed to get address transaction counts (%s): %v", address, err)
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+
+	writeJSON(w, counts, m.GetIndentCtx(r))
+}
+
+// addressSummary writes a trimmed summary of an address for lightweight
+// clients such as wallets: the balance, transaction count, and the times of
+// the first and last transactions.
+func (c *appContext) addressSummary(w http.ResponseWriter, r *http.Request) {
+	addresses, err := m.GetAddressCtx(r, c.Params)
+	if err != nil || len(addresses) > 1 {
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+
+	address := addresses[0]
+	summary, err := c.DataSource.AddressSummary(r.Context(), address)
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("AddressSummary: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		log.Warnf("failed to get address summary (%s): %v", address, err)
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+
+	writeJSON(w, summary, m.GetIndentCtx(r))
+}
+
+// maxPaymentURITextLen is the maximum length in bytes of the label and message
+// of a payment request URI. This keeps the URI within the capacity of a QR
+// code.
+const maxPaymentURITextLen = 256
+
+// paymentURI builds the payment request URI for the address in the request
+// path from the "amount" (in DCR), "label" and "message" URL queries.
+func (c *appContext) paymentURI(r *http.Request) (*apitypes.PaymentURI, error) {
+	addresses, err := m.GetAddressCtx(r, c.Params)
+	if err != nil {
+		return nil, err
+	}
+	if len(addresses) > 1 {
+		return nil, fmt.Errorf("only one address is allowed")
+	}
+
+	query := r.URL.Query()
+	uri := &apitypes.PaymentURI{
+		Address: addresses[0],
+		Label:   query.Get("label"),
+		Message: query.Get("message"),
+	}
+	if len(uri.Label) > maxPaymentURITextLen || len(uri.Message) > maxPaymentURITextLen {
+		return nil, fmt.Errorf("label and message are limited to %d bytes",
+			maxPaymentURITextLen)
+	}
+	var amount dcrutil.Amount
+	if amountStr := query.Get("amount"); amountStr != "" {
+		coins, err := strconv.ParseFloat(amountStr, 64)
+		if err != nil || coins <= 0 {
+			return nil, fmt.Errorf("invalid amount")
+		}
+		amount, err = dcrutil.NewAmount(coins)
+		if err != nil || amount > dcrutil.MaxAmount {
+			return nil, fmt.Errorf("invalid amount")
+		}
+		uri.Amount = amount.ToCoin()
+	}
+	uri.URI = txhelpers.PaymentURI(uri.Address, amount, uri.Label, uri.Message)
+	return uri, nil
+}
+
+// addressPaymentURI writes the payment request URI for an address.
+func (c *appContext) addressPaymentURI(w http.ResponseWriter, r *http.Request) {
+	uri, err := c.paymentURI(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, uri, m.GetIndentCtx(r))
+}
+
+// verifyMessage checks the signature of a message in the POSTed JSON object,
+// {"message": "<message>", "signature": "<base64>"}, against the address, as
+// made by the dcrwallet signmessage RPC.
+func (c *appContext) verifyMessage(w http.ResponseWriter, r *http.Request) {
+	addresses, err := m.GetAddressCtx(r, c.Params)
+	if err != nil || len(addresses) > 1 {
+		http.Error(w, "only one address is allowed", http.StatusBadRequest)
+		return
+	}
+	var req struct {
+		Message   string `json:"message"`
+		Signature string `json:"signature"`
+	}
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil || req.Signature == "" {
+		http.Error(w, `expected a JSON object {"message": "<message>", "signature": "<base64>"}`,
+			http.StatusBadRequest)
+		return
+	}
+
+	valid, err := txhelpers.VerifyMessage(addresses[0], req.Signature, req.Message, c.Params)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, &apitypes.VerifiedMessage{
+		Address:   addresses[0],
+		Message:   req.Message,
+		Signature: req.Signature,
+		Valid:     valid,
+	}, m.GetIndentCtx(r))
+}
+
+// Default and maximum sizes in pixels of the QR code images.
+const (
+	defaultQRSize = 256
+	maxQRSize     = 1024
+)
+
+// addressQRCode writes a QR code encoding the payment request URI for an
+// address, as a PNG image or, with the "format=svg" URL query, an SVG image.
+// The "size" URL query sets the image width and height in pixels.
+func (c *appContext) addressQRCode(w http.ResponseWriter, r *http.Request) {
+	uri, err := c.paymentURI(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	query := r.URL.Query()
+	size := defaultQRSize
+	if sizeStr := query.Get("size"); sizeStr != "" {
+		size, err = strconv.Atoi(sizeStr)
+		if err != nil || size <= 0 || size > maxQRSize {
+			http.Error(w, fmt.Sprintf("invalid size (max %d)", maxQRSize),
+				http.StatusBadRequest)
+			return
+		}
+	}
+
+	// The URI is user input, so it is not logged.
+	code, err := qrcode.New(uri.URI, qrcode.Medium)
+	if err != nil {
+		http.Error(w, "unable to encode the payment URI as a QR code",
+			http.StatusBadRequest)
+		return
+	}
+
+	var img []byte
+	switch query.Get("format") {
+	case "", "png":
+		img, err = code.PNG(size)
+		if err != nil {
+			apiLog.Errorf("Failed to encode QR code image: %v", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError),
+				http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "image/png")
+	case "svg":
+		img = qrSVG(code.Bitmap(), size)
+		w.Header().Set("Content-Type", "image/svg+xml")
+	default:
+		http.Error(w, "invalid format", http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Length", strconv.Itoa(len(img)))
+	if _, err = w.Write(img); err != nil {
+		apiLog.Warnf("ResponseWriter.Write error: %v", err)
+	}
+}
+
+// qrSVG draws the QR code modules, true for dark, as an SVG image of the given
+// width and height.
+func qrSVG(bitmap [][]bool, size int) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" `+
+		`viewBox="0 0 %d %d" shape-rendering="crispEdges">`, size, size,
+		len(bitmap), len(bitmap))
+	fmt.Fprintf(&b, `<rect width="%d" height="%d" fill="#fff"/><path fill="#000" d="`,
+		len(bitmap), len(bitmap))
+	for y, row := range bitmap {
+		for x, dark := range row {
+			if dark {
+				fmt.Fprintf(&b, "M%d %dh1v1h-1z", x, y)
+			}
+		}
+	}
+	b.WriteString(`"/></svg>`)
+	return b.Bytes()
+}
+
+// addressBalanceAt computes the confirmed balance of an address as of the
+// mainchain block specified by the "height" URL query, or as of the last block
+// mined at or before the UNIX time specified by the "time" URL query.
+func (c *appContext) addressBalanceAt(w http.ResponseWriter, r *http.Request) {
+	addresses, err := m.GetAddressCtx(r, c.Params)
+	if err != nil || len(addresses) > 1 {
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+	address := addresses[0]
+
+	var bal *dbtypes.HistoricalAddressBalance
+	query := r.URL.Query()
+	if heightStr := query.Get("height"); heightStr != "" {
+		var height int64
+		height, err = strconv.ParseInt(heightStr, 10, 64)
+		if err != nil {
+			http.Error(w, "invalid height", http.StatusBadRequest)
+			return
+		}
+		bal, err = c.DataSource.AddressBalanceAt(r.Context(), address, height)
+	} else if timeStr := query.Get("time"); timeStr != "" {
+		var t int64
+		t, err = strconv.ParseInt(timeStr, 10, 64)
+		if err != nil {
+			http.Error(w, "invalid time", http.StatusBadRequest)
+			return
+		}
+		bal, err = c.DataSource.AddressBalanceAtTime(r.Context(), address, t)
+	} else {
+		bal, err = c.DataSource.AddressBalanceAt(r.Context(), address, c.DataSource.Height())
+	}
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("AddressBalanceAt: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		log.Warnf("failed to get historical address balance (%s): %v", address, err)
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+
+	writeJSON(w, bal, m.GetIndentCtx(r))
+}
+
+// addressExists provides access to the existsaddresses RPC call and parses the
 // hexadecimal string into a list of bools. A maximum of 64 addresses can be
 // provided. Duplicates are not filtered.
 func (c *appContext) addressExists(w http.ResponseWriter, r *http.Request) {
@@ -1544,7 +3304,7 @@ func (c *appContext) addressIoCsv(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	rows, err := c.DataSource.AddressTxIoCsv(address)
+	rows, err := c.DataSource.AddressTxIoCsv(r.Context(), address)
 	if err != nil {
 		log.Errorf("Failed to fetch AddressTxIoCsv: %v", err)
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
@@ -1571,7 +3331,7 @@ func (c *appContext) getAddressTxTypesData(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	data, err := c.DataSource.TxHistoryData(address, dbtypes.TxsType,
+	data, err := c.DataSource.TxHistoryData(r.Context(), address, dbtypes.TxsType,
 		dbtypes.TimeGroupingFromStr(chartGrouping))
 	if dbtypes.IsTimeoutErr(err) {
 		apiLog.Errorf("TxHistoryData: %v", err)
@@ -1601,7 +3361,7 @@ func (c *appContext) getAddressTxAmountFlowData(w http.ResponseWriter, r *http.R
 		return
 	}
 
-	data, err := c.DataSource.TxHistoryData(address, dbtypes.AmountFlow,
+	data, err := c.DataSource.TxHistoryData(r.Context(), address, dbtypes.AmountFlow,
 		dbtypes.TimeGroupingFromStr(chartGrouping))
 	if dbtypes.IsTimeoutErr(err) {
 		apiLog.Errorf("TxHistoryData: %v", err)
@@ -1617,8 +3377,16 @@ func (c *appContext) getAddressTxAmountFlowData(w http.ResponseWriter, r *http.R
 	writeJSON(w, data, m.GetIndentCtx(r))
 }
 
+// ChartTypeData serves the chart data for the chart type in the URL path. If
+// either of the "from" or "to" URL query parameters (UNIX seconds) are set, the
+// chart is served for that time span at a resolution selected automatically
+// from the span, and the "bin" and "axis" parameters are ignored.
 func (c *appContext) ChartTypeData(w http.ResponseWriter, r *http.Request) {
 	chartType := m.GetChartTypeCtx(r)
+	if from, to := r.URL.Query().Get("from"), r.URL.Query().Get("to"); from != "" || to != "" {
+		c.spanChartData(w, r, chartType, from, to)
+		return
+	}
 	bin := r.URL.Query().Get("bin")
 	// Support the deprecated URL parameter "zoom".
 	if bin == "" {
@@ -1634,6 +3402,62 @@ func (c *appContext) ChartTypeData(w http.ResponseWriter, r *http.Request) {
 	writeJSONBytes(w, chartData)
 }
 
+// spanChartData serves the chart for the time span from the UNIX time from to
+// the UNIX time to. An empty from is the genesis block, and an empty to is the
+// best block.
+func (c *appContext) spanChartData(w http.ResponseWriter, r *http.Request, chartType, from, to string) {
+	var start, end uint64
+	var err error
+	if from != "" {
+		if start, err = strconv.ParseUint(from, 10, 64); err != nil {
+			http.Error(w, "invalid from time", http.StatusUnprocessableEntity)
+			return
+		}
+	}
+	if to != "" {
+		if end, err = strconv.ParseUint(to, 10, 64); err != nil {
+			http.Error(w, "invalid to time", http.StatusUnprocessableEntity)
+			return
+		}
+	}
+
+	chartData, err := c.charts.SpanChart(chartType, start, end)
+	if err == cache.UnknownChartErr {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	writeJSONBytes(w, chartData)
+}
+
+// defaultProjectionPeriod is how far into the future the coin supply is
+// projected if no end time is specified.
+const defaultProjectionPeriod = 4 * 365 * 24 * time.Hour
+
+// coinSupplyProjection serves the observed coin supply series with a projection
+// to the time specified by the "until" URL query parameter (UNIX seconds).
+func (c *appContext) coinSupplyProjection(w http.ResponseWriter, r *http.Request) {
+	until := time.Now().Add(defaultProjectionPeriod)
+	if untilStr := r.URL.Query().Get("until"); untilStr != "" {
+		t, err := strconv.ParseInt(untilStr, 10, 64)
+		if err != nil {
+			http.Error(w, "invalid until time", http.StatusUnprocessableEntity)
+			return
+		}
+		until = time.Unix(t, 0)
+	}
+
+	chartData, err := c.charts.CoinSupplyProjection(until)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	writeJSONBytes(w, chartData)
+}
+
 // route: /market/{token}/candlestick/{bin}
 func (c *appContext) getCandlestickChart(w http.ResponseWriter, r *http.Request) {
 	if c.xcBot == nil {
@@ -1696,7 +3520,40 @@ func (c *appContext) getAddressTransactions(w http.ResponseWriter, r *http.Reque
 		skip = 0
 	}
 
-	txs, err := c.DataSource.AddressTransactionDetails(address, count, skip, dbtypes.AddrTxnAll)
+	from, to, byTime, err := timeRangeFromQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	after, err := pageCursorFromQuery(r, dbtypes.CursorAddressRows)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	txnType, err := addrTxnViewFromQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if txnType != dbtypes.AddrTxnAll && (byTime || after != nil) {
+		http.Error(w, "txntype is not supported with a time range or cursor",
+			http.StatusBadRequest)
+		return
+	}
+	if after != nil && !byTime {
+		// Pages after a cursor are queried like a time range covering every
+		// block.
+		from, to, byTime = time.Unix(0, 0), time.Now().Add(maxBlockTimeOffset), true
+	}
+
+	var txs *apitypes.Address
+	var next *dbtypes.PageCursor
+	if byTime {
+		txs, next, err = c.DataSource.AddressTransactionDetailsByTime(r.Context(), address,
+			from, to, count, skip, after)
+	} else {
+		txs, next, err = c.DataSource.AddressTransactionDetails(r.Context(), address, count, skip, txnType)
+	}
 	if dbtypes.IsTimeoutErr(err) {
 		apiLog.Errorf("AddressTransactionDetails: %v", err)
 		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
@@ -1707,10 +3564,89 @@ func (c *appContext) getAddressTransactions(w http.ResponseWriter, r *http.Reque
 		http.Error(w, http.StatusText(422), 422)
 		return
 	}
+	setNextCursor(w, next)
 	writeJSON(w, txs, m.GetIndentCtx(r))
 }
 
-func (c *appContext) getAddressTransactionsRaw(w http.ResponseWriter, r *http.Request) {
+// addrTxnViewFromQuery parses the optional "txntype" URL query parameter, which
+// selects the address transactions view. The default is dbtypes.AddrTxnAll.
+func addrTxnViewFromQuery(r *http.Request) (dbtypes.AddrTxnViewType, error) {
+	txntype := r.URL.Query().Get("txntype")
+	if txntype == "" {
+		return dbtypes.AddrTxnAll, nil
+	}
+	txnType := dbtypes.AddrTxnViewTypeFromStr(txntype)
+	if txnType == dbtypes.AddrTxnUnknown {
+		return txnType, fmt.Errorf("unknown txntype %q", txntype)
+	}
+	return txnType, nil
+}
+
+// maxBlockTimeOffset is how far into the future a block's timestamp may be, as
+// enforced by consensus. It bounds the default end of a time range.
+const maxBlockTimeOffset = 2 * time.Hour
+
+// timeRangeFromQuery parses the optional "from" and "to" URL query parameters
+// as UNIX times in seconds. byTime is false if neither is set. If only one is
+// set, the range is open on the other end.
+func timeRangeFromQuery(r *http.Request) (from, to time.Time, byTime bool, err error) {
+	q := r.URL.Query()
+	fromStr, toStr := q.Get("from"), q.Get("to")
+	if fromStr == "" && toStr == "" {
+		return
+	}
+
+	from = time.Unix(0, 0)
+	to = time.Now().Add(maxBlockTimeOffset)
+	if fromStr != "" {
+		t, errF := strconv.ParseInt(fromStr, 10, 64)
+		if errF != nil || t < 0 {
+			err = fmt.Errorf("invalid from time %q", fromStr)
+			return
+		}
+		from = time.Unix(t, 0)
+	}
+	if toStr != "" {
+		t, errT := strconv.ParseInt(toStr, 10, 64)
+		if errT != nil || t < 0 {
+			err = fmt.Errorf("invalid to time %q", toStr)
+			return
+		}
+		to = time.Unix(t, 0)
+	}
+	if to.Before(from) {
+		err = fmt.Errorf("to time is before from time")
+		return
+	}
+	return from, to, true, nil
+}
+
+// nextCursorHeader is the response header with the cursor of the next page of
+// a listing that may be paged with the "cursor" URL query parameter instead of
+// skipping rows. It is not set on the last page.
+const nextCursorHeader = "X-Next-Cursor"
+
+// pageCursorFromQuery parses the optional "cursor" URL query parameter, which
+// must be a cursor for the given kind of listing. The cursor is nil if the
+// parameter is not set.
+func pageCursorFromQuery(r *http.Request, kind dbtypes.PageCursorKind) (*dbtypes.PageCursor, error) {
+	cursor := r.URL.Query().Get("cursor")
+	if cursor == "" {
+		return nil, nil
+	}
+	return dbtypes.DecodePageCursor(cursor, kind)
+}
+
+// setNextCursor sets the next page cursor header if there is a next page.
+func setNextCursor(w http.ResponseWriter, next *dbtypes.PageCursor) {
+	if next != nil {
+		w.Header().Set(nextCursorHeader, next.Encode())
+	}
+}
+
+// getAddressRewardTickets serves the mainchain tickets with a commitment to the
+// address, i.e. the tickets whose rewards will pay out to the address.
+func (c *appContext) getAddressRewardTickets(w http.ResponseWriter, r *http.Request) {
 	addresses, err := m.GetAddressCtx(r, c.Params)
 	if err != nil || len(addresses) > 1 {
 		http.Error(w, http.StatusText(422), 422)
@@ -1721,7 +3657,7 @@ func (c *appContext) getAddressTransactionsRaw(w http.ResponseWriter, r *http.Re
 	count := int64(m.GetNCtx(r))
 	skip := int64(m.GetMCtx(r))
 	if count <= 0 {
-		count = 10
+		count = 100
 	} else if count > 8000 {
 		count = 8000
 	}
@@ -1729,7 +3665,197 @@ func (c *appContext) getAddressTransactionsRaw(w http.ResponseWriter, r *http.Re
 		skip = 0
 	}
 
-	// TODO: add postgresql powered method
+	after, err := pageCursorFromQuery(r, dbtypes.CursorRewardTickets)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	tickets, next, err := c.DataSource.TicketsByRewardAddress(r.Context(), address, count, skip, after)
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("TicketsByRewardAddress: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		apiLog.Errorf("TicketsByRewardAddress: %v", err)
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+	if tickets == nil {
+		tickets = []*dbtypes.RewardTicket{}
+	}
+	setNextCursor(w, next)
+	writeJSON(w, tickets, m.GetIndentCtx(r))
+}
+
+// getAddressUTXOs serves a page of the unspent outputs paying to the address,
+// with their maturity, in the order given by the "sort" URL query parameter:
+// newest (default), oldest, largest or smallest.
+func (c *appContext) getAddressUTXOs(w http.ResponseWriter, r *http.Request) {
+	addresses, err := m.GetAddressCtx(r, c.Params)
+	if err != nil || len(addresses) > 1 {
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+	address := addresses[0]
+
+	sortBy := r.URL.Query().Get("sort")
+	switch sortBy {
+	case "":
+		sortBy = dbtypes.UTXOSortNewest
+	case dbtypes.UTXOSortNewest, dbtypes.UTXOSortOldest,
+		dbtypes.UTXOSortLargest, dbtypes.UTXOSortSmallest:
+	default:
+		http.Error(w, "invalid sort, must be newest, oldest, largest or smallest",
+			http.StatusBadRequest)
+		return
+	}
+
+	count := int64(m.GetNCtx(r))
+	skip := int64(m.GetMCtx(r))
+	if count <= 0 {
+		count = 100
+	} else if count > 8000 {
+		count = 8000
+	}
+	if skip <= 0 {
+		skip = 0
+	}
+
+	utxos, err := c.DataSource.AddressUTXOs(r.Context(), address, count, skip, sortBy)
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("AddressUTXOs: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		apiLog.Errorf("AddressUTXOs: %v", err)
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+	writeJSON(w, utxos, m.GetIndentCtx(r))
+}
+
+// getAddressCoinbaseBlocks serves the mainchain blocks with coinbase outputs
+// paying to the address, i.e. the blocks mined by the address.
+func (c *appContext) getAddressCoinbaseBlocks(w http.ResponseWriter, r *http.Request) {
+	addresses, err := m.GetAddressCtx(r, c.Params)
+	if err != nil || len(addresses) > 1 {
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+	address := addresses[0]
+
+	count := int64(m.GetNCtx(r))
+	skip := int64(m.GetMCtx(r))
+	if count <= 0 {
+		count = 100
+	} else if count > 8000 {
+		count = 8000
+	}
+	if skip <= 0 {
+		skip = 0
+	}
+
+	after, err := pageCursorFromQuery(r, dbtypes.CursorCoinbase)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	blocks, next, err := c.DataSource.CoinbaseBlocksByAddress(r.Context(), address, count, skip, after)
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("CoinbaseBlocksByAddress: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		apiLog.Errorf("CoinbaseBlocksByAddress: %v", err)
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+	if blocks == nil {
+		blocks = []*dbtypes.CoinbaseBlock{}
+	}
+	setNextCursor(w, next)
+	writeJSON(w, blocks, m.GetIndentCtx(r))
+}
+
+// getAddressStakingPosition serves the DCR locked in unspent tickets with a
+// commitment to the address, and the pending payouts to it from recent votes.
+func (c *appContext) getAddressStakingPosition(w http.ResponseWriter, r *http.Request) {
+	addresses, err := m.GetAddressCtx(r, c.Params)
+	if err != nil || len(addresses) > 1 {
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+	address := addresses[0]
+
+	pos, err := c.DataSource.StakingPosition(r.Context(), address)
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("StakingPosition: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		apiLog.Errorf("StakingPosition: %v", err)
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+	writeJSON(w, pos, m.GetIndentCtx(r))
+}
+
+// getAddressTicketLuck serves the aggregate luck of the voted tickets with a
+// commitment to the address.
+func (c *appContext) getAddressTicketLuck(w http.ResponseWriter, r *http.Request) {
+	addresses, err := m.GetAddressCtx(r, c.Params)
+	if err != nil || len(addresses) > 1 {
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+	address := addresses[0]
+
+	luck, err := c.DataSource.AddressTicketLuck(r.Context(), address)
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("AddressTicketLuck: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		apiLog.Errorf("AddressTicketLuck: %v", err)
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+	writeJSON(w, luck, m.GetIndentCtx(r))
+}
+
+func (c *appContext) getAddressTransactionsRaw(w http.ResponseWriter, r *http.Request) {
+	addresses, err := m.GetAddressCtx(r, c.Params)
+	if err != nil || len(addresses) > 1 {
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+	address := addresses[0]
+
+	count := int64(m.GetNCtx(r))
+	skip := int64(m.GetMCtx(r))
+	if count <= 0 {
+		count = 10
+	} else if count > 8000 {
+		count = 8000
+	}
+	if skip <= 0 {
+		skip = 0
+	}
+
+	// The raw transactions come from dcrd, which cannot filter by time.
+	if _, _, byTime, _ := timeRangeFromQuery(r); byTime {
+		http.Error(w, "from and to are not supported for raw transactions", http.StatusBadRequest)
+		return
+	}
+
+	// TODO: add postgresql powered method
 	txs := c.DataSource.GetAddressTransactionsRawWithSkip(address, int(count), int(skip))
 	if txs == nil {
 		http.Error(w, http.StatusText(422), 422)
@@ -1746,7 +3872,7 @@ func (c *appContext) getAgendaData(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, http.StatusText(422), 422)
 		return
 	}
-	chartDataByTime, err := c.DataSource.AgendaVotes(agendaId, 0)
+	chartDataByTime, err := c.DataSource.AgendaVotes(r.Context(), agendaId, 0)
 	if dbtypes.IsTimeoutErr(err) {
 		apiLog.Errorf("AgendaVotes timeout error %v", err)
 		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
@@ -1757,7 +3883,7 @@ func (c *appContext) getAgendaData(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	chartDataByHeight, err := c.DataSource.AgendaVotes(agendaId, 1)
+	chartDataByHeight, err := c.DataSource.AgendaVotes(r.Context(), agendaId, 1)
 	if dbtypes.IsTimeoutErr(err) {
 		apiLog.Errorf("AgendaVotes timeout error: %v", err)
 		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
@@ -1776,6 +3902,156 @@ func (c *appContext) getAgendaData(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, data, "")
 }
 
+// getAgendaStatus processes a request for the voting progress of an agenda
+// from /agendas/{agendaId}/status.
+func (c *appContext) getAgendaStatus(w http.ResponseWriter, r *http.Request) {
+	agendaId := m.GetAgendaIdCtx(r)
+	if agendaId == "" {
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+	status, err := c.DataSource.AgendaStatus(r.Context(), agendaId)
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("AgendaStatus timeout error: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return
+	}
+	if err == sql.ErrNoRows {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		apiLog.Errorf("AgendaStatus error: %v", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError),
+			http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, status, m.GetIndentCtx(r))
+}
+
+// getAgendaTicketTypes processes a request for the vote choices cast on an
+// agenda by ticket type from /agendas/{agendaId}/ticket-types.
+func (c *appContext) getAgendaTicketTypes(w http.ResponseWriter, r *http.Request) {
+	agendaId := m.GetAgendaIdCtx(r)
+	if agendaId == "" {
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+	votes, err := c.DataSource.AgendaVotesByTicketType(r.Context(), agendaId)
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("AgendaVotesByTicketType timeout error: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return
+	}
+	if err == sql.ErrNoRows {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		apiLog.Errorf("AgendaVotesByTicketType error: %v", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError),
+			http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, votes, m.GetIndentCtx(r))
+}
+
+// getAgendaTimeline processes a request for the voting timeline of an agenda
+// from /agendas/{agendaId}/timeline. The "interval" URL query sets the number
+// of blocks in each span of the timeline, and the "format" URL query may be
+// "json" (the default) or "csv".
+func (c *appContext) getAgendaTimeline(w http.ResponseWriter, r *http.Request) {
+	agendaId := m.GetAgendaIdCtx(r)
+	if agendaId == "" {
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+	query := r.URL.Query()
+	var interval int64
+	if intervalStr := query.Get("interval"); intervalStr != "" {
+		var err error
+		interval, err = strconv.ParseInt(intervalStr, 10, 64)
+		if err != nil || interval < 1 {
+			http.Error(w, "invalid interval", http.StatusBadRequest)
+			return
+		}
+	}
+	var asCSV bool
+	switch query.Get("format") {
+	case "", "json":
+	case "csv":
+		asCSV = true
+	default:
+		http.Error(w, "invalid format", http.StatusBadRequest)
+		return
+	}
+
+	timeline, err := c.DataSource.AgendaVoteTimeline(r.Context(), agendaId, interval)
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("AgendaVoteTimeline timeout error: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return
+	}
+	if err == sql.ErrNoRows {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		apiLog.Errorf("AgendaVoteTimeline error: %v", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError),
+			http.StatusInternalServerError)
+		return
+	}
+
+	if !asCSV {
+		writeJSON(w, timeline, m.GetIndentCtx(r))
+		return
+	}
+
+	rows := make([][]string, 0, len(timeline.Intervals)+1)
+	rows = append(rows, []string{"start_height", "end_height", "start_time",
+		"end_time", "yes", "abstain", "no", "total", "cum_yes", "cum_abstain",
+		"cum_no", "cum_total", "yes_percent", "abstain_percent", "no_percent",
+		"quorum_progress"})
+	u32 := func(n uint32) string { return strconv.FormatUint(uint64(n), 10) }
+	pct := func(f float64) string { return strconv.FormatFloat(f, 'f', 2, 64) }
+	for _, avi := range timeline.Intervals {
+		rows = append(rows, []string{
+			strconv.FormatInt(avi.StartHeight, 10),
+			strconv.FormatInt(avi.EndHeight, 10),
+			strconv.FormatInt(avi.StartTime, 10),
+			strconv.FormatInt(avi.EndTime, 10),
+			u32(avi.Yes), u32(avi.Abstain), u32(avi.No), u32(avi.Total),
+			u32(avi.CumYes), u32(avi.CumAbstain), u32(avi.CumNo), u32(avi.CumTotal),
+			pct(avi.YesPercent), pct(avi.AbstainPercent), pct(avi.NoPercent),
+			pct(avi.QuorumProgress),
+		})
+	}
+	filename := fmt.Sprintf("agenda-timeline-%s-%d.csv", agendaId, timeline.Interval)
+	writeCSV(w, rows, filename, false)
+}
+
+// getUpgradeProgress processes a request for the adoption of the latest block
+// and vote versions by miners and stakeholders from /stake/upgrade.
+func (c *appContext) getUpgradeProgress(w http.ResponseWriter, r *http.Request) {
+	progress, err := c.DataSource.UpgradeProgress(r.Context())
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("UpgradeProgress timeout error: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		apiLog.Errorf("UpgradeProgress error: %v", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError),
+			http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, progress, m.GetIndentCtx(r))
+}
+
 func (c *appContext) getExchanges(w http.ResponseWriter, r *http.Request) {
 	if c.xcBot == nil {
 		http.Error(w, "Exchange monitoring disabled.", http.StatusServiceUnavailable)
@@ -1816,6 +4092,204 @@ func (c *appContext) getCurrencyCodes(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, codes, m.GetIndentCtx(r))
 }
 
+const (
+	// rateCacheTime is how long exchange rates are cached.
+	rateCacheTime = time.Minute
+	// rateMaxAge is how long a cached exchange rate is served, marked stale,
+	// when no rate provider has a rate.
+	rateMaxAge = 24 * time.Hour
+	// dailyPriceMaxAge is how far back the recorded daily prices are searched
+	// for an exchange rate.
+	dailyPriceMaxAge = 7 * 24 * time.Hour
+)
+
+// dailyPriceRates is an exchanges.RateProvider serving the closing price of
+// the most recent day in the recorded daily price history, as a stale rate.
+type dailyPriceRates struct {
+	source chainstore.ChainStore
+}
+
+// Name is the name of the daily price history as a rate provider.
+func (d *dailyPriceRates) Name() string {
+	return "daily_prices"
+}
+
+// Rate gets the most recent recorded daily price in the currency.
+func (d *dailyPriceRates) Rate(code string) (*exchanges.Rate, error) {
+	prices, err := d.source.DailyPrices(context.Background(), code,
+		time.Now().Add(-dailyPriceMaxAge))
+	if err != nil {
+		return nil, err
+	}
+	if len(prices) == 0 {
+		return nil, fmt.Errorf("no recent daily %s prices", code)
+	}
+	last := prices[len(prices)-1]
+	return &exchanges.Rate{
+		Currency: code,
+		Price:    last.Close,
+		Source:   d.Name(),
+		Updated:  last.Day.UNIX(),
+		Stale:    true,
+	}, nil
+}
+
+// fiatRate gets the exchange rate requested with the "fiat" URL query
+// parameter, which is a currency code, or true for the default currency. A nil
+// rate and error are returned if no conversion was requested, and a non-nil
+// status is the HTTP error status for the error.
+func (c *appContext) fiatRate(r *http.Request) (*exchanges.Rate, int, error) {
+	code := r.URL.Query().Get("fiat")
+	if code == "" {
+		return nil, 0, nil
+	}
+	if c.rates == nil {
+		return nil, http.StatusServiceUnavailable, fmt.Errorf("exchange monitoring disabled")
+	}
+	if b, err := strconv.ParseBool(code); err == nil {
+		if !b {
+			return nil, 0, nil
+		}
+		code = c.xcBot.BtcIndex
+	}
+	rate, err := c.rates.Rate(strings.ToUpper(code))
+	if err != nil {
+		apiLog.Debugf("No %s exchange rate: %v", code, err)
+		return nil, http.StatusServiceUnavailable, fmt.Errorf("no exchange rate available")
+	}
+	return rate, 0, nil
+}
+
+// fiatValues converts the DCR amounts to fiat at the rate.
+func fiatValues(rate *exchanges.Rate, dcr map[string]float64) *apitypes.FiatValues {
+	values := make(map[string]float64, len(dcr))
+	for k, v := range dcr {
+		values[k] = rate.Convert(v)
+	}
+	return &apitypes.FiatValues{
+		Currency: rate.Currency,
+		Rate:     rate.Price,
+		Stale:    rate.Stale,
+		Values:   values,
+	}
+}
+
+// getExchangeRate serves the price of DCR in the currency given by the "code"
+// URL query parameter, or the default currency, from the monitored exchanges
+// or, if they are unavailable, the recorded daily prices.
+func (c *appContext) getExchangeRate(w http.ResponseWriter, r *http.Request) {
+	if c.rates == nil {
+		http.Error(w, "Exchange monitoring disabled.", http.StatusServiceUnavailable)
+		return
+	}
+	code := r.URL.Query().Get("code")
+	if code == "" {
+		code = c.xcBot.BtcIndex
+	}
+	rate, err := c.rates.Rate(strings.ToUpper(code))
+	if err != nil {
+		apiLog.Debugf("No %s exchange rate: %v", code, err)
+		http.Error(w, fmt.Sprintf("No exchange rate for code %s", code), http.StatusNotFound)
+		return
+	}
+	writeJSON(w, rate, m.GetIndentCtx(r))
+}
+
+// getDailyPrices returns the daily DCR price history recorded from the
+// ExchangeBot. The currency defaults to the ExchangeBot's index, and may be
+// specified with the "code" URL query. The "days" URL query limits the history
+// to the most recent days.
+func (c *appContext) getDailyPrices(w http.ResponseWriter, r *http.Request) {
+	if c.xcBot == nil {
+		http.Error(w, "Exchange monitoring disabled.", http.StatusServiceUnavailable)
+		return
+	}
+
+	code := r.URL.Query().Get("code")
+	if code == "" {
+		code = c.xcBot.BtcIndex
+	}
+	var since time.Time
+	if daysStr := r.URL.Query().Get("days"); daysStr != "" {
+		days, err := strconv.Atoi(daysStr)
+		if err != nil || days < 1 {
+			http.Error(w, "invalid days", http.StatusBadRequest)
+			return
+		}
+		since = time.Now().AddDate(0, 0, 1-days)
+	}
+
+	prices, err := c.DataSource.DailyPrices(r.Context(), code, since)
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("DailyPrices: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		apiLog.Errorf("DailyPrices: %v", err)
+		http.Error(w, http.StatusText(http.StatusUnprocessableEntity),
+			http.StatusUnprocessableEntity)
+		return
+	}
+	if prices == nil {
+		prices = []*dbtypes.DailyPrice{}
+	}
+	writeJSON(w, prices, m.GetIndentCtx(r))
+}
+
+// getUTXODistribution serves the most recently recorded distribution of the
+// unspent output values among value buckets.
+func (c *appContext) getUTXODistribution(w http.ResponseWriter, r *http.Request) {
+	dist, err := c.DataSource.UTXODistribution(r.Context())
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("UTXODistribution: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return
+	}
+	if err == sql.ErrNoRows {
+		http.Error(w, "No UTXO distribution recorded yet.", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		apiLog.Errorf("UTXODistribution: %v", err)
+		http.Error(w, http.StatusText(http.StatusUnprocessableEntity),
+			http.StatusUnprocessableEntity)
+		return
+	}
+	writeJSON(w, dist, m.GetIndentCtx(r))
+}
+
+// getUTXODistributionHistory serves the daily history of the UTXO value
+// distribution, oldest first, optionally limited to the last days.
+func (c *appContext) getUTXODistributionHistory(w http.ResponseWriter, r *http.Request) {
+	var since time.Time
+	if daysStr := r.URL.Query().Get("days"); daysStr != "" {
+		days, err := strconv.Atoi(daysStr)
+		if err != nil || days < 1 {
+			http.Error(w, "invalid days", http.StatusBadRequest)
+			return
+		}
+		since = time.Now().AddDate(0, 0, 1-days)
+	}
+
+	dists, err := c.DataSource.UTXODistributions(r.Context(), since)
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("UTXODistributions: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		apiLog.Errorf("UTXODistributions: %v", err)
+		http.Error(w, http.StatusText(http.StatusUnprocessableEntity),
+			http.StatusUnprocessableEntity)
+		return
+	}
+	if dists == nil {
+		dists = []*dbtypes.UTXODistribution{}
+	}
+	writeJSON(w, dists, m.GetIndentCtx(r))
+}
+
 // getAgendasData returns high level agendas details that includes Name,
 // Description, Vote Version, VotingDone height, Activated, HardForked,
 // StartTime and ExpireTime.
@@ -1865,6 +4339,34 @@ func (c *appContext) BlockHashPathAndIndexCtx(next http.Handler) http.Handler {
 	})
 }
 
+// BlockTimeIndexCtx embeds the height of the mainchain block nearest the time
+// embedded by m.BlockTimePathCtx into the request context as the block index.
+func (c *appContext) BlockTimeIndexCtx(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bt, ok := m.GetBlockTimeCtx(r)
+		if !ok {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+		height, err := c.DataSource.BlockHeightAtTime(r.Context(), bt.Time, bt.After)
+		if dbtypes.IsTimeoutErr(err) {
+			apiLog.Errorf("BlockHeightAtTime: %v", err)
+			http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+			return
+		}
+		if err != nil {
+			apiLog.Errorf("BlockHeightAtTime: %v", err)
+			http.Error(w, http.StatusText(422), 422)
+			return
+		}
+		if height < 0 {
+			http.Error(w, "no block at the requested time", http.StatusNotFound)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(m.BlockIndexCtx(r, height)))
+	})
+}
+
 func (c *appContext) BlockIndexLatestCtx(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ctx := m.BlockIndexLatestCtx(r, c.DataSource)
@@ -1880,7 +4382,7 @@ func (c *appContext) getBlockHashCtx(r *http.Request) (string, error) {
 	hash, err := m.GetBlockHashCtx(r)
 	if err != nil {
 		idx := int64(m.GetBlockIndexCtx(r))
-		hash, err = c.DataSource.GetBlockHash(idx)
+		hash, err = c.DataSource.GetBlockHash(r.Context(), idx)
 		if err != nil {
 			apiLog.Errorf("Unable to GetBlockHash: %v", err)
 			return "", err
@@ -0,0 +1,734 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/chaincfg/v2"
+	apitypes "github.com/decred/dcrdata/api/types/v5"
+	"github.com/decred/dcrdata/db/dbtypes/v2"
+	exptypes "github.com/decred/dcrdata/explorer/types/v2"
+	"github.com/decred/dcrdata/mempool/v5"
+	m "github.com/decred/dcrdata/middleware/v3"
+	"github.com/decred/dcrdata/v5/chainstore"
+	"github.com/decred/dcrdata/v5/dailyexport"
+	"github.com/go-chi/chi"
+)
+
+// storeStub satisfies chainstore.ChainStore, but will panic with a nil pointer
+// dereference for methods we do not explicitly define here.
+type storeStub struct {
+	chainstore.ChainStore
+	dbStats   *apitypes.DBStats
+	revocable *dbtypes.RevocableTickets
+	next      *dbtypes.PageCursor
+	after     *dbtypes.PageCursor // the cursor of the last request
+	height    int64               // the height of the block at any time
+	addrTxs   *apitypes.Address
+	txnType   dbtypes.AddrTxnViewType // the view of the last request
+	before    int64                   // the before height of the last request
+	limit     int64                   // the limit of the last request
+	from, to  int64                   // the block range of the last request
+	sigType   dbtypes.VinSigType      // the signature script type of the last request
+	interval  int64                   // the timeline interval of the last request
+	timeline  *apitypes.AgendaVoteTimeline
+	poolInfo  *apitypes.TicketPoolInfo
+	err       error
+}
+
+func (s *storeStub) GetPoolInfoByHash(_ string) *apitypes.TicketPoolInfo {
+	return s.poolInfo
+}
+
+func (s *storeStub) TicketDemandHistory(_ context.Context) ([]dbtypes.BlockTicketDemand, error) {
+	history := make([]dbtypes.BlockTicketDemand, s.height+1)
+	for i := range history {
+		history[i] = dbtypes.BlockTicketDemand{Height: int64(i), FreshStake: 5, SBits: 2e8}
+	}
+	return history, s.err
+}
+
+func (s *storeStub) GetHeight() (int64, error) {
+	return s.height, s.err
+}
+
+// BlockTimesByHeights gives the block at each height a time of 1000 plus 300
+// seconds per block.
+func (s *storeStub) BlockTimesByHeights(_ context.Context, heights []int64) (map[int64]int64, error) {
+	times := make(map[int64]int64, len(heights))
+	for _, h := range heights {
+		if h <= s.height {
+			times[h] = 1000 + 300*h
+		}
+	}
+	return times, s.err
+}
+
+func (s *storeStub) AgendaVoteTimeline(_ context.Context, _ string, interval int64) (*apitypes.AgendaVoteTimeline, error) {
+	s.interval = interval
+	return s.timeline, s.err
+}
+
+func (s *storeStub) VinsBySigType(_ context.Context, from, to int64, sigType dbtypes.VinSigType) ([]*dbtypes.SigTypeVin, error) {
+	s.from, s.to, s.sigType = from, to, sigType
+	return nil, s.err
+}
+
+func (s *storeStub) BlockHeight(_ context.Context, _ string) (int64, error) {
+	return s.height, s.err
+}
+
+func (s *storeStub) BlockSummariesBefore(_ context.Context, before, limit int64) ([]*apitypes.BlockDataBasic, error) {
+	s.before, s.limit = before, limit
+	return nil, s.err
+}
+
+func (s *storeStub) AddressTransactionDetails(_ context.Context, _ string, _, _ int64,
+	txnType dbtypes.AddrTxnViewType) (*apitypes.Address, *dbtypes.PageCursor, error) {
+	s.txnType = txnType
+	return s.addrTxs, s.next, s.err
+}
+
+func (s *storeStub) BlockHeightAtTime(_ context.Context, _ int64, _ bool) (int64, error) {
+	return s.height, s.err
+}
+
+func (s *storeStub) DBStats(_ context.Context) (*apitypes.DBStats, error) {
+	return s.dbStats, s.err
+}
+
+func (s *storeStub) RevocableTickets(_ context.Context, _ string, _, _ int64,
+	after *dbtypes.PageCursor) (*dbtypes.RevocableTickets, *dbtypes.PageCursor, error) {
+	s.after = after
+	return s.revocable, s.next, s.err
+}
+
+func TestDBStatsHandler(t *testing.T) {
+	stats := &apitypes.DBStats{
+		TotalBytes: 8192,
+		Tables: []*apitypes.TableStats{
+			{Name: "blocks", Rows: 10, TableBytes: 4096, TotalBytes: 8192},
+		},
+	}
+	tests := []struct {
+		name     string
+		store    *storeStub
+		wantCode int
+	}{
+		{"ok", &storeStub{dbStats: stats}, http.StatusOK},
+		{"timeout", &storeStub{err: errors.New(dbtypes.TimeoutPrefix)},
+			http.StatusServiceUnavailable},
+		{"error", &storeStub{err: errors.New("cockroach")}, 422},
+	}
+	for _, tt := range tests {
+		c := &appContext{DataSource: tt.store}
+		rr := httptest.NewRecorder()
+		c.dbStats(rr, httptest.NewRequest("GET", "/db/stats", nil))
+		if rr.Code != tt.wantCode {
+			t.Errorf("%s: got status %d, wanted %d", tt.name, rr.Code, tt.wantCode)
+			continue
+		}
+		if tt.wantCode != http.StatusOK {
+			continue
+		}
+		var got apitypes.DBStats
+		if err := json.NewDecoder(rr.Body).Decode(&got); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if got.TotalBytes != stats.TotalBytes || len(got.Tables) != 1 ||
+			got.Tables[0].Name != "blocks" {
+			t.Errorf("%s: unexpected stats %+v", tt.name, got)
+		}
+	}
+}
+
+func TestRevocableTicketsCursor(t *testing.T) {
+	next := &dbtypes.PageCursor{Kind: dbtypes.CursorRevocable, Height: 4000, TxIndex: 3}
+	store := &storeStub{
+		revocable: &dbtypes.RevocableTickets{Tickets: []*dbtypes.RevocableTicket{}},
+		next:      next,
+	}
+	c := &appContext{DataSource: store}
+
+	// The first page has the cursor of the next page in the header.
+	rr := httptest.NewRecorder()
+	c.getRevocableTickets(rr, httptest.NewRequest("GET", "/stake/revocable", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("got status %d, wanted %d", rr.Code, http.StatusOK)
+	}
+	cursor := rr.Header().Get(nextCursorHeader)
+	if cursor != next.Encode() {
+		t.Fatalf("got next cursor %q, wanted %q", cursor, next.Encode())
+	}
+	if store.after != nil {
+		t.Errorf("first page requested after cursor %+v", store.after)
+	}
+
+	// The next page is requested after the cursor, and is the last page.
+	store.next = nil
+	rr = httptest.NewRecorder()
+	c.getRevocableTickets(rr, httptest.NewRequest("GET", "/stake/revocable?cursor="+cursor, nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("got status %d, wanted %d", rr.Code, http.StatusOK)
+	}
+	if store.after == nil || *store.after != *next {
+		t.Errorf("next page requested after cursor %+v, wanted %+v", store.after, next)
+	}
+	if h := rr.Header().Get(nextCursorHeader); h != "" {
+		t.Errorf("last page has next cursor %q", h)
+	}
+
+	// A cursor for another listing is rejected.
+	other := &dbtypes.PageCursor{Kind: dbtypes.CursorCoinbase, Height: 4000}
+	rr = httptest.NewRecorder()
+	c.getRevocableTickets(rr, httptest.NewRequest("GET", "/stake/revocable?cursor="+other.Encode(), nil))
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("got status %d for a coinbase blocks cursor, wanted %d", rr.Code, http.StatusBadRequest)
+	}
+}
+
+func TestBlockTimeIndexCtx(t *testing.T) {
+	tests := []struct {
+		name       string
+		store      *storeStub
+		wantCode   int
+		wantHeight int64
+	}{
+		{"found", &storeStub{height: 420000}, http.StatusOK, 420000},
+		{"no block", &storeStub{height: -1}, http.StatusNotFound, 0},
+		{"timeout", &storeStub{err: errors.New(dbtypes.TimeoutPrefix)},
+			http.StatusServiceUnavailable, 0},
+	}
+	for _, tt := range tests {
+		c := &appContext{DataSource: tt.store}
+		var height int64
+		handler := m.BlockTimePathCtx(c.BlockTimeIndexCtx(http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				height = int64(m.GetBlockIndexCtx(r))
+			})))
+		mux := chi.NewRouter()
+		mux.Get("/block/at/{timestamp}", handler.ServeHTTP)
+
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, httptest.NewRequest("GET", "/block/at/1576000000", nil))
+		if rr.Code != tt.wantCode {
+			t.Errorf("%s: got status %d, wanted %d", tt.name, rr.Code, tt.wantCode)
+			continue
+		}
+		if height != tt.wantHeight {
+			t.Errorf("%s: got height %d, wanted %d", tt.name, height, tt.wantHeight)
+		}
+	}
+}
+
+func TestTicketPoolInfoByHash(t *testing.T) {
+	const hash = "000000000000000011a7e8eb9d2b6a1cb4a8c8d1b3c3b4a3b3f4b7f2b5c6d7e8"
+	tests := []struct {
+		name     string
+		poolInfo *apitypes.TicketPoolInfo
+		wantCode int
+	}{
+		{"found", &apitypes.TicketPoolInfo{Height: 42, Size: 40960}, http.StatusOK},
+		{"unknown block", nil, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		c := &appContext{DataSource: &storeStub{poolInfo: tt.poolInfo}}
+		mux := chi.NewRouter()
+		mux.With(m.BlockIndexOrHashPathCtx).Get("/stake/pool/b/{idxorhash}", c.getTicketPoolInfo)
+
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, httptest.NewRequest("GET", "/stake/pool/b/"+hash, nil))
+		if rr.Code != tt.wantCode {
+			t.Errorf("%s: got status %d, wanted %d", tt.name, rr.Code, tt.wantCode)
+		}
+	}
+}
+
+func TestBlockListBefore(t *testing.T) {
+	const hash = "000000000000000011a7e8eb9d2b6a1cb4a8c8d1b3c3b4a3b3f4b7f2b5c6d7e8"
+	tests := []struct {
+		query      string
+		err        error
+		wantCode   int
+		wantBefore int64
+		wantLimit  int64
+	}{
+		{"", nil, http.StatusOK, 101, 20},
+		{"?before=50&limit=5", nil, http.StatusOK, 50, 5},
+		{"?limit=5000", nil, http.StatusOK, 101, maxBlockRangeCount},
+		{"?before=" + hash, nil, http.StatusOK, 42, 20},
+		{"?before=" + hash, sql.ErrNoRows, http.StatusNotFound, 0, 0},
+		{"?before=deadbeef", nil, http.StatusBadRequest, 0, 0},
+		{"?before=-1", nil, http.StatusBadRequest, 0, 0},
+		{"?limit=0", nil, http.StatusBadRequest, 0, 0},
+	}
+	for _, tt := range tests {
+		store := &storeStub{height: 42, err: tt.err}
+		c := &appContext{
+			DataSource: store,
+			Status:     apitypes.NewStatus(100, 8, APIVersion, "", "mainnet"),
+		}
+		rr := httptest.NewRecorder()
+		c.getBlockList(rr, httptest.NewRequest("GET", "/block/list"+tt.query, nil))
+		if rr.Code != tt.wantCode {
+			t.Errorf("%q: got status %d, wanted %d", tt.query, rr.Code, tt.wantCode)
+			continue
+		}
+		if store.before != tt.wantBefore || store.limit != tt.wantLimit {
+			t.Errorf("%q: got before %d and limit %d, wanted %d and %d", tt.query,
+				store.before, store.limit, tt.wantBefore, tt.wantLimit)
+		}
+		if tt.wantCode == http.StatusOK && rr.Body.String() != "[]\n" {
+			t.Errorf("%q: got body %q", tt.query, rr.Body.String())
+		}
+	}
+}
+
+func TestBlockRangeVins(t *testing.T) {
+	tests := []struct {
+		path     string
+		wantCode int
+		wantFrom int64
+		wantTo   int64
+		wantType dbtypes.VinSigType
+	}{
+		{"/block/range/10/20/vins?sigtype=multisig", http.StatusOK, 10, 20, dbtypes.VinSigMultiSig},
+		{"/block/range/20/10/vins?sigtype=stakesubmission", http.StatusOK, 10, 20, dbtypes.VinSigStakeSubmission},
+		{"/block/range/10/20/vins", http.StatusBadRequest, 0, 0, 0},
+		{"/block/range/10/20/vins?sigtype=p2sh", http.StatusBadRequest, 0, 0, 0},
+		{"/block/range/10/200/vins?sigtype=multisig", http.StatusBadRequest, 0, 0, 0},
+	}
+	for _, tt := range tests {
+		store := &storeStub{}
+		c := &appContext{
+			DataSource: store,
+			Status:     apitypes.NewStatus(100, 8, APIVersion, "", "mainnet"),
+		}
+		mux := chi.NewRouter()
+		mux.With(m.BlockIndex0PathCtx, m.BlockIndexPathCtx).
+			Get("/block/range/{idx0}/{idx}/vins", c.getBlockRangeVins)
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, httptest.NewRequest("GET", tt.path, nil))
+		if rr.Code != tt.wantCode {
+			t.Errorf("%s: got status %d, wanted %d", tt.path, rr.Code, tt.wantCode)
+			continue
+		}
+		if store.from != tt.wantFrom || store.to != tt.wantTo || store.sigType != tt.wantType {
+			t.Errorf("%s: got range [%d, %d] of type %v", tt.path, store.from,
+				store.to, store.sigType)
+		}
+		if tt.wantCode == http.StatusOK && rr.Body.String() != "[]\n" {
+			t.Errorf("%s: got body %q", tt.path, rr.Body.String())
+		}
+	}
+}
+
+func TestAgendaTimeline(t *testing.T) {
+	timeline := &apitypes.AgendaVoteTimeline{
+		ID:       "fixlnseqlocks",
+		Interval: 288,
+		Intervals: []*apitypes.AgendaVoteInterval{{
+			StartHeight: 1000, EndHeight: 1287, StartTime: 1500000000, EndTime: 1500080000,
+			Yes: 3, Abstain: 1, No: 1, Total: 5, CumYes: 3, CumAbstain: 1, CumNo: 1,
+			CumTotal: 5, YesPercent: 75, AbstainPercent: 20, NoPercent: 25,
+			QuorumProgress: 0.0992,
+		}},
+	}
+	tests := []struct {
+		path         string
+		wantCode     int
+		wantInterval int64
+		wantType     string
+	}{
+		{"/agendas/fixlnseqlocks/timeline", http.StatusOK, 0, "application/json; charset=utf-8"},
+		{"/agendas/fixlnseqlocks/timeline?interval=288&format=csv", http.StatusOK, 288, "text/csv"},
+		{"/agendas/fixlnseqlocks/timeline?interval=0", http.StatusBadRequest, 0, ""},
+		{"/agendas/fixlnseqlocks/timeline?format=xml", http.StatusBadRequest, 0, ""},
+	}
+	for _, tt := range tests {
+		store := &storeStub{timeline: timeline}
+		c := &appContext{DataSource: store}
+		mux := chi.NewRouter()
+		mux.With(m.AgendaIdCtx).Get("/agendas/{agendaId}/timeline", c.getAgendaTimeline)
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, httptest.NewRequest("GET", tt.path, nil))
+		if rr.Code != tt.wantCode {
+			t.Errorf("%s: got status %d, wanted %d", tt.path, rr.Code, tt.wantCode)
+			continue
+		}
+		if tt.wantCode != http.StatusOK {
+			continue
+		}
+		if store.interval != tt.wantInterval {
+			t.Errorf("%s: got interval %d, wanted %d", tt.path, store.interval, tt.wantInterval)
+		}
+		if ct := rr.Header().Get("Content-Type"); ct != tt.wantType {
+			t.Errorf("%s: got content type %q, wanted %q", tt.path, ct, tt.wantType)
+		}
+	}
+
+	store := &storeStub{timeline: timeline}
+	c := &appContext{DataSource: store}
+	mux := chi.NewRouter()
+	mux.With(m.AgendaIdCtx).Get("/agendas/{agendaId}/timeline", c.getAgendaTimeline)
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest("GET", "/agendas/fixlnseqlocks/timeline?format=csv", nil))
+	wantCSV := "start_height,end_height,start_time,end_time,yes,abstain,no,total," +
+		"cum_yes,cum_abstain,cum_no,cum_total,yes_percent,abstain_percent,no_percent,quorum_progress\n" +
+		"1000,1287,1500000000,1500080000,3,1,1,5,3,1,1,5,75.00,20.00,25.00,0.10\n"
+	if rr.Body.String() != wantCSV {
+		t.Errorf("got CSV %q, wanted %q", rr.Body.String(), wantCSV)
+	}
+
+	store.err = sql.ErrNoRows
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest("GET", "/agendas/unknown/timeline", nil))
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("got status %d for an unknown agenda, wanted 404", rr.Code)
+	}
+}
+
+func TestTicketPoolSimulation(t *testing.T) {
+	tests := []struct {
+		path       string
+		wantCode   int
+		wantPoints int
+	}{
+		{"/stake/simulate", http.StatusOK, 7},
+		{"/stake/simulate?window=500&poolsize=4096&demand=value", http.StatusOK, 2},
+		{"/stake/simulate?window=0", http.StatusBadRequest, 0},
+		{"/stake/simulate?poolsize=70000", http.StatusBadRequest, 0},
+		{"/stake/simulate?demand=votes", http.StatusBadRequest, 0},
+	}
+	for _, tt := range tests {
+		c := &appContext{
+			DataSource: &storeStub{height: 999},
+			Params:     chaincfg.MainNetParams(),
+		}
+		rr := httptest.NewRecorder()
+		c.getTicketPoolSimulation(rr, httptest.NewRequest("GET", tt.path, nil))
+		if rr.Code != tt.wantCode {
+			t.Errorf("%s: got status %d, wanted %d", tt.path, rr.Code, tt.wantCode)
+			continue
+		}
+		if tt.wantCode != http.StatusOK {
+			continue
+		}
+		var res struct {
+			Points []json.RawMessage `json:"points"`
+		}
+		if err := json.Unmarshal(rr.Body.Bytes(), &res); err != nil {
+			t.Fatalf("%s: %v", tt.path, err)
+		}
+		if len(res.Points) != tt.wantPoints {
+			t.Errorf("%s: got %d points, wanted %d", tt.path, len(res.Points), tt.wantPoints)
+		}
+	}
+}
+
+func TestSubsidySchedule(t *testing.T) {
+	params := chaincfg.MainNetParams()
+	c := &appContext{
+		DataSource: &storeStub{height: 20000},
+		Params:     params,
+	}
+	rr := httptest.NewRecorder()
+	c.getSubsidySchedule(rr, httptest.NewRequest("GET", "/supply/reductions", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("got status %d", rr.Code)
+	}
+	var schedule apitypes.SubsidySchedule
+	if err := json.Unmarshal(rr.Body.Bytes(), &schedule); err != nil {
+		t.Fatal(err)
+	}
+	if schedule.Height != 20000 || schedule.AvgBlockTime != 300 ||
+		schedule.ReductionInterval != 6144 {
+		t.Errorf("unexpected schedule %+v", schedule)
+	}
+	if len(schedule.Reductions) != 3 {
+		t.Fatalf("got %d past reductions, wanted 3", len(schedule.Reductions))
+	}
+	for i, sr := range schedule.Reductions {
+		if sr.Number != int64(i+1) || sr.Height != 6144*int64(i+1) ||
+			sr.Time != 1000+300*sr.Height || sr.Estimated {
+			t.Errorf("unexpected past reduction %+v", *sr)
+		}
+	}
+	// Each reduction multiplies the subsidy by 100/101, to the atom.
+	first, second := schedule.Reductions[0], schedule.Reductions[1]
+	if want := first.Total * 100 / 101; second.Total < want-3 || second.Total > want+3 {
+		t.Errorf("got totals %d and %d", first.Total, second.Total)
+	}
+
+	next := schedule.Next
+	if next.Number != 4 || next.Height != 24576 || !next.Estimated ||
+		schedule.BlocksRemaining != 4576 || schedule.SecondsRemaining != 4576*300 ||
+		next.Time != 1000+300*24576 {
+		t.Errorf("unexpected next reduction %+v in %d blocks", *next,
+			schedule.BlocksRemaining)
+	}
+}
+
+func TestAddressTransactionsView(t *testing.T) {
+	store := &storeStub{addrTxs: &apitypes.Address{}}
+	c := &appContext{DataSource: store, Params: chaincfg.MainNetParams()}
+	mux := chi.NewRouter()
+	mux.With(m.AddressPathCtxN(1)).Get("/address/{address}", c.getAddressTransactions)
+
+	const path = "/address/Dsi8hhDzr3SvcGcv4NEGvRqFkwZ2ncRhukk"
+	tests := []struct {
+		query    string
+		wantCode int
+		want     dbtypes.AddrTxnViewType
+	}{
+		{"", http.StatusOK, dbtypes.AddrTxnAll},
+		{"?txntype=merged", http.StatusOK, dbtypes.AddrMergedTxn},
+		{"?txntype=merged_credit", http.StatusOK, dbtypes.AddrMergedTxnCredit},
+		{"?txntype=debit", http.StatusOK, dbtypes.AddrTxnDebit},
+		{"?txntype=mixed", http.StatusBadRequest, dbtypes.AddrTxnUnknown},
+		{"?txntype=merged&from=1576000000", http.StatusBadRequest, dbtypes.AddrTxnUnknown},
+	}
+	for _, tt := range tests {
+		store.txnType = dbtypes.AddrTxnUnknown
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, httptest.NewRequest("GET", path+tt.query, nil))
+		if rr.Code != tt.wantCode {
+			t.Errorf("%q: got status %d, wanted %d", tt.query, rr.Code, tt.wantCode)
+			continue
+		}
+		if store.txnType != tt.want {
+			t.Errorf("%q: got view %v, wanted %v", tt.query, store.txnType, tt.want)
+		}
+	}
+}
+
+func TestMempoolGraph(t *testing.T) {
+	const (
+		parent = "1111111111111111111111111111111111111111111111111111111111111111"
+		child  = "2222222222222222222222222222222222222222222222222222222222222222"
+		lone   = "3333333333333333333333333333333333333333333333333333333333333333"
+	)
+	graph := mempool.NewTxGraph()
+	graph.Reset([]exptypes.MempoolTx{
+		{TxID: parent, Type: "Regular", Size: 250, Fees: 0.0001},
+		{TxID: child, Type: "Regular", Size: 250, Fees: 0.0009,
+			Vin: []exptypes.MempoolInput{{TxId: parent, Outdex: 1}}},
+		{TxID: lone, Type: "Ticket", Size: 300, Fees: 0.003},
+	}, 1000, "abcd")
+	c := &appContext{mempoolGraph: graph}
+	mux := chi.NewRouter()
+	mux.Get("/mempool/graph", c.getMempoolGraph)
+	mux.With(m.TransactionHashCtx).Get("/mempool/graph/{txid}", c.getMempoolTxPackages)
+
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest("GET", "/mempool/graph", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("got status %d", rr.Code)
+	}
+	var txGraph apitypes.MempoolTxGraph
+	if err := json.Unmarshal(rr.Body.Bytes(), &txGraph); err != nil {
+		t.Fatal(err)
+	}
+	// The unrelated transaction is not listed, and the child's package is
+	// listed after its parent's lower fee rate.
+	if txGraph.Height != 1000 || txGraph.NumTransactions != 3 || len(txGraph.Transactions) != 2 {
+		t.Fatalf("unexpected graph %+v", txGraph)
+	}
+	if txGraph.Transactions[0].TxID != parent || txGraph.Transactions[1].TxID != child {
+		t.Errorf("got transactions %s and %s", txGraph.Transactions[0].TxID,
+			txGraph.Transactions[1].TxID)
+	}
+
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest("GET", "/mempool/graph/"+child, nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("got status %d", rr.Code)
+	}
+	var pkgs apitypes.MempoolTxPackages
+	if err := json.Unmarshal(rr.Body.Bytes(), &pkgs); err != nil {
+		t.Fatal(err)
+	}
+	anc := pkgs.Ancestors
+	if anc.Count != 2 || anc.Size != 500 || anc.Fees != 0.001 || anc.FeeRate != 0.002 {
+		t.Errorf("unexpected ancestor package %+v", anc)
+	}
+	if len(pkgs.Parents) != 1 || pkgs.Parents[0] != parent || len(pkgs.AncestorTxns) != 1 ||
+		pkgs.Descendants.Count != 1 || len(pkgs.Children) != 0 {
+		t.Errorf("unexpected packages %+v", pkgs)
+	}
+
+	// The parent's descendants include a child added later.
+	graph.Add(&exptypes.MempoolTx{TxID: lone[:63] + "4", Size: 250, Fees: 0.0001,
+		Vin: []exptypes.MempoolInput{{TxId: child}}})
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest("GET", "/mempool/graph/"+parent, nil))
+	pkgs = apitypes.MempoolTxPackages{}
+	if err := json.Unmarshal(rr.Body.Bytes(), &pkgs); err != nil {
+		t.Fatal(err)
+	}
+	if pkgs.Descendants.Count != 3 || len(pkgs.DescendantTxns) != 2 || len(pkgs.Children) != 1 {
+		t.Errorf("unexpected packages %+v", pkgs)
+	}
+
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest("GET", "/mempool/graph/"+strings.Repeat("0", 64), nil))
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("got status %d for a transaction not in mempool", rr.Code)
+	}
+}
+
+func TestVerifyMessage(t *testing.T) {
+	const addr = "DsUZxxoHJSty8DCfwfartwTYbuhmVct7tJu"
+	c := &appContext{Params: chaincfg.MainNetParams()}
+	mux := chi.NewRouter()
+	mux.With(m.AddressPathCtxN(1)).Post("/address/{address}/verify", c.verifyMessage)
+
+	// A well-formed signature by another key is not valid.
+	sig := "H" + strings.Repeat("A", 87)
+	tests := []struct {
+		name     string
+		address  string
+		body     string
+		wantCode int
+	}{
+		{"signed", addr, `{"message": "hi", "signature": "` + sig + `"}`, http.StatusOK},
+		{"no signature", addr, `{"message": "hi"}`, http.StatusBadRequest},
+		{"bad signature", addr, `{"message": "hi", "signature": "!"}`, http.StatusBadRequest},
+		{"script hash address", "DcaephHCqjdfb3gPz778DJZWvwmUUs3ssGk",
+			`{"message": "hi", "signature": "` + sig + `"}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, httptest.NewRequest("POST", "/address/"+tt.address+"/verify",
+			strings.NewReader(tt.body)))
+		if rr.Code != tt.wantCode {
+			t.Errorf("%s: got status %d, wanted %d", tt.name, rr.Code, tt.wantCode)
+			continue
+		}
+		if tt.wantCode != http.StatusOK {
+			continue
+		}
+		var res apitypes.VerifiedMessage
+		if err := json.Unmarshal(rr.Body.Bytes(), &res); err != nil {
+			t.Fatal(err)
+		}
+		if res.Address != tt.address || res.Message != "hi" || res.Valid {
+			t.Errorf("%s: unexpected result %+v", tt.name, res)
+		}
+	}
+}
+
+func TestAddressQRCode(t *testing.T) {
+	const addr = "DsUZxxoHJSty8DCfwfartwTYbuhmVct7tJu"
+	c := &appContext{Params: chaincfg.MainNetParams()}
+	mux := chi.NewRouter()
+	mux.With(m.AddressPathCtxN(1)).Get("/address/{address}/qr", c.addressQRCode)
+
+	long := strings.Repeat("x", maxPaymentURITextLen+1)
+	tests := []struct {
+		name     string
+		query    string
+		wantCode int
+		wantType string
+	}{
+		{"png", "?amount=1.5&label=dcrdata", http.StatusOK, "image/png"},
+		{"svg", "?format=svg&size=64", http.StatusOK, "image/svg+xml"},
+		{"long label", "?label=" + long, http.StatusBadRequest, ""},
+		{"long message", "?message=" + long, http.StatusBadRequest, ""},
+		{"too large", "?size=4096", http.StatusBadRequest, ""},
+	}
+	for _, tt := range tests {
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, httptest.NewRequest("GET", "/address/"+addr+"/qr"+tt.query, nil))
+		if rr.Code != tt.wantCode {
+			t.Errorf("%s: got status %d, wanted %d", tt.name, rr.Code, tt.wantCode)
+			continue
+		}
+		if got := rr.Header().Get("Content-Type"); tt.wantType != "" && got != tt.wantType {
+			t.Errorf("%s: got content type %q, wanted %q", tt.name, got, tt.wantType)
+		}
+	}
+}
+
+func TestDailyExports(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dailyexport")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// Write an exported day's files and manifest.
+	day := &apitypes.DailyExport{Date: "2020-01-01", Start: 1577836800, End: 1577923200}
+	if err = os.Mkdir(filepath.Join(dir, day.Date), 0700); err != nil {
+		t.Fatal(err)
+	}
+	for _, table := range dailyexport.Tables {
+		name := table + ".csv.gz"
+		err = ioutil.WriteFile(filepath.Join(dir, day.Date, name), []byte(table), 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+		day.Files = append(day.Files, &apitypes.DailyExportFile{Table: table, Name: name})
+	}
+	b, _ := json.Marshal(day)
+	if err = ioutil.WriteFile(filepath.Join(dir, day.Date, "manifest.json"), b, 0600); err != nil {
+		t.Fatal(err)
+	}
+	exports, err := dailyexport.New(dir, nil, time.Unix(day.Start, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path     string
+		exports  *dailyexport.Exporter
+		wantCode int
+		wantBody string
+	}{
+		{"/export/daily", exports, http.StatusOK, ""},
+		{"/export/daily?since=2020-01-02", exports, http.StatusOK, "[]"},
+		{"/export/daily?since=yesterday", exports, http.StatusBadRequest, ""},
+		{"/export/daily", nil, http.StatusServiceUnavailable, ""},
+		{"/export/daily/2020-01-01/vouts.csv.gz", exports, http.StatusOK, "vouts"},
+		{"/export/daily/2020-01-01/manifest.json", exports, http.StatusNotFound, ""},
+		{"/export/daily/2020-01-02/vouts.csv.gz", exports, http.StatusNotFound, ""},
+	}
+	for _, tt := range tests {
+		c := &appContext{dailyExports: tt.exports}
+		mux := chi.NewRouter()
+		mux.Route("/export/daily", func(r chi.Router) {
+			r.Get("/", c.getDailyExports)
+			r.With(m.DailyExportFileCtx).Get("/{date}/{file}", c.getDailyExportFile)
+		})
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, httptest.NewRequest("GET", tt.path, nil))
+		if rr.Code != tt.wantCode {
+			t.Errorf("%s: got status %d, wanted %d", tt.path, rr.Code, tt.wantCode)
+			continue
+		}
+		if tt.wantBody != "" && strings.TrimSpace(rr.Body.String()) != tt.wantBody {
+			t.Errorf("%s: got body %q, wanted %q", tt.path, rr.Body.String(), tt.wantBody)
+		}
+	}
+
+	rr := httptest.NewRecorder()
+	c := &appContext{dailyExports: exports}
+	c.getDailyExports(rr, httptest.NewRequest("GET", "/export/daily", nil))
+	var days []*apitypes.DailyExport
+	if err = json.Unmarshal(rr.Body.Bytes(), &days); err != nil {
+		t.Fatal(err)
+	}
+	if len(days) != 1 || days[0].Date != day.Date || len(days[0].Files) != 3 {
+		t.Errorf("unexpected days %v", days)
+	}
+}
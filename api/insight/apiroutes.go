@@ -26,25 +26,9 @@ import (
 	"github.com/decred/dcrdata/db/dbtypes/v2"
 	m "github.com/decred/dcrdata/middleware/v3"
 	"github.com/decred/dcrdata/rpcutils/v3"
+	"github.com/decred/dcrdata/v5/chainstore"
 )
 
-type BlockDataSource interface {
-	AddressBalance(address string) (bal *dbtypes.AddressBalance, cacheUpdated bool, err error)
-	AddressIDsByOutpoint(txHash string, voutIndex uint32) ([]uint64, []string, int64, error)
-	AddressUTXO(address string) ([]*dbtypes.AddressTxnOutput, bool, error)
-	BlockSummaryTimeRange(min, max int64, limit int) ([]dbtypes.BlockDataBasic, error)
-	GetBlockHash(idx int64) (string, error)
-	GetBlockHeight(hash string) (int64, error)
-	GetBlockVerboseByHash(hash string, verboseTx bool) *chainjson.GetBlockVerboseResult
-	GetHeight() (int64, error)
-	GetRawTransaction(txid *chainhash.Hash) (*chainjson.TxRawResult, error)
-	GetTransactionHex(txid *chainhash.Hash) string
-	Height() int64
-	InsightAddressTransactions(addr []string, recentBlockHeight int64) (txs, recentTxs []chainhash.Hash, err error)
-	SendRawTransaction(txhex string) (string, error)
-	SpendDetailsForFundingTx(fundHash string) ([]*apitypes.SpendByFundingHash, error)
-}
-
 const (
 	defaultReqPerSecLimit = 20.0
 
@@ -72,7 +56,7 @@ const (
 // methods include the http.Handlers for the URL path routes.
 type InsightApi struct {
 	nodeClient      *rpcclient.Client
-	BlockData       BlockDataSource
+	BlockData       chainstore.ChainStore
 	params          *chaincfg.Params
 	mp              rpcutils.MempoolAddressChecker
 	status          *apitypes.Status
@@ -83,7 +67,7 @@ type InsightApi struct {
 }
 
 // NewInsightApi is the constructor for InsightApi.
-func NewInsightApi(client *rpcclient.Client, blockData BlockDataSource, params *chaincfg.Params,
+func NewInsightApi(client *rpcclient.Client, blockData chainstore.ChainStore, params *chaincfg.Params,
 	memPoolData rpcutils.MempoolAddressChecker, JSONIndent string, status *apitypes.Status) *InsightApi {
 
 	return &InsightApi{
@@ -149,7 +133,7 @@ func (iapi *InsightApi) getTransaction(w http.ResponseWriter, r *http.Request) {
 	txsOld := []*chainjson.TxRawResult{txOld}
 
 	// convert to insight struct
-	txsNew, err := iapi.TxConverter(txsOld)
+	txsNew, err := iapi.TxConverter(r.Context(), txsOld)
 
 	if err != nil {
 		apiLog.Errorf("Error Processing Transactions")
@@ -190,7 +174,7 @@ func (iapi *InsightApi) getBlockSummary(w http.ResponseWriter, r *http.Request)
 			return
 		}
 
-		hash, err = iapi.BlockData.GetBlockHash(int64(idx))
+		hash, err = iapi.BlockData.GetBlockHash(r.Context(), int64(idx))
 		if dbtypes.IsTimeoutErr(err) {
 			apiLog.Errorf("GetBlockHash: %v", err)
 			http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
@@ -231,7 +215,7 @@ func (iapi *InsightApi) getBlockHash(w http.ResponseWriter, r *http.Request) {
 		writeInsightError(w, "Block height out of range")
 		return
 	}
-	hash, err := iapi.BlockData.GetBlockHash(int64(idx))
+	hash, err := iapi.BlockData.GetBlockHash(r.Context(), int64(idx))
 	if dbtypes.IsTimeoutErr(err) {
 		apiLog.Errorf("GetBlockHash: %v", err)
 		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
@@ -259,7 +243,7 @@ func (iapi *InsightApi) getRawBlock(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		hash, err = iapi.BlockData.GetBlockHash(int64(idx))
+		hash, err = iapi.BlockData.GetBlockHash(r.Context(), int64(idx))
 		if dbtypes.IsTimeoutErr(err) {
 			apiLog.Errorf("GetBlockHash: %v", err)
 			http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
@@ -373,7 +357,7 @@ func (iapi *InsightApi) getAddressesTxnOutput(w http.ResponseWriter, r *http.Req
 		tStart := time.Now()
 
 		// Query for UTXOs for the current address.
-		confirmedTxnOutputs, _, err := iapi.BlockData.AddressUTXO(address)
+		confirmedTxnOutputs, _, err := iapi.BlockData.AddressUTXO(r.Context(), address)
 
 		apiLog.Debugf("AddressUTXO completed for %s with %d UTXOs in %v.",
 			address, len(confirmedTxnOutputs), time.Since(tStart))
@@ -603,7 +587,7 @@ func (iapi *InsightApi) getTransactions(w http.ResponseWriter, r *http.Request)
 		}
 
 		// Convert to chainjson transaction to Insight tx type.
-		txsNew, err := iapi.TxConverter(txsOld)
+		txsNew, err := iapi.TxConverter(r.Context(), txsOld)
 		if err != nil {
 			apiLog.Error("getTransactions: Error processing transactions: %v", err)
 			writeInsightError(w, "Error Processing Transactions")
@@ -628,7 +612,7 @@ func (iapi *InsightApi) getTransactions(w http.ResponseWriter, r *http.Request)
 		}
 
 		hashes, recentTxs, err :=
-			iapi.BlockData.InsightAddressTransactions([]string{address},
+			iapi.BlockData.InsightAddressTransactions(r.Context(), []string{address},
 				int64(iapi.status.Height()-2))
 		if dbtypes.IsTimeoutErr(err) {
 			apiLog.Errorf("InsightAddressTransactions: %v", err)
@@ -742,7 +726,7 @@ func (iapi *InsightApi) getTransactions(w http.ResponseWriter, r *http.Request)
 
 		// Convert to chainjson transaction to Insight tx type. TxConverter also
 		// retrieves previous outpoint addresses.
-		txsNew, err := iapi.TxConverter(txsOld)
+		txsNew, err := iapi.TxConverter(r.Context(), txsOld)
 		if err != nil {
 			apiLog.Error("getTransactions: Error processing transactions: %v", err)
 			writeInsightError(w, "Error Processing Transactions")
@@ -795,7 +779,7 @@ func (iapi *InsightApi) getAddressesTxn(w http.ResponseWriter, r *http.Request)
 	var UnconfirmedTxTimes []int64
 
 	rawTxs, recentTxs, err :=
-		iapi.BlockData.InsightAddressTransactions(addresses, int64(iapi.status.Height()-2))
+		iapi.BlockData.InsightAddressTransactions(r.Context(), addresses, int64(iapi.status.Height()-2))
 	if dbtypes.IsTimeoutErr(err) {
 		apiLog.Errorf("InsightAddressTransactions: %v", err)
 		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
@@ -918,7 +902,7 @@ func (iapi *InsightApi) getAddressesTxn(w http.ResponseWriter, r *http.Request)
 	}
 
 	// Convert to Insight API struct.
-	txsNew, err := iapi.DcrToInsightTxns(txsOld, noAsm, noScriptSig, noSpent)
+	txsNew, err := iapi.DcrToInsightTxns(r.Context(), txsOld, noAsm, noScriptSig, noSpent)
 	if err != nil {
 		apiLog.Error("Unable to process transactions")
 		writeInsightError(w, fmt.Sprintf("Unable to convert transactions (%v)", err))
@@ -1102,9 +1086,18 @@ func (iapi *InsightApi) getBlockSummaryByTime(w http.ResponseWriter, r *http.Req
 	summaryOutput.Pagination.Current = blockDate.Format(ymdFormat)
 	summaryOutput.Pagination.IsToday = isToday
 
-	// TODO: limit the query rather than returning all and limiting in go.
+	// Request one more block than the limit to determine if there are more
+	// blocks on this date.
+	limit := GetLimitCtx(r)
+	if limit < 0 {
+		limit = 0
+	}
+	queryLimit := limit
+	if limit > 0 {
+		queryLimit++
+	}
 	minTime, maxTime := minDate.Unix(), maxDate.Unix()
-	blockSummary, err := iapi.BlockData.BlockSummaryTimeRange(minTime, maxTime, 0)
+	blockSummary, err := iapi.BlockData.BlockSummaryTimeRange(r.Context(), minTime, maxTime, queryLimit)
 	if dbtypes.IsTimeoutErr(err) {
 		apiLog.Errorf("BlockSummaryTimeRange: %v", err)
 		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
@@ -1115,25 +1108,20 @@ func (iapi *InsightApi) getBlockSummaryByTime(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	// Generate the pagination parameters More and MoreTs, and limit the result.
-	limit := GetLimitCtx(r)
-	if limit > 0 {
-		var outputBlockSummary []dbtypes.BlockDataBasic
-		for i, block := range blockSummary {
-			if i >= limit {
-				summaryOutput.Pagination.More = true
-				break
-			}
-			outputBlockSummary = append(outputBlockSummary, block)
-			blockTime := block.Time.UNIX()
-			if blockTime < summaryOutput.Pagination.MoreTs {
-				summaryOutput.Pagination.MoreTs = blockTime
-			}
-		}
-		summaryOutput.Blocks = outputBlockSummary
+	// Generate the pagination parameters More and MoreTs, and limit the
+	// result. The blocks are sorted newest first, so MoreTs is the time of the
+	// oldest block returned.
+	if limit > 0 && len(blockSummary) > limit {
+		blockSummary = blockSummary[:limit]
+		summaryOutput.Pagination.More = true
+	}
+	if blockSummary == nil {
+		blockSummary = []dbtypes.BlockDataBasic{}
+	}
+	summaryOutput.Blocks = blockSummary
+	if n := len(blockSummary); summaryOutput.Pagination.More && n > 0 {
+		summaryOutput.Pagination.MoreTs = blockSummary[n-1].Time.UNIX()
 	} else {
-		summaryOutput.Blocks = blockSummary
-		summaryOutput.Pagination.More = false
 		summaryOutput.Pagination.MoreTs = minTime
 	}
 
@@ -1161,7 +1149,7 @@ func (iapi *InsightApi) getAddressInfo(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Get confirmed balance.
-	balance, _, err := iapi.BlockData.AddressBalance(address)
+	balance, _, err := iapi.BlockData.AddressBalance(r.Context(), address)
 	if dbtypes.IsTimeoutErr(err) {
 		apiLog.Errorf("AddressSpentUnspent: %v", err)
 		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
@@ -1190,7 +1178,7 @@ func (iapi *InsightApi) getAddressInfo(w http.ResponseWriter, r *http.Request) {
 
 	// Get confirmed transactions.
 	rawTxs, recentTxs, err :=
-		iapi.BlockData.InsightAddressTransactions(addresses, int64(iapi.status.Height()-2))
+		iapi.BlockData.InsightAddressTransactions(r.Context(), addresses, int64(iapi.status.Height()-2))
 	if dbtypes.IsTimeoutErr(err) {
 		apiLog.Errorf("InsightAddressTransactions: %v", err)
 		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
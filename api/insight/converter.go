@@ -5,22 +5,26 @@
 package insight
 
 import (
+	"context"
+	"database/sql"
+
 	"github.com/decred/dcrd/blockchain/standalone"
 	"github.com/decred/dcrd/dcrutil/v2"
 	chainjson "github.com/decred/dcrd/rpc/jsonrpc/types/v2"
 	apitypes "github.com/decred/dcrdata/api/types/v5"
+	"github.com/decred/dcrdata/db/dbtypes/v2"
 	"github.com/decred/dcrdata/txhelpers/v4"
 )
 
 // TxConverter converts dcrd-tx to insight tx
-func (iapi *InsightApi) TxConverter(txs []*chainjson.TxRawResult) ([]apitypes.InsightTx, error) {
-	return iapi.DcrToInsightTxns(txs, false, false, false)
+func (iapi *InsightApi) TxConverter(ctx context.Context, txs []*chainjson.TxRawResult) ([]apitypes.InsightTx, error) {
+	return iapi.DcrToInsightTxns(ctx, txs, false, false, false)
 }
 
 // DcrToInsightTxns converts a chainjson TxRawResult to a InsightTx. The asm,
 // scriptSig, and spending status may be skipped by setting the appropriate
 // input arguments.
-func (iapi *InsightApi) DcrToInsightTxns(txs []*chainjson.TxRawResult, noAsm, noScriptSig, noSpent bool) ([]apitypes.InsightTx, error) {
+func (iapi *InsightApi) DcrToInsightTxns(ctx context.Context, txs []*chainjson.TxRawResult, noAsm, noScriptSig, noSpent bool) ([]apitypes.InsightTx, error) {
 	newTxs := make([]apitypes.InsightTx, 0, len(txs))
 	for _, tx := range txs {
 		// Build new InsightTx
@@ -36,10 +40,34 @@ func (iapi *InsightApi) DcrToInsightTxns(txs []*chainjson.TxRawResult, noAsm, no
 			Size:          uint32(len(tx.Hex) / 2),
 		}
 
+		// For a confirmed transaction, the input values and addresses and the
+		// confirmations are computed from the DB. Otherwise, they come from
+		// dcrd, and the addresses from the funding transactions.
+		var dbInputs *dbtypes.TxInputsSummary
+		if tx.BlockHeight > 0 {
+			var err error
+			dbInputs, err = iapi.BlockData.MainchainTxInputs(ctx, tx.Txid)
+			if err != nil && err != sql.ErrNoRows {
+				apiLog.Errorf("MainchainTxInputs: %v", err)
+			}
+			if dbInputs != nil && len(dbInputs.ValueIns) != len(tx.Vin) {
+				apiLog.Warnf("MainchainTxInputs: %d inputs in DB, %d from dcrd for %s",
+					len(dbInputs.ValueIns), len(tx.Vin), tx.Txid)
+				dbInputs = nil
+			}
+			if dbInputs != nil {
+				txNew.Blockheight = dbInputs.BlockHeight
+				txNew.Confirmations = iapi.BlockData.Height() - dbInputs.BlockHeight + 1
+			}
+		}
+
 		// Vins
 		var vInSum dcrutil.Amount
 		for vinID, vin := range tx.Vin {
 			amt, _ := dcrutil.NewAmount(vin.AmountIn)
+			if dbInputs != nil {
+				amt = dcrutil.Amount(dbInputs.ValueIns[vinID])
+			}
 			vInSum += amt
 
 			InsightVin := &apitypes.InsightVin{
@@ -47,7 +75,7 @@ func (iapi *InsightApi) DcrToInsightTxns(txs []*chainjson.TxRawResult, noAsm, no
 				Vout:     newUint32Ptr(vin.Vout),
 				Sequence: newUint32Ptr(vin.Sequence),
 				N:        vinID,
-				Value:    vin.AmountIn,
+				Value:    amt.ToCoin(),
 				ValueSat: int64(amt),
 				CoinBase: vin.Coinbase,
 			}
@@ -70,8 +98,10 @@ func (iapi *InsightApi) DcrToInsightTxns(txs []*chainjson.TxRawResult, noAsm, no
 			// First, attempt to get input addresses from our DB, which should
 			// work if the funding transaction is confirmed. Otherwise use RPC
 			// to get the funding transaction outpoint addresses.
-			if !vinGenerated {
-				_, addresses, _, err := iapi.BlockData.AddressIDsByOutpoint(vin.Txid, vin.Vout)
+			if !vinGenerated && dbInputs != nil && len(dbInputs.Addresses[vinID]) > 0 {
+				InsightVin.Addr = dbInputs.Addresses[vinID][0]
+			} else if !vinGenerated {
+				_, addresses, _, err := iapi.BlockData.AddressIDsByOutpoint(ctx, vin.Txid, vin.Vout)
 				if err == nil && len(addresses) > 0 {
 					InsightVin.Addr = addresses[0]
 				} else {
@@ -129,7 +159,7 @@ func (iapi *InsightApi) DcrToInsightTxns(txs []*chainjson.TxRawResult, noAsm, no
 			// Populate the spending status of all vouts. Note: this only
 			// gathers information from the database, which does not include
 			// mempool transactions.
-			addrFull, err := iapi.BlockData.SpendDetailsForFundingTx(txNew.Txid)
+			addrFull, err := iapi.BlockData.SpendDetailsForFundingTx(ctx, txNew.Txid)
 			if err != nil {
 				return nil, err
 			}
@@ -75,6 +75,51 @@ type BlockTransactions struct {
 	STx []string `json:"stx"`
 }
 
+// BlockFull models a block's summary, its trimmed transactions by tree, the
+// validation details of its votes, its ticket info, and the hashes of its
+// previous and next blocks, as shown on the block page.
+type BlockFull struct {
+	Summary       *BlockDataBasic   `json:"summary"`
+	Valid         bool              `json:"valid"`
+	Mainchain     bool              `json:"mainchain"`
+	Confirmations int64             `json:"confirmations"`
+	PreviousHash  string            `json:"previous_hash"`
+	NextHash      string            `json:"next_hash"`
+	VoteBits      uint16            `json:"vote_bits"`
+	TotalSent     float64           `json:"total_sent"`
+	MiningFee     float64           `json:"mining_fee"`
+	TotalMixed    int64             `json:"total_mixed"`
+	Tx            []*BlockTxTrimmed `json:"tx"`
+	Stake         BlockStakeFull    `json:"stake"`
+}
+
+// BlockStakeFull models the stake tree transactions of a block, and the ticket
+// pool size, ticket price, and the tickets that missed their votes.
+type BlockStakeFull struct {
+	PoolSize    uint32            `json:"pool_size"`
+	TicketPrice float64           `json:"ticket_price"`
+	Tickets     []*BlockTxTrimmed `json:"tickets"`
+	Votes       []*BlockTxTrimmed `json:"votes"`
+	Revocations []*BlockTxTrimmed `json:"revocations"`
+	Misses      []string          `json:"misses"`
+}
+
+// BlockTxTrimmed models a block's transaction with its total value and fees in
+// DCR. FeeRate is in DCR/kB. TicketSpent and Vote are only set for votes.
+type BlockTxTrimmed struct {
+	TxID        string    `json:"txid"`
+	Total       float64   `json:"total"`
+	Fees        float64   `json:"fees"`
+	FeeRate     float64   `json:"fee_rate"`
+	VinCount    int       `json:"vin_count"`
+	VoutCount   int       `json:"vout_count"`
+	Coinbase    bool      `json:"coinbase,omitempty"`
+	MixCount    uint32    `json:"mix_count,omitempty"`
+	MixDenom    int64     `json:"mix_denom,omitempty"`
+	TicketSpent string    `json:"ticket_spent,omitempty"`
+	Vote        *VoteInfo `json:"vote,omitempty"`
+}
+
 // tx raw
 // tx short (tx raw - extra context)
 // txout
@@ -85,8 +130,9 @@ type BlockTransactions struct {
 // Tx models TxShort with the number of confirmations and block info Block
 type Tx struct {
 	TxShort
-	Confirmations int64    `json:"confirmations"`
-	Block         *BlockID `json:"block,omitempty"`
+	Confirmations int64                  `json:"confirmations"`
+	Block         *BlockID               `json:"block,omitempty"`
+	TimeLocks     *txhelpers.TxTimeLocks `json:"timelocks,omitempty"`
 }
 
 // TxShort models info about transaction TxID
@@ -115,6 +161,152 @@ type AgendaAPIResponse struct {
 	ByTime   *dbtypes.AgendaVoteChoices `json:"by_time"`
 }
 
+// AgendaStatus describes the voting progress of an agenda as of the best block
+// at Height. The vote counts are for the rule change interval starting at
+// VotingStarted. DecisionHeight is the height at which the outcome of that
+// interval is determined (or the activation height for a locked in agenda),
+// and the estimated times assume the target block time. For an agenda that is
+// voting and has not reached quorum, QuorumHeight and QuorumTime estimate when
+// quorum will be reached at the current vote rate, if before the end of the
+// interval.
+type AgendaStatus struct {
+	ID               string  `json:"id"`
+	Status           string  `json:"status"`
+	Height           int64   `json:"height"`
+	VotingStarted    int64   `json:"voting_started"`
+	VotingDone       int64   `json:"voting_done"`
+	Activated        int64   `json:"activated"`
+	Yes              uint32  `json:"yes"`
+	Abstain          uint32  `json:"abstain"`
+	No               uint32  `json:"no"`
+	Quorum           uint32  `json:"quorum"`
+	QuorumReached    bool    `json:"quorum_reached"`
+	Approval         float64 `json:"approval"`
+	PassThreshold    float64 `json:"pass_threshold"`
+	VoteRate         float64 `json:"vote_rate"`
+	QuorumHeight     int64   `json:"quorum_height,omitempty"`
+	QuorumTime       int64   `json:"quorum_time,omitempty"`
+	DecisionHeight   int64   `json:"decision_height,omitempty"`
+	BlocksToDecision int64   `json:"blocks_to_decision,omitempty"`
+	DecisionTime     int64   `json:"decision_time,omitempty"`
+}
+
+// AgendaTicketTypeVotes is the breakdown of the vote choices cast on an agenda
+// in its voting interval by the type of the voting tickets (solo, pooled or
+// other), which shows whether VSP tickets vote differently than solo stakers.
+type AgendaTicketTypeVotes struct {
+	ID            string             `json:"id"`
+	VotingStarted int64              `json:"voting_started"`
+	VotingDone    int64              `json:"voting_done"`
+	TicketTypes   []*TicketTypeVotes `json:"ticket_types"`
+}
+
+// TicketTypeVotes is the number of each vote choice cast by tickets of a type.
+// Approval is the fraction of the non-abstaining votes that are yes.
+type TicketTypeVotes struct {
+	TicketType string  `json:"ticket_type"`
+	Yes        uint32  `json:"yes"`
+	Abstain    uint32  `json:"abstain"`
+	No         uint32  `json:"no"`
+	Total      uint32  `json:"total"`
+	Approval   float64 `json:"approval"`
+}
+
+// AgendaVoteTimeline is the voting timeline of an agenda in its voting
+// interval, as the vote choices cast in each consecutive span of Interval
+// blocks starting at VotingStarted. Spans without votes are omitted.
+type AgendaVoteTimeline struct {
+	ID            string                `json:"id"`
+	VotingStarted int64                 `json:"voting_started"`
+	VotingDone    int64                 `json:"voting_done"`
+	Interval      int64                 `json:"interval"`
+	Quorum        uint32                `json:"quorum"`
+	PassThreshold float64               `json:"pass_threshold"`
+	Intervals     []*AgendaVoteInterval `json:"intervals"`
+}
+
+// AgendaVoteInterval is the number of each vote choice cast on an agenda in
+// the blocks [StartHeight, EndHeight], with the cumulative counts since the
+// start of voting. The percentages and the quorum progress are cumulative.
+// YesPercent and NoPercent are of the non-abstaining votes, AbstainPercent of
+// all votes, and QuorumProgress is the non-abstaining votes as a percentage of
+// the quorum.
+type AgendaVoteInterval struct {
+	StartHeight    int64   `json:"start_height"`
+	EndHeight      int64   `json:"end_height"`
+	StartTime      int64   `json:"start_time"`
+	EndTime        int64   `json:"end_time"`
+	Yes            uint32  `json:"yes"`
+	Abstain        uint32  `json:"abstain"`
+	No             uint32  `json:"no"`
+	Total          uint32  `json:"total"`
+	CumYes         uint32  `json:"cum_yes"`
+	CumAbstain     uint32  `json:"cum_abstain"`
+	CumNo          uint32  `json:"cum_no"`
+	CumTotal       uint32  `json:"cum_total"`
+	YesPercent     float64 `json:"yes_percent"`
+	AbstainPercent float64 `json:"abstain_percent"`
+	NoPercent      float64 `json:"no_percent"`
+	QuorumProgress float64 `json:"quorum_progress"`
+}
+
+// UpgradeProgress describes the adoption of the latest block version by miners
+// and of the latest vote version by stakeholders as of the best block at
+// Height, relative to the upgrade rules of the network, with the heights at
+// which each version previously crossed the thresholds.
+type UpgradeProgress struct {
+	Height   int64                `json:"height"`
+	Miners   MinerUpgradeProgress `json:"miners"`
+	Voters   VoterUpgradeProgress `json:"voters"`
+	Timeline []*UpgradeThreshold  `json:"timeline"`
+}
+
+// MinerUpgradeProgress is the number of the last Window blocks with at least
+// the latest block version, Version. The version is enforced for new blocks
+// once Count reaches EnforceThreshold, and lower versions are rejected once it
+// reaches RejectThreshold.
+type MinerUpgradeProgress struct {
+	Version          int32 `json:"version"`
+	Count            int64 `json:"count"`
+	Window           int64 `json:"window"`
+	EnforceThreshold int64 `json:"enforce_threshold"`
+	RejectThreshold  int64 `json:"reject_threshold"`
+	Enforced         bool  `json:"enforced"`
+	Rejecting        bool  `json:"rejecting"`
+}
+
+// VoterUpgradeProgress is the number of votes with at least the latest vote
+// version, Version, out of all votes in the current stake version interval.
+// The stake version is upgraded after an interval in which the fraction of
+// such votes reaches Threshold.
+type VoterUpgradeProgress struct {
+	Version         int32   `json:"version"`
+	IntervalStart   int64   `json:"interval_start"`
+	IntervalEnd     int64   `json:"interval_end"`
+	Count           int64   `json:"count"`
+	Total           int64   `json:"total"`
+	Fraction        float64 `json:"fraction"`
+	Threshold       float64 `json:"threshold"`
+	MajorityReached bool    `json:"majority_reached"`
+}
+
+// Kinds of UpgradeThreshold.
+const (
+	UpgradeMinerEnforce  = "miner_enforce"
+	UpgradeMinerReject   = "miner_reject"
+	UpgradeVoterMajority = "voter_majority"
+)
+
+// UpgradeThreshold is the block at which a block or vote version crossed one
+// of the upgrade thresholds. For UpgradeVoterMajority, it is the last block of
+// the stake version interval in which the version had a majority of the votes.
+type UpgradeThreshold struct {
+	Kind    string `json:"kind"`
+	Version int32  `json:"version"`
+	Height  int64  `json:"height"`
+	Time    int64  `json:"time"`
+}
+
 // TrimmedTx models data to resemble to result of the decoderawtransaction RPC.
 type TrimmedTx struct {
 	TxID     string          `json:"txid"`
@@ -138,6 +330,22 @@ type VoteInfo struct {
 	Choices    []*txhelpers.VoteChoice `json:"vote_choices"`
 }
 
+// Vote is a vote (ssgen) transaction with its vote bits decoded. Ticket is the
+// spent ticket, and the vote is in the block at BlockHeight. Validation is the
+// vote's approval of the previous block, and Choices maps the ID of each agenda
+// of the vote version to the vote's choice, e.g. "yes", "no" or "abstain".
+type Vote struct {
+	TxID        string              `json:"txid"`
+	Ticket      string              `json:"ticket"`
+	BlockHash   string              `json:"block_hash"`
+	BlockHeight int64               `json:"block_height"`
+	IsMainchain bool                `json:"is_mainchain"`
+	Validation  BlockValidation     `json:"block_validation"`
+	Version     uint32              `json:"vote_version"`
+	Bits        uint16              `json:"vote_bits"`
+	Choices     dbtypes.VoteChoices `json:"choices"`
+}
+
 // BlockValidation models data about a vote's decision on a block
 type BlockValidation struct {
 	Hash     string `json:"hash"`
@@ -145,13 +353,54 @@ type BlockValidation struct {
 	Validity bool   `json:"validity"`
 }
 
-// BlockID models very basic info about a block
+// BlockID models very basic info about a block. Status is the chain status of
+// the block with respect to the transaction, one of the TxBlock* constants.
 type BlockID struct {
 	BlockHash   string `json:"blockhash"`
 	BlockHeight int64  `json:"blockheight"`
 	BlockIndex  uint32 `json:"blockindex"`
 	Time        int64  `json:"time"`
 	BlockTime   int64  `json:"blocktime"`
+	Status      string `json:"status,omitempty"`
+}
+
+// The chain status of a block in which a transaction appears. A transaction in
+// a main chain block whose regular transactions were disapproved by
+// stakeholders is invalidated.
+const (
+	TxBlockMainchain   = "mainchain"
+	TxBlockSidechain   = "sidechain"
+	TxBlockInvalidated = "invalidated"
+)
+
+// TxBlockStatus returns the chain status of a transaction's block given the
+// transaction's mainchain and validity flags.
+func TxBlockStatus(isMainchain, isValid bool) string {
+	switch {
+	case !isMainchain:
+		return TxBlockSidechain
+	case !isValid:
+		return TxBlockInvalidated
+	default:
+		return TxBlockMainchain
+	}
+}
+
+// TxBlock is a block in which a transaction appears, with the chain status of
+// the block.
+type TxBlock struct {
+	BlockHash   string `json:"blockhash"`
+	BlockHeight int64  `json:"blockheight"`
+	BlockIndex  uint32 `json:"blockindex"`
+	Status      string `json:"status"`
+}
+
+// TxDuplicate is a transaction that appears in more than one block. Mainchain
+// indicates if one of the blocks is a valid main chain block.
+type TxDuplicate struct {
+	TxID      string     `json:"txid"`
+	Mainchain bool       `json:"mainchain"`
+	Blocks    []*TxBlock `json:"blocks"`
 }
 
 // BlockRaw contains the hexadecimal encoded bytes of a serialized block.
@@ -161,6 +410,22 @@ type BlockRaw struct {
 	Hex    string `json:"hex"`
 }
 
+// BlockHeaderLine is a block header in the newline-delimited JSON stream of
+// block headers. SBits is the stake difficulty in atoms.
+type BlockHeaderLine struct {
+	Height       uint32 `json:"height"`
+	Hash         string `json:"hash"`
+	PreviousHash string `json:"previousblockhash"`
+	Time         int64  `json:"time"`
+	Bits         string `json:"bits"`
+	SBits        int64  `json:"sbits"`
+	VoteBits     uint16 `json:"votebits"`
+	Voters       uint16 `json:"voters"`
+	FreshStake   uint8  `json:"freshstake"`
+	Revocations  uint8  `json:"revocations"`
+	PoolSize     uint32 `json:"poolsize"`
+}
+
 // VoutMined appends a best block hash, number of confimations and if a
 // transaction is a coinbase to a transaction output
 type VoutMined struct {
@@ -303,6 +568,7 @@ type TxIn struct {
 	BlockHeight     uint32  `json:"blockheight"`
 	BlockIndex      uint32  `json:"blockindex"`
 	SignatureScript string  `json:"sigscript"`
+	SigType         string  `json:"sigtype"`
 }
 
 // OutPoint is used to track previous transaction outputs.
@@ -316,6 +582,11 @@ type OutPoint struct {
 type Address struct {
 	Address      string            `json:"address"`
 	Transactions []*AddressTxShort `json:"address_transactions"`
+	// TipHash and TipHeight identify the best block as of which the
+	// transactions were retrieved. If the tip changes between requests for
+	// consecutive pages, the pages may be inconsistent.
+	TipHash   string `json:"tip_hash,omitempty"`
+	TipHeight int64  `json:"tip_height,omitempty"`
 }
 
 // AddressTxRaw is modeled from SearchRawTransactionsResult but with size in
@@ -340,19 +611,98 @@ type AddressTxShort struct {
 	Size          int32   `json:"size"`
 	Time          TimeAPI `json:"time"`
 	Value         float64 `json:"value"`
+	Fees          float64 `json:"fees"`
+	FeeRate       float64 `json:"fee_rate"`
 	Confirmations int64   `json:"confirmations"`
+	// NetAmount and MergedCount are only set in the merged views, in which the
+	// address' inputs and outputs in a transaction are combined. NetAmount is
+	// the credits less the debits, and MergedCount is the number of inputs and
+	// outputs combined.
+	NetAmount   *float64 `json:"net_amount,omitempty"`
+	MergedCount uint64   `json:"merged_count,omitempty"`
 }
 
 // AddressTotals represents the number and value of spent and unspent outputs
 // for an address.
 type AddressTotals struct {
-	Address      string  `json:"address"`
-	BlockHash    string  `json:"blockhash"`
-	BlockHeight  uint64  `json:"blockheight"`
-	NumSpent     int64   `json:"num_stxos"`
-	NumUnspent   int64   `json:"num_utxos"`
-	CoinsSpent   float64 `json:"dcr_spent"`
-	CoinsUnspent float64 `json:"dcr_unspent"`
+	Address      string           `json:"address"`
+	BlockHash    string           `json:"blockhash"`
+	BlockHeight  uint64           `json:"blockheight"`
+	NumSpent     int64            `json:"num_stxos"`
+	NumUnspent   int64            `json:"num_utxos"`
+	CoinsSpent   float64          `json:"dcr_spent"`
+	CoinsUnspent float64          `json:"dcr_unspent"`
+	FirstSeen    *dbtypes.TimeDef `json:"first_seen,omitempty"`
+	LastActivity *dbtypes.TimeDef `json:"last_activity,omitempty"`
+	Fiat         *FiatValues      `json:"fiat,omitempty"`
+}
+
+// AddressUTXOs is a page of the unspent outputs paying to an address. Count
+// and Atoms are the number and value of all of the address's unspent outputs,
+// and Offset is the number of outputs skipped before the page, in the order
+// given by Sort.
+type AddressUTXOs struct {
+	Address string         `json:"address"`
+	Count   int64          `json:"count"`
+	Atoms   int64          `json:"atoms"`
+	Offset  int64          `json:"offset"`
+	Sort    string         `json:"sort"`
+	UTXOs   []*AddressUTXO `json:"utxos"`
+}
+
+// AddressUTXO is an unspent output paying to an address. Coinbase and stake
+// (vote and revocation) outputs, and ticket change outputs, may only be spent
+// after Maturity confirmations, and BlocksToMaturity is the number of blocks
+// remaining until then. The Maturity of the stake submission output of a
+// ticket is the ticket maturity, after which the ticket may vote. Spendable is
+// false for immature outputs and for the stake submission output of a ticket,
+// which is only spent by a vote or revocation.
+type AddressUTXO struct {
+	TxHash           string  `json:"txid"`
+	Vout             uint32  `json:"vout"`
+	Tree             int8    `json:"tree"`
+	TxType           string  `json:"tx_type"`
+	Height           int64   `json:"height"`
+	BlockTime        int64   `json:"block_time"`
+	Atoms            int64   `json:"atoms"`
+	Amount           float64 `json:"amount"`
+	ScriptPubKey     string  `json:"script_pubkey"`
+	Confirmations    int64   `json:"confirmations"`
+	Coinbase         bool    `json:"coinbase"`
+	Maturity         int64   `json:"maturity"`
+	BlocksToMaturity int64   `json:"blocks_to_maturity"`
+	Spendable        bool    `json:"spendable"`
+}
+
+// AddressTxCounts are the numbers of valid mainchain funding (outputs paying to
+// the address) and spending (inputs spending from the address) rows of an
+// address, as of the best block.
+type AddressTxCounts struct {
+	Address     string `json:"address"`
+	BlockHash   string `json:"blockhash"`
+	BlockHeight uint64 `json:"blockheight"`
+	NumFunding  int64  `json:"num_funding"`
+	NumSpending int64  `json:"num_spending"`
+}
+
+// PaymentURI is a payment request URI for an address, with the optional
+// amount in DCR, label and message encoded in the URI.
+type PaymentURI struct {
+	Address string  `json:"address"`
+	Amount  float64 `json:"amount,omitempty"`
+	Label   string  `json:"label,omitempty"`
+	Message string  `json:"message,omitempty"`
+	URI     string  `json:"uri"`
+}
+
+// VerifiedMessage is the result of verifying a signed message against an
+// address. Valid is true if the signature was made with the address's private
+// key, proving ownership of the address.
+type VerifiedMessage struct {
+	Address   string `json:"address"`
+	Message   string `json:"message"`
+	Signature string `json:"signature"`
+	Valid     bool   `json:"valid"`
 }
 
 // BlockDataWithTxType adds an array of TxRawWithTxType to
@@ -376,6 +726,33 @@ type TxRawWithTxType struct {
 	TxType string
 }
 
+// TxValidation is the result of checking a raw transaction against the
+// blockchain, the mempool, and the default mempool fee and size policy without
+// broadcasting it. LikelyAccepted indicates that none of the checks failed,
+// although the node may still reject the transaction, for example if its
+// signatures are invalid. Amounts are in DCR, and FeeRate is in DCR/kB.
+type TxValidation struct {
+	TxID           string   `json:"txid,omitempty"`
+	Type           string   `json:"type,omitempty"`
+	LikelyAccepted bool     `json:"likely_accepted"`
+	Size           int      `json:"size"`
+	Fee            float64  `json:"fee"`
+	FeeRate        float64  `json:"fee_rate"`
+	MinFee         float64  `json:"min_fee"`
+	RejectReasons  []string `json:"reject_reasons,omitempty"`
+	Warnings       []string `json:"warnings,omitempty"`
+}
+
+// Reject records a reason the transaction would be rejected.
+func (v *TxValidation) Reject(format string, args ...interface{}) {
+	v.RejectReasons = append(v.RejectReasons, fmt.Sprintf(format, args...))
+}
+
+// Warn records a caveat that does not cause the transaction to be rejected.
+func (v *TxValidation) Warn(format string, args ...interface{}) {
+	v.Warnings = append(v.Warnings, fmt.Sprintf(format, args...))
+}
+
 // ScriptSig models the signature script used to redeem the origin transaction
 // as a JSON object (non-coinbase txns only)
 type ScriptSig struct {
@@ -416,6 +793,7 @@ type Status struct {
 	dbLastBlockTime int64
 	height          uint32
 	nodeConnections int64
+	nodeDegraded    bool
 	api             APIStatus
 }
 
@@ -426,6 +804,7 @@ type APIStatus struct {
 	DBLastBlockTime int64  `json:"db_block_time"`
 	Height          uint32 `json:"node_height"`
 	NodeConnections int64  `json:"node_connections"`
+	NodeDegraded    bool   `json:"node_degraded"`
 	APIVersion      int    `json:"api_version"`
 	DcrdataVersion  string `json:"dcrdata_version"`
 	NetworkName     string `json:"network_name"`
@@ -454,6 +833,7 @@ func (s *Status) API() APIStatus {
 		DBLastBlockTime: s.dbLastBlockTime,
 		Height:          s.height,
 		NodeConnections: s.nodeConnections,
+		NodeDegraded:    s.nodeDegraded,
 		APIVersion:      s.api.APIVersion,
 		DcrdataVersion:  s.api.DcrdataVersion,
 		NetworkName:     s.api.NetworkName,
@@ -468,6 +848,94 @@ type Happy struct {
 	NodeConnections int64 `json:"node_connections"`
 }
 
+// WebsocketMetrics describes the explorer's websocket connections.
+// AvgSessionSeconds is the average length of the sessions that have ended,
+// and MessagesPerSecond is the rate of messages sent and received over the
+// last sampling interval.
+type WebsocketMetrics struct {
+	ActiveClients     int     `json:"active_clients"`
+	EndedSessions     int64   `json:"ended_sessions"`
+	AvgSessionSeconds float64 `json:"avg_session_seconds"`
+	TotalMessages     uint64  `json:"total_messages"`
+	MessagesPerSecond float64 `json:"messages_per_second"`
+	HeartbeatTimeouts int64   `json:"heartbeat_timeouts"`
+}
+
+// CacheStats describes the contents and use of one of the internal data caches.
+// Entries is the number of cached items, and Hits and Misses count lookups
+// since startup. Updated is the UNIX time that data was last stored in or
+// flushed from the cache, and AgeSeconds is the time elapsed since then. For a
+// cache that has never been updated, Updated is 0 and AgeSeconds is null.
+type CacheStats struct {
+	Name       string `json:"name"`
+	Entries    int    `json:"entries"`
+	Hits       int    `json:"hits"`
+	Misses     int    `json:"misses"`
+	Updated    int64  `json:"updated"`
+	AgeSeconds *int64 `json:"age_seconds"`
+}
+
+// SetUpdated sets Updated and AgeSeconds from the time of the cache's last
+// update. A zero time indicates that the cache has never been updated.
+func (cs *CacheStats) SetUpdated(updated time.Time) {
+	if updated.IsZero() {
+		cs.Updated, cs.AgeSeconds = 0, nil
+		return
+	}
+	age := int64(time.Since(updated).Seconds())
+	cs.Updated, cs.AgeSeconds = updated.Unix(), &age
+}
+
+// HeightNtfnStats contains counts of the new block height notifications sent
+// by the DB to its update channel clients, such as the API status handler.
+type HeightNtfnStats struct {
+	// Clients is the number of update channel clients.
+	Clients int `json:"clients"`
+	// Signaled is the number of heights signaled, summed over the clients.
+	Signaled uint64 `json:"signaled"`
+	// Delivered is the number of heights sent on the clients' channels.
+	Delivered uint64 `json:"delivered"`
+	// Coalesced is the number of heights that were replaced by a newer height
+	// before they could be delivered to a slow client.
+	Coalesced uint64 `json:"coalesced"`
+	// Dropped is the number of heights that were never delivered because the
+	// notifier was stopped.
+	Dropped uint64 `json:"dropped"`
+}
+
+// DBStats reports the sizes and maintenance state of the dcrdata DB tables.
+// TotalBytes is the sum of the TotalBytes of the Tables.
+type DBStats struct {
+	TotalBytes int64         `json:"total_bytes"`
+	Tables     []*TableStats `json:"tables"`
+}
+
+// TableStats describes a DB table. Rows and DeadRows are the statistics
+// collector's estimates of the live and dead rows. TableBytes is the size of
+// the table's main data, IndexBytes is the size of all of its indexes, and
+// TotalBytes includes both and any TOAST data. BloatFraction is the fraction
+// of the rows that are dead, and BloatBytes estimates the space they occupy.
+// LastVacuum and LastAnalyze are the UNIX times of the last manual or
+// automatic VACUUM and ANALYZE of the table, or zero if it never happened.
+type TableStats struct {
+	Name          string  `json:"name"`
+	Rows          int64   `json:"rows"`
+	DeadRows      int64   `json:"dead_rows"`
+	TableBytes    int64   `json:"table_bytes"`
+	IndexBytes    int64   `json:"index_bytes"`
+	TotalBytes    int64   `json:"total_bytes"`
+	BloatFraction float64 `json:"bloat_fraction"`
+	BloatBytes    int64   `json:"bloat_bytes"`
+	LastVacuum    int64   `json:"last_vacuum"`
+	LastAnalyze   int64   `json:"last_analyze"`
+}
+
+// CacheFlush reports the number of entries removed from a flushed cache.
+type CacheFlush struct {
+	Name    string `json:"name"`
+	Flushed int    `json:"flushed"`
+}
+
 // Happy indicates how dcrdata is or isn't happy.
 func (s *Status) Happy() Happy {
 	s.RLock()
@@ -531,6 +999,20 @@ func (s *Status) SetConnections(conns int64) {
 	s.Unlock()
 }
 
+// NodeDegraded indicates if the RPC connection to the node is failing.
+func (s *Status) NodeDegraded() bool {
+	s.RLock()
+	defer s.RUnlock()
+	return s.nodeDegraded
+}
+
+// SetNodeDegraded sets the degraded state of the RPC connection to the node.
+func (s *Status) SetNodeDegraded(degraded bool) {
+	s.Lock()
+	s.nodeDegraded = degraded
+	s.Unlock()
+}
+
 // SetReady sets the ready state.
 func (s *Status) SetReady(ready bool) {
 	s.Lock()
@@ -558,19 +1040,33 @@ func (s *Status) DBUpdate(height uint32, blockTime int64) {
 
 // CoinSupply models the coin supply at a certain best block.
 type CoinSupply struct {
-	Height   int64  `json:"block_height"`
-	Hash     string `json:"block_hash"`
-	Mined    int64  `json:"supply_mined"`
-	Ultimate int64  `json:"supply_ultimate"`
-}
-
-// TicketPoolInfo models data about ticket pool
+	Height   int64       `json:"block_height"`
+	Hash     string      `json:"block_hash"`
+	Mined    int64       `json:"supply_mined"`
+	Ultimate int64       `json:"supply_ultimate"`
+	Fiat     *FiatValues `json:"fiat,omitempty"`
+}
+
+// FiatValues are the fiat equivalents of the DCR amounts of a response, keyed
+// by amount, at the price Rate of one DCR in Currency. They are included when
+// requested with the "fiat" URL query parameter. Stale is set if the rate is
+// not current.
+type FiatValues struct {
+	Currency string             `json:"currency"`
+	Rate     float64            `json:"rate"`
+	Stale    bool               `json:"stale,omitempty"`
+	Values   map[string]float64 `json:"values"`
+}
+
+// TicketPoolInfo models data about ticket pool. ValWeightedAvg is the average
+// ticket value weighted by value, sum(value^2)/sum(value).
 type TicketPoolInfo struct {
-	Height  uint32   `json:"height"`
-	Size    uint32   `json:"size"`
-	Value   float64  `json:"value"`
-	ValAvg  float64  `json:"valavg"`
-	Winners []string `json:"winners"`
+	Height         uint32   `json:"height"`
+	Size           uint32   `json:"size"`
+	Value          float64  `json:"value"`
+	ValAvg         float64  `json:"valavg"`
+	ValWeightedAvg float64  `json:"valweightedavg"`
+	Winners        []string `json:"winners"`
 }
 
 // TicketPoolValsAndSizes models two arrays, one each for ticket values and
@@ -646,6 +1142,38 @@ type BlockSubsidies struct {
 	Total      int64  `json:"total,omitempty"`
 }
 
+// SubsidyReduction is the Number-th block subsidy reduction, at Height, with
+// the subsidies of a block with all votes from then on. Time is the time of
+// the block at Height, or its estimated time if Estimated is true.
+type SubsidyReduction struct {
+	Number     int64 `json:"number"`
+	Height     int64 `json:"height"`
+	Time       int64 `json:"time"`
+	Estimated  bool  `json:"estimated,omitempty"`
+	Work       int64 `json:"work_reward"`
+	Stake      int64 `json:"stake_reward"`
+	TotalStake int64 `json:"stake_reward_total"`
+	Tax        int64 `json:"project_subsidy"`
+	Total      int64 `json:"total"`
+}
+
+// SubsidySchedule is the block subsidy reduction schedule as of the best block
+// at Height. The next reduction's time is estimated from AvgBlockTime, the
+// mean block time in seconds over the last reduction interval of blocks. The
+// past reductions are in height order.
+type SubsidySchedule struct {
+	Height            int64               `json:"height"`
+	Time              int64               `json:"time"`
+	ReductionInterval int64               `json:"reduction_interval"`
+	MulSubsidy        int64               `json:"mul_subsidy"`
+	DivSubsidy        int64               `json:"div_subsidy"`
+	AvgBlockTime      float64             `json:"avg_block_time"`
+	Next              *SubsidyReduction   `json:"next"`
+	BlocksRemaining   int64               `json:"blocks_remaining"`
+	SecondsRemaining  int64               `json:"seconds_remaining"`
+	Reductions        []*SubsidyReduction `json:"reductions"`
+}
+
 // StakeDiff represents data about the evaluated stake difficulty and estimates
 type StakeDiff struct {
 	chainjson.GetStakeDifficultyResult
@@ -733,6 +1261,11 @@ type TicketInfo struct {
 	LotteryBlock     *TinyBlock `json:"lottery_block"`
 	Vote             *string    `json:"vote"`
 	Revocation       *string    `json:"revocation"`
+	// Spend is the vote or revocation spending the ticket, with its height
+	// and reward, if the ticket is spent.
+	Spend *dbtypes.TicketSpend `json:"spend,omitempty"`
+	// Commitments specify where the ticket's reward will pay out.
+	Commitments []*dbtypes.TicketCommitment `json:"commitments,omitempty"`
 }
 
 // TinyBlock is the hash and height of a block.
@@ -748,6 +1281,7 @@ type TicketPoolChartsData struct {
 	TimeChart    *dbtypes.PoolTicketsData `json:"time_chart"`
 	PriceChart   *dbtypes.PoolTicketsData `json:"price_chart"`
 	OutputsChart *dbtypes.PoolTicketsData `json:"outputs_chart"`
+	AgeChart     *dbtypes.PoolTicketsData `json:"age_chart"`
 	Mempool      *PriceCountTime          `json:"mempool"`
 }
 
@@ -765,6 +1299,125 @@ type PowerlessTickets struct {
 	Revoked []PowerlessTicket `json:"revoked"`
 }
 
+// MempoolShortSummary counts the transactions in mempool by type.
+type MempoolShortSummary struct {
+	Height     uint32  `json:"height"`
+	Time       int64   `json:"time"`
+	NumAll     int     `json:"num_all"`
+	NumRegular int     `json:"num_regular"`
+	NumTickets int     `json:"num_tickets"`
+	NumVotes   int     `json:"num_votes"`
+	NumRevokes int     `json:"num_revokes"`
+	TotalOut   float64 `json:"total"`
+	TotalSize  int32   `json:"size"`
+}
+
+// MempoolExpiring lists the mempool transactions that expire within Blocks
+// blocks of the best block at Height, soonest first.
+type MempoolExpiring struct {
+	Height       uint32               `json:"height"`
+	Time         int64                `json:"time"`
+	Blocks       int64                `json:"blocks"`
+	Transactions []*MempoolExpiringTx `json:"transactions"`
+}
+
+// MempoolExpiringTx is a mempool transaction that expires at ExpiryHeight.
+// BlocksToExpiry is the number of blocks, starting with the next block, that
+// may still include the transaction.
+type MempoolExpiringTx struct {
+	TxID           string  `json:"txid"`
+	Type           string  `json:"type"`
+	Time           int64   `json:"time"`
+	Size           int32   `json:"size"`
+	FeeRate        float64 `json:"fee_rate"`
+	ExpiryHeight   int64   `json:"expiry_height"`
+	BlocksToExpiry int64   `json:"blocks_to_expiry"`
+}
+
+// MempoolTxGraph lists the mempool transactions that spend the outputs of, or
+// have outputs spent by, other mempool transactions, lowest ancestor package
+// fee rate first. NumTransactions is the number of transactions in mempool.
+type MempoolTxGraph struct {
+	Height          int64                `json:"height"`
+	Hash            string               `json:"hash"`
+	NumTransactions int                  `json:"num_transactions"`
+	Transactions    []*MempoolTxPackages `json:"transactions"`
+}
+
+// MempoolTxPackages describes the dependencies of a mempool transaction.
+// Parents and Children are the mempool transactions it directly spends and is
+// spent by. The Ancestors package is the transaction with all of its mempool
+// ancestors, which must be mined with or before it, so a low ancestor package
+// fee rate explains a transaction left waiting despite its own fee rate. The
+// Descendants package is the transaction with all of its mempool descendants.
+// AncestorTxns and DescendantTxns are only set for a single transaction. Fees
+// are in DCR, and fee rates in DCR/kB.
+type MempoolTxPackages struct {
+	TxID           string              `json:"txid"`
+	Type           string              `json:"type"`
+	Size           int32               `json:"size"`
+	Fees           float64             `json:"fees"`
+	FeeRate        float64             `json:"fee_rate"`
+	Parents        []string            `json:"parents"`
+	Children       []string            `json:"children"`
+	Ancestors      MempoolPackageStats `json:"ancestors"`
+	Descendants    MempoolPackageStats `json:"descendants"`
+	AncestorTxns   []string            `json:"ancestor_txns,omitempty"`
+	DescendantTxns []string            `json:"descendant_txns,omitempty"`
+}
+
+// MempoolPackageStats are the totals of a package of mempool transactions.
+// Fees are in DCR, and the fee rate in DCR/kB.
+type MempoolPackageStats struct {
+	Count   int     `json:"count"`
+	Size    int32   `json:"size"`
+	Fees    float64 `json:"fees"`
+	FeeRate float64 `json:"fee_rate"`
+}
+
+// DailyExport describes the bulk export of the main chain blocks with times in
+// the UTC day Date (YYYY-MM-DD), i.e. in [Start, End) as UNIX times. Created
+// is the UNIX time of the export.
+type DailyExport struct {
+	Date    string             `json:"date"`
+	Start   int64              `json:"start"`
+	End     int64              `json:"end"`
+	Created int64              `json:"created"`
+	Files   []*DailyExportFile `json:"files"`
+}
+
+// DailyExportFile is a gzipped CSV file of a table's rows in a daily export.
+// Rows excludes the header, and Size and SHA256 are of the gzipped file.
+type DailyExportFile struct {
+	Table  string `json:"table"`
+	Name   string `json:"name"`
+	Rows   int64  `json:"rows"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// BlockStats are the totals for a range of blocks. Amounts are in DCR.
+type BlockStats struct {
+	Blocks       uint64  `json:"blocks"`
+	Transactions uint64  `json:"transactions"`
+	Bytes        uint64  `json:"bytes"`
+	Fees         float64 `json:"fees"`
+	NewCoins     float64 `json:"new_coins"`
+}
+
+// HomeSummary combines the data shown by the explorer's home page widgets.
+// Amounts are in DCR.
+type HomeSummary struct {
+	Block           *BlockDataBasic      `json:"block"`
+	Mempool         *MempoolShortSummary `json:"mempool"`
+	TicketPrice     float64              `json:"ticket_price"`
+	NextTicketPrice float64              `json:"next_ticket_price"`
+	PoolSize        uint32               `json:"pool_size"`
+	PoolValue       float64              `json:"pool_value"`
+	DevFundBalance  float64              `json:"dev_fund_balance"`
+	Last24Hours     *BlockStats          `json:"last_24h"`
+}
+
 // PriceCountTime is a basic set of information about ticket in the mempool.
 type PriceCountTime struct {
 	Price float64         `json:"price"`
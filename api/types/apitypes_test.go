@@ -1,8 +1,11 @@
 package types
 
 import (
+	"encoding/json"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestScriptClass_String(t *testing.T) {
@@ -188,3 +191,44 @@ func TestTimeAPI_MarshalUnmarshalJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestTxBlockStatus(t *testing.T) {
+	tests := []struct {
+		testName    string
+		isMainchain bool
+		isValid     bool
+		want        string
+	}{
+		{"mainchain", true, true, TxBlockMainchain},
+		{"invalidated", true, false, TxBlockInvalidated},
+		{"sidechain", false, true, TxBlockSidechain},
+		{"invalid sidechain", false, false, TxBlockSidechain},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			if got := TxBlockStatus(tt.isMainchain, tt.isValid); got != tt.want {
+				t.Errorf("TxBlockStatus(%v, %v) = %v, want %v", tt.isMainchain,
+					tt.isValid, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCacheStats_SetUpdated(t *testing.T) {
+	var cs CacheStats
+	cs.SetUpdated(time.Time{})
+	b, err := json.Marshal(&cs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), `"updated":0,"age_seconds":null`) {
+		t.Errorf("never updated cache marshaled as %s", b)
+	}
+
+	updated := time.Now().Add(-time.Minute)
+	cs.SetUpdated(updated)
+	if cs.Updated != updated.Unix() || cs.AgeSeconds == nil || *cs.AgeSeconds < 60 {
+		t.Errorf("unexpected updated %d and age %v", cs.Updated, cs.AgeSeconds)
+	}
+}
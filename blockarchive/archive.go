@@ -0,0 +1,249 @@
+// Copyright (c) 2020, The Decred developers
+// See LICENSE for details.
+
+// Package blockarchive stores serialized blocks on disk as they are synced, so
+// that raw blocks and block headers may be served without node RPCs.
+package blockarchive
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/wire"
+)
+
+// ErrNotFound is returned for blocks that are not in the archive.
+var ErrNotFound = errors.New("block not in archive")
+
+// tmpSuffix is the suffix of a block file that is being written.
+const tmpSuffix = ".tmp"
+
+// Archive is a directory of serialized blocks. Each block is in a file named
+// by the block hash, in a subdirectory named by the last two characters of the
+// hash. When the retention is positive, only the blocks at heights within that
+// many blocks of the best main chain block are kept.
+type Archive struct {
+	dir    string
+	retain int64
+
+	mtx      sync.RWMutex
+	heights  map[chainhash.Hash]uint32
+	byHeight map[uint32][]chainhash.Hash
+	low      int64 // lowest height that may have blocks
+	best     int64 // best main chain height
+}
+
+// New opens the archive in dir, creating the directory if necessary, and
+// indexes the blocks already in it. A retain of zero keeps all blocks.
+func New(dir string, retain int64) (*Archive, error) {
+	if retain < 0 {
+		return nil, fmt.Errorf("invalid retention %d", retain)
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+
+	a := &Archive{
+		dir:      dir,
+		retain:   retain,
+		heights:  make(map[chainhash.Hash]uint32),
+		byHeight: make(map[uint32][]chainhash.Hash),
+		low:      -1,
+		best:     -1,
+	}
+
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		// Remove blocks that were not completely written.
+		if strings.HasSuffix(path, tmpSuffix) {
+			return os.Remove(path)
+		}
+		hash, err := chainhash.NewHashFromStr(info.Name())
+		if err != nil {
+			log.Warnf("Ignoring unrecognized file %s in block archive.", path)
+			return nil
+		}
+		header, err := readHeader(path)
+		if err != nil {
+			return fmt.Errorf("failed to read block %v header: %v", hash, err)
+		}
+		a.index(*hash, header.Height)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof("Block archive in %s has %d blocks.", dir, len(a.heights))
+	return a, nil
+}
+
+// Len returns the number of blocks in the archive.
+func (a *Archive) Len() int {
+	a.mtx.RLock()
+	defer a.mtx.RUnlock()
+	return len(a.heights)
+}
+
+// index records the block's height. The caller must hold the mutex for
+// writing, unless the archive is not yet shared.
+func (a *Archive) index(hash chainhash.Hash, height uint32) {
+	if _, found := a.heights[hash]; found {
+		return
+	}
+	a.heights[hash] = height
+	a.byHeight[height] = append(a.byHeight[height], hash)
+	if a.low < 0 || int64(height) < a.low {
+		a.low = int64(height)
+	}
+}
+
+// path returns the location of the block file for the hash.
+func (a *Archive) path(hash string) string {
+	return filepath.Join(a.dir, hash[len(hash)-2:], hash)
+}
+
+// Add writes the serialized block to the archive. When the block is on the
+// main chain, it is the new best block, and blocks that are no longer within
+// the retention are removed.
+func (a *Archive) Add(msgBlock *wire.MsgBlock, isMainchain bool) error {
+	hash := msgBlock.BlockHash()
+	height := msgBlock.Header.Height
+
+	a.mtx.RLock()
+	_, found := a.heights[hash]
+	a.mtx.RUnlock()
+
+	if !found {
+		var buf bytes.Buffer
+		buf.Grow(msgBlock.SerializeSize())
+		if err := msgBlock.Serialize(&buf); err != nil {
+			return err
+		}
+
+		// Write to a temporary file that is renamed when complete so that
+		// only whole blocks are indexed.
+		path := a.path(hash.String())
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(path+tmpSuffix, buf.Bytes(), 0600); err != nil {
+			return err
+		}
+		if err := os.Rename(path+tmpSuffix, path); err != nil {
+			return err
+		}
+	}
+
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	a.index(hash, height)
+	if isMainchain && int64(height) > a.best {
+		a.best = int64(height)
+		return a.prune()
+	}
+	return nil
+}
+
+// prune removes the blocks below the retention window. The caller must hold
+// the mutex for writing.
+func (a *Archive) prune() error {
+	if a.retain == 0 || a.low < 0 {
+		return nil
+	}
+	for ; a.low <= a.best-a.retain; a.low++ {
+		height := uint32(a.low)
+		for _, hash := range a.byHeight[height] {
+			err := os.Remove(a.path(hash.String()))
+			if err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			delete(a.heights, hash)
+		}
+		delete(a.byHeight, height)
+	}
+	return nil
+}
+
+// contains checks that the block is in the archive.
+func (a *Archive) contains(hash string) (string, error) {
+	blockHash, err := chainhash.NewHashFromStr(hash)
+	if err != nil {
+		return "", err
+	}
+	a.mtx.RLock()
+	_, found := a.heights[*blockHash]
+	a.mtx.RUnlock()
+	if !found {
+		return "", ErrNotFound
+	}
+	return a.path(blockHash.String()), nil
+}
+
+// RawBlock returns the serialized block and its height. ErrNotFound is
+// returned if the block is not in the archive.
+func (a *Archive) RawBlock(hash string) ([]byte, uint32, error) {
+	path, err := a.contains(hash)
+	if err != nil {
+		return nil, 0, err
+	}
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		// Pruned since the index was checked.
+		return nil, 0, ErrNotFound
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(b) < wire.MaxBlockHeaderPayload {
+		return nil, 0, fmt.Errorf("block file %s is truncated", path)
+	}
+	var header wire.BlockHeader
+	if err = header.FromBytes(b[:wire.MaxBlockHeaderPayload]); err != nil {
+		return nil, 0, err
+	}
+	return b, header.Height, nil
+}
+
+// Header returns the header of the block. ErrNotFound is returned if the block
+// is not in the archive.
+func (a *Archive) Header(hash string) (*wire.BlockHeader, error) {
+	path, err := a.contains(hash)
+	if err != nil {
+		return nil, err
+	}
+	header, err := readHeader(path)
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+	return header, err
+}
+
+// readHeader reads the header at the start of a block file.
+func readHeader(path string) (*wire.BlockHeader, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	b := make([]byte, wire.MaxBlockHeaderPayload)
+	if _, err = io.ReadFull(f, b); err != nil {
+		return nil, err
+	}
+	header := new(wire.BlockHeader)
+	if err = header.FromBytes(b); err != nil {
+		return nil, err
+	}
+	return header, nil
+}
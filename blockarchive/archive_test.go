@@ -0,0 +1,102 @@
+package blockarchive
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/wire"
+)
+
+func testBlock(height uint32, nonce uint32) *wire.MsgBlock {
+	return &wire.MsgBlock{
+		Header: wire.BlockHeader{
+			Version:   7,
+			Height:    height,
+			Nonce:     nonce,
+			Timestamp: time.Unix(1580000000+int64(height)*300, 0),
+		},
+	}
+}
+
+func TestArchive(t *testing.T) {
+	dir, err := ioutil.TempDir("", "blockarchive")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// Keep the 3 most recent blocks.
+	a, err := New(dir, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var blocks []*wire.MsgBlock
+	for h := uint32(0); h < 5; h++ {
+		blocks = append(blocks, testBlock(h, 0))
+	}
+	sideBlock := testBlock(3, 1)
+
+	for _, b := range blocks[:4] {
+		if err = a.Add(b, true); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = a.Add(sideBlock, false); err != nil {
+		t.Fatal(err)
+	}
+	if a.Len() != 4 {
+		t.Errorf("got %d blocks, wanted 4", a.Len())
+	}
+
+	// The side chain block is retained while in the window.
+	hash := sideBlock.BlockHash().String()
+	raw, height, err := a.RawBlock(hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want bytes.Buffer
+	_ = sideBlock.Serialize(&want)
+	if height != 3 || !bytes.Equal(raw, want.Bytes()) {
+		t.Errorf("wrong raw block at height %d", height)
+	}
+	header, err := a.Header(hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if header.BlockHash() != sideBlock.BlockHash() {
+		t.Errorf("wrong header %v", header.BlockHash())
+	}
+
+	// Blocks below the window are pruned.
+	if _, _, err = a.RawBlock(blocks[0].BlockHash().String()); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound for pruned block, got %v", err)
+	}
+	if err = a.Add(blocks[4], true); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = a.Header(blocks[1].BlockHash().String()); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound for pruned block, got %v", err)
+	}
+
+	// Reopening indexes the blocks on disk.
+	a, err = New(dir, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Len() != 4 {
+		t.Errorf("got %d blocks after reopening, wanted 4", a.Len())
+	}
+	for _, b := range append(blocks[2:], sideBlock) {
+		if _, _, err = a.RawBlock(b.BlockHash().String()); err != nil {
+			t.Errorf("block %v not found after reopening: %v", b.BlockHash(), err)
+		}
+	}
+
+	if _, _, err = a.RawBlock("zz"); err == nil {
+		t.Errorf("expected an error for an invalid hash")
+	}
+}
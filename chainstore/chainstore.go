@@ -0,0 +1,209 @@
+// Copyright (c) 2020, The Decred developers
+// See LICENSE for details.
+
+// Package chainstore defines the ChainStore interface through which the
+// explorer and API packages access stored chain data. *dcrpg.ChainDB is the
+// PostgreSQL implementation. Alternative backends, or mock stores in handler
+// tests, need only satisfy ChainStore.
+package chainstore
+
+import (
+	"context"
+	"time"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/chaincfg/v2"
+	chainjson "github.com/decred/dcrd/rpc/jsonrpc/types/v2"
+	"github.com/decred/dcrd/wire"
+	apitypes "github.com/decred/dcrdata/api/types/v5"
+	"github.com/decred/dcrdata/db/dbtypes/v2"
+	exptypes "github.com/decred/dcrdata/explorer/types/v2"
+	"github.com/decred/dcrdata/txhelpers/v4"
+)
+
+// ChainStore is the set of chain data retrieval and transaction relay methods
+// used by the explorer, API and Insight API packages.
+type ChainStore interface {
+	// Chain tip, blocks and headers.
+	BlockHeight(ctx context.Context, hash string) (int64, error)
+	Height() int64
+	HeightDB(ctx context.Context) (int64, error)
+	BlockHash(ctx context.Context, height int64) (string, error)
+	GetBlockHeight(ctx context.Context, hash string) (int64, error)
+	GetBlockHash(ctx context.Context, idx int64) (string, error)
+	BlockHeightAtTime(ctx context.Context, t int64, after bool) (int64, error)
+	GetHeight() (int64, error)
+	GetBestBlockHash() (string, error)
+	GetChainParams() *chaincfg.Params
+	GetBlockByHash(string) (*wire.MsgBlock, error)
+	GetBlockVerboseByHash(hash string, verboseTx bool) *chainjson.GetBlockVerboseResult
+	GetHeader(idx int) *chainjson.GetBlockHeaderVerboseResult
+	GetBlockHeaderByHash(hash string) (*wire.BlockHeader, error)
+	BlockHeaders(ctx context.Context, fromHeight int64, count int) ([]*wire.BlockHeader, error)
+	BlockFull(ctx context.Context, hash string) (*apitypes.BlockFull, error)
+	GetExplorerBlock(hash string) *exptypes.BlockInfo
+	GetExplorerBlocks(start int, end int) []*exptypes.BlockBasic
+	GetExplorerFullBlocks(start int, end int) []*exptypes.BlockInfo
+	GetTip() (*exptypes.WebBasicBlock, error)
+	BlockStatus(ctx context.Context, hash string) (dbtypes.BlockStatus, error)
+	BlockFlags(ctx context.Context, hash string) (bool, bool, error)
+	BlockStakeHeader(ctx context.Context, hash string) (*dbtypes.BlockStakeHeader, error)
+	BlockChainWork(ctx context.Context, hash string) (*dbtypes.BlockChainWork, error)
+	ChainTipsChainWork(ctx context.Context) ([]*dbtypes.BlockChainWork, error)
+	SideChainBlocks(ctx context.Context) ([]*dbtypes.BlockStatus, error)
+	DisapprovedBlocks(ctx context.Context) ([]*dbtypes.BlockStatus, error)
+	BlockMissedVotes(ctx context.Context, blockHash string) ([]string, error)
+	VotesInBlock(ctx context.Context, hash string) (int16, error)
+	BlockSubsidy(height int64, voters uint16) *chainjson.GetBlockSubsidyResult
+	BlockTimeByHeight(ctx context.Context, height int64) (int64, error)
+	BlockTimesByHeights(ctx context.Context, heights []int64) (map[int64]int64, error)
+	GetSummary(idx int) *apitypes.BlockDataBasic
+	GetSummaryRange(idx0, idx1 int) []*apitypes.BlockDataBasic
+	GetSummaryRangeStepped(idx0, idx1, step int) []*apitypes.BlockDataBasic
+	BlockSummariesBefore(ctx context.Context, before, limit int64) ([]*apitypes.BlockDataBasic, error)
+	GetSummaryByHash(hash string, withTxTotals bool) *apitypes.BlockDataBasic
+	GetBestBlockSummary() *apitypes.BlockDataBasic
+	BlockSummaryTimeRange(ctx context.Context, min, max int64, limit int) ([]dbtypes.BlockDataBasic, error)
+	GetBlockSize(idx int) (int32, error)
+	GetBlockSizeRange(idx0, idx1 int) ([]int32, error)
+	GetTransactionsForBlockByHash(hash string) *apitypes.BlockTransactions
+	PosIntervals(ctx context.Context, limit, offset uint64) ([]*dbtypes.BlocksGroupedInfo, error)
+	TimeBasedIntervals(ctx context.Context, timeGrouping dbtypes.TimeBasedGrouping, limit, offset uint64) (
+		[]*dbtypes.BlocksGroupedInfo, error)
+	CurrentDifficulty() (float64, error)
+	Difficulty(timestamp int64) float64
+	CurrentCoinSupply() *apitypes.CoinSupply
+	NodeDegraded() bool
+
+	// Transactions and outputs.
+	Transaction(ctx context.Context, txHash string) ([]*dbtypes.Tx, error)
+	TransactionBlocks(ctx context.Context, hash string) ([]*dbtypes.BlockStatus, []uint32, error)
+	TxHeight(txid *chainhash.Hash) (height int64)
+	VinsForTx(context.Context, *dbtypes.Tx) (
+		vins []dbtypes.VinTxProperty, prevPkScripts []string, scriptVersions []uint16, err error)
+	VoutsForTx(context.Context, *dbtypes.Tx) ([]dbtypes.Vout, error)
+	GetExplorerTx(ctx context.Context, txid string) *exptypes.TxInfo
+	GetRawTransaction(txid *chainhash.Hash) (*chainjson.TxRawResult, error)
+	GetRawAPITransaction(txid *chainhash.Hash) *apitypes.Tx
+	GetTransactionHex(txid *chainhash.Hash) string
+	GetTrimmedTransaction(txid *chainhash.Hash) *apitypes.TrimmedTx
+	GetAllTxIn(txid *chainhash.Hash) []*apitypes.TxIn
+	GetAllTxOut(txid *chainhash.Hash) []*apitypes.TxOut
+	MainchainTxInputs(ctx context.Context, txid string) (*dbtypes.TxInputsSummary, error)
+	SpendingTransaction(ctx context.Context, fundingTx string, vout uint32) (string, uint32, int8, error)
+	SpendingTransactions(ctx context.Context, fundingTxID string) ([]string, []uint32, []uint32, error)
+	SpendDetailsForFundingTx(ctx context.Context, fundHash string) ([]*apitypes.SpendByFundingHash, error)
+	AddressIDsByOutpoint(ctx context.Context, txHash string, voutIndex uint32) ([]uint64, []string, int64, error)
+	DuplicateTransactions(ctx context.Context, N, offset int64) ([]*apitypes.TxDuplicate, error)
+	NullDataByPrefix(ctx context.Context, prefix []byte, N, offset int64) (
+		[]*dbtypes.NullDataOutput, error)
+	ScriptAnomalies(ctx context.Context, from, to int64) (*dbtypes.ScriptAnomalyReport, error)
+	BlockAnomalies(ctx context.Context, from, to int64, kind string) ([]*dbtypes.BlockAnomaly, error)
+	VinsBySigType(ctx context.Context, from, to int64, sigType dbtypes.VinSigType) ([]*dbtypes.SigTypeVin, error)
+	DecodeRawTransaction(txhex string) (*chainjson.TxRawResult, error)
+	ValidateRawTransaction(ctx context.Context, txhex string) (*apitypes.TxValidation, error)
+	SendRawTransaction(txhex string) (string, error)
+
+	// Addresses.
+	AddressHistory(ctx context.Context, address string, N, offset int64, txnType dbtypes.AddrTxnViewType) (
+		[]*dbtypes.AddressRow, *dbtypes.AddressBalance, error)
+	AddressData(ctx context.Context, address string, N, offset int64, txnType dbtypes.AddrTxnViewType) (
+		*dbtypes.AddressInfo, error)
+	FillAddressTransactions(ctx context.Context, addrInfo *dbtypes.AddressInfo) error
+	GetExplorerAddress(address string, count, offset int64) (
+		*dbtypes.AddressInfo, txhelpers.AddressType, txhelpers.AddressError)
+	AddressBalance(ctx context.Context, address string) (bal *dbtypes.AddressBalance, cacheUpdated bool, err error)
+	AddressBalanceAt(ctx context.Context, address string, height int64) (
+		*dbtypes.HistoricalAddressBalance, error)
+	AddressBalanceAtTime(ctx context.Context, address string, t int64) (
+		*dbtypes.HistoricalAddressBalance, error)
+	AddressSummary(ctx context.Context, address string) (*dbtypes.AddressSummary, error)
+	AddressTotals(ctx context.Context, address string) (*apitypes.AddressTotals, error)
+	AddressTxCounts(ctx context.Context, address string) (*apitypes.AddressTxCounts, error)
+	AddressTransactionDetails(ctx context.Context, addr string, count, skip int64, txnType dbtypes.AddrTxnViewType) (
+		*apitypes.Address, *dbtypes.PageCursor, error)
+	AddressTransactionDetailsByTime(ctx context.Context, addr string, from, to time.Time, count, skip int64,
+		after *dbtypes.PageCursor) (*apitypes.Address, *dbtypes.PageCursor, error)
+	AddressTxIoCsv(ctx context.Context, address string) ([][]string, error)
+	AddressUTXO(ctx context.Context, address string) ([]*dbtypes.AddressTxnOutput, bool, error)
+	AddressUTXOs(ctx context.Context, address string, N, offset int64, sortBy string) (
+		*apitypes.AddressUTXOs, error)
+	GetAddressTransactionsRawWithSkip(addr string, count, skip int) []*apitypes.AddressTxRaw
+	InsightAddressTransactions(ctx context.Context, addr []string, recentBlockHeight int64) (
+		txs, recentTxs []chainhash.Hash, err error)
+	TxHistoryData(ctx context.Context, address string, addrChart dbtypes.HistoryChart, chartGroupings dbtypes.TimeBasedGrouping) (
+		*dbtypes.ChartsData, error)
+	DevBalance() (*dbtypes.AddressBalance, error)
+	CoinbaseBlocksByAddress(ctx context.Context, address string, N, offset int64, after *dbtypes.PageCursor) (
+		[]*dbtypes.CoinbaseBlock, *dbtypes.PageCursor, error)
+
+	// Tickets, votes and staking.
+	PoolStatusForTicket(ctx context.Context, txid string) (dbtypes.TicketSpendType, dbtypes.TicketPoolStatus, error)
+	TicketMiss(ctx context.Context, ticketHash string) (string, int64, error)
+	TicketPoolVisualization(ctx context.Context, interval dbtypes.TimeBasedGrouping) (
+		*dbtypes.PoolTicketsData, *dbtypes.PoolTicketsData, *dbtypes.PoolTicketsData, *dbtypes.PoolTicketsData, int64, error)
+	GetTicketInfo(ctx context.Context, txid string) (*apitypes.TicketInfo, error)
+	TicketCommitments(ctx context.Context, txid string) ([]*dbtypes.TicketCommitment, error)
+	TicketsByRewardAddress(ctx context.Context, address string, N, offset int64, after *dbtypes.PageCursor) (
+		[]*dbtypes.RewardTicket, *dbtypes.PageCursor, error)
+	RevocableTickets(ctx context.Context, address string, N, offset int64, after *dbtypes.PageCursor) (
+		*dbtypes.RevocableTickets, *dbtypes.PageCursor, error)
+	StakingPosition(ctx context.Context, address string) (*dbtypes.StakingPosition, error)
+	TicketLuck(ctx context.Context, txid string) (*dbtypes.TicketLuck, error)
+	TicketWinningBlocks(ctx context.Context, ticketHash string) ([]*dbtypes.TicketWinningBlock, error)
+	AddressTicketLuck(ctx context.Context, address string) (*dbtypes.AddressTicketLuck, error)
+	TicketVoteOdds(ctx context.Context, txids []string, numBlocks int64) (
+		*dbtypes.TicketVoteOdds, error)
+	PowerlessTickets(ctx context.Context) (*apitypes.PowerlessTickets, error)
+	GetStakeInfoExtendedByHash(hash string) *apitypes.StakeInfoExtended
+	GetStakeInfoExtendedByHeight(idx int) *apitypes.StakeInfoExtended
+	GetPoolInfo(idx int) *apitypes.TicketPoolInfo
+	GetPoolInfoByHash(hash string) *apitypes.TicketPoolInfo
+	GetPoolInfoRange(idx0, idx1 int) []apitypes.TicketPoolInfo
+	GetPoolValAndSizeRange(idx0, idx1 int) ([]float64, []uint32)
+	GetPool(idx int64) ([]string, error)
+	GetWinners(ctx context.Context, idx int64) ([]string, error)
+	GetWinnersByHash(ctx context.Context, hash string) ([]string, error)
+	GetVoteInfo(txid *chainhash.Hash) (*apitypes.VoteInfo, error)
+	Vote(ctx context.Context, txHash string) (*apitypes.Vote, error)
+	GetVoteVersionInfo(ver uint32) (*chainjson.GetVoteInfoResult, error)
+	GetStakeVersionsLatest() (*chainjson.StakeVersions, error)
+	GetStakeDiffEstimates() *apitypes.StakeDiff
+	StakeDiffEstimateAccuracy(ctx context.Context, N, offset int64) (
+		[]*dbtypes.StakeDiffEstimateAccuracy, error)
+	StakeDiffWindowsOHLC(ctx context.Context, N, offset int64) (
+		[]*dbtypes.StakeDiffWindowOHLC, error)
+	TicketDemandHistory(ctx context.Context) ([]dbtypes.BlockTicketDemand, error)
+	GetSDiff(idx int) float64
+	GetSDiffRange(idx0, idx1 int) []float64
+	MinerShares(ctx context.Context, numBlocks int64) (*dbtypes.MinerShares, error)
+
+	// Agendas and proposals.
+	AgendasVotesSummary(ctx context.Context, agendaID string) (summary *dbtypes.AgendaSummary, err error)
+	AgendaVotes(ctx context.Context, agendaID string, chartType int) (*dbtypes.AgendaVoteChoices, error)
+	AllAgendas() (map[string]dbtypes.MileStone, error)
+	AgendaStatus(ctx context.Context, agendaID string) (*apitypes.AgendaStatus, error)
+	AgendaVotesByTicketType(ctx context.Context, agendaID string) (*apitypes.AgendaTicketTypeVotes, error)
+	AgendaVoteTimeline(ctx context.Context, agendaID string, interval int64) (*apitypes.AgendaVoteTimeline, error)
+	ProposalVotes(ctx context.Context, proposalToken string) (*dbtypes.ProposalChartsData, error)
+	LastPiParserSync() time.Time
+
+	// Mempool.
+	GetMempoolSSTxSummary() *apitypes.MempoolTicketFeeInfo
+	GetMempoolShortSummary() *apitypes.MempoolShortSummary
+	GetMempoolExpiring(blocks int64) *apitypes.MempoolExpiring
+	GetMempoolSSTxFeeRates(N int) *apitypes.MempoolTicketFees
+	GetMempoolSSTxDetails(N int) *apitypes.MempoolTicketDetails
+	GetMempoolPriceCountTime() *apitypes.PriceCountTime
+	NextBlockPreview() *exptypes.NextBlockPreview
+
+	// Prices, distributions and database administration.
+	DailyPrices(ctx context.Context, currency string, since time.Time) ([]*dbtypes.DailyPrice, error)
+	UTXODistribution(ctx context.Context) (*dbtypes.UTXODistribution, error)
+	UTXODistributions(ctx context.Context, since time.Time) ([]*dbtypes.UTXODistribution, error)
+	UpgradeProgress(ctx context.Context) (*apitypes.UpgradeProgress, error)
+	CacheStats() []*apitypes.CacheStats
+	HeightNtfnStats() *apitypes.HeightNtfnStats
+	FlushCaches(names []string) ([]*apitypes.CacheFlush, error)
+	DBStats(ctx context.Context) (*apitypes.DBStats, error)
+}
@@ -5,6 +5,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"net/http"
@@ -197,7 +198,7 @@ func mainCore() error {
 	signal.Notify(c, os.Interrupt)
 
 	// Check current height of DB
-	lastBlock, err := db.HeightDB()
+	lastBlock, err := db.HeightDB(context.Background())
 	if err != nil {
 		log.Errorln("RetrieveBestBlockHeight:", err)
 		return err
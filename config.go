@@ -15,6 +15,7 @@ import (
 	"regexp"
 	"runtime"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -23,6 +24,8 @@ import (
 	"github.com/decred/dcrd/dcrutil/v2"
 	"github.com/decred/dcrd/wire"
 	"github.com/decred/dcrdata/db/dbtypes/v2"
+	"github.com/decred/dcrdata/db/dcrpg/v5"
+	"github.com/decred/dcrdata/v5/maintenance"
 	"github.com/decred/dcrdata/v5/netparams"
 	"github.com/decred/dcrdata/v5/version"
 	"github.com/decred/slog"
@@ -55,11 +58,20 @@ var (
 	defaultMainnetPort         = "7777"
 	defaultTestnetPort         = "17778"
 	defaultSimnetPort          = "17779"
+	defaultGRPCPort            = "7780"
 	defaultIndentJSON          = "   "
 	defaultCacheControlMaxAge  = 86400
 	defaultInsightReqRateLimit = 20.0
 	defaultMaxCSVAddrs         = 25
 	defaultServerHeader        = "dcrdata"
+	defaultRateLimitKeyFactor  = 10.0
+	defaultAPICacheDir         = "apicache"
+	defaultAPICacheMaxEntries  = 500
+	defaultUsageWindow         = time.Hour
+
+	defaultAnomalyMinVotes      = 3
+	defaultAnomalyMaxBlockSize  = 250000
+	defaultAnomalyMaxReorgDepth = 2
 
 	defaultMempoolMinInterval = 2
 	defaultMempoolMaxInterval = 120
@@ -69,6 +81,10 @@ var (
 	defaultProposalsFileName = "proposals.db"
 	defaultPoliteiaAPIURl    = "https://proposals.decred.org"
 	defaultChartsCacheDump   = "chartscache.gob"
+	defaultMempoolFile       = "mempool.gob"
+	defaultEmailDigest       = time.Hour
+	defaultChartsBlockSpan   = 7 * 24 * time.Hour
+	defaultChartsDaySpan     = 365 * 24 * time.Hour
 
 	defaultPGHost           = "127.0.0.1:5432"
 	defaultPGUser           = "dcrdata"
@@ -78,6 +94,7 @@ var (
 	defaultAddrCacheCap     = 1 << 28 // 256 MiB
 	defaultAddrCacheLimit   = 2048
 	defaultAddrCacheUXTOCap = 1 << 28
+	defaultAddrSpendBatch   = 10000
 
 	defaultExchangeIndex     = "USD"
 	defaultDisabledExchanges = "huobi,dragonex"
@@ -87,9 +104,32 @@ var (
 	defaultTestnetLink  = "https://testnet.dcrdata.org/"
 	defaultOnionAddress = ""
 
+	defaultFeedTxMinValue = 1000.0
+
+	defaultKafkaTopicPrefix = "dcrdata."
+
+	// unixListenPrefix prefixes an apilisten unix socket path.
+	unixListenPrefix = "unix:"
+
 	maxSyncStatusLimit = 5000
 )
 
+// maintenanceTasks lists the periodic maintenance tasks in the order they are
+// added to the scheduler.
+var maintenanceTasks = []string{"analyze", "vacuum", "devbalance", "charts", "mempool",
+	"mempoolsave", "utxodist"}
+
+// defaultMaintSchedules are the default schedules of the maintenance tasks.
+var defaultMaintSchedules = map[string]string{
+	"analyze":     "30 4 * * *",
+	"vacuum":      "0 5 * * 0",
+	"devbalance":  "@every 10m",
+	"charts":      "@every 1h",
+	"mempool":     "@every 15m",
+	"mempoolsave": "@every 5m",
+	"utxodist":    "15 0 * * *",
+}
+
 type config struct {
 	// General application behavior
 	HomeDir      string `short:"A" long:"appdata" description:"Path to application home directory" env:"DCRDATA_APPDATA_DIR"`
@@ -111,7 +151,7 @@ type config struct {
 
 	// API
 	APIProto            string  `long:"apiproto" description:"Protocol for API (http or https)" env:"DCRDATA_ENABLE_HTTPS"`
-	APIListen           string  `long:"apilisten" description:"Listen address for API. default localhost:7777, :17778 testnet, :17779 simnet" env:"DCRDATA_LISTEN_URL"`
+	APIListen           string  `long:"apilisten" description:"Listen address for API. default localhost:7777, :17778 testnet, :17779 simnet. A unix socket path may be given as unix:/path/to/socket." env:"DCRDATA_LISTEN_URL"`
 	IndentJSON          string  `long:"indentjson" description:"String for JSON indentation (default is \"   \"), when indentation is requested via URL query."`
 	UseRealIP           bool    `long:"userealip" description:"Use the RealIP middleware from the pressly/chi/middleware package to get the client's real IP from the X-Forwarded-For or X-Real-IP headers, in that order." env:"DCRDATA_USE_REAL_IP"`
 	CacheControlMaxAge  int     `long:"cachecontrol-maxage" description:"Set CacheControl in the HTTP response header to a value in seconds for clients to cache the response. This applies only to FileServer routes." env:"DCRDATA_MAX_CACHE_AGE"`
@@ -119,6 +159,20 @@ type config struct {
 	MaxCSVAddrs         int     `long:"max-api-addrs" description:"Maximum allowed comma-separated addresses for endpoints that accept multiple addresses."`
 	CompressAPI         bool    `long:"compress-api" description:"Use compression for a number of endpoints with commonly large responses."`
 	ServerHeader        string  `long:"server-http-header" description:"Set the HTTP response header Server key value. Valid values are \"off\", \"version\", or a custom string."`
+	AdminToken          string  `long:"admin-token" description:"Bearer token required by the /api/admin endpoints for inspecting and flushing the internal caches. The admin endpoints are disabled if empty." env:"DCRDATA_ADMIN_TOKEN"`
+
+	RateLimitRPS            float64  `long:"ratelimit-rps" description:"Requests/second per client for the API, websocket and gRPC rate limiter. Rate limiting is disabled if 0." env:"DCRDATA_RATE_LIMIT"`
+	RateLimitBurst          int      `long:"ratelimit-burst" description:"Maximum burst of requests per client for the API and websocket rate limiter." env:"DCRDATA_RATE_LIMIT_BURST"`
+	RateLimitExpensiveRPS   float64  `long:"ratelimit-expensive-rps" description:"Requests/second per client for expensive API routes such as address history and charts, in addition to ratelimit-rps. No separate limit is applied if 0." env:"DCRDATA_RATE_LIMIT_EXPENSIVE"`
+	RateLimitExpensiveBurst int      `long:"ratelimit-expensive-burst" description:"Maximum burst of requests per client for expensive API routes." env:"DCRDATA_RATE_LIMIT_EXPENSIVE_BURST"`
+	RateLimitKeys           []string `long:"ratelimit-key" description:"API key that clients may send in the X-API-Key header to be rate limited per key rather than per IP. May be given multiple times."`
+	RateLimitKeyFactor      float64  `long:"ratelimit-key-factor" description:"Multiplier for the rate limiter budgets of clients with a valid API key." env:"DCRDATA_RATE_LIMIT_KEY_FACTOR"`
+
+	APICache           bool `long:"api-cache" description:"Store the responses of expensive API routes, such as charts and the ticket pool, on disk in the data directory, and serve them until they are refreshed after the next block." env:"DCRDATA_API_CACHE"`
+	APICacheMaxEntries int  `long:"api-cache-max-entries" description:"Maximum number of distinct requests with cached responses when api-cache is set. Not limited if 0."`
+
+	UsageStats  bool          `long:"usage-stats" description:"Count the API requests, errors, response sizes and latencies by route and by client (API key or IP, or anonymous in privacy mode) for the /api/admin/usage endpoint." env:"DCRDATA_USAGE_STATS"`
+	UsageWindow time.Duration `long:"usage-window" description:"Rolling window over which the API usage is counted when usage-stats is set."`
 
 	// Data I/O
 	MempoolMinInterval int    `long:"mp-min-interval" description:"The minimum time in seconds between mempool reports, regardless of number of new tickets seen." env:"DCRDATA_MEMPOOL_MIN_INTERVAL"`
@@ -128,10 +182,14 @@ type config struct {
 	ProposalsFileName  string `long:"proposalsdbfile" description:"Proposals DB file name (default is proposals.db)." env:"DCRDATA_PROPOSALS_DB_FILE_NAME"`
 	PoliteiaAPIURL     string `long:"politeiaurl" description:"Defines the root API politeia URL (defaults to https://proposals.decred.org)."`
 	ChartsCacheDump    string `long:"chartscache" description:"Defines the file name that holds the charts cache data on system exit."`
+	MempoolFile        string `long:"mempoolfile" description:"Defines the file name that holds the mempool transactions, saved periodically and on system exit, so the times they were first seen survive restarts."`
 	PiPropRepoOwner    string `long:"piproposalsowner" description:"Defines the owner to the github repo where Politeia's proposals are pushed."`
 	PiPropRepoName     string `long:"piproposalsrepo" description:"Defines the name of the github repo where Politeia's proposals are pushed."`
 	DisablePiParser    bool   `long:"disable-piparser" description:"Disables the piparser tool from running."`
 
+	ChartsBlockSpan time.Duration `long:"charts-block-span" description:"Block size, fees, and transaction count charts requested by time span are served at block resolution if the span starts within this duration of the best block."`
+	ChartsDaySpan   time.Duration `long:"charts-day-span" description:"Block size, fees, and transaction count charts requested by time span are served at day resolution if the span starts within this duration of the best block, otherwise at week resolution. Must not be less than charts-block-span."`
+
 	PurgeNBestBlocks int `long:"purge-n-blocks" description:"Purge all data for the N best blocks, using the best block across all DBs if they are out of sync."`
 
 	FullMode         bool          `long:"pg" description:"Run in \"Full Mode\" mode,  enables postgresql support" env:"DCRDATA_ENABLE_FULL_MODE"`
@@ -145,11 +203,49 @@ type config struct {
 	AddrCacheLimit   int           `long:"addr-cache-address-limit" description:"Maximum number of addresses allowed in the address cache."`
 	AddrCacheUXTOCap int           `long:"addr-cache-utxo-cap" description:"UTXO cache capacity in bytes."`
 	DropIndexes      bool          `long:"drop-inds" short:"D" description:"Drop all table indexes and exit."`
+	HeightNtfnBuffer int           `long:"height-ntfn-buffer" description:"Capacity of the buffered channels used to notify subscribers of new block heights. Heights are coalesced when a subscriber falls further behind."`
+	PGNotifyChannel  string        `long:"pg-notify-channel" description:"PostgreSQL NOTIFY channel on which new block, reorg, block invalidation, and sync complete events are sent as JSON for external consumers to LISTEN. Disabled if empty."`
+
+	ArchiveSpentDepth    int64 `long:"archive-spent-depth" description:"Move the addresses and vouts table rows of outputs spent more than this many blocks below the best block to the archive schema, keeping the tables and indexes read by the balance and UTXO queries small. Must be at least 256. Disabled if 0. Not supported by CockroachDB."`
+	ArchiveSpentInterval int64 `long:"archive-spent-interval" description:"Number of blocks between moves of spent rows to the archive schema. 144 blocks if 0."`
+	UnarchiveSpent       bool  `long:"unarchive-spent" description:"Move the archived spent rows back to the addresses and vouts tables, and exit. Blocks cannot be reindexed while rows are archived. Requires archive-spent-depth=0."`
+
+	CheckAddrSpending  bool  `long:"check-addr-spending" description:"Scan the addresses table for missing or mismatched spending transaction links, report their counts by block range, and exit."`
+	RepairAddrSpending bool  `long:"repair-addr-spending" description:"Scan the addresses table for missing or mismatched spending transaction links, repair them, and exit."`
+	AddrSpendingStart  int64 `long:"addr-spending-start" description:"Spending block height at which to start check-addr-spending or repair-addr-spending, e.g. to resume an interrupted repair."`
+	AddrSpendingBatch  int64 `long:"addr-spending-batch" description:"Number of blocks checked or repaired in each batch by check-addr-spending and repair-addr-spending."`
+
+	AuditIntegrity bool `long:"audit-integrity" description:"Check the referential integrity of the blocks, transactions, vins, vouts and addresses tables, report the number of orphaned rows found by each check, and exit."`
+	PruneOrphans   bool `long:"prune-orphans" description:"Check the referential integrity of the blocks, transactions, vins, vouts and addresses tables, delete the orphaned rows or clear their dangling references, and exit."`
+
+	Reindex       string `long:"reindex" description:"Re-process the mainchain blocks in the height range START-END with duplicate checks to repair missing or mismatched blocks, after syncing and reporting the missing or mismatched blocks, and exit. Resume an interrupted reindex with a later START."`
+	ReindexDryRun bool   `long:"reindex-dry-run" description:"Only report the missing or mismatched blocks in the reindex range, and exit."`
+	reindexStart  int64
+	reindexEnd    int64
+
+	ExportSnapshot int64  `long:"export-snapshot" description:"Export the PostgreSQL data for the blocks up to and including this mainchain height to the snapshot-dir, and exit."`
+	SnapshotDir    string `long:"snapshot-dir" description:"Directory to which export-snapshot writes the chain snapshot."`
+	ImportSnapshot string `long:"import-snapshot" description:"Import the chain snapshot in this directory into an empty PostgreSQL database, and exit. Restart dcrdata without it to resume syncing from the snapshot height."`
+
+	BlockArchiveDir       string `long:"block-archive-dir" description:"Directory in which to archive serialized blocks as they are synced, so that the raw block and block header API endpoints do not need dcrd RPCs. Disabled if empty."`
+	BlockArchiveRetention int64  `long:"block-archive-retention" description:"Number of most recent blocks to keep in the block archive. All blocks are kept if 0."`
+
+	DailyExportDir string `long:"daily-export-dir" description:"Directory in which to write gzipped CSV files of each UTC day's main chain blocks, transactions and transaction outputs, which are listed and served by the /api/export/daily endpoints. Disabled if empty."`
+
+	KafkaBrokers     []string `long:"kafka-brokers" description:"Kafka broker address (host:port) to which JSON records of the stored blocks, transactions, outputs and address balance changes are published, both during sync and for new blocks. May be repeated, or comma-separated. Disabled if empty."`
+	KafkaTopicPrefix string   `long:"kafka-topic-prefix" description:"Prefix of the Kafka topic names. The topic of each table is the prefix and the table name (blocks, transactions, vouts or addresses), and the reorg and disapproval events go to the prefix and chain_events."`
+
+	GRPCListen string `long:"grpclisten" description:"Listen address (host:port) of the gRPC block, transaction, address and stake services. The host defaults to localhost and the port to 7780. Disabled if empty."`
+	GRPCCert   string `long:"grpccert" description:"TLS certificate file of the gRPC services. TLS is only used if both grpccert and grpckey are set."`
+	GRPCKey    string `long:"grpckey" description:"TLS key file of the gRPC services."`
 
 	NoDevPrefetch    bool `long:"no-dev-prefetch" description:"Disable automatic dev fund balance query on new blocks. When true, the query will still be run on demand, but not automatically after new blocks are connected." env:"DCRDATA_DISABLE_DEV_PREFETCH"`
 	SyncAndQuit      bool `long:"sync-and-quit" description:"Sync to the best block and exit. Do not start the explorer or API." env:"DCRDATA_ENABLE_SYNC_N_QUIT"`
 	ImportSideChains bool `long:"import-side-chains" description:"(experimental) Enable startup import of side chains retrieved from dcrd via getchaintips." env:"DCRDATA_IMPORT_SIDE_CHAINS"`
 
+	MaintSchedules []string `long:"maint" description:"Schedule of a maintenance task as task=spec, where spec is a 5-field cron expression (minute hour day-of-month month day-of-week), @every <duration>, @hourly, @daily, @weekly, @monthly, or off to disable the task. Tasks: analyze (30 4 * * *), vacuum (0 5 * * 0), devbalance (@every 10m), charts (@every 1h), mempool (@every 15m), mempoolsave (@every 5m), utxodist (15 0 * * *). May be repeated."`
+	maintSchedules map[string]string
+
 	SyncStatusLimit int `long:"sync-status-limit" description:"Sets the number of blocks behind the current best height past which only the syncing status page can be served on the running web server. Value should be greater than 2 but less than 5000."`
 
 	// WatchAddresses []string `short:"w" long:"watchaddress" description:"Watched address (receiving). One per line."`
@@ -174,10 +270,38 @@ type config struct {
 	RateMaster        string `long:"ratemaster" description:"The address of a DCRRates instance. Exchange monitoring will get all data from a DCRRates subscription." env:"DCRDATA_RATE_MASTER"`
 	RateCertificate   string `long:"ratecert" description:"File containing DCRRates TLS certificate file." env:"DCRDATA_RATE_MASTER"`
 
+	// Webhooks
+	Webhooks             []string `long:"webhook" description:"URL to which block invalidation events, naming the invalidated block and its reversed transactions, and agenda_status events, for agendas reaching quorum, locking in, activating, or failing, are POSTed as JSON. May be repeated."`
+	AlertScriptAnomalies bool     `long:"alert-script-anomalies" description:"Also POST the outputs of each new block with nonstandard scripts or script versions other than 0 to the webhooks as script_anomaly events."`
+
+	// Watched addresses
+	WatchAddresses       []string      `long:"watch-address" description:"Address whose activity in new main chain blocks is reported, as address, address:amount or address:amount:thresholds. Transfers of at least amount DCR are alerted instantly by e-mail rather than batched in the digests. The optional thresholds, e.g. above=5000,below=100,hysteresis=50,cooldown=24h, alert the address balance crossing above or below the DCR amounts to the webhooks as address_balance events and by e-mail. A threshold is not alerted again until the balance moves back past it by the hysteresis DCR, nor within the cooldown. May be repeated."`
+	AlertAddressActivity bool          `long:"alert-address-activity" description:"Also POST the activity of the watched addresses in each new main chain block to the webhooks as address_activity events."`
+	SMTPServer           string        `long:"smtp-server" description:"SMTP server, as host:port, through which the activity of the watched addresses is e-mailed. E-mail is disabled if empty."`
+	SMTPUser             string        `long:"smtp-user" description:"Username for PLAIN authentication with the SMTP server. No authentication is attempted if empty."`
+	SMTPPass             string        `long:"smtp-pass" description:"Password for PLAIN authentication with the SMTP server."`
+	EmailFrom            string        `long:"email-from" description:"Sender address of the watched address e-mails."`
+	EmailTo              []string      `long:"email-to" description:"Recipient address of the watched address e-mails. May be repeated."`
+	EmailDigest          time.Duration `long:"email-digest" description:"Interval between the e-mail digests of the watched address activity. Only the alerts are e-mailed if 0."`
+
+	// Block anomalies
+	AlertBlockAnomalies  bool   `long:"alert-block-anomalies" description:"Also POST the anomalies of each new main chain block, and deep reorgs, to the webhooks as block_anomaly events."`
+	NoAnomalyEmptyBlocks bool   `long:"no-anomaly-empty-blocks" description:"Do not flag main chain blocks without regular transactions as anomalous."`
+	AnomalyMinVotes      uint16 `long:"anomaly-min-votes" description:"Flag main chain blocks with fewer votes as anomalous. Disabled if 0."`
+	AnomalyMaxBlockSize  uint32 `long:"anomaly-max-block-size" description:"Flag main chain blocks larger than this many bytes as anomalous. Disabled if 0."`
+	AnomalyMaxReorgDepth int64  `long:"anomaly-max-reorg-depth" description:"Flag reorgs removing more than this many blocks from the main chain as anomalous. Disabled if 0."`
+
+	// Feeds
+	FeedTxMinValue float64 `long:"feedtxminvalue" description:"Minimum total output value, in DCR, of the transactions in the large transactions Atom/RSS feed." env:"DCRDATA_FEED_TX_MIN_VALUE"`
+	FeedBaseURL    string  `long:"feedbaseurl" description:"Public base URL of the explorer, such as https://explorer.dcrdata.org, for the links in the Atom/RSS feeds. The links are relative to the site root if empty." env:"DCRDATA_FEED_BASE_URL"`
+
 	// Links
 	MainnetLink  string `long:"mainnet-link" description:"When dcrdata is on testnet, this address will be used to direct a user to a dcrdata on mainnet when appropriate." env:"DCRDATA_MAINNET_LINK"`
 	TestnetLink  string `long:"testnet-link" description:"When dcrdata is on mainnet, this address will be used to direct a user to a dcrdata on testnet when appropriate." env:"DCRDATA_TESTNET_LINK"`
 	OnionAddress string `long:"onion-address" description:"Hidden service address" env:"DCRDATA_ONION_ADDRESS"`
+
+	// Privacy
+	Privacy bool `long:"privacy" description:"Privacy mode for public explorers such as onion services. Disables the exchange monitor and the politeia proposals and piparser, which make requests to external services, and omits the client address, host, and request URI from the HTTP request logs." env:"DCRDATA_PRIVACY"`
 }
 
 var (
@@ -191,6 +315,10 @@ var (
 		ProposalsFileName:   defaultProposalsFileName,
 		PoliteiaAPIURL:      defaultPoliteiaAPIURl,
 		ChartsCacheDump:     defaultChartsCacheDump,
+		MempoolFile:         defaultMempoolFile,
+		EmailDigest:         defaultEmailDigest,
+		ChartsBlockSpan:     defaultChartsBlockSpan,
+		ChartsDaySpan:       defaultChartsDaySpan,
 		DebugLevel:          defaultLogLevel,
 		HTTPProfPath:        defaultHTTPProfPath,
 		APIProto:            defaultAPIProto,
@@ -199,6 +327,9 @@ var (
 		InsightReqRateLimit: defaultInsightReqRateLimit,
 		MaxCSVAddrs:         defaultMaxCSVAddrs,
 		ServerHeader:        defaultServerHeader,
+		RateLimitKeyFactor:  defaultRateLimitKeyFactor,
+		APICacheMaxEntries:  defaultAPICacheMaxEntries,
+		UsageWindow:         defaultUsageWindow,
 		DcrdCert:            defaultDaemonRPCCertFile,
 		MempoolMinInterval:  defaultMempoolMinInterval,
 		MempoolMaxInterval:  defaultMempoolMaxInterval,
@@ -211,12 +342,20 @@ var (
 		AddrCacheCap:        defaultAddrCacheCap,
 		AddrCacheLimit:      defaultAddrCacheLimit,
 		AddrCacheUXTOCap:    defaultAddrCacheUXTOCap,
+		HeightNtfnBuffer:    dcrpg.DefaultHeightNtfnBuffer,
+		AddrSpendingBatch:   int64(defaultAddrSpendBatch),
 		ExchangeCurrency:    defaultExchangeIndex,
 		DisabledExchanges:   defaultDisabledExchanges,
 		RateCertificate:     defaultRateCertFile,
 		MainnetLink:         defaultMainnetLink,
 		TestnetLink:         defaultTestnetLink,
 		OnionAddress:        defaultOnionAddress,
+		FeedTxMinValue:      defaultFeedTxMinValue,
+		KafkaTopicPrefix:    defaultKafkaTopicPrefix,
+
+		AnomalyMinVotes:      uint16(defaultAnomalyMinVotes),
+		AnomalyMaxBlockSize:  uint32(defaultAnomalyMaxBlockSize),
+		AnomalyMaxReorgDepth: int64(defaultAnomalyMaxReorgDepth),
 	}
 )
 
@@ -546,6 +685,13 @@ func loadConfig() (*config, error) {
 		return loadConfigError(err)
 	}
 
+	// Privacy mode disables the services that make requests to external
+	// services.
+	if cfg.Privacy {
+		cfg.EnableExchangeBot = false
+		cfg.DisablePiParser = true
+	}
+
 	// Append the network type to the data directory so it is "namespaced" per
 	// network.  In addition to the block database, there are other pieces of
 	// data that are saved to disk such as address manager state. All data is
@@ -606,6 +752,100 @@ func loadConfig() (*config, error) {
 		return nil, fmt.Errorf("purge-n-blocks must be non-negative")
 	}
 
+	// Validate address spending info check options.
+	if cfg.AddrSpendingStart < 0 {
+		return nil, fmt.Errorf("addr-spending-start must be non-negative")
+	}
+	if cfg.AddrSpendingBatch < 1 {
+		return nil, fmt.Errorf("addr-spending-batch must be positive")
+	}
+
+	if cfg.UnarchiveSpent && cfg.ArchiveSpentDepth != 0 {
+		return nil, fmt.Errorf("unarchive-spent requires archive-spent-depth=0")
+	}
+
+	// Validate the block reindex range.
+	if cfg.Reindex != "" {
+		cfg.reindexStart, cfg.reindexEnd, err = parseReindexRange(cfg.Reindex)
+		if err != nil {
+			return nil, err
+		}
+	} else if cfg.ReindexDryRun {
+		return nil, fmt.Errorf("reindex-dry-run requires reindex")
+	}
+
+	// Validate the chain snapshot options.
+	if cfg.ExportSnapshot < 0 {
+		return nil, fmt.Errorf("export-snapshot must be non-negative")
+	}
+	if cfg.ExportSnapshot > 0 {
+		if cfg.SnapshotDir == "" {
+			return nil, fmt.Errorf("export-snapshot requires snapshot-dir")
+		}
+		if cfg.ImportSnapshot != "" {
+			return nil, fmt.Errorf("export-snapshot and import-snapshot may not be used together")
+		}
+		cfg.SnapshotDir = cleanAndExpandPath(cfg.SnapshotDir)
+	}
+	if cfg.ImportSnapshot != "" {
+		cfg.ImportSnapshot = cleanAndExpandPath(cfg.ImportSnapshot)
+	}
+
+	// Validate the block archive options.
+	if cfg.BlockArchiveRetention < 0 {
+		return nil, fmt.Errorf("block-archive-retention must be non-negative")
+	}
+	if cfg.BlockArchiveDir != "" {
+		cfg.BlockArchiveDir = cleanAndExpandPath(cfg.BlockArchiveDir)
+	}
+	if cfg.DailyExportDir != "" {
+		cfg.DailyExportDir = cleanAndExpandPath(cfg.DailyExportDir)
+	}
+
+	// Split comma-separated Kafka brokers.
+	var kafkaBrokers []string
+	for _, brokers := range cfg.KafkaBrokers {
+		for _, broker := range strings.Split(brokers, ",") {
+			if broker = strings.TrimSpace(broker); broker != "" {
+				kafkaBrokers = append(kafkaBrokers, broker)
+			}
+		}
+	}
+	cfg.KafkaBrokers = kafkaBrokers
+
+	// Validate the gRPC listen address and TLS options. As for apilisten, an
+	// address without a host binds to localhost only.
+	if cfg.GRPCListen != "" {
+		cfg.GRPCListen, err = normalizeNetworkAddress(cfg.GRPCListen, defaultHost, defaultGRPCPort)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if (cfg.GRPCCert == "") != (cfg.GRPCKey == "") {
+		return nil, fmt.Errorf("grpccert and grpckey must be set together")
+	}
+	if cfg.GRPCCert != "" {
+		cfg.GRPCCert = cleanAndExpandPath(cfg.GRPCCert)
+		cfg.GRPCKey = cleanAndExpandPath(cfg.GRPCKey)
+	}
+
+	if cfg.FeedTxMinValue < 0 {
+		return nil, fmt.Errorf("feedtxminvalue must be non-negative")
+	}
+	if cfg.FeedBaseURL != "" {
+		u, err := url.Parse(cfg.FeedBaseURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("feedbaseurl %q is not an http or https URL", cfg.FeedBaseURL)
+		}
+		cfg.FeedBaseURL = strings.TrimSuffix(cfg.FeedBaseURL, "/")
+	}
+
+	// Validate maintenance task schedules.
+	cfg.maintSchedules, err = parseMaintSchedules(cfg.MaintSchedules)
+	if err != nil {
+		return nil, err
+	}
+
 	// Set the host names and ports to the default if the user does not specify
 	// them.
 	cfg.DcrdServ, err = normalizeNetworkAddress(cfg.DcrdServ, defaultHost, activeNet.JSONRPCClientPort)
@@ -639,6 +879,18 @@ func loadConfig() (*config, error) {
 		cfg.PGQueryTimeout = defaultPGQueryTimeout
 	}
 
+	// Validate the chart resolution tiers.
+	if cfg.ChartsBlockSpan <= 0 {
+		cfg.ChartsBlockSpan = defaultChartsBlockSpan
+	}
+	if cfg.ChartsDaySpan <= 0 {
+		cfg.ChartsDaySpan = defaultChartsDaySpan
+	}
+	if cfg.ChartsDaySpan < cfg.ChartsBlockSpan {
+		return loadConfigError(fmt.Errorf("charts-day-span (%v) must not be "+
+			"less than charts-block-span (%v)", cfg.ChartsDaySpan, cfg.ChartsBlockSpan))
+	}
+
 	// Parse, validate, and set debug log level(s).
 	if err := parseAndSetDebugLevels(cfg.DebugLevel); err != nil {
 		err = fmt.Errorf("%s: %v", funcName, err.Error())
@@ -655,8 +907,14 @@ func loadConfig() (*config, error) {
 	}
 	cfg.PoliteiaAPIURL = urlPath
 
-	// Check the supplied APIListen address
-	if cfg.APIListen == "" {
+	// Check the supplied APIListen address or unix socket path.
+	if strings.HasPrefix(cfg.APIListen, unixListenPrefix) {
+		socketPath := strings.TrimPrefix(cfg.APIListen, unixListenPrefix)
+		if socketPath == "" {
+			return loadConfigError(fmt.Errorf("apilisten unix socket path is empty"))
+		}
+		cfg.APIListen = unixListenPrefix + cleanAndExpandPath(socketPath)
+	} else if cfg.APIListen == "" {
 		cfg.APIListen = defaultHost + ":" + defaultPort
 	} else {
 		cfg.APIListen, err = normalizeNetworkAddress(cfg.APIListen, defaultHost, defaultPort)
@@ -678,6 +936,7 @@ func loadConfig() (*config, error) {
 	cfg.ProposalsFileName = cleanAndExpandPath(cfg.ProposalsFileName)
 	cfg.RateCertificate = cleanAndExpandPath(cfg.RateCertificate)
 	cfg.ChartsCacheDump = cleanAndExpandPath(cfg.ChartsCacheDump)
+	cfg.MempoolFile = cleanAndExpandPath(cfg.MempoolFile)
 
 	// Clean up the provided mainnet and testnet links, ensuring there is a single
 	// trailing slash.
@@ -687,6 +946,54 @@ func loadConfig() (*config, error) {
 	return &cfg, nil
 }
 
+// parseReindexRange parses a block height range START-END.
+func parseReindexRange(r string) (start, end int64, err error) {
+	parts := strings.Split(r, "-")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("invalid reindex range %q, expected START-END", r)
+	}
+	start, err = strconv.ParseInt(strings.TrimSpace(parts[0]), 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid reindex range start %q", parts[0])
+	}
+	end, err = strconv.ParseInt(strings.TrimSpace(parts[1]), 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid reindex range end %q", parts[1])
+	}
+	if start < 0 || end < start {
+		return 0, 0, fmt.Errorf("invalid reindex range %d-%d", start, end)
+	}
+	return start, end, nil
+}
+
+// parseMaintSchedules applies the task=spec overrides to the default
+// maintenance task schedules. Disabled tasks are omitted from the result.
+func parseMaintSchedules(overrides []string) (map[string]string, error) {
+	schedules := make(map[string]string, len(defaultMaintSchedules))
+	for task, spec := range defaultMaintSchedules {
+		schedules[task] = spec
+	}
+	for _, o := range overrides {
+		kv := strings.SplitN(o, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid maint value %q, expected task=spec", o)
+		}
+		task, spec := strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1])
+		if _, found := defaultMaintSchedules[task]; !found {
+			return nil, fmt.Errorf("unknown maintenance task %q", task)
+		}
+		if spec == "off" {
+			delete(schedules, task)
+			continue
+		}
+		if _, err := maintenance.ParseSchedule(spec); err != nil {
+			return nil, fmt.Errorf("invalid schedule for maintenance task %s: %v", task, err)
+		}
+		schedules[task] = spec
+	}
+	return schedules, nil
+}
+
 // netName returns the name used when referring to a decred network. TestNet3
 // correctly returns "testnet3", but not TestNet2. This function may be removed
 // after testnet2 is ancient history.
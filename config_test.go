@@ -248,6 +248,29 @@ func TestDisablePiparserValueOnSimnet(t *testing.T) {
 	}
 }
 
+func TestPrivacyMode(t *testing.T) {
+	args := os.Args
+	defer func() { os.Args = args }()
+	os.Args = append(os.Args, "--privacy", "--exchange-monitor",
+		"--apilisten=unix:/tmp/dcrdata.sock")
+
+	cfg, err := loadConfig()
+	if err != nil {
+		t.Fatalf("Failed to load dcrdata config: %v", err)
+	}
+
+	if cfg.EnableExchangeBot {
+		t.Error("expected the exchange monitor to be disabled in privacy mode")
+	}
+	if !cfg.DisablePiParser {
+		t.Error("expected piparser to be disabled in privacy mode")
+	}
+	if cfg.APIListen != "unix:/tmp/dcrdata.sock" {
+		t.Errorf("Expected API listen unix socket unix:/tmp/dcrdata.sock, got %s",
+			cfg.APIListen)
+	}
+}
+
 func TestRetrieveRootPath(t *testing.T) {
 	type testData struct {
 		RawURL   string
@@ -318,3 +341,37 @@ func TestNormalizeNetworkAddress(t *testing.T) {
 		}
 	}
 }
+
+func TestParseReindexRange(t *testing.T) {
+	tests := []struct {
+		input         string
+		start, end    int64
+		shouldBeError bool
+	}{
+		{"0-100", 0, 100, false},
+		{"400000-400000", 400000, 400000, false},
+		{" 10 - 20 ", 10, 20, false},
+		{"20-10", 0, 0, true},
+		{"-1-10", 0, 0, true},
+		{"10", 0, 0, true},
+		{"a-10", 0, 0, true},
+		{"10-", 0, 0, true},
+	}
+	for _, test := range tests {
+		start, end, err := parseReindexRange(test.input)
+		if err != nil {
+			if !test.shouldBeError {
+				t.Errorf("Unexpected error parsing %q: %v", test.input, err)
+			}
+			continue
+		}
+		if test.shouldBeError {
+			t.Errorf("Error expected but not seen for %q", test.input)
+			continue
+		}
+		if start != test.start || end != test.end {
+			t.Errorf("Unexpected result. input: %q, returned: %d-%d, expected: %d-%d",
+				test.input, start, end, test.start, test.end)
+		}
+	}
+}
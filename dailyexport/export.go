@@ -0,0 +1,320 @@
+// Copyright (c) 2020, The Decred developers
+// See LICENSE for details.
+
+// Package dailyexport writes bulk exports of the main chain blocks,
+// transactions and transaction outputs of each UTC day as gzipped CSV files,
+// so that researchers may download the chain data without crawling the API.
+package dailyexport
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	apitypes "github.com/decred/dcrdata/api/types/v5"
+)
+
+// Tables are the tables exported for each day, in the order of their files.
+var Tables = []string{"blocks", "transactions", "vouts"}
+
+// DateFormat is the layout of the dates of the exported days, which name their
+// directories.
+const DateFormat = "2006-01-02"
+
+// settleTime is how long after the end of a day the best block's time must be
+// before the day is exported. Block times need only be after the median time
+// of the previous blocks, so later blocks may still have times in the day, and
+// the day's blocks are unlikely to be reorganized by then.
+const settleTime = time.Hour
+
+// manifestFile is the name of the file describing a day's export, which is
+// written last.
+const manifestFile = "manifest.json"
+
+// tmpSuffix is the suffix of the directory of a day that is being exported.
+const tmpSuffix = ".tmp"
+
+// ErrNotFound is returned for files that are not in the exports.
+var ErrNotFound = errors.New("export file not found")
+
+// Source provides the best block and the CSV rows of the exported tables for
+// the main chain blocks with times in [start, end).
+type Source interface {
+	GetBestBlockSummary() *apitypes.BlockDataBasic
+	ExportDailyTable(ctx context.Context, table string, start, end time.Time, w io.Writer) (int64, error)
+}
+
+// Exporter maintains a directory of daily exports, with a subdirectory named
+// by the date of each exported day holding a gzipped CSV file of each table
+// and a manifest. Days are exported in order from the day of the genesis
+// block once they have settled.
+type Exporter struct {
+	dir     string
+	source  Source
+	first   time.Time
+	trigger chan struct{}
+
+	mtx  sync.RWMutex
+	days map[string]*apitypes.DailyExport
+}
+
+// New opens the exports in dir, creating the directory if necessary, and
+// indexes the days already exported. Incomplete exports are removed. The
+// exports start with the UTC day of the genesis time.
+func New(dir string, source Source, genesis time.Time) (*Exporter, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+
+	g := genesis.UTC()
+	e := &Exporter{
+		dir:     dir,
+		source:  source,
+		first:   time.Date(g.Year(), g.Month(), g.Day(), 0, 0, 0, 0, time.UTC),
+		trigger: make(chan struct{}, 1),
+		days:    make(map[string]*apitypes.DailyExport),
+	}
+
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		if !entry.IsDir() {
+			log.Warnf("Ignoring unrecognized file %s in daily exports.", path)
+			continue
+		}
+		if strings.HasSuffix(entry.Name(), tmpSuffix) {
+			if err = os.RemoveAll(path); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if _, err = time.Parse(DateFormat, entry.Name()); err != nil {
+			log.Warnf("Ignoring unrecognized directory %s in daily exports.", path)
+			continue
+		}
+		day, err := readManifest(path)
+		if err != nil || day.Date != entry.Name() {
+			log.Warnf("Removing the daily export in %s without a valid manifest.", path)
+			if err = os.RemoveAll(path); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		e.days[day.Date] = day
+	}
+
+	log.Infof("Daily exports in %s have %d days.", dir, len(e.days))
+	return e, nil
+}
+
+// readManifest reads the manifest of the day's export in dir.
+func readManifest(dir string) (*apitypes.DailyExport, error) {
+	b, err := ioutil.ReadFile(filepath.Join(dir, manifestFile))
+	if err != nil {
+		return nil, err
+	}
+	day := new(apitypes.DailyExport)
+	if err = json.Unmarshal(b, day); err != nil {
+		return nil, err
+	}
+	if len(day.Files) != len(Tables) {
+		return nil, fmt.Errorf("export has %d files, expected %d", len(day.Files), len(Tables))
+	}
+	return day, nil
+}
+
+// BlockStored signals that a new block was stored, which may settle a day.
+// It does not block.
+func (e *Exporter) BlockStored() {
+	select {
+	case e.trigger <- struct{}{}:
+	default:
+	}
+}
+
+// Run exports the settled days that have not been exported, and then the days
+// that settle as blocks are stored, until the context is canceled.
+func (e *Exporter) Run(ctx context.Context) {
+	for {
+		if err := e.exportSettled(ctx); err != nil && ctx.Err() == nil {
+			log.Errorf("Daily export failed: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-e.trigger:
+		}
+	}
+}
+
+// exportSettled exports the days that have settled as of the best block and
+// have not been exported, oldest first.
+func (e *Exporter) exportSettled(ctx context.Context) error {
+	best := e.source.GetBestBlockSummary()
+	if best == nil {
+		return fmt.Errorf("no best block")
+	}
+	bestTime := time.Unix(best.Time.UNIX(), 0)
+
+	for day := e.first; ; day = day.AddDate(0, 0, 1) {
+		end := day.AddDate(0, 0, 1)
+		if end.Add(settleTime).After(bestTime) {
+			return nil
+		}
+		date := day.Format(DateFormat)
+		e.mtx.RLock()
+		_, found := e.days[date]
+		e.mtx.RUnlock()
+		if found {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		export, err := e.exportDay(ctx, day, end)
+		if err != nil {
+			return fmt.Errorf("failed to export %s: %v", date, err)
+		}
+		e.mtx.Lock()
+		e.days[date] = export
+		e.mtx.Unlock()
+		log.Debugf("Exported %s with %d blocks.", date, export.Files[0].Rows)
+	}
+}
+
+// exportDay writes the files of the day in [start, end) to a temporary
+// directory that is renamed once the manifest is written, so that only
+// complete exports are indexed.
+func (e *Exporter) exportDay(ctx context.Context, start, end time.Time) (*apitypes.DailyExport, error) {
+	date := start.Format(DateFormat)
+	dir := filepath.Join(e.dir, date)
+	tmpDir := dir + tmpSuffix
+	if err := os.RemoveAll(tmpDir); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(tmpDir, 0700); err != nil {
+		return nil, err
+	}
+
+	export := &apitypes.DailyExport{
+		Date:    date,
+		Start:   start.Unix(),
+		End:     end.Unix(),
+		Created: time.Now().Unix(),
+	}
+	for _, table := range Tables {
+		file, err := e.exportTable(ctx, tmpDir, table, start, end)
+		if err != nil {
+			_ = os.RemoveAll(tmpDir)
+			return nil, err
+		}
+		export.Files = append(export.Files, file)
+	}
+
+	b, err := json.MarshalIndent(export, "", "    ")
+	if err == nil {
+		err = ioutil.WriteFile(filepath.Join(tmpDir, manifestFile), b, 0600)
+	}
+	if err == nil {
+		err = os.Rename(tmpDir, dir)
+	}
+	if err != nil {
+		_ = os.RemoveAll(tmpDir)
+		return nil, err
+	}
+	return export, nil
+}
+
+// exportTable writes the gzipped CSV file of the table's rows to dir.
+func (e *Exporter) exportTable(ctx context.Context, dir, table string, start, end time.Time) (*apitypes.DailyExportFile, error) {
+	file := &apitypes.DailyExportFile{
+		Table: table,
+		Name:  table + ".csv.gz",
+	}
+	f, err := os.Create(filepath.Join(dir, file.Name))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	hasher := sha256.New()
+	counter := &countingWriter{w: io.MultiWriter(f, hasher)}
+	zw := gzip.NewWriter(counter)
+	w := bufio.NewWriter(zw)
+	file.Rows, err = e.source.ExportDailyTable(ctx, table, start, end, w)
+	if err != nil {
+		return nil, err
+	}
+	if err = w.Flush(); err != nil {
+		return nil, err
+	}
+	if err = zw.Close(); err != nil {
+		return nil, err
+	}
+	file.Size = counter.n
+	file.SHA256 = hex.EncodeToString(hasher.Sum(nil))
+	return file, f.Close()
+}
+
+// countingWriter counts the bytes written to w.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// Days lists the exported days, oldest first, starting with the date since if
+// it is not empty.
+func (e *Exporter) Days(since string) []*apitypes.DailyExport {
+	e.mtx.RLock()
+	days := make([]*apitypes.DailyExport, 0, len(e.days))
+	for date, day := range e.days {
+		// Dates in DateFormat sort chronologically.
+		if date >= since {
+			days = append(days, day)
+		}
+	}
+	e.mtx.RUnlock()
+	sort.Slice(days, func(i, j int) bool {
+		return days[i].Date < days[j].Date
+	})
+	return days
+}
+
+// File returns the path of the named file of the day's export. ErrNotFound is
+// returned if the day has not been exported or has no such file.
+func (e *Exporter) File(date, name string) (string, error) {
+	e.mtx.RLock()
+	day, found := e.days[date]
+	e.mtx.RUnlock()
+	if !found {
+		return "", ErrNotFound
+	}
+	for _, file := range day.Files {
+		if file.Name == name {
+			return filepath.Join(e.dir, date, name), nil
+		}
+	}
+	return "", ErrNotFound
+}
@@ -0,0 +1,136 @@
+package dailyexport
+
+import (
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	apitypes "github.com/decred/dcrdata/api/types/v5"
+	"github.com/decred/dcrdata/db/dbtypes/v2"
+)
+
+// sourceStub has a block every 6 hours, and fails to export the vouts of the
+// day failDay.
+type sourceStub struct {
+	best    time.Time
+	failDay string
+}
+
+func (s *sourceStub) GetBestBlockSummary() *apitypes.BlockDataBasic {
+	return &apitypes.BlockDataBasic{Time: apitypes.TimeAPI{S: dbtypes.NewTimeDef(s.best)}}
+}
+
+func (s *sourceStub) ExportDailyTable(_ context.Context, table string, start, end time.Time, w io.Writer) (int64, error) {
+	if table == "vouts" && start.Format(DateFormat) == s.failDay {
+		return 0, errors.New("timeout")
+	}
+	fmt.Fprintln(w, "time")
+	var n int64
+	for t := start; t.Before(end); t = t.Add(6 * time.Hour) {
+		fmt.Fprintln(w, t.Unix())
+		n++
+	}
+	return n, nil
+}
+
+func TestExporter(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dailyexport")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	genesis := time.Date(2020, 1, 1, 10, 0, 0, 0, time.UTC)
+	source := &sourceStub{best: time.Date(2020, 1, 3, 0, 30, 0, 0, time.UTC)}
+	e, err := New(dir, source, genesis)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	// The second day has not settled.
+	if err = e.exportSettled(ctx); err != nil {
+		t.Fatal(err)
+	}
+	days := e.Days("")
+	if len(days) != 1 || days[0].Date != "2020-01-01" || days[0].Start != 1577836800 ||
+		days[0].End != 1577923200 {
+		t.Fatalf("unexpected days %v", days)
+	}
+
+	path, err := e.File("2020-01-01", "transactions.csv.gz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := days[0].Files[1]
+	sum := sha256.Sum256(b)
+	if file.Table != "transactions" || file.Rows != 4 || file.Size != int64(len(b)) ||
+		file.SHA256 != hex.EncodeToString(sum[:]) {
+		t.Errorf("unexpected file %+v", file)
+	}
+	f, _ := os.Open(path)
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	csv, _ := ioutil.ReadAll(zr)
+	if want := "time\n1577836800\n1577858400\n1577880000\n1577901600\n"; string(csv) != want {
+		t.Errorf("got CSV %q, wanted %q", csv, want)
+	}
+
+	for _, bad := range [][2]string{{"2020-01-01", "manifest.json"}, {"2020-01-02", "blocks.csv.gz"}} {
+		if _, err = e.File(bad[0], bad[1]); err != ErrNotFound {
+			t.Errorf("expected ErrNotFound for %s/%s, got %v", bad[0], bad[1], err)
+		}
+	}
+
+	// A failed export leaves no files, and is retried.
+	source.best = time.Date(2020, 1, 4, 1, 0, 0, 0, time.UTC)
+	source.failDay = "2020-01-03"
+	if err = e.exportSettled(ctx); err == nil {
+		t.Fatal("expected an error exporting 2020-01-03")
+	}
+	if _, err = os.Stat(filepath.Join(dir, "2020-01-03"+tmpSuffix)); !os.IsNotExist(err) {
+		t.Errorf("expected the incomplete export to be removed, got %v", err)
+	}
+	source.failDay = ""
+	if err = e.exportSettled(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if days = e.Days("2020-01-02"); len(days) != 2 || days[1].Date != "2020-01-03" {
+		t.Fatalf("unexpected days %v", days)
+	}
+
+	// Reopening indexes the exports on disk, and removes incomplete ones.
+	for _, d := range []string{"2020-01-04", "2020-01-05" + tmpSuffix} {
+		if err = os.Mkdir(filepath.Join(dir, d), 0700); err != nil {
+			t.Fatal(err)
+		}
+	}
+	e, err = New(dir, source, genesis)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if days = e.Days(""); len(days) != 3 {
+		t.Errorf("got %d days after reopening, wanted 3", len(days))
+	}
+	for _, d := range []string{"2020-01-04", "2020-01-05" + tmpSuffix} {
+		if _, err = os.Stat(filepath.Join(dir, d)); !os.IsNotExist(err) {
+			t.Errorf("expected %s to be removed, got %v", d, err)
+		}
+	}
+}
@@ -670,6 +670,9 @@ type AddressCache struct {
 	maxUTXOsPerAddr int
 	cacheMetrics    cacheMetrics
 	ProjectAddress  string
+	// updated is the time an item was last added to or cleared from the
+	// cache.
+	updated time.Time
 }
 
 // NewAddressCache constructs an AddressCache with capacity for the specified
@@ -687,6 +690,7 @@ func NewAddressCache(rowCapacity, addressCapacity, utxoCapacityBytes int) *Addre
 		cap:             rowCapacity,
 		capAddr:         addressCapacity,
 		maxUTXOsPerAddr: maxUTXOsPerAddr,
+		updated:         time.Now(),
 	}
 	log.Debugf("Allowing %d cached UTXOs per address (max %d addresses), using ~%.0f MiB.",
 		ac.maxUTXOsPerAddr, addressCapacity, float64(utxoCapacityBytes)/1024/1024)
@@ -714,6 +718,14 @@ func (ac *AddressCache) HistoryStats() (hits, misses int) {
 	return ac.cacheMetrics.historyStats()
 }
 
+// LastUpdated returns the time an item was last added to or cleared from the
+// cache.
+func (ac *AddressCache) LastUpdated() time.Time {
+	ac.mtx.RLock()
+	defer ac.mtx.RUnlock()
+	return ac.updated
+}
+
 // Reporter prints the number of cached addresses, rows, and utxos, as well as a
 // table of cache hits and misses.
 func (ac *AddressCache) Reporter() {
@@ -761,6 +773,7 @@ func (ac *AddressCache) ClearAll() (numCleared int) {
 	defer ac.mtx.Unlock()
 	numCleared = len(ac.a)
 	ac.a = make(map[string]*AddressCacheItem)
+	ac.updated = time.Now()
 	return
 }
 
@@ -779,6 +792,7 @@ func (ac *AddressCache) Clear(addrs []string) (numCleared int) {
 		delete(ac.a, addrs[i])
 		numCleared++
 	}
+	ac.updated = time.Now()
 	return
 }
 
@@ -849,6 +863,12 @@ func (ac *AddressCache) Rows(addr string) ([]*dbtypes.AddressRowCompact, *BlockI
 	return aci.Rows()
 }
 
+// Cached reports whether there is an item in the cache for the given address.
+// Unlike Balance and the other getters, this does not count a hit or miss.
+func (ac *AddressCache) Cached(addr string) bool {
+	return ac.addressCacheItem(addr) != nil
+}
+
 // NumRows returns the number of non-merged rows. If the rows are not cached, a
 // count of -1 and *BlockID of nil are returned.
 func (ac *AddressCache) NumRows(addr string) (int, *BlockID) {
@@ -1019,6 +1039,7 @@ func (ac *AddressCache) addCacheItem(addr string, aci *AddressCacheItem) (succes
 	haveSpace := ac.purgeRowsToFit(len(aci.rows) - alreadyStored)
 	if haveSpace {
 		ac.a[addr] = aci
+		ac.updated = time.Now()
 		log.Tracef("Added new AddressCacheItem: %s", addr)
 		success = true
 	} else {
@@ -10,6 +10,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"sort"
 	"sync"
 	"time"
 
@@ -20,21 +21,29 @@ import (
 
 // Keys for specifying chart data type.
 const (
-	BlockSize       = "block-size"
-	BlockChainSize  = "blockchain-size"
-	ChainWork       = "chainwork"
-	CoinSupply      = "coin-supply"
-	DurationBTW     = "duration-btw-blocks"
-	HashRate        = "hashrate"
-	POWDifficulty   = "pow-difficulty"
-	TicketPrice     = "ticket-price"
-	TxCount         = "tx-count"
-	Fees            = "fees"
-	AnonymitySet    = "privacy-participation"
-	TicketPoolSize  = "ticket-pool-size"
-	TicketPoolValue = "ticket-pool-value"
-	WindMissedVotes = "missed-votes"
-	PercentStaked   = "stake-participation"
+	BlockSize         = "block-size"
+	BlockChainSize    = "blockchain-size"
+	ChainWork         = "chainwork"
+	CoinSupply        = "coin-supply"
+	DurationBTW       = "duration-btw-blocks"
+	HashRate          = "hashrate"
+	POWDifficulty     = "pow-difficulty"
+	TicketPrice       = "ticket-price"
+	TxCount           = "tx-count"
+	Fees              = "fees"
+	AnonymitySet      = "privacy-participation"
+	TicketPoolSize    = "ticket-pool-size"
+	TicketPoolValue   = "ticket-pool-value"
+	TicketPoolPrice   = "ticket-pool-price"
+	TxTypeCount       = "tx-type-count"
+	TxTypeVolume      = "tx-type-volume"
+	WindMissedVotes   = "missed-votes"
+	PercentStaked     = "stake-participation"
+	VoteParticipation = "vote-participation"
+	TicketFees        = "ticket-fees"
+	BlockFill         = "block-fill"
+	TicketShare       = "ticket-share"
+	TicketDemand      = "ticket-demand"
 
 	// Some chartResponse keys
 	heightKey       = "h"
@@ -56,6 +65,24 @@ const (
 	durationKey     = "duration"
 	workKey         = "work"
 	rateKey         = "rate"
+	observedKey     = "observed"
+	regularKey      = "regular"
+	ticketsKey      = "tickets"
+	votesKey        = "votes"
+	revokesKey      = "revokes"
+	projectedKey    = "projected"
+	blockTimeKey    = "block_time"
+	voteRatioKey    = "participation"
+	minKey          = "min"
+	medianKey       = "median"
+	maxKey          = "max"
+	closingKey      = "closing"
+	fillKey         = "fill"
+	backlogKey      = "backlog"
+	soloKey         = "solo"
+	pooledKey       = "pooled"
+	otherKey        = "other"
+	elasticityKey   = "elasticity"
 )
 
 // binLevel specifies the granularity of data.
@@ -70,6 +97,7 @@ const (
 	DayBin     binLevel = "day"
 	BlockBin   binLevel = "block"
 	WindowBin  binLevel = "window"
+	WeekBin    binLevel = "week"
 	HeightAxis axisType = "height"
 	TimeAxis   axisType = "time"
 )
@@ -77,7 +105,8 @@ const (
 // Check if the chart is window binned.
 func isWindowBin(chart string) bool {
 	switch chart {
-	case POWDifficulty, TicketPrice, WindMissedVotes:
+	case POWDifficulty, TicketPrice, WindMissedVotes, VoteParticipation, TicketFees,
+		TicketDemand:
 		return true
 	}
 	return false
@@ -112,6 +141,9 @@ func ParseAxis(aType string) axisType {
 const (
 	// aDay defines the number of seconds in a day.
 	aDay = 86400
+	// daysPerWeek is the number of days aggregated in each week of the Weeks
+	// zoomSet.
+	daysPerWeek = 7
 	// HashrateAvgLength is the number of blocks used the rolling average for
 	// the network hashrate calculation.
 	HashrateAvgLength = 120
@@ -120,7 +152,7 @@ const (
 // cacheVersion helps detect when the cache data stored has changed its
 // structure or content. A change on the cache version results to recomputing
 // all the charts data a fresh thereby making the cache to hold the latest changes.
-var cacheVersion = semver.NewSemver(6, 1, 1)
+var cacheVersion = semver.NewSemver(6, 6, 0)
 
 // versionedCacheData defines the cache data contents to be written into a .gob file.
 type versionedCacheData struct {
@@ -145,6 +177,25 @@ const UnknownChartErr = ChartError("unknown chart")
 // ignore the bin flag.
 const InvalidBinErr = ChartError("invalid bin")
 
+// InvalidSpanErr is returned when a span chart is requested with a start time
+// after the end time.
+const InvalidSpanErr = ChartError("invalid span")
+
+// ChartTiers configures the resolution of the long series served by SpanChart.
+// Block resolution is retained for spans starting within BlockSpan of the best
+// block, and day resolution for spans starting within DaySpan. Older spans are
+// served at week resolution.
+type ChartTiers struct {
+	BlockSpan time.Duration
+	DaySpan   time.Duration
+}
+
+// DefaultChartTiers serves a week of blocks and a year of days.
+var DefaultChartTiers = ChartTiers{
+	BlockSpan: 7 * 24 * time.Hour,
+	DaySpan:   365 * 24 * time.Hour,
+}
+
 // An interface for reading and setting the length of datasets.
 type lengther interface {
 	Length() int
@@ -257,13 +308,14 @@ func newChartUints(size int) ChartUints {
 // is managed by explorer, and subsequently the database packages. ChartData
 // provides methods for validating the data and handling concurrency. The
 // cacheID is updated anytime new data is added and validated (see
-// Lengthen), typically once per bin duration.
+// Lengthen), typically once per bin duration, and on reorgs.
 type zoomSet struct {
 	cacheID      uint64
 	Height       ChartUints
 	Time         ChartUints
 	PoolSize     ChartUints
 	PoolValue    ChartUints
+	PoolPrice    ChartUints
 	BlockSize    ChartUints
 	TxCount      ChartUints
 	NewAtoms     ChartUints
@@ -271,6 +323,22 @@ type zoomSet struct {
 	Fees         ChartUints
 	TotalMixed   ChartUints
 	AnonymitySet ChartUints
+	// Transaction counts and total sent atoms by transaction type.
+	RegularCount  ChartUints
+	TicketCount   ChartUints
+	VoteCount     ChartUints
+	RevokeCount   ChartUints
+	RegularVolume ChartUints
+	TicketVolume  ChartUints
+	VoteVolume    ChartUints
+	RevokeVolume  ChartUints
+	// Backlog is the size in bytes of the mempool when each block was mined,
+	// or zero if it was not recorded.
+	Backlog ChartUints
+	// The number of live tickets by purchase type at each block.
+	SoloLive   ChartUints
+	PooledLive ChartUints
+	OtherLive  ChartUints
 }
 
 // Snip truncates the zoomSet to a provided length.
@@ -282,6 +350,7 @@ func (set *zoomSet) Snip(length int) {
 	set.Time = set.Time.snip(length)
 	set.PoolSize = set.PoolSize.snip(length)
 	set.PoolValue = set.PoolValue.snip(length)
+	set.PoolPrice = set.PoolPrice.snip(length)
 	set.BlockSize = set.BlockSize.snip(length)
 	set.TxCount = set.TxCount.snip(length)
 	set.NewAtoms = set.NewAtoms.snip(length)
@@ -289,27 +358,52 @@ func (set *zoomSet) Snip(length int) {
 	set.Fees = set.Fees.snip(length)
 	set.TotalMixed = set.TotalMixed.snip(length)
 	set.AnonymitySet = set.AnonymitySet.snip(length)
+	set.RegularCount = set.RegularCount.snip(length)
+	set.TicketCount = set.TicketCount.snip(length)
+	set.VoteCount = set.VoteCount.snip(length)
+	set.RevokeCount = set.RevokeCount.snip(length)
+	set.RegularVolume = set.RegularVolume.snip(length)
+	set.TicketVolume = set.TicketVolume.snip(length)
+	set.VoteVolume = set.VoteVolume.snip(length)
+	set.RevokeVolume = set.RevokeVolume.snip(length)
+	set.Backlog = set.Backlog.snip(length)
+	set.SoloLive = set.SoloLive.snip(length)
+	set.PooledLive = set.PooledLive.snip(length)
+	set.OtherLive = set.OtherLive.snip(length)
 }
 
 // Constructor for a sized zoomSet for blocks, which has has no Height slice
 // since the height is implicit for block-binned data.
 func newBlockSet(size int) *zoomSet {
 	return &zoomSet{
-		Height:       newChartUints(size),
-		Time:         newChartUints(size),
-		PoolSize:     newChartUints(size),
-		PoolValue:    newChartUints(size),
-		BlockSize:    newChartUints(size),
-		TxCount:      newChartUints(size),
-		NewAtoms:     newChartUints(size),
-		Chainwork:    newChartUints(size),
-		Fees:         newChartUints(size),
-		TotalMixed:   newChartUints(size),
-		AnonymitySet: newChartUints(size),
-	}
-}
-
-// Constructor for a sized zoomSet for day-binned data.
+		Height:        newChartUints(size),
+		Time:          newChartUints(size),
+		PoolSize:      newChartUints(size),
+		PoolValue:     newChartUints(size),
+		PoolPrice:     newChartUints(size),
+		BlockSize:     newChartUints(size),
+		TxCount:       newChartUints(size),
+		NewAtoms:      newChartUints(size),
+		Chainwork:     newChartUints(size),
+		Fees:          newChartUints(size),
+		TotalMixed:    newChartUints(size),
+		AnonymitySet:  newChartUints(size),
+		RegularCount:  newChartUints(size),
+		TicketCount:   newChartUints(size),
+		VoteCount:     newChartUints(size),
+		RevokeCount:   newChartUints(size),
+		RegularVolume: newChartUints(size),
+		TicketVolume:  newChartUints(size),
+		VoteVolume:    newChartUints(size),
+		RevokeVolume:  newChartUints(size),
+		Backlog:       newChartUints(size),
+		SoloLive:      newChartUints(size),
+		PooledLive:    newChartUints(size),
+		OtherLive:     newChartUints(size),
+	}
+}
+
+// Constructor for a sized zoomSet for day- or week-binned data.
 func newDaySet(size int) *zoomSet {
 	set := newBlockSet(size)
 	set.Height = newChartUints(size)
@@ -318,14 +412,23 @@ func newDaySet(size int) *zoomSet {
 
 // windowSet is for data that only changes at the difficulty change interval,
 // 144 blocks on mainnet. stakeValid defines the number windows before the
-// stake validation height.
+// stake validation height. VoteParticipation is derived from MissedVotes
+// during Lengthen, and is not stored in the cache dump. The ticket fee rates
+// are the minimum, median and maximum fee rates of the tickets mined in the
+// window, and the minimum fee rate of those mined in the closing blocks of the
+// window, when ticket demand is highest, all in atoms/kB.
 type windowSet struct {
-	cacheID     uint64
-	Time        ChartUints
-	PowDiff     ChartFloats
-	TicketPrice ChartUints
-	StakeCount  ChartUints
-	MissedVotes ChartUints
+	cacheID           uint64
+	Time              ChartUints
+	PowDiff           ChartFloats
+	TicketPrice       ChartUints
+	StakeCount        ChartUints
+	MissedVotes       ChartUints
+	VoteParticipation ChartFloats
+	TicketFeeMin      ChartUints
+	TicketFeeMedian   ChartUints
+	TicketFeeMax      ChartUints
+	TicketFeeClosing  ChartUints
 }
 
 // Snip truncates the windowSet to a provided length.
@@ -339,16 +442,26 @@ func (set *windowSet) Snip(length int) {
 	set.TicketPrice = set.TicketPrice.snip(length)
 	set.StakeCount = set.StakeCount.snip(length)
 	set.MissedVotes = set.MissedVotes.snip(length)
+	set.VoteParticipation = set.VoteParticipation.snip(length)
+	set.TicketFeeMin = set.TicketFeeMin.snip(length)
+	set.TicketFeeMedian = set.TicketFeeMedian.snip(length)
+	set.TicketFeeMax = set.TicketFeeMax.snip(length)
+	set.TicketFeeClosing = set.TicketFeeClosing.snip(length)
 }
 
 // Constructor for a sized windowSet.
 func newWindowSet(size int) *windowSet {
 	return &windowSet{
-		Time:        newChartUints(size),
-		PowDiff:     newChartFloats(size),
-		TicketPrice: newChartUints(size),
-		StakeCount:  newChartUints(size),
-		MissedVotes: newChartUints(size),
+		Time:              newChartUints(size),
+		PowDiff:           newChartFloats(size),
+		TicketPrice:       newChartUints(size),
+		StakeCount:        newChartUints(size),
+		MissedVotes:       newChartUints(size),
+		VoteParticipation: newChartFloats(size),
+		TicketFeeMin:      newChartUints(size),
+		TicketFeeMedian:   newChartUints(size),
+		TicketFeeMax:      newChartUints(size),
+		TicketFeeClosing:  newChartUints(size),
 	}
 }
 
@@ -356,22 +469,39 @@ func newWindowSet(size int) *windowSet {
 // has a lot of extraneous fields, and also embeds sync.RWMutex, so is not
 // suitable for gobbing.
 type ChartGobject struct {
-	Height       ChartUints
-	Time         ChartUints
-	PoolSize     ChartUints
-	PoolValue    ChartUints
-	BlockSize    ChartUints
-	TxCount      ChartUints
-	NewAtoms     ChartUints
-	Chainwork    ChartUints
-	Fees         ChartUints
-	WindowTime   ChartUints
-	PowDiff      ChartFloats
-	TicketPrice  ChartUints
-	StakeCount   ChartUints
-	MissedVotes  ChartUints
-	TotalMixed   ChartUints
-	AnonymitySet ChartUints
+	Height           ChartUints
+	Time             ChartUints
+	PoolSize         ChartUints
+	PoolValue        ChartUints
+	PoolPrice        ChartUints
+	BlockSize        ChartUints
+	TxCount          ChartUints
+	NewAtoms         ChartUints
+	Chainwork        ChartUints
+	Fees             ChartUints
+	WindowTime       ChartUints
+	PowDiff          ChartFloats
+	TicketPrice      ChartUints
+	StakeCount       ChartUints
+	MissedVotes      ChartUints
+	TicketFeeMin     ChartUints
+	TicketFeeMedian  ChartUints
+	TicketFeeMax     ChartUints
+	TicketFeeClosing ChartUints
+	TotalMixed       ChartUints
+	AnonymitySet     ChartUints
+	RegularCount     ChartUints
+	TicketCount      ChartUints
+	VoteCount        ChartUints
+	RevokeCount      ChartUints
+	RegularVolume    ChartUints
+	TicketVolume     ChartUints
+	VoteVolume       ChartUints
+	RevokeVolume     ChartUints
+	Backlog          ChartUints
+	SoloLive         ChartUints
+	PooledLive       ChartUints
+	OtherLive        ChartUints
 }
 
 // The chart data is cached with the current cacheID of the zoomSet or windowSet.
@@ -400,9 +530,10 @@ type lengtherMap map[string]lengther
 // messaging.
 type ChartUpdater struct {
 	Tag string
-	// In addition to the sql.Rows and an error, the fetcher should return a
+	// The Fetcher's query should be canceled when the context is done. In
+	// addition to the sql.Rows and an error, the fetcher should return a
 	// context.CancelFunc if appropriate, else a dummy.
-	Fetcher func(*ChartData) (*sql.Rows, func(), error)
+	Fetcher func(context.Context, *ChartData) (*sql.Rows, func(), error)
 	// The Appender will be run under mutex lock.
 	Appender func(*ChartData, *sql.Rows) error
 }
@@ -411,7 +542,8 @@ type ChartUpdater struct {
 // managing data validation and update concurrency, but does not perform any
 // data retrieval and must be used with care to keep the data valid. The Blocks
 // and Windows fields must be updated by (presumably) a database package. The
-// Days data is auto-generated from the Blocks data during Lengthen-ing.
+// Days data is auto-generated from the Blocks data during Lengthen-ing, and the
+// Weeks data from the Days data. Tiers may be set before the ChartData is used.
 type ChartData struct {
 	mtx          sync.RWMutex
 	ctx          context.Context
@@ -420,10 +552,13 @@ type ChartData struct {
 	Blocks       *zoomSet
 	Windows      *windowSet
 	Days         *zoomSet
+	Weeks        *zoomSet
+	Tiers        ChartTiers
 	cacheMtx     sync.RWMutex
 	cache        map[string]*cachedChart
 	updateMtx    sync.Mutex
 	updaters     []ChartUpdater
+	chainParams  *chaincfg.Params
 }
 
 // ValidateLengths checks that the length of all arguments is equal.
@@ -468,9 +603,13 @@ func (charts *ChartData) Lengthen() error {
 	// Make sure the database has set an equal number of blocks in each data set.
 	blocks := charts.Blocks
 	shortest, err := ValidateLengths(blocks.Height, blocks.Time,
-		blocks.PoolSize, blocks.PoolValue, blocks.BlockSize, blocks.TxCount,
-		blocks.NewAtoms, blocks.Chainwork, blocks.Fees, blocks.TotalMixed,
-		blocks.AnonymitySet)
+		blocks.PoolSize, blocks.PoolValue, blocks.PoolPrice, blocks.BlockSize,
+		blocks.TxCount, blocks.NewAtoms, blocks.Chainwork, blocks.Fees,
+		blocks.TotalMixed, blocks.AnonymitySet, blocks.RegularCount,
+		blocks.TicketCount, blocks.VoteCount, blocks.RevokeCount,
+		blocks.RegularVolume, blocks.TicketVolume, blocks.VoteVolume,
+		blocks.RevokeVolume, blocks.Backlog, blocks.SoloLive,
+		blocks.PooledLive, blocks.OtherLive)
 	if err != nil {
 		log.Warnf("ChartData.Lengthen: block data length mismatch detected. "+
 			"Truncating blocks length to %d", shortest)
@@ -483,7 +622,9 @@ func (charts *ChartData) Lengthen() error {
 
 	windows := charts.Windows
 	shortest, err = ValidateLengths(windows.Time, windows.PowDiff,
-		windows.TicketPrice, windows.StakeCount, windows.MissedVotes)
+		windows.TicketPrice, windows.StakeCount, windows.MissedVotes,
+		windows.TicketFeeMin, windows.TicketFeeMedian, windows.TicketFeeMax,
+		windows.TicketFeeClosing)
 	if err != nil {
 		log.Warnf("ChartData.Lengthen: window data length mismatch detected. "+
 			"Truncating windows length to %d", shortest)
@@ -492,6 +633,7 @@ func (charts *ChartData) Lengthen() error {
 	if shortest == 0 {
 		return fmt.Errorf("unexpected zero-length window data")
 	}
+	charts.lengthenVoteParticipation()
 
 	days := charts.Days
 
@@ -545,6 +687,7 @@ func (charts *ChartData) Lengthen() error {
 			days.Height = append(days.Height, uint64(interval[1]-1))
 			days.PoolSize = append(days.PoolSize, blocks.PoolSize.Avg(interval[0], interval[1]))
 			days.PoolValue = append(days.PoolValue, blocks.PoolValue.Avg(interval[0], interval[1]))
+			days.PoolPrice = append(days.PoolPrice, blocks.PoolPrice.Avg(interval[0], interval[1]))
 			days.BlockSize = append(days.BlockSize, blocks.BlockSize.Sum(interval[0], interval[1]))
 			days.TxCount = append(days.TxCount, blocks.TxCount.Sum(interval[0], interval[1]))
 			days.NewAtoms = append(days.NewAtoms, blocks.NewAtoms.Sum(interval[0], interval[1]))
@@ -552,32 +695,131 @@ func (charts *ChartData) Lengthen() error {
 			days.Fees = append(days.Fees, blocks.Fees.Sum(interval[0], interval[1]))
 			days.TotalMixed = append(days.TotalMixed, blocks.TotalMixed.Sum(interval[0], interval[1]))
 			days.AnonymitySet = append(days.AnonymitySet, blocks.AnonymitySet.Avg(interval[0], interval[1]))
+			days.RegularCount = append(days.RegularCount, blocks.RegularCount.Sum(interval[0], interval[1]))
+			days.TicketCount = append(days.TicketCount, blocks.TicketCount.Sum(interval[0], interval[1]))
+			days.VoteCount = append(days.VoteCount, blocks.VoteCount.Sum(interval[0], interval[1]))
+			days.RevokeCount = append(days.RevokeCount, blocks.RevokeCount.Sum(interval[0], interval[1]))
+			days.RegularVolume = append(days.RegularVolume, blocks.RegularVolume.Sum(interval[0], interval[1]))
+			days.TicketVolume = append(days.TicketVolume, blocks.TicketVolume.Sum(interval[0], interval[1]))
+			days.VoteVolume = append(days.VoteVolume, blocks.VoteVolume.Sum(interval[0], interval[1]))
+			days.RevokeVolume = append(days.RevokeVolume, blocks.RevokeVolume.Sum(interval[0], interval[1]))
+			days.Backlog = append(days.Backlog, blocks.Backlog.Avg(interval[0], interval[1]))
+			days.SoloLive = append(days.SoloLive, blocks.SoloLive.Avg(interval[0], interval[1]))
+			days.PooledLive = append(days.PooledLive, blocks.PooledLive.Avg(interval[0], interval[1]))
+			days.OtherLive = append(days.OtherLive, blocks.OtherLive.Avg(interval[0], interval[1]))
 		}
 	}
 
 	// Check that all relevant datasets have been updated to the same length.
 	daysLen, err := ValidateLengths(days.Height, days.Time, days.PoolSize,
-		days.PoolValue, days.BlockSize, days.TxCount, days.NewAtoms,
-		days.Chainwork, days.Fees, days.TotalMixed, days.AnonymitySet)
+		days.PoolValue, days.PoolPrice, days.BlockSize, days.TxCount,
+		days.NewAtoms, days.Chainwork, days.Fees, days.TotalMixed,
+		days.AnonymitySet, days.RegularCount, days.TicketCount, days.VoteCount,
+		days.RevokeCount, days.RegularVolume, days.TicketVolume,
+		days.VoteVolume, days.RevokeVolume, days.Backlog, days.SoloLive,
+		days.PooledLive, days.OtherLive)
 	if err != nil {
 		return fmt.Errorf("day bin: %v", err)
 	} else if daysLen == 0 {
 		log.Warnf("(*ChartData).Lengthen: Zero-length day-binned data!")
 	}
 
+	newWeeks, err := charts.lengthenWeeks()
+	if err != nil {
+		return fmt.Errorf("week bin: %v", err)
+	}
+
 	charts.cacheMtx.Lock()
 	defer charts.cacheMtx.Unlock()
-	// The cacheID for day-binned data, only increment the cacheID when entries
-	// were added.
+	// The cacheID for day- and week-binned data, only increment the cacheID
+	// when entries were added.
 	if len(intervals) > 0 {
 		days.cacheID++
 	}
-	// For blocks and windows, the cacheID is the last timestamp.
-	charts.Blocks.cacheID = blocks.Time[len(blocks.Time)-1]
-	charts.Windows.cacheID = windows.Time[len(windows.Time)-1]
+	if newWeeks > 0 {
+		charts.Weeks.cacheID++
+	}
+	// Blocks are added, and the last window may be updated, with every call.
+	// The cacheID is not the last timestamp, since a reorg can replace the
+	// data at the same height or in the same window without changing it.
+	charts.Blocks.cacheID++
+	charts.Windows.cacheID++
 	return nil
 }
 
+// lengthenWeeks appends a week to the Weeks zoomSet for each new run of
+// daysPerWeek days in the Days zoomSet, taking sums, averages, or the last
+// value of the days in the same way as the days are made from blocks. Weeks
+// beyond the Days data, such as after a reorg, are dropped first. The number
+// of weeks added is returned. charts.mtx must be locked for writing.
+func (charts *ChartData) lengthenWeeks() (int, error) {
+	days, weeks := charts.Days, charts.Weeks
+	weeks.Snip(len(days.Time) / daysPerWeek)
+
+	var added int
+	for s := len(weeks.Time) * daysPerWeek; s+daysPerWeek <= len(days.Time); s += daysPerWeek {
+		e := s + daysPerWeek
+		weeks.Height = append(weeks.Height, days.Height[e-1])
+		weeks.Time = append(weeks.Time, days.Time[s])
+		weeks.PoolSize = append(weeks.PoolSize, days.PoolSize.Avg(s, e))
+		weeks.PoolValue = append(weeks.PoolValue, days.PoolValue.Avg(s, e))
+		weeks.PoolPrice = append(weeks.PoolPrice, days.PoolPrice.Avg(s, e))
+		weeks.BlockSize = append(weeks.BlockSize, days.BlockSize.Sum(s, e))
+		weeks.TxCount = append(weeks.TxCount, days.TxCount.Sum(s, e))
+		weeks.NewAtoms = append(weeks.NewAtoms, days.NewAtoms.Sum(s, e))
+		weeks.Chainwork = append(weeks.Chainwork, days.Chainwork[e-1])
+		weeks.Fees = append(weeks.Fees, days.Fees.Sum(s, e))
+		weeks.TotalMixed = append(weeks.TotalMixed, days.TotalMixed.Sum(s, e))
+		weeks.AnonymitySet = append(weeks.AnonymitySet, days.AnonymitySet.Avg(s, e))
+		weeks.RegularCount = append(weeks.RegularCount, days.RegularCount.Sum(s, e))
+		weeks.TicketCount = append(weeks.TicketCount, days.TicketCount.Sum(s, e))
+		weeks.VoteCount = append(weeks.VoteCount, days.VoteCount.Sum(s, e))
+		weeks.RevokeCount = append(weeks.RevokeCount, days.RevokeCount.Sum(s, e))
+		weeks.RegularVolume = append(weeks.RegularVolume, days.RegularVolume.Sum(s, e))
+		weeks.TicketVolume = append(weeks.TicketVolume, days.TicketVolume.Sum(s, e))
+		weeks.VoteVolume = append(weeks.VoteVolume, days.VoteVolume.Sum(s, e))
+		weeks.RevokeVolume = append(weeks.RevokeVolume, days.RevokeVolume.Sum(s, e))
+		weeks.Backlog = append(weeks.Backlog, days.Backlog.Avg(s, e))
+		weeks.SoloLive = append(weeks.SoloLive, days.SoloLive.Avg(s, e))
+		weeks.PooledLive = append(weeks.PooledLive, days.PooledLive.Avg(s, e))
+		weeks.OtherLive = append(weeks.OtherLive, days.OtherLive.Avg(s, e))
+		added++
+	}
+
+	_, err := ValidateLengths(weeks.Height, weeks.Time, weeks.PoolSize,
+		weeks.PoolValue, weeks.PoolPrice, weeks.BlockSize, weeks.TxCount,
+		weeks.NewAtoms, weeks.Chainwork, weeks.Fees, weeks.TotalMixed,
+		weeks.AnonymitySet, weeks.RegularCount, weeks.TicketCount,
+		weeks.VoteCount, weeks.RevokeCount, weeks.RegularVolume,
+		weeks.TicketVolume, weeks.VoteVolume, weeks.RevokeVolume, weeks.Backlog,
+		weeks.SoloLive, weeks.PooledLive, weeks.OtherLive)
+	return added, err
+}
+
+// lengthenVoteParticipation appends the fraction of expected votes that were
+// cast for each window in MissedVotes that does not yet have a participation
+// value. Only new windows are computed. The window containing the stake
+// validation height is only partially eligible for votes, and windows before it
+// have a participation of zero. charts.mtx must be locked for writing.
+func (charts *ChartData) lengthenVoteParticipation() {
+	windows := charts.Windows
+	windows.VoteParticipation = windows.VoteParticipation.snip(len(windows.MissedVotes))
+	windowSize := int64(charts.DiffInterval)
+	votesPerBlock := int64(charts.chainParams.TicketsPerBlock)
+	for i := len(windows.VoteParticipation); i < len(windows.MissedVotes); i++ {
+		start, end := int64(i)*windowSize, int64(i+1)*windowSize
+		if start < int64(charts.StartPOS) {
+			start = int64(charts.StartPOS)
+		}
+		var participation float64
+		if end > start {
+			expected := (end - start) * votesPerBlock
+			participation = float64(expected-int64(windows.MissedVotes[i])) / float64(expected)
+		}
+		windows.VoteParticipation = append(windows.VoteParticipation, participation)
+	}
+}
+
 // ReorgHandler handles the charts cache data reorganization. ReorgHandler
 // satisfies notification.ReorgHandler, and is registered as a handler in
 // main.go.
@@ -592,11 +834,20 @@ func (charts *ChartData) ReorgHandler(reorg *txhelpers.ReorgData) error {
 	daysLen -= 2
 	log.Debugf("ChartData.ReorgHandler snipping days height to %d", daysLen)
 	charts.Days.Snip(daysLen)
+	charts.Weeks.Snip(daysLen / daysPerWeek)
 	// Drop the last window
 	windowsLen := len(charts.Windows.Time)
 	windowsLen--
 	log.Debugf("ChartData.ReorgHandler snipping windows to height to %d", windowsLen)
 	charts.Windows.Snip(windowsLen)
+	// Expire the charts cached from the orphaned blocks, and those made from
+	// the snipped data before the new chain is added.
+	charts.cacheMtx.Lock()
+	charts.Blocks.cacheID++
+	charts.Days.cacheID++
+	charts.Weeks.cacheID++
+	charts.Windows.cacheID++
+	charts.cacheMtx.Unlock()
 	charts.mtx.Unlock()
 	return nil
 }
@@ -663,6 +914,7 @@ func (charts *ChartData) readCacheFile(filePath string) error {
 	charts.Blocks.Time = gobject.Time
 	charts.Blocks.PoolSize = gobject.PoolSize
 	charts.Blocks.PoolValue = gobject.PoolValue
+	charts.Blocks.PoolPrice = gobject.PoolPrice
 	charts.Blocks.BlockSize = gobject.BlockSize
 	charts.Blocks.TxCount = gobject.TxCount
 	charts.Blocks.NewAtoms = gobject.NewAtoms
@@ -670,11 +922,27 @@ func (charts *ChartData) readCacheFile(filePath string) error {
 	charts.Blocks.Fees = gobject.Fees
 	charts.Blocks.TotalMixed = gobject.TotalMixed
 	charts.Blocks.AnonymitySet = gobject.AnonymitySet
+	charts.Blocks.RegularCount = gobject.RegularCount
+	charts.Blocks.TicketCount = gobject.TicketCount
+	charts.Blocks.VoteCount = gobject.VoteCount
+	charts.Blocks.RevokeCount = gobject.RevokeCount
+	charts.Blocks.RegularVolume = gobject.RegularVolume
+	charts.Blocks.TicketVolume = gobject.TicketVolume
+	charts.Blocks.VoteVolume = gobject.VoteVolume
+	charts.Blocks.RevokeVolume = gobject.RevokeVolume
+	charts.Blocks.Backlog = gobject.Backlog
+	charts.Blocks.SoloLive = gobject.SoloLive
+	charts.Blocks.PooledLive = gobject.PooledLive
+	charts.Blocks.OtherLive = gobject.OtherLive
 	charts.Windows.Time = gobject.WindowTime
 	charts.Windows.PowDiff = gobject.PowDiff
 	charts.Windows.TicketPrice = gobject.TicketPrice
 	charts.Windows.StakeCount = gobject.StakeCount
 	charts.Windows.MissedVotes = gobject.MissedVotes
+	charts.Windows.TicketFeeMin = gobject.TicketFeeMin
+	charts.Windows.TicketFeeMedian = gobject.TicketFeeMedian
+	charts.Windows.TicketFeeMax = gobject.TicketFeeMax
+	charts.Windows.TicketFeeClosing = gobject.TicketFeeClosing
 
 	charts.mtx.Unlock()
 
@@ -684,6 +952,7 @@ func (charts *ChartData) readCacheFile(filePath string) error {
 		charts.Blocks.Snip(0)
 		charts.Windows.Snip(0)
 		charts.Days.Snip(0)
+		charts.Weeks.Snip(0)
 	}
 
 	return nil
@@ -730,23 +999,76 @@ func (charts *ChartData) TriggerUpdate(_ string, _ uint32) error {
 
 func (charts *ChartData) gobject() *ChartGobject {
 	return &ChartGobject{
-		Height:       charts.Blocks.Height,
-		Time:         charts.Blocks.Time,
-		PoolSize:     charts.Blocks.PoolSize,
-		PoolValue:    charts.Blocks.PoolValue,
-		BlockSize:    charts.Blocks.BlockSize,
-		TxCount:      charts.Blocks.TxCount,
-		NewAtoms:     charts.Blocks.NewAtoms,
-		Chainwork:    charts.Blocks.Chainwork,
-		Fees:         charts.Blocks.Fees,
-		TotalMixed:   charts.Blocks.TotalMixed,
-		AnonymitySet: charts.Blocks.AnonymitySet,
-		WindowTime:   charts.Windows.Time,
-		PowDiff:      charts.Windows.PowDiff,
-		TicketPrice:  charts.Windows.TicketPrice,
-		StakeCount:   charts.Windows.StakeCount,
-		MissedVotes:  charts.Windows.MissedVotes,
+		Height:           charts.Blocks.Height,
+		Time:             charts.Blocks.Time,
+		PoolSize:         charts.Blocks.PoolSize,
+		PoolValue:        charts.Blocks.PoolValue,
+		PoolPrice:        charts.Blocks.PoolPrice,
+		BlockSize:        charts.Blocks.BlockSize,
+		TxCount:          charts.Blocks.TxCount,
+		NewAtoms:         charts.Blocks.NewAtoms,
+		Chainwork:        charts.Blocks.Chainwork,
+		Fees:             charts.Blocks.Fees,
+		TotalMixed:       charts.Blocks.TotalMixed,
+		AnonymitySet:     charts.Blocks.AnonymitySet,
+		WindowTime:       charts.Windows.Time,
+		PowDiff:          charts.Windows.PowDiff,
+		TicketPrice:      charts.Windows.TicketPrice,
+		StakeCount:       charts.Windows.StakeCount,
+		MissedVotes:      charts.Windows.MissedVotes,
+		TicketFeeMin:     charts.Windows.TicketFeeMin,
+		TicketFeeMedian:  charts.Windows.TicketFeeMedian,
+		TicketFeeMax:     charts.Windows.TicketFeeMax,
+		TicketFeeClosing: charts.Windows.TicketFeeClosing,
+		RegularCount:     charts.Blocks.RegularCount,
+		TicketCount:      charts.Blocks.TicketCount,
+		VoteCount:        charts.Blocks.VoteCount,
+		RevokeCount:      charts.Blocks.RevokeCount,
+		RegularVolume:    charts.Blocks.RegularVolume,
+		TicketVolume:     charts.Blocks.TicketVolume,
+		VoteVolume:       charts.Blocks.VoteVolume,
+		RevokeVolume:     charts.Blocks.RevokeVolume,
+		Backlog:          charts.Blocks.Backlog,
+		SoloLive:         charts.Blocks.SoloLive,
+		PooledLive:       charts.Blocks.PooledLive,
+		OtherLive:        charts.Blocks.OtherLive,
+	}
+}
+
+// BlockStats are the totals of the block data for a range of blocks. Fees and
+// NewAtoms are in atoms.
+type BlockStats struct {
+	Blocks       uint64
+	Transactions uint64
+	Bytes        uint64
+	Fees         uint64
+	NewAtoms     uint64
+}
+
+// BlockStatsSince totals the block data for the blocks with a time stamp after
+// the UNIX time since. Blocks for which the fees or new atoms have not been
+// loaded do not count toward those totals.
+func (charts *ChartData) BlockStatsSince(since uint64) *BlockStats {
+	charts.mtx.RLock()
+	defer charts.mtx.RUnlock()
+	blocks := charts.Blocks
+	stats := new(BlockStats)
+	for i := len(blocks.Time) - 1; i >= 0 && blocks.Time[i] > since; i-- {
+		stats.Blocks++
+		if i < len(blocks.TxCount) {
+			stats.Transactions += blocks.TxCount[i]
+		}
+		if i < len(blocks.BlockSize) {
+			stats.Bytes += blocks.BlockSize[i]
+		}
+		if i < len(blocks.Fees) {
+			stats.Fees += blocks.Fees[i]
+		}
+		if i < len(blocks.NewAtoms) {
+			stats.NewAtoms += blocks.NewAtoms[i]
+		}
 	}
+	return stats
 }
 
 // StateID returns a unique (enough) ID associated with the state of the Blocks
@@ -818,6 +1140,27 @@ func (charts *ChartData) TicketPriceTip() int32 {
 	return int32(len(charts.Windows.TicketPrice))*charts.DiffInterval - 1
 }
 
+// TxTypesTip is the height of the transaction type data.
+func (charts *ChartData) TxTypesTip() int32 {
+	charts.mtx.RLock()
+	defer charts.mtx.RUnlock()
+	return int32(len(charts.Blocks.RegularCount)) - 1
+}
+
+// BacklogTip is the height of the Backlog data.
+func (charts *ChartData) BacklogTip() int32 {
+	charts.mtx.RLock()
+	defer charts.mtx.RUnlock()
+	return int32(len(charts.Blocks.Backlog)) - 1
+}
+
+// TicketShareTip is the height of the live ticket data by purchase type.
+func (charts *ChartData) TicketShareTip() int32 {
+	charts.mtx.RLock()
+	defer charts.mtx.RUnlock()
+	return int32(len(charts.Blocks.SoloLive)) - 1
+}
+
 // PoolSizeTip is the height of the PoolSize data.
 func (charts *ChartData) PoolSizeTip() int32 {
 	charts.mtx.RLock()
@@ -832,6 +1175,13 @@ func (charts *ChartData) MissedVotesTip() int32 {
 	return int32(len(charts.Windows.MissedVotes))*charts.DiffInterval - 1
 }
 
+// TicketFeesTip is the height of the ticket fee rate data.
+func (charts *ChartData) TicketFeesTip() int32 {
+	charts.mtx.RLock()
+	defer charts.mtx.RUnlock()
+	return int32(len(charts.Windows.TicketFeeMedian))*charts.DiffInterval - 1
+}
+
 // AddUpdater adds a ChartUpdater to the Updaters slice. Updaters are run
 // sequentially during (*ChartData).Update.
 func (charts *ChartData) AddUpdater(updater ChartUpdater) {
@@ -854,7 +1204,7 @@ func (charts *ChartData) Update() error {
 	for _, updater := range charts.updaters {
 		ti := time.Now()
 		stateID := charts.StateID()
-		rows, cancel, err := updater.Fetcher(charts)
+		rows, cancel, err := updater.Fetcher(charts.ctx, charts)
 		if err != nil {
 			err = fmt.Errorf("error encountered during charts %s update. aborting update: %v", updater.Tag, err)
 		} else {
@@ -901,6 +1251,7 @@ func NewChartData(ctx context.Context, height uint32, chainParams *chaincfg.Para
 	size := int(height * 5 / 4)
 	days := int(time.Since(genesis)/time.Hour/24)*5/4 + 1 // at least one day
 	windows := int(base64Height/chainParams.StakeDiffWindowSize+1) * 5 / 4
+	weeks := days/daysPerWeek + 1
 
 	return &ChartData{
 		ctx:          ctx,
@@ -909,8 +1260,11 @@ func NewChartData(ctx context.Context, height uint32, chainParams *chaincfg.Para
 		Blocks:       newBlockSet(size),
 		Windows:      newWindowSet(windows),
 		Days:         newDaySet(days),
+		Weeks:        newDaySet(weeks),
+		Tiers:        DefaultChartTiers,
 		cache:        make(map[string]*cachedChart),
 		updaters:     make([]ChartUpdater, 0),
+		chainParams:  chainParams,
 	}
 }
 
@@ -928,6 +1282,8 @@ func (charts *ChartData) cacheID(bin binLevel) uint64 {
 		return charts.Blocks.cacheID
 	case DayBin:
 		return charts.Days.cacheID
+	case WeekBin:
+		return charts.Weeks.cacheID
 	case WindowBin:
 		return charts.Windows.cacheID
 	}
@@ -945,16 +1301,14 @@ func (charts *ChartData) getCache(chartID string, bin binLevel, axis axisType) (
 	return
 }
 
-// Store the chart associated with the provided type and BinLevel.
-func (charts *ChartData) cacheChart(chartID string, bin binLevel, axis axisType, data []byte) {
+// Store the chart associated with the provided type and BinLevel, made from
+// the data with the cacheID.
+func (charts *ChartData) cacheChart(chartID string, bin binLevel, axis axisType, cacheID uint64, data []byte) {
 	ck := cacheKey(chartID, bin, axis)
 	charts.cacheMtx.Lock()
 	defer charts.cacheMtx.Unlock()
-	// Using the current best cacheID. This leaves open the small possibility that
-	// the cacheID is wrong, if the cacheID has been updated between the
-	// ChartMaker and here. This would just cause a one block delay.
 	charts.cache[ck] = &cachedChart{
-		cacheID: charts.cacheID(bin),
+		cacheID: cacheID,
 		data:    data,
 	}
 }
@@ -964,21 +1318,29 @@ func (charts *ChartData) cacheChart(chartID string, bin binLevel, axis axisType,
 type ChartMaker func(charts *ChartData, bin binLevel, axis axisType) ([]byte, error)
 
 var chartMakers = map[string]ChartMaker{
-	BlockSize:       blockSizeChart,
-	BlockChainSize:  blockchainSizeChart,
-	ChainWork:       chainWorkChart,
-	CoinSupply:      coinSupplyChart,
-	DurationBTW:     durationBTWChart,
-	HashRate:        hashRateChart,
-	POWDifficulty:   powDifficultyChart,
-	TicketPrice:     ticketPriceChart,
-	TxCount:         txCountChart,
-	Fees:            feesChart,
-	AnonymitySet:    anonymitySetChart,
-	TicketPoolSize:  ticketPoolSizeChart,
-	TicketPoolValue: poolValueChart,
-	WindMissedVotes: missedVotesChart,
-	PercentStaked:   stakedCoinsChart,
+	BlockSize:         blockSizeChart,
+	BlockChainSize:    blockchainSizeChart,
+	ChainWork:         chainWorkChart,
+	CoinSupply:        coinSupplyChart,
+	DurationBTW:       durationBTWChart,
+	HashRate:          hashRateChart,
+	POWDifficulty:     powDifficultyChart,
+	TicketPrice:       ticketPriceChart,
+	TxCount:           txCountChart,
+	Fees:              feesChart,
+	AnonymitySet:      anonymitySetChart,
+	TicketPoolSize:    ticketPoolSizeChart,
+	TicketPoolValue:   poolValueChart,
+	TicketPoolPrice:   poolPriceChart,
+	TxTypeCount:       txTypeCountChart,
+	TxTypeVolume:      txTypeVolumeChart,
+	WindMissedVotes:   missedVotesChart,
+	PercentStaked:     stakedCoinsChart,
+	VoteParticipation: voteParticipationChart,
+	TicketFees:        ticketFeesChart,
+	BlockFill:         blockFillChart,
+	TicketShare:       ticketShareChart,
+	TicketDemand:      ticketDemandChart,
 }
 
 // Chart will return a JSON-encoded chartResponse of the provided chart,
@@ -999,17 +1361,91 @@ func (charts *ChartData) Chart(chartID, binString, axisString string) ([]byte, e
 		return nil, UnknownChartErr
 	}
 	// Do the locking here, rather than in encode, so that the helper functions
-	// (accumulate, btw) are run under lock.
+	// (accumulate, btw) are run under lock. The cacheID is read under the same
+	// lock, since Lengthen and ReorgHandler update it with the data.
 	charts.mtx.RLock()
+	charts.cacheMtx.RLock()
+	cacheID = charts.cacheID(bin)
+	charts.cacheMtx.RUnlock()
 	data, err := maker(charts, bin, axis)
 	charts.mtx.RUnlock()
 	if err != nil {
 		return nil, err
 	}
-	charts.cacheChart(chartID, bin, axis, data)
+	charts.cacheChart(chartID, bin, axis, cacheID, data)
 	return data, nil
 }
 
+// spanSeries are the long per-block series that SpanChart serves, keyed by
+// chart ID. Each returns the chartResponse key and data set for a zoomSet.
+var spanSeries = map[string]func(*zoomSet) (string, ChartUints){
+	BlockSize: func(set *zoomSet) (string, ChartUints) { return sizeKey, set.BlockSize },
+	Fees:      func(set *zoomSet) (string, ChartUints) { return feesKey, set.Fees },
+	TxCount:   func(set *zoomSet) (string, ChartUints) { return countKey, set.TxCount },
+}
+
+// spanTier selects the bin level and zoomSet for a span starting at the given
+// UNIX time. A tier with no data yet falls back to the next finer tier. Should
+// be called under at least a (ChartData).mtx.RLock.
+func (charts *ChartData) spanTier(start, tip uint64) (binLevel, *zoomSet) {
+	age := time.Duration(0)
+	if start < tip {
+		age = time.Duration(tip-start) * time.Second
+	}
+	if age > charts.Tiers.DaySpan && len(charts.Weeks.Time) > 0 {
+		return WeekBin, charts.Weeks
+	}
+	if age > charts.Tiers.BlockSpan && len(charts.Days.Time) > 0 {
+		return DayBin, charts.Days
+	}
+	return BlockBin, charts.Blocks
+}
+
+// SpanChart returns a JSON-encoded chartResponse of the block size, fees, or
+// transaction count chart with the points from start to end, in UNIX seconds.
+// An end of zero is the time of the best block. The resolution is selected
+// from the configured Tiers by how far before the best block the span starts,
+// so that long spans are served from the pre-aggregated days or weeks. The
+// response includes the selected bin and both the time and height of each
+// point.
+func (charts *ChartData) SpanChart(chartID string, start, end uint64) ([]byte, error) {
+	series, found := spanSeries[chartID]
+	if !found {
+		return nil, UnknownChartErr
+	}
+
+	charts.mtx.RLock()
+	defer charts.mtx.RUnlock()
+	if len(charts.Blocks.Time) == 0 {
+		return nil, fmt.Errorf("no chart data")
+	}
+	tip := charts.Blocks.Time[len(charts.Blocks.Time)-1]
+	if end == 0 || end > tip {
+		end = tip
+	}
+	if start > end {
+		return nil, InvalidSpanErr
+	}
+
+	bin, set := charts.spanTier(start, tip)
+	times := set.Time
+	first := sort.Search(len(times), func(i int) bool { return times[i] >= start })
+	last := sort.Search(len(times), func(i int) bool { return times[i] > end })
+	key, data := series(set)
+	// The blocks may be lengthened by an updater before they are validated.
+	if _, err := ValidateLengths(times, set.Height, data); err != nil {
+		return nil, err
+	}
+	if last < first {
+		last = first
+	}
+	return encode(lengtherMap{
+		timeKey:   times[first:last],
+		heightKey: set.Height[first:last],
+		key:       data[first:last],
+	}, chartResponse{binKey: bin})
+}
+
 // Encode the data sets. Optionally add arbitrary additional data as part of the
 // chartResponse seed. A nil seed is allowed.
 func encode(sets lengtherMap, seed chartResponse) ([]byte, error) {
@@ -1128,6 +1564,85 @@ func blockSizeChart(charts *ChartData, bin binLevel, axis axisType) ([]byte, err
 	return nil, InvalidBinErr
 }
 
+// blockFillChart is the size of the blocks as a fraction of the maximum block
+// size, and the size of the mempool backlog when the blocks were mined. Day
+// binned data is the total size of the day's blocks as a fraction of the total
+// maximum size, and the average backlog.
+func blockFillChart(charts *ChartData, bin binLevel, axis axisType) ([]byte, error) {
+	seed := binAxisSeed(bin, axis)
+	var set *zoomSet
+	switch bin {
+	case BlockBin:
+		set = charts.Blocks
+	case DayBin:
+		set = charts.Days
+	default:
+		return nil, InvalidBinErr
+	}
+
+	var maxSize uint64
+	if charts.chainParams != nil {
+		for _, size := range charts.chainParams.MaximumBlockSizes {
+			if uint64(size) > maxSize {
+				maxSize = uint64(size)
+			}
+		}
+	}
+	fill := newChartFloats(len(set.BlockSize))
+	if maxSize > 0 {
+		var prevHeight int64 = -1
+		for i, size := range set.BlockSize {
+			numBlocks := uint64(1)
+			if bin == DayBin {
+				numBlocks = uint64(int64(set.Height[i]) - prevHeight)
+				prevHeight = int64(set.Height[i])
+			}
+			fill = append(fill, float64(size)/float64(numBlocks*maxSize))
+		}
+	}
+
+	series := lengtherMap{
+		fillKey:    fill,
+		backlogKey: set.Backlog,
+	}
+	switch {
+	case axis == TimeAxis:
+		series[timeKey] = set.Time
+	case bin == DayBin:
+		series[heightKey] = set.Height
+	}
+	return encode(series, seed)
+}
+
+// ticketShareChart is the number of live tickets by purchase type: solo,
+// pooled (VSP) and other, such as split tickets. Day binned data is the daily
+// average.
+func ticketShareChart(charts *ChartData, bin binLevel, axis axisType) ([]byte, error) {
+	seed := binAxisSeed(bin, axis)
+	var set *zoomSet
+	switch bin {
+	case BlockBin:
+		set = charts.Blocks
+	case DayBin:
+		set = charts.Days
+	default:
+		return nil, InvalidBinErr
+	}
+
+	series := lengtherMap{
+		soloKey:   set.SoloLive,
+		pooledKey: set.PooledLive,
+		otherKey:  set.OtherLive,
+	}
+	switch {
+	case axis == TimeAxis:
+		series[timeKey] = set.Time
+	case bin == DayBin:
+		series[heightKey] = set.Height
+	}
+	return encode(series, seed)
+}
+
 func blockchainSizeChart(charts *ChartData, bin binLevel, axis axisType) ([]byte, error) {
 	seed := binAxisSeed(bin, axis)
 	switch bin {
@@ -1229,6 +1744,89 @@ func coinSupplyChart(charts *ChartData, bin binLevel, axis axisType) ([]byte, er
 	return nil, InvalidBinErr
 }
 
+const (
+	// projectionBlockTimeWindow is the number of the most recent blocks used to
+	// compute the average block time for coin supply projections.
+	projectionBlockTimeWindow = 4032
+
+	// maxProjectionDays limits the length of a coin supply projection.
+	maxProjectionDays = 100 * 365
+)
+
+// CoinSupplyProjection returns a JSON-encoded chartResponse with the observed
+// coin supply series, binned by day, and a daily projection of the coin supply
+// from the best block to the specified time. The projection is computed from
+// the network's subsidy schedule, assuming all votes are cast, and the average
+// block time of recent blocks.
+func (charts *ChartData) CoinSupplyProjection(until time.Time) ([]byte, error) {
+	if charts.chainParams == nil {
+		return nil, fmt.Errorf("no chain parameters")
+	}
+
+	charts.mtx.RLock()
+	defer charts.mtx.RUnlock()
+
+	tipHeight := int64(len(charts.Blocks.Time)) - 1
+	if tipHeight < 0 || len(charts.Blocks.NewAtoms) < len(charts.Blocks.Time) {
+		return nil, fmt.Errorf("coin supply data not available")
+	}
+	tipTime := int64(charts.Blocks.Time[tipHeight])
+	if until.Unix() <= tipTime {
+		return nil, fmt.Errorf("projection end time must be after the best block time")
+	}
+	days := (until.Unix() - tipTime) / aDay
+	if days > maxProjectionDays {
+		days = maxProjectionDays
+	}
+
+	// Use the observed average block time if there are enough blocks.
+	blockTime := int64(charts.chainParams.TargetTimePerBlock.Seconds())
+	if tipHeight > projectionBlockTimeWindow {
+		start := charts.Blocks.Time[tipHeight-projectionBlockTimeWindow]
+		if avg := (tipTime - int64(start)) / projectionBlockTimeWindow; avg > 0 {
+			blockTime = avg
+		}
+	}
+
+	supply := accumulate(charts.Blocks.NewAtoms[:tipHeight+1])
+	tipSupply := supply[tipHeight]
+
+	projTimes := make(ChartUints, 0, days+1)
+	projHeights := make(ChartUints, 0, days+1)
+	projSupply := make(ChartUints, 0, days+1)
+	projTimes = append(projTimes, uint64(tipTime))
+	projHeights = append(projHeights, uint64(tipHeight))
+	projSupply = append(projSupply, tipSupply)
+
+	height, total := tipHeight, int64(tipSupply)
+	for day := int64(1); day <= days; day++ {
+		nextHeight := tipHeight + day*aDay/blockTime
+		total += txhelpers.SubsidySum(height, nextHeight, charts.chainParams)
+		height = nextHeight
+		projTimes = append(projTimes, uint64(tipTime+day*aDay))
+		projHeights = append(projHeights, uint64(height))
+		projSupply = append(projSupply, uint64(total))
+	}
+
+	observedLen := len(charts.Days.Time)
+	if l := len(charts.Days.NewAtoms); l < observedLen {
+		observedLen = l
+	}
+
+	return json.Marshal(chartResponse{
+		observedKey: chartResponse{
+			timeKey:   charts.Days.Time[:observedLen],
+			supplyKey: accumulate(charts.Days.NewAtoms[:observedLen]),
+		},
+		projectedKey: chartResponse{
+			timeKey:   projTimes,
+			heightKey: projHeights,
+			supplyKey: projSupply,
+		},
+		blockTimeKey: blockTime,
+	})
+}
+
 func durationBTWChart(charts *ChartData, bin binLevel, axis axisType) ([]byte, error) {
 	seed := binAxisSeed(bin, axis)
 	switch bin {
@@ -1399,6 +1997,54 @@ func ticketPriceChart(charts *ChartData, _ binLevel, axis axisType) ([]byte, err
 	}
 }
 
+// priceElasticity is the arc (midpoint) price elasticity of ticket demand
+// between each stake difficulty window and the one before it, i.e. the
+// percent change in tickets purchased divided by the percent change in ticket
+// price. The first window, and any window where the price or the purchase count
+// did not change, has an elasticity of zero.
+func priceElasticity(prices, counts ChartUints) ChartFloats {
+	n := len(prices)
+	if len(counts) < n {
+		n = len(counts)
+	}
+	elasticity := make(ChartFloats, n)
+	for i := 1; i < n; i++ {
+		p0, p1 := float64(prices[i-1]), float64(prices[i])
+		q0, q1 := float64(counts[i-1]), float64(counts[i])
+		if p0 == p1 || q0+q1 == 0 {
+			continue
+		}
+		dq := (q1 - q0) / ((q1 + q0) / 2)
+		dp := (p1 - p0) / ((p1 + p0) / 2)
+		elasticity[i] = dq / dp
+	}
+	return elasticity
+}
+
+// ticketDemandChart relates the ticket price of each stake difficulty window to
+// the number of tickets purchased in that window, along with the price
+// elasticity of demand between consecutive windows.
+func ticketDemandChart(charts *ChartData, _ binLevel, axis axisType) ([]byte, error) {
+	seed := chartResponse{windowKey: charts.DiffInterval}
+	windows := charts.Windows
+	elasticity := priceElasticity(windows.TicketPrice, windows.StakeCount)
+	switch axis {
+	case HeightAxis:
+		return encode(lengtherMap{
+			priceKey:      windows.TicketPrice,
+			countKey:      windows.StakeCount,
+			elasticityKey: elasticity,
+		}, seed)
+	default:
+		return encode(lengtherMap{
+			timeKey:       windows.Time,
+			priceKey:      windows.TicketPrice,
+			countKey:      windows.StakeCount,
+			elasticityKey: elasticity,
+		}, seed)
+	}
+}
+
 func txCountChart(charts *ChartData, bin binLevel, axis axisType) ([]byte, error) {
 	seed := binAxisSeed(bin, axis)
 	switch bin {
@@ -1560,6 +2206,88 @@ func poolValueChart(charts *ChartData, bin binLevel, axis axisType) ([]byte, err
 	return nil, InvalidBinErr
 }
 
+// poolPriceChart is the value-weighted average price of the live tickets.
+func poolPriceChart(charts *ChartData, bin binLevel, axis axisType) ([]byte, error) {
+	seed := binAxisSeed(bin, axis)
+	switch bin {
+	case BlockBin:
+		switch axis {
+		case HeightAxis:
+			return encode(lengtherMap{
+				priceKey: charts.Blocks.PoolPrice,
+			}, seed)
+		default:
+			return encode(lengtherMap{
+				timeKey:  charts.Blocks.Time,
+				priceKey: charts.Blocks.PoolPrice,
+			}, seed)
+		}
+	case DayBin:
+		switch axis {
+		case HeightAxis:
+			return encode(lengtherMap{
+				heightKey: charts.Days.Height,
+				priceKey:  charts.Days.PoolPrice,
+			}, seed)
+		default:
+			return encode(lengtherMap{
+				timeKey:  charts.Days.Time,
+				priceKey: charts.Days.PoolPrice,
+			}, seed)
+		}
+	}
+	return nil, InvalidBinErr
+}
+
+// txTypeCountChart is the number of regular transactions, tickets, votes, and
+// revocations, for a stacked chart.
+func txTypeCountChart(charts *ChartData, bin binLevel, axis axisType) ([]byte, error) {
+	return txTypesChart(charts, bin, axis, func(set *zoomSet) lengtherMap {
+		return lengtherMap{
+			regularKey: set.RegularCount,
+			ticketsKey: set.TicketCount,
+			votesKey:   set.VoteCount,
+			revokesKey: set.RevokeCount,
+		}
+	})
+}
+
+// txTypeVolumeChart is the total sent atoms of the regular transactions,
+// tickets, votes, and revocations, for a stacked chart.
+func txTypeVolumeChart(charts *ChartData, bin binLevel, axis axisType) ([]byte, error) {
+	return txTypesChart(charts, bin, axis, func(set *zoomSet) lengtherMap {
+		return lengtherMap{
+			regularKey: set.RegularVolume,
+			ticketsKey: set.TicketVolume,
+			votesKey:   set.VoteVolume,
+			revokesKey: set.RevokeVolume,
+		}
+	})
+}
+
+// txTypesChart encodes the transaction type series selected by seriesFunc with
+// the x-axis data for the bin and axis.
+func txTypesChart(charts *ChartData, bin binLevel, axis axisType, seriesFunc func(*zoomSet) lengtherMap) ([]byte, error) {
+	seed := binAxisSeed(bin, axis)
+	var set *zoomSet
+	switch bin {
+	case BlockBin:
+		set = charts.Blocks
+	case DayBin:
+		set = charts.Days
+	default:
+		return nil, InvalidBinErr
+	}
+	series := seriesFunc(set)
+	switch {
+	case axis == TimeAxis:
+		series[timeKey] = set.Time
+	case bin == DayBin:
+		series[heightKey] = set.Height
+	}
+	return encode(series, seed)
+}
+
 func missedVotesChart(charts *ChartData, _ binLevel, axis axisType) ([]byte, error) {
 	prestakeWindows := int(charts.StartPOS / charts.DiffInterval)
 	if prestakeWindows >= len(charts.Windows.MissedVotes) ||
@@ -1583,6 +2311,32 @@ func missedVotesChart(charts *ChartData, _ binLevel, axis axisType) ([]byte, err
 	}
 }
 
+// voteParticipationChart is the fraction of the expected votes that were cast
+// in each stake difficulty window, starting with the window containing the
+// stake validation height.
+func voteParticipationChart(charts *ChartData, _ binLevel, axis axisType) ([]byte, error) {
+	prestakeWindows := int(charts.StartPOS / charts.DiffInterval)
+	if prestakeWindows >= len(charts.Windows.VoteParticipation) ||
+		prestakeWindows >= len(charts.Windows.Time) {
+		prestakeWindows = 0
+	}
+	seed := chartResponse{
+		windowKey: charts.DiffInterval,
+		offsetKey: prestakeWindows,
+	}
+	switch axis {
+	case HeightAxis:
+		return encode(lengtherMap{
+			voteRatioKey: charts.Windows.VoteParticipation[prestakeWindows:],
+		}, seed)
+	default:
+		return encode(lengtherMap{
+			timeKey:      charts.Windows.Time[prestakeWindows:],
+			voteRatioKey: charts.Windows.VoteParticipation[prestakeWindows:],
+		}, seed)
+	}
+}
+
 func stakedCoinsChart(charts *ChartData, bin binLevel, axis axisType) ([]byte, error) {
 	seed := binAxisSeed(bin, axis)
 	switch bin {
@@ -1618,3 +2372,25 @@ func stakedCoinsChart(charts *ChartData, bin binLevel, axis axisType) ([]byte, e
 	}
 	return nil, InvalidBinErr
 }
+
+func ticketFeesChart(charts *ChartData, _ binLevel, axis axisType) ([]byte, error) {
+	seed := chartResponse{windowKey: charts.DiffInterval}
+	windows := charts.Windows
+	switch axis {
+	case HeightAxis:
+		return encode(lengtherMap{
+			minKey:     windows.TicketFeeMin,
+			medianKey:  windows.TicketFeeMedian,
+			maxKey:     windows.TicketFeeMax,
+			closingKey: windows.TicketFeeClosing,
+		}, seed)
+	default:
+		return encode(lengtherMap{
+			timeKey:    windows.Time,
+			minKey:     windows.TicketFeeMin,
+			medianKey:  windows.TicketFeeMedian,
+			maxKey:     windows.TicketFeeMax,
+			closingKey: windows.TicketFeeClosing,
+		}, seed)
+	}
+}
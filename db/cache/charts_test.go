@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"math"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -59,6 +60,7 @@ func TestChartsCache(t *testing.T) {
 		charts.Blocks.Time = append(charts.Blocks.Time, t)
 		charts.Blocks.PoolSize = append(charts.Blocks.PoolSize, v)
 		charts.Blocks.PoolValue = append(charts.Blocks.PoolValue, v)
+		charts.Blocks.PoolPrice = append(charts.Blocks.PoolPrice, v)
 		charts.Blocks.BlockSize = append(charts.Blocks.BlockSize, v)
 		charts.Blocks.TxCount = append(charts.Blocks.TxCount, v)
 		charts.Blocks.NewAtoms = append(charts.Blocks.NewAtoms, v)
@@ -66,11 +68,27 @@ func TestChartsCache(t *testing.T) {
 		charts.Blocks.Fees = append(charts.Blocks.Fees, v)
 		charts.Blocks.TotalMixed = append(charts.Blocks.TotalMixed, v)
 		charts.Blocks.AnonymitySet = append(charts.Blocks.AnonymitySet, v)
+		charts.Blocks.RegularCount = append(charts.Blocks.RegularCount, v)
+		charts.Blocks.TicketCount = append(charts.Blocks.TicketCount, v)
+		charts.Blocks.VoteCount = append(charts.Blocks.VoteCount, v)
+		charts.Blocks.RevokeCount = append(charts.Blocks.RevokeCount, v)
+		charts.Blocks.RegularVolume = append(charts.Blocks.RegularVolume, v)
+		charts.Blocks.TicketVolume = append(charts.Blocks.TicketVolume, v)
+		charts.Blocks.VoteVolume = append(charts.Blocks.VoteVolume, v)
+		charts.Blocks.RevokeVolume = append(charts.Blocks.RevokeVolume, v)
+		charts.Blocks.Backlog = append(charts.Blocks.Backlog, v)
+		charts.Blocks.SoloLive = append(charts.Blocks.SoloLive, v)
+		charts.Blocks.PooledLive = append(charts.Blocks.PooledLive, v)
+		charts.Blocks.OtherLive = append(charts.Blocks.OtherLive, v)
 		charts.Windows.Time = ChartUints{0}
 		charts.Windows.PowDiff = ChartFloats{0}
 		charts.Windows.TicketPrice = ChartUints{0}
 		charts.Windows.StakeCount = ChartUints{0}
 		charts.Windows.MissedVotes = ChartUints{0}
+		charts.Windows.TicketFeeMin = ChartUints{0}
+		charts.Windows.TicketFeeMedian = ChartUints{0}
+		charts.Windows.TicketFeeMax = ChartUints{0}
+		charts.Windows.TicketFeeClosing = ChartUints{0}
 	}
 
 	seedUints := ChartUints{1, 2, 3, 4, 5, 6}
@@ -141,6 +159,7 @@ func TestChartsCache(t *testing.T) {
 		comp("Time before read", charts.Blocks.Time, seedTimes, false)
 		comp("PoolSize before read", charts.Blocks.PoolSize, seedUints, false)
 		comp("PoolValue before read", charts.Blocks.PoolValue, seedUints, false)
+		comp("PoolPrice before read", charts.Blocks.PoolPrice, seedUints, false)
 		comp("BlockSize before read", charts.Blocks.BlockSize, seedUints, false)
 		comp("TxCount before read", charts.Blocks.TxCount, seedUints, false)
 		comp("NewAtoms before read", charts.Blocks.NewAtoms, seedUints, false)
@@ -158,6 +177,7 @@ func TestChartsCache(t *testing.T) {
 		comp("Time after read", charts.Blocks.Time, seedTimes, true)
 		comp("PoolSize after read", charts.Blocks.PoolSize, seedUints, true)
 		comp("PoolValue after read", charts.Blocks.PoolValue, seedUints, true)
+		comp("PoolPrice after read", charts.Blocks.PoolPrice, seedUints, true)
 		comp("BlockSize after read", charts.Blocks.BlockSize, seedUints, true)
 		comp("TxCount after read", charts.Blocks.TxCount, seedUints, true)
 		comp("NewAtoms after read", charts.Blocks.NewAtoms, seedUints, true)
@@ -170,6 +190,7 @@ func TestChartsCache(t *testing.T) {
 		comp("Time after Lengthen", charts.Days.Time, ChartUints{0, aDay, 2 * aDay}, true)
 		comp("PoolSize after Lengthen", charts.Days.PoolSize, uintDaysAvg, true)
 		comp("PoolValue after Lengthen", charts.Days.PoolValue, uintDaysAvg, true)
+		comp("PoolPrice after Lengthen", charts.Days.PoolPrice, uintDaysAvg, true)
 		comp("BlockSize after Lengthen", charts.Days.BlockSize, uintDaysSum, true)
 		comp("TxCount after Lengthen", charts.Days.TxCount, uintDaysSum, true)
 		comp("NewAtoms after Lengthen", charts.Days.NewAtoms, uintDaysSum, true)
@@ -178,6 +199,16 @@ func TestChartsCache(t *testing.T) {
 		comp("Fees after Lengthen", charts.Days.Fees, uintDaysSum, true)
 		comp("TotalMixed after Lengthen", charts.Days.TotalMixed, uintDaysSum, true)
 		comp("AnonymitySet after Lengthen", charts.Days.AnonymitySet, uintDaysAvg, true)
+		comp("RegularCount after Lengthen", charts.Days.RegularCount, uintDaysSum, true)
+		comp("TicketCount after Lengthen", charts.Days.TicketCount, uintDaysSum, true)
+		comp("VoteCount after Lengthen", charts.Days.VoteCount, uintDaysSum, true)
+		comp("RevokeCount after Lengthen", charts.Days.RevokeCount, uintDaysSum, true)
+		comp("RegularVolume after Lengthen", charts.Days.RegularVolume, uintDaysSum, true)
+		comp("TicketVolume after Lengthen", charts.Days.TicketVolume, uintDaysSum, true)
+		comp("VoteVolume after Lengthen", charts.Days.VoteVolume, uintDaysSum, true)
+		comp("RevokeVolume after Lengthen", charts.Days.RevokeVolume, uintDaysSum, true)
+		comp("Backlog after Lengthen", charts.Days.Backlog, uintDaysAvg, true)
+		comp("SoloLive after Lengthen", charts.Days.SoloLive, uintDaysAvg, true)
 
 		// An additional call to lengthen should not add any data.
 		timeLen := len(charts.Days.Time)
@@ -276,12 +307,16 @@ func TestChartReorg(t *testing.T) {
 	}
 	resetCharts := func() {
 		charts.Windows = &windowSet{
-			cacheID:     0,
-			Time:        newUints(),
-			PowDiff:     newFloats(),
-			TicketPrice: newUints(),
-			StakeCount:  newUints(),
-			MissedVotes: newUints(),
+			cacheID:          0,
+			Time:             newUints(),
+			PowDiff:          newFloats(),
+			TicketPrice:      newUints(),
+			StakeCount:       newUints(),
+			MissedVotes:      newUints(),
+			TicketFeeMin:     newUints(),
+			TicketFeeMedian:  newUints(),
+			TicketFeeMax:     newUints(),
+			TicketFeeClosing: newUints(),
 		}
 		charts.Days = &zoomSet{
 			cacheID:   0,
@@ -289,17 +324,23 @@ func TestChartReorg(t *testing.T) {
 			Time:      newUints(),
 			PoolSize:  newUints(),
 			PoolValue: newUints(),
+			PoolPrice: newUints(),
 			BlockSize: newUints(),
 			TxCount:   newUints(),
 			NewAtoms:  newUints(),
 			Chainwork: newUints(),
 			Fees:      newUints(),
 		}
+		charts.Weeks = &zoomSet{
+			Height: ChartUints{1},
+			Time:   ChartUints{1},
+		}
 		charts.Blocks = &zoomSet{
 			cacheID:    0,
 			Time:       newUints(),
 			PoolSize:   newUints(),
 			PoolValue:  newUints(),
+			PoolPrice:  newUints(),
 			BlockSize:  newUints(),
 			TxCount:    newUints(),
 			NewAtoms:   newUints(),
@@ -335,6 +376,10 @@ func TestChartReorg(t *testing.T) {
 		if charts.Days.Time.Length() != newDayLen {
 			t.Errorf("unexpected days length %d", charts.Days.Time.Length())
 		}
+		// Weeks beyond the remaining days are dropped
+		if charts.Weeks.Time.Length() != newDayLen/daysPerWeek {
+			t.Errorf("unexpected weeks length %d", charts.Weeks.Time.Length())
+		}
 		// Reorg snips last window
 		if charts.Windows.Time.Length() != newWindowLen {
 			t.Errorf("unexpected windows length %d", charts.Windows.Time.Length())
@@ -348,3 +393,365 @@ func TestChartReorg(t *testing.T) {
 	resetCharts()
 	testReorg(2, 2, 1, 1, 2)
 }
+
+func TestChartCacheReorg(t *testing.T) {
+	charts := NewChartData(context.Background(), 0, chaincfg.MainNetParams())
+	uintsType, floatsType := reflect.TypeOf(ChartUints{}), reflect.TypeOf(ChartFloats{})
+	// appendAll appends v to every data set of a zoomSet or windowSet.
+	appendAll := func(set interface{}, v uint64) {
+		rv := reflect.ValueOf(set).Elem()
+		for i := 0; i < rv.NumField(); i++ {
+			switch f := rv.Field(i); f.Type() {
+			case uintsType:
+				f.Set(reflect.Append(f, reflect.ValueOf(v)))
+			case floatsType:
+				f.Set(reflect.Append(f, reflect.ValueOf(float64(v))))
+			}
+		}
+	}
+	for _, v := range []uint64{1, 2, 3} {
+		appendAll(charts.Blocks, v)
+	}
+	appendAll(charts.Windows, 5)
+	appendAll(charts.Windows, 6)
+	if err := charts.Lengthen(); err != nil {
+		t.Fatal(err)
+	}
+
+	var resp struct {
+		Price []uint64 `json:"price"`
+		Count []uint64 `json:"count"`
+	}
+	checkChart := func(chartID, bin string, key *[]uint64, want []uint64) {
+		t.Helper()
+		data, err := charts.Chart(chartID, bin, string(HeightAxis))
+		if err != nil {
+			t.Fatal(err)
+		}
+		*key = nil
+		if err = json.Unmarshal(data, &resp); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(*key, want) {
+			t.Errorf("%s chart: expected %v, found %v", chartID, want, *key)
+		}
+	}
+	checkChart(TicketPrice, "", &resp.Price, []uint64{5, 6})
+	checkChart(TxCount, string(BlockBin), &resp.Count, []uint64{1, 2, 3})
+
+	// Replace the last block with another at the same height, which changes
+	// the last window without changing its start time.
+	charts.ReorgHandler(&txhelpers.ReorgData{
+		NewChainHeight: 2,
+		NewChain:       make([]chainhash.Hash, 1),
+	})
+	checkChart(TicketPrice, "", &resp.Price, []uint64{5})
+	checkChart(TxCount, string(BlockBin), &resp.Count, []uint64{1, 2})
+
+	appendAll(charts.Blocks, 4)
+	appendAll(charts.Windows, 7)
+	charts.Windows.Time[1] = 6
+	if err := charts.Lengthen(); err != nil {
+		t.Fatal(err)
+	}
+	checkChart(TicketPrice, "", &resp.Price, []uint64{5, 7})
+	checkChart(TxCount, string(BlockBin), &resp.Count, []uint64{1, 2, 4})
+}
+
+func TestCoinSupplyProjection(t *testing.T) {
+	params := chaincfg.MainNetParams()
+	charts := NewChartData(context.Background(), 0, params)
+
+	// Three blocks, five minutes apart, mined on the first day.
+	start := uint64(params.GenesisBlock.Header.Timestamp.Unix())
+	charts.Blocks.Time = ChartUints{start, start + 300, start + 600}
+	charts.Blocks.NewAtoms = ChartUints{0, 100, 200}
+	charts.Days.Time = ChartUints{start}
+	charts.Days.NewAtoms = ChartUints{300}
+
+	until := time.Unix(int64(start)+600+3*aDay, 0)
+	data, err := charts.CoinSupplyProjection(until)
+	if err != nil {
+		t.Fatalf("CoinSupplyProjection error: %v", err)
+	}
+
+	var resp struct {
+		Observed struct {
+			Supply []uint64 `json:"supply"`
+		} `json:"observed"`
+		Projected struct {
+			Time   []uint64 `json:"t"`
+			Height []uint64 `json:"h"`
+			Supply []uint64 `json:"supply"`
+		} `json:"projected"`
+		BlockTime int64 `json:"block_time"`
+	}
+	if err = json.Unmarshal(data, &resp); err != nil {
+		t.Fatalf("json.Unmarshal error: %v", err)
+	}
+
+	if len(resp.Observed.Supply) != 1 || resp.Observed.Supply[0] != 300 {
+		t.Errorf("unexpected observed supply %v", resp.Observed.Supply)
+	}
+	if len(resp.Projected.Supply) != 4 {
+		t.Fatalf("expected 4 projected points, got %d", len(resp.Projected.Supply))
+	}
+	if resp.BlockTime != int64(params.TargetTimePerBlock.Seconds()) {
+		t.Errorf("expected the target block time, got %d", resp.BlockTime)
+	}
+
+	blocksPerDay := uint64(aDay / resp.BlockTime)
+	for i := range resp.Projected.Supply {
+		wantHeight := 2 + uint64(i)*blocksPerDay
+		if resp.Projected.Height[i] != wantHeight {
+			t.Errorf("point %d: expected height %d, got %d", i, wantHeight, resp.Projected.Height[i])
+		}
+		wantSupply := 300 + uint64(txhelpers.SubsidySum(2, int64(wantHeight), params))
+		if resp.Projected.Supply[i] != wantSupply {
+			t.Errorf("point %d: expected supply %d, got %d", i, wantSupply, resp.Projected.Supply[i])
+		}
+	}
+
+	if _, err = charts.CoinSupplyProjection(time.Unix(int64(start), 0)); err == nil {
+		t.Errorf("expected an error for a projection into the past")
+	}
+}
+
+func TestVoteParticipation(t *testing.T) {
+	charts := NewChartData(context.Background(), 0, chaincfg.MainNetParams())
+	// Mainnet stake validation height is 4096, in the 29th 144-block window.
+	charts.Windows.MissedVotes = make(ChartUints, 30)
+	charts.Windows.MissedVotes[28] = 40 // 80 voting blocks, 400 expected votes
+	charts.Windows.MissedVotes[29] = 72 // 720 expected votes
+
+	charts.lengthenVoteParticipation()
+	participation := charts.Windows.VoteParticipation
+	if len(participation) != 30 {
+		t.Fatalf("expected 30 windows, found %d", len(participation))
+	}
+	if participation[27] != 0 {
+		t.Errorf("expected zero participation before stake validation, found %f", participation[27])
+	}
+	if participation[28] != 0.9 || participation[29] != 0.9 {
+		t.Errorf("expected participation of 0.9, found %f and %f", participation[28], participation[29])
+	}
+
+	// Only new windows are computed, and snipped windows are recomputed.
+	charts.Windows.VoteParticipation[29] = 0.5
+	charts.Windows.MissedVotes = append(charts.Windows.MissedVotes, 0)
+	charts.lengthenVoteParticipation()
+	if charts.Windows.VoteParticipation[29] != 0.5 || charts.Windows.VoteParticipation[30] != 1 {
+		t.Errorf("unexpected participation after lengthening: %v", charts.Windows.VoteParticipation[29:])
+	}
+	charts.Windows.Snip(29)
+	charts.Windows.MissedVotes = append(charts.Windows.MissedVotes, 72)
+	charts.lengthenVoteParticipation()
+	if len(charts.Windows.VoteParticipation) != 30 || charts.Windows.VoteParticipation[29] != 0.9 {
+		t.Errorf("unexpected participation after snipping: %v", charts.Windows.VoteParticipation[28:])
+	}
+}
+
+func TestBlockFillChart(t *testing.T) {
+	charts := NewChartData(context.Background(), 0, chaincfg.MainNetParams())
+	maxSize := uint64(chaincfg.MainNetParams().MaximumBlockSizes[0])
+	charts.Blocks.Time = ChartUints{100, 200, 300}
+	charts.Blocks.BlockSize = ChartUints{maxSize / 2, maxSize, maxSize / 4}
+	charts.Blocks.Backlog = ChartUints{0, 5000, 100}
+	charts.Days.Height = ChartUints{1, 2}
+	charts.Days.Time = ChartUints{0, aDay}
+	charts.Days.BlockSize = ChartUints{maxSize * 3 / 2, maxSize / 4}
+	charts.Days.Backlog = ChartUints{2500, 100}
+
+	var resp struct {
+		Fill    []float64 `json:"fill"`
+		Backlog []uint64  `json:"backlog"`
+		Height  []uint64  `json:"h"`
+	}
+	data, err := blockFillChart(charts, BlockBin, HeightAxis)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = json.Unmarshal(data, &resp); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(resp.Fill, []float64{0.5, 1, 0.25}) ||
+		!reflect.DeepEqual(resp.Backlog, []uint64{0, 5000, 100}) {
+		t.Errorf("unexpected block fill data %+v", resp)
+	}
+
+	// A day's fill is relative to the maximum size of all of its blocks.
+	data, err = blockFillChart(charts, DayBin, HeightAxis)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = json.Unmarshal(data, &resp); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(resp.Fill, []float64{0.75, 0.25}) ||
+		!reflect.DeepEqual(resp.Height, []uint64{1, 2}) {
+		t.Errorf("unexpected day fill data %+v", resp)
+	}
+
+	if _, err = blockFillChart(charts, WindowBin, HeightAxis); err != InvalidBinErr {
+		t.Errorf("expected InvalidBinErr, got %v", err)
+	}
+}
+
+func TestBlockStatsSince(t *testing.T) {
+	charts := NewChartData(context.Background(), 0, chaincfg.MainNetParams())
+	charts.Blocks.Time = ChartUints{100, 200, 300, 400}
+	charts.Blocks.TxCount = ChartUints{1, 2, 3, 4}
+	charts.Blocks.BlockSize = ChartUints{10, 20, 30, 40}
+	charts.Blocks.NewAtoms = ChartUints{5, 5, 5, 5}
+	charts.Blocks.Fees = ChartUints{1, 1, 1} // not yet loaded for the tip
+
+	stats := charts.BlockStatsSince(200)
+	want := BlockStats{Blocks: 2, Transactions: 7, Bytes: 70, Fees: 1, NewAtoms: 10}
+	if *stats != want {
+		t.Errorf("expected %+v, got %+v", want, *stats)
+	}
+
+	if stats = charts.BlockStatsSince(400); stats.Blocks != 0 {
+		t.Errorf("expected no blocks, got %d", stats.Blocks)
+	}
+}
+
+func TestSpanChart(t *testing.T) {
+	charts := NewChartData(context.Background(), 0, chaincfg.MainNetParams())
+	charts.Tiers = ChartTiers{
+		BlockSpan: 3 * 24 * time.Hour,
+		DaySpan:   20 * 24 * time.Hour,
+	}
+
+	// 30 days of blocks, one every 6 hours, each of size 10.
+	blocks := charts.Blocks
+	for i := uint64(0); i < 30*4; i++ {
+		for _, set := range []*ChartUints{&blocks.PoolSize, &blocks.PoolValue,
+			&blocks.PoolPrice, &blocks.TxCount, &blocks.NewAtoms, &blocks.Chainwork,
+			&blocks.Fees, &blocks.TotalMixed, &blocks.AnonymitySet,
+			&blocks.RegularCount, &blocks.TicketCount, &blocks.VoteCount,
+			&blocks.RevokeCount, &blocks.RegularVolume, &blocks.TicketVolume,
+			&blocks.VoteVolume, &blocks.RevokeVolume, &blocks.Backlog,
+			&blocks.SoloLive, &blocks.PooledLive, &blocks.OtherLive} {
+			*set = append(*set, i)
+		}
+		blocks.Height = append(blocks.Height, i)
+		blocks.Time = append(blocks.Time, i*aDay/4)
+		blocks.BlockSize = append(blocks.BlockSize, 10)
+	}
+	charts.Windows.Time = ChartUints{0}
+	charts.Windows.PowDiff = ChartFloats{0}
+	charts.Windows.TicketPrice = ChartUints{0}
+	charts.Windows.StakeCount = ChartUints{0}
+	charts.Windows.MissedVotes = ChartUints{0}
+	charts.Windows.TicketFeeMin = ChartUints{0}
+	charts.Windows.TicketFeeMedian = ChartUints{0}
+	charts.Windows.TicketFeeMax = ChartUints{0}
+	charts.Windows.TicketFeeClosing = ChartUints{0}
+	if err := charts.Lengthen(); err != nil {
+		t.Fatalf("Lengthen error: %v", err)
+	}
+
+	// The last day is incomplete, so there are 29 days and 4 weeks.
+	if len(charts.Weeks.Time) != 4 {
+		t.Fatalf("expected 4 weeks, found %d", len(charts.Weeks.Time))
+	}
+	if charts.Weeks.BlockSize[0] != charts.Days.BlockSize.Sum(0, 7) || charts.Weeks.Time[1] != 7*aDay ||
+		charts.Weeks.Height[0] != 7*4-1 {
+		t.Errorf("unexpected first week size %d, second week time %d, first week height %d",
+			charts.Weeks.BlockSize[0], charts.Weeks.Time[1], charts.Weeks.Height[0])
+	}
+
+	tip := blocks.Time[len(blocks.Time)-1]
+	tests := []struct {
+		name       string
+		start, end uint64
+		bin        binLevel
+		points     int
+	}{
+		{"blocks", tip - aDay, 0, BlockBin, 5},
+		{"days", tip - 10*aDay, tip - 5*aDay, DayBin, 5},
+		{"weeks", 0, 0, WeekBin, 4},
+	}
+	for _, tt := range tests {
+		data, err := charts.SpanChart(BlockSize, tt.start, tt.end)
+		if err != nil {
+			t.Fatalf("%s: SpanChart error: %v", tt.name, err)
+		}
+		var resp struct {
+			Bin    binLevel `json:"bin"`
+			Time   []uint64 `json:"t"`
+			Height []uint64 `json:"h"`
+			Size   []uint64 `json:"size"`
+		}
+		if err = json.Unmarshal(data, &resp); err != nil {
+			t.Fatalf("%s: json.Unmarshal error: %v", tt.name, err)
+		}
+		if resp.Bin != tt.bin {
+			t.Errorf("%s: expected bin %s, got %s", tt.name, tt.bin, resp.Bin)
+		}
+		if len(resp.Time) != tt.points || len(resp.Height) != tt.points || len(resp.Size) != tt.points {
+			t.Errorf("%s: expected %d points, got %d", tt.name, tt.points, len(resp.Time))
+		}
+	}
+
+	if _, err := charts.SpanChart(BlockSize, tip, tip-aDay); err != InvalidSpanErr {
+		t.Errorf("expected InvalidSpanErr, got %v", err)
+	}
+	if _, err := charts.SpanChart(TicketPrice, 0, 0); err != UnknownChartErr {
+		t.Errorf("expected UnknownChartErr, got %v", err)
+	}
+}
+
+func TestTicketDemandChart(t *testing.T) {
+	charts := NewChartData(context.Background(), 0, chaincfg.MainNetParams())
+	charts.Windows.Time = ChartUints{100, 200, 300, 400}
+	charts.Windows.TicketPrice = ChartUints{100, 300, 300, 100}
+	charts.Windows.StakeCount = ChartUints{20, 10, 40, 0}
+
+	var resp struct {
+		Price      []uint64  `json:"price"`
+		Count      []uint64  `json:"count"`
+		Elasticity []float64 `json:"elasticity"`
+		Time       []uint64  `json:"t"`
+		Window     uint64    `json:"window"`
+	}
+	data, err := ticketDemandChart(charts, WindowBin, TimeAxis)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = json.Unmarshal(data, &resp); err != nil {
+		t.Fatal(err)
+	}
+	// Window 1: count -2/3, price +1. Window 2: no price change. Window 3:
+	// count -2, price -1.
+	wantElasticity := []float64{0, -2.0 / 3, 0, 2}
+	if len(resp.Elasticity) != len(wantElasticity) {
+		t.Fatalf("expected %d windows, found %d", len(wantElasticity), len(resp.Elasticity))
+	}
+	for i, want := range wantElasticity {
+		if math.Abs(resp.Elasticity[i]-want) > 1e-9 {
+			t.Errorf("window %d: expected elasticity %f, found %f", i, want, resp.Elasticity[i])
+		}
+	}
+	if !reflect.DeepEqual(resp.Price, []uint64(charts.Windows.TicketPrice)) ||
+		!reflect.DeepEqual(resp.Count, []uint64(charts.Windows.StakeCount)) ||
+		!reflect.DeepEqual(resp.Time, []uint64(charts.Windows.Time)) ||
+		resp.Window != uint64(charts.DiffInterval) {
+		t.Errorf("unexpected ticket demand data %+v", resp)
+	}
+
+	// The series are truncated to the shortest window set.
+	charts.Windows.StakeCount = charts.Windows.StakeCount[:3]
+	data, err = charts.Chart(TicketDemand, "", string(HeightAxis))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Elasticity, resp.Time = nil, nil
+	if err = json.Unmarshal(data, &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Price) != 3 || len(resp.Elasticity) != 3 || resp.Time != nil {
+		t.Errorf("unexpected height axis ticket demand data %+v", resp)
+	}
+}
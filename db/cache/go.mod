@@ -2,6 +2,8 @@ module github.com/decred/dcrdata/db/cache/v3
 
 go 1.12
 
+replace github.com/decred/dcrdata/txhelpers/v4 => ../../txhelpers
+
 require (
 	github.com/decred/dcrd/chaincfg/chainhash v1.0.2
 	github.com/decred/dcrd/chaincfg/v2 v2.3.0
@@ -1,6 +1,7 @@
 package dbtypes
 
 import (
+	"encoding/hex"
 	"fmt"
 	"math"
 
@@ -53,6 +54,7 @@ func MsgBlockToDBBlock(msgBlock *wire.MsgBlock, chainParams *chaincfg.Params, ch
 		PreviousHash: blockHeader.PrevBlock.String(),
 		ChainWork:    chainWork,
 		Winners:      winners,
+		FinalState:   hex.EncodeToString(blockHeader.FinalState[:]),
 	}
 }
 
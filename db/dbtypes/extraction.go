@@ -2,10 +2,13 @@ package dbtypes
 
 import (
 	"bytes"
+	"encoding/binary"
 	"encoding/hex"
 	"fmt"
+	"math"
 
 	"github.com/decred/dcrd/blockchain/stake/v2"
+	"github.com/decred/dcrd/chaincfg/chainhash"
 	"github.com/decred/dcrd/chaincfg/v2"
 	"github.com/decred/dcrd/txscript/v2"
 	"github.com/decred/dcrd/wire"
@@ -93,6 +96,7 @@ func processTransactions(msgBlock *wire.MsgBlock, tree int8, chainParams *chainc
 			sent += txout.Value
 		}
 		fees := spent - sent
+		size := int64(tx.SerializeSize())
 		dbTx := &Tx{
 			BlockHash:        blockHash.String(),
 			BlockHeight:      int64(blockHeight),
@@ -105,10 +109,11 @@ func processTransactions(msgBlock *wire.MsgBlock, tree int8, chainParams *chainc
 			BlockIndex:       uint32(txIndex),
 			Locktime:         tx.LockTime,
 			Expiry:           tx.Expiry,
-			Size:             uint32(tx.SerializeSize()),
+			Size:             uint32(size),
 			Spent:            spent,
 			Sent:             sent,
 			Fees:             fees,
+			FeeRate:          txhelpers.FeeRate(spent, sent, size),
 			MixCount:         int32(mixCount),
 			MixDenom:         mixDenom,
 			NumVin:           uint32(len(tx.TxIn)),
@@ -135,6 +140,7 @@ func processTransactions(msgBlock *wire.MsgBlock, tree int8, chainParams *chainc
 				BlockHeight: txin.BlockHeight,
 				BlockIndex:  txin.BlockIndex,
 				ScriptHex:   txin.SignatureScript,
+				SigType:     ClassifyVinSig(tx, stake.TxType(dbTx.TxType), idx),
 				IsValid:     dbTx.IsValid,
 				IsMainchain: isMainchain,
 			})
@@ -179,3 +185,189 @@ func processTransactions(msgBlock *wire.MsgBlock, tree int8, chainParams *chainc
 
 	return dbTransactions, dbTxVouts, dbTxVins
 }
+
+// ticketCommitmentScriptLen is the length of a ticket commitment output script:
+// OP_RETURN OP_DATA_30 <20-byte hash> <8-byte amount> <2-byte fee limits>.
+const ticketCommitmentScriptLen = 32
+
+// NewTicketCommitment parses a ticket commitment output script, which must be
+// one of the odd-indexed outputs of a ticket purchase.
+func NewTicketCommitment(pkScript []byte, params *chaincfg.Params) (*TicketCommitment, error) {
+	if len(pkScript) < ticketCommitmentScriptLen {
+		return nil, fmt.Errorf("commitment script too short: %d bytes", len(pkScript))
+	}
+	addr, err := stake.AddrFromSStxPkScrCommitment(pkScript, params)
+	if err != nil {
+		return nil, err
+	}
+	amt, err := stake.AmountFromSStxPkScrCommitment(pkScript)
+	if err != nil {
+		return nil, err
+	}
+
+	// The fee limits are encoded as exponents of 2 in atoms, and each only
+	// applies if its flag is set.
+	limits := binary.LittleEndian.Uint16(pkScript[30:32])
+	feeLimit := func(flag, exp uint16) int64 {
+		if limits&flag == 0 {
+			return -1
+		}
+		if exp > 62 {
+			return math.MaxInt64
+		}
+		return int64(1) << exp
+	}
+
+	return &TicketCommitment{
+		Address:        addr.Address(),
+		Amount:         int64(amt),
+		VoteFeeLimit:   feeLimit(stake.SStxVoteFractionFlag, limits&stake.SStxVoteReturnFractionMask),
+		RevokeFeeLimit: feeLimit(stake.SStxRevFractionFlag, (limits&stake.SStxRevReturnFractionMask)>>8),
+	}, nil
+}
+
+// TicketCommitments extracts the commitments from the outputs of a ticket
+// purchase transaction. Commitments are the odd-indexed outputs.
+func TicketCommitments(vouts []*Vout, params *chaincfg.Params) ([]*TicketCommitment, error) {
+	var commitments []*TicketCommitment
+	for i := 1; i < len(vouts); i += 2 {
+		c, err := NewTicketCommitment(vouts[i].ScriptPubKey, params)
+		if err != nil {
+			return nil, fmt.Errorf("invalid commitment in output %d: %v", i, err)
+		}
+		commitments = append(commitments, c)
+	}
+	return commitments, nil
+}
+
+// ExtractScriptAnomalies returns the outputs of the block's regular and stake
+// transactions with nonstandard scripts, or with a script version other than
+// the default version 0, which are otherwise not expected on chain.
+func ExtractScriptAnomalies(msgBlock *wire.MsgBlock) []*ScriptAnomaly {
+	blockHash := msgBlock.BlockHash().String()
+	height := int64(msgBlock.Header.Height)
+
+	var anomalies []*ScriptAnomaly
+	findAnomalies := func(txs []*wire.MsgTx, tree int8) {
+		for _, tx := range txs {
+			var txHash string
+			for io, txout := range tx.TxOut {
+				class := txscript.GetScriptClass(txout.Version, txout.PkScript)
+				if class != txscript.NonStandardTy &&
+					txout.Version == 0 {
+					continue
+				}
+				if txHash == "" {
+					txHash = tx.TxHash().String()
+				}
+				anomalies = append(anomalies, &ScriptAnomaly{
+					Height:     height,
+					BlockHash:  blockHash,
+					TxHash:     txHash,
+					TxIndex:    uint32(io),
+					TxTree:     tree,
+					Value:      txout.Value,
+					Version:    txout.Version,
+					ScriptType: class.String(),
+				})
+			}
+		}
+	}
+	findAnomalies(msgBlock.Transactions, wire.TxTreeRegular)
+	findAnomalies(msgBlock.STransactions, wire.TxTreeStake)
+	return anomalies
+}
+
+// ClassifyVinSig returns the type of the signature script of the input of the
+// transaction with the given index. The inputs of votes and revocations that
+// spend the ticket are VinSigStakeSubmission spends, and the stakebase inputs
+// of votes are VinSigCoinbase spends. Otherwise, a push only script pushing a
+// signature and a public key is a VinSigPubKeyHash spend, and one ending with a
+// multisig redeem script is a VinSigMultiSig spend.
+func ClassifyVinSig(tx *wire.MsgTx, txType stake.TxType, idx int) VinSigType {
+	switch {
+	case txType == stake.TxTypeRegular && idx == 0 && tx.TxIn[0].PreviousOutPoint.Hash == (chainhash.Hash{}),
+		txType == stake.TxTypeSSGen && idx == 0:
+		return VinSigCoinbase
+	case txType == stake.TxTypeSSGen && idx == 1,
+		txType == stake.TxTypeSSRtx && idx == 0:
+		return VinSigStakeSubmission
+	}
+
+	sigScript := tx.TxIn[idx].SignatureScript
+	if !txscript.IsPushOnlyScript(sigScript) {
+		return VinSigNonStandard
+	}
+	pushes, err := txscript.PushedData(sigScript)
+	if err != nil || len(pushes) < 2 {
+		return VinSigNonStandard
+	}
+	last := pushes[len(pushes)-1]
+	if txscript.GetScriptClass(0, last) == txscript.MultiSigTy {
+		return VinSigMultiSig
+	}
+	// The public key of a pay-to-pubkey-hash spend is a compressed or
+	// uncompressed secp256k1 key, or an ed25519 key.
+	if len(pushes) == 2 && (len(last) == 33 || len(last) == 65 || len(last) == 32) {
+		return VinSigPubKeyHash
+	}
+	return VinSigNonStandard
+}
+
+// CheckBlock returns the anomalies of a main chain block under the rules.
+// Votes are only counted from the stake validation height. CheckBlock returns
+// nil for nil rules.
+func (r *BlockAnomalyRules) CheckBlock(msgBlock *wire.MsgBlock, stakeValidationHeight int64) []*BlockAnomaly {
+	if r == nil {
+		return nil
+	}
+	var anomalies []*BlockAnomaly
+	flag := func(kind string, value int64) {
+		anomalies = append(anomalies, &BlockAnomaly{
+			Height:    int64(msgBlock.Header.Height),
+			BlockHash: msgBlock.BlockHash().String(),
+			Time:      msgBlock.Header.Timestamp.Unix(),
+			Kind:      kind,
+			Value:     value,
+		})
+	}
+
+	// The coinbase is not counted as a regular transaction.
+	if numRegular := len(msgBlock.Transactions) - 1; r.EmptyBlocks && numRegular <= 0 {
+		flag(BlockAnomalyEmpty, 0)
+	}
+
+	if r.MinVotes > 0 && int64(msgBlock.Header.Height) >= stakeValidationHeight {
+		var numVotes int64
+		for _, stx := range msgBlock.STransactions {
+			if stake.IsSSGen(stx) {
+				numVotes++
+			}
+		}
+		if numVotes < int64(r.MinVotes) {
+			flag(BlockAnomalyFewVotes, numVotes)
+		}
+	}
+
+	if size := msgBlock.SerializeSize(); r.MaxSize > 0 && size > int(r.MaxSize) {
+		flag(BlockAnomalyLarge, int64(size))
+	}
+
+	return anomalies
+}
+
+// CheckReorg returns the deep reorg anomaly of a reorg removing depth blocks
+// from the main chain, with the header of the new main chain tip, or nil if
+// the reorg is not deep under the rules.
+func (r *BlockAnomalyRules) CheckReorg(depth int64, tip *wire.BlockHeader) *BlockAnomaly {
+	if r == nil || r.MaxReorgDepth <= 0 || depth <= r.MaxReorgDepth {
+		return nil
+	}
+	return &BlockAnomaly{
+		Height:    int64(tip.Height),
+		BlockHash: tip.BlockHash().String(),
+		Time:      tip.Timestamp.Unix(),
+		Kind:      BlockAnomalyDeepReorg,
+		Value:     depth,
+	}
+}
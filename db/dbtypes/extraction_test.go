@@ -0,0 +1,231 @@
+package dbtypes
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/decred/dcrd/blockchain/stake/v2"
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/chaincfg/v2"
+	"github.com/decred/dcrd/dcrutil/v2"
+	"github.com/decred/dcrd/txscript/v2"
+	"github.com/decred/dcrd/wire"
+)
+
+func TestNewTicketCommitment(t *testing.T) {
+	params := chaincfg.MainNetParams()
+	hash := bytes.Repeat([]byte{0x01}, 20)
+
+	// OP_RETURN OP_DATA_30 <hash> <amount> <fee limits>
+	makeScript := func(amount uint64, p2sh bool, limits uint16) []byte {
+		script := make([]byte, 32)
+		script[0], script[1] = 0x6a, 0x1e
+		copy(script[2:22], hash)
+		if p2sh {
+			amount |= 1 << 63
+		}
+		binary.LittleEndian.PutUint64(script[22:30], amount)
+		binary.LittleEndian.PutUint16(script[30:32], limits)
+		return script
+	}
+
+	pkhAddr, err := dcrutil.NewAddressPubKeyHash(hash, params, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shAddr, err := dcrutil.NewAddressScriptHashFromHash(hash, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		script []byte
+		want   TicketCommitment
+	}{
+		{
+			name:   "p2pkh, revoke limit only",
+			script: makeScript(1234567, false, 0x5800),
+			want: TicketCommitment{
+				Address:        pkhAddr.Address(),
+				Amount:         1234567,
+				VoteFeeLimit:   -1,
+				RevokeFeeLimit: 1 << 24,
+			},
+		},
+		{
+			name:   "p2sh, both limits",
+			script: makeScript(42, true, 0x4a50),
+			want: TicketCommitment{
+				Address:        shAddr.Address(),
+				Amount:         42,
+				VoteFeeLimit:   1 << 16,
+				RevokeFeeLimit: 1 << 10,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		c, err := NewTicketCommitment(tt.script, params)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+			continue
+		}
+		if *c != tt.want {
+			t.Errorf("%s: expected %+v, got %+v", tt.name, tt.want, *c)
+		}
+	}
+
+	if _, err = NewTicketCommitment([]byte{0x6a}, params); err == nil {
+		t.Errorf("expected an error for a short script")
+	}
+}
+
+func TestExtractScriptAnomalies(t *testing.T) {
+	// OP_DUP OP_HASH160 OP_DATA_20 <hash> OP_EQUALVERIFY OP_CHECKSIG
+	p2pkh := append([]byte{0x76, 0xa9, 0x14}, bytes.Repeat([]byte{0x01}, 20)...)
+	p2pkh = append(p2pkh, 0x88, 0xac)
+	nonstandard := []byte{0x51, 0x52, 0x93} // OP_1 OP_2 OP_ADD
+
+	tx := wire.NewMsgTx()
+	tx.AddTxOut(wire.NewTxOut(1, p2pkh))
+	tx.AddTxOut(wire.NewTxOut(2, nonstandard))
+	tx.AddTxOut(&wire.TxOut{Value: 3, Version: 1, PkScript: p2pkh})
+	msgBlock := &wire.MsgBlock{
+		Header:       wire.BlockHeader{Height: 100},
+		Transactions: []*wire.MsgTx{tx},
+	}
+
+	anomalies := ExtractScriptAnomalies(msgBlock)
+	if len(anomalies) != 2 {
+		t.Fatalf("expected 2 anomalies, got %d", len(anomalies))
+	}
+	if a := anomalies[0]; a.TxIndex != 1 || a.Version != 0 || a.ScriptType != "nonstandard" {
+		t.Errorf("unexpected nonstandard script anomaly: %+v", *a)
+	}
+	if a := anomalies[1]; a.TxIndex != 2 || a.Version != 1 || a.Height != 100 {
+		t.Errorf("unexpected script version anomaly: %+v", *a)
+	}
+
+	report := NewScriptAnomalyReport(100, 100, anomalies)
+	if report.Types["nonstandard"] != 2 || report.Versions[0] != 1 || report.Versions[1] != 1 {
+		t.Errorf("unexpected report counts: %v, %v", report.Types, report.Versions)
+	}
+}
+
+func TestClassifyVinSig(t *testing.T) {
+	sig := bytes.Repeat([]byte{0x30}, 71)
+	pubKey := append([]byte{0x02}, bytes.Repeat([]byte{0x01}, 32)...)
+	redeemScript, err := txscript.NewScriptBuilder().AddOp(txscript.OP_2).
+		AddData(pubKey).AddData(pubKey).AddData(pubKey).
+		AddOp(txscript.OP_3).AddOp(txscript.OP_CHECKMULTISIG).Script()
+	if err != nil {
+		t.Fatal(err)
+	}
+	script := func(data ...[]byte) []byte {
+		b := txscript.NewScriptBuilder()
+		for _, d := range data {
+			b.AddData(d)
+		}
+		s, err := b.Script()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+
+	prevOut := wire.NewOutPoint(&chainhash.Hash{1}, 0, wire.TxTreeRegular)
+	tx := wire.NewMsgTx()
+	tx.AddTxIn(wire.NewTxIn(prevOut, 0, script(sig, pubKey)))
+	tx.AddTxIn(wire.NewTxIn(prevOut, 0, script(sig, sig, redeemScript)))
+	tx.AddTxIn(wire.NewTxIn(prevOut, 0, []byte{0x51, 0x52, 0x93}))
+	tx.AddTxIn(wire.NewTxIn(prevOut, 0, script(sig)))
+
+	tests := []struct {
+		txType stake.TxType
+		idx    int
+		want   VinSigType
+	}{
+		{stake.TxTypeRegular, 0, VinSigPubKeyHash},
+		{stake.TxTypeRegular, 1, VinSigMultiSig},
+		{stake.TxTypeRegular, 2, VinSigNonStandard},
+		{stake.TxTypeRegular, 3, VinSigNonStandard},
+		{stake.TxTypeSSGen, 0, VinSigCoinbase},
+		{stake.TxTypeSSGen, 1, VinSigStakeSubmission},
+		{stake.TxTypeSSRtx, 0, VinSigStakeSubmission},
+		{stake.TxTypeSStx, 1, VinSigMultiSig},
+	}
+	for _, tt := range tests {
+		if got := ClassifyVinSig(tx, tt.txType, tt.idx); got != tt.want {
+			t.Errorf("input %d of %v: got %v, wanted %v", tt.idx, tt.txType, got, tt.want)
+		}
+	}
+
+	coinbase := wire.NewMsgTx()
+	coinbase.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{}, wire.MaxPrevOutIndex,
+		wire.TxTreeRegular), 0, nil))
+	if got := ClassifyVinSig(coinbase, stake.TxTypeRegular, 0); got != VinSigCoinbase {
+		t.Errorf("coinbase input: got %v", got)
+	}
+
+	for _, s := range []VinSigType{VinSigNonStandard, VinSigCoinbase, VinSigPubKeyHash,
+		VinSigMultiSig, VinSigStakeSubmission} {
+		if got, err := VinSigTypeFromStr(s.String()); err != nil || got != s {
+			t.Errorf("VinSigTypeFromStr(%q) = %v, %v", s, got, err)
+		}
+	}
+	if _, err := VinSigTypeFromStr("p2sh"); err == nil {
+		t.Errorf("expected an error for an unknown type")
+	}
+}
+
+func TestBlockAnomalyRules(t *testing.T) {
+	coinbase := wire.NewMsgTx()
+	coinbase.AddTxOut(wire.NewTxOut(1, []byte{0x51}))
+	msgBlock := &wire.MsgBlock{
+		Header:       wire.BlockHeader{Height: 5000},
+		Transactions: []*wire.MsgTx{coinbase},
+	}
+	size := int64(msgBlock.SerializeSize())
+
+	rules := &BlockAnomalyRules{
+		EmptyBlocks: true,
+		MinVotes:    3,
+		MaxSize:     uint32(size - 1),
+	}
+	anomalies := rules.CheckBlock(msgBlock, 4096)
+	if len(anomalies) != 3 {
+		t.Fatalf("expected 3 anomalies, got %d", len(anomalies))
+	}
+	want := []struct {
+		kind  string
+		value int64
+	}{{BlockAnomalyEmpty, 0}, {BlockAnomalyFewVotes, 0}, {BlockAnomalyLarge, size}}
+	for i, w := range want {
+		if a := anomalies[i]; a.Kind != w.kind || a.Value != w.value || a.Height != 5000 {
+			t.Errorf("unexpected anomaly %d: %+v", i, *a)
+		}
+	}
+
+	// Votes are not required before the stake validation height, and the
+	// size rule is disabled with a zero threshold.
+	rules.MaxSize = 0
+	anomalies = rules.CheckBlock(msgBlock, 8192)
+	if len(anomalies) != 1 || anomalies[0].Kind != BlockAnomalyEmpty {
+		t.Errorf("expected only an empty block anomaly, got %d anomalies", len(anomalies))
+	}
+
+	var nilRules *BlockAnomalyRules
+	if nilRules.CheckBlock(msgBlock, 0) != nil || nilRules.CheckReorg(10, &msgBlock.Header) != nil {
+		t.Errorf("nil rules flagged anomalies")
+	}
+
+	rules.MaxReorgDepth = 2
+	if a := rules.CheckReorg(2, &msgBlock.Header); a != nil {
+		t.Errorf("reorg of depth 2 flagged: %+v", *a)
+	}
+	if a := rules.CheckReorg(3, &msgBlock.Header); a == nil || a.Kind != BlockAnomalyDeepReorg || a.Value != 3 {
+		t.Errorf("reorg of depth 3 not flagged")
+	}
+}
@@ -6,7 +6,9 @@ package dbtypes
 import (
 	"context"
 	"database/sql/driver"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"sort"
 	"strconv"
@@ -364,6 +366,11 @@ const (
 	// the first progress bar id on the status page.
 	InitialDBLoad = "initial-load"
 
+	// StakeDBSync tracks the blocks connected to the stake database, which is
+	// advanced alongside InitialDBLoad, but may be rewound first. StakeDBSync
+	// value references the stake database progress bar id on the status page.
+	StakeDBSync = "stakedb-sync"
+
 	// AddressesTableSync is a sync that runs immediately after initialDBLoad.
 	// Data previously loaded into vins table is sync'd with the addresses
 	// table. AddressesTableSync value references the second progress bar id on
@@ -516,6 +523,89 @@ func ChoiceIndexFromStr(choice string) (VoteChoice, error) {
 	}
 }
 
+// VinSigType is the type of a transaction input's signature script, and the
+// underlying integer value is stored in the database (do not change these
+// without upgrading the DB!).
+type VinSigType int16
+
+// These are the recognized VinSigType values. VinSigStakeSubmission is the
+// type of the inputs of votes and revocations that spend the ticket, whatever
+// the form of their signature scripts.
+const (
+	VinSigNonStandard VinSigType = iota
+	VinSigCoinbase
+	VinSigPubKeyHash
+	VinSigMultiSig
+	VinSigStakeSubmission
+)
+
+// String implements the Stringer interface for VinSigType.
+func (s VinSigType) String() string {
+	switch s {
+	case VinSigCoinbase:
+		return "coinbase"
+	case VinSigPubKeyHash:
+		return "pubkeyhash"
+	case VinSigMultiSig:
+		return "multisig"
+	case VinSigStakeSubmission:
+		return "stakesubmission"
+	default:
+		return "nonstandard"
+	}
+}
+
+// VinSigTypeFromStr converts the signature script type string to a
+// VinSigType.
+func VinSigTypeFromStr(sigType string) (VinSigType, error) {
+	switch sigType {
+	case "nonstandard":
+		return VinSigNonStandard, nil
+	case "coinbase":
+		return VinSigCoinbase, nil
+	case "pubkeyhash":
+		return VinSigPubKeyHash, nil
+	case "multisig":
+		return VinSigMultiSig, nil
+	case "stakesubmission":
+		return VinSigStakeSubmission, nil
+	default:
+		return VinSigNonStandard, fmt.Errorf(`signature script type "%s" is unknown`, sigType)
+	}
+}
+
+// VoteChoices maps the agenda IDs of a vote version to the choice IDs of a
+// vote, e.g. "yes", "no" or "abstain". It is stored as a JSONB column.
+type VoteChoices map[string]string
+
+// NewVoteChoices creates the VoteChoices for the decoded choices of a vote.
+func NewVoteChoices(choices []*txhelpers.VoteChoice) VoteChoices {
+	vc := make(VoteChoices, len(choices))
+	for _, c := range choices {
+		vc[c.ID] = c.Choice.Id
+	}
+	return vc
+}
+
+// Value satisfies driver.Valuer
+func (vc VoteChoices) Value() (driver.Value, error) {
+	j, err := json.Marshal(vc)
+	return string(j), err
+}
+
+// Scan satisfies sql.Scanner
+func (vc *VoteChoices) Scan(src interface{}) error {
+	if src == nil {
+		*vc = nil
+		return nil
+	}
+	source, ok := src.([]byte)
+	if !ok {
+		return fmt.Errorf("scan type assertion .([]byte) failed")
+	}
+	return json.Unmarshal(source, vc)
+}
+
 // These are text keys used to identify different chart types.
 const (
 	AvgBlockSize    = "avg-block-size"
@@ -534,6 +624,7 @@ const (
 	FeePerBlock     = "fee-per-block"
 	TicketPoolSize  = "ticket-pool-size"
 	TicketPoolValue = "ticket-pool-value"
+	TicketPoolPrice = "ticket-pool-price"
 )
 
 // MileStone defines the various stages passed by vote on a given agenda.
@@ -584,6 +675,31 @@ type SyncResult struct {
 	Error  error
 }
 
+// The phases of a database sync recorded in a SyncCheckpoint. Blocks are
+// stored in the SyncPhaseBlocks phase, the tables are indexed in the
+// SyncPhaseIndexing phase, and the spending info of the vouts and addresses
+// tables is updated in the SyncPhaseSpending phase.
+const (
+	SyncPhaseBlocks   = "blocks"
+	SyncPhaseIndexing = "indexing"
+	SyncPhaseSpending = "spending"
+	SyncPhaseComplete = "complete"
+)
+
+// SyncCheckpoint records the progress of a database sync at a main chain block,
+// with the numbers of rows in the main tables at that time.
+type SyncCheckpoint struct {
+	Height          int64     `json:"height"`
+	Hash            string    `json:"hash"`
+	Phase           string    `json:"phase"`
+	NumBlocks       int64     `json:"num_blocks"`
+	NumTransactions int64     `json:"num_transactions"`
+	NumVins         int64     `json:"num_vins"`
+	NumVouts        int64     `json:"num_vouts"`
+	NumAddresses    int64     `json:"num_addresses"`
+	Time            time.Time `json:"time"`
+}
+
 // JSONB is used to implement the sql.Scanner and driver.Valuer interfaces
 // required for the type to make a postgresql compatible JSONB type.
 type JSONB map[string]interface{}
@@ -1486,6 +1602,68 @@ func UncompactMergedRows(merged []*AddressRowMerged) []*AddressRow {
 	return rows
 }
 
+// Orders of a page of the unspent outputs of an address.
+const (
+	UTXOSortNewest   = "newest"
+	UTXOSortOldest   = "oldest"
+	UTXOSortLargest  = "largest"
+	UTXOSortSmallest = "smallest"
+)
+
+// PageCursorKind identifies the listing a PageCursor belongs to.
+type PageCursorKind string
+
+// Listings paged with a PageCursor.
+const (
+	CursorAddressRows   PageCursorKind = "addr"
+	CursorRewardTickets PageCursorKind = "rwtk"
+	CursorRevocable     PageCursorKind = "rvtk"
+	CursorCoinbase      PageCursorKind = "cb"
+)
+
+// PageCursor is the sort key of the last row of a page of a listing. The next
+// page starts with the rows after it in the listing's order, so that rows
+// added or removed before the cursor, such as the rows of new blocks, do not
+// shift the following pages. Each listing only uses the fields of its sort
+// key:
+//
+//   - address rows: BlockTime DESC, TxHash, IsFunding, IOIndex
+//   - reward tickets: Height DESC, TxIndex
+//   - revocable tickets: Height, TxIndex
+//   - coinbase blocks: Height DESC
+//
+// where TxIndex is the index of the transaction in its block and IOIndex is
+// the index of the input or output in its transaction.
+type PageCursor struct {
+	Kind      PageCursorKind `json:"k"`
+	Height    int64          `json:"h,omitempty"`
+	BlockTime int64          `json:"bt,omitempty"`
+	TxIndex   uint32         `json:"ti,omitempty"`
+	TxHash    string         `json:"tx,omitempty"`
+	IsFunding bool           `json:"f,omitempty"`
+	IOIndex   uint32         `json:"io,omitempty"`
+}
+
+// Encode encodes the cursor as an opaque URL-safe string.
+func (c *PageCursor) Encode() string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodePageCursor decodes a cursor encoded with (*PageCursor).Encode, which
+// must be for the given kind of listing.
+func DecodePageCursor(s string, kind PageCursorKind) (*PageCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	var c PageCursor
+	if err = json.Unmarshal(b, &c); err != nil || c.Kind != kind {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	return &c, nil
+}
+
 // AddressTxnOutput is a compact version of api/types.AddressTxnOutput.
 type AddressTxnOutput struct {
 	Address  string
@@ -1553,22 +1731,33 @@ type ScriptPubKeyData struct {
 
 // VinTxProperty models a transaction input with previous outpoint information.
 type VinTxProperty struct {
-	PrevOut     string  `json:"prevout"`
-	PrevTxHash  string  `json:"prevtxhash"`
-	PrevTxIndex uint32  `json:"prevvoutidx"`
-	PrevTxTree  uint16  `json:"tree"`
-	Sequence    uint32  `json:"sequence"`
-	ValueIn     int64   `json:"amountin"`
-	TxID        string  `json:"tx_hash"`
-	TxIndex     uint32  `json:"tx_index"`
-	TxTree      uint16  `json:"tx_tree"`
-	TxType      int16   `json:"tx_type"`
-	BlockHeight uint32  `json:"blockheight"`
-	BlockIndex  uint32  `json:"blockindex"`
-	ScriptHex   []byte  `json:"scripthex"`
-	IsValid     bool    `json:"is_valid"`
-	IsMainchain bool    `json:"is_mainchain"`
-	Time        TimeDef `json:"time"`
+	PrevOut     string     `json:"prevout"`
+	PrevTxHash  string     `json:"prevtxhash"`
+	PrevTxIndex uint32     `json:"prevvoutidx"`
+	PrevTxTree  uint16     `json:"tree"`
+	Sequence    uint32     `json:"sequence"`
+	ValueIn     int64      `json:"amountin"`
+	TxID        string     `json:"tx_hash"`
+	TxIndex     uint32     `json:"tx_index"`
+	TxTree      uint16     `json:"tx_tree"`
+	TxType      int16      `json:"tx_type"`
+	BlockHeight uint32     `json:"blockheight"`
+	BlockIndex  uint32     `json:"blockindex"`
+	ScriptHex   []byte     `json:"scripthex"`
+	SigType     VinSigType `json:"sig_type"`
+	IsValid     bool       `json:"is_valid"`
+	IsMainchain bool       `json:"is_mainchain"`
+	Time        TimeDef    `json:"time"`
+}
+
+// TxInputsSummary describes the inputs of a mainchain transaction using the
+// values and addresses of the previous outpoints stored in the DB. ValueIns
+// and Addresses are indexed by input.
+type TxInputsSummary struct {
+	BlockHeight int64
+	ValueIn     int64
+	ValueIns    []int64
+	Addresses   [][]string
 }
 
 // PoolTicketsData defines the real time data
@@ -1581,6 +1770,34 @@ type PoolTicketsData struct {
 	Live     []uint64  `json:"live,omitempty"`
 	Outputs  []uint64  `json:"outputs,omitempty"`
 	Count    []uint64  `json:"count,omitempty"`
+	Age      []uint64  `json:"age,omitempty"`
+}
+
+// Ticket purchase types, inferred from the number of outputs of the ticket
+// purchase transaction. A solo ticket has a single commitment, a pooled (VSP)
+// ticket has a second commitment for the pool fee, and tickets with more
+// commitments, such as split tickets, are other.
+const (
+	TicketTypeSolo   = "solo"
+	TicketTypePooled = "pooled"
+	TicketTypeOther  = "other"
+)
+
+// TicketTypes lists the ticket purchase types.
+var TicketTypes = []string{TicketTypeSolo, TicketTypePooled, TicketTypeOther}
+
+// TicketType classifies a ticket purchase transaction by its number of
+// outputs, which are the stake submission, and a commitment and change output
+// for each of the ticket's contributors.
+func TicketType(numVout uint32) string {
+	switch numVout {
+	case 3:
+		return TicketTypeSolo
+	case 5:
+		return TicketTypePooled
+	default:
+		return TicketTypeOther
+	}
 }
 
 // Vin models a transaction input.
@@ -1635,6 +1852,7 @@ type Tx struct {
 	Spent       int64   `json:"spent"`
 	Sent        int64   `json:"sent"`
 	Fees        int64   `json:"fees"`
+	FeeRate     int64   `json:"fee_rate"`
 	MixCount    int32   `json:"mix_count"`
 	MixDenom    int64   `json:"mix_denom"`
 	NumVin      uint32  `json:"numvin"`
@@ -1676,6 +1894,24 @@ type Block struct {
 	PreviousHash string   `json:"previousblockhash"`
 	ChainWork    string   `json:"chainwork"`
 	Winners      []string `json:"winners"`
+	FinalState   string   `json:"finalstate"`
+}
+
+// StoredBlock is a block and the transactions, outputs and addresses table
+// rows stored with it, along with the DB row IDs of each.
+type StoredBlock struct {
+	Block       *Block
+	BlockDbID   uint64
+	IsValid     bool
+	IsMainchain bool
+	// Txns have their Vouts and VoutDbIds set, and TxDbIDs are the
+	// corresponding transactions table row IDs.
+	Txns    []*Tx
+	TxDbIDs []uint64
+	// AddressRows are the funding and spending addresses table rows, and
+	// AddressDbIDs are their row IDs.
+	AddressRows  []*AddressRow
+	AddressDbIDs []uint64
 }
 
 type BlockDataBasic struct {
@@ -1686,18 +1922,78 @@ type BlockDataBasic struct {
 	StakeDiff  float64 `json:"sdiff,omitempty"`
 	Time       TimeDef `json:"time,omitempty"`
 	NumTx      uint32  `json:"txlength,omitempty"`
+	PoolSize   uint32  `json:"poolsize,omitempty"`
+}
+
+// The stakeholder approval states of a block's regular transactions. The
+// votes in the next block approve or disapprove a block, so the approval of a
+// block without a next block is pending.
+const (
+	BlockApproved        = "approved"
+	BlockDisapproved     = "disapproved"
+	BlockApprovalPending = "pending"
+)
+
+// BlockValidity returns the approval state of a block given its is_valid flag
+// and the hash of the next block, if any. Blocks are stored as valid until the
+// next block's votes disapprove them, so a block with no next block is
+// pending regardless of its is_valid flag.
+func BlockValidity(isValid bool, nextHash string) string {
+	switch {
+	case nextHash == "":
+		return BlockApprovalPending
+	case !isValid:
+		return BlockDisapproved
+	default:
+		return BlockApproved
+	}
 }
 
-// BlockStatus describes a block's status in the block chain.
+// BlockStatus describes a block's status in the block chain. Validity is one
+// of BlockApproved, BlockDisapproved, or BlockApprovalPending.
 type BlockStatus struct {
 	IsValid     bool   `json:"is_valid"`
 	IsMainchain bool   `json:"is_mainchain"`
+	Validity    string `json:"validity,omitempty"`
 	Height      uint32 `json:"height"`
 	PrevHash    string `json:"previous_hash"`
 	Hash        string `json:"hash"`
 	NextHash    string `json:"next_hash"`
 }
 
+// BlockChainWork describes the proof-of-work of a block, and the cumulative
+// work of the chain ending with the block. Work values are hex-encoded.
+type BlockChainWork struct {
+	Hash        string `json:"hash"`
+	Height      uint32 `json:"height"`
+	IsMainchain bool   `json:"is_mainchain"`
+	Bits        uint32 `json:"bits"`
+	Work        string `json:"work"`
+	ChainWork   string `json:"chainwork"`
+}
+
+// BlockStakeHeader describes the stake fields of a block header: the votes
+// and their approval of the previous block (VoteBits), the ticket lottery
+// FinalState (hex), the numbers of new tickets and revocations, the ticket pool
+// size, and the stake difficulty in DCR. The field names follow dcrd's
+// getblockheader result. FinalState is empty if it is not yet recorded, as for
+// the blocks stored before the DB upgrade that added it until it is set in the
+// background after the upgrade.
+type BlockStakeHeader struct {
+	Hash         string  `json:"hash"`
+	Height       uint32  `json:"height"`
+	IsValid      bool    `json:"is_valid"`
+	IsMainchain  bool    `json:"is_mainchain"`
+	VoteBits     uint16  `json:"votebits"`
+	FinalState   string  `json:"finalstate"`
+	Voters       uint16  `json:"voters"`
+	FreshStake   uint8   `json:"freshstake"`
+	Revocations  uint8   `json:"revocations"`
+	PoolSize     uint32  `json:"poolsize"`
+	SBits        float64 `json:"sbits"`
+	StakeVersion uint32  `json:"stakeversion"`
+}
+
 // SideChain represents blocks of a side chain, in ascending height order.
 type SideChain struct {
 	Hashes  []string
@@ -1721,6 +2017,8 @@ type AddressTx struct {
 	MatchedTxIndex uint32
 	MergedTxnCount uint64 `json:",omitempty"`
 	BlockHeight    uint32
+	Fee            dcrutil.Amount
+	FeeRate        dcrutil.Amount
 }
 
 // IOID formats an identification string for the transaction input (or output)
@@ -1808,13 +2106,336 @@ type AddressInfo struct {
 // AddressBalance represents the number and value of spent and unspent outputs
 // for an address.
 type AddressBalance struct {
-	Address      string  `json:"address"`
-	NumSpent     int64   `json:"num_stxos"`
-	NumUnspent   int64   `json:"num_utxos"`
-	TotalSpent   int64   `json:"amount_spent"`
-	TotalUnspent int64   `json:"amount_unspent"`
-	FromStake    float64 `json:"from_stake"`
-	ToStake      float64 `json:"to_stake"`
+	Address      string   `json:"address"`
+	NumSpent     int64    `json:"num_stxos"`
+	NumUnspent   int64    `json:"num_utxos"`
+	TotalSpent   int64    `json:"amount_spent"`
+	TotalUnspent int64    `json:"amount_unspent"`
+	FromStake    float64  `json:"from_stake"`
+	ToStake      float64  `json:"to_stake"`
+	FirstSeen    *TimeDef `json:"first_seen,omitempty"`
+	LastActivity *TimeDef `json:"last_activity,omitempty"`
+}
+
+// HistoricalAddressBalance is the confirmed balance of an address as of a
+// certain mainchain block. Amounts are in atoms.
+type HistoricalAddressBalance struct {
+	Address   string  `json:"address"`
+	Height    int64   `json:"height"`
+	BlockHash string  `json:"block_hash"`
+	Time      TimeDef `json:"time"`
+	Received  int64   `json:"received"`
+	Sent      int64   `json:"sent"`
+	Balance   int64   `json:"balance"`
+}
+
+// DailyPrice is the open, high, low and close of the DCR price in a fiat
+// currency on a UTC day.
+type DailyPrice struct {
+	Day      TimeDef `json:"day"`
+	Currency string  `json:"currency"`
+	Open     float64 `json:"open"`
+	High     float64 `json:"high"`
+	Low      float64 `json:"low"`
+	Close    float64 `json:"close"`
+}
+
+// UTXOBucketBounds are the boundaries, in atoms, between the value buckets of
+// the UTXO set distribution: dust (less than 0.001 DCR), 0.001-1 DCR, 1-10,
+// 10-100, 100-1k, 1k-10k, 10k-100k, and 100k DCR or more.
+var UTXOBucketBounds = []int64{1e5, 1e8, 1e9, 1e10, 1e11, 1e12, 1e13}
+
+// UTXOBucket is the number and total value, in atoms, of the unspent outputs
+// with values in [Min, Max). Max is zero for the last, unbounded bucket.
+type UTXOBucket struct {
+	Min   int64 `json:"min"`
+	Max   int64 `json:"max,omitempty"`
+	Count int64 `json:"count"`
+	Value int64 `json:"value"`
+}
+
+// UTXODistribution is the distribution of the unspent output values among the
+// UTXOBucketBounds buckets recorded on a UTC day, as of the block at Height.
+type UTXODistribution struct {
+	Day     TimeDef      `json:"day"`
+	Height  int64        `json:"height"`
+	Buckets []UTXOBucket `json:"buckets"`
+}
+
+// NewUTXOBuckets creates the empty buckets of a UTXODistribution.
+func NewUTXOBuckets() []UTXOBucket {
+	buckets := make([]UTXOBucket, len(UTXOBucketBounds)+1)
+	for i, bound := range UTXOBucketBounds {
+		buckets[i].Max = bound
+		buckets[i+1].Min = bound
+	}
+	return buckets
+}
+
+// StakeDiffEstimateAccuracy compares dcrd's estimates of the next window's
+// stake difficulty, made while the blocks of a window were stored, with the
+// stake difficulty that was realized in the next window. Amounts are in DCR.
+type StakeDiffEstimateAccuracy struct {
+	// Window is the stake difficulty window in which the estimates were made.
+	// The estimates are for window Window+1.
+	Window       int64   `json:"window"`
+	NumEstimates int64   `json:"num_estimates"`
+	MeanExpected float64 `json:"mean_expected"`
+	// The last estimate made in the window, and its bounds.
+	LastHeight   int64   `json:"last_height"`
+	LastExpected float64 `json:"last_expected"`
+	LastMin      float64 `json:"last_min"`
+	LastMax      float64 `json:"last_max"`
+	// Actual is the stake difficulty of window Window+1.
+	Actual float64 `json:"actual"`
+	// Error is LastExpected - Actual, and ErrorPercent is Error as a
+	// percentage of Actual.
+	Error        float64 `json:"error"`
+	ErrorPercent float64 `json:"error_percent"`
+	// InBounds indicates if Actual is within [LastMin, LastMax].
+	InBounds bool `json:"in_bounds"`
+}
+
+// StakeDiffWindowOHLC is the ticket price of a stake difficulty window in the
+// open-high-low-close form used by financial chart libraries. The price only
+// changes between windows, so the open and close prices of a window are the
+// same. Times are UNIX timestamps and amounts are in DCR.
+type StakeDiffWindowOHLC struct {
+	Window      int64 `json:"window"`
+	StartHeight int64 `json:"start_height"`
+	EndHeight   int64 `json:"end_height"`
+	// Blocks is the number of blocks stored in the window, which is less than
+	// the window size for the current window.
+	Blocks    int64   `json:"blocks"`
+	Time      int64   `json:"time"`
+	EndTime   int64   `json:"end_time"`
+	Open      float64 `json:"open"`
+	High      float64 `json:"high"`
+	Low       float64 `json:"low"`
+	Close     float64 `json:"close"`
+	Purchased int64   `json:"tickets_purchased"`
+	// PoolSize and PoolValue are the ticket pool size and value at the last
+	// block of the window.
+	PoolSize  int64   `json:"pool_size"`
+	PoolValue float64 `json:"pool_value"`
+}
+
+// BlockTicketDemand is the number of tickets purchased in a main chain block,
+// its ticket price in atoms, and the live ticket pool size in its header.
+type BlockTicketDemand struct {
+	Height     int64
+	FreshStake uint16
+	PoolSize   uint32
+	SBits      int64
+}
+
+// AddressSummary contains the headline numbers for an address: its confirmed
+// balance in atoms, the number of mainchain transactions involving it, and the
+// times of the first and last of these transactions. The times are nil if the
+// address has no transactions.
+type AddressSummary struct {
+	Address         string   `json:"address"`
+	Balance         int64    `json:"balance"`
+	NumTransactions int64    `json:"num_transactions"`
+	FirstSeen       *TimeDef `json:"first_seen,omitempty"`
+	LastActivity    *TimeDef `json:"last_activity,omitempty"`
+}
+
+// TicketCommitment describes a ticket commitment output, which specifies where
+// the ticket's reward will be paid when it votes or is revoked. Amounts are in
+// atoms. A fee limit of -1 indicates that no limit applies.
+type TicketCommitment struct {
+	Address        string `json:"address"`
+	Amount         int64  `json:"amount"`
+	VoteFeeLimit   int64  `json:"vote_fee_limit"`
+	RevokeFeeLimit int64  `json:"revoke_fee_limit"`
+}
+
+// TicketSpend is the vote or revocation spending a ticket. Reward is the total
+// output value of the spending transaction less the ticket price, in atoms:
+// the stake subsidy paid by a vote, or the negative fee paid by a revocation.
+type TicketSpend struct {
+	TxHash string `json:"txid"`
+	Type   string `json:"type"`
+	Height int64  `json:"height"`
+	Reward int64  `json:"reward"`
+}
+
+// NewTicketSpend creates the TicketSpend for a ticket with the given price,
+// in DCR, spent by the transaction with the given spend type, hash, height and
+// total output value, in atoms.
+func NewTicketSpend(spendType TicketSpendType, txHash string, height, sent int64, price float64) *TicketSpend {
+	spendTypeStr := "vote"
+	if spendType == TicketRevoked {
+		spendTypeStr = "revocation"
+	}
+	atoms, _ := dcrutil.NewAmount(price)
+	return &TicketSpend{
+		TxHash: txHash,
+		Type:   spendTypeStr,
+		Height: height,
+		Reward: sent - int64(atoms),
+	}
+}
+
+// RewardTicket is a ticket with a commitment to a certain reward address.
+// CommitmentAmount is the sum of the ticket's commitments to the address.
+// Spend is the vote or revocation spending the ticket, if it is spent.
+type RewardTicket struct {
+	TxHash           string       `json:"tx_hash"`
+	BlockHash        string       `json:"block_hash"`
+	BlockHeight      int64        `json:"block_height"`
+	Price            float64      `json:"price"`
+	CommitmentAmount int64        `json:"commitment_amount"`
+	PoolStatus       string       `json:"pool_status"`
+	SpendType        string       `json:"spend_type"`
+	Spend            *TicketSpend `json:"spend,omitempty"`
+}
+
+// RevocableTicket is a missed or expired mainchain ticket that has not been
+// revoked. RecoverableAmount is the sum of the ticket's commitments, in atoms,
+// which a revocation returns less the transaction fee. When listing the
+// tickets for a reward address, only the commitments to that address count.
+type RevocableTicket struct {
+	TxHash            string  `json:"tx_hash"`
+	BlockHeight       int64   `json:"block_height"`
+	PoolStatus        string  `json:"pool_status"`
+	Price             float64 `json:"price"`
+	RecoverableAmount int64   `json:"recoverable_amount"`
+}
+
+// RevocableTickets is a page of revocable tickets, optionally for a reward
+// address. NumTickets and TotalRecoverable cover all revocable tickets rather
+// than just the listed ones.
+type RevocableTickets struct {
+	Address          string             `json:"address,omitempty"`
+	NumTickets       int64              `json:"num_tickets"`
+	TotalRecoverable int64              `json:"total_recoverable"`
+	Tickets          []*RevocableTicket `json:"tickets"`
+}
+
+// NullDataOutput is a null data (OP_RETURN) output of a mainchain
+// transaction. Payload is the hex-encoded data pushed by the output's script,
+// and Text is the payload as text if it is valid UTF-8.
+type NullDataOutput struct {
+	TxHash      string  `json:"txid"`
+	TxIndex     uint32  `json:"vout"`
+	BlockHeight int64   `json:"block_height"`
+	BlockTime   TimeDef `json:"block_time"`
+	Payload     string  `json:"payload"`
+	Text        string  `json:"text,omitempty"`
+}
+
+// CoinbaseBlock is a mainchain block with coinbase outputs paying to a certain
+// address. Value is the sum of those outputs in atoms.
+type CoinbaseBlock struct {
+	Height int64   `json:"height"`
+	Hash   string  `json:"hash"`
+	Time   TimeDef `json:"time"`
+	Value  int64   `json:"value"`
+}
+
+// MinerShare is the number of blocks in a range with their largest coinbase
+// output paying to an address, and the estimated share of the network
+// hashrate that this represents. HashRate is in PH/s.
+type MinerShare struct {
+	Address    string  `json:"address"`
+	NumBlocks  int64   `json:"num_blocks"`
+	LastHeight int64   `json:"last_height"`
+	Share      float64 `json:"share"`
+	HashRate   float64 `json:"hashrate,omitempty"`
+}
+
+// MinerShares is the distribution of the mainchain blocks from FromHeight to
+// ToHeight among their payout addresses, most blocks first.
+type MinerShares struct {
+	FromHeight      int64         `json:"from_height"`
+	ToHeight        int64         `json:"to_height"`
+	NumBlocks       int64         `json:"num_blocks"`
+	NetworkHashRate float64       `json:"network_hashrate,omitempty"`
+	Miners          []*MinerShare `json:"miners"`
+}
+
+// TicketLuck compares the number of blocks a voted ticket was live with the
+// expected number for tickets that vote. Luck is the number of standard
+// deviations by which the ticket voted sooner than expected, so it is positive
+// for lucky tickets and negative for unlucky ones.
+type TicketLuck struct {
+	TxHash         string  `json:"tx_hash"`
+	PurchaseHeight int64   `json:"purchase_height"`
+	VoteHeight     int64   `json:"vote_height"`
+	LiveBlocks     int64   `json:"live_blocks"`
+	ExpectedBlocks float64 `json:"expected_blocks"`
+	StdDevBlocks   float64 `json:"stddev_blocks"`
+	Luck           float64 `json:"luck"`
+}
+
+// AddressTicketLuck aggregates the luck of the voted tickets with a commitment
+// to an address. Luck is the number of standard errors by which the tickets'
+// mean number of live blocks is less than expected.
+type AddressTicketLuck struct {
+	Address        string  `json:"address"`
+	NumVoted       int64   `json:"num_voted"`
+	MeanLiveBlocks float64 `json:"mean_live_blocks"`
+	ExpectedBlocks float64 `json:"expected_blocks"`
+	StdDevBlocks   float64 `json:"stddev_blocks"`
+	Luck           float64 `json:"luck"`
+	// Luckiest and Unluckiest are the voted tickets with the highest and
+	// lowest luck.
+	Luckiest   *TicketLuck `json:"luckiest,omitempty"`
+	Unluckiest *TicketLuck `json:"unluckiest,omitempty"`
+}
+
+// ErrEmptyTicketPool is returned for vote odds when the ticket pool size is
+// zero, such as before the stake database is synced, so the odds are unknown.
+var ErrEmptyTicketPool = errors.New("ticket pool is empty")
+
+// TicketVoteOdds describes the chance that at least one of a set of tickets
+// votes within the next Blocks blocks, given the current pool size. Unknown
+// lists the requested tickets that are not mainchain tickets, and NumEligible
+// counts the tickets that are live or immature, and so may still vote.
+type TicketVoteOdds struct {
+	Height         int64               `json:"height"`
+	PoolSize       int64               `json:"pool_size"`
+	Blocks         int64               `json:"blocks"`
+	NumTickets     int                 `json:"num_tickets"`
+	NumEligible    int                 `json:"num_eligible"`
+	Unknown        []string            `json:"unknown,omitempty"`
+	Probability    float64             `json:"probability"`
+	ExpectedVotes  float64             `json:"expected_votes"`
+	MeanWaitBlocks float64             `json:"mean_wait_blocks,omitempty"`
+	Quantiles      []*VoteWaitQuantile `json:"quantiles"`
+}
+
+// VoteWaitQuantile is the number of blocks within which at least one ticket
+// votes with the given probability.
+type VoteWaitQuantile struct {
+	Probability float64 `json:"probability"`
+	Blocks      int64   `json:"blocks"`
+}
+
+// StakingPosition summarizes the DCR committed to an address by tickets that
+// have not been spent, and by recent votes with outputs that are not yet
+// spendable. Amounts are in atoms.
+type StakingPosition struct {
+	Address string `json:"address"`
+	Height  int64  `json:"height"`
+	// Unspent tickets by status, and the sums of their commitments to the
+	// address. Unrevoked tickets were missed or expired.
+	NumImmature     int64 `json:"num_immature"`
+	ImmatureAmount  int64 `json:"immature_amount"`
+	NumLive         int64 `json:"num_live"`
+	LiveAmount      int64 `json:"live_amount"`
+	NumUnrevoked    int64 `json:"num_unrevoked"`
+	UnrevokedAmount int64 `json:"unrevoked_amount"`
+	// LockedAmount is the total committed to the address by unspent tickets.
+	LockedAmount int64 `json:"locked_amount"`
+	// PendingPayout is the total paid to the address by votes that are not
+	// yet mature, and PendingReward is the part of it in excess of the
+	// tickets' commitments.
+	NumPendingVotes int64 `json:"num_pending_votes"`
+	PendingPayout   int64 `json:"pending_payout"`
+	PendingReward   int64 `json:"pending_reward"`
 }
 
 // HasStakeOutputs checks whether any of the Address tx outputs were
@@ -1829,6 +2450,19 @@ func (balance *AddressBalance) HasStakeInputs() bool {
 	return balance.ToStake > 0
 }
 
+// AddActivity extends the address' first seen and last activity times to
+// include the given time of a transaction involving the address.
+func (balance *AddressBalance) AddActivity(t time.Time) {
+	if balance.FirstSeen == nil || t.Before(balance.FirstSeen.T) {
+		firstSeen := NewTimeDef(t)
+		balance.FirstSeen = &firstSeen
+	}
+	if balance.LastActivity == nil || t.After(balance.LastActivity.T) {
+		lastActivity := NewTimeDef(t)
+		balance.LastActivity = &lastActivity
+	}
+}
+
 // ReduceAddressHistory generates a template AddressInfo from a slice of
 // AddressRow. All fields except NumUnconfirmed and Transactions are set
 // completely. Transactions is partially set, with each transaction having only
@@ -1920,3 +2554,108 @@ func (a *AddressInfo) PostProcess(tipHeight uint32) {
 		tx.BlockHeight = tipHeight - uint32(tx.Confirmations) + 1
 	}
 }
+
+// ScriptAnomaly is a transaction output with a nonstandard script, or with a
+// script version other than 0. Value is in atoms.
+type ScriptAnomaly struct {
+	Height     int64  `json:"height"`
+	BlockHash  string `json:"block_hash"`
+	TxHash     string `json:"tx_hash"`
+	TxIndex    uint32 `json:"vout"`
+	TxTree     int8   `json:"tree"`
+	Value      int64  `json:"value"`
+	Version    uint16 `json:"version"`
+	ScriptType string `json:"script_type"`
+}
+
+// SigTypeVin is a mainchain transaction input with a signature script of type
+// SigType. Value is in atoms.
+type SigTypeVin struct {
+	Height      int64  `json:"height"`
+	TxHash      string `json:"tx_hash"`
+	TxIndex     uint32 `json:"vin"`
+	TxTree      int8   `json:"tree"`
+	PrevTxHash  string `json:"prev_tx_hash"`
+	PrevTxIndex uint32 `json:"prev_vout"`
+	Value       int64  `json:"value"`
+	Sequence    uint32 `json:"sequence"`
+	SigType     string `json:"sig_type"`
+}
+
+// ScriptAnomalyReport summarizes the ScriptAnomalys in the mainchain blocks
+// with heights in the range [From, To], counting the outputs by script type
+// and by script version.
+type ScriptAnomalyReport struct {
+	From     int64            `json:"from"`
+	To       int64            `json:"to"`
+	Types    map[string]int64 `json:"script_types"`
+	Versions map[uint16]int64 `json:"versions"`
+	Outputs  []*ScriptAnomaly `json:"outputs"`
+}
+
+// NewScriptAnomalyReport creates a ScriptAnomalyReport for the range [from,
+// to] from the anomalous outputs in the range.
+func NewScriptAnomalyReport(from, to int64, outputs []*ScriptAnomaly) *ScriptAnomalyReport {
+	report := &ScriptAnomalyReport{
+		From:     from,
+		To:       to,
+		Types:    make(map[string]int64),
+		Versions: make(map[uint16]int64),
+		Outputs:  outputs,
+	}
+	if report.Outputs == nil {
+		report.Outputs = []*ScriptAnomaly{}
+	}
+	for _, out := range outputs {
+		report.Types[out.ScriptType]++
+		report.Versions[out.Version]++
+	}
+	return report
+}
+
+// These are the kinds of BlockAnomaly.
+const (
+	BlockAnomalyEmpty     = "empty"
+	BlockAnomalyFewVotes  = "few_votes"
+	BlockAnomalyLarge     = "large"
+	BlockAnomalyDeepReorg = "deep_reorg"
+)
+
+// BlockAnomaly is a main chain block matching one of the BlockAnomalyRules.
+// Value is the measure that matched the rule: the number of regular
+// transactions, the number of votes, the size in bytes, or, for a deep reorg
+// ending at the block, the number of blocks removed from the main chain.
+type BlockAnomaly struct {
+	Height    int64  `json:"height"`
+	BlockHash string `json:"block_hash"`
+	Time      int64  `json:"time"`
+	Kind      string `json:"kind"`
+	Value     int64  `json:"value"`
+}
+
+// TicketWinningBlock is a block in which a ticket was selected to vote. Time
+// is a UNIX timestamp, and FinalState is the block header's final state of the
+// ticket lottery.
+type TicketWinningBlock struct {
+	Hash        string `json:"hash"`
+	Height      int64  `json:"height"`
+	Time        int64  `json:"time"`
+	IsMainchain bool   `json:"is_mainchain"`
+	FinalState  string `json:"final_state"`
+}
+
+// BlockAnomalyRules are the rules for flagging unusual main chain blocks. A
+// zero threshold disables its rule.
+type BlockAnomalyRules struct {
+	// EmptyBlocks flags blocks with no regular transactions besides the
+	// coinbase.
+	EmptyBlocks bool
+	// MinVotes flags blocks with fewer votes, once votes are required at the
+	// stake validation height.
+	MinVotes uint16
+	// MaxSize flags blocks larger than this many bytes.
+	MaxSize uint32
+	// MaxReorgDepth flags reorgs removing more than this many blocks from the
+	// main chain.
+	MaxReorgDepth int64
+}
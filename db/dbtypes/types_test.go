@@ -3,6 +3,9 @@ package dbtypes
 import (
 	"testing"
 	"time"
+
+	"github.com/decred/dcrd/chaincfg/v2"
+	"github.com/decred/dcrdata/txhelpers/v4"
 )
 
 const (
@@ -154,3 +157,143 @@ func TestTimeDef_Scan(t *testing.T) {
 		t.Fatal("TimeDef.Scan(int64) should have failed")
 	}
 }
+
+func TestAddressBalanceAddActivity(t *testing.T) {
+	var bal AddressBalance
+	times := []time.Time{
+		time.Unix(trefUNIX, 0),
+		time.Unix(trefUNIX-3600, 0),
+		time.Unix(trefUNIX+7200, 0),
+		time.Unix(trefUNIX+60, 0),
+	}
+	for _, tt := range times {
+		bal.AddActivity(tt)
+	}
+	if bal.FirstSeen == nil || bal.FirstSeen.UNIX() != trefUNIX-3600 {
+		t.Errorf("incorrect FirstSeen: %v", bal.FirstSeen)
+	}
+	if bal.LastActivity == nil || bal.LastActivity.UNIX() != trefUNIX+7200 {
+		t.Errorf("incorrect LastActivity: %v", bal.LastActivity)
+	}
+}
+
+func TestNewUTXOBuckets(t *testing.T) {
+	buckets := NewUTXOBuckets()
+	if len(buckets) != len(UTXOBucketBounds)+1 {
+		t.Fatalf("got %d buckets, wanted %d", len(buckets), len(UTXOBucketBounds)+1)
+	}
+	if buckets[0].Min != 0 || buckets[len(buckets)-1].Max != 0 {
+		t.Errorf("the first and last buckets should be unbounded below and above")
+	}
+	for i := 1; i < len(buckets); i++ {
+		if buckets[i].Min != buckets[i-1].Max || buckets[i].Min != UTXOBucketBounds[i-1] {
+			t.Errorf("bucket %d [%d, %d) does not follow bucket %d [%d, %d)", i,
+				buckets[i].Min, buckets[i].Max, i-1, buckets[i-1].Min, buckets[i-1].Max)
+		}
+	}
+}
+
+func TestVoteChoices(t *testing.T) {
+	// Vote version 7 on mainnet has the headercommitments agenda, with the yes
+	// choice at bits 0x0004.
+	params := chaincfg.MainNetParams()
+	choices := NewVoteChoices(txhelpers.VoteChoices(7, 0x0005, params))
+	if len(choices) != 1 || choices["headercommitments"] != "yes" {
+		t.Fatalf("wrong choices %v", choices)
+	}
+
+	v, err := choices.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var scanned VoteChoices
+	if err = scanned.Scan([]byte(v.(string))); err != nil {
+		t.Fatal(err)
+	}
+	if len(scanned) != 1 || scanned["headercommitments"] != "yes" {
+		t.Errorf("wrong scanned choices %v", scanned)
+	}
+
+	if err = scanned.Scan(nil); err != nil || scanned != nil {
+		t.Errorf("expected nil choices, got %v (%v)", scanned, err)
+	}
+}
+
+func TestBlockValidity(t *testing.T) {
+	tests := []struct {
+		name     string
+		isValid  bool
+		nextHash string
+		want     string
+	}{
+		{"tip", true, "", BlockApprovalPending},
+		{"approved", true, "00000000000000001f2", BlockApproved},
+		{"disapproved", false, "00000000000000001f2", BlockDisapproved},
+	}
+	for _, tt := range tests {
+		if got := BlockValidity(tt.isValid, tt.nextHash); got != tt.want {
+			t.Errorf("%s: got validity %s, wanted %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestTicketType(t *testing.T) {
+	tests := []struct {
+		numVout uint32
+		want    string
+	}{
+		{3, TicketTypeSolo},
+		{5, TicketTypePooled},
+		{7, TicketTypeOther},
+		{1, TicketTypeOther},
+	}
+	for _, tt := range tests {
+		if got := TicketType(tt.numVout); got != tt.want {
+			t.Errorf("TicketType(%d) = %s, wanted %s", tt.numVout, got, tt.want)
+		}
+	}
+}
+
+func TestPageCursor(t *testing.T) {
+	c := &PageCursor{
+		Kind:      CursorAddressRows,
+		BlockTime: trefUNIX,
+		TxHash:    "4a3d1c3f0e9c0d3b6a5f0b4d6f5e0c1a2b3c4d5e6f708192a3b4c5d6e7f80910",
+		IsFunding: true,
+		IOIndex:   2,
+	}
+	encoded := c.Encode()
+	got, err := DecodePageCursor(encoded, CursorAddressRows)
+	if err != nil {
+		t.Fatalf("DecodePageCursor: %v", err)
+	}
+	if *got != *c {
+		t.Errorf("decoded cursor %+v, wanted %+v", got, c)
+	}
+
+	// A cursor is only valid for its own listing.
+	if _, err = DecodePageCursor(encoded, CursorRewardTickets); err == nil {
+		t.Errorf("decoded an address rows cursor as a reward tickets cursor")
+	}
+	for _, s := range []string{"not a cursor!", "bm90IGpzb24"} {
+		if _, err = DecodePageCursor(s, CursorAddressRows); err == nil {
+			t.Errorf("decoded invalid cursor %q", s)
+		}
+	}
+}
+
+func TestNewTicketSpend(t *testing.T) {
+	// A vote returns the ticket price plus the stake subsidy.
+	vote := NewTicketSpend(TicketVoted, "ab", 4200, 14028734211, 139.88)
+	want := TicketSpend{TxHash: "ab", Type: "vote", Height: 4200, Reward: 40734211}
+	if *vote != want {
+		t.Errorf("got vote spend %+v, wanted %+v", vote, want)
+	}
+
+	// A revocation returns the ticket price less its fee.
+	revoke := NewTicketSpend(TicketRevoked, "cd", 9000, 13987994200, 139.88)
+	want = TicketSpend{TxHash: "cd", Type: "revocation", Height: 9000, Reward: -5800}
+	if *revoke != want {
+		t.Errorf("got revocation spend %+v, wanted %+v", revoke, want)
+	}
+}
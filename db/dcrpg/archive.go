@@ -0,0 +1,334 @@
+// Copyright (c) 2020, The Decred developers
+// See LICENSE for details.
+
+package dcrpg
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/decred/dcrdata/db/dcrpg/v5/internal"
+)
+
+const (
+	// DefaultArchiveInterval is the number of blocks between archival runs
+	// when no interval is specified.
+	DefaultArchiveInterval = 144
+
+	// MinArchiveDepth is the minimum archive depth. Rows are only archived
+	// when they are spent too deep in the main chain to be affected by a
+	// reorganization, so that archived rows are never updated or upserted by
+	// block storage.
+	MinArchiveDepth = 256
+
+	// archiveBatchBlocks is the number of blocks of spent rows moved to the
+	// archive in each database transaction, so that the first archival of a
+	// large database does not move every spent row at once.
+	archiveBatchBlocks = 2000
+)
+
+// spentArchive tracks the archival of the address and vout rows of outputs
+// spent deep in the main chain to the archive schema, and plans the address
+// and vout queries accordingly. The archive tables inherit from the addresses
+// and vouts tables, so a query reads the archived rows unless it is restricted
+// to the hot tables. A nil *spentArchive, as when archival is disabled, plans
+// every query unchanged.
+type spentArchive struct {
+	depth    int64
+	interval int64
+	running  uint32 // atomic, 1 while rows are being moved
+
+	mtx sync.RWMutex
+	// Rows spent by transactions below height are archived, and no address
+	// row with a block time after boundary is archived.
+	height   int64
+	boundary time.Time
+}
+
+// unspentQuery plans a query of unspent outputs, which are never archived, to
+// read only the hot tables.
+func (sa *spentArchive) unspentQuery(query string) string {
+	if sa == nil {
+		return query
+	}
+	return internal.HotTables(query)
+}
+
+// sinceQuery plans a query of the address rows with a block time no earlier
+// than since to read only the hot tables if since is after the archive
+// boundary.
+func (sa *spentArchive) sinceQuery(query string, since time.Time) string {
+	if sa == nil {
+		return query
+	}
+	sa.mtx.RLock()
+	hot := since.After(sa.boundary)
+	sa.mtx.RUnlock()
+	if hot {
+		return internal.HotTables(query)
+	}
+	return query
+}
+
+// progress returns the height below which spent rows are archived.
+func (sa *spentArchive) progress() int64 {
+	sa.mtx.RLock()
+	defer sa.mtx.RUnlock()
+	return sa.height
+}
+
+// createArchiveTables creates the archive schema with its tables and indexes.
+func createArchiveTables(db *sql.DB) error {
+	if _, err := db.Exec(internal.CreateArchiveSchema); err != nil {
+		return fmt.Errorf("failed to create archive schema: %v", err)
+	}
+	for _, pair := range internal.ArchiveTables {
+		if _, err := db.Exec(pair[1]); err != nil {
+			return fmt.Errorf("failed to create %s table: %v", pair[0], err)
+		}
+	}
+	for _, stmt := range internal.ArchiveIndexes {
+		if _, err := db.Exec(stmt); err != nil {
+			return fmt.Errorf("failed to index archive table: %v", err)
+		}
+	}
+	return nil
+}
+
+// DropArchive drops the archive schema, including the archived address and
+// vout rows. The addresses and vouts tables cannot be dropped while the
+// archive tables inherit from them.
+func DropArchive(db SqlExecutor) error {
+	_, err := db.Exec(internal.DropArchiveSchema)
+	return err
+}
+
+// retrieveArchiveProgress gets the archived height and block time boundary. A
+// new archive has a zero height and the UNIX epoch boundary.
+func retrieveArchiveProgress(ctx context.Context, db *sql.DB) (int64, time.Time, error) {
+	var height int64
+	var boundary time.Time
+	err := db.QueryRowContext(ctx, internal.SelectArchiveProgress).Scan(&height, &boundary)
+	if err == sql.ErrNoRows {
+		return 0, time.Unix(0, 0), nil
+	}
+	return height, boundary, err
+}
+
+// archivedHeight gets the height below which spent rows have been archived,
+// or 0 if the archive schema does not exist or no rows were archived.
+func archivedHeight(ctx context.Context, db *sql.DB) (int64, error) {
+	var exists bool
+	if err := db.QueryRowContext(ctx, internal.SelectArchiveExists).Scan(&exists); err != nil || !exists {
+		return 0, err
+	}
+	height, _, err := retrieveArchiveProgress(ctx, db)
+	return height, err
+}
+
+// checkUnarchived returns an error if spent row archival is enabled or any
+// rows are archived. The archive tables do not inherit the unique indexes of
+// the addresses and vouts tables, so the upserts that update existing rows
+// would insert duplicates of the archived rows. The operation is described by
+// op, e.g. "block reindex".
+func (pgb *ChainDB) checkUnarchived(op string) error {
+	if pgb.archive != nil {
+		return fmt.Errorf("%s is not supported while spent row archival is "+
+			"enabled; restart with --archive-spent-depth=0", op)
+	}
+	height, err := archivedHeight(pgb.ctx, pgb.db)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve archive progress: %v",
+			pgb.replaceCancelError(err))
+	}
+	if height > 0 {
+		return fmt.Errorf("%s is not supported with spent rows archived below "+
+			"height %d; restore them with --unarchive-spent first", op, height)
+	}
+	return nil
+}
+
+// enableArchive creates any missing archive tables and loads the archive
+// progress. Rows spent more than depth blocks below the best block are moved
+// to the archive every interval blocks.
+func (pgb *ChainDB) enableArchive(depth, interval int64) error {
+	if depth < MinArchiveDepth {
+		return fmt.Errorf("archive depth %d is less than the minimum, %d",
+			depth, MinArchiveDepth)
+	}
+	if interval <= 0 {
+		interval = DefaultArchiveInterval
+	}
+	if err := createArchiveTables(pgb.db); err != nil {
+		return err
+	}
+	height, boundary, err := retrieveArchiveProgress(pgb.ctx, pgb.db)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve archive progress: %v", err)
+	}
+	pgb.archive = &spentArchive{
+		depth:    depth,
+		interval: interval,
+		height:   height,
+		boundary: boundary,
+	}
+	log.Infof("Archiving rows spent more than %d blocks deep every %d blocks "+
+		"(archived below height %d).", depth, interval, height)
+	return nil
+}
+
+// ArchiveSpent moves the address and vout rows of outputs spent by valid main
+// chain transactions more than the archive depth below the best block to the
+// archive schema, in batches of blocks. The number of moved address and vout
+// rows are returned. An error is returned if archival is not enabled or is
+// already running.
+func (pgb *ChainDB) ArchiveSpent(ctx context.Context) (addressRows, vouts int64, err error) {
+	sa := pgb.archive
+	if sa == nil {
+		return 0, 0, fmt.Errorf("spent row archival is not enabled")
+	}
+	if !atomic.CompareAndSwapUint32(&sa.running, 0, 1) {
+		return 0, 0, fmt.Errorf("spent row archival is already running")
+	}
+	defer atomic.StoreUint32(&sa.running, 0)
+
+	if !pgb.beginWrite() {
+		return 0, 0, ErrShuttingDown
+	}
+	defer pgb.endWrite()
+
+	target := pgb.Height() - sa.depth
+	for from := sa.progress(); from < target; from = sa.progress() {
+		to := from + archiveBatchBlocks
+		if to > target {
+			to = target
+		}
+		a, v, err := pgb.archiveSpentRange(ctx, from, to)
+		if err != nil {
+			return addressRows, vouts, pgb.replaceCancelError(err)
+		}
+		addressRows += a
+		vouts += v
+		log.Infof("Archived %d address rows and %d vouts spent in blocks [%d,%d).",
+			a, v, from, to)
+	}
+	return addressRows, vouts, nil
+}
+
+// archiveSpentRange moves the address and vout rows of outputs spent by valid
+// main chain transactions in the height range [from, to) to the archive
+// schema, and sets the archive progress to height to, in one database
+// transaction.
+func (pgb *ChainDB) archiveSpentRange(ctx context.Context, from, to int64) (addressRows, vouts int64, err error) {
+	sa := pgb.archive
+	dbTx, err := pgb.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, 0, fmt.Errorf("unable to begin database transaction: %v", err)
+	}
+
+	// Record the new boundary before moving any rows. Queries planned with
+	// the new boundary while the rows are being moved read the archive too.
+	if _, err = dbTx.ExecContext(ctx, internal.DeleteArchiveProgress); err == nil {
+		_, err = dbTx.ExecContext(ctx, internal.InsertArchiveProgress, to)
+	}
+	var height int64
+	var boundary time.Time
+	if err == nil {
+		err = dbTx.QueryRowContext(ctx, internal.SelectArchiveProgress).Scan(&height, &boundary)
+	}
+	if err != nil {
+		_ = dbTx.Rollback()
+		return 0, 0, fmt.Errorf("failed to update archive progress: %v", err)
+	}
+	sa.mtx.Lock()
+	prevHeight, prevBoundary := sa.height, sa.boundary
+	sa.height, sa.boundary = height, boundary
+	sa.mtx.Unlock()
+
+	res, err := dbTx.ExecContext(ctx, internal.ArchiveSpentAddressRows, from, to)
+	if err == nil {
+		addressRows, _ = res.RowsAffected()
+		res, err = dbTx.ExecContext(ctx, internal.ArchiveSpentVouts, from, to)
+	}
+	if err == nil {
+		vouts, _ = res.RowsAffected()
+		err = dbTx.Commit()
+	} else {
+		_ = dbTx.Rollback()
+	}
+	if err != nil {
+		// The archive progress was not updated.
+		sa.mtx.Lock()
+		sa.height, sa.boundary = prevHeight, prevBoundary
+		sa.mtx.Unlock()
+		return 0, 0, err
+	}
+	return addressRows, vouts, nil
+}
+
+// UnarchiveSpent moves the archived address and vout rows back to the addresses
+// and vouts tables, and clears the archive progress, so that blocks can be
+// reindexed. The number of moved address and vout rows are returned. Archival
+// must be disabled.
+func (pgb *ChainDB) UnarchiveSpent(ctx context.Context) (addressRows, vouts int64, err error) {
+	if pgb.archive != nil {
+		return 0, 0, fmt.Errorf("spent row archival is enabled")
+	}
+	height, err := archivedHeight(ctx, pgb.db)
+	if err != nil || height == 0 {
+		return 0, 0, pgb.replaceCancelError(err)
+	}
+
+	if !pgb.beginWrite() {
+		return 0, 0, ErrShuttingDown
+	}
+	defer pgb.endWrite()
+
+	dbTx, err := pgb.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, 0, fmt.Errorf("unable to begin database transaction: %v", err)
+	}
+	res, err := dbTx.ExecContext(ctx, internal.UnarchiveAddressRows)
+	if err == nil {
+		addressRows, _ = res.RowsAffected()
+		res, err = dbTx.ExecContext(ctx, internal.UnarchiveVouts)
+	}
+	if err == nil {
+		vouts, _ = res.RowsAffected()
+		_, err = dbTx.ExecContext(ctx, internal.DeleteArchiveProgress)
+	}
+	if err == nil {
+		err = dbTx.Commit()
+	} else {
+		_ = dbTx.Rollback()
+	}
+	if err != nil {
+		return 0, 0, pgb.replaceCancelError(err)
+	}
+	log.Infof("Restored %d address rows and %d vouts archived below height %d.",
+		addressRows, vouts, height)
+	return addressRows, vouts, nil
+}
+
+// archiveSpentOnInterval starts ArchiveSpent in a goroutine when a main chain
+// block at a multiple of the archive interval is stored, except during batch
+// sync.
+func (pgb *ChainDB) archiveSpentOnInterval(height int64) {
+	sa := pgb.archive
+	if sa == nil || pgb.InBatchSync || height%sa.interval != 0 {
+		return
+	}
+	if atomic.LoadUint32(&sa.running) != 0 {
+		log.Debugf("Spent row archival is still running at height %d.", height)
+		return
+	}
+	go func() {
+		if _, _, err := pgb.ArchiveSpent(pgb.ctx); err != nil {
+			log.Errorf("Failed to archive spent rows: %v", err)
+		}
+	}()
+}
@@ -0,0 +1,283 @@
+// Copyright (c) 2020, The Decred developers
+// See LICENSE for details.
+
+package dcrpg
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	apitypes "github.com/decred/dcrdata/api/types/v5"
+	"github.com/decred/dcrdata/db/dbtypes/v2"
+)
+
+// Names of the internal caches reported by CacheStats and flushed by
+// FlushCaches.
+const (
+	// CacheTicketPool is the ticket pool charts data cache.
+	CacheTicketPool = "ticketpool"
+	// CacheAddresses is the address balance, rows, UTXO and history cache.
+	CacheAddresses = "addresses"
+	// CacheUnspentTickets is the cache of unspent ticket DB row IDs.
+	CacheUnspentTickets = "unspenttickets"
+	// CacheDevFund is the project fund address data in the address cache.
+	CacheDevFund = "devfund"
+	// CacheBlockIDs is the cache of recent block_chain table row IDs.
+	CacheBlockIDs = "blockids"
+)
+
+// CacheNames lists the names of all of the internal caches.
+var CacheNames = []string{CacheTicketPool, CacheAddresses, CacheUnspentTickets,
+	CacheDevFund, CacheBlockIDs}
+
+// blockIDCacheSize is the number of recent blocks' row IDs kept in the
+// blockIDCache, which is well beyond the depth of any expected reorg.
+const blockIDCacheSize = 256
+
+// cacheStats counts the hits and misses of a cache, and records when data was
+// last stored in or flushed from the cache.
+type cacheStats struct {
+	mtx     sync.Mutex
+	hits    int
+	misses  int
+	updated time.Time
+}
+
+func (cs *cacheStats) hit() {
+	cs.mtx.Lock()
+	cs.hits++
+	cs.mtx.Unlock()
+}
+
+func (cs *cacheStats) miss() {
+	cs.mtx.Lock()
+	cs.misses++
+	cs.mtx.Unlock()
+}
+
+func (cs *cacheStats) touch() {
+	cs.mtx.Lock()
+	cs.updated = time.Now()
+	cs.mtx.Unlock()
+}
+
+// report creates a CacheStats with the given name and number of entries.
+func (cs *cacheStats) report(name string, entries int) *apitypes.CacheStats {
+	cs.mtx.Lock()
+	defer cs.mtx.Unlock()
+	return newCacheStats(name, entries, cs.hits, cs.misses, cs.updated)
+}
+
+func newCacheStats(name string, entries, hits, misses int, updated time.Time) *apitypes.CacheStats {
+	stats := &apitypes.CacheStats{
+		Name:    name,
+		Entries: entries,
+		Hits:    hits,
+		Misses:  misses,
+	}
+	stats.SetUpdated(updated)
+	return stats
+}
+
+// blockIDCache maps the hashes of the most recently stored blocks to their
+// block_chain table row IDs. Once full, the row ID of the oldest block is
+// evicted for each new block, so the memory used does not grow with the chain.
+// The row IDs of evicted blocks, and of blocks stored before a restart, are
+// looked up in the DB.
+type blockIDCache struct {
+	mtx   sync.Mutex
+	ids   map[chainhash.Hash]uint64
+	order []chainhash.Hash // ring of the cached hashes, oldest at next
+	next  int
+	stats cacheStats
+}
+
+func newBlockIDCache(capacity int) *blockIDCache {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &blockIDCache{
+		ids:   make(map[chainhash.Hash]uint64, capacity),
+		order: make([]chainhash.Hash, 0, capacity),
+	}
+}
+
+// get returns the cached row ID of the block with the given hash.
+func (c *blockIDCache) get(hash chainhash.Hash) (uint64, bool) {
+	c.mtx.Lock()
+	id, ok := c.ids[hash]
+	c.mtx.Unlock()
+	if ok {
+		c.stats.hit()
+	} else {
+		c.stats.miss()
+	}
+	return id, ok
+}
+
+// set caches the row ID of the block with the given hash, evicting the oldest
+// block if the cache is full.
+func (c *blockIDCache) set(hash chainhash.Hash, id uint64) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if _, ok := c.ids[hash]; !ok {
+		if len(c.order) < cap(c.order) {
+			c.order = append(c.order, hash)
+		} else {
+			delete(c.ids, c.order[c.next])
+			c.order[c.next] = hash
+			c.next = (c.next + 1) % len(c.order)
+		}
+	}
+	c.ids[hash] = id
+	c.stats.touch()
+}
+
+// Len returns the number of block row IDs in the cache.
+func (c *blockIDCache) Len() int {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return len(c.ids)
+}
+
+// Clear removes all of the block row IDs from the cache, returning the number
+// removed.
+func (c *blockIDCache) Clear() int {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	n := len(c.ids)
+	c.ids = make(map[chainhash.Hash]uint64, cap(c.order))
+	c.order = c.order[:0]
+	c.next = 0
+	c.stats.touch()
+	return n
+}
+
+// len returns the number of ticket pool chart intervals in the cache.
+func (tpc *ticketPoolDataCache) len() int {
+	tpc.RLock()
+	defer tpc.RUnlock()
+	return len(tpc.Height)
+}
+
+// clear removes the charts data for all intervals, returning the number of
+// intervals removed.
+func (tpc *ticketPoolDataCache) clear() int {
+	tpc.Lock()
+	defer tpc.Unlock()
+	n := len(tpc.Height)
+	tpc.Height = make(map[dbtypes.TimeBasedGrouping]int64)
+	tpc.Hash = make(map[dbtypes.TimeBasedGrouping]chainhash.Hash)
+	tpc.TimeGraphCache = make(map[dbtypes.TimeBasedGrouping]*dbtypes.PoolTicketsData)
+	tpc.PriceGraphCache = make(map[dbtypes.TimeBasedGrouping]*dbtypes.PoolTicketsData)
+	tpc.DonutGraphCache = make(map[dbtypes.TimeBasedGrouping]*dbtypes.PoolTicketsData)
+	tpc.AgeGraphCache = make(map[dbtypes.TimeBasedGrouping]*dbtypes.PoolTicketsData)
+	tpc.stats.touch()
+	return n
+}
+
+// Len returns the number of ticket row IDs in the cache.
+func (t *TicketTxnIDGetter) Len() int {
+	t.mtx.RLock()
+	defer t.mtx.RUnlock()
+	return len(t.idCache)
+}
+
+// Clear removes all of the ticket row IDs from the cache, returning the number
+// removed. Subsequent lookups query the DB.
+func (t *TicketTxnIDGetter) Clear() int {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	n := len(t.idCache)
+	t.idCache = make(map[string]uint64)
+	t.stats.touch()
+	return n
+}
+
+// CacheStats reports the number of entries, the hits and misses, and the last
+// update time of each of the internal caches listed in CacheNames.
+func (pgb *ChainDB) CacheStats() []*apitypes.CacheStats {
+	stats := make([]*apitypes.CacheStats, 0, len(CacheNames))
+	for _, name := range CacheNames {
+		switch name {
+		case CacheTicketPool:
+			stats = append(stats, ticketPoolGraphsCache.stats.report(name,
+				ticketPoolGraphsCache.len()))
+		case CacheAddresses:
+			numAddrs, _, _ := pgb.AddressCache.Length()
+			var hits, misses int
+			for _, hitsMisses := range []func() (int, int){pgb.AddressCache.BalanceStats,
+				pgb.AddressCache.RowStats, pgb.AddressCache.UtxoStats,
+				pgb.AddressCache.HistoryStats} {
+				h, m := hitsMisses()
+				hits += h
+				misses += m
+			}
+			stats = append(stats, newCacheStats(name, numAddrs, hits, misses,
+				pgb.AddressCache.LastUpdated()))
+		case CacheUnspentTickets:
+			stats = append(stats, pgb.unspentTicketCache.stats.report(name,
+				pgb.unspentTicketCache.Len()))
+		case CacheDevFund:
+			var entries int
+			if pgb.AddressCache.Cached(pgb.devAddress) {
+				entries = 1
+			}
+			stats = append(stats, pgb.devFundStats.report(name, entries))
+		case CacheBlockIDs:
+			stats = append(stats, pgb.lastBlock.stats.report(name,
+				pgb.lastBlock.Len()))
+		}
+	}
+	return stats
+}
+
+// FlushCaches removes all data from the named caches. If names is empty, all
+// of the caches in CacheNames are flushed. Nothing is flushed if any of the
+// names is not recognized. Since the project fund data is stored in the
+// address cache, flushing CacheAddresses also flushes CacheDevFund.
+func (pgb *ChainDB) FlushCaches(names []string) ([]*apitypes.CacheFlush, error) {
+	if len(names) == 0 {
+		names = CacheNames
+	}
+	for _, name := range names {
+		var known bool
+		for _, cacheName := range CacheNames {
+			if name == cacheName {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown cache %q", name)
+		}
+	}
+
+	flushed := make([]*apitypes.CacheFlush, 0, len(names))
+	for _, name := range names {
+		var n int
+		switch name {
+		case CacheTicketPool:
+			n = ticketPoolGraphsCache.clear()
+		case CacheAddresses:
+			n = pgb.AddressCache.ClearAll()
+		case CacheUnspentTickets:
+			n = pgb.unspentTicketCache.Clear()
+		case CacheDevFund:
+			if pgb.AddressCache.Cached(pgb.devAddress) {
+				n = pgb.AddressCache.Clear([]string{pgb.devAddress})
+			}
+			pgb.devFundStats.touch()
+		case CacheBlockIDs:
+			n = pgb.lastBlock.Clear()
+		}
+		log.Infof("Flushed %d entries from the %s cache.", n, name)
+		flushed = append(flushed, &apitypes.CacheFlush{
+			Name:    name,
+			Flushed: n,
+		})
+	}
+	return flushed, nil
+}
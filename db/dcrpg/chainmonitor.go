@@ -104,7 +104,13 @@ func (p *ChainMonitor) switchToSideChain(reorgData *txhelpers.ReorgData) (int32,
 		blockHash := msgBlock.BlockHash()
 		chainWork, err := p.db.GetChainWork(&blockHash)
 		if err != nil {
-			return 0, nil, fmt.Errorf("GetChainWork failed (%s): %v", blockHash.String(), err)
+			log.Warnf("GetChainWork failed (%s): %v. Computing from header bits.",
+				blockHash.String(), err)
+			chainWork, err = p.db.ChainWorkFromHeader(&msgBlock.Header)
+			if err != nil {
+				return 0, nil, fmt.Errorf("ChainWorkFromHeader failed (%s): %v",
+					blockHash.String(), err)
+			}
 		}
 
 		// New blocks stored this way are considered part of mainchain. They are
@@ -176,5 +182,10 @@ func (p *ChainMonitor) ReorgHandler(reorg *txhelpers.ReorgData) (err error) {
 	// Freshen project fund balance and clear ALL address cache data.
 	_ = p.db.FreshenAddressCaches(true, nil) // async update
 
+	if err == nil {
+		p.db.signalReorg(reorg)
+		p.db.recordReorgAnomaly(reorg)
+	}
+
 	return err
 }
@@ -0,0 +1,72 @@
+// Copyright (c) 2020, The Decred developers
+// See LICENSE for details.
+
+package dcrpg
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/decred/dcrdata/db/dcrpg/v5/internal"
+)
+
+// ExportDailyTable writes the rows of the blocks, transactions or vouts table
+// for the main chain blocks with times in [start, end) to w as CSV, with a
+// header of the column names, and returns the number of rows written. Like the
+// snapshot export, it is not subject to the query timeout.
+func (pgb *ChainDB) ExportDailyTable(ctx context.Context, table string, start, end time.Time, w io.Writer) (int64, error) {
+	query, ok := internal.DailyExportTables[table]
+	if !ok {
+		return 0, fmt.Errorf("table %s is not exported", table)
+	}
+	rows, err := pgb.db.QueryContext(ctx, query, start, end)
+	if err != nil {
+		return 0, pgb.replaceCancelError(err)
+	}
+	defer closeRows(rows)
+
+	numRows, err := writeCSVRows(rows, w)
+	return numRows, pgb.replaceCancelError(err)
+}
+
+// writeCSVRows writes the column names and the rows to w as CSV. NULL values
+// are empty.
+func writeCSVRows(rows *sql.Rows, w io.Writer) (int64, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return 0, err
+	}
+	cw := csv.NewWriter(w)
+	if err = cw.Write(columns); err != nil {
+		return 0, err
+	}
+
+	values := make([]sql.NullString, len(columns))
+	dest := make([]interface{}, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	record := make([]string, len(columns))
+	var numRows int64
+	for rows.Next() {
+		if err = rows.Scan(dest...); err != nil {
+			return numRows, err
+		}
+		for i, v := range values {
+			record[i] = v.String // empty if NULL
+		}
+		if err = cw.Write(record); err != nil {
+			return numRows, err
+		}
+		numRows++
+	}
+	if err = rows.Err(); err != nil {
+		return numRows, err
+	}
+	cw.Flush()
+	return numRows, cw.Error()
+}
@@ -3,10 +3,12 @@ module github.com/decred/dcrdata/db/dcrpg/v5
 go 1.12
 
 replace (
+	github.com/decred/dcrdata/api/types/v5 => ../../api/types
 	github.com/decred/dcrdata/db/cache/v3 => ../cache
 	github.com/decred/dcrdata/db/dbtypes/v2 => ../dbtypes
 	github.com/decred/dcrdata/explorer/types/v2 => ../../explorer/types
 	github.com/decred/dcrdata/mempool/v5 => ../../mempool
+	github.com/decred/dcrdata/stakedb/v3 => ../../stakedb
 	github.com/decred/dcrdata/txhelpers/v4 => ../../txhelpers
 )
 
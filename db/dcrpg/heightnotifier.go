@@ -0,0 +1,201 @@
+// Copyright (c) 2020, The Decred developers
+// See LICENSE for details.
+
+package dcrpg
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	apitypes "github.com/decred/dcrdata/api/types/v5"
+)
+
+const (
+	// DefaultHeightNtfnBuffer is the default capacity of the channels returned
+	// by UpdateChan.
+	DefaultHeightNtfnBuffer = 8
+
+	// heightNtfnTimeout is how long a height notification may go unreceived
+	// before the client is considered hung and dcrdata is shut down.
+	heightNtfnTimeout = time.Minute
+)
+
+// heightNotifier delivers height notifications to a single client channel
+// without blocking the caller of notify. Notifications are buffered by the
+// channel, and when the channel is full, pending heights are coalesced so
+// that only the most recent height is delivered once the client catches up.
+// The latest signaled height is always delivered unless the notifier is
+// stopped.
+type heightNotifier struct {
+	c    chan uint32
+	wake chan struct{}
+
+	mtx     sync.Mutex
+	latest  uint32
+	pending bool
+	stats   apitypes.HeightNtfnStats
+}
+
+func newHeightNotifier(bufferSize int) *heightNotifier {
+	if bufferSize < 0 {
+		bufferSize = 0
+	}
+	return &heightNotifier{
+		c:    make(chan uint32, bufferSize),
+		wake: make(chan struct{}, 1),
+	}
+}
+
+// notify queues the height for delivery, replacing any height that is still
+// pending.
+func (hn *heightNotifier) notify(height uint32) {
+	hn.mtx.Lock()
+	hn.stats.Signaled++
+	if hn.pending {
+		hn.stats.Coalesced++
+	}
+	hn.latest = height
+	hn.pending = true
+	hn.mtx.Unlock()
+
+	select {
+	case hn.wake <- struct{}{}:
+	default: // the run loop is already signaled
+	}
+}
+
+// next takes the pending height, if any.
+func (hn *heightNotifier) next() (uint32, bool) {
+	hn.mtx.Lock()
+	defer hn.mtx.Unlock()
+	if !hn.pending {
+		return 0, false
+	}
+	hn.pending = false
+	return hn.latest, true
+}
+
+// requeue restores a height taken with next that could not be delivered,
+// unless a newer height has been signaled in the meantime.
+func (hn *heightNotifier) requeue(height uint32) {
+	hn.mtx.Lock()
+	defer hn.mtx.Unlock()
+	if hn.pending {
+		hn.stats.Coalesced++
+		return
+	}
+	hn.latest = height
+	hn.pending = true
+}
+
+func (hn *heightNotifier) delivered() {
+	hn.mtx.Lock()
+	hn.stats.Delivered++
+	hn.mtx.Unlock()
+}
+
+// dropPending counts any undelivered height as dropped.
+func (hn *heightNotifier) dropPending() {
+	hn.mtx.Lock()
+	if hn.pending {
+		hn.pending = false
+		hn.stats.Dropped++
+	}
+	hn.mtx.Unlock()
+}
+
+// Stats returns a copy of the notifier's statistics.
+func (hn *heightNotifier) Stats() apitypes.HeightNtfnStats {
+	hn.mtx.Lock()
+	defer hn.mtx.Unlock()
+	return hn.stats
+}
+
+// run delivers pending heights to the client channel until the context is
+// canceled. If the client does not receive a height within the timeout, the
+// onTimeout function is called. Delivery of the height is still attempted
+// after a timeout.
+func (hn *heightNotifier) run(ctx context.Context, timeout time.Duration, onTimeout func()) {
+	defer hn.dropPending()
+	for {
+		select {
+		case <-hn.wake:
+		case <-ctx.Done():
+			return
+		}
+
+		for {
+			height, ok := hn.next()
+			if !ok {
+				break
+			}
+
+			if !hn.deliver(ctx, height, timeout, onTimeout) {
+				return
+			}
+		}
+	}
+}
+
+// deliver sends the height to the client channel, returning false if the
+// context is canceled first. If a newer height is signaled while waiting, the
+// height is requeued so that the latest height is delivered instead.
+func (hn *heightNotifier) deliver(ctx context.Context, height uint32, timeout time.Duration, onTimeout func()) bool {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	for {
+		select {
+		case hn.c <- height:
+			hn.delivered()
+			return true
+		case <-hn.wake:
+			hn.requeue(height)
+			return true
+		case <-timer.C:
+			// Keep waiting, but only time out once.
+			onTimeout()
+		case <-ctx.Done():
+			hn.requeue(height)
+			return false
+		}
+	}
+}
+
+// UpdateChan creates a channel that will receive height updates. All calls to
+// UpdateChan should be completed before blocks start being connected. Heights
+// signaled while the channel is full are coalesced, so a slow receiver will
+// skip intermediate heights but always receive the latest height.
+func (pgb *ChainDB) UpdateChan() chan uint32 {
+	hn := newHeightNotifier(pgb.heightNtfnBuffer)
+	i := len(pgb.heightClients)
+	pgb.heightClients = append(pgb.heightClients, hn)
+	go hn.run(pgb.ctx, heightNtfnTimeout, func() {
+		log.Criticalf("(*ChainDB).SignalHeight: heightClients[%d] timed out. Forcing a shutdown.", i)
+		pgb.shutdownDcrdata()
+	})
+	return hn.c
+}
+
+// SignalHeight signals the database height to any registered receivers.
+// This function is exported so that it can be called once externally after all
+// update channel clients have subscribed. SignalHeight does not block.
+func (pgb *ChainDB) SignalHeight(height uint32) {
+	for _, hn := range pgb.heightClients {
+		hn.notify(height)
+	}
+}
+
+// HeightNtfnStats returns the combined statistics of the height notifiers
+// created with UpdateChan.
+func (pgb *ChainDB) HeightNtfnStats() *apitypes.HeightNtfnStats {
+	stats := &apitypes.HeightNtfnStats{Clients: len(pgb.heightClients)}
+	for _, hn := range pgb.heightClients {
+		s := hn.Stats()
+		stats.Signaled += s.Signaled
+		stats.Delivered += s.Delivered
+		stats.Coalesced += s.Coalesced
+		stats.Dropped += s.Dropped
+	}
+	return stats
+}
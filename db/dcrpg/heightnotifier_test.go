@@ -0,0 +1,79 @@
+package dcrpg
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestHeightNotifierCoalesce(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	hn := newHeightNotifier(1)
+	timedOut := make(chan struct{}, 1)
+	go hn.run(ctx, time.Minute, func() { timedOut <- struct{}{} })
+
+	// With nobody receiving, all but the buffered height and one in-flight
+	// height are coalesced, but the latest height must still be delivered.
+	const lastHeight = 100
+	for h := uint32(1); h <= lastHeight; h++ {
+		hn.notify(h)
+	}
+
+	var got uint32
+	deadline := time.After(5 * time.Second)
+	for got != lastHeight {
+		select {
+		case got = <-hn.c:
+		case <-deadline:
+			t.Fatalf("latest height not delivered, last received %d", got)
+		}
+	}
+
+	stats := hn.Stats()
+	if stats.Signaled != lastHeight {
+		t.Errorf("expected %d signaled, got %d", lastHeight, stats.Signaled)
+	}
+	if stats.Coalesced == 0 || stats.Delivered+stats.Coalesced > stats.Signaled {
+		t.Errorf("unexpected delivered (%d) and coalesced (%d) counts for %d signaled",
+			stats.Delivered, stats.Coalesced, stats.Signaled)
+	}
+	if stats.Dropped != 0 {
+		t.Errorf("expected no dropped heights, got %d", stats.Dropped)
+	}
+
+	select {
+	case <-timedOut:
+		t.Errorf("unexpected timeout")
+	default:
+	}
+}
+
+func TestHeightNtfnStats(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	pgb := &ChainDB{ctx: ctx, heightNtfnBuffer: 4}
+	c1, c2 := pgb.UpdateChan(), pgb.UpdateChan()
+
+	// Each height is received before the next is signaled, so none are
+	// coalesced.
+	for want := uint32(1); want <= 2; want++ {
+		pgb.SignalHeight(want)
+		for _, c := range []chan uint32{c1, c2} {
+			select {
+			case got := <-c:
+				if got != want {
+					t.Fatalf("got height %d, wanted %d", got, want)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatalf("height %d not delivered", want)
+			}
+		}
+	}
+
+	stats := pgb.HeightNtfnStats()
+	if stats.Clients != 2 || stats.Signaled != 4 || stats.Delivered != 4 {
+		t.Errorf("unexpected stats %+v", *stats)
+	}
+}
@@ -78,6 +78,16 @@ func DeindexTransactionTableOnBlockHeight(db *sql.DB) (err error) {
 	return
 }
 
+func IndexTransactionTableOnCoinbase(db *sql.DB) (err error) {
+	_, err = db.Exec(internal.IndexTransactionTableOnCoinbase)
+	return
+}
+
+func DeindexTransactionTableOnCoinbase(db *sql.DB) (err error) {
+	_, err = db.Exec(internal.DeindexTransactionTableOnCoinbase)
+	return
+}
+
 // Blocks table indexes
 
 func IndexBlockTableOnHash(db *sql.DB) (err error) {
@@ -134,6 +144,16 @@ func DeindexVoutTableOnSpendTxID(db *sql.DB) (err error) {
 	return
 }
 
+func IndexVoutTableOnNullData(db *sql.DB) (err error) {
+	_, err = db.Exec(internal.IndexVoutTableOnNullData)
+	return
+}
+
+func DeindexVoutTableOnNullData(db *sql.DB) (err error) {
+	_, err = db.Exec(internal.DeindexVoutTableOnNullData)
+	return
+}
+
 // addresses table indexes
 
 // IndexBlockTimeOnTableAddress creates the index for the addresses table over
@@ -294,6 +314,16 @@ func DeindexTicketsTableOnPoolStatus(db *sql.DB) (err error) {
 	return
 }
 
+func IndexTicketsTableOnRewardAddresses(db *sql.DB) (err error) {
+	_, err = db.Exec(internal.IndexTicketsTableOnRewardAddresses)
+	return
+}
+
+func DeindexTicketsTableOnRewardAddresses(db *sql.DB) (err error) {
+	_, err = db.Exec(internal.DeindexTicketsTableOnRewardAddresses)
+	return
+}
+
 // missed votes table indexes
 
 func IndexMissesTableOnHashes(db *sql.DB) (err error) {
@@ -452,6 +482,7 @@ func (pgb *ChainDB) DeindexAll() error {
 		{DeindexTransactionTableOnHashes},
 		{DeindexTransactionTableOnBlockIn},
 		{DeindexTransactionTableOnBlockHeight},
+		{DeindexTransactionTableOnCoinbase},
 
 		// vins table
 		{DeindexVinTableOnVins},
@@ -460,6 +491,7 @@ func (pgb *ChainDB) DeindexAll() error {
 		// vouts table
 		{DeindexVoutTableOnTxHashIdx},
 		{DeindexVoutTableOnSpendTxID},
+		{DeindexVoutTableOnNullData},
 
 		// addresses table
 		{DeindexBlockTimeOnTableAddress},
@@ -493,6 +525,7 @@ func (pgb *ChainDB) DeindexAll() error {
 
 		// stats table
 		{DeindexStatsTableOnHeight},
+		{DeindexStatsTableOnBlocksID},
 	}
 
 	var err error
@@ -524,6 +557,7 @@ func (pgb *ChainDB) IndexAll(barLoad chan *dbtypes.ProgressBarLoad) error {
 		{Msg: "transactions table on tx/block hashes", IndexFunc: IndexTransactionTableOnHashes},
 		{Msg: "transactions table on block id/idx", IndexFunc: IndexTransactionTableOnBlockIn},
 		{Msg: "transactions table on block height", IndexFunc: IndexTransactionTableOnBlockHeight},
+		{Msg: "transactions table on coinbase block height", IndexFunc: IndexTransactionTableOnCoinbase},
 
 		// vins table
 		{Msg: "vins table on txin", IndexFunc: IndexVinTableOnVins},
@@ -532,6 +566,7 @@ func (pgb *ChainDB) IndexAll(barLoad chan *dbtypes.ProgressBarLoad) error {
 		// vouts table
 		{Msg: "vouts table on tx hash and index", IndexFunc: IndexVoutTableOnTxHashIdx},
 		// {Msg: "vouts table on spend tx row id", IndexFunc: IndexVoutTableOnSpendTxID},
+		{Msg: "vouts table on null data payload", IndexFunc: IndexVoutTableOnNullData},
 
 		// votes table
 		{Msg: "votes table on candidate block", IndexFunc: IndexVotesTableOnCandidate},
@@ -568,6 +603,7 @@ func (pgb *ChainDB) IndexAll(barLoad chan *dbtypes.ProgressBarLoad) error {
 
 		// stats table
 		{Msg: "stats table on height", IndexFunc: IndexStatsTableOnHeight},
+		{Msg: "stats table on blocks row ID", IndexFunc: IndexStatsTableOnBlocksID},
 	}
 
 	for _, val := range allIndexes {
@@ -589,12 +625,13 @@ func (pgb *ChainDB) IndexAll(barLoad chan *dbtypes.ProgressBarLoad) error {
 }
 
 // IndexTicketsTable creates indexes in the tickets table on ticket hash,
-// ticket pool status and tx DB ID columns.
+// ticket pool status, tx DB ID and reward addresses columns.
 func (pgb *ChainDB) IndexTicketsTable(barLoad chan *dbtypes.ProgressBarLoad) error {
 	ticketsTableIndexes := []indexingInfo{
 		{Msg: "ticket hash", IndexFunc: IndexTicketsTableOnHashes},
 		{Msg: "ticket pool status", IndexFunc: IndexTicketsTableOnPoolStatus},
 		{Msg: "transaction Db ID", IndexFunc: IndexTicketsTableOnTxDbID},
+		{Msg: "reward addresses", IndexFunc: IndexTicketsTableOnRewardAddresses},
 	}
 
 	for _, val := range ticketsTableIndexes {
@@ -616,12 +653,13 @@ func (pgb *ChainDB) IndexTicketsTable(barLoad chan *dbtypes.ProgressBarLoad) err
 }
 
 // DeindexTicketsTable drops indexes in the tickets table on ticket hash,
-// ticket pool status and tx DB ID columns.
+// ticket pool status, tx DB ID and reward addresses columns.
 func (pgb *ChainDB) DeindexTicketsTable() error {
 	ticketsTablesDeIndexes := []deIndexingInfo{
 		{DeindexTicketsTableOnHashes},
 		{DeindexTicketsTableOnPoolStatus},
 		{DeindexTicketsTableOnTxDbID},
+		{DeindexTicketsTableOnRewardAddresses},
 	}
 
 	var err error
@@ -716,3 +754,17 @@ func DeindexStatsTableOnHeight(db *sql.DB) (err error) {
 	_, err = db.Exec(internal.DeindexStatsOnHeight)
 	return
 }
+
+// IndexStatsTableOnBlocksID creates the index for the stats table over
+// blocks_id.
+func IndexStatsTableOnBlocksID(db *sql.DB) (err error) {
+	_, err = db.Exec(internal.IndexStatsOnBlocksID)
+	return
+}
+
+// DeindexStatsTableOnBlocksID drops the index for the stats table over
+// blocks_id.
+func DeindexStatsTableOnBlocksID(db *sql.DB) (err error) {
+	_, err = db.Exec(internal.DeindexStatsOnBlocksID)
+	return
+}
@@ -21,7 +21,11 @@ import (
 // GetRawTransaction gets a chainjson.TxRawResult for the specified transaction
 // hash.
 func (pgb *ChainDB) GetRawTransaction(txid *chainhash.Hash) (*chainjson.TxRawResult, error) {
-	txraw, err := rpcutils.GetTransactionVerboseByID(pgb.Client, txid)
+	var txraw *chainjson.TxRawResult
+	err := pgb.retryRPC(func() (err error) {
+		txraw, err = rpcutils.GetTransactionVerboseByID(pgb.Client, txid)
+		return
+	})
 	if err != nil {
 		log.Errorf("GetRawTransactionVerbose failed for: %s", txid)
 		return nil, err
@@ -29,9 +33,20 @@ func (pgb *ChainDB) GetRawTransaction(txid *chainhash.Hash) (*chainjson.TxRawRes
 	return txraw, nil
 }
 
+// MainchainTxInputs returns the block height of the mainchain transaction
+// with the given hash, and the value and previous outpoint addresses of each of
+// its inputs. If the transaction is not in a mainchain block, the error is
+// sql.ErrNoRows.
+func (pgb *ChainDB) MainchainTxInputs(ctx context.Context, txid string) (*dbtypes.TxInputsSummary, error) {
+	ctx, cancel := pgb.queryContext(ctx)
+	defer cancel()
+	summary, err := RetrieveMainchainTxInputs(ctx, pgb.db, txid)
+	return summary, pgb.replaceCancelError(err)
+}
+
 // GetBlockHeight returns the height of the block with the specified hash.
-func (pgb *ChainDB) GetBlockHeight(hash string) (int64, error) {
-	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+func (pgb *ChainDB) GetBlockHeight(ctx context.Context, hash string) (int64, error) {
+	ctx, cancel := pgb.queryContext(ctx)
 	defer cancel()
 	height, err := RetrieveBlockHeight(ctx, pgb.db, hash)
 	if err != nil {
@@ -76,9 +91,9 @@ func sortTxsByTimeAndHash(txns []txSortable) {
 // by hash. It also returns a list of recently (defined as greater than
 // recentBlockHeight) confirmed transactions that can be used to validate
 // mempool status.
-func (pgb *ChainDB) InsightAddressTransactions(addr []string, recentBlockHeight int64) (txs, recentTxs []chainhash.Hash, err error) {
+func (pgb *ChainDB) InsightAddressTransactions(ctx context.Context, addr []string, recentBlockHeight int64) (txs, recentTxs []chainhash.Hash, err error) {
 	// Time of a "recent" block
-	recentBlocktime, err0 := pgb.BlockTimeByHeight(recentBlockHeight)
+	recentBlocktime, err0 := pgb.BlockTimeByHeight(ctx, recentBlockHeight)
 	if err0 != nil {
 		return nil, nil, err0
 	}
@@ -87,7 +102,7 @@ func (pgb *ChainDB) InsightAddressTransactions(addr []string, recentBlockHeight
 	var txns []chainhash.Hash // []txSortable
 	var numRecent int
 	for i := range addr {
-		rows, err := pgb.AddressRowsMerged(addr[i])
+		rows, err := pgb.AddressRowsMerged(ctx, addr[i])
 		if err != nil {
 			return nil, nil, err
 		}
@@ -119,8 +134,8 @@ func (pgb *ChainDB) InsightAddressTransactions(addr []string, recentBlockHeight
 
 // AddressIDsByOutpoint fetches all address row IDs for a given outpoint
 // (txHash:voutIndex).
-func (pgb *ChainDB) AddressIDsByOutpoint(txHash string, voutIndex uint32) ([]uint64, []string, int64, error) {
-	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+func (pgb *ChainDB) AddressIDsByOutpoint(ctx context.Context, txHash string, voutIndex uint32) ([]uint64, []string, int64, error) {
+	ctx, cancel := pgb.queryContext(ctx)
 	defer cancel()
 	ids, addrs, val, err := RetrieveAddressIDsByOutpoint(ctx, pgb.db, txHash, voutIndex)
 	return ids, addrs, val, pgb.replaceCancelError(err)
@@ -138,8 +153,12 @@ func (pgb *ChainDB) InsightSearchRPCAddressTransactions(addr string, count,
 		return nil
 	}
 	prevVoutExtraData := true
-	txs, err := pgb.Client.SearchRawTransactionsVerbose(
-		address, skip, count, prevVoutExtraData, true, nil)
+	var txs []*chainjson.SearchRawTransactionsResult
+	err = pgb.retryRPC(func() (err error) {
+		txs, err = pgb.Client.SearchRawTransactionsVerbose(
+			address, skip, count, prevVoutExtraData, true, nil)
+		return
+	})
 
 	if err != nil {
 		log.Warnf("GetAddressTransactions failed for address %s: %v", addr, err)
@@ -151,8 +170,7 @@ func (pgb *ChainDB) InsightSearchRPCAddressTransactions(addr string, count,
 // GetTransactionHex returns the full serialized transaction for the specified
 // transaction hash as a hex encode string.
 func (pgb *ChainDB) GetTransactionHex(txid *chainhash.Hash) string {
-	txraw, err := rpcutils.GetTransactionVerboseByID(pgb.Client, txid)
-
+	txraw, err := pgb.GetRawTransaction(txid)
 	if err != nil {
 		log.Errorf("GetRawTransactionVerbose failed for: %v", err)
 		return ""
@@ -189,8 +207,8 @@ func makeBlockTransactions(blockVerbose *chainjson.GetBlockVerboseResult) *apity
 
 // GetBlockHash returns the hash of the block at the specified height. TODO:
 // create GetBlockHashes to return all blocks at a given height.
-func (pgb *ChainDB) GetBlockHash(idx int64) (string, error) {
-	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+func (pgb *ChainDB) GetBlockHash(ctx context.Context, idx int64) (string, error) {
+	ctx, cancel := pgb.queryContext(ctx)
 	defer cancel()
 	hash, err := RetrieveBlockHash(ctx, pgb.db, idx)
 	if err != nil {
@@ -200,10 +218,11 @@ func (pgb *ChainDB) GetBlockHash(idx int64) (string, error) {
 	return hash, nil
 }
 
-// BlockSummaryTimeRange returns the blocks created within a specified time
-// range (min, max time), up to limit transactions.
-func (pgb *ChainDB) BlockSummaryTimeRange(min, max int64, limit int) ([]dbtypes.BlockDataBasic, error) {
-	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+// BlockSummaryTimeRange returns the mainchain blocks created within a specified
+// time range (min, max time), newest first, up to limit blocks. A limit of 0
+// returns all blocks in the range.
+func (pgb *ChainDB) BlockSummaryTimeRange(ctx context.Context, min, max int64, limit int) ([]dbtypes.BlockDataBasic, error) {
+	ctx, cancel := pgb.queryContext(ctx)
 	defer cancel()
 	blockSummary, err := RetrieveBlockSummaryByTimeRange(ctx, pgb.db, min, max, limit)
 	return blockSummary, pgb.replaceCancelError(err)
@@ -211,7 +230,7 @@ func (pgb *ChainDB) BlockSummaryTimeRange(min, max int64, limit int) ([]dbtypes.
 
 // AddressUTXO returns the unspent transaction outputs (UTXOs) paying to the
 // specified address in a []*dbtypes.AddressTxnOutput.
-func (pgb *ChainDB) AddressUTXO(address string) ([]*dbtypes.AddressTxnOutput, bool, error) {
+func (pgb *ChainDB) AddressUTXO(ctx context.Context, address string) ([]*dbtypes.AddressTxnOutput, bool, error) {
 	// Check the cache first.
 	bestHash, height := pgb.BestBlock()
 	utxos, validHeight := pgb.AddressCache.UTXOs(address)
@@ -242,7 +261,7 @@ func (pgb *ChainDB) AddressUTXO(address string) ([]*dbtypes.AddressTxnOutput, bo
 		<-wait
 
 		// Try again, starting with the cache.
-		return pgb.AddressUTXO(address)
+		return pgb.AddressUTXO(ctx, address)
 	}
 
 	// We will run the DB query, so block others from doing the same. When query
@@ -256,9 +275,9 @@ func (pgb *ChainDB) AddressUTXO(address string) ([]*dbtypes.AddressTxnOutput, bo
 	}
 
 	// Query the DB for the current UTXO set for this address.
-	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	ctx, cancel := pgb.queryContext(ctx)
 	defer cancel()
-	txnOutputs, err := RetrieveAddressDbUTXOs(ctx, pgb.db, address)
+	txnOutputs, err := RetrieveAddressDbUTXOs(ctx, pgb.db, pgb.archive, address)
 	if err != nil {
 		return nil, false, pgb.replaceCancelError(err)
 	}
@@ -271,8 +290,8 @@ func (pgb *ChainDB) AddressUTXO(address string) ([]*dbtypes.AddressTxnOutput, bo
 
 // SpendDetailsForFundingTx will return the details of any spending transactions
 // (tx, index, block height) for a given funding transaction.
-func (pgb *ChainDB) SpendDetailsForFundingTx(fundHash string) ([]*apitypes.SpendByFundingHash, error) {
-	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+func (pgb *ChainDB) SpendDetailsForFundingTx(ctx context.Context, fundHash string) ([]*apitypes.SpendByFundingHash, error) {
+	ctx, cancel := pgb.queryContext(ctx)
 	defer cancel()
 	addrRow, err := RetrieveSpendingTxsByFundingTxWithBlockHeight(ctx, pgb.db, fundHash)
 	if err != nil {
@@ -0,0 +1,41 @@
+// Copyright (c) 2020, The Decred developers
+// See LICENSE for details.
+
+package internal
+
+// These queries relate primarily to the "address_counts" table, which holds the
+// numbers of valid mainchain funding and spending rows of the addresses table
+// for each address.
+const (
+	CreateAddressCountsTable = `CREATE TABLE IF NOT EXISTS address_counts (
+		address TEXT PRIMARY KEY,
+		num_funding INT8 NOT NULL,
+		num_spending INT8 NOT NULL
+	);`
+
+	// UpdateAddressCounts adds the changes in the numbers of funding ($2) and
+	// spending ($3) rows to the counts of the addresses ($1), inserting the
+	// counts of new addresses.
+	UpdateAddressCounts = `INSERT INTO address_counts (address, num_funding, num_spending)
+		SELECT * FROM UNNEST($1::TEXT[], $2::INT8[], $3::INT8[])
+		ON CONFLICT (address) DO UPDATE
+		SET num_funding = address_counts.num_funding + EXCLUDED.num_funding,
+			num_spending = address_counts.num_spending + EXCLUDED.num_spending;`
+
+	SelectAddressCounts = `SELECT num_funding, num_spending
+		FROM address_counts
+		WHERE address = $1;`
+
+	// The following statements recount all addresses from the addresses table,
+	// such as after an upgrade or a snapshot import.
+
+	DeleteAddressCounts = `DELETE FROM address_counts;`
+
+	InsertAddressCountsFromAddresses = `INSERT INTO address_counts (address, num_funding, num_spending)
+		SELECT address,
+			COUNT(*) FILTER (WHERE is_funding),
+			COUNT(*) FILTER (WHERE NOT is_funding)
+		FROM addresses
+		WHERE valid_mainchain
+		GROUP BY address;`
+)
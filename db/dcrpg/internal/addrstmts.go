@@ -24,30 +24,42 @@ const (
 		tx_vin_vout_index, tx_vin_vout_row_id, value, block_time, is_funding, valid_mainchain, tx_type)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) `
 
+	// The address row insert statements return the row id and the change in
+	// the number of valid mainchain rows for the address, for the
+	// address_counts table.
+
 	// InsertAddressRow inserts a address block row without checking for unique
 	// index conflicts. This should only be used before the unique indexes are
 	// created or there may be constraint violations (errors).
-	InsertAddressRow = insertAddressRow + `RETURNING id;`
+	InsertAddressRow = insertAddressRow + `RETURNING id, valid_mainchain::INT;`
 
 	// UpsertAddressRow is an upsert (insert or update on conflict), returning
-	// the inserted/updated address row id.
-	UpsertAddressRow = insertAddressRow + `ON CONFLICT (tx_vin_vout_row_id, address, is_funding) DO UPDATE
+	// the inserted/updated address row id. The change in the number of valid
+	// mainchain rows accounts for the validity of an existing row.
+	UpsertAddressRow = `WITH existing AS (
+			SELECT valid_mainchain FROM addresses
+			WHERE address = $1 AND is_funding = $8 AND tx_vin_vout_row_id = $5
+		)
+		` + insertAddressRow + `ON CONFLICT (tx_vin_vout_row_id, address, is_funding) DO UPDATE
 		SET matching_tx_hash = $2, tx_hash = $3, tx_vin_vout_index = $4,
-		block_time = $7, valid_mainchain = $9 RETURNING id;`
+		block_time = $7, valid_mainchain = $9
+		RETURNING id, valid_mainchain::INT - COALESCE((SELECT valid_mainchain::INT FROM existing), 0);`
 
 	// InsertAddressRowOnConflictDoNothing allows an INSERT with a DO NOTHING on
 	// conflict with addresses' unique tx index, while returning the row id of
 	// either the inserted row or the existing row that causes the conflict. The
 	// complexity of this statement is necessary to avoid an unnecessary UPSERT,
-	// which would have performance consequences. The row is not locked.
+	// which would have performance consequences. The row is not locked. An
+	// existing row is unchanged, so it does not change the number of valid
+	// mainchain rows.
 	InsertAddressRowOnConflictDoNothing = `WITH inserting AS (` +
 		insertAddressRow +
 		`	ON CONFLICT (tx_vin_vout_row_id, address, is_funding) DO NOTHING -- no lock on row
-			RETURNING id
+			RETURNING id, valid_mainchain::INT AS num_valid
 		)
-		SELECT id FROM inserting
+		SELECT id, num_valid FROM inserting
 		UNION  ALL
-		SELECT id FROM addresses
+		SELECT id, 0 FROM addresses
 		WHERE  address = $1 AND is_funding = $8 AND tx_vin_vout_row_id = $5 -- only executed if no INSERT
 		LIMIT  1;`
 
@@ -83,12 +95,51 @@ const (
 	addrsColumnNames = `id, address, matching_tx_hash, tx_hash, tx_type, valid_mainchain,
 		tx_vin_vout_index, block_time, tx_vin_vout_row_id, value, is_funding`
 
+	// addrsRowOrder is the order of an address' rows, newest first. The order
+	// is total for the valid mainchain rows, which is required for stable
+	// pagination: the rows of a block are ordered by transaction hash, since
+	// the addresses table does not record the transaction's block index, then
+	// by the input or output index, with the inputs (is_funding false) first.
+	addrsRowOrder = `block_time DESC, tx_hash ASC, is_funding, tx_vin_vout_index`
+
+	// addrsMergedRowOrder is the order of the merged views, which have one row
+	// per transaction.
+	addrsMergedRowOrder = `block_time DESC, tx_hash ASC`
+
 	SelectAddressAllByAddress = `SELECT ` + addrsColumnNames + ` FROM addresses
 		WHERE address=$1
-		ORDER BY block_time DESC, tx_hash ASC;`
+		ORDER BY ` + addrsRowOrder + `;`
 	SelectAddressAllMainchainByAddress = `SELECT ` + addrsColumnNames + ` FROM addresses
 		WHERE address=$1 AND valid_mainchain
-		ORDER BY block_time DESC, tx_hash ASC;`
+		ORDER BY ` + addrsRowOrder + `;`
+
+	// addressRowsAtHeight selects the valid mainchain rows of the address ($1)
+	// for the transactions mined at or below the given height ($2). The
+	// matching transaction is cleared if it was mined above that height, so
+	// the rows are as they were when the block at that height was the best
+	// block, even if newer blocks are stored while querying. A transaction has
+	// at most one valid mainchain row, so the joins do not repeat rows.
+	addressRowsAtHeight = `(SELECT addresses.id, addresses.address,
+			CASE WHEN matching.tx_hash IS NULL THEN ''
+				ELSE addresses.matching_tx_hash END AS matching_tx_hash,
+			addresses.tx_hash, addresses.tx_type, addresses.valid_mainchain,
+			addresses.tx_vin_vout_index, addresses.block_time,
+			addresses.tx_vin_vout_row_id, addresses.value, addresses.is_funding
+		FROM addresses
+		JOIN transactions AS mined ON mined.tx_hash = addresses.tx_hash
+			AND mined.is_mainchain AND mined.is_valid
+			AND mined.block_height <= $2
+		LEFT JOIN transactions AS matching ON matching.tx_hash = addresses.matching_tx_hash
+			AND matching.is_mainchain AND matching.is_valid
+			AND matching.block_height <= $2
+		WHERE addresses.address = $1 AND addresses.valid_mainchain
+		) AS addresses`
+
+	// SelectAddressAllMainchainByAddressAtHeight is like
+	// SelectAddressAllMainchainByAddress, but pinned to the block height $2.
+	SelectAddressAllMainchainByAddressAtHeight = `SELECT ` + addrsColumnNames +
+		` FROM ` + addressRowsAtHeight + `
+		ORDER BY ` + addrsRowOrder + `;`
 
 	SelectAddressesAllTxnWithHeight = `SELECT
 			addresses.tx_hash,
@@ -112,7 +163,7 @@ const (
 	// where grouping is done by a specified time interval, for an addresses.
 	selectAddressTimeGroupingCount = `SELECT COUNT(DISTINCT %s) FROM addresses WHERE address=$1;`
 
-	SelectAddressUnspentCountANDValue = `SELECT COUNT(*), SUM(value) FROM addresses
+	SelectAddressUnspentCountANDValue = `SELECT COUNT(*), COALESCE(SUM(value), 0) FROM addresses
 	    WHERE address = $1 AND is_funding = TRUE AND matching_tx_hash = '' AND valid_mainchain = TRUE;`
 
 	SelectAddressSpentCountANDValue = `SELECT COUNT(*), SUM(value) FROM addresses
@@ -147,13 +198,16 @@ const (
 	// (3 rows)
 	//
 	// Since part of the grouping is on "matching_tx_hash = ''", what is
-	// logically "any" empty matching is actually no_empty_matching.
+	// logically "any" empty matching is actually no_empty_matching. The
+	// earliest and latest block times of each group are also selected.
 	SelectAddressSpentUnspentCountAndValue = `SELECT
 			BOOL_AND(tx_type = 0) AS is_regular,
 			COUNT(*),
 			SUM(value),
 			is_funding,
-			BOOL_AND(matching_tx_hash = '') AS all_empty_matching
+			BOOL_AND(matching_tx_hash = '') AS all_empty_matching,
+			MIN(block_time),
+			MAX(block_time)
 			-- NOT BOOL_AND(matching_tx_hash = '') AS no_empty_matching
 		FROM addresses
 		WHERE address = $1 AND valid_mainchain = TRUE
@@ -161,6 +215,43 @@ const (
 			matching_tx_hash=''  -- separate spent and unspent
 		ORDER BY count, is_funding;`
 
+	// SelectAddressSpentUnspentCountAndValueAtHeight is like
+	// SelectAddressSpentUnspentCountAndValue, but pinned to the block height
+	// $2.
+	SelectAddressSpentUnspentCountAndValueAtHeight = `SELECT
+			BOOL_AND(tx_type = 0) AS is_regular,
+			COUNT(*),
+			SUM(value),
+			is_funding,
+			BOOL_AND(matching_tx_hash = '') AS all_empty_matching,
+			MIN(block_time),
+			MAX(block_time)
+		FROM ` + addressRowsAtHeight + `
+		GROUP BY tx_type=0, is_funding,
+			matching_tx_hash=''  -- separate spent and unspent
+		ORDER BY count, is_funding;`
+
+	// SelectAddressReceivedSentAtHeight gets the total amount received and
+	// sent by the given address in mainchain transactions mined at or below
+	// the given block height.
+	SelectAddressReceivedSentAtHeight = `SELECT
+			COALESCE(SUM(CASE WHEN addresses.is_funding THEN addresses.value ELSE 0 END), 0),
+			COALESCE(SUM(CASE WHEN addresses.is_funding THEN 0 ELSE addresses.value END), 0)
+		FROM addresses
+		JOIN transactions ON addresses.tx_hash = transactions.tx_hash
+			AND transactions.is_mainchain
+		WHERE addresses.address = $1 AND addresses.valid_mainchain
+			AND transactions.block_height <= $2;`
+
+	// SelectAddressSummary gets the number of distinct valid mainchain
+	// transactions involving the given address, and the UNIX times of the
+	// first and last of them (0 if there are none).
+	SelectAddressSummary = `SELECT COUNT(DISTINCT tx_hash),
+			COALESCE(EXTRACT(EPOCH FROM MIN(block_time)), 0)::INT8,
+			COALESCE(EXTRACT(EPOCH FROM MAX(block_time)), 0)::INT8
+		FROM addresses
+		WHERE address = $1 AND valid_mainchain;`
+
 	SelectAddressUnspentWithTxn = `SELECT
 			addresses.address,
 			addresses.tx_hash,
@@ -178,11 +269,54 @@ const (
 	// Since tx_vin_vout_row_id is the vouts table primary key (id) when
 	// is_funding=true, there is no need to join vouts on tx_hash and tx_index.
 
+	// selectAddressUTXOsPage selects up to $2 of the unspent outputs paying to
+	// the address $1, skipping $3, in the order specified by the format verb.
+	// The funding transaction's tree, type and block index determine the
+	// maturity of the output.
+	selectAddressUTXOsPage = `SELECT
+			addresses.tx_hash,
+			addresses.tx_vin_vout_index,
+			addresses.value,
+			transactions.block_height,
+			addresses.block_time,
+			transactions.tree,
+			transactions.tx_type,
+			transactions.block_index,
+			vouts.pkscript
+		FROM addresses
+		JOIN transactions ON addresses.tx_hash = transactions.tx_hash
+			AND transactions.is_valid AND transactions.is_mainchain
+		JOIN vouts ON addresses.tx_vin_vout_row_id = vouts.id
+		WHERE addresses.address = $1 AND addresses.is_funding
+			AND addresses.matching_tx_hash = '' AND addresses.valid_mainchain
+		ORDER BY %s
+		LIMIT $2 OFFSET $3;`
+
 	SelectAddressLimitNByAddress = `SELECT ` + addrsColumnNames + ` FROM addresses
 		WHERE address=$1 AND valid_mainchain = TRUE
-		ORDER BY block_time DESC, tx_hash ASC
+		ORDER BY ` + addrsRowOrder + `
+		LIMIT $2 OFFSET $3;`
+
+	// SelectAddressLimitNByAddressTimeRange is like SelectAddressLimitNByAddress,
+	// but only for the rows with a block time between $4 and $5, inclusive.
+	SelectAddressLimitNByAddressTimeRange = `SELECT ` + addrsColumnNames + ` FROM addresses
+		WHERE address=$1 AND valid_mainchain = TRUE
+			AND block_time >= $4 AND block_time <= $5
+		ORDER BY ` + addrsRowOrder + `
 		LIMIT $2 OFFSET $3;`
 
+	// SelectAddressLimitNByAddressTimeRangeAfter is like
+	// SelectAddressLimitNByAddressTimeRange, but selects the rows after the
+	// row with the block time $3, transaction hash $4, funding flag $5 and
+	// input or output index $6 in the addrsRowOrder instead of skipping rows.
+	SelectAddressLimitNByAddressTimeRangeAfter = `SELECT ` + addrsColumnNames + ` FROM addresses
+		WHERE address=$1 AND valid_mainchain = TRUE
+			AND block_time >= $7 AND block_time <= $8
+			AND (block_time < $3 OR (block_time = $3
+				AND (tx_hash, is_funding, tx_vin_vout_index) > ($4, $5, $6)))
+		ORDER BY ` + addrsRowOrder + `
+		LIMIT $2;`
+
 	// SelectAddressLimitNByAddressSubQry was used in certain cases prior to
 	// sorting the block_time_index.
 	// SelectAddressLimitNByAddressSubQry = `WITH these AS (SELECT ` + addrsColumnNames +
@@ -194,20 +328,20 @@ const (
 		FROM addresses
 		WHERE address=$1 AND is_funding = FALSE          -- spending transactions
 		GROUP BY (tx_hash, valid_mainchain, block_time)  -- merging common transactions in same valid mainchain block
-		ORDER BY block_time DESC LIMIT $2 OFFSET $3;`
+		ORDER BY ` + addrsMergedRowOrder + ` LIMIT $2 OFFSET $3;`
 
 	SelectAddressMergedCreditView = `SELECT tx_hash, valid_mainchain, block_time, sum(value), COUNT(*)
 		FROM addresses
 		WHERE address=$1 AND is_funding = TRUE           -- funding transactions
 		GROUP BY (tx_hash, valid_mainchain, block_time)  -- merging common transactions in same valid mainchain block
-		ORDER BY block_time DESC LIMIT $2 OFFSET $3;`
+		ORDER BY ` + addrsMergedRowOrder + ` LIMIT $2 OFFSET $3;`
 
 	SelectAddressMergedViewAll = `SELECT tx_hash, valid_mainchain, block_time, sum(CASE WHEN is_funding = TRUE THEN value ELSE 0 END),
 		sum(CASE WHEN is_funding = FALSE THEN value ELSE 0 END), COUNT(*)
 		FROM addresses
 		WHERE address=$1                                 -- spending and funding transactions
 		GROUP BY (tx_hash, valid_mainchain, block_time)  -- merging common transactions in same valid mainchain block
-		ORDER BY block_time DESC`
+		ORDER BY ` + addrsMergedRowOrder
 
 	SelectAddressMergedView = SelectAddressMergedViewAll + ` LIMIT $2 OFFSET $3;`
 
@@ -216,12 +350,12 @@ const (
 
 	SelectAddressDebitsLimitNByAddress = `SELECT ` + addrsColumnNames + `
 		FROM addresses WHERE address=$1 AND is_funding = FALSE AND valid_mainchain
-		ORDER BY block_time DESC, tx_hash ASC
+		ORDER BY ` + addrsRowOrder + `
 		LIMIT $2 OFFSET $3;`
 
 	SelectAddressCreditsLimitNByAddress = `SELECT ` + addrsColumnNames + `
 		FROM addresses WHERE address=$1 AND is_funding AND valid_mainchain
-		ORDER BY block_time DESC, tx_hash ASC
+		ORDER BY ` + addrsRowOrder + `
 		LIMIT $2 OFFSET $3;`
 
 	SelectAddressIDsByFundingOutpoint = `SELECT id, address, value
@@ -267,6 +401,31 @@ const (
 			AND vouts.tx_index=addresses.tx_vin_vout_index
 			AND transactions.id=vouts.spend_tx_row_id;`
 
+	// CountAddressesMismatchedMatchingTxHashRange counts the funding
+	// addresses rows with a matching_tx_hash that is missing or differs from
+	// the spending transaction of the output, for spending transactions in
+	// blocks [$1, $2).
+	CountAddressesMismatchedMatchingTxHashRange = `SELECT COUNT(*)
+		FROM addresses
+		JOIN vouts ON vouts.tx_hash=addresses.tx_hash
+			AND vouts.tx_index=addresses.tx_vin_vout_index
+		JOIN transactions ON transactions.id=vouts.spend_tx_row_id
+		WHERE transactions.block_height >= $1 AND transactions.block_height < $2
+			AND vouts.value>0 AND addresses.is_funding
+			AND addresses.matching_tx_hash IS DISTINCT FROM transactions.tx_hash;`
+
+	// UpdateAddressesMismatchedMatchingTxHashRange is like
+	// UpdateAllAddressesMatchingTxHashRange, but only sets matching_tx_hash on
+	// the rows where it is missing or wrong.
+	UpdateAddressesMismatchedMatchingTxHashRange = `UPDATE addresses SET matching_tx_hash=transactions.tx_hash
+		FROM vouts, transactions
+		WHERE transactions.block_height >= $1 AND transactions.block_height < $2
+			AND vouts.value>0 AND addresses.is_funding
+			AND vouts.tx_hash=addresses.tx_hash
+			AND vouts.tx_index=addresses.tx_vin_vout_index
+			AND transactions.id=vouts.spend_tx_row_id
+			AND addresses.matching_tx_hash IS DISTINCT FROM transactions.tx_hash;`
+
 	UpdateAllAddressesMatchingTxHash = `UPDATE addresses SET matching_tx_hash=transactions.tx_hash
 		FROM vouts, transactions
 		WHERE vouts.value>0 AND addresses.is_funding
@@ -306,11 +465,18 @@ const (
 	// where matching_tx_hash is not already set.
 	AssignMatchingTxHashForOutpoint = SetAddressMatchingTxHashForOutpoint + ` AND matching_tx_hash='';`
 
+	// SetAddressMainchainForVoutIDs and SetAddressMainchainForVinIDs only
+	// update the rows with a different valid_mainchain, returning their
+	// addresses for the address_counts table.
 	SetAddressMainchainForVoutIDs = `UPDATE addresses SET valid_mainchain=$1
-		WHERE is_funding = TRUE AND tx_vin_vout_row_id=$2;`
+		WHERE is_funding = TRUE AND tx_vin_vout_row_id=$2
+			AND valid_mainchain IS DISTINCT FROM $1
+		RETURNING address;`
 
 	SetAddressMainchainForVinIDs = `UPDATE addresses SET valid_mainchain=$1
-		WHERE is_funding = FALSE AND tx_vin_vout_row_id=$2;`
+		WHERE is_funding = FALSE AND tx_vin_vout_row_id=$2
+			AND valid_mainchain IS DISTINCT FROM $1
+		RETURNING address;`
 
 	SetTxTypeOnAddressesByVinAndVoutIDs = `UPDATE addresses SET tx_type=$1 WHERE
 		tx_vin_vout_row_id=$2 AND is_funding=$3;`
@@ -378,6 +544,19 @@ const (
 		WHERE addresses.tx_hash = tr.tx_hash;`
 )
 
+// Statements selecting a page of the unspent outputs paying to an address,
+// newest, oldest, largest or smallest first. Ties are ordered by outpoint.
+var (
+	SelectAddressUTXOsNewest = fmt.Sprintf(selectAddressUTXOsPage,
+		`addresses.block_time DESC, addresses.tx_hash, addresses.tx_vin_vout_index`)
+	SelectAddressUTXOsOldest = fmt.Sprintf(selectAddressUTXOsPage,
+		`addresses.block_time, addresses.tx_hash, addresses.tx_vin_vout_index`)
+	SelectAddressUTXOsLargest = fmt.Sprintf(selectAddressUTXOsPage,
+		`addresses.value DESC, addresses.tx_hash, addresses.tx_vin_vout_index`)
+	SelectAddressUTXOsSmallest = fmt.Sprintf(selectAddressUTXOsPage,
+		`addresses.value, addresses.tx_hash, addresses.tx_vin_vout_index`)
+)
+
 // MakeAddressRowInsertStatement returns the appropriate addresses insert statement for
 // the desired conflict checking and handling behavior. For checked=false, no ON
 // CONFLICT checks will be performed, and the value of updateOnConflict is
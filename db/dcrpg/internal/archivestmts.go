@@ -0,0 +1,158 @@
+// Copyright (c) 2020, The Decred developers
+// See LICENSE for details.
+
+package internal
+
+import (
+	"regexp"
+	"sync"
+)
+
+// These queries relate to the archive schema, which holds the address and vout
+// rows of outputs spent deep in the main chain. The archive tables inherit from
+// the addresses and vouts tables, so queries of the parent tables read the
+// archived rows too, while the queries of ONLY the parent (hot) tables, such as
+// the unspent output queries, use the smaller indexes of the recent and unspent
+// rows. Updates and deletes of the parent tables apply to the archived rows.
+const (
+	CreateArchiveSchema = `CREATE SCHEMA IF NOT EXISTS archive;`
+
+	DropArchiveSchema = `DROP SCHEMA IF EXISTS archive CASCADE;`
+
+	CreateArchiveAddressTable = `CREATE TABLE IF NOT EXISTS archive.addresses ()
+		INHERITS (addresses);`
+
+	CreateArchiveVoutTable = `CREATE TABLE IF NOT EXISTS archive.vouts ()
+		INHERITS (vouts);`
+
+	// CreateArchiveProgressTable creates the table recording the height below
+	// which spent rows have been archived, and the latest block time of the
+	// main chain blocks below that height. Address rows with a later block
+	// time are never archived.
+	CreateArchiveProgressTable = `CREATE TABLE IF NOT EXISTS archive.progress (
+		height INT8 NOT NULL,
+		boundary_time TIMESTAMPTZ NOT NULL
+	);`
+
+	// Indexes are not inherited. The archived rows are looked up by address
+	// and transaction for the address history and transaction pages, and by id
+	// when updating vouts.
+	IndexArchiveAddressTableOnAddress = `CREATE INDEX IF NOT EXISTS ix_archive_addresses_address
+		ON archive.addresses(address, block_time DESC);`
+	IndexArchiveAddressTableOnTxHash = `CREATE INDEX IF NOT EXISTS ix_archive_addresses_tx_hash
+		ON archive.addresses(tx_hash);`
+	IndexArchiveAddressTableOnMatchingTxHash = `CREATE INDEX IF NOT EXISTS ix_archive_addresses_matching_tx_hash
+		ON archive.addresses(matching_tx_hash);`
+	IndexArchiveVoutTableOnID = `CREATE UNIQUE INDEX IF NOT EXISTS uix_archive_vouts_id
+		ON archive.vouts(id);`
+	IndexArchiveVoutTableOnTxHashIdx = `CREATE INDEX IF NOT EXISTS ix_archive_vouts_txhash_ind
+		ON archive.vouts(tx_hash, tx_index);`
+
+	// SelectArchiveExists checks if the archive schema has been created.
+	SelectArchiveExists = `SELECT to_regclass('archive.progress') IS NOT NULL;`
+
+	SelectArchiveProgress = `SELECT height, boundary_time FROM archive.progress;`
+
+	DeleteArchiveProgress = `DELETE FROM archive.progress;`
+
+	// InsertArchiveProgress records the archived height $1 and the latest
+	// block time of the main chain blocks below it.
+	InsertArchiveProgress = `INSERT INTO archive.progress (height, boundary_time)
+		SELECT $1, COALESCE(MAX(time), 'epoch'::TIMESTAMPTZ)
+		FROM blocks
+		WHERE height < $1 AND is_mainchain;`
+
+	// ArchiveSpentAddressRows moves the valid main chain address rows of
+	// outputs spent by, and the inputs of, valid main chain transactions in
+	// the height range [$1, $2) to the archive. The funding and spending rows
+	// are matched separately so that the matching_tx_hash and tx_hash indexes
+	// are used.
+	ArchiveSpentAddressRows = `WITH funding AS (
+			DELETE FROM ONLY addresses
+			USING transactions
+			WHERE addresses.valid_mainchain AND addresses.is_funding
+				AND addresses.matching_tx_hash = transactions.tx_hash
+				AND transactions.block_height >= $1 AND transactions.block_height < $2
+				AND transactions.is_valid AND transactions.is_mainchain
+			RETURNING addresses.*
+		), spending AS (
+			DELETE FROM ONLY addresses
+			USING transactions
+			WHERE addresses.valid_mainchain AND NOT addresses.is_funding
+				AND addresses.tx_hash = transactions.tx_hash
+				AND transactions.block_height >= $1 AND transactions.block_height < $2
+				AND transactions.is_valid AND transactions.is_mainchain
+			RETURNING addresses.*
+		)
+		INSERT INTO archive.addresses
+		SELECT * FROM funding
+		UNION ALL
+		SELECT * FROM spending;`
+
+	// ArchiveSpentVouts moves the vouts spent by valid main chain transactions
+	// in the height range [$1, $2) to the archive.
+	ArchiveSpentVouts = `WITH moved AS (
+			DELETE FROM ONLY vouts
+			USING transactions
+			WHERE vouts.spend_tx_row_id = transactions.id
+				AND transactions.block_height >= $1 AND transactions.block_height < $2
+				AND transactions.is_valid AND transactions.is_mainchain
+			RETURNING vouts.*
+		)
+		INSERT INTO archive.vouts SELECT * FROM moved;`
+
+	// UnarchiveAddressRows moves the archived address rows back to the hot
+	// addresses table.
+	UnarchiveAddressRows = `WITH moved AS (
+			DELETE FROM archive.addresses
+			RETURNING *
+		)
+		INSERT INTO addresses SELECT * FROM moved;`
+
+	// UnarchiveVouts moves the archived vouts back to the hot vouts table.
+	UnarchiveVouts = `WITH moved AS (
+			DELETE FROM archive.vouts
+			RETURNING *
+		)
+		INSERT INTO vouts SELECT * FROM moved;`
+)
+
+// ArchiveTables are the archive schema tables in the order they are created.
+var ArchiveTables = [][2]string{
+	{"archive.addresses", CreateArchiveAddressTable},
+	{"archive.vouts", CreateArchiveVoutTable},
+	{"archive.progress", CreateArchiveProgressTable},
+}
+
+// ArchiveIndexes create the indexes of the archive tables.
+var ArchiveIndexes = []string{
+	IndexArchiveAddressTableOnAddress,
+	IndexArchiveAddressTableOnTxHash,
+	IndexArchiveAddressTableOnMatchingTxHash,
+	IndexArchiveVoutTableOnID,
+	IndexArchiveVoutTableOnTxHashIdx,
+}
+
+var (
+	hotTableRE    = regexp.MustCompile(`\b(FROM|JOIN)\s+(addresses|vouts)\b`)
+	hotQueries    = make(map[string]string)
+	hotQueriesMtx sync.RWMutex
+)
+
+// HotTables returns the query with its reads of the addresses and vouts tables
+// restricted to the hot tables (FROM ONLY addresses), excluding the archived
+// rows. It must only be used for queries that cannot match archived rows, such
+// as those of unspent outputs.
+func HotTables(query string) string {
+	hotQueriesMtx.RLock()
+	hot, ok := hotQueries[query]
+	hotQueriesMtx.RUnlock()
+	if ok {
+		return hot
+	}
+	hot = hotTableRE.ReplaceAllString(query, "$1 ONLY $2")
+	hotQueriesMtx.Lock()
+	hotQueries[query] = hot
+	hotQueriesMtx.Unlock()
+	return hot
+}
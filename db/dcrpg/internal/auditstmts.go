@@ -0,0 +1,129 @@
+// Copyright (c) 2020, The Decred developers
+// See LICENSE for details.
+
+package internal
+
+// Referential integrity audit of the blocks, transactions, vins, vouts and
+// addresses tables. The tables have no foreign key constraints, so rows may be
+// orphaned by an interrupted sync, reorg or manual intervention.
+const (
+	// CountTxnsWithoutBlock counts the transactions whose block is not in the
+	// blocks table.
+	CountTxnsWithoutBlock = `SELECT COUNT(*) FROM transactions
+		WHERE NOT EXISTS (SELECT 1 FROM blocks WHERE hash = transactions.block_hash);`
+
+	// DeleteTxnsWithoutBlock deletes the transactions whose block is not in
+	// the blocks table.
+	DeleteTxnsWithoutBlock = `DELETE FROM transactions
+		WHERE NOT EXISTS (SELECT 1 FROM blocks WHERE hash = transactions.block_hash);`
+
+	// CountMissingTxnVins counts the vin row IDs in the transactions table's
+	// vin_db_ids arrays that are not in the vins table.
+	CountMissingTxnVins = `SELECT COUNT(*)
+		FROM transactions, UNNEST(transactions.vin_db_ids) AS vin_db_id
+		WHERE NOT EXISTS (SELECT 1 FROM vins WHERE id = vin_db_id);`
+
+	// CountMissingTxnVouts counts the vout row IDs in the transactions table's
+	// vout_db_ids arrays that are not in the vouts table.
+	CountMissingTxnVouts = `SELECT COUNT(*)
+		FROM transactions, UNNEST(transactions.vout_db_ids) AS vout_db_id
+		WHERE NOT EXISTS (SELECT 1 FROM vouts WHERE id = vout_db_id);`
+
+	// CountVinsWithoutTxn counts the vins whose transaction is not in the
+	// transactions table.
+	CountVinsWithoutTxn = `SELECT COUNT(*) FROM vins
+		WHERE NOT EXISTS (SELECT 1 FROM transactions WHERE tx_hash = vins.tx_hash);`
+
+	// DeleteVinsWithoutTxn deletes the vins whose transaction is not in the
+	// transactions table.
+	DeleteVinsWithoutTxn = `DELETE FROM vins
+		WHERE NOT EXISTS (SELECT 1 FROM transactions WHERE tx_hash = vins.tx_hash);`
+
+	// CountVoutsWithoutTxn counts the vouts, including archived vouts, whose
+	// transaction is not in the transactions table.
+	CountVoutsWithoutTxn = `SELECT COUNT(*) FROM vouts
+		WHERE NOT EXISTS (SELECT 1 FROM transactions WHERE tx_hash = vouts.tx_hash);`
+
+	// DeleteVoutsWithoutTxn deletes the vouts, including archived vouts, whose
+	// transaction is not in the transactions table.
+	DeleteVoutsWithoutTxn = `DELETE FROM vouts
+		WHERE NOT EXISTS (SELECT 1 FROM transactions WHERE tx_hash = vouts.tx_hash);`
+
+	// CountVoutsWithMissingSpend counts the vouts with a spending transaction
+	// row ID that is not in the transactions table.
+	CountVoutsWithMissingSpend = `SELECT COUNT(*) FROM vouts
+		WHERE spend_tx_row_id IS NOT NULL
+			AND NOT EXISTS (SELECT 1 FROM transactions WHERE id = vouts.spend_tx_row_id);`
+
+	// ResetVoutsWithMissingSpend clears the spending transaction row ID of the
+	// vouts with a spending transaction that is not in the transactions table.
+	ResetVoutsWithMissingSpend = `UPDATE vouts SET spend_tx_row_id = NULL
+		WHERE spend_tx_row_id IS NOT NULL
+			AND NOT EXISTS (SELECT 1 FROM transactions WHERE id = vouts.spend_tx_row_id);`
+
+	// CountFundingAddressesWithoutVout counts the funding address rows whose
+	// vout is not in the vouts table.
+	CountFundingAddressesWithoutVout = `SELECT COUNT(*) FROM addresses
+		WHERE is_funding
+			AND NOT EXISTS (SELECT 1 FROM vouts WHERE id = addresses.tx_vin_vout_row_id);`
+
+	// DeleteFundingAddressesWithoutVout deletes the funding address rows whose
+	// vout is not in the vouts table, and subtracts the valid mainchain ones
+	// from the address_counts table in the same statement.
+	DeleteFundingAddressesWithoutVout = `WITH orphans AS (
+			SELECT id, address, valid_mainchain FROM addresses
+			WHERE is_funding
+				AND NOT EXISTS (SELECT 1 FROM vouts WHERE id = addresses.tx_vin_vout_row_id)
+		), decremented AS (
+			UPDATE address_counts SET num_funding = num_funding - counts.num
+			FROM (SELECT address, COUNT(*) AS num FROM orphans
+				WHERE valid_mainchain GROUP BY address) AS counts
+			WHERE address_counts.address = counts.address
+		)
+		DELETE FROM addresses USING orphans WHERE addresses.id = orphans.id;`
+
+	// CountSpendingAddressesWithoutVin counts the spending address rows whose
+	// vin is not in the vins table.
+	CountSpendingAddressesWithoutVin = `SELECT COUNT(*) FROM addresses
+		WHERE NOT is_funding
+			AND NOT EXISTS (SELECT 1 FROM vins WHERE id = addresses.tx_vin_vout_row_id);`
+
+	// DeleteSpendingAddressesWithoutVin deletes the spending address rows
+	// whose vin is not in the vins table, and subtracts the valid mainchain
+	// ones from the address_counts table in the same statement.
+	DeleteSpendingAddressesWithoutVin = `WITH orphans AS (
+			SELECT id, address, valid_mainchain FROM addresses
+			WHERE NOT is_funding
+				AND NOT EXISTS (SELECT 1 FROM vins WHERE id = addresses.tx_vin_vout_row_id)
+		), decremented AS (
+			UPDATE address_counts SET num_spending = num_spending - counts.num
+			FROM (SELECT address, COUNT(*) AS num FROM orphans
+				WHERE valid_mainchain GROUP BY address) AS counts
+			WHERE address_counts.address = counts.address
+		)
+		DELETE FROM addresses USING orphans WHERE addresses.id = orphans.id;`
+)
+
+// IntegrityCheck is a referential integrity check with a statement counting
+// the orphaned rows, and optionally a statement pruning or repairing them.
+type IntegrityCheck struct {
+	Name  string
+	Count string
+	Prune string
+}
+
+// IntegrityChecks are the referential integrity checks in the order they are
+// run. Since pruning a row may orphan the rows referring to it, the rows of a
+// table are pruned before the rows of the tables that refer to it. The
+// missing vin and vout IDs of transactions can not be repaired without
+// reindexing the blocks, so they are only reported.
+var IntegrityChecks = []IntegrityCheck{
+	{"transactions without block", CountTxnsWithoutBlock, DeleteTxnsWithoutBlock},
+	{"missing transaction vins", CountMissingTxnVins, ""},
+	{"missing transaction vouts", CountMissingTxnVouts, ""},
+	{"vins without transaction", CountVinsWithoutTxn, DeleteVinsWithoutTxn},
+	{"vouts without transaction", CountVoutsWithoutTxn, DeleteVoutsWithoutTxn},
+	{"vouts with missing spending transaction", CountVoutsWithMissingSpend, ResetVoutsWithMissingSpend},
+	{"funding addresses without vout", CountFundingAddressesWithoutVout, DeleteFundingAddressesWithoutVout},
+	{"spending addresses without vin", CountSpendingAddressesWithoutVin, DeleteSpendingAddressesWithoutVin},
+}
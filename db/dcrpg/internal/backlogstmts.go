@@ -0,0 +1,32 @@
+package internal
+
+// These queries relate to the mempool_backlog table, which records the size of
+// the mempool when each main chain block was mined. The mempool is not
+// archived, so backlogs are only recorded for blocks stored during normal
+// operation after the table was created.
+const (
+	CreateMempoolBacklogTable = `CREATE TABLE IF NOT EXISTS mempool_backlog (
+		block_hash TEXT PRIMARY KEY,
+		height INT4 NOT NULL,
+		time TIMESTAMPTZ NOT NULL,
+		num_txns INT4 NOT NULL,
+		size INT8 NOT NULL
+	);`
+
+	// UpsertMempoolBacklog records the number of transactions $4 and total
+	// size $5 of the mempool at time $3 when the block with hash $1 at height
+	// $2 was mined.
+	UpsertMempoolBacklog = `INSERT INTO mempool_backlog (block_hash, height, time, num_txns, size)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (block_hash) DO UPDATE
+		SET time = $3, num_txns = $4, size = $5;`
+
+	// SelectMempoolBacklogAboveHeight selects the mempool size in bytes when
+	// each main chain block above height $1 was mined, or zero if it was not
+	// recorded.
+	SelectMempoolBacklogAboveHeight = `SELECT blocks.height, COALESCE(mempool_backlog.size, 0)
+		FROM blocks
+		LEFT JOIN mempool_backlog ON mempool_backlog.block_hash = blocks.hash
+		WHERE blocks.is_mainchain AND blocks.height > $1
+		ORDER BY blocks.height;`
+)
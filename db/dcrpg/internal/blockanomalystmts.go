@@ -0,0 +1,35 @@
+package internal
+
+// These queries relate to the block_anomalies table, which records the main
+// chain blocks flagged by the block anomaly rules as each block is stored, and
+// the deep reorgs ending at a block.
+const (
+	CreateBlockAnomaliesTable = `CREATE TABLE IF NOT EXISTS block_anomalies (
+		height INT4 NOT NULL,
+		block_hash TEXT NOT NULL,
+		time TIMESTAMPTZ NOT NULL,
+		kind TEXT NOT NULL,
+		value INT8 NOT NULL,
+		PRIMARY KEY (height, block_hash, kind)
+	);`
+
+	// InsertBlockAnomaly records an anomaly of a block, updating the value of
+	// an anomaly of the same kind already recorded for the block.
+	InsertBlockAnomaly = `INSERT INTO block_anomalies (height, block_hash,
+		time, kind, value)
+	VALUES ($1, $2, $3, $4, $5)
+	ON CONFLICT (height, block_hash, kind) DO UPDATE
+		SET value = $5;`
+
+	// SelectBlockAnomaliesRange selects the anomalies of the mainchain blocks
+	// with heights in the range [$1, $2], of kind $3, or of all kinds if $3 is
+	// empty.
+	SelectBlockAnomaliesRange = `SELECT block_anomalies.height,
+		block_anomalies.block_hash, block_anomalies.time, kind, value
+	FROM block_anomalies
+	JOIN blocks ON blocks.hash = block_anomalies.block_hash
+		AND blocks.is_mainchain
+	WHERE block_anomalies.height BETWEEN $1 AND $2
+		AND ($3 = '' OR kind = $3)
+	ORDER BY block_anomalies.height, kind;`
+)
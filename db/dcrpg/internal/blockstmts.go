@@ -34,7 +34,8 @@ const (
 		stake_version INT4,
 		previous_hash TEXT,
 		chainwork TEXT,
-		winners TEXT[]
+		winners TEXT[],
+		final_state TEXT
 	);`
 
 	// Block inserts. is_valid refers to blocks that have been validated by
@@ -48,12 +49,13 @@ const (
 		numtx, num_rtx, tx, txDbIDs, num_stx, stx, stxDbIDs,
 		time, nonce, vote_bits, voters,
 		fresh_stake, revocations, pool_size, bits, sbits,
-		difficulty, stake_version, previous_hash, chainwork, winners)
+		difficulty, stake_version, previous_hash, chainwork, winners,
+		final_state)
 	VALUES ($1, $2, $3, $4, $5, $6,
 		$7, $8, $9, $10, $11, $12, $13,
 		$14, $15, $16, $17, $18, $19,
 		$20, $21, $22, $23, $24, $25,
-		$26, $27) `
+		$26, $27, $28) `
 
 	// InsertBlockRow inserts a new block row without checking for unique index
 	// conflicts. This should only be used before the unique indexes are created
@@ -99,12 +101,73 @@ const (
 		FROM blocks WHERE time BETWEEN $1 and $2 ORDER BY time DESC LIMIT $3;`
 	SelectBlockByTimeRangeSQLNoLimit = `SELECT hash, height, size, time, numtx
 		FROM blocks WHERE time BETWEEN $1 and $2 ORDER BY time DESC;`
+
+	// SelectMainchainBlocksByTimeRange selects the summaries of up to $3 of the
+	// most recent mainchain blocks with times in the range [$1, $2].
+	SelectMainchainBlocksByTimeRange = `SELECT hash, height, size, time, numtx, pool_size
+		FROM blocks WHERE time BETWEEN $1 and $2 AND is_mainchain
+		ORDER BY time DESC LIMIT $3;`
+	// SelectMainchainBlocksByTimeRangeNoLimit is SelectMainchainBlocksByTimeRange
+	// without a limit.
+	SelectMainchainBlocksByTimeRangeNoLimit = `SELECT hash, height, size, time, numtx, pool_size
+		FROM blocks WHERE time BETWEEN $1 and $2 AND is_mainchain
+		ORDER BY time DESC;`
 	SelectBlockHashByHeight = `SELECT hash FROM blocks WHERE height = $1 AND is_mainchain = true;`
 	SelectBlockHeightByHash = `SELECT height FROM blocks WHERE hash = $1;`
 
+	// SelectMainchainBlocksStatsRange selects the height and hash of the
+	// mainchain blocks in a height range, and whether each has a stats row.
+	SelectMainchainBlocksStatsRange = `SELECT blocks.height, blocks.hash,
+			stats.blocks_id IS NOT NULL
+		FROM blocks LEFT JOIN stats ON stats.blocks_id = blocks.id
+		WHERE blocks.is_mainchain AND blocks.height BETWEEN $1 AND $2
+		ORDER BY blocks.height;`
+
+	// SelectWinnersByHash selects the lottery winners for the block with the
+	// given hash, which may be on a side chain.
+	SelectWinnersByHash = `SELECT winners FROM blocks WHERE hash = $1;`
+	// SelectWinnersByHeight selects the lottery winners for the mainchain
+	// block at the given height.
+	SelectWinnersByHeight = `SELECT winners FROM blocks
+		WHERE height = $1 AND is_mainchain = true;`
+
+	// SelectBlockStakeHeader selects the stake fields of the header of the
+	// block with the given hash, which may be on a side chain.
+	SelectBlockStakeHeader = `SELECT hash, height, is_valid, is_mainchain,
+			vote_bits, COALESCE(final_state, ''), voters, fresh_stake, revocations,
+			pool_size, sbits, stake_version
+		FROM blocks WHERE hash = $1;`
+
+	// SelectBlocksWithoutFinalState selects the row id and hash of up to $2
+	// blocks with row ids after $1 and no final state, which is only the case
+	// for blocks stored before the final_state column was added.
+	SelectBlocksWithoutFinalState = `SELECT id, hash FROM blocks
+		WHERE id > $1 AND final_state IS NULL
+		ORDER BY id LIMIT $2;`
+	// UpdateBlockFinalState sets the final state of the block with row id $1.
+	UpdateBlockFinalState = `UPDATE blocks SET final_state = $2 WHERE id = $1;`
+
 	SelectBlockTimeByHeight = `SELECT time FROM blocks
 		WHERE height = $1 AND is_mainchain = true;`
 
+	SelectBlockHashTimeByHeight = `SELECT hash, time FROM blocks
+		WHERE height = $1 AND is_mainchain = true;`
+
+	SelectBlockTimesByHeights = `SELECT height, time FROM blocks
+		WHERE height = ANY($1) AND is_mainchain = true;`
+
+	// SelectBlockHeightByTime selects the height of the last mainchain block
+	// with a timestamp at or before the given time.
+	SelectBlockHeightByTime = `SELECT height FROM blocks
+		WHERE time <= $1 AND is_mainchain = true
+		ORDER BY height DESC LIMIT 1;`
+
+	// SelectBlockHeightAfterTime selects the height of the first mainchain
+	// block with a timestamp at or after the given time.
+	SelectBlockHeightAfterTime = `SELECT height FROM blocks
+		WHERE time >= $1 AND is_mainchain = true
+		ORDER BY height LIMIT 1;`
+
 	RetrieveBestBlockHeightAny = `SELECT id, hash, height FROM blocks
 		ORDER BY height DESC LIMIT 1;`
 	RetrieveBestBlockHeight = `SELECT id, hash, height FROM blocks
@@ -171,6 +234,18 @@ const (
 		WHERE is_mainchain = FALSE AND block_chain.next_hash=''
 		ORDER BY height DESC;`
 
+	SelectBlockChainWork = `SELECT hash, height, is_mainchain, bits, chainwork
+		FROM blocks
+		WHERE hash = $1;`
+
+	// SelectChainTipsChainWork selects the mainchain tip and all side chain
+	// tips, with the most cumulative work first.
+	SelectChainTipsChainWork = `SELECT hash, height, is_mainchain, bits, chainwork
+		FROM blocks
+		JOIN block_chain ON this_hash=hash
+		WHERE block_chain.next_hash=''
+		ORDER BY chainwork DESC, height DESC;`
+
 	SelectBlockStatus = `SELECT is_valid, is_mainchain, height, previous_hash, hash, block_chain.next_hash
 		FROM blocks
 		JOIN block_chain ON this_hash=hash
@@ -250,6 +325,18 @@ const (
 		WHERE blocks.height BETWEEN $1 AND $2
 		ORDER BY blocks.height DESC;`
 
+	// SelectBlockDataBefore selects up to $2 mainchain blocks below height $1,
+	// newest first, which is a descending scan of the blocks height index that
+	// does not degrade with the depth of the page.
+	SelectBlockDataBefore = `
+		SELECT blocks.hash, blocks.height, blocks.size,
+			blocks.difficulty, blocks.sbits, blocks.time, stats.pool_size,
+			stats.pool_val, blocks.winners, blocks.is_valid
+		FROM blocks INNER JOIN stats ON blocks.id = stats.blocks_id
+		WHERE blocks.is_mainchain AND blocks.height < $1
+		ORDER BY blocks.height DESC
+		LIMIT $2;`
+
 	SelectBlockDataRangeWithSkip = `
 		SELECT blocks.hash, blocks.height, blocks.size,
 			blocks.difficulty, blocks.sbits, blocks.time, stats.pool_size,
@@ -309,6 +396,37 @@ const (
 		WHERE time >= $1
 		ORDER BY time
 		LIMIT 1;`
+
+	// SelectBlockVersionCounts counts the versions of the mainchain blocks in
+	// the height range [$1, $2].
+	SelectBlockVersionCounts = `SELECT version, COUNT(*)
+		FROM blocks
+		WHERE is_mainchain AND height BETWEEN $1 AND $2
+		GROUP BY version
+		ORDER BY version;`
+
+	// SelectBlockVersionThresholds selects, for each mainchain block version,
+	// the first heights and times at which at least $2 and $3 of the trailing
+	// $1 mainchain blocks had that version or higher, or NULLs if never.
+	SelectBlockVersionThresholds = `SELECT t.version,
+			t.enforce_height, EXTRACT(EPOCH FROM e.time)::INT8,
+			t.reject_height, EXTRACT(EPOCH FROM r.time)::INT8
+		FROM (
+			SELECT v.version,
+				MIN(w.height) FILTER (WHERE w.num >= $2) AS enforce_height,
+				MIN(w.height) FILTER (WHERE w.num >= $3) AS reject_height
+			FROM (SELECT DISTINCT version FROM blocks WHERE is_mainchain) v
+			CROSS JOIN LATERAL (
+				SELECT height, COUNT(*) FILTER (WHERE version >= v.version)
+					OVER (ORDER BY height ROWS BETWEEN $1::INT8 - 1 PRECEDING AND CURRENT ROW) AS num
+				FROM blocks
+				WHERE is_mainchain
+			) w
+			GROUP BY v.version
+		) t
+		LEFT JOIN blocks e ON e.height = t.enforce_height AND e.is_mainchain
+		LEFT JOIN blocks r ON r.height = t.reject_height AND r.is_mainchain
+		ORDER BY t.version;`
 )
 
 func BlockInsertStatement(checked bool) string {
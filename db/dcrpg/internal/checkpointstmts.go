@@ -0,0 +1,54 @@
+// Copyright (c) 2020, The Decred developers
+// See LICENSE for details.
+
+package internal
+
+// These queries relate primarily to the "sync_checkpoints" table.
+const (
+	CreateSyncCheckpointsTable = `CREATE TABLE IF NOT EXISTS sync_checkpoints (
+		id SERIAL PRIMARY KEY,
+		height INT8 NOT NULL,
+		hash TEXT NOT NULL,
+		phase TEXT NOT NULL,
+		num_blocks INT8 NOT NULL,
+		num_transactions INT8 NOT NULL,
+		num_vins INT8 NOT NULL,
+		num_vouts INT8 NOT NULL,
+		num_addresses INT8 NOT NULL,
+		time TIMESTAMPTZ NOT NULL
+	);`
+
+	// selectSyncRowCounts selects the largest row id of the blocks,
+	// transactions, vins, vouts and addresses tables. Rows are only inserted
+	// during a sync, so these are the numbers of rows stored. The primary key
+	// index makes this cheap even when the other indexes are dropped.
+	selectSyncRowCounts = `SELECT
+		(SELECT COALESCE(MAX(id), 0) FROM blocks),
+		(SELECT COALESCE(MAX(id), 0) FROM transactions),
+		(SELECT COALESCE(MAX(id), 0) FROM vins),
+		(SELECT COALESCE(MAX(id), 0) FROM vouts),
+		(SELECT COALESCE(MAX(id), 0) FROM addresses)`
+
+	SelectSyncRowCounts = selectSyncRowCounts + `;`
+
+	// InsertSyncCheckpoint records a checkpoint at block height $1 with hash
+	// $2, in sync phase $3, with the current row counts.
+	InsertSyncCheckpoint = `INSERT INTO sync_checkpoints (height, hash, phase,
+			num_blocks, num_transactions, num_vins, num_vouts, num_addresses, time)
+		SELECT $1, $2, $3, counts.*, NOW()
+		FROM (` + selectSyncRowCounts + `) AS counts;`
+
+	// PruneSyncCheckpoints removes all but the $1 most recent checkpoints.
+	PruneSyncCheckpoints = `DELETE FROM sync_checkpoints
+		WHERE id NOT IN (SELECT id FROM sync_checkpoints ORDER BY id DESC LIMIT $1);`
+
+	// DeleteSyncCheckpointsAbove removes the checkpoints above height $1, such
+	// as after blocks are purged.
+	DeleteSyncCheckpointsAbove = `DELETE FROM sync_checkpoints WHERE height > $1;`
+
+	SelectLastSyncCheckpoint = `SELECT height, hash, phase, num_blocks,
+			num_transactions, num_vins, num_vouts, num_addresses, time
+		FROM sync_checkpoints
+		ORDER BY id DESC
+		LIMIT 1;`
+)
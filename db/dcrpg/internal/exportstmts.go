@@ -0,0 +1,48 @@
+package internal
+
+// dayHeights selects the range of heights of the main chain blocks with times
+// in [$1, $2), so that the transactions are found by the indexed block height
+// rather than their block times.
+const dayHeights = `WITH day AS (
+		SELECT MIN(height) AS low, MAX(height) AS high
+		FROM blocks
+		WHERE is_mainchain AND time >= $1 AND time < $2
+	) `
+
+// DailyExportTables maps the tables of the daily exports to the queries of
+// their CSV columns for the main chain blocks with times in [$1, $2). Times are
+// UNIX seconds, scripts are hex, and addresses are space-separated.
+var DailyExportTables = map[string]string{
+	"blocks": `SELECT height, hash, EXTRACT(EPOCH FROM time)::INT8 AS time,
+			size, version, is_valid, numtx, num_rtx, num_stx, nonce, vote_bits,
+			voters, fresh_stake, revocations, pool_size, bits, sbits, difficulty,
+			stake_version, previous_hash, chainwork
+		FROM blocks
+		WHERE is_mainchain AND time >= $1 AND time < $2
+		ORDER BY height;`,
+
+	"transactions": dayHeights + `SELECT block_height, block_hash,
+			EXTRACT(EPOCH FROM block_time)::INT8 AS block_time, tx_hash, tree,
+			block_index, tx_type, version, lock_time, expiry, size, spent, sent,
+			fees, fee_rate, mix_count, mix_denom, num_vin, num_vout, is_valid
+		FROM transactions, day
+		WHERE block_height BETWEEN day.low AND day.high AND is_mainchain
+			AND block_time >= $1 AND block_time < $2
+		ORDER BY block_height, tree, block_index;`,
+
+	// The outputs of transactions in disapproved blocks are omitted, so that
+	// each output is listed once.
+	"vouts": dayHeights + `SELECT transactions.block_height, vouts.tx_hash,
+			vouts.tx_tree, vouts.tx_index, vouts.value, vouts.version,
+			vouts.script_type, vouts.script_req_sigs,
+			array_to_string(vouts.script_addresses, ' ') AS script_addresses,
+			encode(vouts.pkscript, 'hex') AS pkscript, vouts.mixed
+		FROM transactions
+		JOIN day ON transactions.block_height BETWEEN day.low AND day.high
+		JOIN vouts ON vouts.tx_hash = transactions.tx_hash
+			AND vouts.tx_tree = transactions.tree
+		WHERE transactions.is_mainchain AND transactions.is_valid
+			AND transactions.block_time >= $1 AND transactions.block_time < $2
+		ORDER BY transactions.block_height, transactions.tree,
+			transactions.block_index, vouts.tx_index;`,
+}
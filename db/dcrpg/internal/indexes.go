@@ -13,6 +13,7 @@ const (
 	IndexOfTransactionsTableOnHashes      = "uix_tx_hashes"
 	IndexOfTransactionsTableOnBlockInd    = "uix_tx_block_in"
 	IndexOfTransactionsTableOnBlockHeight = "ix_tx_block_height"
+	IndexOfTransactionsTableOnCoinbase    = "ix_tx_coinbase"
 
 	// vins table
 
@@ -23,6 +24,7 @@ const (
 
 	IndexOfVoutsTableOnTxHashInd = "uix_vout_txhash_ind"
 	IndexOfVoutsTableOnSpendTxID = "uix_vout_spendtxid_ind"
+	IndexOfVoutsTableOnNullData  = "ix_vout_nulldata"
 
 	// addresses table
 
@@ -34,9 +36,10 @@ const (
 
 	// tickets table
 
-	IndexOfTicketsTableOnHashes     = "uix_ticket_hashes_index"
-	IndexOfTicketsTableOnTxRowID    = "uix_ticket_ticket_db_id"
-	IndexOfTicketsTableOnPoolStatus = "uix_tickets_pool_status"
+	IndexOfTicketsTableOnHashes          = "uix_ticket_hashes_index"
+	IndexOfTicketsTableOnTxRowID         = "uix_ticket_ticket_db_id"
+	IndexOfTicketsTableOnPoolStatus      = "uix_tickets_pool_status"
+	IndexOfTicketsTableOnRewardAddresses = "ix_tickets_reward_addresses"
 
 	// votes table
 
@@ -69,7 +72,8 @@ const (
 
 	// stats table
 
-	IndexOfHeightOnStatsTable = "uix_stats_height"
+	IndexOfHeightOnStatsTable   = "uix_stats_height"
+	IndexOfBlocksIDOnStatsTable = "ix_stats_blocks_id"
 )
 
 // AddressesIndexNames are the names of the indexes on the addresses table.
@@ -84,10 +88,12 @@ var IndexDescriptions = map[string]string{
 	IndexOfTransactionsTableOnHashes:       "transactions on block hash and transaction hash",
 	IndexOfTransactionsTableOnBlockInd:     "transactions on block hash, block index, and tx tree",
 	IndexOfTransactionsTableOnBlockHeight:  "transactions on block height",
+	IndexOfTransactionsTableOnCoinbase:     "transactions on block height for coinbase transactions",
 	IndexOfVinsTableOnVin:                  "vins on transaction hash and index",
 	IndexOfVinsTableOnPrevOut:              "vins on previous outpoint",
 	IndexOfVoutsTableOnTxHashInd:           "vouts on transaction hash and index",
 	IndexOfVoutsTableOnSpendTxID:           "vouts on spend_tx_row_id",
+	IndexOfVoutsTableOnNullData:            "vouts on null data payload",
 	IndexOfAddressTableOnAddress:           "addresses table on address",
 	IndexOfAddressTableOnVoutID:            "addresses table on vout row id, address, and is_funding",
 	IndexOfAddressTableOnBlockTime:         "addresses table on block time",
@@ -96,6 +102,7 @@ var IndexDescriptions = map[string]string{
 	IndexOfTicketsTableOnHashes:            "tickets table on block hash and transaction hash",
 	IndexOfTicketsTableOnTxRowID:           "tickets table on transactions table row ID",
 	IndexOfTicketsTableOnPoolStatus:        "tickets table on pool status",
+	IndexOfTicketsTableOnRewardAddresses:   "tickets table on reward addresses",
 	IndexOfVotesTableOnHashes:              "votes table on block hash and transaction hash",
 	IndexOfVotesTableOnBlockHash:           "votes table on block hash",
 	IndexOfVotesTableOnCandBlock:           "votes table on candidate block",
@@ -108,4 +115,5 @@ var IndexDescriptions = map[string]string{
 	IndexOfProposalsTableOnToken:           "proposals on token and time",
 	IndexOfProposalVotesTableOnProposalsID: "proposal_votes on proposals row ID",
 	IndexOfHeightOnStatsTable:              "stats table on height",
+	IndexOfBlocksIDOnStatsTable:            "stats table on blocks row ID",
 }
@@ -0,0 +1,32 @@
+package internal
+
+// These queries relate to the daily_prices table, which records the daily
+// open, high, low and close of the DCR exchange rate in a fiat currency as
+// reported by the exchange bot. Days are UTC.
+const (
+	CreateDailyPricesTable = `CREATE TABLE IF NOT EXISTS daily_prices (
+		day DATE NOT NULL,
+		currency TEXT NOT NULL,
+		open FLOAT8 NOT NULL,
+		high FLOAT8 NOT NULL,
+		low FLOAT8 NOT NULL,
+		close FLOAT8 NOT NULL,
+		PRIMARY KEY (day, currency)
+	);`
+
+	// UpsertDailyPrice records the price $3 for currency $2 on day $1. The
+	// first price of a day is its open, and the last is its close.
+	UpsertDailyPrice = `INSERT INTO daily_prices (day, currency, open, high, low, close)
+	VALUES ($1, $2, $3, $3, $3, $3)
+	ON CONFLICT (day, currency) DO UPDATE
+		SET high = GREATEST(daily_prices.high, $3),
+			low = LEAST(daily_prices.low, $3),
+			close = $3;`
+
+	// SelectDailyPrices selects the daily prices for currency $1 on and after
+	// day $2, oldest first.
+	SelectDailyPrices = `SELECT day, open, high, low, close
+		FROM daily_prices
+		WHERE currency = $1 AND day >= $2
+		ORDER BY day;`
+)
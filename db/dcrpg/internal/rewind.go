@@ -47,7 +47,10 @@ const (
 					AND transactions.id = ANY(array_cat(blocks.txdbids, blocks.stxdbids))
 			)`
 
-	DeleteAddressesSubQry = `DELETE FROM addresses WHERE id IN (` + addressesForBlockHash + `);`
+	// DeleteAddressesSubQry returns the deleted rows' address, is_funding and
+	// valid_mainchain for the address_counts table.
+	DeleteAddressesSubQry = `DELETE FROM addresses WHERE id IN (` + addressesForBlockHash + `)
+		RETURNING address, is_funding, valid_mainchain;`
 
 	DeleteStakeAddressesFunding = `DELETE FROM addresses
 		USING transactions, blocks
@@ -0,0 +1,49 @@
+package internal
+
+// These queries relate to the script_anomalies table, which records the
+// transaction outputs with nonstandard scripts or script versions other than 0
+// as each block is stored. Values are in atoms.
+const (
+	CreateScriptAnomaliesTable = `CREATE TABLE IF NOT EXISTS script_anomalies (
+		height INT4 NOT NULL,
+		block_hash TEXT NOT NULL,
+		tx_hash TEXT NOT NULL,
+		tx_index INT4 NOT NULL,
+		tx_tree INT2 NOT NULL,
+		value INT8 NOT NULL,
+		version INT2 NOT NULL,
+		script_type TEXT NOT NULL,
+		PRIMARY KEY (height, block_hash, tx_hash, tx_index)
+	);`
+
+	// InsertScriptAnomaly records an output of a block, ignoring outputs of
+	// the block that are already recorded.
+	InsertScriptAnomaly = `INSERT INTO script_anomalies (height, block_hash,
+		tx_hash, tx_index, tx_tree, value, version, script_type)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	ON CONFLICT (height, block_hash, tx_hash, tx_index) DO NOTHING;`
+
+	// InsertScriptAnomaliesFromVouts records the outputs already stored in the
+	// vouts table, for each block containing their transactions.
+	InsertScriptAnomaliesFromVouts = `INSERT INTO script_anomalies (height,
+		block_hash, tx_hash, tx_index, tx_tree, value, version, script_type)
+	SELECT transactions.block_height, transactions.block_hash, vouts.tx_hash,
+		vouts.tx_index, vouts.tx_tree, vouts.value, vouts.version,
+		vouts.script_type
+	FROM vouts
+	JOIN transactions ON transactions.tx_hash = vouts.tx_hash
+		AND transactions.tree = vouts.tx_tree
+	WHERE vouts.script_type = 'nonstandard' OR vouts.version <> 0
+	ON CONFLICT (height, block_hash, tx_hash, tx_index) DO NOTHING;`
+
+	// SelectScriptAnomaliesRange selects the outputs in mainchain blocks with
+	// heights in the range [$1, $2].
+	SelectScriptAnomaliesRange = `SELECT script_anomalies.height,
+		script_anomalies.block_hash, tx_hash, tx_index, tx_tree, value,
+		version, script_type
+	FROM script_anomalies
+	JOIN blocks ON blocks.hash = script_anomalies.block_hash
+		AND blocks.is_mainchain
+	WHERE script_anomalies.height BETWEEN $1 AND $2
+	ORDER BY script_anomalies.height, tx_tree, tx_hash, tx_index;`
+)
@@ -0,0 +1,96 @@
+package internal
+
+// Chain snapshot export and import.
+const (
+	// SelectSnapshotRows selects the rows of a table as JSON objects. The
+	// table name and the row filter, which uses the snapshot height as $1, are
+	// formatted in.
+	SelectSnapshotRows = `SELECT row_to_json(t)::TEXT FROM %s t WHERE %s;`
+
+	// SelectTableColumns selects the column names of a table in order.
+	SelectTableColumns = `SELECT column_name FROM information_schema.columns
+		WHERE table_name = $1
+		ORDER BY ordinal_position;`
+
+	// InsertSnapshotRows inserts a JSON array of rows into a table. The table
+	// name is formatted in twice.
+	InsertSnapshotRows = `INSERT INTO %s
+		SELECT * FROM json_populate_recordset(NULL::%s, $1::JSON);`
+
+	// ResetSerialSequence sets the next value of a table's id sequence to
+	// follow the largest id in the table. The table name is formatted in.
+	ResetSerialSequence = `SELECT setval(pg_get_serial_sequence('%[1]s', 'id'),
+		COALESCE(MAX(id), 0) + 1, false) FROM %[1]s;`
+
+	// The following statements remove the references to the data of blocks
+	// after the snapshot height from the imported rows.
+
+	// ResetSnapshotNextHashes clears the next block hash of blocks whose next
+	// block was not exported.
+	ResetSnapshotNextHashes = `UPDATE block_chain SET next_hash = ''
+		WHERE next_hash <> ''
+			AND NOT EXISTS (SELECT 1 FROM blocks WHERE hash = block_chain.next_hash);`
+
+	// ResetSnapshotVoutSpends clears the spending transaction of outputs spent
+	// by transactions that were not exported.
+	ResetSnapshotVoutSpends = `UPDATE vouts SET spend_tx_row_id = NULL
+		WHERE spend_tx_row_id IS NOT NULL
+			AND NOT EXISTS (SELECT 1 FROM transactions WHERE id = vouts.spend_tx_row_id);`
+
+	// ResetSnapshotAddressSpends clears the matching spending transaction of
+	// funding address rows spent by transactions that were not exported.
+	ResetSnapshotAddressSpends = `UPDATE addresses SET matching_tx_hash = ''
+		WHERE is_funding AND matching_tx_hash <> ''
+			AND NOT EXISTS (SELECT 1 FROM transactions WHERE tx_hash = addresses.matching_tx_hash);`
+
+	// ResetSnapshotTicketSpends marks tickets spent after the snapshot height
+	// ($1) as unspent, and voted tickets as live again. Revoked tickets keep
+	// their missed or expired status.
+	ResetSnapshotTicketSpends = `UPDATE tickets
+		SET spend_type = 0, spend_height = NULL, spend_tx_db_id = NULL,
+			pool_status = CASE WHEN pool_status = 1 THEN 0 ELSE pool_status END
+		WHERE spend_height > $1;`
+
+	// ResetSnapshotTicketMisses marks missed tickets without an exported miss
+	// as live again.
+	ResetSnapshotTicketMisses = `UPDATE tickets SET pool_status = 0
+		WHERE pool_status = 3
+			AND NOT EXISTS (SELECT 1 FROM misses WHERE ticket_hash = tickets.tx_hash);`
+
+	// ResetSnapshotTicketExpiries marks tickets that expire after the snapshot
+	// height ($1), given the ticket maturity plus expiry ($2), as live again.
+	ResetSnapshotTicketExpiries = `UPDATE tickets SET pool_status = 0
+		WHERE pool_status = 2 AND block_height + $2 > $1;`
+)
+
+// SnapshotTableFilters are the tables included in a chain snapshot, in the
+// order they are imported, with the filter selecting the rows for the blocks
+// up to and including the snapshot height ($1). The meta, testing,
+// sync_checkpoints and address_counts tables are not included. The address
+// counts are recounted after an import.
+var SnapshotTableFilters = [][2]string{
+	{"blocks", "height <= $1"},
+	{"transactions", "block_height <= $1"},
+	{"vins", "id IN (SELECT unnest(vin_db_ids) FROM transactions WHERE block_height <= $1)"},
+	{"vouts", "id IN (SELECT unnest(vout_db_ids) FROM transactions WHERE block_height <= $1)"},
+	{"block_chain", "block_db_id IN (SELECT id FROM blocks WHERE height <= $1)"},
+	{"addresses", `(is_funding AND tx_vin_vout_row_id IN
+		(SELECT unnest(vout_db_ids) FROM transactions WHERE block_height <= $1))
+		OR (NOT is_funding AND tx_vin_vout_row_id IN
+		(SELECT unnest(vin_db_ids) FROM transactions WHERE block_height <= $1))`},
+	{"tickets", "block_height <= $1"},
+	{"votes", "height <= $1"},
+	{"misses", "height <= $1"},
+	{"agendas", "$1 >= 0"},
+	{"agenda_votes", "votes_row_id IN (SELECT id FROM votes WHERE height <= $1)"},
+	{"proposals", "$1 >= 0"},
+	{"proposal_votes", "$1 >= 0"},
+	{"stats", "height <= $1"},
+	{"stake_diff_estimates", "height <= $1"},
+	{"daily_prices", "$1 >= 0"},
+	{"script_anomalies", "height <= $1"},
+	{"block_anomalies", "height <= $1"},
+	{"winning_tickets", "height <= $1"},
+	{"mempool_backlog", "height <= $1"},
+	{"utxo_distribution", "height <= $1"},
+}
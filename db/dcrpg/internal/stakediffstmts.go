@@ -0,0 +1,82 @@
+package internal
+
+// These queries relate to the stake_diff_estimates table, which records dcrd's
+// estimates of the next stake difficulty window's ticket price as each
+// mainchain block is stored. Amounts are in atoms.
+const (
+	CreateStakeDiffEstimatesTable = `CREATE TABLE IF NOT EXISTS stake_diff_estimates (
+		height INT4 PRIMARY KEY,
+		block_hash TEXT NOT NULL,
+		window_num INT4 NOT NULL,
+		expected INT8,
+		min INT8,
+		max INT8
+	);`
+
+	// UpsertStakeDiffEstimate inserts the estimate made at a block height,
+	// replacing any estimate from a block at the same height that has been
+	// reorganized out of the main chain.
+	UpsertStakeDiffEstimate = `INSERT INTO stake_diff_estimates (
+		height, block_hash, window_num, expected, min, max)
+	VALUES ($1, $2, $3, $4, $5, $6)
+	ON CONFLICT (height) DO UPDATE
+		SET block_hash = $2, window_num = $3, expected = $4, min = $5, max = $6;`
+
+	// SelectStakeDiffEstimateAccuracy compares the estimates made during each
+	// window with the actual stake difficulty of the following window, which
+	// is the sbits of that window's first block. Windows for which the next
+	// window has not started are excluded. $1 is the window size, and $2 and
+	// $3 are the LIMIT and OFFSET, newest window first.
+	SelectStakeDiffEstimateAccuracy = `WITH est AS (
+			SELECT stake_diff_estimates.*
+			FROM stake_diff_estimates
+			JOIN blocks ON blocks.hash = stake_diff_estimates.block_hash
+				AND blocks.is_mainchain
+		), last AS (
+			SELECT DISTINCT ON (window_num) window_num, height, expected, min, max
+			FROM est
+			ORDER BY window_num, height DESC
+		), stats AS (
+			SELECT window_num, COUNT(*) AS num, AVG(expected) AS mean
+			FROM est
+			GROUP BY window_num
+		)
+		SELECT last.window_num, stats.num, stats.mean, last.height,
+			last.expected, last.min, last.max, blocks.sbits
+		FROM last
+		JOIN stats USING (window_num)
+		JOIN blocks ON blocks.height = (last.window_num + 1) * $1
+			AND blocks.is_mainchain
+		ORDER BY last.window_num DESC
+		LIMIT $2 OFFSET $3;`
+
+	// SelectStakeDiffWindowsOHLC aggregates the ticket price of the main chain
+	// blocks in each stake difficulty window as open, high, low and close
+	// prices, with the window's block range, times, ticket purchases, and the
+	// ticket pool size and value at its last block. The price is constant
+	// within a window, so the four prices only differ if the window's blocks
+	// are inconsistent. $1 is the window size, and $2 and $3 are the first and
+	// last block heights, newest window first.
+	SelectStakeDiffWindowsOHLC = `SELECT blocks.height/$1 AS window_num,
+			MIN(blocks.height), MAX(blocks.height),
+			MIN(blocks.time), MAX(blocks.time),
+			(ARRAY_AGG(blocks.sbits ORDER BY blocks.height))[1],
+			MAX(blocks.sbits), MIN(blocks.sbits),
+			(ARRAY_AGG(blocks.sbits ORDER BY blocks.height DESC))[1],
+			SUM(blocks.fresh_stake),
+			(ARRAY_AGG(stats.pool_size ORDER BY blocks.height DESC))[1],
+			(ARRAY_AGG(stats.pool_val ORDER BY blocks.height DESC))[1]
+		FROM blocks
+		JOIN stats ON stats.blocks_id = blocks.id
+		WHERE blocks.height BETWEEN $2 AND $3
+			AND blocks.is_mainchain
+		GROUP BY window_num
+		ORDER BY window_num DESC;`
+
+	// SelectTicketDemandHistory selects the ticket purchases, ticket price
+	// and live ticket pool size of every main chain block.
+	SelectTicketDemandHistory = `SELECT height, fresh_stake, pool_size, sbits
+		FROM blocks
+		WHERE is_mainchain
+		ORDER BY height;`
+)
@@ -21,7 +21,11 @@ const (
 		pool_status INT2,
 		is_mainchain BOOLEAN,
 		spend_height INT4,
-		spend_tx_db_id INT8
+		spend_tx_db_id INT8,
+		reward_addresses TEXT[],
+		commitment_amounts INT8[],
+		vote_fee_limits INT8[],
+		revoke_fee_limits INT8[]
 	);`
 
 	// insertTicketRow is the basis for several ticket insert/upsert statements.
@@ -29,12 +33,14 @@ const (
 		tx_hash, block_hash, block_height, purchase_tx_db_id,
 		stakesubmission_address, is_multisig, is_split,
 		num_inputs, price, fee, spend_type, pool_status,
-		is_mainchain)
+		is_mainchain, reward_addresses, commitment_amounts,
+		vote_fee_limits, revoke_fee_limits)
 	VALUES (
 		$1, $2, $3,	$4,
 		$5, $6, $7,
 		$8, $9, $10, $11, $12,
-		$13) `
+		$13, $14, $15,
+		$16, $17) `
 
 	// InsertTicketRow inserts a new ticket row without checking for unique
 	// index conflicts. This should only be used before the unique indexes are
@@ -96,6 +102,12 @@ const (
 		` ON tickets(pool_status);`
 	DeindexTicketsTableOnPoolStatus = `DROP INDEX IF EXISTS ` + IndexOfTicketsTableOnPoolStatus + ` CASCADE;`
 
+	// IndexTicketsTableOnRewardAddresses creates a GIN index on the array of
+	// commitment addresses for lookups of tickets by reward address.
+	IndexTicketsTableOnRewardAddresses = `CREATE INDEX IF NOT EXISTS ` + IndexOfTicketsTableOnRewardAddresses +
+		` ON tickets USING GIN (reward_addresses);`
+	DeindexTicketsTableOnRewardAddresses = `DROP INDEX IF EXISTS ` + IndexOfTicketsTableOnRewardAddresses + ` CASCADE;`
+
 	SelectTicketsInBlock        = `SELECT * FROM tickets WHERE block_hash = $1;`
 	SelectTicketsTxDbIDsInBlock = `SELECT purchase_tx_db_id FROM tickets WHERE block_hash = $1;`
 	SelectTicketsForAddress     = `SELECT * FROM tickets WHERE stakesubmission_address = $1;`
@@ -104,7 +116,165 @@ const (
 	SelectTicketIDHeightByHash = `SELECT id, block_height FROM tickets` + forTxHashMainchainFirst
 	SelectTicketIDByHash       = `SELECT id FROM tickets` + forTxHashMainchainFirst
 	SelectTicketStatusByHash   = `SELECT id, spend_type, pool_status FROM tickets` + forTxHashMainchainFirst
-	SelectTicketInfoByHash     = `SELECT block_hash, block_height, spend_type, pool_status, spend_tx_db_id FROM tickets` + forTxHashMainchainFirst
+	SelectTicketInfoByHash     = `SELECT block_hash, block_height, spend_type, pool_status, spend_tx_db_id, price FROM tickets` + forTxHashMainchainFirst
+
+	// SelectTicketCommitmentOutputsByIDRange selects the hash and commitment
+	// output scripts, in output order, of the tickets with row ids in the range
+	// [$1, $2).
+	SelectTicketCommitmentOutputsByIDRange = `SELECT DISTINCT ON (vouts.tx_hash, vouts.tx_index)
+			vouts.tx_hash, vouts.pkscript
+		FROM tickets
+		JOIN vouts ON vouts.tx_hash = tickets.tx_hash
+		WHERE tickets.id >= $1 AND tickets.id < $2
+			AND vouts.tx_tree = 1 AND vouts.tx_index % 2 = 1
+		ORDER BY vouts.tx_hash, vouts.tx_index;`
+
+	// SetTicketCommitments sets the commitment columns of the tickets from
+	// parallel arrays with one element per commitment: the ticket hashes ($1),
+	// reward addresses ($2), amounts ($3), and vote ($4) and revoke ($5) fee
+	// limits. A ticket's commitments must be in output order.
+	SetTicketCommitments = `UPDATE tickets
+		SET reward_addresses = c.addrs, commitment_amounts = c.amounts,
+			vote_fee_limits = c.vote_limits, revoke_fee_limits = c.revoke_limits
+		FROM (SELECT tx_hash, array_agg(addr ORDER BY n) AS addrs,
+				array_agg(amount ORDER BY n) AS amounts,
+				array_agg(vote_limit ORDER BY n) AS vote_limits,
+				array_agg(revoke_limit ORDER BY n) AS revoke_limits
+			FROM unnest($1::TEXT[], $2::TEXT[], $3::INT8[], $4::INT8[], $5::INT8[])
+				WITH ORDINALITY AS t(tx_hash, addr, amount, vote_limit, revoke_limit, n)
+			GROUP BY tx_hash) AS c
+		WHERE tickets.tx_hash = c.tx_hash;`
+
+	// SelectTicketCommitmentsByHash selects a ticket's commitment outputs.
+	SelectTicketCommitmentsByHash = `SELECT reward_addresses, commitment_amounts, vote_fee_limits, revoke_fee_limits
+		FROM tickets` + forTxHashMainchainFirst
+
+	// ticketsByRewardAddress selects mainchain tickets with a commitment to the
+	// given address, the hash, height and total output value of the vote or
+	// revocation spending the ticket, if any, and the index of the ticket
+	// purchase in its block. The commitment amount is the sum of the ticket's
+	// commitments to the address.
+	ticketsByRewardAddress = `SELECT tickets.tx_hash, tickets.block_hash, tickets.block_height,
+			tickets.price,
+			(SELECT SUM(amt) FROM UNNEST(reward_addresses, commitment_amounts) AS c(addr, amt)
+				WHERE addr = $1)::INT8,
+			tickets.pool_status, tickets.spend_type,
+			spend.tx_hash, spend.block_height, spend.sent,
+			transactions.block_index
+		FROM tickets
+		JOIN transactions ON transactions.id = tickets.purchase_tx_db_id
+		LEFT JOIN transactions AS spend ON spend.id = tickets.spend_tx_db_id
+			AND tickets.spend_type <> 0
+		WHERE tickets.reward_addresses @> ARRAY[$1]::TEXT[]
+			AND tickets.is_mainchain`
+
+	// SelectTicketsByRewardAddress selects a page of the mainchain tickets with
+	// a commitment to the given address, newest first.
+	SelectTicketsByRewardAddress = ticketsByRewardAddress + `
+		ORDER BY tickets.block_height DESC, transactions.block_index
+		LIMIT $2 OFFSET $3;`
+
+	// SelectTicketsByRewardAddressAfter is like SelectTicketsByRewardAddress,
+	// but selects the tickets after the ticket at height $3 and block index $4
+	// instead of skipping tickets.
+	SelectTicketsByRewardAddressAfter = ticketsByRewardAddress + `
+			AND (tickets.block_height < $3 OR (tickets.block_height = $3
+				AND transactions.block_index > $4))
+		ORDER BY tickets.block_height DESC, transactions.block_index
+		LIMIT $2;`
+
+	// SelectTicketVoteHeightsByHash selects a ticket's purchase height, spend
+	// type, and spend height.
+	SelectTicketVoteHeightsByHash = `SELECT block_height, spend_type, spend_height FROM tickets` +
+		forTxHashMainchainFirst
+
+	// SelectVotedTicketsByRewardAddress selects the mainchain tickets with a
+	// commitment to the given address that have voted, and their purchase and
+	// vote heights, newest vote first.
+	SelectVotedTicketsByRewardAddress = `SELECT tx_hash, block_height, spend_height
+		FROM tickets
+		WHERE reward_addresses @> ARRAY[$1]::TEXT[]
+			AND is_mainchain
+			AND spend_type = 2
+		ORDER BY spend_height DESC;`
+
+	// SelectTicketPoolStatusesByHashes selects the purchase height and pool
+	// status of the mainchain tickets with the given hashes.
+	SelectTicketPoolStatusesByHashes = `SELECT tx_hash, block_height, pool_status
+		FROM tickets
+		WHERE tx_hash = ANY($1)
+			AND is_mainchain;`
+
+	// SelectTicketStakeByRewardAddress counts the unspent mainchain tickets
+	// with a commitment to the given address, and sums their commitments to
+	// it, by status. Tickets purchased after height $2 are immature, and tickets
+	// voted after height $3 have immature vote outputs.
+	SelectTicketStakeByRewardAddress = `WITH t AS (
+			SELECT block_height, pool_status, spend_type, spend_height,
+				(SELECT SUM(amt) FROM UNNEST(reward_addresses, commitment_amounts) AS c(addr, amt)
+					WHERE addr = $1) AS amt
+			FROM tickets
+			WHERE reward_addresses @> ARRAY[$1]::TEXT[]
+				AND is_mainchain
+				AND (spend_type = 0 OR spend_height > $3)
+		)
+		SELECT
+			COUNT(*) FILTER (WHERE spend_type = 0 AND pool_status = 0 AND block_height > $2),
+			COALESCE(SUM(amt) FILTER (WHERE spend_type = 0 AND pool_status = 0 AND block_height > $2), 0)::INT8,
+			COUNT(*) FILTER (WHERE spend_type = 0 AND pool_status = 0 AND block_height <= $2),
+			COALESCE(SUM(amt) FILTER (WHERE spend_type = 0 AND pool_status = 0 AND block_height <= $2), 0)::INT8,
+			COUNT(*) FILTER (WHERE spend_type = 0 AND pool_status > 0),
+			COALESCE(SUM(amt) FILTER (WHERE spend_type = 0 AND pool_status > 0), 0)::INT8,
+			COUNT(*) FILTER (WHERE spend_type = 2),
+			COALESCE(SUM(amt) FILTER (WHERE spend_type = 2), 0)::INT8
+		FROM t;`
+
+	// revocableTickets selects the unspent mainchain tickets that were missed
+	// or expired, and the sum of their commitments. If $1 is not empty, only
+	// the tickets with a commitment to address $1 are selected, and only those
+	// commitments are summed.
+	revocableTickets = `SELECT tickets.tx_hash, tickets.block_height, tickets.pool_status,
+			tickets.price,
+			COALESCE((SELECT SUM(amt) FROM UNNEST(reward_addresses, commitment_amounts) AS c(addr, amt)
+				WHERE $1 = '' OR addr = $1), 0)::INT8 AS amt,
+			transactions.block_index
+		FROM tickets
+		JOIN transactions ON transactions.id = tickets.purchase_tx_db_id
+		WHERE tickets.is_mainchain
+			AND tickets.spend_type = 0
+			AND tickets.pool_status IN (2, 3)
+			AND ($1 = '' OR tickets.reward_addresses @> ARRAY[$1]::TEXT[])`
+
+	// SelectRevocableTickets selects a page of the revocable tickets, oldest
+	// first.
+	SelectRevocableTickets = revocableTickets + `
+		ORDER BY tickets.block_height, transactions.block_index
+		LIMIT $2 OFFSET $3;`
+
+	// SelectRevocableTicketsAfter is like SelectRevocableTickets, but selects
+	// the tickets after the ticket at height $3 and block index $4 instead of
+	// skipping tickets.
+	SelectRevocableTicketsAfter = revocableTickets + `
+			AND (tickets.block_height, transactions.block_index) > ($3, $4)
+		ORDER BY tickets.block_height, transactions.block_index
+		LIMIT $2;`
+
+	// SelectRevocableTicketsTotals counts the revocable tickets and sums their
+	// recoverable amounts.
+	SelectRevocableTicketsTotals = `WITH t AS (` + revocableTickets + `)
+		SELECT COUNT(*), COALESCE(SUM(amt), 0)::INT8 FROM t;`
+
+	// SelectPendingVotePayoutsByAddress sums the outputs paying to the given
+	// address of the mainchain votes after height $2, which are not yet
+	// spendable.
+	SelectPendingVotePayoutsByAddress = `SELECT COALESCE(SUM(addresses.value), 0)::INT8
+		FROM addresses
+		JOIN votes ON votes.tx_hash = addresses.tx_hash
+			AND votes.is_mainchain
+		WHERE addresses.address = $1
+			AND addresses.is_funding
+			AND addresses.valid_mainchain
+			AND votes.height > $2;`
 
 	SelectUnspentTickets = `SELECT id, tx_hash FROM tickets
 		WHERE spend_type = 0 AND is_mainchain = true;`
@@ -119,6 +289,15 @@ const (
 		WHERE pool_status = 0 AND tickets.is_mainchain = TRUE
 		GROUP BY price ORDER BY price;`
 
+	// SelectLiveTicketsByAge counts the live tickets in buckets of $2 blocks
+	// by the number of blocks since they matured at or before block $1. The
+	// last of the $3 buckets also counts any older tickets.
+	SelectLiveTicketsByAge = `SELECT LEAST(($1 - block_height - 1) / $2, $3 - 1) AS age_bucket,
+		COUNT(*)
+		FROM tickets
+		WHERE pool_status = 0 AND is_mainchain = TRUE AND block_height < $1
+		GROUP BY age_bucket ORDER BY age_bucket;`
+
 	selectTicketsByPurchaseDate = `SELECT %s as timestamp,
 		SUM(price) as price,
 		SUM(CASE WHEN tickets.block_height >= $1 THEN 1 ELSE 0 END) as immature,
@@ -159,11 +338,54 @@ const (
 		SET is_mainchain=$1
 		WHERE block_hash=$2;`
 
+	// SelectLiveTicketChangesByOutputsAboveHeight selects the change in the
+	// number of live tickets in each main chain block above height $1 by the
+	// number of outputs of the tickets' purchase transactions, which
+	// distinguishes solo (3) and pooled (5) tickets. Tickets become live $2
+	// (ticket maturity) blocks after they are mined, and leave the pool when
+	// they vote, miss a vote, or expire $3 (ticket expiry) blocks after they
+	// become live.
+	SelectLiveTicketChangesByOutputsAboveHeight = `SELECT blocks.height,
+			COALESCE(SUM(changes.delta) FILTER (WHERE changes.num_vout = 3), 0),
+			COALESCE(SUM(changes.delta) FILTER (WHERE changes.num_vout = 5), 0),
+			COALESCE(SUM(changes.delta) FILTER (WHERE changes.num_vout NOT IN (3, 5)), 0)
+		FROM blocks
+		LEFT JOIN (
+			SELECT tickets.block_height + $2 AS height, transactions.num_vout, 1 AS delta
+			FROM tickets
+			JOIN transactions ON transactions.id = tickets.purchase_tx_db_id
+			WHERE tickets.is_mainchain AND tickets.block_height + $2 > $1
+			UNION ALL
+			SELECT votes.height, transactions.num_vout, -1
+			FROM votes
+			JOIN tickets ON tickets.id = votes.ticket_tx_db_id
+			JOIN transactions ON transactions.id = tickets.purchase_tx_db_id
+			WHERE votes.is_mainchain AND votes.height > $1
+			UNION ALL
+			SELECT misses.height, transactions.num_vout, -1
+			FROM misses
+			JOIN blocks ON blocks.hash = misses.block_hash
+			JOIN tickets ON tickets.tx_hash = misses.ticket_hash
+			JOIN transactions ON transactions.id = tickets.purchase_tx_db_id
+			WHERE blocks.is_mainchain AND tickets.is_mainchain AND misses.height > $1
+			UNION ALL
+			SELECT tickets.block_height + $2 + $3, transactions.num_vout, -1
+			FROM tickets
+			JOIN transactions ON transactions.id = tickets.purchase_tx_db_id
+			WHERE tickets.is_mainchain AND tickets.pool_status = 2
+				AND tickets.block_height + $2 + $3 > $1
+		) AS changes ON changes.height = blocks.height
+		WHERE blocks.is_mainchain AND blocks.height > $1
+		GROUP BY blocks.height
+		ORDER BY blocks.height;`
+
 	// votes table
 
 	// CreateVotesTable creates a new table named votes. block_time field is
 	// needed to plot "Cumulative Vote Choices" agendas chart that plots
-	// cumulative votes count against time over the voting period.
+	// cumulative votes count against time over the voting period. choices is a
+	// JSON object of the choice ID (e.g. "yes") for each agenda of the vote
+	// version, decoded from vote_bits.
 	CreateVotesTable = `CREATE TABLE IF NOT EXISTS votes (
 		id SERIAL PRIMARY KEY,
 		height INT4,
@@ -178,7 +400,8 @@ const (
 		ticket_price FLOAT8,
 		vote_reward FLOAT8,
 		is_mainchain BOOLEAN,
-		block_time TIMESTAMPTZ
+		block_time TIMESTAMPTZ,
+		choices JSONB
 	);`
 
 	// insertVoteRow is the basis for several vote insert/upsert statements.
@@ -187,13 +410,13 @@ const (
 		block_hash, candidate_block_hash,
 		version, vote_bits, block_valid,
 		ticket_hash, ticket_tx_db_id, ticket_price, vote_reward,
-		is_mainchain, block_time)
+		is_mainchain, block_time, choices)
 	VALUES (
 		$1, $2,
 		$3, $4,
 		$5, $6, $7,
 		$8, $9, $10, $11,
-		$12, $13) `
+		$12, $13, $14) `
 
 	// InsertVoteRow inserts a new vote row without checking for unique index
 	// conflicts. This should only be used before the unique indexes are created
@@ -262,6 +485,45 @@ const (
 	SelectAllVoteDbIDsHeightsTicketHashes = `SELECT id, height, ticket_hash FROM votes;`
 	SelectAllVoteDbIDsHeightsTicketDbIDs  = `SELECT id, height, ticket_tx_db_id FROM votes;`
 
+	// SelectVoteByTxHash selects the height, version, vote bits and validation
+	// of the block's parent for a vote, preferring the mainchain vote.
+	SelectVoteByTxHash = `SELECT height, candidate_block_hash, version, vote_bits, block_valid
+		FROM votes
+		WHERE tx_hash = $1
+		ORDER BY is_mainchain DESC
+		LIMIT 1;`
+
+	// SelectVoteDetailsByTxHash selects a vote with its decoded vote bits,
+	// preferring the mainchain vote.
+	SelectVoteDetailsByTxHash = `SELECT tx_hash, ticket_hash, block_hash, height,
+			is_mainchain, candidate_block_hash, block_valid, version, vote_bits,
+			choices
+		FROM votes
+		WHERE tx_hash = $1
+		ORDER BY is_mainchain DESC
+		LIMIT 1;`
+
+	// SelectDistinctVoteBits selects each distinct vote version and vote bits
+	// combination.
+	SelectDistinctVoteBits = `SELECT DISTINCT version, vote_bits FROM votes;`
+
+	// UpdateVoteChoices sets the choices of the votes for each vote version
+	// ($1), vote bits ($2) and choices ($3) combination.
+	UpdateVoteChoices = `UPDATE votes
+		SET choices = decoded.choices
+		FROM (SELECT unnest($1::INT4[]) AS version, unnest($2::INT2[]) AS vote_bits,
+			unnest($3::JSONB[]) AS choices) AS decoded
+		WHERE votes.version = decoded.version AND votes.vote_bits = decoded.vote_bits;`
+
+	// SelectVoteVersionsByInterval counts the mainchain votes of each version
+	// in each stake version interval, given the first height of the intervals
+	// ($1) and the interval length ($2).
+	SelectVoteVersionsByInterval = `SELECT (height - $1) / $2 AS interval_index, version, COUNT(*)
+		FROM votes
+		WHERE is_mainchain AND height >= $1
+		GROUP BY interval_index, version
+		ORDER BY interval_index, version;`
+
 	UpdateVotesMainchainAll = `UPDATE votes
 		SET is_mainchain=b.is_mainchain
 		FROM (
@@ -458,6 +720,30 @@ const (
 			AND votes.height >= $5 AND votes.height <= $6
 			AND votes.is_mainchain = TRUE `
 
+	// SelectAgendaVoteTotalsByTicketOutputs counts the vote choices for an
+	// agenda by the number of outputs of the voting tickets' purchase
+	// transactions, which distinguishes solo and pooled tickets.
+	SelectAgendaVoteTotalsByTicketOutputs = `SELECT transactions.num_vout,
+			count(CASE WHEN agenda_votes.agenda_vote_choice = $1 THEN 1 ELSE NULL END) AS yes,
+			count(CASE WHEN agenda_votes.agenda_vote_choice = $2 THEN 1 ELSE NULL END) AS abstain,
+			count(CASE WHEN agenda_votes.agenda_vote_choice = $3 THEN 1 ELSE NULL END) AS no,
+			count(*) AS total
+		FROM agenda_votes
+		INNER JOIN votes ON agenda_votes.votes_row_id = votes.id
+		INNER JOIN tickets ON votes.ticket_tx_db_id = tickets.id
+		INNER JOIN transactions ON tickets.purchase_tx_db_id = transactions.id
+		WHERE agenda_votes.agendas_row_id = (SELECT id from agendas WHERE name = $4)
+			AND votes.height >= $5 AND votes.height <= $6
+			AND votes.is_mainchain = TRUE
+		GROUP BY transactions.num_vout;`
+
+	// SelectAgendaVotesByInterval counts the vote choices for an agenda in
+	// each consecutive span of $7 blocks starting at the voting start height,
+	// with the times of the first and last votes in the span.
+	SelectAgendaVotesByInterval = `SELECT (votes.height - $5) / $7 AS interval_index,
+			min(votes.block_time), max(votes.block_time),` +
+		selectAgendaVotesQuery + `GROUP BY interval_index ORDER BY interval_index;`
+
 	// Proposals Table
 
 	CreateProposalsTable = `CREATE TABLE IF NOT EXISTS proposals (
@@ -5,65 +5,117 @@ package internal
 
 // The stats table holds additional data beyond basic block data. row are unique
 // on height, so this table does not retain information related to orphaned
-// blocks.
+// blocks. pool_val_wavg is the value-weighted average value of the live
+// tickets in atoms, sum(value^2)/sum(value).
 const (
 	CreateStatsTable = `
 		CREATE TABLE IF NOT EXISTS stats (
 		blocks_id INT4 REFERENCES blocks(id) ON DELETE CASCADE,
 		height INT4 UNIQUE,
 		pool_size INT8,
-		pool_val INT8
+		pool_val INT8,
+		pool_val_wavg INT8
 	);`
 
 	IndexStatsOnHeight   = `CREATE UNIQUE INDEX ` + IndexOfHeightOnStatsTable + ` ON stats(height);`
 	DeindexStatsOnHeight = `DROP INDEX ` + IndexOfHeightOnStatsTable + ` CASCADE;`
 
+	// IndexStatsOnBlocksID speeds up the join with the blocks table that is
+	// used to look up pool info by block hash.
+	IndexStatsOnBlocksID   = `CREATE INDEX ` + IndexOfBlocksIDOnStatsTable + ` ON stats(blocks_id);`
+	DeindexStatsOnBlocksID = `DROP INDEX ` + IndexOfBlocksIDOnStatsTable + ` CASCADE;`
+
 	UpsertStats = `
-		INSERT INTO stats (blocks_id, height, pool_size, pool_val)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO stats (blocks_id, height, pool_size, pool_val, pool_val_wavg)
+		VALUES ($1, $2, $3, $4, $5)
 		ON CONFLICT (height)
 		DO UPDATE SET
 		blocks_id = $1,
 		height = $2,
 		pool_size = $3,
-		pool_val = $4
+		pool_val = $4,
+		pool_val_wavg = $5
 	;`
 
 	// SelectPoolInfo selects information about the ticket pool when a block with
 	// given hash was mined. The inner join serves to select only mainchain
 	// blocks.
 	SelectPoolInfo = `
-		SELECT blocks.winners, stats.pool_val, stats.pool_size
+		SELECT blocks.winners, stats.pool_val, stats.pool_size, stats.pool_val_wavg
 		FROM stats INNER JOIN blocks ON stats.blocks_id = blocks.id
 		WHERE blocks.hash = $1
 	;`
 
 	SelectPoolStatsAboveHeight = `
-		SELECT pool_size, pool_val
+		SELECT pool_size, pool_val, pool_val_wavg
 		FROM stats
 		WHERE height > $1
 		ORDER BY height
 	;`
 
 	SelectPoolInfoByHeight = `
-		SELECT blocks.hash, stats.pool_size, stats.pool_val, blocks.winners
+		SELECT blocks.hash, stats.pool_size, stats.pool_val, stats.pool_val_wavg,
+			blocks.winners
 		FROM stats JOIN blocks ON stats.blocks_id = blocks.id
 		WHERE stats.height = $1
 			AND is_mainchain
 	;`
 	SelectPoolInfoByHash = `
-		SELECT stats.height, stats.pool_size, stats.pool_val,	blocks.winners
+		SELECT stats.height, stats.pool_size, stats.pool_val,
+			stats.pool_val_wavg, blocks.winners
 		FROM stats JOIN blocks ON stats.blocks_id = blocks.id
 		WHERE blocks.hash = $1
 	;`
 	SelectPoolInfoRange = `
 		SELECT stats.height, blocks.hash, stats.pool_size,
-			stats.pool_val, blocks.winners
+			stats.pool_val, stats.pool_val_wavg, blocks.winners
 		FROM stats JOIN blocks ON stats.blocks_id = blocks.id
 		WHERE stats.height BETWEEN $1 AND $2
 			AND is_mainchain
 	;`
 
+	// SetPoolValWavgFromTickets sets pool_val_wavg at every height from the
+	// main chain tickets. A ticket is live from its maturity height,
+	// block_height + $1, until it votes, is missed, or expires $2 blocks after
+	// maturity. The live ticket value sums at each height are the running
+	// totals of the changes at each height. $3 is the voted spend_type.
+	SetPoolValWavgFromTickets = `
+		WITH missed AS (
+			SELECT misses.ticket_hash, MIN(misses.height) AS height
+			FROM misses JOIN blocks ON blocks.hash = misses.block_hash
+			WHERE blocks.is_mainchain
+			GROUP BY misses.ticket_hash
+		),
+		live AS (
+			SELECT ROUND(tickets.price * 1e8)::NUMERIC AS val,
+				tickets.block_height + $1 AS enter,
+				LEAST(CASE WHEN tickets.spend_type = $3 THEN tickets.spend_height END,
+					missed.height, tickets.block_height + $1 + $2) AS leave
+			FROM tickets LEFT JOIN missed ON missed.ticket_hash = tickets.tx_hash
+			WHERE tickets.is_mainchain
+		),
+		changes AS (
+			SELECT height, SUM(val) AS val, SUM(sq) AS sq
+			FROM (
+				SELECT enter AS height, val, val*val AS sq FROM live
+				UNION ALL
+				SELECT leave, -val, -val*val FROM live
+			) AS change
+			GROUP BY height
+		),
+		pool AS (
+			SELECT stats.height,
+				SUM(COALESCE(changes.val, 0)) OVER w AS val,
+				SUM(COALESCE(changes.sq, 0)) OVER w AS sq
+			FROM stats LEFT JOIN changes ON changes.height = stats.height
+			WINDOW w AS (ORDER BY stats.height)
+		)
+		UPDATE stats
+		SET pool_val_wavg = CASE WHEN pool.val > 0
+			THEN FLOOR(pool.sq / pool.val)::INT8 ELSE 0 END
+		FROM pool
+		WHERE stats.height = pool.height;`
+
 	SelectPoolValSizeRange = `
 		SELECT poolsize, poolval
 		FROM stats
@@ -0,0 +1,49 @@
+package internal
+
+// These queries load the rows stored with a block, as sent to the stored rows
+// handlers, for consumers that backfill the blocks they missed.
+const (
+	// SelectStoredBlockByHeight selects the main chain block at height $1
+	// with its row ID.
+	SelectStoredBlockByHeight = `SELECT id, hash, height, size, is_valid,
+			version, numtx, num_rtx, num_stx, time, nonce, vote_bits, voters,
+			fresh_stake, revocations, pool_size, bits, sbits, difficulty,
+			stake_version, previous_hash, COALESCE(chainwork, '')
+		FROM blocks
+		WHERE height = $1 AND is_mainchain;`
+
+	// SelectStoredTxns selects the transactions of the block with hash $1, in
+	// block order.
+	SelectStoredTxns = `SELECT id, block_hash, block_height, block_time,
+			time, tx_type, version, tree, tx_hash, block_index, lock_time, expiry,
+			size, spent, sent, fees, mix_count, mix_denom, num_vin, vin_db_ids,
+			num_vout, vout_db_ids, is_valid, is_mainchain, fee_rate
+		FROM transactions
+		WHERE block_hash = $1
+		ORDER BY tree, block_index;`
+
+	// SelectStoredVouts selects the outputs of the transactions of the block
+	// with hash $1.
+	SelectStoredVouts = `SELECT vouts.id, vouts.tx_hash, vouts.tx_index,
+			vouts.tx_tree, transactions.tx_type, vouts.value, vouts.version,
+			vouts.script_req_sigs, vouts.script_type, vouts.script_addresses,
+			vouts.mixed
+		FROM transactions
+		JOIN vouts ON vouts.id = ANY(transactions.vout_db_ids)
+		WHERE transactions.block_hash = $1;`
+
+	// SelectStoredAddressRows selects the funding and spending address rows
+	// of the transactions of the block with hash $1. The block time
+	// distinguishes the rows of a transaction that is also in a side chain
+	// block.
+	SelectStoredAddressRows = `SELECT addresses.id, addresses.address,
+			addresses.tx_hash, addresses.valid_mainchain,
+			addresses.matching_tx_hash, addresses.value, addresses.block_time,
+			addresses.is_funding, addresses.tx_vin_vout_index,
+			addresses.tx_vin_vout_row_id, addresses.tx_type
+		FROM transactions
+		JOIN addresses ON addresses.tx_hash = transactions.tx_hash
+			AND addresses.block_time = transactions.block_time
+		WHERE transactions.block_hash = $1
+		ORDER BY addresses.id;`
+)
@@ -54,3 +54,34 @@ const (
 
 	RetrievePGVersion = `SELECT version();`
 )
+
+// Notify sends a notification with the payload in $2 to any sessions
+// listening on the channel named by $1.
+const Notify = `SELECT pg_notify($1, $2);`
+
+// SelectTablesNeedingVacuum lists the public schema tables with at least $1
+// dead tuples where dead tuples make up at least the fraction $2 of the live
+// tuples.
+const SelectTablesNeedingVacuum = `SELECT relname
+	FROM pg_stat_user_tables
+	WHERE schemaname = 'public'
+		AND n_dead_tup >= $1
+		AND n_dead_tup >= $2 * GREATEST(n_live_tup, 1)
+	ORDER BY n_dead_tup DESC;`
+
+// SelectTableStats reports, for each of the user tables named in the array $1,
+// the estimated live and dead tuple counts, the sizes of the table, its
+// indexes and the total including TOAST data, and the last manual or
+// automatic VACUUM and ANALYZE times, largest tables first. Tables of the same
+// name in other schemas are included with a qualified name, e.g.
+// archive.addresses.
+const SelectTableStats = `SELECT
+		CASE WHEN schemaname = 'public' THEN relname ELSE schemaname || '.' || relname END,
+		n_live_tup, n_dead_tup,
+		pg_relation_size(relid), pg_indexes_size(relid),
+		pg_total_relation_size(relid),
+		GREATEST(last_vacuum, last_autovacuum),
+		GREATEST(last_analyze, last_autoanalyze)
+	FROM pg_stat_user_tables
+	WHERE relname = ANY($1)
+	ORDER BY pg_total_relation_size(relid) DESC;`
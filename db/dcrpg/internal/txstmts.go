@@ -36,7 +36,8 @@ const (
 		num_vout INT4,
 		vout_db_ids INT8[],
 		is_valid BOOLEAN,
-		is_mainchain BOOLEAN
+		is_mainchain BOOLEAN,
+		fee_rate INT8
 	);`
 
 	// insertTxRow is the basis for several tx insert/upsert statements.
@@ -46,14 +47,14 @@ const (
 		lock_time, expiry, size, spent, sent, fees,
 		mix_count, mix_denom,
 		num_vin, vin_db_ids, num_vout, vout_db_ids,
-		is_valid, is_mainchain)
+		is_valid, is_mainchain, fee_rate)
 	VALUES (
 		$1, $2, $3, $4,
 		$5, $6, $7, $8, $9,
 		$10, $11, $12, $13, $14, $15,
 		$16, $17,
 		$18, $19, $20, $21,
-		$22, $23) `
+		$22, $23, $24) `
 
 	// InsertTxRow inserts a new transaction row without checking for unique
 	// index conflicts. This should only be used before the unique indexes are
@@ -121,6 +122,64 @@ const (
 		` ON transactions(block_height);`
 	DeindexTransactionTableOnBlockHeight = `DROP INDEX ` + IndexOfTransactionsTableOnBlockHeight + ` CASCADE;`
 
+	// IndexTransactionTableOnCoinbase creates a partial index on block height
+	// of only the coinbase transactions, for lookups of the miner payouts in a
+	// range of blocks.
+	IndexTransactionTableOnCoinbase = `CREATE INDEX IF NOT EXISTS ` + IndexOfTransactionsTableOnCoinbase +
+		` ON transactions(block_height) WHERE tree = 0 AND block_index = 0;`
+	DeindexTransactionTableOnCoinbase = `DROP INDEX IF EXISTS ` + IndexOfTransactionsTableOnCoinbase + ` CASCADE;`
+
+	// coinbaseBlocksByAddress selects the mainchain blocks with coinbase
+	// outputs paying to the given address. coinbaseBlocksByAddressGroup sums
+	// those outputs by block, newest first.
+	coinbaseBlocksByAddress = `SELECT transactions.block_height, transactions.block_hash,
+			transactions.block_time, SUM(addresses.value)::INT8
+		FROM addresses
+		JOIN transactions ON transactions.tx_hash = addresses.tx_hash
+			AND transactions.is_mainchain
+		WHERE addresses.address = $1
+			AND addresses.is_funding
+			AND addresses.valid_mainchain
+			AND transactions.tree = 0
+			AND transactions.block_index = 0`
+	coinbaseBlocksByAddressGroup = `
+		GROUP BY transactions.block_height, transactions.block_hash, transactions.block_time
+		ORDER BY transactions.block_height DESC`
+
+	// SelectCoinbaseBlocksByAddress selects a page of the mainchain blocks with
+	// coinbase outputs paying to the given address, newest first, and the sum
+	// of those outputs.
+	SelectCoinbaseBlocksByAddress = coinbaseBlocksByAddress + coinbaseBlocksByAddressGroup + `
+		LIMIT $2 OFFSET $3;`
+
+	// SelectCoinbaseBlocksByAddressAfter is like SelectCoinbaseBlocksByAddress,
+	// but selects the blocks below height $3 instead of skipping blocks.
+	SelectCoinbaseBlocksByAddressAfter = coinbaseBlocksByAddress + `
+			AND transactions.block_height < $3` + coinbaseBlocksByAddressGroup + `
+		LIMIT $2;`
+
+	// SelectCoinbasePayoutAddressCounts counts the mainchain blocks on or after
+	// height $1 by payout address. A block's payout address is the address
+	// receiving its largest coinbase output, excluding the treasury output at
+	// index 0.
+	SelectCoinbasePayoutAddressCounts = `SELECT address, COUNT(*) AS num_blocks, MAX(block_height)
+		FROM (
+			SELECT DISTINCT ON (transactions.block_height) transactions.block_height,
+				vouts.script_addresses[1] AS address
+			FROM transactions
+			JOIN vouts ON vouts.tx_hash = transactions.tx_hash
+				AND vouts.tx_tree = 0
+			WHERE transactions.tree = 0
+				AND transactions.block_index = 0
+				AND transactions.is_mainchain
+				AND transactions.block_height >= $1
+				AND vouts.tx_index > 0
+				AND vouts.value > 0
+			ORDER BY transactions.block_height, vouts.value DESC
+		) AS miners
+		GROUP BY address
+		ORDER BY num_blocks DESC, address;`
+
 	SelectTxByHash = `SELECT id, block_hash, block_index, tree
 		FROM transactions
 		WHERE tx_hash = $1
@@ -128,6 +187,11 @@ const (
 	SelectTxsByBlockHash = `SELECT id, tx_hash, block_index, tree, block_time
 		FROM transactions WHERE block_hash = $1;`
 
+	// SelectMainchainTxExists checks for a mainchain transaction with the
+	// given hash.
+	SelectMainchainTxExists = `SELECT EXISTS(SELECT 1 FROM transactions
+		WHERE tx_hash = $1 AND is_mainchain);`
+
 	SelectTxBlockTimeByHash = `SELECT block_time
 		FROM transactions
 		WHERE tx_hash = $1
@@ -137,7 +201,7 @@ const (
 	SelectFullTxByHash = `SELECT id, block_hash, block_height, block_time,
 			time, tx_type, version, tree, tx_hash, block_index, lock_time, expiry,
 			size, spent, sent, fees, mix_count, mix_denom, num_vin, vin_db_ids,
-			num_vout, vout_db_ids, is_valid, is_mainchain
+			num_vout, vout_db_ids, is_valid, is_mainchain, fee_rate
 		FROM transactions WHERE tx_hash = $1
 		ORDER BY is_mainchain DESC, is_valid DESC, block_time DESC
 		LIMIT 1;`
@@ -145,7 +209,7 @@ const (
 	SelectFullTxsByHash = `SELECT id, block_hash, block_height, block_time,
 			time, tx_type, version, tree, tx_hash, block_index, lock_time, expiry,
 			size, spent, sent, fees, mix_count, mix_denom, num_vin, vin_db_ids,
-			num_vout, vout_db_ids, is_valid, is_mainchain
+			num_vout, vout_db_ids, is_valid, is_mainchain, fee_rate
 		FROM transactions WHERE tx_hash = $1
 		ORDER BY is_mainchain DESC, is_valid DESC, block_time DESC;`
 
@@ -179,13 +243,29 @@ const (
 		WHERE tx_hash = $1
 		ORDER BY is_valid DESC, is_mainchain DESC, block_height DESC;`
 
+	// SelectDuplicateTxns selects the hashes of transactions that appear in more
+	// than one block, most recent first, with a limit of $1 and offset of $2. A
+	// transaction can only be in one valid mainchain block, so only transactions
+	// with a side chain or invalidated instance are considered.
+	SelectDuplicateTxns = `SELECT tx_hash
+		FROM transactions
+		WHERE tx_hash IN (
+			SELECT tx_hash FROM transactions
+			WHERE NOT is_mainchain OR NOT is_valid
+		)
+		GROUP BY tx_hash
+		HAVING COUNT(*) > 1
+		ORDER BY MAX(block_height) DESC, tx_hash
+		LIMIT $1 OFFSET $2;`
+
 	UpdateRegularTxnsValidMainchainByBlock = `UPDATE transactions
 		SET is_valid=$1, is_mainchain=$2
 		WHERE block_hash=$3 AND tree=0;`
 
 	UpdateRegularTxnsValidByBlock = `UPDATE transactions
 		SET is_valid=$1
-		WHERE block_hash=$2 AND tree=0;`
+		WHERE block_hash=$2 AND tree=0
+		RETURNING id, tx_hash;`
 
 	UpdateTxnsMainchainByBlock = `UPDATE transactions
 		SET is_mainchain=$1
@@ -223,7 +303,7 @@ const (
 			AND tickets.is_mainchain
 		GROUP BY num_vout;`
 
-	SelectTxnByDbID = `SELECT block_hash, block_height, tx_hash
+	SelectTxnByDbID = `SELECT block_hash, block_height, tx_hash, sent
 		FROM transactions WHERE id = $1;`
 
 	//SelectTxByPrevOut = `SELECT * FROM transactions WHERE vins @> json_build_array(json_build_object('prevtxhash',$1)::jsonb)::jsonb;`
@@ -270,6 +350,25 @@ const (
 		GROUP BY block_height
 		ORDER BY block_height;`
 
+	// SelectTxTypesPerBlockAboveHeight selects the number and total sent
+	// value of the regular transactions, tickets, votes, and revocations in
+	// each mainchain block above a height.
+	SelectTxTypesPerBlockAboveHeight = `
+		SELECT block_height,
+			COUNT(CASE WHEN tx_type = 0 THEN 1 ELSE NULL END),
+			COUNT(CASE WHEN tx_type = 1 THEN 1 ELSE NULL END),
+			COUNT(CASE WHEN tx_type = 2 THEN 1 ELSE NULL END),
+			COUNT(CASE WHEN tx_type = 3 THEN 1 ELSE NULL END),
+			SUM(CASE WHEN tx_type = 0 THEN sent ELSE 0 END),
+			SUM(CASE WHEN tx_type = 1 THEN sent ELSE 0 END),
+			SUM(CASE WHEN tx_type = 2 THEN sent ELSE 0 END),
+			SUM(CASE WHEN tx_type = 3 THEN sent ELSE 0 END)
+		FROM transactions
+		WHERE is_mainchain
+			AND block_height > $1
+		GROUP BY block_height
+		ORDER BY block_height;`
+
 	SelectMixedTotalPerBlock = `
 		SELECT block_height AS block_height, 
 			SUM(mix_count * mix_denom) AS total_mixed
@@ -295,6 +394,23 @@ var (
 		FROM transactions
 		WHERE tx_type = %d;`,
 		stake.TxTypeSSRtx)
+
+	// SelectTicketFeeRatesPerWindow selects the minimum, median and maximum fee
+	// rates of the mainchain tickets mined in each stake difficulty window ($2
+	// blocks) above height $1, and the minimum fee rate of the tickets mined in
+	// the last $3 blocks of the window.
+	SelectTicketFeeRatesPerWindow = fmt.Sprintf(`SELECT block_height / $2 AS window_index,
+			MIN(fee_rate),
+			percentile_disc(0.5) WITHIN GROUP (ORDER BY fee_rate),
+			MAX(fee_rate),
+			COALESCE(MIN(fee_rate) FILTER (WHERE block_height %% $2 >= $2 - $3), 0)
+		FROM transactions
+		WHERE tx_type = %d
+			AND is_mainchain
+			AND block_height > $1
+		GROUP BY window_index
+		ORDER BY window_index;`,
+		stake.TxTypeSStx)
 )
 
 // MakeTxInsertStatement returns the appropriate transaction insert statement
@@ -0,0 +1,42 @@
+package internal
+
+// These queries relate to the utxo_distribution table, which records the
+// number and total value of the unspent outputs in each value bucket once a
+// UTC day. Buckets are numbered by width_bucket with the bucket bounds.
+const (
+	CreateUTXODistributionTable = `CREATE TABLE IF NOT EXISTS utxo_distribution (
+		day DATE NOT NULL,
+		height INT8 NOT NULL,
+		bucket INT2 NOT NULL,
+		count INT8 NOT NULL,
+		value INT8 NOT NULL,
+		PRIMARY KEY (day, bucket)
+	);`
+
+	// DeleteUTXODistributionDay removes the distribution recorded on day $1.
+	DeleteUTXODistributionDay = `DELETE FROM utxo_distribution WHERE day = $1;`
+
+	// InsertUTXODistribution records the distribution of the unspent output
+	// values among the buckets with bounds $3 on day $1, as of height $2.
+	InsertUTXODistribution = `INSERT INTO utxo_distribution (day, height, bucket, count, value)
+		SELECT $1, $2, width_bucket(vouts.value, $3::INT8[]) AS bucket,
+			count(*), sum(vouts.value)
+		FROM vouts
+		JOIN transactions ON transactions.tx_hash = vouts.tx_hash
+		WHERE vouts.spend_tx_row_id IS NULL AND vouts.value > 0
+			AND transactions.is_mainchain AND transactions.is_valid
+		GROUP BY bucket;`
+
+	// SelectUTXODistributions selects the distributions recorded on and after
+	// day $1, oldest first.
+	SelectUTXODistributions = `SELECT day, height, bucket, count, value
+		FROM utxo_distribution
+		WHERE day >= $1
+		ORDER BY day, bucket;`
+
+	// SelectLatestUTXODistribution selects the most recent distribution.
+	SelectLatestUTXODistribution = `SELECT day, height, bucket, count, value
+		FROM utxo_distribution
+		WHERE day = (SELECT MAX(day) FROM utxo_distribution)
+		ORDER BY bucket;`
+)
@@ -20,12 +20,17 @@ const (
 		prev_tx_index INT8,
 		prev_tx_tree INT2,
 		value_in INT8,
-		tx_type INT4
+		tx_type INT4,
+		sequence INT8,
+		sig_type INT2,
+		sig_script BYTEA -- only for inputs that spend no previous output
 	);`
 
 	// insertVinRow is the basis for several vinvs insert/upsert statements.
 	insertVinRow = `INSERT INTO vins (tx_hash, tx_index, tx_tree, prev_tx_hash, prev_tx_index, prev_tx_tree,
-		value_in, is_valid, is_mainchain, block_time, tx_type) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) `
+		value_in, is_valid, is_mainchain, block_time, tx_type, sequence, sig_type,
+		sig_script)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14) `
 
 	// InsertVinRow inserts a new vin row without checking for unique index
 	// conflicts. This should only be used before the unique indexes are created
@@ -36,7 +41,8 @@ const (
 	// inserted/updated vin row id.
 	UpsertVinRow = insertVinRow + `ON CONFLICT (tx_hash, tx_index, tx_tree) DO UPDATE
 		SET is_valid = $8, is_mainchain = $9, block_time = $10,
-			prev_tx_hash = $4, prev_tx_index = $5, prev_tx_tree = $6
+			prev_tx_hash = $4, prev_tx_index = $5, prev_tx_tree = $6,
+			sequence = $12, sig_type = $13, sig_script = $14
 		RETURNING id;`
 
 	// InsertVinRowOnConflictDoNothing allows an INSERT with a DO NOTHING on
@@ -107,19 +113,96 @@ const (
 		WHERE prev_tx_hash=$1 AND vins.is_valid AND vins.is_mainchain;`
 	SelectSpendingTxByPrevOut = `SELECT id, tx_hash, tx_index, tx_tree FROM vins
 		WHERE prev_tx_hash=$1 AND prev_tx_index=$2 ORDER BY is_valid DESC, is_mainchain DESC, block_time DESC;`
+	// SelectMainchainSpenderByPrevOut selects the valid mainchain
+	// transaction spending the given outpoint, if any.
+	SelectMainchainSpenderByPrevOut = `SELECT tx_hash FROM vins
+		WHERE prev_tx_hash=$1 AND prev_tx_index=$2 AND is_valid AND is_mainchain
+		LIMIT 1;`
 	SelectFundingTxsByTx        = `SELECT id, prev_tx_hash FROM vins WHERE tx_hash=$1;`
 	SelectFundingTxByTxIn       = `SELECT id, prev_tx_hash FROM vins WHERE tx_hash=$1 AND tx_index=$2;`
 	SelectFundingOutpointByTxIn = `SELECT id, prev_tx_hash, prev_tx_index, prev_tx_tree FROM vins
 		WHERE tx_hash=$1 AND tx_index=$2;`
 
+	// SelectMainchainTxInputsWithAddresses selects the block height of the
+	// mainchain transaction with the given hash, and the value and previous
+	// outpoint addresses of each of its inputs in order. Stakebase and coinbase
+	// inputs have no previous outpoint addresses.
+	SelectMainchainTxInputsWithAddresses = `SELECT transactions.block_height, vins.value_in,
+			COALESCE(vouts.script_addresses, '{}')
+		FROM transactions
+		JOIN vins ON vins.id = ANY(transactions.vin_db_ids)
+		LEFT JOIN vouts ON vouts.tx_hash = vins.prev_tx_hash
+			AND vouts.tx_index = vins.prev_tx_index
+			AND vouts.tx_tree = vins.prev_tx_tree
+		WHERE transactions.tx_hash = $1
+			AND transactions.is_mainchain
+		ORDER BY vins.tx_index;`
+
 	SelectFundingOutpointByVinID     = `SELECT prev_tx_hash, prev_tx_index, prev_tx_tree FROM vins WHERE id=$1;`
 	SelectFundingOutpointIndxByVinID = `SELECT prev_tx_index FROM vins WHERE id=$1;`
 	SelectFundingTxByVinID           = `SELECT prev_tx_hash FROM vins WHERE id=$1;`
 	SelectSpendingTxByVinID          = `SELECT tx_hash, tx_index, tx_tree FROM vins WHERE id=$1;`
 	SelectAllVinInfoByID             = `SELECT tx_hash, tx_index, tx_tree, is_valid, is_mainchain, block_time,
 		prev_tx_hash, prev_tx_index, prev_tx_tree, value_in, tx_type FROM vins WHERE id = $1;`
+	// SelectVinsBySigTypeRange selects the vins of mainchain transactions in
+	// blocks with heights in the range [$1, $2] with signature scripts of type
+	// $3.
+	SelectVinsBySigTypeRange = `SELECT transactions.block_height, vins.tx_hash,
+			vins.tx_index, vins.tx_tree, vins.prev_tx_hash, vins.prev_tx_index,
+			vins.value_in, vins.sequence
+		FROM transactions
+		JOIN vins ON vins.tx_hash = transactions.tx_hash
+			AND vins.tx_tree = transactions.tree
+		WHERE transactions.block_height BETWEEN $1 AND $2
+			AND transactions.is_mainchain AND vins.is_mainchain
+			AND vins.sig_type = $3
+		ORDER BY transactions.block_height, vins.tx_tree,
+			transactions.block_index, vins.tx_index;`
+
+	// SetStakebaseSigScripts sets the signature scripts of the stakebase
+	// inputs to the consensus stakebase script $1.
+	SetStakebaseSigScripts = `UPDATE vins SET sig_script = $1
+		WHERE tx_tree = 1 AND tx_index = 0
+			AND prev_tx_hash = '0000000000000000000000000000000000000000000000000000000000000000';`
+
+	// SetCoinbaseSigScripts sets the signature scripts of the inputs of the
+	// coinbase transactions with the hashes in the array $1 to the scripts in
+	// the array $2.
+	SetCoinbaseSigScripts = `UPDATE vins SET sig_script = scripts.sig_script
+		FROM unnest($1::TEXT[], $2::BYTEA[]) AS scripts(tx_hash, sig_script)
+		WHERE vins.tx_hash = scripts.tx_hash
+			AND vins.tx_tree = 0 AND vins.tx_index = 0;`
+
+	// SetVinSigTypesAndSequences sets the sequence numbers ($4) and signature
+	// script types ($5) of the vins with the transaction hashes ($1), indexes
+	// ($2) and trees ($3) in the arrays.
+	SetVinSigTypesAndSequences = `UPDATE vins SET sequence = vin.sequence, sig_type = vin.sig_type
+		FROM unnest($1::TEXT[], $2::INT4[], $3::INT2[], $4::INT8[], $5::INT2[])
+			AS vin(tx_hash, tx_index, tx_tree, sequence, sig_type)
+		WHERE vins.tx_hash = vin.tx_hash AND vins.tx_index = vin.tx_index
+			AND vins.tx_tree = vin.tx_tree;`
+
 	SelectVinVoutPairByID = `SELECT tx_hash, tx_index, prev_tx_hash, prev_tx_index FROM vins WHERE id = $1;`
 
+	// SelectVinsWithPrevOutsByIDs selects the vins with the given row IDs, with
+	// the addresses of the previous outputs and the block heights of the
+	// previous transactions, preferring mainchain and valid blocks.
+	SelectVinsWithPrevOutsByIDs = `SELECT vins.tx_index, vins.prev_tx_hash,
+			vins.prev_tx_index, vins.prev_tx_tree, vins.value_in, vins.sig_script,
+			COALESCE(vouts.script_addresses, '{}'), COALESCE(prev.block_height, 0)
+		FROM vins
+		LEFT JOIN vouts ON vouts.tx_hash = vins.prev_tx_hash
+			AND vouts.tx_index = vins.prev_tx_index
+			AND vouts.tx_tree = vins.prev_tx_tree
+		LEFT JOIN LATERAL (
+			SELECT block_height FROM transactions
+			WHERE tx_hash = vins.prev_tx_hash
+			ORDER BY is_mainchain DESC, is_valid DESC
+			LIMIT 1
+		) prev ON TRUE
+		WHERE vins.id = ANY($1)
+		ORDER BY vins.tx_index;`
+
 	SelectUTXOsViaVinsMatch = `SELECT vouts.id, vouts.tx_hash, vouts.tx_index,   -- row ID and outpoint
 			vouts.script_addresses, vouts.value, vouts.mixed         -- value, addresses, and mixed flag of output
 		FROM vouts
@@ -258,6 +341,38 @@ const (
 		` ON vouts(spend_tx_row_id);`
 	DeindexVoutTableOnSpendTxID = `DROP INDEX IF EXISTS ` + IndexOfVoutsTableOnSpendTxID + ` CASCADE;`
 
+	// nullDataPayload is the data pushed by a null data (OP_RETURN) pkscript,
+	// which follows the OP_RETURN and the push opcode. The push opcode is
+	// either a direct push of up to 75 bytes, or OP_PUSHDATA1, OP_PUSHDATA2, or
+	// OP_PUSHDATA4 followed by a 1, 2, or 4 byte data length.
+	nullDataPayload = `SUBSTRING(vouts.pkscript FROM CASE
+			WHEN LENGTH(vouts.pkscript) < 2 THEN 2
+			WHEN GET_BYTE(vouts.pkscript, 1) = 76 THEN 4
+			WHEN GET_BYTE(vouts.pkscript, 1) = 77 THEN 5
+			WHEN GET_BYTE(vouts.pkscript, 1) = 78 THEN 7
+			ELSE 3 END)`
+
+	// IndexVoutTableOnNullData creates a partial index on the payload of the
+	// null data outputs, for searches by payload prefix.
+	IndexVoutTableOnNullData = `CREATE INDEX IF NOT EXISTS ` + IndexOfVoutsTableOnNullData +
+		` ON vouts((` + nullDataPayload + `)) WHERE script_type = 'nulldata';`
+	DeindexVoutTableOnNullData = `DROP INDEX IF EXISTS ` + IndexOfVoutsTableOnNullData + ` CASCADE;`
+
+	// SelectNullDataByPayloadRange selects the mainchain null data outputs with
+	// a payload at least $1 and, unless $2 is NULL, less than $2, newest first.
+	// The results are limited to $3 outputs, skipping $4.
+	SelectNullDataByPayloadRange = `SELECT vouts.tx_hash, vouts.tx_index,
+			transactions.block_height, transactions.block_time, ` + nullDataPayload + `
+		FROM vouts
+		JOIN transactions ON transactions.tx_hash = vouts.tx_hash
+			AND transactions.tree = vouts.tx_tree
+			AND transactions.is_mainchain
+		WHERE vouts.script_type = 'nulldata'
+			AND ` + nullDataPayload + ` >= $1
+			AND ($2::BYTEA IS NULL OR ` + nullDataPayload + ` < $2)
+		ORDER BY transactions.block_height DESC, vouts.tx_hash, vouts.tx_index
+		LIMIT $3 OFFSET $4;`
+
 	SelectAddressByTxHash = `SELECT id, script_addresses, value, mixed FROM vouts
 		WHERE tx_hash = $1 AND tx_index = $2 AND tx_tree = $3;`
 
@@ -270,6 +385,31 @@ const (
 	SelectVoutIDByOutpoint = `SELECT id FROM vouts WHERE tx_hash=$1 and tx_index=$2;`
 	SelectVoutByID         = `SELECT * FROM vouts WHERE id=$1;`
 
+	// SelectVoutsWithSpentByIDs selects the vouts with the given row IDs, and
+	// whether each is spent by a valid mainchain transaction.
+	SelectVoutsWithSpentByIDs = `SELECT tx_index, value, pkscript, script_type,
+			script_addresses,
+			EXISTS(SELECT 1 FROM vins
+				WHERE vins.prev_tx_hash = vouts.tx_hash
+					AND vins.prev_tx_index = vouts.tx_index
+					AND vins.prev_tx_tree = vouts.tx_tree
+					AND vins.is_valid AND vins.is_mainchain)
+		FROM vouts
+		WHERE id = ANY($1)
+		ORDER BY tx_index;`
+
+	// SelectMainchainOutpoint selects the value of the given output of a valid
+	// mainchain transaction, and the transaction's tree, type, block height,
+	// and index in the block.
+	SelectMainchainOutpoint = `SELECT vouts.value, transactions.tree,
+		transactions.tx_type, transactions.block_height, transactions.block_index
+	FROM vouts
+	JOIN transactions ON transactions.tx_hash = vouts.tx_hash
+		AND transactions.tree = vouts.tx_tree
+		AND transactions.is_mainchain AND transactions.is_valid
+	WHERE vouts.tx_hash = $1 AND vouts.tx_index = $2
+	LIMIT 1;`
+
 	RetrieveVoutValue  = `SELECT value FROM vouts WHERE tx_hash=$1 and tx_index=$2;`
 	RetrieveVoutValues = `SELECT value, tx_index, tx_tree FROM vouts WHERE tx_hash=$1;`
 )
@@ -0,0 +1,37 @@
+package internal
+
+// These queries relate to the winning_tickets table, which records the tickets
+// selected by the lottery of each main chain block, so that the blocks in which
+// a ticket was selected can be found. The rows of blocks that are reorganized
+// out of the main chain are kept, so queries check blocks.is_mainchain.
+const (
+	CreateWinningTicketsTable = `CREATE TABLE IF NOT EXISTS winning_tickets (
+		ticket_hash TEXT NOT NULL,
+		block_hash TEXT NOT NULL,
+		height INT4 NOT NULL,
+		PRIMARY KEY (ticket_hash, block_hash)
+	);`
+
+	// InsertWinningTickets records the winning tickets $3 of the block with
+	// hash $1 at height $2. Tickets already recorded for the block are
+	// skipped.
+	InsertWinningTickets = `INSERT INTO winning_tickets (ticket_hash, block_hash, height)
+		SELECT ticket_hash, $1, $2 FROM UNNEST($3::TEXT[]) AS ticket_hash
+		ON CONFLICT (ticket_hash, block_hash) DO NOTHING;`
+
+	// InsertAllWinningTickets records the winning tickets of all the blocks in
+	// the blocks table.
+	InsertAllWinningTickets = `INSERT INTO winning_tickets (ticket_hash, block_hash, height)
+		SELECT ticket_hash, blocks.hash, blocks.height
+		FROM blocks, UNNEST(blocks.winners) AS ticket_hash
+		ON CONFLICT (ticket_hash, block_hash) DO NOTHING;`
+
+	// SelectTicketWinningBlocks selects the blocks in which the ticket $1 was
+	// selected to vote, including side chain blocks, oldest first.
+	SelectTicketWinningBlocks = `SELECT blocks.hash, blocks.height, blocks.time,
+			blocks.is_mainchain, COALESCE(blocks.final_state, '')
+		FROM winning_tickets
+		JOIN blocks ON blocks.hash = winning_tickets.block_hash
+		WHERE winning_tickets.ticket_hash = $1
+		ORDER BY blocks.height, blocks.is_mainchain DESC;`
+)
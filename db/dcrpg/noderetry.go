@@ -0,0 +1,136 @@
+// Copyright (c) 2020, The Decred developers
+// See LICENSE for details.
+
+package dcrpg
+
+import (
+	"context"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/decred/dcrd/rpcclient/v5"
+)
+
+const (
+	// rpcMaxAttempts is the maximum number of times an idempotent dcrd RPC is
+	// attempted before giving up when the connection appears to be down.
+	rpcMaxAttempts = 4
+
+	// rpcInitialBackoff is the delay before the first retry. The delay doubles
+	// with each subsequent attempt.
+	rpcInitialBackoff = 250 * time.Millisecond
+)
+
+// isRPCConnectionError checks if the error from an RPC indicates that the
+// connection to dcrd was lost, as opposed to an error returned by dcrd for the
+// request itself. Only the former is worth retrying.
+func isRPCConnectionError(err error) bool {
+	if err == nil {
+		return false
+	}
+	switch err {
+	case rpcclient.ErrClientDisconnect, rpcclient.ErrClientNotConnected,
+		io.EOF, io.ErrUnexpectedEOF:
+		return true
+	}
+	if _, ok := err.(net.Error); ok {
+		return true
+	}
+	errStr := err.Error()
+	return strings.Contains(errStr, "connection refused") ||
+		strings.Contains(errStr, "connection reset") ||
+		strings.Contains(errStr, "broken pipe")
+}
+
+// nodeHealth tracks the state of the connection to dcrd as observed by the
+// results of RPCs made through retryRPC.
+type nodeHealth struct {
+	mtx      sync.RWMutex
+	degraded bool
+	since    time.Time
+	lastErr  error
+}
+
+// setDegraded records a connection failure, returning true if this is a
+// transition from a healthy state.
+func (nh *nodeHealth) setDegraded(err error) bool {
+	nh.mtx.Lock()
+	defer nh.mtx.Unlock()
+	nh.lastErr = err
+	if nh.degraded {
+		return false
+	}
+	nh.degraded = true
+	nh.since = time.Now()
+	return true
+}
+
+// setHealthy records a successful RPC, returning true if this is a transition
+// from a degraded state.
+func (nh *nodeHealth) setHealthy() bool {
+	nh.mtx.Lock()
+	defer nh.mtx.Unlock()
+	if !nh.degraded {
+		return false
+	}
+	nh.degraded = false
+	nh.lastErr = nil
+	return true
+}
+
+func (nh *nodeHealth) isDegraded() bool {
+	nh.mtx.RLock()
+	defer nh.mtx.RUnlock()
+	return nh.degraded
+}
+
+// retryRPC calls the provided function, which should perform a single
+// idempotent dcrd RPC, until it succeeds, it fails with an error that does not
+// indicate a lost connection, the maximum number of attempts is reached, or
+// the context is canceled. The backoff between attempts grows exponentially.
+// The node health status is updated according to the outcome.
+func retryRPC(ctx context.Context, nh *nodeHealth, call func() error) error {
+	backoff := rpcInitialBackoff
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = call()
+		if !isRPCConnectionError(err) {
+			if nh.setHealthy() {
+				log.Infof("dcrd RPC connection restored.")
+			}
+			return err
+		}
+
+		if nh.setDegraded(err) {
+			log.Warnf("dcrd RPC connection degraded: %v", err)
+		}
+
+		if attempt == rpcMaxAttempts {
+			return err
+		}
+
+		log.Debugf("Retrying dcrd RPC in %v (attempt %d of %d).", backoff,
+			attempt+1, rpcMaxAttempts)
+		select {
+		case <-time.After(backoff):
+			backoff *= 2
+		case <-ctx.Done():
+			return err
+		}
+	}
+}
+
+// retryRPC performs an idempotent dcrd RPC with retries if the connection
+// appears to have dropped. See the retryRPC function.
+func (pgb *ChainDB) retryRPC(call func() error) error {
+	return retryRPC(pgb.ctx, &pgb.nodeHealth, call)
+}
+
+// NodeDegraded indicates if recent RPCs to dcrd have failed because the
+// connection was lost.
+func (pgb *ChainDB) NodeDegraded() bool {
+	return pgb.nodeHealth.isDegraded()
+}
@@ -0,0 +1,61 @@
+package dcrpg
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/decred/dcrd/rpcclient/v5"
+)
+
+func TestRetryRPC(t *testing.T) {
+	var nh nodeHealth
+	ctx := context.Background()
+
+	// A non-connection error is returned immediately.
+	var calls int
+	errBadRequest := errors.New("-8: invalid parameter")
+	err := retryRPC(ctx, &nh, func() error {
+		calls++
+		return errBadRequest
+	})
+	if err != errBadRequest || calls != 1 {
+		t.Errorf("expected 1 call and %v, got %d calls and %v", errBadRequest, calls, err)
+	}
+	if nh.isDegraded() {
+		t.Errorf("node should not be degraded by a request error")
+	}
+
+	// Connection errors are retried until success, and the degraded state is
+	// cleared once the call succeeds.
+	calls = 0
+	err = retryRPC(ctx, &nh, func() error {
+		calls++
+		if calls < 2 {
+			return rpcclient.ErrClientDisconnect
+		}
+		return nil
+	})
+	if err != nil || calls != 2 {
+		t.Errorf("expected 2 calls and no error, got %d calls and %v", calls, err)
+	}
+	if nh.isDegraded() {
+		t.Errorf("node should not be degraded after a successful call")
+	}
+
+	// A canceled context stops retries, leaving the node degraded.
+	ctx, cancel := context.WithCancel(ctx)
+	cancel()
+	calls = 0
+	err = retryRPC(ctx, &nh, func() error {
+		calls++
+		return rpcclient.ErrClientNotConnected
+	})
+	if err != rpcclient.ErrClientNotConnected || calls != 1 {
+		t.Errorf("expected 1 call and %v, got %d calls and %v",
+			rpcclient.ErrClientNotConnected, calls, err)
+	}
+	if !nh.isDegraded() {
+		t.Errorf("node should be degraded after a connection error")
+	}
+}
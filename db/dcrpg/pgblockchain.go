@@ -79,14 +79,20 @@ var piParserCounter uint32
 
 // ticketPoolDataCache stores the most recent ticketpool graphs information
 // fetched to minimize the possibility of making multiple queries to the db
-// fetching the same information.
+// fetching the same information. The data for an interval is fresh if it was
+// fetched at the best block Hash, rather than Height, which is ambiguous
+// across reorgs.
 type ticketPoolDataCache struct {
 	sync.RWMutex
 	Height          map[dbtypes.TimeBasedGrouping]int64
+	Hash            map[dbtypes.TimeBasedGrouping]chainhash.Hash
 	TimeGraphCache  map[dbtypes.TimeBasedGrouping]*dbtypes.PoolTicketsData
 	PriceGraphCache map[dbtypes.TimeBasedGrouping]*dbtypes.PoolTicketsData
 	// DonutGraphCache persist data for the Number of tickets outputs pie chart.
 	DonutGraphCache map[dbtypes.TimeBasedGrouping]*dbtypes.PoolTicketsData
+	// AgeGraphCache persist data for the live ticket age bar graph.
+	AgeGraphCache map[dbtypes.TimeBasedGrouping]*dbtypes.PoolTicketsData
+	stats         cacheStats
 }
 
 // ProposalsFetcher defines the interface of the proposals plug-n-play data source.
@@ -99,26 +105,32 @@ type ProposalsFetcher interface {
 // ticketPoolGraphsCache persists the latest ticketpool data queried from the db.
 var ticketPoolGraphsCache = &ticketPoolDataCache{
 	Height:          make(map[dbtypes.TimeBasedGrouping]int64),
+	Hash:            make(map[dbtypes.TimeBasedGrouping]chainhash.Hash),
 	TimeGraphCache:  make(map[dbtypes.TimeBasedGrouping]*dbtypes.PoolTicketsData),
 	PriceGraphCache: make(map[dbtypes.TimeBasedGrouping]*dbtypes.PoolTicketsData),
 	DonutGraphCache: make(map[dbtypes.TimeBasedGrouping]*dbtypes.PoolTicketsData),
+	AgeGraphCache:   make(map[dbtypes.TimeBasedGrouping]*dbtypes.PoolTicketsData),
+	stats:           cacheStats{updated: time.Now()},
 }
 
 // TicketPoolData is a thread-safe way to access the ticketpool graphs data
-// stored in the cache.
-func TicketPoolData(interval dbtypes.TimeBasedGrouping, height int64) (timeGraph *dbtypes.PoolTicketsData,
-	priceGraph *dbtypes.PoolTicketsData, donutChart *dbtypes.PoolTicketsData, actualHeight int64, intervalFound, isStale bool) {
+// stored in the cache. The data is stale if it was not fetched at the block
+// with the given hash.
+func TicketPoolData(interval dbtypes.TimeBasedGrouping, hash chainhash.Hash) (timeGraph *dbtypes.PoolTicketsData,
+	priceGraph *dbtypes.PoolTicketsData, donutChart *dbtypes.PoolTicketsData, ageGraph *dbtypes.PoolTicketsData,
+	actualHeight int64, intervalFound, isStale bool) {
 	ticketPoolGraphsCache.RLock()
 	defer ticketPoolGraphsCache.RUnlock()
 
-	var tFound, pFound, dFound bool
+	var tFound, pFound, dFound, aFound bool
 	timeGraph, tFound = ticketPoolGraphsCache.TimeGraphCache[interval]
 	priceGraph, pFound = ticketPoolGraphsCache.PriceGraphCache[interval]
 	donutChart, dFound = ticketPoolGraphsCache.DonutGraphCache[interval]
-	intervalFound = tFound && pFound && dFound
+	ageGraph, aFound = ticketPoolGraphsCache.AgeGraphCache[interval]
+	intervalFound = tFound && pFound && dFound && aFound
 
 	actualHeight = ticketPoolGraphsCache.Height[interval]
-	isStale = ticketPoolGraphsCache.Height[interval] != height
+	isStale = ticketPoolGraphsCache.Hash[interval] != hash
 
 	return
 }
@@ -127,14 +139,18 @@ func TicketPoolData(interval dbtypes.TimeBasedGrouping, height int64) (timeGraph
 // This is a thread-safe way to update ticket pool cache data. TryLock helps avoid
 // stacking calls to update the cache.
 func UpdateTicketPoolData(interval dbtypes.TimeBasedGrouping, timeGraph *dbtypes.PoolTicketsData,
-	priceGraph *dbtypes.PoolTicketsData, donutcharts *dbtypes.PoolTicketsData, height int64) {
+	priceGraph *dbtypes.PoolTicketsData, donutcharts *dbtypes.PoolTicketsData, ageGraph *dbtypes.PoolTicketsData,
+	height int64, hash chainhash.Hash) {
 	ticketPoolGraphsCache.Lock()
 	defer ticketPoolGraphsCache.Unlock()
 
 	ticketPoolGraphsCache.Height[interval] = height
+	ticketPoolGraphsCache.Hash[interval] = hash
 	ticketPoolGraphsCache.TimeGraphCache[interval] = timeGraph
 	ticketPoolGraphsCache.PriceGraphCache[interval] = priceGraph
 	ticketPoolGraphsCache.DonutGraphCache[interval] = donutcharts
+	ticketPoolGraphsCache.AgeGraphCache[interval] = ageGraph
+	ticketPoolGraphsCache.stats.touch()
 }
 
 // utxoStore provides a UTXOData cache with thread-safe get/set methods.
@@ -262,14 +278,16 @@ type ChainDB struct {
 	devAddress         string
 	dupChecks          bool
 	bestBlock          *BestBlock
-	lastBlock          map[chainhash.Hash]uint64
+	lastBlock          *blockIDCache
 	stakeDB            *stakedb.StakeDatabase
 	unspentTicketCache *TicketTxnIDGetter
 	AddressCache       *cache.AddressCache
+	devFundStats       cacheStats
 	CacheLocks         cacheLocks
 	devPrefetch        bool
 	InBatchSync        bool
 	InReorg            bool
+	reindexing         bool // set by ReindexBlockRange
 	tpUpdatePermission map[dbtypes.TimeBasedGrouping]*trylock.Mutex
 	utxoCache          utxoStore
 	mixSetDiffsMtx     sync.Mutex
@@ -282,11 +300,34 @@ type ChainDB struct {
 	// BlockCache stores apitypes.BlockDataBasic and apitypes.StakeInfoExtended
 	// in StoreBlock for quick retrieval without a DB query.
 	BlockCache        *apitypes.APICache
-	heightClients     []chan uint32
+	heightClients     []*heightNotifier
+	heightNtfnBuffer  int
+	invalidationMtx   sync.RWMutex
+	invalidationHdlrs []func(*exptypes.BlockInvalidation)
+	validityMtx       sync.RWMutex
+	validityHdlrs     []func(*exptypes.BlockValidity)
+	anomalyMtx        sync.RWMutex
+	anomalyHdlrs      []func([]*dbtypes.ScriptAnomaly)
+	anomalyRules      *dbtypes.BlockAnomalyRules
+	blockAnomalyHdlrs []func([]*dbtypes.BlockAnomaly)
+	agendaMtx         sync.RWMutex
+	agendaHdlrs       []func(*exptypes.AgendaStatusChange)
+	reorgMtx          sync.RWMutex
+	reorgHdlrs        []func(*txhelpers.ReorgData)
+	storedMtx         sync.RWMutex
+	storedHdlrs       []func(*wire.MsgBlock, bool)
+	recordsHdlrs      []func(*dbtypes.StoredBlock)
+	notifyChannel     string
+	archive           *spentArchive
+	writes            writeTracker
 	shutdownDcrdata   func()
 	Client            *rpcclient.Client
+	nodeHealth        nodeHealth
 	tipMtx            sync.Mutex
 	tipSummary        *apitypes.BlockDataBasic
+	demandMtx         sync.Mutex
+	demandHash        string // best block of demandHistory
+	demandHistory     []dbtypes.BlockTicketDemand
 	lastExplorerBlock struct {
 		sync.Mutex
 		hash      string
@@ -304,11 +345,23 @@ type ChainDeployments struct {
 	chainInfo *dbtypes.BlockChainData
 }
 
-// BestBlock is mutex-protected block hash and height.
+// BestBlock is mutex-protected block hash and height. The hash is kept as a
+// chainhash.Hash and as a string, so that neither getter needs a conversion.
+// The hash string is empty if there are no blocks.
 type BestBlock struct {
-	mtx    sync.RWMutex
-	height int64
-	hash   string
+	mtx     sync.RWMutex
+	height  int64
+	hash    chainhash.Hash
+	hashStr string
+}
+
+// set updates the best block. hashStr must be the string of hash.
+func (block *BestBlock) set(height int64, hash *chainhash.Hash, hashStr string) {
+	block.mtx.Lock()
+	block.height = height
+	block.hash = *hash
+	block.hashStr = hashStr
+	block.mtx.Unlock()
 }
 
 // lastSync defines the latest sync time for the proposal votes sync.
@@ -342,6 +395,22 @@ func (pgb *ChainDB) replaceCancelError(err error) error {
 	return errors.New(patched)
 }
 
+// queryContext returns a context for a DB query with the configured query
+// timeout. The context is canceled if either ctx, such as an HTTP request's
+// context, or the ChainDB's context is done, so that the query is aborted when
+// the client goes away or dcrdata is shutting down.
+func (pgb *ChainDB) queryContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithTimeout(ctx, pgb.queryTimeout)
+	go func() {
+		select {
+		case <-pgb.ctx.Done():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, cancel
+}
+
 // MissingSideChainBlocks identifies side chain blocks that are missing from the
 // DB. Side chains known to dcrd are listed via the getchaintips RPC. Each block
 // presence in the postgres DB is checked, and any missing block is returned in
@@ -373,7 +442,7 @@ func (pgb *ChainDB) MissingSideChainBlocks() ([]dbtypes.SideChain, int, error) {
 		// For each block in the side chain, check if it already stored.
 		for is := range sideChain {
 			// Check for the block hash in the DB.
-			sideHeightDB, err := pgb.BlockHeight(sideChain[is])
+			sideHeightDB, err := pgb.BlockHeight(pgb.ctx, sideChain[is])
 			if err == sql.ErrNoRows {
 				// This block is NOT already in the DB.
 				blocksToStore[it].Hashes = append(blocksToStore[it].Hashes, sideChain[is])
@@ -405,6 +474,7 @@ type TicketTxnIDGetter struct {
 	mtx     sync.RWMutex
 	idCache map[string]uint64
 	db      *sql.DB
+	stats   cacheStats
 }
 
 // TxnDbID fetches DB row ID for the ticket specified by the input transaction
@@ -419,6 +489,7 @@ func (t *TicketTxnIDGetter) TxnDbID(txid string, expire bool) (uint64, error) {
 	dbID, ok := t.idCache[txid]
 	t.mtx.RUnlock()
 	if ok {
+		t.stats.hit()
 		if expire {
 			t.mtx.Lock()
 			delete(t.idCache, txid)
@@ -427,6 +498,7 @@ func (t *TicketTxnIDGetter) TxnDbID(txid string, expire bool) (uint64, error) {
 		return dbID, nil
 	}
 	// Cache miss. Get the row id by hash from the tickets table.
+	t.stats.miss()
 	log.Tracef("Cache miss for %s.", txid)
 	return RetrieveTicketIDByHashNoCancel(t.db, txid)
 }
@@ -439,6 +511,7 @@ func (t *TicketTxnIDGetter) Set(txid string, txDbID uint64) {
 	t.mtx.Lock()
 	defer t.mtx.Unlock()
 	t.idCache[txid] = txDbID
+	t.stats.touch()
 }
 
 // SetN stores several (transaction hash, DB row ID) pairs in the map.
@@ -451,6 +524,7 @@ func (t *TicketTxnIDGetter) SetN(txid []string, txDbID []uint64) {
 	for i := range txid {
 		t.idCache[txid[i]] = txDbID[i]
 	}
+	t.stats.touch()
 }
 
 // NewTicketTxnIDGetter constructs a new TicketTxnIDGetter with an empty cache.
@@ -458,6 +532,7 @@ func NewTicketTxnIDGetter(db *sql.DB) *TicketTxnIDGetter {
 	return &TicketTxnIDGetter{
 		db:      db,
 		idCache: make(map[string]uint64),
+		stats:   cacheStats{updated: time.Now()},
 	}
 }
 
@@ -473,6 +548,21 @@ type ChainDBCfg struct {
 	DevPrefetch, HidePGConfig         bool
 	AddrCacheRowCap, AddrCacheAddrCap int
 	AddrCacheUTXOByteCap              int
+	// HeightNtfnBuffer is the capacity of the channels returned by
+	// UpdateChan. A value of 0 uses DefaultHeightNtfnBuffer.
+	HeightNtfnBuffer int
+	// NotifyChannel is the PostgreSQL channel on which chain events are sent
+	// with pg_notify. Notifications are disabled if it is empty.
+	NotifyChannel string
+	// ArchiveDepth enables the archival of the address and vout rows of
+	// outputs spent more than ArchiveDepth blocks below the best block, every
+	// ArchiveInterval blocks. Archival is disabled if ArchiveDepth is 0, and is
+	// not supported by CockroachDB. See ArchiveSpent.
+	ArchiveDepth, ArchiveInterval int64
+	// AnomalyRules are the rules for flagging unusual main chain blocks and
+	// deep reorgs, which are recorded in the block_anomalies table. No
+	// anomalies are recorded if it is nil.
+	AnomalyRules *dbtypes.BlockAnomalyRules
 }
 
 // NewChainDB constructs a ChainDB for the given connection and Decred network
@@ -608,7 +698,7 @@ func NewChainDBWithCancel(ctx context.Context, cfg *ChainDBCfg, stakeDB *stakedb
 		}
 		// Do upgrades required by meta table versioning.
 		log.Infof("DB schema version %v upgrading to version %v", dbVer, targetDatabaseVersion)
-		upgrader := NewUpgrader(ctx, db, client, stakeDB)
+		upgrader := NewUpgrader(ctx, db, client, stakeDB, params)
 		success, err := upgrader.UpgradeDatabase()
 		if err != nil {
 			return nil, fmt.Errorf("failed to upgrade database: %v", err)
@@ -758,8 +848,13 @@ func NewChainDBWithCancel(ctx context.Context, cfg *ChainDBCfg, stakeDB *stakedb
 	log.Infof("Setting PostgreSQL DB statement timeout to %v.", queryTimeout)
 
 	bestBlock := &BestBlock{
-		height: bestHeight,
-		hash:   bestHash,
+		height:  bestHeight,
+		hashStr: bestHash,
+	}
+	if bestHash != "" {
+		if err = chainhash.Decode(&bestBlock.hash, bestHash); err != nil {
+			return nil, fmt.Errorf("invalid best block hash %q: %v", bestHash, err)
+		}
 	}
 
 	// Create the address cache with the given capacity. The project fund
@@ -768,6 +863,11 @@ func NewChainDBWithCancel(ctx context.Context, cfg *ChainDBCfg, stakeDB *stakedb
 		cfg.AddrCacheUTXOByteCap)
 	addrCache.ProjectAddress = projectFundAddress
 
+	heightNtfnBuffer := cfg.HeightNtfnBuffer
+	if heightNtfnBuffer == 0 {
+		heightNtfnBuffer = DefaultHeightNtfnBuffer
+	}
+
 	chainDB := &ChainDB{
 		ctx:                ctx,
 		queryTimeout:       queryTimeout,
@@ -777,10 +877,11 @@ func NewChainDBWithCancel(ctx context.Context, cfg *ChainDBCfg, stakeDB *stakedb
 		devAddress:         projectFundAddress,
 		dupChecks:          true,
 		bestBlock:          bestBlock,
-		lastBlock:          make(map[chainhash.Hash]uint64),
+		lastBlock:          newBlockIDCache(blockIDCacheSize),
 		stakeDB:            stakeDB,
 		unspentTicketCache: unspentTicketCache,
 		AddressCache:       addrCache,
+		devFundStats:       cacheStats{updated: time.Now()},
 		CacheLocks:         cacheLocks{cache.NewCacheLock(), cache.NewCacheLock(), cache.NewCacheLock(), cache.NewCacheLock()},
 		devPrefetch:        cfg.DevPrefetch,
 		tpUpdatePermission: tpUpdatePermissions,
@@ -791,12 +892,23 @@ func NewChainDBWithCancel(ctx context.Context, cfg *ChainDBCfg, stakeDB *stakedb
 		cockroach:          cockroach,
 		MPC:                new(mempool.MempoolDataCache),
 		BlockCache:         apitypes.NewAPICache(1e4),
-		heightClients:      make([]chan uint32, 0),
+		heightClients:      make([]*heightNotifier, 0),
+		heightNtfnBuffer:   heightNtfnBuffer,
+		notifyChannel:      cfg.NotifyChannel,
+		anomalyRules:       cfg.AnomalyRules,
 		shutdownDcrdata:    shutdown,
 		Client:             client,
 	}
 	chainDB.lastExplorerBlock.difficulties = make(map[int64]float64)
 
+	if cfg.ArchiveDepth > 0 {
+		if cockroach {
+			log.Warnf("Spent row archival is not supported by CockroachDB.")
+		} else if err = chainDB.enableArchive(cfg.ArchiveDepth, cfg.ArchiveInterval); err != nil {
+			return nil, err
+		}
+	}
+
 	// Update the current chain state in the ChainDB
 	if client != nil {
 		bci, err := chainDB.BlockchainInfo()
@@ -822,7 +934,7 @@ func NewChainDBWithCancel(ctx context.Context, cfg *ChainDBCfg, stakeDB *stakedb
 			netName:         params.Name,
 			currencyNet:     uint32(params.Net),
 			bestBlockHeight: bestBlock.height,
-			bestBlockHash:   bestBlock.hash,
+			bestBlockHash:   bestBlock.hashStr,
 			dbVer:           *legacyDatabaseVersion,
 			ibdComplete:     true, // We don't know this, but assume it is done.
 		})
@@ -832,7 +944,7 @@ func NewChainDBWithCancel(ctx context.Context, cfg *ChainDBCfg, stakeDB *stakedb
 
 		// Now run any upgrades from legacyDatabaseVersion to
 		// targetDatabaseVersion.
-		upgrader := NewUpgrader(ctx, db, client, stakeDB)
+		upgrader := NewUpgrader(ctx, db, client, stakeDB, params)
 		success, err := upgrader.UpgradeDatabase()
 		if err != nil {
 			return chainDB, fmt.Errorf("failed to upgrade legacy database: %v", err)
@@ -841,9 +953,98 @@ func NewChainDBWithCancel(ctx context.Context, cfg *ChainDBCfg, stakeDB *stakedb
 		}
 	}
 
+	// Set the final state of any blocks stored before it was recorded.
+	if client != nil {
+		go chainDB.backfillFinalState()
+	}
+
 	return chainDB, nil
 }
 
+// backfillFinalState sets the final state of the blocks stored before the
+// final_state column was added, from the block headers retrieved from dcrd. It
+// runs in the background, since there is a dcrd call per block, and resumes on
+// the next startup if it is interrupted. Side chain blocks that dcrd does not
+// know are left without a final state.
+func (pgb *ChainDB) backfillFinalState() {
+	const batchSize = 1000
+	var lastID, numSet, numMissing int64
+	lastReport := time.Now()
+	for {
+		rows, err := pgb.db.QueryContext(pgb.ctx, internal.SelectBlocksWithoutFinalState,
+			lastID, batchSize)
+		if err != nil {
+			if pgb.ctx.Err() == nil {
+				log.Errorf("Unable to select blocks without a final state: %v", err)
+			}
+			return
+		}
+		var ids []int64
+		var hashes []string
+		for rows.Next() {
+			var id int64
+			var hash string
+			if err = rows.Scan(&id, &hash); err != nil {
+				break
+			}
+			ids = append(ids, id)
+			hashes = append(hashes, hash)
+		}
+		if err == nil {
+			err = rows.Err()
+		}
+		closeRows(rows)
+		if err != nil {
+			log.Errorf("Unable to scan blocks without a final state: %v", err)
+			return
+		}
+		if len(ids) == 0 {
+			break
+		}
+		if lastID == 0 {
+			log.Infof("Setting the final state of the blocks stored before it was recorded...")
+		}
+
+		for i, hash := range hashes {
+			if pgb.ctx.Err() != nil {
+				return
+			}
+			header, err := pgb.GetBlockHeaderByHash(hash)
+			if isRPCConnectionError(err) {
+				log.Warnf("Stopped setting the final state of the blocks: %v", err)
+				return
+			}
+			if err != nil {
+				log.Debugf("Unable to get header for block %s: %v", hash, err)
+				numMissing++
+				continue
+			}
+			_, err = pgb.db.ExecContext(pgb.ctx, internal.UpdateBlockFinalState,
+				ids[i], hex.EncodeToString(header.FinalState[:]))
+			if err != nil {
+				if pgb.ctx.Err() == nil {
+					log.Errorf("Unable to set the final state of block %s: %v", hash, err)
+				}
+				return
+			}
+			numSet++
+		}
+		lastID = ids[len(ids)-1]
+
+		if time.Since(lastReport) > 30*time.Second {
+			lastReport = time.Now()
+			log.Infof("Set the final state of %d blocks.", numSet)
+		}
+	}
+
+	if numSet > 0 {
+		log.Infof("Set the final state of %d blocks.", numSet)
+	}
+	if numMissing > 0 {
+		log.Warnf("The final state of %d blocks unknown to dcrd was not set.", numMissing)
+	}
+}
+
 // StartPiparserHandler controls how piparser update handler will be initiated.
 // This handler should to be run once only when the first sync after startup completes.
 func (pgb *ChainDB) StartPiparserHandler() {
@@ -857,9 +1058,10 @@ func (pgb *ChainDB) StartPiparserHandler() {
 	}
 }
 
-// Close closes the underlying sql.DB connection to the database.
+// Close closes the underlying sql.DB connection to the database after any
+// in-flight writes finish. See Shutdown to limit the wait.
 func (pgb *ChainDB) Close() error {
-	return pgb.db.Close()
+	return pgb.Shutdown(context.Background())
 }
 
 // SqlDB returns the underlying sql.DB, which should not be used directly unless
@@ -887,7 +1089,12 @@ func (pgb *ChainDB) UseMempoolChecker(mp rpcutils.MempoolAddressChecker) {
 }
 
 // EnableDuplicateCheckOnInsert specifies whether SQL insertions should check
-// for row conflicts (duplicates), and avoid adding or updating.
+// for row conflicts (duplicates), and avoid adding or updating. With checks
+// enabled, the vins, vouts, transactions, tickets and addresses inserts use
+// native ON CONFLICT statements. Checks are only disabled for a large bulk
+// load, when DeindexAll has dropped the unique indexes for speed. ON CONFLICT
+// requires a unique index on the conflict target, so plain INSERT statements
+// are used until the indexes are rebuilt.
 func (pgb *ChainDB) EnableDuplicateCheckOnInsert(dupCheck bool) {
 	if pgb == nil {
 		return
@@ -949,32 +1156,60 @@ func (pgb *ChainDB) DropTables() {
 }
 
 // SideChainBlocks retrieves all known side chain blocks.
-func (pgb *ChainDB) SideChainBlocks() ([]*dbtypes.BlockStatus, error) {
-	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+func (pgb *ChainDB) SideChainBlocks(ctx context.Context) ([]*dbtypes.BlockStatus, error) {
+	ctx, cancel := pgb.queryContext(ctx)
 	defer cancel()
 	scb, err := RetrieveSideChainBlocks(ctx, pgb.db)
 	return scb, pgb.replaceCancelError(err)
 }
 
 // SideChainTips retrieves the tip/head block for all known side chains.
-func (pgb *ChainDB) SideChainTips() ([]*dbtypes.BlockStatus, error) {
-	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+func (pgb *ChainDB) SideChainTips(ctx context.Context) ([]*dbtypes.BlockStatus, error) {
+	ctx, cancel := pgb.queryContext(ctx)
 	defer cancel()
 	sct, err := RetrieveSideChainTips(ctx, pgb.db)
 	return sct, pgb.replaceCancelError(err)
 }
 
+// BlockChainWork retrieves the proof-of-work and cumulative chain work of the
+// specified block.
+func (pgb *ChainDB) BlockChainWork(ctx context.Context, hash string) (*dbtypes.BlockChainWork, error) {
+	ctx, cancel := pgb.queryContext(ctx)
+	defer cancel()
+	bcw, err := RetrieveBlockChainWork(ctx, pgb.db, hash)
+	return bcw, pgb.replaceCancelError(err)
+}
+
+// BlockStakeHeader retrieves the stake fields of the header of the block with
+// the given hash, which may be on a side chain.
+func (pgb *ChainDB) BlockStakeHeader(ctx context.Context, hash string) (*dbtypes.BlockStakeHeader, error) {
+	ctx, cancel := pgb.queryContext(ctx)
+	defer cancel()
+	bsh, err := RetrieveBlockStakeHeader(ctx, pgb.db, hash)
+	return bsh, pgb.replaceCancelError(err)
+}
+
+// ChainTipsChainWork retrieves the proof-of-work and cumulative chain work of
+// the main chain tip and all known side chain tips, with the most work first.
+// This allows competing branches to be compared during a reorganization.
+func (pgb *ChainDB) ChainTipsChainWork(ctx context.Context) ([]*dbtypes.BlockChainWork, error) {
+	ctx, cancel := pgb.queryContext(ctx)
+	defer cancel()
+	tips, err := RetrieveChainTipsChainWork(ctx, pgb.db)
+	return tips, pgb.replaceCancelError(err)
+}
+
 // DisapprovedBlocks retrieves all blocks disapproved by stakeholder votes.
-func (pgb *ChainDB) DisapprovedBlocks() ([]*dbtypes.BlockStatus, error) {
-	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+func (pgb *ChainDB) DisapprovedBlocks(ctx context.Context) ([]*dbtypes.BlockStatus, error) {
+	ctx, cancel := pgb.queryContext(ctx)
 	defer cancel()
 	disb, err := RetrieveDisapprovedBlocks(ctx, pgb.db)
 	return disb, pgb.replaceCancelError(err)
 }
 
 // BlockStatus retrieves the block chain status of the specified block.
-func (pgb *ChainDB) BlockStatus(hash string) (dbtypes.BlockStatus, error) {
-	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+func (pgb *ChainDB) BlockStatus(ctx context.Context, hash string) (dbtypes.BlockStatus, error) {
+	ctx, cancel := pgb.queryContext(ctx)
 	defer cancel()
 	bs, err := RetrieveBlockStatus(ctx, pgb.db, hash)
 	return bs, pgb.replaceCancelError(err)
@@ -987,8 +1222,8 @@ func (pgb *ChainDB) blockFlags(ctx context.Context, hash string) (bool, bool, er
 }
 
 // BlockFlags retrieves the block's isValid and isMainchain flags.
-func (pgb *ChainDB) BlockFlags(hash string) (bool, bool, error) {
-	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+func (pgb *ChainDB) BlockFlags(ctx context.Context, hash string) (bool, bool, error) {
+	ctx, cancel := pgb.queryContext(ctx)
 	defer cancel()
 	return pgb.blockFlags(ctx, hash)
 }
@@ -1045,6 +1280,12 @@ func (pgb *ChainDB) RegisterCharts(charts *cache.ChartData) {
 		Appender: appendMissedVotesPerWindow,
 	})
 
+	charts.AddUpdater(cache.ChartUpdater{
+		Tag:      "ticket fee rates",
+		Fetcher:  pgb.ticketFeeRates,
+		Appender: appendTicketFeeRatesPerWindow,
+	})
+
 	charts.AddUpdater(cache.ChartUpdater{
 		Tag:      "fees",
 		Fetcher:  pgb.blockFees,
@@ -1063,18 +1304,36 @@ func (pgb *ChainDB) RegisterCharts(charts *cache.ChartData) {
 		Appender: pgb.appendAnonymitySet, // ChainDB's method since it caches data.
 	})
 
+	charts.AddUpdater(cache.ChartUpdater{
+		Tag:      "tx types",
+		Fetcher:  pgb.txTypes,
+		Appender: appendTxTypes,
+	})
+
 	charts.AddUpdater(cache.ChartUpdater{
 		Tag:      "pool stats",
 		Fetcher:  pgb.poolStats,
 		Appender: appendPoolStats,
 	})
+
+	charts.AddUpdater(cache.ChartUpdater{
+		Tag:      "mempool backlog",
+		Fetcher:  pgb.mempoolBacklog,
+		Appender: appendMempoolBacklog,
+	})
+
+	charts.AddUpdater(cache.ChartUpdater{
+		Tag:      "ticket share",
+		Fetcher:  pgb.liveTicketChanges,
+		Appender: appendLiveTicketChanges,
+	})
 }
 
 // TransactionBlocks retrieves the blocks in which the specified transaction
 // appears, along with the index of the transaction in each of the blocks. The
 // next and previous block hashes are NOT SET in each BlockStatus.
-func (pgb *ChainDB) TransactionBlocks(txHash string) ([]*dbtypes.BlockStatus, []uint32, error) {
-	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+func (pgb *ChainDB) TransactionBlocks(ctx context.Context, txHash string) ([]*dbtypes.BlockStatus, []uint32, error) {
+	ctx, cancel := pgb.queryContext(ctx)
 	defer cancel()
 	hashes, heights, inds, valids, mainchains, err := RetrieveTxnsBlocks(ctx, pgb.db, txHash)
 	if err != nil {
@@ -1096,17 +1355,57 @@ func (pgb *ChainDB) TransactionBlocks(txHash string) ([]*dbtypes.BlockStatus, []
 	return blocks, inds, nil
 }
 
+// DuplicateTransactions retrieves up to N transactions, skipping offset, that
+// appear in more than one block, such as in both a side chain and the main
+// chain. Each of the blocks is listed with its chain status.
+func (pgb *ChainDB) DuplicateTransactions(ctx context.Context, N, offset int64) ([]*apitypes.TxDuplicate, error) {
+	ctx, cancel := pgb.queryContext(ctx)
+	defer cancel()
+	txHashes, err := retrieveDuplicateTxns(ctx, pgb.db, N, offset)
+	if err != nil {
+		return nil, pgb.replaceCancelError(err)
+	}
+
+	txns := make([]*apitypes.TxDuplicate, 0, len(txHashes))
+	for _, txHash := range txHashes {
+		hashes, heights, inds, valids, mainchains, err := RetrieveTxnsBlocks(ctx, pgb.db, txHash)
+		if err != nil {
+			return nil, pgb.replaceCancelError(err)
+		}
+
+		dup := &apitypes.TxDuplicate{
+			TxID:   txHash,
+			Blocks: make([]*apitypes.TxBlock, len(hashes)),
+		}
+		for i := range hashes {
+			status := apitypes.TxBlockStatus(mainchains[i], valids[i])
+			if status == apitypes.TxBlockMainchain {
+				dup.Mainchain = true
+			}
+			dup.Blocks[i] = &apitypes.TxBlock{
+				BlockHash:   hashes[i],
+				BlockHeight: int64(heights[i]),
+				BlockIndex:  inds[i],
+				Status:      status,
+			}
+		}
+		txns = append(txns, dup)
+	}
+
+	return txns, nil
+}
+
 // HeightDB retrieves the best block height according to the meta table.
-func (pgb *ChainDB) HeightDB() (int64, error) {
-	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+func (pgb *ChainDB) HeightDB(ctx context.Context) (int64, error) {
+	ctx, cancel := pgb.queryContext(ctx)
 	defer cancel()
 	_, height, err := DBBestBlock(ctx, pgb.db)
 	return height, pgb.replaceCancelError(err)
 }
 
 // HashDB retrieves the best block hash according to the meta table.
-func (pgb *ChainDB) HashDB() (string, error) {
-	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+func (pgb *ChainDB) HashDB(ctx context.Context) (string, error) {
+	ctx, cancel := pgb.queryContext(ctx)
 	defer cancel()
 	hash, _, err := DBBestBlock(ctx, pgb.db)
 	return hash, pgb.replaceCancelError(err)
@@ -1114,8 +1413,8 @@ func (pgb *ChainDB) HashDB() (string, error) {
 
 // HeightHashDB retrieves the best block height and hash according to the meta
 // table.
-func (pgb *ChainDB) HeightHashDB() (int64, string, error) {
-	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+func (pgb *ChainDB) HeightHashDB(ctx context.Context) (int64, string, error) {
+	ctx, cancel := pgb.queryContext(ctx)
 	defer cancel()
 	hash, height, err := DBBestBlock(ctx, pgb.db)
 	return height, hash, pgb.replaceCancelError(err)
@@ -1123,8 +1422,8 @@ func (pgb *ChainDB) HeightHashDB() (int64, string, error) {
 
 // HeightDBLegacy queries the blocks table for the best block height. When the
 // tables are empty, the returned height will be -1.
-func (pgb *ChainDB) HeightDBLegacy() (int64, error) {
-	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+func (pgb *ChainDB) HeightDBLegacy(ctx context.Context) (int64, error) {
+	ctx, cancel := pgb.queryContext(ctx)
 	defer cancel()
 	bestHeight, _, _, err := RetrieveBestBlockHeight(ctx, pgb.db)
 	height := int64(bestHeight)
@@ -1135,8 +1434,8 @@ func (pgb *ChainDB) HeightDBLegacy() (int64, error) {
 }
 
 // HashDBLegacy queries the blocks table for the best block's hash.
-func (pgb *ChainDB) HashDBLegacy() (string, error) {
-	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+func (pgb *ChainDB) HashDBLegacy(ctx context.Context) (string, error) {
+	ctx, cancel := pgb.queryContext(ctx)
 	defer cancel()
 	_, bestHash, _, err := RetrieveBestBlockHeight(ctx, pgb.db)
 	return bestHash, pgb.replaceCancelError(err)
@@ -1144,8 +1443,8 @@ func (pgb *ChainDB) HashDBLegacy() (string, error) {
 
 // HeightHashDBLegacy queries the blocks table for the best block's height and
 // hash.
-func (pgb *ChainDB) HeightHashDBLegacy() (uint64, string, error) {
-	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+func (pgb *ChainDB) HeightHashDBLegacy(ctx context.Context) (uint64, string, error) {
+	ctx, cancel := pgb.queryContext(ctx)
 	defer cancel()
 	height, hash, _, err := RetrieveBestBlockHeight(ctx, pgb.db)
 	return height, hash, pgb.replaceCancelError(err)
@@ -1179,27 +1478,28 @@ func (pgb *ChainDB) GetBestBlockHash() (string, error) {
 func (block *BestBlock) HashStr() string {
 	block.mtx.RLock()
 	defer block.mtx.RUnlock()
-	return block.hash
+	return block.hashStr
 }
 
-// Hash uses the last stored block hash.
+// Hash uses the last stored block hash. The returned hash is a copy.
 func (block *BestBlock) Hash() *chainhash.Hash {
-	// Caller should check hash instead of error
-	hash, _ := chainhash.NewHashFromStr(block.HashStr())
-	return hash
+	block.mtx.RLock()
+	hash := block.hash
+	block.mtx.RUnlock()
+	return &hash
 }
 
 func (pgb *ChainDB) BestBlock() (*chainhash.Hash, int64) {
 	pgb.bestBlock.mtx.RLock()
-	defer pgb.bestBlock.mtx.RUnlock()
-	hash, _ := chainhash.NewHashFromStr(pgb.bestBlock.hash)
-	return hash, pgb.bestBlock.height
+	hash, height := pgb.bestBlock.hash, pgb.bestBlock.height
+	pgb.bestBlock.mtx.RUnlock()
+	return &hash, height
 }
 
 func (pgb *ChainDB) BestBlockStr() (string, int64) {
 	pgb.bestBlock.mtx.RLock()
 	defer pgb.bestBlock.mtx.RUnlock()
-	return pgb.bestBlock.hash, pgb.bestBlock.height
+	return pgb.bestBlock.hashStr, pgb.bestBlock.height
 }
 
 // BestBlockHash is a getter for ChainDB.bestBlock.hash.
@@ -1213,8 +1513,8 @@ func (pgb *ChainDB) BestBlockHashStr() string {
 }
 
 // BlockHeight queries the DB for the height of the specified hash.
-func (pgb *ChainDB) BlockHeight(hash string) (int64, error) {
-	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+func (pgb *ChainDB) BlockHeight(ctx context.Context, hash string) (int64, error) {
+	ctx, cancel := pgb.queryContext(ctx)
 	defer cancel()
 	height, err := RetrieveBlockHeight(ctx, pgb.db, hash)
 	return height, pgb.replaceCancelError(err)
@@ -1222,8 +1522,8 @@ func (pgb *ChainDB) BlockHeight(hash string) (int64, error) {
 
 // BlockHash queries the DB for the hash of the mainchain block at the given
 // height.
-func (pgb *ChainDB) BlockHash(height int64) (string, error) {
-	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+func (pgb *ChainDB) BlockHash(ctx context.Context, height int64) (string, error) {
+	ctx, cancel := pgb.queryContext(ctx)
 	defer cancel()
 	hash, err := RetrieveBlockHash(ctx, pgb.db, height)
 	return hash, pgb.replaceCancelError(err)
@@ -1231,17 +1531,26 @@ func (pgb *ChainDB) BlockHash(height int64) (string, error) {
 
 // BlockTimeByHeight queries the DB for the time of the mainchain block at the
 // given height.
-func (pgb *ChainDB) BlockTimeByHeight(height int64) (int64, error) {
-	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+func (pgb *ChainDB) BlockTimeByHeight(ctx context.Context, height int64) (int64, error) {
+	ctx, cancel := pgb.queryContext(ctx)
 	defer cancel()
 	time, err := RetrieveBlockTimeByHeight(ctx, pgb.db, height)
 	return time.UNIX(), pgb.replaceCancelError(err)
 }
 
+// BlockTimesByHeights retrieves the times of the main chain blocks at the
+// given heights, by height. Heights without a main chain block are omitted.
+func (pgb *ChainDB) BlockTimesByHeights(ctx context.Context, heights []int64) (map[int64]int64, error) {
+	ctx, cancel := pgb.queryContext(ctx)
+	defer cancel()
+	times, err := retrieveBlockTimesByHeights(ctx, pgb.db, heights)
+	return times, pgb.replaceCancelError(err)
+}
+
 // VotesInBlock returns the number of votes mined in the block with the
 // specified hash.
-func (pgb *ChainDB) VotesInBlock(hash string) (int16, error) {
-	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+func (pgb *ChainDB) VotesInBlock(ctx context.Context, hash string) (int16, error) {
+	ctx, cancel := pgb.queryContext(ctx)
 	defer cancel()
 	voters, err := RetrieveBlockVoteCount(ctx, pgb.db, hash)
 	if err != nil {
@@ -1364,8 +1673,8 @@ func (pgb *ChainDB) PiProposalsHistory() (int64, error) {
 }
 
 // ProposalVotes retrieves all the votes data associated with the provided token.
-func (pgb *ChainDB) ProposalVotes(proposalToken string) (*dbtypes.ProposalChartsData, error) {
-	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+func (pgb *ChainDB) ProposalVotes(ctx context.Context, proposalToken string) (*dbtypes.ProposalChartsData, error) {
+	ctx, cancel := pgb.queryContext(ctx)
 	defer cancel()
 	chartsData, err := retrieveProposalVotesData(ctx, pgb.db, proposalToken)
 	return chartsData, pgb.replaceCancelError(err)
@@ -1375,8 +1684,8 @@ func (pgb *ChainDB) ProposalVotes(proposalToken string) (*dbtypes.ProposalCharts
 // specified funding transaction. The spending transaction hashes, the spending
 // tx input indexes, and the corresponding funding tx output indexes, and an
 // error value are returned.
-func (pgb *ChainDB) SpendingTransactions(fundingTxID string) ([]string, []uint32, []uint32, error) {
-	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+func (pgb *ChainDB) SpendingTransactions(ctx context.Context, fundingTxID string) ([]string, []uint32, []uint32, error) {
+	ctx, cancel := pgb.queryContext(ctx)
 	defer cancel()
 	_, spendingTxns, vinInds, voutInds, err := RetrieveSpendingTxsByFundingTx(ctx, pgb.db, fundingTxID)
 	return spendingTxns, vinInds, voutInds, pgb.replaceCancelError(err)
@@ -1385,9 +1694,9 @@ func (pgb *ChainDB) SpendingTransactions(fundingTxID string) ([]string, []uint32
 // SpendingTransaction returns the transaction that spends the specified
 // transaction outpoint, if it is spent. The spending transaction hash, input
 // index, tx tree, and an error value are returned.
-func (pgb *ChainDB) SpendingTransaction(fundingTxID string,
+func (pgb *ChainDB) SpendingTransaction(ctx context.Context, fundingTxID string,
 	fundingTxVout uint32) (string, uint32, int8, error) {
-	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	ctx, cancel := pgb.queryContext(ctx)
 	defer cancel()
 	_, spendingTx, vinInd, tree, err := RetrieveSpendingTxByTxOut(ctx, pgb.db, fundingTxID, fundingTxVout)
 	return spendingTx, vinInd, tree, pgb.replaceCancelError(err)
@@ -1395,8 +1704,8 @@ func (pgb *ChainDB) SpendingTransaction(fundingTxID string,
 
 // BlockTransactions retrieves all transactions in the specified block, their
 // indexes in the block, their tree, and an error value.
-func (pgb *ChainDB) BlockTransactions(blockHash string) ([]string, []uint32, []int8, error) {
-	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+func (pgb *ChainDB) BlockTransactions(ctx context.Context, blockHash string) ([]string, []uint32, []int8, error) {
+	ctx, cancel := pgb.queryContext(ctx)
 	defer cancel()
 	_, blockTransactions, blockInds, trees, _, err := RetrieveTxsByBlockHash(ctx, pgb.db, blockHash)
 	return blockTransactions, blockInds, trees, pgb.replaceCancelError(err)
@@ -1404,8 +1713,8 @@ func (pgb *ChainDB) BlockTransactions(blockHash string) ([]string, []uint32, []i
 
 // Transaction retrieves all rows from the transactions table for the given
 // transaction hash.
-func (pgb *ChainDB) Transaction(txHash string) ([]*dbtypes.Tx, error) {
-	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+func (pgb *ChainDB) Transaction(ctx context.Context, txHash string) ([]*dbtypes.Tx, error) {
+	ctx, cancel := pgb.queryContext(ctx)
 	defer cancel()
 	_, dbTxs, err := RetrieveDbTxsByHash(ctx, pgb.db, txHash)
 	return dbTxs, pgb.replaceCancelError(err)
@@ -1413,8 +1722,8 @@ func (pgb *ChainDB) Transaction(txHash string) ([]*dbtypes.Tx, error) {
 
 // BlockMissedVotes retrieves the ticket IDs for all missed votes in the
 // specified block, and an error value.
-func (pgb *ChainDB) BlockMissedVotes(blockHash string) ([]string, error) {
-	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+func (pgb *ChainDB) BlockMissedVotes(ctx context.Context, blockHash string) ([]string, error) {
+	ctx, cancel := pgb.queryContext(ctx)
 	defer cancel()
 	mv, err := RetrieveMissedVotesInBlock(ctx, pgb.db, blockHash)
 	return mv, pgb.replaceCancelError(err)
@@ -1424,8 +1733,8 @@ func (pgb *ChainDB) BlockMissedVotes(blockHash string) ([]string, error) {
 // vote but failed to do so (miss). There may be multiple since this consideres
 // side chain blocks. See TicketMiss for a mainchain-only version. If the ticket
 // never missed a vote, the returned error will be sql.ErrNoRows.
-func (pgb *ChainDB) TicketMisses(ticketHash string) ([]string, []int64, error) {
-	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+func (pgb *ChainDB) TicketMisses(ctx context.Context, ticketHash string) ([]string, []int64, error) {
+	ctx, cancel := pgb.queryContext(ctx)
 	defer cancel()
 	blockHashes, blockHeights, err := RetrieveMissesForTicket(ctx, pgb.db, ticketHash)
 	return blockHashes, blockHeights, pgb.replaceCancelError(err)
@@ -1434,8 +1743,8 @@ func (pgb *ChainDB) TicketMisses(ticketHash string) ([]string, []int64, error) {
 // TicketMiss retrieves the mainchain block in which the specified ticket was
 // called to vote but failed to do so (miss). If the ticket never missed a vote,
 // the returned error will be sql.ErrNoRows.
-func (pgb *ChainDB) TicketMiss(ticketHash string) (string, int64, error) {
-	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+func (pgb *ChainDB) TicketMiss(ctx context.Context, ticketHash string) (string, int64, error) {
+	ctx, cancel := pgb.queryContext(ctx)
 	defer cancel()
 	blockHash, blockHeight, err := RetrieveMissForTicket(ctx, pgb.db, ticketHash)
 	return blockHash, blockHeight, pgb.replaceCancelError(err)
@@ -1443,16 +1752,16 @@ func (pgb *ChainDB) TicketMiss(ticketHash string) (string, int64, error) {
 
 // PoolStatusForTicket retrieves the specified ticket's spend status and ticket
 // pool status, and an error value.
-func (pgb *ChainDB) PoolStatusForTicket(txid string) (dbtypes.TicketSpendType, dbtypes.TicketPoolStatus, error) {
-	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+func (pgb *ChainDB) PoolStatusForTicket(ctx context.Context, txid string) (dbtypes.TicketSpendType, dbtypes.TicketPoolStatus, error) {
+	ctx, cancel := pgb.queryContext(ctx)
 	defer cancel()
 	_, spendType, poolStatus, err := RetrieveTicketStatusByHash(ctx, pgb.db, txid)
 	return spendType, poolStatus, pgb.replaceCancelError(err)
 }
 
 // VoutValue retrieves the value of the specified transaction outpoint in atoms.
-func (pgb *ChainDB) VoutValue(txID string, vout uint32) (uint64, error) {
-	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+func (pgb *ChainDB) VoutValue(ctx context.Context, txID string, vout uint32) (uint64, error) {
+	ctx, cancel := pgb.queryContext(ctx)
 	defer cancel()
 	voutValue, err := RetrieveVoutValue(ctx, pgb.db, txID, vout)
 	if err != nil {
@@ -1464,8 +1773,8 @@ func (pgb *ChainDB) VoutValue(txID string, vout uint32) (uint64, error) {
 // VoutValues retrieves the values of each outpoint of the specified
 // transaction. The corresponding indexes in the block and tx trees of the
 // outpoints, and an error value are also returned.
-func (pgb *ChainDB) VoutValues(txID string) ([]uint64, []uint32, []int8, error) {
-	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+func (pgb *ChainDB) VoutValues(ctx context.Context, txID string) ([]uint64, []uint32, []int8, error) {
+	ctx, cancel := pgb.queryContext(ctx)
 	defer cancel()
 	voutValues, txInds, txTrees, err := RetrieveVoutValues(ctx, pgb.db, txID)
 	if err != nil {
@@ -1477,8 +1786,8 @@ func (pgb *ChainDB) VoutValues(txID string) ([]uint64, []uint32, []int8, error)
 // TransactionBlock retrieves the hash of the block containing the specified
 // transaction. The index of the transaction within the block, the transaction
 // index, and an error value are also returned.
-func (pgb *ChainDB) TransactionBlock(txID string) (string, uint32, int8, error) {
-	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+func (pgb *ChainDB) TransactionBlock(ctx context.Context, txID string) (string, uint32, int8, error) {
+	ctx, cancel := pgb.queryContext(ctx)
 	defer cancel()
 	_, blockHash, blockInd, tree, err := RetrieveTxByHash(ctx, pgb.db, txID)
 	return blockHash, blockInd, tree, pgb.replaceCancelError(err)
@@ -1486,8 +1795,8 @@ func (pgb *ChainDB) TransactionBlock(txID string) (string, uint32, int8, error)
 
 // AgendaVotes fetches the data used to plot a graph of votes cast per day per
 // choice for the provided agenda.
-func (pgb *ChainDB) AgendaVotes(agendaID string, chartType int) (*dbtypes.AgendaVoteChoices, error) {
-	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+func (pgb *ChainDB) AgendaVotes(ctx context.Context, agendaID string, chartType int) (*dbtypes.AgendaVoteChoices, error) {
+	ctx, cancel := pgb.queryContext(ctx)
 	defer cancel()
 
 	chainInfo := pgb.ChainInfo()
@@ -1505,8 +1814,8 @@ func (pgb *ChainDB) AgendaVotes(agendaID string, chartType int) (*dbtypes.Agenda
 
 // AgendasVotesSummary fetches the total vote choices count for the provided
 // agenda.
-func (pgb *ChainDB) AgendasVotesSummary(agendaID string) (summary *dbtypes.AgendaSummary, err error) {
-	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+func (pgb *ChainDB) AgendasVotesSummary(ctx context.Context, agendaID string) (summary *dbtypes.AgendaSummary, err error) {
+	ctx, cancel := pgb.queryContext(ctx)
 	defer cancel()
 
 	chainInfo := pgb.ChainInfo()
@@ -1528,8 +1837,8 @@ func (pgb *ChainDB) AgendasVotesSummary(agendaID string) (summary *dbtypes.Agend
 }
 
 // AgendaVoteCounts returns the vote counts for the agenda as builtin types.
-func (pgb *ChainDB) AgendaVoteCounts(agendaID string) (yes, abstain, no uint32, err error) {
-	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+func (pgb *ChainDB) AgendaVoteCounts(ctx context.Context, agendaID string) (yes, abstain, no uint32, err error) {
+	ctx, cancel := pgb.queryContext(ctx)
 	defer cancel()
 
 	chainInfo := pgb.ChainInfo()
@@ -1549,11 +1858,364 @@ func (pgb *ChainDB) AllAgendas() (map[string]dbtypes.MileStone, error) {
 	return retrieveAllAgendas(pgb.db)
 }
 
+// AgendaStatus returns the voting progress of the agenda as of the best block,
+// with estimates of when quorum will be reached and when the outcome will be
+// decided based on the vote rate so far in the current rule change interval.
+// sql.ErrNoRows is returned for an unknown agenda.
+func (pgb *ChainDB) AgendaStatus(ctx context.Context, agendaID string) (*apitypes.AgendaStatus, error) {
+	chainInfo := pgb.ChainInfo()
+	if chainInfo == nil {
+		return nil, fmt.Errorf("chain deployment data not available")
+	}
+	agendaInfo, ok := chainInfo.AgendaMileStones[agendaID]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+
+	var yes, abstain, no uint32
+	if agendaInfo.VotingStarted > 0 {
+		ctx, cancel := pgb.queryContext(ctx)
+		defer cancel()
+		var err error
+		yes, abstain, no, err = retrieveTotalAgendaVotesCount(ctx, pgb.db,
+			agendaID, agendaInfo.VotingStarted, agendaInfo.VotingDone)
+		if err != nil {
+			return nil, pgb.replaceCancelError(err)
+		}
+	}
+
+	return makeAgendaStatus(agendaID, agendaInfo, pgb.Height(), yes, abstain,
+		no, pgb.chainParams, time.Now()), nil
+}
+
+// AgendaVotesByTicketType returns the vote choices cast on the agenda in its
+// voting interval, broken down by the type of the voting tickets. The counts
+// are zero if voting has not started. sql.ErrNoRows is returned for an unknown
+// agenda.
+func (pgb *ChainDB) AgendaVotesByTicketType(ctx context.Context, agendaID string) (*apitypes.AgendaTicketTypeVotes, error) {
+	chainInfo := pgb.ChainInfo()
+	if chainInfo == nil {
+		return nil, fmt.Errorf("chain deployment data not available")
+	}
+	agendaInfo, ok := chainInfo.AgendaMileStones[agendaID]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+
+	votes := newTicketTypeVotes()
+	if agendaInfo.VotingStarted > 0 {
+		ctx, cancel := pgb.queryContext(ctx)
+		defer cancel()
+		var err error
+		votes, err = retrieveAgendaVotesByTicketType(ctx, pgb.db, agendaID,
+			agendaInfo.VotingStarted, agendaInfo.VotingDone)
+		if err != nil {
+			return nil, pgb.replaceCancelError(err)
+		}
+	}
+
+	return &apitypes.AgendaTicketTypeVotes{
+		ID:            agendaID,
+		VotingStarted: agendaInfo.VotingStarted,
+		VotingDone:    agendaInfo.VotingDone,
+		TicketTypes:   votes,
+	}, nil
+}
+
+// AgendaVoteTimeline returns the voting timeline of the agenda in its voting
+// interval, with the vote choices cast in each span of interval blocks. The
+// span defaults to a day's worth of blocks if interval is not positive, and is
+// at most a rule change interval. The timeline has no spans if voting has not
+// started. sql.ErrNoRows is returned for an unknown agenda.
+func (pgb *ChainDB) AgendaVoteTimeline(ctx context.Context, agendaID string, interval int64) (*apitypes.AgendaVoteTimeline, error) {
+	chainInfo := pgb.ChainInfo()
+	if chainInfo == nil {
+		return nil, fmt.Errorf("chain deployment data not available")
+	}
+	agendaInfo, ok := chainInfo.AgendaMileStones[agendaID]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+
+	if interval <= 0 {
+		interval = int64(24 * time.Hour / pgb.chainParams.TargetTimePerBlock)
+	}
+	if maxInterval := int64(pgb.chainParams.RuleChangeActivationInterval); interval > maxInterval {
+		interval = maxInterval
+	}
+
+	var intervals []*apitypes.AgendaVoteInterval
+	if agendaInfo.VotingStarted > 0 {
+		ctx, cancel := pgb.queryContext(ctx)
+		defer cancel()
+		var err error
+		intervals, err = retrieveAgendaVotesByInterval(ctx, pgb.db, agendaID,
+			agendaInfo.VotingStarted, agendaInfo.VotingDone, interval)
+		if err != nil {
+			return nil, pgb.replaceCancelError(err)
+		}
+	}
+
+	return makeAgendaVoteTimeline(agendaID, agendaInfo, interval, intervals,
+		pgb.chainParams), nil
+}
+
+// makeAgendaVoteTimeline computes the cumulative vote counts, percentages and
+// quorum progress of the spans of an agenda's voting timeline, which are in
+// height order.
+func makeAgendaVoteTimeline(agendaID string, agendaInfo dbtypes.MileStone, interval int64,
+	intervals []*apitypes.AgendaVoteInterval, params *chaincfg.Params) *apitypes.AgendaVoteTimeline {
+	timeline := &apitypes.AgendaVoteTimeline{
+		ID:            agendaID,
+		VotingStarted: agendaInfo.VotingStarted,
+		VotingDone:    agendaInfo.VotingDone,
+		Interval:      interval,
+		Quorum:        params.RuleChangeActivationQuorum,
+		PassThreshold: float64(params.RuleChangeActivationMultiplier) /
+			float64(params.RuleChangeActivationDivisor),
+		Intervals: intervals,
+	}
+	if timeline.Intervals == nil {
+		timeline.Intervals = []*apitypes.AgendaVoteInterval{}
+	}
+
+	var yes, abstain, no, total uint32
+	for _, avi := range timeline.Intervals {
+		yes += avi.Yes
+		abstain += avi.Abstain
+		no += avi.No
+		total += avi.Total
+		avi.CumYes, avi.CumAbstain, avi.CumNo, avi.CumTotal = yes, abstain, no, total
+
+		// Abstaining votes count toward neither quorum nor approval.
+		if counted := yes + no; counted > 0 {
+			avi.YesPercent = 100 * float64(yes) / float64(counted)
+			avi.NoPercent = 100 * float64(no) / float64(counted)
+		}
+		if total > 0 {
+			avi.AbstainPercent = 100 * float64(abstain) / float64(total)
+		}
+		if timeline.Quorum > 0 {
+			avi.QuorumProgress = 100 * float64(yes+no) / float64(timeline.Quorum)
+		}
+	}
+
+	return timeline
+}
+
+// makeAgendaStatus computes the voting progress of an agenda at the given best
+// block height from its vote counts in the current rule change interval.
+func makeAgendaStatus(agendaID string, agendaInfo dbtypes.MileStone, height int64,
+	yes, abstain, no uint32, params *chaincfg.Params, now time.Time) *apitypes.AgendaStatus {
+	status := &apitypes.AgendaStatus{
+		ID:            agendaID,
+		Status:        agendaInfo.Status.String(),
+		Height:        height,
+		VotingStarted: agendaInfo.VotingStarted,
+		VotingDone:    agendaInfo.VotingDone,
+		Activated:     agendaInfo.Activated,
+		Yes:           yes,
+		Abstain:       abstain,
+		No:            no,
+		Quorum:        params.RuleChangeActivationQuorum,
+		PassThreshold: float64(params.RuleChangeActivationMultiplier) /
+			float64(params.RuleChangeActivationDivisor),
+	}
+
+	// Abstaining votes count toward neither quorum nor approval.
+	counted := yes + no
+	status.QuorumReached = counted >= status.Quorum
+	if counted > 0 {
+		status.Approval = float64(yes) / float64(counted)
+	}
+
+	blocksFromNow := func(blocks int64) int64 {
+		return now.Add(time.Duration(blocks) * params.TargetTimePerBlock).Unix()
+	}
+
+	switch agendaInfo.Status {
+	case dbtypes.StartedAgendaStatus:
+		status.DecisionHeight = agendaInfo.VotingDone + 1
+		if elapsed := height - agendaInfo.VotingStarted + 1; elapsed > 0 {
+			status.VoteRate = float64(counted) / float64(elapsed)
+		}
+		if !status.QuorumReached && status.VoteRate > 0 {
+			blocks := int64(math.Ceil(float64(status.Quorum-counted) / status.VoteRate))
+			if height+blocks <= agendaInfo.VotingDone {
+				status.QuorumHeight = height + blocks
+				status.QuorumTime = blocksFromNow(blocks)
+			}
+		}
+	case dbtypes.LockedInAgendaStatus, dbtypes.ActivatedAgendaStatus:
+		status.DecisionHeight = agendaInfo.Activated
+	case dbtypes.FailedAgendaStatus:
+		status.DecisionHeight = agendaInfo.VotingDone + 1
+	}
+
+	if status.DecisionHeight > height {
+		status.BlocksToDecision = status.DecisionHeight - height
+		status.DecisionTime = blocksFromNow(status.BlocksToDecision)
+	}
+
+	return status
+}
+
+// voteVersionInterval is the number of mainchain votes of each version in a
+// stake version interval, which spans the heights [start, end].
+type voteVersionInterval struct {
+	index  int64
+	start  int64
+	end    int64
+	counts map[int32]int64
+}
+
+// versionMajority counts the votes with at least the given version, and checks
+// whether they are a majority of all the votes in the interval according to
+// the stake version upgrade rules of the network.
+func (vi *voteVersionInterval) versionMajority(version int32, params *chaincfg.Params) (count, total int64, majority bool) {
+	for v, n := range vi.counts {
+		total += n
+		if v >= version {
+			count += n
+		}
+	}
+	required := total * int64(params.StakeMajorityMultiplier) /
+		int64(params.StakeMajorityDivisor)
+	return count, total, total > 0 && count >= required
+}
+
+// voterMajorities finds the first of the stake version intervals, ordered by
+// height, in which each vote version had a majority of the votes. Only the
+// intervals that end at or below height are considered. The Time of each
+// returned threshold is not set.
+func voterMajorities(intervals []*voteVersionInterval, height int64, params *chaincfg.Params) []*apitypes.UpgradeThreshold {
+	var thresholds []*apitypes.UpgradeThreshold
+	reached := make(map[int32]bool)
+	for _, vi := range intervals {
+		if vi.end > height {
+			break
+		}
+		versions := make([]int32, 0, len(vi.counts))
+		for v := range vi.counts {
+			versions = append(versions, v)
+		}
+		sort.Slice(versions, func(i, j int) bool { return versions[i] < versions[j] })
+		for _, v := range versions {
+			if reached[v] {
+				continue
+			}
+			if _, _, majority := vi.versionMajority(v, params); !majority {
+				continue
+			}
+			reached[v] = true
+			thresholds = append(thresholds, &apitypes.UpgradeThreshold{
+				Kind:    apitypes.UpgradeVoterMajority,
+				Version: v,
+				Height:  vi.end,
+			})
+		}
+	}
+	return thresholds
+}
+
+// UpgradeProgress reports the adoption of the latest block version in the
+// upgrade window of the most recent blocks, and of the latest vote version in
+// the current stake version interval, relative to the upgrade rules of the
+// network. The timeline lists when each block and vote version crossed the
+// upgrade thresholds, ordered by height.
+func (pgb *ChainDB) UpgradeProgress(ctx context.Context) (*apitypes.UpgradeProgress, error) {
+	ctx, cancel := pgb.queryContext(ctx)
+	defer cancel()
+
+	params := pgb.chainParams
+	height := pgb.Height()
+	progress := &apitypes.UpgradeProgress{
+		Height: height,
+		Miners: apitypes.MinerUpgradeProgress{
+			Window:           int64(params.BlockUpgradeNumToCheck),
+			EnforceThreshold: int64(params.BlockEnforceNumRequired),
+			RejectThreshold:  int64(params.BlockRejectNumRequired),
+		},
+		Voters: apitypes.VoterUpgradeProgress{
+			Threshold: float64(params.StakeMajorityMultiplier) /
+				float64(params.StakeMajorityDivisor),
+		},
+	}
+
+	// Block versions in the upgrade window ending at the best block.
+	miners := &progress.Miners
+	blockVersions, err := retrieveBlockVersionCounts(ctx, pgb.db,
+		height-miners.Window+1, height)
+	if err != nil {
+		return nil, pgb.replaceCancelError(err)
+	}
+	for v := range blockVersions {
+		if v > miners.Version {
+			miners.Version = v
+		}
+	}
+	for v, n := range blockVersions {
+		if v >= miners.Version {
+			miners.Count += n
+		}
+	}
+	miners.Enforced = miners.Count >= miners.EnforceThreshold
+	miners.Rejecting = miners.Count >= miners.RejectThreshold
+
+	timeline, err := retrieveBlockVersionThresholds(ctx, pgb.db, miners.Window,
+		miners.EnforceThreshold, miners.RejectThreshold)
+	if err != nil {
+		return nil, pgb.replaceCancelError(err)
+	}
+
+	// Vote versions in the stake version intervals, which are aligned with the
+	// stake validation height.
+	svi := params.StakeVersionInterval
+	offset := params.StakeValidationHeight % svi
+	intervals, err := retrieveVoteVersionsByInterval(ctx, pgb.db, offset, svi)
+	if err != nil {
+		return nil, pgb.replaceCancelError(err)
+	}
+
+	voters := &progress.Voters
+	if height >= offset {
+		index := (height - offset) / svi
+		voters.IntervalStart = offset + index*svi
+		voters.IntervalEnd = voters.IntervalStart + svi - 1
+		if n := len(intervals); n > 0 && intervals[n-1].index == index {
+			current := intervals[n-1]
+			for v := range current.counts {
+				if v > voters.Version {
+					voters.Version = v
+				}
+			}
+			voters.Count, voters.Total, voters.MajorityReached =
+				current.versionMajority(voters.Version, params)
+			voters.Fraction = float64(voters.Count) / float64(voters.Total)
+		}
+	}
+
+	for _, t := range voterMajorities(intervals, height, params) {
+		blockTime, err := RetrieveBlockTimeByHeight(ctx, pgb.db, t.Height)
+		if err != nil {
+			return nil, pgb.replaceCancelError(err)
+		}
+		t.Time = blockTime.UNIX()
+		timeline = append(timeline, t)
+	}
+	sort.SliceStable(timeline, func(i, j int) bool {
+		return timeline[i].Height < timeline[j].Height
+	})
+	progress.Timeline = timeline
+
+	return progress, nil
+}
+
 // NumAddressIntervals gets the number of unique time intervals for the
 // specified grouping where there are entries in the addresses table for the
 // given address.
-func (pgb *ChainDB) NumAddressIntervals(addr string, grouping dbtypes.TimeBasedGrouping) (int64, error) {
-	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+func (pgb *ChainDB) NumAddressIntervals(ctx context.Context, addr string, grouping dbtypes.TimeBasedGrouping) (int64, error) {
+	ctx, cancel := pgb.queryContext(ctx)
 	defer cancel()
 	return retrieveAddressTxsCount(ctx, pgb.db, addr, grouping.String())
 }
@@ -1562,12 +2224,12 @@ func (pgb *ChainDB) NumAddressIntervals(addr string, grouping dbtypes.TimeBasedG
 // total count for all the transactions linked to the provided address grouped
 // by years, months, weeks and days time grouping in seconds.
 // This helps plot more meaningful address history graphs to the user.
-func (pgb *ChainDB) AddressMetrics(addr string) (*dbtypes.AddressMetrics, error) {
+func (pgb *ChainDB) AddressMetrics(ctx context.Context, addr string) (*dbtypes.AddressMetrics, error) {
 	// For each time grouping/interval size, get the number if intervals with
 	// data for the address.
 	var metrics dbtypes.AddressMetrics
 	for _, s := range dbtypes.TimeIntervals {
-		numIntervals, err := pgb.NumAddressIntervals(addr, s)
+		numIntervals, err := pgb.NumAddressIntervals(ctx, addr, s)
 		if err != nil {
 			return nil, fmt.Errorf("retrieveAddressAllTxsCount failed: error: %v", err)
 		}
@@ -1586,7 +2248,7 @@ func (pgb *ChainDB) AddressMetrics(addr string) (*dbtypes.AddressMetrics, error)
 
 	// Get the time of the block with the first transaction involving the
 	// address (oldest transaction block time).
-	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	ctx, cancel := pgb.queryContext(ctx)
 	defer cancel()
 	blockTime, err := retrieveOldestTxBlockTime(ctx, pgb.db, addr)
 	if err != nil {
@@ -1601,7 +2263,7 @@ func (pgb *ChainDB) AddressMetrics(addr string) (*dbtypes.AddressMetrics, error)
 // address and transaction type (i.e. all, credit, or debit) from the DB. Only
 // the first N transactions starting from the offset element in the set of all
 // txnType transactions.
-func (pgb *ChainDB) AddressTransactions(address string, N, offset int64,
+func (pgb *ChainDB) AddressTransactions(ctx context.Context, address string, N, offset int64,
 	txnType dbtypes.AddrTxnViewType) (addressRows []*dbtypes.AddressRow, err error) {
 	var addrFunc func(context.Context, *sql.DB, string, int64, int64) ([]*dbtypes.AddressRow, error)
 	switch txnType {
@@ -1621,7 +2283,7 @@ func (pgb *ChainDB) AddressTransactions(address string, N, offset int64,
 		return nil, fmt.Errorf("unknown AddrTxnViewType %v", txnType)
 	}
 
-	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	ctx, cancel := pgb.queryContext(ctx)
 	defer cancel()
 
 	addressRows, err = addrFunc(ctx, pgb.db, address, N, offset)
@@ -1631,8 +2293,8 @@ func (pgb *ChainDB) AddressTransactions(address string, N, offset int64,
 
 // AddressTransactionsAll retrieves all non-merged main chain addresses table
 // rows for the given address.
-func (pgb *ChainDB) AddressTransactionsAll(address string) (addressRows []*dbtypes.AddressRow, err error) {
-	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+func (pgb *ChainDB) AddressTransactionsAll(ctx context.Context, address string) (addressRows []*dbtypes.AddressRow, err error) {
+	ctx, cancel := pgb.queryContext(ctx)
 	defer cancel()
 
 	addressRows, err = RetrieveAllMainchainAddressTxns(ctx, pgb.db, address)
@@ -1642,8 +2304,8 @@ func (pgb *ChainDB) AddressTransactionsAll(address string) (addressRows []*dbtyp
 
 // AddressTransactionsAllMerged retrieves all merged (stakeholder-approved and
 // mainchain only) addresses table rows for the given address.
-func (pgb *ChainDB) AddressTransactionsAllMerged(address string) (addressRows []*dbtypes.AddressRow, err error) {
-	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+func (pgb *ChainDB) AddressTransactionsAllMerged(ctx context.Context, address string) (addressRows []*dbtypes.AddressRow, err error) {
+	ctx, cancel := pgb.queryContext(ctx)
 	defer cancel()
 
 	onlyValidMainchain := true
@@ -1655,8 +2317,8 @@ func (pgb *ChainDB) AddressTransactionsAllMerged(address string) (addressRows []
 
 // AddressHistoryAll retrieves N address rows of type AddrTxnAll, skipping over
 // offset rows first, in order of block time.
-func (pgb *ChainDB) AddressHistoryAll(address string, N, offset int64) ([]*dbtypes.AddressRow, *dbtypes.AddressBalance, error) {
-	return pgb.AddressHistory(address, N, offset, dbtypes.AddrTxnAll)
+func (pgb *ChainDB) AddressHistoryAll(ctx context.Context, address string, N, offset int64) ([]*dbtypes.AddressRow, *dbtypes.AddressBalance, error) {
+	return pgb.AddressHistory(ctx, address, N, offset, dbtypes.AddrTxnAll)
 }
 
 // TicketPoolBlockMaturity returns the block at which all tickets with height
@@ -1668,9 +2330,9 @@ func (pgb *ChainDB) TicketPoolBlockMaturity() int64 {
 
 // TicketPoolByDateAndInterval fetches the tickets ordered by the purchase date
 // interval provided and an error value.
-func (pgb *ChainDB) TicketPoolByDateAndInterval(maturityBlock int64,
+func (pgb *ChainDB) TicketPoolByDateAndInterval(ctx context.Context, maturityBlock int64,
 	interval dbtypes.TimeBasedGrouping) (*dbtypes.PoolTicketsData, error) {
-	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	ctx, cancel := pgb.queryContext(ctx)
 	defer cancel()
 	tpd, err := retrieveTicketsByDate(ctx, pgb.db, maturityBlock, interval.String())
 	return tpd, pgb.replaceCancelError(err)
@@ -1681,8 +2343,8 @@ func (pgb *ChainDB) TicketPoolByDateAndInterval(maturityBlock int64,
 // whose count is defined by chainParams.StakeDiffWindowSize. During this
 // chainParams.StakeDiffWindowSize block interval the ticket price and the
 // difficulty value is constant.
-func (pgb *ChainDB) PosIntervals(limit, offset uint64) ([]*dbtypes.BlocksGroupedInfo, error) {
-	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+func (pgb *ChainDB) PosIntervals(ctx context.Context, limit, offset uint64) ([]*dbtypes.BlocksGroupedInfo, error) {
+	ctx, cancel := pgb.queryContext(ctx)
 	defer cancel()
 	bgi, err := retrieveWindowBlocks(ctx, pgb.db,
 		pgb.chainParams.StakeDiffWindowSize, pgb.Height(), limit, offset)
@@ -1692,9 +2354,9 @@ func (pgb *ChainDB) PosIntervals(limit, offset uint64) ([]*dbtypes.BlocksGrouped
 // TimeBasedIntervals retrieves blocks groups by the selected time-based
 // interval. For the consecutive groups the number of blocks grouped together is
 // not uniform.
-func (pgb *ChainDB) TimeBasedIntervals(timeGrouping dbtypes.TimeBasedGrouping,
+func (pgb *ChainDB) TimeBasedIntervals(ctx context.Context, timeGrouping dbtypes.TimeBasedGrouping,
 	limit, offset uint64) ([]*dbtypes.BlocksGroupedInfo, error) {
-	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	ctx, cancel := pgb.queryContext(ctx)
 	defer cancel()
 	bgi, err := retrieveTimeBasedBlockListing(ctx, pgb.db, timeGrouping.String(),
 		limit, offset)
@@ -1708,19 +2370,21 @@ func (pgb *ChainDB) TimeBasedIntervals(timeGrouping dbtypes.TimeBasedGrouping,
 // a query and updates the cache. If there is no cached data for the interval,
 // this will launch a new query for the data if one is not already running, and
 // if one is running, it will wait for the query to complete.
-func (pgb *ChainDB) TicketPoolVisualization(interval dbtypes.TimeBasedGrouping) (*dbtypes.PoolTicketsData,
-	*dbtypes.PoolTicketsData, *dbtypes.PoolTicketsData, int64, error) {
+func (pgb *ChainDB) TicketPoolVisualization(ctx context.Context, interval dbtypes.TimeBasedGrouping) (*dbtypes.PoolTicketsData,
+	*dbtypes.PoolTicketsData, *dbtypes.PoolTicketsData, *dbtypes.PoolTicketsData, int64, error) {
 	// Attempt to retrieve data for the current block from cache.
-	heightSeen := pgb.Height() // current block seen *by the ChainDB*
+	hashSeen, heightSeen := pgb.BestBlock() // current block seen *by the ChainDB*
 	if heightSeen < 0 {
-		return nil, nil, nil, -1, fmt.Errorf("no charts data available")
+		return nil, nil, nil, nil, -1, fmt.Errorf("no charts data available")
 	}
-	timeChart, priceChart, outputsChart, height, intervalFound, stale :=
-		TicketPoolData(interval, heightSeen)
+	timeChart, priceChart, outputsChart, ageChart, height, intervalFound, stale :=
+		TicketPoolData(interval, *hashSeen)
 	if intervalFound && !stale {
 		// The cache was fresh.
-		return timeChart, priceChart, outputsChart, height, nil
+		ticketPoolGraphsCache.stats.hit()
+		return timeChart, priceChart, outputsChart, ageChart, height, nil
 	}
+	ticketPoolGraphsCache.stats.miss()
 
 	// Cache is stale or empty. Attempt to gain updater status.
 	if !pgb.tpUpdatePermission[interval].TryLock() {
@@ -1733,14 +2397,14 @@ func (pgb *ChainDB) TicketPoolVisualization(interval dbtypes.TimeBasedGrouping)
 			pgb.tpUpdatePermission[interval].Lock()
 			defer pgb.tpUpdatePermission[interval].Unlock()
 			// Try again to pull it from cache now that the update is completed.
-			heightSeen = pgb.Height()
-			timeChart, priceChart, outputsChart, height, intervalFound, stale =
-				TicketPoolData(interval, heightSeen)
+			hashSeen, _ = pgb.BestBlock()
+			timeChart, priceChart, outputsChart, ageChart, height, intervalFound, stale =
+				TicketPoolData(interval, *hashSeen)
 			// We waited for the updater of this interval, so it should be found
 			// at this point. If not, this is an error.
 			if !intervalFound {
 				log.Errorf("Charts data for interval %v failed to update.", interval)
-				return nil, nil, nil, 0, fmt.Errorf("no charts data available")
+				return nil, nil, nil, nil, 0, fmt.Errorf("no charts data available")
 			}
 			if stale {
 				log.Warnf("Charts data for interval %v updated, but still stale.", interval)
@@ -1748,74 +2412,372 @@ func (pgb *ChainDB) TicketPoolVisualization(interval dbtypes.TimeBasedGrouping)
 		}
 		// else return the stale data instead of waiting.
 
-		return timeChart, priceChart, outputsChart, height, nil
+		return timeChart, priceChart, outputsChart, ageChart, height, nil
 	}
 	// This goroutine is now the cache updater.
 	defer pgb.tpUpdatePermission[interval].Unlock()
 
 	// Retrieve chart data for best block in DB.
 	var err error
-	timeChart, priceChart, outputsChart, height, err = pgb.ticketPoolVisualization(interval)
+	var hash chainhash.Hash
+	timeChart, priceChart, outputsChart, ageChart, height, hash, err = pgb.ticketPoolVisualization(ctx, interval)
 	if err != nil {
 		log.Errorf("Failed to fetch ticket pool data: %v", err)
-		return nil, nil, nil, 0, err
+		return nil, nil, nil, nil, 0, err
 	}
 
 	// Update the cache with the new ticket pool data.
-	UpdateTicketPoolData(interval, timeChart, priceChart, outputsChart, height)
+	UpdateTicketPoolData(interval, timeChart, priceChart, outputsChart, ageChart, height, hash)
 
-	return timeChart, priceChart, outputsChart, height, nil
+	return timeChart, priceChart, outputsChart, ageChart, height, nil
 }
 
 // ticketPoolVisualization fetches the following ticketpool data: tickets
 // grouped on the specified interval, tickets grouped by price, and ticket
-// counts by ticket type (solo, pool, other split). The interval may be one of:
+// counts by ticket type (solo, pool, other split), and live tickets grouped by
+// age. The interval may be one of:
 // "mo", "wk", "day", or "all". The data is needed to populate the ticketpool
-// graphs. The data grouped by time and price are returned in a slice.
-func (pgb *ChainDB) ticketPoolVisualization(interval dbtypes.TimeBasedGrouping) (timeChart *dbtypes.PoolTicketsData,
-	priceChart *dbtypes.PoolTicketsData, byInputs *dbtypes.PoolTicketsData, height int64, err error) {
-	// Ensure DB height is the same before and after queries since they are not
-	// atomic. Initial height:
-	height = pgb.Height()
+// graphs. The data grouped by time and price are returned in a slice, with
+// the height and hash of the best block they were fetched at.
+func (pgb *ChainDB) ticketPoolVisualization(ctx context.Context, interval dbtypes.TimeBasedGrouping) (timeChart *dbtypes.PoolTicketsData,
+	priceChart *dbtypes.PoolTicketsData, byInputs *dbtypes.PoolTicketsData, byAge *dbtypes.PoolTicketsData,
+	height int64, hash chainhash.Hash, err error) {
+	// Ensure the DB best block is the same before and after queries since they
+	// are not atomic. A reorg may replace it at the same height, so compare
+	// hashes. Initial best block:
+	bestHash, height := pgb.BestBlock()
+	hash = *bestHash
 	for {
 		// Latest block where mature tickets may have been mined.
 		maturityBlock := pgb.TicketPoolBlockMaturity()
 
 		// Tickets grouped by time interval
-		timeChart, err = pgb.TicketPoolByDateAndInterval(maturityBlock, interval)
+		timeChart, err = pgb.TicketPoolByDateAndInterval(ctx, maturityBlock, interval)
 		if err != nil {
-			return nil, nil, nil, 0, err
+			return nil, nil, nil, nil, 0, hash, err
 		}
 
 		// Tickets grouped by price
-		priceChart, err = pgb.TicketsByPrice(maturityBlock)
+		priceChart, err = pgb.TicketsByPrice(ctx, maturityBlock)
 		if err != nil {
-			return nil, nil, nil, 0, err
+			return nil, nil, nil, nil, 0, hash, err
 		}
 
 		// Tickets grouped by number of inputs.
-		byInputs, err = pgb.TicketsByInputCount()
+		byInputs, err = pgb.TicketsByInputCount(ctx)
 		if err != nil {
-			return nil, nil, nil, 0, err
+			return nil, nil, nil, nil, 0, hash, err
 		}
 
-		heightEnd := pgb.Height()
-		if heightEnd == height {
+		// Live tickets grouped by age.
+		byAge, err = pgb.TicketsByAge(ctx, maturityBlock)
+		if err != nil {
+			return nil, nil, nil, nil, 0, hash, err
+		}
+
+		hashEnd, heightEnd := pgb.BestBlock()
+		if *hashEnd == hash {
 			break
 		}
 		// otherwise try again to ensure charts are consistent.
-		height = heightEnd
+		hash, height = *hashEnd, heightEnd
 	}
 
 	return
 }
 
+// TicketCommitments retrieves the commitment outputs of the ticket with the
+// given hash, which specify the addresses where the ticket's reward will pay
+// out.
+func (pgb *ChainDB) TicketCommitments(ctx context.Context, txid string) ([]*dbtypes.TicketCommitment, error) {
+	ctx, cancel := pgb.queryContext(ctx)
+	defer cancel()
+	commitments, err := RetrieveTicketCommitments(ctx, pgb.db, txid)
+	return commitments, pgb.replaceCancelError(err)
+}
+
+// TicketsByRewardAddress retrieves up to N mainchain tickets, skipping offset
+// or starting after the cursor, with a commitment to the given reward address,
+// and the cursor of the next page.
+func (pgb *ChainDB) TicketsByRewardAddress(ctx context.Context, address string, N, offset int64,
+	after *dbtypes.PageCursor) ([]*dbtypes.RewardTicket, *dbtypes.PageCursor, error) {
+	ctx, cancel := pgb.queryContext(ctx)
+	defer cancel()
+	tickets, next, err := RetrieveTicketsByRewardAddress(ctx, pgb.db, address, N, offset, after)
+	return tickets, next, pgb.replaceCancelError(err)
+}
+
+// AddressUTXOs retrieves up to N of the unspent outputs paying to the address,
+// skipping offset, in the order given by sortBy, which is one of the
+// dbtypes.UTXOSort* orders, with the number and value of all of the address's
+// unspent outputs.
+func (pgb *ChainDB) AddressUTXOs(ctx context.Context, address string, N, offset int64, sortBy string) (*apitypes.AddressUTXOs, error) {
+	ctx, cancel := pgb.queryContext(ctx)
+	defer cancel()
+
+	count, atoms, err := RetrieveAddressUnspent(ctx, pgb.db, pgb.archive, address)
+	if err != nil {
+		return nil, pgb.replaceCancelError(err)
+	}
+
+	utxos, err := RetrieveAddressUTXOs(ctx, pgb.db, pgb.archive, address, N, offset, sortBy,
+		pgb.Height(), pgb.chainParams)
+	if err != nil {
+		return nil, pgb.replaceCancelError(err)
+	}
+	if utxos == nil {
+		utxos = []*apitypes.AddressUTXO{}
+	}
+
+	return &apitypes.AddressUTXOs{
+		Address: address,
+		Count:   count,
+		Atoms:   atoms,
+		Offset:  offset,
+		Sort:    sortBy,
+		UTXOs:   utxos,
+	}, nil
+}
+
+// RevocableTickets retrieves up to N missed or expired mainchain tickets that
+// have not been revoked, skipping offset or starting after the cursor, the
+// totals for all such tickets, and the cursor of the next page. If address is
+// not empty, only tickets with a commitment to it are included.
+func (pgb *ChainDB) RevocableTickets(ctx context.Context, address string, N, offset int64,
+	after *dbtypes.PageCursor) (*dbtypes.RevocableTickets, *dbtypes.PageCursor, error) {
+	ctx, cancel := pgb.queryContext(ctx)
+	defer cancel()
+	tickets, next, err := RetrieveRevocableTickets(ctx, pgb.db, address, N, offset, after)
+	return tickets, next, pgb.replaceCancelError(err)
+}
+
+// CoinbaseBlocksByAddress retrieves up to N mainchain blocks, skipping offset
+// or starting after the cursor, with coinbase outputs paying to the given
+// address, and the cursor of the next page.
+func (pgb *ChainDB) CoinbaseBlocksByAddress(ctx context.Context, address string, N, offset int64,
+	after *dbtypes.PageCursor) ([]*dbtypes.CoinbaseBlock, *dbtypes.PageCursor, error) {
+	ctx, cancel := pgb.queryContext(ctx)
+	defer cancel()
+	blocks, next, err := RetrieveCoinbaseBlocksByAddress(ctx, pgb.db, address, N, offset, after)
+	return blocks, next, pgb.replaceCancelError(err)
+}
+
+// MinerShares computes the distribution of the last numBlocks mainchain blocks
+// among their coinbase payout addresses.
+func (pgb *ChainDB) MinerShares(ctx context.Context, numBlocks int64) (*dbtypes.MinerShares, error) {
+	height := pgb.Height()
+	fromHeight := height - numBlocks + 1
+	if fromHeight < 1 {
+		fromHeight = 1 // the genesis block has no payouts
+	}
+	ctx, cancel := pgb.queryContext(ctx)
+	defer cancel()
+	miners, n, err := RetrieveMinerShares(ctx, pgb.db, fromHeight)
+	if err != nil {
+		return nil, pgb.replaceCancelError(err)
+	}
+	return &dbtypes.MinerShares{
+		FromHeight: fromHeight,
+		ToHeight:   height,
+		NumBlocks:  n,
+		Miners:     miners,
+	}, nil
+}
+
+// setTicketLuck computes the number of blocks the voted ticket was live, and
+// its luck given the mean and standard deviation of the live blocks of voted
+// tickets.
+func (pgb *ChainDB) setTicketLuck(t *dbtypes.TicketLuck, mean, stdDev float64) {
+	t.LiveBlocks = t.VoteHeight - t.PurchaseHeight - int64(pgb.chainParams.TicketMaturity)
+	t.ExpectedBlocks = mean
+	t.StdDevBlocks = stdDev
+	t.Luck = (mean - float64(t.LiveBlocks)) / stdDev
+}
+
+// NullDataByPrefix retrieves up to N mainchain null data (OP_RETURN) outputs,
+// skipping offset, with a payload beginning with the given prefix, newest
+// first.
+func (pgb *ChainDB) NullDataByPrefix(ctx context.Context, prefix []byte, N, offset int64) ([]*dbtypes.NullDataOutput, error) {
+	ctx, cancel := pgb.queryContext(ctx)
+	defer cancel()
+	outputs, err := RetrieveNullDataByPrefix(ctx, pgb.db, prefix, N, offset)
+	return outputs, pgb.replaceCancelError(err)
+}
+
+// TicketLuck compares the number of blocks the voted ticket with the given
+// hash was live with the expected number.
+func (pgb *ChainDB) TicketLuck(ctx context.Context, txid string) (*dbtypes.TicketLuck, error) {
+	ctx, cancel := pgb.queryContext(ctx)
+	defer cancel()
+	purchaseHeight, voteHeight, voted, err := RetrieveTicketVoteHeights(ctx, pgb.db, txid)
+	if err != nil {
+		return nil, pgb.replaceCancelError(err)
+	}
+	if !voted {
+		return nil, fmt.Errorf("ticket %s has not voted", txid)
+	}
+
+	t := &dbtypes.TicketLuck{
+		TxHash:         txid,
+		PurchaseHeight: purchaseHeight,
+		VoteHeight:     voteHeight,
+	}
+	mean, stdDev := txhelpers.CalcVotingBlocksStats(pgb.chainParams)
+	pgb.setTicketLuck(t, mean, stdDev)
+	return t, nil
+}
+
+// AddressTicketLuck aggregates the luck of the voted tickets with a commitment
+// to the given address.
+func (pgb *ChainDB) AddressTicketLuck(ctx context.Context, address string) (*dbtypes.AddressTicketLuck, error) {
+	ctx, cancel := pgb.queryContext(ctx)
+	defer cancel()
+	tickets, err := RetrieveVotedTicketsByRewardAddress(ctx, pgb.db, address)
+	if err != nil {
+		return nil, pgb.replaceCancelError(err)
+	}
+
+	mean, stdDev := txhelpers.CalcVotingBlocksStats(pgb.chainParams)
+	luck := &dbtypes.AddressTicketLuck{
+		Address:        address,
+		NumVoted:       int64(len(tickets)),
+		ExpectedBlocks: mean,
+		StdDevBlocks:   stdDev,
+	}
+	if len(tickets) == 0 {
+		return luck, nil
+	}
+
+	var totalLiveBlocks int64
+	for _, t := range tickets {
+		pgb.setTicketLuck(t, mean, stdDev)
+		totalLiveBlocks += t.LiveBlocks
+		if luck.Luckiest == nil || t.Luck > luck.Luckiest.Luck {
+			luck.Luckiest = t
+		}
+		if luck.Unluckiest == nil || t.Luck < luck.Unluckiest.Luck {
+			luck.Unluckiest = t
+		}
+	}
+	n := float64(len(tickets))
+	luck.MeanLiveBlocks = float64(totalLiveBlocks) / n
+	luck.Luck = (mean - luck.MeanLiveBlocks) / (stdDev / math.Sqrt(n))
+	return luck, nil
+}
+
+// voteOddsQuantiles are the probabilities for which TicketVoteOdds reports the
+// number of blocks within which at least one ticket votes.
+var voteOddsQuantiles = []float64{0.5, 0.9, 0.99}
+
+// TicketVoteOdds computes the probability that at least one of the tickets
+// with the given hashes votes within the next numBlocks blocks, and the
+// distribution of the wait for the first vote. A numBlocks that is not
+// positive, or beyond the point where every ticket has expired, is set to the
+// ticket maturity plus expiry.
+func (pgb *ChainDB) TicketVoteOdds(ctx context.Context, txids []string, numBlocks int64) (*dbtypes.TicketVoteOdds, error) {
+	height := pgb.Height()
+	poolSize := int64(pgb.stakeDB.PoolSize())
+	if poolSize == 0 {
+		return nil, dbtypes.ErrEmptyTicketPool
+	}
+	ctx, cancel := pgb.queryContext(ctx)
+	defer cancel()
+	heights, statuses, err := RetrieveTicketPoolStatuses(ctx, pgb.db, txids)
+	if err != nil {
+		return nil, pgb.replaceCancelError(err)
+	}
+
+	maturity := int64(pgb.chainParams.TicketMaturity)
+	expiry := int64(pgb.chainParams.TicketExpiry)
+	if numBlocks <= 0 || numBlocks > maturity+expiry {
+		numBlocks = maturity + expiry
+	}
+	odds := &dbtypes.TicketVoteOdds{
+		Height:     height,
+		PoolSize:   poolSize,
+		Blocks:     numBlocks,
+		NumTickets: len(txids),
+		Quantiles:  []*dbtypes.VoteWaitQuantile{},
+	}
+
+	// Each ticket may vote from the block after it matures until it expires.
+	// Offsets are relative to the next block.
+	pVote := float64(pgb.chainParams.TicketsPerBlock) / float64(odds.PoolSize)
+	windows := make([]txhelpers.TicketVoteWindow, 0, len(txids))
+	for _, txid := range txids {
+		purchaseHeight, found := heights[txid]
+		if !found {
+			odds.Unknown = append(odds.Unknown, txid)
+			continue
+		}
+		if statuses[txid] != dbtypes.PoolStatusLive {
+			continue
+		}
+		w := txhelpers.TicketVoteWindow{
+			First: purchaseHeight + maturity - height,
+			Last:  purchaseHeight + maturity + expiry - height - 1,
+		}
+		if w.First < 0 {
+			w.First = 0
+		}
+		windows = append(windows, w)
+		odds.NumEligible++
+
+		last := w.Last
+		if last >= numBlocks {
+			last = numBlocks - 1
+		}
+		if eligibleBlocks := last - w.First + 1; eligibleBlocks > 0 {
+			odds.ExpectedVotes += 1 - math.Pow(1-pVote, float64(eligibleBlocks))
+		}
+	}
+
+	noVote := txhelpers.CalcNoVoteProbabilities(windows, odds.PoolSize,
+		pgb.chainParams.TicketsPerBlock, numBlocks)
+	odds.Probability = 1 - noVote[numBlocks-1]
+	if odds.Probability <= 0 {
+		return odds, nil
+	}
+
+	// The mean wait is conditioned on a vote within numBlocks.
+	prev, q := 1.0, 0
+	for i, p := range noVote {
+		odds.MeanWaitBlocks += float64(i+1) * (prev - p)
+		prev = p
+		for q < len(voteOddsQuantiles) && 1-p >= voteOddsQuantiles[q] {
+			odds.Quantiles = append(odds.Quantiles, &dbtypes.VoteWaitQuantile{
+				Probability: voteOddsQuantiles[q],
+				Blocks:      int64(i + 1),
+			})
+			q++
+		}
+	}
+	odds.MeanWaitBlocks /= odds.Probability
+	return odds, nil
+}
+
+// StakingPosition summarizes the DCR committed to the given address by unspent
+// tickets, and the pending payouts to it from recent votes.
+func (pgb *ChainDB) StakingPosition(ctx context.Context, address string) (*dbtypes.StakingPosition, error) {
+	height := pgb.Height()
+	ctx, cancel := pgb.queryContext(ctx)
+	defer cancel()
+	pos, err := RetrieveStakingPosition(ctx, pgb.db, address,
+		height-int64(pgb.chainParams.TicketMaturity),
+		height-int64(pgb.chainParams.CoinbaseMaturity))
+	if err != nil {
+		return nil, pgb.replaceCancelError(err)
+	}
+	pos.Height = height
+	return pos, nil
+}
+
 // GetTicketInfo retrieves information about the pool and spend statuses, the
 // purchase block, the lottery block, and the spending transaction.
-func (pgb *ChainDB) GetTicketInfo(txid string) (*apitypes.TicketInfo, error) {
-	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+func (pgb *ChainDB) GetTicketInfo(ctx context.Context, txid string) (*apitypes.TicketInfo, error) {
+	ctx, cancel := pgb.queryContext(ctx)
 	defer cancel()
-	spendStatus, poolStatus, purchaseBlock, lotteryBlock, spendTxid, err := RetrieveTicketInfoByHash(ctx, pgb.db, txid)
+	spendStatus, poolStatus, purchaseBlock, lotteryBlock, spend, err := RetrieveTicketInfoByHash(ctx, pgb.db, txid)
 
 	if err != nil {
 		return nil, pgb.replaceCancelError(err)
@@ -1830,9 +2792,9 @@ func (pgb *ChainDB) GetTicketInfo(txid string) (*apitypes.TicketInfo, error) {
 	}
 	if spendStatus == dbtypes.TicketRevoked {
 		status = spendStatus.String()
-		revocation = &spendTxid
+		revocation = &spend.TxHash
 	} else if spendStatus == dbtypes.TicketVoted {
-		vote = &spendTxid
+		vote = &spend.TxHash
 	}
 
 	if poolStatus == dbtypes.PoolStatusMissed {
@@ -1854,11 +2816,15 @@ func (pgb *ChainDB) GetTicketInfo(txid string) (*apitypes.TicketInfo, error) {
 		LotteryBlock:     lotteryBlock,
 		Vote:             vote,
 		Revocation:       revocation,
+		Spend:            spend,
 	}, nil
 }
 
 func (pgb *ChainDB) updateProjectFundCache() error {
-	_, _, err := pgb.AddressHistoryAll(pgb.devAddress, 1, 0)
+	_, _, err := pgb.AddressHistoryAll(pgb.ctx, pgb.devAddress, 1, 0)
+	if err == nil {
+		pgb.devFundStats.touch()
+	}
 	return err
 	// Update balance.
 	// _, _, err := pgb.AddressBalance(pgb.devAddress)
@@ -1897,8 +2863,14 @@ func (pgb *ChainDB) FreshenAddressCaches(lazyProjectFund bool, expireAddresses [
 		return nil
 	}
 
+	// The update must finish before the DB is closed by Shutdown.
+	if !pgb.beginWrite() {
+		return nil
+	}
+
 	if lazyProjectFund {
 		go func() {
+			defer pgb.endWrite()
 			runtime.Gosched()
 			if err := updateFundData(); err != nil {
 				log.Error(err)
@@ -1906,6 +2878,7 @@ func (pgb *ChainDB) FreshenAddressCaches(lazyProjectFund bool, expireAddresses [
 		}()
 		return nil
 	}
+	defer pgb.endWrite()
 	return updateFundData()
 }
 
@@ -1918,14 +2891,17 @@ func (pgb *ChainDB) DevBalance() (*dbtypes.AddressBalance, error) {
 	cachedBalance, validBlock := pgb.AddressCache.Balance(pgb.devAddress)
 	bestBlockHash := pgb.BestBlockHash()
 	if cachedBalance != nil && validBlock.Hash == *bestBlockHash {
+		pgb.devFundStats.hit()
 		return cachedBalance, nil
 	}
+	pgb.devFundStats.miss()
 
 	if !pgb.InReorg {
-		bal, _, err := pgb.AddressBalance(pgb.devAddress)
+		bal, _, err := pgb.AddressBalance(pgb.ctx, pgb.devAddress)
 		if err != nil {
 			return nil, err
 		}
+		pgb.devFundStats.touch()
 		return bal, nil
 	}
 
@@ -1939,12 +2915,19 @@ func (pgb *ChainDB) DevBalance() (*dbtypes.AddressBalance, error) {
 // AddressBalance attempts to retrieve balance information for a specific
 // address from cache, and if cache is stale or missing data for the address, a
 // DB query is used. A successful DB query will freshen the cache.
-func (pgb *ChainDB) AddressBalance(address string) (bal *dbtypes.AddressBalance, cacheUpdated bool, err error) {
-	// Check the cache first.
+func (pgb *ChainDB) AddressBalance(ctx context.Context, address string) (bal *dbtypes.AddressBalance, cacheUpdated bool, err error) {
 	bestHash, height := pgb.BestBlock()
+	return pgb.addressBalance(ctx, address, cache.NewBlockID(bestHash, height))
+}
+
+// addressBalance is like AddressBalance, but the balance is as of the given
+// block. The DB query is pinned to the block's height, and the cache is only
+// updated if the block is still the best block.
+func (pgb *ChainDB) addressBalance(ctx context.Context, address string, block *cache.BlockID) (bal *dbtypes.AddressBalance, cacheUpdated bool, err error) {
+	// Check the cache first.
 	var validHeight *cache.BlockID
 	bal, validHeight = pgb.AddressCache.Balance(address) // bal is a copy
-	if bal != nil && *bestHash == validHeight.Hash {
+	if bal != nil && block.Hash == validHeight.Hash {
 		return
 	}
 
@@ -1959,7 +2942,7 @@ func (pgb *ChainDB) AddressBalance(address string) (bal *dbtypes.AddressBalance,
 		<-wait
 
 		// Try again, starting with the cache.
-		return pgb.AddressBalance(address)
+		return pgb.addressBalance(ctx, address, block)
 	}
 
 	// We will run the DB query, so block others from doing the same. When query
@@ -1968,25 +2951,84 @@ func (pgb *ChainDB) AddressBalance(address string) (bal *dbtypes.AddressBalance,
 	defer done()
 
 	// Cache is empty or stale, so query the DB.
-	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	ctx, cancel := pgb.queryContext(ctx)
 	defer cancel()
-	bal, err = RetrieveAddressBalance(ctx, pgb.db, address)
+	bal, err = RetrieveAddressBalanceAtHeight(ctx, pgb.db, address, block.Height)
 	if err != nil {
 		err = pgb.replaceCancelError(err)
 		return
 	}
 
-	// Update the address cache.
-	cacheUpdated = pgb.AddressCache.StoreBalance(address, bal,
-		cache.NewBlockID(bestHash, height)) // stores a copy of bal
+	// Update the address cache, unless a newer block was stored meanwhile.
+	if *pgb.BestBlockHash() == block.Hash {
+		cacheUpdated = pgb.AddressCache.StoreBalance(address, bal, block) // stores a copy of bal
+	}
 	return
 }
 
+// AddressSummary returns the balance, number of transactions, and first and
+// last transaction times for the address. The balance and, if the address'
+// rows are cached, the transaction data come from the address cache.
+// Otherwise, the transaction data are retrieved with a single aggregate query
+// rather than loading the address' history.
+func (pgb *ChainDB) AddressSummary(ctx context.Context, address string) (*dbtypes.AddressSummary, error) {
+	bal, _, err := pgb.AddressBalance(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+
+	var numTxns, firstSeen, lastActivity int64
+	bestHash, _ := pgb.BestBlock()
+	rows, validBlock := pgb.AddressCache.Rows(address)
+	if rows != nil && validBlock != nil && validBlock.Hash == *bestHash {
+		numTxns, firstSeen, lastActivity = addressRowsSummary(rows)
+	} else {
+		ctx, cancel := pgb.queryContext(ctx)
+		defer cancel()
+		numTxns, firstSeen, lastActivity, err = RetrieveAddressTxnSummary(ctx, pgb.db, address)
+		if err != nil {
+			return nil, pgb.replaceCancelError(err)
+		}
+	}
+
+	summary := &dbtypes.AddressSummary{
+		Address:         address,
+		Balance:         bal.TotalUnspent,
+		NumTransactions: numTxns,
+	}
+	if numTxns > 0 {
+		first := dbtypes.NewTimeDefFromUNIX(firstSeen)
+		last := dbtypes.NewTimeDefFromUNIX(lastActivity)
+		summary.FirstSeen, summary.LastActivity = &first, &last
+	}
+	return summary, nil
+}
+
+// addressRowsSummary computes the number of distinct valid mainchain
+// transactions in the address rows, and the times of the first and last.
+func addressRowsSummary(rows []*dbtypes.AddressRowCompact) (numTxns, firstSeen, lastActivity int64) {
+	txns := make(map[chainhash.Hash]struct{}, len(rows))
+	for _, r := range rows {
+		if !r.ValidMainChain {
+			continue
+		}
+		txns[r.TxHash] = struct{}{}
+		if firstSeen == 0 || r.TxBlockTime < firstSeen {
+			firstSeen = r.TxBlockTime
+		}
+		if r.TxBlockTime > lastActivity {
+			lastActivity = r.TxBlockTime
+		}
+	}
+	return int64(len(txns)), firstSeen, lastActivity
+}
+
 // updateAddressRows updates address rows, or waits for them to update by an
 // ongoing query. On completion, the cache should be ready, although it must be
 // checked again. The returned []*dbtypes.AddressRow contains ALL non-merged
-// address transaction rows that were stored in the cache.
-func (pgb *ChainDB) updateAddressRows(address string) (rows []*dbtypes.AddressRow, err error) {
+// address transaction rows that were stored in the cache, as of the returned
+// block.
+func (pgb *ChainDB) updateAddressRows(ctx context.Context, address string) (rows []*dbtypes.AddressRow, blockID *cache.BlockID, err error) {
 	busy, wait, done := pgb.CacheLocks.rows.TryLock(address)
 	if busy {
 		// Just wait until the updater is finished.
@@ -2004,11 +3046,15 @@ func (pgb *ChainDB) updateAddressRows(address string) (rows []*dbtypes.AddressRo
 	pgb.AddressCache.ClearRows(address)
 
 	hash, height := pgb.BestBlock()
-	blockID := cache.NewBlockID(hash, height)
+	blockID = cache.NewBlockID(hash, height)
 
-	// Retrieve all non-merged address transaction rows.
-	rows, err = pgb.AddressTransactionsAll(address)
+	// Retrieve all non-merged address transaction rows as of the best block,
+	// even if newer blocks are stored during the query.
+	ctx, cancel := pgb.queryContext(ctx)
+	defer cancel()
+	rows, err = RetrieveAllMainchainAddressTxnsAtHeight(ctx, pgb.db, address, height)
 	if err != nil && err != sql.ErrNoRows {
+		err = pgb.replaceCancelError(err)
 		return
 	}
 
@@ -2019,7 +3065,7 @@ func (pgb *ChainDB) updateAddressRows(address string) (rows []*dbtypes.AddressRo
 
 // AddressRowsMerged gets the merged address rows either from cache or via DB
 // query.
-func (pgb *ChainDB) AddressRowsMerged(address string) ([]*dbtypes.AddressRowMerged, error) {
+func (pgb *ChainDB) AddressRowsMerged(ctx context.Context, address string) ([]*dbtypes.AddressRowMerged, error) {
 	// Try the address cache.
 	hash := pgb.BestBlockHash()
 	rowsCompact, validBlock := pgb.AddressCache.Rows(address)
@@ -2039,11 +3085,11 @@ func (pgb *ChainDB) AddressRowsMerged(address string) ([]*dbtypes.AddressRowMerg
 	log.Tracef("AddressRowsMerged: rows cache MISS for %s.", address)
 
 	// Update or wait for an update to the cached AddressRows.
-	rows, err := pgb.updateAddressRows(address)
+	rows, _, err := pgb.updateAddressRows(ctx, address)
 	if err != nil {
 		if IsRetryError(err) {
 			// Try again, starting with cache.
-			return pgb.AddressRowsMerged(address)
+			return pgb.AddressRowsMerged(ctx, address)
 		}
 		return nil, err
 	}
@@ -2054,7 +3100,7 @@ func (pgb *ChainDB) AddressRowsMerged(address string) ([]*dbtypes.AddressRowMerg
 
 // AddressRowsCompact gets non-merged address rows either from cache or via DB
 // query.
-func (pgb *ChainDB) AddressRowsCompact(address string) ([]*dbtypes.AddressRowCompact, error) {
+func (pgb *ChainDB) AddressRowsCompact(ctx context.Context, address string) ([]*dbtypes.AddressRowCompact, error) {
 	// Try the address cache.
 	hash := pgb.BestBlockHash()
 	rowsCompact, validBlock := pgb.AddressCache.Rows(address)
@@ -2074,11 +3120,11 @@ func (pgb *ChainDB) AddressRowsCompact(address string) ([]*dbtypes.AddressRowCom
 	log.Tracef("AddressRowsCompact: rows cache MISS for %s.", address)
 
 	// Update or wait for an update to the cached AddressRows.
-	rows, err := pgb.updateAddressRows(address)
+	rows, _, err := pgb.updateAddressRows(ctx, address)
 	if err != nil {
 		if IsRetryError(err) {
 			// Try again, starting with cache.
-			return pgb.AddressRowsCompact(address)
+			return pgb.AddressRowsCompact(ctx, address)
 		}
 		return nil, err
 	}
@@ -2089,8 +3135,8 @@ func (pgb *ChainDB) AddressRowsCompact(address string) ([]*dbtypes.AddressRowCom
 
 // retrieveMergedTxnCount queries the DB for the merged address transaction view
 // row count.
-func (pgb *ChainDB) retrieveMergedTxnCount(addr string, txnView dbtypes.AddrTxnViewType) (int, error) {
-	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+func (pgb *ChainDB) retrieveMergedTxnCount(ctx context.Context, addr string, txnView dbtypes.AddrTxnViewType) (int, error) {
+	ctx, cancel := pgb.queryContext(ctx)
 	defer cancel()
 
 	var count int64
@@ -2109,12 +3155,12 @@ func (pgb *ChainDB) retrieveMergedTxnCount(addr string, txnView dbtypes.AddrTxnV
 }
 
 // mergedTxnCount checks cache and falls back to retrieveMergedTxnCount.
-func (pgb *ChainDB) mergedTxnCount(addr string, txnView dbtypes.AddrTxnViewType) (int, error) {
+func (pgb *ChainDB) mergedTxnCount(ctx context.Context, addr string, txnView dbtypes.AddrTxnViewType) (int, error) {
 	// Try the cache first.
 	rows, blockID := pgb.AddressCache.Rows(addr)
 	if blockID == nil {
 		// Query the DB.
-		return pgb.retrieveMergedTxnCount(addr, txnView)
+		return pgb.retrieveMergedTxnCount(ctx, addr, txnView)
 	}
 
 	return dbtypes.CountMergedRowsCompact(rows, txnView)
@@ -2122,8 +3168,8 @@ func (pgb *ChainDB) mergedTxnCount(addr string, txnView dbtypes.AddrTxnViewType)
 
 // nonMergedTxnCount gets the non-merged address transaction view row count via
 // AddressBalance, which checks the cache and falls back to a DB query.
-func (pgb *ChainDB) nonMergedTxnCount(addr string, txnView dbtypes.AddrTxnViewType) (int, error) {
-	bal, _, err := pgb.AddressBalance(addr)
+func (pgb *ChainDB) nonMergedTxnCount(ctx context.Context, addr string, txnView dbtypes.AddrTxnViewType) (int, error) {
+	bal, _, err := pgb.AddressBalance(ctx, addr)
 	if err != nil {
 		return 0, err
 	}
@@ -2143,7 +3189,7 @@ func (pgb *ChainDB) nonMergedTxnCount(addr string, txnView dbtypes.AddrTxnViewTy
 
 // CountTransactions gets the total row count for the given address and address
 // transaction view.
-func (pgb *ChainDB) CountTransactions(addr string, txnView dbtypes.AddrTxnViewType) (int, error) {
+func (pgb *ChainDB) CountTransactions(ctx context.Context, addr string, txnView dbtypes.AddrTxnViewType) (int, error) {
 	merged, err := txnView.IsMerged()
 	if err != nil {
 		return 0, err
@@ -2154,7 +3200,7 @@ func (pgb *ChainDB) CountTransactions(addr string, txnView dbtypes.AddrTxnViewTy
 		countFn = pgb.mergedTxnCount
 	}
 
-	count, err := countFn(addr, txnView)
+	count, err := countFn(ctx, addr, txnView)
 	if err != nil {
 		return 0, err
 	}
@@ -2164,13 +3210,23 @@ func (pgb *ChainDB) CountTransactions(addr string, txnView dbtypes.AddrTxnViewTy
 // AddressHistory queries the database for rows of the addresses table
 // containing values for a certain type of transaction (all, credits, or debits)
 // for the given address.
-func (pgb *ChainDB) AddressHistory(address string, N, offset int64,
+func (pgb *ChainDB) AddressHistory(ctx context.Context, address string, N, offset int64,
 	txnView dbtypes.AddrTxnViewType) ([]*dbtypes.AddressRow, *dbtypes.AddressBalance, error) {
+	addressRows, balance, _, err := pgb.addressHistory(ctx, address, N, offset, txnView)
+	return addressRows, balance, err
+}
+
+// addressHistory is like AddressHistory, but also returns the block that the
+// address rows and balance are consistent with. This is the best block when
+// the rows were retrieved, even if newer blocks are stored while querying.
+func (pgb *ChainDB) addressHistory(ctx context.Context, address string, N, offset int64,
+	txnView dbtypes.AddrTxnViewType) ([]*dbtypes.AddressRow, *dbtypes.AddressBalance, *cache.BlockID, error) {
 	// Try the address rows cache.
 	hash, height := pgb.BestBlock()
+	block := cache.NewBlockID(hash, height)
 	addressRows, validBlock, err := pgb.AddressCache.Transactions(address, N, offset, txnView)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
 	cacheCurrent := validBlock != nil && validBlock.Hash == *hash
@@ -2182,35 +3238,36 @@ func (pgb *ChainDB) AddressHistory(address string, N, offset int64,
 
 		// Update or wait for an update to the cached AddressRows, returning ALL
 		// NON-MERGED address transaction rows.
-		addressRows, err = pgb.updateAddressRows(address)
+		addressRows, block, err = pgb.updateAddressRows(ctx, address)
 		if err != nil && err != sql.ErrNoRows {
 			// See if another caller ran the update, in which case we were just
 			// waiting to avoid a simultaneous query. With luck the cache will
 			// be updated with this data, although it may not be. Try again.
 			if IsRetryError(err) {
 				// Try again, starting with cache.
-				return pgb.AddressHistory(address, N, offset, txnView)
+				return pgb.addressHistory(ctx, address, N, offset, txnView)
 			}
-			return nil, nil, fmt.Errorf("failed to updateAddressRows: %v", err)
+			return nil, nil, nil, fmt.Errorf("failed to updateAddressRows: %v", err)
 		}
 
 		// Select the correct type and range of address rows, merging if needed.
 		addressRows, err = dbtypes.SliceAddressRows(addressRows, int(N), int(offset), txnView)
 		if err != nil {
-			return nil, nil, fmt.Errorf("failed to SliceAddressRows: %v", err)
+			return nil, nil, nil, fmt.Errorf("failed to SliceAddressRows: %v", err)
 		}
 	}
 	log.Debugf("Address rows (view=%s) cache HIT for %s.",
 		txnView.String(), address)
 
-	// addressRows is now present and current. Proceed to get the balance.
+	// addressRows is now present and current as of block. Proceed to get the
+	// balance as of the same block.
 
 	// Try the address balance cache.
 	balance, validBlock := pgb.AddressCache.Balance(address) // balance is a copy
-	cacheCurrent = validBlock != nil && validBlock.Hash == *hash
+	cacheCurrent = validBlock != nil && validBlock.Hash == block.Hash
 	if cacheCurrent {
 		log.Debugf("Address balance cache HIT for %s.", address)
-		return addressRows, balance, nil
+		return addressRows, balance, block, nil
 	}
 	log.Debugf("Address balance cache MISS for %s.", address)
 
@@ -2226,7 +3283,7 @@ func (pgb *ChainDB) AddressHistory(address string, N, offset int64,
 		} else {
 			addrInfo, fromStake, toStake := dbtypes.ReduceAddressHistory(addressRows)
 			if addrInfo == nil {
-				return addressRows, nil,
+				return addressRows, nil, nil,
 					fmt.Errorf("ReduceAddressHistory failed. len(addressRows) = %d",
 						len(addressRows))
 			}
@@ -2240,16 +3297,19 @@ func (pgb *ChainDB) AddressHistory(address string, N, offset int64,
 				FromStake:    fromStake,
 				ToStake:      toStake,
 			}
+			if numTxns, first, last := addressRowsSummary(dbtypes.CompactRows(addressRows)); numTxns > 0 {
+				balance.AddActivity(time.Unix(first, 0))
+				balance.AddActivity(time.Unix(last, 0))
+			}
 		}
 		// Update balance cache.
-		blockID := cache.NewBlockID(hash, height)
-		pgb.AddressCache.StoreBalance(address, balance, blockID) // a copy of balance is stored
+		pgb.AddressCache.StoreBalance(address, balance, block) // a copy of balance is stored
 	} else {
 		// Count spent/unspent amounts and transactions.
 		log.Debugf("Obtaining balance via DB query.")
-		balance, _, err = pgb.AddressBalance(address)
+		balance, _, err = pgb.addressBalance(ctx, address, block)
 		if err != nil && err != sql.ErrNoRows {
-			return nil, nil, err
+			return nil, nil, nil, err
 		}
 	}
 
@@ -2257,20 +3317,20 @@ func (pgb *ChainDB) AddressHistory(address string, N, offset int64,
 		address, balance.NumSpent, dcrutil.Amount(balance.TotalSpent).ToCoin(),
 		balance.NumUnspent, dcrutil.Amount(balance.TotalUnspent).ToCoin())
 	log.Infof("Receive count for address %s: count = %d at block %d.",
-		address, balance.NumSpent+balance.NumUnspent, height)
+		address, balance.NumSpent+balance.NumUnspent, block.Height)
 
-	return addressRows, balance, nil
+	return addressRows, balance, block, nil
 }
 
 // AddressData returns comprehensive, paginated information for an address.
-func (pgb *ChainDB) AddressData(address string, limitN, offsetAddrOuts int64,
+func (pgb *ChainDB) AddressData(ctx context.Context, address string, limitN, offsetAddrOuts int64,
 	txnType dbtypes.AddrTxnViewType) (addrData *dbtypes.AddressInfo, err error) {
 	merged, err := txnType.IsMerged()
 	if err != nil {
 		return nil, err
 	}
 
-	addrHist, balance, err := pgb.AddressHistory(address, limitN, offsetAddrOuts, txnType)
+	addrHist, balance, err := pgb.AddressHistory(ctx, address, limitN, offsetAddrOuts, txnType)
 	if dbtypes.IsTimeoutErr(err) {
 		return nil, err
 	}
@@ -2322,7 +3382,7 @@ func (pgb *ChainDB) AddressData(address string, limitN, offsetAddrOuts int64,
 		}
 		if addrData.IsMerged {
 			// For merged views, check the cache and fall back on a DB query.
-			count, err := pgb.mergedTxnCount(address, txnType)
+			count, err := pgb.mergedTxnCount(ctx, address, txnType)
 			if err != nil {
 				return nil, err
 			}
@@ -2348,7 +3408,7 @@ func (pgb *ChainDB) AddressData(address string, limitN, offsetAddrOuts int64,
 		}
 
 		// Query database for transaction details.
-		err = pgb.FillAddressTransactions(addrData)
+		err = pgb.FillAddressTransactions(ctx, addrData)
 		if dbtypes.IsTimeoutErr(err) {
 			return nil, err
 		}
@@ -2491,8 +3551,8 @@ SPENDING_TX_DUPLICATE_CHECK:
 // DbTxByHash retrieves a row of the transactions table corresponding to the
 // given transaction hash. Transactions in valid and mainchain blocks are chosen
 // first.
-func (pgb *ChainDB) DbTxByHash(txid string) (*dbtypes.Tx, error) {
-	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+func (pgb *ChainDB) DbTxByHash(ctx context.Context, txid string) (*dbtypes.Tx, error) {
+	ctx, cancel := pgb.queryContext(ctx)
 	defer cancel()
 	_, dbTx, err := RetrieveDbTxByHash(ctx, pgb.db, txid)
 	return dbTx, pgb.replaceCancelError(err)
@@ -2501,8 +3561,8 @@ func (pgb *ChainDB) DbTxByHash(txid string) (*dbtypes.Tx, error) {
 // FundingOutpointIndxByVinID retrieves the the transaction output index of the
 // previous outpoint for a transaction input specified by row ID in the vins
 // table, which stores previous outpoints for each vin.
-func (pgb *ChainDB) FundingOutpointIndxByVinID(id uint64) (uint32, error) {
-	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+func (pgb *ChainDB) FundingOutpointIndxByVinID(ctx context.Context, id uint64) (uint32, error) {
+	ctx, cancel := pgb.queryContext(ctx)
 	defer cancel()
 	ind, err := RetrieveFundingOutpointIndxByVinID(ctx, pgb.db, id)
 	return ind, pgb.replaceCancelError(err)
@@ -2512,7 +3572,7 @@ func (pgb *ChainDB) FundingOutpointIndxByVinID(id uint64) (uint32, error) {
 // explorer.AddressInfo generated by dbtypes.ReduceAddressHistory, usually from
 // the output of AddressHistory. This function also sets the number of
 // unconfirmed transactions for the current best block in the database.
-func (pgb *ChainDB) FillAddressTransactions(addrInfo *dbtypes.AddressInfo) error {
+func (pgb *ChainDB) FillAddressTransactions(ctx context.Context, addrInfo *dbtypes.AddressInfo) error {
 	if addrInfo == nil {
 		return nil
 	}
@@ -2522,13 +3582,15 @@ func (pgb *ChainDB) FillAddressTransactions(addrInfo *dbtypes.AddressInfo) error
 	for i, txn := range addrInfo.Transactions {
 		// Retrieve the most valid, most mainchain, and most recent tx with this
 		// hash. This means it prefers mainchain and valid blocks first.
-		dbTx, err := pgb.DbTxByHash(txn.TxID)
+		dbTx, err := pgb.DbTxByHash(ctx, txn.TxID)
 		if err != nil {
 			return err
 		}
 		txn.Size = dbTx.Size
 		txn.FormattedSize = humanize.Bytes(uint64(dbTx.Size))
 		txn.Total = dcrutil.Amount(dbTx.Sent).ToCoin()
+		txn.Fee = dcrutil.Amount(dbTx.Fees)
+		txn.FeeRate = dcrutil.Amount(dbTx.FeeRate)
 		txn.Time = dbTx.BlockTime
 		if txn.Time.UNIX() > 0 {
 			txn.Confirmations = uint64(pgb.Height() - dbTx.BlockHeight + 1)
@@ -2545,7 +3607,7 @@ func (pgb *ChainDB) FillAddressTransactions(addrInfo *dbtypes.AddressInfo) error
 			if !txn.IsFunding {
 				// Spending transaction: lookup the previous outpoint's txout
 				// index by the vins table row ID.
-				idx, err := pgb.FundingOutpointIndxByVinID(dbTx.VinDbIds[txn.InOutID])
+				idx, err := pgb.FundingOutpointIndxByVinID(ctx, dbTx.VinDbIds[txn.InOutID])
 				if err != nil {
 					log.Warnf("Matched Transaction Lookup failed for %s:%d: id: %d:  %v",
 						txn.TxID, txn.InOutID, txn.InOutID, err)
@@ -2555,7 +3617,7 @@ func (pgb *ChainDB) FillAddressTransactions(addrInfo *dbtypes.AddressInfo) error
 			} else {
 				// Funding transaction: lookup by the matching (spending) tx
 				// hash and tx index.
-				_, idx, _, err := pgb.SpendingTransaction(txn.TxID, txn.InOutID)
+				_, idx, _, err := pgb.SpendingTransaction(ctx, txn.TxID, txn.InOutID)
 				if err != nil {
 					log.Warnf("Matched Transaction Lookup failed for %s:%d: %v",
 						txn.TxID, txn.InOutID, err)
@@ -2573,14 +3635,14 @@ func (pgb *ChainDB) FillAddressTransactions(addrInfo *dbtypes.AddressInfo) error
 
 // AddressTotals queries for the following totals: amount spent, amount unspent,
 // number of unspent transaction outputs and number spent.
-func (pgb *ChainDB) AddressTotals(address string) (*apitypes.AddressTotals, error) {
+func (pgb *ChainDB) AddressTotals(ctx context.Context, address string) (*apitypes.AddressTotals, error) {
 	// Fetch address totals
 	var err error
 	var ab *dbtypes.AddressBalance
 	if address == pgb.devAddress {
 		ab, err = pgb.DevBalance()
 	} else {
-		ab, _, err = pgb.AddressBalance(address)
+		ab, _, err = pgb.AddressBalance(ctx, address)
 	}
 
 	if err != nil || ab == nil {
@@ -2597,9 +3659,80 @@ func (pgb *ChainDB) AddressTotals(address string) (*apitypes.AddressTotals, erro
 		NumUnspent:   ab.NumUnspent,
 		CoinsSpent:   dcrutil.Amount(ab.TotalSpent).ToCoin(),
 		CoinsUnspent: dcrutil.Amount(ab.TotalUnspent).ToCoin(),
+		FirstSeen:    ab.FirstSeen,
+		LastActivity: ab.LastActivity,
+	}, nil
+}
+
+// AddressTxCounts retrieves the numbers of valid mainchain funding and spending
+// rows of the address from the address_counts table, which is updated as
+// blocks are stored, rather than counting the rows of the addresses table.
+func (pgb *ChainDB) AddressTxCounts(ctx context.Context, address string) (*apitypes.AddressTxCounts, error) {
+	ctx, cancel := pgb.queryContext(ctx)
+	defer cancel()
+	bestHash, bestHeight := pgb.BestBlockStr()
+	numFunding, numSpending, err := RetrieveAddressCounts(ctx, pgb.db, address)
+	if err != nil {
+		return nil, pgb.replaceCancelError(err)
+	}
+	return &apitypes.AddressTxCounts{
+		Address:     address,
+		BlockHash:   bestHash,
+		BlockHeight: uint64(bestHeight),
+		NumFunding:  numFunding,
+		NumSpending: numSpending,
 	}, nil
 }
 
+// AddressBalanceAt computes the confirmed balance of the address as of the
+// mainchain block at the given height. This may be used to audit the balance
+// of an address at an arbitrary point in its history.
+func (pgb *ChainDB) AddressBalanceAt(ctx context.Context, address string, height int64) (*dbtypes.HistoricalAddressBalance, error) {
+	if height < 0 || height > pgb.Height() {
+		return nil, fmt.Errorf("invalid height %d", height)
+	}
+	ctx, cancel := pgb.queryContext(ctx)
+	defer cancel()
+	bal, err := RetrieveHistoricalAddressBalance(ctx, pgb.db, address, height)
+	return bal, pgb.replaceCancelError(err)
+}
+
+// BlockHeightAtTime gets the height of the mainchain block nearest the given
+// UNIX time: the last block at or before the time, which was the chain tip at
+// that time, or the first block at or after the time if after is true. Since
+// block timestamps do not strictly increase with height, these are the highest
+// and lowest blocks on either side of the time. The height is -1 if there is
+// no such block.
+func (pgb *ChainDB) BlockHeightAtTime(ctx context.Context, t int64, after bool) (int64, error) {
+	ctx, cancel := pgb.queryContext(ctx)
+	defer cancel()
+	retrieve := RetrieveBlockHeightByTime
+	if after {
+		retrieve = RetrieveBlockHeightAfterTime
+	}
+	height, err := retrieve(ctx, pgb.db, time.Unix(t, 0))
+	if err == sql.ErrNoRows {
+		return -1, nil
+	}
+	return height, pgb.replaceCancelError(err)
+}
+
+// AddressBalanceAtTime computes the confirmed balance of the address as of the
+// last mainchain block mined at or before the given UNIX time.
+func (pgb *ChainDB) AddressBalanceAtTime(ctx context.Context, address string, t int64) (*dbtypes.HistoricalAddressBalance, error) {
+	ctx, cancel := pgb.queryContext(ctx)
+	defer cancel()
+	height, err := RetrieveBlockHeightByTime(ctx, pgb.db, time.Unix(t, 0))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("no blocks at or before time %d", t)
+		}
+		return nil, pgb.replaceCancelError(err)
+	}
+	bal, err := RetrieveHistoricalAddressBalance(ctx, pgb.db, address, height)
+	return bal, pgb.replaceCancelError(err)
+}
+
 // MakeCsvAddressRows converts an AddressRow slice into a [][]string, including
 // column headers, suitable for saving to CSV.
 func MakeCsvAddressRows(rows []*dbtypes.AddressRow) [][]string {
@@ -2672,8 +3805,8 @@ func MakeCsvAddressRowsCompact(rows []*dbtypes.AddressRowCompact) [][]string {
 
 // AddressTxIoCsv grabs rows of an address' transaction input/output data as a
 // 2-D array of strings to be CSV-formatted.
-func (pgb *ChainDB) AddressTxIoCsv(address string) ([][]string, error) {
-	rows, err := pgb.AddressRowsCompact(address)
+func (pgb *ChainDB) AddressTxIoCsv(ctx context.Context, address string) ([][]string, error) {
+	rows, err := pgb.AddressRowsCompact(ctx, address)
 	if err != nil {
 		return nil, err
 	}
@@ -2725,7 +3858,7 @@ func (pgb *ChainDB) AddressTxIoCsv(address string) ([][]string, error) {
 
 	// log.Debugf("AddressTxIoCsv: Merged address rows cache MISS for %s.", address)
 
-	// ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	// ctx, cancel := pgb.queryContext(ctx)
 	// defer cancel()
 
 	// csvRows, err := retrieveAddressIoCsv(ctx, pgb.db, address)
@@ -2735,18 +3868,18 @@ func (pgb *ChainDB) AddressTxIoCsv(address string) ([][]string, error) {
 	// return csvRows, nil
 }
 
-func (pgb *ChainDB) addressInfo(addr string, count, skip int64, txnType dbtypes.AddrTxnViewType) (*dbtypes.AddressInfo, *dbtypes.AddressBalance, error) {
+func (pgb *ChainDB) addressInfo(ctx context.Context, addr string, count, skip int64, txnType dbtypes.AddrTxnViewType) (*dbtypes.AddressInfo, *dbtypes.AddressBalance, *cache.BlockID, error) {
 	address, err := dcrutil.DecodeAddress(addr, pgb.chainParams)
 	if err != nil {
 		log.Infof("Invalid address %s: %v", addr, err)
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
 	// Get rows from the addresses table for the address
-	addrHist, balance, err := pgb.AddressHistory(addr, count, skip, txnType)
+	addrHist, balance, block, err := pgb.addressHistory(ctx, addr, count, skip, txnType)
 	if err != nil {
 		log.Errorf("Unable to get address %s history: %v", address, err)
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
 	// Generate AddressInfo skeleton from the address table rows
@@ -2754,10 +3887,10 @@ func (pgb *ChainDB) addressInfo(addr string, count, skip int64, txnType dbtypes.
 	if addrData == nil {
 		// Empty history is not expected for credit txnType with any txns.
 		if txnType != dbtypes.AddrTxnDebit && (balance.NumSpent+balance.NumUnspent) > 0 {
-			return nil, nil, fmt.Errorf("empty address history (%s): n=%d&start=%d", address, count, skip)
+			return nil, nil, nil, fmt.Errorf("empty address history (%s): n=%d&start=%d", address, count, skip)
 		}
 		// No mined transactions. Return Address with nil Transactions slice.
-		return nil, balance, nil
+		return nil, balance, block, nil
 	}
 
 	// Transactions to fetch with FillAddressTransactions. This should be a
@@ -2770,35 +3903,90 @@ func (pgb *ChainDB) addressInfo(addr string, count, skip int64, txnType dbtypes.
 		addrData.Transactions = addrData.TxnsSpending
 	default:
 		// shouldn't happen because AddressHistory does this check
-		return nil, nil, fmt.Errorf("unknown address transaction type: %v", txnType)
+		return nil, nil, nil, fmt.Errorf("unknown address transaction type: %v", txnType)
 	}
 
 	// Query database for transaction details
-	err = pgb.FillAddressTransactions(addrData)
+	err = pgb.FillAddressTransactions(ctx, addrData)
 	if err != nil {
-		return nil, balance, fmt.Errorf("Unable to fill address %s transactions: %v", address, err)
+		return nil, balance, nil, fmt.Errorf("Unable to fill address %s transactions: %v", address, err)
 	}
 
-	return addrData, balance, nil
+	return addrData, balance, block, nil
 }
 
 // AddressTransactionDetails returns an apitypes.Address with at most the last
 // count transactions of type txnType in which the address was involved,
-// starting after skip transactions. This does NOT include unconfirmed
-// transactions.
-func (pgb *ChainDB) AddressTransactionDetails(addr string, count, skip int64,
-	txnType dbtypes.AddrTxnViewType) (*apitypes.Address, error) {
+// starting after skip transactions, and the cursor of the next page. Only the
+// AddrTxnAll view is paged by cursor. In the merged views, each transaction is
+// a single entry with the address' net amount. This does NOT include
+// unconfirmed transactions. The transactions are consistent with the returned
+// tip block, even if newer blocks are stored while querying.
+func (pgb *ChainDB) AddressTransactionDetails(ctx context.Context, addr string, count, skip int64,
+	txnType dbtypes.AddrTxnViewType) (*apitypes.Address, *dbtypes.PageCursor, error) {
 	// Fetch address history for given transaction range and type
-	addrData, _, err := pgb.addressInfo(addr, count, skip, txnType)
+	addrData, _, block, err := pgb.addressInfo(ctx, addr, count, skip, txnType)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	txs := addressTxnsShort(addr, addrData)
+	txs.TipHash, txs.TipHeight = block.Hash.String(), block.Height
+	if txnType != dbtypes.AddrTxnAll {
+		return txs, nil, nil
 	}
+	return txs, addressTxnsCursor(addrData, count), nil
+}
+
+// AddressTransactionDetailsByTime returns an apitypes.Address with at most
+// count of the address' valid mainchain transactions with a block time in the
+// range [from, to], newest first, starting after skip transactions or after
+// the cursor if it is not nil, and the cursor of the next page.
+func (pgb *ChainDB) AddressTransactionDetailsByTime(ctx context.Context, addr string,
+	from, to time.Time, count, skip int64, after *dbtypes.PageCursor) (*apitypes.Address, *dbtypes.PageCursor, error) {
+	ctx, cancel := pgb.queryContext(ctx)
+	defer cancel()
+
+	addrHist, err := RetrieveAddressTxnsByTimeRange(ctx, pgb.db, pgb.archive, addr,
+		from, to, count, skip, after)
+	if err != nil {
+		return nil, nil, pgb.replaceCancelError(err)
+	}
+
+	addrData, _, _ := dbtypes.ReduceAddressHistory(addrHist)
+	if addrData != nil {
+		if err = pgb.FillAddressTransactions(ctx, addrData); err != nil {
+			return nil, nil, fmt.Errorf("Unable to fill address %s transactions: %v", addr, err)
+		}
+	}
+	return addressTxnsShort(addr, addrData), addressTxnsCursor(addrData, count), nil
+}
+
+// addressTxnsCursor returns the cursor of the page after a full page of count
+// address rows, which is nil if the page is not full. addrData may be nil if
+// there are no transactions.
+func addressTxnsCursor(addrData *dbtypes.AddressInfo, count int64) *dbtypes.PageCursor {
+	if addrData == nil || int64(len(addrData.Transactions)) != count {
+		return nil
+	}
+	last := addrData.Transactions[len(addrData.Transactions)-1]
+	return &dbtypes.PageCursor{
+		Kind:      dbtypes.CursorAddressRows,
+		BlockTime: last.Time.UNIX(),
+		TxHash:    last.TxID,
+		IsFunding: last.IsFunding,
+		IOIndex:   last.InOutID,
+	}
+}
+
+// addressTxnsShort converts the transactions in the AddressInfo to an
+// apitypes.Address. addrData may be nil if there are no transactions.
+func addressTxnsShort(addr string, addrData *dbtypes.AddressInfo) *apitypes.Address {
 	// No transactions found. Not an error.
 	if addrData == nil {
 		return &apitypes.Address{
 			Address:      addr,
 			Transactions: make([]*apitypes.AddressTxShort, 0), // not nil for JSON formatting
-		}, nil
+		}
 	}
 
 	// Convert each dbtypes.AddressTx to apitypes.AddressTxShort
@@ -2809,16 +3997,23 @@ func (pgb *ChainDB) AddressTransactionDetails(addr string, count, skip int64,
 			TxID:          txs[i].TxID,
 			Time:          apitypes.TimeAPI{S: txs[i].Time},
 			Value:         txs[i].Total,
+			Fees:          txs[i].Fee.ToCoin(),
+			FeeRate:       txs[i].FeeRate.ToCoin(),
 			Confirmations: int64(txs[i].Confirmations),
 			Size:          int32(txs[i].Size),
 		})
+		if txs[i].MergedTxnCount > 0 {
+			net := txs[i].ReceivedTotal - txs[i].SentTotal
+			txsShort[i].NetAmount = &net
+			txsShort[i].MergedCount = txs[i].MergedTxnCount
+		}
 	}
 
 	// put a bow on it
 	return &apitypes.Address{
 		Address:      addr,
 		Transactions: txsShort,
-	}, nil
+	}
 }
 
 // UpdateChainState updates the blockchain's state, which includes each of the
@@ -2911,7 +4106,14 @@ func (pgb *ChainDB) Store(blockData *blockdata.BlockData, msgBlock *wire.MsgBloc
 		return nil
 	}
 
-	// update blockchain state
+	if !pgb.beginWrite() {
+		return ErrShuttingDown
+	}
+	defer pgb.endWrite()
+
+	// update blockchain state, keeping the previous agenda statuses to detect
+	// voting milestones reached with this block.
+	prevChainInfo := pgb.ChainInfo()
 	pgb.UpdateChainState(blockData.BlockchainInfo)
 
 	// New blocks stored this way are considered valid and part of mainchain,
@@ -2931,6 +4133,12 @@ func (pgb *ChainDB) Store(blockData *blockdata.BlockData, msgBlock *wire.MsgBloc
 	// Signal updates to any subscribed heightClients.
 	pgb.SignalHeight(msgBlock.Header.Height)
 
+	if err == nil {
+		pgb.storeStakeDiffEstimate(msgBlock, &blockData.EstStakeDiff)
+		pgb.notifyBlock(msgBlock)
+		pgb.checkAgendaStatus(prevChainInfo, msgBlock)
+	}
+
 	return err
 }
 
@@ -3012,7 +4220,7 @@ func (pgb *ChainDB) RewindStakeDB(ctx context.Context, toHeight int64, quiet ...
 
 // TxHistoryData fetches the address history chart data for specified chart
 // type and time grouping.
-func (pgb *ChainDB) TxHistoryData(address string, addrChart dbtypes.HistoryChart,
+func (pgb *ChainDB) TxHistoryData(ctx context.Context, address string, addrChart dbtypes.HistoryChart,
 	chartGroupings dbtypes.TimeBasedGrouping) (cd *dbtypes.ChartsData, err error) {
 	// First check cache for this address' chart data of the given type and
 	// interval.
@@ -3040,7 +4248,7 @@ func (pgb *ChainDB) TxHistoryData(address string, addrChart dbtypes.HistoryChart
 		<-wait
 
 		// Try again, starting with the cache.
-		return pgb.TxHistoryData(address, addrChart, chartGroupings)
+		return pgb.TxHistoryData(ctx, address, addrChart, chartGroupings)
 	}
 
 	// We will run the DB query, so block others from doing the same. When query
@@ -3050,7 +4258,7 @@ func (pgb *ChainDB) TxHistoryData(address string, addrChart dbtypes.HistoryChart
 
 	timeInterval := chartGroupings.String()
 
-	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	ctx, cancel := pgb.queryContext(ctx)
 	defer cancel()
 
 	switch addrChart {
@@ -3076,17 +4284,31 @@ func (pgb *ChainDB) TxHistoryData(address string, addrChart dbtypes.HistoryChart
 
 // TicketsByPrice returns chart data for tickets grouped by price. maturityBlock
 // is used to define when tickets are considered live.
-func (pgb *ChainDB) TicketsByPrice(maturityBlock int64) (*dbtypes.PoolTicketsData, error) {
-	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+func (pgb *ChainDB) TicketsByPrice(ctx context.Context, maturityBlock int64) (*dbtypes.PoolTicketsData, error) {
+	ctx, cancel := pgb.queryContext(ctx)
 	defer cancel()
 	ptd, err := retrieveTicketByPrice(ctx, pgb.db, maturityBlock)
 	return ptd, pgb.replaceCancelError(err)
 }
 
+// ticketAgeBuckets is the number of buckets, each an equal fraction of the
+// ticket expiry, in the ticket pool age chart.
+const ticketAgeBuckets = 20
+
+// TicketsByAge returns chart data for live tickets grouped by the number of
+// blocks since purchase. maturityBlock is used to define when tickets are
+// considered live.
+func (pgb *ChainDB) TicketsByAge(ctx context.Context, maturityBlock int64) (*dbtypes.PoolTicketsData, error) {
+	ctx, cancel := pgb.queryContext(ctx)
+	defer cancel()
+	ptd, err := retrieveTicketsByAge(ctx, pgb.db, maturityBlock, pgb.chainParams, ticketAgeBuckets)
+	return ptd, pgb.replaceCancelError(err)
+}
+
 // TicketsByInputCount returns chart data for tickets grouped by number of
 // inputs.
-func (pgb *ChainDB) TicketsByInputCount() (*dbtypes.PoolTicketsData, error) {
-	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+func (pgb *ChainDB) TicketsByInputCount(ctx context.Context) (*dbtypes.PoolTicketsData, error) {
+	ctx, cancel := pgb.queryContext(ctx)
 	defer cancel()
 	ptd, err := retrieveTicketsGroupedByType(ctx, pgb.db)
 	return ptd, pgb.replaceCancelError(err)
@@ -3095,8 +4317,8 @@ func (pgb *ChainDB) TicketsByInputCount() (*dbtypes.PoolTicketsData, error) {
 // windowStats fetches the charts data from retrieveWindowStats.
 // This is the Fetcher half of a pair that make up a cache.ChartUpdater. The
 // Appender half is appendWindowStats.
-func (pgb *ChainDB) windowStats(charts *cache.ChartData) (*sql.Rows, func(), error) {
-	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+func (pgb *ChainDB) windowStats(ctx context.Context, charts *cache.ChartData) (*sql.Rows, func(), error) {
+	ctx, cancel := pgb.queryContext(ctx)
 
 	rows, err := retrieveWindowStats(ctx, pgb.db, charts)
 	if err != nil {
@@ -3109,8 +4331,8 @@ func (pgb *ChainDB) windowStats(charts *cache.ChartData) (*sql.Rows, func(), err
 // missedVotesStats fetches the charts data from retrieveMissedVotes.
 // This is the Fetcher half of a pair that make up a cache.ChartUpdater. The
 // Appender half is appendMissedVotes.
-func (pgb *ChainDB) missedVotesStats(charts *cache.ChartData) (*sql.Rows, func(), error) {
-	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+func (pgb *ChainDB) missedVotesStats(ctx context.Context, charts *cache.ChartData) (*sql.Rows, func(), error) {
+	ctx, cancel := pgb.queryContext(ctx)
 
 	rows, err := retrieveMissedVotes(ctx, pgb.db, charts)
 	if err != nil {
@@ -3120,11 +4342,25 @@ func (pgb *ChainDB) missedVotesStats(charts *cache.ChartData) (*sql.Rows, func()
 	return rows, cancel, nil
 }
 
+// ticketFeeRates fetches the charts data from retrieveTicketFeeRates. This is
+// the Fetcher half of a pair that make up a cache.ChartUpdater. The Appender
+// half is appendTicketFeeRatesPerWindow.
+func (pgb *ChainDB) ticketFeeRates(ctx context.Context, charts *cache.ChartData) (*sql.Rows, func(), error) {
+	ctx, cancel := pgb.queryContext(ctx)
+
+	rows, err := retrieveTicketFeeRates(ctx, pgb.db, charts)
+	if err != nil {
+		return nil, cancel, fmt.Errorf("ticketFeeRates: %v", pgb.replaceCancelError(err))
+	}
+
+	return rows, cancel, nil
+}
+
 // chartBlocks sets or updates a series of per-block datasets.
 // This is the Fetcher half of a pair that make up a cache.ChartUpdater. The
 // Appender half is appendChartBlocks.
-func (pgb *ChainDB) chartBlocks(charts *cache.ChartData) (*sql.Rows, func(), error) {
-	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+func (pgb *ChainDB) chartBlocks(ctx context.Context, charts *cache.ChartData) (*sql.Rows, func(), error) {
+	ctx, cancel := pgb.queryContext(ctx)
 
 	rows, err := retrieveChartBlocks(ctx, pgb.db, charts)
 	if err != nil {
@@ -3136,8 +4372,8 @@ func (pgb *ChainDB) chartBlocks(charts *cache.ChartData) (*sql.Rows, func(), err
 // coinSupply fetches the coin supply chart data from retrieveCoinSupply.
 // This is the Fetcher half of a pair that make up a cache.ChartUpdater. The
 // Appender half is appendCoinSupply.
-func (pgb *ChainDB) coinSupply(charts *cache.ChartData) (*sql.Rows, func(), error) {
-	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+func (pgb *ChainDB) coinSupply(ctx context.Context, charts *cache.ChartData) (*sql.Rows, func(), error) {
+	ctx, cancel := pgb.queryContext(ctx)
 
 	rows, err := retrieveCoinSupply(ctx, pgb.db, charts)
 	if err != nil {
@@ -3148,9 +4384,9 @@ func (pgb *ChainDB) coinSupply(charts *cache.ChartData) (*sql.Rows, func(), erro
 }
 
 // txPerDay fetches the tx-per-day chart data from retrieveTxPerDay.
-func (pgb *ChainDB) txPerDay(timeArr []dbtypes.TimeDef, txCountArr []uint64) (
+func (pgb *ChainDB) txPerDay(ctx context.Context, timeArr []dbtypes.TimeDef, txCountArr []uint64) (
 	[]dbtypes.TimeDef, []uint64, error) {
-	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	ctx, cancel := pgb.queryContext(ctx)
 	defer cancel()
 
 	var err error
@@ -3165,8 +4401,8 @@ func (pgb *ChainDB) txPerDay(timeArr []dbtypes.TimeDef, txCountArr []uint64) (
 // blockFees sets or updates a series of per-block fees.
 // This is the Fetcher half of a pair that make up a cache.ChartUpdater. The
 // Appender half is appendBlockFees.
-func (pgb *ChainDB) blockFees(charts *cache.ChartData) (*sql.Rows, func(), error) {
-	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+func (pgb *ChainDB) blockFees(ctx context.Context, charts *cache.ChartData) (*sql.Rows, func(), error) {
+	ctx, cancel := pgb.queryContext(ctx)
 
 	rows, err := retrieveBlockFees(ctx, pgb.db, charts)
 	if err != nil {
@@ -3175,11 +4411,24 @@ func (pgb *ChainDB) blockFees(charts *cache.ChartData) (*sql.Rows, func(), error
 	return rows, cancel, nil
 }
 
+// txTypes sets or updates the series of per-block transaction counts and sent
+// values by transaction type. This is the Fetcher half of a pair that make up a
+// cache.ChartUpdater. The Appender half is appendTxTypes.
+func (pgb *ChainDB) txTypes(ctx context.Context, charts *cache.ChartData) (*sql.Rows, func(), error) {
+	ctx, cancel := pgb.queryContext(ctx)
+
+	rows, err := retrieveTxTypes(ctx, pgb.db, charts)
+	if err != nil {
+		return nil, cancel, fmt.Errorf("txTypes: %v", pgb.replaceCancelError(err))
+	}
+	return rows, cancel, nil
+}
+
 // appendAnonymitySet sets or updates a series of per-block privacy
 // participation. This is the Fetcher half of a pair that make up a
 // cache.ChartUpdater. The Appender half is appendPrivacyParticipation.
-func (pgb *ChainDB) privacyParticipation(charts *cache.ChartData) (*sql.Rows, func(), error) {
-	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+func (pgb *ChainDB) privacyParticipation(ctx context.Context, charts *cache.ChartData) (*sql.Rows, func(), error) {
+	ctx, cancel := pgb.queryContext(ctx)
 
 	rows, err := retrievePrivacyParticipation(ctx, pgb.db, charts)
 	if err != nil {
@@ -3194,7 +4443,7 @@ func (pgb *ChainDB) privacyParticipation(charts *cache.ChartData) (*sql.Rows, fu
 // returned with a nil error as a signal to the appender. This is the Fetcher
 // half of a pair that make up a cache.ChartUpdater. The Appender half is
 // appendAnonymitySet.
-func (pgb *ChainDB) anonymitySet(charts *cache.ChartData) (*sql.Rows, func(), error) {
+func (pgb *ChainDB) anonymitySet(ctx context.Context, charts *cache.ChartData) (*sql.Rows, func(), error) {
 	// First check if the necessary data is available in mixSetDiffs.
 	nextDataHeight := uint32(len(charts.Blocks.AnonymitySet))
 	targetDataHeight := uint32(len(charts.Blocks.Height) - 1)
@@ -3206,7 +4455,7 @@ func (pgb *ChainDB) anonymitySet(charts *cache.ChartData) (*sql.Rows, func(), er
 		if _, found := pgb.mixSetDiffs[h]; !found {
 			log.Debugf("Mixed set deltas not available at height %d. Querying DB...", h)
 			// A DB query is necessary.
-			return pgb.retrieveAnonymitySet(int32(nextDataHeight) - 1) // -1 means include genesis
+			return pgb.retrieveAnonymitySet(ctx, int32(nextDataHeight)-1) // -1 means include genesis
 		}
 	}
 
@@ -3217,8 +4466,8 @@ func (pgb *ChainDB) anonymitySet(charts *cache.ChartData) (*sql.Rows, func(), er
 // retrieveAnonymitySet fetches the mixed output fund/spend heights and values
 // for outputs funded after bestHeight. To include all blocks including genesis
 // use -1 for bestHeight.
-func (pgb *ChainDB) retrieveAnonymitySet(bestHeight int32) (*sql.Rows, func(), error) {
-	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+func (pgb *ChainDB) retrieveAnonymitySet(ctx context.Context, bestHeight int32) (*sql.Rows, func(), error) {
+	ctx, cancel := pgb.queryContext(ctx)
 	rows, err := pgb.db.QueryContext(ctx, internal.SelectMixedVouts, bestHeight)
 	if err != nil {
 		return nil, cancel, fmt.Errorf("chartBlocks: %v", pgb.replaceCancelError(err))
@@ -3279,8 +4528,8 @@ func (pgb *ChainDB) appendAnonymitySet(charts *cache.ChartData, rows *sql.Rows)
 // poolStats sets or updates a series of per-height ticket pool statistics.
 // This is the Fetcher half of a pair that make up a cache.ChartUpdater. The
 // Appender half is appendPoolStats.
-func (pgb *ChainDB) poolStats(charts *cache.ChartData) (*sql.Rows, func(), error) {
-	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+func (pgb *ChainDB) poolStats(ctx context.Context, charts *cache.ChartData) (*sql.Rows, func(), error) {
+	ctx, cancel := pgb.queryContext(ctx)
 
 	rows, err := retrievePoolStats(ctx, pgb.db, charts)
 	if err != nil {
@@ -3289,10 +4538,36 @@ func (pgb *ChainDB) poolStats(charts *cache.ChartData) (*sql.Rows, func(), error
 	return rows, cancel, nil
 }
 
+// mempoolBacklog sets or updates a series of the mempool size when each block
+// was mined. This is the Fetcher half of a pair that make up a
+// cache.ChartUpdater. The Appender half is appendMempoolBacklog.
+func (pgb *ChainDB) mempoolBacklog(ctx context.Context, charts *cache.ChartData) (*sql.Rows, func(), error) {
+	ctx, cancel := pgb.queryContext(ctx)
+
+	rows, err := retrieveMempoolBacklog(ctx, pgb.db, charts)
+	if err != nil {
+		return nil, cancel, fmt.Errorf("mempoolBacklog: %v", pgb.replaceCancelError(err))
+	}
+	return rows, cancel, nil
+}
+
+// liveTicketChanges fetches the change in the number of live tickets by
+// purchase type in each block. This is the Fetcher half of a pair that make up
+// a cache.ChartUpdater. The Appender half is appendLiveTicketChanges.
+func (pgb *ChainDB) liveTicketChanges(ctx context.Context, charts *cache.ChartData) (*sql.Rows, func(), error) {
+	ctx, cancel := pgb.queryContext(ctx)
+
+	rows, err := retrieveLiveTicketChanges(ctx, pgb.db, charts, pgb.chainParams)
+	if err != nil {
+		return nil, cancel, fmt.Errorf("liveTicketChanges: %v", pgb.replaceCancelError(err))
+	}
+	return rows, cancel, nil
+}
+
 // PowerlessTickets fetches all missed and expired tickets, sorted by revocation
 // status.
-func (pgb *ChainDB) PowerlessTickets() (*apitypes.PowerlessTickets, error) {
-	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+func (pgb *ChainDB) PowerlessTickets(ctx context.Context) (*apitypes.PowerlessTickets, error) {
+	ctx, cancel := pgb.queryContext(ctx)
 	defer cancel()
 	return retrievePowerlessTickets(ctx, pgb.db)
 }
@@ -3301,9 +4576,9 @@ func (pgb *ChainDB) PowerlessTickets() (*apitypes.PowerlessTickets, error) {
 // retrieveTicketByOutputCount
 // This chart has been deprecated. Leaving ticketsByBlocks for possible future
 // re-appropriation, says buck54321 on April 24, 2019.
-func (pgb *ChainDB) ticketsByBlocks(heightArr, soloArr, pooledArr []uint64) ([]uint64,
+func (pgb *ChainDB) ticketsByBlocks(ctx context.Context, heightArr, soloArr, pooledArr []uint64) ([]uint64,
 	[]uint64, []uint64, error) {
-	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	ctx, cancel := pgb.queryContext(ctx)
 	defer cancel()
 
 	var err error
@@ -3320,9 +4595,9 @@ func (pgb *ChainDB) ticketsByBlocks(heightArr, soloArr, pooledArr []uint64) ([]u
 // from retrieveTicketByOutputCount.
 // This chart has been deprecated. Leaving ticketsByTPWindows for possible
 // future re-appropriation, says buck54321 on April 24, 2019.
-func (pgb *ChainDB) ticketsByTPWindows(heightArr, soloArr, pooledArr []uint64) ([]uint64,
+func (pgb *ChainDB) ticketsByTPWindows(ctx context.Context, heightArr, soloArr, pooledArr []uint64) ([]uint64,
 	[]uint64, []uint64, error) {
-	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	ctx, cancel := pgb.queryContext(ctx)
 	defer cancel()
 
 	var err error
@@ -3411,8 +4686,8 @@ func (pgb *ChainDB) setVinsMainchainOneTxn(vinDbIDs dbtypes.UInt64Array,
 // PkScriptByVinID retrieves the pkScript and script version for the row of the
 // vouts table corresponding to the previous output of the vin specified by row
 // ID of the vins table.
-func (pgb *ChainDB) PkScriptByVinID(id uint64) (pkScript []byte, ver uint16, err error) {
-	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+func (pgb *ChainDB) PkScriptByVinID(ctx context.Context, id uint64) (pkScript []byte, ver uint16, err error) {
+	ctx, cancel := pgb.queryContext(ctx)
 	defer cancel()
 	pks, ver, err := RetrievePkScriptByVinID(ctx, pgb.db, id)
 	return pks, ver, pgb.replaceCancelError(err)
@@ -3420,8 +4695,8 @@ func (pgb *ChainDB) PkScriptByVinID(id uint64) (pkScript []byte, ver uint16, err
 
 // PkScriptByVoutID retrieves the pkScript and script version for the row of the
 // vouts table specified by the row ID id.
-func (pgb *ChainDB) PkScriptByVoutID(id uint64) (pkScript []byte, ver uint16, err error) {
-	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+func (pgb *ChainDB) PkScriptByVoutID(ctx context.Context, id uint64) (pkScript []byte, ver uint16, err error) {
+	ctx, cancel := pgb.queryContext(ctx)
 	defer cancel()
 	pks, ver, err := RetrievePkScriptByVoutID(ctx, pgb.db, id)
 	return pks, ver, pgb.replaceCancelError(err)
@@ -3430,13 +4705,13 @@ func (pgb *ChainDB) PkScriptByVoutID(id uint64) (pkScript []byte, ver uint16, er
 // VinsForTx returns a slice of dbtypes.VinTxProperty values for each vin
 // referenced by the transaction dbTx, along with the pkScript and script
 // version for the corresponding previous outpoints.
-func (pgb *ChainDB) VinsForTx(dbTx *dbtypes.Tx) ([]dbtypes.VinTxProperty, []string, []uint16, error) {
+func (pgb *ChainDB) VinsForTx(ctx context.Context, dbTx *dbtypes.Tx) ([]dbtypes.VinTxProperty, []string, []uint16, error) {
 	// Retrieve the pkScript and script version for the previous outpoint of
 	// each vin.
 	prevPkScripts := make([]string, 0, len(dbTx.VinDbIds))
 	versions := make([]uint16, 0, len(dbTx.VinDbIds))
 	for _, id := range dbTx.VinDbIds {
-		pkScript, ver, err := pgb.PkScriptByVinID(id)
+		pkScript, ver, err := pgb.PkScriptByVinID(ctx, id)
 		if err != nil {
 			return nil, nil, nil, fmt.Errorf("PkScriptByVinID: %v", err)
 		}
@@ -3445,7 +4720,7 @@ func (pgb *ChainDB) VinsForTx(dbTx *dbtypes.Tx) ([]dbtypes.VinTxProperty, []stri
 	}
 
 	// Retrieve the vins row data.
-	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	ctx, cancel := pgb.queryContext(ctx)
 	defer cancel()
 	vins, err := RetrieveVinsByIDs(ctx, pgb.db, dbTx.VinDbIds)
 	if err != nil {
@@ -3456,107 +4731,124 @@ func (pgb *ChainDB) VinsForTx(dbTx *dbtypes.Tx) ([]dbtypes.VinTxProperty, []stri
 
 // VoutsForTx returns a slice of dbtypes.Vout values for each vout referenced by
 // the transaction dbTx.
-func (pgb *ChainDB) VoutsForTx(dbTx *dbtypes.Tx) ([]dbtypes.Vout, error) {
-	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+func (pgb *ChainDB) VoutsForTx(ctx context.Context, dbTx *dbtypes.Tx) ([]dbtypes.Vout, error) {
+	ctx, cancel := pgb.queryContext(ctx)
 	defer cancel()
 	vouts, err := RetrieveVoutsByIDs(ctx, pgb.db, dbTx.VoutDbIds)
 	return vouts, pgb.replaceCancelError(err)
 }
 
 func (pgb *ChainDB) TipToSideChain(mainRoot string) (string, int64, error) {
-	tipHash := pgb.BestBlockHashStr()
-	var blocksMoved, txnsUpdated, vinsUpdated, votesUpdated, ticketsUpdated, addrsUpdated int64
+	tipHash, tipHeight := pgb.BestBlockStr()
+	var blocksMoved int64
+	var updated sideChainUpdates
 	for tipHash != mainRoot {
-		// 1. Block. Set is_mainchain=false on the tip block, return hash of
-		// previous block.
-		now := time.Now()
-		previousHash, err := SetMainchainByBlockHash(pgb.db, tipHash, false)
-		if err != nil {
-			log.Errorf("Failed to set block %s as a sidechain block: %v",
-				tipHash, err)
-		}
+		previousHash := pgb.blockToSideChain(tipHash, &updated)
 		blocksMoved++
-		log.Debugf("SetMainchainByBlockHash: %v", time.Since(now))
 
-		// 2. Transactions. Set is_mainchain=false on all transactions in the
-		// tip block, returning only the number of transactions updated.
-		now = time.Now()
-		rowsUpdated, _, err := UpdateTransactionsMainchain(pgb.db, tipHash, false)
-		if err != nil {
-			log.Errorf("Failed to set transactions in block %s as sidechain: %v",
-				tipHash, err)
+		// Move on to the previous block, which is one block lower, so its
+		// height need not be looked up.
+		var hash chainhash.Hash
+		if err := chainhash.Decode(&hash, previousHash); err != nil {
+			return tipHash, blocksMoved, fmt.Errorf("invalid previous block "+
+				"hash %q of block %s: %v", previousHash, tipHash, err)
 		}
-		txnsUpdated += rowsUpdated
-		log.Debugf("UpdateTransactionsMainchain: %v", time.Since(now))
+		tipHash = previousHash
+		tipHeight--
+		pgb.bestBlock.set(tipHeight, &hash, tipHash)
+	}
 
-		// 3. Vouts. For all transactions in this block, locate any vouts that
-		// reference them in vouts.spend_tx_row_id, and unset spend_tx_row_id.
-		err = clearVoutAllSpendTxRowIDs(pgb.db, tipHash)
-		if err != nil {
-			log.Errorf("clearVoutAllSpendTxRowIDs for block %s: %v", tipHash, err)
-		}
+	log.Debugf("Reorg orphaned: %d blocks, %d txns, %d vins, %d addresses, %d votes, %d tickets",
+		blocksMoved, updated.txns, updated.vins, updated.addrs, updated.votes, updated.tickets)
 
-		// 4. Vins. Set is_mainchain=false on all vins, returning the number of
-		// vins updated, the vins table row IDs, and the vouts table row IDs.
-		now = time.Now()
-		rowsUpdated, vinDbIDsBlk, voutDbIDsBlk, err := pgb.SetVinsMainchainByBlock(tipHash) // isMainchain from transactions table
-		if err != nil {
-			log.Errorf("Failed to set vins in block %s as sidechain: %v",
-				tipHash, err)
-		}
-		vinsUpdated += rowsUpdated
-		log.Debugf("SetVinsMainchainByBlock: %v", time.Since(now))
-
-		// 5. Addresses. Set valid_mainchain=false on all addresses rows
-		// corresponding to the spending transactions specified by the vins DB
-		// row IDs, and the funding transactions specified by the vouts DB row
-		// IDs. The IDs come for free via RetrieveTxnsVinsVoutsByBlock.
-		now = time.Now()
-		numAddrSpending, numAddrFunding, err := UpdateAddressesMainchainByIDs(pgb.db,
-			vinDbIDsBlk, voutDbIDsBlk, false)
-		if err != nil {
-			log.Errorf("Failed to set addresses rows in block %s as sidechain: %v",
-				tipHash, err)
-		}
-		addrsUpdated += numAddrSpending + numAddrFunding
-		log.Debugf("UpdateAddressesMainchainByIDs: %v", time.Since(now))
+	return tipHash, blocksMoved, nil
+}
 
-		// 6. Votes. Sets is_mainchain=false on all votes in the tip block.
-		now = time.Now()
-		rowsUpdated, err = UpdateVotesMainchain(pgb.db, tipHash, false)
-		if err != nil {
-			log.Errorf("Failed to set votes in block %s as sidechain: %v",
-				tipHash, err)
-		}
-		votesUpdated += rowsUpdated
-		log.Debugf("UpdateVotesMainchain: %v", time.Since(now))
+// sideChainUpdates counts the rows updated when moving blocks to side chain.
+type sideChainUpdates struct {
+	txns, vins, votes, tickets, addrs int64
+}
 
-		// 7. Tickets. Sets is_mainchain=false on all tickets in the tip block.
-		now = time.Now()
-		rowsUpdated, err = UpdateTicketsMainchain(pgb.db, tipHash, false)
-		if err != nil {
-			log.Errorf("Failed to set tickets in block %s as sidechain: %v",
-				tipHash, err)
-		}
-		ticketsUpdated += rowsUpdated
-		log.Debugf("UpdateTicketsMainchain: %v", time.Since(now))
+// blockToSideChain sets is_mainchain=false for the block with the given hash
+// and its transactions, vins, addresses, votes, and tickets, and unsets the
+// spending info of the vouts spent by its transactions. The numbers of updated
+// rows are added to updated, and the hash of the previous block is returned.
+// Errors are logged, but the remaining steps are still attempted.
+func (pgb *ChainDB) blockToSideChain(hash string, updated *sideChainUpdates) string {
+	// 1. Block. Set is_mainchain=false on the block, return hash of previous
+	// block.
+	now := time.Now()
+	previousHash, err := SetMainchainByBlockHash(pgb.db, hash, false)
+	if err != nil {
+		log.Errorf("Failed to set block %s as a sidechain block: %v",
+			hash, err)
+	}
+	log.Debugf("SetMainchainByBlockHash: %v", time.Since(now))
 
-		// move on to next block
-		tipHash = previousHash
+	// 2. Transactions. Set is_mainchain=false on all transactions in the
+	// block, returning only the number of transactions updated.
+	now = time.Now()
+	rowsUpdated, _, err := UpdateTransactionsMainchain(pgb.db, hash, false)
+	if err != nil {
+		log.Errorf("Failed to set transactions in block %s as sidechain: %v",
+			hash, err)
+	}
+	updated.txns += rowsUpdated
+	log.Debugf("UpdateTransactionsMainchain: %v", time.Since(now))
 
-		pgb.bestBlock.mtx.Lock()
-		pgb.bestBlock.height, err = pgb.BlockHeight(tipHash)
-		if err != nil {
-			log.Errorf("Failed to retrieve block height for %s", tipHash)
-		}
-		pgb.bestBlock.hash = tipHash
-		pgb.bestBlock.mtx.Unlock()
+	// 3. Vouts. For all transactions in this block, locate any vouts that
+	// reference them in vouts.spend_tx_row_id, and unset spend_tx_row_id.
+	err = clearVoutAllSpendTxRowIDs(pgb.db, hash)
+	if err != nil {
+		log.Errorf("clearVoutAllSpendTxRowIDs for block %s: %v", hash, err)
 	}
 
-	log.Debugf("Reorg orphaned: %d blocks, %d txns, %d vins, %d addresses, %d votes, %d tickets",
-		blocksMoved, txnsUpdated, vinsUpdated, addrsUpdated, votesUpdated, ticketsUpdated)
+	// 4. Vins. Set is_mainchain=false on all vins, returning the number of
+	// vins updated, the vins table row IDs, and the vouts table row IDs.
+	now = time.Now()
+	rowsUpdated, vinDbIDsBlk, voutDbIDsBlk, err := pgb.SetVinsMainchainByBlock(hash) // isMainchain from transactions table
+	if err != nil {
+		log.Errorf("Failed to set vins in block %s as sidechain: %v",
+			hash, err)
+	}
+	updated.vins += rowsUpdated
+	log.Debugf("SetVinsMainchainByBlock: %v", time.Since(now))
+
+	// 5. Addresses. Set valid_mainchain=false on all addresses rows
+	// corresponding to the spending transactions specified by the vins DB
+	// row IDs, and the funding transactions specified by the vouts DB row
+	// IDs. The IDs come for free via RetrieveTxnsVinsVoutsByBlock.
+	now = time.Now()
+	numAddrSpending, numAddrFunding, err := UpdateAddressesMainchainByIDs(pgb.db,
+		vinDbIDsBlk, voutDbIDsBlk, false)
+	if err != nil {
+		log.Errorf("Failed to set addresses rows in block %s as sidechain: %v",
+			hash, err)
+	}
+	updated.addrs += numAddrSpending + numAddrFunding
+	log.Debugf("UpdateAddressesMainchainByIDs: %v", time.Since(now))
 
-	return tipHash, blocksMoved, nil
+	// 6. Votes. Sets is_mainchain=false on all votes in the block.
+	now = time.Now()
+	rowsUpdated, err = UpdateVotesMainchain(pgb.db, hash, false)
+	if err != nil {
+		log.Errorf("Failed to set votes in block %s as sidechain: %v",
+			hash, err)
+	}
+	updated.votes += rowsUpdated
+	log.Debugf("UpdateVotesMainchain: %v", time.Since(now))
+
+	// 7. Tickets. Sets is_mainchain=false on all tickets in the block.
+	now = time.Now()
+	rowsUpdated, err = UpdateTicketsMainchain(pgb.db, hash, false)
+	if err != nil {
+		log.Errorf("Failed to set tickets in block %s as sidechain: %v",
+			hash, err)
+	}
+	updated.tickets += rowsUpdated
+	log.Debugf("UpdateTicketsMainchain: %v", time.Since(now))
+
+	return previousHash
 }
 
 // StoreBlock processes the input wire.MsgBlock, and saves to the data tables.
@@ -3565,15 +4857,25 @@ func (pgb *ChainDB) StoreBlock(msgBlock *wire.MsgBlock, isValid, isMainchain,
 	updateExistingRecords, updateAddressesSpendingInfo, updateTicketsSpendingInfo bool,
 	chainWork string) (numVins int64, numVouts int64, numAddresses int64, err error) {
 
+	// Do not start writing a block that may not finish before the DB is closed.
+	if !pgb.beginWrite() {
+		err = ErrShuttingDown
+		return
+	}
+	defer pgb.endWrite()
+
+	// Hash the block header once.
+	blockHash := msgBlock.BlockHash()
+
 	// winningTickets is only set during initial chain sync.
 	// Retrieve it from the stakeDB.
 	var tpi *apitypes.TicketPoolInfo
 	var winningTickets []string
 	if isMainchain {
 		var found bool
-		tpi, found = pgb.stakeDB.PoolInfo(msgBlock.BlockHash())
+		tpi, found = pgb.stakeDB.PoolInfo(blockHash)
 		if !found {
-			err = fmt.Errorf("TicketPoolInfo not found for block %s", msgBlock.BlockHash().String())
+			err = fmt.Errorf("TicketPoolInfo not found for block %s", blockHash)
 			return
 		}
 		if tpi.Height != msgBlock.Header.Height {
@@ -3602,7 +4904,7 @@ func (pgb *ChainDB) StoreBlock(msgBlock *wire.MsgBlock, isValid, isMainchain,
 	if isMainchain && !bytes.Equal(zeroHash[:], prevBlockHash[:]) {
 		lastTpi, found := pgb.stakeDB.PoolInfo(prevBlockHash)
 		if !found {
-			err = fmt.Errorf("stakedb.PoolInfo failed for block %s", msgBlock.BlockHash())
+			err = fmt.Errorf("stakedb.PoolInfo failed for block %s", blockHash)
 			return
 		}
 		winners = lastTpi.Winners
@@ -3668,7 +4970,7 @@ func (pgb *ChainDB) StoreBlock(msgBlock *wire.MsgBlock, isValid, isMainchain,
 	dbBlock.TxDbIDs = resReg.txDbIDs
 	dbBlock.STxDbIDs = resStk.txDbIDs
 
-	if isMainchain {
+	if isMainchain && !pgb.reindexing {
 		pgb.mixSetDiffsMtx.Lock()
 		pgb.mixSetDiffs[msgBlock.Header.Height] = resReg.mixSetDelta + resStk.mixSetDelta
 		pgb.mixSetDiffsMtx.Unlock()
@@ -3692,7 +4994,24 @@ func (pgb *ChainDB) StoreBlock(msgBlock *wire.MsgBlock, isValid, isMainchain,
 		log.Error("InsertBlock:", err)
 		return
 	}
-	pgb.lastBlock[msgBlock.BlockHash()] = blockDbID
+	pgb.lastBlock.set(blockHash, blockDbID)
+
+	// Record the tickets selected to vote on a main chain block.
+	if len(winningTickets) > 0 {
+		err = InsertWinningTickets(pgb.db, dbBlock.Hash, int64(dbBlock.Height),
+			winningTickets)
+		if err != nil {
+			err = fmt.Errorf("InsertWinningTickets: %v", err)
+			return
+		}
+	}
+
+	// Record the mempool backlog when a new main chain block is mined. The
+	// mempool cache must still describe the pool that the block was mined
+	// from, which is not the case during the initial sync or a reindex.
+	if isMainchain && !pgb.InBatchSync && !pgb.reindexing {
+		pgb.storeMempoolBacklog(dbBlock)
+	}
 
 	// Insert the block in the block_chain table with the previous block hash
 	// and an empty string for the next block hash, which may be updated when a
@@ -3715,13 +5034,25 @@ func (pgb *ChainDB) StoreBlock(msgBlock *wire.MsgBlock, isValid, isMainchain,
 		return
 	}
 
+	// Record the outputs with nonstandard scripts or unusual script versions.
+	scriptAnomalies := dbtypes.ExtractScriptAnomalies(msgBlock)
+	if err = InsertScriptAnomalies(pgb.db, scriptAnomalies); err != nil {
+		err = fmt.Errorf("InsertScriptAnomalies: %v", err)
+		return
+	}
+
+	// Record the anomalies of a main chain block under the block anomaly rules.
+	var blockAnomalies []*dbtypes.BlockAnomaly
 	if isMainchain {
-		// Update best block height and hash.
-		pgb.bestBlock.mtx.Lock()
-		pgb.bestBlock.height = int64(dbBlock.Height)
-		pgb.bestBlock.hash = dbBlock.Hash
-		pgb.bestBlock.mtx.Unlock()
+		blockAnomalies = pgb.anomalyRules.CheckBlock(msgBlock,
+			pgb.chainParams.StakeValidationHeight)
+		if err = InsertBlockAnomalies(pgb.db, blockAnomalies); err != nil {
+			err = fmt.Errorf("InsertBlockAnomalies: %v", err)
+			return
+		}
+	}
 
+	if isMainchain {
 		// Insert the block stats.
 		if tpi != nil {
 			err = InsertBlockStats(pgb.db, blockDbID, tpi)
@@ -3731,20 +5062,53 @@ func (pgb *ChainDB) StoreBlock(msgBlock *wire.MsgBlock, isValid, isMainchain,
 			}
 		}
 
-		// Update the best block in the meta table.
-		err = SetDBBestBlock(pgb.db, dbBlock.Hash, int64(dbBlock.Height))
-		if err != nil {
-			err = fmt.Errorf("SetDBBestBlock: %v", err)
-			return
+		// Update the best block height and hash, in memory and in the meta
+		// table. A reindexed block below the best block does not change it.
+		if !pgb.reindexing || int64(dbBlock.Height) >= pgb.bestBlock.Height() {
+			pgb.bestBlock.set(int64(dbBlock.Height), &blockHash, dbBlock.Hash)
+			err = SetDBBestBlock(pgb.db, dbBlock.Hash, int64(dbBlock.Height))
+			if err != nil {
+				err = fmt.Errorf("SetDBBestBlock: %v", err)
+				return
+			}
 		}
 	}
 
 	// If not in batch sync, lazy update the dev fund balance, and expire cache
-	// data for the affected addresses.
+	// data for the affected addresses. Anomalies in reindexed blocks are old
+	// news.
 	if !pgb.InBatchSync {
 		if err = pgb.FreshenAddressCaches(true, addresses); err != nil {
 			log.Warnf("FreshenAddressCaches: %v", err)
 		}
+		if isMainchain && len(scriptAnomalies) > 0 && !pgb.reindexing {
+			pgb.signalScriptAnomalies(scriptAnomalies)
+		}
+		if len(blockAnomalies) > 0 && !pgb.reindexing {
+			pgb.signalBlockAnomalies(blockAnomalies)
+		}
+	}
+
+	pgb.signalBlockStored(msgBlock, isMainchain)
+	if isMainchain {
+		pgb.archiveSpentOnInterval(int64(msgBlock.Header.Height))
+	}
+
+	// The rows of reindexed blocks were already sent when they were first
+	// stored.
+	if resReg.addressRows != nil && resStk.addressRows != nil && !pgb.reindexing {
+		stored := &dbtypes.StoredBlock{
+			Block:       dbBlock,
+			BlockDbID:   blockDbID,
+			IsValid:     isValid,
+			IsMainchain: isMainchain,
+			Txns:        append(resReg.txns, resStk.txns...),
+			TxDbIDs:     append(append([]uint64{}, resReg.txDbIDs...), resStk.txDbIDs...),
+			AddressRows: append(resReg.addressRows.rows, resStk.addressRows.rows...),
+			AddressDbIDs: append(resReg.addressRows.ids,
+				resStk.addressRows.ids...),
+		}
+		pgb.signalStoredRows(stored)
 	}
 
 	return
@@ -3752,9 +5116,7 @@ func (pgb *ChainDB) StoreBlock(msgBlock *wire.MsgBlock, isValid, isMainchain,
 
 // SetDBBestBlock stores ChainDB's BestBlock data in the meta table.
 func (pgb *ChainDB) SetDBBestBlock() error {
-	pgb.bestBlock.mtx.RLock()
-	bbHash, bbHeight := pgb.bestBlock.hash, pgb.bestBlock.height
-	pgb.bestBlock.mtx.RUnlock()
+	bbHash, bbHeight := pgb.BestBlockStr()
 	return SetDBBestBlock(pgb.db, bbHash, bbHeight)
 }
 
@@ -3771,12 +5133,17 @@ func (pgb *ChainDB) UpdateLastBlock(msgBlock *wire.MsgBlock, isMainchain bool) e
 		return nil
 	}
 
+	// Convert the hashes to strings for the queries once.
+	lastHashStr := lastBlockHash.String()
+	blockHash := msgBlock.BlockHash()
+	blockHashStr := blockHash.String()
+
 	// Ensure previous block has the same main/sidechain status. If the
 	// current block being added is side chain, do not invalidate the
 	// mainchain block or any of its components, or update the block_chain
 	// table to point to this block.
 	if !isMainchain { // only check when current block is side chain
-		_, lastIsMainchain, err := pgb.BlockFlagsNoCancel(lastBlockHash.String())
+		_, lastIsMainchain, err := pgb.BlockFlagsNoCancel(lastHashStr)
 		if err != nil {
 			log.Errorf("Unable to determine status of previous block %v: %v",
 				lastBlockHash, err)
@@ -3787,18 +5154,18 @@ func (pgb *ChainDB) UpdateLastBlock(msgBlock *wire.MsgBlock, isMainchain bool) e
 		if lastIsMainchain != isMainchain {
 			log.Debugf("Previous block %v is on the main chain, while current "+
 				"block %v is on a side chain. Not updating main chain parent.",
-				lastBlockHash, msgBlock.BlockHash())
+				lastBlockHash, blockHash)
 			return nil
 		}
 	}
 
 	// Attempt to find the row id of the block hash in cache.
-	lastBlockDbID, ok := pgb.lastBlock[lastBlockHash]
+	lastBlockDbID, ok := pgb.lastBlock.get(lastBlockHash)
 	if !ok {
 		log.Debugf("The previous block %s for block %s not found in cache, "+
-			"looking it up.", lastBlockHash, msgBlock.BlockHash())
+			"looking it up.", lastBlockHash, blockHash)
 		var err error
-		lastBlockDbID, err = pgb.BlockChainDbIDNoCancel(lastBlockHash.String())
+		lastBlockDbID, err = pgb.BlockChainDbIDNoCancel(lastHashStr)
 		if err != nil {
 			return fmt.Errorf("unable to locate block %s in block_chain table: %v",
 				lastBlockHash, err)
@@ -3806,7 +5173,7 @@ func (pgb *ChainDB) UpdateLastBlock(msgBlock *wire.MsgBlock, isMainchain bool) e
 	}
 
 	// Update the previous block's next block hash in the block_chain table.
-	err := UpdateBlockNext(pgb.db, lastBlockDbID, msgBlock.BlockHash().String())
+	err := UpdateBlockNext(pgb.db, lastBlockDbID, blockHashStr)
 	if err != nil {
 		return fmt.Errorf("UpdateBlockNext: %v", err)
 	}
@@ -3827,25 +5194,25 @@ func (pgb *ChainDB) UpdateLastBlock(msgBlock *wire.MsgBlock, isMainchain bool) e
 
 		// For the transactions invalidated by this block, locate any vouts that
 		// reference them in vouts.spend_tx_row_id, and unset spend_tx_row_id.
-		err = clearVoutRegularSpendTxRowIDs(pgb.db, lastBlockHash.String())
+		err = clearVoutRegularSpendTxRowIDs(pgb.db, lastHashStr)
 		if err != nil {
 			return fmt.Errorf("clearVoutRegularSpendTxRowIDs: %v", err)
 		}
 
 		// Update the is_valid flag for the last block's vins.
-		err = UpdateLastVins(pgb.db, lastBlockHash.String(), lastIsValid, isMainchain)
+		err = UpdateLastVins(pgb.db, lastHashStr, lastIsValid, isMainchain)
 		if err != nil {
 			return fmt.Errorf("UpdateLastVins: %v", err)
 		}
 
 		// Update the is_valid flag for the last block's regular transactions.
-		_, _, err = UpdateTransactionsValid(pgb.db, lastBlockHash.String(), lastIsValid)
+		_, _, reversedTxns, err := UpdateTransactionsValid(pgb.db, lastHashStr, lastIsValid)
 		if err != nil {
 			return fmt.Errorf("UpdateTransactionsValid: %v", err)
 		}
 
 		// Update addresses table for last block's regular transactions.
-		err = UpdateLastAddressesValid(pgb.db, lastBlockHash.String(), lastIsValid)
+		err = UpdateLastAddressesValid(pgb.db, lastHashStr, lastIsValid)
 		if err != nil {
 			return fmt.Errorf("UpdateLastAddressesValid: %v", err)
 		}
@@ -3853,11 +5220,402 @@ func (pgb *ChainDB) UpdateLastBlock(msgBlock *wire.MsgBlock, isMainchain bool) e
 		// NOTE: Updating the tickets, votes, and misses tables is not
 		// necessary since the stake tree is not subject to stakeholder
 		// approval.
+
+		// Notify subscribers of the reversed transactions, except during
+		// batch sync or a reindex when the invalidation is old news. A side
+		// chain block's disapproval of its parent does not change the state
+		// of the main chain.
+		if isMainchain && !pgb.InBatchSync && !pgb.reindexing {
+			pgb.signalInvalidation(&exptypes.BlockInvalidation{
+				Hash:          lastHashStr,
+				Height:        int64(msgBlock.Header.Height) - 1,
+				InvalidatedBy: blockHashStr,
+				Transactions:  reversedTxns,
+			})
+		}
+	}
+
+	// The previous block is no longer pending approval.
+	if !pgb.InBatchSync && !pgb.reindexing {
+		validity := dbtypes.BlockApproved
+		if !lastIsValid {
+			validity = dbtypes.BlockDisapproved
+		}
+		pgb.signalBlockValidity(&exptypes.BlockValidity{
+			Hash:        lastHashStr,
+			Height:      int64(msgBlock.Header.Height) - 1,
+			IsMainchain: isMainchain,
+			Validity:    validity,
+			VotedBy:     blockHashStr,
+		})
 	}
 
 	return nil
 }
 
+// RegisterBlockValidityHandler registers a function to be called when the
+// votes in a new block approve or disapprove the previous block, resolving its
+// pending approval. Handlers are not called during batch sync or a reindex.
+// Handlers are called synchronously during block storage, and should not block.
+func (pgb *ChainDB) RegisterBlockValidityHandler(handler func(*exptypes.BlockValidity)) {
+	pgb.validityMtx.Lock()
+	pgb.validityHdlrs = append(pgb.validityHdlrs, handler)
+	pgb.validityMtx.Unlock()
+}
+
+// signalBlockValidity sends the block's resolved approval to the registered
+// handlers.
+func (pgb *ChainDB) signalBlockValidity(bv *exptypes.BlockValidity) {
+	pgb.validityMtx.RLock()
+	for _, handler := range pgb.validityHdlrs {
+		handler(bv)
+	}
+	pgb.validityMtx.RUnlock()
+}
+
+// RegisterInvalidationHandler registers a function to be called when a main
+// chain block is invalidated by the votes in the next block, after its data is
+// updated in the DB. Handlers are not called during batch sync or a reindex.
+// Handlers are called synchronously during block storage, and should not block.
+func (pgb *ChainDB) RegisterInvalidationHandler(handler func(*exptypes.BlockInvalidation)) {
+	pgb.invalidationMtx.Lock()
+	pgb.invalidationHdlrs = append(pgb.invalidationHdlrs, handler)
+	pgb.invalidationMtx.Unlock()
+}
+
+// signalInvalidation sends the invalidation to the registered handlers and on
+// the PostgreSQL notify channel.
+func (pgb *ChainDB) signalInvalidation(inv *exptypes.BlockInvalidation) {
+	log.Infof("Block %s (height %d) invalidated by %s, reversing %d transactions.",
+		inv.Hash, inv.Height, inv.InvalidatedBy, len(inv.Transactions))
+
+	pgb.invalidationMtx.RLock()
+	for _, handler := range pgb.invalidationHdlrs {
+		handler(inv)
+	}
+	pgb.invalidationMtx.RUnlock()
+
+	pgb.notifyInvalidation(inv)
+}
+
+// RegisterScriptAnomalyHandler registers a function to be called with the
+// outputs of a new main chain block that have nonstandard scripts or script
+// versions other than 0, after the block is stored. Handlers are not called
+// during batch sync or a reindex. Handlers are called synchronously during
+// block storage, and should not block.
+func (pgb *ChainDB) RegisterScriptAnomalyHandler(handler func([]*dbtypes.ScriptAnomaly)) {
+	pgb.anomalyMtx.Lock()
+	pgb.anomalyHdlrs = append(pgb.anomalyHdlrs, handler)
+	pgb.anomalyMtx.Unlock()
+}
+
+// signalScriptAnomalies sends the anomalous outputs of a block to the
+// registered handlers.
+func (pgb *ChainDB) signalScriptAnomalies(anomalies []*dbtypes.ScriptAnomaly) {
+	log.Infof("Block %d has %d outputs with nonstandard scripts or unusual "+
+		"script versions.", anomalies[0].Height, len(anomalies))
+
+	pgb.anomalyMtx.RLock()
+	for _, handler := range pgb.anomalyHdlrs {
+		handler(anomalies)
+	}
+	pgb.anomalyMtx.RUnlock()
+}
+
+// RegisterBlockAnomalyHandler registers a function to be called with the
+// anomalies of a new main chain block under the block anomaly rules, after the
+// block is stored, or with the anomaly of a deep reorg, after the reorg.
+// Handlers are not called during batch sync or a reindex. Handlers are called
+// synchronously, and should not block.
+func (pgb *ChainDB) RegisterBlockAnomalyHandler(handler func([]*dbtypes.BlockAnomaly)) {
+	pgb.anomalyMtx.Lock()
+	pgb.blockAnomalyHdlrs = append(pgb.blockAnomalyHdlrs, handler)
+	pgb.anomalyMtx.Unlock()
+}
+
+// signalBlockAnomalies sends the anomalies of a block to the registered
+// handlers.
+func (pgb *ChainDB) signalBlockAnomalies(anomalies []*dbtypes.BlockAnomaly) {
+	for _, a := range anomalies {
+		log.Infof("Block %d (%s) is anomalous: %s (%d).", a.Height,
+			a.BlockHash, a.Kind, a.Value)
+	}
+
+	pgb.anomalyMtx.RLock()
+	for _, handler := range pgb.blockAnomalyHdlrs {
+		handler(anomalies)
+	}
+	pgb.anomalyMtx.RUnlock()
+}
+
+// recordReorgAnomaly records and signals the anomaly of a completed reorg if
+// it is deep under the block anomaly rules.
+func (pgb *ChainDB) recordReorgAnomaly(reorg *txhelpers.ReorgData) {
+	if pgb.anomalyRules == nil || pgb.anomalyRules.MaxReorgDepth <= 0 {
+		return
+	}
+	// NewChain does not include the common ancestor.
+	commonAncestorHeight := int64(reorg.NewChainHeight) - int64(len(reorg.NewChain))
+	depth := int64(reorg.OldChainHeight) - commonAncestorHeight
+	if depth <= pgb.anomalyRules.MaxReorgDepth {
+		return
+	}
+
+	header, err := pgb.Client.GetBlockHeader(&reorg.NewChainHead)
+	if err != nil {
+		log.Errorf("Unable to get header of block %v to record a deep reorg: %v",
+			reorg.NewChainHead, err)
+		return
+	}
+	anomaly := pgb.anomalyRules.CheckReorg(depth, header)
+	if err = InsertBlockAnomalies(pgb.db, []*dbtypes.BlockAnomaly{anomaly}); err != nil {
+		log.Errorf("InsertBlockAnomalies: %v", err)
+		return
+	}
+	pgb.signalBlockAnomalies([]*dbtypes.BlockAnomaly{anomaly})
+}
+
+// RegisterBlockStoredHandler registers a function to be called with each block
+// after it is stored, and whether it is on the main chain. Unlike the other
+// handlers, these are also called during batch sync. Handlers are called
+// synchronously during block storage, and should not block.
+func (pgb *ChainDB) RegisterBlockStoredHandler(handler func(msgBlock *wire.MsgBlock, isMainchain bool)) {
+	pgb.storedMtx.Lock()
+	pgb.storedHdlrs = append(pgb.storedHdlrs, handler)
+	pgb.storedMtx.Unlock()
+}
+
+// signalBlockStored sends the stored block to the registered handlers.
+func (pgb *ChainDB) signalBlockStored(msgBlock *wire.MsgBlock, isMainchain bool) {
+	pgb.storedMtx.RLock()
+	for _, handler := range pgb.storedHdlrs {
+		handler(msgBlock, isMainchain)
+	}
+	pgb.storedMtx.RUnlock()
+}
+
+// RegisterStoredRowsHandler registers a function to be called with the rows
+// stored for each block, and their row IDs. Like the block stored handlers,
+// these are also called during batch sync, synchronously during block storage,
+// but not for reindexed blocks, whose rows were sent when they were first
+// stored. The rows are only collected when there is at least one such handler.
+func (pgb *ChainDB) RegisterStoredRowsHandler(handler func(stored *dbtypes.StoredBlock)) {
+	pgb.storedMtx.Lock()
+	pgb.recordsHdlrs = append(pgb.recordsHdlrs, handler)
+	pgb.storedMtx.Unlock()
+}
+
+// collectStoredRows indicates if there are any stored rows handlers.
+func (pgb *ChainDB) collectStoredRows() bool {
+	pgb.storedMtx.RLock()
+	defer pgb.storedMtx.RUnlock()
+	return len(pgb.recordsHdlrs) > 0
+}
+
+// signalStoredRows sends the stored rows to the registered handlers.
+func (pgb *ChainDB) signalStoredRows(stored *dbtypes.StoredBlock) {
+	pgb.storedMtx.RLock()
+	for _, handler := range pgb.recordsHdlrs {
+		handler(stored)
+	}
+	pgb.storedMtx.RUnlock()
+}
+
+// RegisterReorgHandler registers a function to be called when a chain
+// reorganization completes, after the blocks of the new chain are stored.
+// Handlers are called synchronously by the reorg handler, and should not block.
+func (pgb *ChainDB) RegisterReorgHandler(handler func(*txhelpers.ReorgData)) {
+	pgb.reorgMtx.Lock()
+	pgb.reorgHdlrs = append(pgb.reorgHdlrs, handler)
+	pgb.reorgMtx.Unlock()
+}
+
+// signalReorg sends the completed reorg to the registered handlers and on the
+// PostgreSQL notify channel.
+func (pgb *ChainDB) signalReorg(reorg *txhelpers.ReorgData) {
+	pgb.reorgMtx.RLock()
+	for _, handler := range pgb.reorgHdlrs {
+		handler(reorg)
+	}
+	pgb.reorgMtx.RUnlock()
+
+	pgb.notifyReorg(reorg)
+}
+
+// RegisterAgendaStatusHandler registers a function to be called when an agenda
+// reaches quorum, locks in, activates, or fails with a new main chain block.
+// Handlers are called synchronously after the block is stored, and should not
+// block.
+func (pgb *ChainDB) RegisterAgendaStatusHandler(handler func(*exptypes.AgendaStatusChange)) {
+	pgb.agendaMtx.Lock()
+	pgb.agendaHdlrs = append(pgb.agendaHdlrs, handler)
+	pgb.agendaMtx.Unlock()
+}
+
+// signalAgendaStatus sends the agenda status change to the registered
+// handlers.
+func (pgb *ChainDB) signalAgendaStatus(asc *exptypes.AgendaStatusChange) {
+	log.Infof("Agenda %s %s at block %d.", asc.AgendaID, asc.Event, asc.Height)
+
+	pgb.agendaMtx.RLock()
+	for _, handler := range pgb.agendaHdlrs {
+		handler(asc)
+	}
+	pgb.agendaMtx.RUnlock()
+}
+
+// checkAgendaStatus signals the agendas that locked in, activated, or failed
+// with the new main chain block, according to the agenda statuses before
+// (prev) and after the block, and the voting agendas whose quorum was reached
+// by the votes in the block.
+func (pgb *ChainDB) checkAgendaStatus(prev *dbtypes.BlockChainData, msgBlock *wire.MsgBlock) {
+	chainInfo := pgb.ChainInfo()
+	if chainInfo == nil {
+		return
+	}
+	height, hash := int64(msgBlock.Header.Height), msgBlock.BlockHash().String()
+	changes := agendaStatusChanges(prev, chainInfo, height, hash)
+
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	defer cancel()
+
+	quorum := pgb.chainParams.RuleChangeActivationQuorum
+	for agendaID, agendaInfo := range chainInfo.AgendaMileStones {
+		if agendaInfo.Status != dbtypes.StartedAgendaStatus ||
+			height < agendaInfo.VotingStarted || height > agendaInfo.VotingDone {
+			continue
+		}
+		yes, abstain, no, err := retrieveTotalAgendaVotesCount(ctx, pgb.db,
+			agendaID, agendaInfo.VotingStarted, height)
+		if err != nil {
+			log.Errorf("Failed to count votes for agenda %s: %v", agendaID,
+				pgb.replaceCancelError(err))
+			continue
+		}
+		if yes+no < quorum {
+			continue
+		}
+		// Only signal if the quorum was not already reached before this block.
+		if height > agendaInfo.VotingStarted {
+			prevYes, _, prevNo, err := retrieveTotalAgendaVotesCount(ctx, pgb.db,
+				agendaID, agendaInfo.VotingStarted, height-1)
+			if err != nil {
+				log.Errorf("Failed to count votes for agenda %s: %v", agendaID,
+					pgb.replaceCancelError(err))
+				continue
+			}
+			if prevYes+prevNo >= quorum {
+				continue
+			}
+		}
+		changes = append(changes, &exptypes.AgendaStatusChange{
+			AgendaID: agendaID,
+			Event:    exptypes.AgendaQuorumReached,
+			Status:   agendaInfo.Status.String(),
+			Height:   height,
+			Hash:     hash,
+			Yes:      yes,
+			Abstain:  abstain,
+			No:       no,
+			Quorum:   quorum,
+		})
+	}
+
+	for _, asc := range changes {
+		pgb.signalAgendaStatus(asc)
+	}
+}
+
+// agendaStatusChanges lists the agendas with a status of locked in, activated,
+// or failed in cur that had a different status in prev, sorted by agenda ID.
+// Nothing is listed without the previous statuses.
+func agendaStatusChanges(prev, cur *dbtypes.BlockChainData, height int64, hash string) []*exptypes.AgendaStatusChange {
+	if prev == nil || cur == nil {
+		return nil
+	}
+	var changes []*exptypes.AgendaStatusChange
+	for agendaID, agendaInfo := range cur.AgendaMileStones {
+		prevInfo, found := prev.AgendaMileStones[agendaID]
+		if !found || prevInfo.Status == agendaInfo.Status {
+			continue
+		}
+		var event string
+		switch agendaInfo.Status {
+		case dbtypes.LockedInAgendaStatus:
+			event = exptypes.AgendaLockedIn
+		case dbtypes.ActivatedAgendaStatus:
+			event = exptypes.AgendaActivated
+		case dbtypes.FailedAgendaStatus:
+			event = exptypes.AgendaFailed
+		default:
+			continue
+		}
+		changes = append(changes, &exptypes.AgendaStatusChange{
+			AgendaID: agendaID,
+			Event:    event,
+			Status:   agendaInfo.Status.String(),
+			Height:   height,
+			Hash:     hash,
+		})
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].AgendaID < changes[j].AgendaID
+	})
+	return changes
+}
+
+// ScriptAnomalies reports the outputs of the mainchain blocks with heights in
+// the range [from, to] that have nonstandard scripts or script versions other
+// than 0.
+func (pgb *ChainDB) ScriptAnomalies(ctx context.Context, from, to int64) (*dbtypes.ScriptAnomalyReport, error) {
+	ctx, cancel := pgb.queryContext(ctx)
+	defer cancel()
+	outputs, err := RetrieveScriptAnomalies(ctx, pgb.db, from, to)
+	if err != nil {
+		return nil, pgb.replaceCancelError(err)
+	}
+	return dbtypes.NewScriptAnomalyReport(from, to, outputs), nil
+}
+
+// VinsBySigType retrieves the vins of the mainchain transactions in blocks with
+// heights in the range [from, to] with signature scripts of the given type.
+func (pgb *ChainDB) VinsBySigType(ctx context.Context, from, to int64, sigType dbtypes.VinSigType) ([]*dbtypes.SigTypeVin, error) {
+	ctx, cancel := pgb.queryContext(ctx)
+	defer cancel()
+	vins, err := RetrieveVinsBySigType(ctx, pgb.db, from, to, sigType)
+	return vins, pgb.replaceCancelError(err)
+}
+
+// BlockAnomalies retrieves the anomalies of the mainchain blocks with heights
+// in the range [from, to] of the given kind, or of all kinds if kind is empty.
+func (pgb *ChainDB) BlockAnomalies(ctx context.Context, from, to int64, kind string) ([]*dbtypes.BlockAnomaly, error) {
+	ctx, cancel := pgb.queryContext(ctx)
+	defer cancel()
+	anomalies, err := RetrieveBlockAnomalies(ctx, pgb.db, from, to, kind)
+	if err != nil {
+		return nil, pgb.replaceCancelError(err)
+	}
+	if anomalies == nil {
+		anomalies = []*dbtypes.BlockAnomaly{}
+	}
+	return anomalies, nil
+}
+
+// TicketWinningBlocks retrieves the blocks in which the ticket with the given
+// hash was selected to vote, including side chain blocks, oldest first.
+func (pgb *ChainDB) TicketWinningBlocks(ctx context.Context, ticketHash string) ([]*dbtypes.TicketWinningBlock, error) {
+	ctx, cancel := pgb.queryContext(ctx)
+	defer cancel()
+	blocks, err := RetrieveTicketWinningBlocks(ctx, pgb.db, ticketHash)
+	if err != nil {
+		return nil, pgb.replaceCancelError(err)
+	}
+	if blocks == nil {
+		blocks = []*dbtypes.TicketWinningBlock{}
+	}
+	return blocks, nil
+}
+
 // storeTxnsResult is the type of object sent back from the goroutines wrapping
 // storeBlockTxnTree in StoreBlock.
 type storeTxnsResult struct {
@@ -3866,6 +5624,9 @@ type storeTxnsResult struct {
 	err                             error
 	addresses                       map[string]struct{}
 	mixSetDelta                     int64
+	// txns and addressRows are only set when collecting the stored rows.
+	txns        []*dbtypes.Tx
+	addressRows *storedAddressRows
 }
 
 func (r *storeTxnsResult) Error() string {
@@ -4086,7 +5847,7 @@ txns:
 	if txTree == wire.TxTreeStake {
 		// Tickets: Insert new (unspent) tickets
 		newTicketDbIDs, newTicketTx, err := InsertTickets(pgb.db, dbTransactions, txDbIDs,
-			pgb.dupChecks, updateExistingRecords)
+			pgb.dupChecks, updateExistingRecords, pgb.chainParams)
 		if err != nil && err != sql.ErrNoRows {
 			log.Error("InsertTickets:", err)
 			txRes.err = err
@@ -4253,14 +6014,29 @@ txns:
 	}
 
 	// Insert each new funding AddressRow, absent MatchingTxHash (spending txn
-	// since these new address rows are *funding*).
-	_, err = InsertAddressRowsDbTx(dbTx, dbAddressRowsFlat, pgb.dupChecks, updateExistingRecords)
+	// since these new address rows are *funding*). The changes in the address
+	// counts from the funding and spending rows are applied together before
+	// committing.
+	countChanges := make(addressCountChanges)
+	addressDbIDs, err := insertAddressRowsDbTx(dbTx, dbAddressRowsFlat, pgb.dupChecks,
+		updateExistingRecords, countChanges)
 	if err != nil {
 		_ = dbTx.Rollback()
 		log.Error("InsertAddressRows:", err)
 		txRes.err = err
 		return txRes
 	}
+
+	// Collect the inserted rows for the stored rows handlers, if any.
+	var storedRows *storedAddressRows
+	if pgb.collectStoredRows() {
+		storedRows = &storedAddressRows{
+			rows: dbAddressRowsFlat,
+			ids:  addressDbIDs,
+		}
+		txRes.txns = dbTransactions
+		txRes.addressRows = storedRows
+	}
 	txRes.numAddresses = int64(totalAddressRows)
 	txRes.addresses = make(map[string]struct{})
 	for _, ad := range dbAddressRowsFlat {
@@ -4303,7 +6079,8 @@ txns:
 				vin.PrevTxHash, vin.PrevTxIndex, int8(vin.PrevTxTree),
 				spendingTxHash, spendingTxIndex, vinDbID, utxoData, pgb.dupChecks,
 				updateExistingRecords, tx.IsMainchainBlock, tx.IsValid,
-				vin.TxType, updateAddressesSpendingInfo, tx.BlockTime)
+				vin.TxType, updateAddressesSpendingInfo, countChanges, storedRows,
+				tx.BlockTime)
 			if err != nil {
 				txRes.err = fmt.Errorf(`insertSpendingAddressRow: %v + %v (rollback)`,
 					err, dbTx.Rollback())
@@ -4334,6 +6111,12 @@ txns:
 		}
 	}
 
+	if err = updateAddressCounts(dbTx, countChanges); err != nil {
+		txRes.err = fmt.Errorf(`updateAddressCounts: %v + %v (rollback)`,
+			err, dbTx.Rollback())
+		return txRes
+	}
+
 	txRes.err = dbTx.Commit()
 	txRes.mixSetDelta = mixDiff
 
@@ -4494,7 +6277,7 @@ func (pgb *ChainDB) CollectTicketSpendDBInfo(dbTxns []*dbtypes.Tx, txDbIDs []uin
 // but much more slowly for a number of reasons (that are well worth
 // investigating BTW!).
 func (pgb *ChainDB) UpdateSpendingInfoInAllAddresses(barLoad chan *dbtypes.ProgressBarLoad) (int64, error) {
-	heightDB, err := pgb.HeightDB()
+	heightDB, err := pgb.HeightDB(pgb.ctx)
 	if err != nil {
 		return 0, fmt.Errorf("DBBestBlock: %v", err)
 	}
@@ -4531,19 +6314,80 @@ func (pgb *ChainDB) UpdateSpendingInfoInAllAddresses(barLoad chan *dbtypes.Progr
 
 			tStart = time.Now()
 		}
-	}
+	}
+
+	// Signal the completion of the sync to the status page.
+	if barLoad != nil {
+		barLoad <- &dbtypes.ProgressBarLoad{
+			From:  heightDB,
+			To:    heightDB,
+			Msg:   addressesSyncStatusMsg,
+			BarID: dbtypes.AddressesTableSync,
+		}
+	}
+
+	return rowsTouched, nil
+}
+
+// CheckSpendingInfoInAddresses scans the addresses table for funding rows with
+// a missing or mismatched matching_tx_hash, in batches of batchSize blocks
+// starting at fromHeight. The number of such rows in each batch is logged. If
+// repair is true, the rows in each batch are also updated with the correct
+// spending transaction hash. The scan stops at the end of a batch if the
+// ChainDB's context is canceled, and the height at which to resume is
+// returned along with the total number of mismatched and repaired rows.
+func (pgb *ChainDB) CheckSpendingInfoInAddresses(fromHeight, batchSize int64,
+	repair bool) (mismatched, repaired, resumeHeight int64, err error) {
+	heightDB, err := pgb.HeightDB(pgb.ctx)
+	if err != nil {
+		return 0, 0, fromHeight, fmt.Errorf("DBBestBlock: %v", err)
+	}
+	if batchSize < 1 {
+		batchSize = 1
+	}
+	if fromHeight < 0 {
+		fromHeight = 0
+	}
+
+	for start := fromHeight; start <= heightDB; start += batchSize {
+		if pgb.ctx.Err() != nil {
+			log.Infof("Address spending info check interrupted. "+
+				"Resume at height %d.", start)
+			return mismatched, repaired, start, nil
+		}
+
+		end := start + batchSize
+		if end > heightDB+1 {
+			end = heightDB + 1
+		}
+
+		var N int64
+		err = pgb.db.QueryRow(internal.CountAddressesMismatchedMatchingTxHashRange,
+			start, end).Scan(&N)
+		if err != nil {
+			return mismatched, repaired, start, err
+		}
+		mismatched += N
+		if N == 0 {
+			log.Debugf("Blocks [%d,%d]: no mismatched address rows.", start, end-1)
+			continue
+		}
+		log.Infof("Blocks [%d,%d]: %d address rows with missing or "+
+			"mismatched spending info.", start, end-1, N)
 
-	// Signal the completion of the sync to the status page.
-	if barLoad != nil {
-		barLoad <- &dbtypes.ProgressBarLoad{
-			From:  heightDB,
-			To:    heightDB,
-			Msg:   addressesSyncStatusMsg,
-			BarID: dbtypes.AddressesTableSync,
+		if !repair {
+			continue
+		}
+		N, err = sqlExec(pgb.db, internal.UpdateAddressesMismatchedMatchingTxHashRange,
+			"failed to update addresses spending info: ", start, end)
+		if err != nil {
+			return mismatched, repaired, start, err
 		}
+		repaired += N
+		log.Infof("Blocks [%d,%d]: repaired %d address rows.", start, end-1, N)
 	}
 
-	return rowsTouched, nil
+	return mismatched, repaired, heightDB + 1, nil
 }
 
 // UpdateSpendingInfoInAllTickets reviews all votes and revokes and sets this
@@ -4639,7 +6483,27 @@ func ticketpoolStatusSlice(ss dbtypes.TicketPoolStatus, N int) []dbtypes.TicketP
 // GetChainWork fetches the chainjson.BlockHeaderVerbose and returns only the
 // ChainWork attribute as a hex-encoded string, without 0x prefix.
 func (pgb *ChainDB) GetChainWork(hash *chainhash.Hash) (string, error) {
-	return rpcutils.GetChainWork(pgb.Client, hash)
+	var chainWork string
+	err := pgb.retryRPC(func() (err error) {
+		chainWork, err = rpcutils.GetChainWork(pgb.Client, hash)
+		return
+	})
+	return chainWork, err
+}
+
+// ChainWorkFromHeader computes the cumulative chain work of the block with the
+// given header from the stored chain work of its parent and the header's bits.
+// The genesis block's chain work is simply its own work.
+func (pgb *ChainDB) ChainWorkFromHeader(header *wire.BlockHeader) (string, error) {
+	prevChainWork := "0"
+	if header.Height > 0 {
+		prev, err := pgb.BlockChainWork(pgb.ctx, header.PrevBlock.String())
+		if err != nil {
+			return "", fmt.Errorf("unable to retrieve parent chain work: %v", err)
+		}
+		prevChainWork = prev.ChainWork
+	}
+	return txhelpers.AccumulateChainWork(prevChainWork, header.Bits)
 }
 
 // GenesisStamp returns the stamp of the lowest mainchain block in the database.
@@ -4667,10 +6531,10 @@ func (pgb *ChainDB) GetStakeInfoExtendedByHash(hashStr string) *apitypes.StakeIn
 	msgBlock := block.MsgBlock()
 	height := msgBlock.Header.Height
 
-	var size, val int64
+	var size, val, wavg int64
 	var winners []string
 	err = pgb.db.QueryRowContext(pgb.ctx, internal.SelectPoolInfo,
-		hashStr).Scan(pq.Array(&winners), &val, &size)
+		hashStr).Scan(pq.Array(&winners), &val, &size, &wavg)
 	if err != nil {
 		log.Errorf("Error retrieving mainchain block with stats for hash %s: %v", hashStr, err)
 		return nil
@@ -4678,11 +6542,12 @@ func (pgb *ChainDB) GetStakeInfoExtendedByHash(hashStr string) *apitypes.StakeIn
 
 	coin := dcrutil.Amount(val).ToCoin()
 	tpi := &apitypes.TicketPoolInfo{
-		Height:  height,
-		Size:    uint32(size),
-		Value:   coin,
-		ValAvg:  coin / float64(size),
-		Winners: winners,
+		Height:         height,
+		Size:           uint32(size),
+		Value:          coin,
+		ValAvg:         coin / float64(size),
+		ValWeightedAvg: dcrutil.Amount(wavg).ToCoin(),
+		Winners:        winners,
 	}
 
 	windowSize := uint32(pgb.chainParams.StakeDiffWindowSize)
@@ -4701,7 +6566,7 @@ func (pgb *ChainDB) GetStakeInfoExtendedByHash(hashStr string) *apitypes.StakeIn
 // GetStakeInfoExtendedByHeight gets extended stake information for the
 // mainchain block at the specified height.
 func (pgb *ChainDB) GetStakeInfoExtendedByHeight(height int) *apitypes.StakeInfoExtended {
-	hashStr, err := pgb.BlockHash(int64(height))
+	hashStr, err := pgb.BlockHash(pgb.ctx, int64(height))
 	if err != nil {
 		log.Errorf("GetStakeInfoExtendedByHeight -> BlockHash: %v", err)
 		return nil
@@ -4730,6 +6595,24 @@ func (pgb *ChainDB) GetPoolInfoByHash(hash string) *apitypes.TicketPoolInfo {
 	return ticketPoolInfo
 }
 
+// GetWinnersByHash retrieves the tickets called to vote on the block with the
+// specified hash.
+func (pgb *ChainDB) GetWinnersByHash(ctx context.Context, hash string) ([]string, error) {
+	ctx, cancel := pgb.queryContext(ctx)
+	defer cancel()
+	winners, err := RetrieveWinnersByHash(ctx, pgb.db, hash)
+	return winners, pgb.replaceCancelError(err)
+}
+
+// GetWinners retrieves the tickets called to vote on the mainchain block at the
+// specified height.
+func (pgb *ChainDB) GetWinners(ctx context.Context, idx int64) ([]string, error) {
+	ctx, cancel := pgb.queryContext(ctx)
+	defer cancel()
+	winners, err := RetrieveWinners(ctx, pgb.db, idx)
+	return winners, pgb.replaceCancelError(err)
+}
+
 // GetPoolInfoRange retrieves the ticket pool statistics for a range of block
 // heights, as a slice.
 func (pgb *ChainDB) GetPoolInfoRange(idx0, idx1 int) []apitypes.TicketPoolInfo {
@@ -4810,7 +6693,11 @@ func (pgb *ChainDB) GetPool(idx int64) ([]string, error) {
 // CurrentCoinSupply gets the current coin supply as an *apitypes.CoinSupply,
 // which additionally contains block info and max supply.
 func (pgb *ChainDB) CurrentCoinSupply() (supply *apitypes.CoinSupply) {
-	coinSupply, err := pgb.Client.GetCoinSupply()
+	var coinSupply dcrutil.Amount
+	err := pgb.retryRPC(func() (err error) {
+		coinSupply, err = pgb.Client.GetCoinSupply()
+		return
+	})
 	if err != nil {
 		log.Errorf("RPC failure (GetCoinSupply): %v", err)
 		return
@@ -4834,7 +6721,12 @@ func (pgb *ChainDB) GetBlockByHash(hash string) (*wire.MsgBlock, error) {
 		log.Errorf("Invalid block hash %s", hash)
 		return nil, err
 	}
-	return pgb.Client.GetBlock(blockHash)
+	var msgBlock *wire.MsgBlock
+	err = pgb.retryRPC(func() (err error) {
+		msgBlock, err = pgb.Client.GetBlock(blockHash)
+		return
+	})
+	return msgBlock, err
 }
 
 // GetHeader fetches the *chainjson.GetBlockHeaderVerboseResult for a given
@@ -4851,7 +6743,88 @@ func (pgb *ChainDB) GetBlockHeaderByHash(hash string) (*wire.BlockHeader, error)
 		log.Errorf("Invalid block hash %s", hash)
 		return nil, err
 	}
-	return pgb.Client.GetBlockHeader(blockHash)
+	var header *wire.BlockHeader
+	err = pgb.retryRPC(func() (err error) {
+		header, err = pgb.Client.GetBlockHeader(blockHash)
+		return
+	})
+	return header, err
+}
+
+// MaxHeadersPerRequest is the maximum number of headers returned by
+// BlockHeaders, and the most that dcrd returns from a getheaders RPC.
+const MaxHeadersPerRequest = wire.MaxBlockHeadersPerMsg
+
+// BlockHeaders fetches up to count mainchain block headers, but no more than
+// MaxHeadersPerRequest, starting with the block at fromHeight. Fewer headers
+// are returned if the best block is reached, and none if fromHeight is above
+// the best block. The headers are retrieved from dcrd using the block hashes
+// in the database as the locator and stop hash.
+func (pgb *ChainDB) BlockHeaders(ctx context.Context, fromHeight int64, count int) ([]*wire.BlockHeader, error) {
+	if count > MaxHeadersPerRequest {
+		count = MaxHeadersPerRequest
+	}
+	tip := pgb.Height()
+	if fromHeight < 0 || fromHeight > tip || count < 1 {
+		return nil, nil
+	}
+	endHeight := fromHeight + int64(count) - 1
+	if endHeight > tip {
+		endHeight = tip
+	}
+
+	headers := make([]*wire.BlockHeader, 0, endHeight-fromHeight+1)
+	locatorHeight := fromHeight - 1
+	if fromHeight == 0 {
+		// The genesis block is not after any locator, so start with it.
+		genesis := pgb.chainParams.GenesisBlock.Header
+		headers = append(headers, &genesis)
+		if endHeight == 0 {
+			return headers, nil
+		}
+		locatorHeight = 0
+	}
+
+	ctx, cancel := pgb.queryContext(ctx)
+	defer cancel()
+	locatorStr, err := RetrieveBlockHash(ctx, pgb.db, locatorHeight)
+	if err != nil {
+		return nil, pgb.replaceCancelError(err)
+	}
+	stopStr, err := RetrieveBlockHash(ctx, pgb.db, endHeight)
+	if err != nil {
+		return nil, pgb.replaceCancelError(err)
+	}
+	locator, err := chainhash.NewHashFromStr(locatorStr)
+	if err != nil {
+		return nil, err
+	}
+	hashStop, err := chainhash.NewHashFromStr(stopStr)
+	if err != nil {
+		return nil, err
+	}
+
+	var res *chainjson.GetHeadersResult
+	err = pgb.retryRPC(func() (err error) {
+		res, err = pgb.Client.GetHeaders([]*chainhash.Hash{locator}, hashStop)
+		return
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, headerHex := range res.Headers {
+		b, err := hex.DecodeString(headerHex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid header hex: %v", err)
+		}
+		header := new(wire.BlockHeader)
+		if err = header.FromBytes(b); err != nil {
+			return nil, fmt.Errorf("invalid header: %v", err)
+		}
+		headers = append(headers, header)
+	}
+	return headers, nil
 }
 
 // GetRawAPITransaction gets an *apitypes.Tx for a given transaction ID.
@@ -4861,8 +6834,10 @@ func (pgb *ChainDB) GetRawAPITransaction(txid *chainhash.Hash) *apitypes.Tx {
 }
 
 func (pgb *ChainDB) getRawAPITransaction(txid *chainhash.Hash) (tx *apitypes.Tx, hex string) {
-	var err error
-	tx, hex, err = rpcutils.APITransaction(pgb.Client, txid)
+	err := pgb.retryRPC(func() (err error) {
+		tx, hex, err = rpcutils.APITransaction(pgb.Client, txid)
+		return
+	})
 	if err != nil {
 		log.Errorf("APITransaction failed: %v", err)
 	}
@@ -4885,13 +6860,26 @@ func (pgb *ChainDB) GetTrimmedTransaction(txid *chainhash.Hash) *apitypes.Trimme
 	}
 }
 
+// Vote retrieves the vote transaction with its decoded vote bits from the votes
+// table. sql.ErrNoRows is returned if the transaction is not a stored vote.
+func (pgb *ChainDB) Vote(ctx context.Context, txHash string) (*apitypes.Vote, error) {
+	ctx, cancel := pgb.queryContext(ctx)
+	defer cancel()
+	vote, err := retrieveVote(ctx, pgb.db, txHash)
+	return vote, pgb.replaceCancelError(err)
+}
+
 // GetVoteInfo attempts to decode the vote bits of a SSGen transaction. If the
 // transaction is not a valid SSGen, the VoteInfo output will be nil. Depending
 // on the stake version with which dcrdata is compiled with (chaincfg.Params),
 // the Choices field of VoteInfo may be a nil slice even if the votebits were
 // set for a previously-valid agenda.
 func (pgb *ChainDB) GetVoteInfo(txhash *chainhash.Hash) (*apitypes.VoteInfo, error) {
-	tx, err := pgb.Client.GetRawTransaction(txhash)
+	var tx *dcrutil.Tx
+	err := pgb.retryRPC(func() (err error) {
+		tx, err = pgb.Client.GetRawTransaction(txhash)
+		return
+	})
 	if err != nil {
 		log.Errorf("GetRawTransaction failed for: %v", txhash)
 		return nil, nil
@@ -4941,13 +6929,19 @@ func (pgb *ChainDB) GetStakeVersionsLatest() (*chainjson.StakeVersions, error) {
 // GetAllTxIn gets all transaction inputs, as a slice of *apitypes.TxIn, for a
 // given transaction ID.
 func (pgb *ChainDB) GetAllTxIn(txid *chainhash.Hash) []*apitypes.TxIn {
-	tx, err := pgb.Client.GetRawTransaction(txid)
+	var tx *dcrutil.Tx
+	err := pgb.retryRPC(func() (err error) {
+		tx, err = pgb.Client.GetRawTransaction(txid)
+		return
+	})
 	if err != nil {
 		log.Errorf("Unknown transaction %s", txid)
 		return nil
 	}
 
-	allTxIn0 := tx.MsgTx().TxIn
+	msgTx := tx.MsgTx()
+	txType := stake.DetermineTxType(msgTx)
+	allTxIn0 := msgTx.TxIn
 	allTxIn := make([]*apitypes.TxIn, len(allTxIn0))
 	for i := range allTxIn {
 		txIn := &apitypes.TxIn{
@@ -4961,6 +6955,7 @@ func (pgb *ChainDB) GetAllTxIn(txid *chainhash.Hash) []*apitypes.TxIn {
 			BlockHeight:     allTxIn0[i].BlockHeight,
 			BlockIndex:      allTxIn0[i].BlockIndex,
 			SignatureScript: hex.EncodeToString(allTxIn0[i].SignatureScript),
+			SigType:         dbtypes.ClassifyVinSig(msgTx, txType, i).String(),
 		}
 		allTxIn[i] = txIn
 	}
@@ -4971,7 +6966,11 @@ func (pgb *ChainDB) GetAllTxIn(txid *chainhash.Hash) []*apitypes.TxIn {
 // GetAllTxOut gets all transaction outputs, as a slice of *apitypes.TxOut, for
 // a given transaction ID.
 func (pgb *ChainDB) GetAllTxOut(txid *chainhash.Hash) []*apitypes.TxOut {
-	tx, err := pgb.Client.GetRawTransactionVerbose(txid)
+	var tx *chainjson.TxRawResult
+	err := pgb.retryRPC(func() (err error) {
+		tx, err = pgb.Client.GetRawTransactionVerbose(txid)
+		return
+	})
 	if err != nil {
 		log.Warnf("Unknown transaction %s", txid)
 		return nil
@@ -5074,6 +7073,15 @@ func (pgb *ChainDB) BlockSummaryRange(idx0, idx1 int64) ([]*apitypes.BlockDataBa
 	return RetrieveBlockSummaryRange(pgb.ctx, pgb.db, idx0, idx1)
 }
 
+// BlockSummariesBefore returns the *apitypes.BlockDataBasic for up to limit
+// mainchain blocks below the given height, newest first.
+func (pgb *ChainDB) BlockSummariesBefore(ctx context.Context, before, limit int64) ([]*apitypes.BlockDataBasic, error) {
+	ctx, cancel := pgb.queryContext(ctx)
+	defer cancel()
+	blocks, err := RetrieveBlockSummariesBefore(ctx, pgb.db, before, limit)
+	return blocks, pgb.replaceCancelError(err)
+}
+
 // GetSummaryStepped returns the []*apitypes.BlockDataBasic for a given block
 // height.
 func (pgb *ChainDB) GetSummaryRangeStepped(idx0, idx1, step int) []*apitypes.BlockDataBasic {
@@ -5177,7 +7185,7 @@ func (pgb *ChainDB) BlockSummaryByHash(hash string) (*apitypes.BlockDataBasic, e
 // no blocks in the table (yet), a nil pointer is returned.
 func (pgb *ChainDB) GetBestBlockSummary() *apitypes.BlockDataBasic {
 	// Attempt to retrieve height of best block in DB.
-	dbBlkHeight, err := pgb.HeightDB()
+	dbBlkHeight, err := pgb.HeightDB(pgb.ctx)
 	if err != nil {
 		log.Errorf("GetBlockSummaryHeight failed: %v", err)
 		return nil
@@ -5293,6 +7301,150 @@ func (pgb *ChainDB) SDiffRange(ind0, ind1 int64) ([]float64, error) {
 	return RetrieveSDiffRange(pgb.ctx, pgb.db, ind0, ind1)
 }
 
+// StakeDiffEstimateAccuracy compares dcrd's stake difficulty estimates made
+// during each of the last N windows, after skipping offset windows, with the
+// stake difficulty realized in the following window.
+func (pgb *ChainDB) StakeDiffEstimateAccuracy(ctx context.Context, N, offset int64) ([]*dbtypes.StakeDiffEstimateAccuracy, error) {
+	ctx, cancel := pgb.queryContext(ctx)
+	defer cancel()
+	windows, err := RetrieveStakeDiffEstimateAccuracy(ctx, pgb.db,
+		pgb.chainParams.StakeDiffWindowSize, N, offset)
+	return windows, pgb.replaceCancelError(err)
+}
+
+// StakeDiffWindowsOHLC retrieves the ticket price and ticket pool statistics
+// of the last N stake difficulty windows, including the current window, after
+// skipping offset windows.
+func (pgb *ChainDB) StakeDiffWindowsOHLC(ctx context.Context, N, offset int64) ([]*dbtypes.StakeDiffWindowOHLC, error) {
+	ctx, cancel := pgb.queryContext(ctx)
+	defer cancel()
+	windows, err := RetrieveStakeDiffWindowsOHLC(ctx, pgb.db,
+		pgb.chainParams.StakeDiffWindowSize, pgb.Height(), N, offset)
+	return windows, pgb.replaceCancelError(err)
+}
+
+// TicketDemandHistory retrieves the ticket purchases, ticket price and live
+// ticket pool size of every main chain block, in height order. The history is
+// cached until the best block changes, and must not be modified.
+func (pgb *ChainDB) TicketDemandHistory(ctx context.Context) ([]dbtypes.BlockTicketDemand, error) {
+	pgb.demandMtx.Lock()
+	defer pgb.demandMtx.Unlock()
+	bestHash := pgb.BestBlockHashStr()
+	if pgb.demandHistory != nil && pgb.demandHash == bestHash {
+		return pgb.demandHistory, nil
+	}
+
+	ctx, cancel := pgb.queryContext(ctx)
+	defer cancel()
+	history, err := RetrieveTicketDemandHistory(ctx, pgb.db)
+	if err != nil {
+		return nil, pgb.replaceCancelError(err)
+	}
+	pgb.demandHistory, pgb.demandHash = history, bestHash
+	return history, nil
+}
+
+// StoreDailyPrice records a DCR price in the given fiat currency, updating
+// the daily price history for the UTC day of t.
+func (pgb *ChainDB) StoreDailyPrice(currency string, price float64, t time.Time) error {
+	return InsertDailyPrice(pgb.db, currency, price, t)
+}
+
+// DailyPrices retrieves the daily DCR price history in the given fiat
+// currency, oldest first, starting with the UTC day of since.
+func (pgb *ChainDB) DailyPrices(ctx context.Context, currency string, since time.Time) ([]*dbtypes.DailyPrice, error) {
+	ctx, cancel := pgb.queryContext(ctx)
+	defer cancel()
+	prices, err := RetrieveDailyPrices(ctx, pgb.db, currency, since)
+	return prices, pgb.replaceCancelError(err)
+}
+
+// StoreUTXODistribution records the distribution of the unspent output values
+// among the dbtypes.UTXOBucketBounds buckets for the current UTC day, replacing
+// any already recorded for the day. This scans all unspent outputs, so it is
+// not subject to the query timeout.
+func (pgb *ChainDB) StoreUTXODistribution() error {
+	height := pgb.Height()
+	err := InsertUTXODistribution(pgb.ctx, pgb.db, pgb.archive, time.Now(), height)
+	if err != nil {
+		return pgb.replaceCancelError(err)
+	}
+	log.Debugf("Recorded the UTXO distribution at height %d.", height)
+	return nil
+}
+
+// UTXODistribution retrieves the most recently recorded UTXO distribution.
+// sql.ErrNoRows is returned if none has been recorded.
+func (pgb *ChainDB) UTXODistribution(ctx context.Context) (*dbtypes.UTXODistribution, error) {
+	ctx, cancel := pgb.queryContext(ctx)
+	defer cancel()
+	dist, err := RetrieveLatestUTXODistribution(ctx, pgb.db)
+	return dist, pgb.replaceCancelError(err)
+}
+
+// UTXODistributions retrieves the UTXO distributions recorded on and after the
+// UTC day of since, oldest first.
+func (pgb *ChainDB) UTXODistributions(ctx context.Context, since time.Time) ([]*dbtypes.UTXODistribution, error) {
+	ctx, cancel := pgb.queryContext(ctx)
+	defer cancel()
+	dists, err := RetrieveUTXODistributions(ctx, pgb.db, since)
+	return dists, pgb.replaceCancelError(err)
+}
+
+// storeStakeDiffEstimate records the stake difficulty estimates obtained from
+// dcrd when the block was connected. Estimates that were not obtained, as
+// indicated by a zero expected value, are not stored.
+func (pgb *ChainDB) storeStakeDiffEstimate(msgBlock *wire.MsgBlock,
+	est *chainjson.EstimateStakeDiffResult) {
+	if est.Expected == 0 {
+		return
+	}
+	toAtoms := func(v float64) int64 {
+		amt, _ := dcrutil.NewAmount(v)
+		return int64(amt)
+	}
+	err := InsertStakeDiffEstimate(pgb.db, int64(msgBlock.Header.Height),
+		msgBlock.BlockHash().String(), pgb.chainParams.StakeDiffWindowSize,
+		toAtoms(est.Expected), toAtoms(est.Min), toAtoms(est.Max))
+	if err != nil {
+		log.Errorf("Failed to store stake difficulty estimate: %v", err)
+	}
+}
+
+// storeMempoolBacklog records the number of transactions and total size of the
+// mempool from which the given block was mined. Nothing is recorded if the
+// mempool cache does not describe the pool at the block's parent.
+func (pgb *ChainDB) storeMempoolBacklog(dbBlock *dbtypes.Block) {
+	summary := pgb.MPC.GetShortSummary()
+	if summary.Height+1 != dbBlock.Height {
+		log.Debugf("Not recording mempool backlog for block %d with mempool at height %d.",
+			dbBlock.Height, summary.Height)
+		return
+	}
+	err := InsertMempoolBacklog(pgb.db, dbBlock.Hash, int64(dbBlock.Height),
+		dbtypes.NewTimeDefFromUNIX(summary.Time), summary.NumAll, int64(summary.TotalSize))
+	if err != nil {
+		log.Warnf("Failed to store mempool backlog for block %d: %v", dbBlock.Height, err)
+	}
+}
+
+// GetMempoolShortSummary returns the current *apitypes.MempoolShortSummary.
+func (pgb *ChainDB) GetMempoolShortSummary() *apitypes.MempoolShortSummary {
+	return pgb.MPC.GetShortSummary()
+}
+
+// GetMempoolExpiring returns the cached mempool transactions that expire within
+// the given number of blocks after the best block.
+func (pgb *ChainDB) GetMempoolExpiring(blocks int64) *apitypes.MempoolExpiring {
+	return pgb.MPC.GetExpiring(blocks)
+}
+
+// NextBlockPreview returns the likely next block assembled from the cached
+// mempool transactions.
+func (pgb *ChainDB) NextBlockPreview() *exptypes.NextBlockPreview {
+	return pgb.MPC.NextBlockPreview(pgb.chainParams)
+}
+
 // GetMempoolSSTxSummary returns the current *apitypes.MempoolTicketFeeInfo.
 func (pgb *ChainDB) GetMempoolSSTxSummary() *apitypes.MempoolTicketFeeInfo {
 	_, feeInfo := pgb.MPC.GetFeeInfoExtra()
@@ -5656,6 +7808,89 @@ func (pgb *ChainDB) GetExplorerBlock(hash string) *exptypes.BlockInfo {
 	return block
 }
 
+// BlockFull gets the block summary, the trimmed transactions by tree, the vote
+// validation details, the ticket info, and the previous and next block hashes
+// for the specified block. sql.ErrNoRows is returned for an unknown block.
+func (pgb *ChainDB) BlockFull(ctx context.Context, hash string) (*apitypes.BlockFull, error) {
+	status, err := pgb.BlockStatus(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	misses, err := pgb.BlockMissedVotes(ctx, hash)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	// The BlockInfo may be shared, so it is not modified.
+	info := pgb.GetExplorerBlock(hash)
+	if info == nil {
+		return nil, fmt.Errorf("unable to get block %s", hash)
+	}
+	summary := pgb.GetSummaryByHash(hash, true)
+	if summary == nil {
+		return nil, fmt.Errorf("unable to get block %s summary", hash)
+	}
+
+	if misses == nil {
+		misses = []string{}
+	}
+	return &apitypes.BlockFull{
+		Summary:       summary,
+		Valid:         status.IsValid,
+		Mainchain:     status.IsMainchain,
+		Confirmations: info.Confirmations,
+		PreviousHash:  status.PrevHash,
+		NextHash:      status.NextHash,
+		VoteBits:      info.VoteBits,
+		TotalSent:     info.TotalSent,
+		MiningFee:     info.MiningFee,
+		TotalMixed:    info.TotalMixed,
+		Tx:            makeBlockTxsTrimmed(info.Tx),
+		Stake: apitypes.BlockStakeFull{
+			PoolSize:    info.PoolSize,
+			TicketPrice: info.SBits,
+			Tickets:     makeBlockTxsTrimmed(info.Tickets),
+			Votes:       makeBlockTxsTrimmed(info.Votes),
+			Revocations: makeBlockTxsTrimmed(info.Revs),
+			Misses:      misses,
+		},
+	}, nil
+}
+
+// makeBlockTxsTrimmed converts the explorer's trimmed transactions to the API
+// type, including the vote info of votes.
+func makeBlockTxsTrimmed(txs []*exptypes.TrimmedTxInfo) []*apitypes.BlockTxTrimmed {
+	trimmed := make([]*apitypes.BlockTxTrimmed, 0, len(txs))
+	for _, tx := range txs {
+		t := &apitypes.BlockTxTrimmed{
+			TxID:      tx.TxID,
+			Total:     tx.Total,
+			Fees:      tx.Fees,
+			FeeRate:   tx.FeeRate.ToCoin(),
+			VinCount:  tx.VinCount,
+			VoutCount: tx.VoutCount,
+			Coinbase:  tx.Coinbase,
+			MixCount:  tx.MixCount,
+			MixDenom:  tx.MixDenom,
+		}
+		if vi := tx.VoteInfo; vi != nil {
+			t.TicketSpent = vi.TicketSpent
+			t.Vote = &apitypes.VoteInfo{
+				Validation: apitypes.BlockValidation{
+					Hash:     vi.Validation.Hash,
+					Height:   vi.Validation.Height,
+					Validity: vi.Validation.Validity,
+				},
+				Version: vi.Version,
+				Bits:    vi.Bits,
+				Choices: vi.Choices,
+			}
+		}
+		trimmed = append(trimmed, t)
+	}
+	return trimmed
+}
+
 // GetExplorerBlocks creates an slice of exptypes.BlockBasic beginning at start
 // and decreasing in block height to end, not including end.
 func (pgb *ChainDB) GetExplorerBlocks(start int, end int) []*exptypes.BlockBasic {
@@ -5722,8 +7957,87 @@ func (pgb *ChainDB) txWithTicketPrice(txhash *chainhash.Hash) (*chainjson.TxRawR
 }
 
 // GetExplorerTx creates a *exptypes.TxInfo for the transaction with the given
-// ID.
-func (pgb *ChainDB) GetExplorerTx(txid string) *exptypes.TxInfo {
+// ID. Transactions in mainchain blocks are reconstructed from the database, so
+// dcrd is only queried for mempool transactions and those not yet stored.
+func (pgb *ChainDB) GetExplorerTx(ctx context.Context, txid string) *exptypes.TxInfo {
+	tx, err := pgb.explorerTxFromDB(ctx, txid)
+	if err == nil {
+		return tx
+	}
+	if err != sql.ErrNoRows {
+		log.Warnf("Unable to build transaction %s from the database: %v", txid, err)
+	}
+	return pgb.explorerTxFromRPC(txid)
+}
+
+// explorerTxFromDB creates a *exptypes.TxInfo for the transaction with the
+// given ID from the transactions, vins, vouts and votes tables. sql.ErrNoRows
+// is returned if the transaction is not in a mainchain block.
+func (pgb *ChainDB) explorerTxFromDB(ctx context.Context, txid string) (*exptypes.TxInfo, error) {
+	ctx, cancel := pgb.queryContext(ctx)
+	defer cancel()
+
+	_, dbTx, err := RetrieveDbTxByHash(ctx, pgb.db, txid)
+	if err != nil {
+		return nil, pgb.replaceCancelError(err)
+	}
+	if !dbTx.IsMainchainBlock {
+		return nil, sql.ErrNoRows
+	}
+
+	coinbase := dbTx.Tree == wire.TxTreeRegular && dbTx.BlockIndex == 0
+	tx := &exptypes.TxInfo{
+		TxBasic: &exptypes.TxBasic{
+			TxID:          txid,
+			FormattedSize: humanize.Bytes(uint64(dbTx.Size)),
+			Total:         dcrutil.Amount(dbTx.Sent).ToCoin(),
+			Coinbase:      coinbase,
+			MixCount:      uint32(dbTx.MixCount),
+			MixDenom:      dbTx.MixDenom,
+		},
+		Type:          txhelpers.TxTypeToString(int(dbTx.TxType)),
+		BlockHeight:   dbTx.BlockHeight,
+		BlockIndex:    dbTx.BlockIndex,
+		BlockHash:     dbTx.BlockHash,
+		Confirmations: pgb.Height() - dbTx.BlockHeight + 1,
+		Time:          exptypes.TimeDef(dbTx.BlockTime),
+	}
+	if coinbase {
+		tx.Type = exptypes.CoinbaseTypeStr
+	} else {
+		tx.Fee, tx.FeeRate = dcrutil.Amount(dbTx.Fees), dcrutil.Amount(dbTx.FeeRate)
+	}
+
+	if tx.IsVote() {
+		tx.VoteInfo, err = retrieveVoteInfo(ctx, pgb.db, txid, pgb.chainParams)
+		if err != nil {
+			return nil, pgb.replaceCancelError(err)
+		}
+	}
+
+	tx.Vin, err = retrieveExplorerVins(ctx, pgb.db, dbTx.VinDbIds, coinbase)
+	if err != nil {
+		return nil, pgb.replaceCancelError(err)
+	}
+	tx.Vout, err = retrieveExplorerVouts(ctx, pgb.db, dbTx.VoutDbIds)
+	if err != nil {
+		return nil, pgb.replaceCancelError(err)
+	}
+	if len(tx.Vin) == 0 {
+		return nil, fmt.Errorf("no inputs stored for transaction %s", txid)
+	}
+
+	pgb.setExplorerTxMaturity(tx)
+
+	// Initialize the spending transaction slice for safety.
+	tx.SpendingTxns = make([]exptypes.TxInID, len(tx.Vout))
+
+	return tx, nil
+}
+
+// explorerTxFromRPC creates a *exptypes.TxInfo for the transaction with the
+// given ID from the transaction and previous outputs obtained from dcrd.
+func (pgb *ChainDB) explorerTxFromRPC(txid string) *exptypes.TxInfo {
 	txhash, err := chainhash.NewHashFromStr(txid)
 	if err != nil {
 		log.Errorf("Invalid transaction hash %s", txid)
@@ -5820,6 +8134,40 @@ func (pgb *ChainDB) GetExplorerTx(txid string) *exptypes.TxInfo {
 	if tx.Vin[0].IsCoinBase() {
 		tx.Type = exptypes.CoinbaseTypeStr
 	}
+	pgb.setExplorerTxMaturity(tx)
+
+	outputs := make([]exptypes.Vout, 0, len(txraw.Vout))
+	for i, vout := range txraw.Vout {
+		txout, err := pgb.Client.GetTxOut(txhash, uint32(i), true)
+		if err != nil {
+			log.Warnf("Failed to determine if tx out is spent for output %d of tx %s", i, txid)
+		}
+		var opReturn string
+		if strings.Contains(vout.ScriptPubKey.Asm, "OP_RETURN") {
+			opReturn = vout.ScriptPubKey.Asm
+		}
+		outputs = append(outputs, exptypes.Vout{
+			Addresses:       vout.ScriptPubKey.Addresses,
+			Amount:          vout.Value,
+			FormattedAmount: humanize.Commaf(vout.Value),
+			OP_RETURN:       opReturn,
+			Type:            vout.ScriptPubKey.Type,
+			Spent:           txout == nil,
+			Index:           vout.N,
+		})
+	}
+	tx.Vout = outputs
+
+	// Initialize the spending transaction slice for safety.
+	tx.SpendingTxns = make([]exptypes.TxInID, len(outputs))
+
+	return tx
+}
+
+// setExplorerTxMaturity sets the maturity of coinbase, vote, revocation and
+// ticket transactions, and of vote funds, given the transaction's type and
+// confirmations.
+func (pgb *ChainDB) setExplorerTxMaturity(tx *exptypes.TxInfo) {
 	if tx.Type == exptypes.CoinbaseTypeStr || tx.IsRevocation() {
 		if tx.Confirmations < int64(pgb.chainParams.CoinbaseMaturity) {
 			tx.Mature = "False"
@@ -5849,33 +8197,6 @@ func (pgb *ChainDB) GetExplorerTx(txid string) *exptypes.TxInfo {
 	CoinbaseMaturityInHours := (pgb.chainParams.TargetTimePerBlock.Hours() * float64(pgb.chainParams.CoinbaseMaturity))
 	tx.MaturityTimeTill = ((float64(pgb.chainParams.CoinbaseMaturity) -
 		float64(tx.Confirmations)) / float64(pgb.chainParams.CoinbaseMaturity)) * CoinbaseMaturityInHours
-
-	outputs := make([]exptypes.Vout, 0, len(txraw.Vout))
-	for i, vout := range txraw.Vout {
-		txout, err := pgb.Client.GetTxOut(txhash, uint32(i), true)
-		if err != nil {
-			log.Warnf("Failed to determine if tx out is spent for output %d of tx %s", i, txid)
-		}
-		var opReturn string
-		if strings.Contains(vout.ScriptPubKey.Asm, "OP_RETURN") {
-			opReturn = vout.ScriptPubKey.Asm
-		}
-		outputs = append(outputs, exptypes.Vout{
-			Addresses:       vout.ScriptPubKey.Addresses,
-			Amount:          vout.Value,
-			FormattedAmount: humanize.Commaf(vout.Value),
-			OP_RETURN:       opReturn,
-			Type:            vout.ScriptPubKey.Type,
-			Spent:           txout == nil,
-			Index:           vout.N,
-		})
-	}
-	tx.Vout = outputs
-
-	// Initialize the spending transaction slice for safety.
-	tx.SpendingTxns = make([]exptypes.TxInID, len(outputs))
-
-	return tx
 }
 
 func makeExplorerAddressTx(data *chainjson.SearchRawTransactionsResult, address string) *dbtypes.AddressTx {
@@ -6226,6 +8547,7 @@ func (pgb *ChainDB) GetMempool() []exptypes.MempoolTx {
 			Type:     txhelpers.DetermineTxTypeString(msgTx),
 			VoteInfo: voteInfo,
 			Vin:      exptypes.MsgTxMempoolInputs(msgTx),
+			Expiry:   msgTx.Expiry,
 		})
 	}
 
@@ -6237,28 +8559,6 @@ func (pgb *ChainDB) BlockchainInfo() (*chainjson.GetBlockChainInfoResult, error)
 	return pgb.Client.GetBlockChainInfo()
 }
 
-// UpdateChan creates a channel that will receive height updates. All calls to
-// UpdateChan should be completed before blocks start being connected.
-func (pgb *ChainDB) UpdateChan() chan uint32 {
-	c := make(chan uint32)
-	pgb.heightClients = append(pgb.heightClients, c)
-	return c
-}
-
-// SignalHeight signals the database height to any registered receivers.
-// This function is exported so that it can be called once externally after all
-// update channel clients have subscribed.
-func (pgb *ChainDB) SignalHeight(height uint32) {
-	for i, c := range pgb.heightClients {
-		select {
-		case c <- height:
-		case <-time.NewTimer(time.Minute).C:
-			log.Criticalf("(*DBDataSaver).SignalHeight: heightClients[%d] timed out. Forcing a shutdown.", i)
-			pgb.shutdownDcrdata()
-		}
-	}
-}
-
 func (pgb *ChainDB) MixedUtxosByHeight() (heights, utxoCountReg, utxoValueReg, utxoCountStk, utxoValueStk []int64, err error) {
 	var rows *sql.Rows
 	rows, err = pgb.db.Query(internal.SelectMixedVouts, -1)
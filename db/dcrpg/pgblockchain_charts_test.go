@@ -12,7 +12,7 @@ import (
 )
 
 func registerDummyFeeAndPoolInfo(charts *cache.ChartData) {
-	dummyFetcher := func(charts *cache.ChartData) (*sql.Rows, func(), error) {
+	dummyFetcher := func(_ context.Context, charts *cache.ChartData) (*sql.Rows, func(), error) {
 		return nil, func() {}, nil
 	}
 
@@ -59,7 +59,9 @@ func TestPgCharts(t *testing.T) {
 			t.Fatalf("%s blocks length validation error: %v", tag, err)
 		}
 		_, err = cache.ValidateLengths(windows.TicketPrice, windows.PowDiff,
-			windows.Time, windows.StakeCount, windows.MissedVotes)
+			windows.Time, windows.StakeCount, windows.MissedVotes,
+			windows.TicketFeeMin, windows.TicketFeeMedian, windows.TicketFeeMax,
+			windows.TicketFeeClosing)
 		if err != nil {
 			t.Fatalf("%s windows length validation error: %v", tag, err)
 		}
@@ -75,7 +75,7 @@ func openDB() (func() error, error) {
 	cfg := &ChainDBCfg{
 		dbi,
 		chaincfg.MainNetParams(),
-		true, false, 24, 1024, 1 << 16,
+		true, false, 24, 1024, 1 << 16, 0, "", 0, 0, nil,
 	}
 	var err error
 	db, err = NewChainDB(cfg, nil, nil, new(dummyParser), nil, func() {})
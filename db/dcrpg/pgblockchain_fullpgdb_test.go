@@ -3,12 +3,14 @@
 package dcrpg
 
 import (
+	"context"
 	"encoding/csv"
 	"fmt"
 	"os"
 	"testing"
 
 	"github.com/davecgh/go-spew/spew"
+	apitypes "github.com/decred/dcrdata/api/types/v5"
 	"github.com/decred/dcrd/wire"
 	"github.com/decred/dcrdata/db/dcrpg/v5/internal"
 )
@@ -44,7 +46,7 @@ func TestMixedUtxosByHeight(t *testing.T) {
 }
 
 func TestAddressRows(t *testing.T) {
-	rows, err := db.AddressRowsMerged("Dsh6khiGjTuyExADXxjtDgz1gRr9C5dEUf6")
+	rows, err := db.AddressRowsMerged(context.Background(), "Dsh6khiGjTuyExADXxjtDgz1gRr9C5dEUf6")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -65,6 +67,50 @@ func TestAddressRows(t *testing.T) {
 	}
 }
 
+func TestPoolInfoValWeightedAvg(t *testing.T) {
+	// The ticket pool is full well after the first stake validation height,
+	// and the value-weighted average of a pool of tickets with unequal prices
+	// exceeds the plain average.
+	const height = 100000
+	check := func(what string, tpi *apitypes.TicketPoolInfo) {
+		t.Helper()
+		if tpi.ValWeightedAvg <= 0 {
+			t.Errorf("%s: ValWeightedAvg is %v at height %d", what, tpi.ValWeightedAvg, tpi.Height)
+		}
+		if tpi.ValWeightedAvg < tpi.ValAvg {
+			t.Errorf("%s: ValWeightedAvg %v is less than ValAvg %v", what,
+				tpi.ValWeightedAvg, tpi.ValAvg)
+		}
+	}
+
+	tpi, err := RetrievePoolInfo(db.ctx, db.db, height)
+	if err != nil {
+		t.Fatalf("RetrievePoolInfo: %v", err)
+	}
+	check("RetrievePoolInfo", tpi)
+
+	hash, err := RetrieveBlockHash(db.ctx, db.db, height)
+	if err != nil {
+		t.Fatalf("RetrieveBlockHash: %v", err)
+	}
+	tpi, err = RetrievePoolInfoByHash(db.ctx, db.db, hash)
+	if err != nil {
+		t.Fatalf("RetrievePoolInfoByHash: %v", err)
+	}
+	check("RetrievePoolInfoByHash", tpi)
+
+	tpis, _, err := RetrievePoolInfoRange(db.ctx, db.db, height, height+9)
+	if err != nil {
+		t.Fatalf("RetrievePoolInfoRange: %v", err)
+	}
+	if len(tpis) != 10 {
+		t.Fatalf("RetrievePoolInfoRange returned %d blocks, wanted 10", len(tpis))
+	}
+	for i := range tpis {
+		check("RetrievePoolInfoRange", &tpis[i])
+	}
+}
+
 func TestMissingIndexes(t *testing.T) {
 	missing, descs, err := db.MissingIndexes()
 	if err != nil {
@@ -116,7 +162,7 @@ func TestStuff(t *testing.T) {
 	testTxBlockTree := wire.TxTreeRegular
 
 	// Test number of spent outputs / spending transactions
-	spendingTxns, _, _, err := db.SpendingTransactions(testTx)
+	spendingTxns, _, _, err := db.SpendingTransactions(context.Background(), testTx)
 	if err != nil {
 		t.Error("SpendingTransactions", err)
 	}
@@ -128,7 +174,7 @@ func TestStuff(t *testing.T) {
 	}
 
 	// Test a certain spending transaction is as expected
-	spendingTx, _, _, err := db.SpendingTransaction(testTx, voutInd)
+	spendingTx, _, _, err := db.SpendingTransaction(context.Background(), testTx, voutInd)
 	if err != nil {
 		t.Error("SpendingTransaction", err)
 	}
@@ -140,7 +186,7 @@ func TestStuff(t *testing.T) {
 	}
 
 	// Block containing the transaction
-	blockHash, blockInd, txTree, err := db.TransactionBlock(testTx)
+	blockHash, blockInd, txTree, err := db.TransactionBlock(context.Background(), testTx)
 	if err != nil {
 		t.Fatal("TransactionBlock", err)
 	}
@@ -156,7 +202,7 @@ func TestStuff(t *testing.T) {
 	}
 
 	// List block transactions
-	blockTransactions, blockTreeOutInds, blockTxTrees, err := db.BlockTransactions(blockHash)
+	blockTransactions, blockTreeOutInds, blockTxTrees, err := db.BlockTransactions(context.Background(), blockHash)
 	if err != nil {
 		t.Error("BlockTransactions", err)
 	}
@@ -181,13 +227,13 @@ func TestStuff(t *testing.T) {
 			testTxBlockInd, blockTransactions[testTxBlockInd], testTx)
 	}
 
-	voutValue, err := db.VoutValue(testTx, voutInd)
+	voutValue, err := db.VoutValue(context.Background(), testTx, voutInd)
 	if err != nil {
 		t.Fatalf("VoutValue: %v", err)
 	}
 	t.Log(spew.Sdump(testTx, voutInd, voutValue))
 
-	voutValues, txInds, txTrees, err := db.VoutValues(testTx)
+	voutValues, txInds, txTrees, err := db.VoutValues(context.Background(), testTx)
 	if err != nil {
 		t.Fatalf("VoutValues: %v", err)
 	}
@@ -1,8 +1,25 @@
 package dcrpg
 
 import (
+	"bytes"
+	"context"
+	"database/sql"
 	"errors"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/decred/dcrd/blockchain/stake/v2"
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/chaincfg/v2"
+	"github.com/decred/dcrd/wire"
+	apitypes "github.com/decred/dcrdata/api/types/v5"
+	"github.com/decred/dcrdata/db/dbtypes/v2"
+	"github.com/decred/dcrdata/db/dcrpg/v5/internal"
+	exptypes "github.com/decred/dcrdata/explorer/types/v2"
+	"github.com/decred/dcrdata/txhelpers/v4"
+	"github.com/lib/pq"
 )
 
 func TestIsRetryError(t *testing.T) {
@@ -23,3 +40,591 @@ func TestIsRetryError(t *testing.T) {
 		})
 	}
 }
+
+func TestQueryContext(t *testing.T) {
+	waitDone := func(ctx context.Context) bool {
+		select {
+		case <-ctx.Done():
+			return true
+		case <-time.After(5 * time.Second):
+			return false
+		}
+	}
+
+	// Canceling the caller's context cancels the query.
+	pgbCtx, pgbCancel := context.WithCancel(context.Background())
+	defer pgbCancel()
+	pgb := &ChainDB{ctx: pgbCtx, queryTimeout: time.Hour}
+	reqCtx, reqCancel := context.WithCancel(context.Background())
+	ctx, cancel := pgb.queryContext(reqCtx)
+	defer cancel()
+	reqCancel()
+	if !waitDone(ctx) {
+		t.Error("query context not canceled with the caller's context")
+	}
+
+	// Canceling the ChainDB's context cancels the query.
+	ctx, cancel = pgb.queryContext(context.Background())
+	defer cancel()
+	pgbCancel()
+	if !waitDone(ctx) {
+		t.Error("query context not canceled with the ChainDB's context")
+	}
+
+	// The query timeout applies.
+	pgb = &ChainDB{ctx: context.Background(), queryTimeout: time.Millisecond}
+	ctx, cancel = pgb.queryContext(context.Background())
+	defer cancel()
+	if !waitDone(ctx) || ctx.Err() != context.DeadlineExceeded {
+		t.Error("query context did not time out")
+	}
+}
+
+// scanResult is a rowScanner that sets the id or returns err.
+type scanResult struct {
+	id  uint64
+	err error
+}
+
+func (r scanResult) Scan(dest ...interface{}) error {
+	if r.err != nil {
+		return r.err
+	}
+	*dest[0].(*uint64) = r.id
+	return nil
+}
+
+func TestQueryInsertID(t *testing.T) {
+	errFailed := errors.New("insert failed")
+	tests := []struct {
+		name    string
+		results []scanResult
+		wantID  uint64
+		wantErr error
+	}{
+		{"inserted", []scanResult{{id: 7}}, 7, nil},
+		// The conflicting row was committed after the statement's snapshot.
+		{"retried", []scanResult{{err: sql.ErrNoRows}, {id: 7}}, 7, nil},
+		{"not found", []scanResult{{err: sql.ErrNoRows}, {err: sql.ErrNoRows}}, 0, sql.ErrNoRows},
+		{"failed", []scanResult{{err: errFailed}}, 0, errFailed},
+	}
+	for _, tt := range tests {
+		var queries int
+		id, err := queryInsertID(func() rowScanner {
+			r := tt.results[queries]
+			queries++
+			return r
+		})
+		if id != tt.wantID || err != tt.wantErr {
+			t.Errorf("%s: got id %d, err %v, wanted %d, %v", tt.name, id, err, tt.wantID, tt.wantErr)
+		}
+		if queries != len(tt.results) {
+			t.Errorf("%s: ran %d queries, wanted %d", tt.name, queries, len(tt.results))
+		}
+	}
+}
+
+func TestSetTicketLuck(t *testing.T) {
+	params := chaincfg.MainNetParams()
+	pgb := &ChainDB{chainParams: params}
+	mean, stdDev := txhelpers.CalcVotingBlocksStats(params)
+
+	maturity := int64(params.TicketMaturity)
+	tests := []struct {
+		name       string
+		liveBlocks int64
+		positive   bool
+	}{
+		{"lucky", int64(mean) / 4, true},
+		{"unlucky", 3 * int64(mean), false},
+	}
+	for _, tt := range tests {
+		tl := &dbtypes.TicketLuck{
+			PurchaseHeight: 1000,
+			VoteHeight:     1000 + maturity + tt.liveBlocks,
+		}
+		pgb.setTicketLuck(tl, mean, stdDev)
+		if tl.LiveBlocks != tt.liveBlocks {
+			t.Errorf("%s: LiveBlocks = %d, want %d", tt.name, tl.LiveBlocks, tt.liveBlocks)
+		}
+		if (tl.Luck > 0) != tt.positive {
+			t.Errorf("%s: Luck = %f, want positive %v", tt.name, tl.Luck, tt.positive)
+		}
+	}
+}
+
+func TestPayloadPrefixUpperBound(t *testing.T) {
+	tests := []struct {
+		name   string
+		prefix []byte
+		want   []byte
+	}{
+		{"simple", []byte{0x01, 0x02}, []byte{0x01, 0x03}},
+		{"carry", []byte{0x01, 0xff, 0xff}, []byte{0x02}},
+		{"all ff", []byte{0xff, 0xff}, nil},
+		{"empty", []byte{}, nil},
+	}
+	for _, tt := range tests {
+		got := payloadPrefixUpperBound(tt.prefix)
+		if !bytes.Equal(got, tt.want) || (got == nil) != (tt.want == nil) {
+			t.Errorf("%s: got %x, want %x", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestMakeBlockTxsTrimmed(t *testing.T) {
+	vote := &exptypes.TrimmedTxInfo{
+		TxBasic: &exptypes.TxBasic{
+			TxID:    "vote",
+			Total:   1.5,
+			FeeRate: 10000,
+			VoteInfo: &exptypes.VoteInfo{
+				Validation: exptypes.BlockValidation{
+					Hash:     "prev",
+					Height:   99,
+					Validity: true,
+				},
+				Version:     8,
+				Bits:        1,
+				TicketSpent: "ticket",
+			},
+		},
+		VinCount:  2,
+		VoutCount: 3,
+		VoteValid: true,
+	}
+	coinbase := &exptypes.TrimmedTxInfo{
+		TxBasic: &exptypes.TxBasic{
+			TxID:     "coinbase",
+			Coinbase: true,
+		},
+	}
+
+	txs := makeBlockTxsTrimmed([]*exptypes.TrimmedTxInfo{vote, coinbase})
+	if len(txs) != 2 {
+		t.Fatalf("got %d transactions, want 2", len(txs))
+	}
+	v := txs[0]
+	if v.TxID != "vote" || v.VinCount != 2 || v.VoutCount != 3 || v.FeeRate != 0.0001 {
+		t.Errorf("unexpected vote transaction %+v", v)
+	}
+	if v.TicketSpent != "ticket" || v.Vote == nil || !v.Vote.Validation.Validity ||
+		v.Vote.Validation.Height != 99 || v.Vote.Version != 8 {
+		t.Errorf("unexpected vote info %+v", v.Vote)
+	}
+	if cb := txs[1]; !cb.Coinbase || cb.Vote != nil || cb.TicketSpent != "" {
+		t.Errorf("unexpected coinbase transaction %+v", cb)
+	}
+	if txs := makeBlockTxsTrimmed(nil); txs == nil || len(txs) != 0 {
+		t.Errorf("expected an empty non-nil slice, got %v", txs)
+	}
+}
+
+func TestMakeAgendaStatus(t *testing.T) {
+	params := chaincfg.MainNetParams()
+	now := time.Unix(1580000000, 0)
+	blockTime := int64(params.TargetTimePerBlock.Seconds())
+
+	// Voting, with 2000 counted votes in the first 1000 blocks of the interval.
+	voting := dbtypes.MileStone{
+		Status:        dbtypes.StartedAgendaStatus,
+		VotingStarted: 1000,
+		VotingDone:    9063,
+		Activated:     17128,
+	}
+	status := makeAgendaStatus("fixlnseqlocks", voting, 1999, 1500, 100, 500, params, now)
+	if status.QuorumReached {
+		t.Errorf("quorum should not be reached with 2000 votes")
+	}
+	if status.Approval != 0.75 || status.VoteRate != 2 {
+		t.Errorf("got approval %v and vote rate %v, wanted 0.75 and 2",
+			status.Approval, status.VoteRate)
+	}
+	// 2032 more votes are needed for quorum at 2 votes per block.
+	if status.QuorumHeight != 3015 || status.QuorumTime != now.Unix()+1016*blockTime {
+		t.Errorf("got quorum height %d and time %d", status.QuorumHeight, status.QuorumTime)
+	}
+	if status.DecisionHeight != 9064 || status.BlocksToDecision != 7065 ||
+		status.DecisionTime != now.Unix()+7065*blockTime {
+		t.Errorf("got decision height %d, blocks %d, and time %d",
+			status.DecisionHeight, status.BlocksToDecision, status.DecisionTime)
+	}
+
+	// At a low vote rate, quorum is not expected before the end of voting.
+	status = makeAgendaStatus("fixlnseqlocks", voting, 1999, 100, 0, 0, params, now)
+	if status.QuorumHeight != 0 || status.QuorumTime != 0 {
+		t.Errorf("unexpected quorum estimate at height %d", status.QuorumHeight)
+	}
+
+	// Locked in, the decision is the activation.
+	lockedIn := voting
+	lockedIn.Status = dbtypes.LockedInAgendaStatus
+	status = makeAgendaStatus("fixlnseqlocks", lockedIn, 12000, 7000, 100, 500, params, now)
+	if !status.QuorumReached || status.QuorumHeight != 0 || status.VoteRate != 0 {
+		t.Errorf("unexpected quorum status for locked in agenda: %+v", status)
+	}
+	if status.DecisionHeight != 17128 || status.BlocksToDecision != 5128 {
+		t.Errorf("got decision height %d and blocks %d", status.DecisionHeight,
+			status.BlocksToDecision)
+	}
+
+	// Activated, there is nothing left to estimate.
+	activated := voting
+	activated.Status = dbtypes.ActivatedAgendaStatus
+	status = makeAgendaStatus("fixlnseqlocks", activated, 20000, 7000, 100, 500, params, now)
+	if status.BlocksToDecision != 0 || status.DecisionTime != 0 {
+		t.Errorf("unexpected decision estimate for activated agenda: %+v", status)
+	}
+}
+
+func TestMakeAgendaVoteTimeline(t *testing.T) {
+	params := chaincfg.MainNetParams()
+	voting := dbtypes.MileStone{
+		Status:        dbtypes.StartedAgendaStatus,
+		VotingStarted: 1000,
+		VotingDone:    9063,
+	}
+	intervals := []*apitypes.AgendaVoteInterval{
+		{StartHeight: 1000, EndHeight: 1287, Yes: 1200, Abstain: 100, No: 300, Total: 1600},
+		{StartHeight: 1576, EndHeight: 1863, Yes: 1800, Abstain: 0, No: 700, Total: 2500},
+	}
+	timeline := makeAgendaVoteTimeline("fixlnseqlocks", voting, 288, intervals, params)
+	if timeline.Quorum != params.RuleChangeActivationQuorum || timeline.Interval != 288 ||
+		timeline.PassThreshold != 0.75 {
+		t.Errorf("unexpected timeline %+v", timeline)
+	}
+
+	first, second := timeline.Intervals[0], timeline.Intervals[1]
+	if first.CumTotal != 1600 || first.YesPercent != 80 || first.NoPercent != 20 ||
+		first.AbstainPercent != 6.25 {
+		t.Errorf("unexpected first interval %+v", first)
+	}
+	if second.CumYes != 3000 || second.CumAbstain != 100 || second.CumNo != 1000 ||
+		second.CumTotal != 4100 || second.YesPercent != 75 {
+		t.Errorf("unexpected second interval %+v", second)
+	}
+	// 4000 of the 4032 mainnet quorum votes are counted.
+	if want := 100 * 4000 / float64(4032); second.QuorumProgress != want {
+		t.Errorf("got quorum progress %v, wanted %v", second.QuorumProgress, want)
+	}
+
+	if empty := makeAgendaVoteTimeline("fixlnseqlocks", voting, 288, nil, params); empty.Intervals == nil {
+		t.Errorf("expected an empty non-nil intervals slice")
+	}
+}
+
+func TestAgendaStatusChanges(t *testing.T) {
+	chainData := func(statuses map[string]dbtypes.AgendaStatusType) *dbtypes.BlockChainData {
+		bcd := &dbtypes.BlockChainData{
+			AgendaMileStones: make(map[string]dbtypes.MileStone, len(statuses)),
+		}
+		for id, status := range statuses {
+			bcd.AgendaMileStones[id] = dbtypes.MileStone{Status: status}
+		}
+		return bcd
+	}
+
+	prev := chainData(map[string]dbtypes.AgendaStatusType{
+		"a": dbtypes.StartedAgendaStatus,
+		"b": dbtypes.StartedAgendaStatus,
+		"c": dbtypes.LockedInAgendaStatus,
+		"d": dbtypes.InitialAgendaStatus,
+		"e": dbtypes.StartedAgendaStatus,
+	})
+	cur := chainData(map[string]dbtypes.AgendaStatusType{
+		"a": dbtypes.StartedAgendaStatus,
+		"b": dbtypes.FailedAgendaStatus,
+		"c": dbtypes.ActivatedAgendaStatus,
+		"d": dbtypes.StartedAgendaStatus,
+		"e": dbtypes.LockedInAgendaStatus,
+		"f": dbtypes.ActivatedAgendaStatus,
+	})
+
+	if changes := agendaStatusChanges(nil, cur, 100, "hash"); len(changes) != 0 {
+		t.Errorf("expected no changes without previous statuses, got %d", len(changes))
+	}
+
+	changes := agendaStatusChanges(prev, cur, 100, "hash")
+	want := [][2]string{
+		{"b", exptypes.AgendaFailed},
+		{"c", exptypes.AgendaActivated},
+		{"e", exptypes.AgendaLockedIn},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("got %d changes, wanted %d", len(changes), len(want))
+	}
+	for i, asc := range changes {
+		if asc.AgendaID != want[i][0] || asc.Event != want[i][1] ||
+			asc.Height != 100 || asc.Hash != "hash" {
+			t.Errorf("change %d is %+v, wanted %s %s", i, asc, want[i][0], want[i][1])
+		}
+	}
+}
+
+func TestVoterMajorities(t *testing.T) {
+	params := &chaincfg.Params{
+		StakeMajorityMultiplier: 3,
+		StakeMajorityDivisor:    4,
+	}
+	intervals := []*voteVersionInterval{
+		{index: 0, start: 10, end: 19, counts: map[int32]int64{6: 10}},
+		// 6 of 10 votes is short of the majority of 10*3/4 = 7 votes.
+		{index: 1, start: 20, end: 29, counts: map[int32]int64{6: 4, 7: 6}},
+		// Version 8 votes also count toward version 7.
+		{index: 3, start: 40, end: 49, counts: map[int32]int64{6: 2, 7: 5, 8: 3}},
+		// Not yet complete.
+		{index: 4, start: 50, end: 59, counts: map[int32]int64{8: 8}},
+	}
+
+	count, total, majority := intervals[1].versionMajority(7, params)
+	if count != 6 || total != 10 || majority {
+		t.Errorf("versionMajority(7) = %d, %d, %v", count, total, majority)
+	}
+
+	got := voterMajorities(intervals, 55, params)
+	want := []struct {
+		version int32
+		height  int64
+	}{{6, 19}, {7, 49}}
+	if len(got) != len(want) {
+		t.Fatalf("got %d majorities, wanted %d", len(got), len(want))
+	}
+	for i, w := range want {
+		if got[i].Version != w.version || got[i].Height != w.height {
+			t.Errorf("majority %d: got version %d at %d, wanted version %d at %d",
+				i, got[i].Version, got[i].Height, w.version, w.height)
+		}
+	}
+}
+
+type execRecorder struct {
+	args []interface{}
+}
+
+func (e *execRecorder) Exec(_ string, args ...interface{}) (sql.Result, error) {
+	e.args = args
+	return nil, nil
+}
+
+func TestAddressCountChanges(t *testing.T) {
+	changes := make(addressCountChanges)
+	changes.add("Dsb", true, 1)
+	changes.add("Dsb", false, 2)
+	changes.add("Dsa", true, 1)
+	changes.add("Dsa", true, -1) // no net change
+	changes.add("Dsc", false, -1)
+	changes.add("Dsd", true, 0)
+
+	var e execRecorder
+	if err := updateAddressCounts(&e, changes); err != nil {
+		t.Fatal(err)
+	}
+	if len(e.args) != 3 {
+		t.Fatalf("got %d args, wanted 3", len(e.args))
+	}
+	addresses := e.args[0].(pq.StringArray)
+	funding := e.args[1].(pq.Int64Array)
+	spending := e.args[2].(pq.Int64Array)
+	if !reflect.DeepEqual(addresses, pq.StringArray{"Dsb", "Dsc"}) ||
+		!reflect.DeepEqual(funding, pq.Int64Array{1, 0}) ||
+		!reflect.DeepEqual(spending, pq.Int64Array{2, -1}) {
+		t.Errorf("got addresses %v, funding %v, spending %v", addresses, funding, spending)
+	}
+
+	// No statement is executed without changes.
+	e.args = nil
+	if err := updateAddressCounts(&e, addressCountChanges{"Dsa": {0, 0}}); err != nil || e.args != nil {
+		t.Errorf("unexpected update %v, %v", e.args, err)
+	}
+}
+
+func TestTicketTxnIDGetterStats(t *testing.T) {
+	tc := NewTicketTxnIDGetter(nil)
+	tc.SetN([]string{"t1", "t2"}, []uint64{1, 2})
+	tc.Set("t3", 3)
+
+	if id, err := tc.TxnDbID("t2", true); err != nil || id != 2 {
+		t.Fatalf("got id %d, err %v", id, err)
+	}
+	if _, err := tc.TxnDbID("t1", false); err != nil {
+		t.Fatal(err)
+	}
+
+	stats := tc.stats.report(CacheUnspentTickets, tc.Len())
+	if stats.Entries != 2 || stats.Hits != 2 || stats.Misses != 0 {
+		t.Errorf("got %d entries, %d hits, %d misses", stats.Entries,
+			stats.Hits, stats.Misses)
+	}
+
+	if n := tc.Clear(); n != 2 {
+		t.Errorf("cleared %d entries, wanted 2", n)
+	}
+	if tc.Len() != 0 {
+		t.Errorf("%d entries remain after clearing", tc.Len())
+	}
+}
+
+func TestBlockIDCache(t *testing.T) {
+	c := newBlockIDCache(2)
+	h1, h2, h3 := chainhash.Hash{1}, chainhash.Hash{2}, chainhash.Hash{3}
+	c.set(h1, 1)
+	c.set(h2, 2)
+	c.set(h1, 11) // update, no eviction
+	if id, ok := c.get(h1); !ok || id != 11 {
+		t.Fatalf("got id %d (%v), wanted 11", id, ok)
+	}
+
+	// The oldest block is evicted once the cache is full.
+	c.set(h3, 3)
+	if _, ok := c.get(h1); ok {
+		t.Errorf("oldest block not evicted")
+	}
+	for _, h := range []chainhash.Hash{h2, h3} {
+		if _, ok := c.get(h); !ok {
+			t.Errorf("block %v evicted", h)
+		}
+	}
+
+	stats := c.stats.report(CacheBlockIDs, c.Len())
+	if stats.Entries != 2 || stats.Hits != 3 || stats.Misses != 1 {
+		t.Errorf("got %d entries, %d hits, %d misses", stats.Entries,
+			stats.Hits, stats.Misses)
+	}
+
+	if n := c.Clear(); n != 2 {
+		t.Errorf("cleared %d entries, wanted 2", n)
+	}
+	c.set(h1, 1)
+	if c.Len() != 1 {
+		t.Errorf("got %d entries after clearing, wanted 1", c.Len())
+	}
+}
+
+func TestUTXOMaturity(t *testing.T) {
+	params := chaincfg.MainNetParams()
+	tests := []struct {
+		name          string
+		txType        stake.TxType
+		tree          int8
+		blockIndex    uint32
+		vout          uint32
+		wantMaturity  int64
+		wantSpendable bool
+	}{
+		{"regular", stake.TxTypeRegular, wire.TxTreeRegular, 3, 0, 0, true},
+		{"coinbase", stake.TxTypeRegular, wire.TxTreeRegular, 0, 2, int64(params.CoinbaseMaturity), true},
+		{"vote", stake.TxTypeSSGen, wire.TxTreeStake, 0, 2, int64(params.CoinbaseMaturity), true},
+		{"revocation", stake.TxTypeSSRtx, wire.TxTreeStake, 1, 0, int64(params.CoinbaseMaturity), true},
+		{"ticket submission", stake.TxTypeSStx, wire.TxTreeStake, 0, 0, int64(params.TicketMaturity), false},
+		{"ticket change", stake.TxTypeSStx, wire.TxTreeStake, 0, 2, int64(params.SStxChangeMaturity), true},
+	}
+	for _, tt := range tests {
+		maturity, spendable := utxoMaturity(tt.txType, tt.tree, tt.blockIndex, tt.vout, params)
+		if maturity != tt.wantMaturity || spendable != tt.wantSpendable {
+			t.Errorf("%s: got maturity %d, spendable %v, wanted %d, %v", tt.name,
+				maturity, spendable, tt.wantMaturity, tt.wantSpendable)
+		}
+	}
+}
+
+func TestSpentArchiveQueries(t *testing.T) {
+	utxos := internal.SelectUTXOs
+	if got := (*spentArchive)(nil).unspentQuery(utxos); got != utxos {
+		t.Errorf("disabled archive changed the query to %q", got)
+	}
+
+	sa := &spentArchive{height: 1000, boundary: time.Unix(1500000000, 0)}
+	wantHot := strings.Replace(utxos, "FROM vouts", "FROM ONLY vouts", 1)
+	if got := sa.unspentQuery(utxos); got != wantHot {
+		t.Errorf("got unspent query %q, wanted %q", got, wantHot)
+	}
+
+	history := internal.SelectAddressLimitNByAddressTimeRange
+	if got := sa.sinceQuery(history, sa.boundary); got != history {
+		t.Errorf("query starting at the boundary must read the archive, got %q", got)
+	}
+	got := sa.sinceQuery(history, sa.boundary.Add(time.Second))
+	if !strings.Contains(got, "FROM ONLY addresses") {
+		t.Errorf("query starting after the boundary must read only the hot table, got %q", got)
+	}
+}
+
+func TestBestBlock(t *testing.T) {
+	bb := &BestBlock{height: -1}
+	if bb.HashStr() != "" || *bb.Hash() != zeroHash {
+		t.Errorf("unexpected best block hash with no blocks")
+	}
+
+	hash := chainhash.HashH([]byte("block"))
+	bb.set(42, &hash, hash.String())
+	if bb.Height() != 42 || *bb.Hash() != hash || bb.HashStr() != hash.String() {
+		t.Errorf("got best block %d %s, wanted 42 %s", bb.Height(), bb.HashStr(), hash)
+	}
+
+	// The returned hash is a copy.
+	bb.Hash()[0]++
+	if *bb.Hash() != hash {
+		t.Errorf("best block hash modified through a getter")
+	}
+}
+
+func BenchmarkBestBlock(b *testing.B) {
+	hash := chainhash.HashH([]byte("block"))
+	pgb := &ChainDB{bestBlock: new(BestBlock)}
+	pgb.bestBlock.set(42, &hash, hash.String())
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		pgb.BestBlock()
+		pgb.BestBlockStr()
+	}
+}
+
+func TestTicketPoolDataCacheReorg(t *testing.T) {
+	defer ticketPoolGraphsCache.clear()
+	interval := dbtypes.DayGrouping
+	hash := chainhash.HashH([]byte("block"))
+	pgb := &ChainDB{bestBlock: new(BestBlock)}
+	pgb.bestBlock.set(42, &hash, hash.String())
+
+	timeChart := &dbtypes.PoolTicketsData{Count: []uint64{1}}
+	UpdateTicketPoolData(interval, timeChart, &dbtypes.PoolTicketsData{},
+		&dbtypes.PoolTicketsData{}, &dbtypes.PoolTicketsData{}, 42, hash)
+
+	// The data fetched at the best block is served from the cache.
+	gotTime, _, _, _, height, err := pgb.TicketPoolVisualization(context.Background(), interval)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotTime != timeChart || height != 42 {
+		t.Errorf("cached data not returned for the best block")
+	}
+
+	// A reorg to another block at the same height makes the data stale.
+	reorgHash := chainhash.HashH([]byte("reorg block"))
+	pgb.bestBlock.set(42, &reorgHash, reorgHash.String())
+	hashSeen, _ := pgb.BestBlock()
+	_, _, _, _, height, found, stale := TicketPoolData(interval, *hashSeen)
+	if !found || !stale || height != 42 {
+		t.Errorf("got found %v, stale %v at height %d after a same height reorg", found, stale, height)
+	}
+	if _, _, _, _, _, _, stale = TicketPoolData(interval, hash); stale {
+		t.Errorf("data stale for the block it was fetched at")
+	}
+}
+
+func TestAddressRowsSummary(t *testing.T) {
+	rows := []*dbtypes.AddressRowCompact{
+		{TxHash: chainhash.Hash{1}, TxBlockTime: 2000, ValidMainChain: true, IsFunding: true},
+		{TxHash: chainhash.Hash{1}, TxBlockTime: 2000, ValidMainChain: true},
+		{TxHash: chainhash.Hash{2}, TxBlockTime: 1000, ValidMainChain: true},
+		{TxHash: chainhash.Hash{3}, TxBlockTime: 500}, // not valid mainchain
+	}
+	numTxns, first, last := addressRowsSummary(rows)
+	if numTxns != 2 || first != 1000 || last != 2000 {
+		t.Errorf("got %d transactions from %d to %d", numTxns, first, last)
+	}
+	if numTxns, first, last = addressRowsSummary(nil); numTxns != 0 || first != 0 || last != 0 {
+		t.Errorf("got %d transactions from %d to %d for no rows", numTxns, first, last)
+	}
+}
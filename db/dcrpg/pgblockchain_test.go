@@ -4,6 +4,7 @@ package dcrpg
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"fmt"
 	"reflect"
@@ -14,6 +15,7 @@ import (
 	"github.com/decred/dcrd/chaincfg/v2"
 	"github.com/decred/dcrd/dcrutil/v2"
 	chainjson "github.com/decred/dcrd/rpc/jsonrpc/types/v2"
+	apitypes "github.com/decred/dcrdata/api/types/v5"
 	"github.com/decred/dcrdata/db/cache/v3"
 	"github.com/decred/dcrdata/db/dbtypes/v2"
 )
@@ -21,7 +23,7 @@ import (
 func TestChainDB_AddressTransactionsAll(t *testing.T) {
 	// address with no transactions.
 	address := "DsUBCQWJsW8raht1i4gXTv7xPu3ySpUxxxx"
-	rows, err := db.AddressTransactionsAll(address)
+	rows, err := db.AddressTransactionsAll(context.Background(), address)
 	if err != nil {
 		t.Errorf("err should have been nil, was: %v", err)
 	}
@@ -29,7 +31,7 @@ func TestChainDB_AddressTransactionsAll(t *testing.T) {
 		t.Fatalf("should have been no rows, got %v", rows)
 	}
 
-	height, hash, _ := db.HeightHashDBLegacy()
+	height, hash, _ := db.HeightHashDBLegacy(context.Background())
 	h, _ := chainhash.NewHashFromStr(hash)
 	blockID := cache.NewBlockID(h, int64(height))
 	wasStored := db.AddressCache.StoreRows(address, rows, blockID)
@@ -46,10 +48,50 @@ func TestChainDB_AddressTransactionsAll(t *testing.T) {
 	}
 }
 
+func TestRetrieveAddressAtHeight(t *testing.T) {
+	address := "Dcur2mcGjmENx4DhNqDctW5wJCVyT3Qeqkx"
+	ctx := context.Background()
+
+	// At the best block, the pinned rows and balance match the unpinned ones.
+	height := db.Height()
+	rows, err := db.AddressTransactionsAll(context.Background(), address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pinnedRows, err := RetrieveAllMainchainAddressTxnsAtHeight(ctx, db.db, address, height)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pinnedRows) != len(rows) {
+		t.Errorf("got %d rows at height %d, expected %d", len(pinnedRows), height, len(rows))
+	}
+
+	bal, err := RetrieveAddressBalance(ctx, db.db, address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pinnedBal, err := RetrieveAddressBalanceAtHeight(ctx, db.db, address, height)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(bal, pinnedBal) {
+		t.Errorf("got balance %+v at height %d, expected %+v", pinnedBal, height, bal)
+	}
+
+	// Nothing is funded at genesis.
+	pinnedRows, err = RetrieveAllMainchainAddressTxnsAtHeight(ctx, db.db, address, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pinnedRows) != 0 {
+		t.Errorf("got %d rows at height 0, expected none", len(pinnedRows))
+	}
+}
+
 func TestMergeRows(t *testing.T) {
 	address := "Dcur2mcGjmENx4DhNqDctW5wJCVyT3Qeqkx"
 
-	rows, err := db.AddressTransactionsAll(address)
+	rows, err := db.AddressTransactionsAll(context.Background(), address)
 	if err != nil {
 		t.Errorf("err should have been nil, was: %v", err)
 	}
@@ -66,7 +108,7 @@ func TestMergeRows(t *testing.T) {
 	t.Logf("%d rows combined to %d merged rows in %v", len(rows),
 		len(mergedRows), time.Since(tStart))
 
-	mergedRows0, err := db.AddressTransactionsAllMerged(address)
+	mergedRows0, err := db.AddressTransactionsAllMerged(context.Background(), address)
 	if err != nil {
 		t.Errorf("err should have been nil, was: %v", err)
 	}
@@ -92,7 +134,7 @@ func TestMergeRows(t *testing.T) {
 }
 
 func TestRetrieveUTXOs(t *testing.T) {
-	utxos, err := RetrieveUTXOs(context.Background(), db.db)
+	utxos, err := RetrieveUTXOs(context.Background(), db.db, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -109,8 +151,76 @@ func TestRetrieveUTXOs(t *testing.T) {
 		dcrutil.Amount(totalValue))
 }
 
+func TestUTXODistribution(t *testing.T) {
+	if err := CreateTable(db.db, "utxo_distribution"); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if err := InsertUTXODistribution(ctx, db.db, nil, time.Now(), db.Height()); err != nil {
+		t.Fatal(err)
+	}
+	dist, err := RetrieveLatestUTXODistribution(ctx, db.db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The buckets should account for all of the UTXOs.
+	utxos, err := RetrieveUTXOs(ctx, db.db, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var count, value int64
+	for _, b := range dist.Buckets {
+		count += b.Count
+		value += b.Value
+	}
+	var totalValue int64
+	for i := range utxos {
+		totalValue += utxos[i].Value
+	}
+	if count != int64(len(utxos)) || value != totalValue {
+		t.Errorf("distribution has %d UTXOs worth %d, expected %d worth %d",
+			count, value, len(utxos), totalValue)
+	}
+}
+
+func TestSyncCheckpoint(t *testing.T) {
+	if err := CreateTable(db.db, "sync_checkpoints"); err != nil {
+		t.Fatal(err)
+	}
+	hash, height := db.BestBlockStr()
+	if err := InsertSyncCheckpoint(db.db, height, hash, dbtypes.SyncPhaseComplete, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	checkpoint, err := RetrieveSyncCheckpoint(ctx, db.db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if checkpoint == nil || checkpoint.Height != height || checkpoint.Hash != hash {
+		t.Fatalf("wrong checkpoint %v, expected height %d hash %s", checkpoint, height, hash)
+	}
+	if checkpoint.NumBlocks == 0 || checkpoint.NumTransactions == 0 {
+		t.Errorf("checkpoint row counts not set: %v", checkpoint)
+	}
+
+	// The DB is consistent with the checkpoint.
+	if _, err = db.resumeSyncCheckpoint(ctx, height); err != nil {
+		t.Error(err)
+	}
+
+	// Checkpoints above the best block are removed.
+	if _, err = DeleteSyncCheckpointsAbove(db.db, height-1); err != nil {
+		t.Fatal(err)
+	}
+	if checkpoint, err = RetrieveSyncCheckpoint(ctx, db.db); err != nil || checkpoint != nil {
+		t.Errorf("expected no checkpoint, got %v (%v)", checkpoint, err)
+	}
+}
+
 func TestUtxoStore_Reinit(t *testing.T) {
-	utxos, err := RetrieveUTXOs(context.Background(), db.db)
+	utxos, err := RetrieveUTXOs(context.Background(), db.db, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -235,6 +345,63 @@ func TestRetrieveTxsByBlockHash(t *testing.T) {
 	}
 }
 
+func TestExplorerTxFromDB(t *testing.T) {
+	block0 := "298e5cc3d985bfe7f81dc135f360abe089edd4396b86d2de66b0cef42b21d980" // genesis
+	_, txHashes, _, _, _, err := RetrieveTxsByBlockHash(
+		context.Background(), db.db, block0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txHashes) == 0 {
+		t.Fatal("no transactions in the genesis block")
+	}
+
+	_, dbTx, err := RetrieveDbTxByHash(context.Background(), db.db, txHashes[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx, err := db.explorerTxFromDB(context.Background(), txHashes[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.BlockHash != block0 || tx.Type != "Coinbase" {
+		t.Errorf("got %s transaction in block %s", tx.Type, tx.BlockHash)
+	}
+	if len(tx.Vin) != int(dbTx.NumVin) || len(tx.Vout) != int(dbTx.NumVout) {
+		t.Errorf("got %d inputs and %d outputs, expected %d and %d",
+			len(tx.Vin), len(tx.Vout), dbTx.NumVin, dbTx.NumVout)
+	}
+	if !tx.Vin[0].IsCoinBase() {
+		t.Errorf("the first input is not a coinbase input")
+	}
+}
+
+func TestVote(t *testing.T) {
+	var txHash string
+	err := db.db.QueryRow(`SELECT tx_hash FROM votes WHERE is_mainchain LIMIT 1;`).Scan(&txHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vote, err := db.Vote(context.Background(), txHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vote.TxID != txHash || vote.Ticket == "" || !vote.IsMainchain {
+		t.Errorf("wrong vote %v", vote)
+	}
+	if vote.Validation.Height != vote.BlockHeight-1 {
+		t.Errorf("vote at height %d validated block %d", vote.BlockHeight,
+			vote.Validation.Height)
+	}
+	if vote.Choices == nil {
+		t.Errorf("vote choices not set")
+	}
+
+	if _, err = db.Vote(context.Background(), "00"); err != sql.ErrNoRows {
+		t.Errorf("expected sql.ErrNoRows, got %v", err)
+	}
+}
+
 func TestUpdateChainState(t *testing.T) {
 	// rawData is a sample payload format as returned by getBlockChainInfo rpc endpoint.
 	var rawData = []byte(`{
@@ -348,3 +515,133 @@ func TestUpdateChainState(t *testing.T) {
 		t.Fatalf("expected both payloads to match but the did not")
 	}
 }
+
+func TestDuplicateTransactions(t *testing.T) {
+	txns, err := db.DuplicateTransactions(context.Background(), 20, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tx := range txns {
+		if len(tx.Blocks) < 2 {
+			t.Errorf("transaction %s in %d blocks", tx.TxID, len(tx.Blocks))
+		}
+		var mainchain int
+		for _, b := range tx.Blocks {
+			if b.Status == apitypes.TxBlockMainchain {
+				mainchain++
+			}
+		}
+		if mainchain > 1 || tx.Mainchain != (mainchain == 1) {
+			t.Errorf("transaction %s in %d valid mainchain blocks, mainchain = %v",
+				tx.TxID, mainchain, tx.Mainchain)
+		}
+	}
+}
+
+func TestInsertTxConflict(t *testing.T) {
+	var id uint64
+	var txHash, blockHash string
+	err := db.db.QueryRow(`SELECT id, tx_hash, block_hash FROM transactions LIMIT 1;`).
+		Scan(&id, &txHash, &blockHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Inserting a stored transaction returns the id of the existing row
+	// rather than sql.ErrNoRows.
+	dbTx := &dbtypes.Tx{TxID: txHash, BlockHash: blockHash}
+	gotID, err := InsertTx(db.db, dbTx, true, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotID != id {
+		t.Errorf("got row id %d, expected %d", gotID, id)
+	}
+	ids, err := InsertTxns(db.db, []*dbtypes.Tx{dbTx, dbTx}, true, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 2 || ids[0] != id || ids[1] != id {
+		t.Errorf("got row ids %v, expected two of %d", ids, id)
+	}
+}
+
+func TestAddressTxCounts(t *testing.T) {
+	var address string
+	var numFunding, numSpending int64
+	err := db.db.QueryRow(`SELECT address,
+			COUNT(*) FILTER (WHERE is_funding), COUNT(*) FILTER (WHERE NOT is_funding)
+		FROM addresses
+		WHERE valid_mainchain
+		GROUP BY address
+		ORDER BY COUNT(*) DESC
+		LIMIT 1;`).Scan(&address, &numFunding, &numSpending)
+	if err != nil {
+		t.Fatal(err)
+	}
+	counts, err := db.AddressTxCounts(context.Background(), address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if counts.NumFunding != numFunding || counts.NumSpending != numSpending {
+		t.Errorf("address %s counts (%d, %d) do not match the addresses table (%d, %d)",
+			address, counts.NumFunding, counts.NumSpending, numFunding, numSpending)
+	}
+}
+
+func TestBlockStakeHeader(t *testing.T) {
+	hash, height := db.BestBlockStr()
+	bsh, err := db.BlockStakeHeader(context.Background(), hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bsh.Hash != hash || int64(bsh.Height) != height || !bsh.IsMainchain {
+		t.Errorf("got block %s (%d), mainchain = %v, wanted %s (%d)", bsh.Hash,
+			bsh.Height, bsh.IsMainchain, hash, height)
+	}
+	// The final state is 6 bytes, hex encoded.
+	if bsh.FinalState != "" && len(bsh.FinalState) != 12 {
+		t.Errorf("invalid final state %q", bsh.FinalState)
+	}
+
+	if _, err = db.BlockStakeHeader(context.Background(), "00"); err != sql.ErrNoRows {
+		t.Errorf("expected sql.ErrNoRows for an unknown block, got %v", err)
+	}
+}
+
+func TestAgendaVotesByTicketType(t *testing.T) {
+	var agendaID string
+	err := db.db.QueryRow(`SELECT name FROM agendas LIMIT 1;`).Scan(&agendaID)
+	if err == sql.ErrNoRows {
+		t.Skip("no agendas stored")
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	_, height := db.BestBlockStr()
+	votes, err := retrieveAgendaVotesByTicketType(ctx, db.db, agendaID, 0, height)
+	if err != nil {
+		t.Fatal(err)
+	}
+	yes, abstain, no, err := retrieveTotalAgendaVotesCount(ctx, db.db, agendaID, 0, height)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(votes) != len(dbtypes.TicketTypes) {
+		t.Fatalf("got %d ticket types, expected %d", len(votes), len(dbtypes.TicketTypes))
+	}
+	var sumYes, sumAbstain, sumNo uint32
+	for _, ttv := range votes {
+		sumYes += ttv.Yes
+		sumAbstain += ttv.Abstain
+		sumNo += ttv.No
+	}
+	// Every vote has a stored ticket, so no votes are lost in the breakdown.
+	if sumYes != yes || sumAbstain != abstain || sumNo != no {
+		t.Errorf("ticket type vote totals (%d, %d, %d) do not match the agenda totals (%d, %d, %d)",
+			sumYes, sumAbstain, sumNo, yes, abstain, no)
+	}
+}
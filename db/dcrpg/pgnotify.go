@@ -0,0 +1,114 @@
+// Copyright (c) 2020, The Decred developers
+// See LICENSE for details.
+
+package dcrpg
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/decred/dcrd/wire"
+	"github.com/decred/dcrdata/db/dcrpg/v5/internal"
+	exptypes "github.com/decred/dcrdata/explorer/types/v2"
+	"github.com/decred/dcrdata/txhelpers/v4"
+)
+
+// Chain event types sent to PostgreSQL LISTENers on the notify channel.
+const (
+	ChainEventBlock        = "block"
+	ChainEventReorg        = "reorg"
+	ChainEventSyncComplete = "sync_complete"
+	ChainEventInvalidation = "block_invalidated"
+)
+
+// notifyTimeout is the maximum time to wait for pg_notify.
+const notifyTimeout = 10 * time.Second
+
+// ChainEvent is the JSON payload of a notification sent with pg_notify on the
+// notify channel. Height and Hash are always the main chain tip after the
+// event. The Old* and CommonAncestor fields are only set for reorgs, Time is
+// only set for new blocks, and the Invalidated* fields are only set for block
+// invalidations. The reversed transactions are not included since payloads
+// are limited to 8000 bytes.
+type ChainEvent struct {
+	Event             string `json:"event"`
+	Height            int64  `json:"height"`
+	Hash              string `json:"hash"`
+	Time              int64  `json:"time,omitempty"`
+	OldHeight         int64  `json:"old_height,omitempty"`
+	OldHash           string `json:"old_hash,omitempty"`
+	CommonAncestor    string `json:"common_ancestor,omitempty"`
+	InvalidatedHeight int64  `json:"invalidated_height,omitempty"`
+	InvalidatedHash   string `json:"invalidated_hash,omitempty"`
+	NumReversedTxns   int    `json:"num_reversed_txns,omitempty"`
+}
+
+// notify sends the chain event as compact JSON on the notify channel, if one
+// is configured. Failures are logged but not returned since notifications are
+// a courtesy to external consumers and must not interrupt block processing.
+func (pgb *ChainDB) notify(event *ChainEvent) {
+	if pgb.notifyChannel == "" {
+		return
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		log.Errorf("Failed to encode %s notification: %v", event.Event, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(pgb.ctx, notifyTimeout)
+	defer cancel()
+	_, err = pgb.db.ExecContext(ctx, internal.Notify, pgb.notifyChannel, string(payload))
+	if err != nil {
+		log.Warnf("Failed to send %s notification on channel %q: %v",
+			event.Event, pgb.notifyChannel, pgb.replaceCancelError(err))
+	}
+}
+
+// notifyBlock sends a ChainEventBlock notification for a new main chain block.
+func (pgb *ChainDB) notifyBlock(msgBlock *wire.MsgBlock) {
+	pgb.notify(&ChainEvent{
+		Event:  ChainEventBlock,
+		Height: int64(msgBlock.Header.Height),
+		Hash:   msgBlock.BlockHash().String(),
+		Time:   msgBlock.Header.Timestamp.Unix(),
+	})
+}
+
+// notifyReorg sends a ChainEventReorg notification for a completed reorg.
+func (pgb *ChainDB) notifyReorg(reorg *txhelpers.ReorgData) {
+	pgb.notify(&ChainEvent{
+		Event:          ChainEventReorg,
+		Height:         int64(reorg.NewChainHeight),
+		Hash:           reorg.NewChainHead.String(),
+		OldHeight:      int64(reorg.OldChainHeight),
+		OldHash:        reorg.OldChainHead.String(),
+		CommonAncestor: reorg.CommonAncestor.String(),
+	})
+}
+
+// notifyInvalidation sends a ChainEventInvalidation notification for a block
+// disapproved by the votes in the next block.
+func (pgb *ChainDB) notifyInvalidation(inv *exptypes.BlockInvalidation) {
+	pgb.notify(&ChainEvent{
+		Event:             ChainEventInvalidation,
+		Height:            inv.Height + 1,
+		Hash:              inv.InvalidatedBy,
+		InvalidatedHeight: inv.Height,
+		InvalidatedHash:   inv.Hash,
+		NumReversedTxns:   len(inv.Transactions),
+	})
+}
+
+// notifySyncComplete sends a ChainEventSyncComplete notification with the
+// best block after the initial sync.
+func (pgb *ChainDB) notifySyncComplete() {
+	hash, height := pgb.BestBlock()
+	pgb.notify(&ChainEvent{
+		Event:  ChainEventSyncComplete,
+		Height: height,
+		Hash:   hash.String(),
+	})
+}
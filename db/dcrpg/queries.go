@@ -11,20 +11,24 @@ import (
 	"encoding/hex"
 	"fmt"
 	"math/big"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/decred/dcrd/blockchain/stake/v2"
 	"github.com/decred/dcrd/chaincfg/chainhash"
 	"github.com/decred/dcrd/chaincfg/v2"
 	"github.com/decred/dcrd/dcrutil/v2"
+	chainjson "github.com/decred/dcrd/rpc/jsonrpc/types/v2"
 	"github.com/decred/dcrd/txscript/v2"
 	"github.com/decred/dcrd/wire"
 	apitypes "github.com/decred/dcrdata/api/types/v5"
 	"github.com/decred/dcrdata/db/cache/v3"
 	"github.com/decred/dcrdata/db/dbtypes/v2"
 	"github.com/decred/dcrdata/db/dcrpg/v5/internal"
+	exptypes "github.com/decred/dcrdata/explorer/types/v2"
 	"github.com/decred/dcrdata/txhelpers/v4"
 	humanize "github.com/dustin/go-humanize"
 	"github.com/lib/pq"
@@ -78,6 +82,57 @@ func SetIBDComplete(db SqlExecutor, ibdComplete bool) error {
 	return nil
 }
 
+// InsertSyncCheckpoint records a sync checkpoint at the given block, with the
+// current table row counts, and removes all but the most recent keep
+// checkpoints.
+func InsertSyncCheckpoint(db SqlExecutor, height int64, hash, phase string, keep int) error {
+	_, err := sqlExec(db, internal.InsertSyncCheckpoint,
+		"failed to insert sync checkpoint: ", height, hash, phase)
+	if err != nil {
+		return err
+	}
+	_, err = sqlExec(db, internal.PruneSyncCheckpoints,
+		"failed to prune sync checkpoints: ", keep)
+	return err
+}
+
+// DeleteSyncCheckpointsAbove removes the sync checkpoints above the given
+// height.
+func DeleteSyncCheckpointsAbove(db SqlExecutor, height int64) (int64, error) {
+	return sqlExec(db, internal.DeleteSyncCheckpointsAbove,
+		"failed to delete sync checkpoints: ", height)
+}
+
+// RetrieveSyncCheckpoint retrieves the most recent sync checkpoint. A nil
+// checkpoint is returned if there are none.
+func RetrieveSyncCheckpoint(ctx context.Context, db *sql.DB) (*dbtypes.SyncCheckpoint, error) {
+	var cp dbtypes.SyncCheckpoint
+	err := db.QueryRowContext(ctx, internal.SelectLastSyncCheckpoint).Scan(
+		&cp.Height, &cp.Hash, &cp.Phase, &cp.NumBlocks, &cp.NumTransactions,
+		&cp.NumVins, &cp.NumVouts, &cp.NumAddresses, &cp.Time)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &cp, nil
+}
+
+// retrieveSyncRowCounts retrieves the current row counts of the tables
+// recorded in a sync checkpoint. Only the row counts of the returned checkpoint
+// are set.
+func retrieveSyncRowCounts(ctx context.Context, db *sql.DB) (*dbtypes.SyncCheckpoint, error) {
+	var cp dbtypes.SyncCheckpoint
+	err := db.QueryRowContext(ctx, internal.SelectSyncRowCounts).Scan(
+		&cp.NumBlocks, &cp.NumTransactions, &cp.NumVins, &cp.NumVouts,
+		&cp.NumAddresses)
+	if err != nil {
+		return nil, err
+	}
+	return &cp, nil
+}
+
 // outputCountType defines the modes of the output count chart data.
 // outputCountByAllBlocks defines count per block i.e. solo and pooled tickets
 // count per block. outputCountByTicketPoolWindow defines the output count per
@@ -507,13 +562,38 @@ func DeleteDuplicateAgendaVotes(db *sql.DB) (int64, error) {
 	return sqlExec(db, internal.DeleteAgendaVotesDuplicateRows, execErrPrefix)
 }
 
+// rowScanner is satisfied by *sql.Row.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// queryInsertID scans the row id returned by an insert statement from
+// internal.Make*InsertStatement. The ON CONFLICT DO NOTHING statements fall
+// back to selecting the conflicting row, but that SELECT uses the snapshot
+// taken when the statement began. If the conflicting row was committed by a
+// concurrent transaction after that, the statement returns no rows. Running the
+// query again takes a new snapshot in which the row is visible, so the
+// conflicting row id is returned. sql.ErrNoRows is only returned if the retry
+// also finds no row, and callers treat it as any other insert error. The
+// unchecked statements used during a bulk load without the unique indexes (see
+// EnableDuplicateCheckOnInsert) always return the inserted row id.
+func queryInsertID(queryRow func() rowScanner) (id uint64, err error) {
+	err = queryRow().Scan(&id)
+	if err == sql.ErrNoRows {
+		err = queryRow().Scan(&id)
+	}
+	return
+}
+
 // --- stake (votes, tickets, misses) tables ---
 
 // InsertTickets takes a slice of *dbtypes.Tx and corresponding DB row IDs for
 // transactions, extracts the tickets, and inserts the tickets into the
-// database. Outputs are a slice of DB row IDs of the inserted tickets, and an
-// error.
-func InsertTickets(db *sql.DB, dbTxns []*dbtypes.Tx, txDbIDs []uint64, checked, updateExistingRecords bool) ([]uint64, []*dbtypes.Tx, error) {
+// database. The ticket commitment outputs are parsed to store the reward
+// addresses, amounts and fee limits. Outputs are a slice of DB row IDs of the
+// inserted tickets, and an error.
+func InsertTickets(db *sql.DB, dbTxns []*dbtypes.Tx, txDbIDs []uint64, checked, updateExistingRecords bool,
+	params *chaincfg.Params) ([]uint64, []*dbtypes.Tx, error) {
 	dbtx, err := db.Begin()
 	if err != nil {
 		return nil, nil, fmt.Errorf("unable to begin database transaction: %v", err)
@@ -558,16 +638,27 @@ func InsertTickets(db *sql.DB, dbTxns []*dbtypes.Tx, txDbIDs []uint64, checked,
 		fee := dcrutil.Amount(tx.Fees).ToCoin()
 		isSplit := tx.NumVin > 1
 
-		var id uint64
-		err := stmt.QueryRow(
-			tx.TxID, tx.BlockHash, tx.BlockHeight, ticketDbIDs[i],
-			stakesubmissionAddress, isMultisig, isSplit, tx.NumVin,
-			price, fee, dbtypes.TicketUnspent, dbtypes.PoolStatusLive,
-			tx.IsMainchainBlock).Scan(&id)
+		// The commitment columns are NULL for a ticket with commitments that
+		// cannot be parsed, rather than empty arrays, so that the row is not
+		// mistaken for one with no commitments.
+		var rewardAddrs, amounts, voteLimits, revokeLimits interface{}
+		commitments, err := dbtypes.TicketCommitments(tx.Vouts, params)
+		if err != nil {
+			log.Errorf("Unable to parse commitments of ticket %s: %v", tx.TxID, err)
+		} else {
+			addrs, amts, votes, revokes := ticketCommitmentArrays(commitments)
+			rewardAddrs, amounts = pq.StringArray(addrs), pq.Int64Array(amts)
+			voteLimits, revokeLimits = pq.Int64Array(votes), pq.Int64Array(revokes)
+		}
+
+		id, err := queryInsertID(func() rowScanner {
+			return stmt.QueryRow(
+				tx.TxID, tx.BlockHash, tx.BlockHeight, ticketDbIDs[i],
+				stakesubmissionAddress, isMultisig, isSplit, tx.NumVin,
+				price, fee, dbtypes.TicketUnspent, dbtypes.PoolStatusLive,
+				tx.IsMainchainBlock, rewardAddrs, amounts, voteLimits, revokeLimits)
+		})
 		if err != nil {
-			if err == sql.ErrNoRows {
-				continue
-			}
 			_ = stmt.Close() // try, but we want the QueryRow error back
 			if errRoll := dbtx.Rollback(); errRoll != nil {
 				log.Errorf("Rollback failed: %v", errRoll)
@@ -583,6 +674,35 @@ func InsertTickets(db *sql.DB, dbTxns []*dbtypes.Tx, txDbIDs []uint64, checked,
 	return ids, ticketTx, dbtx.Commit()
 }
 
+// ticketCommitmentArrays splits the ticket commitments into the arrays stored
+// in the tickets table.
+func ticketCommitmentArrays(commitments []*dbtypes.TicketCommitment) (addrs []string, amounts, voteLimits, revokeLimits []int64) {
+	for _, c := range commitments {
+		addrs = append(addrs, c.Address)
+		amounts = append(amounts, c.Amount)
+		voteLimits = append(voteLimits, c.VoteFeeLimit)
+		revokeLimits = append(revokeLimits, c.RevokeFeeLimit)
+	}
+	return
+}
+
+// ticketCommitmentsFromArrays is the inverse of ticketCommitmentArrays.
+func ticketCommitmentsFromArrays(addrs []string, amounts, voteLimits, revokeLimits []int64) ([]*dbtypes.TicketCommitment, error) {
+	if len(amounts) != len(addrs) || len(voteLimits) != len(addrs) || len(revokeLimits) != len(addrs) {
+		return nil, fmt.Errorf("mismatched commitment array lengths")
+	}
+	commitments := make([]*dbtypes.TicketCommitment, 0, len(addrs))
+	for i := range addrs {
+		commitments = append(commitments, &dbtypes.TicketCommitment{
+			Address:        addrs[i],
+			Amount:         amounts[i],
+			VoteFeeLimit:   voteLimits[i],
+			RevokeFeeLimit: revokeLimits[i],
+		})
+	}
+	return commitments, nil
+}
+
 // InsertVotes takes a slice of *dbtypes.Tx, which must contain all the stake
 // transactions in a block, extracts the votes, and inserts the votes into the
 // database. The input MsgBlockPG contains each stake transaction's MsgTx in
@@ -733,6 +853,8 @@ func InsertVotes(db *sql.DB, dbTxns []*dbtypes.Tx, _ /*txDbIDs*/ []uint64, fTx *
 			return nil, nil, nil, nil, nil, err
 		}
 
+		choices := txhelpers.VoteChoices(voteVersion, voteBits, params)
+
 		voteReward := dcrutil.Amount(msgTx.TxIn[0].ValueIn).ToCoin()
 		stakeSubmissionAmount := dcrutil.Amount(msgTx.TxIn[1].ValueIn).ToCoin()
 		stakeSubmissionTxHash := msgTx.TxIn[1].PreviousOutPoint.Hash.String()
@@ -764,7 +886,8 @@ func InsertVotes(db *sql.DB, dbTxns []*dbtypes.Tx, _ /*txDbIDs*/ []uint64, fTx *
 			tx.BlockHeight, tx.TxID, tx.BlockHash, candidateBlockHash,
 			int32(voteVersion), int16(voteBits), validBlock.Validity,
 			stakeSubmissionTxHash, ticketTxDbID, stakeSubmissionAmount,
-			voteReward, tx.IsMainchainBlock, tx.BlockTime).Scan(&votesRowID)
+			voteReward, tx.IsMainchainBlock, tx.BlockTime,
+			dbtypes.NewVoteChoices(choices)).Scan(&votesRowID)
 		if err != nil {
 			if err == sql.ErrNoRows {
 				continue
@@ -779,12 +902,6 @@ func InsertVotes(db *sql.DB, dbTxns []*dbtypes.Tx, _ /*txDbIDs*/ []uint64, fTx *
 			continue // rest of loop deals with agendas table
 		}
 
-		_, _, _, choices, err := txhelpers.SSGenVoteChoices(msgTx, params)
-		if err != nil {
-			bail()
-			return nil, nil, nil, nil, nil, err
-		}
-
 		var rowID uint64
 		for _, val := range choices {
 			// As of here, storedAgendas should not be empty and
@@ -1174,15 +1291,306 @@ func RetrieveTicketStatusByHash(ctx context.Context, db *sql.DB, ticketHash stri
 	return
 }
 
+// RetrieveTicketCommitments retrieves the commitment outputs of the ticket
+// with the given hash, preferring the mainchain ticket row.
+func RetrieveTicketCommitments(ctx context.Context, db *sql.DB, ticketHash string) ([]*dbtypes.TicketCommitment, error) {
+	var addrs []string
+	var amounts, voteLimits, revokeLimits []int64
+	err := db.QueryRowContext(ctx, internal.SelectTicketCommitmentsByHash, ticketHash).
+		Scan(pq.Array(&addrs), pq.Array(&amounts), pq.Array(&voteLimits), pq.Array(&revokeLimits))
+	if err != nil {
+		return nil, err
+	}
+	return ticketCommitmentsFromArrays(addrs, amounts, voteLimits, revokeLimits)
+}
+
+// RetrieveTicketsByRewardAddress retrieves up to N mainchain tickets with a
+// commitment to the given reward address, newest first, skipping offset, or
+// after the ticket given by the cursor if it is not nil, in which case offset
+// is ignored. The returned cursor is for the next page, and is nil if there
+// are no more tickets.
+func RetrieveTicketsByRewardAddress(ctx context.Context, db *sql.DB, address string, N, offset int64,
+	after *dbtypes.PageCursor) ([]*dbtypes.RewardTicket, *dbtypes.PageCursor, error) {
+	var rows *sql.Rows
+	var err error
+	if after != nil {
+		rows, err = db.QueryContext(ctx, internal.SelectTicketsByRewardAddressAfter,
+			address, N, after.Height, after.TxIndex)
+	} else {
+		rows, err = db.QueryContext(ctx, internal.SelectTicketsByRewardAddress, address, N, offset)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	defer closeRows(rows)
+
+	var tickets []*dbtypes.RewardTicket
+	var blockIndex uint32
+	for rows.Next() {
+		var t dbtypes.RewardTicket
+		var poolStatus dbtypes.TicketPoolStatus
+		var spendType dbtypes.TicketSpendType
+		var spendHash sql.NullString
+		var spendHeight, spendSent sql.NullInt64
+		err = rows.Scan(&t.TxHash, &t.BlockHash, &t.BlockHeight, &t.Price,
+			&t.CommitmentAmount, &poolStatus, &spendType,
+			&spendHash, &spendHeight, &spendSent, &blockIndex)
+		if err != nil {
+			return nil, nil, err
+		}
+		t.PoolStatus = poolStatus.String()
+		t.SpendType = spendType.String()
+		if spendHash.Valid {
+			t.Spend = dbtypes.NewTicketSpend(spendType, spendHash.String,
+				spendHeight.Int64, spendSent.Int64, t.Price)
+		}
+		tickets = append(tickets, &t)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	var next *dbtypes.PageCursor
+	if int64(len(tickets)) == N {
+		next = &dbtypes.PageCursor{
+			Kind:    dbtypes.CursorRewardTickets,
+			Height:  tickets[len(tickets)-1].BlockHeight,
+			TxIndex: blockIndex,
+		}
+	}
+	return tickets, next, nil
+}
+
+// RetrieveRevocableTickets retrieves up to N missed or expired mainchain
+// tickets that have not been revoked, oldest first, skipping offset, or after
+// the ticket given by the cursor if it is not nil, and the totals for all such
+// tickets. If address is not empty, only the tickets with a commitment to the
+// address are included. The returned cursor is for the next page, and is nil
+// if there are no more tickets.
+func RetrieveRevocableTickets(ctx context.Context, db *sql.DB, address string, N, offset int64,
+	after *dbtypes.PageCursor) (*dbtypes.RevocableTickets, *dbtypes.PageCursor, error) {
+	revocable := &dbtypes.RevocableTickets{
+		Address: address,
+		Tickets: []*dbtypes.RevocableTicket{},
+	}
+	err := db.QueryRowContext(ctx, internal.SelectRevocableTicketsTotals, address).Scan(
+		&revocable.NumTickets, &revocable.TotalRecoverable)
+	if err != nil || revocable.NumTickets == 0 {
+		return revocable, nil, err
+	}
+
+	var rows *sql.Rows
+	if after != nil {
+		rows, err = db.QueryContext(ctx, internal.SelectRevocableTicketsAfter,
+			address, N, after.Height, after.TxIndex)
+	} else {
+		rows, err = db.QueryContext(ctx, internal.SelectRevocableTickets, address, N, offset)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	defer closeRows(rows)
+
+	var blockIndex uint32
+	for rows.Next() {
+		var t dbtypes.RevocableTicket
+		var poolStatus dbtypes.TicketPoolStatus
+		err = rows.Scan(&t.TxHash, &t.BlockHeight, &poolStatus, &t.Price,
+			&t.RecoverableAmount, &blockIndex)
+		if err != nil {
+			return nil, nil, err
+		}
+		t.PoolStatus = poolStatus.String()
+		revocable.Tickets = append(revocable.Tickets, &t)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	var next *dbtypes.PageCursor
+	if n := len(revocable.Tickets); int64(n) == N {
+		next = &dbtypes.PageCursor{
+			Kind:    dbtypes.CursorRevocable,
+			Height:  revocable.Tickets[n-1].BlockHeight,
+			TxIndex: blockIndex,
+		}
+	}
+	return revocable, next, nil
+}
+
+// RetrieveCoinbaseBlocksByAddress retrieves up to N mainchain blocks with
+// coinbase outputs paying to the given address, newest first, skipping offset,
+// or below the block given by the cursor if it is not nil. The returned cursor
+// is for the next page, and is nil if there are no more blocks.
+func RetrieveCoinbaseBlocksByAddress(ctx context.Context, db *sql.DB, address string,
+	N, offset int64, after *dbtypes.PageCursor) ([]*dbtypes.CoinbaseBlock, *dbtypes.PageCursor, error) {
+	var rows *sql.Rows
+	var err error
+	if after != nil {
+		rows, err = db.QueryContext(ctx, internal.SelectCoinbaseBlocksByAddressAfter,
+			address, N, after.Height)
+	} else {
+		rows, err = db.QueryContext(ctx, internal.SelectCoinbaseBlocksByAddress, address, N, offset)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	defer closeRows(rows)
+
+	var blocks []*dbtypes.CoinbaseBlock
+	for rows.Next() {
+		var b dbtypes.CoinbaseBlock
+		if err = rows.Scan(&b.Height, &b.Hash, &b.Time, &b.Value); err != nil {
+			return nil, nil, err
+		}
+		blocks = append(blocks, &b)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	var next *dbtypes.PageCursor
+	if int64(len(blocks)) == N {
+		next = &dbtypes.PageCursor{
+			Kind:   dbtypes.CursorCoinbase,
+			Height: blocks[len(blocks)-1].Height,
+		}
+	}
+	return blocks, next, nil
+}
+
+// RetrieveMinerShares counts the mainchain blocks from fromHeight to the best
+// block by payout address.
+func RetrieveMinerShares(ctx context.Context, db *sql.DB, fromHeight int64) ([]*dbtypes.MinerShare, int64, error) {
+	rows, err := db.QueryContext(ctx, internal.SelectCoinbasePayoutAddressCounts, fromHeight)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer closeRows(rows)
+
+	var miners []*dbtypes.MinerShare
+	var numBlocks int64
+	for rows.Next() {
+		var address sql.NullString
+		m := new(dbtypes.MinerShare)
+		if err = rows.Scan(&address, &m.NumBlocks, &m.LastHeight); err != nil {
+			return nil, 0, err
+		}
+		m.Address = address.String
+		numBlocks += m.NumBlocks
+		miners = append(miners, m)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	for _, m := range miners {
+		m.Share = float64(m.NumBlocks) / float64(numBlocks)
+	}
+	return miners, numBlocks, nil
+}
+
+// RetrieveTicketVoteHeights retrieves the purchase and vote heights of the
+// ticket with the given hash, preferring the mainchain ticket row. If the
+// ticket has not voted, voted is false and voteHeight is zero.
+func RetrieveTicketVoteHeights(ctx context.Context, db *sql.DB, ticketHash string) (purchaseHeight,
+	voteHeight int64, voted bool, err error) {
+	var spendType dbtypes.TicketSpendType
+	var spendHeight sql.NullInt64
+	err = db.QueryRowContext(ctx, internal.SelectTicketVoteHeightsByHash, ticketHash).
+		Scan(&purchaseHeight, &spendType, &spendHeight)
+	if err != nil || spendType != dbtypes.TicketVoted {
+		return
+	}
+	return purchaseHeight, spendHeight.Int64, true, nil
+}
+
+// RetrieveVotedTicketsByRewardAddress retrieves the purchase and vote heights
+// of the voted mainchain tickets with a commitment to the given address,
+// newest vote first.
+func RetrieveVotedTicketsByRewardAddress(ctx context.Context, db *sql.DB, address string) ([]*dbtypes.TicketLuck, error) {
+	rows, err := db.QueryContext(ctx, internal.SelectVotedTicketsByRewardAddress, address)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	var tickets []*dbtypes.TicketLuck
+	for rows.Next() {
+		var t dbtypes.TicketLuck
+		if err = rows.Scan(&t.TxHash, &t.PurchaseHeight, &t.VoteHeight); err != nil {
+			return nil, err
+		}
+		tickets = append(tickets, &t)
+	}
+	return tickets, rows.Err()
+}
+
+// RetrieveTicketPoolStatuses retrieves the purchase heights and pool statuses
+// of the mainchain tickets with the given hashes. Tickets that are not found
+// are omitted from the returned maps.
+func RetrieveTicketPoolStatuses(ctx context.Context, db *sql.DB, txids []string) (map[string]int64, map[string]dbtypes.TicketPoolStatus, error) {
+	rows, err := db.QueryContext(ctx, internal.SelectTicketPoolStatusesByHashes, pq.Array(txids))
+	if err != nil {
+		return nil, nil, err
+	}
+	defer closeRows(rows)
+
+	heights := make(map[string]int64, len(txids))
+	statuses := make(map[string]dbtypes.TicketPoolStatus, len(txids))
+	for rows.Next() {
+		var txid string
+		var height int64
+		var status dbtypes.TicketPoolStatus
+		if err = rows.Scan(&txid, &height, &status); err != nil {
+			return nil, nil, err
+		}
+		heights[txid] = height
+		statuses[txid] = status
+	}
+	return heights, statuses, rows.Err()
+}
+
+// RetrieveStakingPosition summarizes the stake committed to the given reward
+// address as of the best block height. Tickets purchased after
+// immatureTicketHeight are immature, and votes cast after pendingVoteHeight
+// have outputs that are not yet spendable.
+func RetrieveStakingPosition(ctx context.Context, db *sql.DB, address string,
+	immatureTicketHeight, pendingVoteHeight int64) (*dbtypes.StakingPosition, error) {
+	pos := &dbtypes.StakingPosition{Address: address}
+	var pendingCommitments int64
+	err := db.QueryRowContext(ctx, internal.SelectTicketStakeByRewardAddress,
+		address, immatureTicketHeight, pendingVoteHeight).Scan(
+		&pos.NumImmature, &pos.ImmatureAmount, &pos.NumLive, &pos.LiveAmount,
+		&pos.NumUnrevoked, &pos.UnrevokedAmount, &pos.NumPendingVotes,
+		&pendingCommitments)
+	if err != nil {
+		return nil, err
+	}
+	pos.LockedAmount = pos.ImmatureAmount + pos.LiveAmount + pos.UnrevokedAmount
+
+	if pos.NumPendingVotes > 0 {
+		err = db.QueryRowContext(ctx, internal.SelectPendingVotePayoutsByAddress,
+			address, pendingVoteHeight).Scan(&pos.PendingPayout)
+		if err != nil {
+			return nil, err
+		}
+		pos.PendingReward = pos.PendingPayout - pendingCommitments
+	}
+	return pos, nil
+}
+
 // RetrieveTicketInfoByHash retrieves the ticket spend and pool statuses as well
-// as the purchase and spending block info and spending txid.
+// as the purchase and spending block info and the spending transaction.
 func RetrieveTicketInfoByHash(ctx context.Context, db *sql.DB, ticketHash string) (spendStatus dbtypes.TicketSpendType,
-	poolStatus dbtypes.TicketPoolStatus, purchaseBlock, lotteryBlock *apitypes.TinyBlock, spendTxid string, err error) {
+	poolStatus dbtypes.TicketPoolStatus, purchaseBlock, lotteryBlock *apitypes.TinyBlock, spend *dbtypes.TicketSpend, err error) {
 	var dbid sql.NullInt64
-	var purchaseHash, spendHash string
+	var purchaseHash, spendHash, spendTxid string
 	var purchaseHeight, spendHeight uint32
+	var price float64
+	var sent int64
 	err = db.QueryRowContext(ctx, internal.SelectTicketInfoByHash, ticketHash).
-		Scan(&purchaseHash, &purchaseHeight, &spendStatus, &poolStatus, &dbid)
+		Scan(&purchaseHash, &purchaseHeight, &spendStatus, &poolStatus, &dbid, &price)
 	if err != nil {
 		return
 	}
@@ -1202,11 +1610,12 @@ func RetrieveTicketInfoByHash(ctx context.Context, db *sql.DB, ticketHash string
 	}
 
 	err = db.QueryRowContext(ctx, internal.SelectTxnByDbID, dbid.Int64).
-		Scan(&spendHash, &spendHeight, &spendTxid)
+		Scan(&spendHash, &spendHeight, &spendTxid, &sent)
 
 	if err != nil {
 		return
 	}
+	spend = dbtypes.NewTicketSpend(spendStatus, spendTxid, int64(spendHeight), sent, price)
 
 	if spendStatus == dbtypes.TicketVoted {
 		lotteryBlock = &apitypes.TinyBlock{
@@ -1326,6 +1735,46 @@ func retrieveTicketByPrice(ctx context.Context, db *sql.DB, maturityBlock int64)
 	return tickets, nil
 }
 
+// retrieveTicketsByAge fetches the count of live tickets in the current
+// ticketpool in numBuckets buckets of equal fractions of the ticket expiry, by
+// the number of blocks since purchase. The maturity block is needed to identify
+// live tickets. Age is the fewest blocks since purchase of a ticket in each
+// bucket, and Live is the count of tickets in the bucket.
+func retrieveTicketsByAge(ctx context.Context, db *sql.DB, maturityBlock int64,
+	params *chaincfg.Params, numBuckets int) (*dbtypes.PoolTicketsData, error) {
+	bucketSize := (int64(params.TicketExpiry) + int64(numBuckets) - 1) / int64(numBuckets)
+	rows, err := db.QueryContext(ctx, internal.SelectLiveTicketsByAge, maturityBlock,
+		bucketSize, numBuckets)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	tickets := &dbtypes.PoolTicketsData{
+		Age:  make([]uint64, numBuckets),
+		Live: make([]uint64, numBuckets),
+	}
+	for i := range tickets.Age {
+		tickets.Age[i] = uint64(params.TicketMaturity) + 1 + uint64(i)*uint64(bucketSize)
+	}
+	for rows.Next() {
+		var bucket int
+		var count uint64
+		if err = rows.Scan(&bucket, &count); err != nil {
+			return nil, fmt.Errorf("retrieveTicketsByAge %v", err)
+		}
+		if bucket < 0 || bucket >= numBuckets {
+			continue
+		}
+		tickets.Live[bucket] = count
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return tickets, nil
+}
+
 // retrieveTicketsGroupedByType fetches the count of tickets in the current
 // ticketpool grouped by ticket type (inferred by their output counts). The
 // grouping used here i.e. solo, pooled and tixsplit is just a guessing based on
@@ -1509,21 +1958,135 @@ func setSpendingForTickets(dbtx *sql.Tx, ticketDbIDs, spendDbIDs []uint64,
 
 // --- addresses table ---
 
+// addressCountChanges accumulates the changes in the numbers of valid
+// mainchain funding and spending rows of the addresses table, by address, to be
+// applied to the address_counts table with updateAddressCounts.
+type addressCountChanges map[string]*[2]int64
+
+// add records a change in the number of funding or spending rows of an
+// address.
+func (changes addressCountChanges) add(address string, isFunding bool, delta int64) {
+	if delta == 0 {
+		return
+	}
+	counts := changes[address]
+	if counts == nil {
+		counts = new([2]int64)
+		changes[address] = counts
+	}
+	if isFunding {
+		counts[0] += delta
+	} else {
+		counts[1] += delta
+	}
+}
+
+// storedAddressRows collects the inserted addresses table rows and their row
+// IDs. Nothing is collected by a nil *storedAddressRows.
+type storedAddressRows struct {
+	rows []*dbtypes.AddressRow
+	ids  []uint64
+}
+
+// add records an inserted addresses table row.
+func (stored *storedAddressRows) add(id uint64, row *dbtypes.AddressRow) {
+	if stored == nil {
+		return
+	}
+	stored.rows = append(stored.rows, row)
+	stored.ids = append(stored.ids, id)
+}
+
+// updateAddressCounts applies the changes to the address_counts table. The
+// addresses are updated in sorted order so that concurrent database
+// transactions updating the same addresses do not deadlock.
+func updateAddressCounts(db SqlExecutor, changes addressCountChanges) error {
+	addresses := make([]string, 0, len(changes))
+	for address, counts := range changes {
+		if counts[0] != 0 || counts[1] != 0 {
+			addresses = append(addresses, address)
+		}
+	}
+	if len(addresses) == 0 {
+		return nil
+	}
+	sort.Strings(addresses)
+
+	funding := make([]int64, len(addresses))
+	spending := make([]int64, len(addresses))
+	for i, address := range addresses {
+		funding[i], spending[i] = changes[address][0], changes[address][1]
+	}
+	_, err := db.Exec(internal.UpdateAddressCounts, pq.StringArray(addresses),
+		pq.Int64Array(funding), pq.Int64Array(spending))
+	return err
+}
+
+// rebuildAddressCounts recounts the valid mainchain funding and spending rows
+// of every address in the addresses table.
+func rebuildAddressCounts(db SqlExecutor) (int64, error) {
+	if _, err := db.Exec(internal.DeleteAddressCounts); err != nil {
+		return 0, fmt.Errorf("failed to delete address counts: %v", err)
+	}
+	return sqlExec(db, internal.InsertAddressCountsFromAddresses,
+		"failed to insert address counts: ")
+}
+
+// RetrieveAddressCounts retrieves the numbers of valid mainchain funding and
+// spending rows of the address from the address_counts table. An address with
+// no transactions has no counts, and zero counts are returned.
+func RetrieveAddressCounts(ctx context.Context, db *sql.DB, address string) (numFunding, numSpending int64, err error) {
+	err = db.QueryRowContext(ctx, internal.SelectAddressCounts, address).
+		Scan(&numFunding, &numSpending)
+	if err == sql.ErrNoRows {
+		err = nil
+	}
+	return
+}
+
+// queryInsertAddressRow is like queryInsertID for the addresses table insert
+// statements, which also return the change in the number of valid mainchain
+// rows of the address.
+func queryInsertAddressRow(queryRow func() *sql.Row) (id uint64, numValid int64, err error) {
+	err = queryRow().Scan(&id, &numValid)
+	if err == sql.ErrNoRows {
+		err = queryRow().Scan(&id, &numValid)
+	}
+	return
+}
+
 // InsertAddressRow inserts an AddressRow (input or output), returning the row
 // ID in the addresses table of the inserted data.
 func InsertAddressRow(db *sql.DB, dbA *dbtypes.AddressRow, dupCheck, updateExistingRecords bool) (uint64, error) {
-	sqlStmt := internal.MakeAddressRowInsertStatement(dupCheck, updateExistingRecords)
-	var id uint64
-	err := db.QueryRow(sqlStmt, dbA.Address, dbA.MatchingTxHash, dbA.TxHash,
-		dbA.TxVinVoutIndex, dbA.VinVoutDbID, dbA.Value, dbA.TxBlockTime,
-		dbA.IsFunding, dbA.ValidMainChain, dbA.TxType).Scan(&id)
-	return id, err
+	dbTx, err := db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("unable to begin database transaction: %v", err)
+	}
+	ids, err := InsertAddressRowsDbTx(dbTx, []*dbtypes.AddressRow{dbA},
+		dupCheck, updateExistingRecords)
+	if err != nil {
+		_ = dbTx.Rollback()
+		return 0, err
+	}
+	return ids[0], dbTx.Commit()
 }
 
 // InsertAddressRowsDbTx is like InsertAddressRows, except that it takes a
 // sql.Tx. The caller is required to Commit or Rollback the transaction
 // depending on the returned error value.
 func InsertAddressRowsDbTx(dbTx *sql.Tx, dbAs []*dbtypes.AddressRow, dupCheck, updateExistingRecords bool) ([]uint64, error) {
+	changes := make(addressCountChanges)
+	ids, err := insertAddressRowsDbTx(dbTx, dbAs, dupCheck, updateExistingRecords, changes)
+	if err != nil {
+		return nil, err
+	}
+	return ids, updateAddressCounts(dbTx, changes)
+}
+
+// insertAddressRowsDbTx is like InsertAddressRowsDbTx, except that the changes
+// in the address counts are recorded in changes rather than applied.
+func insertAddressRowsDbTx(dbTx *sql.Tx, dbAs []*dbtypes.AddressRow, dupCheck, updateExistingRecords bool,
+	changes addressCountChanges) ([]uint64, error) {
 	// Prepare the addresses row insert statement.
 	stmt, err := dbTx.Prepare(internal.MakeAddressRowInsertStatement(dupCheck, updateExistingRecords))
 	if err != nil {
@@ -1533,19 +2096,17 @@ func InsertAddressRowsDbTx(dbTx *sql.Tx, dbAs []*dbtypes.AddressRow, dupCheck, u
 	// Insert each addresses table row, storing the inserted row IDs.
 	ids := make([]uint64, 0, len(dbAs))
 	for _, dbA := range dbAs {
-		var id uint64
-		err := stmt.QueryRow(dbA.Address, dbA.MatchingTxHash, dbA.TxHash,
-			dbA.TxVinVoutIndex, dbA.VinVoutDbID, dbA.Value, dbA.TxBlockTime,
-			dbA.IsFunding, dbA.ValidMainChain, dbA.TxType).Scan(&id)
+		id, numValid, err := queryInsertAddressRow(func() *sql.Row {
+			return stmt.QueryRow(dbA.Address, dbA.MatchingTxHash, dbA.TxHash,
+				dbA.TxVinVoutIndex, dbA.VinVoutDbID, dbA.Value, dbA.TxBlockTime,
+				dbA.IsFunding, dbA.ValidMainChain, dbA.TxType)
+		})
 		if err != nil {
-			if err == sql.ErrNoRows {
-				log.Errorf("failed to insert/update an AddressRow: %v", *dbA)
-				continue
-			}
 			_ = stmt.Close() // try, but we want the QueryRow error back
 			return nil, err
 		}
 		ids = append(ids, id)
+		changes.add(dbA.Address, dbA.IsFunding, numValid)
 	}
 
 	// Close prepared statement. Ignore errors as we'll Commit regardless.
@@ -1572,8 +2133,8 @@ func InsertAddressRows(db *sql.DB, dbAs []*dbtypes.AddressRow, dupCheck, updateE
 	return ids, dbtx.Commit()
 }
 
-func RetrieveAddressUnspent(ctx context.Context, db *sql.DB, address string) (count, totalAmount int64, err error) {
-	err = db.QueryRowContext(ctx, internal.SelectAddressUnspentCountANDValue, address).
+func RetrieveAddressUnspent(ctx context.Context, db *sql.DB, sa *spentArchive, address string) (count, totalAmount int64, err error) {
+	err = db.QueryRowContext(ctx, sa.unspentQuery(internal.SelectAddressUnspentCountANDValue), address).
 		Scan(&count, &totalAmount)
 	return
 }
@@ -1591,13 +2152,74 @@ func retrieveAddressTxsCount(ctx context.Context, db *sql.DB, address, interval
 	return
 }
 
-// RetrieveAddressBalance gets the numbers of spent and unspent outpoints
-// for the given address, the total amounts spent and unspent, the number of
-// distinct spending transactions, and the fraction spent to and received from
-// stake-related transactions.
-func RetrieveAddressBalance(ctx context.Context, db *sql.DB, address string) (balance *dbtypes.AddressBalance, err error) {
-	// Never return nil *AddressBalance.
-	balance = &dbtypes.AddressBalance{Address: address}
+// RetrieveHistoricalAddressBalance gets the confirmed balance of the address
+// as of the mainchain block at the given height, using the heights of the
+// funding and spending transactions of the address.
+func RetrieveHistoricalAddressBalance(ctx context.Context, db *sql.DB, address string,
+	height int64) (*dbtypes.HistoricalAddressBalance, error) {
+	bal := &dbtypes.HistoricalAddressBalance{
+		Address: address,
+		Height:  height,
+	}
+	err := db.QueryRowContext(ctx, internal.SelectBlockHashTimeByHeight, height).
+		Scan(&bal.BlockHash, &bal.Time)
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.QueryRowContext(ctx, internal.SelectAddressReceivedSentAtHeight,
+		address, height).Scan(&bal.Received, &bal.Sent)
+	if err != nil {
+		return nil, err
+	}
+	bal.Balance = bal.Received - bal.Sent
+	return bal, nil
+}
+
+// RetrieveAddressTxnSummary gets the number of valid mainchain transactions
+// involving the address, and the UNIX times of the first and last of them.
+func RetrieveAddressTxnSummary(ctx context.Context, db *sql.DB, address string) (numTxns, firstSeen, lastActivity int64, err error) {
+	err = db.QueryRowContext(ctx, internal.SelectAddressSummary, address).
+		Scan(&numTxns, &firstSeen, &lastActivity)
+	return
+}
+
+// RetrieveBlockHeightByTime gets the height of the last mainchain block with a
+// timestamp at or before the given time.
+func RetrieveBlockHeightByTime(ctx context.Context, db *sql.DB, t time.Time) (height int64, err error) {
+	err = db.QueryRowContext(ctx, internal.SelectBlockHeightByTime, t).Scan(&height)
+	return
+}
+
+// RetrieveBlockHeightAfterTime gets the height of the first mainchain block
+// with a timestamp at or after the given time.
+func RetrieveBlockHeightAfterTime(ctx context.Context, db *sql.DB, t time.Time) (height int64, err error) {
+	err = db.QueryRowContext(ctx, internal.SelectBlockHeightAfterTime, t).Scan(&height)
+	return
+}
+
+// RetrieveAddressBalance gets the numbers of spent and unspent outpoints
+// for the given address, the total amounts spent and unspent, the number of
+// distinct spending transactions, the fraction spent to and received from
+// stake-related transactions, and the times of the first and last transactions
+// involving the address.
+func RetrieveAddressBalance(ctx context.Context, db *sql.DB, address string) (*dbtypes.AddressBalance, error) {
+	return retrieveAddressBalance(ctx, db, address,
+		internal.SelectAddressSpentUnspentCountAndValue, address)
+}
+
+// RetrieveAddressBalanceAtHeight is like RetrieveAddressBalance, but only
+// includes the transactions mined at or below the given mainchain height, and
+// the spends by those transactions. The balance is thus consistent with the
+// block at that height even if newer blocks are stored during the query.
+func RetrieveAddressBalanceAtHeight(ctx context.Context, db *sql.DB, address string, height int64) (*dbtypes.AddressBalance, error) {
+	return retrieveAddressBalance(ctx, db, address,
+		internal.SelectAddressSpentUnspentCountAndValueAtHeight, address, height)
+}
+
+func retrieveAddressBalance(ctx context.Context, db *sql.DB, address, stmt string, args ...interface{}) (balance *dbtypes.AddressBalance, err error) {
+	// Never return nil *AddressBalance.
+	balance = &dbtypes.AddressBalance{Address: address}
 
 	// The sql.Tx does not have a timeout, as the individual queries will.
 	var dbtx *sql.Tx
@@ -1612,7 +2234,7 @@ func RetrieveAddressBalance(ctx context.Context, db *sql.DB, address string) (ba
 
 	// Query for spent and unspent totals.
 	var rows *sql.Rows
-	rows, err = db.QueryContext(ctx, internal.SelectAddressSpentUnspentCountAndValue, address)
+	rows, err = db.QueryContext(ctx, stmt, args...)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			_ = dbtx.Commit()
@@ -1629,10 +2251,14 @@ func RetrieveAddressBalance(ctx context.Context, db *sql.DB, address string) (ba
 	for rows.Next() {
 		var count, totalValue int64
 		var noMatchingTx, isFunding, isRegular bool
-		err = rows.Scan(&isRegular, &count, &totalValue, &isFunding, &noMatchingTx)
+		var firstTime, lastTime time.Time
+		err = rows.Scan(&isRegular, &count, &totalValue, &isFunding, &noMatchingTx,
+			&firstTime, &lastTime)
 		if err != nil {
 			return
 		}
+		balance.AddActivity(firstTime)
+		balance.AddActivity(lastTime)
 
 		// Unspent == funding with no matching transaction
 		if isFunding && noMatchingTx {
@@ -1716,41 +2342,59 @@ func countMerged(ctx context.Context, db *sql.DB, address, query string) (count
 	return
 }
 
-// RetrieveAddressUTXOs gets the unspent transaction outputs (UTXOs) paying to
-// the specified address as a []*apitypes.AddressTxnOutput. The input current
-// block height is used to compute confirmations of the located transactions.
-func RetrieveAddressUTXOs(ctx context.Context, db *sql.DB, address string, currentBlockHeight int64) ([]*apitypes.AddressTxnOutput, error) {
-	stmt, err := db.Prepare(internal.SelectAddressUnspentWithTxn)
-	if err != nil {
-		log.Error(err)
-		return nil, err
+// RetrieveAddressUTXOs gets up to N of the unspent transaction outputs (UTXOs)
+// paying to the specified address, skipping offset, in the order given by
+// sortBy, which is one of the dbtypes.UTXOSort* orders. The input current
+// block height is used to compute the confirmations and the remaining blocks
+// to maturity of the outputs. Only the hot addresses and vouts tables are read
+// when spent rows are archived.
+func RetrieveAddressUTXOs(ctx context.Context, db *sql.DB, sa *spentArchive, address string, N, offset int64,
+	sortBy string, currentBlockHeight int64, params *chaincfg.Params) ([]*apitypes.AddressUTXO, error) {
+	var query string
+	switch sortBy {
+	case dbtypes.UTXOSortNewest:
+		query = internal.SelectAddressUTXOsNewest
+	case dbtypes.UTXOSortOldest:
+		query = internal.SelectAddressUTXOsOldest
+	case dbtypes.UTXOSortLargest:
+		query = internal.SelectAddressUTXOsLargest
+	case dbtypes.UTXOSortSmallest:
+		query = internal.SelectAddressUTXOsSmallest
+	default:
+		return nil, fmt.Errorf("unknown UTXO sort order %q", sortBy)
 	}
-	rows, err := stmt.QueryContext(ctx, address)
-	_ = stmt.Close()
+
+	rows, err := db.QueryContext(ctx, sa.unspentQuery(query), address, N, offset)
 	if err != nil {
-		log.Error(err)
 		return nil, err
 	}
 	defer closeRows(rows)
 
-	var outputs []*apitypes.AddressTxnOutput
+	var outputs []*apitypes.AddressUTXO
 	for rows.Next() {
 		pkScript := []byte{}
-		var blockHeight, atoms int64
 		var blockTime dbtypes.TimeDef
-		txnOutput := new(apitypes.AddressTxnOutput)
-		if err = rows.Scan(&txnOutput.Address, &txnOutput.TxnID,
-			&atoms, &blockHeight, &blockTime, &txnOutput.Vout, &pkScript); err != nil {
-			log.Error(err)
+		var txType int16
+		var blockIndex uint32
+		utxo := new(apitypes.AddressUTXO)
+		if err = rows.Scan(&utxo.TxHash, &utxo.Vout, &utxo.Atoms, &utxo.Height,
+			&blockTime, &utxo.Tree, &txType, &blockIndex, &pkScript); err != nil {
 			return nil, err
 		}
-		txnOutput.BlockTime = blockTime.UNIX()
-		txnOutput.ScriptPubKey = hex.EncodeToString(pkScript)
-		txnOutput.Amount = dcrutil.Amount(atoms).ToCoin()
-		txnOutput.Satoshis = atoms
-		txnOutput.Height = blockHeight
-		txnOutput.Confirmations = currentBlockHeight - blockHeight + 1
-		outputs = append(outputs, txnOutput)
+		utxo.BlockTime = blockTime.UNIX()
+		utxo.TxType = txhelpers.TxTypeToString(int(txType))
+		utxo.ScriptPubKey = hex.EncodeToString(pkScript)
+		utxo.Amount = dcrutil.Amount(utxo.Atoms).ToCoin()
+		utxo.Confirmations = currentBlockHeight - utxo.Height + 1
+		utxo.Coinbase = utxo.Tree == wire.TxTreeRegular && blockIndex == 0
+		var spendable bool
+		utxo.Maturity, spendable = utxoMaturity(stake.TxType(txType), utxo.Tree,
+			blockIndex, utxo.Vout, params)
+		if utxo.Confirmations < utxo.Maturity {
+			utxo.BlocksToMaturity = utxo.Maturity - utxo.Confirmations
+		}
+		utxo.Spendable = spendable && utxo.BlocksToMaturity == 0
+		outputs = append(outputs, utxo)
 	}
 	if err = rows.Err(); err != nil {
 		return nil, err
@@ -1759,11 +2403,33 @@ func RetrieveAddressUTXOs(ctx context.Context, db *sql.DB, address string, curre
 	return outputs, nil
 }
 
+// utxoMaturity returns the number of confirmations required before an output
+// of a transaction with the given type, tree and block index may be spent by a
+// regular transaction. The returned bool is false for the stake submission
+// output of a ticket, which may only be spent by a vote or revocation.
+func utxoMaturity(txType stake.TxType, tree int8, blockIndex, vout uint32,
+	params *chaincfg.Params) (int64, bool) {
+	switch txType {
+	case stake.TxTypeRegular:
+		if tree == wire.TxTreeRegular && blockIndex == 0 {
+			return int64(params.CoinbaseMaturity), true
+		}
+	case stake.TxTypeSSGen, stake.TxTypeSSRtx:
+		return int64(params.CoinbaseMaturity), true
+	case stake.TxTypeSStx:
+		if vout == 0 {
+			return int64(params.TicketMaturity), false
+		}
+		return int64(params.SStxChangeMaturity), true
+	}
+	return 0, true
+}
+
 // RetrieveAddressDbUTXOs gets the unspent transaction outputs (UTXOs) paying to
 // the specified address as a []*dbtypes.AddressTxnOutput. The input current
 // block height is used to compute confirmations of the located transactions.
-func RetrieveAddressDbUTXOs(ctx context.Context, db *sql.DB, address string) ([]*dbtypes.AddressTxnOutput, error) {
-	stmt, err := db.Prepare(internal.SelectAddressUnspentWithTxn)
+func RetrieveAddressDbUTXOs(ctx context.Context, db *sql.DB, sa *spentArchive, address string) ([]*dbtypes.AddressTxnOutput, error) {
+	stmt, err := db.Prepare(sa.unspentQuery(internal.SelectAddressUnspentWithTxn))
 	if err != nil {
 		log.Error(err)
 		return nil, err
@@ -1870,6 +2536,21 @@ func RetrieveAllMainchainAddressTxns(ctx context.Context, db *sql.DB, address st
 	return scanAddressQueryRows(rows, creditDebitQuery)
 }
 
+// RetrieveAllMainchainAddressTxnsAtHeight is like
+// RetrieveAllMainchainAddressTxns, but only retrieves the rows for the
+// transactions mined at or below the given mainchain height, with the matching
+// transaction cleared if it was mined above that height.
+func RetrieveAllMainchainAddressTxnsAtHeight(ctx context.Context, db *sql.DB, address string, height int64) ([]*dbtypes.AddressRow, error) {
+	rows, err := db.QueryContext(ctx, internal.SelectAddressAllMainchainByAddressAtHeight,
+		address, height)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	return scanAddressQueryRows(rows, creditDebitQuery)
+}
+
 // RetrieveAllAddressMergedTxns retrieves all merged rows of the address table
 // pertaining to the given address. Specify only valid_mainchain=true rows via
 // the onlyValidMainchain argument.
@@ -1892,6 +2573,34 @@ func RetrieveAddressTxns(ctx context.Context, db *sql.DB, address string, N, off
 		internal.SelectAddressLimitNByAddress, creditDebitQuery)
 }
 
+// RetrieveAddressTxnsByTimeRange retrieves at most N of the address' valid
+// mainchain addresses table rows with a block time in the range [from, to],
+// newest first, skipping the first offset rows, or after the row given by the
+// cursor if it is not nil, in which case offset is ignored. If the range
+// starts after the archived rows, only the hot addresses table is read.
+func RetrieveAddressTxnsByTimeRange(ctx context.Context, db *sql.DB, sa *spentArchive, address string,
+	from, to time.Time, N, offset int64, after *dbtypes.PageCursor) ([]*dbtypes.AddressRow, error) {
+	var rows *sql.Rows
+	var err error
+	if after != nil {
+		query := sa.sinceQuery(internal.SelectAddressLimitNByAddressTimeRangeAfter, from)
+		rows, err = db.QueryContext(ctx, query, address, N,
+			dbtypes.NewTimeDef(time.Unix(after.BlockTime, 0)), after.TxHash,
+			after.IsFunding, after.IOIndex,
+			dbtypes.NewTimeDef(from), dbtypes.NewTimeDef(to))
+	} else {
+		query := sa.sinceQuery(internal.SelectAddressLimitNByAddressTimeRange, from)
+		rows, err = db.QueryContext(ctx, query,
+			address, N, offset, dbtypes.NewTimeDef(from), dbtypes.NewTimeDef(to))
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	return scanAddressQueryRows(rows, creditDebitQuery)
+}
+
 func RetrieveAddressDebitTxns(ctx context.Context, db *sql.DB, address string, N, offset int64) ([]*dbtypes.AddressRow, error) {
 	return retrieveAddressTxns(ctx, db, address, N, offset,
 		internal.SelectAddressDebitsLimitNByAddress, creditQuery)
@@ -2205,12 +2914,23 @@ func InsertVin(db *sql.DB, dbVin dbtypes.VinTxProperty, checked bool, updateOnCo
 	if len(updateOnConflict) > 0 {
 		doUpsert = updateOnConflict[0]
 	}
-	err = db.QueryRow(internal.MakeVinInsertStatement(checked, doUpsert),
-		dbVin.TxID, dbVin.TxIndex, dbVin.TxTree,
-		dbVin.PrevTxHash, dbVin.PrevTxIndex, dbVin.PrevTxTree,
-		dbVin.ValueIn, dbVin.IsValid, dbVin.IsMainchain, dbVin.Time,
-		dbVin.TxType).Scan(&id)
-	return
+	return queryInsertID(func() rowScanner {
+		return db.QueryRow(internal.MakeVinInsertStatement(checked, doUpsert),
+			dbVin.TxID, dbVin.TxIndex, dbVin.TxTree,
+			dbVin.PrevTxHash, dbVin.PrevTxIndex, dbVin.PrevTxTree,
+			dbVin.ValueIn, dbVin.IsValid, dbVin.IsMainchain, dbVin.Time,
+			dbVin.TxType, dbVin.Sequence, dbVin.SigType, vinSigScript(&dbVin))
+	})
+}
+
+// vinSigScript returns the signature script of a coinbase or stakebase input,
+// which spends no previous output, to be stored in the vins table. The
+// signature scripts of other inputs are not stored.
+func vinSigScript(vin *dbtypes.VinTxProperty) []byte {
+	if !txhelpers.IsZeroHashStr(vin.PrevTxHash) {
+		return nil
+	}
+	return vin.ScriptHex
 }
 
 // InsertVinsStmt is like InsertVins, except that it takes a sql.Stmt. The
@@ -2219,10 +2939,12 @@ func InsertVinsStmt(stmt *sql.Stmt, dbVins dbtypes.VinTxPropertyARRAY, checked b
 	// TODO/Question: Should we skip inserting coinbase txns, which have same PrevTxHash?
 	ids := make([]uint64, 0, len(dbVins))
 	for _, vin := range dbVins {
-		var id uint64
-		err := stmt.QueryRow(vin.TxID, vin.TxIndex, vin.TxTree,
-			vin.PrevTxHash, vin.PrevTxIndex, vin.PrevTxTree,
-			vin.ValueIn, vin.IsValid, vin.IsMainchain, vin.Time, vin.TxType).Scan(&id)
+		id, err := queryInsertID(func() rowScanner {
+			return stmt.QueryRow(vin.TxID, vin.TxIndex, vin.TxTree,
+				vin.PrevTxHash, vin.PrevTxIndex, vin.PrevTxTree,
+				vin.ValueIn, vin.IsValid, vin.IsMainchain, vin.Time, vin.TxType,
+				vin.Sequence, vin.SigType, vinSigScript(&vin))
+		})
 		if err != nil {
 			return ids, fmt.Errorf("InsertVins INSERT exec failed: %v", err)
 		}
@@ -2289,14 +3011,14 @@ func InsertVout(db *sql.DB, dbVout *dbtypes.Vout, checked bool, updateOnConflict
 		doUpsert = updateOnConflict[0]
 	}
 	insertStatement := internal.MakeVoutInsertStatement(checked, doUpsert)
-	var id uint64
-	err := db.QueryRow(insertStatement,
-		dbVout.TxHash, dbVout.TxIndex, dbVout.TxTree,
-		dbVout.Value, int32(dbVout.Version),
-		dbVout.ScriptPubKey, int32(dbVout.ScriptPubKeyData.ReqSigs),
-		dbVout.ScriptPubKeyData.Type,
-		pq.Array(dbVout.ScriptPubKeyData.Addresses)).Scan(&id)
-	return id, err
+	return queryInsertID(func() rowScanner {
+		return db.QueryRow(insertStatement,
+			dbVout.TxHash, dbVout.TxIndex, dbVout.TxTree,
+			dbVout.Value, int32(dbVout.Version),
+			dbVout.ScriptPubKey, int32(dbVout.ScriptPubKeyData.ReqSigs),
+			dbVout.ScriptPubKeyData.Type,
+			pq.Array(dbVout.ScriptPubKeyData.Addresses), dbVout.Mixed)
+	})
 }
 
 // InsertVoutsStmt is like InsertVouts, except that it takes a sql.Stmt. The
@@ -2305,16 +3027,14 @@ func InsertVoutsStmt(stmt *sql.Stmt, dbVouts []*dbtypes.Vout, checked bool, doUp
 	addressRows := make([]dbtypes.AddressRow, 0, len(dbVouts)) // may grow with multisig
 	ids := make([]uint64, 0, len(dbVouts))
 	for _, vout := range dbVouts {
-		var id uint64
-		err := stmt.QueryRow(
-			vout.TxHash, vout.TxIndex, vout.TxTree, vout.Value, int32(vout.Version),
-			vout.ScriptPubKey, int32(vout.ScriptPubKeyData.ReqSigs),
-			vout.ScriptPubKeyData.Type,
-			pq.Array(vout.ScriptPubKeyData.Addresses), vout.Mixed).Scan(&id)
+		id, err := queryInsertID(func() rowScanner {
+			return stmt.QueryRow(
+				vout.TxHash, vout.TxIndex, vout.TxTree, vout.Value, int32(vout.Version),
+				vout.ScriptPubKey, int32(vout.ScriptPubKeyData.ReqSigs),
+				vout.ScriptPubKeyData.Type,
+				pq.Array(vout.ScriptPubKeyData.Addresses), vout.Mixed)
+		})
 		if err != nil {
-			if err == sql.ErrNoRows {
-				continue
-			}
 			return nil, nil, err
 		}
 
@@ -2519,6 +3239,33 @@ func RetrieveSpendingTxByTxOut(ctx context.Context, db *sql.DB, txHash string,
 	return
 }
 
+// RetrieveMainchainSpenderByTxOut gets the hash of the valid mainchain
+// transaction spending the given outpoint. sql.ErrNoRows is returned if the
+// outpoint is not spent on mainchain.
+func RetrieveMainchainSpenderByTxOut(ctx context.Context, db *sql.DB, txHash string,
+	voutIndex uint32) (spender string, err error) {
+	err = db.QueryRowContext(ctx, internal.SelectMainchainSpenderByPrevOut,
+		txHash, voutIndex).Scan(&spender)
+	return
+}
+
+// RetrieveMainchainOutpoint gets the value of the given output of a valid
+// mainchain transaction, and the transaction's tree, type, block height, and
+// index in the block. sql.ErrNoRows is returned if there is no such output.
+func RetrieveMainchainOutpoint(ctx context.Context, db *sql.DB, txHash string,
+	voutIndex uint32) (value int64, tree int8, txType int16, height int64, blockIndex uint32, err error) {
+	err = db.QueryRowContext(ctx, internal.SelectMainchainOutpoint, txHash,
+		voutIndex).Scan(&value, &tree, &txType, &height, &blockIndex)
+	return
+}
+
+// RetrieveMainchainTxExists checks for a mainchain transaction with the given
+// hash.
+func RetrieveMainchainTxExists(ctx context.Context, db *sql.DB, txHash string) (exists bool, err error) {
+	err = db.QueryRowContext(ctx, internal.SelectMainchainTxExists, txHash).Scan(&exists)
+	return
+}
+
 // RetrieveSpendingTxsByFundingTx gets info on all spending transaction inputs
 // for the given funding transaction specified by DB row ID. This function is
 // called by SpendingTransactions, an important part of the transaction page
@@ -2577,6 +3324,88 @@ func RetrieveSpendingTxsByFundingTxWithBlockHeight(ctx context.Context, db *sql.
 	return
 }
 
+// payloadPrefixUpperBound returns the smallest byte string greater than all
+// byte strings with the given prefix, or nil if there is no such bound.
+func payloadPrefixUpperBound(prefix []byte) []byte {
+	for i := len(prefix) - 1; i >= 0; i-- {
+		if prefix[i] != 0xff {
+			bound := make([]byte, i+1)
+			copy(bound, prefix)
+			bound[i]++
+			return bound
+		}
+	}
+	return nil
+}
+
+// RetrieveNullDataByPrefix retrieves up to N mainchain null data outputs,
+// skipping offset, with a payload beginning with the given prefix, newest
+// first.
+func RetrieveNullDataByPrefix(ctx context.Context, db *sql.DB, prefix []byte, N, offset int64) ([]*dbtypes.NullDataOutput, error) {
+	// A NULL upper bound matches all payloads greater than the prefix.
+	var upperBound interface{}
+	if bound := payloadPrefixUpperBound(prefix); bound != nil {
+		upperBound = bound
+	}
+	rows, err := db.QueryContext(ctx, internal.SelectNullDataByPayloadRange,
+		prefix, upperBound, N, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	var outputs []*dbtypes.NullDataOutput
+	for rows.Next() {
+		var out dbtypes.NullDataOutput
+		var payload []byte
+		err = rows.Scan(&out.TxHash, &out.TxIndex, &out.BlockHeight,
+			&out.BlockTime, &payload)
+		if err != nil {
+			return nil, err
+		}
+		out.Payload = hex.EncodeToString(payload)
+		if utf8.Valid(payload) {
+			out.Text = string(payload)
+		}
+		outputs = append(outputs, &out)
+	}
+	return outputs, rows.Err()
+}
+
+// RetrieveMainchainTxInputs retrieves the block height of the mainchain
+// transaction with the given hash, and the value and previous outpoint
+// addresses of each of its inputs. If the transaction is not in a mainchain
+// block, the error is sql.ErrNoRows.
+func RetrieveMainchainTxInputs(ctx context.Context, db *sql.DB, txHash string) (*dbtypes.TxInputsSummary, error) {
+	rows, err := db.QueryContext(ctx, internal.SelectMainchainTxInputsWithAddresses, txHash)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	var summary *dbtypes.TxInputsSummary
+	for rows.Next() {
+		var height, valueIn int64
+		var addrs []string
+		if err = rows.Scan(&height, &valueIn, pq.Array(&addrs)); err != nil {
+			return nil, err
+		}
+		if summary == nil {
+			summary = &dbtypes.TxInputsSummary{BlockHeight: height}
+		}
+		summary.ValueIn += valueIn
+		summary.ValueIns = append(summary.ValueIns, valueIn)
+		summary.Addresses = append(summary.Addresses, addrs)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	if summary == nil {
+		return nil, sql.ErrNoRows
+	}
+	return summary, nil
+}
+
 // RetrieveVinByID gets from the vins table for the provided row ID.
 func RetrieveVinByID(ctx context.Context, db *sql.DB, vinDbID uint64) (prevOutHash string, prevOutVoutInd uint32,
 	prevOutTree int8, txHash string, txVinInd uint32, txTree int8, valueIn int64, err error) {
@@ -2639,13 +3468,14 @@ func RetrieveVoutsByIDs(ctx context.Context, db *sql.DB, voutDbIDs []uint64) ([]
 	return vouts, nil
 }
 
-func RetrieveUTXOsByVinsJoin(ctx context.Context, db *sql.DB) ([]dbtypes.UTXO, error) {
-	return retrieveUTXOs(ctx, db, internal.SelectUTXOsViaVinsMatch)
+func RetrieveUTXOsByVinsJoin(ctx context.Context, db *sql.DB, sa *spentArchive) ([]dbtypes.UTXO, error) {
+	return retrieveUTXOs(ctx, db, sa.unspentQuery(internal.SelectUTXOsViaVinsMatch))
 }
 
-// RetrieveUTXOs gets the entire UTXO set from the vouts and vins tables.
-func RetrieveUTXOs(ctx context.Context, db *sql.DB) ([]dbtypes.UTXO, error) {
-	return retrieveUTXOs(ctx, db, internal.SelectUTXOs)
+// RetrieveUTXOs gets the entire UTXO set from the vouts and vins tables, not
+// including the archived vouts, which are all spent.
+func RetrieveUTXOs(ctx context.Context, db *sql.DB, sa *spentArchive) ([]dbtypes.UTXO, error) {
+	return retrieveUTXOs(ctx, db, sa.unspentQuery(internal.SelectUTXOs))
 }
 
 // retrieveUTXOs gets the entire UTXO set from the vouts and vins tables.
@@ -2872,9 +3702,14 @@ func InsertSpendingAddressRow(db *sql.DB, fundingTxHash string, fundingTxVoutInd
 		return 0, 0, false, fmt.Errorf(`unable to begin database transaction: %v`, err)
 	}
 
+	changes := make(addressCountChanges)
 	c, voutDbID, mixedOut, err := insertSpendingAddressRow(dbtx, fundingTxHash, fundingTxVoutIndex,
 		fundingTxTree, spendingTxHash, spendingTxVinIndex, vinDbID, utxoData, checked,
-		updateExisting, mainchain, valid, txType, updateFundingRow, spendingTXBlockTime)
+		updateExisting, mainchain, valid, txType, updateFundingRow, changes, nil,
+		spendingTXBlockTime)
+	if err == nil {
+		err = updateAddressCounts(dbtx, changes)
+	}
 	if err != nil {
 		return 0, 0, false, fmt.Errorf(`RowsAffected: %v + %v (rollback)`,
 			err, dbtx.Rollback())
@@ -2902,11 +3737,13 @@ func updateSpendTxInfoInAllVouts(db SqlExecutor) (int64, error) {
 
 // insertSpendingAddressRow inserts a new row in the addresses table for a new
 // transaction input, and updates the spending information for the addresses
-// table row and vouts table row corresponding to the previous outpoint.
+// table row and vouts table row corresponding to the previous outpoint. The
+// inserted rows are added to stored.
 func insertSpendingAddressRow(tx *sql.Tx, fundingTxHash string, fundingTxVoutIndex uint32,
 	fundingTxTree int8, spendingTxHash string, spendingTxVinIndex uint32, vinDbID uint64,
 	spentUtxoData *dbtypes.UTXOData, checked, updateExisting, mainchain, valid bool, txType int16,
-	updateFundingRow bool, blockT ...dbtypes.TimeDef) (int64, uint64, bool, error) {
+	updateFundingRow bool, changes addressCountChanges, stored *storedAddressRows,
+	blockT ...dbtypes.TimeDef) (int64, uint64, bool, error) {
 
 	// Select addresses and value from the matching funding tx output. A maximum
 	// of one row and a minimum of none are expected.
@@ -2956,13 +3793,27 @@ func insertSpendingAddressRow(tx *sql.Tx, fundingTxHash string, fundingTxVoutInd
 	sqlStmt := internal.MakeAddressRowInsertStatement(checked, updateExisting)
 	for i := range addrs {
 		var isFunding bool // spending
-		var rowID uint64
-		err := tx.QueryRow(sqlStmt, addrs[i], fundingTxHash, spendingTxHash,
-			spendingTxVinIndex, vinDbID, value, blockTime, isFunding,
-			mainchain && valid, txType).Scan(&rowID)
+		id, numValid, err := queryInsertAddressRow(func() *sql.Row {
+			return tx.QueryRow(sqlStmt, addrs[i], fundingTxHash, spendingTxHash,
+				spendingTxVinIndex, vinDbID, value, blockTime, isFunding,
+				mainchain && valid, txType)
+		})
 		if err != nil {
 			return 0, 0, mixed, fmt.Errorf("InsertAddressRow: %v", err)
 		}
+		changes.add(addrs[i], isFunding, numValid)
+		stored.add(id, &dbtypes.AddressRow{
+			Address:        addrs[i],
+			ValidMainChain: mainchain && valid,
+			MatchingTxHash: fundingTxHash,
+			IsFunding:      isFunding,
+			TxBlockTime:    blockTime,
+			TxHash:         spendingTxHash,
+			TxVinVoutIndex: spendingTxVinIndex,
+			Value:          value,
+			VinVoutDbID:    vinDbID,
+			TxType:         txType,
+		})
 	}
 
 	if updateFundingRow && valid {
@@ -3058,38 +3909,131 @@ func retrieveTotalAgendaVotesCount(ctx context.Context, db *sql.DB, agendaID str
 	return
 }
 
+// newTicketTypeVotes creates zero vote counts for each of the ticket types in
+// dbtypes.TicketTypes.
+func newTicketTypeVotes() []*apitypes.TicketTypeVotes {
+	votes := make([]*apitypes.TicketTypeVotes, 0, len(dbtypes.TicketTypes))
+	for _, ticketType := range dbtypes.TicketTypes {
+		votes = append(votes, &apitypes.TicketTypeVotes{TicketType: ticketType})
+	}
+	return votes
+}
+
+// retrieveAgendaVotesByTicketType counts the vote choices for the provided
+// agenda id in its voting interval by the type of the voting tickets, as
+// classified by dbtypes.TicketType. The counts of all of the types in
+// dbtypes.TicketTypes are returned, in that order.
+func retrieveAgendaVotesByTicketType(ctx context.Context, db *sql.DB, agendaID string,
+	votingStartHeight, votingDoneHeight int64) ([]*apitypes.TicketTypeVotes, error) {
+	rows, err := db.QueryContext(ctx, internal.SelectAgendaVoteTotalsByTicketOutputs,
+		dbtypes.Yes, dbtypes.Abstain, dbtypes.No, agendaID, votingStartHeight,
+		votingDoneHeight)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	votes := newTicketTypeVotes()
+	byType := make(map[string]*apitypes.TicketTypeVotes, len(votes))
+	for _, ttv := range votes {
+		byType[ttv.TicketType] = ttv
+	}
+
+	for rows.Next() {
+		var numVout, yes, abstain, no, total uint32
+		if err = rows.Scan(&numVout, &yes, &abstain, &no, &total); err != nil {
+			return nil, err
+		}
+		ttv := byType[dbtypes.TicketType(numVout)]
+		ttv.Yes += yes
+		ttv.Abstain += abstain
+		ttv.No += no
+		ttv.Total += total
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Abstaining votes count toward neither approval nor rejection.
+	for _, ttv := range votes {
+		if counted := ttv.Yes + ttv.No; counted > 0 {
+			ttv.Approval = float64(ttv.Yes) / float64(counted)
+		}
+	}
+
+	return votes, nil
+}
+
+// retrieveAgendaVotesByInterval counts the vote choices for the provided
+// agenda id in each consecutive span of interval blocks of its voting
+// interval. Only the per-span counts, heights and times are set, and spans
+// without votes are omitted.
+func retrieveAgendaVotesByInterval(ctx context.Context, db *sql.DB, agendaID string,
+	votingStartHeight, votingDoneHeight, interval int64) ([]*apitypes.AgendaVoteInterval, error) {
+	rows, err := db.QueryContext(ctx, internal.SelectAgendaVotesByInterval,
+		dbtypes.Yes, dbtypes.Abstain, dbtypes.No, agendaID, votingStartHeight,
+		votingDoneHeight, interval)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	var intervals []*apitypes.AgendaVoteInterval
+	for rows.Next() {
+		var index int64
+		var startTime, endTime time.Time
+		avi := new(apitypes.AgendaVoteInterval)
+		err = rows.Scan(&index, &startTime, &endTime, &avi.Yes, &avi.Abstain,
+			&avi.No, &avi.Total)
+		if err != nil {
+			return nil, err
+		}
+		avi.StartHeight = votingStartHeight + index*interval
+		avi.EndHeight = avi.StartHeight + interval - 1
+		if avi.EndHeight > votingDoneHeight {
+			avi.EndHeight = votingDoneHeight
+		}
+		avi.StartTime = startTime.Unix()
+		avi.EndTime = endTime.Unix()
+		intervals = append(intervals, avi)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return intervals, nil
+}
+
 // --- transactions table ---
 
 func InsertTx(db *sql.DB, dbTx *dbtypes.Tx, checked, updateExistingRecords bool) (uint64, error) {
 	insertStatement := internal.MakeTxInsertStatement(checked, updateExistingRecords)
-	var id uint64
-	err := db.QueryRow(insertStatement,
-		dbTx.BlockHash, dbTx.BlockHeight, dbTx.BlockTime, dbTx.Time,
-		dbTx.TxType, int16(dbTx.Version), dbTx.Tree, dbTx.TxID, dbTx.BlockIndex,
-		int32(dbTx.Locktime), int32(dbTx.Expiry), dbTx.Size, dbTx.Spent, dbTx.Sent, dbTx.Fees,
-		dbTx.MixCount, dbTx.MixDenom,
-		dbTx.NumVin, dbtypes.UInt64Array(dbTx.VinDbIds),
-		dbTx.NumVout, dbtypes.UInt64Array(dbTx.VoutDbIds),
-		dbTx.IsValid, dbTx.IsMainchainBlock).Scan(&id)
-	return id, err
+	return queryInsertID(func() rowScanner {
+		return db.QueryRow(insertStatement,
+			dbTx.BlockHash, dbTx.BlockHeight, dbTx.BlockTime, dbTx.Time,
+			dbTx.TxType, int16(dbTx.Version), dbTx.Tree, dbTx.TxID, dbTx.BlockIndex,
+			int32(dbTx.Locktime), int32(dbTx.Expiry), dbTx.Size, dbTx.Spent, dbTx.Sent, dbTx.Fees,
+			dbTx.MixCount, dbTx.MixDenom,
+			dbTx.NumVin, dbtypes.UInt64Array(dbTx.VinDbIds),
+			dbTx.NumVout, dbtypes.UInt64Array(dbTx.VoutDbIds),
+			dbTx.IsValid, dbTx.IsMainchainBlock, dbTx.FeeRate)
+	})
 }
 
 func InsertTxnsStmt(stmt *sql.Stmt, dbTxns []*dbtypes.Tx, checked, updateExistingRecords bool) ([]uint64, error) {
 	ids := make([]uint64, 0, len(dbTxns))
 	for _, tx := range dbTxns {
-		var id uint64
-		err := stmt.QueryRow(
-			tx.BlockHash, tx.BlockHeight, tx.BlockTime, tx.Time,
-			tx.TxType, int16(tx.Version), tx.Tree, tx.TxID, tx.BlockIndex,
-			int32(tx.Locktime), int32(tx.Expiry), tx.Size, tx.Spent, tx.Sent, tx.Fees,
-			tx.MixCount, tx.MixDenom,
-			tx.NumVin, dbtypes.UInt64Array(tx.VinDbIds),
-			tx.NumVout, dbtypes.UInt64Array(tx.VoutDbIds), tx.IsValid,
-			tx.IsMainchainBlock).Scan(&id)
+		id, err := queryInsertID(func() rowScanner {
+			return stmt.QueryRow(
+				tx.BlockHash, tx.BlockHeight, tx.BlockTime, tx.Time,
+				tx.TxType, int16(tx.Version), tx.Tree, tx.TxID, tx.BlockIndex,
+				int32(tx.Locktime), int32(tx.Expiry), tx.Size, tx.Spent, tx.Sent, tx.Fees,
+				tx.MixCount, tx.MixDenom,
+				tx.NumVin, dbtypes.UInt64Array(tx.VinDbIds),
+				tx.NumVout, dbtypes.UInt64Array(tx.VoutDbIds), tx.IsValid,
+				tx.IsMainchainBlock, tx.FeeRate)
+		})
 		if err != nil {
-			if err == sql.ErrNoRows {
-				continue
-			}
 			return nil, err
 		}
 		ids = append(ids, id)
@@ -3130,19 +4074,17 @@ func InsertTxns(db *sql.DB, dbTxns []*dbtypes.Tx, checked, updateExistingRecords
 
 	ids := make([]uint64, 0, len(dbTxns))
 	for _, tx := range dbTxns {
-		var id uint64
-		err := stmt.QueryRow(
-			tx.BlockHash, tx.BlockHeight, tx.BlockTime, tx.Time,
-			tx.TxType, int16(tx.Version), tx.Tree, tx.TxID, tx.BlockIndex,
-			int32(tx.Locktime), int32(tx.Expiry), tx.Size, tx.Spent, tx.Sent, tx.Fees,
-			tx.MixCount, tx.MixDenom,
-			tx.NumVin, dbtypes.UInt64Array(tx.VinDbIds),
-			tx.NumVout, dbtypes.UInt64Array(tx.VoutDbIds), tx.IsValid,
-			tx.IsMainchainBlock).Scan(&id)
+		id, err := queryInsertID(func() rowScanner {
+			return stmt.QueryRow(
+				tx.BlockHash, tx.BlockHeight, tx.BlockTime, tx.Time,
+				tx.TxType, int16(tx.Version), tx.Tree, tx.TxID, tx.BlockIndex,
+				int32(tx.Locktime), int32(tx.Expiry), tx.Size, tx.Spent, tx.Sent, tx.Fees,
+				tx.MixCount, tx.MixDenom,
+				tx.NumVin, dbtypes.UInt64Array(tx.VinDbIds),
+				tx.NumVout, dbtypes.UInt64Array(tx.VoutDbIds), tx.IsValid,
+				tx.IsMainchainBlock, tx.FeeRate)
+		})
 		if err != nil {
-			if err == sql.ErrNoRows {
-				continue
-			}
 			_ = stmt.Close() // try, but we want the QueryRow error back
 			if errRoll := dbtx.Rollback(); errRoll != nil {
 				log.Errorf("Rollback failed: %v", errRoll)
@@ -3171,7 +4113,8 @@ func RetrieveDbTxByHash(ctx context.Context, db *sql.DB, txHash string) (id uint
 		&dbTx.TxType, &dbTx.Version, &dbTx.Tree, &dbTx.TxID, &dbTx.BlockIndex,
 		&dbTx.Locktime, &dbTx.Expiry, &dbTx.Size, &dbTx.Spent, &dbTx.Sent,
 		&dbTx.Fees, &dbTx.MixCount, &dbTx.MixDenom, &dbTx.NumVin, &vinDbIDs,
-		&dbTx.NumVout, &voutDbIDs, &dbTx.IsValid, &dbTx.IsMainchainBlock)
+		&dbTx.NumVout, &voutDbIDs, &dbTx.IsValid, &dbTx.IsMainchainBlock,
+		&dbTx.FeeRate)
 	dbTx.VinDbIds = vinDbIDs
 	dbTx.VoutDbIds = voutDbIDs
 	return
@@ -3219,7 +4162,8 @@ func RetrieveDbTxsByHash(ctx context.Context, db *sql.DB, txHash string) (ids []
 			&dbTx.TxType, &dbTx.Version, &dbTx.Tree, &dbTx.TxID, &dbTx.BlockIndex,
 			&dbTx.Locktime, &dbTx.Expiry, &dbTx.Size, &dbTx.Spent, &dbTx.Sent,
 			&dbTx.Fees, &dbTx.MixCount, &dbTx.MixDenom, &dbTx.NumVin, &vinids,
-			&dbTx.NumVout, &voutids, &dbTx.IsValid, &dbTx.IsMainchainBlock)
+			&dbTx.NumVout, &voutids, &dbTx.IsValid, &dbTx.IsMainchainBlock,
+			&dbTx.FeeRate)
 		if err != nil {
 			return
 		}
@@ -3235,6 +4179,149 @@ func RetrieveDbTxsByHash(ctx context.Context, db *sql.DB, txHash string) (ids []
 	return
 }
 
+// retrieveExplorerVins retrieves the vins with the given row IDs for display,
+// including the previous outputs' addresses and the heights at which they were
+// created. The input that spends no previous output is the coinbase input if
+// coinbase is true, or otherwise a stakebase.
+func retrieveExplorerVins(ctx context.Context, db *sql.DB, ids []uint64, coinbase bool) ([]exptypes.Vin, error) {
+	rows, err := db.QueryContext(ctx, internal.SelectVinsWithPrevOutsByIDs,
+		dbtypes.UInt64Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	vins := make([]exptypes.Vin, 0, len(ids))
+	for rows.Next() {
+		var txIndex, prevIndex uint32
+		var prevTree int8
+		var prevHash string
+		var valueIn, height int64
+		var sigScript []byte
+		var addresses []string
+		err = rows.Scan(&txIndex, &prevHash, &prevIndex, &prevTree, &valueIn,
+			&sigScript, pq.Array(&addresses), &height)
+		if err != nil {
+			return nil, err
+		}
+
+		amountIn := dcrutil.Amount(valueIn).ToCoin()
+		vin := &chainjson.Vin{
+			Txid:        prevHash,
+			Vout:        prevIndex,
+			Tree:        prevTree,
+			AmountIn:    amountIn,
+			BlockHeight: uint32(height),
+		}
+		if txhelpers.IsZeroHashStr(prevHash) {
+			vin.Txid, vin.Vout, vin.BlockHeight = "", 0, 0
+			// The signature script is not stored for the coinbase inputs of
+			// side chain blocks that dcrd did not know when the sig_script
+			// column was added. A non-empty script marks the input as a
+			// coinbase.
+			if len(sigScript) == 0 {
+				sigScript = txhelpers.CoinbaseScript
+			}
+			if coinbase {
+				vin.Coinbase = hex.EncodeToString(sigScript)
+			} else {
+				vin.Stakebase = hex.EncodeToString(sigScript)
+			}
+		}
+		vins = append(vins, exptypes.Vin{
+			Vin:             vin,
+			Addresses:       addresses,
+			FormattedAmount: humanize.Commaf(amountIn),
+			Index:           txIndex,
+		})
+	}
+	return vins, rows.Err()
+}
+
+// retrieveExplorerVouts retrieves the vouts with the given row IDs for display,
+// noting which are spent by valid mainchain transactions.
+func retrieveExplorerVouts(ctx context.Context, db *sql.DB, ids []uint64) ([]exptypes.Vout, error) {
+	rows, err := db.QueryContext(ctx, internal.SelectVoutsWithSpentByIDs,
+		dbtypes.UInt64Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	vouts := make([]exptypes.Vout, 0, len(ids))
+	for rows.Next() {
+		var txIndex uint32
+		var value int64
+		var pkScript []byte
+		var scriptType string
+		var addresses []string
+		var spent bool
+		err = rows.Scan(&txIndex, &value, &pkScript, &scriptType,
+			pq.Array(&addresses), &spent)
+		if err != nil {
+			return nil, err
+		}
+
+		var opReturn string
+		if asm, _ := txscript.DisasmString(pkScript); strings.Contains(asm, "OP_RETURN") {
+			opReturn = asm
+		}
+		amount := dcrutil.Amount(value).ToCoin()
+		vouts = append(vouts, exptypes.Vout{
+			Addresses:       addresses,
+			Amount:          amount,
+			FormattedAmount: humanize.Commaf(amount),
+			OP_RETURN:       opReturn,
+			Type:            scriptType,
+			Spent:           spent,
+			Index:           txIndex,
+		})
+	}
+	return vouts, rows.Err()
+}
+
+// retrieveVoteInfo retrieves the version and choices of the vote with the
+// given transaction hash, and the validation of the block it voted on.
+func retrieveVoteInfo(ctx context.Context, db *sql.DB, txHash string, params *chaincfg.Params) (*exptypes.VoteInfo, error) {
+	var height int64
+	var blockHash string
+	var version uint32
+	var bits int16 // vote_bits is INT2
+	var valid bool
+	err := db.QueryRowContext(ctx, internal.SelectVoteByTxHash, txHash).Scan(
+		&height, &blockHash, &version, &bits, &valid)
+	if err != nil {
+		return nil, err
+	}
+	return &exptypes.VoteInfo{
+		Validation: exptypes.BlockValidation{
+			Hash:     blockHash,
+			Height:   height - 1,
+			Validity: valid,
+		},
+		Version: version,
+		Bits:    uint16(bits),
+		Choices: txhelpers.VoteChoices(version, uint16(bits), params),
+	}, nil
+}
+
+// retrieveVote retrieves a vote with its decoded vote bits, preferring the
+// mainchain vote if the transaction is in multiple blocks.
+func retrieveVote(ctx context.Context, db *sql.DB, txHash string) (*apitypes.Vote, error) {
+	var vote apitypes.Vote
+	var bits int16 // vote_bits is INT2
+	err := db.QueryRowContext(ctx, internal.SelectVoteDetailsByTxHash, txHash).Scan(
+		&vote.TxID, &vote.Ticket, &vote.BlockHash, &vote.BlockHeight,
+		&vote.IsMainchain, &vote.Validation.Hash, &vote.Validation.Validity,
+		&vote.Version, &bits, &vote.Choices)
+	if err != nil {
+		return nil, err
+	}
+	vote.Validation.Height = vote.BlockHeight - 1
+	vote.Bits = uint16(bits)
+	return &vote, nil
+}
+
 // RetrieveTxnsVinsByBlock retrieves for all the transactions in the specified
 // block the vin_db_ids arrays, is_valid, and is_mainchain. This function is
 // used by handleVinsTableMainchainupgrade, so it should not be subject to
@@ -3377,6 +4464,26 @@ func RetrieveTxnsBlocks(ctx context.Context, db *sql.DB, txHash string) (blockHa
 	return
 }
 
+// retrieveDuplicateTxns retrieves the hashes of up to N transactions, skipping
+// offset, that appear in more than one block.
+func retrieveDuplicateTxns(ctx context.Context, db *sql.DB, N, offset int64) ([]string, error) {
+	rows, err := db.QueryContext(ctx, internal.SelectDuplicateTxns, N, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	var txHashes []string
+	for rows.Next() {
+		var txHash string
+		if err = rows.Scan(&txHash); err != nil {
+			return nil, err
+		}
+		txHashes = append(txHashes, txHash)
+	}
+	return txHashes, rows.Err()
+}
+
 // ----- Historical Charts on /charts page -----
 
 // retrieveChartBlocks sets or updates a few per-block datasets.
@@ -3586,25 +4693,82 @@ func appendMissedVotesPerWindow(charts *cache.ChartData, rows *sql.Rows) error {
 	return rows.Err()
 }
 
-// retrieveBlockFees retrieves any block fee data that is newer than the data
-// in the provided ChartData. This data is used to plot fees on the /charts page.
-// This is the Fetcher half of a pair that make up a cache.ChartUpdater.
-func retrieveBlockFees(ctx context.Context, db *sql.DB, charts *cache.ChartData) (*sql.Rows, error) {
-	rows, err := db.QueryContext(ctx, internal.SelectFeesPerBlockAboveHeight, charts.FeesTip())
+// retrieveTicketFeeRates fetches the per-window ticket fee rate statistics for
+// the windows above the ticket fee data in the provided ChartData. The closing
+// blocks of a window are its last twelfth, 12 blocks on mainnet. This is the
+// Fetcher half of a pair that make up a cache.ChartUpdater.
+func retrieveTicketFeeRates(ctx context.Context, db *sql.DB, charts *cache.ChartData) (*sql.Rows, error) {
+	closingBlocks := charts.DiffInterval / 12
+	if closingBlocks < 1 {
+		closingBlocks = 1
+	}
+	rows, err := db.QueryContext(ctx, internal.SelectTicketFeeRatesPerWindow,
+		charts.TicketFeesTip(), charts.DiffInterval, closingBlocks)
 	if err != nil {
 		return nil, err
 	}
 	return rows, nil
 }
 
-// Append the result from retrieveBlockFees to the provided ChartData. This
-// is the Appender half of a pair that make up a cache.ChartUpdater.
-func appendBlockFees(charts *cache.ChartData, rows *sql.Rows) error {
-	defer rows.Close()
-	blocks := charts.Blocks
-	for rows.Next() {
-		var blockHeight uint64
-		var fees int64
+// Append the results from retrieveTicketFeeRates to the provided ChartData.
+// Windows without tickets have zero fee rates. Only the windows that are
+// already in the window stats are appended, so that partial windows are
+// skipped. This is the Appender half of a pair that make up a
+// cache.ChartUpdater.
+func appendTicketFeeRatesPerWindow(charts *cache.ChartData, rows *sql.Rows) error {
+	defer closeRows(rows)
+
+	windows := charts.Windows
+	appendWindow := func(min, median, max, closing uint64) {
+		windows.TicketFeeMin = append(windows.TicketFeeMin, min)
+		windows.TicketFeeMedian = append(windows.TicketFeeMedian, median)
+		windows.TicketFeeMax = append(windows.TicketFeeMax, max)
+		windows.TicketFeeClosing = append(windows.TicketFeeClosing, closing)
+	}
+
+	for rows.Next() {
+		var index int
+		var min, median, max, closing uint64
+		if err := rows.Scan(&index, &min, &median, &max, &closing); err != nil {
+			return err
+		}
+		if index >= len(windows.Time) {
+			break
+		}
+		for len(windows.TicketFeeMedian) < index {
+			appendWindow(0, 0, 0, 0)
+		}
+		appendWindow(min, median, max, closing)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for len(windows.TicketFeeMedian) < len(windows.Time) {
+		appendWindow(0, 0, 0, 0)
+	}
+	return nil
+}
+
+// retrieveBlockFees retrieves any block fee data that is newer than the data
+// in the provided ChartData. This data is used to plot fees on the /charts page.
+// This is the Fetcher half of a pair that make up a cache.ChartUpdater.
+func retrieveBlockFees(ctx context.Context, db *sql.DB, charts *cache.ChartData) (*sql.Rows, error) {
+	rows, err := db.QueryContext(ctx, internal.SelectFeesPerBlockAboveHeight, charts.FeesTip())
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// Append the result from retrieveBlockFees to the provided ChartData. This
+// is the Appender half of a pair that make up a cache.ChartUpdater.
+func appendBlockFees(charts *cache.ChartData, rows *sql.Rows) error {
+	defer rows.Close()
+	blocks := charts.Blocks
+	for rows.Next() {
+		var blockHeight uint64
+		var fees int64
 		if err := rows.Scan(&blockHeight, &fees); err != nil {
 			log.Errorf("Unable to scan for FeeInfoPerBlock fields: %v", err)
 			return err
@@ -3619,6 +4783,43 @@ func appendBlockFees(charts *cache.ChartData, rows *sql.Rows) error {
 	return rows.Err()
 }
 
+// retrieveTxTypes retrieves the per-block transaction counts and sent values by
+// transaction type that are newer than the data in the provided ChartData. This
+// is the Fetcher half of a pair that make up a cache.ChartUpdater.
+func retrieveTxTypes(ctx context.Context, db *sql.DB, charts *cache.ChartData) (*sql.Rows, error) {
+	rows, err := db.QueryContext(ctx, internal.SelectTxTypesPerBlockAboveHeight, charts.TxTypesTip())
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// Append the result from retrieveTxTypes to the provided ChartData. This is the
+// Appender half of a pair that make up a cache.ChartUpdater.
+func appendTxTypes(charts *cache.ChartData, rows *sql.Rows) error {
+	defer rows.Close()
+	blocks := charts.Blocks
+	for rows.Next() {
+		var blockHeight uint64
+		var regular, tickets, votes, revokes uint64
+		var regularVol, ticketVol, voteVol, revokeVol uint64
+		if err := rows.Scan(&blockHeight, &regular, &tickets, &votes, &revokes,
+			&regularVol, &ticketVol, &voteVol, &revokeVol); err != nil {
+			log.Errorf("Unable to scan for transaction type fields: %v", err)
+			return err
+		}
+		blocks.RegularCount = append(blocks.RegularCount, regular)
+		blocks.TicketCount = append(blocks.TicketCount, tickets)
+		blocks.VoteCount = append(blocks.VoteCount, votes)
+		blocks.RevokeCount = append(blocks.RevokeCount, revokes)
+		blocks.RegularVolume = append(blocks.RegularVolume, regularVol)
+		blocks.TicketVolume = append(blocks.TicketVolume, ticketVol)
+		blocks.VoteVolume = append(blocks.VoteVolume, voteVol)
+		blocks.RevokeVolume = append(blocks.RevokeVolume, revokeVol)
+	}
+	return rows.Err()
+}
+
 // retrievePrivacyParticipation retrieves the sum of all mixed vouts that is
 // newer than the data in the provided ChartData. This data is used to plot fees
 // on the /charts page. This is the Fetcher half of a pair that make up a
@@ -3698,9 +4899,9 @@ func appendAnonymitySet(charts *cache.ChartData, rows *sql.Rows) error {
 	return nil
 }
 
-// retrievePoolStats returns all the pool value and the pool size
-// charts data needed to plot ticket-pool-size and ticket-pool value charts on
-// the charts page. This is the Fetcher half of a pair that make up a
+// retrievePoolStats returns all the pool value, pool size and pool price
+// charts data needed to plot ticket-pool-size, ticket-pool-value and
+// ticket-pool-price charts on the charts page. This is the Fetcher half of a pair that make up a
 // cache.ChartUpdater.
 func retrievePoolStats(ctx context.Context, db *sql.DB, charts *cache.ChartData) (*sql.Rows, error) {
 	rows, err := db.QueryContext(ctx, internal.SelectPoolStatsAboveHeight, charts.PoolSizeTip())
@@ -3710,19 +4911,65 @@ func retrievePoolStats(ctx context.Context, db *sql.DB, charts *cache.ChartData)
 	return rows, nil
 }
 
+// retrieveLiveTicketChanges retrieves the change in the number of live tickets
+// by purchase type in each block newer than the data in the provided
+// ChartData. This is the Fetcher half of a pair that make up a
+// cache.ChartUpdater.
+func retrieveLiveTicketChanges(ctx context.Context, db *sql.DB, charts *cache.ChartData,
+	params *chaincfg.Params) (*sql.Rows, error) {
+	rows, err := db.QueryContext(ctx, internal.SelectLiveTicketChangesByOutputsAboveHeight,
+		charts.TicketShareTip(), params.TicketMaturity, params.TicketExpiry)
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// Append the result from retrieveLiveTicketChanges to the provided ChartData,
+// accumulating the changes into the number of live tickets of each purchase
+// type. This is the Appender half of a pair that make up a cache.ChartUpdater.
+func appendLiveTicketChanges(charts *cache.ChartData, rows *sql.Rows) error {
+	defer rows.Close()
+	blocks := charts.Blocks
+	var solo, pooled, other int64
+	if n := len(blocks.SoloLive); n > 0 {
+		solo = int64(blocks.SoloLive[n-1])
+		pooled = int64(blocks.PooledLive[n-1])
+		other = int64(blocks.OtherLive[n-1])
+	}
+	for rows.Next() {
+		var height, dSolo, dPooled, dOther int64
+		if err := rows.Scan(&height, &dSolo, &dPooled, &dOther); err != nil {
+			log.Errorf("Unable to scan for live ticket changes: %v", err)
+			return err
+		}
+		solo += dSolo
+		pooled += dPooled
+		other += dOther
+		if solo < 0 || pooled < 0 || other < 0 {
+			return fmt.Errorf("negative live ticket count at height %d", height)
+		}
+		blocks.SoloLive = append(blocks.SoloLive, uint64(solo))
+		blocks.PooledLive = append(blocks.PooledLive, uint64(pooled))
+		blocks.OtherLive = append(blocks.OtherLive, uint64(other))
+	}
+	return rows.Err()
+}
+
 // Append the result from retrievePoolStats to the provided ChartData. This is
 // the Appender half of a pair that make up a cache.ChartUpdater.
 func appendPoolStats(charts *cache.ChartData, rows *sql.Rows) error {
 	defer rows.Close()
 	blocks := charts.Blocks
 	for rows.Next() {
-		var pval, psize uint64
-		if err := rows.Scan(&psize, &pval); err != nil {
+		var pval, psize, pprice uint64
+		if err := rows.Scan(&psize, &pval, &pprice); err != nil {
 			log.Errorf("Unable to scan for TicketPoolInfo fields: %v", err)
 			return err
 		}
 		blocks.PoolSize = append(blocks.PoolSize, psize)
 		blocks.PoolValue = append(blocks.PoolValue, pval)
+		blocks.PoolPrice = append(blocks.PoolPrice, pprice)
 	}
 	return rows.Err()
 }
@@ -3934,10 +5181,79 @@ func InsertBlock(db *sql.DB, dbBlock *dbtypes.Block, isValid, isMainchain, check
 		dbBlock.Time, int64(dbBlock.Nonce), int16(dbBlock.VoteBits), dbBlock.Voters,
 		dbBlock.FreshStake, dbBlock.Revocations, dbBlock.PoolSize, int64(dbBlock.Bits),
 		int64(dbBlock.SBits), dbBlock.Difficulty, int32(dbBlock.StakeVersion),
-		dbBlock.PreviousHash, dbBlock.ChainWork, pq.Array(dbBlock.Winners)).Scan(&id)
+		dbBlock.PreviousHash, dbBlock.ChainWork, pq.Array(dbBlock.Winners),
+		dbBlock.FinalState).Scan(&id)
 	return id, err
 }
 
+// InsertWinningTickets records the tickets selected to vote on the block with
+// the given hash and height.
+func InsertWinningTickets(db SqlExecutor, blockHash string, height int64, tickets []string) error {
+	_, err := sqlExec(db, internal.InsertWinningTickets,
+		"failed to insert winning tickets: ", blockHash, height, pq.Array(tickets))
+	return err
+}
+
+// InsertMempoolBacklog records the number of transactions and total size of the
+// mempool when the block with the given hash and height was mined.
+func InsertMempoolBacklog(db SqlExecutor, blockHash string, height int64,
+	t dbtypes.TimeDef, numTxns int, size int64) error {
+	_, err := sqlExec(db, internal.UpsertMempoolBacklog,
+		"failed to insert mempool backlog: ", blockHash, height, t, numTxns, size)
+	return err
+}
+
+// retrieveMempoolBacklog retrieves the mempool size when each block newer than
+// the data in the provided ChartData was mined. This is the Fetcher half of a
+// pair that make up a cache.ChartUpdater.
+func retrieveMempoolBacklog(ctx context.Context, db *sql.DB, charts *cache.ChartData) (*sql.Rows, error) {
+	rows, err := db.QueryContext(ctx, internal.SelectMempoolBacklogAboveHeight, charts.BacklogTip())
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// Append the result from retrieveMempoolBacklog to the provided ChartData. This
+// is the Appender half of a pair that make up a cache.ChartUpdater.
+func appendMempoolBacklog(charts *cache.ChartData, rows *sql.Rows) error {
+	defer rows.Close()
+	blocks := charts.Blocks
+	for rows.Next() {
+		var height, size uint64
+		if err := rows.Scan(&height, &size); err != nil {
+			log.Errorf("Unable to scan for mempool backlog fields: %v", err)
+			return err
+		}
+		blocks.Backlog = append(blocks.Backlog, size)
+	}
+	return rows.Err()
+}
+
+// RetrieveTicketWinningBlocks retrieves the blocks in which the ticket with
+// the given hash was selected to vote, including side chain blocks, oldest
+// first.
+func RetrieveTicketWinningBlocks(ctx context.Context, db *sql.DB, ticketHash string) ([]*dbtypes.TicketWinningBlock, error) {
+	rows, err := db.QueryContext(ctx, internal.SelectTicketWinningBlocks, ticketHash)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	var blocks []*dbtypes.TicketWinningBlock
+	for rows.Next() {
+		var t time.Time
+		b := new(dbtypes.TicketWinningBlock)
+		err = rows.Scan(&b.Hash, &b.Height, &t, &b.IsMainchain, &b.FinalState)
+		if err != nil {
+			return nil, err
+		}
+		b.Time = t.Unix()
+		blocks = append(blocks, b)
+	}
+	return blocks, rows.Err()
+}
+
 // InsertBlockPrevNext inserts a new row of the block_chain table.
 func InsertBlockPrevNext(db *sql.DB, blockDbID uint64,
 	hash, prev, next string) error {
@@ -3950,7 +5266,8 @@ func InsertBlockPrevNext(db *sql.DB, blockDbID uint64,
 
 // InsertBlockStats inserts the block stats into the stats table.
 func InsertBlockStats(db *sql.DB, blockDbID uint64, tpi *apitypes.TicketPoolInfo) error {
-	_, err := db.Exec(internal.UpsertStats, blockDbID, tpi.Height, tpi.Size, int64(tpi.Value*dcrToAtoms))
+	_, err := db.Exec(internal.UpsertStats, blockDbID, tpi.Height, tpi.Size,
+		int64(tpi.Value*dcrToAtoms), int64(tpi.ValWeightedAvg*dcrToAtoms))
 	return err
 }
 
@@ -3988,6 +5305,31 @@ func RetrieveBlockHash(ctx context.Context, db *sql.DB, idx int64) (hash string,
 	return
 }
 
+// RetrieveMainchainBlockHashesRange retrieves the hashes of the mainchain
+// blocks with heights in the range [from, to], keyed by height, and the heights
+// of those blocks that have a stats row.
+func RetrieveMainchainBlockHashesRange(ctx context.Context, db *sql.DB, from, to int64) (map[int64]string, map[int64]bool, error) {
+	rows, err := db.QueryContext(ctx, internal.SelectMainchainBlocksStatsRange, from, to)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer closeRows(rows)
+
+	hashes := make(map[int64]string, to-from+1)
+	haveStats := make(map[int64]bool, to-from+1)
+	for rows.Next() {
+		var height int64
+		var hash string
+		var stats bool
+		if err = rows.Scan(&height, &hash, &stats); err != nil {
+			return nil, nil, err
+		}
+		hashes[height] = hash
+		haveStats[height] = stats
+	}
+	return hashes, haveStats, rows.Err()
+}
+
 // RetrieveBlockTimeByHeight retrieves time hash of the main chain block at the
 // given height, if it exists (be sure to check error against sql.ErrNoRows!).
 func RetrieveBlockTimeByHeight(ctx context.Context, db *sql.DB, idx int64) (time dbtypes.TimeDef, err error) {
@@ -3995,6 +5337,122 @@ func RetrieveBlockTimeByHeight(ctx context.Context, db *sql.DB, idx int64) (time
 	return
 }
 
+// retrieveBlockTimesByHeights retrieves the times of the main chain blocks at
+// the given heights, by height. Heights without a main chain block are omitted.
+func retrieveBlockTimesByHeights(ctx context.Context, db *sql.DB, heights []int64) (map[int64]int64, error) {
+	rows, err := db.QueryContext(ctx, internal.SelectBlockTimesByHeights, pq.Array(heights))
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	times := make(map[int64]int64, len(heights))
+	for rows.Next() {
+		var height int64
+		var t dbtypes.TimeDef
+		if err = rows.Scan(&height, &t); err != nil {
+			return nil, err
+		}
+		times[height] = t.UNIX()
+	}
+	return times, rows.Err()
+}
+
+// retrieveBlockVersionCounts counts the versions of the mainchain blocks in the
+// height range [from, to].
+func retrieveBlockVersionCounts(ctx context.Context, db *sql.DB, from, to int64) (map[int32]int64, error) {
+	rows, err := db.QueryContext(ctx, internal.SelectBlockVersionCounts, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	counts := make(map[int32]int64)
+	for rows.Next() {
+		var version int32
+		var count int64
+		if err = rows.Scan(&version, &count); err != nil {
+			return nil, err
+		}
+		counts[version] = count
+	}
+	return counts, rows.Err()
+}
+
+// retrieveBlockVersionThresholds retrieves, for each mainchain block version,
+// the first blocks at which at least enforce and reject of the trailing window
+// mainchain blocks had that version or higher.
+func retrieveBlockVersionThresholds(ctx context.Context, db *sql.DB, window, enforce, reject int64) ([]*apitypes.UpgradeThreshold, error) {
+	rows, err := db.QueryContext(ctx, internal.SelectBlockVersionThresholds,
+		window, enforce, reject)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	var thresholds []*apitypes.UpgradeThreshold
+	for rows.Next() {
+		var version int32
+		var enforceHeight, enforceTime, rejectHeight, rejectTime sql.NullInt64
+		err = rows.Scan(&version, &enforceHeight, &enforceTime, &rejectHeight,
+			&rejectTime)
+		if err != nil {
+			return nil, err
+		}
+		if enforceHeight.Valid {
+			thresholds = append(thresholds, &apitypes.UpgradeThreshold{
+				Kind:    apitypes.UpgradeMinerEnforce,
+				Version: version,
+				Height:  enforceHeight.Int64,
+				Time:    enforceTime.Int64,
+			})
+		}
+		if rejectHeight.Valid {
+			thresholds = append(thresholds, &apitypes.UpgradeThreshold{
+				Kind:    apitypes.UpgradeMinerReject,
+				Version: version,
+				Height:  rejectHeight.Int64,
+				Time:    rejectTime.Int64,
+			})
+		}
+	}
+	return thresholds, rows.Err()
+}
+
+// retrieveVoteVersionsByInterval counts the mainchain votes of each version in
+// each stake version interval of the given length, the first of which starts
+// at height offset. Intervals without votes are omitted.
+func retrieveVoteVersionsByInterval(ctx context.Context, db *sql.DB, offset, interval int64) ([]*voteVersionInterval, error) {
+	rows, err := db.QueryContext(ctx, internal.SelectVoteVersionsByInterval,
+		offset, interval)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	var intervals []*voteVersionInterval
+	var cur *voteVersionInterval
+	for rows.Next() {
+		var index, count int64
+		var version int32
+		if err = rows.Scan(&index, &version, &count); err != nil {
+			return nil, err
+		}
+		if cur == nil || cur.index != index {
+			start := offset + index*interval
+			cur = &voteVersionInterval{
+				index:  index,
+				start:  start,
+				end:    start + interval - 1,
+				counts: make(map[int32]int64),
+			}
+			intervals = append(intervals, cur)
+		}
+		cur.counts[version] = count
+	}
+	return intervals, rows.Err()
+}
+
 // RetrieveBlockHeight retrieves the height of the block with the given hash, if
 // it exists (be sure to check error against sql.ErrNoRows!).
 func RetrieveBlockHeight(ctx context.Context, db *sql.DB, hash string) (height int64, err error) {
@@ -4056,6 +5514,7 @@ func RetrieveSideChainBlocks(ctx context.Context, db *sql.DB) (blocks []*dbtypes
 		if err != nil {
 			return
 		}
+		bs.Validity = dbtypes.BlockValidity(bs.IsValid, bs.NextHash)
 
 		blocks = append(blocks, &bs)
 	}
@@ -4081,6 +5540,7 @@ func RetrieveSideChainTips(ctx context.Context, db *sql.DB) (blocks []*dbtypes.B
 		if err != nil {
 			return
 		}
+		bs.Validity = dbtypes.BlockApprovalPending
 
 		blocks = append(blocks, &bs)
 	}
@@ -4089,6 +5549,64 @@ func RetrieveSideChainTips(ctx context.Context, db *sql.DB) (blocks []*dbtypes.B
 	return
 }
 
+// RetrieveBlockChainWork retrieves the proof-of-work and cumulative chain work
+// for the block with the specified hash.
+func RetrieveBlockChainWork(ctx context.Context, db *sql.DB, hash string) (*dbtypes.BlockChainWork, error) {
+	var bcw dbtypes.BlockChainWork
+	err := db.QueryRowContext(ctx, internal.SelectBlockChainWork, hash).Scan(&bcw.Hash,
+		&bcw.Height, &bcw.IsMainchain, &bcw.Bits, &bcw.ChainWork)
+	if err != nil {
+		return nil, err
+	}
+	bcw.Work = txhelpers.BlockWork(bcw.Bits)
+	return &bcw, nil
+}
+
+// RetrieveBlockStakeHeader retrieves the stake fields of the header of the
+// block with the given hash.
+func RetrieveBlockStakeHeader(ctx context.Context, db *sql.DB, hash string) (*dbtypes.BlockStakeHeader, error) {
+	var bsh dbtypes.BlockStakeHeader
+	var voteBits int16
+	var sbits int64
+	err := db.QueryRowContext(ctx, internal.SelectBlockStakeHeader, hash).Scan(&bsh.Hash,
+		&bsh.Height, &bsh.IsValid, &bsh.IsMainchain, &voteBits, &bsh.FinalState,
+		&bsh.Voters, &bsh.FreshStake, &bsh.Revocations, &bsh.PoolSize, &sbits,
+		&bsh.StakeVersion)
+	if err != nil {
+		return nil, err
+	}
+	bsh.VoteBits = uint16(voteBits)
+	bsh.SBits = dcrutil.Amount(sbits).ToCoin()
+	return &bsh, nil
+}
+
+// RetrieveChainTipsChainWork retrieves the proof-of-work and cumulative chain
+// work for the tips of the main chain and all known side chains, ordered by
+// decreasing chain work.
+func RetrieveChainTipsChainWork(ctx context.Context, db *sql.DB) (tips []*dbtypes.BlockChainWork, err error) {
+	var rows *sql.Rows
+	rows, err = db.QueryContext(ctx, internal.SelectChainTipsChainWork)
+	if err != nil {
+		return
+	}
+	defer closeRows(rows)
+
+	for rows.Next() {
+		var bcw dbtypes.BlockChainWork
+		err = rows.Scan(&bcw.Hash, &bcw.Height, &bcw.IsMainchain, &bcw.Bits,
+			&bcw.ChainWork)
+		if err != nil {
+			return
+		}
+		bcw.Work = txhelpers.BlockWork(bcw.Bits)
+
+		tips = append(tips, &bcw)
+	}
+	err = rows.Err()
+
+	return
+}
+
 // RetrieveDisapprovedBlocks retrieves the block chain status for all blocks
 // that had their regular transactions invalidated by stakeholder disapproval.
 func RetrieveDisapprovedBlocks(ctx context.Context, db *sql.DB) (blocks []*dbtypes.BlockStatus, err error) {
@@ -4105,6 +5623,7 @@ func RetrieveDisapprovedBlocks(ctx context.Context, db *sql.DB) (blocks []*dbtyp
 		if err != nil {
 			return
 		}
+		bs.Validity = dbtypes.BlockDisapproved
 
 		blocks = append(blocks, &bs)
 	}
@@ -4118,6 +5637,9 @@ func RetrieveDisapprovedBlocks(ctx context.Context, db *sql.DB) (blocks []*dbtyp
 func RetrieveBlockStatus(ctx context.Context, db *sql.DB, hash string) (bs dbtypes.BlockStatus, err error) {
 	err = db.QueryRowContext(ctx, internal.SelectBlockStatus, hash).Scan(&bs.IsValid,
 		&bs.IsMainchain, &bs.Height, &bs.PrevHash, &bs.Hash, &bs.NextHash)
+	if err == nil {
+		bs.Validity = dbtypes.BlockValidity(bs.IsValid, bs.NextHash)
+	}
 	return
 }
 
@@ -4127,8 +5649,8 @@ func RetrieveBlockFlags(ctx context.Context, db *sql.DB, hash string) (isValid b
 	return
 }
 
-// RetrieveBlockSummaryByTimeRange retrieves the slice of block summaries for
-// the given time range. The limit specifies the number of most recent block
+// RetrieveBlockSummaryByTimeRange retrieves the slice of mainchain block
+// summaries for the given time range. The limit specifies the number of most recent block
 // summaries to return. A limit of 0 indicates all blocks in the time range
 // should be included.
 func RetrieveBlockSummaryByTimeRange(ctx context.Context, db *sql.DB, minTime, maxTime int64, limit int) ([]dbtypes.BlockDataBasic, error) {
@@ -4142,13 +5664,13 @@ func RetrieveBlockSummaryByTimeRange(ctx context.Context, db *sql.DB, minTime, m
 	maxT := time.Unix(maxTime, 0)
 
 	if limit == 0 {
-		stmt, err = db.Prepare(internal.SelectBlockByTimeRangeSQLNoLimit)
+		stmt, err = db.Prepare(internal.SelectMainchainBlocksByTimeRangeNoLimit)
 		if err != nil {
 			return nil, err
 		}
 		rows, err = stmt.QueryContext(ctx, minT, maxT)
 	} else {
-		stmt, err = db.Prepare(internal.SelectBlockByTimeRangeSQL)
+		stmt, err = db.Prepare(internal.SelectMainchainBlocksByTimeRange)
 		if err != nil {
 			return nil, err
 		}
@@ -4166,7 +5688,7 @@ func RetrieveBlockSummaryByTimeRange(ctx context.Context, db *sql.DB, minTime, m
 		var dbBlock dbtypes.BlockDataBasic
 		var blockTime dbtypes.TimeDef
 		err = rows.Scan(&dbBlock.Hash, &dbBlock.Height, &dbBlock.Size,
-			&blockTime, &dbBlock.NumTx)
+			&blockTime, &dbBlock.NumTx, &dbBlock.PoolSize)
 		if err != nil {
 			log.Errorf("Unable to scan for block fields: %v", err)
 			return nil, err
@@ -4223,29 +5745,33 @@ func UpdateTransactionsMainchain(db *sql.DB, blockHash string, isMainchain bool)
 }
 
 // UpdateTransactionsValid sets the is_valid column of the transactions table
-// for the regular (non-stake) transactions in the specified block.
-func UpdateTransactionsValid(db *sql.DB, blockHash string, isValid bool) (int64, []uint64, error) {
+// for the regular (non-stake) transactions in the specified block. The row IDs
+// and hashes of the updated transactions are returned.
+func UpdateTransactionsValid(db *sql.DB, blockHash string, isValid bool) (int64, []uint64, []string, error) {
 	rows, err := db.Query(internal.UpdateRegularTxnsValidByBlock, isValid, blockHash)
 	if err != nil {
-		return 0, nil, fmt.Errorf("failed to update regular transactions is_valid: %v", err)
+		return 0, nil, nil, fmt.Errorf("failed to update regular transactions is_valid: %v", err)
 	}
 	defer closeRows(rows)
 
 	var numRows int64
 	var txRowIDs []uint64
+	var txHashes []string
 	for rows.Next() {
 		var id uint64
-		err = rows.Scan(&id)
+		var txHash string
+		err = rows.Scan(&id, &txHash)
 		if err != nil {
-			return 0, nil, err
+			return 0, nil, nil, err
 		}
 
 		txRowIDs = append(txRowIDs, id)
+		txHashes = append(txHashes, txHash)
 		numRows++
 	}
 	err = rows.Err()
 
-	return numRows, txRowIDs, err
+	return numRows, txRowIDs, txHashes, err
 }
 
 // UpdateVotesMainchain sets the is_mainchain column for the votes in the
@@ -4271,16 +5797,55 @@ func UpdateTicketsMainchain(db SqlExecutor, blockHash string, isMainchain bool)
 }
 
 // UpdateAddressesMainchainByIDs sets the valid_mainchain column for the
-// addresses specified by their vin (spending) or vout (funding) row IDs.
-func UpdateAddressesMainchainByIDs(db SqlExecutor, vinsBlk, voutsBlk []dbtypes.UInt64Array, isValidMainchain bool) (numSpendingRows, numFundingRows int64, err error) {
+// addresses specified by their vin (spending) or vout (funding) row IDs, and
+// updates the address counts accordingly. Only the rows with a different
+// valid_mainchain are updated and counted. The rows and counts are updated in
+// one database transaction.
+func UpdateAddressesMainchainByIDs(db *sql.DB, vinsBlk, voutsBlk []dbtypes.UInt64Array, isValidMainchain bool) (numSpendingRows, numFundingRows int64, err error) {
+	delta := int64(-1)
+	if isValidMainchain {
+		delta = 1
+	}
+
+	dbtx, err := db.Begin()
+	if err != nil {
+		err = fmt.Errorf("unable to begin database transaction: %v", err)
+		return
+	}
+	defer func() {
+		if err != nil {
+			_ = dbtx.Rollback() // try, but we want the original error back
+		}
+	}()
+
+	changes := make(addressCountChanges)
+	setMainchain := func(stmt string, rowID uint64, isFunding bool) (int64, error) {
+		rows, err := dbtx.Query(stmt, isValidMainchain, rowID)
+		if err != nil {
+			return 0, err
+		}
+		defer closeRows(rows)
+
+		var N int64
+		for rows.Next() {
+			var address string
+			if err = rows.Scan(&address); err != nil {
+				return 0, err
+			}
+			changes.add(address, isFunding, delta)
+			N++
+		}
+		return N, rows.Err()
+	}
+
 	// Spending/vins: Set valid_mainchain for the is_funding=false addresses
 	// table rows using the vins row ids.
 	var numUpdated int64
 	for iTxn := range vinsBlk {
 		for _, vin := range vinsBlk[iTxn] {
-			numUpdated, err = sqlExec(db, internal.SetAddressMainchainForVinIDs,
-				"failed to update spending addresses is_mainchain: ", isValidMainchain, vin)
+			numUpdated, err = setMainchain(internal.SetAddressMainchainForVinIDs, vin, false)
 			if err != nil {
+				err = fmt.Errorf("failed to update spending addresses is_mainchain: %v", err)
 				return
 			}
 			numSpendingRows += numUpdated
@@ -4291,14 +5856,19 @@ func UpdateAddressesMainchainByIDs(db SqlExecutor, vinsBlk, voutsBlk []dbtypes.U
 	// table rows using the vouts row ids.
 	for iTxn := range voutsBlk {
 		for _, vout := range voutsBlk[iTxn] {
-			numUpdated, err = sqlExec(db, internal.SetAddressMainchainForVoutIDs,
-				"failed to update funding addresses is_mainchain: ", isValidMainchain, vout)
+			numUpdated, err = setMainchain(internal.SetAddressMainchainForVoutIDs, vout, true)
 			if err != nil {
+				err = fmt.Errorf("failed to update funding addresses is_mainchain: %v", err)
 				return
 			}
 			numFundingRows += numUpdated
 		}
 	}
+
+	if err = updateAddressCounts(dbtx, changes); err != nil {
+		return
+	}
+	err = dbtx.Commit()
 	return
 }
 
@@ -4461,11 +6031,12 @@ func RetrievePoolInfo(ctx context.Context, db *sql.DB, ind int64) (*apitypes.Tic
 	}
 	var hash string
 	var winners []string
-	var val int64
+	var val, wavg int64
 	err := db.QueryRowContext(ctx, internal.SelectPoolInfoByHeight, ind).Scan(&hash, &tpi.Size,
-		&val, pq.Array(&winners))
+		&val, &wavg, pq.Array(&winners))
 	tpi.Value = dcrutil.Amount(val).ToCoin()
 	tpi.ValAvg = tpi.Value / float64(tpi.Size)
+	tpi.ValWeightedAvg = dcrutil.Amount(wavg).ToCoin()
 	tpi.Winners = winners
 	return tpi, err
 }
@@ -4474,15 +6045,32 @@ func RetrievePoolInfo(ctx context.Context, db *sql.DB, ind int64) (*apitypes.Tic
 func RetrievePoolInfoByHash(ctx context.Context, db *sql.DB, hash string) (*apitypes.TicketPoolInfo, error) {
 	tpi := new(apitypes.TicketPoolInfo)
 	var winners []string
-	var val int64
+	var val, wavg int64
 	err := db.QueryRowContext(ctx, internal.SelectPoolInfoByHash, hash).Scan(&tpi.Height, &tpi.Size,
-		&val, pq.Array(&winners))
+		&val, &wavg, pq.Array(&winners))
 	tpi.Value = dcrutil.Amount(val).ToCoin()
 	tpi.ValAvg = tpi.Value / float64(tpi.Size)
+	tpi.ValWeightedAvg = dcrutil.Amount(wavg).ToCoin()
 	tpi.Winners = winners
 	return tpi, err
 }
 
+// RetrieveWinnersByHash returns the lottery winners (tickets called to vote
+// on the block) for the block with the given hash.
+func RetrieveWinnersByHash(ctx context.Context, db *sql.DB, hash string) ([]string, error) {
+	var winners []string
+	err := db.QueryRowContext(ctx, internal.SelectWinnersByHash, hash).Scan(pq.Array(&winners))
+	return winners, err
+}
+
+// RetrieveWinners returns the lottery winners for the mainchain block at the
+// given height.
+func RetrieveWinners(ctx context.Context, db *sql.DB, ind int64) ([]string, error) {
+	var winners []string
+	err := db.QueryRowContext(ctx, internal.SelectWinnersByHeight, ind).Scan(pq.Array(&winners))
+	return winners, err
+}
+
 // RetrievePoolInfoRange returns an array of apitypes.TicketPoolInfo for block
 // range ind0 to ind1 and a non-nil error on success
 func RetrievePoolInfoRange(ctx context.Context, db *sql.DB, ind0, ind1 int64) ([]apitypes.TicketPoolInfo, []string, error) {
@@ -4515,14 +6103,15 @@ func RetrievePoolInfoRange(ctx context.Context, db *sql.DB, ind0, ind1 int64) ([
 		var tpi apitypes.TicketPoolInfo
 		var hash string
 		var winners []string
-		var val int64
-		if err = rows.Scan(&tpi.Height, &hash, &tpi.Size, &val,
+		var val, wavg int64
+		if err = rows.Scan(&tpi.Height, &hash, &tpi.Size, &val, &wavg,
 			pq.Array(&winners)); err != nil {
 			log.Errorf("Unable to scan for TicketPoolInfo fields: %v", err)
 			return nil, nil, err
 		}
 		tpi.Value = dcrutil.Amount(val).ToCoin()
 		tpi.ValAvg = tpi.Value / float64(tpi.Size)
+		tpi.ValWeightedAvg = dcrutil.Amount(wavg).ToCoin()
 		tpi.Winners = winners
 		tpis = append(tpis, tpi)
 		hashes = append(hashes, hash)
@@ -4680,6 +6269,39 @@ func RetrieveBlockSummaryRange(ctx context.Context, db *sql.DB, ind0, ind1 int64
 	return blocks, nil
 }
 
+// RetrieveBlockSummariesBefore fetches basic block data for up to limit
+// mainchain blocks below the given height, newest first.
+func RetrieveBlockSummariesBefore(ctx context.Context, db *sql.DB, before, limit int64) ([]*apitypes.BlockDataBasic, error) {
+	rows, err := db.QueryContext(ctx, internal.SelectBlockDataBefore, before, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var blocks []*apitypes.BlockDataBasic
+	for rows.Next() {
+		bd := apitypes.NewBlockDataBasic()
+		var winners []string
+		var isValid bool
+		var val, sbits int64
+		var timestamp dbtypes.TimeDef
+		err = rows.Scan(
+			&bd.Hash, &bd.Height, &bd.Size, &bd.Difficulty, &sbits, &timestamp,
+			&bd.PoolInfo.Size, &val, pq.Array(&winners), &isValid,
+		)
+		if err != nil {
+			return nil, err
+		}
+		bd.PoolInfo.Value = dcrutil.Amount(val).ToCoin()
+		bd.PoolInfo.ValAvg = bd.PoolInfo.Value / float64(bd.Size)
+		bd.Time = apitypes.TimeAPI{S: timestamp}
+		bd.PoolInfo.Winners = winners
+		bd.StakeDiff = dcrutil.Amount(sbits).ToCoin()
+		blocks = append(blocks, bd)
+	}
+	return blocks, rows.Err()
+}
+
 // RetrieveBlockSummaryRangeStepped fetches basic block data for every step'th
 // block in range (ind0, ind1).
 func RetrieveBlockSummaryRangeStepped(ctx context.Context, db *sql.DB, ind0, ind1, step int64) ([]*apitypes.BlockDataBasic, error) {
@@ -4801,6 +6423,334 @@ func RetrieveSBitsByHash(ctx context.Context, db *sql.DB, hash string) (int64, e
 	return sbits, err
 }
 
+// InsertStakeDiffEstimate stores dcrd's estimate of the next window's stake
+// difficulty as of the given mainchain block. The amounts are in atoms.
+func InsertStakeDiffEstimate(db SqlExecutor, height int64, blockHash string,
+	windowSize, expected, min, max int64) error {
+	_, err := sqlExec(db, internal.UpsertStakeDiffEstimate,
+		"failed to insert stake difficulty estimate: ",
+		height, blockHash, height/windowSize, expected, min, max)
+	return err
+}
+
+// RetrieveStakeDiffEstimateAccuracy compares the stake difficulty estimates
+// made during the windows of the given size with the realized stake difficulty
+// of the following windows, newest window first.
+func RetrieveStakeDiffEstimateAccuracy(ctx context.Context, db *sql.DB,
+	windowSize, N, offset int64) ([]*dbtypes.StakeDiffEstimateAccuracy, error) {
+	rows, err := db.QueryContext(ctx, internal.SelectStakeDiffEstimateAccuracy,
+		windowSize, N, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	var windows []*dbtypes.StakeDiffEstimateAccuracy
+	for rows.Next() {
+		var meanExpected float64
+		var expected, min, max, actual int64
+		a := new(dbtypes.StakeDiffEstimateAccuracy)
+		err = rows.Scan(&a.Window, &a.NumEstimates, &meanExpected,
+			&a.LastHeight, &expected, &min, &max, &actual)
+		if err != nil {
+			return nil, err
+		}
+		a.MeanExpected = dcrutil.Amount(int64(meanExpected)).ToCoin()
+		a.LastExpected = dcrutil.Amount(expected).ToCoin()
+		a.LastMin = dcrutil.Amount(min).ToCoin()
+		a.LastMax = dcrutil.Amount(max).ToCoin()
+		a.Actual = dcrutil.Amount(actual).ToCoin()
+		a.Error = dcrutil.Amount(expected - actual).ToCoin()
+		if actual > 0 {
+			a.ErrorPercent = 100 * float64(expected-actual) / float64(actual)
+		}
+		a.InBounds = actual >= min && actual <= max
+		windows = append(windows, a)
+	}
+	return windows, rows.Err()
+}
+
+// RetrieveStakeDiffWindowsOHLC retrieves the ticket price and ticket pool
+// statistics of the N stake difficulty windows of the given size, after
+// skipping offset windows back from the window of the block at height tip,
+// newest window first.
+func RetrieveStakeDiffWindowsOHLC(ctx context.Context, db *sql.DB,
+	windowSize, tip, N, offset int64) ([]*dbtypes.StakeDiffWindowOHLC, error) {
+	endWindow := tip/windowSize - offset
+	if endWindow < 0 || N < 1 {
+		return nil, nil
+	}
+	startWindow := endWindow - N + 1
+	if startWindow < 0 {
+		startWindow = 0
+	}
+	rows, err := db.QueryContext(ctx, internal.SelectStakeDiffWindowsOHLC,
+		windowSize, startWindow*windowSize, (endWindow+1)*windowSize-1)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	var windows []*dbtypes.StakeDiffWindowOHLC
+	for rows.Next() {
+		var startTime, endTime time.Time
+		var sbitsOpen, sbitsHigh, sbitsLow, sbitsClose, poolVal int64
+		w := new(dbtypes.StakeDiffWindowOHLC)
+		err = rows.Scan(&w.Window, &w.StartHeight, &w.EndHeight,
+			&startTime, &endTime, &sbitsOpen, &sbitsHigh, &sbitsLow, &sbitsClose,
+			&w.Purchased, &w.PoolSize, &poolVal)
+		if err != nil {
+			return nil, err
+		}
+		w.Blocks = w.EndHeight - w.StartHeight + 1
+		w.Time = startTime.Unix()
+		w.EndTime = endTime.Unix()
+		w.Open = dcrutil.Amount(sbitsOpen).ToCoin()
+		w.High = dcrutil.Amount(sbitsHigh).ToCoin()
+		w.Low = dcrutil.Amount(sbitsLow).ToCoin()
+		w.Close = dcrutil.Amount(sbitsClose).ToCoin()
+		w.PoolValue = dcrutil.Amount(poolVal).ToCoin()
+		windows = append(windows, w)
+	}
+	return windows, rows.Err()
+}
+
+// RetrieveTicketDemandHistory retrieves the ticket purchases, ticket price
+// and live ticket pool size of every main chain block, in height order.
+func RetrieveTicketDemandHistory(ctx context.Context, db *sql.DB) ([]dbtypes.BlockTicketDemand, error) {
+	rows, err := db.QueryContext(ctx, internal.SelectTicketDemandHistory)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	var history []dbtypes.BlockTicketDemand
+	for rows.Next() {
+		var b dbtypes.BlockTicketDemand
+		if err = rows.Scan(&b.Height, &b.FreshStake, &b.PoolSize, &b.SBits); err != nil {
+			return nil, err
+		}
+		history = append(history, b)
+	}
+	return history, rows.Err()
+}
+
+// InsertScriptAnomalies records the outputs of a block with nonstandard
+// scripts or script versions other than 0. Outputs of the block that are
+// already recorded are skipped.
+func InsertScriptAnomalies(db SqlExecutor, anomalies []*dbtypes.ScriptAnomaly) error {
+	for _, a := range anomalies {
+		_, err := sqlExec(db, internal.InsertScriptAnomaly,
+			"failed to insert script anomaly: ", a.Height, a.BlockHash,
+			a.TxHash, a.TxIndex, a.TxTree, a.Value, a.Version, a.ScriptType)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RetrieveScriptAnomalies retrieves the recorded outputs of the mainchain
+// blocks with heights in the range [from, to], in block order.
+func RetrieveScriptAnomalies(ctx context.Context, db *sql.DB, from, to int64) ([]*dbtypes.ScriptAnomaly, error) {
+	rows, err := db.QueryContext(ctx, internal.SelectScriptAnomaliesRange, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	var anomalies []*dbtypes.ScriptAnomaly
+	for rows.Next() {
+		a := new(dbtypes.ScriptAnomaly)
+		err = rows.Scan(&a.Height, &a.BlockHash, &a.TxHash, &a.TxIndex,
+			&a.TxTree, &a.Value, &a.Version, &a.ScriptType)
+		if err != nil {
+			return nil, err
+		}
+		anomalies = append(anomalies, a)
+	}
+	return anomalies, rows.Err()
+}
+
+// RetrieveVinsBySigType retrieves the vins of mainchain transactions in blocks
+// with heights in the range [from, to] with signature scripts of the given
+// type, in block order.
+func RetrieveVinsBySigType(ctx context.Context, db *sql.DB, from, to int64, sigType dbtypes.VinSigType) ([]*dbtypes.SigTypeVin, error) {
+	rows, err := db.QueryContext(ctx, internal.SelectVinsBySigTypeRange, from, to, sigType)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	var vins []*dbtypes.SigTypeVin
+	for rows.Next() {
+		vin := &dbtypes.SigTypeVin{SigType: sigType.String()}
+		err = rows.Scan(&vin.Height, &vin.TxHash, &vin.TxIndex, &vin.TxTree,
+			&vin.PrevTxHash, &vin.PrevTxIndex, &vin.Value, &vin.Sequence)
+		if err != nil {
+			return nil, err
+		}
+		vins = append(vins, vin)
+	}
+	return vins, rows.Err()
+}
+
+// InsertBlockAnomalies records the anomalies of a block.
+func InsertBlockAnomalies(db SqlExecutor, anomalies []*dbtypes.BlockAnomaly) error {
+	for _, a := range anomalies {
+		_, err := sqlExec(db, internal.InsertBlockAnomaly,
+			"failed to insert block anomaly: ", a.Height, a.BlockHash,
+			dbtypes.NewTimeDef(time.Unix(a.Time, 0)), a.Kind, a.Value)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RetrieveBlockAnomalies retrieves the recorded anomalies of the mainchain
+// blocks with heights in the range [from, to] of the given kind, or of all
+// kinds if kind is empty, in block order.
+func RetrieveBlockAnomalies(ctx context.Context, db *sql.DB, from, to int64, kind string) ([]*dbtypes.BlockAnomaly, error) {
+	rows, err := db.QueryContext(ctx, internal.SelectBlockAnomaliesRange, from, to, kind)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	var anomalies []*dbtypes.BlockAnomaly
+	for rows.Next() {
+		a := new(dbtypes.BlockAnomaly)
+		var t dbtypes.TimeDef
+		err = rows.Scan(&a.Height, &a.BlockHash, &t, &a.Kind, &a.Value)
+		if err != nil {
+			return nil, err
+		}
+		a.Time = t.UNIX()
+		anomalies = append(anomalies, a)
+	}
+	return anomalies, rows.Err()
+}
+
+// InsertDailyPrice records a DCR price in the given currency for the UTC day of
+// t, updating the day's high, low and close.
+func InsertDailyPrice(db SqlExecutor, currency string, price float64, t time.Time) error {
+	y, m, d := t.UTC().Date()
+	_, err := sqlExec(db, internal.UpsertDailyPrice, "failed to insert daily price: ",
+		time.Date(y, m, d, 0, 0, 0, 0, time.UTC), currency, price)
+	return err
+}
+
+// RetrieveDailyPrices retrieves the daily DCR prices in the given currency,
+// oldest first, starting with the UTC day of since.
+func RetrieveDailyPrices(ctx context.Context, db *sql.DB, currency string,
+	since time.Time) ([]*dbtypes.DailyPrice, error) {
+	y, m, d := since.UTC().Date()
+	rows, err := db.QueryContext(ctx, internal.SelectDailyPrices, currency,
+		time.Date(y, m, d, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	var prices []*dbtypes.DailyPrice
+	for rows.Next() {
+		p := &dbtypes.DailyPrice{Currency: currency}
+		err = rows.Scan(&p.Day, &p.Open, &p.High, &p.Low, &p.Close)
+		if err != nil {
+			return nil, err
+		}
+		prices = append(prices, p)
+	}
+	return prices, rows.Err()
+}
+
+// InsertUTXODistribution records the distribution of the unspent output values
+// among the dbtypes.UTXOBucketBounds buckets for the UTC day of t, as of the
+// block at height, replacing any distribution already recorded for the day.
+// The archived vouts, which are all spent, are not read.
+func InsertUTXODistribution(ctx context.Context, db *sql.DB, sa *spentArchive, t time.Time, height int64) error {
+	y, m, d := t.UTC().Date()
+	day := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+
+	dbTx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	_, err = dbTx.ExecContext(ctx, internal.DeleteUTXODistributionDay, day)
+	if err != nil {
+		_ = dbTx.Rollback()
+		return fmt.Errorf("failed to delete UTXO distribution: %v", err)
+	}
+	_, err = dbTx.ExecContext(ctx, sa.unspentQuery(internal.InsertUTXODistribution), day, height,
+		pq.Int64Array(dbtypes.UTXOBucketBounds))
+	if err != nil {
+		_ = dbTx.Rollback()
+		return fmt.Errorf("failed to insert UTXO distribution: %v", err)
+	}
+	return dbTx.Commit()
+}
+
+// RetrieveUTXODistributions retrieves the UTXO distributions recorded on and
+// after the UTC day of since, oldest first.
+func RetrieveUTXODistributions(ctx context.Context, db *sql.DB, since time.Time) ([]*dbtypes.UTXODistribution, error) {
+	y, m, d := since.UTC().Date()
+	rows, err := db.QueryContext(ctx, internal.SelectUTXODistributions,
+		time.Date(y, m, d, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		return nil, err
+	}
+	return scanUTXODistributions(rows)
+}
+
+// RetrieveLatestUTXODistribution retrieves the most recently recorded UTXO
+// distribution. sql.ErrNoRows is returned if none has been recorded.
+func RetrieveLatestUTXODistribution(ctx context.Context, db *sql.DB) (*dbtypes.UTXODistribution, error) {
+	rows, err := db.QueryContext(ctx, internal.SelectLatestUTXODistribution)
+	if err != nil {
+		return nil, err
+	}
+	dists, err := scanUTXODistributions(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(dists) == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return dists[0], nil
+}
+
+// scanUTXODistributions groups the bucket rows, ordered by day, into a
+// distribution for each day, and closes the rows.
+func scanUTXODistributions(rows *sql.Rows) ([]*dbtypes.UTXODistribution, error) {
+	defer closeRows(rows)
+
+	var dists []*dbtypes.UTXODistribution
+	var dist *dbtypes.UTXODistribution
+	for rows.Next() {
+		var day dbtypes.TimeDef
+		var height, count, value int64
+		var bucket int
+		if err := rows.Scan(&day, &height, &bucket, &count, &value); err != nil {
+			return nil, err
+		}
+		if dist == nil || !dist.Day.T.Equal(day.T) {
+			dist = &dbtypes.UTXODistribution{
+				Day:     day,
+				Height:  height,
+				Buckets: dbtypes.NewUTXOBuckets(),
+			}
+			dists = append(dists, dist)
+		}
+		if bucket < 0 || bucket >= len(dist.Buckets) {
+			return nil, fmt.Errorf("invalid UTXO bucket %d", bucket)
+		}
+		dist.Buckets[bucket].Count = count
+		dist.Buckets[bucket].Value = value
+	}
+	return dists, rows.Err()
+}
+
 // RetrieveSDiffRange returns an array of stake difficulties for block range
 // ind0 to ind1.
 func RetrieveSDiffRange(ctx context.Context, db *sql.DB, ind0, ind1 int64) ([]float64, error) {
@@ -0,0 +1,272 @@
+// Copyright (c) 2020, The Decred developers
+// See LICENSE for details.
+
+package dcrpg
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/wire"
+	"github.com/decred/dcrdata/rpcutils/v3"
+)
+
+// Problems with a stored mainchain block reported by CheckBlockRange.
+const (
+	// BlockMissing indicates there is no mainchain block at the height.
+	BlockMissing = "missing"
+	// BlockMismatched indicates the mainchain block at the height is not the
+	// node's mainchain block.
+	BlockMismatched = "mismatched"
+	// BlockMissingStats indicates the mainchain block has no stats row.
+	BlockMissingStats = "missing stats"
+)
+
+// reindexProgressInterval is the minimum time between progress reports by
+// ReindexBlockRange.
+const reindexProgressInterval = 10 * time.Second
+
+// BlockRangeIssue describes a problem with the stored mainchain block at a
+// height. Hash is the node's mainchain block hash, and DBHash is the hash of
+// the stored mainchain block, if any.
+type BlockRangeIssue struct {
+	Height  int64
+	Hash    string
+	DBHash  string
+	Problem string
+}
+
+// String describes the issue for logging.
+func (bi *BlockRangeIssue) String() string {
+	switch bi.Problem {
+	case BlockMismatched:
+		return fmt.Sprintf("block %d: %s (stored %s, node %s)", bi.Height,
+			bi.Problem, bi.DBHash, bi.Hash)
+	default:
+		return fmt.Sprintf("block %d: %s (%s)", bi.Height, bi.Problem, bi.Hash)
+	}
+}
+
+// checkReindexRange ensures that the blocks in the range [start, end] have been
+// synced, so that they are not concurrently stored by the initial sync.
+func (pgb *ChainDB) checkReindexRange(start, end int64) error {
+	if start < 0 || end < start {
+		return fmt.Errorf("invalid block range [%d, %d]", start, end)
+	}
+	heightDB, err := pgb.HeightDB(pgb.ctx)
+	if err != nil {
+		return fmt.Errorf("DBBestBlock: %v", err)
+	}
+	if end > heightDB {
+		return fmt.Errorf("block range end %d is above the best block %d. "+
+			"Sync the database first", end, heightDB)
+	}
+	if stakeDBHeight := int64(pgb.stakeDB.Height()); end > stakeDBHeight {
+		return fmt.Errorf("block range end %d is above the stake database "+
+			"height %d", end, stakeDBHeight)
+	}
+	if pgb.InBatchSync || pgb.InReorg {
+		return fmt.Errorf("unable to reindex blocks during a sync or reorg")
+	}
+	return nil
+}
+
+// CheckBlockRange compares the stored mainchain blocks with heights in the
+// range [start, end] to the node's mainchain, and returns the missing and
+// mismatched blocks, and those without pool stats.
+func (pgb *ChainDB) CheckBlockRange(start, end int64) ([]BlockRangeIssue, error) {
+	if err := pgb.checkReindexRange(start, end); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := pgb.queryContext(pgb.ctx)
+	defer cancel()
+	dbHashes, haveStats, err := RetrieveMainchainBlockHashesRange(ctx, pgb.db, start, end)
+	if err != nil {
+		return nil, pgb.replaceCancelError(err)
+	}
+
+	var issues []BlockRangeIssue
+	for height := start; height <= end; height++ {
+		if pgb.ctx.Err() != nil {
+			return issues, pgb.ctx.Err()
+		}
+		hash, err := pgb.nodeBlockHash(height)
+		if err != nil {
+			return issues, err
+		}
+		issue := BlockRangeIssue{
+			Height: height,
+			Hash:   hash.String(),
+			DBHash: dbHashes[height],
+		}
+		switch {
+		case issue.DBHash == "":
+			issue.Problem = BlockMissing
+		case issue.DBHash != issue.Hash:
+			issue.Problem = BlockMismatched
+		case !haveStats[height]:
+			issue.Problem = BlockMissingStats
+		default:
+			continue
+		}
+		issues = append(issues, issue)
+	}
+	return issues, nil
+}
+
+// ReindexBlockRange re-processes the node's mainchain blocks with heights in
+// the range [start, end] through StoreBlock with duplicate checks, for example
+// to repair gaps left by an interrupted sync. The blocks found missing or
+// mismatched by CheckBlockRange are logged and returned first, and no blocks
+// are stored if dryRun is true. A stored mainchain block that is not the
+// node's mainchain block is moved to side chain before the node's block is
+// stored. The range must not be above the best block, and should not be
+// reindexed while new blocks are being stored. Blocks cannot be reindexed while
+// spent rows are archived. The best block is only changed if the stored best
+// block is replaced, and the handlers and notifications for new blocks are not
+// signaled for the reindexed blocks. The returned resumeHeight is the first
+// height not yet processed, which is end+1 on success.
+func (pgb *ChainDB) ReindexBlockRange(start, end int64, dryRun bool) (issues []BlockRangeIssue, resumeHeight int64, err error) {
+	issues, err = pgb.CheckBlockRange(start, end)
+	if err != nil {
+		return issues, start, err
+	}
+	log.Infof("Blocks [%d,%d]: %d missing or mismatched blocks.", start, end, len(issues))
+	for i := range issues {
+		log.Infof(" - %v", &issues[i])
+	}
+	if dryRun {
+		return issues, start, nil
+	}
+	if err = pgb.checkUnarchived("block reindex"); err != nil {
+		return issues, start, err
+	}
+
+	if !pgb.dupChecks {
+		pgb.EnableDuplicateCheckOnInsert(true)
+		defer pgb.EnableDuplicateCheckOnInsert(false)
+	}
+
+	pgb.reindexing = true
+	defer func() { pgb.reindexing = false }()
+
+	mismatched := make(map[int64]string)
+	for i := range issues {
+		if issues[i].Problem == BlockMismatched {
+			mismatched[issues[i].Height] = issues[i].DBHash
+		}
+	}
+
+	startTime := time.Now()
+	lastReport := startTime
+	for height := start; height <= end; height++ {
+		if pgb.ctx.Err() != nil {
+			log.Infof("Block reindex interrupted. Resume at height %d.", height)
+			return issues, height, nil
+		}
+
+		if dbHash, found := mismatched[height]; found {
+			log.Infof("Moving block %s at height %d to side chain.", dbHash, height)
+			pgb.blockToSideChain(dbHash, &sideChainUpdates{})
+		}
+
+		if err = pgb.reindexBlock(height); err != nil {
+			return issues, height, fmt.Errorf("block %d: %v", height, err)
+		}
+
+		if time.Since(lastReport) > reindexProgressInterval || height == end {
+			lastReport = time.Now()
+			done := height - start + 1
+			rate := float64(done) / time.Since(startTime).Seconds()
+			log.Infof("Reindexed blocks [%d,%d] of [%d,%d] (%.1f%%, %.1f blocks/s).",
+				start, height, start, end, 100*float64(done)/float64(end-start+1), rate)
+		}
+	}
+
+	return issues, end + 1, nil
+}
+
+// reindexBlock stores the node's mainchain block at the given height with
+// StoreBlock, updating existing records.
+func (pgb *ChainDB) reindexBlock(height int64) error {
+	hash, err := pgb.nodeBlockHash(height)
+	if err != nil {
+		return err
+	}
+	var msgBlock *wire.MsgBlock
+	err = pgb.retryRPC(func() (err error) {
+		msgBlock, err = pgb.Client.GetBlock(hash)
+		return
+	})
+	if err != nil {
+		return fmt.Errorf("GetBlock failed (%s): %v", hash, err)
+	}
+	var chainWork string
+	err = pgb.retryRPC(func() (err error) {
+		chainWork, err = rpcutils.GetChainWork(pgb.Client, hash)
+		return
+	})
+	if err != nil {
+		return fmt.Errorf("GetChainWork failed (%s): %v", hash, err)
+	}
+
+	// StoreBlock needs the pool info of the block and its parent, which are
+	// not cached for older blocks.
+	if err = pgb.ensurePoolInfo(*hash, height); err != nil {
+		return err
+	}
+	if height > 0 {
+		if err = pgb.ensurePoolInfo(msgBlock.Header.PrevBlock, height-1); err != nil {
+			return err
+		}
+	}
+
+	// The block is valid unless the next block's votes disapproved it.
+	isValid := true
+	nextHash, err := pgb.nodeBlockHash(height + 1)
+	if err == nil {
+		var nextHeader *wire.BlockHeader
+		nextHeader, err = pgb.GetBlockHeaderByHash(nextHash.String())
+		if err != nil {
+			return err
+		}
+		isValid = nextHeader.VoteBits&1 != 0
+	}
+
+	isMainchain, updateExistingRecords := true, true
+	updateAddressesSpendingInfo, updateTicketsSpendingInfo := true, true
+	_, _, _, err = pgb.StoreBlock(msgBlock, isValid, isMainchain,
+		updateExistingRecords, updateAddressesSpendingInfo,
+		updateTicketsSpendingInfo, chainWork)
+	return err
+}
+
+// ensurePoolInfo computes and caches the pool info of the mainchain block with
+// the given hash and height if it is not in the stake database's cache.
+func (pgb *ChainDB) ensurePoolInfo(hash chainhash.Hash, height int64) error {
+	if _, found := pgb.stakeDB.PoolInfo(hash); found {
+		return nil
+	}
+	tpi, err := pgb.stakeDB.PoolInfoAtHeight(height)
+	if err != nil {
+		return fmt.Errorf("unable to compute pool info at height %d: %v", height, err)
+	}
+	pgb.stakeDB.SetPoolInfo(hash, tpi)
+	return nil
+}
+
+// nodeBlockHash gets the hash of the node's mainchain block at the given
+// height.
+func (pgb *ChainDB) nodeBlockHash(height int64) (*chainhash.Hash, error) {
+	var hash *chainhash.Hash
+	err := pgb.retryRPC(func() (err error) {
+		hash, err = pgb.Client.GetBlockHash(height)
+		return
+	})
+	if err != nil {
+		return nil, fmt.Errorf("GetBlockHash(%d) failed: %v", height, err)
+	}
+	return hash, nil
+}
@@ -102,8 +102,34 @@ func deleteAddressesForBlock(dbTx SqlExecutor, hash string) (rowsDeleted int64,
 	return sqlExec(dbTx, internal.DeleteAddresses, "failed to delete addresses", hash)
 }
 
-func deleteAddressesForBlockSubQry(dbTx SqlExecutor, hash string) (rowsDeleted int64, err error) {
-	return sqlExec(dbTx, internal.DeleteAddressesSubQry, "failed to delete addresses", hash)
+// deleteAddressesForBlockSubQry deletes the addresses rows of the block's
+// transactions, and subtracts the deleted valid mainchain rows from the address
+// counts.
+func deleteAddressesForBlockSubQry(dbTx *sql.Tx, hash string) (rowsDeleted int64, err error) {
+	rows, err := dbTx.Query(internal.DeleteAddressesSubQry, hash)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete addresses: %v", err)
+	}
+
+	changes := make(addressCountChanges)
+	for rows.Next() {
+		var address string
+		var isFunding, validMainchain bool
+		if err = rows.Scan(&address, &isFunding, &validMainchain); err != nil {
+			closeRows(rows)
+			return 0, err
+		}
+		if validMainchain {
+			changes.add(address, isFunding, -1)
+		}
+		rowsDeleted++
+	}
+	closeRows(rows)
+	if err = rows.Err(); err != nil {
+		return 0, err
+	}
+
+	return rowsDeleted, updateAddressCounts(dbTx, changes)
 }
 
 func deleteBlock(dbTx SqlExecutor, hash string) (rowsDeleted int64, err error) {
@@ -6,6 +6,7 @@ package dcrpg
 import (
 	"context"
 	"database/sql"
+	"fmt"
 
 	"github.com/decred/dcrdata/db/dcrpg/v5/internal"
 )
@@ -375,3 +376,51 @@ func CheckBadBlockApproval(ctx context.Context, db *sql.DB) (hashes []string, ap
 
 	return
 }
+
+// IntegrityResult is the outcome of a referential integrity check. Orphans is
+// the number of orphaned rows found, and Pruned is the number of them pruned or
+// repaired. Repairable is false for checks that can only be reported.
+type IntegrityResult struct {
+	Check      string
+	Orphans    int64
+	Pruned     int64
+	Repairable bool
+}
+
+// AuditIntegrity checks the referential integrity of the blocks, transactions,
+// vins, vouts and addresses tables, returning the number of orphaned rows found
+// by each check. If prune is true, the orphaned rows are deleted, or their
+// dangling references cleared, where possible. Pruned address rows are
+// subtracted from the address counts by the same statement. The audit stops
+// between checks if the ChainDB's context is canceled, returning the results
+// so far.
+func (pgb *ChainDB) AuditIntegrity(prune bool) ([]*IntegrityResult, error) {
+	results := make([]*IntegrityResult, 0, len(internal.IntegrityChecks))
+	for _, check := range internal.IntegrityChecks {
+		if err := pgb.ctx.Err(); err != nil {
+			return results, err
+		}
+
+		res := &IntegrityResult{
+			Check:      check.Name,
+			Repairable: check.Prune != "",
+		}
+		results = append(results, res)
+
+		err := pgb.db.QueryRowContext(pgb.ctx, check.Count).Scan(&res.Orphans)
+		if err != nil {
+			return results, fmt.Errorf("%s: %v", check.Name, err)
+		}
+		log.Debugf("Integrity check %q: %d orphans.", check.Name, res.Orphans)
+
+		if !prune || res.Orphans == 0 || !res.Repairable {
+			continue
+		}
+		res.Pruned, err = sqlExec(pgb.db, check.Prune,
+			"failed to prune "+check.Name+":")
+		if err != nil {
+			return results, err
+		}
+	}
+	return results, nil
+}
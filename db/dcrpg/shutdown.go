@@ -0,0 +1,83 @@
+// Copyright (c) 2020, The Decred developers
+// See LICENSE for details.
+
+package dcrpg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrShuttingDown is returned by Store and StoreBlock when they are called
+// after Shutdown.
+var ErrShuttingDown = errors.New("ChainDB is shutting down")
+
+// writeTracker tracks the in-flight operations that must finish before the DB
+// connection pool is closed.
+type writeTracker struct {
+	mtx     sync.Mutex
+	closing bool
+	wg      sync.WaitGroup
+
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// beginWrite registers an in-flight operation, which must be ended with
+// endWrite. It returns false without registering the operation if Shutdown has
+// been called.
+func (pgb *ChainDB) beginWrite() bool {
+	pgb.writes.mtx.Lock()
+	defer pgb.writes.mtx.Unlock()
+	if pgb.writes.closing {
+		return false
+	}
+	pgb.writes.wg.Add(1)
+	return true
+}
+
+// endWrite ends an in-flight operation registered with beginWrite.
+func (pgb *ChainDB) endWrite() {
+	pgb.writes.wg.Done()
+}
+
+// Shutdown stops accepting new blocks from Store and StoreBlock, waits for the
+// blocks being stored and the project fund balance update to finish, and then
+// closes the DB connection pool. If ctx is done before the in-flight operations
+// finish, the pool is closed anyway, and an error is returned. The ChainDB
+// should not be used after Shutdown. Subsequent calls only return the result
+// of closing the pool.
+func (pgb *ChainDB) Shutdown(ctx context.Context) error {
+	pgb.writes.mtx.Lock()
+	pgb.writes.closing = true
+	pgb.writes.mtx.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		pgb.writes.wg.Wait()
+		close(done)
+	}()
+
+	var err error
+	select {
+	case <-done:
+	case <-ctx.Done():
+		err = fmt.Errorf("in-flight DB writes did not finish: %v", ctx.Err())
+		log.Warnf("Closing the DB with %v", err)
+	}
+
+	pgb.writes.closeOnce.Do(func() {
+		if len(pgb.heightClients) > 0 {
+			stats := pgb.HeightNtfnStats()
+			log.Debugf("Height notifications: %d signaled, %d delivered, %d coalesced, %d dropped.",
+				stats.Signaled, stats.Delivered, stats.Coalesced, stats.Dropped)
+		}
+		pgb.writes.closeErr = pgb.db.Close()
+	})
+	if err != nil {
+		return err
+	}
+	return pgb.writes.closeErr
+}
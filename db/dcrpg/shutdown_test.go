@@ -0,0 +1,62 @@
+package dcrpg
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+)
+
+func TestShutdownWaitsForWrites(t *testing.T) {
+	// sql.Open does not connect, so this works without a PostgreSQL server.
+	db, err := sql.Open("postgres", "host=localhost")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pgb := &ChainDB{db: db}
+
+	if !pgb.beginWrite() {
+		t.Fatal("beginWrite failed before Shutdown")
+	}
+
+	shutdownErr := make(chan error, 1)
+	go func() { shutdownErr <- pgb.Shutdown(context.Background()) }()
+
+	select {
+	case err = <-shutdownErr:
+		t.Fatalf("Shutdown returned with a write in flight: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	pgb.endWrite()
+	select {
+	case err = <-shutdownErr:
+		if err != nil {
+			t.Errorf("Shutdown error: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Shutdown did not return after the write finished")
+	}
+
+	if pgb.beginWrite() {
+		t.Error("beginWrite succeeded after Shutdown")
+	}
+	if err = pgb.Close(); err != nil {
+		t.Errorf("Close after Shutdown error: %v", err)
+	}
+}
+
+func TestShutdownDeadline(t *testing.T) {
+	db, err := sql.Open("postgres", "host=localhost")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pgb := &ChainDB{db: db}
+	pgb.beginWrite() // never ended
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err = pgb.Shutdown(ctx); err == nil {
+		t.Error("expected an error from Shutdown with a write in flight")
+	}
+}
@@ -0,0 +1,408 @@
+// Copyright (c) 2020, The Decred developers
+// See LICENSE for details.
+
+package dcrpg
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/decred/dcrdata/db/dcrpg/v5/internal"
+)
+
+// SnapshotManifestFile is the name of the manifest file in a snapshot
+// directory.
+const SnapshotManifestFile = "manifest.json"
+
+// snapshotBatchSize and snapshotBatchBytes limit the number of rows and the
+// size of the JSON inserted by each statement of a snapshot import.
+const (
+	snapshotBatchSize  = 5000
+	snapshotBatchBytes = 1 << 24
+)
+
+// SnapshotTable describes a table's data file in a chain snapshot.
+type SnapshotTable struct {
+	Name    string   `json:"name"`
+	File    string   `json:"file"`
+	Columns []string `json:"columns"`
+	Rows    int64    `json:"rows"`
+}
+
+// SnapshotManifest describes a chain snapshot, which holds the data for the
+// blocks up to and including the snapshot height.
+type SnapshotManifest struct {
+	Network       string          `json:"network"`
+	SchemaVersion string          `json:"schema_version"`
+	Height        int64           `json:"height"`
+	BlockHash     string          `json:"block_hash"`
+	Created       int64           `json:"created"`
+	Tables        []SnapshotTable `json:"tables"`
+}
+
+// check verifies that the snapshot was exported with the given network and
+// the current DB schema version, and that it includes every snapshot table.
+func (m *SnapshotManifest) check(network string) error {
+	if m.Network != network {
+		return fmt.Errorf("snapshot is for network %s, not %s", m.Network, network)
+	}
+	if m.SchemaVersion != targetDatabaseVersion.String() {
+		return fmt.Errorf("snapshot has DB schema version %s, not %v",
+			m.SchemaVersion, targetDatabaseVersion)
+	}
+	if m.Height < 0 || m.BlockHash == "" {
+		return fmt.Errorf("snapshot has no best block")
+	}
+	if len(m.Tables) != len(internal.SnapshotTableFilters) {
+		return fmt.Errorf("snapshot has %d tables, expected %d",
+			len(m.Tables), len(internal.SnapshotTableFilters))
+	}
+	for i, t := range m.Tables {
+		if t.Name != internal.SnapshotTableFilters[i][0] {
+			return fmt.Errorf("snapshot table %d is %s, expected %s",
+				i, t.Name, internal.SnapshotTableFilters[i][0])
+		}
+	}
+	return nil
+}
+
+// ReadSnapshotManifest reads the manifest of the chain snapshot in dir.
+func ReadSnapshotManifest(dir string) (*SnapshotManifest, error) {
+	b, err := ioutil.ReadFile(filepath.Join(dir, SnapshotManifestFile))
+	if err != nil {
+		return nil, err
+	}
+	manifest := new(SnapshotManifest)
+	if err = json.Unmarshal(b, manifest); err != nil {
+		return nil, fmt.Errorf("invalid snapshot manifest: %v", err)
+	}
+	return manifest, nil
+}
+
+// ExportSnapshot writes the data for the blocks up to and including the given
+// mainchain height to a gzipped file of JSON rows for each table in dir, along
+// with a manifest of the DB schema version, the block, and the tables. All
+// tables are read in one repeatable read transaction, so the snapshot is
+// consistent even if blocks are stored during the export. The meta table is
+// not exported. See ImportSnapshot.
+func (pgb *ChainDB) ExportSnapshot(height int64, dir string) (*SnapshotManifest, error) {
+	if height < 0 || height > pgb.Height() {
+		return nil, fmt.Errorf("snapshot height %d is not in the range [0, %d]",
+			height, pgb.Height())
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+
+	ctx := pgb.ctx
+	tx, err := pgb.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelRepeatableRead,
+		ReadOnly:  true,
+	})
+	if err != nil {
+		return nil, pgb.replaceCancelError(err)
+	}
+	// The transaction is only read from.
+	defer func() { _ = tx.Rollback() }()
+
+	manifest := &SnapshotManifest{
+		Network:       pgb.chainParams.Name,
+		SchemaVersion: targetDatabaseVersion.String(),
+		Height:        height,
+		Created:       time.Now().Unix(),
+	}
+	err = tx.QueryRowContext(ctx, internal.SelectBlockHashByHeight, height).
+		Scan(&manifest.BlockHash)
+	if err != nil {
+		return nil, fmt.Errorf("no mainchain block at height %d: %v",
+			height, pgb.replaceCancelError(err))
+	}
+
+	for _, tf := range internal.SnapshotTableFilters {
+		table := SnapshotTable{
+			Name: tf[0],
+			File: tf[0] + ".json.gz",
+		}
+		table.Columns, err = retrieveTableColumns(ctx, tx, table.Name)
+		if err != nil {
+			return nil, pgb.replaceCancelError(err)
+		}
+		table.Rows, err = exportSnapshotTable(ctx, tx, filepath.Join(dir, table.File),
+			fmt.Sprintf(internal.SelectSnapshotRows, table.Name, tf[1]), height)
+		if err != nil {
+			return nil, fmt.Errorf("failed to export table %s: %v",
+				table.Name, pgb.replaceCancelError(err))
+		}
+		log.Infof("Exported %d rows from the %s table.", table.Rows, table.Name)
+		manifest.Tables = append(manifest.Tables, table)
+	}
+
+	b, err := json.MarshalIndent(manifest, "", "    ")
+	if err != nil {
+		return nil, err
+	}
+	// The manifest is written last so an incomplete snapshot has none.
+	err = ioutil.WriteFile(filepath.Join(dir, SnapshotManifestFile), b, 0600)
+	if err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// retrieveTableColumns retrieves the names of a table's columns in order.
+func retrieveTableColumns(ctx context.Context, tx *sql.Tx, table string) ([]string, error) {
+	rows, err := tx.QueryContext(ctx, internal.SelectTableColumns, table)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	var columns []string
+	for rows.Next() {
+		var column string
+		if err = rows.Scan(&column); err != nil {
+			return nil, err
+		}
+		columns = append(columns, column)
+	}
+	return columns, rows.Err()
+}
+
+// exportSnapshotTable writes the JSON rows selected by the query, with the
+// snapshot height as its argument, as lines of a gzipped file.
+func exportSnapshotTable(ctx context.Context, tx *sql.Tx, path, query string, height int64) (int64, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	zw := gzip.NewWriter(f)
+	w := bufio.NewWriter(zw)
+
+	rows, err := tx.QueryContext(ctx, query, height)
+	if err != nil {
+		return 0, err
+	}
+	defer closeRows(rows)
+
+	var numRows int64
+	for rows.Next() {
+		var row []byte
+		if err = rows.Scan(&row); err != nil {
+			return 0, err
+		}
+		if _, err = w.Write(append(row, '\n')); err != nil {
+			return 0, err
+		}
+		numRows++
+	}
+	if err = rows.Err(); err != nil {
+		return 0, err
+	}
+
+	if err = w.Flush(); err != nil {
+		return 0, err
+	}
+	if err = zw.Close(); err != nil {
+		return 0, err
+	}
+	return numRows, f.Close()
+}
+
+// ImportSnapshot loads the chain snapshot in dir, written by ExportSnapshot,
+// into the empty DB in one transaction. The references in the imported rows to
+// blocks after the snapshot height, such as spending transactions and ticket
+// votes, are removed, and the best block in the meta table is set to the
+// snapshot's block, which must be the node's mainchain block at that height.
+// The stake database is then advanced from the node to the snapshot height.
+// Since the indexes are not created and initial block download is not
+// complete, the next sync creates the indexes and updates the addresses
+// spending info after syncing from the snapshot height. The ChainDB's cached
+// chain state is not reloaded, so dcrdata should be restarted after import.
+// Snapshots cannot be imported while spent row archival is enabled.
+func (pgb *ChainDB) ImportSnapshot(dir string) (*SnapshotManifest, error) {
+	if pgb.cockroach {
+		return nil, fmt.Errorf("snapshot import is not supported with CockroachDB")
+	}
+	if pgb.Height() != -1 {
+		return nil, fmt.Errorf("snapshot import requires an empty DB, "+
+			"but the best block height is %d", pgb.Height())
+	}
+	if err := pgb.checkUnarchived("snapshot import"); err != nil {
+		return nil, err
+	}
+
+	manifest, err := ReadSnapshotManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+	if err = manifest.check(pgb.chainParams.Name); err != nil {
+		return nil, err
+	}
+	nodeHash, err := pgb.nodeBlockHash(manifest.Height)
+	if err != nil {
+		return nil, err
+	}
+	if nodeHash.String() != manifest.BlockHash {
+		return nil, fmt.Errorf("snapshot block %s at height %d is not the "+
+			"node's mainchain block %v", manifest.BlockHash, manifest.Height, nodeHash)
+	}
+
+	ctx := pgb.ctx
+	tx, err := pgb.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, pgb.replaceCancelError(err)
+	}
+
+	for _, table := range manifest.Tables {
+		numRows, err := importSnapshotTable(ctx, tx, filepath.Join(dir, table.File), table.Name)
+		if err != nil {
+			_ = tx.Rollback()
+			return nil, fmt.Errorf("failed to import table %s: %v",
+				table.Name, pgb.replaceCancelError(err))
+		}
+		if numRows != table.Rows {
+			_ = tx.Rollback()
+			return nil, fmt.Errorf("imported %d rows into table %s, expected %d",
+				numRows, table.Name, table.Rows)
+		}
+		log.Infof("Imported %d rows into the %s table.", numRows, table.Name)
+
+		for _, column := range table.Columns {
+			if column != "id" {
+				continue
+			}
+			_, err = tx.ExecContext(ctx, fmt.Sprintf(internal.ResetSerialSequence, table.Name))
+			if err != nil {
+				_ = tx.Rollback()
+				return nil, fmt.Errorf("failed to reset the id sequence of table %s: %v",
+					table.Name, pgb.replaceCancelError(err))
+			}
+		}
+	}
+
+	ticketLife := int64(pgb.chainParams.TicketMaturity) + int64(pgb.chainParams.TicketExpiry)
+	fixups := []struct {
+		stmt string
+		args []interface{}
+	}{
+		{internal.ResetSnapshotNextHashes, nil},
+		{internal.ResetSnapshotVoutSpends, nil},
+		{internal.ResetSnapshotAddressSpends, nil},
+		{internal.ResetSnapshotTicketSpends, []interface{}{manifest.Height}},
+		{internal.ResetSnapshotTicketMisses, nil},
+		{internal.ResetSnapshotTicketExpiries, []interface{}{manifest.Height, ticketLife}},
+	}
+	for _, fix := range fixups {
+		N, err := sqlExec(tx, fix.stmt, "failed to update snapshot rows: ", fix.args...)
+		if err != nil {
+			_ = tx.Rollback()
+			return nil, pgb.replaceCancelError(err)
+		}
+		log.Debugf("Updated %d rows referencing blocks after height %d.", N, manifest.Height)
+	}
+
+	N, err := rebuildAddressCounts(tx)
+	if err != nil {
+		_ = tx.Rollback()
+		return nil, pgb.replaceCancelError(err)
+	}
+	log.Infof("Counted the transactions of %d addresses.", N)
+
+	_, err = sqlExec(tx, internal.SetMetaDBBestBlock,
+		"failed to update best block in meta table: ", manifest.Height, manifest.BlockHash)
+	if err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, pgb.replaceCancelError(err)
+	}
+
+	// Advance the stake database to the snapshot height. If it is ahead, the
+	// next sync rewinds it.
+	for h := int64(pgb.stakeDB.Height()) + 1; h <= manifest.Height; h++ {
+		hash, err := pgb.nodeBlockHash(h)
+		if err != nil {
+			return manifest, err
+		}
+		if _, err = pgb.stakeDB.ConnectBlockHash(hash); err != nil {
+			return manifest, fmt.Errorf("failed to connect block %d to the stake "+
+				"database: %v", h, err)
+		}
+		if h%10000 == 0 {
+			log.Infof("Advanced the stake database to height %d.", h)
+		}
+	}
+
+	return manifest, nil
+}
+
+// importSnapshotTable inserts the JSON rows in the gzipped file into the table
+// in batches, returning the number of rows inserted.
+func importSnapshotTable(ctx context.Context, tx *sql.Tx, path, table string) (int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		return 0, err
+	}
+	defer zr.Close()
+	r := bufio.NewReader(zr)
+
+	stmt := fmt.Sprintf(internal.InsertSnapshotRows, table, table)
+	var batch bytes.Buffer
+	var numRows, batchRows int64
+	insertBatch := func() error {
+		if batchRows == 0 {
+			return nil
+		}
+		batch.WriteByte(']')
+		if _, err := tx.ExecContext(ctx, stmt, batch.String()); err != nil {
+			return err
+		}
+		numRows += batchRows
+		batchRows = 0
+		batch.Reset()
+		return nil
+	}
+
+	for {
+		line, err := r.ReadBytes('\n')
+		line = bytes.TrimSpace(line)
+		if len(line) > 0 {
+			if batchRows == 0 {
+				batch.WriteByte('[')
+			} else {
+				batch.WriteByte(',')
+			}
+			batch.Write(line)
+			batchRows++
+			if batchRows >= snapshotBatchSize || batch.Len() >= snapshotBatchBytes {
+				if err := insertBatch(); err != nil {
+					return numRows, err
+				}
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return numRows, err
+		}
+	}
+	return numRows, insertBatch()
+}
@@ -0,0 +1,83 @@
+package dcrpg
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/decred/dcrdata/db/dcrpg/v5/internal"
+)
+
+func validSnapshotManifest() *SnapshotManifest {
+	m := &SnapshotManifest{
+		Network:       "mainnet",
+		SchemaVersion: targetDatabaseVersion.String(),
+		Height:        420000,
+		BlockHash:     "0000000000000000178a2b0e1ac2bd8c1fc2e1fcc6e9a4b7f7a3d6c6d67d8f7b",
+	}
+	for _, tf := range internal.SnapshotTableFilters {
+		m.Tables = append(m.Tables, SnapshotTable{
+			Name: tf[0],
+			File: tf[0] + ".json.gz",
+		})
+	}
+	return m
+}
+
+func TestSnapshotManifestCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(m *SnapshotManifest)
+		wantErr bool
+	}{
+		{"valid", func(m *SnapshotManifest) {}, false},
+		{"network", func(m *SnapshotManifest) { m.Network = "testnet3" }, true},
+		{"schema", func(m *SnapshotManifest) { m.SchemaVersion = "1.0.0" }, true},
+		{"no block", func(m *SnapshotManifest) { m.BlockHash = "" }, true},
+		{"missing table", func(m *SnapshotManifest) { m.Tables = m.Tables[1:] }, true},
+		{"table order", func(m *SnapshotManifest) {
+			m.Tables[0], m.Tables[1] = m.Tables[1], m.Tables[0]
+		}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := validSnapshotManifest()
+			tt.modify(m)
+			if err := m.check("mainnet"); (err != nil) != tt.wantErr {
+				t.Errorf("check() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestReadSnapshotManifest(t *testing.T) {
+	dir, err := ioutil.TempDir("", "snapshot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if _, err = ReadSnapshotManifest(dir); err == nil {
+		t.Errorf("expected an error reading a missing manifest")
+	}
+
+	want := validSnapshotManifest()
+	b, _ := json.Marshal(want)
+	err = ioutil.WriteFile(filepath.Join(dir, SnapshotManifestFile), b, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadSnapshotManifest(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Height != want.Height || got.BlockHash != want.BlockHash ||
+		len(got.Tables) != len(want.Tables) {
+		t.Errorf("read manifest %+v, wanted %+v", got, want)
+	}
+	if err = got.check("mainnet"); err != nil {
+		t.Errorf("read manifest is invalid: %v", err)
+	}
+}
@@ -0,0 +1,168 @@
+// Copyright (c) 2020, The Decred developers
+// See LICENSE for details.
+
+package dcrpg
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/decred/dcrdata/db/dbtypes/v2"
+	"github.com/decred/dcrdata/db/dcrpg/v5/internal"
+	"github.com/lib/pq"
+)
+
+// StoredBlockAt loads the rows stored with the main chain block at the given
+// height, as they were sent to the stored rows handlers, so that a handler
+// that missed the block can backfill it. The vout scripts are not loaded. If
+// there is no main chain block at the height, the error is sql.ErrNoRows. See
+// RegisterStoredRowsHandler.
+func (pgb *ChainDB) StoredBlockAt(ctx context.Context, height int64) (*dbtypes.StoredBlock, error) {
+	ctx, cancel := pgb.queryContext(ctx)
+	defer cancel()
+
+	stored, err := RetrieveStoredBlock(ctx, pgb.db, height)
+	if err != nil {
+		return nil, pgb.replaceCancelError(err)
+	}
+	return stored, nil
+}
+
+// RetrieveStoredBlock loads the block, transactions, vouts and addresses table
+// rows of the main chain block at the given height, with their row IDs.
+func RetrieveStoredBlock(ctx context.Context, db *sql.DB, height int64) (*dbtypes.StoredBlock, error) {
+	block := new(dbtypes.Block)
+	stored := &dbtypes.StoredBlock{
+		Block:       block,
+		IsMainchain: true,
+	}
+	err := db.QueryRowContext(ctx, internal.SelectStoredBlockByHeight, height).Scan(
+		&stored.BlockDbID, &block.Hash, &block.Height, &block.Size,
+		&stored.IsValid, &block.Version, &block.NumTx, &block.NumRegTx,
+		&block.NumStakeTx, &block.Time, &block.Nonce, &block.VoteBits,
+		&block.Voters, &block.FreshStake, &block.Revocations, &block.PoolSize,
+		&block.Bits, &block.SBits, &block.Difficulty, &block.StakeVersion,
+		&block.PreviousHash, &block.ChainWork)
+	if err != nil {
+		return nil, err
+	}
+
+	stored.TxDbIDs, stored.Txns, err = retrieveStoredTxns(ctx, db, block.Hash)
+	if err != nil {
+		return nil, err
+	}
+	stored.AddressDbIDs, stored.AddressRows, err = retrieveStoredAddressRows(ctx, db, block.Hash)
+	if err != nil {
+		return nil, err
+	}
+	return stored, nil
+}
+
+// retrieveStoredTxns loads the transactions of the block with the given hash,
+// with their vouts, in block order.
+func retrieveStoredTxns(ctx context.Context, db *sql.DB, blockHash string) ([]uint64, []*dbtypes.Tx, error) {
+	rows, err := db.QueryContext(ctx, internal.SelectStoredTxns, blockHash)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer closeRows(rows)
+
+	var ids []uint64
+	var txns []*dbtypes.Tx
+	for rows.Next() {
+		var id uint64
+		var dbTx dbtypes.Tx
+		var vinids, voutids dbtypes.UInt64Array
+		err = rows.Scan(&id,
+			&dbTx.BlockHash, &dbTx.BlockHeight, &dbTx.BlockTime, &dbTx.Time,
+			&dbTx.TxType, &dbTx.Version, &dbTx.Tree, &dbTx.TxID, &dbTx.BlockIndex,
+			&dbTx.Locktime, &dbTx.Expiry, &dbTx.Size, &dbTx.Spent, &dbTx.Sent,
+			&dbTx.Fees, &dbTx.MixCount, &dbTx.MixDenom, &dbTx.NumVin, &vinids,
+			&dbTx.NumVout, &voutids, &dbTx.IsValid, &dbTx.IsMainchainBlock,
+			&dbTx.FeeRate)
+		if err != nil {
+			return nil, nil, err
+		}
+		dbTx.VinDbIds = vinids
+		dbTx.VoutDbIds = voutids
+		ids = append(ids, id)
+		txns = append(txns, &dbTx)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	vouts, err := retrieveStoredVouts(ctx, db, blockHash)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, dbTx := range txns {
+		dbTx.Vouts = make([]*dbtypes.Vout, 0, len(dbTx.VoutDbIds))
+		for _, id := range dbTx.VoutDbIds {
+			if vout, found := vouts[id]; found {
+				dbTx.Vouts = append(dbTx.Vouts, vout)
+			}
+		}
+	}
+	return ids, txns, nil
+}
+
+// retrieveStoredVouts loads the vouts of the transactions of the block with the
+// given hash, by row ID.
+func retrieveStoredVouts(ctx context.Context, db *sql.DB, blockHash string) (map[uint64]*dbtypes.Vout, error) {
+	rows, err := db.QueryContext(ctx, internal.SelectStoredVouts, blockHash)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	vouts := make(map[uint64]*dbtypes.Vout)
+	for rows.Next() {
+		var id uint64
+		var vout dbtypes.Vout
+		var addresses pq.StringArray
+		err = rows.Scan(&id, &vout.TxHash, &vout.TxIndex, &vout.TxTree,
+			&vout.TxType, &vout.Value, &vout.Version,
+			&vout.ScriptPubKeyData.ReqSigs, &vout.ScriptPubKeyData.Type,
+			&addresses, &vout.Mixed)
+		if err != nil {
+			return nil, err
+		}
+		if len(addresses) > 0 {
+			vout.ScriptPubKeyData.Addresses = addresses
+		}
+		vouts[id] = &vout
+	}
+	return vouts, rows.Err()
+}
+
+// retrieveStoredAddressRows loads the funding and spending address rows of the
+// transactions of the block with the given hash.
+func retrieveStoredAddressRows(ctx context.Context, db *sql.DB, blockHash string) ([]uint64, []*dbtypes.AddressRow, error) {
+	rows, err := db.QueryContext(ctx, internal.SelectStoredAddressRows, blockHash)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer closeRows(rows)
+
+	var ids []uint64
+	var addressRows []*dbtypes.AddressRow
+	for rows.Next() {
+		var id uint64
+		var addr dbtypes.AddressRow
+		var matchingTxHash sql.NullString
+		var txVinVoutIndex, vinVoutDbID sql.NullInt64
+		err = rows.Scan(&id, &addr.Address, &addr.TxHash, &addr.ValidMainChain,
+			&matchingTxHash, &addr.Value, &addr.TxBlockTime, &addr.IsFunding,
+			&txVinVoutIndex, &vinVoutDbID, &addr.TxType)
+		if err != nil {
+			return nil, nil, err
+		}
+		addr.MatchingTxHash = matchingTxHash.String
+		addr.TxVinVoutIndex = uint32(txVinVoutIndex.Int64)
+		addr.VinVoutDbID = uint64(vinVoutDbID.Int64)
+		ids = append(ids, id)
+		addressRows = append(addressRows, &addr)
+	}
+	return ids, addressRows, rows.Err()
+}
@@ -21,9 +21,19 @@ const (
 	quickStatsTarget         = 250
 	deepStatsTarget          = 600
 	rescanLogBlockChunk      = 500
-	initialLoadSyncStatusMsg = "Syncing stake, base and auxiliary DBs..."
+	initialLoadSyncStatusMsg = "Syncing blocks into the auxiliary DB..."
+	stakeDBSyncStatusMsg     = "Connecting blocks to the stake database..."
 	voutsSyncStatusMsg       = "Syncing vouts table with spending info..."
 	addressesSyncStatusMsg   = "Syncing addresses table with spending info..."
+
+	// syncCheckpointInterval is the number of blocks stored between sync
+	// checkpoints. syncCheckpointsKept is the number of the most recent
+	// checkpoints kept in the sync_checkpoints table. syncVerifyDepth is the
+	// number of the best blocks that are checked against the node's main chain
+	// when resuming a sync.
+	syncCheckpointInterval = 1000
+	syncCheckpointsKept    = 10
+	syncVerifyDepth        = 6
 )
 
 // SyncChainDBAsync is like SyncChainDB except it also takes a result channel on
@@ -73,7 +83,7 @@ func (pgb *ChainDB) SyncChainDB(ctx context.Context, client rpcutils.MasterBlock
 	}
 
 	// Retrieve the best block in the database from the meta table.
-	lastBlock, err := pgb.HeightDB()
+	lastBlock, err := pgb.HeightDB(ctx)
 	if err != nil {
 		return -1, fmt.Errorf("RetrieveBestBlockHeight: %v", err)
 	}
@@ -81,6 +91,12 @@ func (pgb *ChainDB) SyncChainDB(ctx context.Context, client rpcutils.MasterBlock
 		log.Info("Tables are empty, starting fresh.")
 	}
 
+	// Verify the DB against the last sync checkpoint before resuming.
+	checkpoint, err := pgb.resumeSyncCheckpoint(ctx, lastBlock)
+	if err != nil {
+		return lastBlock, fmt.Errorf("failed to resume from sync checkpoint: %v", err)
+	}
+
 	// Remove indexes/constraints before an initial sync or when explicitly
 	// requested to reindex and update spending information in the addresses
 	// table.
@@ -117,13 +133,22 @@ func (pgb *ChainDB) SyncChainDB(ctx context.Context, client rpcutils.MasterBlock
 		}
 	}
 
+	// When the last sync was interrupted while updating spending info, the
+	// tables were already indexed and only the spending info update must be
+	// redone.
+	indexed := checkpoint != nil && checkpoint.Phase == dbtypes.SyncPhaseSpending &&
+		checkpoint.Height == lastBlock
+
 	// When IBD is not yet completed, force reindexing and update of full
 	// spending info in addresses table after block sync.
 	if !ibdComplete {
 		if lastBlock > -1 {
 			log.Warnf("Detected that initial sync was previously started but not completed!")
 		}
-		if !reindexing {
+		if indexed && !newIndexes {
+			log.Infof("Tables were indexed at the last sync checkpoint. Skipping reindexing.")
+			reindexing = false
+		} else if !reindexing {
 			reindexing = true
 			log.Warnf("Forcing table reindexing.")
 		}
@@ -155,7 +180,7 @@ func (pgb *ChainDB) SyncChainDB(ctx context.Context, client rpcutils.MasterBlock
 	if updateAllAddresses {
 		utxoFunc = RetrieveUTXOsByVinsJoin
 	}
-	utxos, err := utxoFunc(ctx, pgb.db)
+	utxos, err := utxoFunc(ctx, pgb.db, pgb.archive)
 	if err != nil {
 		return -1, fmt.Errorf("RetrieveUTXOs: %v", err)
 	}
@@ -209,6 +234,10 @@ func (pgb *ChainDB) SyncChainDB(ctx context.Context, client rpcutils.MasterBlock
 		Msg:   initialLoadSyncStatusMsg,
 		BarID: dbtypes.InitialDBLoad,
 	})
+	sendProgressUpdate(&dbtypes.ProgressBarLoad{
+		Msg:   stakeDBSyncStatusMsg,
+		BarID: dbtypes.StakeDBSync,
+	})
 	// Addresses table sync should only run if bulk update is enabled.
 	if updateAllAddresses {
 		sendProgressUpdate(&dbtypes.ProgressBarLoad{
@@ -270,13 +299,21 @@ func (pgb *ChainDB) SyncChainDB(ctx context.Context, client rpcutils.MasterBlock
 				if barLoad != nil {
 					timeTakenPerBlock := (time.Since(lastProgressUpdateTime).Seconds() /
 						float64(endRangeBlock-ib))
+					remaining := int64(timeTakenPerBlock * float64(nodeHeight-endRangeBlock))
 					sendProgressUpdate(&dbtypes.ProgressBarLoad{
 						From:      ib,
 						To:        nodeHeight,
-						Timestamp: int64(timeTakenPerBlock * float64(nodeHeight-endRangeBlock)),
+						Timestamp: remaining,
 						Msg:       initialLoadSyncStatusMsg,
 						BarID:     dbtypes.InitialDBLoad,
 					})
+					sendProgressUpdate(&dbtypes.ProgressBarLoad{
+						From:      stakeDBHeight,
+						To:        nodeHeight,
+						Timestamp: remaining,
+						Msg:       stakeDBSyncStatusMsg,
+						BarID:     dbtypes.StakeDBSync,
+					})
 					lastProgressUpdateTime = time.Now()
 				}
 			}
@@ -345,6 +382,14 @@ func (pgb *ChainDB) SyncChainDB(ctx context.Context, client rpcutils.MasterBlock
 		// Total transactions is the sum of regular and stake transactions.
 		totalTxs += int64(len(block.STransactions()) + len(block.Transactions()))
 
+		// Record a checkpoint at regular intervals for resuming the sync.
+		if ib%syncCheckpointInterval == 0 {
+			err = pgb.syncCheckpoint(ib, blockHash.String(), dbtypes.SyncPhaseBlocks)
+			if err != nil {
+				return ib, err
+			}
+		}
+
 		// Update explorer pages at intervals of 20 blocks if the update channel
 		// is active (non-nil and not closed).
 		if ib%20 == 0 && !updateAllAddresses {
@@ -366,7 +411,13 @@ func (pgb *ChainDB) SyncChainDB(ctx context.Context, client rpcutils.MasterBlock
 	// Signal the final height to any heightClients.
 	pgb.SignalHeight(uint32(nodeHeight))
 
-	// Signal the end of the initial load sync.
+	// Signal the end of the initial load and stake database syncs.
+	sendProgressUpdate(&dbtypes.ProgressBarLoad{
+		From:  stakeDBHeight,
+		To:    nodeHeight,
+		Msg:   stakeDBSyncStatusMsg,
+		BarID: dbtypes.StakeDBSync,
+	})
 	sendProgressUpdate(&dbtypes.ProgressBarLoad{
 		From:  nodeHeight,
 		To:    nodeHeight,
@@ -374,9 +425,22 @@ func (pgb *ChainDB) SyncChainDB(ctx context.Context, client rpcutils.MasterBlock
 		BarID: dbtypes.InitialDBLoad,
 	})
 
+	// Checkpoint each of the remaining sync phases at the best block.
+	bestHash, bestHeight := pgb.BestBlockStr()
+	setPhase := func(phase string) error {
+		if bestHeight < 0 {
+			return nil
+		}
+		return pgb.syncCheckpoint(bestHeight, bestHash, phase)
+	}
+
 	// Index and analyze tables.
 	var analyzed bool
 	if reindexing {
+		if err = setPhase(dbtypes.SyncPhaseIndexing); err != nil {
+			return nodeHeight, err
+		}
+
 		// To build indexes, there must NOT be duplicate rows in terms of the
 		// constraints defined by the unique indexes. Duplicate transactions,
 		// vins, and vouts can end up in the tables when identical transactions
@@ -427,6 +491,10 @@ func (pgb *ChainDB) SyncChainDB(ctx context.Context, client rpcutils.MasterBlock
 
 	// Batch update addresses table with spending info.
 	if updateAllAddresses {
+		if err = setPhase(dbtypes.SyncPhaseSpending); err != nil {
+			return nodeHeight, err
+		}
+
 		// Analyze vouts and transactions tables first.
 		if !analyzed {
 			log.Infof("Performing an ANALYZE(%d) on vins table...", deepStatsTarget)
@@ -504,6 +572,9 @@ func (pgb *ChainDB) SyncChainDB(ctx context.Context, client rpcutils.MasterBlock
 	if err = SetIBDComplete(pgb.db, true); err != nil {
 		return nodeHeight, fmt.Errorf("failed to set meta.ibd_complete: %v", err)
 	}
+	if err = setPhase(dbtypes.SyncPhaseComplete); err != nil {
+		return nodeHeight, err
+	}
 
 	if barLoad != nil {
 		barID := dbtypes.InitialDBLoad
@@ -519,7 +590,92 @@ func (pgb *ChainDB) SyncChainDB(ctx context.Context, client rpcutils.MasterBlock
 	log.Infof("Sync finished at height %d. Delta: %d blocks, %d transactions, %d ins, %d outs, %d addresses",
 		nodeHeight, nodeHeight-startHeight+1, totalTxs, totalVins, totalVouts, totalAddresses)
 
-	return nodeHeight, err
+	// Every failure above returns early, and a cancelled sync returns from the
+	// block loop, so only a completed sync is announced to listeners.
+	pgb.notifySyncComplete()
+
+	return nodeHeight, nil
+}
+
+// syncCheckpoint records a sync checkpoint at the given block.
+func (pgb *ChainDB) syncCheckpoint(height int64, hash, phase string) error {
+	err := InsertSyncCheckpoint(pgb.db, height, hash, phase, syncCheckpointsKept)
+	if err != nil {
+		return err
+	}
+	log.Debugf("Sync checkpoint (%s) at height %d.", phase, height)
+	return nil
+}
+
+// resumeSyncCheckpoint retrieves the last sync checkpoint and verifies that the
+// DB is consistent with it. The tables must have no fewer rows than at the
+// checkpoint, and the checkpoint block and the best few blocks must be in the
+// node's main chain. Checkpoints above the best block, such as after blocks are
+// purged, are first removed. A nil checkpoint is returned if there are none.
+func (pgb *ChainDB) resumeSyncCheckpoint(ctx context.Context, bestHeight int64) (*dbtypes.SyncCheckpoint, error) {
+	numRemoved, err := DeleteSyncCheckpointsAbove(pgb.db, bestHeight)
+	if err != nil {
+		return nil, err
+	}
+	if numRemoved > 0 {
+		log.Infof("Removed %d sync checkpoints above height %d.", numRemoved, bestHeight)
+	}
+
+	checkpoint, err := RetrieveSyncCheckpoint(ctx, pgb.db)
+	if err != nil || checkpoint == nil {
+		return nil, err
+	}
+	log.Infof("Last sync checkpoint (%s) at height %d, %v.", checkpoint.Phase,
+		checkpoint.Height, checkpoint.Time)
+
+	counts, err := retrieveSyncRowCounts(ctx, pgb.db)
+	if err != nil {
+		return nil, err
+	}
+	if counts.NumBlocks < checkpoint.NumBlocks ||
+		counts.NumTransactions < checkpoint.NumTransactions ||
+		counts.NumVins < checkpoint.NumVins ||
+		counts.NumVouts < checkpoint.NumVouts ||
+		counts.NumAddresses < checkpoint.NumAddresses {
+		return nil, fmt.Errorf("tables have fewer rows than at the checkpoint at height %d",
+			checkpoint.Height)
+	}
+
+	// Verify the checkpoint block, and the best blocks stored since.
+	heights := []int64{checkpoint.Height}
+	low := bestHeight - syncVerifyDepth + 1
+	if low <= checkpoint.Height {
+		low = checkpoint.Height + 1
+	}
+	for height := low; height <= bestHeight; height++ {
+		heights = append(heights, height)
+	}
+	for _, height := range heights {
+		dbHash, err := RetrieveBlockHash(ctx, pgb.db, height)
+		if err != nil {
+			return nil, fmt.Errorf("RetrieveBlockHash(%d): %v", height, err)
+		}
+		if height == checkpoint.Height && dbHash != checkpoint.Hash {
+			return nil, fmt.Errorf("block %s at height %d is not the checkpoint block %s. "+
+				"Restart with --purge-n-blocks=%d to recover", dbHash, height,
+				checkpoint.Hash, bestHeight-height+1)
+		}
+		if pgb.Client == nil {
+			continue
+		}
+		nodeHash, err := pgb.nodeBlockHash(height)
+		if err != nil {
+			return nil, err
+		}
+		if nodeHash.String() != dbHash {
+			return nil, fmt.Errorf("block %s at height %d is not in the node's main chain. "+
+				"Restart with --purge-n-blocks=%d to recover", dbHash, height,
+				bestHeight-height+1)
+		}
+	}
+	log.Infof("Verified %d blocks against the sync checkpoint.", len(heights))
+
+	return checkpoint, nil
 }
 
 func parseUnknownTicketError(err error) (hash *chainhash.Hash) {
@@ -3,6 +3,7 @@
 package dcrpg
 
 import (
+	"context"
 	"database/sql"
 	"testing"
 )
@@ -38,3 +39,24 @@ func TestRetrievePGVersion(t *testing.T) {
 	}
 	t.Logf("\n%s", ver)
 }
+
+func TestDBStats(t *testing.T) {
+	stats, err := db.DBStats(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to retrieve table statistics: %v", err)
+	}
+	if len(stats.Tables) == 0 {
+		t.Fatal("No table statistics retrieved.")
+	}
+	var total int64
+	for _, ts := range stats.Tables {
+		if ts.TotalBytes < ts.TableBytes+ts.IndexBytes {
+			t.Errorf("table %s total size %d is less than the table and index sizes %d + %d",
+				ts.Name, ts.TotalBytes, ts.TableBytes, ts.IndexBytes)
+		}
+		total += ts.TotalBytes
+	}
+	if total != stats.TotalBytes {
+		t.Errorf("total size %d, expected %d", stats.TotalBytes, total)
+	}
+}
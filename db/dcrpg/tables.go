@@ -5,11 +5,14 @@
 package dcrpg
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 
+	apitypes "github.com/decred/dcrdata/api/types/v5"
 	"github.com/decred/dcrdata/db/dbtypes/v2"
 	"github.com/decred/dcrdata/db/dcrpg/v5/internal"
+	"github.com/lib/pq"
 )
 
 var createTableStatements = [][2]string{
@@ -29,6 +32,15 @@ var createTableStatements = [][2]string{
 	{"proposals", internal.CreateProposalsTable},
 	{"proposal_votes", internal.CreateProposalVotesTable},
 	{"stats", internal.CreateStatsTable},
+	{"stake_diff_estimates", internal.CreateStakeDiffEstimatesTable},
+	{"daily_prices", internal.CreateDailyPricesTable},
+	{"script_anomalies", internal.CreateScriptAnomaliesTable},
+	{"block_anomalies", internal.CreateBlockAnomaliesTable},
+	{"winning_tickets", internal.CreateWinningTicketsTable},
+	{"mempool_backlog", internal.CreateMempoolBacklogTable},
+	{"utxo_distribution", internal.CreateUTXODistributionTable},
+	{"sync_checkpoints", internal.CreateSyncCheckpointsTable},
+	{"address_counts", internal.CreateAddressCountsTable},
 }
 
 func createTableMap() map[string]string {
@@ -69,6 +81,12 @@ func dropTable(db SqlExecutor, tableName string) error {
 
 // DropTables drops all of the tables internally recognized tables.
 func DropTables(db *sql.DB) {
+	// The archive tables inherit from the addresses and vouts tables.
+	log.Infof("DROPPING the archive schema.")
+	if err := DropArchive(db); err != nil {
+		log.Errorf("DROP SCHEMA archive; failed: %v", err)
+	}
+
 	lastIndex := len(createTableStatements) - 1
 	for i := range createTableStatements {
 		pair := createTableStatements[lastIndex-i]
@@ -109,6 +127,63 @@ func AnalyzeAllTables(db *sql.DB, statisticsTarget int) error {
 	return dbTx.Commit()
 }
 
+// Vacuum thresholds used by VacuumTables. A table is vacuumed when it has at
+// least vacuumMinDeadTuples dead tuples, and they are at least
+// vacuumDeadTupleFraction of its live tuples.
+const (
+	vacuumMinDeadTuples     = 10000
+	vacuumDeadTupleFraction = 0.1
+)
+
+// AnalyzeTables performs a quick ANALYZE on all tables. This is a no-op for
+// CockroachDB, which maintains its own table statistics.
+func (pgb *ChainDB) AnalyzeTables() error {
+	if pgb.cockroach {
+		return nil
+	}
+	return AnalyzeAllTables(pgb.db, quickStatsTarget)
+}
+
+// VacuumTables performs a VACUUM on the tables with a significant number of
+// dead tuples as reported by the statistics collector, returning the names of
+// the vacuumed tables. This is a no-op for CockroachDB.
+func (pgb *ChainDB) VacuumTables() ([]string, error) {
+	if pgb.cockroach {
+		return nil, nil
+	}
+
+	rows, err := pgb.db.Query(internal.SelectTablesNeedingVacuum,
+		vacuumMinDeadTuples, vacuumDeadTupleFraction)
+	if err != nil {
+		return nil, err
+	}
+
+	var tables []string
+	for rows.Next() {
+		var table string
+		if err = rows.Scan(&table); err != nil {
+			closeRows(rows)
+			return nil, err
+		}
+		tables = append(tables, table)
+	}
+	if err = rows.Err(); err != nil {
+		closeRows(rows)
+		return nil, err
+	}
+	closeRows(rows)
+
+	vacuumed := make([]string, 0, len(tables))
+	for _, table := range tables {
+		// VACUUM cannot run inside a transaction block.
+		if _, err = pgb.db.Exec(`VACUUM ` + pq.QuoteIdentifier(table) + `;`); err != nil {
+			return vacuumed, fmt.Errorf("failed to VACUUM table %s: %v", table, err)
+		}
+		vacuumed = append(vacuumed, table)
+	}
+	return vacuumed, nil
+}
+
 // AnalyzeTable performs an ANALYZE on the specified table after setting
 // default_statistics_target for the transaction.
 func AnalyzeTable(db *sql.DB, table string, statisticsTarget int) error {
@@ -310,3 +385,68 @@ func (pgb *ChainDB) DeleteDuplicatesRecovery(barLoad chan *dbtypes.ProgressBarLo
 	}
 	return nil
 }
+
+// retrieveTableStats retrieves the row count estimates, sizes, and last
+// VACUUM and ANALYZE times of the named tables, largest first. Tables that do
+// not exist are omitted.
+func retrieveTableStats(ctx context.Context, db *sql.DB, tables []string) ([]*apitypes.TableStats, error) {
+	rows, err := db.QueryContext(ctx, internal.SelectTableStats, pq.StringArray(tables))
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	var stats []*apitypes.TableStats
+	for rows.Next() {
+		var ts apitypes.TableStats
+		var lastVacuum, lastAnalyze pq.NullTime
+		err = rows.Scan(&ts.Name, &ts.Rows, &ts.DeadRows, &ts.TableBytes,
+			&ts.IndexBytes, &ts.TotalBytes, &lastVacuum, &lastAnalyze)
+		if err != nil {
+			return nil, err
+		}
+		if allRows := ts.Rows + ts.DeadRows; allRows > 0 {
+			ts.BloatFraction = float64(ts.DeadRows) / float64(allRows)
+			ts.BloatBytes = int64(ts.BloatFraction * float64(ts.TableBytes))
+		}
+		if lastVacuum.Valid {
+			ts.LastVacuum = lastVacuum.Time.Unix()
+		}
+		if lastAnalyze.Valid {
+			ts.LastAnalyze = lastAnalyze.Time.Unix()
+		}
+		stats = append(stats, &ts)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// DBStats reports the estimated row counts, the table and index sizes, the
+// dead row bloat, and the last VACUUM and ANALYZE times of the dcrdata tables.
+// The statistics collector views are not available with CockroachDB.
+func (pgb *ChainDB) DBStats(ctx context.Context) (*apitypes.DBStats, error) {
+	if pgb.cockroach {
+		return nil, fmt.Errorf("table statistics are not supported by CockroachDB")
+	}
+
+	tables := make([]string, 0, len(createTableStatements))
+	for _, pair := range createTableStatements {
+		tables = append(tables, pair[0])
+	}
+
+	ctx, cancel := pgb.queryContext(ctx)
+	defer cancel()
+
+	tableStats, err := retrieveTableStats(ctx, pgb.db, tables)
+	if err != nil {
+		return nil, pgb.replaceCancelError(err)
+	}
+
+	stats := &apitypes.DBStats{Tables: tableStats}
+	for _, ts := range tableStats {
+		stats.TotalBytes += ts.TotalBytes
+	}
+	return stats, nil
+}
@@ -0,0 +1,225 @@
+// Copyright (c) 2020, The Decred developers
+// See LICENSE for details.
+
+package dcrpg
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/decred/dcrd/blockchain/stake/v2"
+	"github.com/decred/dcrd/dcrutil/v2"
+	chainjson "github.com/decred/dcrd/rpc/jsonrpc/types/v2"
+	"github.com/decred/dcrd/wire"
+	apitypes "github.com/decred/dcrdata/api/types/v5"
+	"github.com/decred/dcrdata/txhelpers/v4"
+	"github.com/decred/dcrwallet/wallet/v3/txrules"
+)
+
+// maxStandardTxSize is the largest transaction, in bytes, accepted to the
+// mempool by dcrd's default policy.
+const maxStandardTxSize = 100000
+
+// ValidateRawTransaction checks whether the hex-encoded transaction would
+// likely be accepted to the node's mempool, without broadcasting it. Its inputs
+// are checked against the valid mainchain outputs in the DB, falling back to
+// the node for outputs of mempool transactions, and for conflicts with mempool
+// transactions. Its size, outputs, and fee are checked against dcrd's default
+// mempool policy. The reasons the transaction would be rejected, including
+// failure to decode it, are listed in the returned TxValidation, and an error
+// is only returned if the DB or node could not be queried.
+func (pgb *ChainDB) ValidateRawTransaction(ctx context.Context, txhex string) (*apitypes.TxValidation, error) {
+	v := new(apitypes.TxValidation)
+	msgTx, err := txhelpers.MsgTxFromHex(txhex)
+	if err != nil {
+		v.Reject("unable to decode transaction: %v", err)
+		return v, nil
+	}
+	v.TxID = msgTx.TxHash().String()
+	txType := stake.DetermineTxType(msgTx)
+	v.Type = txhelpers.TxTypeToString(int(txType))
+	v.Size = msgTx.SerializeSize()
+
+	if len(msgTx.TxIn) == 0 {
+		v.Reject("transaction has no inputs")
+	}
+	if len(msgTx.TxOut) == 0 {
+		v.Reject("transaction has no outputs")
+	}
+	if v.Size > maxStandardTxSize {
+		v.Reject("transaction size of %d bytes exceeds the maximum of %d bytes",
+			v.Size, maxStandardTxSize)
+	}
+	if txType == stake.TxTypeRegular && len(msgTx.TxIn) == 1 &&
+		msgTx.TxIn[0].PreviousOutPoint.Hash == zeroHash {
+		v.Reject("coinbase transactions are not relayed")
+		return v, nil
+	}
+	tipHeight := pgb.Height()
+	if msgTx.Expiry != wire.NoExpiryValue && tipHeight+1 >= int64(msgTx.Expiry) {
+		v.Reject("transaction expired at height %d", msgTx.Expiry)
+	}
+
+	ctx, cancel := pgb.queryContext(ctx)
+	defer cancel()
+
+	if pgb.MPC.HasTx(v.TxID) {
+		v.Reject("transaction is already in the mempool")
+	}
+	mined, err := RetrieveMainchainTxExists(ctx, pgb.db, v.TxID)
+	if err != nil {
+		return nil, pgb.replaceCancelError(err)
+	}
+	if mined {
+		v.Reject("transaction is already mined")
+	}
+
+	var totalOut int64
+	for i, out := range msgTx.TxOut {
+		if out.Value < 0 || out.Value > dcrutil.MaxAmount {
+			v.Reject("output %d has an invalid amount of %d atoms", i, out.Value)
+			continue
+		}
+		totalOut += out.Value
+		// Stake transaction outputs have their own script forms, so only
+		// regular outputs are checked for dust.
+		if txType == stake.TxTypeRegular && txrules.IsDustOutput(out, txrules.DefaultRelayFeePerKb) {
+			v.Reject("output %d is dust", i)
+		}
+	}
+
+	if txType == stake.TxTypeSStx {
+		if sbits, _ := pgb.MPC.SBits(); sbits > 0 && msgTx.TxOut[0].Value != sbits {
+			v.Reject("ticket price %v does not match the stake difficulty %v",
+				dcrutil.Amount(msgTx.TxOut[0].Value), dcrutil.Amount(sbits))
+		}
+	}
+	if txType == stake.TxTypeSSGen || txType == stake.TxTypeSSRtx {
+		v.Warn("whether the ticket may vote or be revoked is not checked")
+	}
+
+	var totalIn int64
+	inputsKnown := true
+	spent := make(map[wire.OutPoint]struct{}, len(msgTx.TxIn))
+	for i, in := range msgTx.TxIn {
+		// The stakebase input of a vote creates the vote reward.
+		if txType == stake.TxTypeSSGen && i == 0 {
+			totalIn += in.ValueIn
+			continue
+		}
+		prevOut := in.PreviousOutPoint
+		if _, found := spent[prevOut]; found {
+			v.Reject("input %d spends %v more than once", i, &prevOut)
+			continue
+		}
+		spent[prevOut] = struct{}{}
+
+		value, known, err := pgb.checkTxInput(ctx, v, &prevOut, txType, tipHeight)
+		if err != nil {
+			return nil, err
+		}
+		totalIn += value
+		inputsKnown = inputsKnown && known
+	}
+
+	// Votes and revocations are not subject to the minimum fee.
+	var minFee dcrutil.Amount
+	if txType == stake.TxTypeRegular || txType == stake.TxTypeSStx {
+		minFee = txrules.FeeForSerializeSize(txrules.DefaultRelayFeePerKb, v.Size)
+		v.MinFee = minFee.ToCoin()
+	}
+	if inputsKnown {
+		fee := dcrutil.Amount(totalIn - totalOut)
+		switch {
+		case fee < 0:
+			v.Reject("total output value %v exceeds total input value %v",
+				dcrutil.Amount(totalOut), dcrutil.Amount(totalIn))
+		case fee < minFee:
+			v.Reject("fee of %v is below the minimum relay fee of %v", fee, minFee)
+		case minFee > 0 && txrules.PaysHighFees(dcrutil.Amount(totalIn), msgTx):
+			v.Reject("fee of %v is absurdly high", fee)
+		}
+		if fee >= 0 {
+			v.Fee = fee.ToCoin()
+			v.FeeRate = (fee * 1000 / dcrutil.Amount(v.Size)).ToCoin()
+		}
+	} else {
+		v.Warn("the fee is unknown since some inputs are unknown")
+	}
+
+	v.Warn("signatures and scripts are not verified")
+	v.LikelyAccepted = len(v.RejectReasons) == 0
+	return v, nil
+}
+
+// checkTxInput checks that the previous outpoint spent by an input of a
+// transaction of the given type exists, is unspent on mainchain and in the
+// mempool, and is mature. Problems are recorded in v. The output's value is
+// returned if it is known.
+func (pgb *ChainDB) checkTxInput(ctx context.Context, v *apitypes.TxValidation,
+	prevOut *wire.OutPoint, txType stake.TxType, tipHeight int64) (int64, bool, error) {
+	prevHash := prevOut.Hash.String()
+	if spender := pgb.MPC.Spender(prevHash, prevOut.Index); spender != "" {
+		v.Reject("input %v is already spent by mempool transaction %s", prevOut, spender)
+	}
+
+	value, tree, fundingType, height, blockIndex, err :=
+		RetrieveMainchainOutpoint(ctx, pgb.db, prevHash, prevOut.Index)
+	if err == sql.ErrNoRows {
+		// The output may be created by a mempool transaction, or the DB may
+		// not have the block with the funding transaction yet.
+		var txOut *chainjson.GetTxOutResult
+		err = pgb.retryRPC(func() (err error) {
+			txOut, err = pgb.Client.GetTxOut(&prevOut.Hash, prevOut.Index, true)
+			return
+		})
+		if err != nil {
+			return 0, false, err
+		}
+		if txOut == nil {
+			v.Reject("input %v is missing or spent", prevOut)
+			return 0, false, nil
+		}
+		if txOut.Confirmations == 0 {
+			v.Warn("input %v spends an output of a mempool transaction", prevOut)
+		}
+		amt, err := dcrutil.NewAmount(txOut.Value)
+		if err != nil {
+			return 0, false, err
+		}
+		return int64(amt), true, nil
+	}
+	if err != nil {
+		return 0, false, pgb.replaceCancelError(err)
+	}
+
+	spender, err := RetrieveMainchainSpenderByTxOut(ctx, pgb.db, prevHash, prevOut.Index)
+	switch err {
+	case nil:
+		v.Reject("input %v is already spent by %s", prevOut, spender)
+	case sql.ErrNoRows:
+	default:
+		return 0, false, pgb.replaceCancelError(err)
+	}
+
+	var maturity int64
+	switch stake.TxType(fundingType) {
+	case stake.TxTypeRegular:
+		if tree == wire.TxTreeRegular && blockIndex == 0 {
+			maturity = int64(pgb.chainParams.CoinbaseMaturity)
+		}
+	case stake.TxTypeSSGen, stake.TxTypeSSRtx:
+		maturity = int64(pgb.chainParams.CoinbaseMaturity)
+	case stake.TxTypeSStx:
+		// Ticket change outputs. Tickets themselves are spent by votes and
+		// revocations, which are not checked.
+		if txType != stake.TxTypeSSGen && txType != stake.TxTypeSSRtx {
+			maturity = int64(pgb.chainParams.SStxChangeMaturity)
+		}
+	}
+	if confirmations := tipHeight - height + 1; confirmations < maturity {
+		v.Reject("input %v is immature, with %d of %d required confirmations",
+			prevOut, confirmations, maturity)
+	}
+	return value, true, nil
+}
@@ -9,9 +9,14 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"sync"
+	"time"
 
+	"github.com/decred/dcrd/blockchain/stake/v2"
 	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/chaincfg/v2"
 	"github.com/decred/dcrd/wire"
+	"github.com/decred/dcrdata/db/dbtypes/v2"
 	"github.com/decred/dcrdata/db/dcrpg/v5/internal"
 	"github.com/decred/dcrdata/stakedb/v3"
 	"github.com/decred/dcrdata/txhelpers/v4"
@@ -32,7 +37,7 @@ const (
 	// This includes changes such as creating tables, adding/deleting columns,
 	// adding/deleting indexes or any other operations that create, delete, or
 	// modify the definition of any database relation.
-	schemaVersion = 8
+	schemaVersion = 27
 
 	// maintVersion indicates when certain maintenance operations should be
 	// performed for the same compatVersion and schemaVersion. Such operations
@@ -42,6 +47,22 @@ const (
 	maintVersion = 0
 )
 
+const (
+	// upgradeBatchBlocks is the number of blocks retrieved from dcrd and
+	// updated in each database transaction by the upgrades that need the
+	// blocks, and upgradeRPCWorkers is the number of concurrent requests.
+	upgradeBatchBlocks = 500
+	upgradeRPCWorkers  = 8
+
+	// upgradeBatchTickets is the number of tickets table rows updated by each
+	// statement of the ticket commitments upgrade.
+	upgradeBatchTickets = 50000
+
+	// upgradeProgressInterval is the minimum time between progress reports of
+	// the long upgrades.
+	upgradeProgressInterval = 30 * time.Second
+)
+
 var (
 	targetDatabaseVersion = &DatabaseVersion{
 		compat: compatVersion,
@@ -171,15 +192,18 @@ type Upgrader struct {
 	db      *sql.DB
 	bg      BlockGetter
 	stakeDB *stakedb.StakeDatabase
+	params  *chaincfg.Params
 	ctx     context.Context
 }
 
 // NewUpgrader is a contructor for an Upgrader.
-func NewUpgrader(ctx context.Context, db *sql.DB, bg BlockGetter, stakeDB *stakedb.StakeDatabase) *Upgrader {
+func NewUpgrader(ctx context.Context, db *sql.DB, bg BlockGetter, stakeDB *stakedb.StakeDatabase,
+	params *chaincfg.Params) *Upgrader {
 	return &Upgrader{
 		db:      db,
 		bg:      bg,
 		stakeDB: stakeDB,
+		params:  params,
 		ctx:     ctx,
 	}
 }
@@ -376,7 +400,292 @@ func (u *Upgrader) compatVersion1Upgrades(current, target DatabaseVersion) (bool
 		fallthrough
 
 	case 8:
-		// Perform schema v8 maintenance.
+		err = u.upgrade180to190()
+		if err != nil {
+			return false, fmt.Errorf("failed to upgrade 1.8.0 to 1.9.0: %v", err)
+		}
+		current.schema++
+		if err = updateSchemaVersion(u.db, current.schema); err != nil {
+			return false, fmt.Errorf("failed to update schema version: %v", err)
+		}
+		current.maint = 0
+		if err = updateMaintVersion(u.db, current.maint); err != nil {
+			return false, fmt.Errorf("failed to update maintenance version: %v", err)
+		}
+		fallthrough
+
+	case 9:
+		err = u.upgrade190to1100()
+		if err != nil {
+			return false, fmt.Errorf("failed to upgrade 1.9.0 to 1.10.0: %v", err)
+		}
+		current.schema++
+		if err = updateSchemaVersion(u.db, current.schema); err != nil {
+			return false, fmt.Errorf("failed to update schema version: %v", err)
+		}
+		current.maint = 0
+		if err = updateMaintVersion(u.db, current.maint); err != nil {
+			return false, fmt.Errorf("failed to update maintenance version: %v", err)
+		}
+		fallthrough
+
+	case 10:
+		err = u.upgrade1100to1110()
+		if err != nil {
+			return false, fmt.Errorf("failed to upgrade 1.10.0 to 1.11.0: %v", err)
+		}
+		current.schema++
+		if err = updateSchemaVersion(u.db, current.schema); err != nil {
+			return false, fmt.Errorf("failed to update schema version: %v", err)
+		}
+		current.maint = 0
+		if err = updateMaintVersion(u.db, current.maint); err != nil {
+			return false, fmt.Errorf("failed to update maintenance version: %v", err)
+		}
+		fallthrough
+
+	case 11:
+		err = u.upgrade1110to1120()
+		if err != nil {
+			return false, fmt.Errorf("failed to upgrade 1.11.0 to 1.12.0: %v", err)
+		}
+		current.schema++
+		if err = updateSchemaVersion(u.db, current.schema); err != nil {
+			return false, fmt.Errorf("failed to update schema version: %v", err)
+		}
+		current.maint = 0
+		if err = updateMaintVersion(u.db, current.maint); err != nil {
+			return false, fmt.Errorf("failed to update maintenance version: %v", err)
+		}
+		fallthrough
+
+	case 12:
+		err = u.upgrade1120to1130()
+		if err != nil {
+			return false, fmt.Errorf("failed to upgrade 1.12.0 to 1.13.0: %v", err)
+		}
+		current.schema++
+		if err = updateSchemaVersion(u.db, current.schema); err != nil {
+			return false, fmt.Errorf("failed to update schema version: %v", err)
+		}
+		current.maint = 0
+		if err = updateMaintVersion(u.db, current.maint); err != nil {
+			return false, fmt.Errorf("failed to update maintenance version: %v", err)
+		}
+		fallthrough
+
+	case 13:
+		err = u.upgrade1130to1140()
+		if err != nil {
+			return false, fmt.Errorf("failed to upgrade 1.13.0 to 1.14.0: %v", err)
+		}
+		current.schema++
+		if err = updateSchemaVersion(u.db, current.schema); err != nil {
+			return false, fmt.Errorf("failed to update schema version: %v", err)
+		}
+		current.maint = 0
+		if err = updateMaintVersion(u.db, current.maint); err != nil {
+			return false, fmt.Errorf("failed to update maintenance version: %v", err)
+		}
+		fallthrough
+
+	case 14:
+		err = u.upgrade1140to1150()
+		if err != nil {
+			return false, fmt.Errorf("failed to upgrade 1.14.0 to 1.15.0: %v", err)
+		}
+		current.schema++
+		if err = updateSchemaVersion(u.db, current.schema); err != nil {
+			return false, fmt.Errorf("failed to update schema version: %v", err)
+		}
+		current.maint = 0
+		if err = updateMaintVersion(u.db, current.maint); err != nil {
+			return false, fmt.Errorf("failed to update maintenance version: %v", err)
+		}
+		fallthrough
+
+	case 15:
+		err = u.upgrade1150to1160()
+		if err != nil {
+			return false, fmt.Errorf("failed to upgrade 1.15.0 to 1.16.0: %v", err)
+		}
+		current.schema++
+		if err = updateSchemaVersion(u.db, current.schema); err != nil {
+			return false, fmt.Errorf("failed to update schema version: %v", err)
+		}
+		current.maint = 0
+		if err = updateMaintVersion(u.db, current.maint); err != nil {
+			return false, fmt.Errorf("failed to update maintenance version: %v", err)
+		}
+		fallthrough
+
+	case 16:
+		err = u.upgrade1160to1170()
+		if err != nil {
+			return false, fmt.Errorf("failed to upgrade 1.16.0 to 1.17.0: %v", err)
+		}
+		current.schema++
+		if err = updateSchemaVersion(u.db, current.schema); err != nil {
+			return false, fmt.Errorf("failed to update schema version: %v", err)
+		}
+		current.maint = 0
+		if err = updateMaintVersion(u.db, current.maint); err != nil {
+			return false, fmt.Errorf("failed to update maintenance version: %v", err)
+		}
+		fallthrough
+
+	case 17:
+		err = u.upgrade1170to1180()
+		if err != nil {
+			return false, fmt.Errorf("failed to upgrade 1.17.0 to 1.18.0: %v", err)
+		}
+		current.schema++
+		if err = updateSchemaVersion(u.db, current.schema); err != nil {
+			return false, fmt.Errorf("failed to update schema version: %v", err)
+		}
+		current.maint = 0
+		if err = updateMaintVersion(u.db, current.maint); err != nil {
+			return false, fmt.Errorf("failed to update maintenance version: %v", err)
+		}
+		fallthrough
+
+	case 18:
+		err = u.upgrade1180to1190()
+		if err != nil {
+			return false, fmt.Errorf("failed to upgrade 1.18.0 to 1.19.0: %v", err)
+		}
+		current.schema++
+		if err = updateSchemaVersion(u.db, current.schema); err != nil {
+			return false, fmt.Errorf("failed to update schema version: %v", err)
+		}
+		current.maint = 0
+		if err = updateMaintVersion(u.db, current.maint); err != nil {
+			return false, fmt.Errorf("failed to update maintenance version: %v", err)
+		}
+		fallthrough
+
+	case 19:
+		err = u.upgrade1190to1200()
+		if err != nil {
+			return false, fmt.Errorf("failed to upgrade 1.19.0 to 1.20.0: %v", err)
+		}
+		current.schema++
+		if err = updateSchemaVersion(u.db, current.schema); err != nil {
+			return false, fmt.Errorf("failed to update schema version: %v", err)
+		}
+		current.maint = 0
+		if err = updateMaintVersion(u.db, current.maint); err != nil {
+			return false, fmt.Errorf("failed to update maintenance version: %v", err)
+		}
+		fallthrough
+
+	case 20:
+		err = u.upgrade1200to1210()
+		if err != nil {
+			return false, fmt.Errorf("failed to upgrade 1.20.0 to 1.21.0: %v", err)
+		}
+		current.schema++
+		if err = updateSchemaVersion(u.db, current.schema); err != nil {
+			return false, fmt.Errorf("failed to update schema version: %v", err)
+		}
+		current.maint = 0
+		if err = updateMaintVersion(u.db, current.maint); err != nil {
+			return false, fmt.Errorf("failed to update maintenance version: %v", err)
+		}
+		fallthrough
+
+	case 21:
+		err = u.upgrade1210to1220()
+		if err != nil {
+			return false, fmt.Errorf("failed to upgrade 1.21.0 to 1.22.0: %v", err)
+		}
+		current.schema++
+		if err = updateSchemaVersion(u.db, current.schema); err != nil {
+			return false, fmt.Errorf("failed to update schema version: %v", err)
+		}
+		current.maint = 0
+		if err = updateMaintVersion(u.db, current.maint); err != nil {
+			return false, fmt.Errorf("failed to update maintenance version: %v", err)
+		}
+		fallthrough
+
+	case 22:
+		err = u.upgrade1220to1230()
+		if err != nil {
+			return false, fmt.Errorf("failed to upgrade 1.22.0 to 1.23.0: %v", err)
+		}
+		current.schema++
+		if err = updateSchemaVersion(u.db, current.schema); err != nil {
+			return false, fmt.Errorf("failed to update schema version: %v", err)
+		}
+		current.maint = 0
+		if err = updateMaintVersion(u.db, current.maint); err != nil {
+			return false, fmt.Errorf("failed to update maintenance version: %v", err)
+		}
+		fallthrough
+
+	case 23:
+		err = u.upgrade1230to1240()
+		if err != nil {
+			return false, fmt.Errorf("failed to upgrade 1.23.0 to 1.24.0: %v", err)
+		}
+		current.schema++
+		if err = updateSchemaVersion(u.db, current.schema); err != nil {
+			return false, fmt.Errorf("failed to update schema version: %v", err)
+		}
+		current.maint = 0
+		if err = updateMaintVersion(u.db, current.maint); err != nil {
+			return false, fmt.Errorf("failed to update maintenance version: %v", err)
+		}
+		fallthrough
+
+	case 24:
+		err = u.upgrade1240to1250()
+		if err != nil {
+			return false, fmt.Errorf("failed to upgrade 1.24.0 to 1.25.0: %v", err)
+		}
+		current.schema++
+		if err = updateSchemaVersion(u.db, current.schema); err != nil {
+			return false, fmt.Errorf("failed to update schema version: %v", err)
+		}
+		current.maint = 0
+		if err = updateMaintVersion(u.db, current.maint); err != nil {
+			return false, fmt.Errorf("failed to update maintenance version: %v", err)
+		}
+		fallthrough
+
+	case 25:
+		err = u.upgrade1250to1260()
+		if err != nil {
+			return false, fmt.Errorf("failed to upgrade 1.25.0 to 1.26.0: %v", err)
+		}
+		current.schema++
+		if err = updateSchemaVersion(u.db, current.schema); err != nil {
+			return false, fmt.Errorf("failed to update schema version: %v", err)
+		}
+		current.maint = 0
+		if err = updateMaintVersion(u.db, current.maint); err != nil {
+			return false, fmt.Errorf("failed to update maintenance version: %v", err)
+		}
+		fallthrough
+
+	case 26:
+		err = u.upgrade1260to1270()
+		if err != nil {
+			return false, fmt.Errorf("failed to upgrade 1.26.0 to 1.27.0: %v", err)
+		}
+		current.schema++
+		if err = updateSchemaVersion(u.db, current.schema); err != nil {
+			return false, fmt.Errorf("failed to update schema version: %v", err)
+		}
+		current.maint = 0
+		if err = updateMaintVersion(u.db, current.maint); err != nil {
+			return false, fmt.Errorf("failed to update maintenance version: %v", err)
+		}
+		fallthrough
+
+	case 27:
+		// Perform schema v27 maintenance.
 
 		// No further upgrades.
 		return upgradeCheck()
@@ -398,6 +707,513 @@ func removeTableComments(db *sql.DB) {
 	}
 }
 
+// This adds the ticket commitment columns to the tickets table, and sets them
+// by parsing the commitment outputs of every ticket in the vouts table.
+func (u *Upgrader) upgrade190to1100() error {
+	log.Infof("Performing database upgrade 1.9.0 -> 1.10.0")
+	_, err := u.db.Exec(`ALTER TABLE tickets
+		ADD COLUMN IF NOT EXISTS reward_addresses TEXT[],
+		ADD COLUMN IF NOT EXISTS commitment_amounts INT8[],
+		ADD COLUMN IF NOT EXISTS vote_fee_limits INT8[],
+		ADD COLUMN IF NOT EXISTS revoke_fee_limits INT8[];`)
+	if err != nil {
+		return fmt.Errorf("ALTER TABLE tickets error: %v", err)
+	}
+
+	if err = u.setTicketCommitments(); err != nil {
+		return err
+	}
+
+	log.Infof("Indexing tickets table on reward addresses...")
+	return IndexTicketsTableOnRewardAddresses(u.db)
+}
+
+// This adds the fee_rate column to the transactions table, and sets it from the
+// fees and size of each transaction. The fee rate is in atoms/kB, as computed
+// by txhelpers.FeeRate.
+func (u *Upgrader) upgrade1100to1110() error {
+	log.Infof("Performing database upgrade 1.10.0 -> 1.11.0")
+	_, err := u.db.Exec(`ALTER TABLE transactions
+		ADD COLUMN IF NOT EXISTS fee_rate INT8;`)
+	if err != nil {
+		return fmt.Errorf("ALTER TABLE transactions error: %v", err)
+	}
+
+	log.Infof("Setting transaction fee rates. This will take a while...")
+	N, err := sqlExec(u.db, `UPDATE transactions
+		SET fee_rate = CASE WHEN size > 0 THEN 1000 * fees / size ELSE -1 END;`,
+		"failed to set transactions.fee_rate: ")
+	if err != nil {
+		return err
+	}
+	log.Infof("Set the fee rate of %d transactions.", N)
+	return nil
+}
+
+// This creates the stake_diff_estimates table. Estimates are only recorded for
+// blocks stored after the upgrade since dcrd cannot estimate the stake
+// difficulty as of past blocks.
+func (u *Upgrader) upgrade1110to1120() error {
+	log.Infof("Performing database upgrade 1.11.0 -> 1.12.0")
+	return CreateTable(u.db, "stake_diff_estimates")
+}
+
+// This creates the daily_prices table. Prices are only recorded from the
+// exchange bot after the upgrade.
+func (u *Upgrader) upgrade1120to1130() error {
+	log.Infof("Performing database upgrade 1.12.0 -> 1.13.0")
+	return CreateTable(u.db, "daily_prices")
+}
+
+func (u *Upgrader) upgrade1130to1140() error {
+	// Index the coinbase transactions on block height for the lookups of miner
+	// payout addresses in a range of blocks.
+	log.Infof("Performing database upgrade 1.13.0 -> 1.14.0")
+	return IndexTransactionTableOnCoinbase(u.db)
+}
+
+func (u *Upgrader) upgrade1140to1150() error {
+	// Index the null data outputs on their payloads for searches by payload
+	// prefix.
+	log.Infof("Performing database upgrade 1.14.0 -> 1.15.0")
+	log.Infof("Indexing null data outputs. This may take a while...")
+	return IndexVoutTableOnNullData(u.db)
+}
+
+// This adds the pool_val_wavg column to the stats table, and sets it at every
+// height from the values and lifetimes of the main chain tickets in one UPDATE.
+func (u *Upgrader) upgrade1150to1160() error {
+	log.Infof("Performing database upgrade 1.15.0 -> 1.16.0")
+	_, err := u.db.Exec(`ALTER TABLE stats
+		ADD COLUMN IF NOT EXISTS pool_val_wavg INT8;`)
+	if err != nil {
+		return fmt.Errorf("ALTER TABLE stats error: %v", err)
+	}
+
+	log.Infof("Setting value-weighted average ticket pool values. This may take a while...")
+	N, err := sqlExec(u.db, internal.SetPoolValWavgFromTickets,
+		"failed to set pool_val_wavg: ", int64(u.params.TicketMaturity),
+		int64(u.params.TicketExpiry), int(dbtypes.TicketVoted))
+	if err != nil {
+		return err
+	}
+	log.Infof("Set pool_val_wavg in %d stats rows.", N)
+	return nil
+}
+
+// This creates the script_anomalies table, and records the outputs with
+// nonstandard scripts or script versions other than 0 that are already in the
+// vouts table.
+func (u *Upgrader) upgrade1160to1170() error {
+	log.Infof("Performing database upgrade 1.16.0 -> 1.17.0")
+	if err := CreateTable(u.db, "script_anomalies"); err != nil {
+		return err
+	}
+	log.Infof("Recording nonstandard and unusual version scripts. This may take a while...")
+	N, err := sqlExec(u.db, internal.InsertScriptAnomaliesFromVouts,
+		"failed to insert script anomalies: ")
+	if err != nil {
+		return err
+	}
+	log.Infof("Recorded %d outputs with nonstandard or unusual version scripts.", N)
+	return nil
+}
+
+// This creates the utxo_distribution table. The distribution is only recorded
+// by the utxodist maintenance task after the upgrade.
+func (u *Upgrader) upgrade1170to1180() error {
+	log.Infof("Performing database upgrade 1.17.0 -> 1.18.0")
+	return CreateTable(u.db, "utxo_distribution")
+}
+
+// This creates the sync_checkpoints table. The first checkpoint is recorded by
+// the next sync.
+func (u *Upgrader) upgrade1180to1190() error {
+	log.Infof("Performing database upgrade 1.18.0 -> 1.19.0")
+	return CreateTable(u.db, "sync_checkpoints")
+}
+
+// This adds the choices column to the votes table, and sets it by decoding the
+// vote bits of each distinct vote version and vote bits combination.
+func (u *Upgrader) upgrade1190to1200() error {
+	log.Infof("Performing database upgrade 1.19.0 -> 1.20.0")
+	_, err := u.db.Exec(`ALTER TABLE votes
+		ADD COLUMN IF NOT EXISTS choices JSONB;`)
+	if err != nil {
+		return fmt.Errorf("ALTER TABLE votes error: %v", err)
+	}
+
+	rows, err := u.db.Query(internal.SelectDistinctVoteBits)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var versions []int32
+	var voteBits []int32
+	var choices []string
+	for rows.Next() {
+		var version int32
+		var bits int16
+		if err = rows.Scan(&version, &bits); err != nil {
+			return err
+		}
+		vc, err := dbtypes.NewVoteChoices(txhelpers.VoteChoices(uint32(version),
+			uint16(bits), u.params)).Value()
+		if err != nil {
+			return err
+		}
+		versions = append(versions, version)
+		voteBits = append(voteBits, int32(bits))
+		choices = append(choices, vc.(string))
+	}
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	log.Infof("Setting the choices of all votes. This will take a while...")
+	N, err := sqlExec(u.db, internal.UpdateVoteChoices, "failed to set votes.choices: ",
+		pq.Array(versions), pq.Array(voteBits), pq.Array(choices))
+	if err != nil {
+		return err
+	}
+	log.Infof("Set the choices of %d votes.", N)
+	return nil
+}
+
+// This creates the address_counts table and counts the valid mainchain funding
+// and spending rows of every address.
+func (u *Upgrader) upgrade1200to1210() error {
+	log.Infof("Performing database upgrade 1.20.0 -> 1.21.0")
+	if err := CreateTable(u.db, "address_counts"); err != nil {
+		return err
+	}
+	log.Infof("Counting the transactions of all addresses. This will take a while...")
+	N, err := rebuildAddressCounts(u.db)
+	if err != nil {
+		return err
+	}
+	log.Infof("Counted the transactions of %d addresses.", N)
+	return nil
+}
+
+// This adds the final_state column to the blocks table. The final state is
+// only in the block headers, so it is set for the existing blocks in the
+// background by ChainDB.backfillFinalState rather than holding up the upgrade
+// with a dcrd call per block.
+func (u *Upgrader) upgrade1210to1220() error {
+	log.Infof("Performing database upgrade 1.21.0 -> 1.22.0")
+	_, err := u.db.Exec(`ALTER TABLE blocks
+		ADD COLUMN IF NOT EXISTS final_state TEXT;`)
+	if err != nil {
+		return fmt.Errorf("ALTER TABLE blocks error: %v", err)
+	}
+	return nil
+}
+
+// This creates the block_anomalies table. Anomalies are only recorded for the
+// blocks stored after the upgrade.
+func (u *Upgrader) upgrade1220to1230() error {
+	log.Infof("Performing database upgrade 1.22.0 -> 1.23.0")
+	return CreateTable(u.db, "block_anomalies")
+}
+
+// This creates the winning_tickets table, and fills it with the winners
+// recorded in the blocks table.
+func (u *Upgrader) upgrade1230to1240() error {
+	log.Infof("Performing database upgrade 1.23.0 -> 1.24.0")
+	if err := CreateTable(u.db, "winning_tickets"); err != nil {
+		return err
+	}
+	log.Infof("Recording the winning tickets of each block...")
+	N, err := sqlExec(u.db, internal.InsertAllWinningTickets,
+		"failed to insert winning tickets: ")
+	if err != nil {
+		return err
+	}
+	log.Infof("Recorded %d winning tickets.", N)
+	return nil
+}
+
+// This creates the mempool_backlog table. Backlogs are only recorded for the
+// blocks stored after the upgrade.
+func (u *Upgrader) upgrade1240to1250() error {
+	log.Infof("Performing database upgrade 1.24.0 -> 1.25.0")
+	return CreateTable(u.db, "mempool_backlog")
+}
+
+// This adds the sequence and sig_type columns to the vins table, and sets them
+// from the blocks retrieved from dcrd. The vins of side chain blocks that dcrd
+// does not know are left without a sequence and signature script type.
+func (u *Upgrader) upgrade1250to1260() error {
+	log.Infof("Performing database upgrade 1.25.0 -> 1.26.0")
+	_, err := u.db.Exec(`ALTER TABLE vins
+		ADD COLUMN IF NOT EXISTS sequence INT8,
+		ADD COLUMN IF NOT EXISTS sig_type INT2;`)
+	if err != nil {
+		return fmt.Errorf("ALTER TABLE vins error: %v", err)
+	}
+
+	hashes, err := u.blockHashes()
+	if err != nil {
+		return err
+	}
+	log.Infof("Setting the vin sequence numbers and signature script types of %d blocks "+
+		"from dcrd. This will take a while...", len(hashes))
+	return u.updateFromBlocks(hashes, "vin sequence numbers and signature script types",
+		func(dbTx *sql.Tx, blocks []*wire.MsgBlock) error {
+			var txHashes []string
+			var indexes, trees, sequences, sigTypes []int64
+			addVins := func(txs []*wire.MsgTx, tree int8) {
+				for _, tx := range txs {
+					txHash := tx.TxHash().String()
+					txType := stake.DetermineTxType(tx)
+					for idx, txin := range tx.TxIn {
+						txHashes = append(txHashes, txHash)
+						indexes = append(indexes, int64(idx))
+						trees = append(trees, int64(tree))
+						sequences = append(sequences, int64(txin.Sequence))
+						sigTypes = append(sigTypes, int64(dbtypes.ClassifyVinSig(tx, txType, idx)))
+					}
+				}
+			}
+			for _, msgBlock := range blocks {
+				addVins(msgBlock.Transactions, wire.TxTreeRegular)
+				addVins(msgBlock.STransactions, wire.TxTreeStake)
+			}
+			_, err := dbTx.ExecContext(u.ctx, internal.SetVinSigTypesAndSequences,
+				pq.StringArray(txHashes), pq.Int64Array(indexes), pq.Int64Array(trees),
+				pq.Int64Array(sequences), pq.Int64Array(sigTypes))
+			return err
+		})
+}
+
+// This adds the sig_script column to the vins table, for the signature scripts
+// of the inputs that spend no previous output. The consensus stakebase script
+// is set for the stakebase inputs, and the coinbase scripts are set from the
+// blocks retrieved from dcrd.
+func (u *Upgrader) upgrade1260to1270() error {
+	log.Infof("Performing database upgrade 1.26.0 -> 1.27.0")
+	_, err := u.db.Exec(`ALTER TABLE vins ADD COLUMN IF NOT EXISTS sig_script BYTEA;`)
+	if err != nil {
+		return fmt.Errorf("ALTER TABLE vins error: %v", err)
+	}
+
+	N, err := sqlExec(u.db, internal.SetStakebaseSigScripts,
+		"failed to set stakebase signature scripts: ", u.params.StakeBaseSigScript)
+	if err != nil {
+		return err
+	}
+	log.Infof("Set the signature scripts of %d stakebase inputs.", N)
+
+	hashes, err := u.blockHashes()
+	if err != nil {
+		return err
+	}
+	log.Infof("Setting the coinbase signature scripts of %d blocks from dcrd...",
+		len(hashes))
+	return u.updateFromBlocks(hashes, "coinbase signature scripts",
+		func(dbTx *sql.Tx, blocks []*wire.MsgBlock) error {
+			txHashes := make([]string, 0, len(blocks))
+			scripts := make([][]byte, 0, len(blocks))
+			for _, msgBlock := range blocks {
+				coinbase := msgBlock.Transactions[0]
+				txHashes = append(txHashes, coinbase.TxHash().String())
+				scripts = append(scripts, coinbase.TxIn[0].SignatureScript)
+			}
+			_, err := dbTx.ExecContext(u.ctx, internal.SetCoinbaseSigScripts,
+				pq.StringArray(txHashes), pq.ByteaArray(scripts))
+			return err
+		})
+}
+
+// blockHashes gets the hashes of all blocks, main and side chain, in height
+// order.
+func (u *Upgrader) blockHashes() ([]string, error) {
+	rows, err := u.db.QueryContext(u.ctx, `SELECT hash FROM blocks ORDER BY height;`)
+	if err != nil {
+		return nil, fmt.Errorf("block hash query error: %v", err)
+	}
+	defer closeRows(rows)
+	var hashes []string
+	for rows.Next() {
+		var hash string
+		if err = rows.Scan(&hash); err != nil {
+			return nil, fmt.Errorf("Scan failed: %v", err)
+		}
+		hashes = append(hashes, hash)
+	}
+	return hashes, rows.Err()
+}
+
+// updateFromBlocks retrieves the blocks with the given hashes from dcrd in
+// batches of upgradeBatchBlocks, with concurrent requests, and calls update with
+// the blocks of each batch in one database transaction, logging the progress
+// of the update described by what. The blocks that dcrd does not know, such as
+// orphaned side chain blocks, are skipped.
+func (u *Upgrader) updateFromBlocks(hashes []string, what string, update func(dbTx *sql.Tx, blocks []*wire.MsgBlock) error) error {
+	var numMissing int
+	lastReport := time.Now()
+	for start := 0; start < len(hashes); start += upgradeBatchBlocks {
+		if u.ctx.Err() != nil {
+			return fmt.Errorf("context cancelled")
+		}
+		end := start + upgradeBatchBlocks
+		if end > len(hashes) {
+			end = len(hashes)
+		}
+		blocks := u.getBlocks(hashes[start:end])
+		numMissing += end - start - len(blocks)
+
+		dbTx, err := u.db.BeginTx(u.ctx, nil)
+		if err != nil {
+			return fmt.Errorf("unable to begin database transaction: %v", err)
+		}
+		if err = update(dbTx, blocks); err != nil {
+			_ = dbTx.Rollback()
+			return fmt.Errorf("failed to set the %s: %v", what, err)
+		}
+		if err = dbTx.Commit(); err != nil {
+			return err
+		}
+
+		if time.Since(lastReport) > upgradeProgressInterval || end == len(hashes) {
+			lastReport = time.Now()
+			log.Infof("Set the %s of %d of %d blocks (%.1f%%).", what, end,
+				len(hashes), 100*float64(end)/float64(len(hashes)))
+		}
+	}
+	if numMissing > 0 {
+		log.Warnf("The %s of %d blocks unknown to dcrd were not set.", what, numMissing)
+	}
+	return nil
+}
+
+// getBlocks retrieves the blocks with the given hashes from dcrd with up to
+// upgradeRPCWorkers concurrent requests, in the order of the hashes. The blocks
+// that cannot be retrieved are omitted.
+func (u *Upgrader) getBlocks(hashes []string) []*wire.MsgBlock {
+	blocks := make([]*wire.MsgBlock, len(hashes))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < upgradeRPCWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				hash, err := chainhash.NewHashFromStr(hashes[i])
+				if err == nil {
+					blocks[i], err = u.bg.GetBlock(hash)
+				}
+				if err != nil {
+					log.Debugf("Unable to get block %s: %v", hashes[i], err)
+					blocks[i] = nil
+				}
+			}
+		}()
+	}
+	for i := range hashes {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	found := blocks[:0]
+	for _, msgBlock := range blocks {
+		if msgBlock != nil {
+			found = append(found, msgBlock)
+		}
+	}
+	return found
+}
+
+func (u *Upgrader) setTicketCommitments() error {
+	var maxID int64
+	err := u.db.QueryRow(`SELECT COALESCE(MAX(id), 0) FROM tickets;`).Scan(&maxID)
+	if err != nil {
+		return fmt.Errorf("tickets query error: %v", err)
+	}
+	if maxID == 0 {
+		return nil
+	}
+
+	log.Infof("Setting the ticket commitments. This will take a while...")
+	var numSet int64
+	lastReport := time.Now()
+	for start := int64(0); start <= maxID; start += upgradeBatchTickets {
+		if u.ctx.Err() != nil {
+			return fmt.Errorf("context cancelled")
+		}
+		end := start + upgradeBatchTickets
+		N, err := u.setTicketCommitmentsRange(start, end)
+		if err != nil {
+			return err
+		}
+		numSet += N
+
+		if time.Since(lastReport) > upgradeProgressInterval || end > maxID {
+			lastReport = time.Now()
+			done := end
+			if done > maxID {
+				done = maxID
+			}
+			log.Infof("Set the commitments of %d tickets (row %d of %d, %.1f%%).",
+				numSet, done, maxID, 100*float64(done)/float64(maxID))
+		}
+	}
+	return nil
+}
+
+// setTicketCommitmentsRange sets the commitments of the tickets with row ids in
+// the range [start, end), returning the number of rows updated.
+func (u *Upgrader) setTicketCommitmentsRange(start, end int64) (int64, error) {
+	rows, err := u.db.QueryContext(u.ctx, internal.SelectTicketCommitmentOutputsByIDRange,
+		start, end)
+	if err != nil {
+		return 0, fmt.Errorf("vouts query error: %v", err)
+	}
+
+	// The commitments are parsed here, since the reward addresses are encoded
+	// from the scripts, and then set with one UPDATE for the range.
+	var hashes, addrs []string
+	var amounts, voteLimits, revokeLimits []int64
+	for rows.Next() {
+		var hash string
+		var pkScript []byte
+		if err = rows.Scan(&hash, &pkScript); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("Scan failed: %v", err)
+		}
+		c, err := dbtypes.NewTicketCommitment(pkScript, u.params)
+		if err != nil {
+			log.Warnf("Unable to parse commitment of ticket %s: %v", hash, err)
+			continue
+		}
+		hashes = append(hashes, hash)
+		addrs = append(addrs, c.Address)
+		amounts = append(amounts, c.Amount)
+		voteLimits = append(voteLimits, c.VoteFeeLimit)
+		revokeLimits = append(revokeLimits, c.RevokeFeeLimit)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return 0, err
+	}
+	if len(hashes) == 0 {
+		return 0, nil
+	}
+
+	return sqlExec(u.db, internal.SetTicketCommitments, "failed to update tickets: ",
+		pq.StringArray(hashes), pq.StringArray(addrs), pq.Int64Array(amounts),
+		pq.Int64Array(voteLimits), pq.Int64Array(revokeLimits))
+}
+
+func (u *Upgrader) upgrade180to190() error {
+	// Index the stats table on blocks_id so that pool info and stake info
+	// lookups by block hash do not require a height lookup first.
+	return IndexStatsTableOnBlocksID(u.db)
+}
+
 func (u *Upgrader) upgrade170to180() error {
 	// Index the transactions table on block height. This drastically
 	// accelerates several queries including those for the following charts
@@ -737,7 +1553,8 @@ func (u *Upgrader) upgrade110to120() error {
 			return makeErr("PoolInfoBest error encountered")
 		}
 		// Insert rows.
-		_, err = statsStmt.Exec(id, height, poolInfo.Size, int64(poolInfo.Value*dcrToAtoms))
+		_, err = statsStmt.Exec(id, height, poolInfo.Size, int64(poolInfo.Value*dcrToAtoms),
+			int64(poolInfo.ValWeightedAvg*dcrToAtoms))
 		if err != nil {
 			return makeErr("insert Exec: %v", err)
 		}
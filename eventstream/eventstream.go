@@ -0,0 +1,421 @@
+// Copyright (c) 2020, The Decred developers
+// See LICENSE for details.
+
+// Package eventstream publishes JSON records of the blocks, transactions,
+// outputs and address balance changes stored by dcrdata to Kafka topics, for
+// replication to downstream consumers.
+package eventstream
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/decred/dcrdata/db/dbtypes/v2"
+	exptypes "github.com/decred/dcrdata/explorer/types/v2"
+	"github.com/decred/dcrdata/txhelpers/v4"
+	"github.com/segmentio/kafka-go"
+)
+
+const (
+	// DefaultTopicPrefix is the prefix of the topic names when none is
+	// specified. The topic of each table is the prefix and the table name,
+	// e.g. dcrdata.blocks.
+	DefaultTopicPrefix = "dcrdata."
+
+	// defaultQueueSize is the number of blocks that may be queued for
+	// publishing before further blocks are dropped, to be backfilled from the
+	// Source.
+	defaultQueueSize = 32
+
+	// retryDelay is the initial time between attempts to write a block's
+	// records. It doubles with each failed attempt, up to maxRetryDelay.
+	retryDelay    = 5 * time.Second
+	maxRetryDelay = 5 * time.Minute
+
+	// saveInterval is the minimum time between saves of the published height
+	// to the state file.
+	saveInterval = 10 * time.Second
+)
+
+// Writer writes messages to a single topic. *kafka.Writer is a Writer.
+type Writer interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+	Close() error
+}
+
+// Source loads the stored rows of main chain blocks, so that the blocks not
+// published because the queue was full, or because dcrdata was stopped, can be
+// backfilled. *dcrpg.ChainDB is a Source.
+type Source interface {
+	Height() int64
+	StoredBlockAt(ctx context.Context, height int64) (*dbtypes.StoredBlock, error)
+}
+
+// Config is the configuration of a Publisher. The height of the last published
+// main chain block is saved to StateFile, if set, and the blocks after it are
+// backfilled from the Source when the Publisher is restarted.
+type Config struct {
+	Brokers     []string
+	TopicPrefix string
+	QueueSize   int
+	Source      Source
+	StateFile   string
+}
+
+// queued is a block or chain event waiting to be written.
+type queued struct {
+	stored *dbtypes.StoredBlock
+	event  *ChainEvent
+}
+
+// state is the publishing progress saved to the state file.
+type state struct {
+	Height int64  `json:"height"`
+	Hash   string `json:"hash"`
+}
+
+// Publisher writes the records of the stored blocks to a topic for each table,
+// and the reorganization and disapproval events to the chain events topic.
+// Publish never blocks. Blocks published while the queue is full are dropped,
+// and backfilled from the Source along with the blocks stored while dcrdata
+// was stopped. Failed writes are retried until they succeed or the Publisher
+// is stopped, so each record is delivered at least once.
+type Publisher struct {
+	writers    map[string]Writer
+	source     Source
+	stateFile  string
+	queueSize  int
+	retryDelay time.Duration
+
+	mtx       sync.Mutex
+	queue     []queued
+	numBlocks int  // blocks in queue
+	dropped   bool // blocks were dropped since the last backfill
+	stopped   bool
+	signal    chan struct{}
+
+	last     state // last published main chain block
+	lastSave time.Time
+}
+
+// NewPublisher creates a Publisher for the Kafka brokers in the Config. An
+// empty TopicPrefix uses DefaultTopicPrefix, and a QueueSize of zero uses a
+// default of 32 blocks.
+func NewPublisher(cfg *Config) (*Publisher, error) {
+	if len(cfg.Brokers) == 0 {
+		return nil, fmt.Errorf("no kafka brokers specified")
+	}
+	if cfg.Source == nil {
+		return nil, fmt.Errorf("no source specified")
+	}
+	newWriter := func(topic string) Writer {
+		return kafka.NewWriter(kafka.WriterConfig{
+			Brokers:  cfg.Brokers,
+			Topic:    topic,
+			Balancer: &kafka.Hash{},
+		})
+	}
+	return newPublisher(newWriter, cfg), nil
+}
+
+func newPublisher(newWriter func(topic string) Writer, cfg *Config) *Publisher {
+	topicPrefix := cfg.TopicPrefix
+	if topicPrefix == "" {
+		topicPrefix = DefaultTopicPrefix
+	}
+	queueSize := cfg.QueueSize
+	if queueSize <= 0 {
+		queueSize = defaultQueueSize
+	}
+	writers := make(map[string]Writer, len(Tables)+1)
+	for _, table := range Tables {
+		writers[table] = newWriter(topicPrefix + table)
+	}
+	writers[TopicChainEvents] = newWriter(topicPrefix + TopicChainEvents)
+	return &Publisher{
+		writers:    writers,
+		source:     cfg.Source,
+		stateFile:  cfg.StateFile,
+		queueSize:  queueSize,
+		retryDelay: retryDelay,
+		signal:     make(chan struct{}, 1),
+		last:       state{Height: -1},
+	}
+}
+
+// Publish queues the stored rows of a block to be written by Run. Publish does
+// not block. When the queue is full, the block is dropped, and is backfilled
+// from the Source once the queue is drained if it is a main chain block. Blocks
+// published after Run returns are discarded.
+func (p *Publisher) Publish(stored *dbtypes.StoredBlock) {
+	p.mtx.Lock()
+	switch {
+	case p.stopped:
+		log.Debugf("Not publishing block %s. Event stream is stopped.",
+			stored.Block.Hash)
+	case p.numBlocks >= p.queueSize:
+		if !p.dropped {
+			log.Warnf("Event stream queue is full. Dropping block %s (%d) "+
+				"to be backfilled later.", stored.Block.Hash, stored.Block.Height)
+		}
+		p.dropped = true
+	default:
+		p.queue = append(p.queue, queued{stored: stored})
+		p.numBlocks++
+	}
+	p.mtx.Unlock()
+	p.notify()
+}
+
+// PublishReorg queues a chain reorganization event to be written by Run, after
+// the blocks already queued. Events are never dropped.
+func (p *Publisher) PublishReorg(reorg *txhelpers.ReorgData) {
+	p.publishEvent(reorgEvent(reorg))
+}
+
+// PublishDisapproval queues the event of a block's disapproval by the votes of
+// the next block to be written by Run, after the blocks already queued. Events
+// are never dropped.
+func (p *Publisher) PublishDisapproval(inv *exptypes.BlockInvalidation) {
+	p.publishEvent(disapprovalEvent(inv))
+}
+
+func (p *Publisher) publishEvent(event *ChainEvent) {
+	p.mtx.Lock()
+	if !p.stopped {
+		p.queue = append(p.queue, queued{event: event})
+	}
+	p.mtx.Unlock()
+	p.notify()
+}
+
+// notify signals Run that there is work without blocking.
+func (p *Publisher) notify() {
+	select {
+	case p.signal <- struct{}{}:
+	default:
+	}
+}
+
+// take removes the queued blocks and events, and reports if any blocks were
+// dropped since the last call.
+func (p *Publisher) take() (queue []queued, dropped bool) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	queue, dropped = p.queue, p.dropped
+	p.queue, p.numBlocks, p.dropped = nil, 0, false
+	return
+}
+
+// Run writes the records of the queued blocks and events until the context is
+// canceled, then saves the published height and closes the writers. The main
+// chain blocks after the saved height are backfilled from the Source first.
+// Blocks still queued when Run returns are written on the next run by
+// backfill, but queued events are not.
+func (p *Publisher) Run(ctx context.Context) {
+	defer func() {
+		p.mtx.Lock()
+		p.stopped = true
+		p.queue = nil
+		p.mtx.Unlock()
+		p.save(true)
+		for table, w := range p.writers {
+			if err := w.Close(); err != nil {
+				log.Errorf("Failed to close %s writer: %v", table, err)
+			}
+		}
+	}()
+
+	p.load()
+	if p.last.Height >= 0 {
+		log.Infof("Resuming event stream after block %s (%d).", p.last.Hash,
+			p.last.Height)
+		if err := p.backfill(ctx, p.source.Height()); err != nil {
+			if ctx.Err() == nil {
+				log.Errorf("Failed to backfill the event stream: %v", err)
+			}
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-p.signal:
+		}
+
+		queue, dropped := p.take()
+		for _, q := range queue {
+			var err error
+			if q.event != nil {
+				err = p.writeEvent(ctx, q.event)
+			} else {
+				err = p.writeBlock(ctx, q.stored)
+			}
+			if ctx.Err() != nil {
+				return
+			}
+			if err != nil {
+				log.Errorf("Failed to publish: %v", err)
+			}
+		}
+		if dropped {
+			if err := p.backfill(ctx, p.source.Height()); err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				log.Errorf("Failed to backfill the event stream: %v", err)
+			}
+		}
+	}
+}
+
+// backfill writes the records of the main chain blocks after the last
+// published block, up to and including height, loaded from the Source.
+func (p *Publisher) backfill(ctx context.Context, height int64) error {
+	if p.last.Height < 0 || height <= p.last.Height {
+		return nil
+	}
+	log.Infof("Backfilling the event stream with blocks [%d,%d].",
+		p.last.Height+1, height)
+	for h := p.last.Height + 1; h <= height; h++ {
+		stored, err := p.source.StoredBlockAt(ctx, h)
+		if err != nil {
+			return fmt.Errorf("failed to load block %d: %v", h, err)
+		}
+		if err = p.write(ctx, stored); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeBlock writes the records of a queued block, after backfilling any main
+// chain blocks between the last published block and it. A main chain block at
+// or below the last published height, as from a reorganization, is written
+// without a backfill.
+func (p *Publisher) writeBlock(ctx context.Context, stored *dbtypes.StoredBlock) error {
+	if stored.IsMainchain {
+		if err := p.backfill(ctx, int64(stored.Block.Height)-1); err != nil {
+			return err
+		}
+	}
+	return p.write(ctx, stored)
+}
+
+// write writes the records of a block to each table's topic, retrying failed
+// writes until the context is canceled, and records a main chain block as the
+// last published block.
+func (p *Publisher) write(ctx context.Context, stored *dbtypes.StoredBlock) error {
+	msgs, err := Messages(stored)
+	if err != nil {
+		return err
+	}
+
+	for _, table := range Tables {
+		if len(msgs[table]) == 0 {
+			continue
+		}
+		what := fmt.Sprintf("%d %s records of block %s", len(msgs[table]),
+			table, stored.Block.Hash)
+		if err = p.writeRetry(ctx, table, what, msgs[table]); err != nil {
+			return err
+		}
+	}
+
+	log.Debugf("Published the records of block %s (%d).", stored.Block.Hash,
+		stored.Block.Height)
+	if stored.IsMainchain {
+		p.last = state{Height: int64(stored.Block.Height), Hash: stored.Block.Hash}
+		p.save(false)
+	}
+	return nil
+}
+
+// writeEvent writes a chain event record to the chain events topic, retrying
+// failed writes until the context is canceled.
+func (p *Publisher) writeEvent(ctx context.Context, event *ChainEvent) error {
+	msg, err := EventMessage(event)
+	if err != nil {
+		return err
+	}
+	what := fmt.Sprintf("%s event of block %s", event.Event, event.Hash)
+	if err = p.writeRetry(ctx, TopicChainEvents, what, []kafka.Message{msg}); err != nil {
+		return err
+	}
+	log.Debugf("Published the %s.", what)
+	return nil
+}
+
+// writeRetry writes the messages to the topic, retrying with an increasing
+// delay until the write succeeds or the context is canceled.
+func (p *Publisher) writeRetry(ctx context.Context, topic, what string, msgs []kafka.Message) error {
+	delay := p.retryDelay
+	for attempt := 1; ; attempt++ {
+		err := p.writers[topic].WriteMessages(ctx, msgs...)
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		log.Warnf("Failed to write %s (attempt %d): %v", what, attempt, err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+		if delay *= 2; delay > maxRetryDelay {
+			delay = maxRetryDelay
+		}
+	}
+}
+
+// load reads the last published block from the state file, if there is one.
+func (p *Publisher) load() {
+	if p.stateFile == "" {
+		return
+	}
+	b, err := ioutil.ReadFile(p.stateFile)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Errorf("Failed to read event stream state: %v", err)
+		}
+		return
+	}
+	var st state
+	if err = json.Unmarshal(b, &st); err != nil {
+		log.Errorf("Failed to decode event stream state %s: %v", p.stateFile, err)
+		return
+	}
+	p.last = st
+}
+
+// save writes the last published block to the state file, at most once every
+// saveInterval unless force is true.
+func (p *Publisher) save(force bool) {
+	if p.stateFile == "" || p.last.Height < 0 {
+		return
+	}
+	if !force && time.Since(p.lastSave) < saveInterval {
+		return
+	}
+	p.lastSave = time.Now()
+	b, err := json.Marshal(&p.last)
+	if err != nil {
+		log.Errorf("Failed to encode event stream state: %v", err)
+		return
+	}
+	tmp := p.stateFile + ".tmp"
+	if err = ioutil.WriteFile(tmp, b, 0644); err == nil {
+		err = os.Rename(tmp, p.stateFile)
+	}
+	if err != nil {
+		log.Errorf("Failed to save event stream state: %v", err)
+	}
+}
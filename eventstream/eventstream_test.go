@@ -0,0 +1,303 @@
+package eventstream
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrdata/db/dbtypes/v2"
+	exptypes "github.com/decred/dcrdata/explorer/types/v2"
+	"github.com/segmentio/kafka-go"
+)
+
+type written struct {
+	topic string
+	key   string
+}
+
+type testWriters struct {
+	mtx      sync.Mutex
+	written  []written
+	failures int // number of writes to fail
+}
+
+type testWriter struct {
+	topic   string
+	writers *testWriters
+}
+
+func (w *testWriter) WriteMessages(_ context.Context, msgs ...kafka.Message) error {
+	w.writers.mtx.Lock()
+	defer w.writers.mtx.Unlock()
+	if w.writers.failures > 0 {
+		w.writers.failures--
+		return errors.New("broker unavailable")
+	}
+	for _, msg := range msgs {
+		w.writers.written = append(w.writers.written, written{w.topic, string(msg.Key)})
+	}
+	return nil
+}
+
+func (w *testWriter) Close() error { return nil }
+
+func testStoredBlock() *dbtypes.StoredBlock {
+	return &dbtypes.StoredBlock{
+		Block: &dbtypes.Block{
+			Hash:   "b1",
+			Height: 100,
+		},
+		BlockDbID:   7,
+		IsValid:     true,
+		IsMainchain: true,
+		Txns: []*dbtypes.Tx{{
+			TxID:      "t1",
+			BlockHash: "b1",
+			Vouts: []*dbtypes.Vout{
+				{TxHash: "t1", TxIndex: 0, Value: 5},
+				{TxHash: "t1", TxIndex: 1, Value: 6},
+			},
+			VoutDbIds:        []uint64{21, 22},
+			IsValid:          true,
+			IsMainchainBlock: true,
+		}},
+		TxDbIDs: []uint64{11},
+		AddressRows: []*dbtypes.AddressRow{
+			{Address: "Dsa", TxHash: "t1", IsFunding: true, Value: 5, VinVoutDbID: 21},
+			{Address: "Dsb", TxHash: "t1", MatchingTxHash: "t0", Value: 9, VinVoutDbID: 31},
+		},
+		AddressDbIDs: []uint64{41, 42},
+	}
+}
+
+func TestMessages(t *testing.T) {
+	msgs, err := Messages(testStoredBlock())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantKeys := map[string][]string{
+		TableTransactions: {"b1:transactions:11"},
+		TableVouts:        {"b1:vouts:21", "b1:vouts:22"},
+		TableAddresses:    {"b1:addresses:41", "b1:addresses:42"},
+		TableBlocks:       {"b1:blocks:7"},
+	}
+	for table, keys := range wantKeys {
+		if len(msgs[table]) != len(keys) {
+			t.Fatalf("got %d %s messages, expected %d", len(msgs[table]), table, len(keys))
+		}
+		for i, key := range keys {
+			if string(msgs[table][i].Key) != key {
+				t.Errorf("%s message %d key %s, expected %s", table, i, msgs[table][i].Key, key)
+			}
+		}
+	}
+
+	var vout Vout
+	if err = json.Unmarshal(msgs[TableVouts][1].Value, &vout); err != nil {
+		t.Fatal(err)
+	}
+	if vout.TxRowID != 11 || vout.TxIndex != 1 || vout.Value != 6 {
+		t.Errorf("unexpected vout record %+v", vout)
+	}
+
+	var spend AddressDelta
+	if err = json.Unmarshal(msgs[TableAddresses][1].Value, &spend); err != nil {
+		t.Fatal(err)
+	}
+	if spend.IsFunding || spend.Value != -9 || spend.BlockHash != "b1" {
+		t.Errorf("unexpected spending address record %+v", spend)
+	}
+}
+
+func TestPublisher(t *testing.T) {
+	writers := &testWriters{failures: 2}
+	p := newTestPublisher(writers, &Config{QueueSize: 1})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	runDone := make(chan struct{})
+	go func() {
+		p.Run(ctx)
+		close(runDone)
+	}()
+
+	p.Publish(testStoredBlock())
+
+	// Wait for the block record, which is written last.
+	waitWritten(t, writers, 6)
+
+	cancel()
+	<-runDone
+
+	wantTopics := []string{"dcrdata.transactions", "dcrdata.vouts", "dcrdata.vouts",
+		"dcrdata.addresses", "dcrdata.addresses", "dcrdata.blocks"}
+	for i, w := range writers.written {
+		if w.topic != wantTopics[i] {
+			t.Errorf("record %d (%s) written to %s, expected %s", i, w.key,
+				w.topic, wantTopics[i])
+		}
+	}
+
+	// Publishing after Run returns must not block.
+	p.Publish(testStoredBlock())
+	p.Publish(testStoredBlock())
+}
+
+func newTestPublisher(writers *testWriters, cfg *Config) *Publisher {
+	p := newPublisher(func(topic string) Writer {
+		return &testWriter{topic, writers}
+	}, cfg)
+	p.retryDelay = time.Millisecond
+	return p
+}
+
+// waitWritten waits for n records to be written.
+func waitWritten(t *testing.T, writers *testWriters, n int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		writers.mtx.Lock()
+		written := len(writers.written)
+		writers.mtx.Unlock()
+		if written == n {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("wrote %d records, expected %d", written, n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// testSource is a Source of main chain blocks with no transactions.
+type testSource struct {
+	height int64
+}
+
+func (s *testSource) Height() int64 {
+	return s.height
+}
+
+func (s *testSource) StoredBlockAt(_ context.Context, height int64) (*dbtypes.StoredBlock, error) {
+	if height > s.height {
+		return nil, errors.New("no block")
+	}
+	return testBlockAt(height), nil
+}
+
+func testBlockAt(height int64) *dbtypes.StoredBlock {
+	return &dbtypes.StoredBlock{
+		Block: &dbtypes.Block{
+			Hash:   fmt.Sprintf("h%d", height),
+			Height: uint32(height),
+		},
+		BlockDbID:   uint64(height),
+		IsValid:     true,
+		IsMainchain: true,
+	}
+}
+
+func TestPublisherBackfill(t *testing.T) {
+	writers := new(testWriters)
+	p := newTestPublisher(writers, &Config{
+		QueueSize: 1,
+		Source:    &testSource{height: 4},
+	})
+
+	// Block 1 is queued, and the rest are dropped without blocking.
+	for h := int64(1); h <= 4; h++ {
+		p.Publish(testBlockAt(h))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	runDone := make(chan struct{})
+	go func() {
+		p.Run(ctx)
+		close(runDone)
+	}()
+	waitWritten(t, writers, 4)
+	cancel()
+	<-runDone
+
+	for i, w := range writers.written {
+		want := fmt.Sprintf("h%d:blocks:%d", i+1, i+1)
+		if w.key != want {
+			t.Errorf("record %d key %s, expected %s", i, w.key, want)
+		}
+	}
+}
+
+func TestPublisherResume(t *testing.T) {
+	dir, err := ioutil.TempDir("", "eventstream")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	stateFile := filepath.Join(dir, "eventstream.json")
+	if err = ioutil.WriteFile(stateFile, []byte(`{"height":2,"hash":"h2"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	writers := new(testWriters)
+	p := newTestPublisher(writers, &Config{
+		Source:    &testSource{height: 4},
+		StateFile: stateFile,
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	runDone := make(chan struct{})
+	go func() {
+		p.Run(ctx)
+		close(runDone)
+	}()
+	waitWritten(t, writers, 2)
+	cancel()
+	<-runDone
+
+	if writers.written[0].key != "h3:blocks:3" || writers.written[1].key != "h4:blocks:4" {
+		t.Errorf("unexpected backfilled records %v", writers.written)
+	}
+	b, err := ioutil.ReadFile(stateFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var st state
+	if err = json.Unmarshal(b, &st); err != nil {
+		t.Fatal(err)
+	}
+	if st.Height != 4 || st.Hash != "h4" {
+		t.Errorf("saved state %+v, expected height 4", st)
+	}
+}
+
+func TestPublishDisapproval(t *testing.T) {
+	writers := new(testWriters)
+	p := newTestPublisher(writers, &Config{})
+	ctx, cancel := context.WithCancel(context.Background())
+	runDone := make(chan struct{})
+	go func() {
+		p.Run(ctx)
+		close(runDone)
+	}()
+
+	p.PublishDisapproval(&exptypes.BlockInvalidation{
+		Hash:          "h5",
+		Height:        5,
+		InvalidatedBy: "h6",
+		Transactions:  []string{"t1"},
+	})
+	waitWritten(t, writers, 1)
+	cancel()
+	<-runDone
+
+	w := writers.written[0]
+	if w.topic != "dcrdata.chain_events" || w.key != "disapproval:h5" {
+		t.Errorf("event written to %s with key %s", w.topic, w.key)
+	}
+}
@@ -0,0 +1,318 @@
+// Copyright (c) 2020, The Decred developers
+// See LICENSE for details.
+
+package eventstream
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrdata/db/dbtypes/v2"
+	exptypes "github.com/decred/dcrdata/explorer/types/v2"
+	"github.com/decred/dcrdata/txhelpers/v4"
+	"github.com/segmentio/kafka-go"
+)
+
+// The tables of the records, which name the topics to which they are written.
+// Each record is keyed by block hash, table and row ID.
+const (
+	TableBlocks       = "blocks"
+	TableTransactions = "transactions"
+	TableVouts        = "vouts"
+	TableAddresses    = "addresses"
+)
+
+// Tables lists the tables in the order their records are written for each
+// block. The block record is written last, after all of its rows.
+var Tables = []string{TableTransactions, TableVouts, TableAddresses, TableBlocks}
+
+// TopicChainEvents names the topic of the chain event records, which follows
+// the topic prefix like the table names.
+const TopicChainEvents = "chain_events"
+
+// The types of the chain event records.
+const (
+	EventReorg       = "reorg"
+	EventDisapproval = "disapproval"
+)
+
+// ChainEvent is the record of a chain reorganization, or of the disapproval of
+// a block by the votes in the next block. The records of the blocks of a reorg's
+// OldChain, which were published when the blocks were stored, are no longer
+// main chain. The regular transactions of a disapproved block are no longer
+// valid. For a reorg, Hash and Height are the new chain head, and for a
+// disapproval they are the disapproved block.
+type ChainEvent struct {
+	Event  string `json:"event"`
+	Hash   string `json:"hash"`
+	Height int64  `json:"height"`
+	// Reorgs
+	CommonAncestor string   `json:"common_ancestor,omitempty"`
+	OldChain       []string `json:"old_chain,omitempty"`
+	NewChain       []string `json:"new_chain,omitempty"`
+	// Disapprovals
+	DisapprovedBy string   `json:"disapproved_by,omitempty"`
+	Transactions  []string `json:"transactions,omitempty"`
+}
+
+func reorgEvent(reorg *txhelpers.ReorgData) *ChainEvent {
+	hashes := func(chain []chainhash.Hash) []string {
+		strs := make([]string, 0, len(chain))
+		for i := range chain {
+			strs = append(strs, chain[i].String())
+		}
+		return strs
+	}
+	return &ChainEvent{
+		Event:          EventReorg,
+		Hash:           reorg.NewChainHead.String(),
+		Height:         int64(reorg.NewChainHeight),
+		CommonAncestor: reorg.CommonAncestor.String(),
+		OldChain:       hashes(reorg.OldChain),
+		NewChain:       hashes(reorg.NewChain),
+	}
+}
+
+func disapprovalEvent(inv *exptypes.BlockInvalidation) *ChainEvent {
+	return &ChainEvent{
+		Event:         EventDisapproval,
+		Hash:          inv.Hash,
+		Height:        inv.Height,
+		DisapprovedBy: inv.InvalidatedBy,
+		Transactions:  inv.Transactions,
+	}
+}
+
+// EventMessage creates the JSON encoded record of a chain event, keyed by the
+// event type and block hash, e.g. reorg:<hash>.
+func EventMessage(event *ChainEvent) (kafka.Message, error) {
+	value, err := json.Marshal(event)
+	if err != nil {
+		return kafka.Message{}, fmt.Errorf("failed to encode %s event: %v",
+			event.Event, err)
+	}
+	return kafka.Message{
+		Key:   []byte(event.Event + ":" + event.Hash),
+		Value: value,
+	}, nil
+}
+
+// Block is the record of a blocks table row.
+type Block struct {
+	RowID        uint64  `json:"row_id"`
+	Hash         string  `json:"hash"`
+	Height       uint32  `json:"height"`
+	Time         int64   `json:"time"`
+	Version      uint32  `json:"version"`
+	Size         uint32  `json:"size"`
+	NumTx        uint32  `json:"num_tx"`
+	NumStakeTx   uint32  `json:"num_stake_tx"`
+	PreviousHash string  `json:"previous_hash"`
+	VoteBits     uint16  `json:"vote_bits"`
+	Voters       uint16  `json:"voters"`
+	FreshStake   uint8   `json:"fresh_stake"`
+	Revocations  uint8   `json:"revocations"`
+	PoolSize     uint32  `json:"pool_size"`
+	Bits         uint32  `json:"bits"`
+	SBits        uint64  `json:"sbits"`
+	Difficulty   float64 `json:"difficulty"`
+	StakeVersion uint32  `json:"stake_version"`
+	ChainWork    string  `json:"chain_work"`
+	IsValid      bool    `json:"is_valid"`
+	IsMainchain  bool    `json:"is_mainchain"`
+}
+
+// Tx is the record of a transactions table row.
+type Tx struct {
+	RowID       uint64 `json:"row_id"`
+	TxHash      string `json:"tx_hash"`
+	BlockHash   string `json:"block_hash"`
+	BlockHeight int64  `json:"block_height"`
+	BlockTime   int64  `json:"block_time"`
+	BlockIndex  uint32 `json:"block_index"`
+	Tree        int8   `json:"tree"`
+	TxType      int16  `json:"tx_type"`
+	Version     uint16 `json:"version"`
+	Locktime    uint32 `json:"locktime"`
+	Expiry      uint32 `json:"expiry"`
+	Size        uint32 `json:"size"`
+	Spent       int64  `json:"spent"`
+	Sent        int64  `json:"sent"`
+	Fees        int64  `json:"fees"`
+	MixCount    int32  `json:"mix_count"`
+	MixDenom    int64  `json:"mix_denom"`
+	NumVin      uint32 `json:"num_vin"`
+	NumVout     uint32 `json:"num_vout"`
+	IsValid     bool   `json:"is_valid"`
+	IsMainchain bool   `json:"is_mainchain"`
+}
+
+// Vout is the record of a vouts table row.
+type Vout struct {
+	RowID      uint64   `json:"row_id"`
+	TxRowID    uint64   `json:"tx_row_id"`
+	TxHash     string   `json:"tx_hash"`
+	TxIndex    uint32   `json:"tx_index"`
+	TxTree     int8     `json:"tx_tree"`
+	TxType     int16    `json:"tx_type"`
+	Value      uint64   `json:"value"`
+	Version    uint16   `json:"version"`
+	ScriptType string   `json:"script_type"`
+	ReqSigs    uint32   `json:"req_sigs"`
+	Addresses  []string `json:"addresses"`
+	Mixed      bool     `json:"mixed"`
+}
+
+// AddressDelta is the record of an addresses table row, which is the change in
+// an address's balance from a transaction output (funding) or input
+// (spending). Value is negative for spending rows. VinVoutRowID is the row ID
+// of the vouts table row for funding rows, or of the vins table row for
+// spending rows.
+type AddressDelta struct {
+	RowID          uint64 `json:"row_id"`
+	Address        string `json:"address"`
+	TxHash         string `json:"tx_hash"`
+	TxVinVoutIndex uint32 `json:"tx_vin_vout_index"`
+	VinVoutRowID   uint64 `json:"vin_vout_row_id"`
+	MatchingTxHash string `json:"matching_tx_hash,omitempty"`
+	IsFunding      bool   `json:"is_funding"`
+	Value          int64  `json:"value"`
+	TxType         int16  `json:"tx_type"`
+	BlockHash      string `json:"block_hash"`
+	BlockTime      int64  `json:"block_time"`
+	ValidMainchain bool   `json:"valid_mainchain"`
+}
+
+// Key is the message key of the record of a table row stored with a block. A
+// row is always sent with the same key, so consumers may use it to discard
+// records that are delivered more than once.
+func Key(blockHash, table string, rowID uint64) string {
+	return fmt.Sprintf("%s:%s:%d", blockHash, table, rowID)
+}
+
+// Messages creates the JSON encoded records of the stored rows of a block, by
+// table.
+func Messages(stored *dbtypes.StoredBlock) (map[string][]kafka.Message, error) {
+	block := stored.Block
+	msgs := make(map[string][]kafka.Message, len(Tables))
+	add := func(table string, rowID uint64, record interface{}) error {
+		value, err := json.Marshal(record)
+		if err != nil {
+			return fmt.Errorf("failed to encode %s row %d: %v", table, rowID, err)
+		}
+		msgs[table] = append(msgs[table], kafka.Message{
+			Key:   []byte(Key(block.Hash, table, rowID)),
+			Value: value,
+		})
+		return nil
+	}
+
+	for it, tx := range stored.Txns {
+		txDbID := stored.TxDbIDs[it]
+		err := add(TableTransactions, txDbID, &Tx{
+			RowID:       txDbID,
+			TxHash:      tx.TxID,
+			BlockHash:   tx.BlockHash,
+			BlockHeight: tx.BlockHeight,
+			BlockTime:   tx.BlockTime.UNIX(),
+			BlockIndex:  tx.BlockIndex,
+			Tree:        tx.Tree,
+			TxType:      tx.TxType,
+			Version:     tx.Version,
+			Locktime:    tx.Locktime,
+			Expiry:      tx.Expiry,
+			Size:        tx.Size,
+			Spent:       tx.Spent,
+			Sent:        tx.Sent,
+			Fees:        tx.Fees,
+			MixCount:    tx.MixCount,
+			MixDenom:    tx.MixDenom,
+			NumVin:      tx.NumVin,
+			NumVout:     tx.NumVout,
+			IsValid:     tx.IsValid,
+			IsMainchain: tx.IsMainchainBlock,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for iv, vout := range tx.Vouts {
+			if iv >= len(tx.VoutDbIds) {
+				break
+			}
+			voutDbID := tx.VoutDbIds[iv]
+			err = add(TableVouts, voutDbID, &Vout{
+				RowID:      voutDbID,
+				TxRowID:    txDbID,
+				TxHash:     vout.TxHash,
+				TxIndex:    vout.TxIndex,
+				TxTree:     vout.TxTree,
+				TxType:     vout.TxType,
+				Value:      vout.Value,
+				Version:    vout.Version,
+				ScriptType: vout.ScriptPubKeyData.Type,
+				ReqSigs:    vout.ScriptPubKeyData.ReqSigs,
+				Addresses:  vout.ScriptPubKeyData.Addresses,
+				Mixed:      vout.Mixed,
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for ia, row := range stored.AddressRows {
+		rowID := stored.AddressDbIDs[ia]
+		value := int64(row.Value)
+		if !row.IsFunding {
+			value = -value
+		}
+		err := add(TableAddresses, rowID, &AddressDelta{
+			RowID:          rowID,
+			Address:        row.Address,
+			TxHash:         row.TxHash,
+			TxVinVoutIndex: row.TxVinVoutIndex,
+			VinVoutRowID:   row.VinVoutDbID,
+			MatchingTxHash: row.MatchingTxHash,
+			IsFunding:      row.IsFunding,
+			Value:          value,
+			TxType:         row.TxType,
+			BlockHash:      block.Hash,
+			BlockTime:      row.TxBlockTime.UNIX(),
+			ValidMainchain: row.ValidMainChain,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err := add(TableBlocks, stored.BlockDbID, &Block{
+		RowID:        stored.BlockDbID,
+		Hash:         block.Hash,
+		Height:       block.Height,
+		Time:         block.Time.UNIX(),
+		Version:      block.Version,
+		Size:         block.Size,
+		NumTx:        block.NumTx,
+		NumStakeTx:   block.NumStakeTx,
+		PreviousHash: block.PreviousHash,
+		VoteBits:     block.VoteBits,
+		Voters:       block.Voters,
+		FreshStake:   block.FreshStake,
+		Revocations:  block.Revocations,
+		PoolSize:     block.PoolSize,
+		Bits:         block.Bits,
+		SBits:        block.SBits,
+		Difficulty:   block.Difficulty,
+		StakeVersion: block.StakeVersion,
+		ChainWork:    block.ChainWork,
+		IsValid:      stored.IsValid,
+		IsMainchain:  stored.IsMainchain,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return msgs, nil
+}
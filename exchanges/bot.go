@@ -93,6 +93,8 @@ type ExchangeBot struct {
 	// The failed flag is set when there are either no up-to-date Bitcoin-fiat
 	// exchanges or no up-to-date Decred exchanges. IsFailed is a getter for failed.
 	failed bool
+	// priceUpdated is the time of the last update that set the price.
+	priceUpdated time.Time
 }
 
 // ExchangeBotState is the current known state of all exchanges, in a certain
@@ -769,6 +771,7 @@ func (bot *ExchangeBot) updateState() error {
 		bot.failed = true
 	} else {
 		bot.failed = false
+		bot.priceUpdated = time.Now()
 		bot.currentState.Price = dcrPrice * btcPrice
 		bot.currentState.BtcPrice = btcPrice
 		bot.currentState.Volume = volume
@@ -0,0 +1,152 @@
+// Copyright (c) 2020, The Decred developers
+// See LICENSE for details.
+
+package exchanges
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Rate is the price of one DCR in a currency. Source is the name of the
+// RateProvider that supplied it, and Updated is the UNIX time at which the
+// price was last set. Stale is set if the price is not current, such as a
+// price recorded on a previous day, or a cached price served while no provider
+// has a current one.
+type Rate struct {
+	Currency string  `json:"currency"`
+	Price    float64 `json:"price"`
+	Source   string  `json:"source"`
+	Updated  int64   `json:"updated"`
+	Stale    bool    `json:"stale"`
+}
+
+// Convert converts an amount of DCR to the rate's currency.
+func (rate *Rate) Convert(dcr float64) float64 {
+	return rate.Price * dcr
+}
+
+// RateProvider is a source of DCR exchange rates.
+type RateProvider interface {
+	// Name identifies the provider in a Rate.
+	Name() string
+	// Rate gets the price of DCR in the currency with the given code.
+	Rate(code string) (*Rate, error)
+}
+
+// ExchangeBotRateProvider is the Name of the ExchangeBot as a RateProvider.
+const ExchangeBotRateProvider = "exchanges"
+
+// Name is the name of the ExchangeBot as a RateProvider.
+func (bot *ExchangeBot) Name() string {
+	return ExchangeBotRateProvider
+}
+
+// Rate gets the volume-averaged price of DCR in the currency with the given
+// code from the monitored exchanges. An error is returned if the ExchangeBot
+// has no up-to-date DCR-BTC exchange or Bitcoin index for the currency.
+func (bot *ExchangeBot) Rate(code string) (*Rate, error) {
+	if code == "" || code == bot.BtcIndex {
+		bot.mtx.RLock()
+		defer bot.mtx.RUnlock()
+		if bot.failed || bot.currentState.Price == 0 {
+			return nil, fmt.Errorf("no current %s price", bot.BtcIndex)
+		}
+		return &Rate{
+			Currency: bot.BtcIndex,
+			Price:    bot.currentState.Price,
+			Source:   ExchangeBotRateProvider,
+			Updated:  bot.priceUpdated.Unix(),
+		}, nil
+	}
+	state, err := bot.ConvertedState(code)
+	if err != nil {
+		return nil, err
+	}
+	bot.mtx.RLock()
+	defer bot.mtx.RUnlock()
+	return &Rate{
+		Currency: code,
+		Price:    state.Price,
+		Source:   ExchangeBotRateProvider,
+		Updated:  bot.priceUpdated.Unix(),
+	}, nil
+}
+
+// FailoverRates is a RateProvider that gets each rate from the first of its
+// providers that has one. The rates are cached for a while to limit the
+// requests to the providers. If none of the providers has a rate, a cached one
+// is returned as stale until it is maxAge old.
+type FailoverRates struct {
+	providers []RateProvider
+	cacheTime time.Duration
+	maxAge    time.Duration
+
+	mtx   sync.Mutex
+	cache map[string]*cachedRate
+}
+
+type cachedRate struct {
+	rate    Rate
+	fetched time.Time
+}
+
+// NewFailoverRates creates a FailoverRates trying the providers in the given
+// order. Rates are cached for cacheTime, and served as stale until maxAge old
+// when no provider has a rate.
+func NewFailoverRates(cacheTime, maxAge time.Duration, providers ...RateProvider) *FailoverRates {
+	return &FailoverRates{
+		providers: providers,
+		cacheTime: cacheTime,
+		maxAge:    maxAge,
+		cache:     make(map[string]*cachedRate),
+	}
+}
+
+// Name lists the names of the providers in order.
+func (f *FailoverRates) Name() string {
+	names := make([]string, 0, len(f.providers))
+	for _, p := range f.providers {
+		names = append(names, p.Name())
+	}
+	return strings.Join(names, ",")
+}
+
+// Rate gets the price of DCR in the currency with the given code from the
+// cache, or from the first provider that has it. The providers are queried
+// without holding the lock, so a slow provider does not block cached rates.
+func (f *FailoverRates) Rate(code string) (*Rate, error) {
+	f.mtx.Lock()
+	cached := f.cache[code]
+	if cached != nil && time.Since(cached.fetched) < f.cacheTime {
+		rate := cached.rate
+		f.mtx.Unlock()
+		return &rate, nil
+	}
+	providers := make([]RateProvider, len(f.providers))
+	copy(providers, f.providers)
+	f.mtx.Unlock()
+
+	errs := make([]string, 0, len(providers))
+	for _, p := range providers {
+		rate, err := p.Rate(code)
+		if err != nil {
+			log.Debugf("Rate provider %s has no %s rate: %v", p.Name(), code, err)
+			errs = append(errs, fmt.Sprintf("%s: %v", p.Name(), err))
+			continue
+		}
+		f.mtx.Lock()
+		f.cache[code] = &cachedRate{rate: *rate, fetched: time.Now()}
+		f.mtx.Unlock()
+		return rate, nil
+	}
+
+	if cached != nil && time.Since(cached.fetched) < f.maxAge {
+		rate := cached.rate
+		rate.Stale = true
+		return &rate, nil
+	}
+	return nil, fmt.Errorf("no %s rate available (%s)", code, strings.Join(errs, "; "))
+}
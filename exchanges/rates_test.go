@@ -0,0 +1,118 @@
+// Copyright (c) 2020, The Decred developers
+// See LICENSE for details.
+
+package exchanges
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+type testRates struct {
+	name  string
+	price float64
+	calls int
+}
+
+func (p *testRates) Name() string {
+	return p.name
+}
+
+func (p *testRates) Rate(code string) (*Rate, error) {
+	p.calls++
+	if p.price == 0 {
+		return nil, fmt.Errorf("unavailable")
+	}
+	return &Rate{Currency: code, Price: p.price, Source: p.name}, nil
+}
+
+func TestFailoverRates(t *testing.T) {
+	primary := &testRates{name: "primary", price: 20}
+	backup := &testRates{name: "backup", price: 19}
+	rates := NewFailoverRates(time.Hour, time.Hour, primary, backup)
+
+	check := func(wantSource string, wantStale bool) {
+		t.Helper()
+		rate, err := rates.Rate("USD")
+		if err != nil {
+			t.Fatalf("Rate: %v", err)
+		}
+		if rate.Source != wantSource || rate.Stale != wantStale {
+			t.Errorf("got a rate from %s (stale %v), wanted %s (stale %v)",
+				rate.Source, rate.Stale, wantSource, wantStale)
+		}
+	}
+
+	// The first provider with a rate is used, and the rate is cached.
+	check("primary", false)
+	check("primary", false)
+	if primary.calls != 1 || backup.calls != 0 {
+		t.Errorf("got %d and %d provider calls", primary.calls, backup.calls)
+	}
+
+	// Without the primary rate, the backup provider is used.
+	rates.cacheTime = 0
+	primary.price = 0
+	check("backup", false)
+
+	// Without any rate, the cached rate is stale until maxAge.
+	backup.price = 0
+	check("backup", true)
+	rates.maxAge = 0
+	if _, err := rates.Rate("USD"); err == nil {
+		t.Errorf("expected an error without a rate")
+	}
+}
+
+// blockingRates blocks in Rate until release is closed.
+type blockingRates struct {
+	testRates
+	entered chan struct{}
+	release chan struct{}
+}
+
+func (p *blockingRates) Rate(code string) (*Rate, error) {
+	if code != "USD" {
+		close(p.entered)
+		<-p.release
+	}
+	return p.testRates.Rate(code)
+}
+
+func TestFailoverRatesSlowProvider(t *testing.T) {
+	slow := &blockingRates{
+		testRates: testRates{name: "slow", price: 20},
+		entered:   make(chan struct{}),
+		release:   make(chan struct{}),
+	}
+	rates := NewFailoverRates(time.Hour, time.Hour, slow)
+	if _, err := rates.Rate("USD"); err != nil {
+		t.Fatalf("Rate: %v", err)
+	}
+
+	// A cached rate is served while a provider is queried for another.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if _, err := rates.Rate("EUR"); err != nil {
+			t.Errorf("Rate: %v", err)
+		}
+	}()
+	<-slow.entered
+	cached := make(chan error)
+	go func() {
+		_, err := rates.Rate("USD")
+		cached <- err
+	}()
+	select {
+	case err := <-cached:
+		if err != nil {
+			t.Errorf("Rate: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Errorf("cached rate blocked by a provider query")
+	}
+	close(slow.release)
+	<-done
+}
@@ -18,12 +18,12 @@ import (
 	"sync/atomic"
 	"time"
 
-	"github.com/decred/dcrd/chaincfg/chainhash"
 	"github.com/decred/dcrd/chaincfg/v2"
 	"github.com/decred/dcrd/dcrutil/v2"
 	chainjson "github.com/decred/dcrd/rpc/jsonrpc/types/v2"
 	"github.com/decred/dcrd/wire"
 
+	apitypes "github.com/decred/dcrdata/api/types/v5"
 	"github.com/decred/dcrdata/blockdata/v5"
 	"github.com/decred/dcrdata/db/dbtypes/v2"
 	"github.com/decred/dcrdata/exchanges/v2"
@@ -34,6 +34,7 @@ import (
 	pstypes "github.com/decred/dcrdata/pubsub/types/v3"
 	"github.com/decred/dcrdata/txhelpers/v4"
 
+	"github.com/decred/dcrdata/v5/chainstore"
 	"github.com/go-chi/chi"
 	"github.com/go-chi/chi/middleware"
 	"github.com/rs/cors"
@@ -60,54 +61,6 @@ const (
 	testnetNetName = "Testnet"
 )
 
-// explorerDataSource implements extra data retrieval functions that require a
-// faster solution than RPC, or additional functionality.
-type explorerDataSource interface {
-	BlockHeight(hash string) (int64, error)
-	Height() int64
-	HeightDB() (int64, error)
-	BlockHash(height int64) (string, error)
-	SpendingTransaction(fundingTx string, vout uint32) (string, uint32, int8, error)
-	SpendingTransactions(fundingTxID string) ([]string, []uint32, []uint32, error)
-	PoolStatusForTicket(txid string) (dbtypes.TicketSpendType, dbtypes.TicketPoolStatus, error)
-	AddressHistory(address string, N, offset int64, txnType dbtypes.AddrTxnViewType) ([]*dbtypes.AddressRow, *dbtypes.AddressBalance, error)
-	AddressData(address string, N, offset int64, txnType dbtypes.AddrTxnViewType) (*dbtypes.AddressInfo, error)
-	DevBalance() (*dbtypes.AddressBalance, error)
-	FillAddressTransactions(addrInfo *dbtypes.AddressInfo) error
-	BlockMissedVotes(blockHash string) ([]string, error)
-	TicketMiss(ticketHash string) (string, int64, error)
-	SideChainBlocks() ([]*dbtypes.BlockStatus, error)
-	DisapprovedBlocks() ([]*dbtypes.BlockStatus, error)
-	BlockStatus(hash string) (dbtypes.BlockStatus, error)
-	BlockFlags(hash string) (bool, bool, error)
-	TicketPoolVisualization(interval dbtypes.TimeBasedGrouping) (*dbtypes.PoolTicketsData, *dbtypes.PoolTicketsData, *dbtypes.PoolTicketsData, int64, error)
-	TransactionBlocks(hash string) ([]*dbtypes.BlockStatus, []uint32, error)
-	Transaction(txHash string) ([]*dbtypes.Tx, error)
-	VinsForTx(*dbtypes.Tx) (vins []dbtypes.VinTxProperty, prevPkScripts []string, scriptVersions []uint16, err error)
-	VoutsForTx(*dbtypes.Tx) ([]dbtypes.Vout, error)
-	PosIntervals(limit, offset uint64) ([]*dbtypes.BlocksGroupedInfo, error)
-	TimeBasedIntervals(timeGrouping dbtypes.TimeBasedGrouping, limit, offset uint64) ([]*dbtypes.BlocksGroupedInfo, error)
-	AgendasVotesSummary(agendaID string) (summary *dbtypes.AgendaSummary, err error)
-	BlockTimeByHeight(height int64) (int64, error)
-	LastPiParserSync() time.Time
-	GetChainParams() *chaincfg.Params
-	GetExplorerBlock(hash string) *types.BlockInfo
-	GetExplorerBlocks(start int, end int) []*types.BlockBasic
-	GetBlockHeight(hash string) (int64, error)
-	GetBlockHash(idx int64) (string, error)
-	GetExplorerTx(txid string) *types.TxInfo
-	GetExplorerAddress(address string, count, offset int64) (*dbtypes.AddressInfo, txhelpers.AddressType, txhelpers.AddressError)
-	GetTip() (*types.WebBasicBlock, error)
-	DecodeRawTransaction(txhex string) (*chainjson.TxRawResult, error)
-	SendRawTransaction(txhex string) (string, error)
-	GetHeight() (int64, error)
-	TxHeight(txid *chainhash.Hash) (height int64)
-	BlockSubsidy(height int64, voters uint16) *chainjson.GetBlockSubsidyResult
-	GetExplorerFullBlocks(start int, end int) []*types.BlockInfo
-	CurrentDifficulty() (float64, error)
-	Difficulty(timestamp int64) float64
-}
-
 // PoliteiaBackend implements methods that manage proposals db data.
 type PoliteiaBackend interface {
 	LastProposalsSync() int64
@@ -193,7 +146,7 @@ type pageData struct {
 
 type explorerUI struct {
 	Mux              *chi.Mux
-	dataSource       explorerDataSource
+	dataSource       chainstore.ChainStore
 	agendasSource    agendaBackend
 	voteTracker      *agendas.VoteTracker
 	proposalsSource  PoliteiaBackend
@@ -254,6 +207,11 @@ func (exp *explorerUI) reloadTemplatesSig(sig os.Signal) {
 	}()
 }
 
+// WebsocketMetrics returns the connection metrics of the websocket hub.
+func (exp *explorerUI) WebsocketMetrics() *apitypes.WebsocketMetrics {
+	return exp.wsHub.Metrics()
+}
+
 // StopWebsocketHub stops the websocket hub
 func (exp *explorerUI) StopWebsocketHub() {
 	if exp == nil {
@@ -266,7 +224,7 @@ func (exp *explorerUI) StopWebsocketHub() {
 
 // ExplorerConfig is the configuration settings for explorerUI.
 type ExplorerConfig struct {
-	DataSource      explorerDataSource
+	DataSource      chainstore.ChainStore
 	UseRealIP       bool
 	AppVersion      string
 	DevPrefetch     bool
@@ -306,9 +264,9 @@ func New(cfg *ExplorerConfig) *explorerUI {
 		explorerLinks.OnionURL = fmt.Sprintf("http://%s/", cfg.OnionAddress)
 	}
 
-	// explorerDataSource is an interface that could have a value of pointer type.
+	// DataSource is an interface that could have a value of pointer type.
 	if exp.dataSource == nil || reflect.ValueOf(exp.dataSource).IsNil() {
-		log.Errorf("An explorerDataSource (PostgreSQL backend) is required.")
+		log.Errorf("A ChainStore (e.g. the PostgreSQL backend) is required.")
 		return nil
 	}
 
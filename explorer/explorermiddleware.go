@@ -42,7 +42,7 @@ func (exp *explorerUI) BlockHashPathOrIndexCtx(next http.Handler) http.Handler {
 		if err != nil {
 			// Not a height, try it as a hash.
 			hash = chi.URLParam(r, "blockhash")
-			height, err = exp.dataSource.BlockHeight(hash)
+			height, err = exp.dataSource.BlockHeight(r.Context(), hash)
 			if exp.timeoutErrorPage(w, err, "BlockHashPathOrIndexCtx>BlockHeight") {
 				return
 			}
@@ -61,17 +61,17 @@ func (exp *explorerUI) BlockHashPathOrIndexCtx(next http.Handler) http.Handler {
 				expectedTime := time.Duration(height-maxHeight) * exp.ChainParams.TargetTimePerBlock
 				message := fmt.Sprintf("This block is expected to arrive in approximately in %v. ", expectedTime)
 				exp.StatusPage(w, defaultErrorCode, message,
-					string(expectedTime), ExpStatusFutureBlock)
+					expectedTime.String(), ExpStatusFutureBlock)
 				return
 			}
 
-			hash, err = exp.dataSource.GetBlockHash(height)
+			hash, err = exp.dataSource.GetBlockHash(r.Context(), height)
 			if err != nil {
-				hash, err = exp.dataSource.BlockHash(height)
+				hash, err = exp.dataSource.BlockHash(r.Context(), height)
 				if err != nil {
 					log.Errorf("(*ChainDB).BlockHash(%d) failed: %v", height, err)
 					exp.StatusPage(w, defaultErrorCode, "could not find that block",
-						string(height), ExpStatusNotFound)
+						strconv.FormatInt(height, 10), ExpStatusNotFound)
 					return
 				}
 			}
@@ -5,6 +5,7 @@
 package explorer
 
 import (
+	"context"
 	"database/sql"
 	"encoding/hex"
 	"encoding/json"
@@ -220,7 +221,7 @@ func (exp *explorerUI) Home(w http.ResponseWriter, r *http.Request) {
 
 // SideChains is the page handler for the "/side" path.
 func (exp *explorerUI) SideChains(w http.ResponseWriter, r *http.Request) {
-	sideBlocks, err := exp.dataSource.SideChainBlocks()
+	sideBlocks, err := exp.dataSource.SideChainBlocks(r.Context())
 	if exp.timeoutErrorPage(w, err, "SideChainBlocks") {
 		return
 	}
@@ -269,7 +270,7 @@ func (exp *explorerUI) InsightRootPage(w http.ResponseWriter, r *http.Request) {
 
 // DisapprovedBlocks is the page handler for the "/disapproved" path.
 func (exp *explorerUI) DisapprovedBlocks(w http.ResponseWriter, r *http.Request) {
-	disapprovedBlocks, err := exp.dataSource.DisapprovedBlocks()
+	disapprovedBlocks, err := exp.dataSource.DisapprovedBlocks(r.Context())
 	if exp.timeoutErrorPage(w, err, "DisapprovedBlocks") {
 		return
 	}
@@ -393,7 +394,7 @@ func (exp *explorerUI) StakeDiffWindows(w http.ResponseWriter, r *http.Request)
 		rows = maxExplorerRows
 	}
 
-	windows, err := exp.dataSource.PosIntervals(rows, offsetWindow)
+	windows, err := exp.dataSource.PosIntervals(r.Context(), rows, offsetWindow)
 	if exp.timeoutErrorPage(w, err, "PosIntervals") {
 		return
 	}
@@ -518,7 +519,7 @@ func (exp *explorerUI) timeBasedBlocksListing(val string, w http.ResponseWriter,
 		rows = maxExplorerRows
 	}
 
-	data, err := exp.dataSource.TimeBasedIntervals(grouping, rows, offset)
+	data, err := exp.dataSource.TimeBasedIntervals(r.Context(), grouping, rows, offset)
 	if exp.timeoutErrorPage(w, err, "TimeBasedIntervals") {
 		return
 	}
@@ -624,7 +625,7 @@ func (exp *explorerUI) Blocks(w http.ResponseWriter, r *http.Request) {
 	}
 
 	for _, s := range summaries {
-		blockStatus, err := exp.dataSource.BlockStatus(s.Hash)
+		blockStatus, err := exp.dataSource.BlockStatus(r.Context(), s.Hash)
 		if exp.timeoutErrorPage(w, err, "BlockStatus") {
 			return
 		}
@@ -698,7 +699,7 @@ func (exp *explorerUI) Block(w http.ResponseWriter, r *http.Request) {
 
 	// Retrieve missed votes, main/side chain status, and stakeholder approval.
 	var err error
-	data.Misses, err = exp.dataSource.BlockMissedVotes(hash)
+	data.Misses, err = exp.dataSource.BlockMissedVotes(r.Context(), hash)
 	if exp.timeoutErrorPage(w, err, "BlockMissedVotes") {
 		return
 	}
@@ -707,7 +708,7 @@ func (exp *explorerUI) Block(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var blockStatus dbtypes.BlockStatus
-	blockStatus, err = exp.dataSource.BlockStatus(hash)
+	blockStatus, err = exp.dataSource.BlockStatus(r.Context(), hash)
 	if exp.timeoutErrorPage(w, err, "BlockStatus") {
 		return
 	}
@@ -746,16 +747,20 @@ func (exp *explorerUI) Block(w http.ResponseWriter, r *http.Request) {
 func (exp *explorerUI) Mempool(w http.ResponseWriter, r *http.Request) {
 	// Safely retrieve the inventory pointer, which can be reset in StoreMPData.
 	inv := exp.MempoolInventory()
+	// The preview locks the inventory itself, so assemble it first.
+	nextBlock := exp.NextBlockPreview()
 
 	// Prevent modifications to the shared inventory struct (e.g. in the
 	// MempoolMonitor) while marshaling the inventory.
 	inv.RLock()
 	str, err := exp.templates.exec("mempool", struct {
 		*CommonPageData
-		Mempool *types.MempoolInfo
+		Mempool   *types.MempoolInfo
+		NextBlock *types.NextBlockPreview
 	}{
 		CommonPageData: exp.commonData(r),
 		Mempool:        inv,
+		NextBlock:      nextBlock,
 	})
 	inv.RUnlock()
 
@@ -804,12 +809,12 @@ func (exp *explorerUI) TxPage(w http.ResponseWriter, r *http.Request) {
 	ioid, _ := r.Context().Value(ctxTxInOutId).(string)
 	inoutid, _ := strconv.ParseInt(ioid, 10, 0)
 
-	tx := exp.dataSource.GetExplorerTx(hash)
+	tx := exp.dataSource.GetExplorerTx(r.Context(), hash)
 	// If dcrd has no information about the transaction, pull the transaction
 	// details from the auxiliary DB database.
 	if tx == nil {
 		// Search for occurrences of the transaction in the database.
-		dbTxs, err := exp.dataSource.Transaction(hash)
+		dbTxs, err := exp.dataSource.Transaction(r.Context(), hash)
 		if exp.timeoutErrorPage(w, err, "Transaction") {
 			return
 		}
@@ -836,7 +841,7 @@ func (exp *explorerUI) TxPage(w http.ResponseWriter, r *http.Request) {
 				FormattedSize: humanize.Bytes(uint64(dbTx0.Size)),
 				Total:         dcrutil.Amount(dbTx0.Sent).ToCoin(),
 				Fee:           fees,
-				FeeRate:       dcrutil.Amount((1000 * int64(fees)) / int64(dbTx0.Size)),
+				FeeRate:       dcrutil.Amount(dbTx0.FeeRate),
 				// VoteInfo TODO - check votes table
 				Coinbase: dbTx0.BlockIndex == 0,
 			},
@@ -857,7 +862,7 @@ func (exp *explorerUI) TxPage(w http.ResponseWriter, r *http.Request) {
 		}
 
 		// Retrieve vouts from DB.
-		vouts, err := exp.dataSource.VoutsForTx(dbTx0)
+		vouts, err := exp.dataSource.VoutsForTx(r.Context(), dbTx0)
 		if exp.timeoutErrorPage(w, err, "VoutsForTx") {
 			return
 		}
@@ -877,7 +882,7 @@ func (exp *explorerUI) TxPage(w http.ResponseWriter, r *http.Request) {
 				opReturn = asm
 			}
 			// Determine if the outpoint is spent
-			spendingTx, _, _, err := exp.dataSource.SpendingTransaction(hash, vouts[iv].TxIndex)
+			spendingTx, _, _, err := exp.dataSource.SpendingTransaction(r.Context(), hash, vouts[iv].TxIndex)
 			if exp.timeoutErrorPage(w, err, "SpendingTransaction") {
 				return
 			}
@@ -898,7 +903,7 @@ func (exp *explorerUI) TxPage(w http.ResponseWriter, r *http.Request) {
 		}
 
 		// Retrieve vins from DB.
-		vins, prevPkScripts, scriptVersions, err := exp.dataSource.VinsForTx(dbTx0)
+		vins, prevPkScripts, scriptVersions, err := exp.dataSource.VinsForTx(r.Context(), dbTx0)
 		if exp.timeoutErrorPage(w, err, "VinsForTx") {
 			return
 		}
@@ -1057,7 +1062,7 @@ func (exp *explorerUI) TxPage(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Details on all the blocks containing this transaction
-	blocks, blockInds, err := exp.dataSource.TransactionBlocks(tx.TxID)
+	blocks, blockInds, err := exp.dataSource.TransactionBlocks(r.Context(), tx.TxID)
 	if exp.timeoutErrorPage(w, err, "TransactionBlocks") {
 		return
 	}
@@ -1081,7 +1086,7 @@ func (exp *explorerUI) TxPage(w http.ResponseWriter, r *http.Request) {
 	// For each output of this transaction, look up any spending transactions,
 	// and the index of the spending transaction input.
 	spendingTxHashes, spendingTxVinInds, voutInds, err :=
-		exp.dataSource.SpendingTransactions(hash)
+		exp.dataSource.SpendingTransactions(r.Context(), hash)
 	if exp.timeoutErrorPage(w, err, "SpendingTransactions") {
 		return
 	}
@@ -1102,7 +1107,7 @@ func (exp *explorerUI) TxPage(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if tx.IsTicket() {
-		spendStatus, poolStatus, err := exp.dataSource.PoolStatusForTicket(hash)
+		spendStatus, poolStatus, err := exp.dataSource.PoolStatusForTicket(r.Context(), hash)
 		if exp.timeoutErrorPage(w, err, "PoolStatusForTicket") {
 			return
 		}
@@ -1125,7 +1130,7 @@ func (exp *explorerUI) TxPage(w http.ResponseWriter, r *http.Request) {
 
 			// For missed tickets, get the block in which it should have voted.
 			if poolStatus == dbtypes.PoolStatusMissed {
-				tx.TicketInfo.LotteryBlock, _, err = exp.dataSource.TicketMiss(hash)
+				tx.TicketInfo.LotteryBlock, _, err = exp.dataSource.TicketMiss(r.Context(), hash)
 				if err != nil && err != sql.ErrNoRows {
 					log.Errorf("Unable to retrieve miss information for ticket %s: %v",
 						hash, err)
@@ -1332,7 +1337,7 @@ func (exp *explorerUI) AddressPage(w http.ResponseWriter, r *http.Request) {
 			UnconfirmedTxns: new(dbtypes.AddressTransactions),
 		}
 	} else {
-		addrData, err = exp.AddressListData(address, txnType, limitN, offsetAddrOuts)
+		addrData, err = exp.AddressListData(r.Context(), address, txnType, limitN, offsetAddrOuts)
 		if exp.timeoutErrorPage(w, err, "TicketsPriceByHeight") {
 			return
 		} else if err != nil {
@@ -1392,7 +1397,7 @@ func (exp *explorerUI) AddressTable(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	addrData, err := exp.AddressListData(address, txnType, limitN, offsetAddrOuts)
+	addrData, err := exp.AddressListData(r.Context(), address, txnType, limitN, offsetAddrOuts)
 	if err != nil {
 		log.Errorf("AddressListData error: %v", err)
 		http.Error(w, http.StatusText(http.StatusInternalServerError),
@@ -1496,9 +1501,9 @@ func parseAddressParams(r *http.Request) (address string, txnType dbtypes.AddrTx
 
 // AddressListData grabs a size-limited and type-filtered set of inputs/outputs
 // for a given address.
-func (exp *explorerUI) AddressListData(address string, txnType dbtypes.AddrTxnViewType, limitN, offsetAddrOuts int64) (addrData *dbtypes.AddressInfo, err error) {
+func (exp *explorerUI) AddressListData(ctx context.Context, address string, txnType dbtypes.AddrTxnViewType, limitN, offsetAddrOuts int64) (addrData *dbtypes.AddressInfo, err error) {
 	// Get addresses table rows for the address.
-	addrData, err = exp.dataSource.AddressData(address, limitN,
+	addrData, err = exp.dataSource.AddressData(ctx, address, limitN,
 		offsetAddrOuts, txnType)
 	if dbtypes.IsTimeoutErr(err) { //exp.timeoutErrorPage(w, err, "TicketsPriceByHeight") {
 		return nil, err
@@ -1575,12 +1580,12 @@ func (exp *explorerUI) Search(w http.ResponseWriter, r *http.Request) {
 	// redirect to the block page if it is.
 	idx, err := strconv.ParseInt(searchStr, 10, 0)
 	if err == nil {
-		_, err = exp.dataSource.GetBlockHash(idx)
+		_, err = exp.dataSource.GetBlockHash(r.Context(), idx)
 		if err == nil {
 			http.Redirect(w, r, "/block/"+searchStr, http.StatusPermanentRedirect)
 			return
 		}
-		_, err = exp.dataSource.BlockHash(idx)
+		_, err = exp.dataSource.BlockHash(r.Context(), idx)
 		if err == nil {
 			http.Redirect(w, r, "/block/"+searchStr, http.StatusPermanentRedirect)
 			return
@@ -1624,7 +1629,7 @@ func (exp *explorerUI) Search(w http.ResponseWriter, r *http.Request) {
 
 	// This is be unnecessarily duplicative and possible very slow for a very
 	// active addresses.
-	addrHist, _, _ := exp.dataSource.AddressHistory(searchStr,
+	addrHist, _, _ := exp.dataSource.AddressHistory(r.Context(), searchStr,
 		1, 0, dbtypes.AddrTxnAll)
 	if len(addrHist) > 0 {
 		http.Redirect(w, r, "/address/"+searchStr, http.StatusPermanentRedirect)
@@ -1660,7 +1665,7 @@ func (exp *explorerUI) Search(w http.ResponseWriter, r *http.Request) {
 
 	// Attempt to get a block index by calling GetBlockHeight to see if the
 	// value is a block hash and then redirect to the block page if it is.
-	_, err = exp.dataSource.GetBlockHeight(searchStrSplit[0])
+	_, err = exp.dataSource.GetBlockHeight(r.Context(), searchStrSplit[0])
 	if err == nil {
 		http.Redirect(w, r, "/block/"+searchStrSplit[0], http.StatusPermanentRedirect)
 		return
@@ -1668,14 +1673,14 @@ func (exp *explorerUI) Search(w http.ResponseWriter, r *http.Request) {
 
 	// Call GetExplorerTx to see if the value is a transaction hash and then
 	// redirect to the tx page if it is.
-	tx := exp.dataSource.GetExplorerTx(searchStrSplit[0])
+	tx := exp.dataSource.GetExplorerTx(r.Context(), searchStrSplit[0])
 	if tx != nil {
 		http.Redirect(w, r, "/tx/"+searchStrRewritten, http.StatusPermanentRedirect)
 		return
 	}
 
 	// Also check the aux DB as it may have transactions from orphaned blocks.
-	dbTxs, err := exp.dataSource.Transaction(searchStrSplit[0])
+	dbTxs, err := exp.dataSource.Transaction(r.Context(), searchStrSplit[0])
 	if err != nil && err != sql.ErrNoRows {
 		log.Errorf("Searching for transaction failed: %v", err)
 	}
@@ -1806,7 +1811,7 @@ func (exp *explorerUI) AgendaPage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	summary, err := exp.dataSource.AgendasVotesSummary(agendaId)
+	summary, err := exp.dataSource.AgendasVotesSummary(r.Context(), agendaId)
 	if err != nil {
 		log.Errorf("fetching Cumulative votes choices count failed: %v", err)
 	}
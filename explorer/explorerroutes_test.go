@@ -6,21 +6,21 @@ import (
 	"testing"
 
 	"github.com/decred/dcrd/chaincfg/v2"
-	"github.com/decred/dcrdata/db/dcrpg/v5"
 	"github.com/decred/dcrdata/explorer/types/v2"
+	"github.com/decred/dcrdata/v5/chainstore"
 )
 
 const (
 	viewsPath = "../views"
 )
 
-// ChainDBStub satisfies explorerDataSource, but will likely panic with a nil
+// ChainDBStub satisfies chainstore.ChainStore, but will panic with a nil
 // pointer dereference for methods we do not explicitly define here.
 type ChainDBStub struct {
-	// Embedding *dcrpg.ChainDB promotes all of the methods needed for
-	// WireDBStub to satisfy the explorerDataSource interface. This allows us to
-	// only implement for ChainDBStub the methods required for the tests.
-	*dcrpg.ChainDB
+	// Embedding the nil chainstore.ChainStore promotes all of the methods
+	// needed for ChainDBStub to satisfy the interface. This allows us to only
+	// implement for ChainDBStub the methods required for the tests.
+	chainstore.ChainStore
 }
 
 // GetChainParams is needed by explorer.New.
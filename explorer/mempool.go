@@ -4,7 +4,10 @@
 
 package explorer
 
-import "github.com/decred/dcrdata/explorer/types/v2"
+import (
+	"github.com/decred/dcrdata/explorer/types/v2"
+	"github.com/decred/dcrdata/mempool/v5"
+)
 
 // matchMempoolVins filters relevant mempool transaction inputs whose previous
 // outpoints match the specified transaction id.
@@ -44,3 +47,22 @@ func (exp *explorerUI) GetTxMempoolInputs(txid string, txType string) (vins []ty
 	vins = append(vins, matchMempoolVins(txid, inv.Votes)...)
 	return
 }
+
+// NextBlockPreview assembles the likely next block from the transactions in
+// the mempool inventory.
+func (exp *explorerUI) NextBlockPreview() *types.NextBlockPreview {
+	inv := exp.MempoolInventory()
+	inv.RLock()
+	txns := make([]types.MempoolTx, 0, len(inv.Transactions)+len(inv.Tickets)+
+		len(inv.Votes)+len(inv.Revocations))
+	txns = append(txns, inv.Votes...)
+	txns = append(txns, inv.Tickets...)
+	txns = append(txns, inv.Revocations...)
+	txns = append(txns, inv.Transactions...)
+	prevHeight, prevHash, invTime := inv.LastBlockHeight, inv.LastBlockHash, inv.Time
+	inv.RUnlock()
+
+	preview := mempool.NextBlockPreview(txns, prevHeight, prevHash, exp.ChainParams)
+	preview.Time = invTime
+	return preview
+}
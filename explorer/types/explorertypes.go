@@ -7,6 +7,7 @@ package types
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -629,6 +630,77 @@ func FilterUniqueLastBlockVotes(txs []*TrimmedTxInfo) (votes []*TrimmedTxInfo) {
 	return votes
 }
 
+// MempoolBlockVotes groups the mempool votes that validate the same block.
+// Stale indicates that the block is not the current best block, so the votes
+// cannot be mined in the next block.
+type MempoolBlockVotes struct {
+	Hash   string      `json:"hash"`
+	Height int64       `json:"height"`
+	Stale  bool        `json:"stale"`
+	Votes  []MempoolTx `json:"votes"`
+}
+
+// MempoolVotesByBlock models all of the mempool votes, grouped by the block
+// they validate. Unlike TrimmedMempoolInfo, votes for non-tip blocks are
+// included, which helps to diagnose near-tip forks.
+type MempoolVotesByBlock struct {
+	LastBlockHeight int64                `json:"block_height"`
+	LastBlockHash   string               `json:"block_hash"`
+	Blocks          []*MempoolBlockVotes `json:"blocks"`
+	Ident           uint64               `json:"id"`
+}
+
+// VotesByBlock groups all of the mempool votes by the block they validate. The
+// groups are sorted by descending block height, with the current best block
+// first among blocks at the same height. Duplicate votes are omitted.
+func (mpi *MempoolInfo) VotesByBlock() *MempoolVotesByBlock {
+	mpi.RLock()
+	defer mpi.RUnlock()
+
+	out := &MempoolVotesByBlock{
+		LastBlockHeight: mpi.LastBlockHeight,
+		LastBlockHash:   mpi.LastBlockHash,
+		Blocks:          []*MempoolBlockVotes{},
+		Ident:           mpi.Ident,
+	}
+
+	blocks := make(map[string]*MempoolBlockVotes)
+	seenVotes := make(map[string]struct{})
+	for i := range mpi.Votes {
+		vote := &mpi.Votes[i]
+		if vote.VoteInfo == nil {
+			continue
+		}
+		if _, seen := seenVotes[vote.TxID]; seen {
+			continue
+		}
+		seenVotes[vote.TxID] = struct{}{}
+
+		validation := vote.VoteInfo.Validation
+		block, found := blocks[validation.Hash]
+		if !found {
+			block = &MempoolBlockVotes{
+				Hash:   validation.Hash,
+				Height: validation.Height,
+				Stale:  validation.Hash != mpi.LastBlockHash,
+			}
+			blocks[validation.Hash] = block
+			out.Blocks = append(out.Blocks, block)
+		}
+		block.Votes = append(block.Votes, *vote.DeepCopy())
+	}
+
+	sort.SliceStable(out.Blocks, func(i, j int) bool {
+		bi, bj := out.Blocks[i], out.Blocks[j]
+		if bi.Height != bj.Height {
+			return bi.Height > bj.Height
+		}
+		return !bi.Stale && bj.Stale
+	})
+
+	return out
+}
+
 // TicketIndex is used to assign an index to a ticket hash.
 type TicketIndex map[string]int
 
@@ -673,6 +745,27 @@ type LikelyMineable struct {
 	Count         int     `json:"count"`
 }
 
+// NextBlockPreview is the likely next block assembled from the mempool
+// transactions: the votes on the current tip, the ticket purchases allowed in
+// a block, and the revocations and regular transactions in order of fee rate
+// that fit in the block. EnoughVotes indicates whether there are enough votes
+// for the block to be valid. NumExcluded is the number of mempool transactions
+// left out of the block.
+type NextBlockPreview struct {
+	Height       int64       `json:"height"`
+	PreviousHash string      `json:"previous_hash"`
+	Time         int64       `json:"time"`
+	Votes        []MempoolTx `json:"votes"`
+	Tickets      []MempoolTx `json:"tickets"`
+	Revocations  []MempoolTx `json:"revs"`
+	Transactions []MempoolTx `json:"tx"`
+	EnoughVotes  bool        `json:"enough_votes"`
+	Size         int32       `json:"size"`
+	Fees         float64     `json:"fees"`
+	TotalOut     float64     `json:"total"`
+	NumExcluded  int         `json:"num_excluded"`
+}
+
 func (mps *MempoolShort) DeepCopy() *MempoolShort {
 	if mps == nil {
 		return nil
@@ -853,6 +946,50 @@ type WebsocketBlock struct {
 	Extra *HomeInfo  `json:"extra"`
 }
 
+// BlockInvalidation describes a block whose regular transaction tree was
+// disapproved by the votes in the following block. The regular transactions
+// listed in Transactions were reversed, and are no longer confirmed.
+type BlockInvalidation struct {
+	Hash          string   `json:"hash"`
+	Height        int64    `json:"height"`
+	InvalidatedBy string   `json:"invalidated_by"`
+	Transactions  []string `json:"transactions"`
+}
+
+// BlockValidity describes the resolution of a block's pending approval by the
+// votes in the next block, VotedBy. Validity is "approved" or "disapproved".
+type BlockValidity struct {
+	Hash        string `json:"hash"`
+	Height      int64  `json:"height"`
+	IsMainchain bool   `json:"is_mainchain"`
+	Validity    string `json:"validity"`
+	VotedBy     string `json:"voted_by"`
+}
+
+// Agenda voting events signaled with an AgendaStatusChange.
+const (
+	AgendaQuorumReached = "quorum_reached"
+	AgendaLockedIn      = "locked_in"
+	AgendaActivated     = "activated"
+	AgendaFailed        = "failed"
+)
+
+// AgendaStatusChange describes an agenda voting milestone reached with the
+// block at Height. Event is one of AgendaQuorumReached, AgendaLockedIn,
+// AgendaActivated, or AgendaFailed. The vote counts are for the current rule
+// change interval, and are only set for AgendaQuorumReached.
+type AgendaStatusChange struct {
+	AgendaID string `json:"agenda_id"`
+	Event    string `json:"event"`
+	Status   string `json:"status"`
+	Height   int64  `json:"height"`
+	Hash     string `json:"hash"`
+	Yes      uint32 `json:"yes,omitempty"`
+	Abstain  uint32 `json:"abstain,omitempty"`
+	No       uint32 `json:"no,omitempty"`
+	Quorum   uint32 `json:"quorum,omitempty"`
+}
+
 // BlockID provides basic identifying information about a block.
 type BlockID struct {
 	Hash   string
@@ -889,6 +1026,9 @@ type MempoolTx struct {
 	//TotalOutAmt float64        `json:"total_amount"`
 	Type     string    `json:"Type"`
 	VoteInfo *VoteInfo `json:"vote_info,omitempty"`
+	// Expiry is the height at which the transaction expires, or zero if it
+	// does not expire.
+	Expiry uint32 `json:"expiry,omitempty"`
 }
 
 func (mpt *MempoolTx) DeepCopy() *MempoolTx {
@@ -316,3 +316,67 @@ func TestDeepCopys(t *testing.T) {
 		t.Errorf("MempoolInfo structs not equal: \n%s\n", cmp.Diff(mpi, mpi2, copts))
 	}
 }
+
+func TestVotesByBlock(t *testing.T) {
+	vote := func(txid, blockHash string, height int64) MempoolTx {
+		return MempoolTx{
+			TxID: txid,
+			Type: "Vote",
+			VoteInfo: &VoteInfo{
+				Validation: BlockValidation{
+					Hash:     blockHash,
+					Height:   height,
+					Validity: true,
+				},
+				ForLastBlock: blockHash == "tip",
+			},
+		}
+	}
+
+	mpi := &MempoolInfo{
+		MempoolShort: MempoolShort{
+			LastBlockHeight: 100,
+			LastBlockHash:   "tip",
+		},
+		Votes: []MempoolTx{
+			vote("v1", "parent", 99),
+			vote("v2", "orphan", 100),
+			vote("v3", "tip", 100),
+			vote("v4", "tip", 100),
+			vote("v3", "tip", 100), // duplicate
+			{TxID: "v5", Type: "Vote"},
+		},
+		Ident: 7,
+	}
+
+	votes := mpi.VotesByBlock()
+	if votes.LastBlockHash != "tip" || votes.LastBlockHeight != 100 || votes.Ident != 7 {
+		t.Errorf("unexpected best block %s (%d), id %d", votes.LastBlockHash,
+			votes.LastBlockHeight, votes.Ident)
+	}
+
+	expected := []struct {
+		hash  string
+		stale bool
+		votes []string
+	}{
+		{"tip", false, []string{"v3", "v4"}},
+		{"orphan", true, []string{"v2"}},
+		{"parent", true, []string{"v1"}},
+	}
+	if len(votes.Blocks) != len(expected) {
+		t.Fatalf("got %d blocks, wanted %d", len(votes.Blocks), len(expected))
+	}
+	for i, exp := range expected {
+		block := votes.Blocks[i]
+		var txids []string
+		for _, v := range block.Votes {
+			txids = append(txids, v.TxID)
+		}
+		if block.Hash != exp.hash || block.Stale != exp.stale ||
+			!reflect.DeepEqual(txids, exp.votes) {
+			t.Errorf("block %d: got %s (stale %v) with votes %v, wanted %s (stale %v) with votes %v",
+				i, block.Hash, block.Stale, txids, exp.hash, exp.stale, exp.votes)
+		}
+	}
+}
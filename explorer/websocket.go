@@ -9,14 +9,20 @@ import (
 	"sync/atomic"
 	"time"
 
+	apitypes "github.com/decred/dcrdata/api/types/v5"
 	"github.com/decred/dcrdata/explorer/types/v2"
 	pstypes "github.com/decred/dcrdata/pubsub/types/v3"
 )
 
 const (
 	wsWriteTimeout = 10 * time.Second
-	wsReadTimeout  = 60 * time.Second
-	pingInterval   = 60 * time.Second
+	// wsHeartbeatInterval is how often a ping control frame is sent to each
+	// client. A client that does not respond with a pong, or send any other
+	// message, within wsPongTimeout of a ping is disconnected.
+	wsHeartbeatInterval = 30 * time.Second
+	wsPongTimeout       = 15 * time.Second
+	// pingInterval is how often the user count is sent to each client.
+	pingInterval = 60 * time.Second
 
 	tickerSigReset int = iota
 	tickerSigStop
@@ -42,11 +48,11 @@ var (
 	sigSyncStatus       = pstypes.SigSyncStatus
 )
 
-// WebSocketMessage represents the JSON object used to send and received typed
-// messages to the web client.
+// WebSocketMessage represents the JSON (or msgpack) object used to send and
+// received typed messages to the web client.
 type WebSocketMessage struct {
-	EventId string `json:"event"`
-	Message string `json:"message"`
+	EventId string `json:"event" msgpack:"event"`
+	Message string `json:"message" msgpack:"message"`
 }
 
 // WebsocketHub and its event loop manage all websocket client connections.
@@ -65,6 +71,15 @@ type WebsocketHub struct {
 	quitWSHandler    chan struct{}
 	dbsSyncing       atomic.Value
 	xcChan           exchangeChannel
+
+	metricsMtx        sync.Mutex
+	endedSessions     int64
+	sessionTime       time.Duration
+	heartbeatTimeouts int64
+	msgCount          uint64
+	lastMsgCount      uint64
+	lastSample        time.Time
+	msgRate           float64
 }
 
 // AreDBsSyncing is a thread-safe way to fetch the boolean in dbsSyncing.
@@ -99,13 +114,15 @@ func NewWebsocketHub() *WebsocketHub {
 		sendBufferChan:   make(chan int, clientSignalSize),
 		quitWSHandler:    make(chan struct{}),
 		xcChan:           make(exchangeChannel, 16),
+		lastSample:       time.Now(),
 	}
 }
 
 type clientHubSpoke struct {
-	cl *client
-	c  *hubSpoke
-	xc exchangeChannel
+	cl        *client
+	c         *hubSpoke
+	xc        exchangeChannel
+	connected time.Time
 }
 
 // NumClients returns the number of clients connected to the websocket hub.
@@ -123,7 +140,7 @@ func (wsh *WebsocketHub) setNumClients(n int) {
 // pointer to the new client data object.
 func (wsh *WebsocketHub) RegisterClient(c *hubSpoke, xcChan exchangeChannel) *client {
 	cl := new(client)
-	wsh.Register <- &clientHubSpoke{cl, c, xcChan}
+	wsh.Register <- &clientHubSpoke{cl, c, xcChan, time.Now()}
 	return cl
 }
 
@@ -142,11 +159,13 @@ func (wsh *WebsocketHub) UnregisterClient(c *hubSpoke) {
 
 // unregisterClient should only be called from the loop in run().
 func (wsh *WebsocketHub) unregisterClient(c *hubSpoke) {
-	if _, ok := wsh.clients[c]; !ok {
+	ch, ok := wsh.clients[c]
+	if !ok {
 		// unknown client, do not close channel
 		log.Warnf("unknown client")
 		return
 	}
+	wsh.endSession(ch)
 	delete(wsh.clients, c)
 	wsh.setNumClients(len(wsh.clients))
 
@@ -162,11 +181,69 @@ func (wsh *WebsocketHub) unregisterAllClients() {
 		spokes = append(spokes, c)
 	}
 	for _, c := range spokes {
+		wsh.endSession(wsh.clients[c])
 		delete(wsh.clients, c)
 		close(*c)
 	}
 }
 
+// endSession records the length of the client's session in the connection
+// metrics.
+func (wsh *WebsocketHub) endSession(ch *clientHubSpoke) {
+	wsh.metricsMtx.Lock()
+	wsh.endedSessions++
+	wsh.sessionTime += time.Since(ch.connected)
+	wsh.metricsMtx.Unlock()
+}
+
+// countMessage records a message sent to or received from a client in the
+// connection metrics.
+func (wsh *WebsocketHub) countMessage() {
+	wsh.metricsMtx.Lock()
+	wsh.msgCount++
+	wsh.metricsMtx.Unlock()
+}
+
+// countHeartbeatTimeout records a client disconnected for failing to respond
+// to the heartbeat in the connection metrics.
+func (wsh *WebsocketHub) countHeartbeatTimeout() {
+	wsh.metricsMtx.Lock()
+	wsh.heartbeatTimeouts++
+	wsh.metricsMtx.Unlock()
+}
+
+// sampleMessageRate updates the message rate with the messages counted since
+// the previous sample.
+func (wsh *WebsocketHub) sampleMessageRate() {
+	wsh.metricsMtx.Lock()
+	defer wsh.metricsMtx.Unlock()
+	now := time.Now()
+	if elapsed := now.Sub(wsh.lastSample).Seconds(); elapsed > 0 {
+		wsh.msgRate = float64(wsh.msgCount-wsh.lastMsgCount) / elapsed
+	}
+	wsh.lastMsgCount = wsh.msgCount
+	wsh.lastSample = now
+}
+
+// Metrics returns the websocket connection metrics: the number of connected
+// clients, the average length of ended sessions, and the message rate.
+func (wsh *WebsocketHub) Metrics() *apitypes.WebsocketMetrics {
+	wsh.metricsMtx.Lock()
+	defer wsh.metricsMtx.Unlock()
+	var avgSession float64
+	if wsh.endedSessions > 0 {
+		avgSession = wsh.sessionTime.Seconds() / float64(wsh.endedSessions)
+	}
+	return &apitypes.WebsocketMetrics{
+		ActiveClients:     wsh.NumClients(),
+		EndedSessions:     wsh.endedSessions,
+		AvgSessionSeconds: avgSession,
+		TotalMessages:     wsh.msgCount,
+		MessagesPerSecond: wsh.msgRate,
+		HeartbeatTimeouts: wsh.heartbeatTimeouts,
+	}
+}
+
 // Periodically ping clients over websocket connection. Stop the ping loop by
 // closing the returned channel.
 func (wsh *WebsocketHub) pingClients() chan<- struct{} {
@@ -180,6 +257,7 @@ func (wsh *WebsocketHub) pingClients() chan<- struct{} {
 		for {
 			select {
 			case <-ticker.C:
+				wsh.sampleMessageRate()
 				wsh.HubRelay <- pstypes.HubMessage{Signal: sigPingAndUserCount}
 			case <-stopPing:
 				return
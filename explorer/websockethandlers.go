@@ -17,12 +17,13 @@ import (
 	"github.com/decred/dcrdata/db/dbtypes/v2"
 	"github.com/decred/dcrdata/explorer/types/v2"
 	pstypes "github.com/decred/dcrdata/pubsub/types/v3"
-	"golang.org/x/net/websocket"
 )
 
 // RootWebsocket is the websocket handler for all pages
 func (exp *explorerUI) RootWebsocket(w http.ResponseWriter, r *http.Request) {
-	wsHandler := websocket.Handler(func(ws *websocket.Conn) {
+	wsHandler := func(ws *wsConn) {
+		log.Tracef("Websocket client connected (msgpack: %v)", ws.msgpack)
+
 		// Create channel to signal updated data availability
 		updateSig := make(hubSpoke, 3)
 		// Create a channel for exchange updates
@@ -44,11 +45,7 @@ func (exp *explorerUI) RootWebsocket(w http.ResponseWriter, r *http.Request) {
 		defer closeWS()
 
 		send := func(webData WebSocketMessage) error {
-			err := ws.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
-			if err != nil && !pstypes.IsWSClosedErr(err) {
-				log.Warnf("SetWriteDeadline failed: %v", err)
-			}
-			if err := websocket.JSON.Send(ws, webData); err != nil {
+			if err := ws.send(&webData); err != nil {
 				// Do not log error if connection is just closed
 				if !pstypes.IsWSClosedErr(err) {
 					log.Debugf("Failed to send web socket message %s: %v", webData.EventId, err)
@@ -57,30 +54,51 @@ func (exp *explorerUI) RootWebsocket(w http.ResponseWriter, r *http.Request) {
 				// the connection and quit.
 				return fmt.Errorf("Send fail")
 			}
+			exp.wsHub.countMessage()
 			return nil
 		}
 
 		requestLimit := 1 << 20
 		// set the max payload size to 1 MB
-		ws.MaxPayloadBytes = requestLimit
+		ws.SetReadLimit(int64(requestLimit))
+
+		// Reads time out if the client stops responding to the heartbeat pings
+		// sent by the send loop below.
+		if err := ws.startHeartbeat(); err != nil {
+			log.Warnf("SetReadDeadline failed: %v", err)
+			return
+		}
+
+		// The receive loop closes readerDone when it returns so that the send
+		// loop quits too.
+		readerDone := make(chan struct{})
 
 		// Start listening for websocket messages from client with raw
 		// transaction bytes (hex encoded) to decode or broadcast.
 		go func() {
+			defer close(readerDone)
 			defer closeWS()
 			for {
 				// Wait to receive a message on the websocket
 				msg := &WebSocketMessage{}
-				err := ws.SetReadDeadline(time.Now().Add(wsReadTimeout))
-				if err != nil && !pstypes.IsWSClosedErr(err) {
-					log.Warnf("SetReadDeadline failed: %v", err)
-				}
-				if err = websocket.JSON.Receive(ws, &msg); err != nil {
-					if err.Error() != "EOF" && !pstypes.IsWSClosedErr(err) {
+				if err := ws.receive(msg); err != nil {
+					switch {
+					case pstypes.IsIOTimeoutErr(err):
+						log.Debugf("Disconnecting websocket client that failed to "+
+							"respond to heartbeat: %v", err)
+						exp.wsHub.countHeartbeatTimeout()
+					case !isWSCloseErr(err) && !pstypes.IsWSClosedErr(err):
 						log.Warnf("websocket client receive error: %v", err)
 					}
 					return
 				}
+				exp.wsHub.countMessage()
+
+				// Any message from the client also shows that it is alive.
+				err := ws.extendReadDeadline()
+				if err != nil && !pstypes.IsWSClosedErr(err) {
+					log.Warnf("SetReadDeadline failed: %v", err)
+				}
 
 				// handle received message according to event ID
 				var webData WebSocketMessage
@@ -164,14 +182,49 @@ func (exp *explorerUI) RootWebsocket(w http.ResponseWriter, r *http.Request) {
 					}
 					webData.Message = string(msg)
 
+				case "getmempoolvotes":
+					// MempoolVotesByBlock. All mempool votes grouped by the block
+					// they validate, including votes for stale tips.
+					inv := exp.MempoolInventory()
+
+					// As with getmempooltxs, skip the update if the client's
+					// mempool ID is current.
+					if msg.Message != "" {
+						clientID, err := strconv.ParseUint(msg.Message, 10, 64)
+						if err != nil {
+							log.Warnf("Unable to parse supplied mempool ID %s", msg.Message)
+						} else if inv.ID() == clientID {
+							continue
+						}
+					}
+
+					msg, err := json.Marshal(inv.VotesByBlock()) // VotesByBlock locks the inventory.
+					if err != nil {
+						log.Warn("Invalid JSON message: ", err)
+						webData.Message = errMsgJSONEncode
+						break
+					}
+					webData.Message = string(msg)
+
+				case "getnextblock":
+					// NextBlockPreview. The likely next block assembled from the
+					// mempool.
+					msg, err := json.Marshal(exp.NextBlockPreview())
+					if err != nil {
+						log.Warn("Invalid JSON message: ", err)
+						webData.Message = errMsgJSONEncode
+						break
+					}
+					webData.Message = string(msg)
+
 				case "getticketpooldata":
 					// Retrieve chart data on the given interval.
 					interval := dbtypes.TimeGroupingFromStr(msg.Message)
 					// Chart height is returned since the cache may be stale,
 					// although it is automatically updated by the first caller
 					// who requests data from a stale cache.
-					timeChart, priceChart, outputsChart, chartHeight, err :=
-						exp.dataSource.TicketPoolVisualization(interval)
+					timeChart, priceChart, outputsChart, ageChart, chartHeight, err :=
+						exp.dataSource.TicketPoolVisualization(r.Context(), interval)
 					if dbtypes.IsTimeoutErr(err) {
 						log.Warnf("TicketPoolVisualization DB timeout: %v", err)
 						webData.Message = "Error: DB timeout"
@@ -207,6 +260,7 @@ func (exp *explorerUI) RootWebsocket(w http.ResponseWriter, r *http.Request) {
 						TimeChart:    timeChart,
 						PriceChart:   priceChart,
 						OutputsChart: outputsChart,
+						AgeChart:     ageChart,
 						Mempool:      mp,
 					}
 
@@ -235,11 +289,25 @@ func (exp *explorerUI) RootWebsocket(w http.ResponseWriter, r *http.Request) {
 			}
 		}()
 
+		heartbeat := time.NewTicker(wsHeartbeatInterval)
+		defer heartbeat.Stop()
+
 		// Send loop (ping, new tx, block, etc. update loop)
 	loop:
 		for {
 			// Wait for signal from the hub to update
 			select {
+			case <-heartbeat.C:
+				if err := ws.ping(); err != nil {
+					if !pstypes.IsWSClosedErr(err) {
+						log.Debugf("Failed to send websocket heartbeat: %v", err)
+					}
+					return
+				}
+
+			case <-readerDone:
+				break loop
+
 			case sig, ok := <-updateSig:
 				// Check if the update channel was closed. Either the websocket
 				// hub will do it after unregistering the client, or forcibly in
@@ -344,7 +412,12 @@ func (exp *explorerUI) RootWebsocket(w http.ResponseWriter, r *http.Request) {
 				break loop
 			} // select
 		} // for a.k.a. loop:
-	}) // wsHandler := websocket.Handler(func(ws *websocket.Conn) {
-
-	wsHandler.ServeHTTP(w, r)
+	} // wsHandler := func(ws *wsConn) {
+
+	ws, err := upgradeWS(w, r)
+	if err != nil {
+		log.Debugf("Failed to upgrade websocket connection: %v", err)
+		return
+	}
+	wsHandler(ws)
 }
@@ -0,0 +1,134 @@
+// Copyright (c) 2020, The Decred developers
+// See LICENSE for details.
+
+package explorer
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/vmihailenco/msgpack/v4"
+)
+
+// wsProtocolMsgpack is the websocket subprotocol a client may request during
+// the handshake to receive msgpack-encoded messages in binary frames instead
+// of JSON text frames.
+const wsProtocolMsgpack = "msgpack"
+
+// wsUpgrader upgrades HTTP connections to websocket connections with
+// permessage-deflate compression enabled when the client supports it. Like
+// golang.org/x/net/websocket, which was previously used, any origin is
+// accepted as long as the Origin header is set.
+var wsUpgrader = websocket.Upgrader{
+	EnableCompression: true,
+	Subprotocols:      []string{wsProtocolMsgpack},
+	CheckOrigin: func(r *http.Request) bool {
+		return r.Header.Get("Origin") != ""
+	},
+}
+
+// wsConn wraps a websocket connection, encoding and decoding WebSocketMessages
+// according to the subprotocol negotiated in the handshake. Sends are safe for
+// concurrent use.
+type wsConn struct {
+	*websocket.Conn
+	writeMtx sync.Mutex
+	msgpack  bool
+}
+
+// upgradeWS upgrades the HTTP connection to a websocket connection. If the
+// upgrade fails, an HTTP error response has already been sent to the client.
+func upgradeWS(w http.ResponseWriter, r *http.Request) (*wsConn, error) {
+	conn, err := wsUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &wsConn{
+		Conn:    conn,
+		msgpack: conn.Subprotocol() == wsProtocolMsgpack,
+	}, nil
+}
+
+// send writes the message to the client, as a msgpack-encoded binary frame if
+// the client negotiated the msgpack subprotocol, or as a JSON text frame
+// otherwise.
+func (ws *wsConn) send(webData *WebSocketMessage) error {
+	msgType, b, err := encodeWSMessage(webData, ws.msgpack)
+	if err != nil {
+		return err
+	}
+
+	ws.writeMtx.Lock()
+	defer ws.writeMtx.Unlock()
+	if err = ws.SetWriteDeadline(time.Now().Add(wsWriteTimeout)); err != nil {
+		return err
+	}
+	return ws.WriteMessage(msgType, b)
+}
+
+// ping sends a ping control frame to the client as a heartbeat. WriteControl
+// may be called concurrently with send.
+func (ws *wsConn) ping() error {
+	return ws.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteTimeout))
+}
+
+// extendReadDeadline gives the client until wsPongTimeout after the next
+// heartbeat to respond, or send another message, before reads time out.
+func (ws *wsConn) extendReadDeadline() error {
+	return ws.SetReadDeadline(time.Now().Add(wsHeartbeatInterval + wsPongTimeout))
+}
+
+// startHeartbeat sets the initial read deadline and a pong handler that
+// extends it whenever the client responds to a heartbeat ping.
+func (ws *wsConn) startHeartbeat() error {
+	ws.SetPongHandler(func(string) error {
+		return ws.extendReadDeadline()
+	})
+	return ws.extendReadDeadline()
+}
+
+// receive reads the next message from the client. Binary frames are decoded
+// as msgpack, and text frames as JSON, regardless of the negotiated
+// subprotocol.
+func (ws *wsConn) receive(msg *WebSocketMessage) error {
+	msgType, b, err := ws.ReadMessage()
+	if err != nil {
+		return err
+	}
+	return decodeWSMessage(msgType, b, msg)
+}
+
+// encodeWSMessage encodes the message for the given encoding, returning the
+// websocket frame type to use.
+func encodeWSMessage(webData *WebSocketMessage, useMsgpack bool) (int, []byte, error) {
+	if useMsgpack {
+		b, err := msgpack.Marshal(webData)
+		return websocket.BinaryMessage, b, err
+	}
+	b, err := json.Marshal(webData)
+	return websocket.TextMessage, b, err
+}
+
+// decodeWSMessage decodes a message received in a websocket frame of the given
+// type.
+func decodeWSMessage(msgType int, b []byte, msg *WebSocketMessage) error {
+	switch msgType {
+	case websocket.BinaryMessage:
+		return msgpack.Unmarshal(b, msg)
+	case websocket.TextMessage:
+		return json.Unmarshal(b, msg)
+	default:
+		return fmt.Errorf("unexpected websocket message type %d", msgType)
+	}
+}
+
+// isWSCloseErr checks if the error indicates that the client closed the
+// connection.
+func isWSCloseErr(err error) bool {
+	return websocket.IsCloseError(err, websocket.CloseNormalClosure,
+		websocket.CloseGoingAway, websocket.CloseNoStatusReceived)
+}
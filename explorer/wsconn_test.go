@@ -0,0 +1,73 @@
+package explorer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestWSMessageEncoding(t *testing.T) {
+	webData := &WebSocketMessage{
+		EventId: "getmempooltxsResp",
+		Message: `{"block_height":420000}`,
+	}
+
+	for _, useMsgpack := range []bool{false, true} {
+		msgType, b, err := encodeWSMessage(webData, useMsgpack)
+		if err != nil {
+			t.Fatalf("encodeWSMessage (msgpack: %v) failed: %v", useMsgpack, err)
+		}
+		wantType := websocket.TextMessage
+		if useMsgpack {
+			wantType = websocket.BinaryMessage
+		}
+		if msgType != wantType {
+			t.Errorf("expected message type %d, got %d", wantType, msgType)
+		}
+
+		var msg WebSocketMessage
+		if err = decodeWSMessage(msgType, b, &msg); err != nil {
+			t.Fatalf("decodeWSMessage (msgpack: %v) failed: %v", useMsgpack, err)
+		}
+		if msg != *webData {
+			t.Errorf("expected %v, got %v", *webData, msg)
+		}
+	}
+
+	if err := decodeWSMessage(websocket.PingMessage, nil, new(WebSocketMessage)); err == nil {
+		t.Errorf("expected an error for a ping message")
+	}
+}
+
+func TestWebsocketHubMetrics(t *testing.T) {
+	wsh := NewWebsocketHub()
+
+	spoke := make(hubSpoke, 1)
+	wsh.registerClient(&clientHubSpoke{new(client), &spoke, nil,
+		time.Now().Add(-10 * time.Second)})
+	for i := 0; i < 4; i++ {
+		wsh.countMessage()
+	}
+	wsh.countHeartbeatTimeout()
+
+	m := wsh.Metrics()
+	if m.ActiveClients != 1 || m.EndedSessions != 0 || m.TotalMessages != 4 ||
+		m.HeartbeatTimeouts != 1 {
+		t.Fatalf("unexpected metrics with a connected client: %+v", m)
+	}
+
+	wsh.unregisterClient(&spoke)
+	m = wsh.Metrics()
+	if m.ActiveClients != 0 || m.EndedSessions != 1 {
+		t.Fatalf("unexpected metrics after the client left: %+v", m)
+	}
+	if m.AvgSessionSeconds < 10 || m.AvgSessionSeconds > 20 {
+		t.Errorf("expected an average session length of about 10s, got %v", m.AvgSessionSeconds)
+	}
+
+	wsh.sampleMessageRate()
+	if m = wsh.Metrics(); m.MessagesPerSecond <= 0 {
+		t.Errorf("expected a positive message rate, got %v", m.MessagesPerSecond)
+	}
+}
@@ -0,0 +1,273 @@
+// Copyright (c) 2020, The Decred developers
+// See LICENSE for details.
+
+// Package feed generates Atom and RSS feeds of new blocks and of transactions
+// with a large total output value.
+package feed
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/decred/dcrd/dcrutil/v2"
+	"github.com/decred/dcrd/wire"
+	"github.com/decred/dcrdata/blockdata/v5"
+)
+
+// DefaultMaxItems is the number of items in each feed when none is specified.
+const DefaultMaxItems = 50
+
+// Item is an entry in a feed. Path is the location of the item's explorer page
+// relative to the site root.
+type Item struct {
+	ID      string
+	Title   string
+	Summary string
+	Path    string
+	Time    time.Time
+}
+
+// Config is the configuration of a Feed. Title prefixes the title of each
+// feed, and transactions with a total output value less than MinTxValue are
+// not included in the transactions feed. BaseURL is the public scheme and host
+// of the site, without a trailing slash, that prefixes the links. The links are
+// relative to the site root if it is empty. The request's Host header is not
+// used, since it is set by the client.
+type Config struct {
+	Title      string
+	MinTxValue dcrutil.Amount
+	MaxItems   int
+	BaseURL    string
+}
+
+// Feed keeps the most recent blocks and large transactions, newest first. It
+// implements blockdata.BlockDataSaver.
+type Feed struct {
+	mtx        sync.RWMutex
+	title      string
+	baseURL    string
+	minTxValue dcrutil.Amount
+	maxItems   int
+	blocks     []*Item
+	txns       []*Item
+}
+
+// NewFeed creates an empty Feed. A MaxItems of zero uses DefaultMaxItems.
+func NewFeed(cfg *Config) *Feed {
+	maxItems := cfg.MaxItems
+	if maxItems <= 0 {
+		maxItems = DefaultMaxItems
+	}
+	return &Feed{
+		title:      cfg.Title,
+		baseURL:    cfg.BaseURL,
+		minTxValue: cfg.MinTxValue,
+		maxItems:   maxItems,
+	}
+}
+
+// Store adds the block, and its transactions with a total output value of at
+// least the configured minimum, to the feeds.
+func (f *Feed) Store(_ *blockdata.BlockData, msgBlock *wire.MsgBlock) error {
+	hash := msgBlock.BlockHash().String()
+	height := msgBlock.Header.Height
+	blockTime := msgBlock.Header.Timestamp
+	numTxns := len(msgBlock.Transactions) + len(msgBlock.STransactions)
+
+	block := &Item{
+		ID:    hash,
+		Title: fmt.Sprintf("Block %d", height),
+		Summary: fmt.Sprintf("Block %d (%s) with %d transactions, %d votes, and %d tickets.",
+			height, hash, numTxns, msgBlock.Header.Voters, msgBlock.Header.FreshStake),
+		Path: "/block/" + hash,
+		Time: blockTime,
+	}
+
+	var txns []*Item
+	addLarge := func(msgTxs []*wire.MsgTx) {
+		for _, msgTx := range msgTxs {
+			var total int64
+			for _, txOut := range msgTx.TxOut {
+				total += txOut.Value
+			}
+			value := dcrutil.Amount(total)
+			if value < f.minTxValue {
+				continue
+			}
+			txid := msgTx.TxHash().String()
+			txns = append(txns, &Item{
+				ID:    txid,
+				Title: fmt.Sprintf("Transaction of %v", value),
+				Summary: fmt.Sprintf("Transaction %s sent %v in block %d.",
+					txid, value, height),
+				Path: "/tx/" + txid,
+				Time: blockTime,
+			})
+		}
+	}
+	addLarge(msgBlock.Transactions)
+	addLarge(msgBlock.STransactions)
+
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.blocks = f.prepend(f.blocks, []*Item{block})
+	f.txns = f.prepend(f.txns, txns)
+	return nil
+}
+
+// prepend adds the new items to the front of items, keeping at most maxItems.
+func (f *Feed) prepend(items, newItems []*Item) []*Item {
+	if len(newItems) == 0 {
+		return items
+	}
+	all := make([]*Item, 0, len(newItems)+len(items))
+	all = append(all, newItems...)
+	all = append(all, items...)
+	if len(all) > f.maxItems {
+		all = all[:f.maxItems]
+	}
+	return all
+}
+
+// Blocks returns the items in the blocks feed, newest first.
+func (f *Feed) Blocks() []*Item {
+	f.mtx.RLock()
+	defer f.mtx.RUnlock()
+	return append([]*Item(nil), f.blocks...)
+}
+
+// Transactions returns the items in the large transactions feed, newest first.
+func (f *Feed) Transactions() []*Item {
+	f.mtx.RLock()
+	defer f.mtx.RUnlock()
+	return append([]*Item(nil), f.txns...)
+}
+
+// BlocksAtom serves the blocks feed as Atom.
+func (f *Feed) BlocksAtom(w http.ResponseWriter, r *http.Request) {
+	f.serveAtom(w, f.title+" blocks", "/feeds/blocks.atom", f.Blocks())
+}
+
+// BlocksRSS serves the blocks feed as RSS.
+func (f *Feed) BlocksRSS(w http.ResponseWriter, r *http.Request) {
+	f.serveRSS(w, f.title+" blocks", f.Blocks())
+}
+
+// TransactionsAtom serves the large transactions feed as Atom.
+func (f *Feed) TransactionsAtom(w http.ResponseWriter, r *http.Request) {
+	f.serveAtom(w, f.txnsTitle(), "/feeds/txns.atom", f.Transactions())
+}
+
+// TransactionsRSS serves the large transactions feed as RSS.
+func (f *Feed) TransactionsRSS(w http.ResponseWriter, r *http.Request) {
+	f.serveRSS(w, f.txnsTitle(), f.Transactions())
+}
+
+func (f *Feed) txnsTitle() string {
+	return fmt.Sprintf("%s transactions of at least %v", f.title, f.minTxValue)
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+}
+
+type atomEntry struct {
+	Title   string   `xml:"title"`
+	ID      string   `xml:"id"`
+	Link    atomLink `xml:"link"`
+	Updated string   `xml:"updated"`
+	Summary string   `xml:"summary"`
+}
+
+type atomFeed struct {
+	XMLName xml.Name     `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string       `xml:"title"`
+	ID      string       `xml:"id"`
+	Links   []atomLink   `xml:"link"`
+	Updated string       `xml:"updated"`
+	Author  string       `xml:"author>name"`
+	Entries []*atomEntry `xml:"entry"`
+}
+
+func (f *Feed) serveAtom(w http.ResponseWriter, title, path string, items []*Item) {
+	base := f.baseURL
+	feed := &atomFeed{
+		Title: title,
+		ID:    base + path,
+		Links: []atomLink{
+			{Href: base + path, Rel: "self"},
+			{Href: base + "/"},
+		},
+		Updated: time.Unix(0, 0).UTC().Format(time.RFC3339),
+		Author:  "dcrdata",
+		Entries: make([]*atomEntry, 0, len(items)),
+	}
+	if len(items) > 0 {
+		feed.Updated = items[0].Time.UTC().Format(time.RFC3339)
+	}
+	for _, item := range items {
+		feed.Entries = append(feed.Entries, &atomEntry{
+			Title:   item.Title,
+			ID:      base + item.Path,
+			Link:    atomLink{Href: base + item.Path},
+			Updated: item.Time.UTC().Format(time.RFC3339),
+			Summary: item.Summary,
+		})
+	}
+	writeXML(w, "application/atom+xml", feed)
+}
+
+type rssItem struct {
+	Title       string `xml:"title"`
+	Link        string `xml:"link"`
+	Description string `xml:"description"`
+	GUID        string `xml:"guid"`
+	PubDate     string `xml:"pubDate"`
+}
+
+type rssFeed struct {
+	XMLName     xml.Name   `xml:"rss"`
+	Version     string     `xml:"version,attr"`
+	Title       string     `xml:"channel>title"`
+	Link        string     `xml:"channel>link"`
+	Description string     `xml:"channel>description"`
+	Items       []*rssItem `xml:"channel>item"`
+}
+
+func (f *Feed) serveRSS(w http.ResponseWriter, title string, items []*Item) {
+	base := f.baseURL
+	feed := &rssFeed{
+		Version:     "2.0",
+		Title:       title,
+		Link:        base + "/",
+		Description: title,
+		Items:       make([]*rssItem, 0, len(items)),
+	}
+	for _, item := range items {
+		feed.Items = append(feed.Items, &rssItem{
+			Title:       item.Title,
+			Link:        base + item.Path,
+			Description: item.Summary,
+			GUID:        base + item.Path,
+			PubDate:     item.Time.UTC().Format(time.RFC1123Z),
+		})
+	}
+	writeXML(w, "application/rss+xml", feed)
+}
+
+func writeXML(w http.ResponseWriter, contentType string, v interface{}) {
+	b, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		log.Errorf("Failed to encode feed: %v", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError),
+			http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", contentType+"; charset=utf-8")
+	_, _ = w.Write([]byte(xml.Header))
+	_, _ = w.Write(b)
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2020, The Decred developers
+
+package feed
+
+import (
+	"encoding/xml"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/dcrutil/v2"
+	"github.com/decred/dcrd/wire"
+)
+
+func testBlock(height uint32, values ...int64) *wire.MsgBlock {
+	msgBlock := &wire.MsgBlock{
+		Header: wire.BlockHeader{
+			Height:    height,
+			Timestamp: time.Unix(1580000000+int64(height)*300, 0),
+		},
+	}
+	for i, v := range values {
+		tx := wire.NewMsgTx()
+		tx.LockTime = uint32(i) // distinct hashes
+		tx.AddTxOut(wire.NewTxOut(v, nil))
+		msgBlock.Transactions = append(msgBlock.Transactions, tx)
+	}
+	return msgBlock
+}
+
+func TestFeed(t *testing.T) {
+	f := NewFeed(&Config{
+		Title:      "dcrdata testnet",
+		MinTxValue: 100 * dcrutil.AtomsPerCoin,
+		MaxItems:   2,
+		BaseURL:    "https://explorer.example.com",
+	})
+	for h := uint32(1); h <= 3; h++ {
+		if err := f.Store(nil, testBlock(h, 50*dcrutil.AtomsPerCoin, int64(h)*100*dcrutil.AtomsPerCoin)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	blocks := f.Blocks()
+	if len(blocks) != 2 || blocks[0].Title != "Block 3" || blocks[1].Title != "Block 2" {
+		t.Fatalf("unexpected blocks feed %v", blocks)
+	}
+	txns := f.Transactions()
+	if len(txns) != 2 || txns[0].Title != "Transaction of 300 DCR" {
+		t.Fatalf("unexpected transactions feed %v", txns)
+	}
+
+	r := httptest.NewRequest("GET", "/feeds/blocks.atom", nil)
+	r.Host = "evil.example.com"
+	w := httptest.NewRecorder()
+	f.BlocksAtom(w, r)
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/atom+xml") {
+		t.Errorf("wrong content type %q", ct)
+	}
+	var atom atomFeed
+	if err := xml.Unmarshal(w.Body.Bytes(), &atom); err != nil {
+		t.Fatalf("invalid atom feed: %v", err)
+	}
+	if len(atom.Entries) != 2 || atom.Entries[0].Link.Href != "https://explorer.example.com"+blocks[0].Path {
+		t.Errorf("unexpected atom entries %v", atom.Entries)
+	}
+
+	w = httptest.NewRecorder()
+	f.TransactionsRSS(w, httptest.NewRequest("GET", "/feeds/txns.rss", nil))
+	var rss rssFeed
+	if err := xml.Unmarshal(w.Body.Bytes(), &rss); err != nil {
+		t.Fatalf("invalid rss feed: %v", err)
+	}
+	if len(rss.Items) != 2 || rss.Items[1].Link != "https://explorer.example.com"+txns[1].Path {
+		t.Errorf("unexpected rss items %v", rss.Items)
+	}
+
+	// Without a base URL, the links are relative to the site root.
+	f = NewFeed(&Config{Title: "dcrdata testnet"})
+	f.Store(nil, testBlock(1))
+	w = httptest.NewRecorder()
+	f.BlocksRSS(w, r)
+	rss = rssFeed{}
+	if err := xml.Unmarshal(w.Body.Bytes(), &rss); err != nil {
+		t.Fatalf("invalid rss feed: %v", err)
+	}
+	if rss.Link != "/" || len(rss.Items) != 1 || rss.Items[0].Link != f.Blocks()[0].Path {
+		t.Errorf("unexpected relative rss feed %v", rss)
+	}
+}
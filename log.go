@@ -22,8 +22,16 @@ import (
 	"github.com/decred/dcrdata/stakedb/v3"
 	"github.com/decred/dcrdata/v5/api"
 	"github.com/decred/dcrdata/v5/api/insight"
+	"github.com/decred/dcrdata/v5/blockarchive"
+	"github.com/decred/dcrdata/v5/dailyexport"
+	"github.com/decred/dcrdata/v5/eventstream"
 	"github.com/decred/dcrdata/v5/explorer"
+	"github.com/decred/dcrdata/v5/feed"
+	"github.com/decred/dcrdata/v5/maintenance"
 	notify "github.com/decred/dcrdata/v5/notification"
+	"github.com/decred/dcrdata/v5/rpc/rpcserver"
+	"github.com/decred/dcrdata/v5/watch"
+	"github.com/decred/dcrdata/v5/webhook"
 	"github.com/decred/slog"
 	"github.com/jrick/logrotate/rotator"
 )
@@ -70,6 +78,14 @@ var (
 	xcBotLog      = backendLog.Logger("XBOT")
 	agendasLog    = backendLog.Logger("AGDB")
 	proposalsLog  = backendLog.Logger("PRDB")
+	maintLog      = backendLog.Logger("MANT")
+	webhookLog    = backendLog.Logger("HOOK")
+	feedLog       = backendLog.Logger("FEED")
+	archiveLog    = backendLog.Logger("BARC")
+	streamLog     = backendLog.Logger("KAFK")
+	watchLog      = backendLog.Logger("WTCH")
+	grpcLog       = backendLog.Logger("GRPC")
+	exportLog     = backendLog.Logger("DEXP")
 )
 
 // Initialize package-global logger variables.
@@ -89,6 +105,14 @@ func init() {
 	exchanges.UseLogger(xcBotLog)
 	agendas.UseLogger(agendasLog)
 	politeia.UseLogger(proposalsLog)
+	maintenance.UseLogger(maintLog)
+	webhook.UseLogger(webhookLog)
+	feed.UseLogger(feedLog)
+	blockarchive.UseLogger(archiveLog)
+	eventstream.UseLogger(streamLog)
+	watch.UseLogger(watchLog)
+	rpcserver.UseLogger(grpcLog)
+	dailyexport.UseLogger(exportLog)
 }
 
 // subsystemLoggers maps each subsystem identifier to its associated logger.
@@ -107,6 +131,14 @@ var subsystemLoggers = map[string]slog.Logger{
 	"XBOT": xcBotLog,
 	"AGDB": agendasLog,
 	"PRDB": proposalsLog,
+	"MANT": maintLog,
+	"HOOK": webhookLog,
+	"FEED": feedLog,
+	"BARC": archiveLog,
+	"KAFK": streamLog,
+	"WTCH": watchLog,
+	"GRPC": grpcLog,
+	"DEXP": exportLog,
 }
 
 // initLogRotator initializes the logging rotater to write logs to logFile and
@@ -7,6 +7,7 @@ package main
 import (
 	"context"
 	"fmt"
+	stdlog "log"
 	"net"
 	"net/http"
 	_ "net/http/pprof"
@@ -21,13 +22,16 @@ import (
 	"time"
 
 	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/dcrutil/v2"
 	"github.com/decred/dcrd/rpcclient/v5"
+	"github.com/decred/dcrd/wire"
 
 	"github.com/decred/dcrdata/blockdata/v5"
 	"github.com/decred/dcrdata/db/cache/v3"
 	"github.com/decred/dcrdata/db/dbtypes/v2"
 	"github.com/decred/dcrdata/db/dcrpg/v5"
 	"github.com/decred/dcrdata/exchanges/v2"
+	exptypes "github.com/decred/dcrdata/explorer/types/v2"
 	"github.com/decred/dcrdata/gov/v3/agendas"
 	"github.com/decred/dcrdata/gov/v3/politeia"
 	"github.com/decred/dcrdata/mempool/v5"
@@ -39,15 +43,48 @@ import (
 	"github.com/decred/dcrdata/stakedb/v3"
 	"github.com/decred/dcrdata/v5/api"
 	"github.com/decred/dcrdata/v5/api/insight"
+	"github.com/decred/dcrdata/v5/blockarchive"
+	"github.com/decred/dcrdata/v5/dailyexport"
+	"github.com/decred/dcrdata/v5/eventstream"
 	"github.com/decred/dcrdata/v5/explorer"
+	"github.com/decred/dcrdata/v5/feed"
+	"github.com/decred/dcrdata/v5/maintenance"
 	notify "github.com/decred/dcrdata/v5/notification"
+	"github.com/decred/dcrdata/v5/rpc/rpcserver"
 	"github.com/decred/dcrdata/v5/version"
+	"github.com/decred/dcrdata/v5/watch"
+	"github.com/decred/dcrdata/v5/webhook"
 
 	"github.com/dmigwi/go-piparser/proposals"
 	"github.com/go-chi/chi"
+	chimw "github.com/go-chi/chi/middleware"
 	"github.com/google/gops/agent"
 )
 
+// scriptAnomalyEvent is the webhook event name for the outputs of a new block
+// with nonstandard scripts or script versions other than 0.
+const scriptAnomalyEvent = "script_anomaly"
+
+// agendaStatusEvent is the webhook event name for an agenda reaching quorum,
+// locking in, activating, or failing.
+const agendaStatusEvent = "agenda_status"
+
+// blockAnomalyEvent is the webhook event name for the anomalies of a new main
+// chain block, or a deep reorg.
+const blockAnomalyEvent = "block_anomaly"
+
+// addressActivityEvent is the webhook event name for the activity of the
+// watched addresses in a new main chain block.
+const addressActivityEvent = "address_activity"
+
+// addressBalanceEvent is the webhook event name for the balances of watched
+// addresses crossing their thresholds in a new main chain block.
+const addressBalanceEvent = "address_balance"
+
+// eventStreamStateFile is the name of the file in the data directory in which
+// the height of the last block published to Kafka is saved.
+const eventStreamStateFile = "eventstream.json"
+
 func main() {
 	// Create a context that is cancelled when a shutdown request is received
 	// via requestShutdown.
@@ -79,6 +116,16 @@ func _main(ctx context.Context) error {
 		}
 	}()
 
+	// In privacy mode, log HTTP requests without the client address, host, or
+	// request URI. This must be set before the routers are created.
+	if cfg.Privacy {
+		log.Infof("Privacy mode enabled. The exchange monitor and politeia " +
+			"proposals are disabled.")
+		chimw.DefaultLogger = chimw.RequestLogger(&m.PrivateLogFormatter{
+			Logger: stdlog.New(os.Stdout, "", stdlog.LstdFlags),
+		})
+	}
+
 	if cfg.CPUProfile != "" {
 		var f *os.File
 		f, err = os.Create(cfg.CPUProfile)
@@ -210,18 +257,54 @@ func _main(ctx context.Context) error {
 		AddrCacheAddrCap:     cfg.AddrCacheLimit,
 		AddrCacheRowCap:      rowCap,
 		AddrCacheUTXOByteCap: cfg.AddrCacheUXTOCap,
+		HeightNtfnBuffer:     cfg.HeightNtfnBuffer,
+		NotifyChannel:        cfg.PGNotifyChannel,
+		ArchiveDepth:         cfg.ArchiveSpentDepth,
+		ArchiveInterval:      cfg.ArchiveSpentInterval,
+		AnomalyRules: &dbtypes.BlockAnomalyRules{
+			EmptyBlocks:   !cfg.NoAnomalyEmptyBlocks,
+			MinVotes:      cfg.AnomalyMinVotes,
+			MaxSize:       cfg.AnomalyMaxBlockSize,
+			MaxReorgDepth: cfg.AnomalyMaxReorgDepth,
+		},
 	}
 
 	mpChecker := rpcutils.NewMempoolAddressChecker(dcrdClient, activeChain)
 	chainDB, err := dcrpg.NewChainDBWithCancel(ctx, &dbCfg,
 		stakeDB, mpChecker, piParser, dcrdClient, requestShutdown)
 	if chainDB != nil {
-		defer chainDB.Close()
+		// Let any block being stored finish before the DB is closed.
+		defer func() {
+			ctxShutdown, cancel := context.WithTimeout(context.Background(), time.Minute)
+			defer cancel()
+			if err := chainDB.Shutdown(ctxShutdown); err != nil {
+				log.Errorf("ChainDB shutdown: %v", err)
+			}
+		}()
 	}
 	if err != nil {
 		return err
 	}
 
+	if cfg.ImportSnapshot != "" {
+		log.Infof("Importing chain snapshot from %s...", cfg.ImportSnapshot)
+		manifest, err := chainDB.ImportSnapshot(cfg.ImportSnapshot)
+		if err == nil {
+			log.Infof("Imported chain snapshot at block %s (%d). Restart dcrdata "+
+				"without --import-snapshot to resume syncing.", manifest.BlockHash,
+				manifest.Height)
+		}
+		requestShutdown()
+		return err
+	}
+
+	if cfg.UnarchiveSpent {
+		log.Infof("Restoring archived spent rows...")
+		_, _, err = chainDB.UnarchiveSpent(ctx)
+		requestShutdown()
+		return err
+	}
+
 	if cfg.DropIndexes {
 		log.Info("Dropping all table indexing and quitting...")
 		err = chainDB.DeindexAll()
@@ -250,6 +333,108 @@ func _main(ctx context.Context) error {
 		}
 	}
 
+	if cfg.CheckAddrSpending || cfg.RepairAddrSpending {
+		if len(missingIndexes) > 0 {
+			requestShutdown()
+			return fmt.Errorf("unable to check addresses table spending info " +
+				"with missing indexes. Sync the database first")
+		}
+		repair := cfg.RepairAddrSpending
+		log.Infof("Checking addresses table spending info from height %d (repair = %v)...",
+			cfg.AddrSpendingStart, repair)
+		mismatched, repaired, resumeHeight, err := chainDB.CheckSpendingInfoInAddresses(
+			cfg.AddrSpendingStart, cfg.AddrSpendingBatch, repair)
+		log.Infof("Found %d address rows with missing or mismatched spending info. "+
+			"Repaired %d.", mismatched, repaired)
+		if err != nil {
+			log.Errorf("Address spending info check failed. "+
+				"Resume with --addr-spending-start=%d.", resumeHeight)
+		}
+		requestShutdown()
+		return err
+	}
+
+	if cfg.AuditIntegrity || cfg.PruneOrphans {
+		prune := cfg.PruneOrphans
+		log.Infof("Auditing database integrity (prune = %v)...", prune)
+		results, err := chainDB.AuditIntegrity(prune)
+		for _, res := range results {
+			switch {
+			case res.Orphans == 0:
+				log.Infof(" - %s: none", res.Check)
+			case !res.Repairable:
+				log.Warnf(" - %s: %d (not repairable, reindex the affected blocks)",
+					res.Check, res.Orphans)
+			default:
+				log.Warnf(" - %s: %d, pruned %d", res.Check, res.Orphans, res.Pruned)
+			}
+		}
+		if err != nil {
+			log.Errorf("Database integrity audit failed: %v", err)
+		}
+		requestShutdown()
+		return err
+	}
+
+	if cfg.ExportSnapshot > 0 {
+		log.Infof("Exporting chain snapshot at height %d to %s...",
+			cfg.ExportSnapshot, cfg.SnapshotDir)
+		manifest, err := chainDB.ExportSnapshot(cfg.ExportSnapshot, cfg.SnapshotDir)
+		if err == nil {
+			log.Infof("Exported chain snapshot at block %s (%d).",
+				manifest.BlockHash, manifest.Height)
+		}
+		requestShutdown()
+		return err
+	}
+
+	// Archive blocks on disk as they are stored, both during sync and for new
+	// blocks, to serve raw blocks and headers without dcrd.
+	var blockArchive *blockarchive.Archive
+	if cfg.BlockArchiveDir != "" {
+		blockArchive, err = blockarchive.New(cfg.BlockArchiveDir, cfg.BlockArchiveRetention)
+		if err != nil {
+			return fmt.Errorf("failed to open block archive: %v", err)
+		}
+		chainDB.RegisterBlockStoredHandler(func(msgBlock *wire.MsgBlock, isMainchain bool) {
+			if err := blockArchive.Add(msgBlock, isMainchain); err != nil {
+				log.Errorf("Failed to archive block %v: %v", msgBlock.BlockHash(), err)
+			}
+		})
+	}
+
+	// Export each UTC day's main chain data to gzipped CSV files once the DB is
+	// in sync.
+	var dailyExports *dailyexport.Exporter
+	if cfg.DailyExportDir != "" {
+		dailyExports, err = dailyexport.New(cfg.DailyExportDir, chainDB,
+			activeChain.GenesisBlock.Header.Timestamp)
+		if err != nil {
+			return fmt.Errorf("failed to open daily exports: %v", err)
+		}
+	}
+
+	// Publish records of the stored blocks, transactions, outputs and address
+	// balance changes to Kafka, both during sync and for new blocks, and the
+	// reorganization and block disapproval events.
+	if len(cfg.KafkaBrokers) > 0 {
+		publisher, err := eventstream.NewPublisher(&eventstream.Config{
+			Brokers:     cfg.KafkaBrokers,
+			TopicPrefix: cfg.KafkaTopicPrefix,
+			Source:      chainDB,
+			StateFile:   filepath.Join(cfg.DataDir, eventStreamStateFile),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create event stream publisher: %v", err)
+		}
+		go publisher.Run(ctx)
+		chainDB.RegisterStoredRowsHandler(publisher.Publish)
+		chainDB.RegisterReorgHandler(publisher.PublishReorg)
+		chainDB.RegisterInvalidationHandler(publisher.PublishDisapproval)
+		log.Infof("Publishing stored rows to Kafka topics %s* at %s.",
+			cfg.KafkaTopicPrefix, strings.Join(cfg.KafkaBrokers, ", "))
+	}
+
 	// Heights gets the current height of each DB, the minimum of the DB heights
 	// (dbHeight), and the chain server height.
 	Heights := func() (nodeHeight, chainDBHeight int64, err error) {
@@ -259,7 +444,7 @@ func _main(ctx context.Context) error {
 			return
 		}
 
-		chainDBHeight, err = chainDB.HeightDB()
+		chainDBHeight, err = chainDB.HeightDB(ctx)
 		if err != nil {
 			log.Errorf("chainDB.HeightDB failed: %v", err)
 			return
@@ -282,7 +467,10 @@ func _main(ctx context.Context) error {
 	}
 
 	if auxHeight > -1 {
-		orphaned, err := rpcutils.OrphanedTipLength(ctx, dcrdClient, auxHeight, chainDB.BlockHash)
+		blockHash := func(height int64) (string, error) {
+			return chainDB.BlockHash(ctx, height)
+		}
+		orphaned, err := rpcutils.OrphanedTipLength(ctx, dcrdClient, auxHeight, blockHash)
 		if err != nil {
 			return fmt.Errorf("Failed to compare tip blocks for the aux DB: %v", err)
 		}
@@ -324,7 +512,7 @@ func _main(ctx context.Context) error {
 	}
 
 	// Get the last block added to the aux DB.
-	lastBlockPG, err := chainDB.HeightDB()
+	lastBlockPG, err := chainDB.HeightDB(ctx)
 	if err != nil {
 		return fmt.Errorf("Unable to get height from PostgreSQL DB: %v", err)
 	}
@@ -336,6 +524,10 @@ func _main(ctx context.Context) error {
 	}
 
 	charts := cache.NewChartData(ctx, uint32(heightDB), activeChain)
+	charts.Tiers = cache.ChartTiers{
+		BlockSpan: cfg.ChartsBlockSpan,
+		DaySpan:   cfg.ChartsDaySpan,
+	}
 	chainDB.RegisterCharts(charts)
 
 	// Aux DB height and stakedb height must be equal. StakeDatabase will
@@ -449,6 +641,8 @@ func _main(ctx context.Context) error {
 			log.Infof("ExchangeBot monitoring %s", xcList)
 			wg.Add(1)
 			go xcBot.Start(ctx, &wg)
+			wg.Add(1)
+			go storeDailyPrices(ctx, &wg, xcBot, chainDB)
 		}
 	}
 
@@ -485,8 +679,10 @@ func _main(ctx context.Context) error {
 	// value throughout.
 	var tracker *agendas.VoteTracker
 	if !cfg.SimNet {
-		tracker, err = agendas.NewVoteTracker(activeChain, dcrdClient,
-			chainDB.AgendaVoteCounts)
+		voteCounts := func(agendaID string) (uint32, uint32, uint32, error) {
+			return chainDB.AgendaVoteCounts(ctx, agendaID)
+		}
+		tracker, err = agendas.NewVoteTracker(activeChain, dcrdClient, voteCounts)
 		if err != nil {
 			return fmt.Errorf("Unable to initialize vote tracker: %v", err)
 		}
@@ -526,6 +722,145 @@ func _main(ctx context.Context) error {
 	blockDataSavers = append(blockDataSavers, psHub)
 	mempoolSavers = append(mempoolSavers, psHub) // individual transactions are from mempool monitor
 
+	// Notify websocket clients and webhooks of blocks invalidated by votes,
+	// and thus of their reversed transactions, and of agenda voting
+	// milestones. Also notify websocket clients when the votes resolve the
+	// pending approval of the previous block.
+	hooks := webhook.NewPoster(cfg.Webhooks, 0)
+	chainDB.RegisterInvalidationHandler(func(inv *exptypes.BlockInvalidation) {
+		psHub.BlockInvalidated(inv)
+		hooks.Post(dcrpg.ChainEventInvalidation, inv)
+	})
+	chainDB.RegisterAgendaStatusHandler(func(asc *exptypes.AgendaStatusChange) {
+		psHub.AgendaStatusChanged(asc)
+		hooks.Post(agendaStatusEvent, asc)
+	})
+	chainDB.RegisterBlockValidityHandler(psHub.BlockValidityResolved)
+	if cfg.AlertScriptAnomalies {
+		chainDB.RegisterScriptAnomalyHandler(func(anomalies []*dbtypes.ScriptAnomaly) {
+			hooks.Post(scriptAnomalyEvent, anomalies)
+		})
+	}
+	if cfg.AlertBlockAnomalies {
+		chainDB.RegisterBlockAnomalyHandler(func(anomalies []*dbtypes.BlockAnomaly) {
+			hooks.Post(blockAnomalyEvent, anomalies)
+		})
+	}
+
+	// Report the activity of the watched addresses in new blocks to the
+	// webhooks and by e-mail.
+	if len(cfg.WatchAddresses) > 0 {
+		rules, err := watch.ParseRules(cfg.WatchAddresses, activeChain)
+		if err != nil {
+			requestShutdown()
+			return err
+		}
+		watcher := watch.NewWatcher(rules, func(address string, height int64) (int64, error) {
+			bal, err := chainDB.AddressBalanceAt(ctx, address, height)
+			if err != nil {
+				return 0, err
+			}
+			return bal.Balance, nil
+		})
+		if cfg.AlertAddressActivity {
+			watcher.AddNotifier(func(acts []*watch.Activity) {
+				hooks.Post(addressActivityEvent, acts)
+			})
+		}
+		watcher.AddBalanceNotifier(func(alerts []*watch.BalanceAlert) {
+			hooks.Post(addressBalanceEvent, alerts)
+		})
+		if cfg.SMTPServer != "" {
+			mailer, err := watch.NewMailer(&watch.MailConfig{
+				Server:         cfg.SMTPServer,
+				Username:       cfg.SMTPUser,
+				Password:       cfg.SMTPPass,
+				From:           cfg.EmailFrom,
+				To:             cfg.EmailTo,
+				DigestInterval: cfg.EmailDigest,
+				Subject:        "[dcrdata " + activeChain.Name + "]",
+			})
+			if err != nil {
+				requestShutdown()
+				return err
+			}
+			watcher.AddNotifier(mailer.Notify)
+			watcher.AddBalanceNotifier(mailer.NotifyBalance)
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				mailer.Run(ctx)
+			}()
+		}
+		// Only the activity in new blocks is reported, not during sync.
+		chainDB.RegisterStoredRowsHandler(func(stored *dbtypes.StoredBlock) {
+			if !chainDB.InBatchSync {
+				watcher.BlockStored(stored)
+			}
+		})
+		chainDB.RegisterReorgHandler(watcher.Reorg)
+		log.Infof("Watching %d addresses.", len(rules))
+	}
+
+	// The rate limiter is shared by the API, websocket and gRPC endpoints. It
+	// is nil, and does not limit, if ratelimit-rps is not set.
+	rateLimiter := m.NewRateLimiter(&m.RateLimiterConfig{
+		Default: m.RateBudget{
+			Rate:  cfg.RateLimitRPS,
+			Burst: cfg.RateLimitBurst,
+		},
+		Expensive: m.RateBudget{
+			Rate:  cfg.RateLimitExpensiveRPS,
+			Burst: cfg.RateLimitExpensiveBurst,
+		},
+		Keys:      cfg.RateLimitKeys,
+		KeyFactor: cfg.RateLimitKeyFactor,
+	})
+	if rateLimiter != nil {
+		log.Infof("Rate limiting API, websocket and gRPC requests to %.4g/s per client.",
+			cfg.RateLimitRPS)
+	}
+
+	// Serve the gRPC services, streaming new blocks and mempool transactions
+	// to subscribers.
+	var rpcServer *rpcserver.Server
+	if cfg.GRPCListen != "" {
+		rpcServer, err = rpcserver.NewServer(&rpcserver.Config{
+			Store:       chainDB,
+			Params:      activeChain,
+			CertFile:    cfg.GRPCCert,
+			KeyFile:     cfg.GRPCKey,
+			RateLimiter: rateLimiter,
+		})
+		if err != nil {
+			requestShutdown()
+			return fmt.Errorf("failed to create gRPC server: %v", err)
+		}
+		listener, err := net.Listen("tcp", cfg.GRPCListen)
+		if err != nil {
+			requestShutdown()
+			return fmt.Errorf("failed to listen for gRPC on %s: %v", cfg.GRPCListen, err)
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := rpcServer.Serve(ctx, listener); err != nil {
+				log.Errorf("gRPC server failed: %v", err)
+			}
+		}()
+		blockDataSavers = append(blockDataSavers, rpcServer)
+		log.Infof("Serving gRPC on %s.", cfg.GRPCListen)
+	}
+
+	// Atom/RSS feeds of new blocks and large transactions.
+	feedTxMinValue, _ := dcrutil.NewAmount(cfg.FeedTxMinValue)
+	feeds := feed.NewFeed(&feed.Config{
+		Title:      "dcrdata " + activeChain.Name,
+		MinTxValue: feedTxMinValue,
+		BaseURL:    cfg.FeedBaseURL,
+	})
+	blockDataSavers = append(blockDataSavers, feeds)
+
 	// Store explorerUI data after pubsubhub.
 	blockDataSavers = append(blockDataSavers, explore)
 	mempoolSavers = append(mempoolSavers, explore)
@@ -546,6 +881,9 @@ func _main(ctx context.Context) error {
 	signalToPSHub := psHub.HubRelay()
 	signalToExplorer := explore.MempoolSignal()
 	mempoolSigOuts := []chan<- pstypes.HubMessage{signalToPSHub, signalToExplorer}
+	if rpcServer != nil {
+		mempoolSigOuts = append(mempoolSigOuts, rpcServer.MempoolSignal())
+	}
 	mpm, err := mempool.NewMempoolMonitor(ctx, mpoolCollector, mempoolSavers,
 		activeChain, dcrdClient, mempoolSigOuts, true)
 
@@ -556,6 +894,20 @@ func _main(ctx context.Context) error {
 		return fmt.Errorf("NewMempoolMonitor: %v", err)
 	}
 
+	// Restore the times that the transactions still in mempool were first seen
+	// before the last shutdown, and save them again on exit.
+	mempoolPath := filepath.Join(cfg.DataDir, cfg.MempoolFile)
+	if n, err := mpm.Load(mempoolPath); err != nil {
+		log.Warnf("Unable to load the saved mempool: %v", err)
+	} else if n > 0 {
+		log.Infof("Restored %d saved mempool transactions.", n)
+	}
+	defer func() {
+		if err := mpm.Save(mempoolPath); err != nil {
+			log.Errorf("Unable to save the mempool: %v", err)
+		}
+	}()
+
 	// Use the MempoolMonitor in aux DB to get unconfirmed transaction data.
 	chainDB.UseMempoolChecker(mpm)
 
@@ -591,8 +943,9 @@ func _main(ctx context.Context) error {
 	// explorer pages.
 	if displaySyncStatusPage {
 		// Start goroutines that keep the update the shared progress bar data,
-		// and signal the websocket hub to send progress updates to clients.
-		barLoad = make(chan *dbtypes.ProgressBarLoad, 2)
+		// and signal the websocket hub to send progress updates to clients. The
+		// buffer allows an update for each progress bar to be queued.
+		barLoad = make(chan *dbtypes.ProgressBarLoad, 8)
 		explore.BeginSyncStatusUpdates(barLoad)
 	} else {
 		// Start a goroutine to update the explorer pages when the DB sync
@@ -649,7 +1002,42 @@ func _main(ctx context.Context) error {
 	defer insightSocketServer.Close()
 	blockDataSavers = append(blockDataSavers, insightSocketServer)
 
+	// Set up the periodic maintenance tasks. They are started after the
+	// initial sync.
+	maint, err := newMaintenanceScheduler(cfg, chainDB, charts, mpm)
+	if err != nil {
+		return fmt.Errorf("failed to set up maintenance tasks: %v", err)
+	}
+
 	// Start dcrdata's JSON web API.
+	// The response cache is nil, and does not cache, if api-cache is not set.
+	var respCache *m.ResponseCache
+	if cfg.APICache {
+		respCache, err = m.NewResponseCache(&m.ResponseCacheConfig{
+			Dir:        filepath.Join(cfg.DataDir, defaultAPICacheDir),
+			Prefixes:   api.CachedRoutePrefixes,
+			Exclude:    api.UncachedRoutePrefixes,
+			Params:     api.CachedRouteParams,
+			MaxEntries: cfg.APICacheMaxEntries,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create the API response cache: %v", err)
+		}
+		log.Infof("Caching the responses of expensive API routes.")
+	}
+
+	// The usage tracker is nil, and does not count requests, if usage-stats
+	// is not set.
+	var usage *m.UsageTracker
+	if cfg.UsageStats {
+		usage = m.NewUsageTracker(&m.UsageTrackerConfig{
+			Window:  cfg.UsageWindow,
+			Keys:    cfg.RateLimitKeys,
+			HideIPs: cfg.Privacy,
+		})
+		log.Infof("Counting API usage by route and client over %v.", cfg.UsageWindow)
+	}
+
 	app := api.NewContext(&api.AppContextConfig{
 		Client:             dcrdClient,
 		Params:             activeChain,
@@ -659,6 +1047,15 @@ func _main(ctx context.Context) error {
 		MaxAddrs:           cfg.MaxCSVAddrs,
 		Charts:             charts,
 		IsPiparserDisabled: cfg.DisablePiParser,
+		Maintenance:        maint,
+		WebsocketMetrics:   explore.WebsocketMetrics,
+		BlockArchive:       blockArchive,
+		AdminToken:         cfg.AdminToken,
+		RateLimiter:        rateLimiter,
+		ResponseCache:      respCache,
+		UsageTracker:       usage,
+		MempoolGraph:       mpm.TxGraph(),
+		DailyExports:       dailyExports,
 	})
 	// Start the notification hander for keeping /status up-to-date.
 	wg.Add(1)
@@ -685,8 +1082,20 @@ func _main(ctx context.Context) error {
 		r.Get("/", explore.Home)
 		r.Get("/visualblocks", explore.VisualBlocks)
 	})
-	webMux.Get("/ws", explore.RootWebsocket)
-	webMux.Get("/ps", psHub.WebSocketHandler)
+	webMux.Group(func(r chi.Router) {
+		if cfg.UseRealIP {
+			r.Use(chimw.RealIP)
+		}
+		r.Use(rateLimiter.Limit)
+		r.Get("/ws", explore.RootWebsocket)
+		r.Get("/ps", psHub.WebSocketHandler)
+	})
+	webMux.Route("/feeds", func(r chi.Router) {
+		r.Get("/blocks.atom", feeds.BlocksAtom)
+		r.Get("/blocks.rss", feeds.BlocksRSS)
+		r.Get("/txns.atom", feeds.TransactionsAtom)
+		r.Get("/txns.rss", feeds.TransactionsRSS)
+	})
 
 	// Make the static assets available under a path with the given prefix.
 	mountAssetPaths := func(pathPrefix string) {
@@ -880,6 +1289,23 @@ func _main(ctx context.Context) error {
 		return err
 	}
 
+	// Re-process a block range now that it is synced, before new blocks are
+	// stored, and exit.
+	if cfg.Reindex != "" {
+		log.Infof("Reindexing blocks [%d,%d] (dry run = %v)...",
+			cfg.reindexStart, cfg.reindexEnd, cfg.ReindexDryRun)
+		_, resumeHeight, err := chainDB.ReindexBlockRange(cfg.reindexStart,
+			cfg.reindexEnd, cfg.ReindexDryRun)
+		if err != nil {
+			log.Errorf("Block reindex failed. Resume with --reindex=%d-%d.",
+				resumeHeight, cfg.reindexEnd)
+		} else if !cfg.ReindexDryRun && resumeHeight <= cfg.reindexEnd {
+			log.Infof("Resume with --reindex=%d-%d.", resumeHeight, cfg.reindexEnd)
+		}
+		requestShutdown()
+		return err
+	}
+
 	// Exits immediately after the sync completes if SyncAndQuit is to true
 	// because all we needed then was the blockchain sync be completed successfully.
 	if cfg.SyncAndQuit {
@@ -995,16 +1421,45 @@ func _main(ctx context.Context) error {
 	}
 
 	// Add charts saver method after explorer and database stores. This may run
-	// asynchronously.
+	// asynchronously. The cached API responses are refreshed after the charts
+	// are updated.
 	blockDataSavers = append(blockDataSavers, blockdata.BlockTrigger{
 		Async: true,
-		Saver: charts.TriggerUpdate,
+		Saver: func(hash string, height uint32) error {
+			err := charts.TriggerUpdate(hash, height)
+			respCache.Refresh(hash)
+			return err
+		},
 	})
 
 	// This dumps the cache charts data into a file for future use on system
 	// exit.
 	defer charts.Dump(dumpPath)
 
+	// Start the maintenance scheduler now that the DB is in sync.
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		maint.Run(ctx)
+	}()
+
+	// Export the settled days that have not been exported, and then each day
+	// as new blocks settle it.
+	if dailyExports != nil {
+		blockDataSavers = append(blockDataSavers, blockdata.BlockTrigger{
+			Async: true,
+			Saver: func(string, uint32) error {
+				dailyExports.BlockStored()
+				return nil
+			},
+		})
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			dailyExports.Run(ctx)
+		}()
+	}
+
 	// Block further usage of the barLoad by sending a nil value
 	if barLoad != nil {
 		select {
@@ -1162,12 +1617,121 @@ func waitForSync(ctx context.Context, aux chan dbtypes.SyncResult) (int64, error
 	return chainDBHeight, nil
 }
 
+// newMaintenanceScheduler creates a maintenance.Scheduler with the enabled
+// periodic maintenance tasks.
+func newMaintenanceScheduler(cfg *config, chainDB *dcrpg.ChainDB,
+	charts *cache.ChartData, mpm *mempool.MempoolMonitor) (*maintenance.Scheduler, error) {
+	taskFuncs := map[string]maintenance.TaskFunc{
+		// Refresh the planner statistics.
+		"analyze": func(context.Context) error {
+			return chainDB.AnalyzeTables()
+		},
+		// Reclaim the space used by dead tuples in tables with many of them.
+		"vacuum": func(context.Context) error {
+			tables, err := chainDB.VacuumTables()
+			if len(tables) > 0 {
+				log.Infof("Vacuumed tables: %s", strings.Join(tables, ", "))
+			}
+			return err
+		},
+		// Keep the dev fund balance cached for the current best block.
+		"devbalance": func(context.Context) error {
+			_, err := chainDB.DevBalance()
+			return err
+		},
+		// Update the charts data cache.
+		"charts": func(context.Context) error {
+			return charts.Update()
+		},
+		// Refresh the mempool inventory from dcrd, dropping transactions that
+		// are no longer in dcrd's mempool.
+		"mempool": func(context.Context) error {
+			return mpm.CollectAndStore()
+		},
+		// Save the mempool so the times transactions were first seen survive
+		// restarts.
+		"mempoolsave": func(context.Context) error {
+			return mpm.Save(filepath.Join(cfg.DataDir, cfg.MempoolFile))
+		},
+		// Record the day's distribution of UTXO values (UTC days).
+		"utxodist": func(context.Context) error {
+			return chainDB.StoreUTXODistribution()
+		},
+	}
+
+	maint := maintenance.NewScheduler()
+	for _, task := range maintenanceTasks {
+		spec, enabled := cfg.maintSchedules[task]
+		if !enabled {
+			continue
+		}
+		// The dev balance is only queried on demand with no-dev-prefetch.
+		if task == "devbalance" && cfg.NoDevPrefetch {
+			continue
+		}
+		if err := maint.Add(task, spec, taskFuncs[task]); err != nil {
+			return nil, err
+		}
+		log.Debugf("Scheduled maintenance task %s: %s", task, spec)
+	}
+	return maint, nil
+}
+
+// storeDailyPrices records the ExchangeBot's aggregate DCR price in its index
+// currency in the daily price history as exchange updates are received.
+func storeDailyPrices(ctx context.Context, wg *sync.WaitGroup,
+	xcBot *exchanges.ExchangeBot, chainDB *dcrpg.ChainDB) {
+	defer wg.Done()
+	xcChans := xcBot.UpdateChannels()
+	for {
+		select {
+		case <-xcChans.Exchange:
+		case <-xcChans.Index:
+			continue
+		case <-xcChans.Quit:
+			return
+		case <-ctx.Done():
+			return
+		}
+		if xcBot.IsFailed() {
+			continue
+		}
+		price := xcBot.Price()
+		if price <= 0 {
+			continue
+		}
+		if err := chainDB.StoreDailyPrice(xcBot.BtcIndex, price, time.Now()); err != nil {
+			log.Errorf("Failed to store daily price: %v", err)
+		}
+	}
+}
+
 func connectNodeRPC(cfg *config, ntfnHandlers *rpcclient.NotificationHandlers) (*rpcclient.Client, semver.Semver, error) {
 	return rpcutils.ConnectNodeRPC(cfg.DcrdServ, cfg.DcrdUser, cfg.DcrdPass,
 		cfg.DcrdCert, cfg.DisableDaemonTLS, true, ntfnHandlers)
 }
 
 func listenAndServeProto(ctx context.Context, wg *sync.WaitGroup, listen, proto string, mux http.Handler) {
+	// Bind a unix socket for the listen address given as unix:/path/to/socket,
+	// such as for an onion service. Remove a socket left by an unclean
+	// shutdown first.
+	var listener net.Listener
+	if strings.HasPrefix(listen, unixListenPrefix) {
+		socketPath := strings.TrimPrefix(listen, unixListenPrefix)
+		if err := os.Remove(socketPath); err != nil && !os.IsNotExist(err) {
+			log.Errorf("Failed to remove existing unix socket %s: %v", socketPath, err)
+			requestShutdown()
+			return
+		}
+		var err error
+		listener, err = net.Listen("unix", socketPath)
+		if err != nil {
+			log.Errorf("Failed to listen on unix socket %s: %v", socketPath, err)
+			requestShutdown()
+			return
+		}
+	}
+
 	// Try to bind web server
 	server := http.Server{
 		Addr:         listen,
@@ -1197,9 +1761,14 @@ func listenAndServeProto(ctx context.Context, wg *sync.WaitGroup, listen, proto
 	// Start the server.
 	go func() {
 		var err error
-		if proto == "https" {
+		switch {
+		case listener != nil && proto == "https":
+			err = server.ServeTLS(listener, "dcrdata.cert", "dcrdata.key")
+		case listener != nil:
+			err = server.Serve(listener)
+		case proto == "https":
 			err = server.ListenAndServeTLS("dcrdata.cert", "dcrdata.key")
-		} else {
+		default:
 			err = server.ListenAndServe()
 		}
 		// If the server dies for any reason other than ErrServerClosed (from
@@ -0,0 +1,53 @@
+// Copyright (c) 2020, The Decred developers
+
+package maintenance
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSchedule(t *testing.T) {
+	// Saturday.
+	from := time.Date(2019, time.June, 15, 10, 30, 20, 0, time.UTC)
+
+	tests := []struct {
+		spec string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2019, time.June, 15, 10, 31, 0, 0, time.UTC)},
+		{"@every 10m", from.Add(10 * time.Minute)},
+		{"@hourly", time.Date(2019, time.June, 15, 11, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2019, time.June, 16, 0, 0, 0, 0, time.UTC)},
+		{"30 4 * * *", time.Date(2019, time.June, 16, 4, 30, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2019, time.June, 15, 10, 45, 0, 0, time.UTC)},
+		{"10-20/5 11 * * *", time.Date(2019, time.June, 15, 11, 10, 0, 0, time.UTC)},
+		{"0 5 * * 0", time.Date(2019, time.June, 16, 5, 0, 0, 0, time.UTC)},
+		{"0 5 * * 7", time.Date(2019, time.June, 16, 5, 0, 0, 0, time.UTC)},
+		{"0 0 1,20 * *", time.Date(2019, time.June, 20, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 1 *", time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		// Day of month or day of week (Monday) when both are restricted.
+		{"0 0 20 * 1", time.Date(2019, time.June, 17, 0, 0, 0, 0, time.UTC)},
+		{"0 0 30 2 *", time.Time{}},
+	}
+
+	for _, tt := range tests {
+		sched, err := ParseSchedule(tt.spec)
+		if err != nil {
+			t.Errorf("ParseSchedule(%q) failed: %v", tt.spec, err)
+			continue
+		}
+		if got := sched.Next(from); !got.Equal(tt.want) {
+			t.Errorf("ParseSchedule(%q).Next = %v, want %v", tt.spec, got, tt.want)
+		}
+	}
+
+	invalid := []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *",
+		"* * * 13 *", "* * * * 8", "5-1 * * * *", "*/0 * * * *", "a * * * *",
+		"@every", "@every 1ms", "@every bogus"}
+	for _, spec := range invalid {
+		if _, err := ParseSchedule(spec); err == nil {
+			t.Errorf("ParseSchedule(%q) should have failed", spec)
+		}
+	}
+}
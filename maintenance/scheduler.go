@@ -0,0 +1,192 @@
+// Copyright (c) 2020, The Decred developers
+// See LICENSE for details.
+
+// Package maintenance provides an in-process scheduler for periodic
+// maintenance tasks such as table statistics updates and cache warming.
+package maintenance
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// TaskFunc performs a maintenance task. It should return promptly when the
+// context is canceled.
+type TaskFunc func(ctx context.Context) error
+
+// TaskStatus describes a scheduled task and the outcome of its last run. Times
+// are UNIX timestamps, and are zero if the event has not happened yet.
+type TaskStatus struct {
+	Name           string `json:"name"`
+	Schedule       string `json:"schedule"`
+	Running        bool   `json:"running"`
+	LastRun        int64  `json:"last_run"`
+	LastDurationMs int64  `json:"last_duration_ms"`
+	LastError      string `json:"last_error,omitempty"`
+	NextRun        int64  `json:"next_run"`
+	Runs           int64  `json:"runs"`
+	Failures       int64  `json:"failures"`
+}
+
+type task struct {
+	spec     string
+	schedule Schedule
+	run      TaskFunc
+
+	mtx          sync.RWMutex
+	running      bool
+	lastRun      time.Time
+	lastDuration time.Duration
+	lastErr      error
+	nextRun      time.Time
+	runs         int64
+	failures     int64
+}
+
+func (t *task) status(name string) TaskStatus {
+	t.mtx.RLock()
+	defer t.mtx.RUnlock()
+	st := TaskStatus{
+		Name:           name,
+		Schedule:       t.spec,
+		Running:        t.running,
+		LastDurationMs: t.lastDuration.Nanoseconds() / int64(time.Millisecond),
+		Runs:           t.runs,
+		Failures:       t.failures,
+	}
+	if !t.lastRun.IsZero() {
+		st.LastRun = t.lastRun.Unix()
+	}
+	if !t.nextRun.IsZero() {
+		st.NextRun = t.nextRun.Unix()
+	}
+	if t.lastErr != nil {
+		st.LastError = t.lastErr.Error()
+	}
+	return st
+}
+
+// Scheduler runs named tasks according to their schedules. Each task runs in
+// its own goroutine, and a task is never run concurrently with itself. If a
+// run takes longer than the interval to the next scheduled time, the missed
+// runs are skipped.
+type Scheduler struct {
+	mtx     sync.RWMutex
+	names   []string
+	tasks   map[string]*task
+	started bool
+}
+
+// NewScheduler creates an empty Scheduler.
+func NewScheduler() *Scheduler {
+	return &Scheduler{
+		tasks: make(map[string]*task),
+	}
+}
+
+// Add registers a task to be run on the given schedule, as accepted by
+// ParseSchedule. Tasks must be added before Run is called.
+func (s *Scheduler) Add(name, spec string, run TaskFunc) error {
+	sched, err := ParseSchedule(spec)
+	if err != nil {
+		return fmt.Errorf("task %s: %v", name, err)
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if s.started {
+		return fmt.Errorf("task %s: scheduler already started", name)
+	}
+	if _, found := s.tasks[name]; found {
+		return fmt.Errorf("task %s already added", name)
+	}
+	s.names = append(s.names, name)
+	s.tasks[name] = &task{
+		spec:     spec,
+		schedule: sched,
+		run:      run,
+	}
+	return nil
+}
+
+// Run starts the scheduled tasks and blocks until the context is canceled and
+// any running tasks have returned.
+func (s *Scheduler) Run(ctx context.Context) {
+	s.mtx.Lock()
+	s.started = true
+	s.mtx.Unlock()
+
+	var wg sync.WaitGroup
+	for _, name := range s.names {
+		wg.Add(1)
+		go func(name string, t *task) {
+			defer wg.Done()
+			s.runTask(ctx, name, t)
+		}(name, s.tasks[name])
+	}
+	wg.Wait()
+	log.Debugf("Maintenance scheduler stopped.")
+}
+
+func (s *Scheduler) runTask(ctx context.Context, name string, t *task) {
+	for {
+		next := t.schedule.Next(time.Now())
+		if next.IsZero() {
+			log.Warnf("Task %s (%s) will never run.", name, t.spec)
+			return
+		}
+		t.mtx.Lock()
+		t.nextRun = next
+		t.mtx.Unlock()
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		t.mtx.Lock()
+		t.running = true
+		t.mtx.Unlock()
+
+		log.Debugf("Running maintenance task %s.", name)
+		start := time.Now()
+		err := t.run(ctx)
+		dur := time.Since(start)
+
+		t.mtx.Lock()
+		t.running = false
+		t.lastRun = start
+		t.lastDuration = dur
+		t.lastErr = err
+		t.runs++
+		if err != nil {
+			t.failures++
+		}
+		t.mtx.Unlock()
+
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			log.Errorf("Maintenance task %s failed after %v: %v", name, dur, err)
+			continue
+		}
+		log.Debugf("Maintenance task %s completed in %v.", name, dur)
+	}
+}
+
+// Status returns the status of each task, in the order they were added.
+func (s *Scheduler) Status() []TaskStatus {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+	status := make([]TaskStatus, 0, len(s.names))
+	for _, name := range s.names {
+		status = append(status, s.tasks[name].status(name))
+	}
+	return status
+}
@@ -0,0 +1,140 @@
+// Copyright (c) 2020, The Decred developers
+// See LICENSE for details.
+
+package mempool
+
+import (
+	"sort"
+
+	"github.com/decred/dcrd/chaincfg/v2"
+	exptypes "github.com/decred/dcrdata/explorer/types/v2"
+)
+
+// previewReservedSize is the number of bytes of the block size limit set aside
+// for the block header and coinbase transaction in a NextBlockPreview.
+const previewReservedSize = 1000
+
+// sortByFeeRate sorts the transactions by descending fee rate.
+func sortByFeeRate(txns []exptypes.MempoolTx) {
+	sort.SliceStable(txns, func(i, j int) bool {
+		return txns[i].FeeRate > txns[j].FeeRate
+	})
+}
+
+// NextBlockPreview assembles the likely next block, mined on the block with
+// the given height and hash, from the mempool transactions txns. The block
+// includes the votes on the previous block that spend distinct tickets, up to
+// the number of votes per block, the highest fee rate ticket purchases up to
+// the maximum per block, and then all revocations and the highest fee rate
+// regular transactions that fit in the block. A regular transaction spending
+// outputs of a mempool transaction that is not already in the block is left
+// out. The txns slice is not modified.
+func NextBlockPreview(txns []exptypes.MempoolTx, prevHeight int64, prevHash string,
+	params *chaincfg.Params) *exptypes.NextBlockPreview {
+	preview := &exptypes.NextBlockPreview{
+		Height:       prevHeight + 1,
+		PreviousHash: prevHash,
+	}
+
+	var tickets, revocations, regular []exptypes.MempoolTx
+	inMempool := make(map[string]struct{}, len(txns))
+	ticketsVoted := make(map[string]struct{})
+	for i := range txns {
+		tx := &txns[i]
+		inMempool[tx.TxID] = struct{}{}
+		switch tx.Type {
+		case "Vote":
+			if tx.VoteInfo == nil || !tx.VoteInfo.ForLastBlock ||
+				len(preview.Votes) >= int(params.TicketsPerBlock) {
+				preview.NumExcluded++
+				continue
+			}
+			if _, found := ticketsVoted[tx.VoteInfo.TicketSpent]; found {
+				preview.NumExcluded++
+				continue
+			}
+			ticketsVoted[tx.VoteInfo.TicketSpent] = struct{}{}
+			preview.Votes = append(preview.Votes, *tx)
+		case "Ticket":
+			tickets = append(tickets, *tx)
+		case "Revocation":
+			revocations = append(revocations, *tx)
+		default:
+			regular = append(regular, *tx)
+		}
+	}
+
+	// Votes are only required once stake validation begins.
+	preview.EnoughVotes = preview.Height < params.StakeValidationHeight ||
+		len(preview.Votes) > int(params.TicketsPerBlock)/2
+
+	maxSize := int32(params.MaximumBlockSizes[0]) - previewReservedSize
+	included := make(map[string]struct{}, len(txns))
+	include := func(tx *exptypes.MempoolTx) bool {
+		if preview.Size+tx.Size > maxSize {
+			return false
+		}
+		preview.Size += tx.Size
+		preview.Fees += tx.Fees
+		preview.TotalOut += tx.TotalOut
+		included[tx.TxID] = struct{}{}
+		return true
+	}
+
+	votes := preview.Votes[:0]
+	for i := range preview.Votes {
+		if include(&preview.Votes[i]) {
+			votes = append(votes, preview.Votes[i])
+		} else {
+			preview.NumExcluded++
+		}
+	}
+	preview.Votes = votes
+
+	sortByFeeRate(tickets)
+	for i := range tickets {
+		if len(preview.Tickets) < int(params.MaxFreshStakePerBlock) && include(&tickets[i]) {
+			preview.Tickets = append(preview.Tickets, tickets[i])
+		} else {
+			preview.NumExcluded++
+		}
+	}
+
+	for i := range revocations {
+		if include(&revocations[i]) {
+			preview.Revocations = append(preview.Revocations, revocations[i])
+		} else {
+			preview.NumExcluded++
+		}
+	}
+
+	sortByFeeRate(regular)
+txLoop:
+	for i := range regular {
+		tx := &regular[i]
+		for _, vin := range tx.Vin {
+			_, unconfirmed := inMempool[vin.TxId]
+			if _, found := included[vin.TxId]; unconfirmed && !found {
+				preview.NumExcluded++
+				continue txLoop
+			}
+		}
+		if include(tx) {
+			preview.Transactions = append(preview.Transactions, *tx)
+		} else {
+			preview.NumExcluded++
+		}
+	}
+
+	return preview
+}
+
+// NextBlockPreview assembles the likely next block from the cached mempool
+// transactions. See the NextBlockPreview function.
+func (c *MempoolDataCache) NextBlockPreview(params *chaincfg.Params) *exptypes.NextBlockPreview {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+	preview := NextBlockPreview(c.txns, int64(c.height), c.hash, params)
+	preview.Time = c.timestamp.Unix()
+	return preview
+}
@@ -0,0 +1,147 @@
+// Copyright (c) 2020, The Decred developers
+// See LICENSE for details.
+
+package mempool
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/decred/dcrd/chaincfg/v2"
+	exptypes "github.com/decred/dcrdata/explorer/types/v2"
+)
+
+func previewTx(txid, txType string, size int32, feeRate float64, spends ...string) exptypes.MempoolTx {
+	tx := exptypes.MempoolTx{
+		TxID:     txid,
+		Type:     txType,
+		Size:     size,
+		FeeRate:  feeRate,
+		Fees:     feeRate * float64(size) / 1000,
+		TotalOut: 1,
+	}
+	for _, txid := range spends {
+		tx.Vin = append(tx.Vin, exptypes.MempoolInput{TxId: txid})
+	}
+	return tx
+}
+
+func previewVote(txid, ticket string, forLastBlock bool) exptypes.MempoolTx {
+	tx := previewTx(txid, "Vote", 300, 0)
+	tx.VoteInfo = &exptypes.VoteInfo{TicketSpent: ticket, ForLastBlock: forLastBlock}
+	return tx
+}
+
+func previewIDs(txns []exptypes.MempoolTx) []string {
+	ids := make([]string, 0, len(txns))
+	for i := range txns {
+		ids = append(ids, txns[i].TxID)
+	}
+	return ids
+}
+
+func TestNextBlockPreview(t *testing.T) {
+	params := chaincfg.MainNetParams()
+	prevHeight := params.StakeValidationHeight + 100
+
+	var txns []exptypes.MempoolTx
+	// Six votes on the last block, one more for a ticket that already voted,
+	// and one on a different block.
+	for i := 0; i < 6; i++ {
+		txns = append(txns, previewVote(fmt.Sprintf("vote%d", i), fmt.Sprintf("ticket%d", i), true))
+	}
+	txns = append(txns, previewVote("vote6", "ticket0", true),
+		previewVote("vote7", "ticket7", false))
+	// One ticket purchase more than the maximum per block, with increasing
+	// fee rates.
+	for i := 0; i <= int(params.MaxFreshStakePerBlock); i++ {
+		txns = append(txns, previewTx(fmt.Sprintf("sstx%02d", i), "Ticket", 300, float64(i+1)/1000))
+	}
+	txns = append(txns, previewTx("revoke", "Revocation", 200, 0),
+		// spendsLow spends an output of low, which has a lower fee rate, so
+		// it is left out.
+		previewTx("low", "Regular", 250, 0.0001),
+		previewTx("spendsLow", "Regular", 250, 0.01, "low"),
+		// spendsHigh spends an output of high, which is included first.
+		previewTx("high", "Regular", 250, 0.02),
+		previewTx("spendsHigh", "Regular", 250, 0.005, "high", "confirmed"),
+		// huge does not fit in the block.
+		previewTx("huge", "Regular", int32(params.MaximumBlockSizes[0]), 1))
+	numTxns := len(txns)
+	first := txns[0]
+
+	preview := NextBlockPreview(txns, prevHeight, "abcd", params)
+	if preview.Height != prevHeight+1 || preview.PreviousHash != "abcd" {
+		t.Errorf("unexpected next block %d on %s", preview.Height, preview.PreviousHash)
+	}
+	if txns[0].TxID != first.TxID || len(txns) != numTxns {
+		t.Errorf("the mempool transactions were modified")
+	}
+
+	if ids := previewIDs(preview.Votes); len(ids) != int(params.TicketsPerBlock) || ids[0] != "vote0" ||
+		ids[len(ids)-1] != "vote4" {
+		t.Errorf("unexpected votes %v", ids)
+	}
+	if !preview.EnoughVotes {
+		t.Errorf("a full set of votes is not enough")
+	}
+	ids := previewIDs(preview.Tickets)
+	if len(ids) != int(params.MaxFreshStakePerBlock) || ids[0] != fmt.Sprintf("sstx%02d", params.MaxFreshStakePerBlock) ||
+		ids[len(ids)-1] != "sstx01" {
+		t.Errorf("unexpected tickets %v", ids)
+	}
+	if ids = previewIDs(preview.Revocations); len(ids) != 1 || ids[0] != "revoke" {
+		t.Errorf("unexpected revocations %v", ids)
+	}
+	if ids = previewIDs(preview.Transactions); len(ids) != 3 || ids[0] != "high" ||
+		ids[1] != "spendsHigh" || ids[2] != "low" {
+		t.Errorf("unexpected transactions %v", ids)
+	}
+
+	// Excluded are vote5 (over the limit), vote6 (ticket already voted), vote7
+	// (other block), sstx00, spendsLow and huge.
+	if preview.NumExcluded != 6 {
+		t.Errorf("got %d excluded transactions, wanted 6", preview.NumExcluded)
+	}
+	var size int32
+	var fees float64
+	for _, txs := range [][]exptypes.MempoolTx{preview.Votes, preview.Tickets,
+		preview.Revocations, preview.Transactions} {
+		for i := range txs {
+			size += txs[i].Size
+			fees += txs[i].Fees
+		}
+	}
+	if preview.Size != size || preview.Fees != fees {
+		t.Errorf("got size %d and fees %f, wanted %d and %f", preview.Size, preview.Fees, size, fees)
+	}
+}
+
+func TestNextBlockPreviewVotes(t *testing.T) {
+	params := chaincfg.MainNetParams()
+	votes := []exptypes.MempoolTx{
+		previewVote("vote0", "ticket0", true),
+		previewVote("vote1", "ticket1", true),
+		previewVote("vote2", "ticket2", true),
+	}
+
+	// A majority of the votes per block is required after stake validation
+	// begins, and no votes before.
+	tests := []struct {
+		name       string
+		prevHeight int64
+		numVotes   int
+		want       bool
+	}{
+		{"majority", params.StakeValidationHeight, 3, true},
+		{"minority", params.StakeValidationHeight, 2, false},
+		{"no votes", params.StakeValidationHeight, 0, false},
+		{"before stake validation", params.StakeValidationHeight - 2, 0, true},
+	}
+	for _, tt := range tests {
+		preview := NextBlockPreview(votes[:tt.numVotes], tt.prevHeight, "abcd", params)
+		if preview.EnoughVotes != tt.want {
+			t.Errorf("%s: got EnoughVotes %v, wanted %v", tt.name, preview.EnoughVotes, tt.want)
+		}
+	}
+}
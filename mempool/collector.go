@@ -143,6 +143,7 @@ func (t *MempoolDataCollector) mempoolTxns() ([]exptypes.MempoolTx, txhelpers.Me
 			TotalOut:  totalOut,
 			Type:      txhelpers.DetermineTxTypeString(msgTx),
 			VoteInfo:  voteInfo,
+			Expiry:    msgTx.Expiry,
 		})
 	}
 
@@ -10,6 +10,7 @@ require (
 	github.com/decred/dcrd/dcrutil/v2 v2.0.1
 	github.com/decred/dcrd/rpc/jsonrpc/types/v2 v2.0.0
 	github.com/decred/dcrd/rpcclient/v5 v5.0.0
+	github.com/decred/dcrd/wire v1.3.0
 	github.com/decred/dcrdata/api/types/v5 v5.0.1
 	github.com/decred/dcrdata/db/dbtypes/v2 v2.2.1
 	github.com/decred/dcrdata/explorer/types/v2 v2.1.1
@@ -20,4 +21,9 @@ require (
 	github.com/dustin/go-humanize v1.0.0
 )
 
-replace github.com/decred/dcrdata/explorer/types/v2 => ../explorer/types
+replace (
+	github.com/decred/dcrdata/api/types/v5 => ../api/types
+	github.com/decred/dcrdata/db/dbtypes/v2 => ../db/dbtypes
+	github.com/decred/dcrdata/explorer/types/v2 => ../explorer/types
+	github.com/decred/dcrdata/txhelpers/v4 => ../txhelpers
+)
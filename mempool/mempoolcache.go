@@ -5,11 +5,13 @@
 package mempool
 
 import (
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/decred/dcrd/dcrutil/v2"
 	chainjson "github.com/decred/dcrd/rpc/jsonrpc/types/v2"
+	"github.com/decred/dcrd/wire"
 	apitypes "github.com/decred/dcrdata/api/types/v5"
 	"github.com/decred/dcrdata/db/dbtypes/v2"
 	exptypes "github.com/decred/dcrdata/explorer/types/v2"
@@ -77,6 +79,33 @@ func (c *MempoolDataCache) SBits() (int64, uint32) {
 	return c.stakeDiff, c.height
 }
 
+// HasTx checks if the transaction with the given hash is in the mempool.
+func (c *MempoolDataCache) HasTx(txid string) bool {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+	for i := range c.txns {
+		if c.txns[i].TxID == txid {
+			return true
+		}
+	}
+	return false
+}
+
+// Spender returns the hash of the mempool transaction spending the given
+// outpoint, or an empty string if no mempool transaction spends it.
+func (c *MempoolDataCache) Spender(txid string, vout uint32) string {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+	for i := range c.txns {
+		for _, in := range c.txns[i].Vin {
+			if in.TxId == txid && in.Outdex == vout {
+				return c.txns[i].TxID
+			}
+		}
+	}
+	return ""
+}
+
 // GetNumTickets returns the mempool height and number of tickets
 func (c *MempoolDataCache) GetNumTickets() (uint32, uint32) {
 	c.mtx.RLock()
@@ -183,6 +212,73 @@ func (c *MempoolDataCache) GetTicketsDetails(N int) (uint32, int64, int, Tickets
 	return c.height, c.timestamp.Unix(), numSSTx, details
 }
 
+// GetShortSummary counts the transactions in the mempool by type.
+func (c *MempoolDataCache) GetShortSummary() *apitypes.MempoolShortSummary {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+	summary := &apitypes.MempoolShortSummary{
+		Height: c.height,
+		Time:   c.timestamp.Unix(),
+		NumAll: len(c.txns),
+	}
+	for i := range c.txns {
+		tx := &c.txns[i]
+		switch tx.Type {
+		case "Vote":
+			summary.NumVotes++
+		case "Ticket":
+			summary.NumTickets++
+		case "Revocation":
+			summary.NumRevokes++
+		default:
+			summary.NumRegular++
+		}
+		summary.TotalOut += tx.TotalOut
+		summary.TotalSize += tx.Size
+	}
+	return summary
+}
+
+// GetExpiring lists the transactions that expire within the given number of
+// blocks after the best block, soonest first.
+func (c *MempoolDataCache) GetExpiring(blocks int64) *apitypes.MempoolExpiring {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+	expiring := &apitypes.MempoolExpiring{
+		Height:       c.height,
+		Time:         c.timestamp.Unix(),
+		Blocks:       blocks,
+		Transactions: []*apitypes.MempoolExpiringTx{},
+	}
+	nextHeight := int64(c.height) + 1
+	for i := range c.txns {
+		tx := &c.txns[i]
+		if tx.Expiry == wire.NoExpiryValue {
+			continue
+		}
+		blocksToExpiry := int64(tx.Expiry) - nextHeight
+		if blocksToExpiry > blocks {
+			continue
+		}
+		if blocksToExpiry < 0 {
+			blocksToExpiry = 0
+		}
+		expiring.Transactions = append(expiring.Transactions, &apitypes.MempoolExpiringTx{
+			TxID:           tx.TxID,
+			Type:           tx.Type,
+			Time:           tx.Time,
+			Size:           tx.Size,
+			FeeRate:        tx.FeeRate,
+			ExpiryHeight:   int64(tx.Expiry),
+			BlocksToExpiry: blocksToExpiry,
+		})
+	}
+	sort.SliceStable(expiring.Transactions, func(i, j int) bool {
+		return expiring.Transactions[i].ExpiryHeight < expiring.Transactions[j].ExpiryHeight
+	})
+	return expiring
+}
+
 // GetTicketPriceCountTime gathers the nominal info for mempool tickets.
 func (c *MempoolDataCache) GetTicketPriceCountTime(feeAvgLength int) *apitypes.PriceCountTime {
 	c.mtx.RLock()
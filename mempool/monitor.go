@@ -56,6 +56,13 @@ type MempoolMonitor struct {
 	dataSavers []MempoolDataSaver
 	client     txhelpers.VerboseTransactionGetter
 
+	// firstSeen holds the times that the transactions loaded from the mempool
+	// file were first seen before a restart, for those still in mempool.
+	firstSeen map[string]int64
+
+	// graph is the dependency graph of the transactions in the inventory.
+	graph *TxGraph
+
 	// Outgoing message
 	signalOuts []chan<- pstypes.HubMessage
 }
@@ -77,6 +84,7 @@ func NewMempoolMonitor(ctx context.Context, collector *MempoolDataCollector,
 		dataSavers: savers,
 		client:     client,
 		signalOuts: signalOuts,
+		graph:      NewTxGraph(),
 	}
 
 	if initialStore {
@@ -86,6 +94,12 @@ func NewMempoolMonitor(ctx context.Context, collector *MempoolDataCollector,
 	return p, err
 }
 
+// TxGraph returns the dependency graph of the mempool transactions, which is
+// updated as new transactions are received and rebuilt with each collection.
+func (p *MempoolMonitor) TxGraph() *TxGraph {
+	return p.graph
+}
+
 // LastBlockHash returns the hash of the most recently stored block.
 func (p *MempoolMonitor) LastBlockHash() chainhash.Hash {
 	p.mtx.RLock()
@@ -159,6 +173,13 @@ func (p *MempoolMonitor) TxHandler(rawTx *chainjson.TxRawResult) error {
 		return nil // back to waiting for new tx signal
 	}
 
+	// A transaction seen before a restart may be relayed again, such as after
+	// dcrd is restarted too.
+	txTime := rawTx.Time
+	if seen, found := p.firstSeen[hash]; found && seen < txTime {
+		txTime = seen
+	}
+
 	// Set Outpoints in the addrMap.
 	p.addrMap.mtx.Lock()
 	if p.addrMap.store == nil {
@@ -201,7 +222,7 @@ func (p *MempoolMonitor) TxHandler(rawTx *chainjson.TxRawResult) error {
 	// Store the current mempool transaction, block info zeroed.
 	p.txnsStore[msgTx.TxHash()] = &txhelpers.TxWithBlockData{
 		Tx:          msgTx,
-		MemPoolTime: rawTx.Time,
+		MemPoolTime: txTime,
 	}
 
 	log.Tracef("New transaction (%s: %s) added %d new and %d previous outpoints, "+
@@ -250,11 +271,12 @@ func (p *MempoolMonitor) TxHandler(rawTx *chainjson.TxRawResult) error {
 		Vin:       exptypes.MsgTxMempoolInputs(msgTx),
 		Coinbase:  standalone.IsCoinBaseTx(msgTx),
 		Hash:      hash,
-		Time:      rawTx.Time,
+		Time:      txTime,
 		Size:      int32(len(rawTx.Hex) / 2),
 		TotalOut:  txhelpers.TotalOutFromMsgTx(msgTx).ToCoin(),
 		Type:      txType,
 		VoteInfo:  voteInfo,
+		Expiry:    msgTx.Expiry,
 	}
 
 	// Maintain a separate total that excludes votes for sidechain
@@ -333,6 +355,7 @@ func (p *MempoolMonitor) TxHandler(rawTx *chainjson.TxRawResult) error {
 		p.inventory.LikelyMineable.Count++
 	}
 	p.inventory.FormattedTotalSize = humanize.Bytes(uint64(p.inventory.TotalSize))
+	p.graph.Add(&tx)
 	p.inventory.Unlock()
 	p.mtx.RUnlock()
 
@@ -367,6 +390,9 @@ func (p *MempoolMonitor) Refresh() (*StakeData, []exptypes.MempoolTx, *exptypes.
 	log.Debugf("%d addresses in mempool pertaining to %d transactions",
 		len(addrOuts), len(txnsStore))
 
+	// Restore the times the transactions were first seen before a restart.
+	p.restoreFirstSeen(txs, txnsStore)
+
 	// Pre-sort the txs so other consumers will not have to do it.
 	sort.Sort(exptypes.MPTxsByTime(txs))
 	inventory := ParseTxns(txs, p.params, &stakeData.LatestBlock)
@@ -388,6 +414,7 @@ func (p *MempoolMonitor) Refresh() (*StakeData, []exptypes.MempoolTx, *exptypes.
 	}
 	p.inventory = inventory
 	p.txnsStore = txnsStore
+	p.graph.Reset(txs, stakeData.LatestBlock.Height, stakeData.LatestBlock.Hash.String())
 	p.mtx.Unlock()
 
 	p.addrMap.mtx.Lock()
@@ -0,0 +1,191 @@
+// Copyright (c) 2020, The Decred developers
+// See LICENSE for details.
+
+package mempool
+
+import (
+	"encoding/gob"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	exptypes "github.com/decred/dcrdata/explorer/types/v2"
+	"github.com/decred/dcrdata/txhelpers/v4"
+)
+
+// mempoolFileVersion is the version of the mempool file format. Files with
+// other versions are ignored.
+const mempoolFileVersion = 1
+
+// savedTx is a mempool transaction in the mempool file, with the time it was
+// first seen and its fees in DCR and DCR/kB.
+type savedTx struct {
+	Hex       string
+	FirstSeen int64
+	Fees      float64
+	FeeRate   float64
+}
+
+// savedMempool is the content of the mempool file.
+type savedMempool struct {
+	Version int
+	Time    int64
+	Txns    []savedTx
+}
+
+// Save writes the transactions in the mempool inventory to a gob file at the
+// given path, so that the times they were first seen may be restored by Load
+// after a restart. The file is replaced atomically.
+func (p *MempoolMonitor) Save(filePath string) error {
+	saved := savedMempool{
+		Version: mempoolFileVersion,
+		Time:    time.Now().Unix(),
+	}
+
+	p.mtx.RLock()
+	if p.inventory != nil {
+		p.inventory.RLock()
+		for _, txs := range [][]exptypes.MempoolTx{p.inventory.Transactions,
+			p.inventory.Tickets, p.inventory.Votes, p.inventory.Revocations} {
+			for i := range txs {
+				tx := &txs[i]
+				hash, err := chainhash.NewHashFromStr(tx.Hash)
+				if err != nil {
+					continue
+				}
+				txData := p.txnsStore[*hash]
+				if txData == nil || txData.Tx == nil {
+					continue
+				}
+				txHex, err := txhelpers.MsgTxToHex(txData.Tx)
+				if err != nil {
+					continue
+				}
+				saved.Txns = append(saved.Txns, savedTx{
+					Hex:       txHex,
+					FirstSeen: tx.Time,
+					Fees:      tx.Fees,
+					FeeRate:   tx.FeeRate,
+				})
+			}
+		}
+		p.inventory.RUnlock()
+	}
+	p.mtx.RUnlock()
+
+	tmpPath := filePath + ".tmp"
+	file, err := os.Create(tmpPath)
+	if err != nil {
+		return err
+	}
+	if err = gob.NewEncoder(file).Encode(&saved); err != nil {
+		file.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err = file.Close(); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	if err = os.Rename(tmpPath, filePath); err != nil {
+		return err
+	}
+	log.Debugf("Saved %d mempool transactions to %s.", len(saved.Txns), filePath)
+	return nil
+}
+
+// readMempoolFile reads the mempool file at the given path, returning the
+// times the saved transactions were first seen by transaction hash.
+func readMempoolFile(filePath string) (map[string]int64, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var saved savedMempool
+	if err = gob.NewDecoder(file).Decode(&saved); err != nil {
+		return nil, err
+	}
+	if saved.Version != mempoolFileVersion {
+		return nil, fmt.Errorf("expected mempool file version %d but found %d",
+			mempoolFileVersion, saved.Version)
+	}
+
+	firstSeen := make(map[string]int64, len(saved.Txns))
+	for i := range saved.Txns {
+		msgTx, err := txhelpers.MsgTxFromHex(saved.Txns[i].Hex)
+		if err != nil {
+			log.Debugf("Skipping invalid saved mempool transaction: %v", err)
+			continue
+		}
+		firstSeen[msgTx.TxHash().String()] = saved.Txns[i].FirstSeen
+	}
+	return firstSeen, nil
+}
+
+// Load reads the mempool file written by Save at the given path, and
+// reconciles the saved transactions with dcrd's current mempool. The
+// transactions that are still in mempool keep the times they were first seen
+// before the restart, while the others are discarded. The mempool data is
+// collected and stored again with the restored times, and the number of
+// restored transactions is returned. A missing file is not an error.
+func (p *MempoolMonitor) Load(filePath string) (int, error) {
+	firstSeen, err := readMempoolFile(filePath)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	p.mtx.Lock()
+	p.firstSeen = firstSeen
+	p.mtx.Unlock()
+
+	if err = p.CollectAndStore(); err != nil {
+		return 0, err
+	}
+
+	p.mtx.RLock()
+	defer p.mtx.RUnlock()
+	log.Debugf("%d of %d saved mempool transactions are still in mempool.",
+		len(p.firstSeen), len(firstSeen))
+	return len(p.firstSeen), nil
+}
+
+// restoreFirstSeen sets the times of the collected transactions to the times
+// they were first seen before a restart, and forgets the transactions that
+// are no longer in mempool.
+func (p *MempoolMonitor) restoreFirstSeen(txs []exptypes.MempoolTx, txnsStore txhelpers.TxnsStore) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	if len(p.firstSeen) == 0 {
+		return
+	}
+
+	inMempool := make(map[string]struct{}, len(txs))
+	for i := range txs {
+		tx := &txs[i]
+		inMempool[tx.Hash] = struct{}{}
+		seen, found := p.firstSeen[tx.Hash]
+		if !found || seen >= tx.Time {
+			continue
+		}
+		tx.Time = seen
+		hash, err := chainhash.NewHashFromStr(tx.Hash)
+		if err != nil {
+			continue
+		}
+		if txData := txnsStore[*hash]; txData != nil {
+			txData.MemPoolTime = seen
+		}
+	}
+
+	for hash := range p.firstSeen {
+		if _, found := inMempool[hash]; !found {
+			delete(p.firstSeen, hash)
+		}
+	}
+}
@@ -0,0 +1,254 @@
+// Copyright (c) 2020, The Decred developers
+// See LICENSE for details.
+
+package mempool
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/decred/dcrd/dcrutil/v2"
+	apitypes "github.com/decred/dcrdata/api/types/v5"
+	exptypes "github.com/decred/dcrdata/explorer/types/v2"
+)
+
+// txNode is a transaction in a TxGraph, with its mempool parents, children,
+// ancestors and descendants, and the totals of its ancestor and descendant
+// packages, which include the transaction itself.
+type txNode struct {
+	tx          exptypes.MempoolTx
+	fees        int64
+	parents     map[string]*txNode
+	children    map[string]*txNode
+	ancestors   map[string]*txNode
+	descendants map[string]*txNode
+	ancSize     int64
+	ancFees     int64
+	descSize    int64
+	descFees    int64
+}
+
+func newTxNode(tx *exptypes.MempoolTx) *txNode {
+	fees, _ := dcrutil.NewAmount(tx.Fees) // 0 if err != nil
+	return &txNode{
+		tx:          *tx,
+		fees:        int64(fees),
+		parents:     make(map[string]*txNode),
+		children:    make(map[string]*txNode),
+		ancestors:   make(map[string]*txNode),
+		descendants: make(map[string]*txNode),
+		ancSize:     int64(tx.Size),
+		ancFees:     int64(fees),
+		descSize:    int64(tx.Size),
+		descFees:    int64(fees),
+	}
+}
+
+// addAncestor records a as an ancestor of the node, and the node as a
+// descendant of a, updating the package totals of both.
+func (n *txNode) addAncestor(a *txNode) {
+	if _, found := n.ancestors[a.tx.TxID]; found {
+		return
+	}
+	n.ancestors[a.tx.TxID] = a
+	n.ancSize += int64(a.tx.Size)
+	n.ancFees += a.fees
+	a.descendants[n.tx.TxID] = n
+	a.descSize += int64(n.tx.Size)
+	a.descFees += n.fees
+}
+
+// TxGraph is the dependency graph of the mempool transactions, where a
+// transaction is the child of the mempool transactions whose outputs it
+// spends. The ancestor and descendant package totals of each transaction are
+// maintained as transactions are added. TxGraph is safe for concurrent use.
+type TxGraph struct {
+	mtx    sync.RWMutex
+	height int64
+	hash   string
+	nodes  map[string]*txNode
+}
+
+// NewTxGraph creates an empty TxGraph.
+func NewTxGraph() *TxGraph {
+	return &TxGraph{nodes: make(map[string]*txNode)}
+}
+
+// Reset replaces the graph with the transactions in mempool after the block
+// with the given height and hash.
+func (g *TxGraph) Reset(txs []exptypes.MempoolTx, height int64, hash string) {
+	nodes := make(map[string]*txNode, len(txs))
+	for i := range txs {
+		nodes[txs[i].TxID] = newTxNode(&txs[i])
+	}
+	link(nodes)
+
+	g.mtx.Lock()
+	g.nodes = nodes
+	g.height, g.hash = height, hash
+	g.mtx.Unlock()
+}
+
+// link sets the parents, children, ancestors and descendants of the nodes.
+func link(nodes map[string]*txNode) {
+	for _, n := range nodes {
+		for _, in := range n.tx.Vin {
+			if p, found := nodes[in.TxId]; found && p != n {
+				n.parents[p.tx.TxID] = p
+				p.children[n.tx.TxID] = n
+			}
+		}
+	}
+	for _, n := range nodes {
+		// Walk the ancestors, which are acyclic since a transaction's hash
+		// commits to the outpoints it spends.
+		stack := make([]*txNode, 0, len(n.parents))
+		for _, p := range n.parents {
+			stack = append(stack, p)
+		}
+		for len(stack) > 0 {
+			a := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if _, found := n.ancestors[a.tx.TxID]; found {
+				continue
+			}
+			n.addAncestor(a)
+			for _, p := range a.parents {
+				stack = append(stack, p)
+			}
+		}
+	}
+}
+
+// Add adds a new mempool transaction to the graph. Its ancestors are the
+// transactions already in the graph, so only the ancestors' descendant
+// packages change, unless a transaction in the graph spends its outputs, in
+// which case the graph is relinked.
+func (g *TxGraph) Add(tx *exptypes.MempoolTx) {
+	g.mtx.Lock()
+	defer g.mtx.Unlock()
+	if _, found := g.nodes[tx.TxID]; found {
+		return
+	}
+	n := newTxNode(tx)
+	g.nodes[tx.TxID] = n
+
+	// A child may have been added first if the transactions were received out
+	// of order.
+	for _, other := range g.nodes {
+		for _, in := range other.tx.Vin {
+			if in.TxId == tx.TxID {
+				g.relink()
+				return
+			}
+		}
+	}
+
+	for _, in := range tx.Vin {
+		p, found := g.nodes[in.TxId]
+		if !found || p == n {
+			continue
+		}
+		n.parents[p.tx.TxID] = p
+		p.children[n.tx.TxID] = n
+		n.addAncestor(p)
+		for _, a := range p.ancestors {
+			n.addAncestor(a)
+		}
+	}
+}
+
+// relink rebuilds the graph's nodes from their transactions. g.mtx must be
+// locked for writing.
+func (g *TxGraph) relink() {
+	nodes := make(map[string]*txNode, len(g.nodes))
+	for txid, n := range g.nodes {
+		nodes[txid] = newTxNode(&n.tx)
+	}
+	link(nodes)
+	g.nodes = nodes
+}
+
+// Graph lists the transactions with mempool parents or children, lowest
+// ancestor package fee rate first.
+func (g *TxGraph) Graph() *apitypes.MempoolTxGraph {
+	g.mtx.RLock()
+	defer g.mtx.RUnlock()
+	graph := &apitypes.MempoolTxGraph{
+		Height:          g.height,
+		Hash:            g.hash,
+		NumTransactions: len(g.nodes),
+		Transactions:    []*apitypes.MempoolTxPackages{},
+	}
+	for _, n := range g.nodes {
+		if len(n.parents) == 0 && len(n.children) == 0 {
+			continue
+		}
+		graph.Transactions = append(graph.Transactions, n.packages(false))
+	}
+	sort.Slice(graph.Transactions, func(i, j int) bool {
+		ti, tj := graph.Transactions[i], graph.Transactions[j]
+		if ti.Ancestors.FeeRate == tj.Ancestors.FeeRate {
+			return ti.TxID < tj.TxID
+		}
+		return ti.Ancestors.FeeRate < tj.Ancestors.FeeRate
+	})
+	return graph
+}
+
+// Packages returns the dependencies of the mempool transaction, including all
+// of its ancestors and descendants, or nil if it is not in mempool.
+func (g *TxGraph) Packages(txid string) *apitypes.MempoolTxPackages {
+	g.mtx.RLock()
+	defer g.mtx.RUnlock()
+	n, found := g.nodes[txid]
+	if !found {
+		return nil
+	}
+	return n.packages(true)
+}
+
+// packages describes the node's dependencies, listing all of its ancestors
+// and descendants if withPackageTxns is set.
+func (n *txNode) packages(withPackageTxns bool) *apitypes.MempoolTxPackages {
+	pkgs := &apitypes.MempoolTxPackages{
+		TxID:        n.tx.TxID,
+		Type:        n.tx.Type,
+		Size:        n.tx.Size,
+		Fees:        n.tx.Fees,
+		FeeRate:     n.tx.FeeRate,
+		Parents:     txids(n.parents),
+		Children:    txids(n.children),
+		Ancestors:   packageStats(len(n.ancestors)+1, n.ancSize, n.ancFees),
+		Descendants: packageStats(len(n.descendants)+1, n.descSize, n.descFees),
+	}
+	if withPackageTxns {
+		pkgs.AncestorTxns = txids(n.ancestors)
+		pkgs.DescendantTxns = txids(n.descendants)
+	}
+	return pkgs
+}
+
+// packageStats makes the package totals, with the fees in DCR and the fee rate
+// in DCR/kB.
+func packageStats(count int, size, fees int64) apitypes.MempoolPackageStats {
+	stats := apitypes.MempoolPackageStats{
+		Count: count,
+		Size:  int32(size),
+		Fees:  dcrutil.Amount(fees).ToCoin(),
+	}
+	if size > 0 {
+		stats.FeeRate = dcrutil.Amount(fees * 1000 / size).ToCoin()
+	}
+	return stats
+}
+
+// txids lists the hashes of the nodes in order.
+func txids(nodes map[string]*txNode) []string {
+	ids := make([]string, 0, len(nodes))
+	for txid := range nodes {
+		ids = append(ids, txid)
+	}
+	sort.Strings(ids)
+	return ids
+}
@@ -7,6 +7,7 @@ package middleware
 import (
 	"bytes"
 	"context"
+	"crypto/subtle"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
@@ -16,6 +17,7 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/decred/dcrd/chaincfg/chainhash"
 	"github.com/decred/dcrd/chaincfg/v2"
@@ -26,6 +28,7 @@ import (
 	"github.com/didip/tollbooth/v5"
 	"github.com/didip/tollbooth/v5/limiter"
 	"github.com/go-chi/chi"
+	chimw "github.com/go-chi/chi/middleware"
 	"github.com/go-chi/docgen"
 )
 
@@ -60,12 +63,15 @@ const (
 	ctxXcToken
 	ctxStickWidth
 	ctxIndent
+	ctxBlockTime
+	ctxExportDate
+	ctxExportFile
 )
 
 type DataSource interface {
 	GetHeight() (int64, error)
-	GetBlockHeight(hash string) (int64, error)
-	GetBlockHash(idx int64) (string, error)
+	GetBlockHeight(ctx context.Context, hash string) (int64, error)
+	GetBlockHash(ctx context.Context, idx int64) (string, error)
 }
 
 type StakeVersionsLatest func() (*chainjson.StakeVersions, error)
@@ -191,6 +197,17 @@ func GetProposalTokenCtx(r *http.Request) string {
 	return tp
 }
 
+// GetDailyExportFileCtx retrieves the date and file name of a daily export file
+// from the request context. If not set, the return values are empty strings.
+func GetDailyExportFileCtx(r *http.Request) (date, name string) {
+	date, _ = r.Context().Value(ctxExportDate).(string)
+	name, _ = r.Context().Value(ctxExportFile).(string)
+	if date == "" || name == "" {
+		apiLog.Trace("daily export file not set")
+	}
+	return
+}
+
 // GetRawHexTx retrieves the ctxRawHexTx data from the request context. If not
 // set, the return value is an empty string.
 func GetRawHexTx(r *http.Request) (string, error) {
@@ -576,6 +593,64 @@ func GetIndentCtx(r *http.Request) string {
 	return indent
 }
 
+// BearerToken creates a middleware that only allows requests with an
+// "Authorization: Bearer <token>" header matching the specified token. Other
+// requests are rejected with 401 Unauthorized. An empty token rejects all
+// requests.
+func BearerToken(token string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			auth := r.Header.Get("Authorization")
+			const prefix = "Bearer "
+			if token == "" || !strings.HasPrefix(auth, prefix) ||
+				subtle.ConstantTimeCompare([]byte(auth[len(prefix):]), []byte(token)) != 1 {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// PrivateLogFormatter is a chi middleware.LogFormatter that logs the method,
+// route pattern, status, size and duration of each request. Unlike
+// middleware.DefaultLogFormatter, it does not log the client address, the
+// host, or the request URI, which may contain the addresses and transactions
+// looked up by the client.
+type PrivateLogFormatter struct {
+	Logger chimw.LoggerInterface
+}
+
+// NewLogEntry creates a new LogEntry for the request.
+func (f *PrivateLogFormatter) NewLogEntry(r *http.Request) chimw.LogEntry {
+	return &privateLogEntry{
+		logger: f.Logger,
+		method: r.Method,
+		rctx:   chi.RouteContext(r.Context()),
+	}
+}
+
+type privateLogEntry struct {
+	logger chimw.LoggerInterface
+	method string
+	rctx   *chi.Context
+}
+
+func (e *privateLogEntry) Write(status, bytes int, _ http.Header, elapsed time.Duration, _ interface{}) {
+	// The route pattern is set as the request is routed.
+	route := "-"
+	if e.rctx != nil && e.rctx.RoutePattern() != "" {
+		route = e.rctx.RoutePattern()
+	}
+	e.logger.Print(fmt.Sprintf("\"%s %s\" - %03d %dB in %s", e.method, route,
+		status, bytes, elapsed))
+}
+
+func (e *privateLogEntry) Panic(v interface{}, _ []byte) {
+	e.logger.Print(fmt.Sprintf("panic: %v", v))
+}
+
 // Server sets the Server header element.
 func Server(server string) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
@@ -618,6 +693,55 @@ func BlockIndexPathCtx(next http.Handler) http.Handler {
 	})
 }
 
+// BlockTime is a UNIX time at which to find the nearest mainchain block. The
+// block is the last one at or before the time, or the first one at or after
+// the time if After is true.
+type BlockTime struct {
+	Time  int64
+	After bool
+}
+
+// BlockTimePathCtx returns a http.HandlerFunc that embeds the UNIX time at the
+// url part {timestamp}, and whether the "match" URL query parameter is "after"
+// rather than "before" (the default), into the request context.
+func BlockTimePathCtx(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t, err := strconv.ParseInt(chi.URLParam(r, "timestamp"), 10, 64)
+		if err != nil || t < 0 {
+			apiLog.Infof("No/invalid timestamp value (int64): %v", err)
+			http.Error(w, "Valid UNIX timestamp not provided", http.StatusBadRequest)
+			return
+		}
+		var after bool
+		switch match := r.URL.Query().Get("match"); match {
+		case "", "before":
+		case "after":
+			after = true
+		default:
+			http.Error(w, "match must be before or after", http.StatusBadRequest)
+			return
+		}
+		ctx := context.WithValue(r.Context(), ctxBlockTime, BlockTime{t, after})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// GetBlockTimeCtx retrieves the ctxBlockTime data from the request context. If
+// not set, the return ok is false.
+func GetBlockTimeCtx(r *http.Request) (bt BlockTime, ok bool) {
+	bt, ok = r.Context().Value(ctxBlockTime).(BlockTime)
+	if !ok {
+		apiLog.Trace("block time not set")
+	}
+	return
+}
+
+// BlockIndexCtx embeds the block index into a request context, as
+// BlockIndexPathCtx does for the index on the url path.
+func BlockIndexCtx(r *http.Request, idx int64) context.Context {
+	return context.WithValue(r.Context(), ctxBlockIndex, int(idx)) // Must be int!
+}
+
 // BlockIndexOrHashPathCtx returns a http.HandlerFunc that embeds the value at
 // the url part {idxorhash} into the request context.
 func BlockIndexOrHashPathCtx(next http.Handler) http.Handler {
@@ -717,6 +841,16 @@ func BlockHashPathCtx(next http.Handler) http.Handler {
 	})
 }
 
+// DailyExportFileCtx returns a http.HandlerFunc that embeds the values at the
+// url parts {date} and {file} into the request context.
+func DailyExportFileCtx(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), ctxExportDate, chi.URLParam(r, "date"))
+		ctx = context.WithValue(ctx, ctxExportFile, chi.URLParam(r, "file"))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 // TransactionHashCtx returns a http.HandlerFunc that embeds the value at the
 // url part {txid} into the request context.
 func TransactionHashCtx(next http.Handler) http.Handler {
@@ -952,7 +1086,7 @@ func GetAgendaIdCtx(r *http.Request) string {
 // the corresponding block index, into a request context.
 func BlockHashPathAndIndexCtx(r *http.Request, source DataSource) context.Context {
 	hash := chi.URLParam(r, "blockhash")
-	height, err := source.GetBlockHeight(hash)
+	height, err := source.GetBlockHeight(r.Context(), hash)
 	if err != nil {
 		apiLog.Errorf("Unable to GetBlockHeight(%d): %v", height, err)
 	}
@@ -984,7 +1118,7 @@ func BlockHashLatestCtx(r *http.Request, source DataSource) context.Context {
 	idx, err := source.GetHeight()
 	if idx >= 0 && err == nil {
 		var err error
-		if hash, err = source.GetBlockHash(idx); err != nil {
+		if hash, err = source.GetBlockHash(r.Context(), idx); err != nil {
 			apiLog.Errorf("Unable to GetBlockHash(%d): %v", idx, err)
 		}
 	}
@@ -1026,7 +1160,7 @@ func GetBlockHeightCtx(r *http.Request, source DataSource) (int64, error) {
 		if err != nil {
 			return 0, err
 		}
-		idx, err = source.GetBlockHeight(hash)
+		idx, err = source.GetBlockHeight(r.Context(), hash)
 		if err != nil {
 			return 0, err
 		}
@@ -4,6 +4,8 @@
 package middleware
 
 import (
+	"bytes"
+	"log"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
@@ -12,6 +14,7 @@ import (
 
 	"github.com/decred/dcrd/chaincfg/v2"
 	"github.com/go-chi/chi"
+	chimw "github.com/go-chi/chi/middleware"
 )
 
 func TestGetAddressCtx(t *testing.T) {
@@ -132,3 +135,95 @@ func TestGetAddressCtx(t *testing.T) {
 		})
 	}
 }
+
+func TestBearerToken(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tests := []struct {
+		name     string
+		token    string
+		auth     string
+		wantCode int
+	}{
+		{"valid", "s3cret", "Bearer s3cret", http.StatusOK},
+		{"wrong token", "s3cret", "Bearer s3cre", http.StatusUnauthorized},
+		{"no header", "s3cret", "", http.StatusUnauthorized},
+		{"wrong scheme", "s3cret", "Basic s3cret", http.StatusUnauthorized},
+		{"no token configured", "", "Bearer ", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/admin", nil)
+			if tt.auth != "" {
+				r.Header.Set("Authorization", tt.auth)
+			}
+			w := httptest.NewRecorder()
+			BearerToken(tt.token)(ok).ServeHTTP(w, r)
+			if w.Code != tt.wantCode {
+				t.Errorf("got status %d, wanted %d", w.Code, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestBlockTimePathCtx(t *testing.T) {
+	var got BlockTime
+	handler := BlockTimePathCtx(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, _ = GetBlockTimeCtx(r)
+	}))
+	mux := chi.NewRouter()
+	mux.Get("/block/at/{timestamp}", handler.ServeHTTP)
+
+	tests := []struct {
+		name     string
+		path     string
+		wantCode int
+		want     BlockTime
+	}{
+		{"before by default", "/block/at/1576000000", http.StatusOK, BlockTime{1576000000, false}},
+		{"before", "/block/at/1576000000?match=before", http.StatusOK, BlockTime{1576000000, false}},
+		{"after", "/block/at/1576000000?match=after", http.StatusOK, BlockTime{1576000000, true}},
+		{"bad match", "/block/at/1576000000?match=nearest", http.StatusBadRequest, BlockTime{}},
+		{"negative", "/block/at/-1", http.StatusBadRequest, BlockTime{}},
+		{"not a number", "/block/at/yesterday", http.StatusBadRequest, BlockTime{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = BlockTime{}
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest("GET", tt.path, nil))
+			if w.Code != tt.wantCode {
+				t.Fatalf("got status %d, wanted %d", w.Code, tt.wantCode)
+			}
+			if got != tt.want {
+				t.Errorf("got block time %+v, wanted %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrivateLogFormatter(t *testing.T) {
+	var buf bytes.Buffer
+	mux := chi.NewRouter()
+	mux.Use(chimw.RequestLogger(&PrivateLogFormatter{Logger: log.New(&buf, "", 0)}))
+	mux.Get("/address/{address}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	r := httptest.NewRequest("GET", "/address/DsUxwT6Kbiur6F2mTuCaXPp3UeEBZxkGzQs?n=20", nil)
+	r.RemoteAddr = "192.0.2.1:51234"
+	r.Header.Set("User-Agent", "test-agent")
+	mux.ServeHTTP(httptest.NewRecorder(), r)
+
+	got := buf.String()
+	if !strings.Contains(got, `"GET /address/{address}" - 200`) {
+		t.Errorf("unexpected log entry %q", got)
+	}
+	for _, private := range []string{"DsUxwT6", "192.0.2.1", "test-agent", "n=20"} {
+		if strings.Contains(got, private) {
+			t.Errorf("log entry %q contains %q", got, private)
+		}
+	}
+}
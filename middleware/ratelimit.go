@@ -0,0 +1,303 @@
+// Copyright (c) 2020, The Decred developers
+// See LICENSE for details.
+
+package middleware
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// APIKeyHeader is the request header in which clients may present an API
+	// key to be rate limited per key rather than per IP address.
+	APIKeyHeader = "X-API-Key"
+
+	// Budget names used for the rate limiter's buckets and metrics.
+	budgetDefault   = "default"
+	budgetExpensive = "expensive"
+
+	// bucketPurgeInterval is how often idle buckets are removed.
+	bucketPurgeInterval = time.Minute
+)
+
+// RateBudget is a token bucket budget. A client may make up to Burst requests
+// at once, and the bucket refills at Rate requests per second. A zero Rate
+// disables the budget.
+type RateBudget struct {
+	Rate  float64
+	Burst int
+}
+
+func (b RateBudget) enabled() bool {
+	return b.Rate > 0
+}
+
+// scale multiplies the rate and burst of the budget by f.
+func (b RateBudget) scale(f float64) RateBudget {
+	return RateBudget{
+		Rate:  b.Rate * f,
+		Burst: int(math.Ceil(float64(b.Burst) * f)),
+	}
+}
+
+// RateLimiterConfig is the configuration for a RateLimiter.
+type RateLimiterConfig struct {
+	// Default is the per-client budget applied to all limited routes.
+	Default RateBudget
+	// Expensive is an additional per-client budget for costly routes such as
+	// address history and charts. It is not enforced if its Rate is zero.
+	Expensive RateBudget
+	// Keys are the API keys that clients may send in the APIKeyHeader header.
+	// Requests with a known key are limited per key instead of per IP, with
+	// budgets scaled by KeyFactor.
+	Keys []string
+	// KeyFactor scales the budgets for requests with a known API key. If
+	// zero, keyed clients get the same budgets as anonymous ones.
+	KeyFactor float64
+}
+
+// RateBudgetMetrics are the counters for one rate limiter budget.
+type RateBudgetMetrics struct {
+	Rate    float64 `json:"rate"`
+	Burst   int     `json:"burst"`
+	Allowed uint64  `json:"allowed"`
+	Limited uint64  `json:"limited"`
+}
+
+// RateLimitMetrics is a snapshot of the rate limiter's state.
+type RateLimitMetrics struct {
+	Clients int                           `json:"clients"`
+	Budgets map[string]*RateBudgetMetrics `json:"budgets"`
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// refill adds the tokens accrued since the last update, up to the burst.
+func (b *tokenBucket) refill(budget RateBudget, now time.Time) {
+	b.tokens += now.Sub(b.last).Seconds() * budget.Rate
+	if b.tokens > float64(budget.Burst) {
+		b.tokens = float64(budget.Burst)
+	}
+	b.last = now
+}
+
+type bucketKey struct {
+	budget string
+	client string
+}
+
+// RateLimiter is a token bucket rate limiter with per-client budgets. Clients
+// are identified by API key if they present a configured one, and otherwise by
+// IP address. When rate limiting behind a reverse proxy, the RealIP middleware
+// should run before the limiter. A nil *RateLimiter does not limit requests.
+type RateLimiter struct {
+	mtx       sync.Mutex
+	budgets   map[string]RateBudget
+	keys      map[string]struct{}
+	keyFactor float64
+	buckets   map[bucketKey]*tokenBucket
+	metrics   map[string]*RateBudgetMetrics
+	lastPurge time.Time
+	now       func() time.Time
+}
+
+// NewRateLimiter creates a RateLimiter from the given configuration. If the
+// default budget is disabled, NewRateLimiter returns nil, which is a usable
+// RateLimiter that allows every request. A Burst less than the Rate is raised
+// to the next whole number of requests per second.
+func NewRateLimiter(cfg *RateLimiterConfig) *RateLimiter {
+	if cfg == nil || !cfg.Default.enabled() {
+		return nil
+	}
+	rl := &RateLimiter{
+		budgets:   make(map[string]RateBudget, 2),
+		keys:      make(map[string]struct{}, len(cfg.Keys)),
+		keyFactor: cfg.KeyFactor,
+		buckets:   make(map[bucketKey]*tokenBucket),
+		metrics:   make(map[string]*RateBudgetMetrics, 2),
+		now:       time.Now,
+	}
+	if rl.keyFactor <= 0 {
+		rl.keyFactor = 1
+	}
+	for _, k := range cfg.Keys {
+		if k != "" {
+			rl.keys[k] = struct{}{}
+		}
+	}
+	addBudget := func(name string, b RateBudget) {
+		if !b.enabled() {
+			return
+		}
+		if minBurst := int(math.Ceil(b.Rate)); b.Burst < minBurst {
+			b.Burst = minBurst
+		}
+		rl.budgets[name] = b
+		rl.metrics[name] = &RateBudgetMetrics{Rate: b.Rate, Burst: b.Burst}
+	}
+	addBudget(budgetDefault, cfg.Default)
+	addBudget(budgetExpensive, cfg.Expensive)
+	rl.lastPurge = rl.now()
+	return rl
+}
+
+// requestClient identifies the requester by API key if the request carries
+// one of the keys, and otherwise by IP address. It returns the client ID and
+// whether the request carried a known API key.
+func requestClient(r *http.Request, keys map[string]struct{}) (string, bool) {
+	return keyClient(r.Header.Get(APIKeyHeader), r.RemoteAddr, keys)
+}
+
+// keyClient identifies a client by the API key if it is one of the keys, and
+// otherwise by the IP address of the remote address, which may be given with
+// or without a port.
+func keyClient(key, remoteAddr string, keys map[string]struct{}) (string, bool) {
+	if key != "" {
+		if _, ok := keys[key]; ok {
+			return "key:" + key, true
+		}
+	}
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		// RealIP sets RemoteAddr without a port.
+		host = remoteAddr
+	}
+	return "ip:" + host, false
+}
+
+// take attempts to remove a token from the client's bucket for the named
+// budget. keyed indicates whether the client is identified by an API key. It
+// returns whether the request is allowed, the effective budget, and the tokens
+// left in the bucket.
+func (rl *RateLimiter) take(budgetName, client string, keyed bool) (bool, RateBudget, float64) {
+	rl.mtx.Lock()
+	defer rl.mtx.Unlock()
+
+	now := rl.now()
+	if now.Sub(rl.lastPurge) >= bucketPurgeInterval {
+		rl.purge(now)
+	}
+
+	budget := rl.budgets[budgetName]
+	if keyed {
+		budget = budget.scale(rl.keyFactor)
+	}
+
+	bk := bucketKey{budgetName, client}
+	b, found := rl.buckets[bk]
+	if !found {
+		b = &tokenBucket{tokens: float64(budget.Burst), last: now}
+		rl.buckets[bk] = b
+	}
+	b.refill(budget, now)
+
+	metrics := rl.metrics[budgetName]
+	if b.tokens < 1 {
+		metrics.Limited++
+		return false, budget, b.tokens
+	}
+	b.tokens--
+	metrics.Allowed++
+	return true, budget, b.tokens
+}
+
+// purge removes buckets that have refilled completely, since a new bucket
+// would be in the same state. The mutex must be held.
+func (rl *RateLimiter) purge(now time.Time) {
+	for bk, b := range rl.buckets {
+		budget := rl.budgets[bk.budget]
+		if strings.HasPrefix(bk.client, "key:") {
+			budget = budget.scale(rl.keyFactor)
+		}
+		b.refill(budget, now)
+		if b.tokens >= float64(budget.Burst) {
+			delete(rl.buckets, bk)
+		}
+	}
+	rl.lastPurge = now
+}
+
+// limit creates a middleware that charges the named budget for each request.
+func (rl *RateLimiter) limit(budgetName string, next http.Handler) http.Handler {
+	if rl == nil {
+		return next
+	}
+	if _, ok := rl.budgets[budgetName]; !ok {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		client, keyed := requestClient(r, rl.keys)
+		ok, budget, tokens := rl.take(budgetName, client, keyed)
+
+		// Advertise the budget using the RateLimit header fields. The reset
+		// time is when the bucket will be full again.
+		h := w.Header()
+		h.Set("RateLimit-Limit", strconv.Itoa(budget.Burst))
+		h.Set("RateLimit-Remaining", strconv.Itoa(int(tokens)))
+		reset := math.Ceil((float64(budget.Burst) - tokens) / budget.Rate)
+		h.Set("RateLimit-Reset", strconv.Itoa(int(reset)))
+
+		if !ok {
+			retry := math.Ceil((1 - tokens) / budget.Rate)
+			h.Set("Retry-After", strconv.Itoa(int(retry)))
+			http.Error(w, http.StatusText(http.StatusTooManyRequests),
+				http.StatusTooManyRequests)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Limit is a middleware that enforces the default budget.
+func (rl *RateLimiter) Limit(next http.Handler) http.Handler {
+	return rl.limit(budgetDefault, next)
+}
+
+// LimitExpensive is a middleware that enforces the expensive route budget. It
+// is meant to be used in addition to Limit, so expensive requests are charged
+// against both budgets.
+func (rl *RateLimiter) LimitExpensive(next http.Handler) http.Handler {
+	return rl.limit(budgetExpensive, next)
+}
+
+// Allow charges the default budget for a request that is not made over HTTP,
+// such as a gRPC call, and reports whether it is allowed. The client is
+// identified by the API key if it is a configured one, and otherwise by the
+// IP address of the remote address. A nil RateLimiter allows every request.
+func (rl *RateLimiter) Allow(key, remoteAddr string) bool {
+	if rl == nil {
+		return true
+	}
+	client, keyed := keyClient(key, remoteAddr, rl.keys)
+	ok, _, _ := rl.take(budgetDefault, client, keyed)
+	return ok
+}
+
+// Metrics returns a snapshot of the request counters for each budget and the
+// number of tracked client buckets. Metrics returns nil for a nil RateLimiter.
+func (rl *RateLimiter) Metrics() *RateLimitMetrics {
+	if rl == nil {
+		return nil
+	}
+	rl.mtx.Lock()
+	defer rl.mtx.Unlock()
+	m := &RateLimitMetrics{
+		Clients: len(rl.buckets),
+		Budgets: make(map[string]*RateBudgetMetrics, len(rl.metrics)),
+	}
+	for name, bm := range rl.metrics {
+		c := *bm
+		m.Budgets[name] = &c
+	}
+	return m
+}
@@ -0,0 +1,135 @@
+// Copyright (c) 2020, The Decred developers
+// See LICENSE for details.
+
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter(t *testing.T) {
+	rl := NewRateLimiter(&RateLimiterConfig{
+		Default:   RateBudget{Rate: 1, Burst: 3},
+		Expensive: RateBudget{Rate: 0.5, Burst: 1},
+		Keys:      []string{"sekrit"},
+		KeyFactor: 2,
+	})
+	now := time.Unix(1576000000, 0)
+	rl.now = func() time.Time { return now }
+	rl.lastPurge = now
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	cheap := rl.Limit(ok)
+	expensive := rl.Limit(rl.LimitExpensive(ok))
+
+	do := func(h http.Handler, ip, key string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.RemoteAddr = ip + ":1234"
+		if key != "" {
+			r.Header.Set(APIKeyHeader, key)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	// The burst is allowed, then the client is limited.
+	for i := 2; i >= 0; i-- {
+		w := do(cheap, "10.0.0.1", "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("request %d limited", 3-i)
+		}
+		if rem := w.Header().Get("RateLimit-Remaining"); rem != strconv.Itoa(i) {
+			t.Errorf("got RateLimit-Remaining %s, wanted %d", rem, i)
+		}
+	}
+	w := do(cheap, "10.0.0.1", "")
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("got status %d, wanted %d", w.Code, http.StatusTooManyRequests)
+	}
+	if ra := w.Header().Get("Retry-After"); ra != "1" {
+		t.Errorf("got Retry-After %s, wanted 1", ra)
+	}
+
+	// Other clients have their own buckets, and unknown keys fall back to IP.
+	if w = do(cheap, "10.0.0.2", "wrong"); w.Code != http.StatusOK {
+		t.Errorf("other client limited")
+	}
+
+	// The bucket refills over time.
+	now = now.Add(time.Second)
+	if w = do(cheap, "10.0.0.1", ""); w.Code != http.StatusOK {
+		t.Errorf("request limited after refill")
+	}
+
+	// Expensive routes have a smaller budget.
+	if w = do(expensive, "10.0.0.3", ""); w.Code != http.StatusOK {
+		t.Fatalf("first expensive request limited")
+	}
+	if w = do(expensive, "10.0.0.3", ""); w.Code != http.StatusTooManyRequests {
+		t.Errorf("second expensive request not limited")
+	}
+	if ra := w.Header().Get("Retry-After"); ra != "2" {
+		t.Errorf("got Retry-After %s, wanted 2", ra)
+	}
+
+	// Keyed clients get scaled budgets regardless of IP.
+	for i := 0; i < 6; i++ {
+		if w = do(cheap, "10.0.0.1", "sekrit"); w.Code != http.StatusOK {
+			t.Fatalf("keyed request %d limited", i+1)
+		}
+	}
+	if w = do(cheap, "10.0.0.4", "sekrit"); w.Code != http.StatusTooManyRequests {
+		t.Errorf("keyed client not limited")
+	}
+
+	m := rl.Metrics()
+	if d := m.Budgets[budgetDefault]; d.Allowed != 13 || d.Limited != 2 {
+		t.Errorf("got default budget metrics %+v", d)
+	}
+	if e := m.Budgets[budgetExpensive]; e.Allowed != 1 || e.Limited != 1 {
+		t.Errorf("got expensive budget metrics %+v", e)
+	}
+
+	// Requests made other than over HTTP share the budgets.
+	if rl.Allow("sekrit", "10.0.0.6:5678") {
+		t.Errorf("keyed client not limited over gRPC")
+	}
+	if !rl.Allow("", "10.0.0.6:5678") {
+		t.Errorf("new client limited over gRPC")
+	}
+	m = rl.Metrics()
+	if d := m.Budgets[budgetDefault]; d.Allowed != 14 || d.Limited != 3 {
+		t.Errorf("got default budget metrics %+v", d)
+	}
+
+	// Idle buckets are purged once full.
+	now = now.Add(bucketPurgeInterval)
+	do(cheap, "10.0.0.5", "")
+	if m = rl.Metrics(); m.Clients != 1 {
+		t.Errorf("got %d clients after purge, wanted 1", m.Clients)
+	}
+}
+
+func TestRateLimiterDisabled(t *testing.T) {
+	rl := NewRateLimiter(&RateLimiterConfig{})
+	if rl != nil {
+		t.Fatal("expected a nil RateLimiter")
+	}
+	h := rl.Limit(rl.LimitExpensive(http.NotFoundHandler()))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	if w.Code != http.StatusNotFound || w.Header().Get("RateLimit-Limit") != "" {
+		t.Errorf("disabled limiter altered the response")
+	}
+	if !rl.Allow("", "10.0.0.1:1234") {
+		t.Errorf("disabled limiter limited a request")
+	}
+	if rl.Metrics() != nil {
+		t.Errorf("expected nil metrics")
+	}
+}
@@ -0,0 +1,434 @@
+// Copyright (c) 2020, The Decred developers
+// See LICENSE for details.
+
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi"
+)
+
+const (
+	// ResponseCacheName is the name of the response cache in the cache
+	// statistics and flush requests.
+	ResponseCacheName = "responses"
+
+	// respFileExt is the extension of the response cache files.
+	respFileExt = ".resp"
+
+	// defaultMaxResponseSize is the largest response body that is cached if
+	// ResponseCacheConfig.MaxSize is not set.
+	defaultMaxResponseSize = 32 << 20
+)
+
+// ResponseCacheConfig is the configuration for a ResponseCache.
+type ResponseCacheConfig struct {
+	// Dir is the directory in which the response bodies are stored. The cache
+	// is disabled if it is empty.
+	Dir string
+	// Prefixes are the URL path prefixes of the cached routes.
+	Prefixes []string
+	// Exclude are the URL path prefixes of routes matching Prefixes that are
+	// not cached, such as routes whose responses do not only change with new
+	// blocks.
+	Exclude []string
+	// Params are the URL query parameters that select the response of a
+	// cached route. Other query parameters do not affect the cache key.
+	Params []string
+	// MaxEntries is the maximum number of cached URLs. It is not limited if
+	// zero.
+	MaxEntries int
+	// MaxSize is the largest response body, in bytes, that is cached.
+	MaxSize int64
+}
+
+type cachedResponse struct {
+	routePath   string
+	file        string
+	contentType string
+	next        http.Handler
+	updated     time.Time
+	// requested is set when the response is requested, and cleared when it is
+	// refreshed, so that responses that are no longer requested are dropped.
+	requested bool
+}
+
+// ResponseCache is a middleware that stores successful responses of GET
+// requests to the configured routes on disk, keyed by URL path and the
+// configured query parameters, and serves
+// them without calling the next handler. After each new block, Refresh
+// regenerates the responses that were requested since the previous block in
+// the background while the stored ones continue to be served. The cache is
+// meant for expensive routes whose responses only change with new blocks. A
+// nil *ResponseCache does not cache responses.
+type ResponseCache struct {
+	dir        string
+	prefixes   []string
+	exclude    []string
+	params     []string
+	maxEntries int
+	maxSize    int64
+
+	mtx     sync.Mutex
+	block   string
+	entries map[string]*cachedResponse
+	hits    int
+	misses  int
+
+	refreshMtx sync.Mutex
+}
+
+// NewResponseCache creates a ResponseCache from the given configuration,
+// creating the cache directory and removing any responses left in it. If the
+// directory is not set, NewResponseCache returns nil, which is a usable
+// ResponseCache that does not cache responses.
+func NewResponseCache(cfg *ResponseCacheConfig) (*ResponseCache, error) {
+	if cfg == nil || cfg.Dir == "" {
+		return nil, nil
+	}
+	if err := os.MkdirAll(cfg.Dir, 0700); err != nil {
+		return nil, err
+	}
+	stale, err := filepath.Glob(filepath.Join(cfg.Dir, "*"+respFileExt))
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range stale {
+		if err = os.Remove(file); err != nil {
+			return nil, err
+		}
+	}
+	maxSize := cfg.MaxSize
+	if maxSize <= 0 {
+		maxSize = defaultMaxResponseSize
+	}
+	return &ResponseCache{
+		dir:        cfg.Dir,
+		prefixes:   cfg.Prefixes,
+		exclude:    cfg.Exclude,
+		params:     cfg.Params,
+		maxEntries: cfg.MaxEntries,
+		maxSize:    maxSize,
+		entries:    make(map[string]*cachedResponse),
+	}, nil
+}
+
+// routePath is the path used to route the request, which is relative to the
+// mount point of the router.
+func routePath(r *http.Request) string {
+	if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePath != "" {
+		return rctx.RoutePath
+	}
+	return r.URL.Path
+}
+
+// cacheable checks if responses to a request with the given method and route
+// path may be cached.
+func (rc *ResponseCache) cacheable(method, path string) bool {
+	if method != http.MethodGet {
+		return false
+	}
+	for _, prefix := range rc.exclude {
+		if strings.HasPrefix(path, prefix) {
+			return false
+		}
+	}
+	for _, prefix := range rc.prefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// cacheKey is the request URI of the cached response for the URL. It is
+// the URL path with only the configured query parameters, in sorted order, so
+// that requests differing only in other parameters or in parameter order share
+// a response. Like the handlers, only the first value of each parameter is
+// used, and empty parameters are omitted. The key is itself a request URI, which
+// is used to refresh the response.
+func (rc *ResponseCache) cacheKey(u *url.URL) string {
+	query := u.Query()
+	params := make(url.Values, len(rc.params))
+	for _, param := range rc.params {
+		if v := query.Get(param); v != "" {
+			params.Set(param, v)
+		}
+	}
+	if len(params) == 0 {
+		return u.EscapedPath()
+	}
+	return u.EscapedPath() + "?" + params.Encode()
+}
+
+// fileName is the name of the file storing the response for the request URI.
+func (rc *ResponseCache) fileName(uri string) string {
+	h := sha256.Sum256([]byte(uri))
+	return filepath.Join(rc.dir, hex.EncodeToString(h[:])+respFileExt)
+}
+
+// Cache is the response caching middleware. It must be used before any
+// middleware that sets request context values used by the handlers, such as
+// Indent, since the responses are refreshed by requests without them.
+func (rc *ResponseCache) Cache(next http.Handler) http.Handler {
+	if rc == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := routePath(r)
+		if !rc.cacheable(r.Method, path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		uri := rc.cacheKey(r.URL)
+		rc.mtx.Lock()
+		block := rc.block
+		entry, found := rc.entries[uri]
+		if found {
+			entry.requested = true
+			rc.hits++
+		} else {
+			rc.misses++
+		}
+		rc.mtx.Unlock()
+
+		if found && rc.serve(w, entry) {
+			return
+		}
+
+		rec := &responseRecorder{ResponseWriter: w, limit: rc.maxSize}
+		next.ServeHTTP(rec, r)
+		rc.store(uri, path, next, rec, block, true)
+	})
+}
+
+// serve writes the stored response to w. A response that could not be read
+// from disk is dropped, and serve returns false before writing anything.
+func (rc *ResponseCache) serve(w http.ResponseWriter, entry *cachedResponse) bool {
+	// A refresh may replace the file while it is served, but the open file is
+	// not affected.
+	f, err := os.Open(entry.file)
+	if err != nil {
+		apiLog.Warnf("Unable to read cached response: %v", err)
+		rc.mtx.Lock()
+		for uri, e := range rc.entries {
+			if e == entry {
+				delete(rc.entries, uri)
+			}
+		}
+		rc.mtx.Unlock()
+		return false
+	}
+	defer f.Close()
+	if entry.contentType != "" {
+		w.Header().Set("Content-Type", entry.contentType)
+	}
+	if _, err = io.Copy(w, f); err != nil {
+		apiLog.Debugf("Failed to write cached response: %v", err)
+	}
+	return true
+}
+
+// store saves a successful recorded response for the request URI if the best
+// block is still the one at which the request started.
+func (rc *ResponseCache) store(uri, path string, next http.Handler, rec *responseRecorder,
+	block string, requested bool) {
+	if rec.status != http.StatusOK || rec.tooBig || rec.Header().Get("Content-Encoding") != "" {
+		return
+	}
+
+	rc.mtx.Lock()
+	defer rc.mtx.Unlock()
+	if rc.block != block {
+		return
+	}
+	entry, found := rc.entries[uri]
+	if !found && rc.maxEntries > 0 && len(rc.entries) >= rc.maxEntries {
+		return
+	}
+
+	// Write the new response to a temporary file and rename it so that the
+	// previous response can be served until it is replaced.
+	file := rc.fileName(uri)
+	tmp := file + ".tmp"
+	if err := ioutil.WriteFile(tmp, rec.body.Bytes(), 0600); err != nil {
+		apiLog.Warnf("Unable to cache response: %v", err)
+		return
+	}
+	if err := os.Rename(tmp, file); err != nil {
+		apiLog.Warnf("Unable to cache response: %v", err)
+		os.Remove(tmp)
+		return
+	}
+
+	if !found {
+		entry = &cachedResponse{routePath: path, file: file}
+		rc.entries[uri] = entry
+	}
+	entry.contentType = rec.Header().Get("Content-Type")
+	entry.next = next
+	entry.updated = time.Now()
+	entry.requested = entry.requested || requested
+}
+
+// Refresh regenerates the cached responses for a new best block with the
+// given hash. Responses that were not requested since the previous refresh
+// are dropped instead. The stored responses are served until they are
+// replaced, so Refresh may be run asynchronously.
+func (rc *ResponseCache) Refresh(blockHash string) {
+	if rc == nil {
+		return
+	}
+	rc.refreshMtx.Lock()
+	defer rc.refreshMtx.Unlock()
+
+	rc.mtx.Lock()
+	rc.block = blockHash
+	refresh := make(map[string]cachedResponse, len(rc.entries))
+	for uri, entry := range rc.entries {
+		if !entry.requested {
+			os.Remove(entry.file)
+			delete(rc.entries, uri)
+			continue
+		}
+		entry.requested = false
+		refresh[uri] = *entry
+	}
+	rc.mtx.Unlock()
+
+	for uri, entry := range refresh {
+		r, err := http.NewRequest(http.MethodGet, uri, nil)
+		if err != nil {
+			apiLog.Errorf("Invalid cached request URI %q: %v", uri, err)
+			continue
+		}
+		// The handlers are routed with chi, which expects a routing context
+		// with the path relative to the router's mount point.
+		rctx := chi.NewRouteContext()
+		rctx.RoutePath = entry.routePath
+		r = r.WithContext(context.WithValue(context.Background(), chi.RouteCtxKey, rctx))
+		rec := &responseRecorder{header: make(http.Header), limit: rc.maxSize}
+		entry.next.ServeHTTP(rec, r)
+		if rec.status != http.StatusOK {
+			apiLog.Debugf("Dropping cached response for %s (status %d).", uri, rec.status)
+			rc.drop(uri)
+			continue
+		}
+		rc.store(uri, entry.routePath, entry.next, rec, blockHash, false)
+	}
+	apiLog.Debugf("Refreshed %d cached responses.", len(refresh))
+}
+
+// drop removes the cached response for the request URI.
+func (rc *ResponseCache) drop(uri string) {
+	rc.mtx.Lock()
+	defer rc.mtx.Unlock()
+	if entry, found := rc.entries[uri]; found {
+		os.Remove(entry.file)
+		delete(rc.entries, uri)
+	}
+}
+
+// Flush removes all cached responses, returning the number removed.
+func (rc *ResponseCache) Flush() int {
+	if rc == nil {
+		return 0
+	}
+	rc.mtx.Lock()
+	defer rc.mtx.Unlock()
+	n := len(rc.entries)
+	for uri, entry := range rc.entries {
+		os.Remove(entry.file)
+		delete(rc.entries, uri)
+	}
+	return n
+}
+
+// ResponseCacheStats are the counters of a ResponseCache. Updated is the time
+// of the most recently stored response.
+type ResponseCacheStats struct {
+	Entries int
+	Hits    int
+	Misses  int
+	Updated time.Time
+}
+
+// Stats reports the number of cached responses, the request counts, and when
+// a response was last stored. Stats returns nil for a nil ResponseCache.
+func (rc *ResponseCache) Stats() *ResponseCacheStats {
+	if rc == nil {
+		return nil
+	}
+	rc.mtx.Lock()
+	defer rc.mtx.Unlock()
+	stats := &ResponseCacheStats{
+		Entries: len(rc.entries),
+		Hits:    rc.hits,
+		Misses:  rc.misses,
+	}
+	for _, entry := range rc.entries {
+		if entry.updated.After(stats.Updated) {
+			stats.Updated = entry.updated
+		}
+	}
+	return stats
+}
+
+// responseRecorder records the status and body of a response, up to limit
+// bytes, while writing it to the embedded ResponseWriter if it is not nil.
+type responseRecorder struct {
+	http.ResponseWriter
+	header http.Header
+	status int
+	body   bytes.Buffer
+	limit  int64
+	tooBig bool
+}
+
+func (rec *responseRecorder) Header() http.Header {
+	if rec.ResponseWriter != nil {
+		return rec.ResponseWriter.Header()
+	}
+	return rec.header
+}
+
+func (rec *responseRecorder) WriteHeader(status int) {
+	if rec.status == 0 {
+		rec.status = status
+	}
+	if rec.ResponseWriter != nil {
+		rec.ResponseWriter.WriteHeader(status)
+	}
+}
+
+func (rec *responseRecorder) Write(b []byte) (int, error) {
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+	if !rec.tooBig {
+		if int64(rec.body.Len()+len(b)) > rec.limit {
+			rec.tooBig = true
+			rec.body = bytes.Buffer{}
+		} else {
+			rec.body.Write(b)
+		}
+	}
+	if rec.ResponseWriter != nil {
+		return rec.ResponseWriter.Write(b)
+	}
+	return len(b), nil
+}
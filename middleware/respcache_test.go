@@ -0,0 +1,201 @@
+// Copyright (c) 2020, The Decred developers
+// See LICENSE for details.
+
+package middleware
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-chi/chi"
+)
+
+func TestResponseCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "respcache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	rc, err := NewResponseCache(&ResponseCacheConfig{
+		Dir:        dir,
+		Prefixes:   []string{"/chart/"},
+		Params:     []string{"bin"},
+		MaxEntries: 2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The cached router is mounted like the API router.
+	var calls int
+	api := chi.NewRouter()
+	api.Use(rc.Cache)
+	api.Get("/chart/{name}", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"chart":%q,"n":%d}`, chi.URLParam(r, "name"), calls)
+	})
+	api.Get("/status", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+	})
+	api.Get("/chart/bad", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		http.Error(w, "bad", http.StatusUnprocessableEntity)
+	})
+	mux := chi.NewRouter()
+	mux.Mount("/api", api)
+
+	get := func(uri string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest("GET", uri, nil))
+		return w
+	}
+
+	rc.Refresh("block1")
+
+	// The first request is served by the handler, and the response is cached.
+	if w := get("/api/chart/fees"); w.Body.String() != `{"chart":"fees","n":1}` {
+		t.Fatalf("unexpected response %q", w.Body.String())
+	}
+	w := get("/api/chart/fees")
+	if w.Body.String() != `{"chart":"fees","n":1}` || calls != 1 {
+		t.Fatalf("response not cached: %q, %d calls", w.Body.String(), calls)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("got Content-Type %q", ct)
+	}
+
+	// Other routes and unsuccessful responses are not cached.
+	get("/api/status")
+	get("/api/status")
+	get("/api/chart/bad")
+	get("/api/chart/bad")
+	if calls != 5 {
+		t.Errorf("got %d handler calls, wanted 5", calls)
+	}
+
+	// Requests with other queries are cached separately, up to MaxEntries.
+	get("/api/chart/fees?bin=day")
+	get("/api/chart/size")
+	get("/api/chart/size")
+	if s := rc.Stats(); s.Entries != 2 || s.Hits != 1 || s.Misses != 6 {
+		t.Errorf("unexpected stats %+v", s)
+	}
+	if calls != 8 {
+		t.Errorf("got %d handler calls, wanted 8", calls)
+	}
+
+	// A new block refreshes the requested responses.
+	rc.Refresh("block2")
+	if calls != 10 {
+		t.Errorf("got %d handler calls after refresh, wanted 10", calls)
+	}
+	if w := get("/api/chart/fees"); w.Body.String() != `{"chart":"fees","n":9}` &&
+		w.Body.String() != `{"chart":"fees","n":10}` {
+		t.Errorf("response not refreshed: %q", w.Body.String())
+	}
+	if calls != 10 {
+		t.Errorf("refreshed response not cached")
+	}
+
+	// Responses not requested since the last block are dropped.
+	rc.Refresh("block3")
+	if s := rc.Stats(); s.Entries != 1 || calls != 11 {
+		t.Errorf("got %d entries and %d calls after refresh", s.Entries, calls)
+	}
+
+	if n := rc.Flush(); n != 1 {
+		t.Errorf("flushed %d responses, wanted 1", n)
+	}
+	if files, _ := filepath.Glob(filepath.Join(dir, "*")); len(files) != 0 {
+		t.Errorf("files left after flush: %v", files)
+	}
+}
+
+func TestResponseCacheDisabled(t *testing.T) {
+	rc, err := NewResponseCache(&ResponseCacheConfig{})
+	if err != nil || rc != nil {
+		t.Fatalf("expected a nil ResponseCache, got %v, %v", rc, err)
+	}
+	h := rc.Cache(http.NotFoundHandler())
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/chart/fees", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("disabled cache altered the response")
+	}
+	rc.Refresh("block")
+	if rc.Stats() != nil || rc.Flush() != 0 {
+		t.Errorf("expected no stats")
+	}
+}
+
+func TestResponseCacheKey(t *testing.T) {
+	dir, err := ioutil.TempDir("", "respcache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	rc, err := NewResponseCache(&ResponseCacheConfig{
+		Dir:      dir,
+		Prefixes: []string{"/chart/"},
+		Exclude:  []string{"/chart/market/"},
+		Params:   []string{"bin", "axis"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var calls int
+	api := chi.NewRouter()
+	api.Use(rc.Cache)
+	api.Get("/chart/*", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		fmt.Fprintf(w, "%d", calls)
+	})
+	mux := chi.NewRouter()
+	mux.Mount("/api", api)
+
+	get := func(uri string) string {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest("GET", uri, nil))
+		return w.Body.String()
+	}
+
+	rc.Refresh("block1")
+
+	// Unknown parameters, parameter order, repeated values and empty
+	// parameters do not change the key.
+	get("/api/chart/fees?bin=day&axis=time")
+	for _, uri := range []string{
+		"/api/chart/fees?axis=time&bin=day",
+		"/api/chart/fees?bin=day&axis=time&_=12345",
+		"/api/chart/fees?bin=day&bin=block&axis=time&zoom=",
+	} {
+		if body := get(uri); body != "1" {
+			t.Errorf("%s: got response %q, wanted the cached one", uri, body)
+		}
+	}
+	if body := get("/api/chart/fees?bin=block&axis=time"); body != "2" {
+		t.Errorf("got response %q for another bin", body)
+	}
+	if key := rc.cacheKey(&url.URL{Path: "/api/chart/fees", RawQuery: "z=1&bin=day&axis="}); key != "/api/chart/fees?bin=day" {
+		t.Errorf("got cache key %q", key)
+	}
+
+	// Excluded routes are not cached.
+	get("/api/chart/market/binance/depth")
+	if body := get("/api/chart/market/binance/depth"); body != "4" {
+		t.Errorf("excluded route cached, got response %q", body)
+	}
+	if s := rc.Stats(); s.Entries != 2 {
+		t.Errorf("got %d cached responses, wanted 2", s.Entries)
+	}
+}
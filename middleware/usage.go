@@ -0,0 +1,310 @@
+// Copyright (c) 2020, The Decred developers
+// See LICENSE for details.
+
+package middleware
+
+import (
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi"
+	chimw "github.com/go-chi/chi/middleware"
+)
+
+const (
+	// usageSlotDuration is the span of the request counters that are rotated
+	// out of the usage tracker's window as they expire.
+	usageSlotDuration = time.Minute
+
+	// defaultUsageWindow is the usage window if UsageTrackerConfig.Window is
+	// not set.
+	defaultUsageWindow = time.Hour
+
+	// defaultMaxUsageClients is the number of distinct clients counted in each
+	// slot if UsageTrackerConfig.MaxClients is not set.
+	defaultMaxUsageClients = 10000
+
+	// Route and client names for requests that are not otherwise identified.
+	usageUnmatched    = "unmatched"
+	usageOtherClients = "other"
+	usageAnonymous    = "anonymous"
+)
+
+// UsageTrackerConfig is the configuration for a UsageTracker.
+type UsageTrackerConfig struct {
+	// Window is the duration of the rolling window over which requests are
+	// counted. It is rounded up to a whole number of minutes.
+	Window time.Duration
+	// Keys are the API keys that identify clients sending them in the
+	// APIKeyHeader header. Other clients are identified by IP address.
+	Keys []string
+	// MaxClients limits the number of distinct clients counted in each minute
+	// of the window. Requests from further clients are counted together.
+	MaxClients int
+	// HideIPs counts the clients without an API key together as anonymous
+	// rather than by IP address, as for privacy mode.
+	HideIPs bool
+}
+
+// UsageCounts are the request counters of a route or a client. The latencies
+// are the time to write the response, in milliseconds.
+type UsageCounts struct {
+	Requests      uint64  `json:"requests"`
+	Errors        uint64  `json:"errors"`
+	Limited       uint64  `json:"limited"`
+	Bytes         uint64  `json:"bytes"`
+	MeanLatencyMS float64 `json:"mean_latency_ms"`
+	MaxLatencyMS  float64 `json:"max_latency_ms"`
+}
+
+// RouteUsage is the usage of an API route, identified by its route pattern.
+type RouteUsage struct {
+	Route string `json:"route"`
+	UsageCounts
+}
+
+// ClientUsage is the usage of a client, identified by API key or IP address.
+// Clients without an API key are anonymous if IP addresses are hidden.
+type ClientUsage struct {
+	Client string `json:"client"`
+	UsageCounts
+}
+
+// UsageStats are the request counters in the usage window, by route and by
+// client, each in descending order of requests.
+type UsageStats struct {
+	Since    int64          `json:"since"`
+	Window   int64          `json:"window_seconds"`
+	Requests uint64         `json:"requests"`
+	Routes   []*RouteUsage  `json:"routes"`
+	Clients  []*ClientUsage `json:"clients"`
+}
+
+type usageCounter struct {
+	requests uint64
+	errors   uint64
+	limited  uint64
+	bytes    uint64
+	latency  time.Duration
+	max      time.Duration
+}
+
+func (c *usageCounter) add(o *usageCounter) {
+	c.requests += o.requests
+	c.errors += o.errors
+	c.limited += o.limited
+	c.bytes += o.bytes
+	c.latency += o.latency
+	if o.max > c.max {
+		c.max = o.max
+	}
+}
+
+func (c *usageCounter) counts() UsageCounts {
+	uc := UsageCounts{
+		Requests:     c.requests,
+		Errors:       c.errors,
+		Limited:      c.limited,
+		Bytes:        c.bytes,
+		MaxLatencyMS: c.max.Seconds() * 1000,
+	}
+	if c.requests > 0 {
+		uc.MeanLatencyMS = c.latency.Seconds() * 1000 / float64(c.requests)
+	}
+	return uc
+}
+
+// usageSlot holds the counters of the requests in one usageSlotDuration.
+type usageSlot struct {
+	start   time.Time
+	routes  map[string]*usageCounter
+	clients map[string]*usageCounter
+}
+
+// UsageTracker counts the requests, errors, response sizes and latencies of
+// the API by route and by client over a rolling window. A nil *UsageTracker
+// does not track requests.
+type UsageTracker struct {
+	mtx        sync.Mutex
+	keys       map[string]struct{}
+	hideIPs    bool
+	numSlots   int
+	maxClients int
+	slots      []*usageSlot
+	now        func() time.Time
+}
+
+// NewUsageTracker creates a UsageTracker from the given configuration.
+func NewUsageTracker(cfg *UsageTrackerConfig) *UsageTracker {
+	window := cfg.Window
+	if window <= 0 {
+		window = defaultUsageWindow
+	}
+	ut := &UsageTracker{
+		keys:       make(map[string]struct{}, len(cfg.Keys)),
+		hideIPs:    cfg.HideIPs,
+		numSlots:   int((window + usageSlotDuration - 1) / usageSlotDuration),
+		maxClients: cfg.MaxClients,
+		now:        time.Now,
+	}
+	if ut.maxClients <= 0 {
+		ut.maxClients = defaultMaxUsageClients
+	}
+	for _, k := range cfg.Keys {
+		if k != "" {
+			ut.keys[k] = struct{}{}
+		}
+	}
+	return ut
+}
+
+// currentSlot returns the slot for the current time, dropping the slots that
+// have left the window. The mutex must be held.
+func (ut *UsageTracker) currentSlot() *usageSlot {
+	start := ut.now().Truncate(usageSlotDuration)
+	if n := len(ut.slots); n > 0 && ut.slots[n-1].start.Equal(start) {
+		return ut.slots[n-1]
+	}
+	oldest := start.Add(-time.Duration(ut.numSlots-1) * usageSlotDuration)
+	i := 0
+	for i < len(ut.slots) && ut.slots[i].start.Before(oldest) {
+		i++
+	}
+	slot := &usageSlot{
+		start:   start,
+		routes:  make(map[string]*usageCounter),
+		clients: make(map[string]*usageCounter),
+	}
+	ut.slots = append(ut.slots[i:], slot)
+	return slot
+}
+
+// record counts a request to the route by the client.
+func (ut *UsageTracker) record(route, client string, status, bytes int, latency time.Duration) {
+	c := &usageCounter{
+		requests: 1,
+		bytes:    uint64(bytes),
+		latency:  latency,
+		max:      latency,
+	}
+	if status >= 400 {
+		c.errors = 1
+	}
+	if status == http.StatusTooManyRequests {
+		c.limited = 1
+	}
+
+	ut.mtx.Lock()
+	defer ut.mtx.Unlock()
+	slot := ut.currentSlot()
+	add := func(m map[string]*usageCounter, name string) {
+		if sum, found := m[name]; found {
+			sum.add(c)
+			return
+		}
+		sum := *c
+		m[name] = &sum
+	}
+	add(slot.routes, route)
+	if _, found := slot.clients[client]; !found && len(slot.clients) >= ut.maxClients {
+		client = usageOtherClients
+	}
+	add(slot.clients, client)
+}
+
+// Track is a middleware that counts each request by route and client. The
+// route is the chi route pattern, so Track should be used by the top level
+// router in order to see the complete pattern once the request is routed. It
+// should also run before the rate limiter in order to count the limited
+// requests.
+func (ut *UsageTracker) Track(next http.Handler) http.Handler {
+	if ut == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		ww := chimw.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(ww, r)
+		latency := time.Since(start)
+
+		route := usageUnmatched
+		if rctx := chi.RouteContext(r.Context()); rctx != nil {
+			if pattern := rctx.RoutePattern(); pattern != "" {
+				route = pattern
+			}
+		}
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+		client, keyed := requestClient(r, ut.keys)
+		if !keyed && ut.hideIPs {
+			client = usageAnonymous
+		}
+		ut.record(route, client, status, ww.BytesWritten(), latency)
+	})
+}
+
+// Stats returns the request counters in the usage window by route, and for
+// the maxClients clients with the most requests. Stats returns nil for a nil
+// UsageTracker.
+func (ut *UsageTracker) Stats(maxClients int) *UsageStats {
+	if ut == nil {
+		return nil
+	}
+	ut.mtx.Lock()
+	ut.currentSlot()
+	routes := make(map[string]*usageCounter)
+	clients := make(map[string]*usageCounter)
+	sum := func(dst, src map[string]*usageCounter) {
+		for name, c := range src {
+			if d, found := dst[name]; found {
+				d.add(c)
+				continue
+			}
+			d := *c
+			dst[name] = &d
+		}
+	}
+	for _, slot := range ut.slots {
+		sum(routes, slot.routes)
+		sum(clients, slot.clients)
+	}
+	stats := &UsageStats{
+		Since:  ut.slots[0].start.Unix(),
+		Window: int64(time.Duration(ut.numSlots) * usageSlotDuration / time.Second),
+	}
+	ut.mtx.Unlock()
+
+	stats.Routes = make([]*RouteUsage, 0, len(routes))
+	for route, c := range routes {
+		stats.Requests += c.requests
+		stats.Routes = append(stats.Routes, &RouteUsage{route, c.counts()})
+	}
+	sort.Slice(stats.Routes, func(i, j int) bool {
+		ri, rj := stats.Routes[i], stats.Routes[j]
+		if ri.Requests == rj.Requests {
+			return ri.Route < rj.Route
+		}
+		return ri.Requests > rj.Requests
+	})
+
+	stats.Clients = make([]*ClientUsage, 0, len(clients))
+	for client, c := range clients {
+		stats.Clients = append(stats.Clients, &ClientUsage{client, c.counts()})
+	}
+	sort.Slice(stats.Clients, func(i, j int) bool {
+		ci, cj := stats.Clients[i], stats.Clients[j]
+		if ci.Requests == cj.Requests {
+			return ci.Client < cj.Client
+		}
+		return ci.Requests > cj.Requests
+	})
+	if maxClients > 0 && len(stats.Clients) > maxClients {
+		stats.Clients = stats.Clients[:maxClients]
+	}
+
+	return stats
+}
@@ -0,0 +1,124 @@
+// Copyright (c) 2020, The Decred developers
+// See LICENSE for details.
+
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi"
+)
+
+func TestUsageTracker(t *testing.T) {
+	ut := NewUsageTracker(&UsageTrackerConfig{
+		Window:     2 * time.Minute,
+		Keys:       []string{"sekrit"},
+		MaxClients: 2,
+	})
+	now := time.Unix(1576000000, 0)
+	ut.now = func() time.Time { return now }
+
+	mux := chi.NewRouter()
+	mux.Use(ut.Track)
+	mux.Get("/block/{idx}", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("block"))
+	})
+	mux.Get("/limited", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "slow down", http.StatusTooManyRequests)
+	})
+
+	do := func(path, ip, key string) {
+		r := httptest.NewRequest("GET", path, nil)
+		r.RemoteAddr = ip + ":1234"
+		if key != "" {
+			r.Header.Set(APIKeyHeader, key)
+		}
+		mux.ServeHTTP(httptest.NewRecorder(), r)
+	}
+
+	do("/block/1", "10.0.0.1", "")
+	do("/block/2", "10.0.0.1", "")
+	do("/block/3", "10.0.0.2", "sekrit")
+	do("/limited", "10.0.0.3", "")
+	do("/nothing", "10.0.0.1", "")
+
+	stats := ut.Stats(0)
+	if stats.Requests != 5 || stats.Window != 120 || stats.Since != now.Truncate(time.Minute).Unix() {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+	if len(stats.Routes) != 3 {
+		t.Fatalf("got %d routes, wanted 3", len(stats.Routes))
+	}
+	block := stats.Routes[0]
+	if block.Route != "/block/{idx}" || block.Requests != 3 || block.Bytes != 15 ||
+		block.Errors != 0 {
+		t.Errorf("unexpected block route usage %+v", block)
+	}
+	limited := stats.Routes[1]
+	if limited.Route != "/limited" || limited.Errors != 1 || limited.Limited != 1 {
+		t.Errorf("unexpected limited route usage %+v", limited)
+	}
+	if stats.Routes[2].Route != usageUnmatched {
+		t.Errorf("got route %q for an unmatched path", stats.Routes[2].Route)
+	}
+
+	// The third client in the minute is counted with the other clients.
+	wantClients := []struct {
+		client   string
+		requests uint64
+	}{{"ip:10.0.0.1", 3}, {"key:sekrit", 1}, {usageOtherClients, 1}}
+	if len(stats.Clients) != len(wantClients) {
+		t.Fatalf("got %d clients, wanted %d", len(stats.Clients), len(wantClients))
+	}
+	for i, want := range wantClients {
+		if c := stats.Clients[i]; c.Client != want.client || c.Requests != want.requests {
+			t.Errorf("got client %q with %d requests, wanted %q with %d", c.Client,
+				c.Requests, want.client, want.requests)
+		}
+	}
+	if top := ut.Stats(1); len(top.Clients) != 1 {
+		t.Errorf("got %d clients, wanted the top 1", len(top.Clients))
+	}
+
+	// Requests leave the window after it has passed.
+	now = now.Add(time.Minute)
+	do("/block/4", "10.0.0.1", "")
+	if stats = ut.Stats(0); stats.Requests != 6 {
+		t.Errorf("got %d requests in the window, wanted 6", stats.Requests)
+	}
+	now = now.Add(time.Minute)
+	if stats = ut.Stats(0); stats.Requests != 1 || stats.Routes[0].Requests != 1 {
+		t.Errorf("got %d requests in the window, wanted 1", stats.Requests)
+	}
+
+	var nilTracker *UsageTracker
+	if nilTracker.Stats(0) != nil {
+		t.Errorf("expected nil stats for a nil tracker")
+	}
+}
+
+func TestUsageTrackerHideIPs(t *testing.T) {
+	ut := NewUsageTracker(&UsageTrackerConfig{
+		Keys:    []string{"sekrit"},
+		HideIPs: true,
+	})
+	handler := ut.Track(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	for _, ip := range []string{"10.0.0.1", "10.0.0.2"} {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.RemoteAddr = ip + ":1234"
+		handler.ServeHTTP(httptest.NewRecorder(), r)
+	}
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set(APIKeyHeader, "sekrit")
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+
+	// Only the API key client is identified.
+	stats := ut.Stats(0)
+	if len(stats.Clients) != 2 || stats.Clients[0].Client != usageAnonymous ||
+		stats.Clients[0].Requests != 2 || stats.Clients[1].Client != "key:sekrit" {
+		t.Errorf("unexpected clients %+v", stats.Clients)
+	}
+}
@@ -255,17 +255,8 @@ export default class extends Controller {
     if (this.qrCode) {
       await fadeIn(this.qrimgTarget)
     } else {
-      let QRCode = await getDefault(
-        import(/* webpackChunkName: "qrcode" */ 'qrcode')
-      )
-      let qrCodeImg = await QRCode.toDataURL(this.dcrAddress,
-        {
-          errorCorrectionLevel: 'H',
-          scale: 6,
-          margin: 0
-        }
-      )
-      this.qrimgTarget.innerHTML = `<img src="${qrCodeImg}"/>`
+      this.qrimgTarget.innerHTML = `<img src="/api/address/${this.dcrAddress}/qr?format=svg&size=200"/>`
+      this.qrCode = true
       await fadeIn(this.qrimgTarget)
       if (this.graph) this.graph.resize()
     }
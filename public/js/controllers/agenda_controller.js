@@ -59,11 +59,19 @@ function voteChoicesByBlockData (d) {
   })
 }
 
+function voteChoicesByTicketTypeData (d) {
+  if (d == null || !(d.ticket_types instanceof Array)) return [[0, 0, 0, 0]]
+  return d.ticket_types.map((t, i) => {
+    return [i, t.yes, t.abstain, t.no]
+  })
+}
+
 export default class extends Controller {
   static get targets () {
     return [
       'cumulativeVoteChoices',
-      'voteChoicesByBlock'
+      'voteChoicesByBlock',
+      'voteChoicesByTicketType'
     ]
   }
 
@@ -71,6 +79,8 @@ export default class extends Controller {
     this.emptydata = [[0, 0, 0, 0]]
     this.cumulativeVoteChoicesChart = false
     this.voteChoicesByBlockChart = false
+    this.voteChoicesByTicketTypeChart = false
+    this.ticketTypes = []
   }
 
   async connect () {
@@ -87,6 +97,11 @@ export default class extends Controller {
     this.voteChoicesByBlockChart.updateOptions({
       file: voteChoicesByBlockData(agendaResponse.data.by_height)
     })
+    let ticketTypesResponse = await axios.get('/api/agendas/' + this.agendaId + '/ticket-types')
+    this.ticketTypes = ticketTypesResponse.data.ticket_types.map((t) => t.ticket_type)
+    this.voteChoicesByTicketTypeChart.updateOptions({
+      file: voteChoicesByTicketTypeData(ticketTypesResponse.data)
+    })
 
     this.element.classList.remove('loading')
   }
@@ -94,6 +109,7 @@ export default class extends Controller {
   disconnect () {
     this.cumulativeVoteChoicesChart.destroy()
     this.voteChoicesByBlockChart.destroy()
+    this.voteChoicesByTicketTypeChart.destroy()
   }
 
   drawCharts () {
@@ -115,6 +131,25 @@ export default class extends Controller {
         plotter: barChartPlotter
       }
     )
+    var ticketTypeLabel = (x) => this.ticketTypes[x] || ''
+    this.voteChoicesByTicketTypeChart = this.drawChart(
+      this.voteChoicesByTicketTypeTarget,
+      {
+        labels: ['Ticket Type', 'Yes', 'Abstain', 'No'],
+        ylabel: 'Vote Choices Cast',
+        title: 'Vote Choices By Ticket Type',
+        plotter: barChartPlotter,
+        showRangeSelector: false,
+        dateWindow: [-0.5, 2.5],
+        axes: {
+          x: {
+            valueFormatter: ticketTypeLabel,
+            axisLabelFormatter: ticketTypeLabel,
+            ticker: () => this.ticketTypes.map((t, i) => ({ v: i, label: t }))
+          }
+        }
+      }
+    )
   }
 
   drawChart (el, options, Dygraph) {
@@ -17,7 +17,7 @@ let Dygraph // lazy loaded on connect
 const aDay = 86400 * 1000 // in milliseconds
 const aMonth = 30 // in days
 const atomsToDCR = 1e-8
-const windowScales = ['ticket-price', 'pow-difficulty', 'missed-votes']
+const windowScales = ['ticket-price', 'pow-difficulty', 'missed-votes', 'vote-participation', 'ticket-fees', 'ticket-demand']
 const hybridScales = ['privacy-participation']
 const lineScales = ['ticket-price', 'privacy-participation']
 const modeScales = ['ticket-price']
@@ -198,6 +198,27 @@ function zip2D (data, ys, yMult, offset) {
   return zipTvY(data.t, ys, yMult)
 }
 
+function txTypesFunc (data, yMult) {
+  yMult = yMult || 1
+  const series = [data.regular, data.tickets, data.votes, data.revokes]
+  return zip2D(data, data.regular).map((pt, i) => {
+    return [pt[0], ...series.map(ys => ys[i] * yMult)]
+  })
+}
+
+function ticketShareFunc (data) {
+  const series = [data.solo, data.pooled, data.other]
+  return zip2D(data, data.solo).map((pt, i) => {
+    return [pt[0], ...series.map(ys => ys[i])]
+  })
+}
+
+function blockFillFunc (data) {
+  return zip2D(data, data.fill, 100).map((pt, i) => {
+    return [pt[0], pt[1], data.backlog[i]]
+  })
+}
+
 function anonymitySetFunc (data) {
   let d
   let start = -1
@@ -326,6 +347,24 @@ function missedVotesFunc (data) {
   return zipWindowHvY(data.missed, data.window, 1, data.offset * data.window)
 }
 
+function voteParticipationFunc (data) {
+  if (data.t) return zipWindowTvY(data.t, data.participation, 100)
+  return zipWindowHvY(data.participation, data.window, 100, data.offset * data.window)
+}
+
+function ticketFeesFunc (data) {
+  const series = [data.min, data.median, data.max, data.closing]
+  const xs = data.t ? data.t.map(t => new Date(t * 1000)) : data.median.map((_, i) => i * data.window)
+  return xs.map((x, i) => {
+    return [x, ...series.map(ys => ys[i] * atomsToDCR)]
+  })
+}
+
+function ticketDemandFunc (data) {
+  if (data.t) return zipWindowTvYZ(data.t, data.count, data.elasticity)
+  return zipWindowHvYZ(data.count, data.elasticity, data.window)
+}
+
 function mapDygraphOptions (data, labelsVal, isDrawPoint, yLabel, labelsMG, labelsMG2) {
   return merge({
     'file': data,
@@ -482,7 +521,9 @@ export default class extends Controller {
       stepPlot: this.settings.mode === 'stepped',
       axes: {},
       series: null,
-      inflation: null
+      inflation: null,
+      stackedGraph: false,
+      fillGraph: false
     }
     rawPoolValue = []
     rawCoinSupply = []
@@ -538,11 +579,63 @@ export default class extends Controller {
         yFormatter = customYFormatter(y => intComma(y) + ' DCR')
         break
 
+      case 'ticket-pool-price': // value-weighted average live ticket price graph
+        d = zip2D(data, data.price, atomsToDCR)
+        assign(gOptions, mapDygraphOptions(d, [xlabel, 'Ticket Pool Price'], true,
+          'Value-Weighted Live Ticket Price (DCR)', true, false))
+        yFormatter = customYFormatter(y => y.toFixed(8) + ' DCR')
+        break
+
+      case 'tx-type-count': // stacked transaction count by type graph
+        d = txTypesFunc(data)
+        assign(gOptions, mapDygraphOptions(d, [xlabel, 'Regular', 'Tickets', 'Votes', 'Revocations'],
+          false, 'Number of Transactions', true, false))
+        gOptions.stackedGraph = true
+        gOptions.fillGraph = true
+        break
+
+      case 'tx-type-volume': // stacked transaction volume by type graph
+        d = txTypesFunc(data, atomsToDCR)
+        assign(gOptions, mapDygraphOptions(d, [xlabel, 'Regular', 'Tickets', 'Votes', 'Revocations'],
+          false, 'Volume (DCR)', true, false))
+        gOptions.stackedGraph = true
+        gOptions.fillGraph = true
+        yFormatter = customYFormatter(y => intComma(y) + ' DCR')
+        break
+
+      case 'ticket-share': // stacked live tickets by purchase type graph
+        d = ticketShareFunc(data)
+        assign(gOptions, mapDygraphOptions(d, [xlabel, 'Solo', 'Pooled (VSP)', 'Other'],
+          false, 'Live Tickets', true, false))
+        gOptions.stackedGraph = true
+        gOptions.fillGraph = true
+        yFormatter = (div, data, i) => {
+          const total = data.series.reduce((sum, s) => sum + s.y, 0)
+          data.series.forEach(s => {
+            const pct = total > 0 ? (s.y / total * 100).toFixed(2) : 0
+            addLegendEntryFmt(div, s, y => `${intComma(y)} tickets (${pct}%)`)
+          })
+        }
+        break
+
       case 'block-size': // block size graph
         d = zip2D(data, data.size)
         assign(gOptions, mapDygraphOptions(d, [xlabel, 'Block Size'], false, 'Block Size', true, false))
         break
 
+      case 'block-fill': // block fill rate and mempool backlog graph
+        d = blockFillFunc(data)
+        assign(gOptions, mapDygraphOptions(d, [xlabel, 'Block Fill', 'Mempool Backlog'], false,
+          'Block Fill (%)', true, false))
+        gOptions.y2label = 'Mempool Backlog'
+        gOptions.series = { 'Mempool Backlog': { axis: 'y2' } }
+        gOptions.axes.y2 = { axisLabelFormatter: (y) => humanize.bytes(y) }
+        yFormatter = (div, data, i) => {
+          addLegendEntryFmt(div, data.series[0], y => y.toFixed(2) + '%')
+          addLegendEntryFmt(div, data.series[1], y => humanize.bytes(y))
+        }
+        break
+
       case 'blockchain-size': // blockchain size graph
         d = zip2D(data, data.size)
         assign(gOptions, mapDygraphOptions(d, [xlabel, 'Blockchain Size'], true,
@@ -639,6 +732,34 @@ export default class extends Controller {
         assign(gOptions, mapDygraphOptions(d, [xlabel, 'Missed Votes'], false,
           'Missed Votes per Window', true, false))
         break
+
+      case 'vote-participation':
+        d = voteParticipationFunc(data)
+        assign(gOptions, mapDygraphOptions(d, [xlabel, 'Vote Participation'], false,
+          'Votes Cast per Window (% of expected)', true, false))
+        yFormatter = customYFormatter(y => y.toFixed(2) + '%')
+        break
+
+      case 'ticket-fees':
+        d = ticketFeesFunc(data)
+        assign(gOptions, mapDygraphOptions(d, [xlabel, 'Min', 'Median', 'Max', 'Closing Min'], false,
+          'Ticket Fee Rate (DCR/kB)', true, false))
+        yFormatter = (div, data) => {
+          data.series.forEach(series => addLegendEntryFmt(div, series, y => y.toFixed(8) + ' DCR/kB'))
+        }
+        break
+
+      case 'ticket-demand':
+        d = ticketDemandFunc(data)
+        assign(gOptions, mapDygraphOptions(d, [xlabel, 'Tickets Bought', 'Price Elasticity'], false,
+          'Tickets Bought per Window', true, false))
+        gOptions.y2label = 'Price Elasticity'
+        gOptions.series = { 'Price Elasticity': { axis: 'y2' } }
+        yFormatter = (div, data) => {
+          addLegendEntryFmt(div, data.series[0], y => intComma(y) + ' tickets')
+          addLegendEntryFmt(div, data.series[1], y => y.toFixed(2))
+        }
+        break
     }
 
     const baseURL = `${this.query.url.protocol}//${this.query.url.host}`
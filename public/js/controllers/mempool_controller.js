@@ -88,7 +88,17 @@ export default class extends Controller {
       'revTotal',
       'revCount',
       'likelyTotal',
-      'mempoolSize'
+      'mempoolSize',
+      'nextHeight',
+      'nextVotes',
+      'nextNotEnough',
+      'nextTickets',
+      'nextRevs',
+      'nextTxs',
+      'nextSize',
+      'nextFees',
+      'nextTotal',
+      'nextExcluded'
     ]
   }
 
@@ -96,6 +106,7 @@ export default class extends Controller {
     // from txhelpers.DetermineTxTypeString
     var mempoolData = this.mempoolTarget.dataset
     ws.send('getmempooltxs', mempoolData.id)
+    ws.send('getnextblock', '')
     this.mempool = new Mempool(mempoolData, this.voteTallyTargets)
     this.txTargetMap = {
       'Vote': this.voteTransactionsTarget,
@@ -117,6 +128,7 @@ export default class extends Controller {
       this.labelVotes()
       this.sortVotesTable()
       keyNav(evt, false, true)
+      ws.send('getnextblock', '')
     })
     ws.registerEvtHandler('mempool', (evt) => {
       var m = JSON.parse(evt)
@@ -124,6 +136,7 @@ export default class extends Controller {
       this.setMempoolFigures()
       this.updateBlock(m)
       ws.send('getmempooltxs', '')
+      ws.send('getnextblock', '')
     })
     ws.registerEvtHandler('getmempooltxsResp', (evt) => {
       var m = JSON.parse(evt)
@@ -134,12 +147,16 @@ export default class extends Controller {
       this.sortVotesTable()
       keyNav(evt, false, true)
     })
+    ws.registerEvtHandler('getnextblockResp', (evt) => {
+      this.setNextBlock(JSON.parse(evt))
+    })
   }
 
   disconnect () {
     ws.deregisterEvtHandlers('newtxs')
     ws.deregisterEvtHandlers('mempool')
     ws.deregisterEvtHandlers('getmempooltxsResp')
+    ws.deregisterEvtHandlers('getnextblockResp')
   }
 
   updateBlock (m) {
@@ -174,6 +191,20 @@ export default class extends Controller {
     // this.setVotes()
   }
 
+  setNextBlock (b) {
+    if (!this.hasNextHeightTarget) return
+    this.nextHeightTarget.textContent = b.height
+    this.nextVotesTarget.textContent = (b.votes || []).length
+    this.nextNotEnoughTarget.classList.toggle('d-hide', b.enough_votes)
+    this.nextTicketsTarget.textContent = (b.tickets || []).length
+    this.nextRevsTarget.textContent = (b.revs || []).length
+    this.nextTxsTarget.textContent = (b.tx || []).length
+    this.nextSizeTarget.textContent = b.size.toLocaleString()
+    this.nextFeesTarget.textContent = humanize.threeSigFigs(b.fees)
+    this.nextTotalTarget.textContent = humanize.threeSigFigs(b.total)
+    this.nextExcludedTarget.textContent = b.num_excluded
+  }
+
   handleTxsResp (m) {
     buildTable(this.regularTransactionsTarget, 'regular transactions', m.tx, txTableRow)
     buildTable(this.revocationTransactionsTarget, 'revocations', m.revs, txTableRow)
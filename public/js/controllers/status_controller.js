@@ -67,12 +67,13 @@ let hasRedirected = false
 
 export default class extends Controller {
   static get targets () {
-    return ['statusSyncing', 'futureBlock', 'init', 'address', 'message']
+    return ['statusSyncing', 'futureBlock', 'init', 'stake', 'address', 'message']
   }
 
   connect () {
     this.progressBars = {
       'initial-load': this.initTarget,
+      'stakedb-sync': this.stakeTarget,
       'addresses-sync': this.addressTarget
     }
     ws.registerEvtHandler('blockchainSync', (evt) => {
@@ -83,6 +84,7 @@ export default class extends Controller {
         var v = d[i]
 
         var bar = this.progressBars[v.progress_bar_id]
+        if (!bar) continue
         while (bar.firstChild) bar.removeChild(bar.firstChild)
         bar.innerHTML = buildProgressBar(v)
 
@@ -76,6 +76,10 @@ function priceGraphData (items, memP) {
   return p
 }
 
+function ageGraphData (items) {
+  return items.age.map((n, i) => [n, items.live[i]])
+}
+
 function populateOutputs (data) {
   var totalCount = parseInt(data.count.reduce((a, n) => { return a + n }, 0))
   var tableData = `<tr><th style="width: 30%;"># of sstxcommitment outputs</th><th>Count</th><th>% Occurrence</th></tr>`
@@ -122,9 +126,11 @@ export default class extends Controller {
     this.tipHeight = 0
     this.purchasesGraph = null
     this.priceGraph = null
+    this.ageGraph = null
     this.graphData = {
       'time_chart': null,
-      'price_chart': null
+      'price_chart': null,
+      'age_chart': null
     }
     this.zoom = 'all'
     this.bars = 'all'
@@ -132,9 +138,10 @@ export default class extends Controller {
     Dygraph = await getDefault(
       import(/* webpackChunkName: "dygraphs" */ '../vendor/dygraphs.min.js')
     )
-    this.chartCount += 2
+    this.chartCount += 3
     this.purchasesGraph = this.makePurchasesGraph()
     this.priceGraph = this.makePriceGraph()
+    this.ageGraph = this.makeAgeGraph()
   }
 
   connect () {
@@ -181,6 +188,12 @@ export default class extends Controller {
         this.priceGraph.updateOptions({ 'file': this.graphData['price_chart'] })
       }
     }
+    if (data['age_chart']) {
+      this.graphData['age_chart'] = ageGraphData(data['age_chart'])
+      if (this.ageGraph !== null) {
+        this.ageGraph.updateOptions({ 'file': this.graphData['age_chart'] })
+      }
+    }
     if (data['outputs_chart']) {
       while (this.outputsTarget.firstChild) this.outputsTarget.removeChild(this.outputsTarget.firstChild)
       this.outputsTarget.appendChild(populateOutputs(data['outputs_chart']))
@@ -190,6 +203,7 @@ export default class extends Controller {
   disconnect () {
     this.purchasesGraph.destroy()
     this.priceGraph.destroy()
+    this.ageGraph.destroy()
 
     ws.deregisterEvtHandlers('ticketpool')
     ws.deregisterEvtHandlers('getticketpooldataResp')
@@ -259,4 +273,22 @@ export default class extends Controller {
       d, { ...commonOptions, ...p }
     )
   }
+
+  makeAgeGraph () {
+    var d = this.graphData['age_chart'] || [[0, 0]]
+    var p = {
+      labels: ['Age', 'Live Tickets'],
+      colors: ['#2971FF'],
+      title: 'Live Ticket Age Distribution',
+      labelsKMB: true,
+      digitsAfterDecimal: 0,
+      showRangeSelector: false,
+      xlabel: 'Blocks Since Purchase',
+      ylabel: 'Number of Tickets'
+    }
+    return new Dygraph(
+      document.getElementById('tickets_by_age'),
+      d, { ...commonOptions, ...p }
+    )
+  }
 }
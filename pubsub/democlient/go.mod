@@ -3,6 +3,7 @@ module github.com/decred/dcrdata/pubsub/democlient
 go 1.12
 
 replace (
+	github.com/decred/dcrdata/pubsub/types/v3 => ../types
 	github.com/decred/dcrdata/pubsub/v4 => ../
 	github.com/decred/dcrdata/explorer/types/v2 => ../../explorer/types
 )
@@ -0,0 +1,84 @@
+// Copyright (c) 2020, The Decred developers
+// See LICENSE for details.
+
+package pubsub
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/net/websocket"
+)
+
+const (
+	// wsHeartbeatInterval is how often a ping control frame is sent to each
+	// client. A client that has not responded with a pong, or sent any other
+	// data, within wsPongTimeout of a ping is disconnected.
+	wsHeartbeatInterval = 30 * time.Second
+	wsPongTimeout       = 15 * time.Second
+)
+
+// activityReader records the time of the last read of any data from a client.
+// Unlike JSON.Receive, which only returns for data frames, it also sees the
+// pong frames that websocket.Conn consumes internally.
+type activityReader struct {
+	r        io.Reader
+	lastRead int64 // unix nanoseconds, accessed atomically
+}
+
+func (ar *activityReader) Read(p []byte) (int, error) {
+	n, err := ar.r.Read(p)
+	if n > 0 {
+		ar.touch()
+	}
+	return n, err
+}
+
+func (ar *activityReader) touch() {
+	atomic.StoreInt64(&ar.lastRead, time.Now().UnixNano())
+}
+
+// idle returns the time since data was last read from the client.
+func (ar *activityReader) idle() time.Duration {
+	return time.Since(time.Unix(0, atomic.LoadInt64(&ar.lastRead)))
+}
+
+// activityHijacker is an http.ResponseWriter for websocket.Server that reads
+// the hijacked connection through an activityReader.
+type activityHijacker struct {
+	http.ResponseWriter
+	activity *activityReader
+}
+
+// Hijack hijacks the connection of the wrapped http.ResponseWriter, replacing
+// the buffered reader with one that reads through the activityReader. Data
+// already buffered by the http.Server is read first.
+func (h *activityHijacker) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hj, ok := h.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("http.ResponseWriter does not implement http.Hijacker")
+	}
+	rwc, buf, err := hj.Hijack()
+	if err != nil {
+		return nil, nil, err
+	}
+	h.activity.r = buf.Reader
+	h.activity.touch()
+	return rwc, bufio.NewReadWriter(bufio.NewReader(h.activity), buf.Writer), nil
+}
+
+// ping sends a ping control frame to the client as a heartbeat. The
+// websocket.Conn's PayloadType must be websocket.PingFrame. PayloadType is only
+// used by Write, while JSON.Send sets the frame type of each message.
+func ping(ws *websocket.Conn) error {
+	if err := ws.SetWriteDeadline(time.Now().Add(wsWriteTimeout)); err != nil {
+		return err
+	}
+	_, err := ws.Write(nil)
+	return err
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2020, The Decred developers
+// See LICENSE for details.
+
+package pubsub
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/net/websocket"
+)
+
+func TestHeartbeat(t *testing.T) {
+	activity := new(activityReader)
+	pinged := make(chan int64, 1)
+	done := make(chan struct{})
+	wsServer := websocket.Server{
+		Handler: func(ws *websocket.Conn) {
+			ws.PayloadType = websocket.PingFrame
+			// Like receiveLoop, keep reading from the client.
+			go func() {
+				var msg string
+				_ = websocket.Message.Receive(ws, &msg)
+			}()
+			sent := atomic.LoadInt64(&activity.lastRead)
+			if err := ping(ws); err != nil {
+				t.Errorf("ping failed: %v", err)
+			}
+			pinged <- sent
+			<-done
+		},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		wsServer.ServeHTTP(&activityHijacker{w, activity}, r)
+	}))
+	defer server.Close()
+	defer close(done)
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+	ws, err := websocket.Dial(url, "", server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+	// The client responds to pings while waiting for a message.
+	go func() {
+		var msg string
+		_ = websocket.Message.Receive(ws, &msg)
+	}()
+
+	sent := <-pinged
+	deadline := time.Now().Add(5 * time.Second)
+	for atomic.LoadInt64(&activity.lastRead) == sent {
+		if time.Now().After(deadline) {
+			t.Fatal("pong not read")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if idle := activity.idle(); idle > time.Second {
+		t.Errorf("client idle for %v after pong", idle)
+	}
+}
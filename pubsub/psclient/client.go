@@ -295,6 +295,15 @@ func (c *Client) receiver() {
 		case *pstypes.AddressMessage:
 			log.Debugf("Message (%s): AddressMessage(address=%s, txHash=%s)",
 				resp.EventId, m.Address, m.TxHash)
+		case *exptypes.BlockInvalidation:
+			log.Debugf("Message (%s): BlockInvalidation(hash=%s, numTx=%d)",
+				resp.EventId, m.Hash, len(m.Transactions))
+		case *exptypes.AgendaStatusChange:
+			log.Debugf("Message (%s): AgendaStatusChange(agenda=%s, event=%s, height=%d)",
+				resp.EventId, m.AgendaID, m.Event, m.Height)
+		case *exptypes.BlockValidity:
+			log.Debugf("Message (%s): BlockValidity(hash=%s, validity=%s)",
+				resp.EventId, m.Hash, m.Validity)
 		default:
 			log.Debugf("Message of type %v unhandled.", resp.EventId)
 			continue
@@ -510,6 +519,18 @@ func DecodeMsg(msg *pstypes.WebSocketMessage) (interface{}, error) {
 		var mpshort exptypes.MempoolShort
 		err := json.Unmarshal(msg.Message, &mpshort)
 		return &mpshort, err
+	case "blockinvalidated":
+		var inv exptypes.BlockInvalidation
+		err := json.Unmarshal(msg.Message, &inv)
+		return &inv, err
+	case "agendastatus":
+		var asc exptypes.AgendaStatusChange
+		err := json.Unmarshal(msg.Message, &asc)
+		return &asc, err
+	case "blockvalidity":
+		var bv exptypes.BlockValidity
+		err := json.Unmarshal(msg.Message, &bv)
+		return &bv, err
 	default:
 		return nil, fmt.Errorf("unrecognized event type")
 	}
@@ -618,3 +639,45 @@ func DecodeMsgNewAddressTx(msg *pstypes.WebSocketMessage) (*pstypes.AddressMessa
 	}
 	return am, nil
 }
+
+// DecodeMsgBlockInvalidated attempts to decode the Message content of the given
+// WebSocketMessage as a blockinvalidated message (*exptypes.BlockInvalidation).
+func DecodeMsgBlockInvalidated(msg *pstypes.WebSocketMessage) (*exptypes.BlockInvalidation, error) {
+	bi, err := DecodeMsg(msg)
+	if err != nil {
+		return nil, err
+	}
+	inv, ok := bi.(*exptypes.BlockInvalidation)
+	if !ok {
+		return nil, fmt.Errorf("content of Message was not of type *exptypes.BlockInvalidation")
+	}
+	return inv, nil
+}
+
+// DecodeMsgAgendaStatus attempts to decode the Message content of the given
+// WebSocketMessage as an agendastatus message (*exptypes.AgendaStatusChange).
+func DecodeMsgAgendaStatus(msg *pstypes.WebSocketMessage) (*exptypes.AgendaStatusChange, error) {
+	as, err := DecodeMsg(msg)
+	if err != nil {
+		return nil, err
+	}
+	asc, ok := as.(*exptypes.AgendaStatusChange)
+	if !ok {
+		return nil, fmt.Errorf("content of Message was not of type *exptypes.AgendaStatusChange")
+	}
+	return asc, nil
+}
+
+// DecodeMsgBlockValidity attempts to decode the Message content of the given
+// WebSocketMessage as a blockvalidity message (*exptypes.BlockValidity).
+func DecodeMsgBlockValidity(msg *pstypes.WebSocketMessage) (*exptypes.BlockValidity, error) {
+	v, err := DecodeMsg(msg)
+	if err != nil {
+		return nil, err
+	}
+	bv, ok := v.(*exptypes.BlockValidity)
+	if !ok {
+		return nil, fmt.Errorf("content of Message was not of type *exptypes.BlockValidity")
+	}
+	return bv, nil
+}
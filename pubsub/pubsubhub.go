@@ -74,8 +74,9 @@ type State struct {
 
 type connection struct {
 	sync.WaitGroup
-	ws     *websocket.Conn
-	client *clientHubSpoke
+	ws       *websocket.Conn
+	client   *clientHubSpoke
+	activity *activityReader
 }
 
 // PubSubHub manages the collection and distribution of block chain and mempool
@@ -402,11 +403,37 @@ func (psh *PubSubHub) sendLoop(conn *connection) {
 	ws := conn.ws
 	defer closeWS(ws)
 
+	heartbeat := time.NewTicker(wsHeartbeatInterval)
+	defer heartbeat.Stop()
+
 loop:
-	for sig := range updateSigChan {
+	for {
+		var sig pstypes.HubMessage
+		select {
+		case <-heartbeat.C:
+			if idle := conn.activity.idle(); idle > wsHeartbeatInterval+wsPongTimeout {
+				log.Debugf("Disconnecting websocket client %d that failed to "+
+					"respond to heartbeat in %v.", clientData.id, idle)
+				return
+			}
+			if err := ping(ws); err != nil {
+				if !pstypes.IsWSClosedErr(err) {
+					log.Debugf("Failed to send websocket heartbeat: %v", err)
+				}
+				return
+			}
+			continue loop
+
+		case s, ok := <-updateSigChan:
+			// If the update channel is closed, the loop terminates.
+			if !ok {
+				return
+			}
+			sig = s
+		}
+
 		log.Tracef("(*PubSubHub)sendLoop: updateSigChan received %v for client %d",
 			sig, clientData.id)
-		// If the update channel is closed, the loop terminates.
 
 		if !sig.IsValid() {
 			log.Errorf("invalid signal to send: %s / %d", sig.Signal, int(sig.Signal))
@@ -487,6 +514,45 @@ loop:
 
 			pushMsg.Message = buff.Bytes()
 
+		case sigBlockInvalidated:
+			inv, ok := sig.Msg.(*exptypes.BlockInvalidation)
+			if !ok {
+				log.Errorf("sigBlockInvalidated did not store a *BlockInvalidation in Msg.")
+				continue loop
+			}
+			err := enc.Encode(inv)
+			if err != nil {
+				log.Warnf("Encode(BlockInvalidation) failed: %v", err)
+			}
+
+			pushMsg.Message = buff.Bytes()
+
+		case sigAgendaStatus:
+			asc, ok := sig.Msg.(*exptypes.AgendaStatusChange)
+			if !ok {
+				log.Errorf("sigAgendaStatus did not store a *AgendaStatusChange in Msg.")
+				continue loop
+			}
+			err := enc.Encode(asc)
+			if err != nil {
+				log.Warnf("Encode(AgendaStatusChange) failed: %v", err)
+			}
+
+			pushMsg.Message = buff.Bytes()
+
+		case sigBlockValidity:
+			bv, ok := sig.Msg.(*exptypes.BlockValidity)
+			if !ok {
+				log.Errorf("sigBlockValidity did not store a *BlockValidity in Msg.")
+				continue loop
+			}
+			err := enc.Encode(bv)
+			if err != nil {
+				log.Warnf("Encode(BlockValidity) failed: %v", err)
+			}
+
+			pushMsg.Message = buff.Bytes()
+
 		case sigPingAndUserCount:
 			// ping and send user count
 			pushMsg.Message = json.RawMessage(strconv.Itoa(psh.wsHub.NumClients())) // No quotes as this is a JSON integer
@@ -540,7 +606,7 @@ loop:
 			log.Errorf("websocket.JSON.Send of %v type message failed: %v", sig, err)
 			return
 		}
-	} // for range { a.k.a. loop:
+	} // for { a.k.a. loop:
 }
 
 // WebSocketHandler is the http.HandlerFunc for new websocket connections. The
@@ -551,9 +617,15 @@ func (psh *PubSubHub) WebSocketHandler(w http.ResponseWriter, r *http.Request) {
 	ch := psh.wsHub.NewClientHubSpoke()
 	defer close(ch.cl.killed)
 
+	// activity records reads from the client, including the pongs to the
+	// heartbeat pings, so the send loop can disconnect unresponsive clients.
+	activity := new(activityReader)
+
 	wsHandler := websocket.Handler(func(ws *websocket.Conn) {
 		// Set the max payload size for this connection.
 		ws.MaxPayloadBytes = psh.wsHub.requestLimit
+		// Write is only used to send the heartbeat pings.
+		ws.PayloadType = websocket.PingFrame
 
 		// The receive loop will be sitting on websocket.JSON.Receive, while the
 		// send loop will be waiting for signals from the WebSocketHub. One must
@@ -565,8 +637,9 @@ func (psh *PubSubHub) WebSocketHandler(w http.ResponseWriter, r *http.Request) {
 		// loop from its waiting to receive data on the connection.
 
 		conn := &connection{
-			client: ch,
-			ws:     ws,
+			client:   ch,
+			ws:       ws,
+			activity: activity,
 		}
 
 		// Start listening for websocket messages from client, returning when
@@ -591,7 +664,7 @@ func (psh *PubSubHub) WebSocketHandler(w http.ResponseWriter, r *http.Request) {
 	wsServer := websocket.Server{
 		Handler: wsHandler,
 	}
-	wsServer.ServeHTTP(w, r)
+	wsServer.ServeHTTP(&activityHijacker{w, activity}, r)
 }
 
 // StoreMPData stores mempool data. It is advisable to pass a copy of the
@@ -606,6 +679,48 @@ func (psh *PubSubHub) StoreMPData(_ *mempool.StakeData, _ []exptypes.MempoolTx,
 	log.Debugf("Updated mempool details for the pubsubhub.")
 }
 
+// BlockInvalidated signals to the WebSocketHub that the regular transactions
+// of a block were disapproved by stakeholders, and thus reversed.
+func (psh *PubSubHub) BlockInvalidated(inv *exptypes.BlockInvalidation) {
+	// Do not block the caller, and do not hang forever in a goroutine waiting
+	// to send.
+	go func() {
+		select {
+		case psh.wsHub.HubRelay <- pstypes.HubMessage{Signal: sigBlockInvalidated, Msg: inv}:
+		case <-time.After(time.Second * 10):
+			log.Errorf("sigBlockInvalidated send failed: Timeout waiting for WebsocketHub.")
+		}
+	}()
+}
+
+// AgendaStatusChanged signals to the WebSocketHub that an agenda reached a
+// voting milestone, such as quorum, lock-in, activation, or failure.
+func (psh *PubSubHub) AgendaStatusChanged(asc *exptypes.AgendaStatusChange) {
+	// Do not block the caller, and do not hang forever in a goroutine waiting
+	// to send.
+	go func() {
+		select {
+		case psh.wsHub.HubRelay <- pstypes.HubMessage{Signal: sigAgendaStatus, Msg: asc}:
+		case <-time.After(time.Second * 10):
+			log.Errorf("sigAgendaStatus send failed: Timeout waiting for WebsocketHub.")
+		}
+	}()
+}
+
+// BlockValidityResolved signals to the WebSocketHub that the votes in a new
+// block approved or disapproved the previous block.
+func (psh *PubSubHub) BlockValidityResolved(bv *exptypes.BlockValidity) {
+	// Do not block the caller, and do not hang forever in a goroutine waiting
+	// to send.
+	go func() {
+		select {
+		case psh.wsHub.HubRelay <- pstypes.HubMessage{Signal: sigBlockValidity, Msg: bv}:
+		case <-time.After(time.Second * 10):
+			log.Errorf("sigBlockValidity send failed: Timeout waiting for WebsocketHub.")
+		}
+	}()
+}
+
 // Store processes and stores new block data, then signals to the WebSocketHub
 // that the new data is available.
 func (psh *PubSubHub) Store(blockData *blockdata.BlockData, msgBlock *wire.MsgBlock) error {
@@ -6,3 +6,5 @@ require (
 	github.com/decred/base58 v1.0.1
 	github.com/decred/dcrdata/explorer/types/v2 v2.1.0
 )
+
+replace github.com/decred/dcrdata/explorer/types/v2 => ../../explorer/types
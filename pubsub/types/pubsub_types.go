@@ -106,15 +106,21 @@ const (
 	SigSyncStatus
 	SigByeNow
 	SigUnknown
+	SigBlockInvalidated
+	SigAgendaStatus
+	SigBlockValidity
 )
 
 var Subscriptions = map[string]HubSignal{
-	"newblock":       SigNewBlock,
-	"mempool":        SigMempoolUpdate,
-	"ping":           SigPingAndUserCount,
-	"newtxs":         SigNewTxs,
-	"address":        SigAddressTx,
-	"blockchainSync": SigSyncStatus,
+	"newblock":         SigNewBlock,
+	"mempool":          SigMempoolUpdate,
+	"ping":             SigPingAndUserCount,
+	"newtxs":           SigNewTxs,
+	"address":          SigAddressTx,
+	"blockchainSync":   SigSyncStatus,
+	"blockinvalidated": SigBlockInvalidated,
+	"agendastatus":     SigAgendaStatus,
+	"blockvalidity":    SigBlockValidity,
 }
 
 // Event type field for an event.
@@ -133,6 +139,9 @@ var eventIDs = map[HubSignal]string{
 	SigAddressTx:        "address",
 	SigSyncStatus:       "blockchainSync",
 	SigByeNow:           "bye",
+	SigBlockInvalidated: "blockinvalidated",
+	SigAgendaStatus:     "agendastatus",
+	SigBlockValidity:    "blockvalidity",
 	SigUnknown:          "unknown",
 }
 
@@ -202,6 +211,12 @@ func (m HubMessage) IsValid() bool {
 		_, ok = m.Msg.(*exptypes.MempoolTx)
 	case SigNewTxs:
 		_, ok = m.Msg.([]*exptypes.MempoolTx)
+	case SigBlockInvalidated:
+		_, ok = m.Msg.(*exptypes.BlockInvalidation)
+	case SigAgendaStatus:
+		_, ok = m.Msg.(*exptypes.AgendaStatusChange)
+	case SigBlockValidity:
+		_, ok = m.Msg.(*exptypes.BlockValidity)
 	}
 
 	return ok
@@ -224,6 +239,15 @@ func (m HubMessage) String() string {
 	case SigNewTxs:
 		txs := m.Msg.([]*exptypes.MempoolTx)
 		sigStr += ":len=" + strconv.Itoa(len(txs))
+	case SigBlockInvalidated:
+		inv := m.Msg.(*exptypes.BlockInvalidation)
+		sigStr += ":" + inv.Hash
+	case SigAgendaStatus:
+		asc := m.Msg.(*exptypes.AgendaStatusChange)
+		sigStr += ":" + asc.AgendaID + ":" + asc.Event
+	case SigBlockValidity:
+		bv := m.Msg.(*exptypes.BlockValidity)
+		sigStr += ":" + bv.Hash + ":" + bv.Validity
 	}
 
 	return sigStr
@@ -46,6 +46,9 @@ var (
 	sigAddressTx        = pstypes.SigAddressTx
 	sigSyncStatus       = pstypes.SigSyncStatus
 	sigByeNow           = pstypes.SigByeNow
+	sigBlockInvalidated = pstypes.SigBlockInvalidated
+	sigAgendaStatus     = pstypes.SigAgendaStatus
+	sigBlockValidity    = pstypes.SigBlockValidity
 )
 
 type txList struct {
@@ -470,6 +473,30 @@ func (wsh *WebsocketHub) Run() {
 				// PubSubHub with a nil slice to be a valid message.
 				hubMsg.Signal = sigNewTxs
 				hubMsg.Msg = ([]*exptypes.MempoolTx)(nil) // PubSubHub accesses each client's own slice.
+			case sigBlockInvalidated:
+				inv, ok := hubMsg.Msg.(*exptypes.BlockInvalidation)
+				if !ok || inv == nil {
+					log.Errorf("sigBlockInvalidated did not store a *BlockInvalidation in Msg.")
+					continue
+				}
+				log.Infof("Signaling invalidation of block %s to %d websocket clients.",
+					inv.Hash, clientsCount)
+			case sigAgendaStatus:
+				asc, ok := hubMsg.Msg.(*exptypes.AgendaStatusChange)
+				if !ok || asc == nil {
+					log.Errorf("sigAgendaStatus did not store a *AgendaStatusChange in Msg.")
+					continue
+				}
+				log.Infof("Signaling agenda %s %s to %d websocket clients.",
+					asc.AgendaID, asc.Event, clientsCount)
+			case sigBlockValidity:
+				bv, ok := hubMsg.Msg.(*exptypes.BlockValidity)
+				if !ok || bv == nil {
+					log.Errorf("sigBlockValidity did not store a *BlockValidity in Msg.")
+					continue
+				}
+				log.Debugf("Signaling block %s %s to %d websocket clients.",
+					bv.Hash, bv.Validity, clientsCount)
 			case sigSubscribe, sigUnsubscribe:
 				log.Warnf("sigSubscribe and sigUnsubscribe are not broadcastable events.")
 				continue // break events
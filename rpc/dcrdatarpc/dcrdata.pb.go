@@ -0,0 +1,2027 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: dcrdata.proto
+
+package dcrdatarpc
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// BlockRequest selects a main chain block by hash, or by height if the hash is
+// empty.
+type BlockRequest struct {
+	Hash                 string   `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	Height               uint32   `protobuf:"varint,2,opt,name=height,proto3" json:"height,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BlockRequest) Reset()         { *m = BlockRequest{} }
+func (m *BlockRequest) String() string { return proto.CompactTextString(m) }
+func (*BlockRequest) ProtoMessage()    {}
+func (*BlockRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cb7c6c62ce743a79, []int{0}
+}
+
+func (m *BlockRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BlockRequest.Unmarshal(m, b)
+}
+func (m *BlockRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BlockRequest.Marshal(b, m, deterministic)
+}
+func (m *BlockRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BlockRequest.Merge(m, src)
+}
+func (m *BlockRequest) XXX_Size() int {
+	return xxx_messageInfo_BlockRequest.Size(m)
+}
+func (m *BlockRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BlockRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BlockRequest proto.InternalMessageInfo
+
+func (m *BlockRequest) GetHash() string {
+	if m != nil {
+		return m.Hash
+	}
+	return ""
+}
+
+func (m *BlockRequest) GetHeight() uint32 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+type BestBlockRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BestBlockRequest) Reset()         { *m = BestBlockRequest{} }
+func (m *BestBlockRequest) String() string { return proto.CompactTextString(m) }
+func (*BestBlockRequest) ProtoMessage()    {}
+func (*BestBlockRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cb7c6c62ce743a79, []int{1}
+}
+
+func (m *BestBlockRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BestBlockRequest.Unmarshal(m, b)
+}
+func (m *BestBlockRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BestBlockRequest.Marshal(b, m, deterministic)
+}
+func (m *BestBlockRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BestBlockRequest.Merge(m, src)
+}
+func (m *BestBlockRequest) XXX_Size() int {
+	return xxx_messageInfo_BestBlockRequest.Size(m)
+}
+func (m *BestBlockRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BestBlockRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BestBlockRequest proto.InternalMessageInfo
+
+type SubscribeBlocksRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SubscribeBlocksRequest) Reset()         { *m = SubscribeBlocksRequest{} }
+func (m *SubscribeBlocksRequest) String() string { return proto.CompactTextString(m) }
+func (*SubscribeBlocksRequest) ProtoMessage()    {}
+func (*SubscribeBlocksRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cb7c6c62ce743a79, []int{2}
+}
+
+func (m *SubscribeBlocksRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SubscribeBlocksRequest.Unmarshal(m, b)
+}
+func (m *SubscribeBlocksRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SubscribeBlocksRequest.Marshal(b, m, deterministic)
+}
+func (m *SubscribeBlocksRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SubscribeBlocksRequest.Merge(m, src)
+}
+func (m *SubscribeBlocksRequest) XXX_Size() int {
+	return xxx_messageInfo_SubscribeBlocksRequest.Size(m)
+}
+func (m *SubscribeBlocksRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SubscribeBlocksRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SubscribeBlocksRequest proto.InternalMessageInfo
+
+// Block is the summary of a block. The time is a UNIX timestamp, and the
+// difficulties are the proof-of-work difficulty and the ticket price in DCR.
+type Block struct {
+	Height               uint32      `protobuf:"varint,1,opt,name=height,proto3" json:"height,omitempty"`
+	Hash                 string      `protobuf:"bytes,2,opt,name=hash,proto3" json:"hash,omitempty"`
+	Time                 int64       `protobuf:"varint,3,opt,name=time,proto3" json:"time,omitempty"`
+	Size                 uint32      `protobuf:"varint,4,opt,name=size,proto3" json:"size,omitempty"`
+	NumTx                uint32      `protobuf:"varint,5,opt,name=num_tx,json=numTx,proto3" json:"num_tx,omitempty"`
+	Difficulty           float64     `protobuf:"fixed64,6,opt,name=difficulty,proto3" json:"difficulty,omitempty"`
+	StakeDifficulty      float64     `protobuf:"fixed64,7,opt,name=stake_difficulty,json=stakeDifficulty,proto3" json:"stake_difficulty,omitempty"`
+	TicketPool           *TicketPool `protobuf:"bytes,8,opt,name=ticket_pool,json=ticketPool,proto3" json:"ticket_pool,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *Block) Reset()         { *m = Block{} }
+func (m *Block) String() string { return proto.CompactTextString(m) }
+func (*Block) ProtoMessage()    {}
+func (*Block) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cb7c6c62ce743a79, []int{3}
+}
+
+func (m *Block) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Block.Unmarshal(m, b)
+}
+func (m *Block) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Block.Marshal(b, m, deterministic)
+}
+func (m *Block) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Block.Merge(m, src)
+}
+func (m *Block) XXX_Size() int {
+	return xxx_messageInfo_Block.Size(m)
+}
+func (m *Block) XXX_DiscardUnknown() {
+	xxx_messageInfo_Block.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Block proto.InternalMessageInfo
+
+func (m *Block) GetHeight() uint32 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+func (m *Block) GetHash() string {
+	if m != nil {
+		return m.Hash
+	}
+	return ""
+}
+
+func (m *Block) GetTime() int64 {
+	if m != nil {
+		return m.Time
+	}
+	return 0
+}
+
+func (m *Block) GetSize() uint32 {
+	if m != nil {
+		return m.Size
+	}
+	return 0
+}
+
+func (m *Block) GetNumTx() uint32 {
+	if m != nil {
+		return m.NumTx
+	}
+	return 0
+}
+
+func (m *Block) GetDifficulty() float64 {
+	if m != nil {
+		return m.Difficulty
+	}
+	return 0
+}
+
+func (m *Block) GetStakeDifficulty() float64 {
+	if m != nil {
+		return m.StakeDifficulty
+	}
+	return 0
+}
+
+func (m *Block) GetTicketPool() *TicketPool {
+	if m != nil {
+		return m.TicketPool
+	}
+	return nil
+}
+
+type TransactionRequest struct {
+	Txid                 string   `protobuf:"bytes,1,opt,name=txid,proto3" json:"txid,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TransactionRequest) Reset()         { *m = TransactionRequest{} }
+func (m *TransactionRequest) String() string { return proto.CompactTextString(m) }
+func (*TransactionRequest) ProtoMessage()    {}
+func (*TransactionRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cb7c6c62ce743a79, []int{4}
+}
+
+func (m *TransactionRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TransactionRequest.Unmarshal(m, b)
+}
+func (m *TransactionRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TransactionRequest.Marshal(b, m, deterministic)
+}
+func (m *TransactionRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TransactionRequest.Merge(m, src)
+}
+func (m *TransactionRequest) XXX_Size() int {
+	return xxx_messageInfo_TransactionRequest.Size(m)
+}
+func (m *TransactionRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_TransactionRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TransactionRequest proto.InternalMessageInfo
+
+func (m *TransactionRequest) GetTxid() string {
+	if m != nil {
+		return m.Txid
+	}
+	return ""
+}
+
+// Transaction is a decoded transaction. The block fields are unset for an
+// unconfirmed transaction.
+type Transaction struct {
+	Txid                 string               `protobuf:"bytes,1,opt,name=txid,proto3" json:"txid,omitempty"`
+	Size                 int32                `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	Version              int32                `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	Locktime             uint32               `protobuf:"varint,4,opt,name=locktime,proto3" json:"locktime,omitempty"`
+	Expiry               uint32               `protobuf:"varint,5,opt,name=expiry,proto3" json:"expiry,omitempty"`
+	Inputs               []*TransactionInput  `protobuf:"bytes,6,rep,name=inputs,proto3" json:"inputs,omitempty"`
+	Outputs              []*TransactionOutput `protobuf:"bytes,7,rep,name=outputs,proto3" json:"outputs,omitempty"`
+	Confirmations        int64                `protobuf:"varint,8,opt,name=confirmations,proto3" json:"confirmations,omitempty"`
+	BlockHash            string               `protobuf:"bytes,9,opt,name=block_hash,json=blockHash,proto3" json:"block_hash,omitempty"`
+	BlockHeight          int64                `protobuf:"varint,10,opt,name=block_height,json=blockHeight,proto3" json:"block_height,omitempty"`
+	BlockTime            int64                `protobuf:"varint,11,opt,name=block_time,json=blockTime,proto3" json:"block_time,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *Transaction) Reset()         { *m = Transaction{} }
+func (m *Transaction) String() string { return proto.CompactTextString(m) }
+func (*Transaction) ProtoMessage()    {}
+func (*Transaction) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cb7c6c62ce743a79, []int{5}
+}
+
+func (m *Transaction) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Transaction.Unmarshal(m, b)
+}
+func (m *Transaction) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Transaction.Marshal(b, m, deterministic)
+}
+func (m *Transaction) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Transaction.Merge(m, src)
+}
+func (m *Transaction) XXX_Size() int {
+	return xxx_messageInfo_Transaction.Size(m)
+}
+func (m *Transaction) XXX_DiscardUnknown() {
+	xxx_messageInfo_Transaction.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Transaction proto.InternalMessageInfo
+
+func (m *Transaction) GetTxid() string {
+	if m != nil {
+		return m.Txid
+	}
+	return ""
+}
+
+func (m *Transaction) GetSize() int32 {
+	if m != nil {
+		return m.Size
+	}
+	return 0
+}
+
+func (m *Transaction) GetVersion() int32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *Transaction) GetLocktime() uint32 {
+	if m != nil {
+		return m.Locktime
+	}
+	return 0
+}
+
+func (m *Transaction) GetExpiry() uint32 {
+	if m != nil {
+		return m.Expiry
+	}
+	return 0
+}
+
+func (m *Transaction) GetInputs() []*TransactionInput {
+	if m != nil {
+		return m.Inputs
+	}
+	return nil
+}
+
+func (m *Transaction) GetOutputs() []*TransactionOutput {
+	if m != nil {
+		return m.Outputs
+	}
+	return nil
+}
+
+func (m *Transaction) GetConfirmations() int64 {
+	if m != nil {
+		return m.Confirmations
+	}
+	return 0
+}
+
+func (m *Transaction) GetBlockHash() string {
+	if m != nil {
+		return m.BlockHash
+	}
+	return ""
+}
+
+func (m *Transaction) GetBlockHeight() int64 {
+	if m != nil {
+		return m.BlockHeight
+	}
+	return 0
+}
+
+func (m *Transaction) GetBlockTime() int64 {
+	if m != nil {
+		return m.BlockTime
+	}
+	return 0
+}
+
+// TransactionInput is an input of a transaction. Coinbase and stakebase inputs
+// have the coinbase or stakebase script instead of a previous outpoint. The
+// amount is in DCR.
+type TransactionInput struct {
+	PrevTxid             string   `protobuf:"bytes,1,opt,name=prev_txid,json=prevTxid,proto3" json:"prev_txid,omitempty"`
+	PrevIndex            uint32   `protobuf:"varint,2,opt,name=prev_index,json=prevIndex,proto3" json:"prev_index,omitempty"`
+	PrevTree             int32    `protobuf:"varint,3,opt,name=prev_tree,json=prevTree,proto3" json:"prev_tree,omitempty"`
+	Coinbase             string   `protobuf:"bytes,4,opt,name=coinbase,proto3" json:"coinbase,omitempty"`
+	Stakebase            string   `protobuf:"bytes,5,opt,name=stakebase,proto3" json:"stakebase,omitempty"`
+	Sequence             uint32   `protobuf:"varint,6,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	AmountIn             float64  `protobuf:"fixed64,7,opt,name=amount_in,json=amountIn,proto3" json:"amount_in,omitempty"`
+	BlockHeight          uint32   `protobuf:"varint,8,opt,name=block_height,json=blockHeight,proto3" json:"block_height,omitempty"`
+	BlockIndex           uint32   `protobuf:"varint,9,opt,name=block_index,json=blockIndex,proto3" json:"block_index,omitempty"`
+	ScriptSig            string   `protobuf:"bytes,10,opt,name=script_sig,json=scriptSig,proto3" json:"script_sig,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TransactionInput) Reset()         { *m = TransactionInput{} }
+func (m *TransactionInput) String() string { return proto.CompactTextString(m) }
+func (*TransactionInput) ProtoMessage()    {}
+func (*TransactionInput) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cb7c6c62ce743a79, []int{6}
+}
+
+func (m *TransactionInput) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TransactionInput.Unmarshal(m, b)
+}
+func (m *TransactionInput) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TransactionInput.Marshal(b, m, deterministic)
+}
+func (m *TransactionInput) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TransactionInput.Merge(m, src)
+}
+func (m *TransactionInput) XXX_Size() int {
+	return xxx_messageInfo_TransactionInput.Size(m)
+}
+func (m *TransactionInput) XXX_DiscardUnknown() {
+	xxx_messageInfo_TransactionInput.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TransactionInput proto.InternalMessageInfo
+
+func (m *TransactionInput) GetPrevTxid() string {
+	if m != nil {
+		return m.PrevTxid
+	}
+	return ""
+}
+
+func (m *TransactionInput) GetPrevIndex() uint32 {
+	if m != nil {
+		return m.PrevIndex
+	}
+	return 0
+}
+
+func (m *TransactionInput) GetPrevTree() int32 {
+	if m != nil {
+		return m.PrevTree
+	}
+	return 0
+}
+
+func (m *TransactionInput) GetCoinbase() string {
+	if m != nil {
+		return m.Coinbase
+	}
+	return ""
+}
+
+func (m *TransactionInput) GetStakebase() string {
+	if m != nil {
+		return m.Stakebase
+	}
+	return ""
+}
+
+func (m *TransactionInput) GetSequence() uint32 {
+	if m != nil {
+		return m.Sequence
+	}
+	return 0
+}
+
+func (m *TransactionInput) GetAmountIn() float64 {
+	if m != nil {
+		return m.AmountIn
+	}
+	return 0
+}
+
+func (m *TransactionInput) GetBlockHeight() uint32 {
+	if m != nil {
+		return m.BlockHeight
+	}
+	return 0
+}
+
+func (m *TransactionInput) GetBlockIndex() uint32 {
+	if m != nil {
+		return m.BlockIndex
+	}
+	return 0
+}
+
+func (m *TransactionInput) GetScriptSig() string {
+	if m != nil {
+		return m.ScriptSig
+	}
+	return ""
+}
+
+// TransactionOutput is an output of a transaction, and the input that spends
+// it, if known. The value is in DCR.
+type TransactionOutput struct {
+	Index                uint32   `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Value                float64  `protobuf:"fixed64,2,opt,name=value,proto3" json:"value,omitempty"`
+	Version              uint32   `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	ScriptType           string   `protobuf:"bytes,4,opt,name=script_type,json=scriptType,proto3" json:"script_type,omitempty"`
+	ScriptPubKey         string   `protobuf:"bytes,5,opt,name=script_pub_key,json=scriptPubKey,proto3" json:"script_pub_key,omitempty"`
+	Addresses            []string `protobuf:"bytes,6,rep,name=addresses,proto3" json:"addresses,omitempty"`
+	SpendTxid            string   `protobuf:"bytes,7,opt,name=spend_txid,json=spendTxid,proto3" json:"spend_txid,omitempty"`
+	SpendIndex           uint32   `protobuf:"varint,8,opt,name=spend_index,json=spendIndex,proto3" json:"spend_index,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TransactionOutput) Reset()         { *m = TransactionOutput{} }
+func (m *TransactionOutput) String() string { return proto.CompactTextString(m) }
+func (*TransactionOutput) ProtoMessage()    {}
+func (*TransactionOutput) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cb7c6c62ce743a79, []int{7}
+}
+
+func (m *TransactionOutput) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TransactionOutput.Unmarshal(m, b)
+}
+func (m *TransactionOutput) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TransactionOutput.Marshal(b, m, deterministic)
+}
+func (m *TransactionOutput) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TransactionOutput.Merge(m, src)
+}
+func (m *TransactionOutput) XXX_Size() int {
+	return xxx_messageInfo_TransactionOutput.Size(m)
+}
+func (m *TransactionOutput) XXX_DiscardUnknown() {
+	xxx_messageInfo_TransactionOutput.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TransactionOutput proto.InternalMessageInfo
+
+func (m *TransactionOutput) GetIndex() uint32 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *TransactionOutput) GetValue() float64 {
+	if m != nil {
+		return m.Value
+	}
+	return 0
+}
+
+func (m *TransactionOutput) GetVersion() uint32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *TransactionOutput) GetScriptType() string {
+	if m != nil {
+		return m.ScriptType
+	}
+	return ""
+}
+
+func (m *TransactionOutput) GetScriptPubKey() string {
+	if m != nil {
+		return m.ScriptPubKey
+	}
+	return ""
+}
+
+func (m *TransactionOutput) GetAddresses() []string {
+	if m != nil {
+		return m.Addresses
+	}
+	return nil
+}
+
+func (m *TransactionOutput) GetSpendTxid() string {
+	if m != nil {
+		return m.SpendTxid
+	}
+	return ""
+}
+
+func (m *TransactionOutput) GetSpendIndex() uint32 {
+	if m != nil {
+		return m.SpendIndex
+	}
+	return 0
+}
+
+// RawTransaction is a serialized transaction in hexadecimal.
+type RawTransaction struct {
+	Hex                  string   `protobuf:"bytes,1,opt,name=hex,proto3" json:"hex,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RawTransaction) Reset()         { *m = RawTransaction{} }
+func (m *RawTransaction) String() string { return proto.CompactTextString(m) }
+func (*RawTransaction) ProtoMessage()    {}
+func (*RawTransaction) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cb7c6c62ce743a79, []int{8}
+}
+
+func (m *RawTransaction) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RawTransaction.Unmarshal(m, b)
+}
+func (m *RawTransaction) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RawTransaction.Marshal(b, m, deterministic)
+}
+func (m *RawTransaction) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RawTransaction.Merge(m, src)
+}
+func (m *RawTransaction) XXX_Size() int {
+	return xxx_messageInfo_RawTransaction.Size(m)
+}
+func (m *RawTransaction) XXX_DiscardUnknown() {
+	xxx_messageInfo_RawTransaction.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RawTransaction proto.InternalMessageInfo
+
+func (m *RawTransaction) GetHex() string {
+	if m != nil {
+		return m.Hex
+	}
+	return ""
+}
+
+type SubscribeMempoolRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SubscribeMempoolRequest) Reset()         { *m = SubscribeMempoolRequest{} }
+func (m *SubscribeMempoolRequest) String() string { return proto.CompactTextString(m) }
+func (*SubscribeMempoolRequest) ProtoMessage()    {}
+func (*SubscribeMempoolRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cb7c6c62ce743a79, []int{9}
+}
+
+func (m *SubscribeMempoolRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SubscribeMempoolRequest.Unmarshal(m, b)
+}
+func (m *SubscribeMempoolRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SubscribeMempoolRequest.Marshal(b, m, deterministic)
+}
+func (m *SubscribeMempoolRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SubscribeMempoolRequest.Merge(m, src)
+}
+func (m *SubscribeMempoolRequest) XXX_Size() int {
+	return xxx_messageInfo_SubscribeMempoolRequest.Size(m)
+}
+func (m *SubscribeMempoolRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SubscribeMempoolRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SubscribeMempoolRequest proto.InternalMessageInfo
+
+// MempoolTransaction is a transaction that entered the mempool. The type is
+// one of Regular, Ticket, Vote or Revocation, the time is the UNIX time it was
+// first seen, and the amounts are in DCR.
+type MempoolTransaction struct {
+	Txid                 string   `protobuf:"bytes,1,opt,name=txid,proto3" json:"txid,omitempty"`
+	Type                 string   `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Time                 int64    `protobuf:"varint,3,opt,name=time,proto3" json:"time,omitempty"`
+	Size                 int32    `protobuf:"varint,4,opt,name=size,proto3" json:"size,omitempty"`
+	TotalOut             float64  `protobuf:"fixed64,5,opt,name=total_out,json=totalOut,proto3" json:"total_out,omitempty"`
+	Fees                 float64  `protobuf:"fixed64,6,opt,name=fees,proto3" json:"fees,omitempty"`
+	FeeRate              float64  `protobuf:"fixed64,7,opt,name=fee_rate,json=feeRate,proto3" json:"fee_rate,omitempty"`
+	Expiry               uint32   `protobuf:"varint,8,opt,name=expiry,proto3" json:"expiry,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MempoolTransaction) Reset()         { *m = MempoolTransaction{} }
+func (m *MempoolTransaction) String() string { return proto.CompactTextString(m) }
+func (*MempoolTransaction) ProtoMessage()    {}
+func (*MempoolTransaction) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cb7c6c62ce743a79, []int{10}
+}
+
+func (m *MempoolTransaction) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MempoolTransaction.Unmarshal(m, b)
+}
+func (m *MempoolTransaction) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MempoolTransaction.Marshal(b, m, deterministic)
+}
+func (m *MempoolTransaction) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MempoolTransaction.Merge(m, src)
+}
+func (m *MempoolTransaction) XXX_Size() int {
+	return xxx_messageInfo_MempoolTransaction.Size(m)
+}
+func (m *MempoolTransaction) XXX_DiscardUnknown() {
+	xxx_messageInfo_MempoolTransaction.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MempoolTransaction proto.InternalMessageInfo
+
+func (m *MempoolTransaction) GetTxid() string {
+	if m != nil {
+		return m.Txid
+	}
+	return ""
+}
+
+func (m *MempoolTransaction) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *MempoolTransaction) GetTime() int64 {
+	if m != nil {
+		return m.Time
+	}
+	return 0
+}
+
+func (m *MempoolTransaction) GetSize() int32 {
+	if m != nil {
+		return m.Size
+	}
+	return 0
+}
+
+func (m *MempoolTransaction) GetTotalOut() float64 {
+	if m != nil {
+		return m.TotalOut
+	}
+	return 0
+}
+
+func (m *MempoolTransaction) GetFees() float64 {
+	if m != nil {
+		return m.Fees
+	}
+	return 0
+}
+
+func (m *MempoolTransaction) GetFeeRate() float64 {
+	if m != nil {
+		return m.FeeRate
+	}
+	return 0
+}
+
+func (m *MempoolTransaction) GetExpiry() uint32 {
+	if m != nil {
+		return m.Expiry
+	}
+	return 0
+}
+
+type AddressRequest struct {
+	Address              string   `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AddressRequest) Reset()         { *m = AddressRequest{} }
+func (m *AddressRequest) String() string { return proto.CompactTextString(m) }
+func (*AddressRequest) ProtoMessage()    {}
+func (*AddressRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cb7c6c62ce743a79, []int{11}
+}
+
+func (m *AddressRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AddressRequest.Unmarshal(m, b)
+}
+func (m *AddressRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AddressRequest.Marshal(b, m, deterministic)
+}
+func (m *AddressRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AddressRequest.Merge(m, src)
+}
+func (m *AddressRequest) XXX_Size() int {
+	return xxx_messageInfo_AddressRequest.Size(m)
+}
+func (m *AddressRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AddressRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AddressRequest proto.InternalMessageInfo
+
+func (m *AddressRequest) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+// AddressBalance is the balance of an address. The spent and unspent totals
+// are in atoms.
+type AddressBalance struct {
+	Address              string   `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	NumSpent             int64    `protobuf:"varint,2,opt,name=num_spent,json=numSpent,proto3" json:"num_spent,omitempty"`
+	NumUnspent           int64    `protobuf:"varint,3,opt,name=num_unspent,json=numUnspent,proto3" json:"num_unspent,omitempty"`
+	TotalSpent           int64    `protobuf:"varint,4,opt,name=total_spent,json=totalSpent,proto3" json:"total_spent,omitempty"`
+	TotalUnspent         int64    `protobuf:"varint,5,opt,name=total_unspent,json=totalUnspent,proto3" json:"total_unspent,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AddressBalance) Reset()         { *m = AddressBalance{} }
+func (m *AddressBalance) String() string { return proto.CompactTextString(m) }
+func (*AddressBalance) ProtoMessage()    {}
+func (*AddressBalance) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cb7c6c62ce743a79, []int{12}
+}
+
+func (m *AddressBalance) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AddressBalance.Unmarshal(m, b)
+}
+func (m *AddressBalance) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AddressBalance.Marshal(b, m, deterministic)
+}
+func (m *AddressBalance) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AddressBalance.Merge(m, src)
+}
+func (m *AddressBalance) XXX_Size() int {
+	return xxx_messageInfo_AddressBalance.Size(m)
+}
+func (m *AddressBalance) XXX_DiscardUnknown() {
+	xxx_messageInfo_AddressBalance.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AddressBalance proto.InternalMessageInfo
+
+func (m *AddressBalance) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *AddressBalance) GetNumSpent() int64 {
+	if m != nil {
+		return m.NumSpent
+	}
+	return 0
+}
+
+func (m *AddressBalance) GetNumUnspent() int64 {
+	if m != nil {
+		return m.NumUnspent
+	}
+	return 0
+}
+
+func (m *AddressBalance) GetTotalSpent() int64 {
+	if m != nil {
+		return m.TotalSpent
+	}
+	return 0
+}
+
+func (m *AddressBalance) GetTotalUnspent() int64 {
+	if m != nil {
+		return m.TotalUnspent
+	}
+	return 0
+}
+
+// AddressTransactionsRequest selects count transactions of an address after
+// skipping the newest skip transactions.
+type AddressTransactionsRequest struct {
+	Address              string   `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Count                int64    `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	Skip                 int64    `protobuf:"varint,3,opt,name=skip,proto3" json:"skip,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AddressTransactionsRequest) Reset()         { *m = AddressTransactionsRequest{} }
+func (m *AddressTransactionsRequest) String() string { return proto.CompactTextString(m) }
+func (*AddressTransactionsRequest) ProtoMessage()    {}
+func (*AddressTransactionsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cb7c6c62ce743a79, []int{13}
+}
+
+func (m *AddressTransactionsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AddressTransactionsRequest.Unmarshal(m, b)
+}
+func (m *AddressTransactionsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AddressTransactionsRequest.Marshal(b, m, deterministic)
+}
+func (m *AddressTransactionsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AddressTransactionsRequest.Merge(m, src)
+}
+func (m *AddressTransactionsRequest) XXX_Size() int {
+	return xxx_messageInfo_AddressTransactionsRequest.Size(m)
+}
+func (m *AddressTransactionsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AddressTransactionsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AddressTransactionsRequest proto.InternalMessageInfo
+
+func (m *AddressTransactionsRequest) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *AddressTransactionsRequest) GetCount() int64 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+func (m *AddressTransactionsRequest) GetSkip() int64 {
+	if m != nil {
+		return m.Skip
+	}
+	return 0
+}
+
+// AddressTransactions is a page of the transactions of an address, as of the
+// best block identified by the tip hash and height.
+type AddressTransactions struct {
+	Address              string                `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Transactions         []*AddressTransaction `protobuf:"bytes,2,rep,name=transactions,proto3" json:"transactions,omitempty"`
+	TipHash              string                `protobuf:"bytes,3,opt,name=tip_hash,json=tipHash,proto3" json:"tip_hash,omitempty"`
+	TipHeight            int64                 `protobuf:"varint,4,opt,name=tip_height,json=tipHeight,proto3" json:"tip_height,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *AddressTransactions) Reset()         { *m = AddressTransactions{} }
+func (m *AddressTransactions) String() string { return proto.CompactTextString(m) }
+func (*AddressTransactions) ProtoMessage()    {}
+func (*AddressTransactions) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cb7c6c62ce743a79, []int{14}
+}
+
+func (m *AddressTransactions) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AddressTransactions.Unmarshal(m, b)
+}
+func (m *AddressTransactions) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AddressTransactions.Marshal(b, m, deterministic)
+}
+func (m *AddressTransactions) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AddressTransactions.Merge(m, src)
+}
+func (m *AddressTransactions) XXX_Size() int {
+	return xxx_messageInfo_AddressTransactions.Size(m)
+}
+func (m *AddressTransactions) XXX_DiscardUnknown() {
+	xxx_messageInfo_AddressTransactions.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AddressTransactions proto.InternalMessageInfo
+
+func (m *AddressTransactions) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *AddressTransactions) GetTransactions() []*AddressTransaction {
+	if m != nil {
+		return m.Transactions
+	}
+	return nil
+}
+
+func (m *AddressTransactions) GetTipHash() string {
+	if m != nil {
+		return m.TipHash
+	}
+	return ""
+}
+
+func (m *AddressTransactions) GetTipHeight() int64 {
+	if m != nil {
+		return m.TipHeight
+	}
+	return 0
+}
+
+// AddressTransaction is a transaction crediting or debiting an address. The
+// amounts are in DCR and the time is a UNIX timestamp.
+type AddressTransaction struct {
+	Txid                 string   `protobuf:"bytes,1,opt,name=txid,proto3" json:"txid,omitempty"`
+	Size                 int32    `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	Time                 int64    `protobuf:"varint,3,opt,name=time,proto3" json:"time,omitempty"`
+	Value                float64  `protobuf:"fixed64,4,opt,name=value,proto3" json:"value,omitempty"`
+	Fees                 float64  `protobuf:"fixed64,5,opt,name=fees,proto3" json:"fees,omitempty"`
+	Confirmations        int64    `protobuf:"varint,6,opt,name=confirmations,proto3" json:"confirmations,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AddressTransaction) Reset()         { *m = AddressTransaction{} }
+func (m *AddressTransaction) String() string { return proto.CompactTextString(m) }
+func (*AddressTransaction) ProtoMessage()    {}
+func (*AddressTransaction) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cb7c6c62ce743a79, []int{15}
+}
+
+func (m *AddressTransaction) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AddressTransaction.Unmarshal(m, b)
+}
+func (m *AddressTransaction) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AddressTransaction.Marshal(b, m, deterministic)
+}
+func (m *AddressTransaction) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AddressTransaction.Merge(m, src)
+}
+func (m *AddressTransaction) XXX_Size() int {
+	return xxx_messageInfo_AddressTransaction.Size(m)
+}
+func (m *AddressTransaction) XXX_DiscardUnknown() {
+	xxx_messageInfo_AddressTransaction.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AddressTransaction proto.InternalMessageInfo
+
+func (m *AddressTransaction) GetTxid() string {
+	if m != nil {
+		return m.Txid
+	}
+	return ""
+}
+
+func (m *AddressTransaction) GetSize() int32 {
+	if m != nil {
+		return m.Size
+	}
+	return 0
+}
+
+func (m *AddressTransaction) GetTime() int64 {
+	if m != nil {
+		return m.Time
+	}
+	return 0
+}
+
+func (m *AddressTransaction) GetValue() float64 {
+	if m != nil {
+		return m.Value
+	}
+	return 0
+}
+
+func (m *AddressTransaction) GetFees() float64 {
+	if m != nil {
+		return m.Fees
+	}
+	return 0
+}
+
+func (m *AddressTransaction) GetConfirmations() int64 {
+	if m != nil {
+		return m.Confirmations
+	}
+	return 0
+}
+
+// TicketPoolRequest selects a block, or the best block if unset.
+type TicketPoolRequest struct {
+	Block                *BlockRequest `protobuf:"bytes,1,opt,name=block,proto3" json:"block,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *TicketPoolRequest) Reset()         { *m = TicketPoolRequest{} }
+func (m *TicketPoolRequest) String() string { return proto.CompactTextString(m) }
+func (*TicketPoolRequest) ProtoMessage()    {}
+func (*TicketPoolRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cb7c6c62ce743a79, []int{16}
+}
+
+func (m *TicketPoolRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TicketPoolRequest.Unmarshal(m, b)
+}
+func (m *TicketPoolRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TicketPoolRequest.Marshal(b, m, deterministic)
+}
+func (m *TicketPoolRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TicketPoolRequest.Merge(m, src)
+}
+func (m *TicketPoolRequest) XXX_Size() int {
+	return xxx_messageInfo_TicketPoolRequest.Size(m)
+}
+func (m *TicketPoolRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_TicketPoolRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TicketPoolRequest proto.InternalMessageInfo
+
+func (m *TicketPoolRequest) GetBlock() *BlockRequest {
+	if m != nil {
+		return m.Block
+	}
+	return nil
+}
+
+// TicketPool is the ticket pool after a block. The values are in DCR.
+type TicketPool struct {
+	Height               uint32   `protobuf:"varint,1,opt,name=height,proto3" json:"height,omitempty"`
+	Size                 uint32   `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	Value                float64  `protobuf:"fixed64,3,opt,name=value,proto3" json:"value,omitempty"`
+	ValueAvg             float64  `protobuf:"fixed64,4,opt,name=value_avg,json=valueAvg,proto3" json:"value_avg,omitempty"`
+	Winners              []string `protobuf:"bytes,5,rep,name=winners,proto3" json:"winners,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TicketPool) Reset()         { *m = TicketPool{} }
+func (m *TicketPool) String() string { return proto.CompactTextString(m) }
+func (*TicketPool) ProtoMessage()    {}
+func (*TicketPool) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cb7c6c62ce743a79, []int{17}
+}
+
+func (m *TicketPool) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TicketPool.Unmarshal(m, b)
+}
+func (m *TicketPool) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TicketPool.Marshal(b, m, deterministic)
+}
+func (m *TicketPool) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TicketPool.Merge(m, src)
+}
+func (m *TicketPool) XXX_Size() int {
+	return xxx_messageInfo_TicketPool.Size(m)
+}
+func (m *TicketPool) XXX_DiscardUnknown() {
+	xxx_messageInfo_TicketPool.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TicketPool proto.InternalMessageInfo
+
+func (m *TicketPool) GetHeight() uint32 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+func (m *TicketPool) GetSize() uint32 {
+	if m != nil {
+		return m.Size
+	}
+	return 0
+}
+
+func (m *TicketPool) GetValue() float64 {
+	if m != nil {
+		return m.Value
+	}
+	return 0
+}
+
+func (m *TicketPool) GetValueAvg() float64 {
+	if m != nil {
+		return m.ValueAvg
+	}
+	return 0
+}
+
+func (m *TicketPool) GetWinners() []string {
+	if m != nil {
+		return m.Winners
+	}
+	return nil
+}
+
+type StakeDifficultyRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StakeDifficultyRequest) Reset()         { *m = StakeDifficultyRequest{} }
+func (m *StakeDifficultyRequest) String() string { return proto.CompactTextString(m) }
+func (*StakeDifficultyRequest) ProtoMessage()    {}
+func (*StakeDifficultyRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cb7c6c62ce743a79, []int{18}
+}
+
+func (m *StakeDifficultyRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StakeDifficultyRequest.Unmarshal(m, b)
+}
+func (m *StakeDifficultyRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StakeDifficultyRequest.Marshal(b, m, deterministic)
+}
+func (m *StakeDifficultyRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StakeDifficultyRequest.Merge(m, src)
+}
+func (m *StakeDifficultyRequest) XXX_Size() int {
+	return xxx_messageInfo_StakeDifficultyRequest.Size(m)
+}
+func (m *StakeDifficultyRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_StakeDifficultyRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StakeDifficultyRequest proto.InternalMessageInfo
+
+// StakeDifficulty is the current and next ticket price, and the minimum,
+// maximum and expected price of the next stake difficulty window, in DCR.
+type StakeDifficulty struct {
+	Current              float64  `protobuf:"fixed64,1,opt,name=current,proto3" json:"current,omitempty"`
+	Next                 float64  `protobuf:"fixed64,2,opt,name=next,proto3" json:"next,omitempty"`
+	EstimateMin          float64  `protobuf:"fixed64,3,opt,name=estimate_min,json=estimateMin,proto3" json:"estimate_min,omitempty"`
+	EstimateMax          float64  `protobuf:"fixed64,4,opt,name=estimate_max,json=estimateMax,proto3" json:"estimate_max,omitempty"`
+	EstimateExpected     float64  `protobuf:"fixed64,5,opt,name=estimate_expected,json=estimateExpected,proto3" json:"estimate_expected,omitempty"`
+	WindowBlockIndex     int64    `protobuf:"varint,6,opt,name=window_block_index,json=windowBlockIndex,proto3" json:"window_block_index,omitempty"`
+	WindowNumber         int64    `protobuf:"varint,7,opt,name=window_number,json=windowNumber,proto3" json:"window_number,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StakeDifficulty) Reset()         { *m = StakeDifficulty{} }
+func (m *StakeDifficulty) String() string { return proto.CompactTextString(m) }
+func (*StakeDifficulty) ProtoMessage()    {}
+func (*StakeDifficulty) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cb7c6c62ce743a79, []int{19}
+}
+
+func (m *StakeDifficulty) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StakeDifficulty.Unmarshal(m, b)
+}
+func (m *StakeDifficulty) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StakeDifficulty.Marshal(b, m, deterministic)
+}
+func (m *StakeDifficulty) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StakeDifficulty.Merge(m, src)
+}
+func (m *StakeDifficulty) XXX_Size() int {
+	return xxx_messageInfo_StakeDifficulty.Size(m)
+}
+func (m *StakeDifficulty) XXX_DiscardUnknown() {
+	xxx_messageInfo_StakeDifficulty.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StakeDifficulty proto.InternalMessageInfo
+
+func (m *StakeDifficulty) GetCurrent() float64 {
+	if m != nil {
+		return m.Current
+	}
+	return 0
+}
+
+func (m *StakeDifficulty) GetNext() float64 {
+	if m != nil {
+		return m.Next
+	}
+	return 0
+}
+
+func (m *StakeDifficulty) GetEstimateMin() float64 {
+	if m != nil {
+		return m.EstimateMin
+	}
+	return 0
+}
+
+func (m *StakeDifficulty) GetEstimateMax() float64 {
+	if m != nil {
+		return m.EstimateMax
+	}
+	return 0
+}
+
+func (m *StakeDifficulty) GetEstimateExpected() float64 {
+	if m != nil {
+		return m.EstimateExpected
+	}
+	return 0
+}
+
+func (m *StakeDifficulty) GetWindowBlockIndex() int64 {
+	if m != nil {
+		return m.WindowBlockIndex
+	}
+	return 0
+}
+
+func (m *StakeDifficulty) GetWindowNumber() int64 {
+	if m != nil {
+		return m.WindowNumber
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterType((*BlockRequest)(nil), "dcrdatarpc.BlockRequest")
+	proto.RegisterType((*BestBlockRequest)(nil), "dcrdatarpc.BestBlockRequest")
+	proto.RegisterType((*SubscribeBlocksRequest)(nil), "dcrdatarpc.SubscribeBlocksRequest")
+	proto.RegisterType((*Block)(nil), "dcrdatarpc.Block")
+	proto.RegisterType((*TransactionRequest)(nil), "dcrdatarpc.TransactionRequest")
+	proto.RegisterType((*Transaction)(nil), "dcrdatarpc.Transaction")
+	proto.RegisterType((*TransactionInput)(nil), "dcrdatarpc.TransactionInput")
+	proto.RegisterType((*TransactionOutput)(nil), "dcrdatarpc.TransactionOutput")
+	proto.RegisterType((*RawTransaction)(nil), "dcrdatarpc.RawTransaction")
+	proto.RegisterType((*SubscribeMempoolRequest)(nil), "dcrdatarpc.SubscribeMempoolRequest")
+	proto.RegisterType((*MempoolTransaction)(nil), "dcrdatarpc.MempoolTransaction")
+	proto.RegisterType((*AddressRequest)(nil), "dcrdatarpc.AddressRequest")
+	proto.RegisterType((*AddressBalance)(nil), "dcrdatarpc.AddressBalance")
+	proto.RegisterType((*AddressTransactionsRequest)(nil), "dcrdatarpc.AddressTransactionsRequest")
+	proto.RegisterType((*AddressTransactions)(nil), "dcrdatarpc.AddressTransactions")
+	proto.RegisterType((*AddressTransaction)(nil), "dcrdatarpc.AddressTransaction")
+	proto.RegisterType((*TicketPoolRequest)(nil), "dcrdatarpc.TicketPoolRequest")
+	proto.RegisterType((*TicketPool)(nil), "dcrdatarpc.TicketPool")
+	proto.RegisterType((*StakeDifficultyRequest)(nil), "dcrdatarpc.StakeDifficultyRequest")
+	proto.RegisterType((*StakeDifficulty)(nil), "dcrdatarpc.StakeDifficulty")
+}
+
+func init() { proto.RegisterFile("dcrdata.proto", fileDescriptor_cb7c6c62ce743a79) }
+
+var fileDescriptor_cb7c6c62ce743a79 = []byte{
+	// 1366 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x57, 0x4d, 0x73, 0x1b, 0x45,
+	0x13, 0xae, 0xb5, 0xac, 0x8f, 0x6d, 0x49, 0x89, 0x3c, 0xef, 0x4b, 0xb2, 0x91, 0xe3, 0xc4, 0x6c,
+	0x52, 0x94, 0xf8, 0x28, 0x3b, 0x25, 0x02, 0xa1, 0x80, 0x4b, 0x1c, 0xa0, 0x08, 0x10, 0x92, 0x5a,
+	0x9b, 0xa2, 0xe0, 0xb2, 0xac, 0x56, 0x63, 0x7b, 0xca, 0xda, 0xd9, 0x65, 0x77, 0x56, 0x96, 0xb9,
+	0xe7, 0x7f, 0x70, 0xe3, 0x02, 0xfc, 0x07, 0x6e, 0x9c, 0xf8, 0x39, 0x54, 0x71, 0xa1, 0xa8, 0xe9,
+	0x99, 0x59, 0xed, 0xea, 0xc3, 0xce, 0x6d, 0xba, 0xfb, 0x99, 0xd6, 0xf4, 0xd3, 0xbd, 0xdd, 0x2d,
+	0xe8, 0x8e, 0xc3, 0x74, 0x1c, 0x88, 0x60, 0x2f, 0x49, 0x63, 0x11, 0x13, 0xd0, 0x62, 0x9a, 0x84,
+	0xee, 0x87, 0xd0, 0x39, 0x98, 0xc4, 0xe1, 0x99, 0x47, 0x7f, 0xcc, 0x69, 0x26, 0x08, 0x81, 0xcd,
+	0xd3, 0x20, 0x3b, 0x75, 0xac, 0x5d, 0x6b, 0x60, 0x7b, 0x78, 0x26, 0x37, 0xa0, 0x71, 0x4a, 0xd9,
+	0xc9, 0xa9, 0x70, 0x36, 0x76, 0xad, 0x41, 0xd7, 0xd3, 0x92, 0x4b, 0xa0, 0x77, 0x40, 0x33, 0x51,
+	0xbe, 0xef, 0x3a, 0x70, 0xe3, 0x30, 0x1f, 0x65, 0x61, 0xca, 0x46, 0x14, 0x0d, 0x99, 0xb1, 0xfc,
+	0x6d, 0x41, 0x1d, 0x35, 0x25, 0x7f, 0x56, 0xd9, 0x5f, 0xf1, 0xdb, 0x1b, 0xa5, 0xdf, 0x26, 0xb0,
+	0x29, 0x58, 0x44, 0x9d, 0xda, 0xae, 0x35, 0xa8, 0x79, 0x78, 0x96, 0xba, 0x8c, 0xfd, 0x44, 0x9d,
+	0x4d, 0xbc, 0x8d, 0x67, 0xf2, 0x1a, 0x34, 0x78, 0x1e, 0xf9, 0x62, 0xe6, 0xd4, 0x51, 0x5b, 0xe7,
+	0x79, 0x74, 0x34, 0x23, 0x77, 0x00, 0xc6, 0xec, 0xf8, 0x98, 0x85, 0xf9, 0x44, 0x5c, 0x38, 0x8d,
+	0x5d, 0x6b, 0x60, 0x79, 0x25, 0x0d, 0x79, 0x13, 0x7a, 0x99, 0x08, 0xce, 0xa8, 0x5f, 0x42, 0x35,
+	0x11, 0x75, 0x1d, 0xf5, 0x9f, 0xcc, 0xa1, 0x8f, 0xa0, 0x2d, 0x58, 0x78, 0x46, 0x85, 0x9f, 0xc4,
+	0xf1, 0xc4, 0x69, 0xed, 0x5a, 0x83, 0xf6, 0xf0, 0xc6, 0xde, 0x9c, 0xcb, 0xbd, 0x23, 0x34, 0xbf,
+	0x88, 0xe3, 0x89, 0x07, 0xa2, 0x38, 0xbb, 0x03, 0x20, 0x47, 0x69, 0xc0, 0xb3, 0x20, 0x14, 0x2c,
+	0xe6, 0x25, 0xa2, 0xc5, 0x8c, 0x8d, 0x0d, 0xd1, 0xf2, 0xec, 0xfe, 0xb3, 0x01, 0xed, 0x12, 0x74,
+	0x15, 0xa6, 0x08, 0x5e, 0x92, 0x54, 0xd7, 0xc1, 0x3b, 0xd0, 0x9c, 0xd2, 0x34, 0x63, 0x31, 0x47,
+	0x9e, 0xea, 0x9e, 0x11, 0x49, 0x1f, 0x5a, 0x92, 0x72, 0xa4, 0x50, 0xd1, 0x55, 0xc8, 0x32, 0x0d,
+	0x74, 0x96, 0xb0, 0xf4, 0x42, 0x53, 0xa6, 0x25, 0xf2, 0x10, 0x1a, 0x8c, 0x27, 0xb9, 0xc8, 0x9c,
+	0xc6, 0x6e, 0x6d, 0xd0, 0x1e, 0xde, 0xae, 0xc4, 0x38, 0x7f, 0xde, 0x53, 0x09, 0xf2, 0x34, 0x96,
+	0x3c, 0x82, 0x66, 0x9c, 0x0b, 0xbc, 0xd6, 0xc4, 0x6b, 0x3b, 0x6b, 0xae, 0x3d, 0x47, 0x94, 0x67,
+	0xd0, 0xe4, 0x3e, 0x74, 0xc3, 0x98, 0x1f, 0xb3, 0x34, 0x0a, 0xa4, 0x39, 0x43, 0x66, 0x6b, 0x5e,
+	0x55, 0x49, 0x76, 0x00, 0x46, 0xf2, 0xe5, 0x3e, 0x56, 0x88, 0x8d, 0x84, 0xd8, 0xa8, 0xf9, 0x5c,
+	0x96, 0xc9, 0xeb, 0xd0, 0xd1, 0x66, 0x55, 0x58, 0x80, 0x3e, 0xda, 0x0a, 0x80, 0xaa, 0xb9, 0x07,
+	0x24, 0xa3, 0x8d, 0x00, 0xe5, 0xe1, 0x88, 0x45, 0xd4, 0xfd, 0x63, 0x03, 0x7a, 0x8b, 0xc1, 0x91,
+	0x6d, 0xb0, 0x93, 0x94, 0x4e, 0xfd, 0x52, 0x16, 0x5a, 0x52, 0x71, 0x24, 0x33, 0xb1, 0x03, 0x80,
+	0x46, 0xc6, 0xc7, 0x74, 0xa6, 0x3f, 0x0d, 0x84, 0x3f, 0x95, 0x8a, 0xf9, 0xdd, 0x94, 0x52, 0x9d,
+	0x16, 0x75, 0x37, 0xa5, 0x54, 0xe6, 0x25, 0x8c, 0x19, 0x1f, 0x05, 0x99, 0xca, 0x8b, 0xed, 0x15,
+	0x32, 0xb9, 0x0d, 0x36, 0xd6, 0x1e, 0x1a, 0xeb, 0x2a, 0xd2, 0x42, 0x21, 0x6f, 0x66, 0xb2, 0x84,
+	0x78, 0x48, 0xb1, 0x9e, 0xbb, 0x5e, 0x21, 0xcb, 0x9f, 0x0c, 0xa2, 0x38, 0xe7, 0xc2, 0x67, 0x5c,
+	0x97, 0x71, 0x4b, 0x29, 0x9e, 0xf2, 0x25, 0x8a, 0x5a, 0x78, 0xb9, 0x42, 0xd1, 0x5d, 0x50, 0xa2,
+	0x0e, 0xc9, 0x46, 0x84, 0x62, 0x4d, 0xc5, 0xb4, 0x03, 0x20, 0x3f, 0xed, 0x44, 0xf8, 0x19, 0x3b,
+	0x71, 0x40, 0xbf, 0x0d, 0x35, 0x87, 0xec, 0xc4, 0xfd, 0xd7, 0x82, 0xad, 0xa5, 0x4c, 0x93, 0xff,
+	0x43, 0x5d, 0xf9, 0x53, 0x5f, 0xbb, 0x12, 0xa4, 0x76, 0x1a, 0x4c, 0x72, 0x55, 0xc8, 0x96, 0xa7,
+	0x84, 0xc5, 0x4a, 0xee, 0xce, 0x2b, 0xf9, 0x2e, 0xb4, 0xf5, 0x4f, 0x8b, 0x8b, 0xc4, 0x90, 0xa6,
+	0x5f, 0x73, 0x74, 0x91, 0x50, 0x72, 0x1f, 0xae, 0x69, 0x40, 0x92, 0x8f, 0xfc, 0x33, 0x7a, 0xa1,
+	0xb9, 0xeb, 0x28, 0xed, 0x8b, 0x7c, 0xf4, 0x25, 0xbd, 0x90, 0xe4, 0x06, 0xe3, 0x71, 0x4a, 0xb3,
+	0x8c, 0xaa, 0xfa, 0xb6, 0xbd, 0xb9, 0x02, 0xe3, 0x4b, 0x28, 0x1f, 0xab, 0x84, 0x37, 0x75, 0x7c,
+	0x52, 0x83, 0x19, 0x97, 0x6f, 0x40, 0xb3, 0x8a, 0x47, 0x31, 0xa8, 0x6e, 0x20, 0x3f, 0xae, 0x0b,
+	0xd7, 0xbc, 0xe0, 0xbc, 0xfc, 0x09, 0xf7, 0xa0, 0x76, 0xaa, 0x43, 0xb7, 0x3d, 0x79, 0x74, 0x6f,
+	0xc1, 0xcd, 0xa2, 0x43, 0x3e, 0xa3, 0x91, 0x6c, 0x26, 0xa6, 0x45, 0xfe, 0x69, 0x01, 0xd1, 0xaa,
+	0x57, 0x68, 0x03, 0xc8, 0x83, 0xee, 0x95, 0xf2, 0x7c, 0x65, 0xaf, 0x34, 0xed, 0x62, 0x1b, 0x6c,
+	0x11, 0x8b, 0x60, 0xe2, 0xc7, 0xb9, 0x40, 0x92, 0x2c, 0xaf, 0x85, 0x8a, 0xe7, 0x39, 0xf6, 0xa5,
+	0x63, 0x8a, 0xdc, 0x48, 0x3d, 0x9e, 0xc9, 0x2d, 0x68, 0x1d, 0x53, 0xea, 0xa7, 0x81, 0xa0, 0xba,
+	0xac, 0x9a, 0xc7, 0x94, 0x7a, 0x81, 0x28, 0x37, 0x91, 0x56, 0xb9, 0x89, 0xb8, 0x6f, 0xc1, 0xb5,
+	0xc7, 0x8a, 0x56, 0xd3, 0xf0, 0x1c, 0x68, 0x6a, 0xa2, 0x75, 0x20, 0x46, 0x74, 0x7f, 0xb7, 0x0a,
+	0xf0, 0x41, 0x30, 0x09, 0x64, 0x25, 0xaf, 0x05, 0xcb, 0xc7, 0xcb, 0x46, 0x2f, 0x49, 0x57, 0xf3,
+	0xa8, 0xe6, 0xb5, 0x78, 0x1e, 0x1d, 0x4a, 0x59, 0x26, 0x48, 0x1a, 0x73, 0xae, 0xcc, 0x8a, 0x08,
+	0xe0, 0x79, 0xf4, 0x0d, 0xcf, 0x0c, 0x40, 0x85, 0xae, 0x00, 0x9b, 0x0a, 0x80, 0x2a, 0xe5, 0xe1,
+	0x1e, 0x74, 0x15, 0xc0, 0xf8, 0xa8, 0x23, 0xa4, 0x83, 0x4a, 0xed, 0xc5, 0xfd, 0x01, 0xfa, 0xfa,
+	0xbd, 0xa5, 0x34, 0x5d, 0x1d, 0xa8, 0xac, 0xf9, 0x30, 0xce, 0x8b, 0x77, 0x2b, 0x01, 0x53, 0x74,
+	0xc6, 0x12, 0x93, 0x36, 0x79, 0x76, 0x7f, 0xb3, 0xe0, 0x7f, 0x2b, 0x7e, 0xe2, 0x12, 0xdf, 0x07,
+	0xd0, 0x11, 0x25, 0xa4, 0xb3, 0x81, 0x4d, 0xf8, 0x4e, 0xb9, 0x09, 0x2f, 0x3b, 0xf4, 0x2a, 0x77,
+	0x64, 0x9e, 0x05, 0x4b, 0x54, 0x8b, 0xad, 0x29, 0xf7, 0x82, 0x25, 0xd8, 0x60, 0x77, 0x00, 0xd0,
+	0xa4, 0x7a, 0x87, 0xe2, 0xcd, 0x96, 0x46, 0x54, 0xb8, 0x3f, 0x5b, 0x40, 0x96, 0xdd, 0xbf, 0xf2,
+	0x00, 0x5b, 0x55, 0xb9, 0x45, 0x83, 0xd8, 0x2c, 0x37, 0x08, 0x53, 0x9e, 0xf5, 0x52, 0x79, 0x2e,
+	0x4d, 0x90, 0xc6, 0x8a, 0x09, 0xe2, 0x3e, 0x81, 0xad, 0xd2, 0x80, 0xd6, 0xb9, 0xda, 0x83, 0x3a,
+	0xb6, 0x37, 0x7c, 0x61, 0x7b, 0xe8, 0x94, 0xe9, 0x2a, 0xef, 0x35, 0x9e, 0x82, 0xb9, 0x2f, 0x2d,
+	0x80, 0xb9, 0x97, 0xcb, 0x36, 0x99, 0x22, 0x46, 0xb3, 0xa1, 0x14, 0xf1, 0xd4, 0xca, 0xf1, 0x6c,
+	0x83, 0x8d, 0x07, 0x3f, 0x98, 0x9e, 0xe8, 0x48, 0x5b, 0xa8, 0x78, 0x3c, 0x3d, 0x91, 0xd9, 0x3e,
+	0x67, 0x9c, 0xd3, 0x54, 0xc6, 0x2b, 0x5b, 0x95, 0x11, 0x71, 0xcd, 0xaa, 0xee, 0x27, 0xa6, 0x87,
+	0xbc, 0xdc, 0x80, 0xeb, 0x0b, 0x26, 0xe9, 0x27, 0xcc, 0xd3, 0x94, 0x72, 0xf5, 0x4e, 0xcb, 0x33,
+	0xa2, 0x7c, 0x28, 0xa7, 0x33, 0xa1, 0x9b, 0x30, 0x9e, 0xe5, 0xa0, 0xa0, 0x99, 0x60, 0x51, 0x20,
+	0xa8, 0x1f, 0x31, 0xae, 0xdf, 0xdb, 0x36, 0xba, 0x67, 0x8c, 0x57, 0x21, 0xc1, 0xcc, 0xd9, 0x5c,
+	0x80, 0x04, 0x33, 0xf2, 0x36, 0x6c, 0x15, 0x10, 0x3a, 0x4b, 0x68, 0x28, 0xe8, 0x58, 0x67, 0xad,
+	0x67, 0x0c, 0x9f, 0x6a, 0x3d, 0x79, 0x07, 0xc8, 0x39, 0xe3, 0xe3, 0xf8, 0xdc, 0x2f, 0xcf, 0x1f,
+	0x95, 0xc6, 0x9e, 0xb2, 0x1c, 0xcc, 0xa7, 0xd0, 0x3d, 0xe8, 0x6a, 0x34, 0xcf, 0xa3, 0x11, 0x4d,
+	0xb1, 0x27, 0xd5, 0xbc, 0x8e, 0x52, 0x7e, 0x8d, 0xba, 0xe1, 0x5f, 0x96, 0xde, 0x6c, 0x0f, 0x69,
+	0x3a, 0x65, 0x21, 0x25, 0x1f, 0x83, 0x5d, 0x6c, 0xab, 0xa4, 0xb2, 0xd3, 0x2c, 0x2e, 0xb1, 0xfd,
+	0xad, 0xa5, 0x32, 0x20, 0x0f, 0xcd, 0xf2, 0xba, 0xb6, 0x44, 0x56, 0xdd, 0xfa, 0x02, 0xae, 0x2f,
+	0x6c, 0xc3, 0xc4, 0x2d, 0xa3, 0x56, 0xaf, 0xca, 0x2b, 0x3c, 0x3d, 0xb0, 0x86, 0x2f, 0x37, 0x2a,
+	0x7b, 0xa4, 0x09, 0xeb, 0xb3, 0xea, 0xca, 0x78, 0x67, 0xcd, 0xd6, 0x65, 0x5c, 0xdf, 0x5c, 0x63,
+	0x27, 0x5f, 0x2d, 0x8d, 0xae, 0xab, 0x5c, 0xf5, 0xcb, 0xf6, 0x85, 0xbb, 0xdf, 0x41, 0x6f, 0x71,
+	0xc8, 0x91, 0x7b, 0x2b, 0x23, 0xaf, 0x8e, 0xc0, 0x7e, 0xe5, 0x47, 0x97, 0x67, 0xe1, 0x03, 0x6b,
+	0xf8, 0xeb, 0x7c, 0x5a, 0x18, 0x0e, 0x1e, 0x43, 0xd3, 0x0c, 0x8e, 0xfe, 0x8a, 0x86, 0xb7, 0xf2,
+	0xc1, 0x0b, 0x03, 0xe7, 0x5b, 0xe8, 0x54, 0x1a, 0xed, 0x1b, 0x97, 0x37, 0xce, 0xc2, 0xe7, 0xdd,
+	0x2b, 0x70, 0xc3, 0x5f, 0x2c, 0xe8, 0xe0, 0xf7, 0x68, 0x1e, 0xfb, 0xa4, 0xd2, 0x41, 0x76, 0xd6,
+	0xfc, 0x81, 0xd0, 0xee, 0xd7, 0xfc, 0xbf, 0x20, 0xde, 0xf2, 0x47, 0x5e, 0x2d, 0xac, 0x95, 0xcd,
+	0xa1, 0xbf, 0x7d, 0x09, 0xe6, 0xe0, 0x83, 0xef, 0xdf, 0x3f, 0x61, 0xe2, 0x34, 0x1f, 0xed, 0x85,
+	0x71, 0xb4, 0x3f, 0xa6, 0x61, 0x4a, 0xc7, 0xfb, 0x1a, 0xbf, 0x3f, 0x7d, 0x6f, 0x3f, 0x4d, 0xc2,
+	0xfd, 0xf9, 0xf5, 0x8f, 0xe6, 0xc7, 0x51, 0x03, 0xff, 0x57, 0xbe, 0xfb, 0xdf, 0x00, 0x96, 0xcb,
+	0x1e, 0x40, 0x68, 0x0e, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// BlockServiceClient is the client API for BlockService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type BlockServiceClient interface {
+	// BestBlock returns the summary of the best block.
+	BestBlock(ctx context.Context, in *BestBlockRequest, opts ...grpc.CallOption) (*Block, error)
+	// Block returns the summary of a main chain block.
+	Block(ctx context.Context, in *BlockRequest, opts ...grpc.CallOption) (*Block, error)
+	// SubscribeBlocks streams the summary of each new block.
+	SubscribeBlocks(ctx context.Context, in *SubscribeBlocksRequest, opts ...grpc.CallOption) (BlockService_SubscribeBlocksClient, error)
+}
+
+type blockServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewBlockServiceClient(cc *grpc.ClientConn) BlockServiceClient {
+	return &blockServiceClient{cc}
+}
+
+func (c *blockServiceClient) BestBlock(ctx context.Context, in *BestBlockRequest, opts ...grpc.CallOption) (*Block, error) {
+	out := new(Block)
+	err := c.cc.Invoke(ctx, "/dcrdatarpc.BlockService/BestBlock", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blockServiceClient) Block(ctx context.Context, in *BlockRequest, opts ...grpc.CallOption) (*Block, error) {
+	out := new(Block)
+	err := c.cc.Invoke(ctx, "/dcrdatarpc.BlockService/Block", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blockServiceClient) SubscribeBlocks(ctx context.Context, in *SubscribeBlocksRequest, opts ...grpc.CallOption) (BlockService_SubscribeBlocksClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_BlockService_serviceDesc.Streams[0], "/dcrdatarpc.BlockService/SubscribeBlocks", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &blockServiceSubscribeBlocksClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type BlockService_SubscribeBlocksClient interface {
+	Recv() (*Block, error)
+	grpc.ClientStream
+}
+
+type blockServiceSubscribeBlocksClient struct {
+	grpc.ClientStream
+}
+
+func (x *blockServiceSubscribeBlocksClient) Recv() (*Block, error) {
+	m := new(Block)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// BlockServiceServer is the server API for BlockService service.
+type BlockServiceServer interface {
+	// BestBlock returns the summary of the best block.
+	BestBlock(context.Context, *BestBlockRequest) (*Block, error)
+	// Block returns the summary of a main chain block.
+	Block(context.Context, *BlockRequest) (*Block, error)
+	// SubscribeBlocks streams the summary of each new block.
+	SubscribeBlocks(*SubscribeBlocksRequest, BlockService_SubscribeBlocksServer) error
+}
+
+// UnimplementedBlockServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedBlockServiceServer struct {
+}
+
+func (*UnimplementedBlockServiceServer) BestBlock(ctx context.Context, req *BestBlockRequest) (*Block, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BestBlock not implemented")
+}
+func (*UnimplementedBlockServiceServer) Block(ctx context.Context, req *BlockRequest) (*Block, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Block not implemented")
+}
+func (*UnimplementedBlockServiceServer) SubscribeBlocks(req *SubscribeBlocksRequest, srv BlockService_SubscribeBlocksServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeBlocks not implemented")
+}
+
+func RegisterBlockServiceServer(s *grpc.Server, srv BlockServiceServer) {
+	s.RegisterService(&_BlockService_serviceDesc, srv)
+}
+
+func _BlockService_BestBlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BestBlockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlockServiceServer).BestBlock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dcrdatarpc.BlockService/BestBlock",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlockServiceServer).BestBlock(ctx, req.(*BestBlockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BlockService_Block_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BlockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlockServiceServer).Block(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dcrdatarpc.BlockService/Block",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlockServiceServer).Block(ctx, req.(*BlockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BlockService_SubscribeBlocks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeBlocksRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BlockServiceServer).SubscribeBlocks(m, &blockServiceSubscribeBlocksServer{stream})
+}
+
+type BlockService_SubscribeBlocksServer interface {
+	Send(*Block) error
+	grpc.ServerStream
+}
+
+type blockServiceSubscribeBlocksServer struct {
+	grpc.ServerStream
+}
+
+func (x *blockServiceSubscribeBlocksServer) Send(m *Block) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _BlockService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "dcrdatarpc.BlockService",
+	HandlerType: (*BlockServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "BestBlock",
+			Handler:    _BlockService_BestBlock_Handler,
+		},
+		{
+			MethodName: "Block",
+			Handler:    _BlockService_Block_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubscribeBlocks",
+			Handler:       _BlockService_SubscribeBlocks_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "dcrdata.proto",
+}
+
+// TransactionServiceClient is the client API for TransactionService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type TransactionServiceClient interface {
+	// Transaction returns a decoded transaction.
+	Transaction(ctx context.Context, in *TransactionRequest, opts ...grpc.CallOption) (*Transaction, error)
+	// RawTransaction returns a serialized transaction.
+	RawTransaction(ctx context.Context, in *TransactionRequest, opts ...grpc.CallOption) (*RawTransaction, error)
+	// SubscribeMempool streams each transaction that enters the mempool.
+	SubscribeMempool(ctx context.Context, in *SubscribeMempoolRequest, opts ...grpc.CallOption) (TransactionService_SubscribeMempoolClient, error)
+}
+
+type transactionServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewTransactionServiceClient(cc *grpc.ClientConn) TransactionServiceClient {
+	return &transactionServiceClient{cc}
+}
+
+func (c *transactionServiceClient) Transaction(ctx context.Context, in *TransactionRequest, opts ...grpc.CallOption) (*Transaction, error) {
+	out := new(Transaction)
+	err := c.cc.Invoke(ctx, "/dcrdatarpc.TransactionService/Transaction", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *transactionServiceClient) RawTransaction(ctx context.Context, in *TransactionRequest, opts ...grpc.CallOption) (*RawTransaction, error) {
+	out := new(RawTransaction)
+	err := c.cc.Invoke(ctx, "/dcrdatarpc.TransactionService/RawTransaction", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *transactionServiceClient) SubscribeMempool(ctx context.Context, in *SubscribeMempoolRequest, opts ...grpc.CallOption) (TransactionService_SubscribeMempoolClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_TransactionService_serviceDesc.Streams[0], "/dcrdatarpc.TransactionService/SubscribeMempool", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &transactionServiceSubscribeMempoolClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TransactionService_SubscribeMempoolClient interface {
+	Recv() (*MempoolTransaction, error)
+	grpc.ClientStream
+}
+
+type transactionServiceSubscribeMempoolClient struct {
+	grpc.ClientStream
+}
+
+func (x *transactionServiceSubscribeMempoolClient) Recv() (*MempoolTransaction, error) {
+	m := new(MempoolTransaction)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// TransactionServiceServer is the server API for TransactionService service.
+type TransactionServiceServer interface {
+	// Transaction returns a decoded transaction.
+	Transaction(context.Context, *TransactionRequest) (*Transaction, error)
+	// RawTransaction returns a serialized transaction.
+	RawTransaction(context.Context, *TransactionRequest) (*RawTransaction, error)
+	// SubscribeMempool streams each transaction that enters the mempool.
+	SubscribeMempool(*SubscribeMempoolRequest, TransactionService_SubscribeMempoolServer) error
+}
+
+// UnimplementedTransactionServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedTransactionServiceServer struct {
+}
+
+func (*UnimplementedTransactionServiceServer) Transaction(ctx context.Context, req *TransactionRequest) (*Transaction, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Transaction not implemented")
+}
+func (*UnimplementedTransactionServiceServer) RawTransaction(ctx context.Context, req *TransactionRequest) (*RawTransaction, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RawTransaction not implemented")
+}
+func (*UnimplementedTransactionServiceServer) SubscribeMempool(req *SubscribeMempoolRequest, srv TransactionService_SubscribeMempoolServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeMempool not implemented")
+}
+
+func RegisterTransactionServiceServer(s *grpc.Server, srv TransactionServiceServer) {
+	s.RegisterService(&_TransactionService_serviceDesc, srv)
+}
+
+func _TransactionService_Transaction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransactionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TransactionServiceServer).Transaction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dcrdatarpc.TransactionService/Transaction",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TransactionServiceServer).Transaction(ctx, req.(*TransactionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TransactionService_RawTransaction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransactionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TransactionServiceServer).RawTransaction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dcrdatarpc.TransactionService/RawTransaction",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TransactionServiceServer).RawTransaction(ctx, req.(*TransactionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TransactionService_SubscribeMempool_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeMempoolRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TransactionServiceServer).SubscribeMempool(m, &transactionServiceSubscribeMempoolServer{stream})
+}
+
+type TransactionService_SubscribeMempoolServer interface {
+	Send(*MempoolTransaction) error
+	grpc.ServerStream
+}
+
+type transactionServiceSubscribeMempoolServer struct {
+	grpc.ServerStream
+}
+
+func (x *transactionServiceSubscribeMempoolServer) Send(m *MempoolTransaction) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _TransactionService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "dcrdatarpc.TransactionService",
+	HandlerType: (*TransactionServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Transaction",
+			Handler:    _TransactionService_Transaction_Handler,
+		},
+		{
+			MethodName: "RawTransaction",
+			Handler:    _TransactionService_RawTransaction_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubscribeMempool",
+			Handler:       _TransactionService_SubscribeMempool_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "dcrdata.proto",
+}
+
+// AddressServiceClient is the client API for AddressService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type AddressServiceClient interface {
+	// Balance returns the balance of an address.
+	Balance(ctx context.Context, in *AddressRequest, opts ...grpc.CallOption) (*AddressBalance, error)
+	// Transactions returns a page of the transactions of an address, newest
+	// first.
+	Transactions(ctx context.Context, in *AddressTransactionsRequest, opts ...grpc.CallOption) (*AddressTransactions, error)
+}
+
+type addressServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewAddressServiceClient(cc *grpc.ClientConn) AddressServiceClient {
+	return &addressServiceClient{cc}
+}
+
+func (c *addressServiceClient) Balance(ctx context.Context, in *AddressRequest, opts ...grpc.CallOption) (*AddressBalance, error) {
+	out := new(AddressBalance)
+	err := c.cc.Invoke(ctx, "/dcrdatarpc.AddressService/Balance", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *addressServiceClient) Transactions(ctx context.Context, in *AddressTransactionsRequest, opts ...grpc.CallOption) (*AddressTransactions, error) {
+	out := new(AddressTransactions)
+	err := c.cc.Invoke(ctx, "/dcrdatarpc.AddressService/Transactions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AddressServiceServer is the server API for AddressService service.
+type AddressServiceServer interface {
+	// Balance returns the balance of an address.
+	Balance(context.Context, *AddressRequest) (*AddressBalance, error)
+	// Transactions returns a page of the transactions of an address, newest
+	// first.
+	Transactions(context.Context, *AddressTransactionsRequest) (*AddressTransactions, error)
+}
+
+// UnimplementedAddressServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedAddressServiceServer struct {
+}
+
+func (*UnimplementedAddressServiceServer) Balance(ctx context.Context, req *AddressRequest) (*AddressBalance, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Balance not implemented")
+}
+func (*UnimplementedAddressServiceServer) Transactions(ctx context.Context, req *AddressTransactionsRequest) (*AddressTransactions, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Transactions not implemented")
+}
+
+func RegisterAddressServiceServer(s *grpc.Server, srv AddressServiceServer) {
+	s.RegisterService(&_AddressService_serviceDesc, srv)
+}
+
+func _AddressService_Balance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddressRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AddressServiceServer).Balance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dcrdatarpc.AddressService/Balance",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AddressServiceServer).Balance(ctx, req.(*AddressRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AddressService_Transactions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddressTransactionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AddressServiceServer).Transactions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dcrdatarpc.AddressService/Transactions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AddressServiceServer).Transactions(ctx, req.(*AddressTransactionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _AddressService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "dcrdatarpc.AddressService",
+	HandlerType: (*AddressServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Balance",
+			Handler:    _AddressService_Balance_Handler,
+		},
+		{
+			MethodName: "Transactions",
+			Handler:    _AddressService_Transactions_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "dcrdata.proto",
+}
+
+// StakeServiceClient is the client API for StakeService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type StakeServiceClient interface {
+	// TicketPool returns the ticket pool info of a block.
+	TicketPool(ctx context.Context, in *TicketPoolRequest, opts ...grpc.CallOption) (*TicketPool, error)
+	// StakeDifficulty returns the current and next ticket price, and the
+	// estimates of the next price.
+	StakeDifficulty(ctx context.Context, in *StakeDifficultyRequest, opts ...grpc.CallOption) (*StakeDifficulty, error)
+}
+
+type stakeServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewStakeServiceClient(cc *grpc.ClientConn) StakeServiceClient {
+	return &stakeServiceClient{cc}
+}
+
+func (c *stakeServiceClient) TicketPool(ctx context.Context, in *TicketPoolRequest, opts ...grpc.CallOption) (*TicketPool, error) {
+	out := new(TicketPool)
+	err := c.cc.Invoke(ctx, "/dcrdatarpc.StakeService/TicketPool", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *stakeServiceClient) StakeDifficulty(ctx context.Context, in *StakeDifficultyRequest, opts ...grpc.CallOption) (*StakeDifficulty, error) {
+	out := new(StakeDifficulty)
+	err := c.cc.Invoke(ctx, "/dcrdatarpc.StakeService/StakeDifficulty", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// StakeServiceServer is the server API for StakeService service.
+type StakeServiceServer interface {
+	// TicketPool returns the ticket pool info of a block.
+	TicketPool(context.Context, *TicketPoolRequest) (*TicketPool, error)
+	// StakeDifficulty returns the current and next ticket price, and the
+	// estimates of the next price.
+	StakeDifficulty(context.Context, *StakeDifficultyRequest) (*StakeDifficulty, error)
+}
+
+// UnimplementedStakeServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedStakeServiceServer struct {
+}
+
+func (*UnimplementedStakeServiceServer) TicketPool(ctx context.Context, req *TicketPoolRequest) (*TicketPool, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TicketPool not implemented")
+}
+func (*UnimplementedStakeServiceServer) StakeDifficulty(ctx context.Context, req *StakeDifficultyRequest) (*StakeDifficulty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StakeDifficulty not implemented")
+}
+
+func RegisterStakeServiceServer(s *grpc.Server, srv StakeServiceServer) {
+	s.RegisterService(&_StakeService_serviceDesc, srv)
+}
+
+func _StakeService_TicketPool_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TicketPoolRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StakeServiceServer).TicketPool(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dcrdatarpc.StakeService/TicketPool",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StakeServiceServer).TicketPool(ctx, req.(*TicketPoolRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StakeService_StakeDifficulty_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StakeDifficultyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StakeServiceServer).StakeDifficulty(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dcrdatarpc.StakeService/StakeDifficulty",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StakeServiceServer).StakeDifficulty(ctx, req.(*StakeDifficultyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _StakeService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "dcrdatarpc.StakeService",
+	HandlerType: (*StakeServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "TicketPool",
+			Handler:    _StakeService_TicketPool_Handler,
+		},
+		{
+			MethodName: "StakeDifficulty",
+			Handler:    _StakeService_StakeDifficulty_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "dcrdata.proto",
+}
@@ -0,0 +1,4 @@
+#!/bin/sh
+
+# Requires protoc-gen-go v1.3.2, which generates code for grpc-go v1.24.
+protoc --go_out=plugins=grpc,paths=source_relative:. dcrdata.proto
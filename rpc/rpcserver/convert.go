@@ -0,0 +1,122 @@
+// Copyright (c) 2020, The Decred developers
+// See LICENSE for details.
+
+package rpcserver
+
+import (
+	apitypes "github.com/decred/dcrdata/api/types/v5"
+	exptypes "github.com/decred/dcrdata/explorer/types/v2"
+	"github.com/decred/dcrdata/v5/rpc/dcrdatarpc"
+)
+
+func blockSummary(b *apitypes.BlockDataBasic) *dcrdatarpc.Block {
+	block := &dcrdatarpc.Block{
+		Height:          b.Height,
+		Hash:            b.Hash,
+		Time:            b.Time.S.UNIX(),
+		Size:            b.Size,
+		NumTx:           b.NumTx,
+		Difficulty:      b.Difficulty,
+		StakeDifficulty: b.StakeDiff,
+	}
+	if b.PoolInfo != nil {
+		block.TicketPool = ticketPool(b.PoolInfo)
+	}
+	return block
+}
+
+func ticketPool(info *apitypes.TicketPoolInfo) *dcrdatarpc.TicketPool {
+	return &dcrdatarpc.TicketPool{
+		Height:   info.Height,
+		Size:     info.Size,
+		Value:    info.Value,
+		ValueAvg: info.ValAvg,
+		Winners:  info.Winners,
+	}
+}
+
+func transaction(tx *apitypes.Tx) *dcrdatarpc.Transaction {
+	rpcTx := &dcrdatarpc.Transaction{
+		Txid:          tx.TxID,
+		Size:          tx.Size,
+		Version:       tx.Version,
+		Locktime:      tx.Locktime,
+		Expiry:        tx.Expiry,
+		Inputs:        make([]*dcrdatarpc.TransactionInput, 0, len(tx.Vin)),
+		Outputs:       make([]*dcrdatarpc.TransactionOutput, 0, len(tx.Vout)),
+		Confirmations: tx.Confirmations,
+	}
+	if tx.Block != nil {
+		rpcTx.BlockHash = tx.Block.BlockHash
+		rpcTx.BlockHeight = tx.Block.BlockHeight
+		rpcTx.BlockTime = tx.Block.BlockTime
+	}
+	for i := range tx.Vin {
+		vin := &tx.Vin[i]
+		in := &dcrdatarpc.TransactionInput{
+			PrevTxid:    vin.Txid,
+			PrevIndex:   vin.Vout,
+			PrevTree:    int32(vin.Tree),
+			Coinbase:    vin.Coinbase,
+			Stakebase:   vin.Stakebase,
+			Sequence:    vin.Sequence,
+			AmountIn:    vin.AmountIn,
+			BlockHeight: vin.BlockHeight,
+			BlockIndex:  vin.BlockIndex,
+		}
+		if vin.ScriptSig != nil {
+			in.ScriptSig = vin.ScriptSig.Hex
+		}
+		rpcTx.Inputs = append(rpcTx.Inputs, in)
+	}
+	for i := range tx.Vout {
+		vout := &tx.Vout[i]
+		out := &dcrdatarpc.TransactionOutput{
+			Index:        vout.N,
+			Value:        vout.Value,
+			Version:      uint32(vout.Version),
+			ScriptType:   vout.ScriptPubKeyDecoded.Type,
+			ScriptPubKey: vout.ScriptPubKeyDecoded.Hex,
+			Addresses:    vout.ScriptPubKeyDecoded.Addresses,
+		}
+		if vout.Spend != nil {
+			out.SpendTxid = vout.Spend.Hash
+			out.SpendIndex = vout.Spend.Index
+		}
+		rpcTx.Outputs = append(rpcTx.Outputs, out)
+	}
+	return rpcTx
+}
+
+func mempoolTransaction(tx *exptypes.MempoolTx) *dcrdatarpc.MempoolTransaction {
+	return &dcrdatarpc.MempoolTransaction{
+		Txid:     tx.TxID,
+		Type:     tx.Type,
+		Time:     tx.Time,
+		Size:     tx.Size,
+		TotalOut: tx.TotalOut,
+		Fees:     tx.Fees,
+		FeeRate:  tx.FeeRate,
+		Expiry:   tx.Expiry,
+	}
+}
+
+func addressTransactions(addr *apitypes.Address) *dcrdatarpc.AddressTransactions {
+	txs := &dcrdatarpc.AddressTransactions{
+		Address:      addr.Address,
+		Transactions: make([]*dcrdatarpc.AddressTransaction, 0, len(addr.Transactions)),
+		TipHash:      addr.TipHash,
+		TipHeight:    addr.TipHeight,
+	}
+	for _, tx := range addr.Transactions {
+		txs.Transactions = append(txs.Transactions, &dcrdatarpc.AddressTransaction{
+			Txid:          tx.TxID,
+			Size:          tx.Size,
+			Time:          tx.Time.S.UNIX(),
+			Value:         tx.Value,
+			Fees:          tx.Fees,
+			Confirmations: tx.Confirmations,
+		})
+	}
+	return txs
+}
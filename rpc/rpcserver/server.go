@@ -0,0 +1,428 @@
+// Copyright (c) 2020, The Decred developers
+// See LICENSE for details.
+
+// Package rpcserver implements the dcrdatarpc gRPC services with a
+// chainstore.ChainStore, for backend consumers that prefer typed contracts to
+// the JSON of the REST API. New blocks and mempool transactions are streamed to
+// subscribers.
+package rpcserver
+
+import (
+	"context"
+	"net"
+	"sync"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/chaincfg/v2"
+	"github.com/decred/dcrd/wire"
+	"github.com/decred/dcrdata/blockdata/v5"
+	"github.com/decred/dcrdata/db/dbtypes/v2"
+	exptypes "github.com/decred/dcrdata/explorer/types/v2"
+	pstypes "github.com/decred/dcrdata/pubsub/types/v3"
+	"github.com/decred/dcrdata/txhelpers/v4"
+	"github.com/decred/dcrdata/v5/chainstore"
+	"github.com/decred/dcrdata/v5/rpc/dcrdatarpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// subscriberQueueSize is the number of messages queued for a subscriber
+	// before it is considered to have fallen behind and its stream is ended.
+	subscriberQueueSize = 64
+
+	// defaultAddressTxCount and maxAddressTxCount are the default and maximum
+	// number of transactions in a page of an address' transactions, as for
+	// the REST API.
+	defaultAddressTxCount = 10
+	maxAddressTxCount     = 8000
+
+	// apiKeyMetadata is the metadata key of a client's API key, as sent in
+	// the X-API-Key header to the REST API.
+	apiKeyMetadata = "x-api-key"
+)
+
+// errFellBehind ends the stream of a subscriber whose queue is full.
+var errFellBehind = status.Error(codes.ResourceExhausted, "subscriber fell behind")
+
+// RateLimiter decides whether a client's request is allowed. The client is
+// identified by its API key if it is a known one, and otherwise by its remote
+// address. *middleware.RateLimiter is a RateLimiter.
+type RateLimiter interface {
+	Allow(key, remoteAddr string) bool
+}
+
+// Config is the configuration of a Server. The TLS certificate and key files
+// are both optional, and the server does not use TLS if they are not set. The
+// RateLimiter is also optional, and calls and new streams are charged to it
+// if it is set.
+type Config struct {
+	Store       chainstore.ChainStore
+	Params      *chaincfg.Params
+	CertFile    string
+	KeyFile     string
+	RateLimiter RateLimiter
+}
+
+// Server serves the dcrdatarpc BlockService, TransactionService,
+// AddressService and StakeService. It implements blockdata.BlockDataSaver to
+// stream new blocks, and receives new mempool transactions on the channel
+// returned by MempoolSignal.
+type Server struct {
+	store         chainstore.ChainStore
+	params        *chaincfg.Params
+	grpc          *grpc.Server
+	mempoolSignal chan pstypes.HubMessage
+
+	mtx         sync.Mutex
+	blockSubs   map[chan *dcrdatarpc.Block]struct{}
+	mempoolSubs map[chan *dcrdatarpc.MempoolTransaction]struct{}
+}
+
+// NewServer creates a Server with the services registered.
+func NewServer(cfg *Config) (*Server, error) {
+	var opts []grpc.ServerOption
+	if cfg.CertFile != "" || cfg.KeyFile != "" {
+		creds, err := credentials.NewServerTLSFromFile(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, grpc.Creds(creds))
+	}
+	if rl := cfg.RateLimiter; rl != nil {
+		opts = append(opts,
+			grpc.UnaryInterceptor(func(ctx context.Context, req interface{},
+				_ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+				if err := allow(ctx, rl); err != nil {
+					return nil, err
+				}
+				return handler(ctx, req)
+			}),
+			grpc.StreamInterceptor(func(srv interface{}, ss grpc.ServerStream,
+				_ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+				if err := allow(ss.Context(), rl); err != nil {
+					return err
+				}
+				return handler(srv, ss)
+			}))
+	}
+
+	s := &Server{
+		store:         cfg.Store,
+		params:        cfg.Params,
+		grpc:          grpc.NewServer(opts...),
+		mempoolSignal: make(chan pstypes.HubMessage, subscriberQueueSize),
+		blockSubs:     make(map[chan *dcrdatarpc.Block]struct{}),
+		mempoolSubs:   make(map[chan *dcrdatarpc.MempoolTransaction]struct{}),
+	}
+	dcrdatarpc.RegisterBlockServiceServer(s.grpc, s)
+	dcrdatarpc.RegisterTransactionServiceServer(s.grpc, s)
+	dcrdatarpc.RegisterAddressServiceServer(s.grpc, s)
+	dcrdatarpc.RegisterStakeServiceServer(s.grpc, s)
+	return s, nil
+}
+
+// allow charges a call to the rate limiter, identifying the client by the API
+// key in the call's metadata and the peer address. It returns a
+// ResourceExhausted status error if the call is not allowed.
+func allow(ctx context.Context, rl RateLimiter) error {
+	var key, remoteAddr string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if keys := md.Get(apiKeyMetadata); len(keys) > 0 {
+			key = keys[0]
+		}
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		remoteAddr = p.Addr.String()
+	}
+	if !rl.Allow(key, remoteAddr) {
+		return status.Error(codes.ResourceExhausted, "rate limit exceeded")
+	}
+	return nil
+}
+
+// Serve serves the services on the listener and relays the mempool signals
+// until the context is canceled.
+func (s *Server) Serve(ctx context.Context, listener net.Listener) error {
+	go func() {
+		for {
+			select {
+			case msg := <-s.mempoolSignal:
+				if msg.Signal != pstypes.SigNewTx {
+					continue
+				}
+				if tx, ok := msg.Msg.(*exptypes.MempoolTx); ok {
+					s.publishMempoolTx(mempoolTransaction(tx))
+				}
+			case <-ctx.Done():
+				s.grpc.Stop()
+				return
+			}
+		}
+	}()
+	return s.grpc.Serve(listener)
+}
+
+// MempoolSignal returns the channel on which the mempool monitor signals new
+// transactions.
+func (s *Server) MempoolSignal() chan<- pstypes.HubMessage {
+	return s.mempoolSignal
+}
+
+// Store streams the summary of a new block to the SubscribeBlocks subscribers.
+func (s *Server) Store(blockData *blockdata.BlockData, msgBlock *wire.MsgBlock) error {
+	summary := blockData.ToBlockSummary()
+	block := blockSummary(&summary)
+	block.NumTx = uint32(len(msgBlock.Transactions) + len(msgBlock.STransactions))
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	for sub := range s.blockSubs {
+		select {
+		case sub <- block:
+		default:
+			log.Warnf("Ending the block stream of a subscriber that fell behind.")
+			delete(s.blockSubs, sub)
+			close(sub)
+		}
+	}
+	return nil
+}
+
+func (s *Server) publishMempoolTx(tx *dcrdatarpc.MempoolTransaction) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	for sub := range s.mempoolSubs {
+		select {
+		case sub <- tx:
+		default:
+			log.Warnf("Ending the mempool stream of a subscriber that fell behind.")
+			delete(s.mempoolSubs, sub)
+			close(sub)
+		}
+	}
+}
+
+// storeError converts an error from the ChainStore to a gRPC status error.
+func storeError(err error) error {
+	if dbtypes.IsTimeoutErr(err) {
+		return status.Error(codes.Unavailable, "database timeout")
+	}
+	log.Errorf("ChainStore error: %v", err)
+	return status.Error(codes.Internal, "internal error")
+}
+
+// BestBlock returns the summary of the best block.
+func (s *Server) BestBlock(_ context.Context, _ *dcrdatarpc.BestBlockRequest) (*dcrdatarpc.Block, error) {
+	summary := s.store.GetBestBlockSummary()
+	if summary == nil {
+		return nil, status.Error(codes.Unavailable, "best block unavailable")
+	}
+	return blockSummary(summary), nil
+}
+
+// Block returns the summary of the block with the requested hash, or the main
+// chain block at the requested height.
+func (s *Server) Block(_ context.Context, req *dcrdatarpc.BlockRequest) (*dcrdatarpc.Block, error) {
+	if req.Hash != "" {
+		if _, err := chainhash.NewHashFromStr(req.Hash); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid block hash")
+		}
+		summary := s.store.GetSummaryByHash(req.Hash, false)
+		if summary == nil {
+			return nil, status.Error(codes.NotFound, "block not found")
+		}
+		return blockSummary(summary), nil
+	}
+	if int64(req.Height) > s.store.Height() {
+		return nil, status.Error(codes.NotFound, "block not found")
+	}
+	summary := s.store.GetSummary(int(req.Height))
+	if summary == nil {
+		return nil, status.Error(codes.NotFound, "block not found")
+	}
+	return blockSummary(summary), nil
+}
+
+// SubscribeBlocks streams the summary of each new block until the client
+// cancels the stream or falls behind.
+func (s *Server) SubscribeBlocks(_ *dcrdatarpc.SubscribeBlocksRequest, stream dcrdatarpc.BlockService_SubscribeBlocksServer) error {
+	sub := make(chan *dcrdatarpc.Block, subscriberQueueSize)
+	s.mtx.Lock()
+	s.blockSubs[sub] = struct{}{}
+	s.mtx.Unlock()
+	defer func() {
+		s.mtx.Lock()
+		delete(s.blockSubs, sub)
+		s.mtx.Unlock()
+	}()
+
+	for {
+		select {
+		case block, ok := <-sub:
+			if !ok {
+				return errFellBehind
+			}
+			if err := stream.Send(block); err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return nil
+		}
+	}
+}
+
+// Transaction returns a decoded transaction.
+func (s *Server) Transaction(_ context.Context, req *dcrdatarpc.TransactionRequest) (*dcrdatarpc.Transaction, error) {
+	txid, err := chainhash.NewHashFromStr(req.Txid)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid transaction ID")
+	}
+	tx := s.store.GetRawAPITransaction(txid)
+	if tx == nil {
+		return nil, status.Error(codes.NotFound, "transaction not found")
+	}
+	return transaction(tx), nil
+}
+
+// RawTransaction returns a serialized transaction in hexadecimal.
+func (s *Server) RawTransaction(_ context.Context, req *dcrdatarpc.TransactionRequest) (*dcrdatarpc.RawTransaction, error) {
+	txid, err := chainhash.NewHashFromStr(req.Txid)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid transaction ID")
+	}
+	hex := s.store.GetTransactionHex(txid)
+	if hex == "" {
+		return nil, status.Error(codes.NotFound, "transaction not found")
+	}
+	return &dcrdatarpc.RawTransaction{Hex: hex}, nil
+}
+
+// SubscribeMempool streams each transaction that enters the mempool until the
+// client cancels the stream or falls behind.
+func (s *Server) SubscribeMempool(_ *dcrdatarpc.SubscribeMempoolRequest, stream dcrdatarpc.TransactionService_SubscribeMempoolServer) error {
+	sub := make(chan *dcrdatarpc.MempoolTransaction, subscriberQueueSize)
+	s.mtx.Lock()
+	s.mempoolSubs[sub] = struct{}{}
+	s.mtx.Unlock()
+	defer func() {
+		s.mtx.Lock()
+		delete(s.mempoolSubs, sub)
+		s.mtx.Unlock()
+	}()
+
+	for {
+		select {
+		case tx, ok := <-sub:
+			if !ok {
+				return errFellBehind
+			}
+			if err := stream.Send(tx); err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return nil
+		}
+	}
+}
+
+// validateAddress checks that an address is valid on the network.
+func (s *Server) validateAddress(address string) error {
+	_, _, addrErr := txhelpers.AddressValidation(address, s.params)
+	switch addrErr {
+	case txhelpers.AddressErrorNoError, txhelpers.AddressErrorZeroAddress:
+		return nil
+	}
+	return status.Errorf(codes.InvalidArgument, "invalid address: %v", addrErr)
+}
+
+// Balance returns the balance of an address.
+func (s *Server) Balance(ctx context.Context, req *dcrdatarpc.AddressRequest) (*dcrdatarpc.AddressBalance, error) {
+	if err := s.validateAddress(req.Address); err != nil {
+		return nil, err
+	}
+	bal, _, err := s.store.AddressBalance(ctx, req.Address)
+	if err != nil {
+		return nil, storeError(err)
+	}
+	return &dcrdatarpc.AddressBalance{
+		Address:      req.Address,
+		NumSpent:     bal.NumSpent,
+		NumUnspent:   bal.NumUnspent,
+		TotalSpent:   bal.TotalSpent,
+		TotalUnspent: bal.TotalUnspent,
+	}, nil
+}
+
+// Transactions returns a page of the transactions of an address, newest first.
+func (s *Server) Transactions(ctx context.Context, req *dcrdatarpc.AddressTransactionsRequest) (*dcrdatarpc.AddressTransactions, error) {
+	if err := s.validateAddress(req.Address); err != nil {
+		return nil, err
+	}
+	count, skip := req.Count, req.Skip
+	if count <= 0 {
+		count = defaultAddressTxCount
+	} else if count > maxAddressTxCount {
+		count = maxAddressTxCount
+	}
+	if skip < 0 {
+		skip = 0
+	}
+
+	addr, _, err := s.store.AddressTransactionDetails(ctx, req.Address, count, skip, dbtypes.AddrTxnAll)
+	if err != nil {
+		return nil, storeError(err)
+	}
+	return addressTransactions(addr), nil
+}
+
+// TicketPool returns the ticket pool info of the requested block, or of the
+// best block if none is requested.
+func (s *Server) TicketPool(_ context.Context, req *dcrdatarpc.TicketPoolRequest) (*dcrdatarpc.TicketPool, error) {
+	var height int64
+	switch {
+	case req.Block == nil:
+		height = s.store.Height()
+	case req.Block.Hash != "":
+		if _, err := chainhash.NewHashFromStr(req.Block.Hash); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid block hash")
+		}
+		info := s.store.GetPoolInfoByHash(req.Block.Hash)
+		if info == nil {
+			return nil, status.Error(codes.NotFound, "block not found")
+		}
+		return ticketPool(info), nil
+	default:
+		height = int64(req.Block.Height)
+	}
+	if height > s.store.Height() {
+		return nil, status.Error(codes.NotFound, "block not found")
+	}
+	info := s.store.GetPoolInfo(int(height))
+	if info == nil {
+		return nil, status.Error(codes.NotFound, "block not found")
+	}
+	return ticketPool(info), nil
+}
+
+// StakeDifficulty returns the current and next ticket price, and the
+// estimates of the next window's price.
+func (s *Server) StakeDifficulty(_ context.Context, _ *dcrdatarpc.StakeDifficultyRequest) (*dcrdatarpc.StakeDifficulty, error) {
+	sd := s.store.GetStakeDiffEstimates()
+	if sd == nil {
+		return nil, status.Error(codes.Unavailable, "stake difficulty unavailable")
+	}
+	return &dcrdatarpc.StakeDifficulty{
+		Current:          sd.CurrentStakeDifficulty,
+		Next:             sd.NextStakeDifficulty,
+		EstimateMin:      sd.Estimates.Min,
+		EstimateMax:      sd.Estimates.Max,
+		EstimateExpected: sd.Estimates.Expected,
+		WindowBlockIndex: int64(sd.IdxBlockInWindow),
+		WindowNumber:     int64(sd.PriceWindowNum),
+	}, nil
+}
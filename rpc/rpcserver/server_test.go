@@ -0,0 +1,261 @@
+// Copyright (c) 2020, The Decred developers
+// See LICENSE for details.
+
+package rpcserver
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/chaincfg/v2"
+	chainjson "github.com/decred/dcrd/rpc/jsonrpc/types/v2"
+	"github.com/decred/dcrd/wire"
+	apitypes "github.com/decred/dcrdata/api/types/v5"
+	"github.com/decred/dcrdata/blockdata/v5"
+	"github.com/decred/dcrdata/db/dbtypes/v2"
+	exptypes "github.com/decred/dcrdata/explorer/types/v2"
+	pstypes "github.com/decred/dcrdata/pubsub/types/v3"
+	"github.com/decred/dcrdata/v5/chainstore"
+	"github.com/decred/dcrdata/v5/rpc/dcrdatarpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const testTxid = "2e7a8b8f2e9ab3e8b5b4ef34e8fba0dc5ab1d2d7ff8a88bba9ac61bbc9ab4e2c"
+
+type storeStub struct {
+	chainstore.ChainStore
+	height int64
+}
+
+func (s *storeStub) Height() int64 {
+	return s.height
+}
+
+func (s *storeStub) GetSummary(idx int) *apitypes.BlockDataBasic {
+	return &apitypes.BlockDataBasic{
+		Height:   uint32(idx),
+		Hash:     "abcd",
+		Time:     apitypes.TimeAPI{S: dbtypes.NewTimeDefFromUNIX(1576000000)},
+		PoolInfo: &apitypes.TicketPoolInfo{Height: uint32(idx), Size: 40960},
+	}
+}
+
+func (s *storeStub) GetBestBlockSummary() *apitypes.BlockDataBasic {
+	return s.GetSummary(int(s.height))
+}
+
+func (s *storeStub) GetPoolInfo(idx int) *apitypes.TicketPoolInfo {
+	return s.GetSummary(idx).PoolInfo
+}
+
+func (s *storeStub) GetRawAPITransaction(txid *chainhash.Hash) *apitypes.Tx {
+	if txid.String() != testTxid {
+		return nil
+	}
+	tx := &apitypes.Tx{
+		Confirmations: 3,
+		Block:         &apitypes.BlockID{BlockHash: "abcd", BlockHeight: 98},
+	}
+	tx.TxID = testTxid
+	tx.Vin = []chainjson.Vin{{Coinbase: "00"}}
+	tx.Vout = []apitypes.Vout{{Value: 1.5, N: 0, Spend: &apitypes.TxInputID{Hash: "ef", Index: 2}}}
+	return tx
+}
+
+func (s *storeStub) AddressBalance(_ context.Context, address string) (*dbtypes.AddressBalance, bool, error) {
+	return &dbtypes.AddressBalance{Address: address, NumUnspent: 2, TotalUnspent: 300}, false, nil
+}
+
+func TestServer(t *testing.T) {
+	srv, err := NewServer(&Config{
+		Store:  &storeStub{height: 100},
+		Params: chaincfg.MainNetParams(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	listener := bufconn.Listen(1 << 20)
+	go srv.Serve(ctx, listener)
+
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithInsecure(),
+		grpc.WithDialer(func(string, time.Duration) (net.Conn, error) {
+			return listener.Dial()
+		}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	blocks := dcrdatarpc.NewBlockServiceClient(conn)
+	txns := dcrdatarpc.NewTransactionServiceClient(conn)
+	addrs := dcrdatarpc.NewAddressServiceClient(conn)
+	stake := dcrdatarpc.NewStakeServiceClient(conn)
+
+	best, err := blocks.BestBlock(ctx, &dcrdatarpc.BestBlockRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if best.Height != 100 || best.Time != 1576000000 || best.TicketPool.Size != 40960 {
+		t.Errorf("unexpected best block %v", best)
+	}
+	if _, err = blocks.Block(ctx, &dcrdatarpc.BlockRequest{Height: 101}); status.Code(err) != codes.NotFound {
+		t.Errorf("got %v for a block above the best block", err)
+	}
+	if _, err = blocks.Block(ctx, &dcrdatarpc.BlockRequest{Hash: "bogus"}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("got %v for an invalid block hash", err)
+	}
+
+	tx, err := txns.Transaction(ctx, &dcrdatarpc.TransactionRequest{Txid: testTxid})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.BlockHeight != 98 || tx.Inputs[0].Coinbase != "00" || tx.Outputs[0].SpendTxid != "ef" ||
+		tx.Outputs[0].SpendIndex != 2 {
+		t.Errorf("unexpected transaction %v", tx)
+	}
+	_, err = txns.Transaction(ctx, &dcrdatarpc.TransactionRequest{Txid: chainhash.Hash{}.String()})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("got %v for an unknown transaction", err)
+	}
+
+	bal, err := addrs.Balance(ctx, &dcrdatarpc.AddressRequest{Address: "Dsi8hhDzr3SvcGcv4NEGvRqFkwZ2ncRhukk"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bal.NumUnspent != 2 || bal.TotalUnspent != 300 {
+		t.Errorf("unexpected balance %v", bal)
+	}
+	if _, err = addrs.Balance(ctx, &dcrdatarpc.AddressRequest{Address: "Tsbogus"}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("got %v for an invalid address", err)
+	}
+
+	// The ticket pool of the best block is returned if no block is requested.
+	pool, err := stake.TicketPool(ctx, &dcrdatarpc.TicketPoolRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pool.Height != 100 {
+		t.Errorf("got the ticket pool at height %d, wanted 100", pool.Height)
+	}
+	pool, err = stake.TicketPool(ctx, &dcrdatarpc.TicketPoolRequest{
+		Block: &dcrdatarpc.BlockRequest{Height: 50},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pool.Height != 50 {
+		t.Errorf("got the ticket pool at height %d, wanted 50", pool.Height)
+	}
+
+	// Subscribers receive the new blocks and mempool transactions.
+	blockStream, err := blocks.SubscribeBlocks(ctx, &dcrdatarpc.SubscribeBlocksRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mempoolStream, err := txns.SubscribeMempool(ctx, &dcrdatarpc.SubscribeMempoolRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Wait for the streams to be registered.
+	for i := 0; i < 100; i++ {
+		srv.mtx.Lock()
+		n := len(srv.blockSubs) + len(srv.mempoolSubs)
+		srv.mtx.Unlock()
+		if n == 2 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	blockData := &blockdata.BlockData{}
+	blockData.Header.Height = 101
+	blockData.Header.Hash = "ef01"
+	msgBlock := &wire.MsgBlock{Transactions: make([]*wire.MsgTx, 3)}
+	if err = srv.Store(blockData, msgBlock); err != nil {
+		t.Fatal(err)
+	}
+	newBlock, err := blockStream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if newBlock.Height != 101 || newBlock.Hash != "ef01" || newBlock.NumTx != 3 {
+		t.Errorf("unexpected new block %v", newBlock)
+	}
+
+	srv.MempoolSignal() <- pstypes.HubMessage{Signal: pstypes.SigMempoolUpdate}
+	srv.MempoolSignal() <- pstypes.HubMessage{
+		Signal: pstypes.SigNewTx,
+		Msg:    &exptypes.MempoolTx{TxID: testTxid, Type: "Ticket", Fees: 0.001},
+	}
+	mempoolTx, err := mempoolStream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mempoolTx.Txid != testTxid || mempoolTx.Type != "Ticket" || mempoolTx.Fees != 0.001 {
+		t.Errorf("unexpected mempool transaction %v", mempoolTx)
+	}
+}
+
+type limiterStub struct {
+	keys []string
+	left int
+}
+
+func (l *limiterStub) Allow(key, _ string) bool {
+	l.keys = append(l.keys, key)
+	l.left--
+	return l.left >= 0
+}
+
+func TestServerRateLimit(t *testing.T) {
+	limiter := &limiterStub{left: 1}
+	srv, err := NewServer(&Config{
+		Store:       &storeStub{height: 100},
+		Params:      chaincfg.MainNetParams(),
+		RateLimiter: limiter,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	listener := bufconn.Listen(1 << 20)
+	go srv.Serve(ctx, listener)
+
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithInsecure(),
+		grpc.WithDialer(func(string, time.Duration) (net.Conn, error) {
+			return listener.Dial()
+		}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	blocks := dcrdatarpc.NewBlockServiceClient(conn)
+
+	// The API key is taken from the metadata, and new streams are charged.
+	keyCtx := metadata.AppendToOutgoingContext(ctx, apiKeyMetadata, "sekrit")
+	if _, err = blocks.BestBlock(keyCtx, &dcrdatarpc.BestBlockRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	stream, err := blocks.SubscribeBlocks(ctx, &dcrdatarpc.SubscribeBlocksRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = stream.Recv(); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("got %v for a stream over the limit", err)
+	}
+	if _, err = blocks.BestBlock(ctx, &dcrdatarpc.BestBlockRequest{}); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("got %v for a call over the limit", err)
+	}
+	if len(limiter.keys) != 3 || limiter.keys[0] != "sekrit" || limiter.keys[1] != "" {
+		t.Errorf("unexpected limited keys %q", limiter.keys)
+	}
+}
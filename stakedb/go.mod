@@ -2,6 +2,12 @@ module github.com/decred/dcrdata/stakedb/v3
 
 go 1.12
 
+replace (
+	github.com/decred/dcrdata/api/types/v5 => ../api/types
+	github.com/decred/dcrdata/db/dbtypes/v2 => ../db/dbtypes
+	github.com/decred/dcrdata/txhelpers/v4 => ../txhelpers
+)
+
 require (
 	github.com/AndreasBriese/bbloom v0.0.0-20190306092124-e2d15f34fcf9 // indirect
 	github.com/DataDog/zstd v1.4.1 // indirect
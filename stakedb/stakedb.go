@@ -6,10 +6,13 @@
 package stakedb
 
 import (
+	"bytes"
 	"encoding/binary"
 	"fmt"
+	"math/big"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 
@@ -101,6 +104,7 @@ type StakeDatabase struct {
 	liveTicketMtx   sync.RWMutex
 	liveTicketCache map[chainhash.Hash]int64
 	poolValue       int64
+	poolValueSq     *big.Int
 	poolInfo        *PoolInfoCache
 	PoolDB          *TicketPool
 
@@ -147,6 +151,7 @@ func LoadAndRecover(client *rpcclient.Client, params *chaincfg.Params,
 		NodeClient:      client,
 		blockCache:      make(map[int64]*dcrutil.Block),
 		liveTicketCache: make(map[chainhash.Hash]int64, params.TicketPoolSize*(params.TicketsPerBlock+1)),
+		poolValueSq:     new(big.Int),
 		poolInfo:        poolInfoCache,
 		PoolDB:          poolDB,
 		heightWaiters:   make(map[int64][]chan *chainhash.Hash),
@@ -264,6 +269,7 @@ func NewStakeDatabase(client *rpcclient.Client, params *chaincfg.Params,
 		NodeClient:      client,
 		blockCache:      make(map[int64]*dcrutil.Block),
 		liveTicketCache: make(map[chainhash.Hash]int64, params.TicketPoolSize*(params.TicketsPerBlock+1)),
+		poolValueSq:     new(big.Int),
 		poolInfo:        poolInfoCache,
 		PoolDB:          poolDB,
 		heightWaiters:   make(map[int64][]chan *chainhash.Hash),
@@ -353,6 +359,7 @@ func (db *StakeDatabase) PopulateLiveTicketCache() error {
 	// Reset ticket cache.
 	db.liveTicketMtx.Lock()
 	db.poolValue = 0
+	db.poolValueSq = new(big.Int)
 	db.liveTicketCache = make(map[chainhash.Hash]int64, db.params.TicketPoolSize*(db.params.TicketsPerBlock+1))
 
 	// Receive the live ticket tx results
@@ -369,6 +376,7 @@ func (db *StakeDatabase) PopulateLiveTicketCache() error {
 
 		value := ticketTx.MsgTx().TxOut[0].Value
 		db.poolValue += value
+		db.poolValueSq.Add(db.poolValueSq, valueSq(value))
 		db.liveTicketCache[p.ticket] = value
 	}
 	db.liveTicketMtx.Unlock()
@@ -575,7 +583,8 @@ func (db *StakeDatabase) ConnectBlock(block *dcrutil.Block) error {
 	poolSize := int64(db.BestNode.PoolSize())
 	winningTickets := db.BestNode.Winners()
 
-	pib := db.makePoolInfo(db.poolValue, poolSize, winningTickets, uint32(height))
+	poolValue, valWeightedAvg := db.poolValues()
+	pib := db.makePoolInfo(poolValue, valWeightedAvg, poolSize, winningTickets, uint32(height))
 	db.poolInfo.Set(*block.Hash(), pib)
 
 	// Append this ticket pool diff
@@ -605,6 +614,31 @@ func (db *StakeDatabase) connectBlock(block *dcrutil.Block, spent []chainhash.Ha
 	})
 }
 
+// valueSq returns the square of the ticket value.
+func valueSq(value int64) *big.Int {
+	v := big.NewInt(value)
+	return v.Mul(v, v)
+}
+
+// valWeightedAvg computes the value-weighted average ticket value in DCR,
+// sum(value^2)/sum(value), given the pool value and the sum of the squares of
+// the ticket values, in atoms.
+func valWeightedAvg(poolValue int64, poolValueSq *big.Int) float64 {
+	if poolValue <= 0 {
+		return 0
+	}
+	sq, _ := new(big.Float).SetInt(poolValueSq).Float64()
+	return sq / float64(poolValue) / dcrutil.AtomsPerCoin
+}
+
+// poolValues returns the pool value in atoms and the value-weighted average
+// ticket value in DCR.
+func (db *StakeDatabase) poolValues() (int64, float64) {
+	db.liveTicketMtx.RLock()
+	defer db.liveTicketMtx.RUnlock()
+	return db.poolValue, valWeightedAvg(db.poolValue, db.poolValueSq)
+}
+
 // applyDiff updates liveTicketCache and poolValue for the given PoolDiff.
 func (db *StakeDatabase) applyDiff(poolDiff PoolDiff) {
 	db.liveTicketMtx.Lock()
@@ -625,6 +659,7 @@ func (db *StakeDatabase) applyDiff(poolDiff PoolDiff) {
 		val := tx.MsgTx().TxOut[0].Value
 		db.liveTicketCache[hash] = val
 		db.poolValue += val
+		db.poolValueSq.Add(db.poolValueSq, valueSq(val))
 	}
 
 	for _, h := range poolDiff.Out {
@@ -634,6 +669,7 @@ func (db *StakeDatabase) applyDiff(poolDiff PoolDiff) {
 			continue
 		}
 		db.poolValue -= valOut
+		db.poolValueSq.Sub(db.poolValueSq, valueSq(valOut))
 		delete(db.liveTicketCache, h)
 	}
 	db.liveTicketMtx.Unlock()
@@ -872,10 +908,11 @@ func (db *StakeDatabase) PoolInfoBest() *apitypes.TicketPoolInfo {
 	// expiredTickets, expireRevoked := db.expires()
 	db.nodeMtx.RUnlock()
 
-	return db.makePoolInfo(db.poolValue, int64(poolSize), winningTickets, height) // db.calcPoolInfo(liveTickets, winningTickets, height)
+	poolValue, valWeightedAvg := db.poolValues()
+	return db.makePoolInfo(poolValue, valWeightedAvg, int64(poolSize), winningTickets, height) // db.calcPoolInfo(liveTickets, winningTickets, height)
 }
 
-func (db *StakeDatabase) makePoolInfo(poolValue, poolSize int64,
+func (db *StakeDatabase) makePoolInfo(poolValue int64, valWeightedAvg float64, poolSize int64,
 	winningTickets []chainhash.Hash, height uint32) *apitypes.TicketPoolInfo {
 	poolCoin := dcrutil.Amount(poolValue).ToCoin()
 	valAvg := 0.0
@@ -889,34 +926,47 @@ func (db *StakeDatabase) makePoolInfo(poolValue, poolSize int64,
 	}
 
 	return &apitypes.TicketPoolInfo{
-		Height:  height,
-		Size:    uint32(poolSize),
-		Value:   poolCoin,
-		ValAvg:  valAvg,
-		Winners: winners,
+		Height:         height,
+		Size:           uint32(poolSize),
+		Value:          poolCoin,
+		ValAvg:         valAvg,
+		ValWeightedAvg: valWeightedAvg,
+		Winners:        winners,
 	}
 }
 
+// calcPoolInfo computes the ticket pool info from the given live tickets. The
+// values of tickets that are not in the live ticket cache are fetched from the
+// node, but not cached since the tickets need not be currently live.
 func (db *StakeDatabase) calcPoolInfo(liveTickets, winningTickets []chainhash.Hash, height uint32) *apitypes.TicketPoolInfo {
 	poolSize := len(liveTickets)
-	db.liveTicketMtx.Lock()
 	var poolValue int64
+	poolValueSq := new(big.Int)
+	var uncached []chainhash.Hash
+	db.liveTicketMtx.RLock()
 	for _, hash := range liveTickets {
 		val, ok := db.liveTicketCache[hash]
 		if !ok {
-			tx, err := db.NodeClient.GetRawTransaction(&hash)
-			if err != nil {
-				log.Errorf("Unable to get transaction %v: %v\n", hash, err)
-				continue
-			}
-			// This isn't quite right for pool tickets where the small
-			// pool fees are included in vout[0], but it's close.
-			val = tx.MsgTx().TxOut[0].Value
-			db.liveTicketCache[hash] = val
+			uncached = append(uncached, hash)
+			continue
 		}
 		poolValue += val
+		poolValueSq.Add(poolValueSq, valueSq(val))
 	}
-	db.liveTicketMtx.Unlock()
+	db.liveTicketMtx.RUnlock()
+	for i := range uncached {
+		tx, err := db.NodeClient.GetRawTransaction(&uncached[i])
+		if err != nil {
+			log.Errorf("Unable to get transaction %v: %v\n", uncached[i], err)
+			continue
+		}
+		// This isn't quite right for pool tickets where the small
+		// pool fees are included in vout[0], but it's close.
+		val := tx.MsgTx().TxOut[0].Value
+		poolValue += val
+		poolValueSq.Add(poolValueSq, valueSq(val))
+	}
+	valWeightedAvg := valWeightedAvg(poolValue, poolValueSq)
 
 	poolCoin := dcrutil.Amount(poolValue).ToCoin()
 	valAvg := 0.0
@@ -930,11 +980,12 @@ func (db *StakeDatabase) calcPoolInfo(liveTickets, winningTickets []chainhash.Ha
 	}
 
 	return &apitypes.TicketPoolInfo{
-		Height:  height,
-		Size:    uint32(poolSize),
-		Value:   poolCoin,
-		ValAvg:  valAvg,
-		Winners: winners,
+		Height:         height,
+		Size:           uint32(poolSize),
+		Value:          poolCoin,
+		ValAvg:         valAvg,
+		ValWeightedAvg: valWeightedAvg,
+		Winners:        winners,
 	}
 }
 
@@ -945,6 +996,52 @@ func (db *StakeDatabase) PoolInfo(hash chainhash.Hash) (*apitypes.TicketPoolInfo
 	return db.poolInfo.Get(hash)
 }
 
+// PoolInfoAtHeight computes the ticket pool info for the mainchain block at the
+// given height, which must not be above the stake database tip. Unlike
+// PoolInfo, it does not rely on the pool info cache. The live tickets are
+// recovered from the ticket pool diffs, and the winners are drawn from them
+// with the lottery seeded by the block header, as dcrd does. This may be slow
+// since the values of tickets that are no longer live are fetched from the
+// node.
+func (db *StakeDatabase) PoolInfoAtHeight(height int64) (*apitypes.TicketPoolInfo, error) {
+	liveTickets, err := db.PoolDB.Pool(height)
+	if err != nil {
+		return nil, err
+	}
+
+	var winners []chainhash.Hash
+	if height >= db.params.StakeValidationHeight-1 && len(liveTickets) > 0 {
+		hash, err := db.NodeClient.GetBlockHash(height)
+		if err != nil {
+			return nil, fmt.Errorf("GetBlockHash(%d) failed: %v", height, err)
+		}
+		header, err := db.NodeClient.GetBlockHeader(hash)
+		if err != nil {
+			return nil, fmt.Errorf("GetBlockHeader(%v) failed: %v", hash, err)
+		}
+		hB, err := header.Bytes()
+		if err != nil {
+			return nil, err
+		}
+		// The lottery selects winners by their index in the live tickets
+		// sorted by hash.
+		sort.Slice(liveTickets, func(i, j int) bool {
+			return bytes.Compare(liveTickets[i][:], liveTickets[j][:]) < 0
+		})
+		prng := stake.NewHash256PRNGFromIV(stake.CalcHash256PRNGIV(hB))
+		idxs, err := stake.FindTicketIdxs(len(liveTickets), db.params.TicketsPerBlock, prng)
+		if err != nil {
+			return nil, err
+		}
+		winners = make([]chainhash.Hash, 0, len(idxs))
+		for _, idx := range idxs {
+			winners = append(winners, liveTickets[idx])
+		}
+	}
+
+	return db.calcPoolInfo(liveTickets, winners, uint32(height)), nil
+}
+
 // PoolSize returns the ticket pool size in the best node of the stake database
 func (db *StakeDatabase) PoolSize() int {
 	db.nodeMtx.Lock()
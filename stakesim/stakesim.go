@@ -0,0 +1,247 @@
+// Copyright (c) 2020, The Decred developers
+// See LICENSE for details.
+
+// Package stakesim replays the historical ticket demand of the main chain
+// against alternative stake difficulty parameters, for evaluating changes to
+// the ticket price algorithm of DCP0001.
+//
+// The simulation starts at the genesis block. In each block, the simulated
+// ticket purchases are either the number of tickets actually purchased, or the
+// number that the amount actually spent on tickets buys at the simulated
+// price. Purchased tickets become live after the ticket maturity, and the
+// tickets per block are removed from the live pool from the stake validation
+// height on, whether they vote or miss. Expired tickets are not modeled.
+package stakesim
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/decred/dcrd/chaincfg/v2"
+	"github.com/decred/dcrd/dcrutil/v2"
+	"github.com/decred/dcrdata/db/dbtypes/v2"
+)
+
+// Demand is how the historical ticket demand is replayed.
+type Demand string
+
+const (
+	// DemandTickets replays the number of tickets purchased in each block.
+	DemandTickets Demand = "tickets"
+	// DemandValue replays the amount spent on tickets in each block, which
+	// buys more or fewer tickets as the simulated price is lower or higher.
+	DemandValue Demand = "value"
+)
+
+// Config is the stake difficulty parameters of a simulation. Zero values use
+// the network's parameters.
+type Config struct {
+	// WindowSize is the number of blocks between ticket price changes.
+	WindowSize int64 `json:"window_size"`
+	// TicketPoolSize is the target ticket pool size in units of the tickets
+	// per block, as in chaincfg.Params.
+	TicketPoolSize int64  `json:"ticket_pool_size"`
+	Demand         Demand `json:"demand"`
+}
+
+// Point is the simulated and actual ticket price, pool size and purchases of
+// the stake difficulty window of the simulation starting at Height. Prices
+// are in DCR, and the pool sizes are at the last block of the window.
+type Point struct {
+	Height          int64   `json:"height"`
+	Price           float64 `json:"price"`
+	PoolSize        int64   `json:"pool_size"`
+	Purchased       int64   `json:"purchased"`
+	ActualPrice     float64 `json:"actual_price"`
+	ActualPoolSize  int64   `json:"actual_pool_size"`
+	ActualPurchased int64   `json:"actual_purchased"`
+}
+
+// Result is a simulation's parameters, the target pool size in tickets that
+// they imply, and the simulated series.
+type Result struct {
+	Config
+	TargetPoolSize int64    `json:"target_pool_size"`
+	Points         []*Point `json:"points"`
+}
+
+// Simulate replays the ticket demand of the main chain blocks in history,
+// which must be in height order starting at the genesis block, with the stake
+// difficulty parameters of cfg.
+func Simulate(params *chaincfg.Params, cfg Config, history []dbtypes.BlockTicketDemand) (*Result, error) {
+	if cfg.WindowSize == 0 {
+		cfg.WindowSize = params.StakeDiffWindowSize
+	}
+	if cfg.TicketPoolSize == 0 {
+		cfg.TicketPoolSize = int64(params.TicketPoolSize)
+	}
+	if cfg.Demand == "" {
+		cfg.Demand = DemandTickets
+	}
+	if cfg.WindowSize < 1 || cfg.TicketPoolSize < 1 {
+		return nil, fmt.Errorf("invalid window size %d or ticket pool size %d",
+			cfg.WindowSize, cfg.TicketPoolSize)
+	}
+	if cfg.Demand != DemandTickets && cfg.Demand != DemandValue {
+		return nil, fmt.Errorf("unknown demand %q", cfg.Demand)
+	}
+	for i := range history {
+		if history[i].Height != int64(i) {
+			return nil, fmt.Errorf("block at height %d missing from history", i)
+		}
+	}
+
+	s := &simulator{
+		params:    params,
+		cfg:       cfg,
+		maturity:  int64(params.TicketMaturity),
+		perBlock:  int64(params.TicketsPerBlock),
+		pool:      make([]int64, len(history)),
+		purchased: make([]int64, len(history)+1),
+	}
+	res := &Result{
+		Config:         cfg,
+		TargetPoolSize: cfg.TicketPoolSize * s.perBlock,
+		Points:         []*Point{},
+	}
+
+	maxFreshStake := int64(params.MaxFreshStakePerBlock)
+	price := params.MinimumStakeDiff
+	var point *Point
+	for i := range history {
+		height := int64(i)
+		block := &history[i]
+		if height > 0 {
+			price = s.nextPrice(height, price)
+		}
+
+		bought := int64(block.FreshStake)
+		if cfg.Demand == DemandValue {
+			bought = bought * block.SBits / price
+		}
+		if bought > maxFreshStake {
+			bought = maxFreshStake
+		}
+		s.purchased[i+1] = s.purchased[i] + bought
+
+		var pool int64
+		if height > 0 {
+			pool = s.pool[i-1]
+		}
+		if height >= s.maturity {
+			pool += s.purchasedIn(height-s.maturity, height-s.maturity)
+		}
+		if height >= params.StakeValidationHeight {
+			pool -= s.perBlock
+		}
+		if pool < 0 {
+			pool = 0
+		}
+		s.pool[i] = pool
+
+		if height%cfg.WindowSize == 0 {
+			point = &Point{
+				Height:      height,
+				Price:       dcrutil.Amount(price).ToCoin(),
+				ActualPrice: dcrutil.Amount(block.SBits).ToCoin(),
+			}
+			res.Points = append(res.Points, point)
+		}
+		point.PoolSize = pool
+		point.Purchased += bought
+		point.ActualPoolSize = int64(block.PoolSize)
+		point.ActualPurchased += int64(block.FreshStake)
+	}
+
+	return res, nil
+}
+
+// simulator holds the simulated pool size after each block and the
+// cumulative ticket purchases.
+type simulator struct {
+	params   *chaincfg.Params
+	cfg      Config
+	maturity int64
+	perBlock int64
+	pool     []int64
+	// purchased[i] is the number of tickets purchased before height i.
+	purchased []int64
+}
+
+// purchasedIn returns the number of tickets purchased in the blocks from
+// height start to end inclusive, clamped to the simulated blocks.
+func (s *simulator) purchasedIn(start, end int64) int64 {
+	if start < 0 {
+		start = 0
+	}
+	if end < start {
+		return 0
+	}
+	return s.purchased[end+1] - s.purchased[start]
+}
+
+// nextPrice returns the ticket price of the block at nextHeight, following
+// calcNextRequiredStakeDifficultyV2 in dcrd's blockchain package with the
+// simulation's window and pool sizes.
+func (s *simulator) nextPrice(nextHeight, curDiff int64) int64 {
+	if nextHeight < int64(s.params.CoinbaseMaturity)+1 {
+		return s.params.MinimumStakeDiff
+	}
+	if nextHeight%s.cfg.WindowSize != 0 {
+		return curDiff
+	}
+
+	var prevPoolSizeAll int64
+	if prevRetargetHeight := nextHeight - s.cfg.WindowSize - 1; prevRetargetHeight >= 0 {
+		prevPoolSizeAll = s.pool[prevRetargetHeight] + s.purchasedIn(
+			prevRetargetHeight-s.maturity+1, prevRetargetHeight)
+	}
+	if prevPoolSizeAll == 0 {
+		return curDiff
+	}
+	curHeight := nextHeight - 1
+	curPoolSizeAll := s.pool[curHeight] + s.purchasedIn(curHeight-s.maturity+1, curHeight)
+
+	targetPoolSizeAll := s.perBlock * (s.cfg.TicketPoolSize + s.maturity)
+	curPoolSizeAllBig := big.NewInt(curPoolSizeAll)
+	nextDiffBig := big.NewInt(curDiff)
+	nextDiffBig.Mul(nextDiffBig, curPoolSizeAllBig)
+	nextDiffBig.Mul(nextDiffBig, curPoolSizeAllBig)
+	nextDiffBig.Div(nextDiffBig, big.NewInt(prevPoolSizeAll))
+	nextDiffBig.Div(nextDiffBig, big.NewInt(targetPoolSizeAll))
+
+	nextDiff := nextDiffBig.Int64()
+	maximumStakeDiff := estimateSupply(s.params, nextHeight) / s.cfg.TicketPoolSize
+	if nextDiff > maximumStakeDiff {
+		nextDiff = maximumStakeDiff
+	}
+	if nextDiff < s.params.MinimumStakeDiff {
+		nextDiff = s.params.MinimumStakeDiff
+	}
+	return nextDiff
+}
+
+// estimateSupply returns an estimate of the coin supply at the given height,
+// as used by dcrd to limit the stake difficulty.
+func estimateSupply(params *chaincfg.Params, height int64) int64 {
+	if height <= 0 {
+		return 0
+	}
+
+	supply := params.BlockOneSubsidy()
+	reductions := height / params.SubsidyReductionInterval
+	subsidy := params.BaseSubsidy
+	for i := int64(0); i < reductions; i++ {
+		supply += params.SubsidyReductionInterval * subsidy
+
+		subsidy *= params.MulSubsidy
+		subsidy /= params.DivSubsidy
+	}
+	supply += (1 + height%params.SubsidyReductionInterval) * subsidy
+
+	// Blocks 0 and 1 have special subsidy amounts that were added above, so
+	// remove what their subsidies would have normally been.
+	supply -= params.BaseSubsidy * 2
+
+	return supply
+}
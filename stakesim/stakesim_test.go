@@ -0,0 +1,100 @@
+// Copyright (c) 2020, The Decred developers
+// See LICENSE for details.
+
+package stakesim
+
+import (
+	"testing"
+
+	"github.com/decred/dcrd/chaincfg/v2"
+	"github.com/decred/dcrdata/db/dbtypes/v2"
+)
+
+// demandHistory makes a history of n blocks with the given ticket purchases
+// per block at the given price.
+func demandHistory(n int, freshStake uint16, sbits int64) []dbtypes.BlockTicketDemand {
+	history := make([]dbtypes.BlockTicketDemand, n)
+	for i := range history {
+		history[i] = dbtypes.BlockTicketDemand{
+			Height:     int64(i),
+			FreshStake: freshStake,
+			PoolSize:   uint32(i),
+			SBits:      sbits,
+		}
+	}
+	return history
+}
+
+func TestSimulate(t *testing.T) {
+	params := chaincfg.SimNetParams()
+	history := demandHistory(2000, 20, params.MinimumStakeDiff)
+
+	res, err := Simulate(params, Config{}, history)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.WindowSize != params.StakeDiffWindowSize || res.Demand != DemandTickets ||
+		res.TargetPoolSize != int64(params.TicketPoolSize)*int64(params.TicketsPerBlock) {
+		t.Errorf("unexpected simulation parameters %+v", res.Config)
+	}
+	if len(res.Points) != 250 {
+		t.Fatalf("got %d points, wanted 250", len(res.Points))
+	}
+	for i, p := range res.Points {
+		if p.Height != int64(i)*params.StakeDiffWindowSize || p.Purchased != 160 ||
+			p.ActualPurchased != 160 {
+			t.Fatalf("unexpected point %+v", p)
+		}
+	}
+
+	// The pool grows by the matured tickets until voting starts, after which
+	// 5 tickets a block are removed.
+	first := res.Points[2] // blocks 16 to 23
+	if first.PoolSize != 8*20 || first.ActualPoolSize != 23 {
+		t.Errorf("unexpected pool sizes %d and %d at height 23", first.PoolSize,
+			first.ActualPoolSize)
+	}
+	last := res.Points[len(res.Points)-1]
+	wantPool := int64(2000-16)*20 - int64(2000-144)*5
+	if last.PoolSize != wantPool {
+		t.Errorf("got final pool size %d, wanted %d", last.PoolSize, wantPool)
+	}
+
+	// With a pool far above its target, the price rises until it is limited,
+	// and more slowly for a larger target pool size.
+	larger, err := Simulate(params, Config{TicketPoolSize: 4 * int64(params.TicketPoolSize)}, history)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mid := len(res.Points) / 4
+	if res.Points[mid].Price <= larger.Points[mid].Price ||
+		larger.Points[mid].Price <= res.Points[0].Price {
+		t.Errorf("got prices %v and %v for the default and larger target pool sizes",
+			res.Points[mid].Price, larger.Points[mid].Price)
+	}
+
+	// Replaying the amount spent buys fewer tickets once the price rises.
+	byValue, err := Simulate(params, Config{Demand: DemandValue}, history)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lastByValue := byValue.Points[len(byValue.Points)-1]
+	if lastByValue.Purchased >= last.Purchased || lastByValue.PoolSize >= last.PoolSize {
+		t.Errorf("got %d tickets purchased and pool size %d by value", lastByValue.Purchased,
+			lastByValue.PoolSize)
+	}
+}
+
+func TestSimulateErrors(t *testing.T) {
+	params := chaincfg.SimNetParams()
+	history := demandHistory(100, 5, params.MinimumStakeDiff)
+	if _, err := Simulate(params, Config{Demand: "votes"}, history); err == nil {
+		t.Errorf("expected an error for an unknown demand")
+	}
+	if _, err := Simulate(params, Config{WindowSize: -1}, history); err == nil {
+		t.Errorf("expected an error for a negative window size")
+	}
+	if _, err := Simulate(params, Config{}, history[1:]); err == nil {
+		t.Errorf("expected an error for a history without the genesis block")
+	}
+}
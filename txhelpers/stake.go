@@ -22,3 +22,64 @@ func CalcMeanVotingBlocks(params *chaincfg.Params) int64 {
 	}
 	return int64(v)
 }
+
+// CalcVotingBlocksStats computes the mean and standard deviation of the number
+// of blocks a ticket is live before voting, for the tickets that vote rather
+// than expire. Unlike CalcMeanVotingBlocks, the probability distribution is
+// conditioned on voting:
+//      P(B | vote) = P(B) / sum(P(b)), b=1 to 40960
+// These describe the expected "luck" of a voted ticket.
+func CalcVotingBlocksStats(params *chaincfg.Params) (mean, stdDev float64) {
+	logPoolSizeM1 := math.Log(float64(params.TicketPoolSize) - 1)
+	logPoolSize := math.Log(float64(params.TicketPoolSize))
+	var pVote, sum, sumSq float64
+	for i := float64(1); i <= float64(params.TicketExpiry); i++ {
+		p := math.Exp((i-1)*logPoolSizeM1 - i*logPoolSize)
+		pVote += p
+		sum += i * p
+		sumSq += i * i * p
+	}
+	mean = sum / pVote
+	stdDev = math.Sqrt(sumSq/pVote - mean*mean)
+	return
+}
+
+// TicketVoteWindow is the range of blocks in which a ticket is eligible to
+// vote, given as offsets from the next block. A window with Last < First never
+// becomes eligible.
+type TicketVoteWindow struct {
+	First int64
+	Last  int64
+}
+
+// CalcNoVoteProbabilities computes, for each of the next numBlocks blocks, the
+// probability that none of the tickets with the given vote windows has voted
+// by the end of that block. With e eligible tickets in a pool of poolSize
+// live tickets, the probability that none are selected in a block is:
+//      prod((poolSize-e-t)/(poolSize-t)), t=0 to ticketsPerBlock-1
+// The pool size is assumed constant over the range.
+func CalcNoVoteProbabilities(windows []TicketVoteWindow, poolSize int64,
+	ticketsPerBlock uint16, numBlocks int64) []float64 {
+	if numBlocks <= 0 {
+		return nil
+	}
+	probs := make([]float64, numBlocks)
+	pNone := 1.0
+	for i := int64(0); i < numBlocks; i++ {
+		var eligible int64
+		for _, w := range windows {
+			if i >= w.First && i <= w.Last {
+				eligible++
+			}
+		}
+		for t := int64(0); t < int64(ticketsPerBlock) && pNone > 0; t++ {
+			if poolSize-t <= 0 || poolSize-eligible-t <= 0 {
+				pNone = 0
+				break
+			}
+			pNone *= float64(poolSize-eligible-t) / float64(poolSize-t)
+		}
+		probs[i] = pNone
+	}
+	return probs
+}
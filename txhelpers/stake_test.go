@@ -1,6 +1,7 @@
 package txhelpers
 
 import (
+	"math"
 	"testing"
 	"time"
 
@@ -69,3 +70,54 @@ func TestRewardPeriods(t *testing.T) {
 			lockedDuration, lockedDuration.Hours()/24)
 	}
 }
+
+func TestCalcVotingBlocksStats(t *testing.T) {
+	for i := range networkRewardPeriods {
+		params := networkRewardPeriods[i].params
+		mean, stdDev := CalcVotingBlocksStats(params)
+
+		// The unconditioned mean is the conditioned mean weighted by the
+		// probability of voting before expiry.
+		poolSize := float64(params.TicketPoolSize)
+		pVote := 1 - math.Pow((poolSize-1)/poolSize, float64(params.TicketExpiry))
+		if got := int64(mean * pVote); got != networkRewardPeriods[i].MeanVotingBlocks {
+			t.Errorf("%s: mean*P(vote) = %d, expected %d", params.Name, got,
+				networkRewardPeriods[i].MeanVotingBlocks)
+		}
+
+		// Truncation at expiry narrows the geometric distribution.
+		if stdDev <= 0 || stdDev >= poolSize {
+			t.Errorf("%s: unexpected standard deviation %f", params.Name, stdDev)
+		}
+	}
+}
+
+func TestCalcNoVoteProbabilities(t *testing.T) {
+	params := chaincfg.MainNetParams()
+	poolSize := int64(params.TicketPoolSize) * int64(params.TicketsPerBlock)
+
+	// A single ticket eligible for every block matches the geometric
+	// distribution with a per-block vote probability of 5/40960.
+	windows := []TicketVoteWindow{{0, int64(params.TicketExpiry)}}
+	probs := CalcNoVoteProbabilities(windows, poolSize, params.TicketsPerBlock, 8192)
+	pVote := float64(params.TicketsPerBlock) / float64(poolSize)
+	want := math.Pow(1-pVote, 8192)
+	if got := probs[len(probs)-1]; math.Abs(got-want) > 1e-9 {
+		t.Errorf("single ticket: got %f, expected %f", got, want)
+	}
+
+	// A ticket that is not yet mature does not change the probability until
+	// its window opens.
+	windows = []TicketVoteWindow{{10, 20}}
+	probs = CalcNoVoteProbabilities(windows, poolSize, params.TicketsPerBlock, 30)
+	if probs[9] != 1 || probs[10] >= 1 || probs[29] != probs[20] {
+		t.Errorf("window not respected: %v", probs)
+	}
+
+	// An expired or never eligible ticket cannot vote.
+	windows = []TicketVoteWindow{{1, 0}}
+	probs = CalcNoVoteProbabilities(windows, poolSize, params.TicketsPerBlock, 5)
+	if probs[4] != 1 {
+		t.Errorf("ineligible ticket voted with probability %f", 1-probs[4])
+	}
+}
@@ -82,3 +82,52 @@ func RewardsAtBlock(blockIdx int64, votes uint16, p *chaincfg.Params) (work, sta
 	tax = subsidyCache.CalcTreasurySubsidy(blockIdx, votes)
 	return
 }
+
+// SubsidySum computes the total subsidy of the blocks in the height range
+// (fromHeight, toHeight], assuming every block includes all votes. This may be
+// used to project the coin supply at a future block height.
+func SubsidySum(fromHeight, toHeight int64, params *chaincfg.Params) int64 {
+	votesPerBlock := params.VotesPerBlock()
+	stakeValidationHeight := params.StakeValidationBeginHeight()
+	reductionInterval := params.SubsidyReductionIntervalBlocks()
+
+	subsidyCache := standalone.NewSubsidyCache(params)
+	blockSubsidy := func(height int64) int64 {
+		switch height {
+		case 0:
+			return 0
+		case 1:
+			return params.BlockOneSubsidy()
+		}
+		subsidy := subsidyCache.CalcWorkSubsidy(height, votesPerBlock) +
+			subsidyCache.CalcTreasurySubsidy(height, votesPerBlock)
+		if height >= stakeValidationHeight {
+			subsidy += subsidyCache.CalcStakeVoteSubsidy(height) * int64(votesPerBlock)
+		}
+		return subsidy
+	}
+
+	var sum int64
+	for height := fromHeight + 1; height <= toHeight; {
+		// The subsidy is constant until the next reduction interval, or until
+		// voting begins, so the blocks up to that point are summed at once.
+		// Blocks 0 and 1 are special cases handled individually.
+		next := (height/reductionInterval + 1) * reductionInterval
+		if height < stakeValidationHeight && next > stakeValidationHeight {
+			next = stakeValidationHeight
+		}
+		if height < 2 {
+			next = height + 1
+		}
+		if next > toHeight+1 {
+			next = toHeight + 1
+		}
+		subsidy := blockSubsidy(height)
+		if subsidy == 0 && height > 1 {
+			break
+		}
+		sum += subsidy * (next - height)
+		height = next
+	}
+	return sum
+}
@@ -70,3 +70,42 @@ func BenchmarkUltimateSubsidy(b *testing.B) {
 			totalSubsidy, totalSubsidy2)
 	}
 }
+
+func TestSubsidySum(t *testing.T) {
+	params := chaincfg.MainNetParams()
+	votes := params.VotesPerBlock()
+	svh := params.StakeValidationBeginHeight()
+
+	// Sum the subsidy block by block for comparison.
+	naiveSum := func(from, to int64) int64 {
+		var sum int64
+		for h := from + 1; h <= to; h++ {
+			if h == 1 {
+				sum += params.BlockOneSubsidy()
+				continue
+			}
+			if h == 0 {
+				continue
+			}
+			work, stake, tax := RewardsAtBlock(h, votes, params)
+			if h >= svh {
+				work += stake * int64(votes)
+			}
+			sum += work + tax
+		}
+		return sum
+	}
+
+	ranges := [][2]int64{
+		{-1, 10},
+		{0, svh + 10},
+		{svh - 3, 3*params.SubsidyReductionIntervalBlocks() + 7},
+		{430000, 430000},
+	}
+	for _, r := range ranges {
+		want := naiveSum(r[0], r[1])
+		if got := SubsidySum(r[0], r[1], params); got != want {
+			t.Errorf("SubsidySum(%d, %d): expected %d, got %d", r[0], r[1], want, got)
+		}
+	}
+}
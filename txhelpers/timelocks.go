@@ -0,0 +1,87 @@
+// Copyright (c) 2020, The Decred developers
+// See LICENSE for details.
+
+package txhelpers
+
+import (
+	"github.com/decred/dcrd/txscript/v2"
+	"github.com/decred/dcrd/wire"
+)
+
+// Kinds of transaction lock time.
+const (
+	LockTimeNone   = "none"
+	LockTimeHeight = "height"
+	LockTimeTime   = "time"
+)
+
+// TxTimeLocks interprets the lock time and expiry of a transaction relative to
+// a block that may include it. A lock time below txscript.LockTimeThreshold is
+// a block height, otherwise it is a UNIX time, and the transaction may only be
+// mined in a block with a greater height or time. The lock time is only
+// enforced when an input has a sequence number other than
+// wire.MaxTxInSequenceNum. A transaction with a non-zero expiry may only be
+// mined in a block with a lower height, so BlocksToExpiry is the number of
+// blocks, starting with the block, that may still include it.
+type TxTimeLocks struct {
+	LockTimeKind   string `json:"locktime_kind"`
+	LockTimeActive bool   `json:"locktime_active"`
+	LockedHeight   int64  `json:"locked_until_height,omitempty"`
+	LockedTime     int64  `json:"locked_until_time,omitempty"`
+	Locked         bool   `json:"locked"`
+	Expires        bool   `json:"expires"`
+	ExpiryHeight   int64  `json:"expiry_height,omitempty"`
+	BlocksToExpiry int64  `json:"blocks_to_expiry,omitempty"`
+	Expired        bool   `json:"expired"`
+}
+
+// TimeLocks interprets the lock time and expiry of a transaction relative to a
+// block with the given height and time, such as the next block for a mempool
+// transaction. sequenceLocked indicates that an input's sequence number
+// enables the lock time.
+func TimeLocks(lockTime, expiry uint32, sequenceLocked bool, height, blockTime int64) *TxTimeLocks {
+	tl := &TxTimeLocks{
+		LockTimeKind:   LockTimeNone,
+		LockTimeActive: lockTime != 0 && sequenceLocked,
+	}
+	switch {
+	case lockTime == 0:
+	case lockTime < txscript.LockTimeThreshold:
+		tl.LockTimeKind = LockTimeHeight
+		tl.LockedHeight = int64(lockTime)
+		tl.Locked = tl.LockTimeActive && height <= tl.LockedHeight
+	default:
+		tl.LockTimeKind = LockTimeTime
+		tl.LockedTime = int64(lockTime)
+		tl.Locked = tl.LockTimeActive && blockTime <= tl.LockedTime
+	}
+
+	if expiry != wire.NoExpiryValue {
+		tl.Expires = true
+		tl.ExpiryHeight = int64(expiry)
+		if height < tl.ExpiryHeight {
+			tl.BlocksToExpiry = tl.ExpiryHeight - height
+		} else {
+			tl.Expired = true
+		}
+	}
+	return tl
+}
+
+// SequenceLocked checks if any of the transaction's inputs has a sequence
+// number that enables its lock time.
+func SequenceLocked(msgTx *wire.MsgTx) bool {
+	for _, txIn := range msgTx.TxIn {
+		if txIn.Sequence != wire.MaxTxInSequenceNum {
+			return true
+		}
+	}
+	return false
+}
+
+// MsgTxTimeLocks interprets the lock time and expiry of the transaction
+// relative to a block with the given height and time.
+func MsgTxTimeLocks(msgTx *wire.MsgTx, height, blockTime int64) *TxTimeLocks {
+	return TimeLocks(msgTx.LockTime, msgTx.Expiry, SequenceLocked(msgTx),
+		height, blockTime)
+}
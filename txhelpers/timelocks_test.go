@@ -0,0 +1,72 @@
+package txhelpers
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/decred/dcrd/wire"
+)
+
+func TestTimeLocks(t *testing.T) {
+	const height, blockTime = 400000, 1580000000
+	tests := []struct {
+		name           string
+		lockTime       uint32
+		expiry         uint32
+		sequenceLocked bool
+		want           TxTimeLocks
+	}{
+		{"none", 0, 0, true, TxTimeLocks{LockTimeKind: LockTimeNone}},
+		{"height locked", height, 0, true, TxTimeLocks{
+			LockTimeKind:   LockTimeHeight,
+			LockTimeActive: true,
+			LockedHeight:   height,
+			Locked:         true,
+		}},
+		{"height unlocked", height - 1, 0, true, TxTimeLocks{
+			LockTimeKind:   LockTimeHeight,
+			LockTimeActive: true,
+			LockedHeight:   height - 1,
+		}},
+		{"height final sequence", height + 10, 0, false, TxTimeLocks{
+			LockTimeKind: LockTimeHeight,
+			LockedHeight: height + 10,
+		}},
+		{"time locked", blockTime + 60, 0, true, TxTimeLocks{
+			LockTimeKind:   LockTimeTime,
+			LockTimeActive: true,
+			LockedTime:     blockTime + 60,
+			Locked:         true,
+		}},
+		{"expiring", 0, height + 2, false, TxTimeLocks{
+			LockTimeKind:   LockTimeNone,
+			Expires:        true,
+			ExpiryHeight:   height + 2,
+			BlocksToExpiry: 2,
+		}},
+		{"expired", 0, height, false, TxTimeLocks{
+			LockTimeKind: LockTimeNone,
+			Expires:      true,
+			ExpiryHeight: height,
+			Expired:      true,
+		}},
+	}
+	for _, tt := range tests {
+		got := TimeLocks(tt.lockTime, tt.expiry, tt.sequenceLocked, height, blockTime)
+		if !reflect.DeepEqual(*got, tt.want) {
+			t.Errorf("%s: got %+v, wanted %+v", tt.name, *got, tt.want)
+		}
+	}
+}
+
+func TestSequenceLocked(t *testing.T) {
+	msgTx := wire.NewMsgTx()
+	msgTx.AddTxIn(&wire.TxIn{Sequence: wire.MaxTxInSequenceNum})
+	if SequenceLocked(msgTx) {
+		t.Errorf("final sequence numbers should not enable the lock time")
+	}
+	msgTx.AddTxIn(&wire.TxIn{Sequence: 0})
+	if !SequenceLocked(msgTx) {
+		t.Errorf("a non-final sequence number should enable the lock time")
+	}
+}
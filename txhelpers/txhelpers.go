@@ -8,12 +8,14 @@ package txhelpers
 
 import (
 	"bytes"
+	"encoding/base64"
 	"encoding/binary"
 	"encoding/hex"
 	"errors"
 	"fmt"
 	"math"
 	"math/big"
+	"net/url"
 	"sort"
 	"strconv"
 	"strings"
@@ -24,6 +26,8 @@ import (
 	"github.com/decred/dcrd/blockchain/standalone"
 	"github.com/decred/dcrd/chaincfg/chainhash"
 	"github.com/decred/dcrd/chaincfg/v2"
+	"github.com/decred/dcrd/dcrec"
+	"github.com/decred/dcrd/dcrec/secp256k1/v2"
 	"github.com/decred/dcrd/dcrutil/v2"
 	chainjson "github.com/decred/dcrd/rpc/jsonrpc/types/v2"
 	"github.com/decred/dcrd/rpcclient/v5"
@@ -736,6 +740,25 @@ func GetDifficultyRatio(bits uint32, params *chaincfg.Params) float64 {
 	return diff
 }
 
+// BlockWork returns the proof-of-work represented by the passed bits field
+// from the header of a block, as a hex-encoded string without 0x prefix.
+func BlockWork(bits uint32) string {
+	return standalone.CalcWork(bits).Text(16)
+}
+
+// AccumulateChainWork adds the work represented by the passed header bits to
+// the hex-encoded cumulative chain work of the previous block, returning the
+// new cumulative chain work formatted like dcrd's getblockheader "chainwork"
+// (64 hex digits, no 0x prefix).
+func AccumulateChainWork(prevChainWork string, bits uint32) (string, error) {
+	work, ok := new(big.Int).SetString(prevChainWork, 16)
+	if !ok {
+		return "", fmt.Errorf("invalid chainwork %q", prevChainWork)
+	}
+	work.Add(work, standalone.CalcWork(bits))
+	return fmt.Sprintf("%064x", work), nil
+}
+
 // SSTXInBlock gets a slice containing all of the SSTX mined in a block
 func SSTXInBlock(block *dcrutil.Block) []*dcrutil.Tx {
 	_, txns := TicketTxnsInBlock(block)
@@ -852,9 +875,16 @@ func SSGenVoteChoices(tx *wire.MsgTx, params *chaincfg.Params) (BlockValidation,
 		return validBlock, 0, 0, nil, err
 	}
 
-	// Determine the ssgen's vote version and get the relevant consensus
+	// Determine the ssgen's vote version and the choices for the consensus
 	// deployments containing the vote items targeted.
 	voteVersion := stake.SSGenVersion(tx)
+	return validBlock, voteVersion, voteBits, VoteChoices(voteVersion, voteBits, params), nil
+}
+
+// VoteChoices gets the choices indicated by the vote bits for each of the
+// consensus deployments of the vote version. The slice is empty if there are
+// no deployments for the vote version.
+func VoteChoices(voteVersion uint32, voteBits uint16, params *chaincfg.Params) []*VoteChoice {
 	deployments := params.Deployments[voteVersion]
 
 	// Allocate space for each choice
@@ -877,7 +907,7 @@ func SSGenVoteChoices(tx *wire.MsgTx, params *chaincfg.Params) (BlockValidation,
 		choices = append(choices, &voteChoice)
 	}
 
-	return validBlock, voteVersion, voteBits, choices, nil
+	return choices
 }
 
 // FeeInfoBlock computes ticket fee statistics for the tickets included in the
@@ -1234,3 +1264,80 @@ func AddressValidation(address string, params *chaincfg.Params) (dcrutil.Address
 
 	return addr, addrType, AddressErrorNoError
 }
+
+// PaymentURIScheme is the URI scheme of Decred payment requests.
+const PaymentURIScheme = "decred"
+
+// PaymentURI formats a payment request URI for the address, in the style of
+// BIP 21. The amount, label and message are only included when set.
+func PaymentURI(address string, amount dcrutil.Amount, label, message string) string {
+	var params []string
+	if amount > 0 {
+		params = append(params, "amount="+strconv.FormatFloat(amount.ToCoin(), 'f', -1, 64))
+	}
+	// Spaces are percent-encoded rather than replaced with "+", which is not
+	// decoded as a space by all wallets.
+	escape := func(s string) string {
+		return strings.Replace(url.QueryEscape(s), "+", "%20", -1)
+	}
+	if label != "" {
+		params = append(params, "label="+escape(label))
+	}
+	if message != "" {
+		params = append(params, "message="+escape(message))
+	}
+	uri := PaymentURIScheme + ":" + address
+	if len(params) > 0 {
+		uri += "?" + strings.Join(params, "&")
+	}
+	return uri
+}
+
+// ErrMessageAddressType is returned by VerifyMessage for an address that
+// cannot sign messages, i.e. one that is not a secp256k1 pubkey hash address.
+var ErrMessageAddressType = errors.New("messages can only be signed by secp256k1 pubkey hash addresses")
+
+// VerifyMessage checks that the base64 encoded compact signature of the
+// message was made with the private key of the address, as with the dcrwallet
+// signmessage RPC, which proves ownership of the address. The verification is
+// the same as dcrd's verifymessage RPC. An error is returned if the address
+// or the signature cannot be decoded, and false if the signature is invalid or
+// was made by another key.
+func VerifyMessage(address, signature, message string, params *chaincfg.Params) (bool, error) {
+	addr, err := dcrutil.DecodeAddress(address, params)
+	if err != nil {
+		return false, err
+	}
+	pkhAddr, ok := addr.(*dcrutil.AddressPubKeyHash)
+	if !ok || pkhAddr.DSA() != dcrec.STEcdsaSecp256k1 {
+		return false, ErrMessageAddressType
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return false, fmt.Errorf("invalid base64 signature: %v", err)
+	}
+
+	// The signed hash commits to a magic prefix so that a message signature
+	// cannot be a transaction signature.
+	var buf bytes.Buffer
+	_ = wire.WriteVarString(&buf, 0, "Decred Signed Message:\n")
+	_ = wire.WriteVarString(&buf, 0, message)
+	pubKey, wasCompressed, err := secp256k1.RecoverCompact(sig, chainhash.HashB(buf.Bytes()))
+	if err != nil {
+		// Mirror dcrd, where a signature that does not recover a public key is
+		// simply not valid.
+		return false, nil
+	}
+	var serializedPubKey []byte
+	if wasCompressed {
+		serializedPubKey = pubKey.SerializeCompressed()
+	} else {
+		serializedPubKey = pubKey.SerializeUncompressed()
+	}
+	pubKeyAddr, err := dcrutil.NewAddressSecpPubKey(serializedPubKey, params)
+	if err != nil {
+		return false, nil
+	}
+	return pubKeyAddr.AddressPubKeyHash().Address() == pkhAddr.Address(), nil
+}
@@ -1,7 +1,9 @@
 package txhelpers
 
 import (
+	"bytes"
 	"crypto/rand"
+	"encoding/base64"
 	"encoding/binary"
 	"fmt"
 	"io"
@@ -13,6 +15,7 @@ import (
 
 	"github.com/decred/dcrd/chaincfg/chainhash"
 	"github.com/decred/dcrd/chaincfg/v2"
+	"github.com/decred/dcrd/dcrec/secp256k1/v2"
 	"github.com/decred/dcrd/dcrutil/v2"
 	chainjson "github.com/decred/dcrd/rpc/jsonrpc/types/v2"
 	"github.com/decred/dcrd/rpcclient/v5"
@@ -310,6 +313,36 @@ func TestFeeRate(t *testing.T) {
 	}
 }
 
+func TestAccumulateChainWork(t *testing.T) {
+	// Mainnet blocks 0 and 1 both have bits 0x1b01ffff.
+	const bits = 0x1b01ffff
+	if work := BlockWork(bits); work != "800040002000" {
+		t.Errorf("Expected block work 800040002000, got %s.", work)
+	}
+
+	chainWork, err := AccumulateChainWork("0", bits)
+	if err != nil {
+		t.Fatalf("AccumulateChainWork failed: %v", err)
+	}
+	expected := "0000000000000000000000000000000000000000000000000000800040002000"
+	if chainWork != expected {
+		t.Errorf("Expected chainwork %s, got %s.", expected, chainWork)
+	}
+
+	chainWork, err = AccumulateChainWork(chainWork, bits)
+	if err != nil {
+		t.Fatalf("AccumulateChainWork failed: %v", err)
+	}
+	expected = "0000000000000000000000000000000000000000000000000001000080004000"
+	if chainWork != expected {
+		t.Errorf("Expected chainwork %s, got %s.", expected, chainWork)
+	}
+
+	if _, err = AccumulateChainWork("not hex", bits); err == nil {
+		t.Errorf("Expected an error for invalid chainwork.")
+	}
+}
+
 func randomHash() chainhash.Hash {
 	var hash chainhash.Hash
 	if _, err := rand.Read(hash[:]); err != nil {
@@ -526,3 +559,87 @@ func BenchmarkMsgTxFromHex(b *testing.B) {
 		}
 	}
 }
+
+func TestPaymentURI(t *testing.T) {
+	const addr = "DsUZxxoHJSty8DCfwfartwTYbuhmVct7tJu"
+	tests := []struct {
+		name    string
+		amount  dcrutil.Amount
+		label   string
+		message string
+		want    string
+	}{
+		{"address only", 0, "", "", "decred:" + addr},
+		{"amount", 150000000, "", "", "decred:" + addr + "?amount=1.5"},
+		{"small amount", 1, "", "", "decred:" + addr + "?amount=0.00000001"},
+		{"all", 1e8, "Coffee & cake", "for Jo",
+			"decred:" + addr + "?amount=1&label=Coffee%20%26%20cake&message=for%20Jo"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PaymentURI(addr, tt.amount, tt.label, tt.message); got != tt.want {
+				t.Errorf("PaymentURI() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyMessage(t *testing.T) {
+	params := chaincfg.MainNetParams()
+	signer := func(seed byte, compressed bool) (string, func(string) string) {
+		privKey, pubKey := secp256k1.PrivKeyFromBytes(bytes.Repeat([]byte{seed}, 32))
+		serializedPubKey := pubKey.SerializeUncompressed()
+		if compressed {
+			serializedPubKey = pubKey.SerializeCompressed()
+		}
+		addr, err := dcrutil.NewAddressSecpPubKey(serializedPubKey, params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sign := func(message string) string {
+			var buf bytes.Buffer
+			_ = wire.WriteVarString(&buf, 0, "Decred Signed Message:\n")
+			_ = wire.WriteVarString(&buf, 0, message)
+			sig, err := secp256k1.SignCompact(privKey, chainhash.HashB(buf.Bytes()), compressed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return base64.StdEncoding.EncodeToString(sig)
+		}
+		return addr.AddressPubKeyHash().Address(), sign
+	}
+	addr, sign := signer(1, true)
+	uncompressedAddr, uncompressedSign := signer(2, false)
+	otherAddr, _ := signer(3, true)
+	const msg = "I own this address."
+	scriptAddr, _ := dcrutil.NewAddressScriptHash([]byte{0x51}, params)
+
+	tests := []struct {
+		name      string
+		address   string
+		signature string
+		message   string
+		want      bool
+		wantErr   bool
+	}{
+		{"valid", addr, sign(msg), msg, true, false},
+		{"valid uncompressed", uncompressedAddr, uncompressedSign(msg), msg, true, false},
+		{"other message", addr, sign(msg), msg + " Not.", false, false},
+		{"other address", otherAddr, sign(msg), msg, false, false},
+		{"truncated signature", addr, sign(msg)[:40], msg, false, false},
+		{"bad base64", addr, "not base64!", msg, false, true},
+		{"script hash address", scriptAddr.Address(), sign(msg), msg, false, true},
+		{"bad address", "Dsbogus", sign(msg), msg, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := VerifyMessage(tt.address, tt.signature, tt.message, params)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("VerifyMessage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("VerifyMessage() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
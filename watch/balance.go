@@ -0,0 +1,242 @@
+// Copyright (c) 2020, The Decred developers
+// See LICENSE for details.
+
+package watch
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/decred/dcrdata/db/dbtypes/v2"
+)
+
+// BalanceSource returns the balance of an address, in atoms, as of the main
+// chain block at the given height.
+type BalanceSource func(address string, height int64) (int64, error)
+
+// Balance threshold directions.
+const (
+	CrossedAbove = "above"
+	CrossedBelow = "below"
+)
+
+// BalanceAlert is the balance of a watched address crossing one of its rule's
+// thresholds in a main chain block. Crossed is CrossedAbove or CrossedBelow.
+// The balance and threshold are in atoms.
+type BalanceAlert struct {
+	Address   string `json:"address"`
+	Height    int64  `json:"height"`
+	BlockHash string `json:"block_hash"`
+	Time      int64  `json:"time"`
+	Crossed   string `json:"crossed"`
+	Threshold int64  `json:"threshold"`
+	Balance   int64  `json:"balance"`
+}
+
+// BalanceNotifier is called with the balance alerts of the watched addresses
+// in a block. Like Notifiers, they are called synchronously during block
+// storage, and should not block.
+type BalanceNotifier func([]*BalanceAlert)
+
+// threshold is the alert state of one of a rule's balance thresholds. An armed
+// threshold is alerted when the balance crosses it.
+type threshold struct {
+	armed     bool
+	lastAlert int64
+}
+
+// balanceState is a tracked address balance and the state of its thresholds.
+type balanceState struct {
+	rule    *Rule
+	balance int64
+	above   threshold
+	below   threshold
+}
+
+// balanceTracker maintains the balances of the addresses with balance
+// thresholds. The balances are loaded from the source with the first block,
+// after reorgs, and after disapprovals of the previous block's regular
+// transactions. Otherwise, each block's valid address rows are applied to
+// them.
+//
+// A block stored at or below the tracked height starts a reorg, during which
+// the blocks of the new chain are only recorded. The balances are reloaded and
+// the thresholds evaluated once, at the new tip, when the reorg completes.
+type balanceTracker struct {
+	source BalanceSource
+
+	mtx     sync.Mutex
+	states  map[string]*balanceState
+	height  int64
+	loaded  bool
+	inReorg bool
+	// The hash and time of the last block stored during a reorg.
+	reorgHash string
+	reorgTime int64
+}
+
+func newBalanceTracker(rules map[string]*Rule, source BalanceSource) *balanceTracker {
+	t := &balanceTracker{
+		source: source,
+		states: make(map[string]*balanceState),
+	}
+	for addr, rule := range rules {
+		if rule.tracksBalance() {
+			t.states[addr] = &balanceState{rule: rule}
+		}
+	}
+	return t
+}
+
+// blockStored updates the balances with the stored block, and returns the
+// thresholds they crossed. Side chain blocks are ignored.
+func (t *balanceTracker) blockStored(stored *dbtypes.StoredBlock) []*BalanceAlert {
+	if len(t.states) == 0 || !stored.IsMainchain {
+		return nil
+	}
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	height := int64(stored.Block.Height)
+	if t.loaded && height <= t.height {
+		t.inReorg = true
+	}
+	if t.inReorg {
+		t.height = height
+		t.reorgHash, t.reorgTime = stored.Block.Hash, stored.Block.Time.UNIX()
+		return nil
+	}
+
+	parentApproved := stored.Block.VoteBits&1 != 0
+	if !t.loaded || height != t.height+1 || !parentApproved {
+		// The balances are reloaded rather than incrementally updated, and
+		// only the thresholds' states are kept.
+		if err := t.load(height); err != nil {
+			log.Errorf("Failed to load the watched address balances at height %d: %v",
+				height, err)
+			t.loaded = false
+			return nil
+		}
+		if !t.loaded {
+			// Arm the thresholds that the starting balances have not crossed.
+			for _, state := range t.states {
+				state.above.armed = state.balance < state.rule.AboveAtoms
+				state.below.armed = state.balance >= state.rule.BelowAtoms
+			}
+			t.loaded = true
+			t.height = height
+			return nil
+		}
+	} else {
+		for _, row := range stored.AddressRows {
+			state, found := t.states[row.Address]
+			if !found || !row.ValidMainChain {
+				continue
+			}
+			if row.IsFunding {
+				state.balance += int64(row.Value)
+			} else {
+				state.balance -= int64(row.Value)
+			}
+		}
+	}
+	t.height = height
+	return t.evaluate(height, stored.Block.Hash, stored.Block.Time.UNIX())
+}
+
+// reorg reloads the balances at the tip of the new chain after a reorg, and
+// returns the thresholds they crossed. The tip's time is that of the last
+// block stored during the reorg, or the current time if that block is not
+// the tip.
+func (t *balanceTracker) reorg(height int64, hash string) []*BalanceAlert {
+	if len(t.states) == 0 {
+		return nil
+	}
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	t.inReorg = false
+	if !t.loaded {
+		// The next block loads the balances and arms the thresholds.
+		return nil
+	}
+	if err := t.load(height); err != nil {
+		log.Errorf("Failed to load the watched address balances at height %d: %v",
+			height, err)
+		t.loaded = false
+		return nil
+	}
+	t.height = height
+
+	blockTime := t.reorgTime
+	if t.reorgHash != hash {
+		blockTime = time.Now().Unix()
+	}
+	return t.evaluate(height, hash, blockTime)
+}
+
+// evaluate updates the thresholds' states with the current balances, and
+// returns the crossings to alert for the block.
+func (t *balanceTracker) evaluate(height int64, hash string, blockTime int64) []*BalanceAlert {
+	var alerts []*BalanceAlert
+	for addr, state := range t.states {
+		rule := state.rule
+		cooldown := int64(rule.Cooldown / time.Second)
+		alert := func(crossed string, threshold int64) {
+			alerts = append(alerts, &BalanceAlert{
+				Address:   addr,
+				Height:    height,
+				BlockHash: hash,
+				Time:      blockTime,
+				Crossed:   crossed,
+				Threshold: threshold,
+				Balance:   state.balance,
+			})
+		}
+		if rule.AboveAtoms > 0 && state.above.update(state.balance >= rule.AboveAtoms,
+			state.balance < rule.AboveAtoms-rule.HysteresisAtoms, blockTime, cooldown) {
+			alert(CrossedAbove, rule.AboveAtoms)
+		}
+		if rule.BelowAtoms > 0 && state.below.update(state.balance < rule.BelowAtoms,
+			state.balance >= rule.BelowAtoms+rule.HysteresisAtoms, blockTime, cooldown) {
+			alert(CrossedBelow, rule.BelowAtoms)
+		}
+	}
+	sort.Slice(alerts, func(i, j int) bool {
+		return alerts[i].Address < alerts[j].Address
+	})
+	return alerts
+}
+
+// load sets the balances as of the block at the height from the source.
+func (t *balanceTracker) load(height int64) error {
+	for addr, state := range t.states {
+		balance, err := t.source(addr, height)
+		if err != nil {
+			return err
+		}
+		state.balance = balance
+	}
+	return nil
+}
+
+// update disarms an armed threshold that the balance crossed, and rearms a
+// disarmed one once the balance is back past it by the hysteresis. It returns
+// true if the crossing is to be alerted, i.e. the cooldown since the last alert
+// at the block time has elapsed.
+func (th *threshold) update(crossed, rearm bool, blockTime, cooldown int64) bool {
+	if !th.armed {
+		th.armed = rearm
+		return false
+	}
+	if !crossed {
+		return false
+	}
+	th.armed = false
+	if th.lastAlert != 0 && blockTime-th.lastAlert < cooldown {
+		return false
+	}
+	th.lastAlert = blockTime
+	return true
+}
@@ -0,0 +1,184 @@
+// Copyright (c) 2020, The Decred developers
+// See LICENSE for details.
+
+package watch
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/decred/dcrd/dcrutil/v2"
+)
+
+// MailConfig is the configuration of a Mailer.
+type MailConfig struct {
+	// Server is the SMTP server as host:port.
+	Server string
+	// Username and Password authenticate with the server using PLAIN auth.
+	// No authentication is attempted if Username is empty.
+	Username string
+	Password string
+	// From is the sender address, and To the recipient addresses.
+	From string
+	To   []string
+	// DigestInterval is how often the batched activity is sent. Only alerts
+	// are sent if zero.
+	DigestInterval time.Duration
+	// Subject prefixes the subject of each message.
+	Subject string
+}
+
+// Mailer sends e-mails for the activity of watched addresses. Alerted activity
+// and balance alerts are sent immediately, while the rest is batched and sent
+// in periodic digests by Run.
+type Mailer struct {
+	cfg  MailConfig
+	auth smtp.Auth
+	send func(msg []byte) error
+
+	mtx     sync.Mutex
+	pending []*Activity
+}
+
+// NewMailer creates a Mailer from the configuration.
+func NewMailer(cfg *MailConfig) (*Mailer, error) {
+	host, _, err := net.SplitHostPort(cfg.Server)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SMTP server %q: %v", cfg.Server, err)
+	}
+	if cfg.From == "" || len(cfg.To) == 0 {
+		return nil, fmt.Errorf("the sender and recipient e-mail addresses are required")
+	}
+	m := &Mailer{cfg: *cfg}
+	if cfg.Username != "" {
+		m.auth = smtp.PlainAuth("", cfg.Username, cfg.Password, host)
+	}
+	m.send = func(msg []byte) error {
+		return smtp.SendMail(m.cfg.Server, m.auth, m.cfg.From, m.cfg.To, msg)
+	}
+	return m, nil
+}
+
+// Notify sends the alerted activity in the background, and batches the rest
+// for the next digest. It is a Notifier.
+func (m *Mailer) Notify(acts []*Activity) {
+	var alerts []*Activity
+	m.mtx.Lock()
+	for _, act := range acts {
+		if act.Alert {
+			alerts = append(alerts, act)
+		} else if m.cfg.DigestInterval > 0 {
+			m.pending = append(m.pending, act)
+		}
+	}
+	m.mtx.Unlock()
+
+	if len(alerts) > 0 {
+		go m.mail(fmt.Sprintf("Alert: %d large watched address transfers", len(alerts)), alerts)
+	}
+}
+
+// NotifyBalance sends the balance alerts in the background. It is a
+// BalanceNotifier.
+func (m *Mailer) NotifyBalance(alerts []*BalanceAlert) {
+	if len(alerts) == 0 {
+		return
+	}
+	subject := fmt.Sprintf("Alert: %d watched address balance thresholds crossed", len(alerts))
+	go m.mailBody(subject, balanceAlertsBody(alerts))
+}
+
+// Run sends the digests until the context is cancelled, when the remaining
+// activity is sent. Run returns immediately if digests are disabled.
+func (m *Mailer) Run(ctx context.Context) {
+	if m.cfg.DigestInterval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(m.cfg.DigestInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			m.sendDigest()
+		case <-ctx.Done():
+			m.sendDigest()
+			return
+		}
+	}
+}
+
+// sendDigest sends the batched activity, if any.
+func (m *Mailer) sendDigest() {
+	m.mtx.Lock()
+	acts := m.pending
+	m.pending = nil
+	m.mtx.Unlock()
+	if len(acts) == 0 {
+		return
+	}
+	m.mail(fmt.Sprintf("Digest: %d watched address transfers", len(acts)), acts)
+}
+
+// mail sends a message listing the activity, logging any failure.
+func (m *Mailer) mail(subject string, acts []*Activity) {
+	m.mailBody(subject, activityBody(acts))
+}
+
+// mailBody sends a message with the body, logging any failure.
+func (m *Mailer) mailBody(subject string, body []byte) {
+	if m.cfg.Subject != "" {
+		subject = m.cfg.Subject + " " + subject
+	}
+	if err := m.send(m.message(subject, body)); err != nil {
+		log.Errorf("Failed to send %q e-mail: %v", subject, err)
+		return
+	}
+	log.Debugf("Sent %q e-mail to %s.", subject, strings.Join(m.cfg.To, ", "))
+}
+
+// message formats the e-mail with the plain text body.
+func (m *Mailer) message(subject string, body []byte) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", m.cfg.From)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(m.cfg.To, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", subject)
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	b.Write(body)
+	return b.Bytes()
+}
+
+// activityBody lists the activity as plain text.
+func activityBody(acts []*Activity) []byte {
+	var b bytes.Buffer
+	for _, act := range acts {
+		direction := "sent"
+		if act.IsFunding {
+			direction = "received"
+		}
+		fmt.Fprintf(&b, "%s %s %s in block %d (%s)\r\n", act.Address, direction,
+			dcrutil.Amount(act.Value), act.Height,
+			time.Unix(act.Time, 0).UTC().Format("2006-01-02 15:04:05 MST"))
+		fmt.Fprintf(&b, "  transaction %s:%d\r\n", act.TxHash, act.Index)
+	}
+	return b.Bytes()
+}
+
+// balanceAlertsBody lists the balance alerts as plain text.
+func balanceAlertsBody(alerts []*BalanceAlert) []byte {
+	var b bytes.Buffer
+	for _, alert := range alerts {
+		fmt.Fprintf(&b, "%s balance of %s is %s %s in block %d (%s)\r\n",
+			alert.Address, dcrutil.Amount(alert.Balance), alert.Crossed,
+			dcrutil.Amount(alert.Threshold), alert.Height,
+			time.Unix(alert.Time, 0).UTC().Format("2006-01-02 15:04:05 MST"))
+	}
+	return b.Bytes()
+}
@@ -0,0 +1,276 @@
+// Copyright (c) 2020, The Decred developers
+// See LICENSE for details.
+
+// Package watch matches the activity of watched addresses in stored blocks
+// against watch rules, and dispatches it to notifiers such as the webhooks and
+// the e-mail digests. The balances of the addresses with balance thresholds are
+// also tracked, and alerted when they cross the thresholds.
+package watch
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/decred/dcrd/chaincfg/v2"
+	"github.com/decred/dcrd/dcrutil/v2"
+	"github.com/decred/dcrdata/db/dbtypes/v2"
+	"github.com/decred/dcrdata/txhelpers/v4"
+)
+
+// Rule is a watched address, with the amount in atoms from which its activity
+// is alerted instantly rather than batched. Alerts are disabled if AlertAtoms
+// is zero.
+//
+// The address's balance is alerted when it rises to AboveAtoms or falls below
+// BelowAtoms, if they are not zero. After an alert, the threshold is not
+// alerted again until the balance has moved back past it by HysteresisAtoms,
+// and no sooner than Cooldown after the alert. Crossings within the cooldown
+// are not alerted.
+type Rule struct {
+	Address         string
+	AlertAtoms      int64
+	AboveAtoms      int64
+	BelowAtoms      int64
+	HysteresisAtoms int64
+	Cooldown        time.Duration
+}
+
+// tracksBalance indicates if the rule has balance thresholds.
+func (rule *Rule) tracksBalance() bool {
+	return rule.AboveAtoms > 0 || rule.BelowAtoms > 0
+}
+
+// ParseRule parses a watch rule of the form address[:amount[:thresholds]],
+// where amount is the alert amount in DCR, and thresholds is a comma-separated
+// list of above=DCR, below=DCR, hysteresis=DCR and cooldown=duration balance
+// alert settings, e.g. address:100:above=5000,hysteresis=50,cooldown=24h. The
+// amount may be empty.
+func ParseRule(s string, params *chaincfg.Params) (*Rule, error) {
+	parts := strings.SplitN(s, ":", 3)
+	addr := parts[0]
+	if _, err := dcrutil.DecodeAddress(addr, params); err != nil {
+		return nil, fmt.Errorf("invalid watched address %q: %v", addr, err)
+	}
+	rule := &Rule{Address: addr}
+	if len(parts) > 1 && parts[1] != "" {
+		atoms, err := parseAtoms(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid alert amount %q for address %s: %v", parts[1], addr, err)
+		}
+		rule.AlertAtoms = atoms
+	}
+	if len(parts) > 2 {
+		if err := rule.parseThresholds(parts[2]); err != nil {
+			return nil, fmt.Errorf("invalid balance thresholds %q for address %s: %v", parts[2], addr, err)
+		}
+	}
+	return rule, nil
+}
+
+// parseThresholds sets the balance thresholds of the rule from a list of
+// setting=value pairs.
+func (rule *Rule) parseThresholds(s string) error {
+	for _, setting := range strings.Split(s, ",") {
+		i := strings.IndexByte(setting, '=')
+		if i < 0 {
+			return fmt.Errorf("%q is not of the form setting=value", setting)
+		}
+		name, value := setting[:i], setting[i+1:]
+		var err error
+		switch name {
+		case "above":
+			rule.AboveAtoms, err = parseAtoms(value)
+		case "below":
+			rule.BelowAtoms, err = parseAtoms(value)
+		case "hysteresis":
+			rule.HysteresisAtoms, err = parseAtoms(value)
+		case "cooldown":
+			rule.Cooldown, err = time.ParseDuration(value)
+			if err == nil && rule.Cooldown < 0 {
+				err = fmt.Errorf("negative cooldown")
+			}
+		default:
+			return fmt.Errorf("unknown setting %q", name)
+		}
+		if err != nil {
+			return fmt.Errorf("invalid %s %q: %v", name, value, err)
+		}
+	}
+	if !rule.tracksBalance() {
+		return fmt.Errorf("an above or below threshold is required")
+	}
+	if rule.AboveAtoms > 0 && rule.BelowAtoms > rule.AboveAtoms {
+		return fmt.Errorf("the below threshold exceeds the above threshold")
+	}
+	return nil
+}
+
+// parseAtoms parses a non-negative amount in DCR.
+func parseAtoms(s string) (int64, error) {
+	dcr, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, err
+	}
+	if dcr < 0 {
+		return 0, fmt.Errorf("negative amount")
+	}
+	atoms, err := dcrutil.NewAmount(dcr)
+	if err != nil {
+		return 0, err
+	}
+	return int64(atoms), nil
+}
+
+// ParseRules parses the watch rules with ParseRule.
+func ParseRules(ss []string, params *chaincfg.Params) ([]*Rule, error) {
+	rules := make([]*Rule, 0, len(ss))
+	for _, s := range ss {
+		rule, err := ParseRule(s, params)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// Activity is a watched address receiving funds in a transaction output, or
+// spending them in an input, of a valid transaction in a main chain block. Value is in atoms. Alert
+// is set if the value reaches the alert amount of the address's rule.
+type Activity struct {
+	Address   string `json:"address"`
+	Height    int64  `json:"height"`
+	BlockHash string `json:"block_hash"`
+	Time      int64  `json:"time"`
+	TxHash    string `json:"tx_hash"`
+	Index     uint32 `json:"index"`
+	IsFunding bool   `json:"is_funding"`
+	Value     int64  `json:"value"`
+	Alert     bool   `json:"alert"`
+}
+
+// Notifier is called with the activity of the watched addresses in a block.
+// Notifiers are called synchronously during block storage, and should not
+// block.
+type Notifier func([]*Activity)
+
+// Watcher matches the address rows of stored blocks against the watch rules.
+type Watcher struct {
+	rules    map[string]*Rule
+	balances *balanceTracker
+
+	mtx              sync.RWMutex
+	notifiers        []Notifier
+	balanceNotifiers []BalanceNotifier
+}
+
+// NewWatcher creates a Watcher for the rules. If several rules are given for
+// an address, the last one applies. The balances of the addresses with balance
+// thresholds are loaded from the BalanceSource, which may only be nil if there
+// are no such rules.
+func NewWatcher(rules []*Rule, source BalanceSource) *Watcher {
+	w := &Watcher{rules: make(map[string]*Rule, len(rules))}
+	for _, rule := range rules {
+		w.rules[rule.Address] = rule
+	}
+	w.balances = newBalanceTracker(w.rules, source)
+	return w
+}
+
+// AddNotifier registers a Notifier to be called by BlockStored.
+func (w *Watcher) AddNotifier(n Notifier) {
+	w.mtx.Lock()
+	w.notifiers = append(w.notifiers, n)
+	w.mtx.Unlock()
+}
+
+// AddBalanceNotifier registers a BalanceNotifier to be called by BlockStored.
+func (w *Watcher) AddBalanceNotifier(n BalanceNotifier) {
+	w.mtx.Lock()
+	w.balanceNotifiers = append(w.balanceNotifiers, n)
+	w.mtx.Unlock()
+}
+
+// Match returns the activity of the watched addresses in the stored block, in
+// the order of the address rows. Side chain blocks have no activity, and the
+// rows of transactions that are not valid, such as the regular transactions
+// of a disapproved block, are skipped.
+func (w *Watcher) Match(stored *dbtypes.StoredBlock) []*Activity {
+	if !stored.IsMainchain {
+		return nil
+	}
+	var acts []*Activity
+	for _, row := range stored.AddressRows {
+		if !row.ValidMainChain {
+			continue
+		}
+		rule, found := w.rules[row.Address]
+		if !found {
+			continue
+		}
+		value := int64(row.Value)
+		acts = append(acts, &Activity{
+			Address:   row.Address,
+			Height:    int64(stored.Block.Height),
+			BlockHash: stored.Block.Hash,
+			Time:      row.TxBlockTime.UNIX(),
+			TxHash:    row.TxHash,
+			Index:     row.TxVinVoutIndex,
+			IsFunding: row.IsFunding,
+			Value:     value,
+			Alert:     rule.AlertAtoms > 0 && value >= rule.AlertAtoms,
+		})
+	}
+	return acts
+}
+
+// BlockStored matches the stored block, and sends any activity to the
+// notifiers. The tracked balances are then updated, and any threshold
+// crossings sent to the balance notifiers. It may be registered as a ChainDB
+// stored rows handler.
+func (w *Watcher) BlockStored(stored *dbtypes.StoredBlock) {
+	acts := w.Match(stored)
+	if len(acts) > 0 {
+		log.Debugf("Block %d has %d watched address inputs and outputs.",
+			stored.Block.Height, len(acts))
+		w.mtx.RLock()
+		for _, notify := range w.notifiers {
+			notify(acts)
+		}
+		w.mtx.RUnlock()
+	}
+
+	alerts := w.balances.blockStored(stored)
+	if len(alerts) == 0 {
+		return
+	}
+	log.Debugf("Block %d has %d watched address balance alerts.",
+		stored.Block.Height, len(alerts))
+	w.notifyBalances(alerts)
+}
+
+// Reorg reloads the tracked balances at the new chain's tip once a chain
+// reorganization is complete, and sends any threshold crossings to the
+// balance notifiers. The balances are not updated while the blocks of the new
+// chain are stored. It should be registered as a ChainDB reorg handler.
+func (w *Watcher) Reorg(reorg *txhelpers.ReorgData) {
+	alerts := w.balances.reorg(int64(reorg.NewChainHeight), reorg.NewChainHead.String())
+	if len(alerts) == 0 {
+		return
+	}
+	log.Debugf("Reorg to height %d has %d watched address balance alerts.",
+		reorg.NewChainHeight, len(alerts))
+	w.notifyBalances(alerts)
+}
+
+// notifyBalances sends the balance alerts to the balance notifiers.
+func (w *Watcher) notifyBalances(alerts []*BalanceAlert) {
+	w.mtx.RLock()
+	for _, notify := range w.balanceNotifiers {
+		notify(alerts)
+	}
+	w.mtx.RUnlock()
+}
@@ -0,0 +1,280 @@
+// Copyright (c) 2020, The Decred developers
+// See LICENSE for details.
+
+package watch
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/chaincfg/v2"
+	"github.com/decred/dcrdata/db/dbtypes/v2"
+	"github.com/decred/dcrdata/txhelpers/v4"
+)
+
+const (
+	addr1 = "Dsi8hhDzr3SvcGcv4NEGvRqFkwZ2ncRhukk"
+	addr2 = "DsUZxxoHJSty8DCfwfartwTYbuhmVct7tJu"
+)
+
+func TestParseRules(t *testing.T) {
+	params := chaincfg.MainNetParams()
+	rules, err := ParseRules([]string{addr1, addr2 + ":12.5"}, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rules[0].Address != addr1 || rules[0].AlertAtoms != 0 {
+		t.Errorf("unexpected rule %+v", *rules[0])
+	}
+	if rules[1].Address != addr2 || rules[1].AlertAtoms != 1250000000 {
+		t.Errorf("unexpected rule %+v", *rules[1])
+	}
+
+	rule, err := ParseRule(addr1+"::above=5000,below=100,hysteresis=0.5,cooldown=24h", params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rule.AlertAtoms != 0 || rule.AboveAtoms != 500000000000 || rule.BelowAtoms != 10000000000 ||
+		rule.HysteresisAtoms != 50000000 || rule.Cooldown != 24*time.Hour {
+		t.Errorf("unexpected balance rule %+v", *rule)
+	}
+
+	for _, s := range []string{"Dsbogus", addr1 + ":lots", addr1 + ":-1", addr1 + ":1:",
+		addr1 + ":1:hysteresis=1", addr1 + ":1:above=1,cooldown=-1h", addr1 + ":1:above=1,big=2",
+		addr1 + ":1:above", addr1 + ":1:above=1,below=2"} {
+		if _, err := ParseRule(s, params); err == nil {
+			t.Errorf("expected an error for rule %q", s)
+		}
+	}
+}
+
+func TestWatcher(t *testing.T) {
+	w := NewWatcher([]*Rule{{Address: addr1}, {Address: addr2, AlertAtoms: 1000}}, nil)
+	var got []*Activity
+	w.AddNotifier(func(acts []*Activity) { got = append(got, acts...) })
+
+	stored := &dbtypes.StoredBlock{
+		Block:       &dbtypes.Block{Hash: "abcd", Height: 42},
+		IsMainchain: true,
+		AddressRows: []*dbtypes.AddressRow{
+			{Address: addr1, TxHash: "tx1", Value: 5000, IsFunding: true, ValidMainChain: true},
+			{Address: "DsOther", TxHash: "tx1", Value: 5000, IsFunding: true, ValidMainChain: true},
+			{Address: addr2, TxHash: "tx2", TxVinVoutIndex: 1, Value: 1000, ValidMainChain: true},
+			{Address: addr2, TxHash: "tx3", Value: 999, ValidMainChain: true},
+			// The regular transactions of a disapproved block are not valid.
+			{Address: addr2, TxHash: "tx4", Value: 5000},
+		},
+	}
+	w.BlockStored(stored)
+	if len(got) != 3 {
+		t.Fatalf("got %d activities, wanted 3", len(got))
+	}
+	if a := got[0]; a.Address != addr1 || a.Height != 42 || a.BlockHash != "abcd" ||
+		!a.IsFunding || a.Alert {
+		t.Errorf("unexpected activity %+v", *a)
+	}
+	if a := got[1]; a.TxHash != "tx2" || a.Index != 1 || !a.Alert {
+		t.Errorf("unexpected alert %+v", *a)
+	}
+	if got[2].Alert {
+		t.Errorf("activity below the alert amount was alerted")
+	}
+
+	got = nil
+	stored.IsMainchain = false
+	w.BlockStored(stored)
+	if len(got) != 0 {
+		t.Errorf("side chain block activity was notified")
+	}
+}
+
+func TestBalanceAlerts(t *testing.T) {
+	var srcBalance, srcHeight int64
+	source := func(address string, height int64) (int64, error) {
+		if address != addr1 {
+			t.Errorf("balance of unexpected address %s loaded", address)
+		}
+		srcHeight = height
+		return srcBalance, nil
+	}
+	w := NewWatcher([]*Rule{{Address: addr1, AboveAtoms: 1000, BelowAtoms: 100,
+		HysteresisAtoms: 50, Cooldown: time.Hour}, {Address: addr2}}, source)
+	var got []*BalanceAlert
+	w.AddBalanceNotifier(func(alerts []*BalanceAlert) { got = append(got, alerts...) })
+
+	tipHash := chainhash.Hash{1}
+	block := func(height uint32, blockTime int64, change int64) *dbtypes.StoredBlock {
+		row := &dbtypes.AddressRow{Address: addr1, Value: uint64(change), IsFunding: true,
+			ValidMainChain: true}
+		if change < 0 {
+			row.Value, row.IsFunding = uint64(-change), false
+		}
+		return &dbtypes.StoredBlock{
+			Block: &dbtypes.Block{Hash: tipHash.String(), Height: height, VoteBits: 1,
+				Time: dbtypes.NewTimeDefFromUNIX(blockTime)},
+			IsMainchain: true,
+			AddressRows: []*dbtypes.AddressRow{row},
+		}
+	}
+
+	// The starting balance is loaded, and its crossings are not alerted.
+	srcBalance = 500
+	w.BlockStored(block(10, 900, 0))
+	if srcHeight != 10 || len(got) != 0 {
+		t.Fatalf("unexpected start at height %d with alerts %v", srcHeight, got)
+	}
+
+	steps := []struct {
+		time   int64
+		change int64
+		alert  string
+	}{
+		{1000, 600, CrossedAbove}, // 1100
+		{1100, -80, ""},           // 1020
+		{1200, -60, ""},           // 960, within the hysteresis
+		{1300, 100, ""},           // 1060, not rearmed
+		{1400, -200, ""},          // 860, rearmed
+		{1500, 200, ""},           // 1060, within the cooldown
+		{1600, -300, ""},          // 760, rearmed
+		{5000, 300, CrossedAbove}, // 1060
+		{5100, -1000, CrossedBelow},
+	}
+	for i, step := range steps {
+		got = nil
+		w.BlockStored(block(uint32(11+i), step.time, step.change))
+		if step.alert == "" {
+			if len(got) != 0 {
+				t.Errorf("step %d: unexpected alert %+v", i, *got[0])
+			}
+			continue
+		}
+		if len(got) != 1 || got[0].Crossed != step.alert || got[0].Address != addr1 ||
+			got[0].Height != int64(11+i) || got[0].Time != step.time {
+			t.Errorf("step %d: expected a %s alert, got %v", i, step.alert, got)
+		}
+	}
+	if got[0].Balance != 60 || got[0].Threshold != 100 {
+		t.Errorf("unexpected below alert %+v", *got[0])
+	}
+
+	// The blocks of a reorg's new chain are not alerted, and the balance is
+	// reloaded at the new tip once the reorg completes.
+	got = nil
+	srcBalance = 2000
+	w.BlockStored(block(18, 8900, 5000))
+	w.BlockStored(block(19, 9000, 0))
+	if srcHeight != 10 || len(got) != 0 {
+		t.Errorf("unexpected alerts %v during reorg", got)
+	}
+	w.Reorg(&txhelpers.ReorgData{NewChainHead: tipHash, NewChainHeight: 19})
+	if srcHeight != 19 || len(got) != 1 || got[0].Crossed != CrossedAbove ||
+		got[0].Balance != 2000 || got[0].Height != 19 || got[0].Time != 9000 {
+		t.Errorf("unexpected alerts %v after reorg to height %d", got, srcHeight)
+	}
+
+	// Disapprovals of the previous block reload the balance, and the invalid
+	// rows are not applied.
+	got = nil
+	srcBalance = 0
+	disapproving := block(20, 9100, 5000)
+	disapproving.Block.VoteBits = 0
+	w.BlockStored(disapproving)
+	if srcHeight != 20 || len(got) != 1 || got[0].Crossed != CrossedBelow || got[0].Balance != 0 {
+		t.Errorf("unexpected alerts %v after disapproval at height %d", got, srcHeight)
+	}
+	got = nil
+	invalid := block(21, 9150, 5000)
+	invalid.AddressRows[0].ValidMainChain = false
+	w.BlockStored(invalid)
+	if len(got) != 0 {
+		t.Errorf("unexpected alerts %v for invalid rows", got)
+	}
+
+	// Side chain blocks are ignored.
+	got = nil
+	sideBlock := block(22, 9200, 5000)
+	sideBlock.IsMainchain = false
+	w.BlockStored(sideBlock)
+	w.BlockStored(block(22, 13000, 1000))
+	if srcHeight != 20 || len(got) != 1 || got[0].Balance != 1000 {
+		t.Errorf("unexpected alerts %v after side chain block", got)
+	}
+}
+
+func TestMailer(t *testing.T) {
+	m, err := NewMailer(&MailConfig{
+		Server:         "smtp.example.com:587",
+		From:           "dcrdata@example.com",
+		To:             []string{"me@example.com"},
+		DigestInterval: time.Hour,
+		Subject:        "[dcrdata]",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var mtx sync.Mutex
+	var sent [][]byte
+	m.send = func(msg []byte) error {
+		mtx.Lock()
+		sent = append(sent, msg)
+		mtx.Unlock()
+		return nil
+	}
+	numSent := func() int {
+		mtx.Lock()
+		defer mtx.Unlock()
+		return len(sent)
+	}
+
+	m.Notify([]*Activity{
+		{Address: addr1, TxHash: "tx1", Value: 100000000, IsFunding: true},
+		{Address: addr2, TxHash: "tx2", Value: 5000000000, Alert: true},
+	})
+
+	// The alert is sent immediately.
+	for i := 0; i < 100 && numSent() == 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if numSent() != 1 {
+		t.Fatalf("alert not sent")
+	}
+	if !bytes.Contains(sent[0], []byte("Subject: [dcrdata] Alert: 1 large")) ||
+		!bytes.Contains(sent[0], []byte(addr2+" sent 50 DCR")) {
+		t.Errorf("unexpected alert message:\n%s", sent[0])
+	}
+
+	// The rest is sent in the digest, which is sent on shutdown.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	m.Run(ctx)
+	if numSent() != 2 {
+		t.Fatalf("digest not sent")
+	}
+	if !bytes.Contains(sent[1], []byte("Digest: 1 watched")) ||
+		!bytes.Contains(sent[1], []byte(addr1+" received 1 DCR")) {
+		t.Errorf("unexpected digest message:\n%s", sent[1])
+	}
+
+	// Balance alerts are sent immediately.
+	m.NotifyBalance([]*BalanceAlert{
+		{Address: addr1, Crossed: CrossedAbove, Threshold: 100000000000, Balance: 120000000000},
+	})
+	for i := 0; i < 100 && numSent() == 2; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if numSent() != 3 {
+		t.Fatalf("balance alert not sent")
+	}
+	if !bytes.Contains(sent[2], []byte("Alert: 1 watched address balance")) ||
+		!bytes.Contains(sent[2], []byte(addr1+" balance of 1200 DCR is above 1000 DCR")) {
+		t.Errorf("unexpected balance alert message:\n%s", sent[2])
+	}
+
+	if _, err = NewMailer(&MailConfig{Server: "smtp.example.com"}); err == nil {
+		t.Errorf("expected an error for a server without a port")
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2020, The Decred developers
+// See LICENSE for details.
+
+// Package webhook posts JSON event notifications to configured HTTP endpoints.
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// defaultTimeout is the HTTP client timeout used when none is specified.
+const defaultTimeout = 10 * time.Second
+
+// Event is the JSON body POSTed to each webhook URL.
+type Event struct {
+	Event string      `json:"event"`
+	Time  int64       `json:"time"`
+	Data  interface{} `json:"data"`
+}
+
+// Poster sends events to a set of webhook URLs.
+type Poster struct {
+	urls   []string
+	client *http.Client
+}
+
+// NewPoster creates a Poster for the given URLs. A timeout of zero uses a
+// default of 10 seconds.
+func NewPoster(urls []string, timeout time.Duration) *Poster {
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	return &Poster{
+		urls:   urls,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// Post sends the event with the given data to each URL asynchronously.
+// Failures are logged, but not retried.
+func (p *Poster) Post(event string, data interface{}) {
+	if p == nil || len(p.urls) == 0 {
+		return
+	}
+
+	body, err := json.Marshal(&Event{
+		Event: event,
+		Time:  time.Now().Unix(),
+		Data:  data,
+	})
+	if err != nil {
+		log.Errorf("Failed to encode %s webhook event: %v", event, err)
+		return
+	}
+
+	for _, url := range p.urls {
+		go func(url string) {
+			if err := p.post(url, body); err != nil {
+				log.Warnf("Failed to post %s event to webhook %s: %v", event, url, err)
+				return
+			}
+			log.Debugf("Posted %s event to webhook %s.", event, url)
+		}(url)
+	}
+}
+
+func (p *Poster) post(url string, body []byte) error {
+	resp, err := p.client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected response status %s", resp.Status)
+	}
+	return nil
+}
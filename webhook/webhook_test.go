@@ -0,0 +1,47 @@
+// Copyright (c) 2020, The Decred developers
+
+package webhook
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestPost(t *testing.T) {
+	received := make(chan *Event, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("wrong content type %q", ct)
+		}
+		ev := new(Event)
+		if err := json.NewDecoder(r.Body).Decode(ev); err != nil {
+			t.Errorf("failed to decode event: %v", err)
+		}
+		received <- ev
+	}))
+	defer srv.Close()
+
+	p := NewPoster([]string{srv.URL}, time.Second)
+	p.Post("test", map[string]int{"height": 42})
+
+	select {
+	case ev := <-received:
+		if ev.Event != "test" {
+			t.Errorf("wrong event %q", ev.Event)
+		}
+		data, ok := ev.Data.(map[string]interface{})
+		if !ok || data["height"] != 42.0 {
+			t.Errorf("wrong data %v", ev.Data)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook not called")
+	}
+
+	// Posting with a nil Poster or no URLs is a no-op.
+	var nilPoster *Poster
+	nilPoster.Post("test", nil)
+	NewPoster(nil, 0).Post("test", nil)
+}